@@ -0,0 +1,187 @@
+package agent
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/agent"
+	"github.com/anowarislam/ado/internal/logging"
+	"github.com/anowarislam/ado/internal/profiling"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// minInterval keeps the agent from sampling faster than the collectors
+// can usefully measure.
+const minInterval = time.Second
+
+// NewCommand returns the agent command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Record system snapshots to disk on an interval",
+		Long: `Record compact snapshots of CPU, memory, disk, and GPU usage to a local
+store, so a host keeps its own history without an external monitoring
+system.
+
+Snapshots are kept in a SQLite database, agent.db, in the agent
+directory under ado's state directory. Snapshots older than --retain,
+and the oldest ones beyond --max-size-mb, are removed as the agent runs.
+Query them with ado meta history.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newRunCommand(),
+		newStatusCommand(),
+	)
+	return cmd
+}
+
+func newRunCommand() *cobra.Command {
+	var (
+		dir       string
+		interval  time.Duration
+		retain    time.Duration
+		maxSizeMB int64
+		pprofAddr string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Record snapshots in the foreground until interrupted",
+		Long: `Take a snapshot every --interval and append it to the store until
+interrupted. CPU usage is averaged over each interval; memory, disk, and
+GPU figures are read at the time of the snapshot, with the same
+collectors as ado top.
+
+Run it as a service to keep history across reboots:
+  ado service install agent
+
+Examples:
+  # Record a snapshot every minute, keeping a week
+  ado agent run
+
+  # Every 10 seconds, keeping a day in at most 20 MB
+  ado agent run --interval 10s --retain 24h --max-size-mb 20`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if interval < minInterval {
+				return fmt.Errorf("--interval must be at least %s", minInterval)
+			}
+			if retain < 0 || maxSizeMB < 0 {
+				return errors.New("--retain and --max-size-mb must not be negative")
+			}
+			store, err := openStore(dir)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			ctx := cmd.Context()
+			if pprofAddr != "" {
+				if _, err := profiling.Listen(ctx, pprofAddr); err != nil {
+					return err
+				}
+			}
+
+			logger := logging.FromContext(ctx)
+			a := &agent.Agent{
+				Store:    store,
+				Interval: interval,
+				Retention: agent.Retention{
+					MaxAge:   retain,
+					MaxBytes: maxSizeMB << 20,
+				},
+				Logger: logger,
+			}
+			logger.Info("agent: started", "store", store.Path, "interval", interval, "retain", retain, "max_size_mb", maxSizeMB)
+			a.Run(ctx)
+			logger.Info("agent: stopped")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "Store directory (default: <state dir>/agent)")
+	cmd.Flags().DurationVarP(&interval, "interval", "n", agent.DefaultInterval, "Time between snapshots")
+	cmd.Flags().DurationVar(&retain, "retain", agent.DefaultMaxAge, "Remove snapshots older than this (0 keeps them)")
+	cmd.Flags().Int64Var(&maxSizeMB, "max-size-mb", agent.DefaultMaxBytes>>20, "Remove the oldest snapshots while the store is larger (0 for no limit)")
+	profiling.AddListenFlag(cmd, &pprofAddr)
+	_ = cmd.MarkFlagDirname("dir")
+	return cmd
+}
+
+func newStatusCommand() *cobra.Command {
+	var (
+		dir    string
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Show what the store holds and the latest snapshot",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			store, err := openStore(dir)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+			payload, err := store.Stats()
+			if err != nil {
+				return err
+			}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatStatus(payload), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", "", "Store directory (default: <state dir>/agent)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	_ = cmd.MarkFlagDirname("dir")
+	return cmd
+}
+
+func openStore(dir string) (*agent.Store, error) {
+	if dir == "" {
+		var err error
+		if dir, err = agent.DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	return agent.Open(dir)
+}
+
+func formatStatus(s agent.Stats) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Store: %s (%s)\n", s.Path, ui.FormatSize(s.SizeBytes))
+	if s.Latest == nil {
+		b.WriteString("No snapshots recorded. Start the agent with: ado agent run\n")
+		return b.String()
+	}
+	fmt.Fprintf(&b, "Snapshots: %d\n", s.Snapshots)
+	fmt.Fprintf(&b, "Range: %s to %s\n", s.Oldest.Format(time.RFC3339), s.Latest.Time.Format(time.RFC3339))
+
+	latest := s.Latest
+	fmt.Fprintf(&b, "\nLatest (%s):\n", latest.Host)
+	fmt.Fprintf(&b, "  CPU       %5.1f%%\n", latest.CPU)
+	fmt.Fprintf(&b, "  Memory    %5.1f%%  %d / %d MB\n", latest.Memory.UsedPercent, latest.Memory.UsedMB, latest.Memory.TotalMB)
+	for _, v := range latest.Storage {
+		fmt.Fprintf(&b, "  %-9s %5.1f%%  %d MB free\n", v.Mountpoint, v.UsedPercent, v.FreeMB)
+	}
+	for _, g := range latest.GPU {
+		fmt.Fprintf(&b, "  GPU %-5d %5.1f%%  %d MB used\n", g.Index, g.UtilizationPercent, g.MemoryUsedMB)
+	}
+	return b.String()
+}
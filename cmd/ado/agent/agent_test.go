@@ -0,0 +1,82 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/agent"
+)
+
+func newTestRoot() (*cobra.Command, *bytes.Buffer) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewCommand())
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	return root, &buf
+}
+
+func TestAgentRunStatus(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "agent")
+
+	root, buf := newTestRoot()
+	root.SetArgs([]string{"agent", "status", "--dir", dir})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "No snapshots recorded") {
+		t.Errorf("empty status = %q", buf.String())
+	}
+
+	root, _ = newTestRoot()
+	root.SetArgs([]string{"agent", "run", "--dir", dir, "--interval", "1s"})
+	ctx, cancel := context.WithTimeout(context.Background(), 1500*time.Millisecond)
+	defer cancel()
+	if err := root.ExecuteContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	root, buf = newTestRoot()
+	root.SetArgs([]string{"agent", "status", "--dir", dir, "-o", "json"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var got agent.Stats
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("%v:\n%s", err, buf.String())
+	}
+	if got.Snapshots != 1 || got.Path != filepath.Join(dir, agent.FileName) || got.Latest == nil || got.Latest.Memory.TotalMB == 0 {
+		t.Errorf("status = %+v", got)
+	}
+
+	root, buf = newTestRoot()
+	root.SetArgs([]string{"agent", "status", "--dir", dir})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Snapshots: 1\n", "Latest (", "Memory"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("status missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestAgentRun_Flags(t *testing.T) {
+	for args, want := range map[string]string{
+		"--interval 100ms": "--interval must be at least 1s",
+		"--retain -1h":     "must not be negative",
+	} {
+		root, _ := newTestRoot()
+		root.SetArgs(append([]string{"agent", "run"}, strings.Fields(args)...))
+		if err := root.Execute(); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error = %v, want %q", args, err, want)
+		}
+	}
+}
@@ -0,0 +1,269 @@
+package alias
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalalias "github.com/anowarislam/ado/internal/alias"
+	"github.com/anowarislam/ado/internal/completion"
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// NewCommand returns the alias parent command with subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage command aliases",
+		Long: `Define short names for ado command lines you type often. Aliases live
+under aliases: in the config file and are expanded before the command
+runs; arguments after the alias are appended to its expansion:
+
+  aliases:
+    sys: meta system --output json
+
+  ado sys --no-network   # runs: ado meta system --output json --no-network
+
+Built-in commands always win, so an alias cannot shadow one, and aliases
+do not expand other aliases.`,
+	}
+
+	cmd.AddCommand(
+		newListCommand(),
+		newAddCommand(),
+		newRemoveCommand(),
+	)
+	return cmd
+}
+
+// Entry is an alias as listed.
+type Entry struct {
+	Name      string `json:"name" yaml:"name"`
+	Expansion string `json:"expansion" yaml:"expansion"`
+	// Shadowed is set for aliases named like a built-in command, which
+	// are never expanded.
+	Shadowed bool `json:"shadowed,omitempty" yaml:"shadowed,omitempty"`
+}
+
+// listOutput is the payload for structured output.
+type listOutput struct {
+	Aliases []Entry `json:"aliases" yaml:"aliases"`
+}
+
+func newListCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List aliases",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			payload := listOutput{Aliases: []Entry{}}
+			for _, name := range sortedNames(cfg.Aliases) {
+				payload.Aliases = append(payload.Aliases, Entry{
+					Name:      name,
+					Expansion: cfg.Aliases[name],
+					Shadowed:  internalalias.Shadows(cmd.Root(), name),
+				})
+			}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatEntries(payload.Aliases), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func newAddCommand() *cobra.Command {
+	var force bool
+
+	cmd := &cobra.Command{
+		Use:         "add NAME EXPANSION",
+		Short:       "Define an alias",
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		Long: `Define NAME as a shorthand for EXPANSION, a command line without the
+leading "ado". Quote EXPANSION so its flags are not read as flags of
+'ado alias add'. It must start with an ado command.
+
+Examples:
+  ado alias add sys "meta system --output json"
+  ado alias add deploy "run deploy --env 'us east'" --force`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, expansion := args[0], args[1]
+			if err := internalalias.ValidateName(name); err != nil {
+				return err
+			}
+			if internalalias.Shadows(cmd.Root(), name) {
+				return fmt.Errorf("%q is a built-in command; aliases cannot shadow it", name)
+			}
+			if _, err := internalalias.Parse(cmd.Root(), expansion); err != nil {
+				return fmt.Errorf("alias %q: %w", name, err)
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if existing, ok := cfg.Aliases[name]; ok && !force {
+				return fmt.Errorf("alias %q already exists: %s (use --force to replace it)", name, existing)
+			}
+
+			path, err := configPath(cmd)
+			if err != nil {
+				return err
+			}
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				plan := ui.NewPlan()
+				plan.Add("write", path, fmt.Sprintf("set alias %q to %q", name, expansion))
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+			if err := internalconfig.SetAlias(path, name, expansion); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Added alias %q in %s\n", name, path)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&force, "force", false, "Replace an existing alias")
+	return cmd
+}
+
+func newRemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "remove NAME",
+		Aliases:           []string{"rm"},
+		Short:             "Delete an alias",
+		Annotations:       map[string]string{ui.DryRunAnnotation: "true"},
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.FirstArg(completeAliases),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := configPath(cmd)
+			if err != nil {
+				return err
+			}
+			dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag)
+			var found bool
+			if dryRun {
+				found, err = hasAlias(path, args[0])
+			} else {
+				found, err = internalconfig.RemoveAlias(path, args[0])
+			}
+			if err != nil {
+				return err
+			}
+			if !found {
+				return fmt.Errorf("alias %q not found in %s", args[0], path)
+			}
+			if dryRun {
+				plan := ui.NewPlan()
+				plan.Add("write", path, fmt.Sprintf("remove alias %q", args[0]))
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "Removed alias %q from %s\n", args[0], path)
+			return nil
+		},
+	}
+	return cmd
+}
+
+// completeAliases completes alias names with their expansions.
+func completeAliases(cmd *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []cobra.Completion
+	for _, name := range sortedNames(cfg.Aliases) {
+		names = append(names, cobra.CompletionWithDesc(name, cfg.Aliases[name]))
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// loadConfig loads the config selected by --config, $ADO_CONFIG, or the
+// default search paths. A file that does not exist yet has no aliases.
+func loadConfig(cmd *cobra.Command) (*internalconfig.Config, error) {
+	configPath, _ := cmd.Root().PersistentFlags().GetString("config")
+	homeDir, _ := os.UserHomeDir()
+	cfg, _, err := internalconfig.PathResolverFor(cmd.Context(), configPath, homeDir).Load()
+	if errors.Is(err, fs.ErrNotExist) {
+		return internalconfig.Default(), nil
+	}
+	return cfg, err
+}
+
+// hasAlias reports whether the config file at path defines name. A file
+// that does not exist defines no aliases.
+func hasAlias(path, name string) (bool, error) {
+	cfg, err := internalconfig.Load(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	_, ok := cfg.Aliases[name]
+	return ok, nil
+}
+
+// configPath returns the config file aliases are written to: --config or
+// $ADO_CONFIG when set, otherwise the user config.
+func configPath(cmd *cobra.Command) (string, error) {
+	if path, _ := cmd.Root().PersistentFlags().GetString("config"); path != "" {
+		return path, nil
+	}
+	if path := os.Getenv("ADO_CONFIG"); path != "" {
+		return path, nil
+	}
+	homeDir, _ := os.UserHomeDir()
+	if path := internalconfig.UserConfigPath(homeDir); path != "" {
+		return path, nil
+	}
+	return "", errors.New("cannot determine config path: set --config or ADO_CONFIG")
+}
+
+func sortedNames(aliases map[string]string) []string {
+	names := make([]string, 0, len(aliases))
+	for name := range aliases {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// formatEntries renders one aligned NAME EXPANSION line per alias.
+func formatEntries(entries []Entry) string {
+	if len(entries) == 0 {
+		return "No aliases defined."
+	}
+	width := 0
+	for _, e := range entries {
+		width = max(width, len(e.Name))
+	}
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = fmt.Sprintf("%-*s  %s", width, e.Name, e.Expansion)
+		if e.Shadowed {
+			lines[i] += "  (shadowed by a built-in command)"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
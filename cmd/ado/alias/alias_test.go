@@ -0,0 +1,152 @@
+package alias
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+)
+
+func execute(t *testing.T, configPath string, args ...string) (string, error) {
+	t.Helper()
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().String("config", configPath, "Path to config file")
+	root.PersistentFlags().Bool("dry-run", false, "")
+	root.AddCommand(NewCommand(), &cobra.Command{Use: "meta"}, &cobra.Command{Use: "echo"})
+
+	var out bytes.Buffer
+	root.SetArgs(append([]string{"alias"}, args...))
+	root.SetOut(&out)
+	root.SetErr(&out)
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestAddListRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ado", "config.yaml")
+
+	out, err := execute(t, path, "list")
+	if err != nil || out != "No aliases defined.\n" {
+		t.Fatalf("list = %q, %v", out, err)
+	}
+
+	if out, err := execute(t, path, "add", "sys", "meta system --output json"); err != nil || !strings.Contains(out, `Added alias "sys" in `+path) {
+		t.Fatalf("add = %q, %v", out, err)
+	}
+	if _, err := execute(t, path, "add", "hi", "echo 'hello world'"); err != nil {
+		t.Fatalf("add hi: %v", err)
+	}
+
+	out, err = execute(t, path, "list")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if want := "hi   echo 'hello world'\nsys  meta system --output json\n"; out != want {
+		t.Errorf("list = %q, want %q", out, want)
+	}
+
+	if _, err := execute(t, path, "add", "sys", "meta"); err == nil || !strings.Contains(err.Error(), "use --force") {
+		t.Errorf("add existing error = %v", err)
+	}
+	if _, err := execute(t, path, "add", "sys", "meta", "--force"); err != nil {
+		t.Errorf("add --force: %v", err)
+	}
+
+	if out, err := execute(t, path, "remove", "hi"); err != nil || !strings.Contains(out, `Removed alias "hi"`) {
+		t.Errorf("remove = %q, %v", out, err)
+	}
+	if _, err := execute(t, path, "rm", "hi"); err == nil || !strings.Contains(err.Error(), `alias "hi" not found`) {
+		t.Errorf("remove missing error = %v", err)
+	}
+
+	cfg, err := internalconfig.Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Aliases) != 1 || cfg.Aliases["sys"] != "meta" {
+		t.Errorf("Aliases = %v", cfg.Aliases)
+	}
+}
+
+func TestAddRemove_DryRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	out, err := execute(t, path, "add", "sys", "meta system", "--dry-run")
+	if err != nil {
+		t.Fatalf("add --dry-run: %v", err)
+	}
+	if want := "Would write " + path + `: set alias "sys" to "meta system"` + "\nDry run: nothing was changed.\n"; out != want {
+		t.Errorf("add --dry-run = %q, want %q", out, want)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("add --dry-run created the config: %v", err)
+	}
+
+	if _, err := execute(t, path, "remove", "sys", "--dry-run"); err == nil || !strings.Contains(err.Error(), `alias "sys" not found`) {
+		t.Errorf("remove missing --dry-run error = %v", err)
+	}
+	if _, err := execute(t, path, "add", "sys", "meta system"); err != nil {
+		t.Fatal(err)
+	}
+	if out, err := execute(t, path, "remove", "sys", "--dry-run"); err != nil || !strings.Contains(out, `remove alias "sys"`) {
+		t.Errorf("remove --dry-run = %q, %v", out, err)
+	}
+	if cfg, err := internalconfig.Load(path); err != nil || cfg.Aliases["sys"] != "meta system" {
+		t.Errorf("alias removed under --dry-run: %v, %v", cfg, err)
+	}
+}
+
+func TestAdd_Errors(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"meta", "echo hi"}, `"meta" is a built-in command; aliases cannot shadow it`},
+		{[]string{"help", "echo hi"}, `"help" is a built-in command`},
+		{[]string{"a b", "echo hi"}, `invalid alias name "a b"`},
+		{[]string{"x", "nope --flag"}, `alias "x": expansion must start with an ado command, not "nope"`},
+		{[]string{"x", "echo 'open"}, `alias "x": unterminated ' quote`},
+		{[]string{"x", " "}, `alias "x": expansion is empty`},
+	}
+	for _, tt := range tests {
+		_, err := execute(t, path, append([]string{"add"}, tt.args...)...)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("add %q error = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("failed adds wrote the config file")
+	}
+}
+
+func TestList_JSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("version: 1\naliases:\n  sys: meta system\n  echo: meta\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := execute(t, path, "list", "-o", "json")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var got listOutput
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	want := []Entry{{Name: "echo", Expansion: "meta", Shadowed: true}, {Name: "sys", Expansion: "meta system"}}
+	if len(got.Aliases) != 2 || got.Aliases[0] != want[0] || got.Aliases[1] != want[1] {
+		t.Errorf("aliases = %+v, want %+v", got.Aliases, want)
+	}
+
+	out, _ = execute(t, path, "list")
+	if !strings.Contains(out, "echo  meta  (shadowed by a built-in command)") {
+		t.Errorf("list = %q", out)
+	}
+}
@@ -0,0 +1,310 @@
+package archive
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalarchive "github.com/anowarislam/ado/internal/archive"
+	"github.com/anowarislam/ado/internal/completion"
+	"github.com/anowarislam/ado/internal/digest"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// checksumInfo describes the checksum file written next to an archive.
+type checksumInfo struct {
+	Algorithm string `json:"algorithm" yaml:"algorithm"`
+	Digest    string `json:"digest" yaml:"digest"`
+	Path      string `json:"path" yaml:"path"`
+}
+
+// createOutput is the payload for archive create.
+type createOutput struct {
+	Archive   string                  `json:"archive" yaml:"archive"`
+	Format    string                  `json:"format" yaml:"format"`
+	SizeBytes int64                   `json:"size_bytes" yaml:"size_bytes"`
+	Checksum  *checksumInfo           `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+	Entries   []internalarchive.Entry `json:"entries" yaml:"entries"`
+}
+
+// extractOutput is the payload for archive extract.
+type extractOutput struct {
+	Archive string                  `json:"archive" yaml:"archive"`
+	Format  string                  `json:"format" yaml:"format"`
+	Dir     string                  `json:"dir" yaml:"dir"`
+	Entries []internalarchive.Entry `json:"entries" yaml:"entries"`
+}
+
+// NewCommand returns the archive parent command with subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Create and extract tar.gz and zip archives",
+		Long: `Create and extract tar.gz and zip archives with glob filtering.
+
+The format follows the archive's extension (.tar.gz, .tgz, .zip) unless
+--format is given.`,
+	}
+
+	cmd.AddCommand(
+		newCreateCommand(),
+		newExtractCommand(),
+	)
+
+	return cmd
+}
+
+func newCreateCommand() *cobra.Command {
+	var (
+		dir          string
+		include      []string
+		exclude      []string
+		format       string
+		reproducible bool
+		checksum     bool
+		algorithm    string
+		output       string
+	)
+
+	cmd := &cobra.Command{
+		Use:         "create ARCHIVE SOURCE...",
+		Short:       "Create an archive from files and directories",
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		Long: `Create ARCHIVE from the given files and directories. Sources are read
+relative to --dir and keep that relative path inside the archive.
+Entries are always sorted by name.
+
+--include limits the archive to matching files; --exclude drops matching
+files and whole directories. Patterns use the same glob syntax as
+ado watch: without a slash they match a single path element ("*.go",
+"vendor"); with one they match the whole archive path, and ** matches
+any number of directories. Nothing is excluded by default.
+
+--reproducible fixes every timestamp to 1980-01-01, normalizes
+permissions to 0644/0755, and drops owners, so the same content always
+produces byte-identical archives.
+
+--checksum writes ARCHIVE.<algorithm> next to the archive in the
+sha256sum format, verifiable with ado hash --check.
+
+--dry-run reads the sources and lists what the archive would hold
+without writing it.
+
+Examples:
+  # Archive a build directory
+  ado archive create dist.tar.gz dist
+
+  # Reproducible source bundle with a checksum
+  ado archive create --reproducible --checksum src.zip . --exclude .git
+
+  # Only Go files, read from another directory
+  ado archive create -C ./service go.tar.gz . --include '**/*.go'`,
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outFormat, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			archivePath, sources := args[0], args[1:]
+			if format == "" {
+				if format, err = internalarchive.DetectFormat(archivePath); err != nil {
+					return err
+				}
+			}
+			if checksum {
+				if _, err := digest.New(algorithm); err != nil {
+					return err
+				}
+				algorithm = strings.ToLower(algorithm)
+			}
+
+			opts := internalarchive.CreateOptions{
+				Format:       format,
+				Include:      include,
+				Exclude:      exclude,
+				Skip:         []string{archivePath},
+				Reproducible: reproducible,
+			}
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				entries, err := internalarchive.Create(io.Discard, dir, sources, opts)
+				if err != nil {
+					return err
+				}
+				plan := ui.NewPlan()
+				plan.Add("write", archivePath, fmt.Sprintf("%s, %d entries", format, len(entries)))
+				if checksum {
+					plan.Add("write", archivePath+"."+algorithm, algorithm+" checksum")
+				}
+				return ui.PrintPlan(cmd.OutOrStdout(), outFormat, plan)
+			}
+
+			entries, err := createArchive(archivePath, dir, sources, opts)
+			if err != nil {
+				return err
+			}
+
+			payload := createOutput{Archive: archivePath, Format: format, Entries: entries}
+			if info, err := os.Stat(archivePath); err == nil {
+				payload.SizeBytes = info.Size()
+			}
+			if checksum {
+				if payload.Checksum, err = writeChecksum(archivePath, algorithm); err != nil {
+					return err
+				}
+			}
+
+			return ui.PrintOutput(cmd.OutOrStdout(), outFormat, payload, func() (string, error) {
+				var b strings.Builder
+				fmt.Fprintf(&b, "created %s (%s, %d entries, %d bytes)\n", payload.Archive, payload.Format, len(payload.Entries), payload.SizeBytes)
+				if c := payload.Checksum; c != nil {
+					fmt.Fprintf(&b, "%s %s  %s\n", c.Algorithm, c.Digest, c.Path)
+				}
+				return b.String(), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&dir, "dir", "C", ".", "Directory the sources are relative to")
+	cmd.Flags().StringArrayVar(&include, "include", nil, "Only archive files matching this glob (repeatable)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "Skip files and directories matching this glob (repeatable)")
+	cmd.Flags().StringVar(&format, "format", "", "Archive format: "+strings.Join(internalarchive.Formats, ", ")+" (default: from the extension)")
+	_ = cmd.RegisterFlagCompletionFunc("format", completion.Fixed(internalarchive.Formats...))
+	cmd.Flags().BoolVar(&reproducible, "reproducible", false, "Fix timestamps, permissions, and owners for byte-identical output")
+	cmd.Flags().BoolVar(&checksum, "checksum", false, "Write ARCHIVE.<algorithm> with the archive's digest")
+	cmd.Flags().StringVarP(&algorithm, "algorithm", "a", digest.DefaultAlgorithm, "Checksum algorithm: "+strings.Join(digest.Algorithms, ", "))
+	_ = cmd.RegisterFlagCompletionFunc("algorithm", completion.Fixed(digest.Algorithms...))
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+// createArchive writes the archive to a temporary file next to path and
+// renames it into place, so a failed run never leaves a truncated archive.
+func createArchive(path, dir string, sources []string, opts internalarchive.CreateOptions) ([]internalarchive.Entry, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return nil, fmt.Errorf("create %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+	opts.Skip = append(opts.Skip, tmp.Name())
+
+	entries, err := internalarchive.Create(tmp, dir, sources, opts)
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("write %s: %w", path, closeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return nil, fmt.Errorf("write %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+// writeChecksum writes "<digest>  <name>" to path.<algorithm>. The name is
+// the archive's base name, so the file verifies from the archive's
+// directory.
+func writeChecksum(path, algorithm string) (*checksumInfo, error) {
+	entry, err := digest.Path(path, algorithm)
+	if err != nil {
+		return nil, fmt.Errorf("hash %s: %w", path, err)
+	}
+	sumPath := path + "." + algorithm
+	line := fmt.Sprintf("%s  %s\n", entry.Digest, filepath.Base(path))
+	if err := os.WriteFile(sumPath, []byte(line), 0o644); err != nil {
+		return nil, fmt.Errorf("write checksum: %w", err)
+	}
+	return &checksumInfo{Algorithm: algorithm, Digest: entry.Digest, Path: sumPath}, nil
+}
+
+func newExtractCommand() *cobra.Command {
+	var (
+		dir     string
+		include []string
+		exclude []string
+		format  string
+		output  string
+	)
+
+	cmd := &cobra.Command{
+		Use:         "extract ARCHIVE",
+		Short:       "Extract an archive into a directory",
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		Long: `Extract ARCHIVE into --dir, creating it if needed. Existing files are
+overwritten.
+
+Entries with absolute paths or ".." components, and symlinks pointing
+outside the destination, stop extraction with an error. Hard links and
+special files are skipped.
+
+--include and --exclude filter entries with the same globs as create.
+
+--dry-run reads and checks the whole archive and lists what would be
+written, without touching --dir.
+
+Examples:
+  # Extract into the current directory
+  ado archive extract dist.tar.gz
+
+  # Extract only the docs into ./out
+  ado archive extract bundle.zip -C out --include 'docs/**'`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			outFormat, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			archivePath := args[0]
+			if format == "" {
+				if format, err = internalarchive.DetectFormat(archivePath); err != nil {
+					return err
+				}
+			}
+
+			dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag)
+			entries, err := internalarchive.Extract(archivePath, dir, internalarchive.ExtractOptions{
+				Format:  format,
+				Include: include,
+				Exclude: exclude,
+				DryRun:  dryRun,
+			})
+			if err != nil {
+				return fmt.Errorf("extract %s: %w", archivePath, err)
+			}
+			if dryRun {
+				plan := ui.NewPlan()
+				for _, e := range entries {
+					target := filepath.Join(dir, filepath.FromSlash(e.Name))
+					switch e.Type {
+					case internalarchive.TypeDir:
+						plan.Add("create", target, "directory")
+					case internalarchive.TypeSymlink:
+						plan.Add("write", target, "symlink")
+					default:
+						plan.Add("write", target, fmt.Sprintf("%d bytes", e.SizeBytes))
+					}
+				}
+				return ui.PrintPlan(cmd.OutOrStdout(), outFormat, plan)
+			}
+
+			payload := extractOutput{Archive: archivePath, Format: format, Dir: dir, Entries: entries}
+			return ui.PrintOutput(cmd.OutOrStdout(), outFormat, payload, func() (string, error) {
+				return fmt.Sprintf("extracted %d entries from %s into %s\n", len(entries), archivePath, dir), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&dir, "dir", "C", ".", "Destination directory")
+	cmd.Flags().StringArrayVar(&include, "include", nil, "Only extract files matching this glob (repeatable)")
+	cmd.Flags().StringArrayVar(&exclude, "exclude", nil, "Skip files and directories matching this glob (repeatable)")
+	cmd.Flags().StringVar(&format, "format", "", "Archive format: "+strings.Join(internalarchive.Formats, ", ")+" (default: from the extension)")
+	_ = cmd.RegisterFlagCompletionFunc("format", completion.Fixed(internalarchive.Formats...))
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
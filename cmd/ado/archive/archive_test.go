@@ -0,0 +1,160 @@
+package archive
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/digest"
+)
+
+func execute(args ...string) (string, error) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().Bool("dry-run", false, "")
+	root.AddCommand(NewCommand())
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"archive"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestArchiveCommand_CreateExtract(t *testing.T) {
+	src := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(src, "docs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"docs/a.md": "a", "main.go": "package main\n"} {
+		if err := os.WriteFile(filepath.Join(src, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Written inside the source tree: the archive must not include itself.
+	archivePath := filepath.Join(src, "out.tar.gz")
+
+	out, err := execute("create", "-C", src, archivePath, ".", "--reproducible", "--checksum", "-o", "json")
+	if err != nil {
+		t.Fatalf("create error = %v", err)
+	}
+	var created createOutput
+	if err := json.Unmarshal([]byte(out), &created); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if created.Format != "tar.gz" || len(created.Entries) != 3 || created.Checksum == nil {
+		t.Fatalf("created = %+v", created)
+	}
+
+	sums, err := os.ReadFile(archivePath + ".sha256")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entry, err := digest.Path(archivePath, digest.SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := entry.Digest + "  out.tar.gz\n"; string(sums) != want {
+		t.Errorf("checksum file = %q, want %q", sums, want)
+	}
+
+	dest := t.TempDir()
+	out, err = execute("extract", archivePath, "-C", dest, "--exclude", "*.go")
+	if err != nil {
+		t.Fatalf("extract error = %v", err)
+	}
+	if want := "extracted 2 entries from " + archivePath + " into " + dest + "\n"; out != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+	if got, err := os.ReadFile(filepath.Join(dest, "docs/a.md")); err != nil || string(got) != "a" {
+		t.Errorf("docs/a.md = %q, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "main.go")); !os.IsNotExist(err) {
+		t.Errorf("excluded main.go was extracted: %v", err)
+	}
+}
+
+func TestArchiveCommand_CreateText(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "f.txt"), []byte("f"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "f.bin")
+
+	out, err := execute("create", "-C", src, "--format", "zip", archivePath, "f.txt")
+	if err != nil {
+		t.Fatalf("create error = %v", err)
+	}
+	if !strings.HasPrefix(out, "created "+archivePath+" (zip, 1 entries, ") {
+		t.Errorf("output = %q", out)
+	}
+	leftovers, _ := filepath.Glob(filepath.Join(filepath.Dir(archivePath), ".f.bin.*"))
+	if len(leftovers) != 0 {
+		t.Errorf("temporary files left behind: %v", leftovers)
+	}
+}
+
+func TestArchiveCommand_DryRun(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "f.txt"), []byte("four"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	archivePath := filepath.Join(t.TempDir(), "f.zip")
+
+	out, err := execute("create", "-C", src, archivePath, "f.txt", "--checksum", "--dry-run")
+	if err != nil {
+		t.Fatalf("create --dry-run error = %v", err)
+	}
+	want := "Would write " + archivePath + ": zip, 1 entries\nWould write " + archivePath + ".sha256: sha256 checksum\nDry run: nothing was changed.\n"
+	if out != want {
+		t.Errorf("create --dry-run output = %q, want %q", out, want)
+	}
+	if entries, _ := os.ReadDir(filepath.Dir(archivePath)); len(entries) != 0 {
+		t.Fatalf("create --dry-run wrote %v", entries)
+	}
+
+	if _, err := execute("create", "-C", src, archivePath, "f.txt"); err != nil {
+		t.Fatal(err)
+	}
+	dest := filepath.Join(t.TempDir(), "out")
+	out, err = execute("extract", archivePath, "-C", dest, "--dry-run")
+	if err != nil {
+		t.Fatalf("extract --dry-run error = %v", err)
+	}
+	if want := "Would write " + filepath.Join(dest, "f.txt") + ": 4 bytes\nDry run: nothing was changed.\n"; out != want {
+		t.Errorf("extract --dry-run output = %q, want %q", out, want)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("extract --dry-run created %s: %v", dest, err)
+	}
+}
+
+func TestArchiveCommand_Errors(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown extension", []string{"create", filepath.Join(dir, "out.rar"), "."}, "cannot tell the format"},
+		{"bad format", []string{"create", "--format", "rar", filepath.Join(dir, "out"), "."}, `unsupported format "rar"`},
+		{"bad algorithm", []string{"create", "--checksum", "-a", "crc", filepath.Join(dir, "out.zip"), "."}, "crc"},
+		{"missing source", []string{"create", "-C", dir, filepath.Join(dir, "out.zip"), "missing"}, "missing"},
+		{"missing archive", []string{"extract", filepath.Join(dir, "none.zip")}, "extract"},
+		{"bad output", []string{"extract", "-o", "xml", "x.zip"}, "xml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := execute(tt.args...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+	if leftovers, _ := filepath.Glob(filepath.Join(dir, "*")); len(leftovers) != 0 {
+		t.Errorf("failed runs left files behind: %v", leftovers)
+	}
+}
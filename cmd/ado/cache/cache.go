@@ -0,0 +1,249 @@
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/cache"
+	"github.com/anowarislam/ado/internal/completion"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// NewCommand returns the cache command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Inspect and clean ado's cache directory",
+		Long: `Report and clean what ado keeps in its cache directory: data it can
+fetch or compute again, such as the result of the update check. Removing
+any of it is safe; ado recreates what it needs.
+
+Categories:
+` + categoryHelp(),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newInfoCommand(),
+		newCleanCommand(),
+	)
+	return cmd
+}
+
+// categorySummary totals the entries of a category.
+type categorySummary struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Entries     int    `json:"entries" yaml:"entries"`
+	SizeBytes   int64  `json:"size_bytes" yaml:"size_bytes"`
+	// Oldest is when the least recently written entry was written.
+	Oldest *time.Time `json:"oldest,omitempty" yaml:"oldest,omitempty"`
+}
+
+type infoOutput struct {
+	Dir        string            `json:"dir" yaml:"dir"`
+	SizeBytes  int64             `json:"size_bytes" yaml:"size_bytes"`
+	Categories []categorySummary `json:"categories" yaml:"categories"`
+	Entries    []cache.Entry     `json:"entries" yaml:"entries"`
+}
+
+func newInfoCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "info",
+		Short: "Show what the cache holds",
+		Long: `Show the cache directory and, for each category, how many entries it
+holds, their total size, and the age of the oldest one, followed by the
+entries themselves.
+
+Examples:
+  # Summary of the cache
+  ado cache info
+
+  # Entries as JSON
+  ado cache info -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			c, err := cache.Default()
+			if err != nil {
+				return err
+			}
+			entries, err := c.Entries()
+			if err != nil {
+				return err
+			}
+			payload := summarize(c.Dir, entries)
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatInfo(payload, time.Now()), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func newCleanCommand() *cobra.Command {
+	var (
+		categories []string
+		olderThan  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove cached data",
+		Long: `Remove the entries of the cache directory, all of them by default, or
+only those in --category last written longer ago than --older-than.
+
+Examples:
+  # Empty the cache
+  ado cache clean
+
+  # Forget the last update check
+  ado cache clean --category update-check
+
+  # See what untouched for a month would be removed
+  ado cache clean --older-than 720h --dry-run`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if olderThan < 0 {
+				return errors.New("--older-than must not be negative")
+			}
+			if err := cache.ValidateCategories(categories); err != nil {
+				return err
+			}
+			c, err := cache.Default()
+			if err != nil {
+				return err
+			}
+			entries, err := c.Entries()
+			if err != nil {
+				return err
+			}
+			var cutoff time.Time
+			if olderThan > 0 {
+				cutoff = time.Now().Add(-olderThan)
+			}
+			selected := cache.Select(entries, categories, cutoff)
+
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				plan := ui.NewPlan()
+				for _, e := range selected {
+					plan.Add("delete", e.Path, e.Category+", "+ui.FormatSize(e.SizeBytes))
+				}
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+			removed, err := c.Remove(selected)
+			var size int64
+			for _, e := range removed {
+				size += e.SizeBytes
+			}
+			if _, werr := fmt.Fprintf(cmd.OutOrStdout(), "Removed %d %s (%s) from %s\n", len(removed), ui.Plural(len(removed), "entry", "entries"), ui.FormatSize(size), c.Dir); werr != nil {
+				return werr
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&categories, "category", nil, "Only entries in this category (repeatable): "+strings.Join(cache.CategoryNames(), ", "))
+	cmd.Flags().DurationVar(&olderThan, "older-than", 0, "Only entries last written longer ago than this (0 for any age)")
+	_ = cmd.RegisterFlagCompletionFunc("category", completion.Fixed(cache.CategoryNames()...))
+	return cmd
+}
+
+// summarize totals entries by category, in the order of cache.Categories.
+func summarize(dir string, entries []cache.Entry) infoOutput {
+	out := infoOutput{Dir: dir, Entries: entries}
+	if out.Entries == nil {
+		out.Entries = []cache.Entry{}
+	}
+	for _, c := range cache.Categories {
+		sum := categorySummary{Name: c.Name, Description: c.Description}
+		for _, e := range entries {
+			if e.Category != c.Name {
+				continue
+			}
+			sum.Entries++
+			sum.SizeBytes += e.SizeBytes
+			if sum.Oldest == nil || e.Modified.Before(*sum.Oldest) {
+				modified := e.Modified
+				sum.Oldest = &modified
+			}
+		}
+		out.SizeBytes += sum.SizeBytes
+		out.Categories = append(out.Categories, sum)
+	}
+	return out
+}
+
+func formatInfo(info infoOutput, now time.Time) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Cache directory: %s\n\n", info.Dir)
+	fmt.Fprintf(&b, "%-12s  %7s  %10s  %s\n", "CATEGORY", "ENTRIES", "SIZE", "OLDEST")
+	total := 0
+	for _, c := range info.Categories {
+		oldest := "-"
+		if c.Oldest != nil {
+			oldest = formatAge(now.Sub(*c.Oldest))
+		}
+		fmt.Fprintf(&b, "%-12s  %7d  %10s  %s\n", c.Name, c.Entries, ui.FormatSize(c.SizeBytes), oldest)
+		total += c.Entries
+	}
+	fmt.Fprintf(&b, "%-12s  %7d  %10s\n", "total", total, ui.FormatSize(info.SizeBytes))
+
+	if len(info.Entries) == 0 {
+		return b.String()
+	}
+	names := make([]string, len(info.Entries))
+	width := 0
+	for i, e := range info.Entries {
+		name, err := filepath.Rel(info.Dir, e.Path)
+		if err != nil {
+			name = e.Path
+		}
+		names[i], width = name, max(width, len(name))
+	}
+	b.WriteString("\nEntries:\n")
+	for i, e := range info.Entries {
+		fmt.Fprintf(&b, "  %-*s  %-12s  %10s  %s\n", width, names[i], e.Category, ui.FormatSize(e.SizeBytes), formatAge(now.Sub(e.Modified)))
+	}
+	return b.String()
+}
+
+// categoryHelp lists the categories for the help text.
+func categoryHelp() string {
+	var b strings.Builder
+	for _, c := range cache.Categories {
+		fmt.Fprintf(&b, "  %-14s %s\n", c.Name, c.Description)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// formatAge renders how long ago something was written, in its largest
+// whole unit.
+func formatAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
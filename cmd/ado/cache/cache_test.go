@@ -0,0 +1,185 @@
+package cache
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/cache"
+	"github.com/anowarislam/ado/internal/ui"
+	"github.com/anowarislam/ado/internal/ui/uitest"
+)
+
+func execute(args ...string) (string, error) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().Bool(ui.DryRunFlag, false, "")
+	root.AddCommand(NewCommand())
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"cache"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+// writeCache fills a new cache directory with an update check, files of
+// an older ado, and the state directory, and returns the cache directory.
+func writeCache(t *testing.T) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(home, "cache"))
+	t.Setenv("XDG_STATE_HOME", filepath.Join(home, "cache", "ado", "state"))
+	dir := filepath.Join(home, "cache", "ado")
+	files := map[string]struct {
+		size int
+		age  time.Duration
+	}{
+		"update-check.json":   {200, 3 * time.Hour},
+		"sysinfo/cpu.json":    {1024, 40 * 24 * time.Hour},
+		"sysinfo/gpu.json":    {512, 45 * 24 * time.Hour},
+		"state/ado/data.json": {10, 0},
+	}
+	for name, f := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, f.size), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		mtime := time.Now().Add(-f.age)
+		if err := os.Chtimes(path, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestCacheInfo(t *testing.T) {
+	dir := writeCache(t)
+	out, err := execute("info", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got infoOutput
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	if got.Dir != dir || got.SizeBytes != 1736 || len(got.Entries) != 2 {
+		t.Errorf("info = %+v", got)
+	}
+	if c := got.Categories[1]; c.Name != cache.CategoryOther || c.Entries != 1 || c.SizeBytes != 1536 {
+		t.Errorf("other = %+v", c)
+	}
+}
+
+func TestFormatInfo(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	dir := filepath.FromSlash("/home/u/.cache/ado")
+	info := summarize(dir, []cache.Entry{
+		{Category: cache.CategoryOther, Path: filepath.Join(dir, "sysinfo"), SizeBytes: 1536, Modified: now.Add(-40 * 24 * time.Hour)},
+		{Category: cache.CategoryUpdateCheck, Path: filepath.Join(dir, "update-check.json"), SizeBytes: 200, Modified: now.Add(-3 * time.Hour)},
+	})
+	uitest.Golden(t, "info", strings.ReplaceAll(formatInfo(info, now), dir, "/home/u/.cache/ado"))
+
+	empty := formatInfo(summarize(dir, nil), now)
+	if strings.Contains(empty, "Entries:") || !strings.Contains(empty, "update-check        0         0 B  -") {
+		t.Errorf("empty cache:\n%s", empty)
+	}
+}
+
+func TestCacheClean(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		wantOut   string
+		remaining []string
+	}{
+		{
+			name:      "all",
+			wantOut:   "Removed 2 entries (1.7 KiB)",
+			remaining: []string{"state"},
+		},
+		{
+			name:      "category",
+			args:      []string{"--category", "update-check"},
+			wantOut:   "Removed 1 entry (200 B)",
+			remaining: []string{"state", "sysinfo"},
+		},
+		{
+			name:      "older than",
+			args:      []string{"--older-than", "720h"},
+			wantOut:   "Removed 1 entry (1.5 KiB)",
+			remaining: []string{"state", "update-check.json"},
+		},
+		{
+			name:      "none",
+			args:      []string{"--category", "update-check", "--older-than", "24h"},
+			wantOut:   "Removed 0 entries (0 B)",
+			remaining: []string{"state", "sysinfo", "update-check.json"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeCache(t)
+			out, err := execute(append([]string{"clean"}, tt.args...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out, tt.wantOut) {
+				t.Errorf("output = %q, want %q", out, tt.wantOut)
+			}
+			assertRemaining(t, dir, tt.remaining)
+		})
+	}
+}
+
+func TestCacheClean_DryRun(t *testing.T) {
+	dir := writeCache(t)
+	out, err := execute("clean", "--category", "other", "--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, filepath.Join(dir, "sysinfo")) || strings.Contains(out, "update-check.json") {
+		t.Errorf("plan:\n%s", out)
+	}
+	assertRemaining(t, dir, []string{"state", "sysinfo", "update-check.json"})
+}
+
+func TestCacheClean_Errors(t *testing.T) {
+	writeCache(t)
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{args: []string{"--category", "downloads"}, want: `unknown cache category "downloads"`},
+		{args: []string{"--older-than", "-1h"}, want: "--older-than must not be negative"},
+	}
+	for _, tt := range tests {
+		_, err := execute(append([]string{"clean"}, tt.args...)...)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("clean %v: err = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+}
+
+func assertRemaining(t *testing.T, dir string, want []string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("remaining = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,198 @@
+package check
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	internalchecks "github.com/anowarislam/ado/internal/checks"
+	"github.com/anowarislam/ado/internal/completion"
+	"github.com/anowarislam/ado/internal/junit"
+	"github.com/anowarislam/ado/internal/tap"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// NewCommand returns the check command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "check",
+		Short: "Run health checks declared in YAML",
+		Long: `Run health checks declared in a YAML file: http, tcp, dns, exec, disk
+space, and certificate expiry probes with thresholds and severities.`,
+	}
+	cmd.AddCommand(newRunCommand())
+	return cmd
+}
+
+func newRunCommand() *cobra.Command {
+	var (
+		output string
+		jobs   int
+		failOn string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run FILE",
+		Short: "Run the checks of a checks file",
+		Long: `Run every check of a checks file, --jobs at a time, and report each
+check's outcome and the overall status: the severity of the most severe
+failed check, or ok.
+
+Each check has a name, one probe, and optionally a severity (critical
+unless defaults: sets another; warning and info are the others) and a
+timeout (10s unless defaults: sets another):
+
+  http   url, method, status (default: any 2xx), contains, max_latency
+  tcp    address (HOST:PORT), max_latency
+  dns    name, type (A, AAAA, CNAME, MX, TXT, NS), server, expect
+  exec   command, args, exit_code (default 0); relative commands and the
+         working directory are those of the checks file
+  disk   path, min_free_mb, max_used_percent
+  cert   address (HOST[:PORT] or https:// URL), server_name, min_days,
+         skip_verify
+
+Text output prints each check as it finishes. JSON and YAML print a
+report of every check, junit a test case per check, and tap a test point
+per check. ado exits 1 when a check of --fail-on severity or worse fails.
+
+Examples:
+  # Run the checks
+  ado check run checks.yaml
+
+  # Fail on warnings too, with a JUnit report for CI
+  ado check run checks.yaml --fail-on warning -o junit > checks.xml
+
+A checks file:
+  defaults:
+    timeout: 5s
+  checks:
+    - name: api
+      http:
+        url: https://api.example.com/healthz
+        contains: '"status":"ok"'
+        max_latency: 500ms
+    - name: postgres
+      tcp:
+        address: db.internal:5432
+    - name: root disk
+      severity: warning
+      disk:
+        path: /
+        min_free_mb: 10240
+    - name: certificate
+      cert:
+        address: api.example.com
+        min_days: 14`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output, ui.OutputJUnit, ui.OutputTAP)
+			if err != nil {
+				return err
+			}
+			if jobs < 1 {
+				return fmt.Errorf("--jobs must be at least 1")
+			}
+			if !slices.Contains(internalchecks.Severities, failOn) {
+				return fmt.Errorf("invalid --fail-on %q (valid: %s)", failOn, strings.Join(internalchecks.Severities, ", "))
+			}
+			file, err := internalchecks.Load(args[0])
+			if err != nil {
+				return err
+			}
+
+			runner := internalchecks.Runner{Jobs: jobs}
+			if format == ui.OutputText {
+				runner.OnDone = func(r internalchecks.Result) {
+					fmt.Fprintln(cmd.OutOrStdout(), formatResult(r))
+				}
+			}
+			report := runner.Run(cmd.Context(), file)
+
+			switch format {
+			case ui.OutputText:
+				fmt.Fprintln(cmd.OutOrStdout(), formatSummary(report))
+			case ui.OutputJUnit:
+				err = junit.Write(cmd.OutOrStdout(), "ado check run", checkSuite(args[0], report))
+			case ui.OutputTAP:
+				err = tap.Write(cmd.OutOrStdout(), checkTests(report))
+			default:
+				err = ui.PrintOutput(cmd.OutOrStdout(), format, report, nil)
+			}
+			if err != nil {
+				return err
+			}
+			if failed := report.FailedAtLeast(failOn); failed > 0 {
+				return ui.Reported(fmt.Errorf("%d of %d checks failed at %s severity or worse", failed, report.Total, failOn))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml, junit, tap")
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", internalchecks.DefaultJobs, "Maximum number of checks running at once")
+	cmd.Flags().StringVar(&failOn, "fail-on", internalchecks.SeverityCritical, "Exit 1 when a check of this severity or worse fails: info, warning, critical")
+	_ = cmd.RegisterFlagCompletionFunc("output", completion.Fixed("text", "json", "yaml", "junit", "tap"))
+	_ = cmd.RegisterFlagCompletionFunc("fail-on", completion.Fixed(internalchecks.Severities...))
+	return cmd
+}
+
+// formatResult is the line printed as a check finishes.
+func formatResult(r internalchecks.Result) string {
+	if r.Status == internalchecks.StatusPass {
+		return fmt.Sprintf("PASS  %s (%s): %s", r.Name, r.Type, r.Message)
+	}
+	return fmt.Sprintf("FAIL  %s (%s, %s): %s", r.Name, r.Type, r.Severity, r.Message)
+}
+
+func formatSummary(r internalchecks.Report) string {
+	return fmt.Sprintf("\nStatus: %s (%d passed, %d failed, %d checks in %s)",
+		r.Status, r.Passed, r.Failed, r.Total, time.Duration(r.DurationMS)*time.Millisecond)
+}
+
+// checkSuite reports r as a JUnit suite called name, with one case per
+// check.
+func checkSuite(name string, r internalchecks.Report) junit.TestSuite {
+	cases := make([]junit.TestCase, len(r.Results))
+	for i, res := range r.Results {
+		c := junit.TestCase{
+			Name:      res.Name,
+			Classname: res.Type,
+			Time:      junit.Seconds(time.Duration(res.DurationMS) * time.Millisecond),
+		}
+		if res.Status == internalchecks.StatusFail {
+			c.Failure = &junit.Failure{Message: res.Message, Type: res.Severity}
+		} else {
+			c.SystemOut = res.Message
+		}
+		cases[i] = c
+	}
+	suite := junit.NewSuite(name, cases...)
+	suite.Time = junit.Seconds(time.Duration(r.DurationMS) * time.Millisecond)
+	return suite
+}
+
+// checkTests reports r as TAP test points.
+func checkTests(r internalchecks.Report) []tap.Test {
+	tests := make([]tap.Test, len(r.Results))
+	for i, res := range r.Results {
+		tests[i] = tap.Test{OK: res.Status == internalchecks.StatusPass, Description: res.Name}
+		if !tests[i].OK {
+			tests[i].Diagnostics = map[string]any{
+				"type":     res.Type,
+				"target":   res.Target,
+				"severity": res.Severity,
+				"message":  res.Message,
+			}
+		}
+	}
+	return tests
+}
@@ -0,0 +1,151 @@
+package check
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	internalchecks "github.com/anowarislam/ado/internal/checks"
+	"github.com/anowarislam/ado/internal/junit"
+	"github.com/anowarislam/ado/internal/ui"
+	"github.com/anowarislam/ado/internal/ui/uitest"
+)
+
+func execute(args ...string) (stdout string, err error) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewCommand())
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"check"}, args...))
+	err = root.Execute()
+	return out.String(), err
+}
+
+// writeChecks writes a checks file with a passing critical disk check
+// and a failing warning one.
+func writeChecks(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "checks.yaml")
+	content := `
+checks:
+  - name: space
+    disk: {path: ., min_free_mb: 1}
+  - name: lots of space
+    severity: warning
+    disk: {path: ., min_free_mb: 1099511627776}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestCheckRun_Text(t *testing.T) {
+	out, err := execute("run", writeChecks(t), "-j", "1")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	for _, want := range []string{"PASS  space (disk): ", "FAIL  lots of space (disk, warning): ", "Status: warning (1 passed, 1 failed, 2 checks in "} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCheckRun_FailOn(t *testing.T) {
+	_, err := execute("run", writeChecks(t), "--fail-on", "warning")
+	if err == nil || !ui.IsReported(err) || err.Error() != "1 of 2 checks failed at warning severity or worse" {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestCheckRun_JSON(t *testing.T) {
+	out, err := execute("run", writeChecks(t), "-o", "json")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	var report internalchecks.Report
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if report.Status != internalchecks.SeverityWarning || len(report.Results) != 2 || report.Results[1].Status != internalchecks.StatusFail {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestCheckRun_JUnit(t *testing.T) {
+	out, err := execute("run", writeChecks(t), "-o", "junit")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	var suites junit.TestSuites
+	if err := xml.Unmarshal([]byte(out), &suites); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+	if suites.Tests != 2 || suites.Failures != 1 || suites.Suites[0].Cases[1].Failure.Type != "warning" {
+		t.Errorf("suites = %+v", suites)
+	}
+}
+
+func TestCheckRun_TAP(t *testing.T) {
+	out, err := execute("run", writeChecks(t), "-o", "tap")
+	if err != nil {
+		t.Fatalf("error = %v", err)
+	}
+	for _, want := range []string{"1..2", "ok 1 - space", "not ok 2 - lots of space", "severity: warning"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestCheckRun_Errors(t *testing.T) {
+	path := writeChecks(t)
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"missing file", []string{"run", filepath.Join(t.TempDir(), "missing.yaml")}, "open checks"},
+		{"invalid file", []string{"run", writeFile(t, "checks: [{name: x}]")}, `check "x": needs one of http`},
+		{"fail-on", []string{"run", path, "--fail-on", "error"}, `invalid --fail-on "error"`},
+		{"jobs", []string{"run", path, "-j", "0"}, "--jobs must be at least 1"},
+		{"output", []string{"run", path, "-o", "xml"}, "unsupported output format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := execute(tt.args...); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatResult(t *testing.T) {
+	results := []internalchecks.Result{
+		{Name: "api", Type: "http", Severity: "critical", Status: internalchecks.StatusPass, Message: "status 200 in 12ms"},
+		{Name: "cert", Type: "cert", Severity: "warning", Status: internalchecks.StatusFail, Message: "expires in 5 days (2026-10-21), want at least 14"},
+	}
+	var lines []string
+	for _, r := range results {
+		lines = append(lines, formatResult(r))
+	}
+	lines = append(lines, formatSummary(internalchecks.Report{Status: "warning", Total: 2, Passed: 1, Failed: 1, DurationMS: 1500, Results: results}))
+	uitest.Golden(t, "results", strings.Join(lines, "\n")+"\n")
+}
+
+func writeFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "checks.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
@@ -1,31 +1,44 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
 
+	"github.com/anowarislam/ado/internal/completion"
 	internalconfig "github.com/anowarislam/ado/internal/config"
+	internaldiff "github.com/anowarislam/ado/internal/diff"
+	"github.com/anowarislam/ado/internal/ghactions"
+	"github.com/anowarislam/ado/internal/junit"
+	"github.com/anowarislam/ado/internal/tap"
 	"github.com/anowarislam/ado/internal/ui"
+	"github.com/anowarislam/ado/internal/validation"
 )
 
+// DefaultsFunc lists the keys of a defaults: section that name no command
+// or flag of the command tree below root.
+type DefaultsFunc func(root *cobra.Command, defaults map[string]any) []string
+
 // NewCommand returns the config parent command with subcommands.
-func NewCommand() *cobra.Command {
+// `config validate` reports the keys defaults finds, if it is not nil.
+func NewCommand(defaults DefaultsFunc) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "config",
 		Short: "Manage ado configuration",
 	}
 
 	cmd.AddCommand(
-		newValidateCommand(),
+		newValidateCommand(defaults),
+		newDiffCommand(),
 	)
 
 	return cmd
 }
 
-func newValidateCommand() *cobra.Command {
+func newValidateCommand(defaults DefaultsFunc) *cobra.Command {
 	var (
 		filePath string
 		strict   bool
@@ -57,37 +70,45 @@ func newValidateCommand() *cobra.Command {
 				path = resolved
 			}
 
-			// Validate
-			result, err := internalconfig.Validate(path)
+			// Validate, expanding references like the commands that
+			// load the config
+			configFlag, _ := cmd.Root().PersistentFlags().GetString("config")
+			homeDir, _ := os.UserHomeDir()
+			expansion := internalconfig.PathResolverFor(cmd.Context(), configFlag, homeDir).Expansion()
+			result, err := internalconfig.ValidateMode(path, expansion)
 			if err != nil {
 				return fmt.Errorf("validation failed: %w", err)
 			}
+			if result.Valid && defaults != nil {
+				if cfg, err := internalconfig.LoadMode(path, expansion); err == nil {
+					for _, msg := range defaults(cmd.Root(), cfg.Defaults) {
+						result.Warnings = append(result.Warnings, internalconfig.ValidationIssue{
+							Message:  msg,
+							Severity: "warning",
+							Rule:     internalconfig.RuleUnknownKey,
+						})
+					}
+				}
+			}
 
 			// In strict mode, warnings become errors
-			if strict && result.HasWarnings() {
-				for _, w := range result.Warnings {
-					result.Errors = append(result.Errors, internalconfig.ValidationIssue{
-						Message:  w.Message,
-						Line:     w.Line,
-						Severity: "error",
-					})
-				}
-				result.Warnings = []internalconfig.ValidationIssue{}
-				result.Valid = false
+			if strict {
+				validation.Strict(result)
 			}
 
 			// Output
-			format, err := ui.ParseOutputFormat(output)
+			format, err := ui.ParseOutputFormat(output, validation.Formats...)
 			if err != nil {
 				return err
 			}
-
-			err = ui.PrintOutput(cmd.OutOrStdout(), format, result, func() (string, error) {
-				return formatValidationResult(result), nil
-			})
-			if err != nil {
+			if err := configReporter.Write(cmd.OutOrStdout(), format, result); err != nil {
 				return err
 			}
+			if ghactions.Enabled() {
+				if err := configReporter.ReportToActions(cmd.OutOrStdout(), format, result); err != nil {
+					return err
+				}
+			}
 
 			// Exit with error code if invalid
 			if !result.Valid {
@@ -100,37 +121,101 @@ func newValidateCommand() *cobra.Command {
 
 	cmd.Flags().StringVarP(&filePath, "file", "f", "", "Path to config file to validate")
 	cmd.Flags().BoolVarP(&strict, "strict", "s", false, "Treat warnings as errors")
-	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, junit, sarif, tap")
+	_ = cmd.MarkFlagFilename("file", "yaml", "yml")
+	_ = cmd.RegisterFlagCompletionFunc("output", completion.Fixed("text", "json", "junit", "sarif", "tap"))
 
 	return cmd
 }
 
-func formatValidationResult(result *internalconfig.ValidationResult) string {
-	var b strings.Builder
+func newDiffCommand() *cobra.Command {
+	var (
+		output   string
+		exitCode bool
+	)
 
-	if result.Valid {
-		fmt.Fprintf(&b, "\u2713 Config valid: %s", result.Path)
-	} else {
-		fmt.Fprintf(&b, "\u2717 Config invalid: %s", result.Path)
-	}
+	cmd := &cobra.Command{
+		Use:   "diff [FILE [FILE]]",
+		Short: "Show how a configuration differs from defaults or another file",
+		Long: `Compare effective configurations structurally, after defaults are applied.
 
-	for _, e := range result.Errors {
-		b.WriteString("\n")
-		if e.Line > 0 {
-			fmt.Fprintf(&b, "  Error: %s at line %d", e.Message, e.Line)
-		} else {
-			fmt.Fprintf(&b, "  Error: %s", e.Message)
-		}
-	}
+With no arguments, compare the active config file (--config, ADO_CONFIG, or
+the search path) with the built-in defaults, showing what it changes. With
+one file, compare that file with the defaults. With two files, compare
+them with each other.
 
-	for _, w := range result.Warnings {
-		b.WriteString("\n")
-		if w.Line > 0 {
-			fmt.Fprintf(&b, "  Warning: %s at line %d", w.Message, w.Line)
-		} else {
-			fmt.Fprintf(&b, "  Warning: %s", w.Message)
-		}
+Output formats match ado diff: text, unified, patch, json, yaml.`,
+		Args: cobra.MaximumNArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := internaldiff.ValidateOutput(output); err != nil {
+				return err
+			}
+
+			var paths []string
+			switch len(args) {
+			case 0:
+				configFlag, _ := cmd.Root().PersistentFlags().GetString("config")
+				homeDir, _ := os.UserHomeDir()
+				_, path, err := internalconfig.LoadResolved(configFlag, homeDir)
+				if err != nil {
+					return err
+				}
+				if path == "" {
+					return errors.New("no config file found; pass a file to compare")
+				}
+				paths = []string{"", path}
+			case 1:
+				paths = []string{"", args[0]}
+			default:
+				paths = args
+			}
+
+			docs := make([]any, 2)
+			names := make([]string, 2)
+			for i, path := range paths {
+				cfg, err := internalconfig.Load(path)
+				if err != nil {
+					return fmt.Errorf("%s: %w", path, err)
+				}
+				if docs[i], err = internaldiff.FromValue(cfg); err != nil {
+					return err
+				}
+				names[i] = path
+				if path == "" {
+					names[i] = "defaults"
+				}
+			}
+
+			differ, err := internaldiff.Print(cmd.OutOrStdout(), output, docs[0], docs[1], names[0], names[1])
+			if err != nil {
+				return err
+			}
+			if differ && exitCode {
+				return internaldiff.ErrDiffer
+			}
+			return nil
+		},
 	}
 
-	return b.String()
+	cmd.Flags().StringVarP(&output, "output", "o", "text", internaldiff.OutputHelp)
+	_ = cmd.RegisterFlagCompletionFunc("output", completion.Fixed(internaldiff.Outputs...))
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit with status 1 when the configurations differ")
+	return cmd
+}
+
+// configReporter reports the results of ado config validate.
+var configReporter = validation.Reporter{Title: "ado config validate", Subject: "Config", Rules: internalconfig.ValidationRules}
+
+// validationSuite reports result as a JUnit suite.
+func validationSuite(result *internalconfig.ValidationResult) junit.TestSuite {
+	return configReporter.Suite(result)
+}
+
+// validationTests reports result as TAP test points.
+func validationTests(result *internalconfig.ValidationResult) []tap.Test {
+	return configReporter.Tests(result)
+}
+
+func formatValidationResult(result *internalconfig.ValidationResult) string {
+	return configReporter.Text(result)
 }
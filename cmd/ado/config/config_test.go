@@ -2,16 +2,19 @@ package config
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
 	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/sarif"
+	"github.com/anowarislam/ado/internal/ui/uitest"
 )
 
 func TestNewCommand(t *testing.T) {
-	cmd := NewCommand()
+	cmd := NewCommand(nil)
 
 	if cmd.Use != "config" {
 		t.Errorf("Use = %q, want %q", cmd.Use, "config")
@@ -23,8 +26,10 @@ func TestNewCommand(t *testing.T) {
 		subcommands[sub.Name()] = true
 	}
 
-	if !subcommands["validate"] {
-		t.Error("expected subcommand 'validate' not found")
+	for _, name := range []string{"validate", "diff"} {
+		if !subcommands[name] {
+			t.Errorf("expected subcommand %q not found", name)
+		}
 	}
 }
 
@@ -36,7 +41,7 @@ func TestConfigValidate_ValidFile(t *testing.T) {
 		t.Fatalf("write temp file: %v", err)
 	}
 
-	cmd := NewCommand()
+	cmd := NewCommand(nil)
 	var buf bytes.Buffer
 	cmd.SetOut(&buf)
 	cmd.SetArgs([]string{"validate", "--file", configPath})
@@ -62,7 +67,7 @@ func TestConfigValidate_WithWarning(t *testing.T) {
 		t.Fatalf("write temp file: %v", err)
 	}
 
-	cmd := NewCommand()
+	cmd := NewCommand(nil)
 	var buf bytes.Buffer
 	cmd.SetOut(&buf)
 	cmd.SetArgs([]string{"validate", "--file", configPath})
@@ -84,6 +89,162 @@ func TestConfigValidate_WithWarning(t *testing.T) {
 	}
 }
 
+func TestConfigValidate_GitHubActions(t *testing.T) {
+	workspace := t.TempDir()
+	configPath := filepath.Join(workspace, "ado.yaml")
+	if err := os.WriteFile(configPath, []byte("version: 1\nunknown_key: value\n"), 0644); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+	summary := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_WORKSPACE", workspace)
+	t.Setenv("GITHUB_STEP_SUMMARY", summary)
+
+	cmd := NewCommand(nil)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"validate", "--file", configPath})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := `::warning file=ado.yaml,line=2,title=ado config validate::unknown key "unknown_key"`
+	if !strings.Contains(buf.String(), want) {
+		t.Errorf("output missing annotation %q:\n%s", want, buf.String())
+	}
+	data, err := os.ReadFile(summary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"### \u2713 Config valid: `ado.yaml`", `| warning | 2 | unknown key "unknown_key" |`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("summary missing %q:\n%s", want, data)
+		}
+	}
+
+	// Structured output stays parseable: the summary still gets the
+	// result, but no annotations are mixed in.
+	cmd = NewCommand(nil)
+	buf.Reset()
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"validate", "--file", configPath, "--output", "json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "::warning") {
+		t.Errorf("json output has annotations:\n%s", buf.String())
+	}
+}
+
+func TestConfigValidate_JUnitOutput(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("version: 1\nunknown_key: value\n"), 0644); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+
+	cmd := NewCommand(nil)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"validate", "--file", configPath, "--output", "junit"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	for _, want := range []string{
+		`<testsuites name="ado config validate" tests="1" failures="0" skipped="0">`,
+		`<testcase name="line 2: unknown key &#34;unknown_key&#34;" classname="` + configPath + `">`,
+		`<system-out>warning: unknown key &#34;unknown_key&#34;</system-out>`,
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, buf.String())
+		}
+	}
+}
+
+func TestConfigValidate_SARIFOutput(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("version: 1\nunknown_key: value\n"), 0644); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+
+	cmd := NewCommand(nil)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"validate", "--file", configPath, "--output", "sarif"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var log sarif.Log
+	if err := json.Unmarshal(buf.Bytes(), &log); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if log.Version != "2.1.0" || len(log.Runs) != 1 {
+		t.Fatalf("log = %+v", log)
+	}
+	run := log.Runs[0]
+	if len(run.Tool.Driver.Rules) != len(internalconfig.ValidationRules) {
+		t.Errorf("rules = %d, want one per validation rule", len(run.Tool.Driver.Rules))
+	}
+	if len(run.Results) != 1 {
+		t.Fatalf("results = %+v, want one", run.Results)
+	}
+	got := run.Results[0]
+	if got.RuleID != internalconfig.RuleUnknownKey || run.Tool.Driver.Rules[got.RuleIndex].ID != got.RuleID || got.Level != "warning" {
+		t.Errorf("result = %+v", got)
+	}
+	if region := got.Locations[0].PhysicalLocation.Region; region == nil || region.StartLine != 2 {
+		t.Errorf("region = %+v, want line 2", region)
+	}
+}
+
+func TestConfigValidate_TAPOutput(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(configPath, []byte("version: 1\nunknown_key: value\n"), 0644); err != nil {
+		t.Fatalf("write temp file: %v", err)
+	}
+
+	cmd := NewCommand(nil)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"validate", "--file", configPath, "--output", "tap"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := "TAP version 13\n1..1\nok 1 - unknown key \"unknown_key\"\n  ---\n  file: " + configPath +
+		"\n  line: 2\n  rule: unknown-key\n  severity: warning\n  ...\n"
+	if buf.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	tests := validationTests(&internalconfig.ValidationResult{Path: "config.yaml", Valid: true})
+	if len(tests) != 1 || !tests[0].OK || tests[0].Description != "config.yaml is valid" {
+		t.Errorf("valid file tests = %+v", tests)
+	}
+}
+
+func TestValidationSuite(t *testing.T) {
+	suite := validationSuite(&internalconfig.ValidationResult{
+		Path: "config.yaml",
+		Errors: []internalconfig.ValidationIssue{
+			{Message: "missing required field: version", Severity: "error"},
+			{Message: `unknown key "x"`, Line: 4, Severity: "error"},
+		},
+	})
+	if suite.Tests != 2 || suite.Failures != 2 {
+		t.Errorf("tests = %d, failures = %d, want 2 and 2", suite.Tests, suite.Failures)
+	}
+	if got := suite.Cases[1].Name; got != `line 4: unknown key "x"` {
+		t.Errorf("case name = %q", got)
+	}
+
+	suite = validationSuite(&internalconfig.ValidationResult{Path: "config.yaml", Valid: true})
+	if suite.Tests != 1 || suite.Failures != 0 || suite.Cases[0].Name != "valid" {
+		t.Errorf("valid file suite = %+v", suite)
+	}
+}
+
 func TestConfigValidate_JSONOutput(t *testing.T) {
 	tmpDir := t.TempDir()
 	configPath := filepath.Join(tmpDir, "config.yaml")
@@ -91,7 +252,7 @@ func TestConfigValidate_JSONOutput(t *testing.T) {
 		t.Fatalf("write temp file: %v", err)
 	}
 
-	cmd := NewCommand()
+	cmd := NewCommand(nil)
 	var buf bytes.Buffer
 	cmd.SetOut(&buf)
 	cmd.SetArgs([]string{"validate", "--file", configPath, "--output", "json"})
@@ -112,9 +273,9 @@ func TestConfigValidate_JSONOutput(t *testing.T) {
 
 func TestFormatValidationResult(t *testing.T) {
 	tests := []struct {
-		name     string
-		result   *internalconfig.ValidationResult
-		contains []string
+		name   string
+		result *internalconfig.ValidationResult
+		golden string
 	}{
 		{
 			name: "valid config",
@@ -124,7 +285,7 @@ func TestFormatValidationResult(t *testing.T) {
 				Errors:   []internalconfig.ValidationIssue{},
 				Warnings: []internalconfig.ValidationIssue{},
 			},
-			contains: []string{"\u2713", "Config valid", "/path/to/config.yaml"},
+			golden: "validation_valid",
 		},
 		{
 			name: "invalid config with error",
@@ -136,7 +297,7 @@ func TestFormatValidationResult(t *testing.T) {
 				},
 				Warnings: []internalconfig.ValidationIssue{},
 			},
-			contains: []string{"\u2717", "Config invalid", "Error:", "missing version"},
+			golden: "validation_invalid",
 		},
 		{
 			name: "valid with warning",
@@ -148,17 +309,93 @@ func TestFormatValidationResult(t *testing.T) {
 					{Message: "unknown key", Line: 5, Severity: "warning"},
 				},
 			},
-			contains: []string{"\u2713", "Config valid", "Warning:", "unknown key", "line 5"},
+			golden: "validation_warning",
+		},
+		{
+			name: "invalid included file",
+			result: &internalconfig.ValidationResult{
+				Valid:    false,
+				Path:     "/path/to/config.yaml",
+				Includes: []string{"/path/to/conf.d/tasks.yaml"},
+				Errors: []internalconfig.ValidationIssue{
+					{Message: `task "test": missing required key "command"`, Line: 3, File: "/path/to/conf.d/tasks.yaml", Severity: "error"},
+				},
+				Warnings: []internalconfig.ValidationIssue{},
+			},
+			golden: "validation_include",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			output := formatValidationResult(tt.result)
-			for _, substr := range tt.contains {
-				if !strings.Contains(output, substr) {
-					t.Errorf("output missing %q: %s", substr, output)
+			uitest.Golden(t, tt.golden, formatValidationResult(tt.result))
+		})
+	}
+}
+
+func TestConfigDiff(t *testing.T) {
+	tmpDir := t.TempDir()
+	a := filepath.Join(tmpDir, "a.yaml")
+	b := filepath.Join(tmpDir, "b.yaml")
+	if err := os.WriteFile(a, []byte("version: 1\nupdates:\n  check: true\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("version: 1\nupdates:\n  check: true\n  channel: prerelease\ntasks:\n  test:\n    command: go\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "file against defaults",
+			args: []string{"diff", a},
+			want: "~ /updates/check: false -> true\n",
+		},
+		{
+			name: "two files",
+			args: []string{"diff", a, b},
+			want: "+ /tasks/test: {\"command\": \"go\"}\n~ /updates/channel: \"stable\" -> \"prerelease\"\n",
+		},
+		{
+			name: "equal files",
+			args: []string{"diff", a, a, "--exit-code"},
+			want: "",
+		},
+		{
+			name:    "exit code",
+			args:    []string{"diff", a, b, "--exit-code"},
+			wantErr: "documents differ",
+		},
+		{
+			name:    "missing file",
+			args:    []string{"diff", filepath.Join(tmpDir, "missing.yaml")},
+			wantErr: "read config",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCommand(nil)
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Execute() error = %v, want %q", err, tt.wantErr)
 				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", buf.String(), tt.want)
 			}
 		})
 	}
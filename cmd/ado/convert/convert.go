@@ -0,0 +1,121 @@
+package convert
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	internalconvert "github.com/anowarislam/ado/internal/convert"
+)
+
+// stdinName is the path that reads standard input.
+const stdinName = "-"
+
+// NewCommand returns the convert command.
+func NewCommand() *cobra.Command {
+	var (
+		from    string
+		to      string
+		compact bool
+	)
+
+	formats := strings.Join(internalconvert.Formats, ", ")
+
+	cmd := &cobra.Command{
+		Use:   "convert [FILE]",
+		Short: "Convert documents between JSON, YAML, TOML, and CSV",
+		Long: `Convert a document between JSON, YAML, TOML, and CSV.
+
+The input is read from FILE, or from stdin when FILE is omitted or "-".
+The input format is taken from --from, then from the file extension; for
+stdin without --from, input starting with { or [ is read as JSON and
+anything else as YAML.
+
+TOML output requires an object at the top level. CSV input uses the first
+row as the header and produces a list of objects with string values; CSV
+output requires a list of objects, with one column per key (sorted) and
+nested values written as JSON.
+
+Examples:
+  # YAML to JSON
+  ado convert config.yaml --to json
+
+  # Compact JSON from stdin
+  cat config.yaml | ado convert --from yaml --to json --compact
+
+  # JSON API response to CSV
+  ado http get :8080/users -b | ado convert --to csv`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			toFormat, err := internalconvert.ParseFormat(to)
+			if err != nil {
+				return fmt.Errorf("--to: %w", err)
+			}
+
+			name := stdinName
+			if len(args) == 1 {
+				name = args[0]
+			}
+
+			var data []byte
+			if name == stdinName {
+				data, err = io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("read stdin: %w", err)
+				}
+			} else {
+				data, err = os.ReadFile(name)
+				if err != nil {
+					return fmt.Errorf("read input: %w", err)
+				}
+			}
+
+			fromFormat, err := inputFormat(from, name, data)
+			if err != nil {
+				return err
+			}
+
+			v, err := internalconvert.Decode(data, fromFormat)
+			if err != nil {
+				return err
+			}
+			out, err := internalconvert.Encode(v, toFormat, internalconvert.Options{Compact: compact})
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "Input format: "+formats+" (default: from file extension)")
+	cmd.Flags().StringVar(&to, "to", "", "Output format: "+formats)
+	cmd.Flags().BoolVar(&compact, "compact", false, "Write JSON on a single line")
+	_ = cmd.RegisterFlagCompletionFunc("from", completion.Fixed(internalconvert.Formats...))
+	_ = cmd.RegisterFlagCompletionFunc("to", completion.Fixed(internalconvert.Formats...))
+	_ = cmd.MarkFlagRequired("to")
+	return cmd
+}
+
+// inputFormat resolves the input format from the flag, the file extension,
+// or the content of stdin, in that order.
+func inputFormat(flag, name string, data []byte) (string, error) {
+	if flag != "" {
+		f, err := internalconvert.ParseFormat(flag)
+		if err != nil {
+			return "", fmt.Errorf("--from: %w", err)
+		}
+		return f, nil
+	}
+	if name == stdinName {
+		return internalconvert.Sniff(data), nil
+	}
+	if f := internalconvert.FormatFromPath(name); f != "" {
+		return f, nil
+	}
+	return "", fmt.Errorf("cannot infer format of %s; use --from", name)
+}
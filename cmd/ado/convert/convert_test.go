@@ -0,0 +1,89 @@
+package convert
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func execute(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewCommand()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestConvert(t *testing.T) {
+	dir := t.TempDir()
+	yamlPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(yamlPath, []byte("name: ado\nport: 80\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	txtPath := filepath.Join(dir, "config.txt")
+	if err := os.WriteFile(txtPath, []byte("name = \"ado\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		want  string
+	}{
+		{"file by extension", "", []string{yamlPath, "--to", "json", "--compact"}, "{\"name\":\"ado\",\"port\":80}\n"},
+		{"stdin sniffed json", `{"a": [1]}`, []string{"--to", "yaml"}, "a:\n    - 1\n"},
+		{"stdin dash with --from", "a = 1\n", []string{"-", "--from", "toml", "--to", "json"}, "{\n  \"a\": 1\n}\n"},
+		{"from overrides extension", "", []string{txtPath, "--from", "toml", "--to", "yaml"}, "name: ado\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := execute(t, tt.stdin, tt.args...)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if out != tt.want {
+				t.Errorf("output = %q, want %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestConvert_Errors(t *testing.T) {
+	dir := t.TempDir()
+	txtPath := filepath.Join(dir, "config.txt")
+	if err := os.WriteFile(txtPath, []byte("a: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		want  string
+	}{
+		{"missing --to", "", []string{}, `required flag(s) "to"`},
+		{"bad --to", "", []string{"--to", "xml"}, "--to: unsupported format"},
+		{"bad --from", "", []string{"--from", "xml", "--to", "json"}, "--from: unsupported format"},
+		{"unknown extension", "", []string{txtPath, "--to", "json"}, "use --from"},
+		{"missing file", "", []string{"/nonexistent.yaml", "--to", "json"}, "no such file"},
+		{"toml needs object", "[1, 2]", []string{"--to", "toml"}, "requires an object"},
+		{"too many args", "", []string{"a", "b", "--to", "json"}, "accepts at most 1 arg(s)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := execute(t, tt.stdin, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,135 @@
+package debug
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/profiling"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// NewCommand returns the hidden debug parent command with subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:    "debug",
+		Short:  "Dump runtime profiles of the ado process",
+		Hidden: true,
+		Long: `Dump goroutine stacks, heap profiles, and GC statistics of this ado
+process, for investigating performance problems in the field.
+
+To profile a long-running command, start it with --pprof-listen instead
+(serve, watch, schedule run) and fetch profiles with go tool pprof:
+
+  ado serve --pprof-listen 127.0.0.1:6060
+  go tool pprof http://127.0.0.1:6060/debug/pprof/heap`,
+	}
+
+	cmd.AddCommand(
+		newGoroutinesCommand(),
+		newHeapCommand(),
+		newGCCommand(),
+	)
+	return cmd
+}
+
+func newGoroutinesCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "goroutines",
+		Short: "Print the stacks of all goroutines",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return profiling.WriteGoroutines(cmd.OutOrStdout())
+		},
+	}
+}
+
+func newHeapCommand() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "heap",
+		Short: "Write a heap profile for go tool pprof",
+		Long: `Run a garbage collection and write a gzipped heap profile to --file, or
+to stdout when it is not a terminal.
+
+Examples:
+  ado debug heap --file heap.pprof
+  go tool pprof -top heap.pprof`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if file == "" {
+				if ui.IsTerminal(cmd.OutOrStdout()) {
+					return errors.New("refusing to write a binary profile to the terminal; use --file or redirect stdout")
+				}
+				return profiling.WriteHeap(cmd.OutOrStdout())
+			}
+
+			f, err := os.Create(file)
+			if err != nil {
+				return err
+			}
+			if err := profiling.WriteHeap(f); err != nil {
+				f.Close()
+				return err
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.ErrOrStderr(), "Wrote heap profile to %s\n", file)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", "Write the profile to this file instead of stdout")
+	return cmd
+}
+
+func newGCCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Print garbage collector and memory statistics",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			stats := profiling.ReadGCStats()
+			return ui.PrintOutput(cmd.OutOrStdout(), format, stats, func() (string, error) {
+				return formatGCStats(stats), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func formatGCStats(s profiling.GCStats) string {
+	var b strings.Builder
+	lastGC := "never"
+	if !s.LastGC.IsZero() {
+		lastGC = s.LastGC.Format(time.RFC3339)
+	}
+	fmt.Fprintf(&b, "GC cycles:     %d (last %s)\n", s.NumGC, lastGC)
+	fmt.Fprintf(&b, "GC pause:      %s total", s.PauseTotal)
+	if len(s.RecentPauses) > 0 {
+		fmt.Fprintf(&b, ", recent %s", strings.Join(s.RecentPauses, " "))
+	}
+	b.WriteByte('\n')
+	fmt.Fprintf(&b, "Heap:          %s in use, %s reserved, %d objects\n", ui.FormatSize(int64(s.HeapAlloc)), ui.FormatSize(int64(s.HeapSys)), s.HeapObjects)
+	fmt.Fprintf(&b, "Next GC:       at %s\n", ui.FormatSize(int64(s.NextGC)))
+	fmt.Fprintf(&b, "Allocated:     %s total\n", ui.FormatSize(int64(s.TotalAlloc)))
+	fmt.Fprintf(&b, "From OS:       %s\n", ui.FormatSize(int64(s.Sys)))
+	fmt.Fprintf(&b, "Goroutines:    %d\n", s.Goroutines)
+	fmt.Fprintf(&b, "GOMAXPROCS:    %d\n", s.GOMAXPROCS)
+	fmt.Fprintf(&b, "Go:            %s\n", s.GoVersion)
+	return b.String()
+}
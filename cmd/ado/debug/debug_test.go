@@ -0,0 +1,65 @@
+package debug
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func execute(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return out.String(), err
+}
+
+func TestNewCommand_Hidden(t *testing.T) {
+	if !NewCommand().Hidden {
+		t.Error("debug command is not hidden")
+	}
+}
+
+func TestGoroutines(t *testing.T) {
+	out, err := execute(t, "goroutines")
+	if err != nil || !strings.Contains(out, "goroutine ") {
+		t.Errorf("goroutines = %q, %v", out, err)
+	}
+}
+
+func TestHeap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "heap.pprof")
+	if _, err := execute(t, "heap", "--file", path); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := os.Stat(path); err != nil || info.Size() == 0 {
+		t.Errorf("heap profile not written: %v", err)
+	}
+
+	out, err := execute(t, "heap")
+	if err != nil || !strings.HasPrefix(out, "\x1f\x8b") {
+		t.Errorf("heap to stdout = %d bytes, %v", len(out), err)
+	}
+}
+
+func TestGC(t *testing.T) {
+	out, err := execute(t, "gc")
+	if err != nil || !strings.Contains(out, "Goroutines:") || !strings.Contains(out, "Heap:") {
+		t.Errorf("gc = %q, %v", out, err)
+	}
+
+	out, err = execute(t, "gc", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var stats map[string]any
+	if err := json.Unmarshal([]byte(out), &stats); err != nil || stats["goroutines"] == nil {
+		t.Errorf("gc -o json = %q, %v", out, err)
+	}
+}
@@ -0,0 +1,59 @@
+package decode
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/codec"
+	"github.com/anowarislam/ado/internal/completion"
+)
+
+// NewCommand returns the decode command.
+func NewCommand() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "decode SCHEME [TEXT...]",
+		Short: "Decode base64, hex, or URL-escaped input",
+		Long: `Decode input with SCHEME and write the decoded bytes to stdout as they
+are, without adding a newline.
+
+The input is the TEXT arguments joined by spaces, or the file given with
+--file, or stdin. For base64, base64url, and hex, whitespace anywhere in
+the input is ignored, so wrapped output from other tools decodes, and
+base64 padding is optional. For url and urlpath, surrounding whitespace
+is ignored.
+
+Schemes: base64, base64url, hex, url, urlpath (see ado encode --help).
+
+Examples:
+  # Decode a token payload
+  ado decode base64url eyJzdWIiOiIxMjM0In0
+
+  # Restore a binary file
+  ado decode base64 --file logo.b64 > logo.png
+
+  # Read a URL-encoded value
+  ado decode url 'a%26b+c'`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completion.FirstArg(completion.Fixed(codec.Schemes...)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scheme, err := codec.ParseScheme(args[0])
+			if err != nil {
+				return err
+			}
+			data, err := codec.ReadInput(cmd.InOrStdin(), args[1:], file)
+			if err != nil {
+				return err
+			}
+			out, err := codec.Decode(scheme, data)
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", `Read input from a file ("-" for stdin)`)
+	return cmd
+}
@@ -0,0 +1,70 @@
+package decode
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func execute(stdin string, args ...string) (string, error) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewCommand())
+	var out bytes.Buffer
+	root.SetIn(strings.NewReader(stdin))
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"decode"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestDecodeCommand(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "in.b64")
+	if err := os.WriteFile(file, []byte("_wD-\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		want  string
+	}{
+		{"arg", "", []string{"base64", "dXNlcjpwYXNz"}, "user:pass"},
+		{"wrapped stdin", "dXNl\ncjpw\nYXNz\n", []string{"base64"}, "user:pass"},
+		{"file", "", []string{"base64url", "--file", file}, "\xff\x00\xfe"},
+		{"url", "", []string{"url", "a+b%26c"}, "a b&c"},
+		{"hex", "", []string{"hex", "68", "69"}, "hi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := execute(tt.stdin, tt.args...)
+			if err != nil || got != tt.want {
+				t.Errorf("Execute() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"bad scheme", []string{"rot13", "x"}, `unsupported scheme "rot13"`},
+		{"bad input", []string{"hex", "zz"}, "decode hex"},
+		{"missing file", []string{"hex", "-f", filepath.Join(t.TempDir(), "missing")}, "read input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := execute("", tt.args...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
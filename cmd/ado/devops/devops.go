@@ -0,0 +1,322 @@
+package devops
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	internaldevops "github.com/anowarislam/ado/internal/devops"
+	"github.com/anowarislam/ado/internal/httpclient"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/secrets"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// TokenEnvVar names the PAT for CI, as with the Azure CLI's devops
+// extension. It is used when the config file names no token.
+const TokenEnvVar = "AZURE_DEVOPS_EXT_PAT"
+
+// defaultTop is how many work items a query returns unless --top is set.
+const defaultTop = 50
+
+type connection struct {
+	url          string
+	organization string
+	project      string
+}
+
+// NewCommand returns the devops parent command with subcommands.
+func NewCommand() *cobra.Command {
+	var conn connection
+
+	cmd := &cobra.Command{
+		Use:   "devops",
+		Short: "Work with Azure DevOps pipelines and work items",
+		Long: `List and run Azure DevOps pipelines and query work items through the
+Azure DevOps REST API.
+
+The organization and project come from --organization and --project or
+the devops: section of the config file:
+
+  devops:
+    organization: contoso
+    project: web
+    token: secret://azure-devops-pat
+
+The personal access token (PAT) is read from devops.token, which is
+normally a secret:// reference, then $AZURE_DEVOPS_EXT_PAT, then the
+secret named azure-devops-pat. Store it once with:
+
+  ado secret set azure-devops-pat
+
+The PAT needs the Build (read and execute) scope for pipelines and Work
+Items (read) for queries. Azure DevOps Server users set devops.url or
+--url to their collection URL.`,
+	}
+
+	cmd.PersistentFlags().StringVar(&conn.organization, "organization", "", "Azure DevOps organization (default from devops.organization)")
+	cmd.PersistentFlags().StringVar(&conn.project, "project", "", "Project (default from devops.project)")
+	cmd.PersistentFlags().StringVar(&conn.url, "url", "", "Azure DevOps host or collection URL (default "+internaldevops.DefaultBaseURL+")")
+
+	cmd.AddCommand(
+		newPipelinesCommand(&conn),
+		newWorkItemsCommand(&conn),
+	)
+	return cmd
+}
+
+func newPipelinesCommand(conn *connection) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pipelines",
+		Short: "List and run pipelines",
+	}
+	cmd.AddCommand(newPipelinesListCommand(conn), newPipelinesRunCommand(conn))
+	return cmd
+}
+
+func newPipelinesListCommand(conn *connection) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the project's pipelines",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			client, err := newClient(cmd, conn)
+			if err != nil {
+				return err
+			}
+			pipelines, err := client.Pipelines(cmd.Context())
+			if err != nil {
+				return err
+			}
+			payload := map[string][]internaldevops.Pipeline{"pipelines": pipelines}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatPipelines(pipelines), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func newPipelinesRunCommand(conn *connection) *cobra.Command {
+	var (
+		output string
+		branch string
+		vars   []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run PIPELINE",
+		Short: "Queue a pipeline run",
+		Long: `Queue a run of a pipeline, given by name or ID, and print the run.
+
+--branch picks the branch of the pipeline's repository to build. --var
+sets a variable that the pipeline allows overriding at queue time.
+
+Examples:
+  # Run the release pipeline on its default branch
+  ado devops pipelines run release
+
+  # Run pipeline 7 on a feature branch with a variable
+  ado devops pipelines run 7 --branch feature/login --var env=staging`,
+		Args:        cobra.ExactArgs(1),
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			variables, err := parseVars(vars)
+			if err != nil {
+				return err
+			}
+			client, err := newClient(cmd, conn)
+			if err != nil {
+				return err
+			}
+			pipeline, err := client.FindPipeline(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				detail := "default branch"
+				if branch != "" {
+					detail = "branch " + branch
+				}
+				if len(vars) > 0 {
+					detail += ", variables " + strings.Join(vars, ", ")
+				}
+				plan := ui.NewPlan()
+				plan.Add("run", fmt.Sprintf("pipeline %s (%d) in %s/%s", pipeline.Name, pipeline.ID, client.Organization, client.Project), detail)
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+
+			run, err := client.RunPipeline(cmd.Context(), pipeline.ID, internaldevops.RunOptions{Branch: branch, Variables: variables})
+			if err != nil {
+				return err
+			}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, run, func() (string, error) {
+				text := fmt.Sprintf("Queued %s run %s (%d): %s", run.Pipeline, run.Name, run.ID, run.State)
+				if run.URL != "" {
+					text += "\n" + run.URL
+				}
+				return text, nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().StringVar(&branch, "branch", "", "Branch to run (default: the pipeline's default branch)")
+	cmd.Flags().StringArrayVar(&vars, "var", nil, "Pipeline variable as NAME=VALUE (repeatable)")
+	return cmd
+}
+
+func newWorkItemsCommand(conn *connection) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workitems",
+		Short: "Query work items",
+	}
+	cmd.AddCommand(newWorkItemsQueryCommand(conn))
+	return cmd
+}
+
+func newWorkItemsQueryCommand(conn *connection) *cobra.Command {
+	var (
+		output string
+		top    int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "query WIQL",
+		Short: "Run a WIQL query",
+		Long: `Run a Work Item Query Language (WIQL) query in the project and print the
+matching work items: ID, type, title, state, and assignee.
+
+Examples:
+  # Active bugs assigned to me
+  ado devops workitems query "SELECT [System.Id] FROM WorkItems WHERE [System.WorkItemType] = 'Bug' AND [System.State] = 'Active' AND [System.AssignedTo] = @Me"
+
+  # Newest 10 work items as JSON
+  ado devops workitems query "SELECT [System.Id] FROM WorkItems ORDER BY [System.CreatedDate] DESC" --top 10 -o json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			if top < 1 {
+				return fmt.Errorf("--top must be at least 1")
+			}
+			client, err := newClient(cmd, conn)
+			if err != nil {
+				return err
+			}
+			items, err := client.QueryWorkItems(cmd.Context(), args[0], top)
+			if err != nil {
+				return err
+			}
+			payload := map[string][]internaldevops.WorkItem{"work_items": items}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatWorkItems(items), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().IntVar(&top, "top", defaultTop, "Maximum number of work items to return")
+	return cmd
+}
+
+// newClient returns a client for the connection given by flags, falling
+// back to the devops: section of the config file.
+func newClient(cmd *cobra.Command, conn *connection) (*internaldevops.Client, error) {
+	configPath, err := cmd.Root().PersistentFlags().GetString("config")
+	if err != nil {
+		return nil, err
+	}
+	homeDir, _ := os.UserHomeDir()
+	cfg, _, err := internalconfig.PathResolverFor(cmd.Context(), configPath, homeDir).Load()
+	if err != nil {
+		return nil, err
+	}
+
+	client := &internaldevops.Client{
+		HTTP: &httpclient.Client{
+			Timeout:   httpclient.DefaultTimeout,
+			Retries:   2,
+			UserAgent: "ado/" + internalmeta.CurrentBuildInfo().Version,
+		},
+		BaseURL:      firstNonEmpty(conn.url, cfg.DevOps.URL),
+		Organization: firstNonEmpty(conn.organization, cfg.DevOps.Organization),
+		Project:      firstNonEmpty(conn.project, cfg.DevOps.Project),
+	}
+
+	ref := firstNonEmpty(cfg.DevOps.Token, os.Getenv(TokenEnvVar), internaldevops.DefaultTokenRef)
+	client.Token, err = secrets.Expand(secrets.Default(), ref)
+	if err != nil {
+		return nil, fmt.Errorf("read azure devops token: %w (store it with 'ado secret set azure-devops-pat' or set $%s)", err, TokenEnvVar)
+	}
+	return client, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func parseVars(pairs []string) (map[string]string, error) {
+	vars := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		name, value, ok := strings.Cut(pair, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid --var %q: expected NAME=VALUE", pair)
+		}
+		vars[name] = value
+	}
+	return vars, nil
+}
+
+func formatPipelines(pipelines []internaldevops.Pipeline) string {
+	if len(pipelines) == 0 {
+		return "No pipelines found."
+	}
+	var b strings.Builder
+	for _, p := range pipelines {
+		name := p.Name
+		if folder := strings.Trim(p.Folder, `\`); folder != "" {
+			name = folder + `\` + name
+		}
+		fmt.Fprintf(&b, "%6d  %s\n", p.ID, name)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func formatWorkItems(items []internaldevops.WorkItem) string {
+	if len(items) == 0 {
+		return "No work items match the query."
+	}
+	var b strings.Builder
+	for _, item := range items {
+		fmt.Fprintf(&b, "%6d  %-10s %-10s %s", item.ID, item.Type, item.State, item.Title)
+		if item.AssignedTo != "" {
+			fmt.Fprintf(&b, " (%s)", item.AssignedTo)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
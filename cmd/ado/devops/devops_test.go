@@ -0,0 +1,159 @@
+package devops
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// newTestRoot returns a root command with devops attached, its config
+// pointing at a fake Azure DevOps server, and a counter of pipeline runs
+// queued.
+func newTestRoot(t *testing.T) (*cobra.Command, *bytes.Buffer, *int) {
+	t.Helper()
+	runs := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/contoso/web/_apis/pipelines":
+			io.WriteString(w, `{"value":[{"id":1,"name":"ci","folder":"\\"},{"id":7,"name":"release","folder":"\\deploy"}]}`)
+		case "/contoso/web/_apis/pipelines/7/runs":
+			runs++
+			io.WriteString(w, `{"id":42,"name":"20261016.1","state":"inProgress","pipeline":{"id":7,"name":"release"},"_links":{"web":{"href":"https://example/run/42"}}}`)
+		case "/contoso/web/_apis/wit/wiql":
+			io.WriteString(w, `{"workItems":[{"id":12}]}`)
+		case "/contoso/_apis/wit/workitems":
+			io.WriteString(w, `{"value":[{"id":12,"fields":{"System.WorkItemType":"Bug","System.Title":"Crash on start","System.State":"Active","System.AssignedTo":{"displayName":"Sam Lee"}}}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			io.WriteString(w, `{"message":"not found"}`)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	config := "version: 1\ndevops:\n  url: " + srv.URL + "\n  organization: contoso\n  project: web\n  token: pat\n"
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().String("config", path, "")
+	root.PersistentFlags().Bool(ui.DryRunFlag, false, "")
+	root.AddCommand(NewCommand())
+
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	return root, &buf, &runs
+}
+
+func TestPipelinesList(t *testing.T) {
+	tests := []struct {
+		output string
+		want   []string
+	}{
+		{"text", []string{"     1  ci", `     7  deploy\release`}},
+		{"json", []string{`"pipelines"`, `"name": "release"`, `"id": 7`}},
+		{"yaml", []string{"pipelines:", "name: ci"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			root, buf, _ := newTestRoot(t)
+			root.SetArgs([]string{"devops", "pipelines", "list", "-o", tt.output})
+			if err := root.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestPipelinesRun(t *testing.T) {
+	root, buf, runs := newTestRoot(t)
+	root.SetArgs([]string{"devops", "pipelines", "run", "release", "--branch", "main", "--var", "env=staging", "-o", "json"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var run struct {
+		ID       int    `json:"id"`
+		Pipeline string `json:"pipeline"`
+		State    string `json:"state"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &run); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	if run.ID != 42 || run.Pipeline != "release" || run.State != "inProgress" || *runs != 1 {
+		t.Errorf("run = %+v, runs queued = %d", run, *runs)
+	}
+}
+
+func TestPipelinesRun_DryRun(t *testing.T) {
+	root, buf, runs := newTestRoot(t)
+	root.SetArgs([]string{"devops", "pipelines", "run", "7", "--var", "env=staging", "--dry-run"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if *runs != 0 {
+		t.Errorf("dry run queued %d runs", *runs)
+	}
+	if out := buf.String(); !strings.Contains(out, "pipeline release (7) in contoso/web") || !strings.Contains(out, "variables env=staging") {
+		t.Errorf("plan = %q", out)
+	}
+}
+
+func TestPipelinesRun_Errors(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"nightly"}, `no pipeline "nightly"`},
+		{[]string{"release", "--var", "env"}, `invalid --var "env"`},
+	}
+	for _, tt := range tests {
+		root, _, runs := newTestRoot(t)
+		root.SetArgs(append([]string{"devops", "pipelines", "run"}, tt.args...))
+		err := root.Execute()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: error = %v, want %q", tt.args, err, tt.want)
+		}
+		if *runs != 0 {
+			t.Errorf("%v: queued %d runs", tt.args, *runs)
+		}
+	}
+}
+
+func TestWorkItemsQuery(t *testing.T) {
+	root, buf, _ := newTestRoot(t)
+	root.SetArgs([]string{"devops", "workitems", "query", "SELECT [System.Id] FROM WorkItems"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	want := "    12  Bug        Active     Crash on start (Sam Lee)\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestNewClient_FlagsOverrideConfig(t *testing.T) {
+	root, _, _ := newTestRoot(t)
+	root.SetArgs([]string{"devops", "pipelines", "list", "--project", "other"})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("error = %v, want the server's 404 for project other", err)
+	}
+}
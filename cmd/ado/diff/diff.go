@@ -0,0 +1,87 @@
+package diff
+
+import (
+	"errors"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	internaldiff "github.com/anowarislam/ado/internal/diff"
+)
+
+// stdinName is the path that reads standard input.
+const stdinName = "-"
+
+// NewCommand returns the diff command.
+func NewCommand() *cobra.Command {
+	var (
+		output   string
+		exitCode bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "diff FILE1 FILE2",
+		Short: "Compare two JSON or YAML documents structurally",
+		Long: `Compare two JSON or YAML documents by key and type rather than by line.
+
+Key order, indentation, quoting, and JSON vs YAML syntax are ignored.
+Values of different types differ even when they look alike (80 vs "80").
+List elements are compared by position. Either file may be "-" for stdin.
+
+Output formats:
+  text     one line per change: "+ path: value", "- path: value",
+           "~ path: old -> new"
+  unified  unified line diff of both documents as canonical YAML
+  patch    JSON Patch (RFC 6902) that turns FILE1 into FILE2
+  json     {"equal": ..., "changes": [...]}
+  yaml     same as json
+
+Paths are JSON Pointers (RFC 6901), e.g. /server/ports/0.
+
+Examples:
+  # Compare two configs
+  ado diff old.yaml new.yaml
+
+  # Compare a JSON API response with a YAML fixture
+  ado http get :8080/config -b | ado diff fixture.yaml -
+
+  # Fail a CI step when generated output drifts
+  ado diff --exit-code expected.json actual.json`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := internaldiff.ValidateOutput(output); err != nil {
+				return err
+			}
+			if args[0] == stdinName && args[1] == stdinName {
+				return errors.New("only one document can be read from stdin")
+			}
+
+			docs := make([]any, 2)
+			for i, name := range args {
+				var err error
+				if name == stdinName {
+					docs[i], err = internaldiff.Decode(cmd.InOrStdin(), "stdin")
+				} else {
+					docs[i], err = internaldiff.Load(name)
+				}
+				if err != nil {
+					return err
+				}
+			}
+
+			differ, err := internaldiff.Print(cmd.OutOrStdout(), output, docs[0], docs[1], args[0], args[1])
+			if err != nil {
+				return err
+			}
+			if differ && exitCode {
+				return internaldiff.ErrDiffer
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", internaldiff.OutputHelp)
+	_ = cmd.RegisterFlagCompletionFunc("output", completion.Fixed(internaldiff.Outputs...))
+	cmd.Flags().BoolVar(&exitCode, "exit-code", false, "Exit with status 1 when the documents differ")
+	return cmd
+}
@@ -0,0 +1,138 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	internaldiff "github.com/anowarislam/ado/internal/diff"
+)
+
+func execute(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewCommand()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func writeFiles(t *testing.T) (string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.json")
+	b := filepath.Join(dir, "b.yaml")
+	if err := os.WriteFile(a, []byte(`{"name": "ado", "port": 80, "tags": ["x"]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(b, []byte("tags: [x, y]\nport: \"80\"\nname: ado\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return a, b
+}
+
+func TestDiff_Outputs(t *testing.T) {
+	a, b := writeFiles(t)
+
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"text", "~ /port: 80 -> \"80\" (number -> string)\n+ /tags/1: \"y\"\n"},
+		{"unified", "--- " + a + "\n+++ " + b + "\n@@ -1,4 +1,5 @@\n name: ado\n-port: 80\n+port: \"80\"\n tags:\n     - x\n+    - \"y\"\n"},
+		{"patch", `[
+  {
+    "op": "replace",
+    "path": "/port",
+    "value": "80"
+  },
+  {
+    "op": "add",
+    "path": "/tags/1",
+    "value": "y"
+  }
+]
+`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			out, err := execute(t, "", a, b, "-o", tt.output)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if out != tt.want {
+				t.Errorf("output =\n%s\nwant\n%s", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiff_JSON(t *testing.T) {
+	a, b := writeFiles(t)
+	out, err := execute(t, "", a, b, "-o", "json")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var result struct {
+		Equal   bool             `json:"equal"`
+		Changes []map[string]any `json:"changes"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Equal || len(result.Changes) != 2 || result.Changes[0]["from"] != float64(80) {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestDiff_Equal(t *testing.T) {
+	a, _ := writeFiles(t)
+	out, err := execute(t, "port: 80\nname: ado\ntags: [x]\n", a, "-", "--exit-code")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if out != "" {
+		t.Errorf("output = %q, want empty for equal documents", out)
+	}
+}
+
+func TestDiff_ExitCode(t *testing.T) {
+	a, b := writeFiles(t)
+	if _, err := execute(t, "", a, b); err != nil {
+		t.Errorf("without --exit-code: error = %v", err)
+	}
+	if _, err := execute(t, "", a, b, "--exit-code"); !errors.Is(err, internaldiff.ErrDiffer) {
+		t.Errorf("with --exit-code: error = %v, want ErrDiffer", err)
+	}
+}
+
+func TestDiff_Errors(t *testing.T) {
+	a, _ := writeFiles(t)
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"one arg", []string{a}, "accepts 2 arg(s)"},
+		{"both stdin", []string{"-", "-"}, "only one document"},
+		{"bad output", []string{a, a, "-o", "xml"}, "unsupported output format"},
+		{"missing file", []string{a, "/nonexistent.yaml"}, "no such file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := execute(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package docs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/cobra/doc"
+
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// DefaultManDir is where `ado docs man` writes pages by default.
+const DefaultManDir = "man"
+
+// NewCommand returns the docs parent command with subcommands.
+func NewCommand(buildInfo internalmeta.BuildInfo) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "docs",
+		Short: "Generate documentation from the command tree",
+		Long: `Generate reference documentation for every ado command from the same
+metadata that drives --help, so packaged docs never drift from the binary.`,
+	}
+
+	cmd.AddCommand(
+		newManCommand(buildInfo),
+		newMarkdownCommand(),
+	)
+
+	return cmd
+}
+
+func newManCommand(buildInfo internalmeta.BuildInfo) *cobra.Command {
+	var dir string
+
+	cmd := &cobra.Command{
+		Use:         "man",
+		Short:       "Generate roff man pages",
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		Long: `Write a section 1 man page for every command to --dir: ado.1,
+ado-meta.1, ado-meta-system.1, and so on. Hidden commands are skipped.
+
+Pages are dated from the build time (or $SOURCE_DATE_EPOCH for dev
+builds), so regenerating them for the same release is reproducible.
+
+Examples:
+  # Generate into ./man and preview
+  ado docs man
+  man ./man/ado-run.1
+
+  # Install system-wide
+  sudo ado docs man --dir /usr/local/share/man/man1`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				plan := ui.NewPlan()
+				for _, name := range manPages(root) {
+					plan.Add("write", filepath.Join(dir, name), "")
+				}
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+
+			if err := os.MkdirAll(dir, 0o755); err != nil {
+				return fmt.Errorf("create %s: %w", dir, err)
+			}
+			root.DisableAutoGenTag = true
+			header := &doc.GenManHeader{
+				Section: "1",
+				Source:  "ado " + buildInfo.Version,
+				Manual:  "ado Manual",
+				Date:    buildDate(buildInfo),
+			}
+			if err := doc.GenManTree(root, header, dir); err != nil {
+				return fmt.Errorf("generate man pages: %w", err)
+			}
+
+			_, err := fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d man pages to %s\n", len(manPages(root)), dir)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", DefaultManDir, "Directory to write the pages to")
+	_ = cmd.MarkFlagDirname("dir")
+	return cmd
+}
+
+// manPages returns the files cobra/doc writes a page to for cmd and its
+// visible descendants: "ado meta system" becomes ado-meta-system.1.
+func manPages(cmd *cobra.Command) []string {
+	var pages []string
+	for _, c := range documented(cmd) {
+		pages = append(pages, strings.ReplaceAll(c.CommandPath(), " ", "-")+".1")
+	}
+	return pages
+}
+
+// buildDate returns the release date recorded in the binary, or nil to let
+// cobra fall back to $SOURCE_DATE_EPOCH or the current time.
+func buildDate(info internalmeta.BuildInfo) *time.Time {
+	t, err := time.Parse(time.RFC3339, info.BuildTime)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,116 @@
+package docs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+)
+
+// newTestRoot returns a small tree standing in for the ado root command.
+func newTestRoot(buildInfo internalmeta.BuildInfo) *cobra.Command {
+	root := &cobra.Command{Use: "ado", Short: "test root"}
+	root.CompletionOptions.DisableDefaultCmd = true
+	group := &cobra.Command{Use: "meta", Short: "Meta commands"}
+	group.AddCommand(&cobra.Command{Use: "info", Short: "Show info", Run: func(*cobra.Command, []string) {}})
+	root.AddCommand(group)
+	root.AddCommand(&cobra.Command{Use: "secret-stuff", Hidden: true, Run: func(*cobra.Command, []string) {}})
+	root.AddCommand(NewCommand(buildInfo))
+	return root
+}
+
+func TestManCommand(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "man")
+	root := newTestRoot(internalmeta.BuildInfo{Version: "1.2.3", BuildTime: "2025-03-04T05:06:07Z"})
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs([]string{"docs", "man", "--dir", dir})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := []string{"ado-docs-man.1", "ado-docs-markdown.1", "ado-docs.1", "ado-meta-info.1", "ado-meta.1", "ado.1"}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+	if !strings.Contains(out.String(), "Wrote 6 man pages to "+dir) {
+		t.Errorf("output = %q", out.String())
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "ado-meta-info.1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := `.TH "ADO-META-INFO" "1" "Mar 2025" "ado 1.2.3" "ado Manual"`
+	if !strings.Contains(string(page), header) {
+		t.Errorf("page header missing %q:\n%s", header, page)
+	}
+	if strings.Contains(string(page), "Auto generated") {
+		t.Error("page contains the auto-generated tag")
+	}
+}
+
+func TestManCommand_BadDir(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	root := newTestRoot(internalmeta.BuildInfo{})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"docs", "man", "--dir", filepath.Join(file, "man")})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "create") {
+		t.Errorf("Execute() error = %v, want create error", err)
+	}
+}
+
+func TestDocsCommands_DryRun(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "out")
+	for _, tt := range []struct {
+		command string
+		first   string
+		pages   int
+	}{
+		{"man", "ado.1", 6},
+		{"markdown", "ado.md", 7},
+	} {
+		root := newTestRoot(internalmeta.BuildInfo{})
+		root.PersistentFlags().Bool("dry-run", false, "")
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs([]string{"docs", tt.command, "--dir", dir, "--dry-run"})
+		if err := root.Execute(); err != nil {
+			t.Fatalf("%s --dry-run error = %v", tt.command, err)
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if len(lines) != tt.pages+1 || lines[0] != "Would write "+filepath.Join(dir, tt.first) {
+			t.Errorf("%s --dry-run output =\n%s", tt.command, out.String())
+		}
+		if _, err := os.Stat(dir); !os.IsNotExist(err) {
+			t.Errorf("%s --dry-run created %s: %v", tt.command, dir, err)
+		}
+	}
+}
+
+func TestBuildDate(t *testing.T) {
+	if got := buildDate(internalmeta.BuildInfo{BuildTime: "unknown"}); got != nil {
+		t.Errorf("buildDate(unknown) = %v, want nil", got)
+	}
+	got := buildDate(internalmeta.BuildInfo{BuildTime: "2025-03-04T05:06:07Z"})
+	if got == nil || got.Year() != 2025 || got.Month() != 3 {
+		t.Errorf("buildDate() = %v", got)
+	}
+}
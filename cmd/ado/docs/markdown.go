@@ -0,0 +1,278 @@
+package docs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// DefaultMarkdownDir is where `ado docs markdown` writes pages by default.
+const DefaultMarkdownDir = "docs/reference"
+
+func newMarkdownCommand() *cobra.Command {
+	var dir string
+
+	cmd := &cobra.Command{
+		Use:         "markdown",
+		Short:       "Generate a markdown CLI reference",
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		Long: `Write a markdown reference to --dir: index.md listing every command, and
+one page per command (ado.md, ado_meta.md, ado_meta_system.md, ...) with
+its description, usage, examples, flag tables, and links to its parent and
+subcommands. Hidden commands are skipped.
+
+The output only depends on the command tree. The docs site build runs this
+before mkdocs, so the published reference always matches the binary.
+
+Examples:
+  # Generate the reference for the docs site
+  ado docs markdown
+
+  # Write it somewhere else
+  ado docs markdown --dir /tmp/ado-reference`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				plan := ui.NewPlan()
+				for _, c := range documented(root) {
+					plan.Add("write", filepath.Join(dir, pageName(c)), "")
+				}
+				plan.Add("write", filepath.Join(dir, "index.md"), "")
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+			n, err := writeMarkdownTree(root, dir)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Wrote %d pages to %s\n", n+1, dir)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVar(&dir, "dir", DefaultMarkdownDir, "Directory to write the pages to")
+	_ = cmd.MarkFlagDirname("dir")
+	return cmd
+}
+
+// writeMarkdownTree writes index.md and a page per command under root. It
+// returns the number of command pages.
+func writeMarkdownTree(root *cobra.Command, dir string) (int, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return 0, fmt.Errorf("create %s: %w", dir, err)
+	}
+
+	commands := documented(root)
+	for _, c := range commands {
+		path := filepath.Join(dir, pageName(c))
+		if err := os.WriteFile(path, []byte(markdownPage(c)), 0o644); err != nil {
+			return 0, fmt.Errorf("write %s: %w", path, err)
+		}
+	}
+
+	path := filepath.Join(dir, "index.md")
+	if err := os.WriteFile(path, []byte(markdownIndex(root, commands)), 0o644); err != nil {
+		return 0, fmt.Errorf("write %s: %w", path, err)
+	}
+	return len(commands), nil
+}
+
+// documented returns cmd and its visible descendants, depth first, in the
+// order cobra lists them in help.
+func documented(cmd *cobra.Command) []*cobra.Command {
+	list := []*cobra.Command{cmd}
+	for _, sub := range cmd.Commands() {
+		if !sub.IsAvailableCommand() || sub.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		list = append(list, documented(sub)...)
+	}
+	return list
+}
+
+// pageName is the file for cmd: "ado meta system" becomes ado_meta_system.md.
+func pageName(cmd *cobra.Command) string {
+	return strings.ReplaceAll(cmd.CommandPath(), " ", "_") + ".md"
+}
+
+func markdownIndex(root *cobra.Command, commands []*cobra.Command) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s CLI Reference\n\n", root.Name())
+	b.WriteString("<!-- Generated by `ado docs markdown`; do not edit. -->\n\n")
+	b.WriteString("| Command | Description |\n|---------|-------------|\n")
+	for _, c := range commands {
+		fmt.Fprintf(&b, "| [`%s`](%s) | %s |\n", c.CommandPath(), pageName(c), cell(c.Short))
+	}
+	return b.String()
+}
+
+func markdownPage(cmd *cobra.Command) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", cmd.CommandPath())
+	b.WriteString("<!-- Generated by `ado docs markdown`; do not edit. -->\n\n")
+	if cmd.Short != "" {
+		b.WriteString(cmd.Short + "\n\n")
+	}
+
+	description, examples := splitExamples(cmd.Long)
+	if description != "" {
+		b.WriteString("## Description\n\n")
+		b.WriteString(renderText(description))
+	}
+
+	if cmd.Runnable() {
+		fmt.Fprintf(&b, "## Usage\n\n```\n%s\n```\n\n", cmd.UseLine())
+	}
+	if len(cmd.Aliases) > 0 {
+		fmt.Fprintf(&b, "Aliases: `%s`\n\n", strings.Join(cmd.Aliases, "`, `"))
+	}
+
+	if cmd.Example != "" {
+		examples = strings.TrimRight(cmd.Example+"\n"+examples, "\n")
+	}
+	if examples != "" {
+		fmt.Fprintf(&b, "## Examples\n\n```bash\n%s\n```\n\n", dedent(examples))
+	}
+
+	if flags := cmd.NonInheritedFlags(); flags.HasAvailableFlags() {
+		b.WriteString("## Flags\n\n")
+		b.WriteString(flagTable(flags))
+	}
+	if flags := cmd.InheritedFlags(); flags.HasAvailableFlags() {
+		b.WriteString("## Inherited Flags\n\n")
+		b.WriteString(flagTable(flags))
+	}
+
+	var subs []*cobra.Command
+	for _, sub := range cmd.Commands() {
+		if sub.IsAvailableCommand() && !sub.IsAdditionalHelpTopicCommand() {
+			subs = append(subs, sub)
+		}
+	}
+	if len(subs) > 0 {
+		b.WriteString("## Subcommands\n\n")
+		for _, sub := range subs {
+			fmt.Fprintf(&b, "- [`%s`](%s) - %s\n", sub.CommandPath(), pageName(sub), sub.Short)
+		}
+		b.WriteString("\n")
+	}
+
+	if cmd.HasParent() {
+		parent := cmd.Parent()
+		fmt.Fprintf(&b, "## See Also\n\n- [`%s`](%s) - %s\n", parent.CommandPath(), pageName(parent), parent.Short)
+	}
+	return strings.TrimRight(b.String(), "\n") + "\n"
+}
+
+func flagTable(flags *pflag.FlagSet) string {
+	var b strings.Builder
+	b.WriteString("| Flag | Type | Default | Description |\n|------|------|---------|-------------|\n")
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		name := "`--" + f.Name + "`"
+		if f.Shorthand != "" {
+			name = "`-" + f.Shorthand + "`, " + name
+		}
+		typ, usage := pflag.UnquoteUsage(f)
+		if typ == "" {
+			typ = "bool"
+		}
+		def := ""
+		if f.DefValue != "" && f.DefValue != "[]" && f.DefValue != "false" {
+			def = "`" + f.DefValue + "`"
+		}
+		fmt.Fprintf(&b, "| %s | %s | %s | %s |\n", name, typ, def, cell(usage))
+	})
+	b.WriteString("\n")
+	return b.String()
+}
+
+// cell escapes s for a table cell.
+func cell(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "|", `\|`), "\n", " ")
+}
+
+// splitExamples separates the "Examples:" section that ado commands end
+// their long help with.
+func splitExamples(long string) (description, examples string) {
+	long = strings.TrimSpace(long)
+	if long == "Examples:" || strings.HasPrefix(long, "Examples:\n") {
+		return "", strings.TrimPrefix(long, "Examples:")
+	}
+	if before, after, ok := strings.Cut(long, "\n\nExamples:\n"); ok {
+		return before, after
+	}
+	return long, ""
+}
+
+// renderText turns help text into markdown: paragraphs stay prose, and
+// indented blocks (config snippets, endpoint lists) become code blocks.
+func renderText(text string) string {
+	var b strings.Builder
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	for i := 0; i < len(lines); {
+		if !isIndented(lines[i]) {
+			start := i
+			for i < len(lines) && !isIndented(lines[i]) && strings.TrimSpace(lines[i]) != "" {
+				i++
+			}
+			if i > start {
+				b.WriteString(strings.Join(lines[start:i], "\n") + "\n\n")
+			}
+			for i < len(lines) && strings.TrimSpace(lines[i]) == "" {
+				i++
+			}
+			continue
+		}
+
+		// An indented block runs until a non-blank, unindented line.
+		start := i
+		end := i
+		for i < len(lines) && (isIndented(lines[i]) || strings.TrimSpace(lines[i]) == "") {
+			if strings.TrimSpace(lines[i]) != "" {
+				end = i + 1
+			}
+			i++
+		}
+		fmt.Fprintf(&b, "```\n%s\n```\n\n", dedent(strings.Join(lines[start:end], "\n")))
+	}
+	return b.String()
+}
+
+func isIndented(line string) bool {
+	return strings.HasPrefix(line, "  ") || strings.HasPrefix(line, "\t")
+}
+
+// dedent removes the indentation common to all non-blank lines.
+func dedent(s string) string {
+	lines := strings.Split(strings.Trim(s, "\n"), "\n")
+	common := -1
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		if common < 0 || indent < common {
+			common = indent
+		}
+	}
+	if common <= 0 {
+		return strings.Join(lines, "\n")
+	}
+	for i, line := range lines {
+		if len(line) >= common {
+			lines[i] = line[common:]
+		} else {
+			lines[i] = ""
+		}
+	}
+	return strings.Join(lines, "\n")
+}
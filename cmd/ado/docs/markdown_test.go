@@ -0,0 +1,159 @@
+package docs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+)
+
+func TestMarkdownCommand(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "reference")
+	root := newTestRoot(internalmeta.BuildInfo{})
+	root.PersistentFlags().String("log-level", "info", "Log level")
+	info, _, err := root.Find([]string{"meta", "info"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	info.Long = "Show info about the host.\n\nSections:\n  cpu    processors\n  disk   mounts\n\nExamples:\n  # Everything\n  ado meta info"
+	info.Aliases = []string{"i"}
+	info.Flags().StringP("output", "o", "text", "Output format (text|json)")
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetArgs([]string{"docs", "markdown", "--dir", dir})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	want := []string{"ado.md", "ado_docs.md", "ado_docs_man.md", "ado_docs_markdown.md", "ado_meta.md", "ado_meta_info.md", "index.md"}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, e := range entries {
+		got = append(got, e.Name())
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("pages = %v, want %v", got, want)
+	}
+	if !strings.Contains(out.String(), "Wrote 7 pages to "+dir) {
+		t.Errorf("output = %q", out.String())
+	}
+
+	page := readFile(t, filepath.Join(dir, "ado_meta_info.md"))
+	for _, s := range []string{
+		"# ado meta info\n",
+		"Show info\n",
+		"## Description\n\nShow info about the host.\n\nSections:\n\n```\ncpu    processors\ndisk   mounts\n```\n",
+		"## Usage\n\n```\nado meta info [flags]\n```\n",
+		"Aliases: `i`\n",
+		"## Examples\n\n```bash\n# Everything\nado meta info\n```\n",
+		"| `-o`, `--output` | string | `text` | Output format (text\\|json) |\n",
+		"## Inherited Flags\n\n| Flag | Type | Default | Description |\n|------|------|---------|-------------|\n| `--log-level` | string | `info` | Log level |\n",
+		"## See Also\n\n- [`ado meta`](ado_meta.md) - Meta commands\n",
+	} {
+		if !strings.Contains(page, s) {
+			t.Errorf("ado_meta_info.md missing %q:\n%s", s, page)
+		}
+	}
+
+	group := readFile(t, filepath.Join(dir, "ado_meta.md"))
+	if strings.Contains(group, "## Usage") {
+		t.Error("non-runnable group has a Usage section")
+	}
+	if !strings.Contains(group, "- [`ado meta info`](ado_meta_info.md) - Show info\n") {
+		t.Errorf("ado_meta.md missing subcommand link:\n%s", group)
+	}
+
+	index := readFile(t, filepath.Join(dir, "index.md"))
+	if !strings.Contains(index, "| [`ado meta info`](ado_meta_info.md) | Show info |\n") {
+		t.Errorf("index.md missing entry:\n%s", index)
+	}
+	if strings.Contains(index, "secret-stuff") {
+		t.Error("index.md lists a hidden command")
+	}
+}
+
+func TestMarkdownCommand_BadDir(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	root := newTestRoot(internalmeta.BuildInfo{})
+	root.SetOut(&bytes.Buffer{})
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs([]string{"docs", "markdown", "--dir", filepath.Join(file, "reference")})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "create") {
+		t.Errorf("Execute() error = %v, want create error", err)
+	}
+}
+
+func TestSplitExamples(t *testing.T) {
+	tests := []struct {
+		name         string
+		long         string
+		wantDesc     string
+		wantExamples string
+	}{
+		{"none", "Just text.", "Just text.", ""},
+		{"both", "Text.\n\nExamples:\n  ado x", "Text.", "  ado x"},
+		{"only examples", "Examples:\n  ado x", "", "\n  ado x"},
+		{"empty", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			desc, examples := splitExamples(tt.long)
+			if desc != tt.wantDesc || examples != tt.wantExamples {
+				t.Errorf("splitExamples() = %q, %q, want %q, %q", desc, examples, tt.wantDesc, tt.wantExamples)
+			}
+		})
+	}
+}
+
+func TestDedent(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want string
+	}{
+		{"common indent", "  a\n    b\n  c", "a\n  b\nc"},
+		{"blank lines", "\n  a\n\n  b\n", "a\n\nb"},
+		{"no indent", "a\n  b", "a\n  b"},
+		{"tabs", "\ta\n\tb", "a\nb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dedent(tt.in); got != tt.want {
+				t.Errorf("dedent(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	in := "First line\nsecond line.\n\nConfig:\n  tasks:\n    test: go test\n\n  more: x\nAfter."
+	want := "First line\nsecond line.\n\nConfig:\n\n```\ntasks:\n  test: go test\n\nmore: x\n```\n\nAfter.\n\n"
+	if got := renderText(in); got != want {
+		t.Errorf("renderText() = %q, want %q", got, want)
+	}
+}
+
+func TestCell(t *testing.T) {
+	if got := cell("a|b\nc"); got != `a\|b c` {
+		t.Errorf("cell() = %q", got)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
@@ -3,25 +3,94 @@ package echo
 import (
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"slices"
 	"strings"
+	"text/template"
 
 	"github.com/spf13/cobra"
 
+	"github.com/anowarislam/ado/internal/completion"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
 	"github.com/anowarislam/ado/internal/ui"
 )
 
-func NewCommand() *cobra.Command {
+// templateData is what --template messages are rendered against.
+type templateData struct {
+	Env    map[string]string
+	Build  internalmeta.BuildInfo
+	Values map[string]string
+}
+
+func NewCommand(buildInfo internalmeta.BuildInfo) *cobra.Command {
 	var (
-		upper  bool
-		lower  bool
-		repeat int
-		output string
+		upper     bool
+		lower     bool
+		repeat    int
+		output    string
+		tmpl      bool
+		setPairs  []string
+		color     string
+		bold      bool
+		styleArg  string
+		separator string
+		noNewline bool
+		null      bool
+		expandEnv bool
+		strict    bool
 	)
 
 	cmd := &cobra.Command{
 		Use:   "echo [message...]",
 		Short: "Echo input text with optional formatting",
-		Args:  cobra.MinimumNArgs(1),
+		Long: `Print the message formed by joining the arguments with spaces (or
+--separator). Without arguments, the message is read from stdin (minus one
+trailing newline), so echo can act as a formatting filter in pipelines.
+
+--expand-env replaces ${VAR} with the environment variable VAR (after
+--template, if both are given). ${VAR:-default} uses default when VAR is
+unset or empty, and \${ is a literal ${. Undefined variables become empty,
+or an error with --strict. Only the braced form is expanded, so $5 and
+$HOME stay as written.
+
+Each message ends with a newline; -n leaves the last one off, and --null
+ends each message with a NUL byte instead, for xargs -0.
+
+With --template, the message is a Go template rendered before any other
+formatting. It can use .Env.NAME (environment variables), .Build.Version
+and the other fields of 'ado meta info', and .Values.KEY (from --set
+KEY=VALUE). Referencing a missing key is an error; use env "NAME" for
+variables that may be unset. The functions default, upper, lower, and
+trim are also available.
+
+--color, --bold, and --style highlight text output. --style takes a
+comma-separated list of bold, dim, italic, underline, a color, or one of
+the presets success, warning, error, and info. Styling is applied only
+when stdout is a terminal and NO_COLOR is not set; set FORCE_COLOR=1 to
+keep it when piping, e.g. into CI logs.
+
+Examples:
+  ado echo hello world
+  ado echo --upper < notes.txt
+  git branch --show-current | ado echo --upper
+
+  # Interpolate environment, build info, and values
+  ado echo --template 'deploying {{.Values.app}} as {{.Env.USER}} with ado {{.Build.Version}}' --set app=api
+  ado echo --template '{{env "REGION" | default "us-east-1"}}'
+
+  # Status lines
+  ado echo --style success "✔ tests passed"
+  ado echo --color yellow --bold "deploy paused"
+
+  # Config snippets from the environment
+  ado echo --expand-env --strict 'url: https://${HOST}:${PORT:-443}'
+
+  # Join and terminate output for other tools
+  ado echo --separator , a b c
+  ado echo -n "no newline"
+  ado echo --null --repeat 3 "file name" | xargs -0 touch`,
+		Args: cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if upper && lower {
 				return errors.New("cannot use --upper and --lower together")
@@ -29,13 +98,57 @@ func NewCommand() *cobra.Command {
 			if repeat < 1 {
 				return fmt.Errorf("--repeat must be >= 1 (got %d)", repeat)
 			}
+			if len(setPairs) > 0 && !tmpl {
+				return errors.New("--set requires --template")
+			}
+			if strict && !expandEnv {
+				return errors.New("--strict requires --expand-env")
+			}
+			if null && noNewline {
+				return errors.New("cannot use --null and --no-newline together")
+			}
 
 			format, err := ui.ParseOutputFormat(output)
 			if err != nil {
 				return err
 			}
+			if (null || noNewline) && format != ui.OutputText {
+				return errors.New("--null and --no-newline only apply to text output")
+			}
+			style, err := ui.ParseStyle(styleArg)
+			if err != nil {
+				return err
+			}
+			if color != "" {
+				if err := ui.ValidateColor(color); err != nil {
+					return err
+				}
+				style.Color = color
+			}
+			style.Bold = style.Bold || bold
+			if !ui.ColorEnabled(cmd.OutOrStdout()) {
+				style = ui.Style{}
+			}
 
-			message := strings.Join(args, " ")
+			message, err := readMessage(cmd.InOrStdin(), args, separator)
+			if err != nil {
+				return err
+			}
+			if tmpl {
+				values, err := parseValues(setPairs)
+				if err != nil {
+					return err
+				}
+				data := templateData{Env: environ(), Build: buildInfo, Values: values}
+				if message, err = renderTemplate(message, data); err != nil {
+					return err
+				}
+			}
+			if expandEnv {
+				if message, err = expandVars(message, os.LookupEnv, strict); err != nil {
+					return err
+				}
+			}
 			if upper {
 				message = strings.ToUpper(message)
 			}
@@ -48,8 +161,25 @@ func NewCommand() *cobra.Command {
 				values[i] = message
 			}
 
+			if null || noNewline {
+				terminator := "\n"
+				if null {
+					terminator = "\x00"
+				}
+				styled := make([]string, len(values))
+				for i, v := range values {
+					styled[i] = style.Render(v)
+				}
+				text := strings.Join(styled, terminator)
+				if null {
+					text += terminator
+				}
+				_, err := io.WriteString(cmd.OutOrStdout(), text)
+				return err
+			}
+
 			return ui.PrintOutput(cmd.OutOrStdout(), format, values, func() (string, error) {
-				return strings.Join(values, "\n"), nil
+				return style.Render(strings.Join(values, "\n")), nil
 			})
 		},
 	}
@@ -58,6 +188,128 @@ func NewCommand() *cobra.Command {
 	cmd.Flags().BoolVar(&lower, "lower", false, "Convert message to lowercase")
 	cmd.Flags().IntVar(&repeat, "repeat", 1, "Number of times to repeat the message")
 	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().BoolVarP(&tmpl, "template", "t", false, "Render the message as a Go template")
+	cmd.Flags().StringArrayVar(&setPairs, "set", nil, "Template value as KEY=VALUE, available as .Values.KEY (repeatable)")
+	cmd.Flags().StringVar(&color, "color", "", "Text color: "+strings.Join(ui.ColorNames, ", "))
+	cmd.Flags().BoolVar(&bold, "bold", false, "Bold text")
+	cmd.Flags().StringVar(&styleArg, "style", "", "Comma-separated styles: bold, dim, italic, underline, a color, or success, warning, error, info")
+	cmd.Flags().StringVar(&separator, "separator", " ", "String placed between arguments")
+	cmd.Flags().BoolVarP(&noNewline, "no-newline", "n", false, "Do not print the trailing newline")
+	cmd.Flags().BoolVar(&null, "null", false, "End each message with a NUL byte instead of a newline (for xargs -0)")
+	cmd.Flags().BoolVarP(&expandEnv, "expand-env", "e", false, "Replace ${VAR} references with environment variables")
+	cmd.Flags().BoolVar(&strict, "strict", false, "With --expand-env, fail on undefined variables")
+	_ = cmd.RegisterFlagCompletionFunc("color", completion.Fixed(ui.ColorNames...))
+	_ = cmd.RegisterFlagCompletionFunc("style", completion.Fixed(slices.Concat(ui.StyleNames, ui.ColorNames)...))
 
 	return cmd
 }
+
+// readMessage joins args with separator, or reads stdin when there are none. A terminal on
+// stdin is rejected rather than waited on.
+func readMessage(in io.Reader, args []string, separator string) (string, error) {
+	if len(args) > 0 {
+		return strings.Join(args, separator), nil
+	}
+	if ui.IsTerminal(in) {
+		return "", errors.New("no message: pass words as arguments or pipe text on stdin")
+	}
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", fmt.Errorf("read stdin: %w", err)
+	}
+	message := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(message, "\r"), nil
+}
+
+// parseValues turns KEY=VALUE pairs into a map; later pairs win.
+func parseValues(pairs []string) (map[string]string, error) {
+	values := make(map[string]string, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --set %q: expected KEY=VALUE", pair)
+		}
+		values[key] = value
+	}
+	return values, nil
+}
+
+func environ() map[string]string {
+	env := map[string]string{}
+	for _, kv := range os.Environ() {
+		if key, value, ok := strings.Cut(kv, "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+var templateFuncs = template.FuncMap{
+	"env": os.Getenv,
+	"default": func(fallback, value string) string {
+		if value == "" {
+			return fallback
+		}
+		return value
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"trim":  strings.TrimSpace,
+}
+
+func renderTemplate(src string, data templateData) (string, error) {
+	t, err := template.New("message").Funcs(templateFuncs).Option("missingkey=error").Parse(src)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, data); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return b.String(), nil
+}
+
+// expandVars replaces ${NAME} and ${NAME:-default} in s using lookup. \${
+// is a literal ${. With strict, undefined variables without a default are
+// an error naming all of them.
+func expandVars(s string, lookup func(string) (string, bool), strict bool) (string, error) {
+	var (
+		b         strings.Builder
+		undefined []string
+	)
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			break
+		}
+		if i > 0 && s[i-1] == '\\' {
+			b.WriteString(s[:i-1])
+			b.WriteString("${")
+			s = s[i+2:]
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", fmt.Errorf("unterminated ${ in %q", s[i:])
+		}
+		b.WriteString(s[:i])
+		name, fallback, hasDefault := strings.Cut(s[i+2:i+end], ":-")
+		if name == "" {
+			return "", fmt.Errorf("empty variable name in %q", s[i:i+end+1])
+		}
+		value, ok := lookup(name)
+		switch {
+		case hasDefault && value == "":
+			value = fallback
+		case !ok && !slices.Contains(undefined, name):
+			undefined = append(undefined, name)
+		}
+		b.WriteString(value)
+		s = s[i+end+1:]
+	}
+	if strict && len(undefined) > 0 {
+		return "", fmt.Errorf("undefined environment variables: %s", strings.Join(undefined, ", "))
+	}
+	return b.String(), nil
+}
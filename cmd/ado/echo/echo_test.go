@@ -4,12 +4,17 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	internalmeta "github.com/anowarislam/ado/internal/meta"
 )
 
+var testBuildInfo = internalmeta.BuildInfo{Name: "ado", Version: "1.2.3"}
+
 func TestEchoCommand(t *testing.T) {
 	tests := []struct {
 		name    string
 		args    []string
+		stdin   string
 		want    string
 		wantErr bool
 	}{
@@ -39,18 +44,32 @@ func TestEchoCommand(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:    "no args error",
-			args:    []string{},
-			wantErr: true,
+			name:  "stdin when no args",
+			args:  []string{"--upper"},
+			stdin: "line one\nline two\n",
+			want:  "LINE ONE\nLINE TWO\n",
+		},
+		{
+			name:  "args take precedence over stdin",
+			args:  []string{"hi"},
+			stdin: "ignored\n",
+			want:  "hi\n",
+		},
+		{
+			name:  "empty stdin",
+			args:  []string{},
+			stdin: "",
+			want:  "",
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd := NewCommand()
+			cmd := NewCommand(testBuildInfo)
 			var buf bytes.Buffer
 			cmd.SetOut(&buf)
 			cmd.SetErr(&buf)
+			cmd.SetIn(strings.NewReader(tt.stdin))
 			cmd.SetArgs(tt.args)
 
 			err := cmd.Execute()
@@ -68,7 +87,7 @@ func TestEchoCommand(t *testing.T) {
 }
 
 func TestEchoCommand_JSONOutput(t *testing.T) {
-	cmd := NewCommand()
+	cmd := NewCommand(testBuildInfo)
 	var buf bytes.Buffer
 	cmd.SetOut(&buf)
 	cmd.SetArgs([]string{"--output", "json", "hello"})
@@ -84,7 +103,7 @@ func TestEchoCommand_JSONOutput(t *testing.T) {
 }
 
 func TestEchoCommand_YAMLOutput(t *testing.T) {
-	cmd := NewCommand()
+	cmd := NewCommand(testBuildInfo)
 	var buf bytes.Buffer
 	cmd.SetOut(&buf)
 	cmd.SetArgs([]string{"--output", "yaml", "hello"})
@@ -98,3 +117,291 @@ func TestEchoCommand_YAMLOutput(t *testing.T) {
 		t.Errorf("Execute() output = %q, want YAML containing 'hello'", got)
 	}
 }
+
+func TestEchoCommand_Template(t *testing.T) {
+	t.Setenv("ECHO_TEST_USER", "dana")
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "env build and values",
+			args: []string{"-t", "{{.Values.app}} by {{.Env.ECHO_TEST_USER}} on {{.Build.Version}}", "--set", "app=api"},
+			want: "api by dana on 1.2.3\n",
+		},
+		{
+			name: "functions",
+			args: []string{"--template", `{{env "ECHO_TEST_UNSET" | default "none"}} {{upper .Values.x}}`, "--set", "x=a=b"},
+			want: "none A=B\n",
+		},
+		{
+			name: "later set wins and upper applies after rendering",
+			args: []string{"--template", "--upper", "{{.Values.k}}", "--set", "k=1", "--set", "k=two"},
+			want: "TWO\n",
+		},
+		{
+			name: "without template braces are literal",
+			args: []string{"{{.Env.HOME}}"},
+			want: "{{.Env.HOME}}\n",
+		},
+		{
+			name:    "missing value",
+			args:    []string{"-t", "{{.Values.nope}}"},
+			wantErr: `map has no entry for key "nope"`,
+		},
+		{
+			name:    "parse error",
+			args:    []string{"-t", "{{.Values.x"},
+			wantErr: "parse template",
+		},
+		{
+			name:    "set without template",
+			args:    []string{"--set", "a=b", "hi"},
+			wantErr: "--set requires --template",
+		},
+		{
+			name:    "invalid set",
+			args:    []string{"-t", "--set", "novalue", "hi"},
+			wantErr: `invalid --set "novalue"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCommand(testBuildInfo)
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Execute() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEchoCommand_Style(t *testing.T) {
+	tests := []struct {
+		name    string
+		force   string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name:  "color and bold",
+			force: "1",
+			args:  []string{"--color", "yellow", "--bold", "paused"},
+			want:  "\x1b[1;33mpaused\x1b[0m\n",
+		},
+		{
+			name:  "preset",
+			force: "1",
+			args:  []string{"--style", "error", "failed"},
+			want:  "\x1b[1;31mfailed\x1b[0m\n",
+		},
+		{
+			name:  "color overrides style color",
+			force: "1",
+			args:  []string{"--style", "underline,success", "--color", "blue", "ok"},
+			want:  "\x1b[4;34mok\x1b[0m\n",
+		},
+		{
+			name:  "each repeated line is styled",
+			force: "1",
+			args:  []string{"--bold", "--repeat", "2", "hi"},
+			want:  "\x1b[1mhi\x1b[0m\n\x1b[1mhi\x1b[0m\n",
+		},
+		{
+			name: "not a terminal",
+			args: []string{"--color", "red", "plain"},
+			want: "plain\n",
+		},
+		{
+			name:  "structured output is never styled",
+			force: "1",
+			args:  []string{"--bold", "-o", "json", "x"},
+			want:  "[\n  \"x\"\n]\n",
+		},
+		{
+			name:    "unknown color",
+			args:    []string{"--color", "purple", "x"},
+			wantErr: `unknown color "purple"`,
+		},
+		{
+			name:    "unknown style",
+			args:    []string{"--style", "blink", "x"},
+			wantErr: `unknown style "blink"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("NO_COLOR", "")
+			t.Setenv("FORCE_COLOR", tt.force)
+			cmd := NewCommand(testBuildInfo)
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Execute() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEchoCommand_NoColor(t *testing.T) {
+	t.Setenv("FORCE_COLOR", "1")
+	t.Setenv("NO_COLOR", "1")
+	cmd := NewCommand(testBuildInfo)
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"--style", "bold,red", "plain"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); got != "plain\n" {
+		t.Errorf("output = %q, want no escape codes", got)
+	}
+}
+
+func TestEchoCommand_Separators(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{name: "separator", args: []string{"--separator", ", ", "a", "b", "c"}, want: "a, b, c\n"},
+		{name: "empty separator", args: []string{"--separator", "", "a", "b"}, want: "ab\n"},
+		{name: "no newline", args: []string{"-n", "hi"}, want: "hi"},
+		{name: "no newline keeps inner newlines", args: []string{"--no-newline", "--repeat", "2", "hi"}, want: "hi\nhi"},
+		{name: "null", args: []string{"--null", "--repeat", "2", "a b"}, want: "a b\x00a b\x00"},
+		{name: "null with separator", args: []string{"--null", "--separator", "/", "x", "y"}, want: "x/y\x00"},
+		{name: "null and no newline", args: []string{"--null", "-n", "x"}, wantErr: "cannot use --null and --no-newline together"},
+		{name: "null with json", args: []string{"--null", "-o", "json", "x"}, wantErr: "only apply to text output"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCommand(testBuildInfo)
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Execute() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandVars(t *testing.T) {
+	env := map[string]string{"HOST": "example.com", "EMPTY": ""}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	tests := []struct {
+		in, want string
+	}{
+		{"plain $HOST $5", "plain $HOST $5"},
+		{"https://${HOST}/", "https://example.com/"},
+		{"${HOST}${HOST}", "example.comexample.com"},
+		{"[${MISSING}]", "[]"},
+		{"${PORT:-443} ${EMPTY:-x} ${HOST:-x}", "443 x example.com"},
+		{`\${HOST} costs $${HOST}`, "${HOST} costs $example.com"},
+	}
+	for _, tt := range tests {
+		got, err := expandVars(tt.in, lookup, false)
+		if err != nil || got != tt.want {
+			t.Errorf("expandVars(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+
+	if _, err := expandVars("${A} ${HOST} ${B} ${A} ${C:-ok} ${EMPTY}", lookup, true); err == nil || err.Error() != "undefined environment variables: A, B" {
+		t.Errorf("strict error = %v", err)
+	}
+	for _, in := range []string{"${HOST", "${}"} {
+		if _, err := expandVars(in, lookup, false); err == nil {
+			t.Errorf("expandVars(%q) = nil error", in)
+		}
+	}
+}
+
+func TestEchoCommand_ExpandEnv(t *testing.T) {
+	t.Setenv("ECHO_TEST_HOST", "db.local")
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{name: "expand", args: []string{"--expand-env", "host=${ECHO_TEST_HOST}"}, want: "host=db.local\n"},
+		{name: "off by default", args: []string{"${ECHO_TEST_HOST}"}, want: "${ECHO_TEST_HOST}\n"},
+		{name: "after template", args: []string{"-t", "-e", "{{.Values.v}}", "--set", "v=${ECHO_TEST_HOST}"}, want: "db.local\n"},
+		{name: "strict", args: []string{"-e", "--strict", "${ECHO_TEST_UNDEFINED}"}, wantErr: "undefined environment variables: ECHO_TEST_UNDEFINED"},
+		{name: "strict without expand", args: []string{"--strict", "x"}, wantErr: "--strict requires --expand-env"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCommand(testBuildInfo)
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&buf)
+			cmd.SetArgs(tt.args)
+
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Execute() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Execute() output = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package encode
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/codec"
+	"github.com/anowarislam/ado/internal/completion"
+)
+
+// NewCommand returns the encode command.
+func NewCommand() *cobra.Command {
+	var (
+		file      string
+		noNewline bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "encode SCHEME [TEXT...]",
+		Short: "Encode text or bytes as base64, hex, or URL escapes",
+		Long: `Encode input with SCHEME and print the result followed by a newline.
+
+The input is the TEXT arguments joined by spaces, or the file given with
+--file, or stdin. Text arguments are encoded exactly, without a trailing
+newline; stdin and files are encoded byte for byte.
+
+Schemes:
+  base64     standard alphabet, padded
+  base64url  URL-safe alphabet, unpadded (as in JWTs)
+  hex        lowercase hexadecimal
+  url        query component: spaces become +, reserved characters %XX
+  urlpath    path segment: spaces become %20, / is escaped
+
+Output is never wrapped, unlike base64 on some platforms.
+
+Examples:
+  # Basic auth header value
+  ado encode base64 'user:pass'
+
+  # Encode a binary file
+  ado encode base64 --file logo.png > logo.b64
+
+  # Build a query string value
+  echo "https://example.com/search?q=$(ado encode url 'a&b c')"`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completion.FirstArg(completion.Fixed(codec.Schemes...)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			scheme, err := codec.ParseScheme(args[0])
+			if err != nil {
+				return err
+			}
+			data, err := codec.ReadInput(cmd.InOrStdin(), args[1:], file)
+			if err != nil {
+				return err
+			}
+			out, err := codec.Encode(scheme, data)
+			if err != nil {
+				return err
+			}
+			if !noNewline {
+				out += "\n"
+			}
+			_, err = fmt.Fprint(cmd.OutOrStdout(), out)
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "", `Read input from a file ("-" for stdin)`)
+	cmd.Flags().BoolVarP(&noNewline, "no-newline", "n", false, "Do not print a trailing newline")
+	return cmd
+}
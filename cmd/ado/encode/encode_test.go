@@ -0,0 +1,69 @@
+package encode
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func execute(stdin string, args ...string) (string, error) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewCommand())
+	var out bytes.Buffer
+	root.SetIn(strings.NewReader(stdin))
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"encode"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestEncodeCommand(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "in.bin")
+	if err := os.WriteFile(file, []byte{0xff, 0x00, 0xfe}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		want  string
+	}{
+		{"args joined", "", []string{"base64", "user:pass", "x"}, "dXNlcjpwYXNzIHg=\n"},
+		{"stdin", "hi\n", []string{"hex"}, "68690a\n"},
+		{"file", "", []string{"BASE64URL", "-f", file}, "_wD-\n"},
+		{"no newline", "", []string{"url", "-n", "a b&c"}, "a+b%26c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := execute(tt.stdin, tt.args...)
+			if err != nil || got != tt.want {
+				t.Errorf("Execute() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncodeCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no scheme", nil, "requires at least 1 arg"},
+		{"bad scheme", []string{"rot13", "x"}, `unsupported scheme "rot13"`},
+		{"args and file", []string{"hex", "x", "-f", "y"}, "not both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := execute("", tt.args...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package env
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/dotenv"
+	"github.com/anowarislam/ado/internal/process"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// defaultFile is loaded when no --file is given.
+const defaultFile = ".env"
+
+// NewCommand returns the env parent command with subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "env",
+		Short: "Load dotenv files and run commands with them",
+		Long: `Load variables from dotenv files, print the resolved environment, or run
+a command with it.
+
+Files are applied in order, so later files override earlier ones.
+Variables already set in the environment win over files unless
+--override is given. Values may reference earlier variables with $VAR,
+${VAR}, ${VAR:-default} (unset or empty) or ${VAR-default} (unset).
+Single-quoted values are taken literally.`,
+	}
+
+	cmd.AddCommand(
+		newPrintCommand(),
+		newRunCommand(),
+	)
+
+	return cmd
+}
+
+// loadOptions are the flags shared by the subcommands.
+type loadOptions struct {
+	files    []string
+	override bool
+}
+
+func (o *loadOptions) register(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&o.files, "file", "f", []string{defaultFile}, "Dotenv file to load (repeatable; later files win)")
+	cmd.Flags().BoolVar(&o.override, "override", false, "Let files override variables already set in the environment")
+}
+
+// resolve loads the files and applies them to the process environment.
+func (o *loadOptions) resolve() ([]string, map[string]string, error) {
+	files := make([][]dotenv.Var, 0, len(o.files))
+	for _, path := range o.files {
+		vars, err := dotenv.ParseFile(path)
+		if err != nil {
+			return nil, nil, fmt.Errorf("load env file: %w", err)
+		}
+		files = append(files, vars)
+	}
+	env, loaded := dotenv.Resolve(os.Environ(), files, o.override)
+	return env, loaded, nil
+}
+
+// printResult is the structured output of env print.
+type printResult struct {
+	Files []string          `json:"files" yaml:"files"`
+	Vars  map[string]string `json:"vars" yaml:"vars"`
+}
+
+func newPrintCommand() *cobra.Command {
+	var (
+		opts   loadOptions
+		all    bool
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "print",
+		Short: "Print the variables resolved from dotenv files",
+		Long: `Print the variables set by the dotenv files, after interpolation and
+precedence are applied. With --all, print the whole environment a command
+run by 'ado env run' would see.
+
+Examples:
+  # Show what .env resolves to
+  ado env print
+
+  # Layer a local override file on top of the defaults
+  ado env print -f .env -f .env.local -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+
+			env, loaded, err := opts.resolve()
+			if err != nil {
+				return err
+			}
+
+			vars := loaded
+			if all {
+				vars = make(map[string]string, len(env))
+				for _, kv := range env {
+					key, value, _ := strings.Cut(kv, "=")
+					vars[key] = value
+				}
+			}
+
+			result := printResult{Files: opts.files, Vars: vars}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, result, func() (string, error) {
+				var b strings.Builder
+				for _, key := range dotenv.SortedKeys(vars) {
+					fmt.Fprintf(&b, "%s=%s\n", key, vars[key])
+				}
+				return b.String(), nil
+			})
+		},
+	}
+
+	opts.register(cmd)
+	cmd.Flags().BoolVar(&all, "all", false, "Include inherited environment variables")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+// ExitError reports that the child command exited with a non-zero status.
+type ExitError struct {
+	Command string
+	Code    int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("%s exited with status %d", e.Command, e.Code)
+}
+
+// ExitCode is the status ado should exit with.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+func newRunCommand() *cobra.Command {
+	var opts loadOptions
+
+	cmd := &cobra.Command{
+		Use:   "run [flags] -- COMMAND [ARGS...]",
+		Short: "Run a command with dotenv files loaded",
+		Long: `Run COMMAND with the variables from the dotenv files added to its
+environment. Output is streamed, and ado exits with the command's exit
+status.
+
+Examples:
+  # Run a server with .env loaded
+  ado env run -- go run ./cmd/server
+
+  # Production settings, overriding anything already exported
+  ado env run -f .env -f .env.production --override -- ./deploy.sh`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			env, _, err := opts.resolve()
+			if err != nil {
+				return err
+			}
+
+			child := exec.CommandContext(cmd.Context(), args[0], args[1:]...)
+			child.Env = env
+			child.Stdin = cmd.InOrStdin()
+			child.Stdout = cmd.OutOrStdout()
+			child.Stderr = cmd.ErrOrStderr()
+			process.Graceful(child, process.GracePeriod)
+
+			err = child.Run()
+			var exitErr *exec.ExitError
+			switch {
+			case err == nil:
+				return nil
+			case errors.As(err, &exitErr) && exitErr.ExitCode() > 0:
+				return &ExitError{Command: args[0], Code: exitErr.ExitCode()}
+			default:
+				return fmt.Errorf("run %s: %w", args[0], err)
+			}
+		},
+	}
+
+	opts.register(cmd)
+	return cmd
+}
@@ -0,0 +1,122 @@
+package env
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func execute(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewCommand()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func writeEnv(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestPrint(t *testing.T) {
+	t.Setenv("ADO_TEST_SET", "from-env")
+	base := writeEnv(t, ".env", "ADO_TEST_A=1\nADO_TEST_B=${ADO_TEST_A}-b\nADO_TEST_SET=from-file\n")
+	local := writeEnv(t, ".env.local", "ADO_TEST_A=2\n")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"single file", []string{"-f", base}, "ADO_TEST_A=1\nADO_TEST_B=1-b\nADO_TEST_SET=from-env\n"},
+		{"later file wins", []string{"-f", base, "-f", local}, "ADO_TEST_A=2\nADO_TEST_B=1-b\nADO_TEST_SET=from-env\n"},
+		{"override", []string{"-f", base, "--override"}, "ADO_TEST_A=1\nADO_TEST_B=1-b\nADO_TEST_SET=from-file\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := execute(t, append([]string{"print"}, tt.args...)...)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if out != tt.want {
+				t.Errorf("output = %q, want %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrint_JSON(t *testing.T) {
+	t.Setenv("ADO_TEST_INHERITED", "yes")
+	path := writeEnv(t, ".env", "ADO_TEST_A=1\n")
+
+	out, err := execute(t, "print", "-f", path, "--all", "-o", "json")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var result printResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Files[0] != path || result.Vars["ADO_TEST_A"] != "1" || result.Vars["ADO_TEST_INHERITED"] != "yes" {
+		t.Errorf("result = %+v", result)
+	}
+}
+
+func TestRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	path := writeEnv(t, ".env", "GREETING=hello\nNAME=\"ado env\"\n")
+
+	out, err := execute(t, "run", "-f", path, "--", "sh", "-c", `echo "$GREETING $NAME"`)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if out != "hello ado env\n" {
+		t.Errorf("output = %q", out)
+	}
+
+	_, err = execute(t, "run", "-f", path, "--", "sh", "-c", "exit 3")
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("Execute() error = %v, want exit status 3", err)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	bad := writeEnv(t, ".env", "not valid\n")
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"missing default file", []string{"print", "-f", filepath.Join(t.TempDir(), ".env")}, "no such file"},
+		{"invalid file", []string{"print", "-f", bad}, "invalid line"},
+		{"bad output", []string{"print", "-f", bad, "-o", "xml"}, "unsupported output format"},
+		{"run without command", []string{"run"}, "requires at least 1 arg(s)"},
+		{"command not found", []string{"run", "-f", writeEnv(t, ".env", ""), "--", "ado-no-such-command"}, "run ado-no-such-command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := execute(t, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
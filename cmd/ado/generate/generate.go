@@ -0,0 +1,195 @@
+package generate
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/scaffold"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// NewCommand returns the generate command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Write starter files for tasks, workflows, checks, CI, and services",
+		Long: `Write a starter file for one of ado's automation commands, filled in
+from answers to a few questions. On a terminal, ado asks each question
+not answered by its flag; otherwise, and with --yes, unanswered
+questions take their defaults.
+
+Files are written to the current directory, or --dir, and are never
+overwritten without --force. Every generated task file, workflow, and
+check suite is validated before it is written.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	for _, g := range scaffold.Generators() {
+		cmd.AddCommand(newGeneratorCommand(g))
+	}
+	return cmd
+}
+
+func newGeneratorCommand(g scaffold.Generator) *cobra.Command {
+	var (
+		dir    string
+		stdout bool
+		force  bool
+		yes    bool
+	)
+	values := make(map[string]*string, len(g.Questions))
+
+	cmd := &cobra.Command{
+		Use:         g.Kind,
+		Short:       g.Description,
+		Long:        g.Description + ".\n\n" + questionHelp(g) + "\n\nExamples:\n  # Answer the questions\n  ado generate " + g.Kind + "\n\n  # Print the defaults\n  ado generate " + g.Kind + " --yes --stdout",
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			answers := scaffold.Answers{}
+			for _, q := range g.Questions {
+				if cmd.Flags().Changed(q.Key) {
+					answers[q.Key] = *values[q.Key]
+				}
+			}
+			if !yes && ui.IsInteractive(cmd.InOrStdin()) {
+				if err := ask(cmd.InOrStdin(), cmd.ErrOrStderr(), g, answers); err != nil {
+					return err
+				}
+			}
+			files, err := g.Generate(answers)
+			if err != nil {
+				return err
+			}
+			if stdout {
+				return printFiles(cmd.OutOrStdout(), files)
+			}
+			return writeFiles(cmd, dir, files, force)
+		},
+	}
+
+	for _, q := range g.Questions {
+		values[q.Key] = new(string)
+		cmd.Flags().StringVar(values[q.Key], q.Key, q.Default, q.Prompt)
+	}
+	cmd.Flags().StringVar(&dir, "dir", ".", "Directory to write the files to")
+	cmd.Flags().BoolVar(&stdout, "stdout", false, "Print the files instead of writing them")
+	cmd.Flags().BoolVar(&force, "force", false, "Overwrite existing files")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "Use defaults for questions not answered by flags, without asking")
+	_ = cmd.MarkFlagDirname("dir")
+	return cmd
+}
+
+// ask asks each question of g not in answers on out, reading answers
+// from in. An empty answer takes the default; an invalid one is asked
+// again.
+func ask(in io.Reader, out io.Writer, g scaffold.Generator, answers scaffold.Answers) error {
+	r := bufio.NewReader(in)
+	for _, q := range g.Questions {
+		if _, ok := answers[q.Key]; ok {
+			continue
+		}
+		for {
+			if q.Default != "" {
+				fmt.Fprintf(out, "%s [%s]: ", q.Prompt, q.Default)
+			} else {
+				fmt.Fprintf(out, "%s: ", q.Prompt)
+			}
+			line, err := r.ReadString('\n')
+			if err != nil && err != io.EOF {
+				return fmt.Errorf("read answer: %w", err)
+			}
+			if err == io.EOF && line == "" {
+				// Input ended: the rest take their defaults.
+				fmt.Fprintln(out)
+				return nil
+			}
+			answer := strings.TrimSpace(line)
+			if answer == "" {
+				answer = q.Default
+			}
+			if verr := q.Check(answer); verr != nil {
+				fmt.Fprintf(out, "  %v\n", verr)
+				continue
+			}
+			answers[q.Key] = answer
+			break
+		}
+	}
+	return nil
+}
+
+// printFiles prints files, each after a comment naming it when there are
+// several.
+func printFiles(w io.Writer, files []scaffold.File) error {
+	for i, f := range files {
+		if len(files) > 1 {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			fmt.Fprintf(w, "# ---- %s ----\n", f.Path)
+		}
+		if _, err := w.Write(f.Data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFiles writes files below dir. Nothing is written when any of them
+// exists, unless force is set.
+func writeFiles(cmd *cobra.Command, dir string, files []scaffold.File, force bool) error {
+	plan := ui.NewPlan()
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f.Path))
+		_, err := os.Stat(path)
+		switch {
+		case err == nil && !force:
+			return fmt.Errorf("%s already exists; use --force to overwrite it", path)
+		case err == nil:
+			plan.Add("overwrite", path, "")
+		case errors.Is(err, os.ErrNotExist):
+			plan.Add("create", path, "")
+		default:
+			return err
+		}
+	}
+	if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+		return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+	}
+
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f.Path))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, f.Data, 0o644); err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintf(cmd.OutOrStdout(), "Wrote %s\n", path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// questionHelp lists the questions of g with their flags for the help
+// text.
+func questionHelp(g scaffold.Generator) string {
+	var b strings.Builder
+	b.WriteString("Questions (flag: question):\n")
+	for _, q := range g.Questions {
+		fmt.Fprintf(&b, "  --%-12s %s\n", q.Key, q.Prompt)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
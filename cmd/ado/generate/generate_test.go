@@ -0,0 +1,128 @@
+package generate
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/scaffold"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+func execute(args ...string) (string, error) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().Bool(ui.DryRunFlag, false, "")
+	root.AddCommand(NewCommand())
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"generate"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestGenerate_Write(t *testing.T) {
+	dir := t.TempDir()
+	out, err := execute("github-actions", "--dir", dir, "--name", "health", "--command", "check run ci/checks.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, ".github", "workflows", "health.yml")
+	if out != "Wrote "+path+"\n" {
+		t.Errorf("output = %q", out)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "run: ado check run ci/checks.yaml") {
+		t.Errorf("workflow:\n%s", data)
+	}
+
+	if _, err := execute("github-actions", "--dir", dir, "--name", "health"); err == nil || !strings.Contains(err.Error(), "already exists; use --force") {
+		t.Errorf("second run: err = %v", err)
+	}
+	if _, err := execute("github-actions", "--dir", dir, "--name", "health", "--command", "version", "--force"); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); !strings.Contains(string(data), "run: ado version") {
+		t.Errorf("--force did not overwrite:\n%s", data)
+	}
+}
+
+func TestGenerate_DryRun(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "backup.service"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := execute("systemd", "--dir", dir, "--name", "backup", "--executable", "/usr/bin/ado", "--force", "--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "overwrite") || !strings.Contains(out, "backup.service") || !strings.Contains(out, "create") || !strings.Contains(out, "backup.timer") {
+		t.Errorf("plan:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "backup.timer")); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote backup.timer: %v", err)
+	}
+}
+
+func TestGenerate_Stdout(t *testing.T) {
+	out, err := execute("systemd", "--stdout", "--executable", "/usr/bin/ado", "--schedule", "hourly")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"# ---- ado-job.service ----\n", "\n# ---- ado-job.timer ----\n", "OnCalendar=hourly"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output lacks %q:\n%s", want, out)
+		}
+	}
+
+	out, err = execute("tasks", "--stdout", "--yes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "# ----") || !strings.HasPrefix(out, "# Tasks for `ado run`") {
+		t.Errorf("single file output:\n%s", out)
+	}
+}
+
+func TestGenerate_InvalidFlag(t *testing.T) {
+	_, err := execute("tasks", "--stdout", "--name", "two words")
+	if err == nil || !strings.Contains(err.Error(), `name: invalid name "two words"`) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestAsk(t *testing.T) {
+	g, err := scaffold.Lookup(scaffold.KindTasks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	answers := scaffold.Answers{"description": "from a flag"}
+	var prompts bytes.Buffer
+	// An invalid name is asked again; an empty command takes the default.
+	if err := ask(strings.NewReader("bad name\ntest\n\n"), &prompts, g, answers); err != nil {
+		t.Fatal(err)
+	}
+	if answers["name"] != "test" || answers["command"] != "make build" || answers["description"] != "from a flag" {
+		t.Errorf("answers = %v", answers)
+	}
+	want := "Task name [build]: " + `  name: invalid name "bad name": use letters, digits, '.', '_' and '-'` + "\nTask name [build]: Command line the task runs [make build]: "
+	if prompts.String() != want {
+		t.Errorf("prompts = %q, want %q", prompts.String(), want)
+	}
+
+	// Input that ends early leaves the rest to their defaults.
+	answers = scaffold.Answers{}
+	if err := ask(strings.NewReader("test"), &prompts, g, answers); err != nil {
+		t.Fatal(err)
+	}
+	if len(answers) != 1 || answers["name"] != "test" {
+		t.Errorf("answers = %v", answers)
+	}
+}
@@ -0,0 +1,180 @@
+package hash
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	"github.com/anowarislam/ado/internal/digest"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// stdinName is the path that reads standard input.
+const stdinName = "-"
+
+// hashOutput is the payload for hashing inputs.
+type hashOutput struct {
+	Algorithm string         `json:"algorithm" yaml:"algorithm"`
+	Entries   []digest.Entry `json:"entries" yaml:"entries"`
+}
+
+// checkOutput is the payload for --check.
+type checkOutput struct {
+	Algorithm string               `json:"algorithm" yaml:"algorithm"`
+	OK        bool                 `json:"ok" yaml:"ok"`
+	Results   []digest.CheckResult `json:"results" yaml:"results"`
+}
+
+// NewCommand returns the hash command.
+func NewCommand() *cobra.Command {
+	var (
+		algorithm string
+		check     string
+		quiet     bool
+		output    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "hash [path...]",
+		Short: "Hash files, directories, or stdin",
+		Long: `Print digests of files, directories, or stdin, or verify them against a
+checksum file.
+
+With no paths, or a path of "-", stdin is hashed. Directories get a
+deterministic Merkle-style digest of their contents: file names and
+contents count, permissions and timestamps do not.
+
+Text output uses the sha256sum format, so it can be saved and verified
+later with --check. --check accepts GNU ("<digest>  name") and BSD
+("SHA256 (name) = <digest>") lines.
+
+Algorithms: ` + strings.Join(digest.Algorithms, ", ") + `
+
+Examples:
+  # Hash files
+  ado hash go.mod go.sum
+
+  # Hash a directory tree with BLAKE2b
+  ado hash -a blake2b ./dist
+
+  # Record and verify checksums
+  ado hash dist/* > sums.txt
+  ado hash --check sums.txt`,
+		Args: cobra.ArbitraryArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			if _, err := digest.New(algorithm); err != nil {
+				return err
+			}
+			algorithm = strings.ToLower(algorithm)
+
+			if check != "" {
+				if len(args) > 0 {
+					return fmt.Errorf("--check takes no paths (got %d)", len(args))
+				}
+				return runCheck(cmd, check, algorithm, quiet, format)
+			}
+
+			if len(args) == 0 {
+				args = []string{stdinName}
+			}
+			payload := hashOutput{Algorithm: algorithm}
+			for _, path := range args {
+				entry, err := hashPath(cmd.InOrStdin(), path, algorithm)
+				if err != nil {
+					return err
+				}
+				payload.Entries = append(payload.Entries, entry)
+			}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				var b strings.Builder
+				for _, e := range payload.Entries {
+					fmt.Fprintf(&b, "%s  %s\n", e.Digest, e.Path)
+				}
+				return b.String(), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&algorithm, "algorithm", "a", digest.DefaultAlgorithm, "Hash algorithm: "+strings.Join(digest.Algorithms, ", "))
+	_ = cmd.RegisterFlagCompletionFunc("algorithm", completion.Fixed(digest.Algorithms...))
+	cmd.Flags().StringVarP(&check, "check", "c", "", `Verify digests listed in a checksum file ("-" for stdin)`)
+	cmd.Flags().BoolVarP(&quiet, "quiet", "q", false, "With --check, print only entries that did not verify")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func hashPath(stdin io.Reader, path, algorithm string) (digest.Entry, error) {
+	if path != stdinName {
+		entry, err := digest.Path(path, algorithm)
+		if err != nil {
+			return digest.Entry{}, fmt.Errorf("hash %s: %w", path, err)
+		}
+		return entry, nil
+	}
+	sum, size, err := digest.Reader(stdin, algorithm)
+	if err != nil {
+		return digest.Entry{}, fmt.Errorf("hash stdin: %w", err)
+	}
+	return digest.Entry{Path: stdinName, Kind: digest.KindStdin, Digest: sum, SizeBytes: size}, nil
+}
+
+func runCheck(cmd *cobra.Command, path, algorithm string, quiet bool, format ui.OutputFormat) error {
+	var r io.Reader = cmd.InOrStdin()
+	if path != stdinName {
+		f, err := os.Open(path)
+		if err != nil {
+			return fmt.Errorf("open checksum file: %w", err)
+		}
+		defer f.Close()
+		r = f
+	}
+	sums, err := digest.ParseChecksums(r, path)
+	if err != nil {
+		return err
+	}
+
+	results := digest.Verify(sums, algorithm)
+	failed := 0
+	for _, res := range results {
+		if res.Status != digest.StatusOK {
+			failed++
+		}
+	}
+
+	payload := checkOutput{Algorithm: algorithm, OK: failed == 0, Results: results}
+	err = ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+		return formatCheck(results, quiet), nil
+	})
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return ui.Reported(fmt.Errorf("%d of %d checksums did not verify", failed, len(results)))
+	}
+	return nil
+}
+
+func formatCheck(results []digest.CheckResult, quiet bool) string {
+	var b strings.Builder
+	for _, res := range results {
+		switch res.Status {
+		case digest.StatusOK:
+			if !quiet {
+				fmt.Fprintf(&b, "%s: OK\n", res.Path)
+			}
+		case digest.StatusError:
+			fmt.Fprintf(&b, "%s: ERROR (%s)\n", res.Path, res.Error)
+		default:
+			fmt.Fprintf(&b, "%s: %s\n", res.Path, strings.ToUpper(res.Status))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,153 @@
+package hash
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const helloSHA256 = "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+func execute(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewCommand()
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetErr(&buf)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func writeFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestHash(t *testing.T) {
+	dir := t.TempDir()
+	file := writeFile(t, dir, "hello.txt", "hello")
+
+	tests := []struct {
+		name  string
+		stdin string
+		args  []string
+		want  string
+	}{
+		{"file", "", []string{file}, helloSHA256 + "  " + file + "\n"},
+		{"stdin", "hello", nil, helloSHA256 + "  -\n"},
+		{"stdin dash", "hello", []string{"-"}, helloSHA256 + "  -\n"},
+		{"md5", "hello", []string{"-a", "MD5"}, "5d41402abc4b2a76b9719d911017c592  -\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := execute(t, tt.stdin, tt.args...)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if out != tt.want {
+				t.Errorf("output = %q, want %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestHash_JSON(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "a.txt", "abc")
+
+	out, err := execute(t, "", dir, "-o", "json")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var payload hashOutput
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if payload.Algorithm != "sha256" || len(payload.Entries) != 1 {
+		t.Fatalf("payload = %+v", payload)
+	}
+	if e := payload.Entries[0]; e.Kind != "dir" || e.SizeBytes != 3 || len(e.Digest) != 64 {
+		t.Errorf("entry = %+v", e)
+	}
+}
+
+func TestHash_Check(t *testing.T) {
+	dir := t.TempDir()
+	good := writeFile(t, dir, "good.txt", "hello")
+	bad := writeFile(t, dir, "bad.txt", "changed")
+
+	sums := helloSHA256 + "  " + good + "\n" +
+		helloSHA256 + "  " + bad + "\n" +
+		helloSHA256 + "  " + filepath.Join(dir, "missing.txt") + "\n"
+	sumsFile := writeFile(t, dir, "sums.txt", sums)
+
+	out, err := execute(t, "", "--check", sumsFile)
+	if err == nil || err.Error() != "2 of 3 checksums did not verify" {
+		t.Errorf("Execute() error = %v", err)
+	}
+	for _, want := range []string{good + ": OK", bad + ": FAILED", "missing.txt: MISSING"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, _ = execute(t, "", "--check", sumsFile, "--quiet")
+	if strings.Contains(out, "OK") {
+		t.Errorf("--quiet printed OK lines:\n%s", out)
+	}
+
+	out, err = execute(t, helloSHA256+"  "+good+"\n", "-c", "-", "-o", "json")
+	if err != nil {
+		t.Fatalf("check from stdin error = %v", err)
+	}
+	if !strings.Contains(out, `"ok": true`) || !strings.Contains(out, `"status": "ok"`) {
+		t.Errorf("JSON output = %s", out)
+	}
+}
+
+func TestHash_RoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	a := writeFile(t, dir, "a.txt", "a")
+	b := writeFile(t, dir, "b.txt", "b")
+
+	sums, err := execute(t, "", "-a", "sha512", a, b, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sumsFile := writeFile(t, t.TempDir(), "sums.txt", sums)
+	if out, err := execute(t, "", "-a", "sha512", "-c", sumsFile); err != nil {
+		t.Errorf("verifying own output failed: %v\n%s", err, out)
+	}
+}
+
+func TestHash_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown algorithm", []string{"-a", "crc32"}, "unsupported algorithm"},
+		{"missing file", []string{"/nonexistent/file"}, "hash /nonexistent/file"},
+		{"check with paths", []string{"-c", "sums.txt", "file"}, "--check takes no paths"},
+		{"missing checksum file", []string{"-c", "/nonexistent/sums.txt"}, "open checksum file"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := execute(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
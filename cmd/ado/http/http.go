@@ -0,0 +1,210 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/httpclient"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/secrets"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// methods are the HTTP methods exposed as subcommands.
+var methods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"}
+
+// NewCommand returns the http command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "http",
+		Short: "Send HTTP requests",
+		Long: `Send HTTP requests with httpie-style request items, retries, and
+structured output.
+
+Request items:
+  Header:value   request header
+  name==value    query parameter
+  field=value    JSON body field (string)
+  field:=json    JSON body field (raw JSON: number, bool, array, object)
+
+Items and --header values may contain secret://NAME references to secrets
+stored with 'ado secret set'.
+
+URLs without a scheme use http://, and ":8080/path" is shorthand for
+http://localhost:8080/path.
+
+Examples:
+  # GET with a query parameter and a header
+  ado http get api.example.com/items page==2 Authorization:"Bearer $TOKEN"
+
+  # POST a JSON body: {"name":"ado","count":3}
+  ado http post :8080/items name=ado count:=3
+
+  # Retry transient failures and print the parsed response as JSON
+  ado http get https://example.com/health --retries 3 -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	for _, method := range methods {
+		cmd.AddCommand(newMethodCommand(method))
+	}
+	return cmd
+}
+
+// options are the flags shared by every method subcommand.
+type options struct {
+	headers     []string
+	data        string
+	timeout     time.Duration
+	retries     int
+	retryDelay  time.Duration
+	output      string
+	bodyOnly    bool
+	checkStatus bool
+}
+
+func newMethodCommand(method string) *cobra.Command {
+	var opts options
+
+	cmd := &cobra.Command{
+		Use:   strings.ToLower(method) + " URL [ITEM...]",
+		Short: fmt.Sprintf("Send a %s request", method),
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(opts.output)
+			if err != nil {
+				return err
+			}
+
+			items := append([]string{}, args[1:]...)
+			headers := append([]string{}, opts.headers...)
+			store := secrets.Default()
+			if err := secrets.ExpandAll(store, items); err != nil {
+				return err
+			}
+			if err := secrets.ExpandAll(store, headers); err != nil {
+				return err
+			}
+
+			req, err := httpclient.NewRequest(method, args[0], items)
+			if err != nil {
+				return err
+			}
+			for _, header := range headers {
+				name, value, ok := strings.Cut(header, ":")
+				if !ok || strings.TrimSpace(name) == "" {
+					return fmt.Errorf("invalid header %q: expected \"Name: value\"", header)
+				}
+				req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+			}
+			if opts.data != "" {
+				if len(req.Fields) > 0 {
+					return errors.New("use either --data or body fields, not both")
+				}
+				if req.Body, err = readData(cmd.InOrStdin(), opts.data); err != nil {
+					return err
+				}
+			}
+
+			client := &httpclient.Client{
+				Timeout:    opts.timeout,
+				Retries:    opts.retries,
+				RetryDelay: opts.retryDelay,
+				UserAgent:  "ado/" + internalmeta.CurrentBuildInfo().Version,
+			}
+			resp, err := client.Do(cmd.Context(), req)
+			if err != nil {
+				return err
+			}
+
+			if opts.bodyOnly && format == ui.OutputText {
+				if _, err := cmd.OutOrStdout().Write(resp.Raw); err != nil {
+					return err
+				}
+			} else if err := ui.PrintOutput(cmd.OutOrStdout(), format, resp, func() (string, error) {
+				return formatResponse(resp), nil
+			}); err != nil {
+				return err
+			}
+
+			if opts.checkStatus {
+				return ui.Reported(httpclient.CheckStatus(resp))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&opts.headers, "header", "H", nil, `Request header as "Name: value" (repeatable)`)
+	cmd.Flags().StringVarP(&opts.data, "data", "d", "", "Raw request body; @FILE reads a file, @- reads stdin")
+	cmd.Flags().DurationVar(&opts.timeout, "timeout", httpclient.DefaultTimeout, "Timeout for each attempt (0 for none)")
+	cmd.Flags().IntVar(&opts.retries, "retries", 0, "Retry transport errors, 429, and 5xx responses up to this many times")
+	cmd.Flags().DurationVar(&opts.retryDelay, "retry-delay", httpclient.DefaultRetryDelay, "Delay before the first retry; doubles after each")
+	cmd.Flags().StringVarP(&opts.output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().BoolVarP(&opts.bodyOnly, "body", "b", false, "Print only the response body (text output)")
+	cmd.Flags().BoolVar(&opts.checkStatus, "check-status", false, "Exit with 3, 4, or 5 on 3xx, 4xx, or 5xx responses")
+	return cmd
+}
+
+// readData resolves the --data value: literal text, @FILE, or @- for stdin.
+func readData(stdin io.Reader, data string) ([]byte, error) {
+	name, ok := strings.CutPrefix(data, "@")
+	if !ok {
+		return []byte(data), nil
+	}
+	if name == "-" {
+		body, err := io.ReadAll(stdin)
+		if err != nil {
+			return nil, fmt.Errorf("read body from stdin: %w", err)
+		}
+		return body, nil
+	}
+	body, err := os.ReadFile(name)
+	if err != nil {
+		return nil, fmt.Errorf("read body: %w", err)
+	}
+	return body, nil
+}
+
+// formatResponse renders the status line, headers, and body like an
+// HTTP message.
+func formatResponse(resp *httpclient.Response) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %s\n", resp.Proto, resp.Status)
+
+	names := make([]string, 0, len(resp.Headers))
+	for name := range resp.Headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range resp.Headers[name] {
+			fmt.Fprintf(&b, "%s: %s\n", name, value)
+		}
+	}
+
+	switch {
+	case resp.BodyBase64 != "":
+		fmt.Fprintf(&b, "\n[binary data: %d bytes]\n", len(resp.Raw))
+	case resp.Body == nil:
+	case httpclient.IsJSON(resp.Headers.Get("Content-Type")):
+		data, err := json.MarshalIndent(resp.Body, "", "  ")
+		if err != nil {
+			data = resp.Raw
+		}
+		fmt.Fprintf(&b, "\n%s\n", data)
+	default:
+		fmt.Fprintf(&b, "\n%s", resp.Raw)
+	}
+	return b.String()
+}
@@ -0,0 +1,211 @@
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/zalando/go-keyring"
+
+	"github.com/anowarislam/ado/internal/httpclient"
+	"github.com/anowarislam/ado/internal/secrets"
+)
+
+// newServer echoes the request back as JSON and answers /missing with 404.
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path == "/missing" {
+			nethttp.Error(w, "not here", nethttp.StatusNotFound)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"method":       r.Method,
+			"query":        r.URL.RawQuery,
+			"token":        r.Header.Get("X-Token"),
+			"content_type": r.Header.Get("Content-Type"),
+			"body":         string(body),
+		})
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func execute(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewCommand())
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	root.SetIn(strings.NewReader(stdin))
+	root.SetArgs(args)
+	err := root.Execute()
+	return buf.String(), err
+}
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+	for _, name := range []string{"get", "post", "put", "patch", "delete", "head", "options"} {
+		if sub, _, err := cmd.Find([]string{name}); err != nil || sub.Name() != name {
+			t.Errorf("subcommand %q not found", name)
+		}
+	}
+}
+
+func TestHTTP_Text(t *testing.T) {
+	srv := newServer(t)
+	out, err := execute(t, "", "http", "post", srv.URL+"/items", "name=ado", "count:=3", "q==1", "-H", "X-Token: abc")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	for _, want := range []string{
+		"HTTP/1.1 200 OK\n",
+		"Content-Type: application/json\n",
+		`"method": "POST"`,
+		`"query": "q=1"`,
+		`"token": "abc"`,
+		`"content_type": "application/json"`,
+		`"body": "{\"count\":3,\"name\":\"ado\"}"`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestHTTP_JSON(t *testing.T) {
+	srv := newServer(t)
+	out, err := execute(t, "", "http", "get", srv.URL, "-o", "json")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var resp struct {
+		StatusCode int            `json:"status_code"`
+		Attempts   int            `json:"attempts"`
+		Headers    map[string]any `json:"headers"`
+		Body       map[string]any `json:"body"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if resp.StatusCode != 200 || resp.Attempts != 1 || resp.Body["method"] != "GET" || resp.Headers["Content-Type"] == nil {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestHTTP_Data(t *testing.T) {
+	srv := newServer(t)
+	file := filepath.Join(t.TempDir(), "body.txt")
+	if err := os.WriteFile(file, []byte("from file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		data  string
+		stdin string
+		want  string
+	}{
+		{"literal", "", "literal"},
+		{"@" + file, "", "from file"},
+		{"@-", "from stdin", "from stdin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.data, func(t *testing.T) {
+			out, err := execute(t, tt.stdin, "http", "put", srv.URL, "-d", tt.data, "-b")
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if !strings.Contains(out, `"body":"`+tt.want+`"`) {
+				t.Errorf("body-only output = %q, want body %q", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTTP_SecretRefs(t *testing.T) {
+	keyring.MockInit()
+	if err := secrets.Default().Set("token", "s3cr3t"); err != nil {
+		t.Fatal(err)
+	}
+	srv := newServer(t)
+
+	out, err := execute(t, "", "http", "post", srv.URL, "-H", "X-Token: secret://token", "q==secret://token", "-b")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(out, `"token":"s3cr3t"`) || !strings.Contains(out, `"query":"q=s3cr3t"`) {
+		t.Errorf("output = %q, want resolved secret in header and query", out)
+	}
+
+	_, err = execute(t, "", "http", "get", srv.URL, "X-Token:secret://missing")
+	if !errors.Is(err, secrets.ErrNotFound) {
+		t.Errorf("Execute(missing secret) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestHTTP_CheckStatus(t *testing.T) {
+	srv := newServer(t)
+
+	out, err := execute(t, "", "http", "get", srv.URL+"/missing")
+	if err != nil {
+		t.Fatalf("without --check-status: error = %v", err)
+	}
+	if !strings.Contains(out, "404 Not Found") || !strings.Contains(out, "not here") {
+		t.Errorf("output = %q", out)
+	}
+
+	_, err = execute(t, "", "http", "get", srv.URL+"/missing", "--check-status")
+	var statusErr *httpclient.StatusError
+	if !errors.As(err, &statusErr) || statusErr.ExitCode() != 4 {
+		t.Errorf("Execute() error = %v, want StatusError with exit code 4", err)
+	}
+}
+
+func TestHTTP_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"missing url", []string{"http", "get"}, "requires at least 1 arg"},
+		{"bad item", []string{"http", "get", "example.com", "oops"}, "invalid request item"},
+		{"bad header", []string{"http", "get", "example.com", "-H", "oops"}, "invalid header"},
+		{"data and fields", []string{"http", "post", "example.com", "a=b", "-d", "x"}, "not both"},
+		{"bad output", []string{"http", "get", "example.com", "-o", "xml"}, "unsupported output format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := execute(t, "", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatResponse_Binary(t *testing.T) {
+	resp := &httpclient.Response{
+		Proto:      "HTTP/1.1",
+		Status:     "200 OK",
+		Headers:    nethttp.Header{"Content-Type": {"image/png"}},
+		BodyBase64: "iVBO",
+		Raw:        []byte{0x89, 'P', 'N'},
+	}
+	want := "HTTP/1.1 200 OK\nContent-Type: image/png\n\n[binary data: 3 bytes]\n"
+	if got := formatResponse(resp); got != want {
+		t.Errorf("formatResponse() = %q, want %q", got, want)
+	}
+}
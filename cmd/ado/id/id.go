@@ -0,0 +1,120 @@
+package id
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	"github.com/anowarislam/ado/internal/idgen"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// idOutput is the payload for structured output.
+type idOutput struct {
+	Kind string   `json:"kind" yaml:"kind"`
+	IDs  []string `json:"ids" yaml:"ids"`
+}
+
+// NewCommand returns the id command.
+func NewCommand() *cobra.Command {
+	var (
+		opts   idgen.Options
+		count  int
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "id [KIND]",
+		Short: "Generate UUIDs, ULIDs, nanoids, and random strings",
+		Long: `Print one or more random identifiers, one per line. KIND defaults to
+uuid.
+
+Kinds:
+  uuid       random UUID (version 4)
+  uuidv7     time-ordered UUID (version 7)
+  ulid       time-ordered ULID; IDs from one run are strictly increasing
+  nanoid     URL-safe nanoid, --length characters from --alphabet
+  hex        --bytes random bytes as hex
+  base64     --bytes random bytes as padded standard base64
+  base64url  --bytes random bytes as unpadded URL-safe base64
+
+All randomness comes from the operating system's secure generator, so
+hex and base64 values are suitable as secrets and tokens.
+
+Examples:
+  # A UUID
+  ado id
+
+  # Ten sortable IDs
+  ado id ulid -n 10
+
+  # A 32-byte token for a config file
+  ado id base64url --bytes 32
+
+  # Short numeric codes
+  ado id nanoid --length 6 --alphabet 0123456789`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completion.FirstArg(completion.Fixed(idgen.Kinds...)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			kind := idgen.KindUUID
+			if len(args) == 1 {
+				kind = strings.ToLower(args[0])
+			}
+			if count < 1 {
+				return errors.New("--count must be at least 1")
+			}
+			if err := checkFlags(cmd, kind); err != nil {
+				return err
+			}
+
+			gen, err := idgen.New(kind, opts)
+			if err != nil {
+				return err
+			}
+			payload := idOutput{Kind: kind, IDs: make([]string, count)}
+			for i := range payload.IDs {
+				if payload.IDs[i], err = gen.Next(); err != nil {
+					return fmt.Errorf("generate %s: %w", kind, err)
+				}
+			}
+
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return strings.Join(payload.IDs, "\n") + "\n", nil
+			})
+		},
+	}
+
+	cmd.Flags().IntVarP(&count, "count", "n", 1, "Number of IDs to generate")
+	cmd.Flags().IntVar(&opts.Length, "length", idgen.DefaultNanoIDLength, "nanoid length in characters")
+	cmd.Flags().StringVar(&opts.Alphabet, "alphabet", idgen.DefaultNanoIDAlphabet, "nanoid alphabet (ASCII)")
+	cmd.Flags().IntVar(&opts.Bytes, "bytes", idgen.DefaultBytes, "Random bytes behind hex and base64 values")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+// kindFlags lists the flags that only apply to some kinds.
+var kindFlags = map[string][]string{
+	"length":   {idgen.KindNanoID},
+	"alphabet": {idgen.KindNanoID},
+	"bytes":    {idgen.KindHex, idgen.KindBase64, idgen.KindBase64URL},
+}
+
+// checkFlags rejects flags that kind would silently ignore.
+func checkFlags(cmd *cobra.Command, kind string) error {
+	for _, name := range []string{"length", "alphabet", "bytes"} {
+		kinds := kindFlags[name]
+		if !cmd.Flags().Changed(name) || slices.Contains(kinds, kind) {
+			continue
+		}
+		return fmt.Errorf("--%s only applies to %s", name, strings.Join(kinds, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package id
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func execute(args ...string) (string, error) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewCommand())
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"id"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestIDCommand(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		lines   int
+		wantLen int
+	}{
+		{"default uuid", nil, 1, 36},
+		{"ulid count", []string{"ULID", "-n", "3"}, 3, 26},
+		{"nanoid length", []string{"nanoid", "--length", "10"}, 1, 10},
+		{"hex bytes", []string{"hex", "--bytes", "8"}, 1, 16},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := execute(tt.args...)
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			lines := strings.Split(strings.TrimSuffix(out, "\n"), "\n")
+			if len(lines) != tt.lines {
+				t.Fatalf("got %d lines, want %d: %q", len(lines), tt.lines, out)
+			}
+			for _, line := range lines {
+				if len(line) != tt.wantLen {
+					t.Errorf("id %q has length %d, want %d", line, len(line), tt.wantLen)
+				}
+			}
+		})
+	}
+}
+
+func TestIDCommand_JSON(t *testing.T) {
+	out, err := execute("uuidv7", "-n", "2", "-o", "json")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var payload idOutput
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if payload.Kind != "uuidv7" || len(payload.IDs) != 2 || payload.IDs[0] == payload.IDs[1] {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestIDCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown kind", []string{"guid"}, `unsupported kind "guid"`},
+		{"zero count", []string{"-n", "0"}, "--count must be at least 1"},
+		{"length with uuid", []string{"uuid", "--length", "5"}, "--length only applies to nanoid"},
+		{"bytes with nanoid", []string{"nanoid", "--bytes", "5"}, "--bytes only applies to hex, base64, base64url"},
+		{"bad alphabet", []string{"nanoid", "--alphabet", "x"}, "2 to 256 characters"},
+		{"too many args", []string{"uuid", "ulid"}, "accepts at most 1 arg"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := execute(tt.args...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package inventory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	internalinventory "github.com/anowarislam/ado/internal/inventory"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// NewCommand returns the inventory parent command with subcommands.
+func NewCommand() *cobra.Command {
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "inventory",
+		Short: "Check and list the hosts of the host inventory",
+		Long: `The host inventory is a YAML file naming the hosts that multi-host
+commands such as 'ado remote' reach, how to connect to them, their
+labels, and groups of them:
+
+  version: 1
+  defaults:                 # SSH settings of every host
+    user: ops
+    options: [-oConnectTimeout=5]
+  hosts:
+    web-1: {address: 10.0.1.11, labels: {zone: a}}
+    web-2: {address: 10.0.1.12, port: 2222}
+    db-1: {}                # address defaults to the name
+  groups:
+    web:
+      hosts: [web-*]        # names or glob patterns
+      labels: {role: web}
+    prod:
+      groups: [web]         # includes the hosts of web
+      hosts: [db-1]
+      user: deploy
+
+The file is --file, $ADO_INVENTORY, or inventory.yaml next to the user
+config file (see 'ado meta paths').`,
+	}
+
+	cmd.PersistentFlags().StringVarP(&file, "file", "f", "", "Inventory file (default: $ADO_INVENTORY or inventory.yaml in the config directory)")
+	_ = cmd.MarkPersistentFlagFilename("file", "yaml", "yml")
+
+	cmd.AddCommand(
+		newValidateCommand(&file),
+		newListCommand(&file),
+	)
+	return cmd
+}
+
+// ValidationResult is the output of inventory validate.
+type ValidationResult struct {
+	Valid  bool     `json:"valid" yaml:"valid"`
+	Path   string   `json:"path" yaml:"path"`
+	Hosts  int      `json:"hosts" yaml:"hosts"`
+	Groups int      `json:"groups" yaml:"groups"`
+	Errors []string `json:"errors" yaml:"errors"`
+}
+
+func newValidateCommand(file *string) *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Check the inventory file for mistakes",
+		Long: `Check the inventory file and report every problem: unknown keys,
+invalid host names, addresses, users, ports, and ssh options, group
+patterns that match no host, unknown or circular included groups, and
+groups named like a host. ado exits 1 when there are problems.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			path := inventoryPath(*file)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("read inventory: %w", err)
+			}
+
+			result := ValidationResult{Path: path, Errors: []string{}}
+			if inv, err := internalinventory.Parse(bytes.NewReader(data)); err != nil {
+				result.Errors = append(result.Errors, err.Error())
+			} else {
+				result.Hosts, result.Groups = len(inv.Hosts), len(inv.Groups)
+				result.Errors = append(result.Errors, inv.Check()...)
+			}
+			result.Valid = len(result.Errors) == 0
+
+			err = ui.PrintOutput(cmd.OutOrStdout(), format, result, func() (string, error) {
+				return formatValidationResult(result), nil
+			})
+			if err != nil {
+				return err
+			}
+			if !result.Valid {
+				return ui.Reported(errors.New("inventory is invalid"))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func formatValidationResult(result ValidationResult) string {
+	if result.Valid {
+		return fmt.Sprintf("✓ Inventory valid: %s (%d hosts, %d groups)", result.Path, result.Hosts, result.Groups)
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "✗ Inventory invalid: %s", result.Path)
+	for _, e := range result.Errors {
+		fmt.Fprintf(&b, "\n  Error: %s", e)
+	}
+	return b.String()
+}
+
+type listOutput struct {
+	Hosts []internalinventory.Host `json:"hosts" yaml:"hosts"`
+}
+
+func newListCommand(file *string) *cobra.Command {
+	var (
+		output string
+		hosts  []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List inventory hosts with their resolved settings",
+		Long: `List the hosts of the inventory with the settings they get from the
+defaults and their groups.
+
+--hosts selects hosts the way other multi-host commands do: a host or
+group name, a glob over host and group names such as web-*, or a label
+selector KEY=VALUE. Patterns are comma-separated or repeated, and a
+pattern that matches nothing is an error.
+
+Examples:
+  # Everything
+  ado inventory list
+
+  # What would 'ado remote system --hosts prod' contact?
+  ado inventory list --hosts prod
+
+  # Hosts in zone a, as JSON
+  ado inventory list --hosts zone=a -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			path := inventoryPath(*file)
+			inv, err := internalinventory.Load(path)
+			if err != nil {
+				return err
+			}
+			payload := listOutput{Hosts: inv.All()}
+			if len(hosts) > 0 {
+				if payload.Hosts, err = inv.Select(hosts); err != nil {
+					return err
+				}
+			}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatHosts(path, payload.Hosts), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().StringSliceVar(&hosts, "hosts", nil, "Only hosts matching these names, globs, or KEY=VALUE labels")
+	_ = cmd.RegisterFlagCompletionFunc("hosts", completion.InventoryHosts("file"))
+	return cmd
+}
+
+func formatHosts(path string, hosts []internalinventory.Host) string {
+	if len(hosts) == 0 {
+		return fmt.Sprintf("No hosts in %s.", path)
+	}
+	rows := [][]string{{"NAME", "DESTINATION", "GROUPS", "LABELS"}}
+	for _, h := range hosts {
+		destination := h.Destination()
+		if h.Port != 0 {
+			destination += fmt.Sprintf(" (port %d)", h.Port)
+		}
+		var labels []string
+		for _, key := range slices.Sorted(maps.Keys(h.Labels)) {
+			labels = append(labels, key+"="+h.Labels[key])
+		}
+		rows = append(rows, []string{h.Name, destination, strings.Join(h.Groups, ","), strings.Join(labels, ",")})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	lines := make([]string, len(rows))
+	for i, row := range rows {
+		var b strings.Builder
+		for j, cell := range row {
+			if j < len(row)-1 {
+				fmt.Fprintf(&b, "%-*s  ", widths[j], cell)
+			} else {
+				b.WriteString(cell)
+			}
+		}
+		lines[i] = strings.TrimRight(b.String(), " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func inventoryPath(file string) string {
+	homeDir, _ := os.UserHomeDir()
+	return internalinventory.Path(file, homeDir)
+}
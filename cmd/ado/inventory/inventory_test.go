@@ -0,0 +1,87 @@
+package inventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+const testInventory = `version: 1
+defaults: {user: ops}
+hosts:
+  web-1: {address: 10.0.1.11, labels: {zone: a}}
+  web-2: {address: 10.0.1.12, port: 2222, labels: {zone: b}}
+  db-1: {}
+groups:
+  web: {hosts: [web-*], labels: {role: web}}
+`
+
+func run(t *testing.T, inventory string, args ...string) (string, error) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "inventory.yaml")
+	if err := os.WriteFile(path, []byte(inventory), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewCommand())
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	root.SetArgs(append(append([]string{"inventory"}, args...), "--file", path))
+	err := root.Execute()
+	return strings.ReplaceAll(buf.String(), path, "PATH"), err
+}
+
+func TestValidate(t *testing.T) {
+	out, err := run(t, testInventory, "validate")
+	if err != nil || out != "✓ Inventory valid: PATH (3 hosts, 1 groups)\n" {
+		t.Errorf("output = %q, error = %v", out, err)
+	}
+
+	out, err = run(t, "version: 1\nhosts: {web-1: {port: 0x10000}}\ngroups: {web: {hosts: [web-3]}}\n", "validate")
+	want := "✗ Inventory invalid: PATH\n  Error: host \"web-1\": port 65536 out of range\n  Error: group \"web\": no host matches \"web-3\"\n"
+	if !ui.IsReported(err) || out != want {
+		t.Errorf("output = %q, want %q (error %v)", out, want, err)
+	}
+
+	out, err = run(t, "version: 1\nhost: {}\n", "validate", "-o", "json")
+	var result ValidationResult
+	if jsonErr := json.Unmarshal([]byte(out), &result); jsonErr != nil || result.Valid || len(result.Errors) != 1 || err == nil {
+		t.Errorf("output = %s, error = %v", out, err)
+	}
+}
+
+func TestList(t *testing.T) {
+	out, err := run(t, testInventory, "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `NAME   DESTINATION                GROUPS  LABELS
+db-1   ops@db-1
+web-1  ops@10.0.1.11              web     role=web,zone=a
+web-2  ops@10.0.1.12 (port 2222)  web     role=web,zone=b
+`
+	if out != want {
+		t.Errorf("output =\n%s\nwant\n%s", out, want)
+	}
+
+	out, err = run(t, testInventory, "list", "--hosts", "zone=b", "-o", "json")
+	var payload listOutput
+	if err != nil || json.Unmarshal([]byte(out), &payload) != nil || len(payload.Hosts) != 1 || payload.Hosts[0].Port != 2222 {
+		t.Errorf("output = %s, error = %v", out, err)
+	}
+
+	if _, err := run(t, testInventory, "list", "--hosts", "cache"); err == nil || !strings.Contains(err.Error(), `no hosts match "cache"`) {
+		t.Errorf("error = %v", err)
+	}
+	if _, err := run(t, "version: 1\nhosts: {-x: {}}\n", "list"); err == nil || !strings.Contains(err.Error(), `host "-x": invalid name`) {
+		t.Errorf("error = %v", err)
+	}
+}
@@ -0,0 +1,540 @@
+package logs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/logging"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// followInterval is how often --follow looks for new records.
+const followInterval = 500 * time.Millisecond
+
+// shortIDLen is how much of a run ID prefixes records of several runs:
+// the random end of the ULID.
+const shortIDLen = 8
+
+// NewCommand returns the logs command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "logs",
+		Short: "Inspect ado's own run logs",
+		Long: `List, read, follow, and prune the run logs ado writes with
+--save-run-log (or defaults.save-run-log: true in the config file): one
+file per run in the logs directory under the state directory, holding
+the run's records at debug level and above.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newListCommand(),
+		newShowCommand(),
+		newPruneCommand(),
+	)
+	return cmd
+}
+
+// otherFile is a file in the logs directory that is not a run log, such
+// as the schedule's run history.
+type otherFile struct {
+	Name      string `json:"name" yaml:"name"`
+	SizeBytes int64  `json:"size_bytes" yaml:"size_bytes"`
+}
+
+type listOutput struct {
+	Dir        string            `json:"dir" yaml:"dir"`
+	Runs       []logging.RunInfo `json:"runs" yaml:"runs"`
+	OtherFiles []otherFile       `json:"other_files" yaml:"other_files"`
+}
+
+func newListCommand() *cobra.Command {
+	var (
+		dir     string
+		command string
+		failed  bool
+		output  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the run logs, oldest first",
+		Long: `List the run logs, oldest first: each run's ID, start time, command,
+status (ok, failed, or unfinished: still running or killed), duration,
+and file size. Other files in the logs directory, such as the
+schedule's run history, are listed after them.
+
+Examples:
+  # Every run log
+  ado logs list
+
+  # Failed runs of ado meta system
+  ado logs list --command "meta system" --failed`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			if dir, err = logsDir(dir); err != nil {
+				return err
+			}
+			runs, err := logging.ListRunLogs(dir)
+			if err != nil {
+				return err
+			}
+			payload := listOutput{Dir: dir, Runs: []logging.RunInfo{}, OtherFiles: otherFiles(dir)}
+			for _, run := range runs {
+				if matchCommand(run, command) && (!failed || run.Status == logging.RunFailed) {
+					payload.Runs = append(payload.Runs, run)
+				}
+			}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatList(payload), nil
+			})
+		},
+	}
+
+	addDirFlag(cmd, &dir)
+	cmd.Flags().StringVar(&command, "command", "", `Only runs of this command and its subcommands, e.g. "meta"`)
+	cmd.Flags().BoolVar(&failed, "failed", false, "Only failed runs")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+// filter selects the records to show.
+type filter struct {
+	level   slog.Level
+	command string
+	since   time.Time
+}
+
+func (f filter) match(rec logging.Record) bool {
+	return rec.Level >= f.level && (f.since.IsZero() || !rec.Time.Before(f.since))
+}
+
+func newShowCommand() *cobra.Command {
+	var (
+		dir     string
+		level   string
+		command string
+		since   time.Duration
+		lines   int
+		follow  bool
+		ndjson  bool
+	)
+
+	cmd := &cobra.Command{
+		Use:     "show [RUN_ID...]",
+		Aliases: []string{"tail"},
+		Short:   "Print the records of run logs",
+		Long: `Print the records of the given runs, or of every run, oldest first.
+A RUN_ID may be abbreviated to a unique prefix or suffix, such as the
+eight characters shown before records of several runs.
+
+With --follow, keep printing records as they are written: of the given
+runs until each has finished, or of every run, including ones started
+later, until interrupted.
+
+Examples:
+  # One run
+  ado logs show 01JA2B3C4D5E6F7G8H9J0K1M2N
+
+  # Warnings and errors of the last day
+  ado logs show --level warn --since 24h
+
+  # The last 20 records, then follow new ones
+  ado logs tail -n 20 -f`,
+		ValidArgsFunction: completeRunIDs(&dir),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if lines < 0 {
+				return errors.New("--lines must not be negative")
+			}
+			if since < 0 {
+				return errors.New("--since must not be negative")
+			}
+			f := filter{command: command}
+			if err := f.level.UnmarshalText([]byte(level)); err != nil {
+				return fmt.Errorf("invalid --level %q: must be debug, info, warn, or error", level)
+			}
+			if since > 0 {
+				f.since = time.Now().Add(-since)
+			}
+			var err error
+			if dir, err = logsDir(dir); err != nil {
+				return err
+			}
+			runs, err := logging.ListRunLogs(dir)
+			if err != nil {
+				return err
+			}
+			if len(args) > 0 {
+				if runs, err = selectRuns(runs, args); err != nil {
+					return err
+				}
+			}
+			runs = slices.DeleteFunc(runs, func(run logging.RunInfo) bool { return !matchCommand(run, command) })
+			if len(runs) == 0 && !follow {
+				if command != "" {
+					return fmt.Errorf("no run logs of %q in %s", command, dir)
+				}
+				return fmt.Errorf("no run logs in %s", dir)
+			}
+
+			v := &viewer{
+				w:        cmd.OutOrStdout(),
+				dir:      dir,
+				filter:   f,
+				ndjson:   ndjson,
+				explicit: len(args) > 0,
+				offsets:  map[string]int64{},
+				prefix:   len(args) != 1,
+			}
+			if err := v.printTail(runs, lines); err != nil {
+				return err
+			}
+			if !follow {
+				return nil
+			}
+			return v.follow(cmd.Context(), runs)
+		},
+	}
+
+	addDirFlag(cmd, &dir)
+	cmd.Flags().StringVar(&level, "level", "debug", "Only records of this level or above: debug, info, warn, error")
+	cmd.Flags().StringVar(&command, "command", "", `Only runs of this command and its subcommands, e.g. "meta"`)
+	cmd.Flags().DurationVar(&since, "since", 0, "Only records written within this long, e.g. 24h")
+	cmd.Flags().IntVarP(&lines, "lines", "n", 0, "Print only the last N matching records (0 for all)")
+	cmd.Flags().BoolVarP(&follow, "follow", "f", false, "Keep printing records as they are written")
+	cmd.Flags().BoolVar(&ndjson, "ndjson", false, "Print records as stored, one JSON object per line")
+	_ = cmd.RegisterFlagCompletionFunc("level", completion.Fixed(completion.LogLevels...))
+	return cmd
+}
+
+func newPruneCommand() *cobra.Command {
+	var (
+		dir       string
+		olderThan time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove old run logs",
+		Long: `Remove the run logs last written longer ago than --older-than; 0
+removes them all. Other files in the logs directory are left alone.
+Runs with --save-run-log also prune, by --run-log-retain.
+
+Examples:
+  # Remove run logs older than a day
+  ado logs prune --older-than 24h
+
+  # See what would be removed
+  ado logs prune --older-than 0 --dry-run`,
+		Args:        cobra.NoArgs,
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if olderThan < 0 {
+				return errors.New("--older-than must not be negative")
+			}
+			var err error
+			if dir, err = logsDir(dir); err != nil {
+				return err
+			}
+			cutoff := time.Now().Add(-olderThan)
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				paths, err := logging.ExpiredRunLogs(dir, cutoff)
+				if err != nil {
+					return err
+				}
+				plan := ui.NewPlan()
+				for _, path := range paths {
+					plan.Add("delete", path, "")
+				}
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+			removed, err := logging.PruneRunLogs(dir, cutoff)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.OutOrStdout(), "Removed %d run %s from %s\n", removed, ui.Plural(removed, "log", "logs"), dir)
+			return err
+		},
+	}
+
+	addDirFlag(cmd, &dir)
+	cmd.Flags().DurationVar(&olderThan, "older-than", logging.DefaultRunLogRetention, "Remove run logs last written longer ago than this (0 for all)")
+	return cmd
+}
+
+func addDirFlag(cmd *cobra.Command, dir *string) {
+	cmd.Flags().StringVar(dir, "dir", "", "Logs directory (default: <state dir>/logs)")
+	_ = cmd.MarkFlagDirname("dir")
+}
+
+// logsDir returns dir, or ado's logs directory when it is empty.
+func logsDir(dir string) (string, error) {
+	if dir != "" {
+		return dir, nil
+	}
+	if dir = internalconfig.ResolveDirs().Log; dir == "" {
+		return "", errors.New("state directory: neither $XDG_STATE_HOME nor $HOME is defined")
+	}
+	return dir, nil
+}
+
+// matchCommand reports whether run is of command or one of its
+// subcommands; an empty command matches every run.
+func matchCommand(run logging.RunInfo, command string) bool {
+	command = strings.Join(strings.Fields(command), " ")
+	return command == "" || run.Command == command || strings.HasPrefix(run.Command, command+" ")
+}
+
+// selectRuns returns the runs named by ids, each a run ID or a unique
+// prefix or suffix of one, in the order given.
+func selectRuns(runs []logging.RunInfo, ids []string) ([]logging.RunInfo, error) {
+	var selected []logging.RunInfo
+	for _, id := range ids {
+		var matches []logging.RunInfo
+		for _, run := range runs {
+			if run.ID == id {
+				matches = []logging.RunInfo{run}
+				break
+			}
+			if strings.HasPrefix(run.ID, id) || strings.HasSuffix(run.ID, id) {
+				matches = append(matches, run)
+			}
+		}
+		switch len(matches) {
+		case 0:
+			return nil, fmt.Errorf("no run log with ID %q", id)
+		case 1:
+			if !slices.ContainsFunc(selected, func(r logging.RunInfo) bool { return r.ID == matches[0].ID }) {
+				selected = append(selected, matches[0])
+			}
+		default:
+			return nil, fmt.Errorf("run ID %q is ambiguous: matches %d runs", id, len(matches))
+		}
+	}
+	return selected, nil
+}
+
+// viewer prints the records of run logs and follows them.
+type viewer struct {
+	w      io.Writer
+	dir    string
+	filter filter
+	ndjson bool
+	// explicit is set when runs were named, so follow does not pick up
+	// new ones.
+	explicit bool
+	// prefix marks each record with its short run ID.
+	prefix bool
+	// offsets is how far each run log has been read.
+	offsets map[string]int64
+}
+
+// printTail prints the matching records of runs, only the last n unless n
+// is 0.
+func (v *viewer) printTail(runs []logging.RunInfo, n int) error {
+	var records []logging.Record
+	for _, run := range runs {
+		offset, err := logging.ReadRecords(run.Path, 0, func(rec logging.Record) error {
+			if v.filter.match(rec) {
+				records = append(records, rec)
+				if n > 0 && len(records) > n {
+					records = records[1:]
+				}
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		v.offsets[run.Path] = offset
+	}
+	for _, rec := range records {
+		if err := v.print(rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// follow prints records as they are appended to runs and, unless runs
+// were named, to run logs created later. It returns when ctx is done, or
+// once every named run has finished.
+func (v *viewer) follow(ctx context.Context, runs []logging.RunInfo) error {
+	paths := make([]string, 0, len(runs))
+	finished := map[string]bool{}
+	for _, run := range runs {
+		paths = append(paths, run.Path)
+		finished[run.Path] = run.Status != logging.RunUnfinished
+	}
+	ticker := time.NewTicker(followInterval)
+	defer ticker.Stop()
+	for {
+		if v.explicit && !slices.ContainsFunc(paths, func(p string) bool { return !finished[p] }) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		if !v.explicit {
+			latest, err := logging.ListRunLogs(v.dir)
+			if err != nil {
+				return err
+			}
+			for _, run := range latest {
+				if _, seen := v.offsets[run.Path]; !seen && matchCommand(run, v.filter.command) {
+					v.offsets[run.Path] = 0
+					paths = append(paths, run.Path)
+				}
+			}
+		}
+		for _, path := range paths {
+			offset, err := logging.ReadRecords(path, v.offsets[path], func(rec logging.Record) error {
+				if rec.Msg == logging.MsgRunFinished {
+					finished[path] = true
+				}
+				if !v.filter.match(rec) {
+					return nil
+				}
+				return v.print(rec)
+			})
+			if errors.Is(err, os.ErrNotExist) {
+				// Pruned while followed.
+				finished[path] = true
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			v.offsets[path] = offset
+		}
+	}
+}
+
+func (v *viewer) print(rec logging.Record) error {
+	if v.ndjson {
+		_, err := fmt.Fprintf(v.w, "%s\n", rec.Line)
+		return err
+	}
+	_, err := fmt.Fprintln(v.w, formatRecord(rec, v.prefix))
+	return err
+}
+
+// formatRecord renders a record like slog's text handler, on one line:
+// time, level, message, then KEY=VALUE fields, after the short run ID
+// when prefix is set.
+func formatRecord(rec logging.Record, prefix bool) string {
+	var b strings.Builder
+	b.WriteString(rec.Time.Format("2006-01-02 15:04:05.000"))
+	fmt.Fprintf(&b, " %-5s ", rec.Level)
+	if prefix && rec.RunID != "" {
+		fmt.Fprintf(&b, "[%s] ", rec.RunID[max(0, len(rec.RunID)-shortIDLen):])
+	}
+	b.WriteString(rec.Msg)
+	for _, a := range rec.Attrs {
+		fmt.Fprintf(&b, " %s=%s", a.Key, formatValue(a.Value))
+	}
+	return b.String()
+}
+
+// formatValue renders a JSON value: strings bare unless they need
+// quoting, anything else as written.
+func formatValue(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) != nil {
+		return string(raw)
+	}
+	if s == "" || strings.ContainsFunc(s, func(r rune) bool { return unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r) }) {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func formatList(out listOutput) string {
+	var b strings.Builder
+	if len(out.Runs) == 0 {
+		fmt.Fprintf(&b, "No run logs in %s. Write them with --save-run-log, or for every run with defaults.save-run-log: true in the config file.\n", out.Dir)
+	} else {
+		cmdWidth := len("COMMAND")
+		for _, run := range out.Runs {
+			cmdWidth = max(cmdWidth, len(run.Command))
+		}
+		fmt.Fprintf(&b, "%-26s  %-19s  %-*s  %-10s  %8s  %s\n", "RUN ID", "STARTED", cmdWidth, "COMMAND", "STATUS", "DURATION", "SIZE")
+		for _, run := range out.Runs {
+			status, duration := run.Status, ""
+			if run.Status == logging.RunFailed && run.ExitCode != nil {
+				status = fmt.Sprintf("failed (%d)", *run.ExitCode)
+			}
+			if run.Status != logging.RunUnfinished {
+				duration = (time.Duration(run.DurationMS) * time.Millisecond).String()
+			}
+			fmt.Fprintf(&b, "%-26s  %-19s  %-*s  %-10s  %8s  %s\n", run.ID, run.Started.Format(time.DateTime), cmdWidth, run.Command, status, duration, ui.FormatSize(run.SizeBytes))
+		}
+	}
+	if len(out.OtherFiles) > 0 {
+		fmt.Fprintf(&b, "\nOther files in %s:\n", out.Dir)
+		for _, f := range out.OtherFiles {
+			fmt.Fprintf(&b, "  %s (%s)\n", f.Name, ui.FormatSize(f.SizeBytes))
+		}
+	}
+	return b.String()
+}
+
+// otherFiles lists the regular files in dir that are not run logs.
+func otherFiles(dir string) []otherFile {
+	files := []otherFile{}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return files
+	}
+	for _, entry := range entries {
+		if _, _, ok := logging.ParseRunLogName(entry.Name()); ok || !entry.Type().IsRegular() {
+			continue
+		}
+		if info, err := entry.Info(); err == nil {
+			files = append(files, otherFile{Name: entry.Name(), SizeBytes: info.Size()})
+		}
+	}
+	return files
+}
+
+// completeRunIDs completes the IDs of the run logs in *dir, newest first.
+func completeRunIDs(dir *string) func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+	return func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+		d, err := logsDir(*dir)
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		runs, _ := logging.ListRunLogs(d)
+		var ids []string
+		for _, run := range slices.Backward(runs) {
+			if !slices.Contains(args, run.ID) {
+				ids = append(ids, run.ID+"\t"+run.Command+", "+run.Started.Format(time.DateTime))
+			}
+		}
+		return ids, cobra.ShellCompDirectiveNoFileComp
+	}
+}
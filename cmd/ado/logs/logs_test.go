@@ -0,0 +1,216 @@
+package logs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/ui"
+	"github.com/anowarislam/ado/internal/ui/uitest"
+)
+
+func execute(ctx context.Context, args ...string) (string, error) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().Bool(ui.DryRunFlag, false, "")
+	root.AddCommand(NewCommand())
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"logs"}, args...))
+	err := root.ExecuteContext(ctx)
+	return out.String(), err
+}
+
+const (
+	okID     = "01JA2B3C4D5E6F7G8H9J0KAAAA"
+	failedID = "01JA2B3C4D5E6F7G8H9J0KBBBB"
+)
+
+// writeLogs writes a finished and a failed run log and the schedule's run
+// history to a new logs directory.
+func writeLogs(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	files := map[string][]string{
+		"2025-10-16-" + okID + ".log": {
+			`{"time":"2025-10-16T14:00:00Z","level":"INFO","msg":"Run started","run_id":"` + okID + `","command":"meta system","args":["ado","meta","system"]}`,
+			`{"time":"2025-10-16T14:00:00.25Z","level":"DEBUG","msg":"Detected GPU","run_id":"` + okID + `","vendor":"NVIDIA","model":"RTX 4090"}`,
+			`{"time":"2025-10-16T14:00:01Z","level":"INFO","msg":"Run finished","run_id":"` + okID + `","command":"meta system","duration_ms":1000,"exit_code":0}`,
+		},
+		"2025-10-16-" + failedID + ".log": {
+			`{"time":"2025-10-16T15:00:00Z","level":"INFO","msg":"Run started","run_id":"` + failedID + `","command":"check run","args":["ado","check","run","checks.yaml"]}`,
+			`{"time":"2025-10-16T15:00:02Z","level":"ERROR","msg":"Run finished","run_id":"` + failedID + `","command":"check run","duration_ms":2000,"exit_code":1,"error":"1 of 2 checks failed"}`,
+		},
+		"schedule.jsonl": {`{"schedule":"backup"}`},
+	}
+	for name, lines := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLogsList(t *testing.T) {
+	dir := writeLogs(t)
+	out, err := execute(context.Background(), "list", "--dir", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uitest.Golden(t, "list", strings.ReplaceAll(out, dir, "/state/ado/logs"))
+
+	out, err = execute(context.Background(), "list", "--dir", dir, "--failed", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got listOutput
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	if len(got.Runs) != 1 || got.Runs[0].ID != failedID || got.Runs[0].Error != "1 of 2 checks failed" || len(got.OtherFiles) != 1 {
+		t.Errorf("list = %+v", got)
+	}
+
+	out, err = execute(context.Background(), "list", "--dir", filepath.Join(dir, "missing"))
+	if err != nil || !strings.HasPrefix(out, "No run logs in ") {
+		t.Errorf("empty list = %q, %v", out, err)
+	}
+}
+
+func TestLogsShow(t *testing.T) {
+	dir := writeLogs(t)
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"one run", []string{okID}, []string{
+			"2025-10-16 14:00:00.000 INFO  Run started command=\"meta system\" args=[\"ado\",\"meta\",\"system\"]",
+			"2025-10-16 14:00:00.250 DEBUG Detected GPU vendor=NVIDIA model=\"RTX 4090\"",
+			"2025-10-16 14:00:01.000 INFO  Run finished command=\"meta system\" duration_ms=1000 exit_code=0",
+		}},
+		{"every run", []string{"--level", "error"}, []string{
+			"2025-10-16 15:00:02.000 ERROR [9J0KBBBB] Run finished command=\"check run\" duration_ms=2000 exit_code=1 error=\"1 of 2 checks failed\"",
+		}},
+		{"suffix", []string{"BBBB", "-n", "1"}, []string{
+			"2025-10-16 15:00:02.000 ERROR Run finished command=\"check run\" duration_ms=2000 exit_code=1 error=\"1 of 2 checks failed\"",
+		}},
+		{"command", []string{"--command", "meta", "--level", "info", "-n", "1"}, []string{
+			"2025-10-16 14:00:01.000 INFO  [9J0KAAAA] Run finished command=\"meta system\" duration_ms=1000 exit_code=0",
+		}},
+		{"since", []string{"--since", "1h"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := execute(context.Background(), append([]string{"show", "--dir", dir}, tt.args...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Split(strings.TrimSuffix(out, "\n"), "\n"); out != "" || len(tt.want) > 0 {
+				if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+					t.Errorf("output:\n%s\nwant:\n%s", out, strings.Join(tt.want, "\n"))
+				}
+			}
+		})
+	}
+
+	out, err := execute(context.Background(), "tail", "--dir", dir, "--ndjson", "-n", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, `{"time":"2025-10-16T15:00:02Z","level":"ERROR"`) || strings.Count(out, "\n") != 1 {
+		t.Errorf("ndjson = %q", out)
+	}
+}
+
+func TestLogsShow_Errors(t *testing.T) {
+	dir := writeLogs(t)
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"ZZZZ"}, `no run log with ID "ZZZZ"`},
+		{[]string{"01JA"}, `run ID "01JA" is ambiguous: matches 2 runs`},
+		{[]string{"--level", "loud"}, `invalid --level "loud"`},
+		{[]string{"--command", "top"}, `no run logs of "top"`},
+		{[]string{"-n", "-1"}, "--lines must not be negative"},
+	}
+	for _, tt := range tests {
+		if _, err := execute(context.Background(), append([]string{"show", "--dir", dir}, tt.args...)...); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%v: error = %v, want %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLogsShow_Follow(t *testing.T) {
+	dir := writeLogs(t)
+	// A finished run has nothing more to follow.
+	if _, err := execute(context.Background(), "show", "--dir", dir, okID, "-f"); err != nil {
+		t.Fatal(err)
+	}
+
+	runningID := "01JA2B3C4D5E6F7G8H9J0KCCCC"
+	path := filepath.Join(dir, "2025-10-16-"+runningID+".log")
+	started := `{"time":"2025-10-16T16:00:00Z","level":"INFO","msg":"Run started","run_id":"` + runningID + `","command":"agent run"}` + "\n"
+	if err := os.WriteFile(path, []byte(started), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		time.Sleep(2 * followInterval)
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			return
+		}
+		defer f.Close()
+		f.WriteString(`{"time":"2025-10-16T16:00:05Z","level":"INFO","msg":"Run finished","run_id":"` + runningID + `","command":"agent run","duration_ms":5000,"exit_code":0}` + "\n")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	out, err := execute(ctx, "show", "--dir", dir, "CCCC", "-f")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ctx.Err() != nil || !strings.Contains(out, "Run started") || !strings.Contains(out, "Run finished") {
+		t.Errorf("follow returned %q after %v", out, ctx.Err())
+	}
+}
+
+func TestLogsPrune(t *testing.T) {
+	dir := writeLogs(t)
+	old := filepath.Join(dir, "2025-10-16-"+okID+".log")
+	past := time.Now().Add(-30 * 24 * time.Hour)
+	if err := os.Chtimes(old, past, past); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := execute(context.Background(), "prune", "--dir", dir, "--dry-run")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "Would delete " + old + "\nDry run: nothing was changed.\n"; out != want {
+		t.Errorf("dry run = %q, want %q", out, want)
+	}
+
+	out, err = execute(context.Background(), "prune", "--dir", dir)
+	if err != nil || out != "Removed 1 run log from "+dir+"\n" {
+		t.Errorf("prune = %q, %v", out, err)
+	}
+	out, err = execute(context.Background(), "prune", "--dir", dir, "--older-than", "0")
+	if err != nil || out != "Removed 1 run log from "+dir+"\n" {
+		t.Errorf("prune all = %q, %v", out, err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "schedule.jsonl")); err != nil {
+		t.Errorf("other file removed: %v", err)
+	}
+	if _, err := execute(context.Background(), "prune", "--dir", dir, "--older-than", "-1h"); err == nil {
+		t.Error("negative --older-than accepted")
+	}
+}
@@ -0,0 +1,98 @@
+package mcp
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/logging"
+	internalmcp "github.com/anowarislam/ado/internal/mcp"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/secrets"
+)
+
+// NewCommand returns the mcp parent command with subcommands.
+func NewCommand(buildInfo internalmeta.BuildInfo) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "mcp",
+		Short: "Expose ado to AI assistants over the Model Context Protocol",
+		Long: `Expose ado capabilities as Model Context Protocol (MCP) tools, so AI
+coding assistants can query host diagnostics and run configured tasks
+through a controlled interface.`,
+	}
+
+	cmd.AddCommand(newServeCommand(buildInfo))
+
+	return cmd
+}
+
+func newServeCommand(buildInfo internalmeta.BuildInfo) *cobra.Command {
+	var (
+		readOnly   bool
+		allowTasks []string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve MCP tools over stdio",
+		Long: `Serve MCP over stdin and stdout. The assistant starts ado as a subprocess
+and exchanges newline-delimited JSON-RPC messages with it; logs go to
+stderr.
+
+Tools:
+  system_info       system diagnostics (ado meta system)
+  validate_config   validate the config file, or YAML passed as content
+  list_tasks        tasks defined in the config file
+  run_task          run a configured task and return its output and exit code
+
+Only tasks from the config file can be run; the config is re-read on every
+call. Restrict them further with --allow-task, or drop list_tasks and
+run_task entirely with --read-only.
+
+Examples:
+  # Register with an assistant that launches MCP servers by command, e.g.
+  # {"command": "ado", "args": ["mcp", "serve"]}
+  ado mcp serve
+
+  # Diagnostics and validation only
+  ado mcp serve --read-only
+
+  # Allow only the test and lint tasks
+  ado mcp serve --allow-task test --allow-task lint`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ctx := cmd.Context()
+
+			configPath, _ := cmd.Root().PersistentFlags().GetString("config")
+			homeDir, _ := os.UserHomeDir()
+			// Reread the config on each call, expanded like this command's.
+			expansion := internalconfig.PathResolverFor(cmd.Context(), configPath, homeDir).Expansion()
+			t := &tools{
+				configPath: configPath,
+				homeDir:    homeDir,
+				loadConfig: func() (*internalconfig.Config, string, error) {
+					return internalconfig.NewPathResolver(configPath, homeDir).SetExpansion(expansion).Load()
+				},
+				secrets:    secrets.Default(),
+				allowTasks: allowTasks,
+			}
+
+			log := logging.FromContext(ctx)
+			s := &internalmcp.Server{
+				Name:    "ado",
+				Version: buildInfo.Version,
+				Tools:   t.list(readOnly),
+				Logger:  log,
+			}
+			log.Debug("Serving MCP over stdio", "read_only", readOnly, "allow_task", allowTasks)
+			return s.Serve(ctx, cmd.InOrStdin(), cmd.OutOrStdout())
+		},
+	}
+
+	cmd.Flags().BoolVar(&readOnly, "read-only", false, "Expose only diagnostics and validation, not tasks")
+	cmd.Flags().StringArrayVar(&allowTasks, "allow-task", nil, "Task that run_task may run (repeatable; default: all configured tasks)")
+	_ = cmd.RegisterFlagCompletionFunc("allow-task", completion.TaskNames)
+	return cmd
+}
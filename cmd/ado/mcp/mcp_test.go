@@ -0,0 +1,246 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/secrets"
+)
+
+func testTools(t *testing.T, defined map[string]internalconfig.Task) *tools {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	return &tools{
+		configPath: path,
+		loadConfig: func() (*internalconfig.Config, string, error) {
+			return &internalconfig.Config{Version: 1, Tasks: defined}, path, nil
+		},
+		secrets: secrets.Map{"greeting": "hello"},
+		collectSystem: func(_ context.Context, opts internalmeta.SystemOptions) internalmeta.SystemInfo {
+			info := internalmeta.SystemInfo{OS: "testos"}
+			if opts.SkipNetwork {
+				info.Platform = "nonet"
+			}
+			return info
+		},
+	}
+}
+
+func TestTools_List(t *testing.T) {
+	tl := testTools(t, nil)
+	names := func(readOnly bool) string {
+		var out []string
+		for _, tool := range tl.list(readOnly) {
+			out = append(out, tool.Name)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := names(false); got != "system_info,validate_config,list_tasks,run_task" {
+		t.Errorf("tools = %s", got)
+	}
+	if got := names(true); got != "system_info,validate_config" {
+		t.Errorf("read-only tools = %s", got)
+	}
+}
+
+func TestTools_SystemInfo(t *testing.T) {
+	res, err := testTools(t, nil).systemInfo(context.Background(), json.RawMessage(`{"no_network": true}`))
+	if err != nil {
+		t.Fatalf("systemInfo() error = %v", err)
+	}
+	if !strings.Contains(res.Text, `"os": "testos"`) || !strings.Contains(res.Text, `"platform": "nonet"`) {
+		t.Errorf("systemInfo() = %s", res.Text)
+	}
+
+	if _, err := testTools(t, nil).systemInfo(context.Background(), json.RawMessage(`{"bogus": 1}`)); err == nil {
+		t.Error("systemInfo() with unknown argument: want error")
+	}
+}
+
+func TestTools_ValidateConfig(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "valid.yaml")
+	if err := os.WriteFile(valid, []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		args        string
+		wantIsError bool
+		wantText    string
+	}{
+		{"valid file", `{"path": "` + valid + `"}`, false, `"valid": true`},
+		{"missing file", `{"path": "` + filepath.Join(dir, "nope.yaml") + `"}`, true, "config file not found"},
+		{"content", `{"content": "version: 1\n"}`, false, `"path": "content"`},
+		{"invalid content", `{"content": "version: ["}`, true, `"valid": false`},
+		{"default path", `{}`, true, "config file not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := testTools(t, nil).validateConfig(context.Background(), json.RawMessage(tt.args))
+			if err != nil {
+				t.Fatalf("validateConfig() error = %v", err)
+			}
+			if res.IsError != tt.wantIsError || !strings.Contains(res.Text, tt.wantText) {
+				t.Errorf("validateConfig() = %+v, want isError %v and %q", res, tt.wantIsError, tt.wantText)
+			}
+		})
+	}
+}
+
+func TestTools_ListTasks(t *testing.T) {
+	tl := testTools(t, map[string]internalconfig.Task{
+		"test": {Command: "go", Args: []string{"test"}},
+		"lint": {Command: "golangci-lint"},
+	})
+	tl.allowTasks = []string{"test"}
+
+	res, err := tl.listTasks(context.Background(), json.RawMessage(`{}`))
+	if err != nil {
+		t.Fatalf("listTasks() error = %v", err)
+	}
+	if !strings.Contains(res.Text, `"name": "test"`) || strings.Contains(res.Text, "lint") {
+		t.Errorf("listTasks() = %s, want only test", res.Text)
+	}
+}
+
+func TestTools_RunTask(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("requires /bin/sh")
+	}
+	defined := map[string]internalconfig.Task{
+		"greet": {Command: "sh", Args: []string{"-c", `echo "$1 $0"; echo warn >&2`, "secret://greeting"}},
+		"fail":  {Command: "sh", Args: []string{"-c", "exit 4"}},
+		"cat":   {Command: "cat"},
+		"empty": {},
+	}
+
+	tests := []struct {
+		name        string
+		allow       []string
+		args        string
+		want        taskResult
+		wantIsError bool
+		wantErr     string
+	}{
+		{
+			name: "output",
+			args: `{"name": "greet", "args": ["world"]}`,
+			want: taskResult{Task: "greet", Stdout: "world hello\n", Stderr: "warn\n"},
+		},
+		{
+			name:        "exit code",
+			args:        `{"name": "fail"}`,
+			want:        taskResult{Task: "fail", ExitCode: 4},
+			wantIsError: true,
+		},
+		{
+			name: "stdin is empty",
+			args: `{"name": "cat"}`,
+			want: taskResult{Task: "cat"},
+		},
+		{name: "missing name", args: `{}`, wantErr: "name is required"},
+		{name: "unknown task", args: `{"name": "nope"}`, wantErr: `unknown task "nope" (available: cat, empty, fail, greet)`},
+		{name: "not allowed", allow: []string{"greet"}, args: `{"name": "fail"}`, wantErr: `unknown task "fail" (available: greet)`},
+		{name: "no command", args: `{"name": "empty"}`, wantErr: "has no command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tl := testTools(t, defined)
+			tl.allowTasks = tt.allow
+			res, err := tl.runTask(context.Background(), json.RawMessage(tt.args))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("runTask() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("runTask() error = %v", err)
+			}
+			var got taskResult
+			if err := json.Unmarshal([]byte(res.Text), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || res.IsError != tt.wantIsError {
+				t.Errorf("runTask() = %+v (isError %v), want %+v (isError %v)", got, res.IsError, tt.want, tt.wantIsError)
+			}
+		})
+	}
+}
+
+func TestCappedBuffer(t *testing.T) {
+	b := &cappedBuffer{max: 5}
+	for _, s := range []string{"abc", "def", "ghi"} {
+		if n, err := b.Write([]byte(s)); n != len(s) || err != nil {
+			t.Fatalf("Write(%q) = %d, %v", s, n, err)
+		}
+	}
+	if b.String() != "abcde" || !b.truncated {
+		t.Errorf("buffer = %q (truncated %v), want abcde (truncated)", b.String(), b.truncated)
+	}
+}
+
+func TestServe_Stdio(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := NewCommand(internalmeta.BuildInfo{Version: "1.2.3"})
+	cmd.PersistentFlags().String("config", configPath, "")
+	var out bytes.Buffer
+	cmd.SetIn(strings.NewReader(strings.Join([]string{
+		`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18"}}`,
+		`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+		`{"jsonrpc":"2.0","id":2,"method":"tools/list"}`,
+		`{"jsonrpc":"2.0","id":3,"method":"tools/call","params":{"name":"validate_config","arguments":{}}}`,
+	}, "\n")))
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs([]string{"serve", "--read-only"})
+	if err := cmd.ExecuteContext(context.Background()); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d responses, want 3:\n%s", len(lines), out.String())
+	}
+	if strings.Contains(lines[1], "run_task") {
+		t.Errorf("--read-only still lists run_task: %s", lines[1])
+	}
+	var call struct {
+		Result struct {
+			Content []struct{ Text string }
+			IsError bool
+		}
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &call); err != nil {
+		t.Fatal(err)
+	}
+	if call.Result.IsError || !strings.Contains(call.Result.Content[0].Text, configPath) {
+		t.Errorf("validate_config = %+v, want valid result for %s", call.Result, configPath)
+	}
+}
+
+func TestRunnable_LoadError(t *testing.T) {
+	tl := &tools{loadConfig: func() (*internalconfig.Config, string, error) {
+		return nil, "", errors.New("broken")
+	}}
+	if _, err := tl.runTask(context.Background(), json.RawMessage(`{"name": "x"}`)); err == nil || !strings.Contains(err.Error(), "load config: broken") {
+		t.Errorf("runTask() error = %v, want load config error", err)
+	}
+}
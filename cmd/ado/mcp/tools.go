@@ -0,0 +1,276 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	internalmcp "github.com/anowarislam/ado/internal/mcp"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/secrets"
+	"github.com/anowarislam/ado/internal/tasks"
+)
+
+// maxTaskOutput bounds each of stdout and stderr returned by run_task.
+const maxTaskOutput = 256 << 10
+
+// tools builds the MCP tools ado exposes.
+type tools struct {
+	configPath string
+	homeDir    string
+	loadConfig func() (*internalconfig.Config, string, error)
+	secrets    secrets.Store
+	// allowTasks restricts run_task; empty allows every configured task.
+	allowTasks []string
+	// collectSystem defaults to internalmeta.CollectSystemInfo.
+	collectSystem func(context.Context, internalmeta.SystemOptions) internalmeta.SystemInfo
+}
+
+func (t *tools) list(readOnly bool) []internalmcp.Tool {
+	list := []internalmcp.Tool{
+		{
+			Name:        "system_info",
+			Description: "Collect host diagnostics: OS, CPU, memory, storage, GPUs, network interfaces, and optionally security posture. Same data as `ado meta system -o json`.",
+			InputSchema: objectSchema(map[string]any{
+				"no_network": map[string]any{"type": "boolean", "description": "Skip network interface collection"},
+				"security":   map[string]any{"type": "boolean", "description": "Include firewall, disk encryption, and similar checks"},
+			}),
+			Call: t.systemInfo,
+		},
+		{
+			Name:        "validate_config",
+			Description: "Validate an ado config file and report errors and warnings with line numbers. Validates the given YAML content, the file at path, or the active config file.",
+			InputSchema: objectSchema(map[string]any{
+				"path":    map[string]any{"type": "string", "description": "Config file to validate"},
+				"content": map[string]any{"type": "string", "description": "YAML config to validate instead of a file"},
+			}),
+			Call: t.validateConfig,
+		},
+	}
+	if readOnly {
+		return list
+	}
+	return append(list,
+		internalmcp.Tool{
+			Name:        "list_tasks",
+			Description: "List the tasks run_task can run, from the tasks: section of the ado config file.",
+			InputSchema: objectSchema(map[string]any{}),
+			Call:        t.listTasks,
+		},
+		internalmcp.Tool{
+			Name:        "run_task",
+			Description: "Run a task defined in the ado config file and return its exit code, stdout, and stderr. Only configured tasks can be run.",
+			InputSchema: objectSchema(map[string]any{
+				"name": map[string]any{"type": "string", "description": "Task name"},
+				"args": map[string]any{
+					"type":        "array",
+					"items":       map[string]any{"type": "string"},
+					"description": "Extra arguments appended to the task's configured args",
+				},
+			}, "name"),
+			Call: t.runTask,
+		},
+	)
+}
+
+func objectSchema(properties map[string]any, required ...string) map[string]any {
+	schema := map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func decodeArgs(args json.RawMessage, v any) error {
+	dec := json.NewDecoder(bytes.NewReader(args))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return fmt.Errorf("invalid arguments: %w", err)
+	}
+	return nil
+}
+
+func (t *tools) systemInfo(ctx context.Context, args json.RawMessage) (*internalmcp.Result, error) {
+	var in struct {
+		NoNetwork bool `json:"no_network"`
+		Security  bool `json:"security"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	collect := t.collectSystem
+	if collect == nil {
+		collect = internalmeta.CollectSystemInfo
+	}
+	return internalmcp.JSONResult(collect(ctx, internalmeta.SystemOptions{
+		SkipNetwork:     in.NoNetwork,
+		IncludeSecurity: in.Security,
+	}))
+}
+
+func (t *tools) validateConfig(_ context.Context, args json.RawMessage) (*internalmcp.Result, error) {
+	var in struct {
+		Path    string  `json:"path"`
+		Content *string `json:"content"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+
+	var result *internalconfig.ValidationResult
+	if in.Content != nil {
+		result = internalconfig.ValidateData("content", []byte(*in.Content))
+	} else {
+		path := in.Path
+		if path == "" {
+			path = t.configPath
+		}
+		if path == "" {
+			resolved, sources := internalconfig.ResolveConfigPath("", t.homeDir)
+			if resolved == "" {
+				return nil, fmt.Errorf("no config file found. Searched: %s", strings.Join(sources, ", "))
+			}
+			path = resolved
+		}
+		var err error
+		if result, err = internalconfig.Validate(path); err != nil {
+			return nil, fmt.Errorf("validation failed: %w", err)
+		}
+	}
+
+	res, err := internalmcp.JSONResult(result)
+	if err != nil {
+		return nil, err
+	}
+	res.IsError = !result.Valid
+	return res, nil
+}
+
+// runnable returns the configured tasks that run_task may run.
+func (t *tools) runnable() (map[string]internalconfig.Task, string, error) {
+	cfg, path, err := t.loadConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("load config: %w", err)
+	}
+	if len(t.allowTasks) == 0 {
+		return cfg.Tasks, path, nil
+	}
+	allowed := make(map[string]internalconfig.Task, len(t.allowTasks))
+	for name, task := range cfg.Tasks {
+		if slices.Contains(t.allowTasks, name) {
+			allowed[name] = task
+		}
+	}
+	return allowed, path, nil
+}
+
+func (t *tools) listTasks(_ context.Context, args json.RawMessage) (*internalmcp.Result, error) {
+	if err := decodeArgs(args, &struct{}{}); err != nil {
+		return nil, err
+	}
+	defined, _, err := t.runnable()
+	if err != nil {
+		return nil, err
+	}
+	return internalmcp.JSONResult(map[string][]tasks.Summary{"tasks": tasks.List(defined)})
+}
+
+// taskResult is the run_task result document.
+type taskResult struct {
+	Task      string `json:"task"`
+	ExitCode  int    `json:"exit_code"`
+	Stdout    string `json:"stdout"`
+	Stderr    string `json:"stderr"`
+	Truncated bool   `json:"truncated,omitempty"`
+}
+
+func (t *tools) runTask(ctx context.Context, args json.RawMessage) (*internalmcp.Result, error) {
+	var in struct {
+		Name string   `json:"name"`
+		Args []string `json:"args"`
+	}
+	if err := decodeArgs(args, &in); err != nil {
+		return nil, err
+	}
+	if in.Name == "" {
+		return nil, errors.New("invalid arguments: name is required")
+	}
+
+	defined, path, err := t.runnable()
+	if err != nil {
+		return nil, err
+	}
+	task, ok := defined[in.Name]
+	if !ok {
+		names := make([]string, 0, len(defined))
+		for _, s := range tasks.List(defined) {
+			names = append(names, s.Name)
+		}
+		return nil, fmt.Errorf("unknown task %q (available: %s)", in.Name, strings.Join(names, ", "))
+	}
+
+	stdout := &cappedBuffer{max: maxTaskOutput}
+	stderr := &cappedBuffer{max: maxTaskOutput}
+	// Stdin stays nil: stdin carries the MCP stream, not task input.
+	runner := tasks.Runner{
+		Stdout:  stdout,
+		Stderr:  stderr,
+		Secrets: t.secrets,
+	}
+	if path != "" {
+		runner.BaseDir = filepath.Dir(path)
+	}
+
+	err = runner.Run(ctx, in.Name, task, in.Args)
+	result := taskResult{
+		Task:      in.Name,
+		Stdout:    stdout.String(),
+		Stderr:    stderr.String(),
+		Truncated: stdout.truncated || stderr.truncated,
+	}
+	var exitErr *tasks.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		result.ExitCode = exitErr.Code
+	case err != nil:
+		return nil, err
+	}
+
+	res, err := internalmcp.JSONResult(result)
+	if err != nil {
+		return nil, err
+	}
+	res.IsError = result.ExitCode != 0
+	return res, nil
+}
+
+// cappedBuffer keeps the first max bytes written and drops the rest, so a
+// chatty task cannot flood the client.
+type cappedBuffer struct {
+	buf       bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.buf.Len(); len(p) > room {
+		b.buf.Write(p[:max(room, 0)])
+		b.truncated = true
+		return len(p), nil
+	}
+	return b.buf.Write(p)
+}
+
+func (b *cappedBuffer) String() string {
+	return b.buf.String()
+}
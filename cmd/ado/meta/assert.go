@@ -0,0 +1,118 @@
+package meta
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/junit"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/tap"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// parseAssertions parses the --assert values.
+func parseAssertions(sources []string) ([]internalmeta.Assertion, error) {
+	assertions := make([]internalmeta.Assertion, 0, len(sources))
+	for _, src := range sources {
+		a, err := internalmeta.ParseAssertion(src)
+		if err != nil {
+			return nil, err
+		}
+		assertions = append(assertions, a)
+	}
+	return assertions, nil
+}
+
+// assertionSections lists the sections assertions refer to, in report
+// order, so the others need not be probed. Assertions on the host
+// identity alone still collect a section, as no sections means all.
+func assertionSections(assertions []internalmeta.Assertion) []string {
+	var names []string
+	for _, a := range assertions {
+		names = append(names, a.Sections()...)
+	}
+	if len(names) == 0 {
+		return []string{internalmeta.SectionCPU}
+	}
+	sections, _ := internalmeta.ParseSections(names)
+	return sections
+}
+
+func formatAssertionReport(r internalmeta.AssertionReport) string {
+	width := 0
+	for _, res := range r.Results {
+		width = max(width, len(res.Assertion))
+	}
+
+	var b strings.Builder
+	for _, res := range r.Results {
+		status := "PASS"
+		if !res.Passed {
+			status = "FAIL"
+		}
+		fmt.Fprintf(&b, "%s  %-*s  %s\n", status, width, res.Assertion, describeAssertionResult(res))
+	}
+	if failed := r.Failed(); failed > 0 {
+		fmt.Fprintf(&b, "\n%d of %d checks failed\n", failed, len(r.Results))
+	} else {
+		fmt.Fprintf(&b, "\nAll %d checks passed\n", len(r.Results))
+	}
+	return b.String()
+}
+
+// describeAssertionResult gives the value compared, or why none was.
+func describeAssertionResult(res internalmeta.AssertionResult) string {
+	if res.Error != "" {
+		return res.Error
+	}
+	return res.Field + " = " + internalmeta.FormatAssertValue(res.Actual)
+}
+
+// assertionSuite reports r as a JUnit suite with a case per result.
+func assertionSuite(r internalmeta.AssertionReport) junit.TestSuite {
+	cases := make([]junit.TestCase, len(r.Results))
+	for i, res := range r.Results {
+		cases[i] = junit.TestCase{Name: res.Assertion, Classname: res.Field}
+		if !res.Passed {
+			cases[i].Failure = &junit.Failure{Message: describeAssertionResult(res)}
+		}
+	}
+	return junit.NewSuite("ado meta system", cases...)
+}
+
+// assertionTests reports r as TAP test points.
+func assertionTests(r internalmeta.AssertionReport) []tap.Test {
+	tests := make([]tap.Test, len(r.Results))
+	for i, res := range r.Results {
+		tests[i] = tap.Test{OK: res.Passed, Description: res.Assertion}
+		if !res.Passed {
+			tests[i].Diagnostics = map[string]any{"field": res.Field, "message": describeAssertionResult(res)}
+		}
+	}
+	return tests
+}
+
+// reportAssertions writes r in format and fails the command if any check
+// failed.
+func reportAssertions(cmd *cobra.Command, format ui.OutputFormat, r internalmeta.AssertionReport) error {
+	var err error
+	switch format {
+	case ui.OutputJUnit:
+		err = junit.Write(cmd.OutOrStdout(), "ado meta system", assertionSuite(r))
+	case ui.OutputTAP:
+		err = tap.Write(cmd.OutOrStdout(), assertionTests(r))
+	default:
+		err = ui.PrintOutput(cmd.OutOrStdout(), format, r, func() (string, error) {
+			return formatAssertionReport(r), nil
+		})
+	}
+	if err != nil {
+		return err
+	}
+	if !r.Passed {
+		return ui.Reported(fmt.Errorf("%d of %d checks failed", r.Failed(), len(r.Results)))
+	}
+	return nil
+}
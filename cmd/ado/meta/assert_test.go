@@ -0,0 +1,112 @@
+package meta
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/ui"
+	"github.com/anowarislam/ado/internal/ui/uitest"
+)
+
+func TestFormatAssertionReport(t *testing.T) {
+	var assertions []internalmeta.Assertion
+	for _, src := range []string{
+		"memory.used_percent < 90",
+		`storage["/"].free_mb > 10240`,
+		"storage[*].used_percent < 20",
+		"cloud.provider == aws",
+	} {
+		a, err := internalmeta.ParseAssertion(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertions = append(assertions, a)
+	}
+
+	uitest.Golden(t, "assertion_report", formatAssertionReport(internalmeta.CheckAssertions(sampleSystemInfo(), assertions)))
+}
+
+func TestMetaSystem_Assert(t *testing.T) {
+	orig := systemCollectors
+	t.Cleanup(func() { systemCollectors = orig })
+	systemCollectors = internalmeta.Collectors{
+		CPUInfo: func(context.Context) (internalmeta.CPUInfo, error) {
+			return internalmeta.CPUInfo{Model: "Intel Xeon", Vendor: "GenuineIntel", Cores: 16}, nil
+		},
+		GPU: func(context.Context) []internalmeta.GPUInfo {
+			return []internalmeta.GPUInfo{{Vendor: "NVIDIA", Model: "A100", Type: "discrete"}}
+		},
+	}
+
+	tests := []struct {
+		name     string
+		args     []string
+		want     []string
+		wantFail bool
+		wantErr  string
+	}{
+		{"pass", []string{"--assert", "cpu.cores >= 8", "--assert", "gpu[0].vendor == NVIDIA"}, []string{"PASS  cpu.cores >= 8", "cpu.cores = 16", "All 2 checks passed"}, false, ""},
+		{"fail", []string{"--assert", "cpu.cores > 32", "--assert", "cpu.vendor == GenuineIntel"}, []string{"FAIL  cpu.cores > 32", "1 of 2 checks failed"}, true, ""},
+		{"uncollected section", []string{"--sections", "cpu", "--assert", "gpu[0].vendor == NVIDIA"}, []string{"section gpu was not collected"}, true, ""},
+		{"junit", []string{"--assert", "cpu.cores > 32", "-o", "junit"}, []string{`<testcase name="cpu.cores &gt; 32" classname="cpu.cores">`, `<failure message="cpu.cores = 16">`}, true, ""},
+		{"tap", []string{"--assert", "cpu.cores > 8", "-o", "tap"}, []string{"ok 1 - cpu.cores > 8"}, false, ""},
+		{"invalid assertion", []string{"--assert", "cpu.cores"}, nil, false, "expected FIELD OP VALUE"},
+		{"junit without assert", []string{"-o", "junit"}, nil, false, "unsupported output format: junit"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCommand(internalmeta.BuildInfo{})
+			var buf bytes.Buffer
+			cmd.SetOut(&buf)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(append([]string{"system", "--no-network"}, tt.args...))
+
+			err := cmd.Execute()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if tt.wantFail != (err != nil) || (err != nil && !ui.IsReported(err)) {
+				t.Errorf("error = %v, want failure %v", err, tt.wantFail)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestMetaSystem_AssertJSON(t *testing.T) {
+	orig := systemCollectors
+	t.Cleanup(func() { systemCollectors = orig })
+	systemCollectors = internalmeta.Collectors{
+		GPU: func(context.Context) []internalmeta.GPUInfo {
+			t.Error("GPU probe ran although no assertion names it")
+			return nil
+		},
+	}
+
+	cmd := NewCommand(internalmeta.BuildInfo{})
+	var buf bytes.Buffer
+	cmd.SetOut(&buf)
+	cmd.SetArgs([]string{"system", "--no-network", "--assert", "memory.used_percent <= 100", "-o", "json"})
+	if err := cmd.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var report internalmeta.AssertionReport
+	if err := json.Unmarshal(buf.Bytes(), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if !report.Passed || len(report.Results) != 1 || report.Results[0].Field != "memory.used_percent" {
+		t.Errorf("report = %+v", report)
+	}
+}
@@ -0,0 +1,318 @@
+package meta
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/agent"
+	"github.com/anowarislam/ado/internal/completion"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// historyPoints is how many steps --step defaults to dividing the range
+// into, about the width of a sparkline that fits a terminal.
+const historyPoints = 60
+
+// defaultHistoryFields are shown when no --field is given.
+var defaultHistoryFields = []string{"cpu_percent", "memory.used_percent", "storage[*].used_percent"}
+
+// sparkBlocks render a sparkline from lowest to highest.
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+type historyOutput struct {
+	From      time.Time      `json:"from" yaml:"from"`
+	To        time.Time      `json:"to" yaml:"to"`
+	Step      string         `json:"step" yaml:"step"`
+	Aggregate string         `json:"aggregate" yaml:"aggregate"`
+	Series    []agent.Series `json:"series" yaml:"series"`
+}
+
+func newHistoryCommand() *cobra.Command {
+	var (
+		output     string
+		dir        string
+		since      string
+		until      string
+		fields     []string
+		step       time.Duration
+		aggregate  string
+		table      bool
+		listFields bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show resource usage over time from the agent's snapshots",
+		Long: `Query the snapshots recorded by ado agent run: CPU, memory, disk, and GPU
+usage over a time range, downsampled to one point per --step.
+
+Fields are named like the snapshot's JSON, with filesystems keyed by
+mountpoint and GPUs by index; * matches any run of characters:
+  cpu_percent
+  memory.used_percent, memory.used_mb, memory.available_mb, memory.swap_used_mb
+  storage[/].used_percent, storage[/].free_mb, storage[*].used_percent
+  gpu[0].utilization_percent, gpu[0].memory_used_mb
+List the fields recorded with --list-fields.
+
+--since and --until take a duration before now (24h) or an RFC 3339
+time. --step defaults to a sixtieth of the range; each step's samples
+are combined with --agg.
+
+Text output summarizes each field with a sparkline, or prints every
+point with --table; JSON and YAML output give the series.
+
+Examples:
+  # Memory over the last 24 hours
+  ado meta history --field memory.used_percent
+
+  # Free space on every filesystem, hourly minimum over a week
+  ado meta history --since 168h --step 1h --agg min --field 'storage[*].free_mb'
+
+  # CPU between two times as a table
+  ado meta history --since 2026-10-16T08:00:00Z --until 2026-10-16T09:00:00Z --table`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			if !slices.Contains(agent.Aggregates, aggregate) {
+				return fmt.Errorf("invalid --agg %q (valid: %s)", aggregate, strings.Join(agent.Aggregates, ", "))
+			}
+			if step < 0 {
+				return errors.New("--step must not be negative")
+			}
+			now := time.Now().UTC()
+			from, err := parseHistoryTime(since, now)
+			if err != nil {
+				return fmt.Errorf("invalid --since: %w", err)
+			}
+			to := now
+			if until != "" {
+				if to, err = parseHistoryTime(until, now); err != nil {
+					return fmt.Errorf("invalid --until: %w", err)
+				}
+			}
+			if !from.Before(to) {
+				return fmt.Errorf("--since %s is not before --until %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+			}
+
+			if dir == "" {
+				if dir, err = agent.DefaultDir(); err != nil {
+					return err
+				}
+			}
+			store, err := agent.Open(dir)
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			known, err := store.Fields()
+			if err != nil {
+				return err
+			}
+			if listFields {
+				payload := map[string][]string{"fields": known}
+				if known == nil {
+					payload["fields"] = []string{}
+				}
+				return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+					if len(known) == 0 {
+						return "No snapshots recorded. Start the agent with: ado agent run", nil
+					}
+					return strings.Join(known, "\n"), nil
+				})
+			}
+			if len(known) == 0 {
+				return errors.New("no snapshots recorded; start the agent with: ado agent run")
+			}
+
+			if step == 0 {
+				step = max(to.Sub(from)/historyPoints, time.Second).Round(time.Second)
+			}
+			fields = splitHistoryFields(fields)
+			if len(fields) == 0 {
+				// Skip defaults the host has nothing for, such as disks
+				// on a host whose filesystems all timed out.
+				for _, pattern := range defaultHistoryFields {
+					if slices.ContainsFunc(known, func(field string) bool { return agent.MatchField(pattern, field) }) {
+						fields = append(fields, pattern)
+					}
+				}
+			}
+			series, err := store.Query(agent.Query{From: from, To: to, Fields: fields, Step: step, Aggregate: aggregate})
+			if err != nil {
+				return err
+			}
+
+			payload := historyOutput{From: from, To: to, Step: step.String(), Aggregate: aggregate, Series: series}
+			if payload.Series == nil {
+				payload.Series = []agent.Series{}
+			}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				if table {
+					return formatHistoryTable(payload), nil
+				}
+				return formatHistory(payload, step), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().StringVar(&dir, "dir", "", "Agent store directory (default: <state dir>/agent)")
+	cmd.Flags().StringVar(&since, "since", "24h", "Start of the range: a duration before now or an RFC 3339 time")
+	cmd.Flags().StringVar(&until, "until", "", "End of the range: a duration before now or an RFC 3339 time (default: now)")
+	cmd.Flags().StringArrayVarP(&fields, "field", "f", nil, "Fields to show, repeatable or comma-separated; * matches any run of characters (default: cpu_percent, memory.used_percent, storage[*].used_percent)")
+	cmd.Flags().DurationVar(&step, "step", 0, "Downsample to one point per step (default: a sixtieth of the range)")
+	cmd.Flags().StringVar(&aggregate, "agg", agent.AggregateAvg, "How samples in a step are combined: avg, min, max")
+	cmd.Flags().BoolVar(&table, "table", false, "Print every point as a table instead of sparklines (text output)")
+	cmd.Flags().BoolVar(&listFields, "list-fields", false, "List the fields recorded and exit")
+	_ = cmd.RegisterFlagCompletionFunc("agg", completion.Fixed(agent.Aggregates...))
+	_ = cmd.MarkFlagDirname("dir")
+	return cmd
+}
+
+// parseHistoryTime parses a --since or --until value: a duration before
+// now or an RFC 3339 time.
+func parseHistoryTime(raw string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(raw); err == nil {
+		if d < 0 {
+			return time.Time{}, fmt.Errorf("duration %q must not be negative", raw)
+		}
+		return now.Add(-d), nil
+	}
+	t, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither a duration nor an RFC 3339 time", raw)
+	}
+	return t.UTC(), nil
+}
+
+// splitHistoryFields splits --field values at commas outside brackets,
+// so mountpoints may contain commas, and accepts quoted keys, as in
+// storage["/"].free_mb, for the stored storage[/].free_mb.
+func splitHistoryFields(values []string) []string {
+	unquote := strings.NewReplacer(`["`, "[", `"]`, "]", `['`, "[", `']`, "]")
+	var fields []string
+	for _, value := range values {
+		depth, start := 0, 0
+		for i, r := range value + "," {
+			switch {
+			case r == '[':
+				depth++
+			case r == ']' && depth > 0:
+				depth--
+			case r == ',' && depth == 0:
+				if field := strings.TrimSpace(value[start:i]); field != "" {
+					fields = append(fields, unquote.Replace(field))
+				}
+				start = i + 1
+			}
+		}
+	}
+	return fields
+}
+
+func formatHistory(h historyOutput, step time.Duration) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "History from %s to %s, %s per %s\n", h.From.Format(time.RFC3339), h.To.Format(time.RFC3339), h.Aggregate, h.Step)
+	if len(h.Series) == 0 {
+		b.WriteString("No snapshots in this range.\n")
+		return b.String()
+	}
+
+	width := len("FIELD")
+	for _, s := range h.Series {
+		width = max(width, len(s.Field))
+	}
+	fmt.Fprintf(&b, "\n%-*s  %9s  %9s  %9s  %9s  %s\n", width, "FIELD", "MIN", "AVG", "MAX", "LAST", "TREND")
+	for _, s := range h.Series {
+		lo, hi, sum := math.Inf(1), math.Inf(-1), 0.0
+		for _, p := range s.Points {
+			lo, hi, sum = min(lo, p.Value), max(hi, p.Value), sum+p.Value
+		}
+		last := s.Points[len(s.Points)-1].Value
+		fmt.Fprintf(&b, "%-*s  %9s  %9s  %9s  %9s  %s\n", width, s.Field,
+			formatHistoryValue(lo), formatHistoryValue(sum/float64(len(s.Points))), formatHistoryValue(hi), formatHistoryValue(last),
+			sparkline(s.Points, h.From, step, lo, hi))
+	}
+	return b.String()
+}
+
+// sparkline draws points scaled from lo to hi, one block per step of the
+// range, with a space for steps without samples. Ranges of more steps
+// than historyPoints*2 draw the points alone.
+func sparkline(points []agent.Point, from time.Time, step time.Duration, lo, hi float64) string {
+	block := func(v float64) rune {
+		if hi == lo {
+			return sparkBlocks[0]
+		}
+		return sparkBlocks[int((v-lo)/(hi-lo)*float64(len(sparkBlocks)-1)+0.5)]
+	}
+	slots := int(points[len(points)-1].Time.Sub(from)/step) + 1
+	if slots > historyPoints*2 {
+		out := make([]rune, len(points))
+		for i, p := range points {
+			out[i] = block(p.Value)
+		}
+		return string(out)
+	}
+	out := []rune(strings.Repeat(" ", slots))
+	for _, p := range points {
+		out[int(p.Time.Sub(from)/step)] = block(p.Value)
+	}
+	return string(out)
+}
+
+func formatHistoryTable(h historyOutput) string {
+	var times []time.Time
+	values := map[time.Time]map[string]float64{}
+	for _, s := range h.Series {
+		for _, p := range s.Points {
+			if values[p.Time] == nil {
+				values[p.Time] = map[string]float64{}
+				times = append(times, p.Time)
+			}
+			values[p.Time][s.Field] = p.Value
+		}
+	}
+	slices.SortFunc(times, func(a, b time.Time) int { return a.Compare(b) })
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%-20s", "TIME")
+	for _, s := range h.Series {
+		fmt.Fprintf(&b, "  %*s", max(len(s.Field), 9), s.Field)
+	}
+	b.WriteString("\n")
+	for _, t := range times {
+		fmt.Fprintf(&b, "%-20s", t.Format(time.RFC3339))
+		for _, s := range h.Series {
+			cell := "-"
+			if v, ok := values[t][s.Field]; ok {
+				cell = formatHistoryValue(v)
+			}
+			fmt.Fprintf(&b, "  %*s", max(len(s.Field), 9), cell)
+		}
+		b.WriteString("\n")
+	}
+	if len(times) == 0 {
+		b.WriteString("No snapshots in this range.\n")
+	}
+	return b.String()
+}
+
+// formatHistoryValue prints whole numbers, such as sizes in MB, without
+// decimals and others with one.
+func formatHistoryValue(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
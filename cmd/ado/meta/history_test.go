@@ -0,0 +1,136 @@
+package meta
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anowarislam/ado/internal/agent"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/ui/uitest"
+)
+
+// sampleHistory is two fields over an hour in 10-minute steps, with a gap
+// where the agent was not running.
+func sampleHistory() historyOutput {
+	from := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+	at := func(minutes int, value float64) agent.Point {
+		return agent.Point{Time: from.Add(time.Duration(minutes) * time.Minute), Value: value}
+	}
+	return historyOutput{
+		From:      from,
+		To:        from.Add(time.Hour),
+		Step:      "10m0s",
+		Aggregate: agent.AggregateAvg,
+		Series: []agent.Series{
+			{Field: "cpu_percent", Points: []agent.Point{at(0, 5), at(10, 12.5), at(20, 80), at(50, 20)}},
+			{Field: "storage[/].free_mb", Points: []agent.Point{at(0, 10240), at(10, 10200), at(50, 9000)}},
+		},
+	}
+}
+
+func TestFormatHistory(t *testing.T) {
+	uitest.Golden(t, "history", formatHistory(sampleHistory(), 10*time.Minute))
+}
+
+func TestFormatHistoryTable(t *testing.T) {
+	uitest.Golden(t, "history_table", formatHistoryTable(sampleHistory()))
+}
+
+func TestParseHistoryTime(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := map[string]string{
+		"24h":                       "2026-10-15T12:00:00Z",
+		"90m":                       "2026-10-16T10:30:00Z",
+		"2026-10-16T09:00:00+02:00": "2026-10-16T07:00:00Z",
+	}
+	for raw, want := range tests {
+		if got, err := parseHistoryTime(raw, now); err != nil || got.Format(time.RFC3339) != want {
+			t.Errorf("parseHistoryTime(%q) = %v, %v, want %s", raw, got, err, want)
+		}
+	}
+	for _, raw := range []string{"-1h", "yesterday", "2026-10-16"} {
+		if _, err := parseHistoryTime(raw, now); err == nil {
+			t.Errorf("parseHistoryTime(%q) succeeded", raw)
+		}
+	}
+}
+
+func TestSplitHistoryFields(t *testing.T) {
+	got := splitHistoryFields([]string{`cpu_percent, storage["/mnt/a,b"].free_mb`, "memory.*"})
+	if strings.Join(got, " ") != "cpu_percent storage[/mnt/a,b].free_mb memory.*" {
+		t.Errorf("splitHistoryFields() = %q", got)
+	}
+}
+
+func TestMetaHistory(t *testing.T) {
+	dir := t.TempDir()
+	store, err := agent.Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().UTC()
+	for i := range 10 {
+		snap := agent.NewSnapshot("web-1", internalmeta.Usage{
+			Time:    now.Add(-time.Duration(i) * time.Minute),
+			CPU:     internalmeta.CPUUsage{TotalPercent: float64(i)},
+			Memory:  internalmeta.MemoryInfo{UsedPercent: 50},
+			Storage: []internalmeta.StorageInfo{{Mountpoint: "/", UsedPercent: 40, FreeMB: 1000}},
+		})
+		if err := store.Append(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+	store.Close()
+
+	run := func(args ...string) (string, error) {
+		cmd := NewCommand(internalmeta.BuildInfo{})
+		var buf bytes.Buffer
+		cmd.SetOut(&buf)
+		cmd.SetErr(&buf)
+		cmd.SetArgs(append([]string{"history", "--dir", dir}, args...))
+		err := cmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"avg per 24m0s", "cpu_percent", "memory.used_percent", "storage[/].used_percent"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = run("--since", "1h", "--step", "1h", "--agg", "max", "-f", `storage["/"].free_mb,cpu_percent`, "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got historyOutput
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("%v:\n%s", err, out)
+	}
+	if got.Step != "1h0m0s" || len(got.Series) != 2 || got.Series[0].Field != "storage[/].free_mb" || got.Series[1].Points[0].Value != 9 {
+		t.Errorf("history = %+v", got)
+	}
+
+	out, err = run("--list-fields")
+	if err != nil || !strings.Contains(out, "memory.swap_used_mb\n") {
+		t.Errorf("--list-fields = %q, %v", out, err)
+	}
+
+	for args, want := range map[string]string{
+		"--field load":                   `no field matches "load"`,
+		"--agg median":                   `invalid --agg "median"`,
+		"--since 1h --until 2h":          "is not before --until",
+		"--since tomorrow":               "invalid --since",
+		"--dir " + t.TempDir() + " -f x": "no snapshots recorded",
+	} {
+		if _, err := run(strings.Fields(args)...); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: error = %v, want %q", args, err, want)
+		}
+	}
+}
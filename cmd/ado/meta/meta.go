@@ -1,13 +1,32 @@
 package meta
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
+	"github.com/anowarislam/ado/internal/completion"
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/features"
+	"github.com/anowarislam/ado/internal/ghactions"
+	"github.com/anowarislam/ado/internal/junit"
 	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/tap"
 	"github.com/anowarislam/ado/internal/ui"
+	"github.com/anowarislam/ado/internal/update"
 )
 
 func NewCommand(buildInfo internalmeta.BuildInfo) *cobra.Command {
@@ -19,8 +38,16 @@ func NewCommand(buildInfo internalmeta.BuildInfo) *cobra.Command {
 	cmd.AddCommand(
 		newInfoCommand(buildInfo),
 		newEnvCommand(),
+		newPathsCommand(),
 		newFeaturesCommand(),
 		newSystemCommand(),
+		newHistoryCommand(),
+		newToolsCommand(),
+		newDockerCommand(),
+		newK8sCommand(),
+		newDepsCommand(),
+		newLicensesCommand(),
+		newVerifyCommand(),
 	)
 
 	return cmd
@@ -38,8 +65,10 @@ func newInfoCommand(buildInfo internalmeta.BuildInfo) *cobra.Command {
 				return err
 			}
 
-			return ui.PrintOutput(cmd.OutOrStdout(), format, buildInfo, func() (string, error) {
-				return formatBuildInfo(buildInfo), nil
+			info := buildInfo
+			applyUpdateState(&info)
+			return ui.PrintOutput(cmd.OutOrStdout(), format, info, func() (string, error) {
+				return formatBuildInfo(info), nil
 			})
 		},
 	}
@@ -49,36 +78,154 @@ func newInfoCommand(buildInfo internalmeta.BuildInfo) *cobra.Command {
 }
 
 func newEnvCommand() *cobra.Command {
-	var output string
+	var (
+		output      string
+		explain     bool
+		allEnv      bool
+		showSecrets bool
+	)
 
 	cmd := &cobra.Command{
 		Use:   "env",
 		Short: "Show configuration and environment information",
+		Long: `Show the resolved config path, search locations, directories, and relevant
+environment variables.
+
+With --explain, also print the ordered decision trace of config resolution:
+every candidate source in precedence order, and why it was or wasn't
+selected.
+
+Besides ado's own variables, a curated set of diagnostic variables (PATH,
+SHELL, TERM, proxies, CI, XDG directories) is shown; --all-env shows every
+variable. Secrets are masked so the output is safe to paste into public
+issues: values of variables whose names contain TOKEN, KEY, SECRET,
+PASSWORD, CREDENTIAL or AUTH, passwords in URLs (e.g. proxy credentials),
+and well-known token formats. --show-secrets disables masking.
+
+Examples:
+  # Why isn't my config loading?
+  ado meta env --explain
+
+  # Full environment for a bug report
+  ado meta env --all-env --output json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			configPath, err := cmd.Root().PersistentFlags().GetString("config")
 			if err != nil {
 				return err
 			}
 
-			info := internalmeta.CollectEnvInfo(configPath)
+			homeDir, _ := os.UserHomeDir()
+			info := internalmeta.CollectEnvInfoWithOptions(configPath, internalmeta.EnvOptions{
+				AllEnv:         allEnv,
+				ShowSecrets:    showSecrets,
+				ConfigResolver: internalconfig.PathResolverFor(cmd.Context(), configPath, homeDir),
+			})
 			format, err := ui.ParseOutputFormat(output)
 			if err != nil {
 				return err
 			}
 
 			return ui.PrintOutput(cmd.OutOrStdout(), format, info, func() (string, error) {
-				return formatEnvInfo(info), nil
+				text := formatEnvInfo(info)
+				if explain {
+					text += formatResolution(info.Resolution)
+				}
+				return text, nil
 			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().BoolVar(&explain, "explain", false, "Show why each config source was or wasn't selected")
+	cmd.Flags().BoolVar(&allEnv, "all-env", false, "Show all environment variables (secrets redacted)")
+	cmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "Show secret values instead of masking them")
 	return cmd
 }
 
+func formatResolution(steps []internalconfig.ResolutionStep) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, "ConfigResolution:")
+	for _, step := range steps {
+		fmt.Fprintf(&b, "  %d. %s", step.Order, step.Source)
+		if step.Path != "" {
+			fmt.Fprintf(&b, " (%s)", step.Path)
+		}
+		fmt.Fprintf(&b, ": %s - %s\n", step.Status, step.Reason)
+	}
+	return b.String()
+}
+
+// featureRegistry is the registry listed and toggled by `meta features`;
+// replaceable in tests.
+var featureRegistry = features.Default
+
+// systemCollectors are the hardware probes of `meta system`; the zero
+// value uses the real ones. Replaceable in tests.
+var systemCollectors internalmeta.Collectors
+
+func newPathsCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "paths",
+		Short: "List the directories ado uses",
+		Long: `List every directory ado reads from or writes to (config, cache, state,
+data, logs, plugins) with whether it exists and how much space it uses, so
+you know what to back up or clean.
+
+Directories follow the XDG Base Directory spec ($XDG_CONFIG_HOME,
+$XDG_CACHE_HOME, $XDG_STATE_HOME, $XDG_DATA_HOME) with ~/Library on macOS
+and %LocalAppData%/%AppData% on Windows. Sizes exclude nested directories
+that are listed separately.
+
+Examples:
+  # Show directories
+  ado meta paths
+
+  # Machine-readable, e.g. for cleanup scripts
+  ado meta paths --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+
+			configPath, err := cmd.Root().PersistentFlags().GetString("config")
+			if err != nil {
+				return err
+			}
+			if configPath == "" {
+				configPath = os.Getenv("ADO_CONFIG")
+			}
+
+			paths := internalmeta.CollectPaths(configPath)
+			payload := map[string][]internalmeta.PathInfo{"paths": paths}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatPaths(paths), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func formatPaths(paths []internalmeta.PathInfo) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, "Paths:")
+	for _, p := range paths {
+		status := "missing"
+		if p.Exists {
+			status = ui.FormatSize(p.SizeBytes)
+		}
+		fmt.Fprintf(&b, "  %-7s %s (%s)\n", p.Kind, p.Path, status)
+		fmt.Fprintf(&b, "          %s\n", p.Description)
+	}
+	return b.String()
+}
+
 func newFeaturesCommand() *cobra.Command {
 	var output string
-	features := []string{}
 
 	cmd := &cobra.Command{
 		Use:   "features",
@@ -89,38 +236,197 @@ func newFeaturesCommand() *cobra.Command {
 				return err
 			}
 
-			payload := map[string][]string{"features": features}
-			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
-				if len(features) == 0 {
-					return "No experimental features enabled", nil
+			cfg, err := loadCommandConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			states := featureRegistry.Resolve(cfg.Features, features.EnvToggles())
+			enabled := []string{}
+			for _, s := range states {
+				if s.Enabled {
+					enabled = append(enabled, s.Name)
 				}
-				return strings.Join(features, "\n"), nil
+			}
+
+			payload := map[string]any{"features": enabled, "available": states}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatFeatureStates(states), nil
 			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.AddCommand(
+		newFeatureToggleCommand(true),
+		newFeatureToggleCommand(false),
+	)
 	return cmd
 }
 
+func newFeatureToggleCommand(enable bool) *cobra.Command {
+	use, verb := "disable", "Disabled"
+	if enable {
+		use, verb = "enable", "Enabled"
+	}
+
+	return &cobra.Command{
+		Use:               use + " FEATURE",
+		Short:             strings.ToUpper(use[:1]) + use[1:] + " a feature flag in the user config",
+		Annotations:       map[string]string{ui.DryRunAnnotation: "true"},
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.FirstArg(completeFeatures),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			feature, ok := featureRegistry.Lookup(name)
+			if !ok {
+				return unknownFeatureError(name)
+			}
+
+			path, err := featureConfigPath(cmd)
+			if err != nil {
+				return err
+			}
+
+			if enable && feature.Stage == features.StageExperimental && !ui.IsInteractive(cmd.InOrStdin()) {
+				fmt.Fprintf(cmd.ErrOrStderr(), "warning: enabling experimental feature %q in a non-interactive environment\n", name)
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				plan := ui.NewPlan()
+				plan.Add("write", path, fmt.Sprintf("%s feature %q", use, name))
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+			if err := internalconfig.SetFeature(path, name, enable); err != nil {
+				return err
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "%s feature %q in %s\n", verb, name, path)
+			return nil
+		},
+	}
+}
+
+// completeFeatures completes registered feature names with their
+// descriptions.
+func completeFeatures(*cobra.Command, []string, string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	var names []cobra.Completion
+	for _, f := range featureRegistry.All() {
+		names = append(names, cobra.CompletionWithDesc(f.Name, f.Description))
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+func unknownFeatureError(name string) error {
+	var names []string
+	for _, f := range featureRegistry.All() {
+		names = append(names, f.Name)
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("unknown feature %q: no features are registered", name)
+	}
+	return fmt.Errorf("unknown feature %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// loadCommandConfig loads the config selected by --config, $ADO_CONFIG, or
+// the default search paths.
+func loadCommandConfig(cmd *cobra.Command) (*internalconfig.Config, error) {
+	configPath, _ := cmd.Root().PersistentFlags().GetString("config")
+	homeDir, _ := os.UserHomeDir()
+	cfg, _, err := internalconfig.PathResolverFor(cmd.Context(), configPath, homeDir).Load()
+	return cfg, err
+}
+
+// featureConfigPath returns the config file that feature toggles are
+// written to: --config or $ADO_CONFIG when set, otherwise the user config.
+func featureConfigPath(cmd *cobra.Command) (string, error) {
+	if configPath, _ := cmd.Root().PersistentFlags().GetString("config"); configPath != "" {
+		return configPath, nil
+	}
+	if envPath := os.Getenv("ADO_CONFIG"); envPath != "" {
+		return envPath, nil
+	}
+	homeDir, _ := os.UserHomeDir()
+	if path := internalconfig.UserConfigPath(homeDir); path != "" {
+		return path, nil
+	}
+	return "", errors.New("cannot determine config path: set --config or ADO_CONFIG")
+}
+
+func formatFeatureStates(states []features.State) string {
+	if len(states) == 0 {
+		return "No experimental features enabled"
+	}
+
+	var b strings.Builder
+	for _, s := range states {
+		status := "disabled"
+		if s.Enabled {
+			status = "enabled"
+		}
+		fmt.Fprintf(&b, "%s: %s (%s, %s)", s.Name, status, s.Stage, s.Source)
+		if s.Description != "" {
+			fmt.Fprintf(&b, " - %s", s.Description)
+		}
+		fmt.Fprintln(&b)
+	}
+	return b.String()
+}
+
 func formatBuildInfo(info internalmeta.BuildInfo) string {
 	var b strings.Builder
 	fmt.Fprintf(&b, "Name: %s\n", info.Name)
 	fmt.Fprintf(&b, "Version: %s\n", info.Version)
-	fmt.Fprintf(&b, "Commit: %s\n", info.Commit)
+	if info.Dirty {
+		fmt.Fprintf(&b, "Commit: %s (dirty)\n", info.Commit)
+	} else {
+		fmt.Fprintf(&b, "Commit: %s\n", info.Commit)
+	}
 	fmt.Fprintf(&b, "BuildTime: %s\n", info.BuildTime)
 	fmt.Fprintf(&b, "GoVersion: %s\n", info.GoVersion)
 	fmt.Fprintf(&b, "Platform: %s\n", info.Platform)
+	if info.LatestVersion != "" {
+		if info.UpdateAvailable {
+			fmt.Fprintf(&b, "LatestVersion: %s (update available)\n", info.LatestVersion)
+		} else {
+			fmt.Fprintf(&b, "LatestVersion: %s\n", info.LatestVersion)
+		}
+	}
 	return b.String()
 }
 
+// applyUpdateState fills the latest release from the cached update check,
+// without touching the network.
+func applyUpdateState(info *internalmeta.BuildInfo) {
+	path, err := update.DefaultStatePath()
+	if err != nil {
+		return
+	}
+	state, err := update.LoadState(path)
+	if err != nil || state.LatestVersion == "" {
+		return
+	}
+	info.LatestVersion = state.LatestVersion
+	info.UpdateAvailable = update.CompareVersions(info.Version, state.LatestVersion) < 0
+}
+
 func newSystemCommand() *cobra.Command {
-	var output string
+	var (
+		output      string
+		noNetwork   bool
+		security    bool
+		ntp         bool
+		ntpServer   string
+		printSchema bool
+		sections    []string
+		fast        bool
+		asserts     []string
+	)
 
 	cmd := &cobra.Command{
 		Use:   "system",
 		Short: "Show system diagnostic information",
-		Long: `Display system-level diagnostic information including OS, CPU, GPU, NPU, memory, and storage.
+		Long: `Display system-level diagnostic information including OS, CPU, GPU, NPU, memory, storage,
+and cloud provider/instance metadata.
 
 Useful for:
   - Troubleshooting environment-specific issues
@@ -133,6 +439,14 @@ Output formats:
   - json: Structured JSON for parsing/automation
   - yaml: Structured YAML for parsing/automation
 
+With --assert, check fields of the report against thresholds instead of
+printing it, and exit non-zero if any check fails. Each assertion is
+FIELD OP VALUE, with FIELD a JSON path of the report, OP one of < <= >
+>= == !=, and VALUE a number, true, false, or a string. Storage is keyed
+by mountpoint, other lists by position; [*] checks every item. Only the
+sections the assertions name are collected unless --sections is given.
+The report can also be written as junit or tap.
+
 Examples:
   # Show system info in human-readable format
   ado meta system
@@ -141,25 +455,847 @@ Examples:
   ado meta system --output json
 
   # Extract specific field with jq
-  ado meta system --output json | jq '.memory.used_percent'`,
+  ado meta system --output json | jq '.memory.used_percent'
+
+  # Skip cloud metadata endpoint lookups
+  ado meta system --no-network
+
+  # Include SELinux/AppArmor, Secure Boot, sysctls, and ulimits
+  ado meta system --security
+
+  # Measure clock drift against an NTP server
+  ado meta system --ntp --ntp-server time.google.com
+
+  # Collect only some sections
+  ado meta system --sections cpu,memory
+
+  # Skip the GPU and NPU probes, the slowest part of the report
+  ado meta system --fast
+
+  # Print the JSON Schema of the structured output
+  ado meta system --print-schema
+
+  # Fail a cron job or CI step when the host is short on memory or disk
+  ado meta system --assert 'memory.used_percent < 90' --assert 'storage["/"].free_mb > 10240'
+
+  # Check every filesystem
+  ado meta system --assert 'storage[*].used_percent < 95' --output junit`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			if printSchema {
+				return printSystemSchema(cmd, output)
+			}
+
+			assertions, err := parseAssertions(asserts)
+			if err != nil {
+				return err
+			}
+			selected, err := internalmeta.ParseSections(sections)
+			if err != nil {
+				return err
+			}
+			switch {
+			case fast:
+				selected = slices.DeleteFunc(slices.Clone(internalmeta.DefaultSystemSections), func(s string) bool {
+					return s == internalmeta.SectionGPU || s == internalmeta.SectionNPU
+				})
+			case len(assertions) > 0 && len(selected) == 0:
+				selected = assertionSections(assertions)
+			}
+			var extra []ui.OutputFormat
+			if len(assertions) > 0 {
+				extra = []ui.OutputFormat{ui.OutputJUnit, ui.OutputTAP}
+			}
+			format, err := ui.ParseOutputFormat(output, extra...)
+			if err != nil {
+				return err
+			}
+
 			ctx := cmd.Context()
-			info := internalmeta.CollectSystemInfo(ctx)
+			opts := internalmeta.SystemOptions{
+				SkipNetwork:     noNetwork,
+				IncludeSecurity: security,
+				Sections:        selected,
+				Collectors:      systemCollectors,
+			}
+			if ntp {
+				opts.NTPServer = ntpServer
+			}
+			info := internalmeta.CollectSystemInfo(ctx, opts)
+			if len(assertions) > 0 {
+				return reportAssertions(cmd, format, internalmeta.CheckAssertions(info, assertions))
+			}
+
+			return ui.PrintOutput(cmd.OutOrStdout(), format, info, func() (string, error) {
+				return formatSystemInfo(info), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml; junit and tap with --assert")
+	cmd.Flags().BoolVar(&noNetwork, "no-network", false, "Skip detectors that make network calls (cloud metadata)")
+	cmd.Flags().BoolVar(&printSchema, "print-schema", false, "Print the JSON Schema of the structured output and exit")
+	cmd.Flags().BoolVar(&ntp, "ntp", false, "Measure clock offset against an NTP server")
+	cmd.Flags().StringVar(&ntpServer, "ntp-server", internalmeta.DefaultNTPServer, "NTP server used with --ntp")
+	cmd.Flags().BoolVar(&security, "security", false, "Include security posture (SELinux/AppArmor, Secure Boot, sysctls, ulimits)")
+	cmd.Flags().StringSliceVar(&sections, "sections", nil, "Collect only these sections: "+strings.Join(internalmeta.SystemSections, ", "))
+	cmd.Flags().BoolVar(&fast, "fast", false, "Skip the GPU and NPU probes")
+	cmd.Flags().StringArrayVar(&asserts, "assert", nil, "Check a field against a threshold, such as 'memory.used_percent < 90'; repeatable, exits non-zero on failure")
+	cmd.MarkFlagsMutuallyExclusive("sections", "fast")
+	cmd.MarkFlagsMutuallyExclusive("assert", "print-schema")
+	_ = cmd.RegisterFlagCompletionFunc("sections", completion.Fixed(internalmeta.SystemSections...))
+	_ = cmd.RegisterFlagCompletionFunc("output", completion.Fixed("text", "json", "yaml", "junit", "tap"))
+	return cmd
+}
+
+// printSystemSchema writes the SystemInfo JSON Schema. The schema is a
+// structured document, so text output falls back to JSON.
+func printSystemSchema(cmd *cobra.Command, output string) error {
+	format, err := ui.ParseOutputFormat(output)
+	if err != nil {
+		return err
+	}
+	if format == ui.OutputText {
+		format = ui.OutputJSON
+	}
+	return ui.PrintOutput(cmd.OutOrStdout(), format, internalmeta.SystemInfoSchema(), nil)
+}
+
+func newDepsCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "deps",
+		Short: "List Go module dependencies compiled into ado",
+		Long: `List the Go module dependencies (path, version, checksum) recorded in the
+ado binary's build info, so security teams can audit exactly what a given
+binary ships.
+
+Examples:
+  # List dependencies
+  ado meta deps
+
+  # Export for an SBOM or vulnerability scanner
+  ado meta deps --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
 			format, err := ui.ParseOutputFormat(output)
 			if err != nil {
 				return err
 			}
 
+			deps := internalmeta.CollectDependencies()
+			payload := map[string][]internalmeta.DependencyInfo{"dependencies": deps}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatDependencies(deps), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func formatDependencies(deps []internalmeta.DependencyInfo) string {
+	if len(deps) == 0 {
+		return "No module dependencies recorded in build info"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Dependencies (%d):\n", len(deps))
+	for _, dep := range deps {
+		fmt.Fprintf(&b, "  %s %s", dep.Path, dep.Version)
+		if dep.Replace != "" {
+			fmt.Fprintf(&b, " => %s", dep.Replace)
+		}
+		if dep.Sum != "" {
+			fmt.Fprintf(&b, " %s", dep.Sum)
+		}
+		fmt.Fprintln(&b)
+	}
+	return b.String()
+}
+
+func newLicensesCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "licenses",
+		Short: "Show licenses of dependencies compiled into ado",
+		Long: `Show the license of every module compiled into ado, grouped by SPDX
+identifier. The inventory is generated at build time and embedded in the
+binary, so no network access or external tooling is required.
+
+Examples:
+  # Show licenses grouped by SPDX identifier
+  ado meta licenses
+
+  # Export the full inventory for compliance review
+  ado meta licenses --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+
+			licenses, err := internalmeta.CollectLicenses()
+			if err != nil {
+				return err
+			}
+			groups := internalmeta.GroupLicenses(licenses)
+
+			payload := map[string]any{
+				"modules":  licenses,
+				"licenses": groups,
+			}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatLicenses(licenses, groups), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func formatLicenses(licenses []internalmeta.LicenseInfo, groups []internalmeta.LicenseGroup) string {
+	if len(licenses) == 0 {
+		return "No license information embedded"
+	}
+
+	versions := make(map[string]string, len(licenses))
+	for _, l := range licenses {
+		versions[l.Path] = l.Version
+	}
+
+	var b strings.Builder
+	for i, group := range groups {
+		if i > 0 {
+			fmt.Fprintln(&b)
+		}
+		fmt.Fprintf(&b, "%s (%d):\n", group.License, len(group.Modules))
+		for _, path := range group.Modules {
+			fmt.Fprintf(&b, "  %s %s\n", path, versions[path])
+		}
+	}
+	return b.String()
+}
+
+// VerifyResult is the outcome of `ado meta verify`.
+type VerifyResult struct {
+	Path            string `json:"path" yaml:"path"`
+	SHA256          string `json:"sha256" yaml:"sha256"`
+	EmbeddedDigest  string `json:"embedded_digest,omitempty" yaml:"embedded_digest,omitempty"`
+	Digest          string `json:"digest" yaml:"digest"` // match, mismatch, not_embedded
+	Checksums       string `json:"checksums,omitempty" yaml:"checksums,omitempty"`
+	Asset           string `json:"asset" yaml:"asset"`
+	Checksum        string `json:"checksum" yaml:"checksum"` // match, mismatch, missing
+	Signature       string `json:"signature" yaml:"signature"`
+	SignatureMethod string `json:"signature_method,omitempty" yaml:"signature_method,omitempty"`
+	SigningIdentity string `json:"signing_identity,omitempty" yaml:"signing_identity,omitempty"`
+	Error           string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Verified reports whether the binary is listed in a checksums.txt whose
+// signature verified. The embedded digest only catches accidental damage:
+// anyone who alters a binary can stamp it again, so a match proves
+// nothing on its own, while a mismatch still fails.
+func (r VerifyResult) Verified() bool {
+	return r.Digest != internalmeta.DigestMismatch && r.Checksum == update.ChecksumMatch && r.Signature == update.SignatureVerified
+}
+
+// verifyOptions selects the checksums file and its signature; empty paths
+// fall back to checksums.txt next to the binary and sidecar files next to
+// it (checksums.txt.sig/.pem, checksums.txt.minisig).
+type verifyOptions struct {
+	checksums   string
+	signature   string
+	certificate string
+	minisig     string
+	minisignKey string
+	asset       string
+	cosign      update.CosignVerifier
+	minisign    update.MinisignVerifier
+}
+
+func newVerifyCommand() *cobra.Command {
+	var (
+		output string
+		opts   verifyOptions
+	)
+
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Verify the integrity and signature of the running ado binary",
+		Long: `Check the running binary's SHA-256 digest against the release's signed
+checksums.txt, after verifying the signature of checksums.txt.
+
+The checksums file is read from --checksums, defaulting to checksums.txt
+next to the binary; download it with its signature from the release. The
+signature is read from --signature/--certificate (cosign keyless) or
+--minisig (minisign), defaulting to checksums.txt.sig/.pem or
+checksums.txt.minisig next to it. Cosign signatures must match the signing
+identity embedded at build time; minisign signatures must match the
+embedded public key.
+
+The digest stamped in at release build time is reported as well, and a
+mismatch fails verification.
+
+Exits non-zero unless the signature verifies and the binary's digest is
+listed in checksums.txt.
+
+Examples:
+  # Verify with checksums.txt and its signature next to the binary
+  ado meta verify
+
+  # Verify with downloaded release files
+  ado meta verify --checksums checksums.txt --signature checksums.txt.sig --certificate checksums.txt.pem`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output, ui.OutputJUnit, ui.OutputTAP)
+			if err != nil {
+				return err
+			}
+
+			executable, err := update.ResolveExecutable()
+			if err != nil {
+				return err
+			}
+
+			identity, issuer := internalmeta.SigningIdentity, internalmeta.SigningIssuer
+			if identity == "" {
+				identity, issuer = update.DefaultCertIdentity, update.DefaultCertIssuer
+			}
+			opts.cosign = update.NewCosignVerifier()
+			opts.cosign.Identity, opts.cosign.Issuer = identity, issuer
+			if opts.minisignKey == "" {
+				opts.minisignKey = internalmeta.MinisignPublicKey
+			}
+			opts.minisign = update.NewMinisignVerifier(opts.minisignKey)
+			opts.asset = update.BinaryAssetName(runtime.GOOS, runtime.GOARCH)
+
+			result, err := verifyBinary(cmd.Context(), executable, opts)
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case ui.OutputJUnit:
+				err = junit.Write(cmd.OutOrStdout(), "ado meta verify", verifySuite(result))
+			case ui.OutputTAP:
+				err = tap.Write(cmd.OutOrStdout(), verifyTests(result))
+			default:
+				err = ui.PrintOutput(cmd.OutOrStdout(), format, result, func() (string, error) {
+					return formatVerifyResult(result), nil
+				})
+			}
+			if err != nil {
+				return err
+			}
+			if ghactions.Enabled() {
+				if err := reportVerifyToActions(cmd.OutOrStdout(), format, result); err != nil {
+					return err
+				}
+			}
+			if !result.Verified() {
+				return ui.Reported(fmt.Errorf("verification failed for %s", executable))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&opts.checksums, "checksums", "", "Release checksums file (default: checksums.txt next to the binary)")
+	cmd.Flags().StringVar(&opts.signature, "signature", "", "Cosign signature of the checksums file (default: <checksums>.sig)")
+	cmd.Flags().StringVar(&opts.certificate, "certificate", "", "Cosign certificate of the checksums file (default: <checksums>.pem)")
+	cmd.Flags().StringVar(&opts.minisig, "minisig", "", "Minisign signature of the checksums file (default: <checksums>.minisig)")
+	cmd.Flags().StringVar(&opts.minisignKey, "minisign-key", "", "Minisign public key (default: embedded at build time)")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml, junit, tap")
+	_ = cmd.RegisterFlagCompletionFunc("output", completion.Fixed("text", "json", "yaml", "junit", "tap"))
+	return cmd
+}
+
+func verifyBinary(ctx context.Context, path string, opts verifyOptions) (VerifyResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return VerifyResult{}, fmt.Errorf("read executable: %w", err)
+	}
+
+	sum := sha256.Sum256(data)
+	result := VerifyResult{
+		Path:           path,
+		SHA256:         hex.EncodeToString(sum[:]),
+		EmbeddedDigest: internalmeta.EmbeddedDigest(),
+		Asset:          opts.asset,
+		Checksum:       update.ChecksumMissing,
+		Signature:      update.SignatureUnsigned,
+	}
+
+	result.Digest, err = internalmeta.VerifyEmbeddedDigest(data)
+	if err != nil {
+		// Builds without the digest field (e.g. stripped by a custom linker
+		// step) cannot be checked; report them like unstamped builds.
+		result.Digest = internalmeta.DigestNotEmbedded
+	}
+
+	checksumsPath := opts.checksums
+	if checksumsPath == "" {
+		checksumsPath = filepath.Join(filepath.Dir(path), update.ChecksumsAsset)
+	}
+	checksums, err := os.ReadFile(checksumsPath)
+	if err != nil {
+		result.Error = fmt.Sprintf("no %s: %v", update.ChecksumsAsset, err)
+		return result, nil
+	}
+	result.Checksums = checksumsPath
+
+	// The checksums file is only trusted once its signature verified.
+	var verr error
+	sig, hasSig := readSidecar(opts.signature, checksumsPath+update.SignatureSuffix)
+	cert, hasCert := readSidecar(opts.certificate, checksumsPath+update.CertificateSuffix)
+	minisig, hasMinisig := readSidecar(opts.minisig, checksumsPath+".minisig")
+
+	switch {
+	case hasSig && hasCert:
+		result.SignatureMethod = "cosign"
+		result.SigningIdentity = opts.cosign.Identity
+		verr = opts.cosign.VerifyBlob(ctx, checksums, sig, cert)
+	case hasMinisig:
+		result.SignatureMethod = "minisign"
+		result.SigningIdentity = opts.minisign.PublicKey
+		verr = opts.minisign.Verify(ctx, checksums, minisig)
+	default:
+		result.Error = fmt.Sprintf("no signature found for %s", checksumsPath)
+		return result, nil
+	}
+
+	switch {
+	case verr == nil:
+		result.Signature = update.SignatureVerified
+	case errors.Is(verr, update.ErrCosignNotFound), errors.Is(verr, update.ErrMinisignNotFound):
+		result.Signature = update.SignatureUnavailable
+		result.Error = verr.Error()
+		return result, nil
+	default:
+		result.Signature = update.SignatureFailed
+		result.Error = verr.Error()
+		return result, nil
+	}
+
+	switch err := update.VerifyChecksum(data, opts.asset, checksums); {
+	case err == nil:
+		result.Checksum = update.ChecksumMatch
+	case errors.Is(err, update.ErrNotListed):
+		result.Error = err.Error()
+	default:
+		result.Checksum = update.ChecksumMismatch
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
+// readSidecar reads explicit, or fallback when explicit is empty.
+func readSidecar(explicit, fallback string) ([]byte, bool) {
+	path := explicit
+	if path == "" {
+		path = fallback
+	}
+	data, err := os.ReadFile(path)
+	return data, err == nil
+}
+
+func formatVerifyResult(r VerifyResult) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Binary: %s\n", r.Path)
+	fmt.Fprintf(&b, "SHA256: %s\n", r.SHA256)
+
+	switch r.Digest {
+	case internalmeta.DigestMatch:
+		fmt.Fprintln(&b, "Digest: OK (matches embedded digest)")
+	case internalmeta.DigestMismatch:
+		fmt.Fprintf(&b, "Digest: MISMATCH (embedded %s)\n", r.EmbeddedDigest)
+	default:
+		fmt.Fprintln(&b, "Digest: not embedded (not a release build)")
+	}
+
+	switch r.Signature {
+	case update.SignatureVerified:
+		fmt.Fprintf(&b, "Signature: OK (%s, %s)\n", r.SignatureMethod, r.SigningIdentity)
+	case update.SignatureFailed:
+		fmt.Fprintf(&b, "Signature: FAILED (%s): %s\n", r.SignatureMethod, r.Error)
+	case update.SignatureUnavailable:
+		fmt.Fprintf(&b, "Signature: FAILED (not checked: %s)\n", r.Error)
+	default:
+		fmt.Fprintf(&b, "Signature: FAILED (%s)\n", r.Error)
+	}
+
+	switch {
+	case r.Checksum == update.ChecksumMatch:
+		fmt.Fprintf(&b, "Checksum: OK (%s in %s)\n", r.Asset, r.Checksums)
+	case r.Checksum == update.ChecksumMismatch:
+		fmt.Fprintf(&b, "Checksum: MISMATCH (%s)\n", r.Error)
+	case r.Signature == update.SignatureVerified:
+		fmt.Fprintf(&b, "Checksum: FAILED (%s)\n", r.Error)
+	default:
+		fmt.Fprintln(&b, "Checksum: not checked (no signed checksums.txt)")
+	}
+	return b.String()
+}
+
+// verifySuite reports r as a JUnit suite with a digest and a signature
+// case. A check that could not be made, such as on a development build,
+// is skipped.
+func verifySuite(r VerifyResult) junit.TestSuite {
+	digest := junit.TestCase{Name: "digest", Classname: r.Path}
+	switch r.Digest {
+	case internalmeta.DigestMatch:
+	case internalmeta.DigestMismatch:
+		digest.Failure = &junit.Failure{Message: fmt.Sprintf("digest %s does not match embedded digest %s", r.SHA256, r.EmbeddedDigest)}
+	default:
+		digest.Skipped = &junit.Skipped{Message: "digest not embedded (not a release build)"}
+	}
+
+	signature := junit.TestCase{Name: "signature", Classname: r.Path}
+	switch r.Signature {
+	case update.SignatureVerified:
+	case update.SignatureFailed:
+		signature.Failure = &junit.Failure{Message: fmt.Sprintf("%s signature verification failed: %s", r.SignatureMethod, r.Error)}
+	default:
+		signature.Failure = &junit.Failure{Message: fmt.Sprintf("signature not verified: %s", r.Error)}
+	}
+
+	checksum := junit.TestCase{Name: "checksum", Classname: r.Path}
+	switch {
+	case r.Checksum == update.ChecksumMatch:
+	case r.Checksum == update.ChecksumMismatch:
+		checksum.Failure = &junit.Failure{Message: r.Error}
+	case r.Signature == update.SignatureVerified:
+		checksum.Failure = &junit.Failure{Message: fmt.Sprintf("%s is not listed in %s", r.Asset, r.Checksums)}
+	default:
+		checksum.Skipped = &junit.Skipped{Message: "no signed checksums.txt"}
+	}
+	return junit.NewSuite(r.Path, digest, signature, checksum)
+}
+
+// verifyTests reports r as TAP test points, taking the outcomes from
+// verifySuite.
+func verifyTests(r VerifyResult) []tap.Test {
+	suite := verifySuite(r)
+	tests := make([]tap.Test, len(suite.Cases))
+	for i, c := range suite.Cases {
+		tests[i] = tap.Test{OK: c.Failure == nil, Description: c.Name}
+		switch {
+		case c.Failure != nil:
+			tests[i].Diagnostics = map[string]any{"message": c.Failure.Message, "binary": r.Path}
+		case c.Skipped != nil:
+			tests[i].Skip = c.Skipped.Message
+		}
+	}
+	return tests
+}
+
+// reportVerifyToActions annotates the run with each failed check, unless
+// w carries structured output, and adds the checks to the job summary.
+func reportVerifyToActions(w io.Writer, format ui.OutputFormat, r VerifyResult) error {
+	if format == ui.OutputText {
+		var failures []ghactions.Annotation
+		if r.Digest == internalmeta.DigestMismatch {
+			failures = append(failures, ghactions.Annotation{
+				Title:   "ado meta verify",
+				Message: fmt.Sprintf("%s: digest %s does not match embedded digest %s", r.Path, r.SHA256, r.EmbeddedDigest),
+			})
+		}
+		switch {
+		case r.Signature == update.SignatureFailed:
+			failures = append(failures, ghactions.Annotation{
+				Title:   "ado meta verify",
+				Message: fmt.Sprintf("%s: %s signature verification failed: %s", r.Path, r.SignatureMethod, r.Error),
+			})
+		case r.Signature != update.SignatureVerified:
+			failures = append(failures, ghactions.Annotation{
+				Title:   "ado meta verify",
+				Message: fmt.Sprintf("%s: signature not verified: %s", r.Path, r.Error),
+			})
+		case r.Checksum != update.ChecksumMatch:
+			failures = append(failures, ghactions.Annotation{
+				Title:   "ado meta verify",
+				Message: fmt.Sprintf("%s: %s", r.Path, r.Error),
+			})
+		}
+		if err := ghactions.Annotate(w, failures...); err != nil {
+			return err
+		}
+	}
+
+	heading := "### \u2713 Binary verified"
+	if !r.Verified() {
+		heading = "### \u2717 Binary verification failed"
+	}
+	signature := r.Signature
+	if r.SignatureMethod != "" {
+		signature += " (" + r.SignatureMethod + ")"
+	}
+	table := ghactions.Table([]string{"Check", "Result"}, [][]string{
+		{"Binary", "`" + r.Path + "`"},
+		{"SHA-256", "`" + r.SHA256 + "`"},
+		{"Digest", r.Digest},
+		{"Signature", signature},
+		{"Checksum", r.Checksum},
+	})
+	return ghactions.AppendSummary(heading + "\n\n" + table)
+}
+
+func newToolsCommand() *cobra.Command {
+	var (
+		output  string
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "tools",
+		Short: "Show versions of common developer tools",
+		Long: `Detect versions of commonly relevant developer tools (git, docker, kubectl, go,
+node, python, make) by running each with a bounded timeout.
+
+Useful for comparing environments between machines when diagnosing
+"works on my machine" issues.
+
+Examples:
+  # Show tool versions
+  ado meta tools
+
+  # Export as JSON with a shorter per-tool timeout
+  ado meta tools --timeout 500ms --output json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+
+			tools := internalmeta.CollectToolInfo(cmd.Context(), timeout)
+			payload := map[string][]internalmeta.ToolInfo{"tools": tools}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatToolInfo(tools), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().DurationVar(&timeout, "timeout", internalmeta.DefaultToolTimeout, "Maximum time to wait for each tool")
+	return cmd
+}
+
+func newDockerCommand() *cobra.Command {
+	var (
+		output  string
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "docker",
+		Short: "Show the container ado runs in and the Docker daemon",
+		Long: `Report whether ado runs in a container (Docker, Podman, Kubernetes,
+containerd, or LXC), the container's ID, and its cgroup memory and CPU
+limits, which tools reading /proc/meminfo or counting host CPUs do not
+see. Then query the Docker daemon at $DOCKER_HOST, or
+/var/run/docker.sock, for its version, storage driver, cgroup driver,
+and container and image counts.
+
+The daemon is optional: when there is no socket, or it cannot be read,
+the reason is reported and the command still succeeds. unix:// sockets
+and plain tcp:// hosts are supported; TLS-protected daemons are not.
+
+Examples:
+  # Am I in a container, and what are its limits?
+  ado meta docker
+
+  # Daemon version for a bug report
+  ado meta docker --output json | jq .daemon.version`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+
+			info := internalmeta.CollectDockerInfo(cmd.Context(), timeout)
 			return ui.PrintOutput(cmd.OutOrStdout(), format, info, func() (string, error) {
-				return formatSystemInfo(info), nil
+				return formatDockerInfo(info), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().DurationVar(&timeout, "timeout", internalmeta.DefaultDockerTimeout, "Maximum time to wait for the Docker daemon")
+	return cmd
+}
+
+func formatDockerInfo(info internalmeta.DockerInfo) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, "Container:")
+	if c := info.Container; c == nil {
+		fmt.Fprintln(&b, "  not detected (running on the host)")
+	} else {
+		fmt.Fprintf(&b, "  Runtime: %s\n", c.Runtime)
+		if c.ID != "" {
+			fmt.Fprintf(&b, "  ID: %s\n", c.ID)
+		}
+		switch {
+		case c.Limits == nil:
+			fmt.Fprintln(&b, "  Limits: unknown (cgroups unavailable)")
+		default:
+			memory, cpu := "unlimited", "unlimited"
+			if c.Limits.MemoryLimitMB > 0 {
+				memory = fmt.Sprintf("%d MB (using %d MB)", c.Limits.MemoryLimitMB, c.Limits.MemoryUsageMB)
+			}
+			if c.Limits.CPUQuota > 0 {
+				cpu = fmt.Sprintf("%.2f CPUs", c.Limits.CPUQuota)
+			}
+			fmt.Fprintf(&b, "  Memory Limit: %s\n", memory)
+			fmt.Fprintf(&b, "  CPU Limit: %s\n", cpu)
+		}
+	}
+
+	d := info.Daemon
+	fmt.Fprintln(&b, "Docker Daemon:")
+	fmt.Fprintf(&b, "  Host: %s\n", d.Host)
+	if !d.Reachable {
+		fmt.Fprintf(&b, "  Status: unreachable: %s\n", d.Error)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "  Version: %s (API %s, %s/%s)\n", d.Version, d.APIVersion, d.OS, d.Arch)
+	if d.Error != "" {
+		fmt.Fprintf(&b, "  Error: %s\n", d.Error)
+		return b.String()
+	}
+	fmt.Fprintf(&b, "  Storage Driver: %s\n", d.StorageDriver)
+	fmt.Fprintf(&b, "  Cgroup Driver: %s (v%s)\n", d.CgroupDriver, d.CgroupVersion)
+	fmt.Fprintf(&b, "  Containers: %d running, %d total\n", d.ContainersRunning, d.Containers)
+	fmt.Fprintf(&b, "  Images: %d\n", d.Images)
+	fmt.Fprintf(&b, "  Resources: %d CPUs, %d MB\n", d.CPUs, d.MemoryMB)
+	return b.String()
+}
+
+func newK8sCommand() *cobra.Command {
+	var (
+		output  string
+		timeout time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "k8s",
+		Short: "Show the Kubernetes context and API server ado is pointed at",
+		Long: `Report the current kubeconfig context: the cluster, user, namespace,
+and API server it points at, read from $KUBECONFIG or ~/.kube/config the
+way kubectl merges them. The API server is queried for its version and
+compared with kubectl on PATH, which supports servers one minor version
+older or newer.
+
+In a pod without a kubeconfig, the service account is used instead, and
+the allocatable CPU, memory, and pods of the pod's node are reported;
+that needs RBAC permission to get the pod and its node.
+
+Only static tokens and client certificates are sent; exec plugins such
+as aws or gke-gcloud-auth-plugin are not run, which is enough for the
+version endpoint on most clusters. An unreachable server is reported,
+not an error.
+
+Examples:
+  # Which cluster am I pointed at?
+  ado meta k8s
+
+  # Check kubectl version skew in CI
+  ado meta k8s --output json | jq -e .skew.supported`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+
+			info := internalmeta.CollectK8sInfo(cmd.Context(), timeout)
+			return ui.PrintOutput(cmd.OutOrStdout(), format, info, func() (string, error) {
+				return formatK8sInfo(info), nil
 			})
 		},
 	}
 
 	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().DurationVar(&timeout, "timeout", internalmeta.DefaultK8sTimeout, "Maximum time to wait for the API server")
 	return cmd
 }
 
+func formatK8sInfo(info internalmeta.K8sInfo) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, "Kubernetes:")
+	if info.Context == "" {
+		fmt.Fprintf(&b, "  Context: none (%s)\n", info.Error)
+		if info.ClientVersion != "" {
+			fmt.Fprintf(&b, "  kubectl: %s\n", info.ClientVersion)
+		}
+		return b.String()
+	}
+
+	fmt.Fprintf(&b, "  Context: %s\n", info.Context)
+	if info.Cluster != "" {
+		fmt.Fprintf(&b, "  Cluster: %s\n", info.Cluster)
+	}
+	if info.User != "" {
+		fmt.Fprintf(&b, "  User: %s\n", info.User)
+	}
+	fmt.Fprintf(&b, "  Namespace: %s\n", info.Namespace)
+	if len(info.Kubeconfig) > 0 {
+		fmt.Fprintf(&b, "  Kubeconfig: %s\n", strings.Join(info.Kubeconfig, ", "))
+	}
+	if info.Server != "" {
+		fmt.Fprintf(&b, "  Server: %s\n", info.Server)
+	}
+	if info.Reachable {
+		fmt.Fprintf(&b, "  Server Version: %s (%s)\n", info.ServerVersion, info.Platform)
+	} else {
+		fmt.Fprintf(&b, "  Server Version: unreachable: %s\n", info.Error)
+	}
+	if info.ClientVersion != "" {
+		line := "  kubectl: " + info.ClientVersion
+		switch skew := info.Skew; {
+		case skew == nil:
+		case skew.Minors == 0:
+			line += " (same minor version as the server)"
+		case skew.Supported:
+			line += fmt.Sprintf(" (skew %+d minor, supported)", skew.Minors)
+		default:
+			line += fmt.Sprintf(" (skew %+d minors, unsupported: kubectl supports one minor version of skew)", skew.Minors)
+		}
+		fmt.Fprintln(&b, line)
+	}
+
+	if n := info.Node; n != nil {
+		fmt.Fprintln(&b, "Node:")
+		if n.Name != "" {
+			fmt.Fprintf(&b, "  Name: %s\n", n.Name)
+		}
+		if n.Error != "" {
+			fmt.Fprintf(&b, "  Allocatable: unknown: %s\n", n.Error)
+		}
+		for _, name := range slices.Sorted(maps.Keys(n.Allocatable)) {
+			fmt.Fprintf(&b, "  Allocatable %s: %s\n", name, n.Allocatable[name])
+		}
+	}
+	return b.String()
+}
+
+func formatToolInfo(tools []internalmeta.ToolInfo) string {
+	var b strings.Builder
+	fmt.Fprintln(&b, "Tools:")
+	for _, tool := range tools {
+		switch {
+		case !tool.Found:
+			fmt.Fprintf(&b, "  %s: not found\n", tool.Name)
+		case tool.Error != "":
+			fmt.Fprintf(&b, "  %s: error: %s (%s)\n", tool.Name, tool.Error, tool.Path)
+		default:
+			fmt.Fprintf(&b, "  %s: %s (%s)\n", tool.Name, tool.Version, tool.Path)
+		}
+	}
+	return b.String()
+}
+
 func formatEnvInfo(info internalmeta.EnvInfo) string {
 	var b strings.Builder
 
@@ -180,6 +1316,8 @@ func formatEnvInfo(info internalmeta.EnvInfo) string {
 
 	fmt.Fprintf(&b, "HomeDir: %s\n", info.HomeDir)
 	fmt.Fprintf(&b, "CacheDir: %s\n", info.CacheDir)
+	fmt.Fprintf(&b, "StateDir: %s\n", info.StateDir)
+	fmt.Fprintf(&b, "DataDir: %s\n", info.DataDir)
 
 	fmt.Fprintln(&b, "EnvVariables:")
 	if len(info.Env) == 0 {
@@ -190,6 +1328,18 @@ func formatEnvInfo(info internalmeta.EnvInfo) string {
 		}
 	}
 
+	if len(info.SystemEnv) > 0 {
+		fmt.Fprintln(&b, "SystemEnv:")
+		keys := make([]string, 0, len(info.SystemEnv))
+		for key := range info.SystemEnv {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&b, "  %s=%s\n", key, info.SystemEnv[key])
+		}
+	}
+
 	return b.String()
 }
 
@@ -204,33 +1354,50 @@ func formatSystemInfo(info internalmeta.SystemInfo) string {
 	fmt.Fprintln(&b)
 
 	// CPU Section
-	fmt.Fprintln(&b, "CPU:")
-	fmt.Fprintf(&b, "  Model: %s\n", info.CPU.Model)
-	fmt.Fprintf(&b, "  Vendor: %s\n", info.CPU.Vendor)
-	fmt.Fprintf(&b, "  Cores: %d\n", info.CPU.Cores)
-	if info.CPU.FrequencyMHz > 0 {
-		fmt.Fprintf(&b, "  Frequency: %.0f MHz\n", info.CPU.FrequencyMHz)
-	} else {
-		fmt.Fprintln(&b, "  Frequency: unknown")
+	if info.Collected(internalmeta.SectionCPU) {
+		fmt.Fprintln(&b, "CPU:")
+		fmt.Fprintf(&b, "  Model: %s\n", info.CPU.Model)
+		fmt.Fprintf(&b, "  Vendor: %s\n", info.CPU.Vendor)
+		fmt.Fprintf(&b, "  Cores: %d\n", info.CPU.Cores)
+		if info.CPU.FrequencyMHz > 0 {
+			fmt.Fprintf(&b, "  Frequency: %.0f MHz\n", info.CPU.FrequencyMHz)
+		} else {
+			fmt.Fprintln(&b, "  Frequency: unknown")
+		}
+		if info.CPU.LimitSource == internalmeta.LimitSourceCgroup {
+			fmt.Fprintf(&b, "  Effective CPUs: %.2f (cgroup quota, applies to this process)\n", info.CPU.EffectiveCPUs)
+		}
+		fmt.Fprintln(&b)
 	}
-	fmt.Fprintln(&b)
 
 	// Memory Section
-	fmt.Fprintln(&b, "Memory:")
-	fmt.Fprintf(&b, "  Total: %d MB\n", info.Memory.TotalMB)
-	fmt.Fprintf(&b, "  Available: %d MB\n", info.Memory.AvailableMB)
-	fmt.Fprintf(&b, "  Used: %d MB (%.1f%%)\n", info.Memory.UsedMB, info.Memory.UsedPercent)
-	if info.Memory.SwapTotalMB > 0 {
-		fmt.Fprintf(&b, "  Swap: %d MB total, %d MB used\n", info.Memory.SwapTotalMB, info.Memory.SwapUsedMB)
+	if info.Collected(internalmeta.SectionMemory) {
+		fmt.Fprintln(&b, "Memory:")
+		fmt.Fprintf(&b, "  Total: %d MB\n", info.Memory.TotalMB)
+		fmt.Fprintf(&b, "  Available: %d MB\n", info.Memory.AvailableMB)
+		fmt.Fprintf(&b, "  Used: %d MB (%.1f%%)\n", info.Memory.UsedMB, info.Memory.UsedPercent)
+		if info.Memory.SwapTotalMB > 0 {
+			fmt.Fprintf(&b, "  Swap: %d MB total, %d MB used\n", info.Memory.SwapTotalMB, info.Memory.SwapUsedMB)
+		}
+		if info.Memory.LimitSource == internalmeta.LimitSourceCgroup {
+			fmt.Fprintf(&b, "  Process Limit: %d MB (cgroup, applies to this process)\n", info.Memory.EffectiveLimitMB)
+			if info.Cgroup != nil {
+				fmt.Fprintf(&b, "  Cgroup Usage: %d MB\n", info.Cgroup.MemoryUsageMB)
+			}
+		}
+		fmt.Fprintln(&b)
 	}
-	fmt.Fprintln(&b)
 
 	// Storage Section
 	if len(info.Storage) > 0 {
 		fmt.Fprintln(&b, "Storage:")
 		for _, storage := range info.Storage {
-			fmt.Fprintf(&b, "  %s: %d MB total, %d MB used (%.1f%%)\n",
-				storage.Mountpoint, storage.TotalMB, storage.UsedMB, storage.UsedPercent)
+			if storage.Unresponsive {
+				fmt.Fprintf(&b, "  %s: unresponsive (%s)\n", storage.Mountpoint, storage.Filesystem)
+				continue
+			}
+			fmt.Fprintf(&b, "  %s: %d MB total, %d MB used (%.1f%%)%s\n",
+				storage.Mountpoint, storage.TotalMB, storage.UsedMB, storage.UsedPercent, formatDiskHealth(storage.Health))
 		}
 		fmt.Fprintln(&b)
 	}
@@ -249,7 +1416,123 @@ func formatSystemInfo(info internalmeta.SystemInfo) string {
 		fmt.Fprintln(&b, "NPU:")
 		fmt.Fprintf(&b, "  Type: %s\n", info.NPU.Type)
 		fmt.Fprintf(&b, "  Detection Method: %s\n", info.NPU.InferenceMethod)
+		fmt.Fprintln(&b)
+	}
+	if !internalmeta.HardwareDetection && !info.Collected(internalmeta.SectionGPU) {
+		fmt.Fprintln(&b, "GPU/NPU: not compiled in (minimal build)")
+		fmt.Fprintln(&b)
+	}
+
+	// Cloud Section
+	if info.Cloud != nil {
+		fmt.Fprintln(&b, "Cloud:")
+		fmt.Fprintf(&b, "  Provider: %s\n", info.Cloud.Provider)
+		fmt.Fprintf(&b, "  Instance Type: %s\n", valueOrUnknown(info.Cloud.InstanceType))
+		fmt.Fprintf(&b, "  Region: %s\n", valueOrUnknown(info.Cloud.Region))
+		fmt.Fprintf(&b, "  Zone: %s\n", valueOrUnknown(info.Cloud.Zone))
+		fmt.Fprintf(&b, "  Detection Method: %s\n", info.Cloud.DetectionMethod)
+		fmt.Fprintln(&b)
+	}
+
+	// Time Section
+	if info.Time != nil {
+		fmt.Fprintln(&b, "Time:")
+		fmt.Fprintf(&b, "  Current: %s\n", info.Time.CurrentTime)
+		fmt.Fprintf(&b, "  Timezone: %s (UTC%s)\n", valueOrUnknown(info.Time.Timezone), info.Time.UTCOffset)
+		fmt.Fprintf(&b, "  Locale: %s\n", info.Time.Locale)
+		switch {
+		case info.Time.NTPOffsetMS != nil:
+			fmt.Fprintf(&b, "  Clock Offset: %+.1f ms (vs %s)\n", *info.Time.NTPOffsetMS, info.Time.NTPServer)
+		case info.Time.NTPError != "":
+			fmt.Fprintf(&b, "  Clock Offset: unknown (%s)\n", info.Time.NTPError)
+		}
+		fmt.Fprintln(&b)
+	}
+
+	// Go Runtime Section
+	if info.GoRuntime != nil {
+		formatGoRuntimeInfo(&b, info.GoRuntime)
+		fmt.Fprintln(&b)
+	}
+
+	// Security Section (optional)
+	if info.Security != nil {
+		formatSecurityInfo(&b, info.Security)
 	}
 
 	return b.String()
 }
+
+func formatGoRuntimeInfo(b *strings.Builder, rt *internalmeta.GoRuntimeInfo) {
+	fmt.Fprintln(b, "Go Runtime:")
+	fmt.Fprintf(b, "  Version: %s\n", rt.Version)
+	if rt.CgroupCPUs > 0 {
+		fmt.Fprintf(b, "  GOMAXPROCS: %d (NumCPU: %d, cgroup quota: %d)\n", rt.GOMAXPROCS, rt.NumCPU, rt.CgroupCPUs)
+	} else {
+		fmt.Fprintf(b, "  GOMAXPROCS: %d (NumCPU: %d)\n", rt.GOMAXPROCS, rt.NumCPU)
+	}
+	if rt.MemoryLimitBytes < 0 {
+		fmt.Fprintln(b, "  GOMEMLIMIT: unlimited")
+	} else {
+		fmt.Fprintf(b, "  GOMEMLIMIT: %d MB\n", rt.MemoryLimitBytes/1024/1024)
+	}
+	if rt.GCPercent < 0 {
+		fmt.Fprintln(b, "  GOGC: off")
+	} else {
+		fmt.Fprintf(b, "  GOGC: %d\n", rt.GCPercent)
+	}
+	fmt.Fprintf(b, "  Goroutines: %d\n", rt.NumGoroutine)
+}
+
+func formatSecurityInfo(b *strings.Builder, sec *internalmeta.SecurityInfo) {
+	fmt.Fprintln(b, "Security:")
+	fmt.Fprintf(b, "  User: %s (uid %d, root: %t)\n", valueOrUnknown(sec.User), sec.UID, sec.IsRoot)
+	fmt.Fprintf(b, "  SELinux: %s\n", sec.SELinux)
+	if sec.AppArmorProfile != "" {
+		fmt.Fprintf(b, "  AppArmor: %s (profile: %s)\n", sec.AppArmor, sec.AppArmorProfile)
+	} else {
+		fmt.Fprintf(b, "  AppArmor: %s\n", sec.AppArmor)
+	}
+	fmt.Fprintf(b, "  Secure Boot: %s\n", sec.SecureBoot)
+
+	if len(sec.Sysctls) > 0 {
+		fmt.Fprintln(b, "  Sysctls:")
+		keys := make([]string, 0, len(sec.Sysctls))
+		for key := range sec.Sysctls {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(b, "    %s = %s\n", key, sec.Sysctls[key])
+		}
+	}
+
+	if len(sec.Ulimits) > 0 {
+		fmt.Fprintln(b, "  Ulimits (soft/hard):")
+		for _, limit := range sec.Ulimits {
+			fmt.Fprintf(b, "    %s: %s/%s\n", limit.Name, limit.Soft, limit.Hard)
+		}
+	}
+}
+
+// formatDiskHealth renders a short health annotation for a storage line.
+// Inconclusive results are omitted to keep the common case uncluttered.
+func formatDiskHealth(health *internalmeta.DiskHealth) string {
+	if health == nil {
+		return ""
+	}
+	switch health.Status {
+	case internalmeta.HealthPassed:
+		return " [health: passed]"
+	case internalmeta.HealthFailing:
+		return " [health: FAILING]"
+	}
+	return ""
+}
+
+func valueOrUnknown(s string) string {
+	if s == "" {
+		return "unknown"
+	}
+	return s
+}
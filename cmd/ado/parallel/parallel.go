@@ -0,0 +1,134 @@
+package parallel
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalparallel "github.com/anowarislam/ado/internal/parallel"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// NewCommand returns the parallel command.
+func NewCommand() *cobra.Command {
+	var (
+		jobs     int
+		failFast bool
+		output   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "parallel [ITEM...] -- COMMAND [ARG...]",
+		Short: "Run a command once per item, several at a time",
+		Long: `Run COMMAND once per item with at most --jobs running at once, like
+xargs -P. Items are the arguments before --, or the non-empty lines of
+stdin when there are none.
+
+Every {} in the command is replaced by the item. Without {}, the item is
+appended as the last argument.
+
+Each command's output is captured and printed in one piece when it
+finishes, so output from different items never interleaves. By default
+every item runs and ado exits 1 if any failed; with --fail-fast the first
+failure cancels the commands still running and skips the rest.
+
+With --output json or yaml, nothing is streamed: ado prints a report with
+each item's status, exit code, duration, and output.
+
+Examples:
+  # Compress every log file, four at a time
+  ls *.log | ado parallel -j 4 -- gzip
+
+  # Items as arguments, with the item in the middle of the command
+  ado parallel api web worker -- docker build -t app-{} ./services/{}
+
+  # Stop at the first failing test package
+  go list ./... | ado parallel --fail-fast -- go test {}
+
+  # Structured report for CI
+  ado parallel a b c -o json -- ./check.sh`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			if dash := cmd.ArgsLenAtDash(); dash < 0 || dash == len(args) {
+				return fmt.Errorf("missing command: use ado parallel [ITEM...] -- COMMAND [ARG...]")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			if jobs < 1 {
+				return fmt.Errorf("--jobs must be at least 1")
+			}
+
+			dash := cmd.ArgsLenAtDash()
+			items, template := args[:dash], args[dash:]
+			if len(items) == 0 {
+				if items, err = readItems(cmd.InOrStdin()); err != nil {
+					return err
+				}
+			}
+
+			ctx := cmd.Context()
+
+			opts := internalparallel.Options{Jobs: jobs, FailFast: failFast}
+			if format == ui.OutputText {
+				opts.OnDone = func(r internalparallel.Result) {
+					_, _ = io.WriteString(cmd.OutOrStdout(), r.Stdout)
+					_, _ = io.WriteString(cmd.ErrOrStderr(), r.Stderr)
+					if r.Status != internalparallel.StatusOK {
+						fmt.Fprintf(cmd.ErrOrStderr(), "%s: %s (%s)\n", r.Status, r.Item, r.Error)
+					}
+				}
+			}
+			report := internalparallel.Run(ctx, items, template, opts)
+
+			if format == ui.OutputText {
+				fmt.Fprintln(cmd.ErrOrStderr(), formatSummary(report))
+			} else if err := ui.PrintOutput(cmd.OutOrStdout(), format, report, nil); err != nil {
+				return err
+			}
+			if report.Succeeded < report.Total {
+				return ui.Reported(fmt.Errorf("%d of %d items did not succeed", report.Total-report.Succeeded, report.Total))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVarP(&jobs, "jobs", "j", runtime.NumCPU(), "Maximum number of commands running at once")
+	cmd.Flags().BoolVar(&failFast, "fail-fast", false, "Cancel running commands and skip the rest after the first failure")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+// readItems returns the non-empty lines of r, trimmed.
+func readItems(r io.Reader) ([]string, error) {
+	var items []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		if item := strings.TrimSpace(scanner.Text()); item != "" {
+			items = append(items, item)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read items from stdin: %w", err)
+	}
+	return items, nil
+}
+
+func formatSummary(r internalparallel.Report) string {
+	summary := fmt.Sprintf("%d succeeded, %d failed", r.Succeeded, r.Failed)
+	if r.Canceled > 0 {
+		summary += fmt.Sprintf(", %d canceled", r.Canceled)
+	}
+	if r.Skipped > 0 {
+		summary += fmt.Sprintf(", %d skipped", r.Skipped)
+	}
+	return fmt.Sprintf("%s (%d items)", summary, r.Total)
+}
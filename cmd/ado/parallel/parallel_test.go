@@ -0,0 +1,100 @@
+package parallel
+
+import (
+	"bytes"
+	"encoding/json"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	internalparallel "github.com/anowarislam/ado/internal/parallel"
+)
+
+func execute(stdin string, args ...string) (stdout, stderr string, err error) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewCommand())
+	var out, errOut bytes.Buffer
+	root.SetIn(strings.NewReader(stdin))
+	root.SetOut(&out)
+	root.SetErr(&errOut)
+	root.SetArgs(append([]string{"parallel"}, args...))
+	err = root.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func TestParallelCommand_Stdin(t *testing.T) {
+	stdout, stderr, err := execute("a\n\n  b  \n", "-j", "1", "--", "echo", "item={}")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if stdout != "item=a\nitem=b\n" {
+		t.Errorf("stdout = %q", stdout)
+	}
+	if stderr != "2 succeeded, 0 failed (2 items)\n" {
+		t.Errorf("stderr = %q", stderr)
+	}
+}
+
+func TestParallelCommand_Failure(t *testing.T) {
+	stdout, stderr, err := execute("", "ok", "bad", "-j", "1", "--", "sh", "-c", `echo $0; [ $0 = ok ]`)
+	if err == nil || err.Error() != "1 of 2 items did not succeed" {
+		t.Errorf("Execute() error = %v", err)
+	}
+	if stdout != "ok\nbad\n" {
+		t.Errorf("stdout = %q", stdout)
+	}
+	for _, want := range []string{"failed: bad (exit status 1)\n", "1 succeeded, 1 failed (2 items)\n"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("stderr missing %q:\n%s", want, stderr)
+		}
+	}
+}
+
+func TestParallelCommand_JSON(t *testing.T) {
+	stdout, stderr, err := execute("", "x", "y", "-o", "json", "--", "echo")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if stderr != "" {
+		t.Errorf("stderr = %q, want nothing outside the report", stderr)
+	}
+	var report internalparallel.Report
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, stdout)
+	}
+	if report.Total != 2 || report.Succeeded != 2 || report.Results[1].Stdout != "y\n" {
+		t.Errorf("report = %+v", report)
+	}
+	if !slices.Equal(report.Results[0].Command, []string{"echo", "x"}) {
+		t.Errorf("command = %q", report.Results[0].Command)
+	}
+}
+
+func TestParallelCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no dash", []string{"a", "b"}, "missing command"},
+		{"empty command", []string{"a", "--"}, "missing command"},
+		{"bad jobs", []string{"-j", "0", "a", "--", "echo"}, "--jobs must be at least 1"},
+		{"bad output", []string{"-o", "xml", "a", "--", "echo"}, "xml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := execute("", tt.args...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatSummary(t *testing.T) {
+	got := formatSummary(internalparallel.Report{Total: 5, Succeeded: 1, Failed: 1, Canceled: 1, Skipped: 2})
+	if want := "1 succeeded, 1 failed, 1 canceled, 2 skipped (5 items)"; got != want {
+		t.Errorf("formatSummary() = %q, want %q", got, want)
+	}
+}
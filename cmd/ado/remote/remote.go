@@ -0,0 +1,303 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	"github.com/anowarislam/ado/internal/inventory"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	internalremote "github.com/anowarislam/ado/internal/remote"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// options are the connection flags shared by the subcommands.
+type options struct {
+	jobs       int
+	upload     bool
+	binary     string
+	sshOptions []string
+	inventory  string
+	hosts      []string
+}
+
+// targets resolves the hosts to contact: the HOST arguments, with their
+// inventory settings when the inventory has them, then the hosts --hosts
+// selects. The inventory is only required with --hosts or --inventory.
+func (o *options) targets(args []string) ([]inventory.Host, error) {
+	homeDir, _ := os.UserHomeDir()
+	path := inventory.Path(o.inventory, homeDir)
+	inv, err := inventory.Load(path)
+	if errors.Is(err, fs.ErrNotExist) && len(o.hosts) == 0 && o.inventory == "" && os.Getenv(inventory.EnvVar) == "" {
+		inv, err = &inventory.Inventory{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []inventory.Host
+	seen := map[string]bool{}
+	add := func(h inventory.Host) {
+		if !seen[h.Name] {
+			seen[h.Name] = true
+			targets = append(targets, h)
+		}
+	}
+	for _, arg := range args {
+		h, ok := inv.Host(arg)
+		if !ok {
+			h = inventory.Host{Name: arg, Address: arg}
+		}
+		add(h)
+	}
+	if len(o.hosts) > 0 {
+		selected, err := inv.Select(o.hosts)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, h := range selected {
+			add(h)
+		}
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("no hosts given: pass HOST arguments or --hosts")
+	}
+	return targets, nil
+}
+
+// fanOut runs fn against each target with a client set up for it.
+func (o *options) fanOut(ctx context.Context, targets []inventory.Host, fn func(ctx context.Context, client *internalremote.Client, destination string, r *internalremote.HostResult) error) []internalremote.HostResult {
+	byName := make(map[string]inventory.Host, len(targets))
+	names := make([]string, len(targets))
+	for i, h := range targets {
+		byName[h.Name] = h
+		names[i] = h.Name
+	}
+	return internalremote.FanOut(ctx, names, o.jobs, func(ctx context.Context, r *internalremote.HostResult) error {
+		h := byName[r.Host]
+		client := &internalremote.Client{
+			Options: append(h.SSHArgs(), o.sshOptions...),
+			Binary:  o.binary,
+			Upload:  o.upload,
+		}
+		return fn(ctx, client, h.Destination(), r)
+	})
+}
+
+// NewCommand returns the remote parent command with subcommands.
+func NewCommand() *cobra.Command {
+	var opts options
+
+	cmd := &cobra.Command{
+		Use:   "remote",
+		Short: "Run ado diagnostics on other hosts over SSH",
+		Long: `Collect 'ado meta' diagnostics from other hosts over SSH, in the same
+formats as running them locally, from many hosts at once.
+
+Hosts are anything ssh accepts: a name from ~/.ssh/config, host, or
+user@host. Hosts of the inventory (see 'ado inventory') are reached with
+their address, user, port, and ssh options, and --hosts selects them by
+name, glob, group, or label. The ssh client on PATH is used, so keys,
+the agent, known_hosts, and ProxyJump work as usual; ssh runs in batch
+mode and never prompts.
+
+The remote host's own ado is run when it is on its PATH. With --upload,
+hosts without it get a temporary copy of this binary, streamed over the
+connection, run once, and deleted; the host's OS and architecture must
+match this binary's.`,
+	}
+
+	cmd.PersistentFlags().IntVarP(&opts.jobs, "jobs", "j", internalremote.DefaultJobs, "Maximum number of hosts contacted at once")
+	cmd.PersistentFlags().BoolVar(&opts.upload, "upload", false, "Run a temporary copy of this binary on hosts without ado")
+	cmd.PersistentFlags().StringVar(&opts.binary, "binary", "ado", "ado command on the remote hosts")
+	cmd.PersistentFlags().StringArrayVar(&opts.sshOptions, "ssh-option", nil, `Extra ssh argument, such as "-p2222" or "-oConnectTimeout=5" (repeatable)`)
+	cmd.PersistentFlags().StringVar(&opts.inventory, "inventory", "", "Inventory file (default: $ADO_INVENTORY or inventory.yaml in the config directory)")
+	cmd.PersistentFlags().StringSliceVar(&opts.hosts, "hosts", nil, "Inventory hosts to contact: names, globs such as web-*, groups, or KEY=VALUE labels")
+	_ = cmd.MarkPersistentFlagFilename("inventory", "yaml", "yml")
+	_ = cmd.RegisterFlagCompletionFunc("hosts", completion.InventoryHosts("inventory"))
+
+	cmd.AddCommand(
+		newSystemCommand(&opts),
+		newEnvCommand(&opts),
+	)
+	return cmd
+}
+
+func newSystemCommand(opts *options) *cobra.Command {
+	var (
+		output    string
+		sections  []string
+		fast      bool
+		noNetwork bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "system [HOST...]",
+		Short: "Show system diagnostics of remote hosts",
+		Long: `Run 'ado meta system' on each host and print the results.
+
+With one host the output is what 'ado meta system' prints there. With
+several, text output has a "==> HOST <==" header per host, and JSON and
+YAML output is {"hosts": [{"host", "system", "error"}]} in argument
+order. ado exits 1 when any host fails, after printing the others.
+
+Examples:
+  # One host
+  ado remote system web-1
+
+  # Memory of a fleet, eight hosts at a time
+  ado remote system web-1 web-2 db-1 --sections memory -o json | jq '.hosts[] | {host, used: .system.memory.used_percent}'
+
+  # Every host of the web group in the inventory
+  ado remote system --hosts web
+
+  # A host without ado installed
+  ado remote system ops@10.0.4.12 --upload`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			targets, err := opts.targets(args)
+			if err != nil {
+				return err
+			}
+			var flags []string
+			if len(sections) > 0 {
+				flags = append(flags, "--sections", strings.Join(sections, ","))
+			}
+			if fast {
+				flags = append(flags, "--fast")
+			}
+			if noNetwork {
+				flags = append(flags, "--no-network")
+			}
+
+			results := opts.fanOut(cmd.Context(), targets, func(ctx context.Context, client *internalremote.Client, destination string, r *internalremote.HostResult) error {
+				if format == ui.OutputText {
+					return runText(ctx, client, destination, r, append([]string{"meta", "system"}, flags...))
+				}
+				info, err := client.SystemInfo(ctx, destination, flags)
+				r.System = info
+				return err
+			})
+			return printResults(cmd, format, results, func(r internalremote.HostResult) any { return r.System })
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().StringSliceVar(&sections, "sections", nil, "Collect only these sections: "+strings.Join(internalmeta.SystemSections, ", "))
+	cmd.Flags().BoolVar(&fast, "fast", false, "Skip the GPU and NPU probes")
+	cmd.Flags().BoolVar(&noNetwork, "no-network", false, "Skip cloud metadata and NTP lookups on the remote hosts")
+	cmd.MarkFlagsMutuallyExclusive("sections", "fast")
+	_ = cmd.RegisterFlagCompletionFunc("sections", completion.Fixed(internalmeta.SystemSections...))
+	return cmd
+}
+
+func newEnvCommand(opts *options) *cobra.Command {
+	var (
+		output  string
+		explain bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "env [HOST...]",
+		Short: "Show the ado configuration and environment of remote hosts",
+		Long: `Run 'ado meta env' on each host and print the results: the config file
+ado resolves there, its directories, and environment variables, with
+secrets masked.
+
+Output follows 'ado remote system': one host prints what 'ado meta env'
+prints, several are grouped per host.
+
+Examples:
+  # Why does the job on build-3 not see the config?
+  ado remote env build-3 --explain`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			targets, err := opts.targets(args)
+			if err != nil {
+				return err
+			}
+			var flags []string
+			if explain {
+				flags = append(flags, "--explain")
+			}
+
+			results := opts.fanOut(cmd.Context(), targets, func(ctx context.Context, client *internalremote.Client, destination string, r *internalremote.HostResult) error {
+				if format == ui.OutputText {
+					return runText(ctx, client, destination, r, append([]string{"meta", "env"}, flags...))
+				}
+				info, err := client.EnvInfo(ctx, destination, flags)
+				r.Env = info
+				return err
+			})
+			return printResults(cmd, format, results, func(r internalremote.HostResult) any { return r.Env })
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	cmd.Flags().BoolVar(&explain, "explain", false, "Include the config resolution trace")
+	return cmd
+}
+
+func runText(ctx context.Context, client *internalremote.Client, destination string, r *internalremote.HostResult, args []string) error {
+	out, err := client.Run(ctx, destination, append(args, "--output", "text"))
+	r.Text = string(out)
+	return err
+}
+
+// printResults prints one host's document as is, or every host's grouped
+// under "hosts", and reports failed hosts.
+func printResults(cmd *cobra.Command, format ui.OutputFormat, results []internalremote.HostResult, document func(internalremote.HostResult) any) error {
+	w := cmd.OutOrStdout()
+	if len(results) == 1 {
+		r := results[0]
+		if r.Error != "" {
+			return fmt.Errorf("%s", r.Error)
+		}
+		if format == ui.OutputText {
+			_, err := fmt.Fprint(w, r.Text)
+			return err
+		}
+		return ui.PrintOutput(w, format, document(r), nil)
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	err := ui.PrintOutput(w, format, map[string][]internalremote.HostResult{"hosts": results}, func() (string, error) {
+		var b strings.Builder
+		for i, r := range results {
+			if i > 0 {
+				b.WriteString("\n")
+			}
+			fmt.Fprintf(&b, "==> %s <==\n", r.Host)
+			if r.Error != "" {
+				fmt.Fprintf(&b, "error: %s\n", r.Error)
+				continue
+			}
+			b.WriteString(r.Text)
+		}
+		return strings.TrimSuffix(b.String(), "\n"), nil
+	})
+	if err != nil {
+		return err
+	}
+	if failed > 0 {
+		return ui.Reported(fmt.Errorf("%d of %d hosts failed", failed, len(results)))
+	}
+	return nil
+}
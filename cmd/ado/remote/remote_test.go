@@ -0,0 +1,155 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// fakeSSH is an ssh stand-in: host "down" is unreachable, any other host
+// answers with its destination as the OS in JSON mode, and as text with
+// the ssh options otherwise.
+const fakeSSH = `#!/bin/sh
+opts=
+while [ "$1" != "--" ]; do opts="$opts $1"; shift; done
+case "$2" in
+down) echo "ssh: connect to host down port 22: Connection refused" >&2; exit 255;;
+esac
+case "$3" in
+*"--output json") echo '{"os":"'"$2"'"}';;
+*) echo "OS: $2 ($opts )";;
+esac
+`
+
+const testInventory = `version: 1
+defaults: {user: ops}
+hosts:
+  web-1: {address: 10.0.1.11}
+  web-2: {address: 10.0.1.12, port: 2222}
+  db-1: {}
+groups:
+  web: {hosts: [web-*]}
+`
+
+func newTestRoot(t *testing.T) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ssh is a shell script")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ssh"), []byte(fakeSSH), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("ADO_INVENTORY", "")
+
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewCommand())
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	return root, &buf
+}
+
+func TestRemoteSystem_SingleHost(t *testing.T) {
+	root, buf := newTestRoot(t)
+	root.SetArgs([]string{"remote", "system", "web-1", "-o", "json"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	var info struct {
+		OS string `json:"os"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &info); err != nil || info.OS != "web-1" {
+		t.Errorf("output = %s (%v)", buf.String(), err)
+	}
+}
+
+func TestRemoteSystem_ManyHosts(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"text", "==> web-1 <==\nOS: web-1 ( -o BatchMode=yes )\n\n==> down <==\nerror: down: ssh: ssh: connect to host down port 22: Connection refused\n\n==> web-2 <==\nOS: web-2 ( -o BatchMode=yes )\n"},
+		{"json", `"host": "down"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			root, buf := newTestRoot(t)
+			root.SetArgs([]string{"remote", "system", "web-1", "down", "web-2", "-j", "2", "-o", tt.output})
+			err := root.Execute()
+			if !ui.IsReported(err) || !strings.Contains(err.Error(), "1 of 3 hosts failed") {
+				t.Errorf("error = %v, want 1 of 3 hosts failed", err)
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("output = %q, want it to contain %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestRemoteEnv_Unreachable(t *testing.T) {
+	root, _ := newTestRoot(t)
+	root.SetArgs([]string{"remote", "env", "down"})
+	err := root.Execute()
+	if err == nil || !strings.Contains(err.Error(), "Connection refused") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestRemoteSystem_Inventory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "inventory.yaml")
+	if err := os.WriteFile(path, []byte(testInventory), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		args []string
+		want string
+		err  string
+	}{
+		{
+			[]string{"--hosts", "web", "--ssh-option", "-q"},
+			"==> web-1 <==\nOS: ops@10.0.1.11 ( -o BatchMode=yes -q )\n\n==> web-2 <==\nOS: ops@10.0.1.12 ( -o BatchMode=yes -p 2222 -q )\n",
+			"",
+		},
+		// Arguments come first; hosts outside the inventory are used as is.
+		{[]string{"db-1", "other", "--hosts", "db-*"}, "==> db-1 <==\nOS: ops@db-1 ( -o BatchMode=yes )\n\n==> other <==\nOS: other ( -o BatchMode=yes )\n", ""},
+		{[]string{"--hosts", "cache"}, "", `no hosts match "cache"`},
+		{nil, "", "no hosts given"},
+	}
+	for _, tt := range tests {
+		root, buf := newTestRoot(t)
+		root.SetArgs(append([]string{"remote", "system", "--inventory", path}, tt.args...))
+		err := root.Execute()
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("%v: error = %v, want %q", tt.args, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %v", tt.args, err)
+		}
+		if buf.String() != tt.want {
+			t.Errorf("%v: output = %q, want %q", tt.args, buf.String(), tt.want)
+		}
+	}
+}
+
+func TestRemoteSystem_MissingInventory(t *testing.T) {
+	root, _ := newTestRoot(t)
+	root.SetArgs([]string{"remote", "system", "--hosts", "web"})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "read inventory") {
+		t.Errorf("error = %v, want the missing inventory", err)
+	}
+}
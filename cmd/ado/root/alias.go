@@ -0,0 +1,114 @@
+package root
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalalias "github.com/anowarislam/ado/internal/alias"
+	internalconfig "github.com/anowarislam/ado/internal/config"
+)
+
+// expandAlias replaces a user-defined alias in args with its expansion
+// from the `aliases:` section of the config file, keeping the arguments
+// around it. The alias is the first argument that is not a global flag or
+// a flag value. Built-in commands always take precedence, so the config
+// is only read for names root does not know.
+func expandAlias(root *cobra.Command, args []string) ([]string, error) {
+	i, global := commandIndex(root, args)
+	if i >= 0 && (args[i] == cobra.ShellCompRequestCmd || args[i] == cobra.ShellCompNoDescRequestCmd) {
+		// Completion requests carry the command line being completed;
+		// its last word is still being typed.
+		j, _ := commandIndex(root, args[i+1:])
+		i = i + 1 + j
+		if j < 0 || i == len(args)-1 {
+			return args, nil
+		}
+	}
+	if i < 0 || internalalias.Shadows(root, args[i]) {
+		return args, nil
+	}
+
+	homeDir, _ := os.UserHomeDir()
+	cfg, _, err := global.resolver(homeDir).Load()
+	if err != nil {
+		return nil, err
+	}
+	expansion, ok := cfg.Aliases[args[i]]
+	if !ok {
+		return args, nil
+	}
+	words, err := internalalias.Parse(root, expansion)
+	if err != nil {
+		return nil, fmt.Errorf("alias %q: %w", args[i], err)
+	}
+	return slices.Concat(args[:i], words, args[i+1:]), nil
+}
+
+// globalFlags are the global flags that decide how the config is read,
+// as seen before the command line is parsed.
+type globalFlags struct {
+	config    string
+	noExpand  bool
+	strictEnv bool
+}
+
+// resolver returns a config resolver for the flags.
+func (g globalFlags) resolver(homeDir string) *internalconfig.PathResolver {
+	return internalconfig.NewPathResolver(g.config, homeDir).SetExpansion(expansionMode(g.noExpand, g.strictEnv))
+}
+
+// expansionMode returns the config expansion mode for --no-expand and
+// --strict-env, defaulting to that of $ADO_CONFIG_EXPAND.
+func expansionMode(noExpand, strictEnv bool) string {
+	switch {
+	case noExpand:
+		return internalconfig.ExpandOff
+	case strictEnv:
+		return internalconfig.ExpandStrict
+	}
+	return internalconfig.DefaultExpansion()
+}
+
+// commandIndex returns the index of the first argument that is not a
+// global flag or its value, or -1 when there is none or an unknown flag
+// comes first. It also returns the global flags that were seen.
+func commandIndex(root *cobra.Command, args []string) (index int, global globalFlags) {
+	flags := root.PersistentFlags()
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			return i, global
+		}
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		flag := flags.Lookup(name)
+		if !strings.HasPrefix(arg, "--") {
+			flag = nil
+			if len(name) == 1 {
+				flag = flags.ShorthandLookup(name)
+			}
+		}
+		if flag == nil {
+			return -1, global
+		}
+		if !hasValue && flag.NoOptDefVal == "" {
+			i++
+			if i == len(args) {
+				break
+			}
+			value = args[i]
+		}
+		switch flag.Name {
+		case "config":
+			global.config = value
+		case "no-expand":
+			global.noExpand = value == "" || value == "true"
+		case "strict-env":
+			global.strictEnv = value == "" || value == "true"
+		}
+	}
+	return -1, global
+}
@@ -0,0 +1,120 @@
+package root
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func writeAliasConfig(t *testing.T, aliases string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("version: 1\naliases:\n"+aliases), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestExpandAlias(t *testing.T) {
+	path := writeAliasConfig(t, "  sys: meta system --output json\n  hi: \"echo 'hello world'\"\n  echo: id uuid\n")
+	t.Setenv("ADO_CONFIG", "")
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"no args", nil, nil},
+		{"alias", []string{"--config", path, "sys"}, []string{"--config", path, "meta", "system", "--output", "json"}},
+		{"global flags before", []string{"--config=" + path, "--log-level", "debug", "hi", "again"}, []string{"--config=" + path, "--log-level", "debug", "echo", "hello world", "again"}},
+		{"built-in wins", []string{"--config", path, "echo", "x"}, []string{"--config", path, "echo", "x"}},
+		{"unknown name", []string{"--config", path, "nope"}, []string{"--config", path, "nope"}},
+		{"alias after command", []string{"--config", path, "echo", "sys"}, []string{"--config", path, "echo", "sys"}},
+		{"unknown flag first", []string{"--bogus", "sys"}, []string{"--bogus", "sys"}},
+		{"completion", []string{"--config", path, "__complete", "sys", ""}, []string{"--config", path, "__complete", "meta", "system", "--output", "json", ""}},
+		{"completing the alias name", []string{"--config", path, "__complete", "sys"}, []string{"--config", path, "__complete", "sys"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := expandAlias(NewRootCommand(), tt.args)
+			if err != nil {
+				t.Fatalf("expandAlias() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("expandAlias() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandAlias_FromEnvironment(t *testing.T) {
+	t.Setenv("ADO_CONFIG", writeAliasConfig(t, "  greet: echo hi\n"))
+
+	root := NewRootCommand()
+	args, err := expandAlias(root, []string{"greet", "there"})
+	if err != nil {
+		t.Fatalf("expandAlias() error = %v", err)
+	}
+	var out bytes.Buffer
+	root.SetOut(&out)
+	if err := execute(context.Background(), root, args); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if out.String() != "hi there\n" {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+func TestExpandAlias_Errors(t *testing.T) {
+	path := writeAliasConfig(t, "  loop: loop again\n  bad: \"echo 'open\"\n")
+	for name, want := range map[string]string{
+		"loop": `alias "loop": expansion must start with an ado command, not "loop"`,
+		"bad":  `alias "bad": unterminated ' quote`,
+	} {
+		if _, err := expandAlias(NewRootCommand(), []string{"--config", path, name}); err == nil || err.Error() != want {
+			t.Errorf("expandAlias(%s) error = %v, want %q", name, err, want)
+		}
+	}
+
+	broken := filepath.Join(t.TempDir(), "broken.yaml")
+	if err := os.WriteFile(broken, []byte("version: ["), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := expandAlias(NewRootCommand(), []string{"--config", broken, "sys"}); err == nil || !strings.Contains(err.Error(), "parse config") {
+		t.Errorf("expandAlias() with broken config error = %v", err)
+	}
+	if _, err := expandAlias(NewRootCommand(), []string{"--config", broken, "echo", "hi"}); err != nil {
+		t.Errorf("expandAlias() read the config for a built-in: %v", err)
+	}
+}
+
+func TestExpandAlias_Expansion(t *testing.T) {
+	path := writeAliasConfig(t, "  greet: echo ${ADO_TEST_GREETING:-hi}\n  who: echo ${ADO_TEST_UNSET}\n")
+	t.Setenv("ADO_TEST_GREETING", "hello")
+	t.Setenv("ADO_CONFIG_EXPAND", "")
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"--config", path, "greet"}, []string{"--config", path, "echo", "hello"}},
+		{[]string{"--config", path, "--no-expand", "greet"}, []string{"--config", path, "--no-expand", "echo", "${ADO_TEST_GREETING:-hi}"}},
+		{[]string{"--config", path, "who", "x"}, []string{"--config", path, "echo", "x"}},
+	}
+	for _, tt := range tests {
+		got, err := expandAlias(NewRootCommand(), tt.args)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("expandAlias(%q) = %q, %v, want %q", tt.args, got, err, tt.want)
+		}
+	}
+
+	_, err := expandAlias(NewRootCommand(), []string{"--strict-env", "--config", path, "who"})
+	if err == nil || !strings.Contains(err.Error(), "undefined environment variable ADO_TEST_UNSET") {
+		t.Errorf("expandAlias() with --strict-env error = %v", err)
+	}
+}
@@ -0,0 +1,261 @@
+package root
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+)
+
+// noDefaultFlags cannot be set from the defaults: section.
+var noDefaultFlags = []string{"config", "help", "version"}
+
+// noDefaultCommands are the command trees defaults are not applied to, so
+// a broken defaults: section can still be validated and worked around.
+var noDefaultCommands = []string{"config", "alias"}
+
+// loadDefaults applies the defaults: section of the config file resolver
+// reads to cmd, warning on warn about keys that name no command or flag.
+// It runs before logging is set up, so a config that cannot be loaded is
+// left for the commands that use it to report.
+func loadDefaults(cmd *cobra.Command, resolver *internalconfig.PathResolver, warn io.Writer) error {
+	if path := commandPath(cmd); len(path) > 0 && slices.Contains(noDefaultCommands, path[0]) {
+		return nil
+	}
+	cfg, _, err := resolver.Config()
+	if err != nil {
+		return nil
+	}
+	problems, err := applyDefaults(cmd, cfg.Defaults)
+	for _, problem := range problems {
+		fmt.Fprintf(warn, "warning: config %s\n", problem)
+	}
+	return err
+}
+
+// defaultsBeforeArgs makes the commands below c that validate their
+// arguments apply the defaults: section first, as cobra validates them
+// before PersistentPreRunE and some validators read flags: `ado watch`
+// needs --task or a command. Applying defaults twice is harmless; the
+// warnings are left for PersistentPreRunE to print once.
+func defaultsBeforeArgs(c *cobra.Command) {
+	for _, sub := range c.Commands() {
+		if validate := sub.Args; validate != nil && sub.Runnable() {
+			sub.Args = func(cmd *cobra.Command, args []string) error {
+				if err := loadDefaults(cmd, configResolver(cmd), io.Discard); err != nil {
+					return err
+				}
+				return validate(cmd, args)
+			}
+		}
+		defaultsBeforeArgs(sub)
+	}
+}
+
+// configResolver returns the config resolver for the root's --config,
+// --no-expand and --strict-env flags, reusing the one in cmd's context,
+// and stores it there for the command and everything it runs.
+func configResolver(cmd *cobra.Command) *internalconfig.PathResolver {
+	flags := cmd.Root().PersistentFlags()
+	configPath, _ := flags.GetString("config")
+	noExpand, _ := flags.GetBool("no-expand")
+	strictEnv, _ := flags.GetBool("strict-env")
+	homeDir, _ := os.UserHomeDir()
+	resolver := internalconfig.PathResolverFor(cmd.Context(), configPath, homeDir).SetExpansion(expansionMode(noExpand, strictEnv))
+	cmd.SetContext(internalconfig.WithPathResolver(cmd.Context(), resolver))
+	return resolver
+}
+
+// applyDefaults sets the flags of cmd that the command line left unset
+// from defaults, a tree keyed by command names with flag values at the
+// leaves:
+//
+//	defaults:
+//	  timeout: 5m          # every command
+//	  meta:
+//	    output: json       # every meta subcommand with --output
+//	    system:
+//	      sections: [cpu, memory]
+//
+// Values nearer to cmd win. Keys on the way to cmd that are neither a
+// subcommand nor a flag of some command below them are skipped and
+// returned as problems, so typos do not go unnoticed.
+func applyDefaults(cmd *cobra.Command, defaults map[string]any) ([]string, error) {
+	var problems []string
+	values := map[string]any{}
+	sources := map[string]string{}
+	level, node, prefix := cmd.Root(), defaults, "defaults"
+	for _, name := range commandPath(cmd) {
+		problems = append(problems, collectDefaults(level, node, prefix, values, sources)...)
+		next, ok := node[name].(map[string]any)
+		if !ok {
+			node = nil
+			break
+		}
+		level, node, prefix = subcommand(level, name), next, prefix+"."+name
+	}
+	if node != nil {
+		problems = append(problems, collectDefaults(level, node, prefix, values, sources)...)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(values)) {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+		if err := setDefault(flag, values[name]); err != nil {
+			return problems, fmt.Errorf("config %s.%s: %w", sources[name], name, err)
+		}
+	}
+	return problems, nil
+}
+
+// collectDefaults records the flag values in node, the defaults for the
+// command level, skipping the sections of its subcommands. It returns the
+// problems with the keys it skips.
+func collectDefaults(level *cobra.Command, node map[string]any, prefix string, values map[string]any, sources map[string]string) []string {
+	var problems []string
+	for _, key := range slices.Sorted(maps.Keys(node)) {
+		value := node[key]
+		if sub := subcommand(level, key); sub != nil {
+			if _, ok := value.(map[string]any); ok {
+				continue
+			}
+		}
+		if problem := defaultKeyProblem(level, prefix, key); problem != "" {
+			problems = append(problems, problem)
+			continue
+		}
+		values[key] = value
+		sources[key] = prefix
+	}
+	return problems
+}
+
+// defaultsProblems lists the keys of the defaults: section, anywhere in
+// the tree, that name no command or flag of the command tree below root.
+// `config validate` reports them.
+func defaultsProblems(root *cobra.Command, defaults map[string]any) []string {
+	return keyProblems(root, defaults, "defaults")
+}
+
+func keyProblems(level *cobra.Command, node map[string]any, prefix string) []string {
+	var problems []string
+	for _, key := range slices.Sorted(maps.Keys(node)) {
+		if sub := subcommand(level, key); sub != nil {
+			if next, ok := node[key].(map[string]any); ok {
+				problems = append(problems, keyProblems(sub, next, prefix+"."+key)...)
+				continue
+			}
+		}
+		if problem := defaultKeyProblem(level, prefix, key); problem != "" {
+			problems = append(problems, problem)
+		}
+	}
+	return problems
+}
+
+// defaultKeyProblem describes why key, at prefix in the defaults: section
+// for the command level, cannot be applied, or returns "" if it can.
+func defaultKeyProblem(level *cobra.Command, prefix, key string) string {
+	if slices.Contains(noDefaultFlags, key) {
+		return fmt.Sprintf("%s.%s: --%s cannot be set from the config file", prefix, key, key)
+	}
+	if !hasFlag(level, key) {
+		return fmt.Sprintf("%s.%s: '%s' has no command or flag named %q", prefix, key, level.CommandPath(), key)
+	}
+	return ""
+}
+
+// commandPath returns the names of cmd and its parents below the root.
+func commandPath(cmd *cobra.Command) []string {
+	var names []string
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		names = append([]string{c.Name()}, names...)
+	}
+	return names
+}
+
+// subcommand returns the direct subcommand of c called name, or nil.
+func subcommand(c *cobra.Command, name string) *cobra.Command {
+	if c == nil {
+		return nil
+	}
+	for _, sub := range c.Commands() {
+		if sub.Name() == name {
+			return sub
+		}
+	}
+	return nil
+}
+
+// hasFlag reports whether c or any command below it has a flag called
+// name, including inherited ones.
+func hasFlag(c *cobra.Command, name string) bool {
+	if c.Flags().Lookup(name) != nil || c.PersistentFlags().Lookup(name) != nil || c.InheritedFlags().Lookup(name) != nil {
+		return true
+	}
+	for _, sub := range c.Commands() {
+		if hasFlag(sub, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// setDefault sets flag to value without marking it changed, so the value
+// acts like the flag's built-in default. Required flags are the exception:
+// a configured default satisfies them.
+func setDefault(flag *pflag.Flag, value any) error {
+	var err error
+	if list, ok := value.([]any); ok {
+		items := make([]string, len(list))
+		for i, item := range list {
+			if items[i], err = scalarString(item); err != nil {
+				return err
+			}
+		}
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			err = slice.Replace(items)
+		} else {
+			err = flag.Value.Set(strings.Join(items, ","))
+		}
+	} else {
+		var s string
+		if s, err = scalarString(value); err == nil {
+			err = flag.Value.Set(s)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid value for --%s: %w", flag.Name, err)
+	}
+	if _, required := flag.Annotations[cobra.BashCompOneRequiredFlag]; required {
+		flag.Changed = true
+	}
+	return nil
+}
+
+// scalarString formats a YAML scalar as it would be typed on the command
+// line.
+func scalarString(v any) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("expected a string, number, boolean, or list of them, got %T", v)
+	}
+}
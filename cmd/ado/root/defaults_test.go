@@ -0,0 +1,261 @@
+package root
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultsTree returns a root with "meta system" and "echo" commands whose
+// flag values after parsing are reported through got.
+func defaultsTree(got map[string]string) *cobra.Command {
+	record := func(cmd *cobra.Command, _ []string) {
+		cmd.Flags().VisitAll(func(f *pflag.Flag) { got[f.Name] = f.Value.String() })
+	}
+	root := &cobra.Command{Use: "ado"}
+	root.PersistentFlags().String("config", "", "")
+	root.PersistentFlags().Duration("timeout", 0, "")
+
+	meta := &cobra.Command{Use: "meta"}
+	system := &cobra.Command{Use: "system", Run: record}
+	system.Flags().StringSlice("sections", nil, "")
+	system.Flags().StringP("output", "o", "text", "")
+	env := &cobra.Command{Use: "env", Run: record}
+	env.Flags().Bool("all", false, "")
+	meta.AddCommand(system, env)
+
+	echo := &cobra.Command{Use: "echo", Run: record}
+	echo.Flags().Int("repeat", 1, "")
+	echo.Flags().String("name", "", "")
+	_ = echo.MarkFlagRequired("name")
+
+	root.AddCommand(meta, echo)
+	return root
+}
+
+func TestApplyDefaults(t *testing.T) {
+	const config = `
+timeout: 5m
+echo:
+  repeat: 2
+  name: world
+meta:
+  output: yaml
+  system:
+    sections: [cpu, memory]
+    output: json
+`
+	var defaults map[string]any
+	if err := yaml.Unmarshal([]byte(config), &defaults); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want map[string]string
+	}{
+		{"nested list and nearest level wins", []string{"meta", "system"}, map[string]string{"sections": "[cpu,memory]", "output": "json", "timeout": "5m0s"}},
+		{"command line wins", []string{"meta", "system", "-o", "text", "--sections", "disk", "--timeout", "1s"}, map[string]string{"sections": "[disk]", "output": "text", "timeout": "1s"}},
+		{"scalar and required flag", []string{"echo"}, map[string]string{"repeat": "2", "name": "world"}},
+		{"flag missing on command", []string{"meta", "env"}, map[string]string{"all": "false", "timeout": "5m0s"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			root := defaultsTree(got)
+			root.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+				_, err := applyDefaults(cmd, defaults)
+				return err
+			}
+			root.SetArgs(tt.args)
+			if err := root.Execute(); err != nil {
+				t.Fatal(err)
+			}
+			for name, want := range tt.want {
+				if got[name] != want {
+					t.Errorf("--%s = %q, want %q", name, got[name], want)
+				}
+			}
+		})
+	}
+}
+
+func TestApplyDefaults_Problems(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  string
+		want    string
+		wantErr bool
+	}{
+		{"unknown flag", "echo:\n  reapeat: 2\n", `defaults.echo.reapeat: 'ado echo' has no command or flag named "reapeat"`, false},
+		{"unknown command", "mtea:\n  system:\n    output: json\n", `defaults.mtea: 'ado' has no command or flag named "mtea"`, false},
+		{"config flag", "config: other.yaml\n", "defaults.config: --config cannot be set from the config file", false},
+		{"bad value", "echo:\n  repeat: many\n", "config defaults.echo.repeat: invalid value for --repeat", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var defaults map[string]any
+			if err := yaml.Unmarshal([]byte(tt.config), &defaults); err != nil {
+				t.Fatal(err)
+			}
+			var problems []string
+			root := defaultsTree(map[string]string{})
+			root.PersistentPreRunE = func(cmd *cobra.Command, _ []string) error {
+				var err error
+				problems, err = applyDefaults(cmd, defaults)
+				return err
+			}
+			root.SetArgs([]string{"echo", "--name", "x"})
+			root.SilenceErrors, root.SilenceUsage = true, true
+			err := root.Execute()
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), tt.want) {
+					t.Errorf("error = %v, want %q", err, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v, want the key skipped", err)
+			}
+			if len(problems) != 1 || problems[0] != tt.want {
+				t.Errorf("problems = %q, want [%q]", problems, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultsProblems(t *testing.T) {
+	const config = `
+timeout: 5m
+colour: never
+echo:
+  repeat: 2
+meta:
+  output: json
+  system:
+    sections: [cpu]
+    sytem: true
+  env:
+    output: json
+`
+	var defaults map[string]any
+	if err := yaml.Unmarshal([]byte(config), &defaults); err != nil {
+		t.Fatal(err)
+	}
+	got := defaultsProblems(defaultsTree(map[string]string{}), defaults)
+	want := []string{
+		`defaults.colour: 'ado' has no command or flag named "colour"`,
+		`defaults.meta.env.output: 'ado meta env' has no command or flag named "output"`,
+		`defaults.meta.system.sytem: 'ado meta system' has no command or flag named "sytem"`,
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("defaultsProblems() = %q, want %q", got, want)
+	}
+}
+
+func TestRootCommand_Defaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("version: 1\ndefaults:\n  echo:\n    repeat: 2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runRoot(t, "--config", path, "echo", "hi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != "hi\nhi\n" {
+		t.Errorf("output = %q, want the message twice", out)
+	}
+}
+
+func TestRootCommand_UnknownDefaults(t *testing.T) {
+	t.Setenv("ADO_NO_UPDATE_CHECK", "1")
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := "version: 1\ndefaults:\n  colour: never\n  echo:\n    repeat: 2\n    reapeat: 3\n"
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	const (
+		rootProblem = `defaults.colour: 'ado' has no command or flag named "colour"`
+		echoProblem = `defaults.echo.reapeat: 'ado echo' has no command or flag named "reapeat"`
+	)
+
+	run := func(args ...string) (stdout, stderr string) {
+		t.Helper()
+		cmd := NewRootCommand()
+		var out, errOut bytes.Buffer
+		cmd.SetOut(&out)
+		cmd.SetErr(&errOut)
+		if err := execute(context.Background(), cmd, append([]string{"--config", path}, args...)); err != nil {
+			t.Fatalf("%v: error = %v", args, err)
+		}
+		return out.String(), errOut.String()
+	}
+
+	// Other commands warn and apply the keys that do exist.
+	out, errOut := run("echo", "hi")
+	if out != "hi\nhi\n" {
+		t.Errorf("echo output = %q, want the message twice", out)
+	}
+	if want := "warning: config " + rootProblem + "\nwarning: config " + echoProblem + "\n"; errOut != want {
+		t.Errorf("echo stderr = %q, want %q", errOut, want)
+	}
+
+	// config validate runs and reports the key.
+	out, _ = run("config", "validate", "-o", "json")
+	for _, key := range []string{"defaults.colour", "defaults.echo.reapeat"} {
+		if !strings.Contains(out, key) {
+			t.Errorf("config validate output = %s, want %s reported", out, key)
+		}
+	}
+	if !strings.Contains(out, `"rule": "unknown-key"`) {
+		t.Errorf("config validate output = %s, want unknown-key issues", out)
+	}
+
+	// The config and alias commands skip defaults, so they do not warn.
+	for _, args := range [][]string{{"config", "validate"}, {"alias", "list"}} {
+		if _, errOut := run(args...); errOut != "" {
+			t.Errorf("%v stderr = %q, want no warnings", args, errOut)
+		}
+	}
+}
+
+func TestDefaultsBeforeArgs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("version: 1\ndefaults:\n  watch:\n    task: build\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var task string
+	root := &cobra.Command{Use: "ado"}
+	root.PersistentFlags().String("config", "", "")
+	watch := &cobra.Command{
+		Use: "watch",
+		Args: func(cmd *cobra.Command, args []string) error {
+			if task == "" && len(args) == 0 {
+				return errors.New("a command after -- or --task is required")
+			}
+			return nil
+		},
+		Run: func(*cobra.Command, []string) {},
+	}
+	watch.Flags().StringVar(&task, "task", "", "")
+	root.AddCommand(watch)
+	defaultsBeforeArgs(root)
+
+	root.SetArgs([]string{"--config", path, "watch"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if task != "build" {
+		t.Errorf("--task = %q, want the configured default", task)
+	}
+}
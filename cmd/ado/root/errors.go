@@ -0,0 +1,109 @@
+package root
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// Error codes for failures without a more specific one.
+const (
+	codeError      = "error"
+	codeExitStatus = "exit_status"
+)
+
+// errorCoder is implemented by errors with a machine-readable code and
+// details for the structured error document.
+type errorCoder interface {
+	ErrorCode() string
+	ErrorDetails() map[string]any
+}
+
+// errorOutput is the document written to stdout for a failed command when
+// JSON or YAML output was requested.
+type errorOutput struct {
+	Error errorDetail `json:"error" yaml:"error"`
+}
+
+type errorDetail struct {
+	Code     string         `json:"code" yaml:"code"`
+	Message  string         `json:"message" yaml:"message"`
+	ExitCode int            `json:"exit_code" yaml:"exit_code"`
+	Details  map[string]any `json:"details,omitempty" yaml:"details,omitempty"`
+}
+
+// newErrorDetail describes err. Errors implementing errorCoder supply
+// their own code and details; other errors that set the exit status are
+// "exit_status" and the rest "error".
+func newErrorDetail(err error) errorDetail {
+	detail := errorDetail{Code: codeError, Message: err.Error(), ExitCode: exitCode(err)}
+	var (
+		coder errorCoder
+		exit  exitCoder
+	)
+	switch {
+	case errors.As(err, &coder):
+		detail.Code = coder.ErrorCode()
+		detail.Details = coder.ErrorDetails()
+	case errors.As(err, &exit):
+		detail.Code = codeExitStatus
+	}
+	// A usage error's suggestions are in details, not the message.
+	if usage, ok := err.(*UsageError); ok {
+		detail.Message = usage.Err.Error()
+	}
+	return detail
+}
+
+// printError writes err to w as an error document when JSON or YAML
+// output was requested, unless the command already wrote a result that
+// describes the failure (see ui.Reported). It reports whether it did.
+func printError(w io.Writer, cmd *cobra.Command, args []string, err error) bool {
+	if err == nil || ui.IsReported(err) {
+		return false
+	}
+	format, ferr := ui.ParseOutputFormat(requestedOutput(cmd, args))
+	if ferr != nil || format == ui.OutputText {
+		return false
+	}
+	return ui.PrintOutput(w, format, errorOutput{Error: newErrorDetail(err)}, nil) == nil
+}
+
+// requestedOutput returns the --output value of the command that ran, or
+// the one found in args when parsing stopped before flags were read.
+func requestedOutput(cmd *cobra.Command, args []string) string {
+	if cmd != nil {
+		if flag := cmd.Flags().Lookup("output"); flag != nil && flag.Changed {
+			return flag.Value.String()
+		}
+	}
+	return outputFlag(args)
+}
+
+// outputFlag returns the value of the last -o/--output in args. It reads
+// the arguments directly because an unknown command or flag stops cobra
+// before the flag is parsed.
+func outputFlag(args []string) string {
+	value := ""
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			return value
+		case arg == "-o" || arg == "--output":
+			if i+1 < len(args) {
+				i++
+				value = args[i]
+			}
+		case strings.HasPrefix(arg, "--output="):
+			value = strings.TrimPrefix(arg, "--output=")
+		case strings.HasPrefix(arg, "-o") && !strings.HasPrefix(arg, "--"):
+			value = strings.TrimPrefix(strings.TrimPrefix(arg, "-o"), "=")
+		}
+	}
+	return value
+}
@@ -0,0 +1,103 @@
+package root
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/anowarislam/ado/internal/tasks"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+func TestNewErrorDetail(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want errorDetail
+	}{
+		{"plain", errors.New("boom"), errorDetail{Code: "error", Message: "boom", ExitCode: 1}},
+		{"exit status", &tasks.ExitError{Task: "test", Code: 3}, errorDetail{Code: "exit_status", Message: `task "test" exited with status 3`, ExitCode: 3}},
+		{
+			"timeout",
+			&TimeoutError{Timeout: time.Second, Err: &tasks.ExitError{Task: "test", Code: 3}},
+			errorDetail{Code: "timeout", Message: `timed out after 1s: task "test" exited with status 3`, ExitCode: 124, Details: map[string]any{"timeout": "1s"}},
+		},
+		{
+			"interrupt",
+			&InterruptError{Signal: syscall.SIGINT},
+			errorDetail{Code: "interrupted", Message: "interrupted", ExitCode: 130, Details: map[string]any{"signal": "interrupt"}},
+		},
+		{
+			"usage",
+			&UsageError{Code: codeUnknownFlag, Name: "--colr", Suggestions: []string{"--color"}, Err: errors.New("unknown flag: --colr")},
+			errorDetail{Code: "unknown_flag", Message: "unknown flag: --colr", ExitCode: 1, Details: map[string]any{"name": "--colr", "suggestions": []string{"--color"}}},
+		},
+		{"wrapped", fmt.Errorf("outer: %w", errors.New("inner")), errorDetail{Code: "error", Message: "outer: inner", ExitCode: 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newErrorDetail(tt.err); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("newErrorDetail() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	err := errors.New("boom")
+	tests := []struct {
+		name string
+		args []string
+		err  error
+		want string
+	}{
+		{"text", []string{"meta"}, err, ""},
+		{"json", []string{"meta", "-o", "json"}, err, "{\n  \"error\": {\n    \"code\": \"error\",\n    \"message\": \"boom\",\n    \"exit_code\": 1\n  }\n}\n"},
+		{"yaml", []string{"meta", "--output=yaml"}, err, "error:\n    code: error\n    message: boom\n    exit_code: 1\n"},
+		{"unsupported format", []string{"diff", "-o", "unified"}, err, ""},
+		{"reported", []string{"meta", "-o", "json"}, ui.Reported(err), ""},
+		{"nil", []string{"meta", "-o", "json"}, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			printError(&buf, nil, tt.args, tt.err)
+			if got := buf.String(); got != tt.want {
+				t.Errorf("printError() wrote %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRootCommand_ErrorDocument(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runRoot(t, "--config", path, "run", "deploy", "-o", "json")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	var got errorOutput
+	if jerr := json.Unmarshal([]byte(out), &got); jerr != nil {
+		t.Fatalf("stdout is not JSON: %q", out)
+	}
+	if got.Error.Code != "error" || got.Error.Message != err.Error() || got.Error.ExitCode != 1 {
+		t.Errorf("error = %+v", got.Error)
+	}
+
+	// A failure the command's own report describes gets no second document.
+	out, err = runRoot(t, "parallel", "x", "-o", "json", "--", "sh", "-c", "exit 1", "{}")
+	if err == nil || strings.Contains(out, `"code"`) || !strings.Contains(out, `"succeeded": 0`) {
+		t.Errorf("parallel output = %q, error = %v", out, err)
+	}
+}
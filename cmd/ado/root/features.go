@@ -0,0 +1,74 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/features"
+)
+
+// experimentalFeatures gate the subsystems that ship compiled in but stay
+// hidden until the user opts in with `ado meta features enable NAME` or
+// $ADO_FEATURES.
+var experimentalFeatures = []features.Feature{
+	{Name: "devops", Description: "ado devops: Azure DevOps pipelines and work items", Stage: features.StageExperimental},
+	{Name: "mcp", Description: "ado mcp: Model Context Protocol server for AI assistants", Stage: features.StageExperimental},
+	{Name: "serve", Description: "ado serve: local REST and gRPC API", Stage: features.StageExperimental},
+	{Name: "workflow", Description: "ado workflow: multi-step YAML pipelines", Stage: features.StageExperimental},
+}
+
+func init() {
+	for _, f := range experimentalFeatures {
+		features.Default.Register(f)
+	}
+}
+
+// gateFeatures hides the commands gated behind features that are off, so
+// they stay out of help, completion, and suggestions. checkFeature keeps
+// them from running.
+func gateFeatures(ctx context.Context, root *cobra.Command, configPath string) {
+	config, env := featureToggles(ctx, configPath)
+
+	var walk func(*cobra.Command)
+	walk = func(c *cobra.Command) {
+		if name := c.Annotations[features.CommandAnnotation]; name != "" && !features.Default.Enabled(name, config, env) {
+			c.Hidden = true
+			return
+		}
+		for _, sub := range c.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+}
+
+// checkFeature refuses to run cmd when it is gated behind a feature that
+// is off.
+func checkFeature(cmd *cobra.Command) error {
+	name, gate := features.CommandFeature(cmd)
+	if gate == nil {
+		return nil
+	}
+	configPath, _ := cmd.Root().PersistentFlags().GetString("config")
+	if config, env := featureToggles(cmd.Context(), configPath); features.Default.Enabled(name, config, env) {
+		return nil
+	}
+	f, _ := features.Default.Lookup(name)
+	return fmt.Errorf("'%s' is behind the %s feature %q; enable it with 'ado meta features enable %s' or %s=%s",
+		gate.CommandPath(), f.Stage, name, name, features.EnvVar, name)
+}
+
+// featureToggles returns the feature toggles from the config file and
+// $ADO_FEATURES. A config that cannot be loaded counts as no toggles; the
+// commands that read it report the error.
+func featureToggles(ctx context.Context, configPath string) (config, env map[string]bool) {
+	homeDir, _ := os.UserHomeDir()
+	if cfg, _, err := internalconfig.PathResolverFor(ctx, configPath, homeDir).Config(); err == nil {
+		config = cfg.Features
+	}
+	return config, features.EnvToggles()
+}
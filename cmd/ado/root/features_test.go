@@ -0,0 +1,66 @@
+package root
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFeatureGate(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("ADO_CONFIG", "")
+	enabled := filepath.Join(home, "enabled.yaml")
+	if err := os.WriteFile(enabled, []byte("version: 1\nfeatures:\n  workflow: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		env     string
+		args    []string
+		wantErr string
+	}{
+		{"disabled by default", "", []string{"workflow", "run", "--help"}, ""},
+		{"refuses to run", "", []string{"workflow", "run", "missing.yaml"}, `'ado workflow' is behind the experimental feature "workflow"; enable it with 'ado meta features enable workflow' or ADO_FEATURES=workflow`},
+		{"enabled in config", "", []string{"--config", enabled, "workflow", "run", "missing.yaml"}, "missing.yaml"},
+		{"enabled in env", "workflow", []string{"workflow", "run", "missing.yaml"}, "missing.yaml"},
+		{"env wins over config", "-workflow", []string{"--config", enabled, "workflow", "run", "missing.yaml"}, "is behind the experimental feature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ADO_FEATURES", tt.env)
+			_, err := runRoot(t, tt.args...)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+			if strings.Contains(tt.wantErr, "missing.yaml") && strings.Contains(err.Error(), "experimental feature") {
+				t.Errorf("gated command did not run: %v", err)
+			}
+		})
+	}
+}
+
+func TestFeatureGate_Help(t *testing.T) {
+	t.Setenv("ADO_FEATURES", "serve")
+	out, err := runRoot(t, "--help")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "\n  serve ") {
+		t.Errorf("enabled command missing from help:\n%s", out)
+	}
+	for _, hidden := range []string{"\n  mcp ", "\n  workflow "} {
+		if strings.Contains(out, hidden) {
+			t.Errorf("help lists disabled command %q:\n%s", strings.TrimSpace(hidden), out)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/hooks"
+	"github.com/anowarislam/ado/internal/secrets"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// hooksKey holds the *hookRun of an invocation in its context once its
+// pre hooks passed, so that its post hooks run.
+type hooksKey struct{}
+
+// hookRun is what the post hooks of an invocation need.
+type hookRun struct {
+	hooks  map[string]internalconfig.Hook
+	path   string
+	runner hooks.Runner
+}
+
+// noHookCommands run no hooks: they do not act, or run while typing.
+var noHookCommands = []string{"help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+
+// runPreHooks runs the pre hooks of the hooks: section that apply to cmd
+// and returns ctx set up for its post hooks. A config that cannot be
+// loaded is left for the commands that use it to report.
+func runPreHooks(ctx context.Context, cmd *cobra.Command) (context.Context, error) {
+	path := commandPath(cmd)
+	if len(path) == 0 || !cmd.Runnable() || slices.Contains(noHookCommands, path[0]) || os.Getenv(hooks.EnvWhen) != "" {
+		return ctx, nil
+	}
+	configPath, _ := cmd.Root().PersistentFlags().GetString("config")
+	homeDir, _ := os.UserHomeDir()
+	cfg, _, err := internalconfig.PathResolverFor(ctx, configPath, homeDir).Config()
+	if err != nil || len(cfg.Hooks) == 0 {
+		return ctx, nil
+	}
+	if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+		slog.DebugContext(ctx, "Hooks skipped under --dry-run")
+		return ctx, nil
+	}
+
+	run := &hookRun{
+		hooks:  cfg.Hooks,
+		path:   strings.Join(path, " "),
+		runner: hooks.Runner{Output: cmd.ErrOrStderr(), Secrets: secrets.Default()},
+	}
+	if err := run.runner.Run(ctx, run.hooks, internalconfig.HookPre, run.path, nil); err != nil {
+		return ctx, err
+	}
+	return context.WithValue(ctx, hooksKey{}, run), nil
+}
+
+// runPostHooks runs the post hooks of cmd, which started at start and
+// ended with err, when its pre hooks ran, and returns the error ado ends
+// with. They run even when cmd was stopped by a signal or --timeout. A
+// post hook that fails under the fail policy fails a command that
+// succeeded; the failure of one that failed is only printed.
+func runPostHooks(cmd *cobra.Command, start time.Time, err error) error {
+	if cmd == nil || cmd.Context() == nil {
+		return err
+	}
+	run, ok := cmd.Context().Value(hooksKey{}).(*hookRun)
+	if !ok {
+		return err
+	}
+	outcome := &hooks.Outcome{Duration: time.Since(start)}
+	if err != nil {
+		outcome.ExitCode = exitCode(err)
+	}
+	hookErr := run.runner.Run(context.WithoutCancel(cmd.Context()), run.hooks, internalconfig.HookPost, run.path, outcome)
+	if err == nil {
+		return hookErr
+	}
+	if hookErr != nil {
+		fmt.Fprintln(cmd.ErrOrStderr(), hookErr)
+	}
+	return err
+}
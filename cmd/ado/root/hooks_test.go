@@ -0,0 +1,124 @@
+package root
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anowarislam/ado/internal/hooks"
+)
+
+// hooksConfig writes a config whose hooks append what they see to a log
+// file, and returns the config's and the log's paths.
+func hooksConfig(t *testing.T, extra string) (config, log string) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+	dir := t.TempDir()
+	log = filepath.Join(dir, "hooks.log")
+	config = filepath.Join(dir, "config.yaml")
+	content := `version: 1
+hooks:
+  audit:
+    when: pre
+    command: sh
+    args: [-c, 'echo "$ADO_HOOK $ADO_COMMAND" >> "$0"', ` + log + `]
+  notify:
+    when: post
+    command: sh
+    args: [-c, 'echo "$ADO_HOOK $ADO_COMMAND $ADO_EXIT_CODE" >> "$0"', ` + log + `]
+` + extra
+	if err := os.WriteFile(config, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return config, log
+}
+
+func readHookLog(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestHooks(t *testing.T) {
+	config, log := hooksConfig(t, `  vpn:
+    when: pre
+    commands: [meta]
+    command: sh
+    args: [-c, "exit 3"]
+  strict:
+    when: post
+    commands: [state]
+    command: sh
+    args: [-c, "exit 1"]
+    on_failure: fail
+`)
+	t.Setenv(hooks.EnvWhen, "")
+	os.Unsetenv(hooks.EnvWhen)
+
+	if _, err := runRoot(t, "--config", config, "echo", "hi"); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := readHookLog(t, log), "pre echo\npost echo 0\n"; got != want {
+		t.Errorf("hooks of echo = %q, want %q", got, want)
+	}
+
+	// A failing pre hook stops the command; no post hooks run.
+	os.Remove(log)
+	_, err := runRoot(t, "--config", config, "meta", "info")
+	if err == nil || err.Error() != `pre hook "vpn" failed: exit status 3` || exitCode(err) != 1 {
+		t.Errorf("meta info: error = %v (exit %d)", err, exitCode(err))
+	}
+	if got, want := readHookLog(t, log), "pre meta info\n"; got != want {
+		t.Errorf("hooks of meta info = %q, want %q", got, want)
+	}
+
+	// A failing post hook under the fail policy fails a command that
+	// succeeded; the exit status of one that failed is passed to the hooks.
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	if _, err := runRoot(t, "--config", config, "state", "set", "key", "value"); err == nil || err.Error() != `post hook "strict" failed: exit status 1` {
+		t.Errorf("state set: error = %v", err)
+	}
+	os.Remove(log)
+	_, err = runRoot(t, "--config", config, "state", "get", "missing")
+	if err == nil || strings.Contains(err.Error(), "hook") {
+		t.Errorf("state get: error = %v, want the command's", err)
+	}
+	if got := readHookLog(t, log); !strings.HasPrefix(got, "pre state get\npost state get ") || strings.HasSuffix(got, " 0\n") {
+		t.Errorf("hooks of state get = %q, want a non-zero exit code", got)
+	}
+}
+
+func TestHooks_Skipped(t *testing.T) {
+	config, log := hooksConfig(t, "")
+	tests := []struct {
+		name string
+		args []string
+		env  string
+	}{
+		{"completion", []string{"__complete", "ec"}, ""},
+		{"help", []string{"help", "echo"}, ""},
+		{"dry run", []string{"run", "--dry-run"}, ""},
+		{"run by a hook", []string{"echo", "hi"}, "pre"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(hooks.EnvWhen, tt.env)
+			if tt.env == "" {
+				os.Unsetenv(hooks.EnvWhen)
+			}
+			if _, err := runRoot(t, append([]string{"--config", config}, tt.args...)...); err != nil {
+				t.Fatal(err)
+			}
+			if got := readHookLog(t, log); got != "" {
+				t.Errorf("hooks ran: %q", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package root
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/metrics"
+)
+
+// startMetrics returns the StatsD client configured under metrics.statsd,
+// or nil when emission is off or the agent address cannot be used.
+func startMetrics(ctx context.Context, cmd *cobra.Command) *metrics.Client {
+	configPath, _ := cmd.Root().PersistentFlags().GetString("config")
+	homeDir, _ := os.UserHomeDir()
+	cfg, _, err := internalconfig.PathResolverFor(ctx, configPath, homeDir).Config()
+	if err != nil {
+		return nil
+	}
+	client, err := metrics.New(cfg.Metrics.StatsD)
+	if err != nil {
+		slog.DebugContext(ctx, "Metrics disabled", "error", err)
+		return nil
+	}
+	return client
+}
+
+// recordCommand reports the run of cmd, which started at start and ended
+// with err, to the metrics client in its context and closes the client.
+func recordCommand(cmd *cobra.Command, start time.Time, err error) {
+	if cmd == nil || cmd.Context() == nil {
+		return
+	}
+	client := metrics.FromContext(cmd.Context())
+	if client == nil {
+		return
+	}
+	code := 0
+	if err != nil {
+		code = exitCode(err)
+	}
+	client.Run(metrics.Command, time.Since(start), code, metrics.Tag("command", commandName(cmd)))
+	_ = client.Close()
+}
@@ -0,0 +1,38 @@
+package root
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRootCommand_Metrics(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("udp listener: %v", err)
+	}
+	defer conn.Close()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	config := "version: 1\nmetrics:\n  statsd:\n    address: " + conn.LocalAddr().String() + "\n    tags: {env: test}\n"
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runRoot(t, "--config", path, "echo", "hi"); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 1024)
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "ado.command.runs:1|c|#env:test,command:echo,status:success,exit_code:0"
+	if got := string(buf[:n]); !strings.HasPrefix(got, want) {
+		t.Errorf("packet = %q, want %q", got, want)
+	}
+}
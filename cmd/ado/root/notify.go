@@ -0,0 +1,81 @@
+package root
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/cmd/ado/shell"
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/update"
+)
+
+// updateNoticeWait bounds how long a finished command waits for a pending
+// update check. A cached result is available immediately; a network check
+// that misses the deadline was recorded as a failed attempt before it
+// started, so it is retried after update.RetryDelay rather than by the
+// next command.
+const updateNoticeWait = 500 * time.Millisecond
+
+// updateNotifier runs the opt-in update availability check in the
+// background and prints a one-line notice when the command completes.
+type updateNotifier struct {
+	current string
+	result  chan *update.CheckState
+}
+
+// startUpdateCheck begins a background update check when enabled in config.
+// It returns nil when no check should run.
+func startUpdateCheck(ctx context.Context, cmd *cobra.Command, current string) *updateNotifier {
+	// Commands run from `ado shell` leave the notice to the session.
+	if _, ok := os.LookupEnv("ADO_NO_UPDATE_CHECK"); ok || cmd.CommandPath() == "ado self update" || shell.Active(ctx) {
+		return nil
+	}
+
+	configPath, _ := cmd.Root().PersistentFlags().GetString("config")
+	homeDir, _ := os.UserHomeDir()
+	cfg, path, err := internalconfig.PathResolverFor(ctx, configPath, homeDir).Config()
+	if err != nil {
+		slog.DebugContext(ctx, "Config load failed; skipping update check", "path", path, "error", err)
+		return nil
+	}
+	if !cfg.Updates.Check {
+		return nil
+	}
+
+	checker, err := update.NewChecker()
+	if err != nil {
+		slog.DebugContext(ctx, "Update check unavailable", "error", err)
+		return nil
+	}
+
+	n := &updateNotifier{current: current, result: make(chan *update.CheckState, 1)}
+	go func() {
+		state, err := checker.Check(ctx, cfg.Updates.Channel)
+		if err != nil {
+			slog.DebugContext(ctx, "Update check failed", "error", err)
+		}
+		n.result <- state
+	}()
+	return n
+}
+
+// notify prints the update notice if the check found a newer release.
+func (n *updateNotifier) notify(w io.Writer) {
+	if n == nil {
+		return
+	}
+
+	select {
+	case state := <-n.result:
+		if state != nil && state.LatestVersion != "" && update.CompareVersions(n.current, state.LatestVersion) < 0 {
+			fmt.Fprintf(w, "A new release of ado is available: %s -> %s (run `ado self update`)\n", n.current, state.LatestVersion)
+		}
+	case <-time.After(updateNoticeWait):
+	}
+}
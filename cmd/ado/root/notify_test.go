@@ -0,0 +1,84 @@
+package root
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/anowarislam/ado/internal/update"
+)
+
+func TestUpdateNotifier_Notify(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		state   *update.CheckState
+		want    string
+	}{
+		{"newer release", "1.0.0", &update.CheckState{LatestVersion: "1.1.0"}, "A new release of ado is available: 1.0.0 -> 1.1.0"},
+		{"up to date", "1.1.0", &update.CheckState{LatestVersion: "1.1.0"}, ""},
+		{"check failed", "1.0.0", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &updateNotifier{current: tt.current, result: make(chan *update.CheckState, 1)}
+			n.result <- tt.state
+
+			var buf bytes.Buffer
+			n.notify(&buf)
+
+			if tt.want == "" && buf.Len() != 0 {
+				t.Errorf("unexpected notice: %q", buf.String())
+			}
+			if tt.want != "" && !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("notice = %q, want containing %q", buf.String(), tt.want)
+			}
+		})
+	}
+
+	// A nil notifier (check disabled) is a no-op.
+	var n *updateNotifier
+	n.notify(&bytes.Buffer{})
+}
+
+func TestStartUpdateCheck_Disabled(t *testing.T) {
+	dir := t.TempDir()
+	disabled := filepath.Join(dir, "disabled.yaml")
+	enabled := filepath.Join(dir, "enabled.yaml")
+	if err := os.WriteFile(disabled, []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(enabled, []byte("version: 1\nupdates:\n  check: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		config string
+		optOut bool
+	}{
+		{"not opted in", disabled, false},
+		{"env opt-out", enabled, true},
+		{"unreadable config", filepath.Join(dir, "missing.yaml"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.optOut {
+				t.Setenv("ADO_NO_UPDATE_CHECK", "1")
+			}
+			cmd := NewRootCommand()
+			if err := cmd.PersistentFlags().Set("config", tt.config); err != nil {
+				t.Fatal(err)
+			}
+
+			if n := startUpdateCheck(context.Background(), cmd, "1.0.0"); n != nil {
+				t.Error("startUpdateCheck() started a check, want nil")
+			}
+		})
+	}
+}
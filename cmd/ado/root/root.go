@@ -1,20 +1,77 @@
 package root
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"os/signal"
+	"runtime/debug"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 
+	"github.com/anowarislam/ado/cmd/ado/agent"
+	"github.com/anowarislam/ado/cmd/ado/alias"
+	"github.com/anowarislam/ado/cmd/ado/archive"
+	"github.com/anowarislam/ado/cmd/ado/cache"
+	"github.com/anowarislam/ado/cmd/ado/check"
 	"github.com/anowarislam/ado/cmd/ado/config"
+	"github.com/anowarislam/ado/cmd/ado/convert"
+	debugcmd "github.com/anowarislam/ado/cmd/ado/debug"
+	"github.com/anowarislam/ado/cmd/ado/decode"
+	"github.com/anowarislam/ado/cmd/ado/devops"
+	"github.com/anowarislam/ado/cmd/ado/diff"
+	"github.com/anowarislam/ado/cmd/ado/docs"
 	"github.com/anowarislam/ado/cmd/ado/echo"
+	"github.com/anowarislam/ado/cmd/ado/encode"
+	"github.com/anowarislam/ado/cmd/ado/env"
+	"github.com/anowarislam/ado/cmd/ado/generate"
+	"github.com/anowarislam/ado/cmd/ado/hash"
+	"github.com/anowarislam/ado/cmd/ado/http"
+	"github.com/anowarislam/ado/cmd/ado/id"
+	"github.com/anowarislam/ado/cmd/ado/inventory"
+	"github.com/anowarislam/ado/cmd/ado/logs"
+	"github.com/anowarislam/ado/cmd/ado/mcp"
 	"github.com/anowarislam/ado/cmd/ado/meta"
+	"github.com/anowarislam/ado/cmd/ado/parallel"
+	"github.com/anowarislam/ado/cmd/ado/remote"
+	"github.com/anowarislam/ado/cmd/ado/run"
+	"github.com/anowarislam/ado/cmd/ado/schedule"
+	"github.com/anowarislam/ado/cmd/ado/secret"
+	"github.com/anowarislam/ado/cmd/ado/self"
+	"github.com/anowarislam/ado/cmd/ado/serve"
+	"github.com/anowarislam/ado/cmd/ado/service"
+	"github.com/anowarislam/ado/cmd/ado/shell"
+	"github.com/anowarislam/ado/cmd/ado/shellinit"
+	"github.com/anowarislam/ado/cmd/ado/state"
+	"github.com/anowarislam/ado/cmd/ado/tls"
+	"github.com/anowarislam/ado/cmd/ado/top"
+	"github.com/anowarislam/ado/cmd/ado/waitfor"
+	"github.com/anowarislam/ado/cmd/ado/watch"
+	"github.com/anowarislam/ado/cmd/ado/workflow"
+	"github.com/anowarislam/ado/internal/completion"
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/crash"
+	"github.com/anowarislam/ado/internal/extension"
+	"github.com/anowarislam/ado/internal/features"
 	"github.com/anowarislam/ado/internal/logging"
 	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/metrics"
+	internalservice "github.com/anowarislam/ado/internal/service"
+	"github.com/anowarislam/ado/internal/ui"
+	"github.com/anowarislam/ado/internal/update"
 )
 
 func NewRootCommand() *cobra.Command {
 	buildInfo := internalmeta.CurrentBuildInfo()
+	var (
+		notifier    *updateNotifier
+		stopTimeout context.CancelFunc = func() {}
+	)
 
 	cmd := &cobra.Command{
 		Use:           "ado",
@@ -24,44 +81,419 @@ func NewRootCommand() *cobra.Command {
 		SilenceErrors: true,
 		Version:       buildInfo.Version,
 		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			// Resolve the config path once for everything below and the
+			// command itself, reusing the resolver from execute.
+			resolver := configResolver(cmd)
+
+			if err := checkFeature(cmd); err != nil {
+				return err
+			}
+
+			// Config defaults come first: they may set the log level.
+			if err := loadDefaults(cmd, resolver, cmd.ErrOrStderr()); err != nil {
+				return err
+			}
+
 			// Initialize logger from flags
-			logLevel, _ := cmd.Flags().GetString("log-level")
-			if logLevel != "" && !logging.IsValidLevel(logLevel) {
-				return fmt.Errorf("invalid log level %q: must be debug, info, warn, or error", logLevel)
+			logLevel, err := resolveLogLevel(cmd.Root().PersistentFlags())
+			if err != nil {
+				return err
+			}
+
+			if err := checkDryRun(cmd); err != nil {
+				return err
+			}
+
+			runLog, err := openRunLog(cmd)
+			if err != nil {
+				return err
 			}
 
 			cfg := logging.Config{
 				Level:  logLevel,
 				Format: "auto",
 				Output: "stderr",
+				RunLog: runLog,
 			}.Validate()
 
 			log := logging.New(cfg)
 			ctx := logging.WithContext(cmd.Context(), log)
+			if runLog != nil {
+				ctx = startRunLog(ctx, cmd, runLog)
+				cmd.SetContext(ctx)
+			}
+			ctx = metrics.WithClient(ctx, startMetrics(ctx, cmd))
+
+			// Read the root's flag: a command with its own --timeout
+			// shadows the global one.
+			timeout, _ := cmd.Root().PersistentFlags().GetDuration("timeout")
+			if timeout < 0 {
+				return errors.New("--timeout must not be negative")
+			}
+			if timeout > 0 {
+				ctx, stopTimeout = context.WithTimeoutCause(ctx, timeout, &TimeoutError{Timeout: timeout})
+			}
+			cmd.SetContext(ctx)
+
+			// Hooks run last, once the command is known to start.
+			if ctx, err = runPreHooks(ctx, cmd); err != nil {
+				return err
+			}
 			cmd.SetContext(ctx)
 
+			notifier = startUpdateCheck(ctx, cmd, buildInfo.Version)
 			return nil
 		},
+		PersistentPostRun: func(cmd *cobra.Command, args []string) {
+			stopTimeout()
+			notifier.notify(cmd.ErrOrStderr())
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return cmd.Help()
 		},
 	}
 
 	cmd.PersistentFlags().String("config", "", "Path to config file")
+	cmd.PersistentFlags().Bool("no-expand", false, "Leave ${VAR} references in config values as written")
+	cmd.PersistentFlags().Bool("strict-env", false, "Fail when a config value references an undefined environment variable")
 	cmd.PersistentFlags().String("log-level", "info", "Log level (debug, info, warn, error)")
+	cmd.PersistentFlags().CountP("verbose", "v", "Log more: -v for debug, repeat for more detail")
+	cmd.PersistentFlags().Bool("debug", false, "Log at debug level (same as --log-level debug)")
+	cmd.PersistentFlags().Bool(ui.DryRunFlag, false, "Show what would change without changing anything")
+	cmd.PersistentFlags().Duration("timeout", 0, "Stop the command after this long, e.g. 30s or 5m (0 for no limit)")
+	cmd.PersistentFlags().Bool("save-run-log", false, "Also write this run's logs, debug level included, to a file in the state directory")
+	cmd.PersistentFlags().Duration("run-log-retain", logging.DefaultRunLogRetention, "Remove run logs older than this when writing one (0 keeps them)")
+	_ = cmd.MarkPersistentFlagFilename("config", "yaml", "yml")
+	_ = cmd.RegisterFlagCompletionFunc("log-level", completion.Fixed(completion.LogLevels...))
 
 	cmd.AddCommand(
-		config.NewCommand(),
-		echo.NewCommand(),
+		agent.NewCommand(),
+		alias.NewCommand(),
+		archive.NewCommand(),
+		cache.NewCommand(),
+		check.NewCommand(),
+		config.NewCommand(defaultsProblems),
+		convert.NewCommand(),
+		debugcmd.NewCommand(),
+		decode.NewCommand(),
+		features.Gate(devops.NewCommand(), "devops"),
+		diff.NewCommand(),
+		docs.NewCommand(buildInfo),
+		echo.NewCommand(buildInfo),
+		encode.NewCommand(),
+		env.NewCommand(),
+		generate.NewCommand(),
+		hash.NewCommand(),
+		http.NewCommand(),
+		id.NewCommand(),
+		inventory.NewCommand(),
+		shellinit.NewCommand(buildInfo),
+		logs.NewCommand(),
+		features.Gate(mcp.NewCommand(buildInfo), "mcp"),
 		meta.NewCommand(buildInfo),
+		parallel.NewCommand(),
+		remote.NewCommand(),
+		run.NewCommand(),
+		schedule.NewCommand(),
+		secret.NewCommand(),
+		self.NewCommand(),
+		features.Gate(serve.NewCommand(buildInfo), "serve"),
+		service.NewCommand(),
+		shell.NewCommand(runShellLine),
+		state.NewCommand(),
+		tls.NewCommand(),
+		top.NewCommand(),
+		waitfor.NewCommand(),
+		watch.NewCommand(),
+		features.Gate(workflow.NewCommand(), "workflow"),
 	)
+	addExtensions(cmd, extension.Deps{BuildInfo: buildInfo})
+	completion.RegisterOutputFlags(cmd)
+	enableSuggestions(cmd)
+	defaultsBeforeArgs(cmd)
 
 	return cmd
 }
 
+// addExtensions adds the commands registered through pkg/adocli. A name
+// that is already taken is a programming error in the distribution, so it
+// panics rather than silently shadowing a command.
+func addExtensions(cmd *cobra.Command, deps extension.Deps) {
+	for _, ext := range extension.Commands(deps) {
+		for _, existing := range cmd.Commands() {
+			if existing.Name() == ext.Name() {
+				panic(fmt.Sprintf("adocli: command %q is already registered", ext.Name()))
+			}
+		}
+		cmd.AddCommand(ext)
+	}
+}
+
+// exitCoder is implemented by errors that carry a process exit status,
+// such as a failed `ado run` task.
+type exitCoder interface {
+	ExitCode() int
+}
+
+// TimeoutError reports that the global --timeout expired. ado exits 124,
+// like timeout(1).
+type TimeoutError struct {
+	Timeout time.Duration
+	// Err is what the command returned after its context was canceled.
+	Err error
+}
+
+func (e *TimeoutError) Error() string {
+	if e.Err == nil || errors.Is(e.Err, context.DeadlineExceeded) {
+		return fmt.Sprintf("timed out after %s", e.Timeout)
+	}
+	return fmt.Sprintf("timed out after %s: %v", e.Timeout, e.Err)
+}
+
+func (e *TimeoutError) Unwrap() error { return e.Err }
+
+// ExitCode implements exitCoder.
+func (e *TimeoutError) ExitCode() int { return 124 }
+
+// ErrorCode implements errorCoder.
+func (e *TimeoutError) ErrorCode() string { return "timeout" }
+
+// ErrorDetails implements errorCoder.
+func (e *TimeoutError) ErrorDetails() map[string]any {
+	return map[string]any{"timeout": e.Timeout.String()}
+}
+
+// resolveLogLevel returns the level selected by --log-level, -v/--verbose
+// and --debug. An explicit --log-level wins; otherwise -v or --debug lower
+// the default to debug. Repeated -v is accepted so scripts keep working
+// once finer levels exist.
+func resolveLogLevel(flags *pflag.FlagSet) (string, error) {
+	level, _ := flags.GetString("log-level")
+	if level != "" && !logging.IsValidLevel(level) {
+		return "", fmt.Errorf("invalid log level %q: must be debug, info, warn, or error", level)
+	}
+	if flags.Changed("log-level") {
+		return level, nil
+	}
+	verbose, _ := flags.GetCount("verbose")
+	debug, _ := flags.GetBool("debug")
+	if verbose > 0 || debug {
+		return "debug", nil
+	}
+	return level, nil
+}
+
+// checkDryRun rejects --dry-run for commands that do not honor it. Help
+// for the root and command groups changes nothing, so it is allowed.
+func checkDryRun(cmd *cobra.Command) error {
+	if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); !dryRun {
+		return nil
+	}
+	if cmd.Annotations[ui.DryRunAnnotation] != "" || !cmd.HasParent() || cmd.HasSubCommands() || cmd.Name() == "help" {
+		return nil
+	}
+	return fmt.Errorf("'%s' does not support --%s", cmd.CommandPath(), ui.DryRunFlag)
+}
+
+// InterruptError reports that ado was stopped by SIGINT or SIGTERM. ado
+// exits 128 plus the signal number, so 130 after Ctrl-C.
+type InterruptError struct {
+	Signal os.Signal
+	// Err is what the command returned after its context was canceled.
+	Err error
+}
+
+func (e *InterruptError) Error() string {
+	msg := "interrupted"
+	if e.Signal == syscall.SIGTERM {
+		msg = "terminated"
+	}
+	if e.Err == nil || errors.Is(e.Err, context.Canceled) {
+		return msg
+	}
+	return fmt.Sprintf("%s: %v", msg, e.Err)
+}
+
+func (e *InterruptError) Unwrap() error { return e.Err }
+
+// ExitCode implements exitCoder.
+func (e *InterruptError) ExitCode() int {
+	if sig, ok := e.Signal.(syscall.Signal); ok {
+		return 128 + int(sig)
+	}
+	return 130
+}
+
+// ErrorCode implements errorCoder.
+func (e *InterruptError) ErrorCode() string { return "interrupted" }
+
+// ErrorDetails implements errorCoder.
+func (e *InterruptError) ErrorDetails() map[string]any {
+	return map[string]any{"signal": e.Signal.String()}
+}
+
+// Execute runs ado with os.Args and exits with its status. Started by the
+// Windows service control manager, it runs as that service.
 func Execute() {
-	if err := NewRootCommand().Execute(); err != nil {
+	os.Exit(internalservice.Main(context.Background(), ExecuteContext))
+}
+
+// ExecuteContext runs ado with os.Args and returns the exit status instead
+// of exiting, so programs that embed ado's commands can run them in
+// process. Errors are printed to stderr, and a panic is reported as a
+// crash with status 70. A second SIGINT or SIGTERM still exits at once.
+func ExecuteContext(ctx context.Context) (code int) {
+	defer func() {
+		if r := recover(); r != nil {
+			code = reportCrash(os.Stderr, r, debug.Stack(), os.Args)
+		}
+	}()
+
+	ctx, stop := notifyContext(ctx)
+	defer stop()
+
+	root := NewRootCommand()
+	args, err := expandAlias(root, os.Args[1:])
+	if err == nil {
+		err = execute(ctx, root, args)
+	} else {
+		printError(os.Stdout, nil, args, err)
+	}
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+		return exitCode(err)
+	}
+	return 0
+}
+
+// issuesURL is where crash reports should be filed.
+const issuesURL = "https://github.com/" + update.DefaultRepository + "/issues"
+
+// reportCrash writes a crash report for a panic and tells the user where
+// to find it. When the report cannot be saved it is printed instead.
+func reportCrash(w io.Writer, value any, stack []byte, args []string) int {
+	report := crash.Report{Time: time.Now(), Value: value, Stack: stack, Args: args, Build: internalmeta.CurrentBuildInfo()}
+	fmt.Fprintf(w, "ado crashed: %v\n", value)
+
+	dir, err := crash.Dir()
+	var path string
+	if err == nil {
+		path, err = crash.Write(dir, report)
+	}
+	if err != nil {
+		fmt.Fprintf(w, "Could not save a crash report (%v):\n\n%s", err, report.Format())
+		return crash.ExitCode
+	}
+	fmt.Fprintf(w, "A crash report was written to %s\n", path)
+	fmt.Fprintf(w, "Please attach it to an issue at %s\n", issuesURL)
+	return crash.ExitCode
+}
+
+// releaseSignalsKey holds a function that makes a notifyContext stop
+// handling signals, for `ado shell` to hand them to each command it runs.
+type releaseSignalsKey struct{}
+
+// notifyContext returns a context canceled with an *InterruptError on the
+// first SIGINT or SIGTERM, so commands stop their work and children
+// through cmd.Context(). A second signal exits at once, for when stopping
+// gracefully hangs.
+func notifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+	ctx = context.WithValue(ctx, releaseSignalsKey{}, func() { signal.Stop(signals) })
+
+	go func() {
+		select {
+		case sig := <-signals:
+			cancel(&InterruptError{Signal: sig})
+		case <-done:
+			return
+		}
+		select {
+		case sig := <-signals:
+			os.Exit((&InterruptError{Signal: sig}).ExitCode())
+		case <-done:
+		}
+	}()
+
+	return ctx, func() {
+		signal.Stop(signals)
+		close(done)
+		cancel(context.Canceled)
+	}
+}
+
+// execute runs root with ctx and args. A command stopped by a signal or
+// by an expired --timeout fails with an *InterruptError or *TimeoutError
+// even if it returned partial results without an error. Failures are also
+// reported on stdout as an error document when -o json or yaml was given.
+//
+// Each call is a fresh invocation with its own config resolver, so lines
+// run by `ado shell` see config changes made by earlier ones.
+func execute(ctx context.Context, root *cobra.Command, args []string) error {
+	_, global := commandIndex(root, args)
+	homeDir, _ := os.UserHomeDir()
+	ctx = internalconfig.WithPathResolver(ctx, global.resolver(homeDir))
+
+	gateFeatures(ctx, root, global.config)
+	root.SetArgs(args)
+	start := time.Now()
+	cmd, err := root.ExecuteContextC(context.WithValue(ctx, runArgsKey{}, append([]string{root.Name()}, args...)))
+	err = stopError(cmd, err)
+	err = runPostHooks(cmd, start, err)
+	recordCommand(cmd, start, err)
+	finishRunLog(cmd, start, err)
+	printError(root.OutOrStdout(), cmd, args, err)
+	return err
+}
+
+// runShellLine runs one line of `ado shell` like a separate invocation of
+// ado, with a fresh command tree. Signals stop only the line: the session
+// keeps running after Ctrl-C.
+func runShellLine(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if release, ok := ctx.Value(releaseSignalsKey{}).(func()); ok {
+		release()
+	}
+	ctx, stop := notifyContext(ctx)
+	defer stop()
+
+	root := NewRootCommand()
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	args, err := expandAlias(root, args)
+	if err != nil {
+		printError(stdout, nil, args, err)
+		return err
+	}
+	return execute(ctx, root, args)
+}
+
+// stopError wraps err in an *InterruptError or *TimeoutError when cmd's
+// context was canceled by a signal or by --timeout.
+func stopError(cmd *cobra.Command, err error) error {
+	if cmd == nil || cmd.Context() == nil {
+		return err
+	}
+	var (
+		interrupt *InterruptError
+		timeout   *TimeoutError
+	)
+	switch cause := context.Cause(cmd.Context()); {
+	case errors.As(cause, &interrupt):
+		return &InterruptError{Signal: interrupt.Signal, Err: err}
+	case errors.As(cause, &timeout):
+		return &TimeoutError{Timeout: timeout.Timeout, Err: err}
+	}
+	return err
+}
+
+// exitCode returns the status ado exits with for err.
+func exitCode(err error) int {
+	var coder exitCoder
+	if errors.As(err, &coder) && coder.ExitCode() > 0 {
+		return coder.ExitCode()
 	}
+	return 1
 }
@@ -2,8 +2,20 @@ package root
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
+	"syscall"
 	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/extension"
+	"github.com/anowarislam/ado/internal/tasks"
 )
 
 func TestNewRootCommand(t *testing.T) {
@@ -19,7 +31,7 @@ func TestNewRootCommand(t *testing.T) {
 		subcommands[sub.Name()] = true
 	}
 
-	expectedCmds := []string{"echo", "meta"}
+	expectedCmds := []string{"alias", "archive", "cache", "check", "convert", "debug", "decode", "diff", "docs", "echo", "encode", "env", "generate", "hash", "http", "id", "init", "logs", "mcp", "meta", "parallel", "run", "schedule", "secret", "self", "serve", "shell", "state", "tls", "top", "wait-for", "watch", "workflow"}
 	for _, name := range expectedCmds {
 		if !subcommands[name] {
 			t.Errorf("expected subcommand %q not found", name)
@@ -116,6 +128,310 @@ func TestRootCommand_LogLevel_Valid(t *testing.T) {
 	}
 }
 
+func TestResolveLogLevel(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, "info"},
+		{[]string{"-v"}, "debug"},
+		{[]string{"-vv"}, "debug"},
+		{[]string{"--verbose"}, "debug"},
+		{[]string{"--debug"}, "debug"},
+		{[]string{"--log-level", "warn"}, "warn"},
+		{[]string{"--log-level", "error", "-v"}, "error"},
+		{[]string{"--debug", "--log-level=info"}, "info"},
+	}
+	for _, tt := range tests {
+		flags := NewRootCommand().PersistentFlags()
+		if err := flags.Parse(tt.args); err != nil {
+			t.Fatalf("Parse(%q) error = %v", tt.args, err)
+		}
+		got, err := resolveLogLevel(flags)
+		if err != nil {
+			t.Errorf("resolveLogLevel(%q) error = %v", tt.args, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("resolveLogLevel(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestRootCommand_Verbose(t *testing.T) {
+	cmd := NewRootCommand()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetArgs([]string{"-vv", "echo", "test", "--debug"})
+	if err := cmd.Execute(); err != nil {
+		t.Errorf("Execute() error = %v", err)
+	}
+}
+
+func TestRootCommand_DryRun(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	tests := []struct {
+		args    []string
+		wantErr string
+	}{
+		{[]string{"--dry-run", "state", "set", "k", "v"}, ""},
+		{[]string{"state", "--dry-run"}, ""},
+		{[]string{"--dry-run", "state", "incr", "k"}, ""},
+		{[]string{"--dry-run", "state", "get", "k"}, "'ado state get' does not support --dry-run"},
+		{[]string{"echo", "hi", "--dry-run"}, "'ado echo' does not support --dry-run"},
+	}
+	for _, tt := range tests {
+		cmd := NewRootCommand()
+		cmd.SetOut(&bytes.Buffer{})
+		cmd.SetErr(&bytes.Buffer{})
+		err := execute(context.Background(), cmd, tt.args)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("execute(%q) error = %v", tt.args, err)
+			}
+		} else if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("execute(%q) error = %v, want %q", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRootCommand_Timeout(t *testing.T) {
+	cmd := NewRootCommand()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	started := time.Now()
+	err := execute(context.Background(), cmd, []string{"--timeout", "100ms", "parallel", "x", "--", "sh", "-c", "sleep 10", "{}"})
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("command ran for %s despite --timeout", elapsed)
+	}
+	var timeout *TimeoutError
+	if !errors.As(err, &timeout) || timeout.Timeout != 100*time.Millisecond {
+		t.Fatalf("execute() error = %v, want *TimeoutError", err)
+	}
+	if got := err.Error(); got != "timed out after 100ms: 1 of 1 items did not succeed" {
+		t.Errorf("error = %q", got)
+	}
+	if exitCode(err) != 124 {
+		t.Errorf("exitCode() = %d, want 124", exitCode(err))
+	}
+}
+
+func TestRootCommand_TimeoutNotReached(t *testing.T) {
+	cmd := NewRootCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	if err := execute(context.Background(), cmd, []string{"--timeout", "1m", "echo", "hi"}); err != nil {
+		t.Fatalf("execute() error = %v", err)
+	}
+	if out.String() != "hi\n" {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+func TestRootCommand_TimeoutNegative(t *testing.T) {
+	cmd := NewRootCommand()
+	if err := execute(context.Background(), cmd, []string{"--timeout", "-1s", "echo", "hi"}); err == nil || err.Error() != "--timeout must not be negative" {
+		t.Errorf("execute() error = %v", err)
+	}
+}
+
+func TestTimeoutError(t *testing.T) {
+	if got := (&TimeoutError{Timeout: time.Second, Err: context.DeadlineExceeded}).Error(); got != "timed out after 1s" {
+		t.Errorf("Error() = %q", got)
+	}
+	inner := errors.New("boom")
+	err := &TimeoutError{Timeout: time.Minute, Err: inner}
+	if got := err.Error(); got != "timed out after 1m0s: boom" || !errors.Is(err, inner) {
+		t.Errorf("Error() = %q, Is(inner) = %v", got, errors.Is(err, inner))
+	}
+}
+
+func TestRootCommand_Interrupt(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	cmd := NewRootCommand()
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	time.AfterFunc(100*time.Millisecond, func() { cancel(&InterruptError{Signal: os.Interrupt}) })
+
+	started := time.Now()
+	err := execute(ctx, cmd, []string{"parallel", "x", "--", "sh", "-c", "sleep 10", "{}"})
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("command ran for %s after the interrupt", elapsed)
+	}
+	var interrupt *InterruptError
+	if !errors.As(err, &interrupt) {
+		t.Fatalf("execute() error = %v, want *InterruptError", err)
+	}
+	if got := err.Error(); got != "interrupted: 1 of 1 items did not succeed" {
+		t.Errorf("error = %q", got)
+	}
+	if exitCode(err) != 130 {
+		t.Errorf("exitCode() = %d, want 130", exitCode(err))
+	}
+}
+
+func TestNotifyContext(t *testing.T) {
+	ctx, stop := notifyContext(context.Background())
+	defer stop()
+
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proc.Signal(syscall.SIGTERM); err != nil {
+		t.Skipf("cannot signal own process: %v", err)
+	}
+	select {
+	case <-ctx.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("context not canceled after SIGTERM")
+	}
+	var interrupt *InterruptError
+	if !errors.As(context.Cause(ctx), &interrupt) || interrupt.Signal != syscall.SIGTERM {
+		t.Errorf("Cause() = %v, want *InterruptError for SIGTERM", context.Cause(ctx))
+	}
+}
+
+func TestRunShellLine(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := runShellLine(context.Background(), []string{"nosuch", "-o", "json"}, &stdout, &stderr)
+	if err == nil || !strings.Contains(stdout.String(), `"code": "unknown_command"`) {
+		t.Errorf("stdout = %q, error = %v", stdout.String(), err)
+	}
+}
+
+func TestRunShellLine_Interrupt(t *testing.T) {
+	session, stop := notifyContext(context.Background())
+	defer stop()
+
+	proc, err := os.FindProcess(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.AfterFunc(200*time.Millisecond, func() { _ = proc.Signal(syscall.SIGTERM) })
+	err = runShellLine(session, []string{"parallel", "x", "--", "sh", "-c", "sleep 10", "{}"}, &bytes.Buffer{}, &bytes.Buffer{})
+	var interrupt *InterruptError
+	if !errors.As(err, &interrupt) {
+		t.Fatalf("runShellLine() error = %v, want *InterruptError", err)
+	}
+	if session.Err() != nil {
+		t.Error("the signal for the line also stopped the session")
+	}
+}
+
+func TestInterruptError(t *testing.T) {
+	if got := (&InterruptError{Signal: os.Interrupt, Err: context.Canceled}).Error(); got != "interrupted" {
+		t.Errorf("Error() = %q", got)
+	}
+	inner := errors.New("boom")
+	err := &InterruptError{Signal: syscall.SIGTERM, Err: inner}
+	if got := err.Error(); got != "terminated: boom" || !errors.Is(err, inner) {
+		t.Errorf("Error() = %q, Is(inner) = %v", got, errors.Is(err, inner))
+	}
+}
+
+func TestReportCrash(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+
+	var out bytes.Buffer
+	code := reportCrash(&out, "boom", []byte("goroutine 1 [running]:\n"), []string{"ado", "echo", "--token=s3cret"})
+	if code != 70 {
+		t.Errorf("reportCrash() = %d, want 70", code)
+	}
+	matches, _ := filepath.Glob(filepath.Join(state, "ado", "crashes", "crash-*.txt"))
+	if len(matches) != 1 {
+		t.Fatalf("crash reports = %v, want one", matches)
+	}
+	if !strings.Contains(out.String(), "ado crashed: boom\nA crash report was written to "+matches[0]+"\n") {
+		t.Errorf("output = %q", out.String())
+	}
+	data, _ := os.ReadFile(matches[0])
+	if !strings.Contains(string(data), "panic: boom") || strings.Contains(string(data), "s3cret") {
+		t.Errorf("report = %q", data)
+	}
+}
+
+// captureStd redirects os.Stdout and os.Stderr to files for the rest of
+// the test and returns a function that reads what was written to them.
+func captureStd(t *testing.T) func() (stdout, stderr string) {
+	t.Helper()
+	dir := t.TempDir()
+	origOut, origErr := os.Stdout, os.Stderr
+	outFile, err := os.Create(filepath.Join(dir, "stdout"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	errFile, err := os.Create(filepath.Join(dir, "stderr"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.Stdout, os.Stderr = outFile, errFile
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = origOut, origErr
+		outFile.Close()
+		errFile.Close()
+	})
+	return func() (string, string) {
+		out, _ := os.ReadFile(outFile.Name())
+		errOut, _ := os.ReadFile(errFile.Name())
+		return string(out), string(errOut)
+	}
+}
+
+func TestExecuteContext(t *testing.T) {
+	t.Setenv("ADO_NO_UPDATE_CHECK", "1")
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	t.Cleanup(extension.Reset)
+	extension.Register(func(extension.Deps) *cobra.Command {
+		return &cobra.Command{Use: "boom", Run: func(*cobra.Command, []string) { panic("boom") }}
+	})
+
+	tests := []struct {
+		args       []string
+		wantCode   int
+		wantStdout string
+		wantStderr string
+	}{
+		{[]string{"echo", "hi"}, 0, "hi\n", ""},
+		{[]string{"metaa"}, 1, "", `unknown command "metaa" for "ado"`},
+		{[]string{"boom"}, 70, "", "ado crashed: boom"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			origArgs := os.Args
+			t.Cleanup(func() { os.Args = origArgs })
+			os.Args = append([]string{"ado"}, tt.args...)
+			read := captureStd(t)
+
+			code := ExecuteContext(context.Background())
+			stdout, stderr := read()
+			if code != tt.wantCode {
+				t.Errorf("ExecuteContext() = %d, want %d (stderr %q)", code, tt.wantCode, stderr)
+			}
+			if stdout != tt.wantStdout || !strings.Contains(stderr, tt.wantStderr) {
+				t.Errorf("stdout = %q, stderr = %q", stdout, stderr)
+			}
+		})
+	}
+}
+
+func TestReportCrash_Unwritable(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("XDG_STATE_HOME", file)
+
+	var out bytes.Buffer
+	if code := reportCrash(&out, "boom", nil, []string{"ado"}); code != 70 {
+		t.Errorf("reportCrash() = %d, want 70", code)
+	}
+	if got := out.String(); !strings.Contains(got, "Could not save a crash report") || !strings.Contains(got, "panic: boom") {
+		t.Errorf("output = %q, want the report printed", got)
+	}
+}
+
 func TestRootCommand_ConfigSubcommand(t *testing.T) {
 	cmd := NewRootCommand()
 
@@ -129,3 +445,107 @@ func TestRootCommand_ConfigSubcommand(t *testing.T) {
 		t.Error("expected subcommand 'config' not found")
 	}
 }
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"plain error", errors.New("boom"), 1},
+		{"task exit status", &tasks.ExitError{Task: "build", Code: 3}, 3},
+		{"wrapped", fmt.Errorf("ctx: %w", &tasks.ExitError{Task: "build", Code: 42}), 42},
+		{"zero code", &tasks.ExitError{Task: "build", Code: 0}, 1},
+		{"timeout", &TimeoutError{Timeout: time.Second}, 124},
+		{"interrupt", &InterruptError{Signal: os.Interrupt}, 130},
+		{"terminate", &InterruptError{Signal: syscall.SIGTERM}, 143},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRootCommand_Extensions(t *testing.T) {
+	t.Cleanup(extension.Reset)
+	extension.Register(func(deps extension.Deps) *cobra.Command {
+		return &cobra.Command{Use: "custom", Short: "Custom command " + deps.BuildInfo.Version}
+	})
+	extension.Register(func(extension.Deps) *cobra.Command { return nil })
+
+	cmd := NewRootCommand()
+	sub, _, err := cmd.Find([]string{"custom"})
+	if err != nil || sub.Name() != "custom" {
+		t.Fatalf("registered command not found: %v", err)
+	}
+	if !strings.HasPrefix(sub.Short, "Custom command ") {
+		t.Errorf("Short = %q", sub.Short)
+	}
+}
+
+func TestNewRootCommand_ExtensionConflict(t *testing.T) {
+	t.Cleanup(extension.Reset)
+	extension.Register(func(extension.Deps) *cobra.Command { return &cobra.Command{Use: "echo"} })
+
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), `command "echo" is already registered`) {
+			t.Errorf("recover() = %v, want conflict panic", r)
+		}
+	}()
+	NewRootCommand()
+}
+
+func TestRootCommand_Completions(t *testing.T) {
+	configPath := filepath.Join(t.TempDir(), "config.yaml")
+	config := "version: 1\ntasks:\n  test:\n    command: go\n    description: Run tests\n  lint:\n    command: golangci-lint\n"
+	if err := os.WriteFile(configPath, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"default output formats", []string{"meta", "info", "-o", ""}, []string{"text", "json", "yaml"}},
+		{"command-specific output formats", []string{"config", "validate", "--output", ""}, []string{"text", "json", "junit", "sarif", "tap"}},
+		{"diff output modes", []string{"diff", "-o", ""}, []string{"text", "unified", "patch", "json", "yaml"}},
+		{"log levels", []string{"--log-level", ""}, []string{"debug", "info", "warn", "error"}},
+		{"hash algorithms", []string{"hash", "--algorithm", ""}, []string{"sha256", "sha512", "blake2b", "md5"}},
+		{"convert formats", []string{"convert", "--to", ""}, []string{"json", "yaml", "toml", "csv"}},
+		{"update channels", []string{"self", "update", "--channel", ""}, []string{"stable", "prerelease"}},
+		{"run task names", []string{"--config", configPath, "run", ""}, []string{"lint\tCheck the tasks in the config file without running them", "test\tRun tests"}},
+		{"watch task names", []string{"--config", configPath, "watch", "--task", ""}, []string{"lint", "test\tRun tests"}},
+		{"run extra args", []string{"--config", configPath, "run", "test", ""}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewRootCommand()
+			var out bytes.Buffer
+			cmd.SetOut(&out)
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(append([]string{cobra.ShellCompRequestCmd}, tt.args...))
+			if err := cmd.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			// The last line is the directive, e.g. ":4".
+			lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+			got := lines[:len(lines)-1]
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("completions = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func BenchmarkNewRootCommand(b *testing.B) {
+	for b.Loop() {
+		NewRootCommand()
+	}
+}
@@ -0,0 +1,130 @@
+package root
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/idgen"
+	"github.com/anowarislam/ado/internal/logging"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+)
+
+// runLogKey holds the *runLog of an invocation in its context.
+type runLogKey struct{}
+
+// runArgsKey holds the arguments of an invocation in its context, which
+// for a line of `ado shell` are not os.Args.
+type runArgsKey struct{}
+
+// runLog is the open run log of an invocation.
+type runLog struct {
+	*logging.RunLog
+	// restore undoes captureDefaultLogger.
+	restore func()
+}
+
+// openRunLog creates the run log when --save-run-log is set and prunes the
+// ones older than --run-log-retain. It returns nil when the run log is off
+// or cannot be created: a run log never stops a command, so that failure
+// is only a warning.
+func openRunLog(cmd *cobra.Command) (*logging.RunLog, error) {
+	flags := cmd.Root().PersistentFlags()
+	retain, _ := flags.GetDuration("run-log-retain")
+	if retain < 0 {
+		return nil, errors.New("--run-log-retain must not be negative")
+	}
+	if enabled, _ := flags.GetBool("save-run-log"); !enabled {
+		return nil, nil
+	}
+
+	dir := internalconfig.ResolveDirs().Log
+	if dir == "" {
+		fmt.Fprintln(cmd.ErrOrStderr(), "warning: run log disabled: state directory: neither $XDG_STATE_HOME nor $HOME is defined")
+		return nil, nil
+	}
+	now := time.Now()
+	if retain > 0 {
+		if _, err := logging.PruneRunLogs(dir, now.Add(-retain)); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "warning: prune run logs: %v\n", err)
+		}
+	}
+	gen, err := idgen.New(idgen.KindULID, idgen.Options{})
+	if err != nil {
+		return nil, err
+	}
+	id, err := gen.Next()
+	if err != nil {
+		return nil, err
+	}
+	l, err := logging.CreateRunLog(dir, id, now)
+	if err != nil {
+		fmt.Fprintf(cmd.ErrOrStderr(), "warning: run log disabled: %v\n", err)
+		return nil, nil
+	}
+	return l, nil
+}
+
+// startRunLog records how the run of cmd started in l and returns ctx
+// carrying l for finishRunLog.
+func startRunLog(ctx context.Context, cmd *cobra.Command, l *logging.RunLog) context.Context {
+	args, _ := ctx.Value(runArgsKey{}).([]string)
+	build := internalmeta.CurrentBuildInfo()
+	l.Log(ctx, slog.LevelInfo, logging.MsgRunStarted,
+		"command", commandName(cmd),
+		"args", internalmeta.RedactArgs(args),
+		"version", build.Version,
+		"pid", os.Getpid(),
+	)
+	return context.WithValue(ctx, runLogKey{}, &runLog{RunLog: l, restore: captureDefaultLogger(l)})
+}
+
+// finishRunLog records how the run of cmd, which started at start, ended
+// with err, and closes the run log in its context.
+func finishRunLog(cmd *cobra.Command, start time.Time, err error) {
+	if cmd == nil || cmd.Context() == nil {
+		return
+	}
+	l, ok := cmd.Context().Value(runLogKey{}).(*runLog)
+	if !ok {
+		return
+	}
+	l.restore()
+	level, code := slog.LevelInfo, 0
+	if err != nil {
+		level, code = slog.LevelError, exitCode(err)
+	}
+	attrs := []any{"command", commandName(cmd), "duration_ms", time.Since(start).Milliseconds(), "exit_code", code}
+	if err != nil {
+		attrs = append(attrs, "error", err.Error())
+	}
+	l.Log(context.Background(), level, logging.MsgRunFinished, attrs...)
+	_ = l.Close()
+}
+
+// captureDefaultLogger also sends the records of the process-wide slog
+// logger, which most packages log to, to the run log; the console shows
+// what it did before. It returns a function that undoes it.
+func captureDefaultLogger(l *logging.RunLog) func() {
+	prev, out, flags := slog.Default(), log.Writer(), log.Flags()
+	slog.SetDefault(slog.New(logging.Tee(prev.Handler(), l.Handler())))
+	// SetDefault routes the log package through the new handler, which
+	// writes through the log package: keep it writing where it did.
+	log.SetOutput(out)
+	log.SetFlags(flags)
+	return func() { slog.SetDefault(prev) }
+}
+
+// commandName is cmd's path without the program name, such as
+// "meta system".
+func commandName(cmd *cobra.Command) string {
+	return strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" ")
+}
@@ -0,0 +1,125 @@
+package root
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anowarislam/ado/internal/logging"
+)
+
+// readRunLogs returns the records of every run log in dir.
+func readRunLogs(t *testing.T, dir string) []map[string]any {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var records []map[string]any
+	for _, entry := range entries {
+		if _, _, ok := logging.ParseRunLogName(entry.Name()); !ok {
+			continue
+		}
+		f, err := os.Open(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			var record map[string]any
+			if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+				t.Fatalf("%s: %v", entry.Name(), err)
+			}
+			records = append(records, record)
+		}
+		f.Close()
+	}
+	return records
+}
+
+func TestRootCommand_RunLog(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	logs := filepath.Join(state, "ado", "logs")
+	if err := os.MkdirAll(logs, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	old := filepath.Join(logs, "2026-01-01-01JA0000000000000000000000.log")
+	other := filepath.Join(logs, "schedule.jsonl")
+	for _, path := range []string{old, other} {
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		past := time.Now().Add(-30 * 24 * time.Hour)
+		if err := os.Chtimes(path, past, past); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(config, []byte("version: 1\nmetrics:\n  statsd:\n    address: localhost:notaport\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runRoot(t, "--config", config, "--save-run-log", "echo", "--", "--token=s3cret"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Errorf("expired run log not pruned: %v", err)
+	}
+	if _, err := os.Stat(other); err != nil {
+		t.Errorf("other log file removed: %v", err)
+	}
+
+	records := readRunLogs(t, logs)
+	if len(records) < 3 {
+		t.Fatalf("records = %v", records)
+	}
+	first, last := records[0], records[len(records)-1]
+	if first["msg"] != "Run started" || first["command"] != "echo" || first["run_id"] == "" {
+		t.Errorf("first record = %v", first)
+	}
+	if args, _ := first["args"].([]any); len(args) != 7 || args[6] != "--token=[REDACTED]" {
+		t.Errorf("args = %v", first["args"])
+	}
+	if last["msg"] != "Run finished" || last["exit_code"] != float64(0) || last["run_id"] != first["run_id"] {
+		t.Errorf("last record = %v", last)
+	}
+	debug := false
+	for _, record := range records {
+		debug = debug || (record["msg"] == "Metrics disabled" && record["level"] == "DEBUG")
+	}
+	if !debug {
+		t.Errorf("debug record of the default logger missing: %v", records)
+	}
+}
+
+func TestRootCommand_RunLogFailure(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	if _, err := runRoot(t, "--save-run-log", "--timeout", "-1s", "echo", "hi"); err == nil {
+		t.Fatal("expected error")
+	}
+	records := readRunLogs(t, filepath.Join(state, "ado", "logs"))
+	last := records[len(records)-1]
+	if last["msg"] != "Run finished" || last["level"] != "ERROR" || last["exit_code"] != float64(1) || last["error"] != "--timeout must not be negative" {
+		t.Errorf("last record = %v", last)
+	}
+}
+
+func TestRootCommand_RunLogOff(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	if _, err := runRoot(t, "echo", "hi"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(state, "ado", "logs")); !os.IsNotExist(err) {
+		t.Errorf("log directory created without --save-run-log: %v", err)
+	}
+	if _, err := runRoot(t, "--run-log-retain", "-1h", "echo", "hi"); err == nil || err.Error() != "--run-log-retain must not be negative" {
+		t.Errorf("error = %v", err)
+	}
+}
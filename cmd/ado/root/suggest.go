@@ -0,0 +1,185 @@
+package root
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// Usage error codes.
+const (
+	codeUnknownCommand = "unknown_command"
+	codeUnknownFlag    = "unknown_flag"
+)
+
+// suggestionDistance is the largest edit distance at which a command or
+// flag is suggested for a mistyped name.
+const suggestionDistance = 2
+
+// maxFlagSuggestions bounds the flags suggested for a mistyped one.
+const maxFlagSuggestions = 3
+
+// UsageError reports an unknown command or flag together with the closest
+// known names.
+type UsageError struct {
+	// Code is "unknown_command" or "unknown_flag".
+	Code string
+	// Name is the command or flag as typed.
+	Name        string
+	Suggestions []string
+	Err         error
+}
+
+func (e *UsageError) Error() string {
+	if len(e.Suggestions) == 0 {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v\n\nDid you mean this?\n\t%s", e.Err, strings.Join(e.Suggestions, "\n\t"))
+}
+
+func (e *UsageError) Unwrap() error { return e.Err }
+
+// ErrorCode implements errorCoder.
+func (e *UsageError) ErrorCode() string { return e.Code }
+
+// ErrorDetails implements errorCoder.
+func (e *UsageError) ErrorDetails() map[string]any {
+	suggestions := e.Suggestions
+	if suggestions == nil {
+		suggestions = []string{}
+	}
+	return map[string]any{"name": e.Name, "suggestions": suggestions}
+}
+
+// enableSuggestions makes root and every command that only groups
+// subcommands reject unknown subcommands with suggestions, instead of
+// printing help, and adds suggestions to unknown flag errors.
+func enableSuggestions(root *cobra.Command) {
+	root.SuggestionsMinimumDistance = suggestionDistance
+	groups := map[*cobra.Command]bool{root: true}
+	root.Args = unknownCommand
+
+	var walk func(*cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		for _, sub := range cmd.Commands() {
+			if sub.HasSubCommands() && !sub.Runnable() {
+				groups[sub] = true
+				sub.SuggestionsMinimumDistance = suggestionDistance
+				sub.Args = unknownCommand
+				sub.RunE = func(cmd *cobra.Command, args []string) error { return cmd.Help() }
+			}
+			walk(sub)
+		}
+	}
+	walk(root)
+
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		// A group cannot have the flag, so a word before it is the
+		// actual mistake: "ado metaa -o json".
+		if args := cmd.Flags().Args(); groups[cmd] && len(args) > 0 {
+			return unknownCommand(cmd, args)
+		}
+		return flagError(cmd, err)
+	})
+}
+
+// unknownCommand is the Args validator of groups: any argument is a
+// mistyped subcommand.
+func unknownCommand(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		return nil
+	}
+	return &UsageError{
+		Code:        codeUnknownCommand,
+		Name:        args[0],
+		Suggestions: cmd.SuggestionsFor(args[0]),
+		Err:         fmt.Errorf("unknown command %q for %q", args[0], cmd.CommandPath()),
+	}
+}
+
+// flagError adds the closest flag names of cmd to an unknown flag error.
+// Other flag errors are returned unchanged.
+func flagError(cmd *cobra.Command, err error) error {
+	msg := err.Error()
+	switch {
+	case strings.HasPrefix(msg, "unknown flag: --"):
+		name := strings.TrimPrefix(msg, "unknown flag: --")
+		return &UsageError{Code: codeUnknownFlag, Name: "--" + name, Suggestions: suggestFlags(cmd, name), Err: err}
+	case strings.HasPrefix(msg, "unknown shorthand flag: "):
+		// unknown shorthand flag: 'x' in -xyz
+		name, _, _ := strings.Cut(strings.TrimPrefix(msg, "unknown shorthand flag: "), " in ")
+		return &UsageError{Code: codeUnknownFlag, Name: "-" + strings.Trim(name, "'"), Err: err}
+	}
+	return err
+}
+
+// suggestFlags returns the visible flags of cmd, including inherited ones,
+// that start with name or are within suggestionDistance of it (1 for
+// names shorter than 5), prefix matches first. Dashes are ignored, so
+// --nonet finds --no-network.
+func suggestFlags(cmd *cobra.Command, name string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+	input := normalizeFlag(name)
+	maxDistance := suggestionDistance
+	if len(input) < 5 {
+		maxDistance = 1
+	}
+
+	var candidates []candidate
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		flag := normalizeFlag(f.Name)
+		d := levenshtein(input, flag)
+		if len(input) > 1 && strings.HasPrefix(flag, input) {
+			d = 0
+		}
+		if d <= maxDistance {
+			candidates = append(candidates, candidate{"--" + f.Name, d})
+		}
+	})
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		if a.distance != b.distance {
+			return a.distance - b.distance
+		}
+		return strings.Compare(a.name, b.name)
+	})
+
+	var names []string
+	for _, c := range candidates[:min(len(candidates), maxFlagSuggestions)] {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+func normalizeFlag(name string) string {
+	return strings.ToLower(strings.ReplaceAll(name, "-", ""))
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
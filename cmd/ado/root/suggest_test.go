@@ -0,0 +1,165 @@
+package root
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func runRoot(t *testing.T, args ...string) (stdout string, err error) {
+	t.Helper()
+	t.Setenv("ADO_NO_UPDATE_CHECK", "1")
+	cmd := NewRootCommand()
+	var out bytes.Buffer
+	cmd.SetOut(&out)
+	cmd.SetErr(&bytes.Buffer{})
+	err = execute(context.Background(), cmd, args)
+	return out.String(), err
+}
+
+func TestUnknownCommand(t *testing.T) {
+	tests := []struct {
+		args []string
+		msg  string
+		want []string
+	}{
+		{[]string{"metaa"}, `unknown command "metaa" for "ado"`, []string{"meta"}},
+		{[]string{"meta", "sytem"}, `unknown command "sytem" for "ado meta"`, []string{"system"}},
+		{[]string{"state", "gett", "key"}, `unknown command "gett" for "ado state"`, []string{"get", "set"}},
+		{[]string{"zzzzzz"}, `unknown command "zzzzzz" for "ado"`, nil},
+		// The mistyped command, not the flag it does not have, is reported.
+		{[]string{"metaa", "-o", "json"}, `unknown command "metaa" for "ado"`, []string{"meta"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			_, err := runRoot(t, tt.args...)
+			var usage *UsageError
+			if !errors.As(err, &usage) {
+				t.Fatalf("error = %v, want *UsageError", err)
+			}
+			if usage.Code != "unknown_command" || usage.Err.Error() != tt.msg || !slices.Equal(usage.Suggestions, tt.want) {
+				t.Errorf("UsageError = %+v (%v), want %q with %q", usage, usage.Err, tt.msg, tt.want)
+			}
+		})
+	}
+
+	_, err := runRoot(t, "meta", "sytem")
+	if want := "unknown command \"sytem\" for \"ado meta\"\n\nDid you mean this?\n\tsystem"; err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+}
+
+func TestGroupWithoutArgsPrintsHelp(t *testing.T) {
+	out, err := runRoot(t, "state")
+	if err != nil || !strings.Contains(out, "Available Commands:") {
+		t.Errorf("state = %q, %v", out, err)
+	}
+}
+
+func TestUnknownFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		name string
+		want []string
+	}{
+		{[]string{"meta", "system", "--outptu", "json"}, "--outptu", []string{"--output"}},
+		// Dashes are ignored when matching.
+		{[]string{"meta", "system", "--nonet"}, "--nonet", []string{"--no-network"}},
+		{[]string{"echo", "--sep", "x"}, "--sep", []string{"--separator", "--set"}},
+		{[]string{"echo", "--timeuot", "1s", "x"}, "--timeuot", []string{"--timeout"}},
+		{[]string{"echo", "--zzzzzz"}, "--zzzzzz", nil},
+		{[]string{"echo", "-q"}, "-q", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			_, err := runRoot(t, tt.args...)
+			var usage *UsageError
+			if !errors.As(err, &usage) || usage.Code != "unknown_flag" {
+				t.Fatalf("error = %v, want unknown_flag *UsageError", err)
+			}
+			if usage.Name != tt.name || !slices.Equal(usage.Suggestions, tt.want) {
+				t.Errorf("UsageError = %q %q, want %q %q", usage.Name, usage.Suggestions, tt.name, tt.want)
+			}
+		})
+	}
+
+	if _, err := runRoot(t, "echo", "--separator"); err == nil || errors.As(err, new(*UsageError)) {
+		t.Errorf("missing flag value error = %v, want a plain error", err)
+	}
+}
+
+func TestUsageError_StructuredOutput(t *testing.T) {
+	out, err := runRoot(t, "meta", "sytem", "--output", "json")
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	var got errorOutput
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("stdout is not JSON: %q", out)
+	}
+	want := errorDetail{
+		Code:     "unknown_command",
+		Message:  `unknown command "sytem" for "ado meta"`,
+		ExitCode: 1,
+		Details:  map[string]any{"name": "sytem", "suggestions": []any{"system"}},
+	}
+	if !reflect.DeepEqual(got.Error, want) {
+		t.Errorf("error = %+v, want %+v", got.Error, want)
+	}
+
+	out, _ = runRoot(t, "echo", "-o", "yaml", "--colr", "red", "x")
+	if !strings.Contains(out, "code: unknown_flag") || !strings.Contains(out, "- --color") {
+		t.Errorf("yaml output = %q", out)
+	}
+
+	if out, _ := runRoot(t, "metaa"); out != "" {
+		t.Errorf("text mode stdout = %q, want empty", out)
+	}
+}
+
+func TestOutputFlag(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, ""},
+		{[]string{"meta", "-o", "json"}, "json"},
+		{[]string{"--output", "yaml", "meta"}, "yaml"},
+		{[]string{"meta", "--output=json"}, "json"},
+		{[]string{"meta", "-ojson"}, "json"},
+		{[]string{"meta", "-o=yaml"}, "yaml"},
+		{[]string{"run", "x", "--", "-o", "json"}, ""},
+		{[]string{"meta", "-o"}, ""},
+	}
+	for _, tt := range tests {
+		if got := outputFlag(tt.args); got != tt.want {
+			t.Errorf("outputFlag(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"", "abc", 3},
+		{"output", "output", 0},
+		{"outptu", "output", 2},
+		{"colr", "color", 1},
+		{"kitten", "sitting", 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,382 @@
+package run
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/ghactions"
+	"github.com/anowarislam/ado/internal/idgen"
+	"github.com/anowarislam/ado/internal/secrets"
+	"github.com/anowarislam/ado/internal/tasks"
+	"github.com/anowarislam/ado/internal/ui"
+	"github.com/anowarislam/ado/internal/validation"
+)
+
+// NewCommand returns the run command, which lists and executes tasks
+// defined in the config file.
+func NewCommand() *cobra.Command {
+	var (
+		output       string
+		plan         bool
+		artifactsDir string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run [task] [-- args...]",
+		Short: "Run a task defined in the config file",
+		Long: `Run a named task from the tasks: section of the config file. Without a
+task name, list the available tasks.
+
+Task output is streamed as it is produced, and ado exits with the task's
+exit status. Arguments after -- are appended to the task's args. With
+--dry-run, the command line is printed instead of run.
+
+With --plan, nothing runs either: ado prints the task with its command
+line, working directory, and the variables it adds to or overrides in
+the environment, followed by the same for every task it runs with
+ado run, nested under it. Tasks run one at a time; a task started by
+another runs while that one waits for it.
+
+A task inherits all of ado's environment unless its env_policy is clean,
+passing only the variables programs need to run, such as PATH and HOME,
+or allowlist, passing those and the ones matching its env_allow globs.
+Its env is set on top; pass secrets there as secret://NAME references.
+
+A task with artifacts: globs, or any task with --artifacts-dir, saves its
+stdout and stderr and the files matching its globs in a directory for the
+run, with a manifest.json listing them. Tasks it runs with ado run save
+theirs there too; $ADO_ARTIFACTS_DIR holds the directory.
+
+Example config:
+  tasks:
+    test:
+      description: Run unit tests
+      command: go
+      args: [test, ./...]
+      env:
+        GOFLAGS: -count=1
+      cwd: .
+      artifacts: [coverage.out, "reports/**"]
+
+Examples:
+  # List tasks
+  ado run
+
+  # Run a task, passing extra arguments
+  ado run test -- -run TestFoo
+
+  # Save the output and artifacts of a task in ./out/DATE-ID
+  ado run test --artifacts-dir out
+
+  # Preview what a task and the tasks it runs would do
+  ado run release --plan
+
+  # Check the tasks without running them
+  ado run lint
+
+A task named like a subcommand of run, such as lint, runs with
+ado run -- lint.`,
+		Args:        cobra.ArbitraryArgs,
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveDefault
+			}
+			// Tasks named like a subcommand are left out: completing
+			// them would run the subcommand.
+			names, directive := completion.TaskNames(cmd, args, toComplete)
+			names = slices.DeleteFunc(names, func(name string) bool {
+				name, _, _ = strings.Cut(name, "\t")
+				sub, _, err := cmd.Find([]string{name})
+				return err == nil && sub != cmd
+			})
+			return names, directive
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			if len(args) == 0 {
+				if plan {
+					return fmt.Errorf("--plan needs a task to plan")
+				}
+				summaries := tasks.List(cfg.Tasks)
+				payload := map[string][]tasks.Summary{"tasks": summaries}
+				return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+					return formatTasks(summaries), nil
+				})
+			}
+
+			name := args[0]
+			task, ok := cfg.Tasks[name]
+			if !ok {
+				return unknownTaskError(name, cfg.Tasks)
+			}
+
+			runner := tasks.Runner{
+				Stdin:   cmd.InOrStdin(),
+				Stdout:  cmd.OutOrStdout(),
+				Stderr:  cmd.ErrOrStderr(),
+				Secrets: secrets.Default(),
+			}
+			if path != "" {
+				runner.BaseDir = filepath.Dir(path)
+			}
+			if plan {
+				step, err := runner.Plan(cfg.Tasks, name, args[1:])
+				if err != nil {
+					return err
+				}
+				err = ui.PrintOutput(cmd.OutOrStdout(), format, step, func() (string, error) {
+					return formatPlan(step), nil
+				})
+				if err == nil && !step.Valid() {
+					err = ui.Reported(fmt.Errorf("task %q runs a task that cannot run", name))
+				}
+				return err
+			}
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				argv, dir := runner.Describe(task, args[1:])
+				detail := ui.QuoteArgs(argv)
+				if dir != "" {
+					detail += " (in " + dir + ")"
+				}
+				plan := ui.NewPlan()
+				plan.Add("run", fmt.Sprintf("task %q", name), detail)
+				return ui.PrintPlan(cmd.OutOrStdout(), format, plan)
+			}
+			dir, created, err := artifactsRun(task, artifactsDir)
+			if err != nil {
+				return err
+			}
+			runner.ArtifactsDir = dir
+			err = runner.Run(cmd.Context(), name, task, args[1:])
+			if _, statErr := os.Stat(dir); created && statErr == nil {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Artifacts saved to %s\n", dir)
+			}
+			return err
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format for the task list, --plan, or --dry-run plan: text, json, yaml")
+	cmd.Flags().BoolVar(&plan, "plan", false, "Print what the task and the tasks it runs would do, without running them")
+	cmd.Flags().StringVar(&artifactsDir, "artifacts-dir", "", "Save the output and artifacts of the tasks in a directory for the run under this one (default: the state directory, for tasks with artifacts)")
+	_ = cmd.MarkFlagDirname("artifacts-dir")
+	cmd.AddCommand(newLintCommand())
+	return cmd
+}
+
+// lintReporter reports the results of ado run lint.
+var lintReporter = validation.Reporter{Title: "ado run lint", Subject: "Tasks", Rules: internalconfig.LintRules}
+
+func newLintCommand() *cobra.Command {
+	var (
+		filePath string
+		strict   bool
+		output   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check the tasks in the config file without running them",
+		Long: `Check the config file like ado config validate, and its tasks the way
+ado run uses them, reporting every problem with its line:
+
+  Errors     aliases and tasks that run an undefined task, as in
+             "ado run NAME", besides the errors of ado config validate
+  Warnings   unknown task keys, tasks named like a subcommand of run,
+             and ${...} references expanded into a shell script, as in
+             sh -c "deploy ${BRANCH}", where a value can inject commands
+
+ado exits 1 when there are errors, or with --strict, warnings. Like ado
+config validate, issues are GitHub Actions annotations when run there.
+
+Examples:
+  # Check the tasks of the config file in use
+  ado run lint
+
+  # Check another file, failing on warnings
+  ado run lint -f ci/tasks.yaml --strict`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output, validation.Formats...)
+			if err != nil {
+				return err
+			}
+
+			configFlag, _ := cmd.Root().PersistentFlags().GetString("config")
+			homeDir, _ := os.UserHomeDir()
+			resolver := internalconfig.PathResolverFor(cmd.Context(), configFlag, homeDir)
+			path := filePath
+			if path == "" {
+				resolved, sources := resolver.Resolve()
+				if resolved == "" {
+					return fmt.Errorf("no config file found. Searched: %s", strings.Join(sources, ", "))
+				}
+				path = resolved
+			}
+
+			var subcommands []string
+			for _, sub := range cmd.Parent().Commands() {
+				subcommands = append(subcommands, sub.Name())
+			}
+			result, err := internalconfig.LintTasks(path, resolver.Expansion(), subcommands)
+			if err != nil {
+				return fmt.Errorf("lint failed: %w", err)
+			}
+			if strict {
+				validation.Strict(result)
+			}
+			if err := lintReporter.Write(cmd.OutOrStdout(), format, result); err != nil {
+				return err
+			}
+			if ghactions.Enabled() {
+				if err := lintReporter.ReportToActions(cmd.OutOrStdout(), format, result); err != nil {
+					return err
+				}
+			}
+			if !result.Valid {
+				return ui.Reported(fmt.Errorf("%s: %d errors", path, len(result.Errors)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&filePath, "file", "f", "", "Config file to check instead of the one in use")
+	cmd.Flags().BoolVarP(&strict, "strict", "s", false, "Treat warnings as errors")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", validation.OutputHelp)
+	_ = cmd.MarkFlagFilename("file", "yaml", "yml")
+	_ = cmd.RegisterFlagCompletionFunc("output", completion.Fixed("text", "json", "yaml", "junit", "sarif", "tap"))
+	return cmd
+}
+
+// artifactsRun returns the artifacts directory of a run of task, given
+// --artifacts-dir, and whether the run created it. A run of ado run from a
+// task whose artifacts are saved uses the directory of that run; other
+// runs create one when --artifacts-dir is set or task has artifacts, and
+// otherwise save none.
+func artifactsRun(task internalconfig.Task, base string) (string, bool, error) {
+	if dir := os.Getenv(tasks.ArtifactsEnv); dir != "" {
+		return dir, false, nil
+	}
+	if base == "" && len(task.Artifacts) == 0 {
+		return "", false, nil
+	}
+	if base == "" {
+		state, err := internalconfig.StateDir()
+		if err != nil {
+			return "", false, fmt.Errorf("artifacts: %w", err)
+		}
+		base = filepath.Join(state, "artifacts")
+	}
+	// Tasks may run elsewhere, and pass the directory on to the tasks
+	// they run.
+	base, err := filepath.Abs(base)
+	if err != nil {
+		return "", false, err
+	}
+	gen, err := idgen.New(idgen.KindULID, idgen.Options{})
+	if err != nil {
+		return "", false, err
+	}
+	id, err := gen.Next()
+	if err != nil {
+		return "", false, err
+	}
+	return filepath.Join(base, time.Now().UTC().Format(time.DateOnly)+"-"+id), true, nil
+}
+
+func loadConfig(cmd *cobra.Command) (*internalconfig.Config, string, error) {
+	configPath, err := cmd.Root().PersistentFlags().GetString("config")
+	if err != nil {
+		return nil, "", err
+	}
+	homeDir, _ := os.UserHomeDir()
+	return internalconfig.PathResolverFor(cmd.Context(), configPath, homeDir).Load()
+}
+
+func unknownTaskError(name string, defined map[string]internalconfig.Task) error {
+	if len(defined) == 0 {
+		return fmt.Errorf("unknown task %q: no tasks defined in config", name)
+	}
+	names := make([]string, 0, len(defined))
+	for n := range defined {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return fmt.Errorf("unknown task %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+func formatTasks(summaries []tasks.Summary) string {
+	if len(summaries) == 0 {
+		return "No tasks defined. Add a tasks: section to the config file."
+	}
+
+	var b strings.Builder
+	fmt.Fprintln(&b, "Tasks:")
+	for _, s := range summaries {
+		line := strings.TrimSpace(s.Command + " " + strings.Join(s.Args, " "))
+		if s.Description != "" {
+			fmt.Fprintf(&b, "  %s: %s (%s)\n", s.Name, s.Description, line)
+		} else {
+			fmt.Fprintf(&b, "  %s: %s\n", s.Name, line)
+		}
+	}
+	return b.String()
+}
+
+// formatPlan renders step and the tasks it runs as an indented tree.
+func formatPlan(step tasks.Step) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Plan for task %q (tasks run one at a time, nothing was run):\n", step.Task)
+	writeStep(&b, step, "")
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func writeStep(b *strings.Builder, step tasks.Step, indent string) {
+	if step.Error != "" {
+		fmt.Fprintf(b, "%s%d. %s: %s\n", indent, step.Order, step.Task, step.Error)
+		return
+	}
+	fmt.Fprintf(b, "%s%d. %s: %s\n", indent, step.Order, step.Task, ui.QuoteArgs(append([]string{step.Command}, step.Args...)))
+	detail := indent + strings.Repeat(" ", len(strconv.Itoa(step.Order))+2)
+	if step.Dir != "" {
+		fmt.Fprintf(b, "%sin %s\n", detail, step.Dir)
+	}
+	removed := 0
+	for _, change := range step.Env {
+		switch change.Change {
+		case tasks.EnvRemove:
+			removed++
+		case tasks.EnvOverride:
+			fmt.Fprintf(b, "%senv ~ %s=%s (replaces the inherited value)\n", detail, change.Name, change.Value)
+		default:
+			fmt.Fprintf(b, "%senv + %s=%s\n", detail, change.Name, change.Value)
+		}
+	}
+	if step.EnvPolicy != "" {
+		fmt.Fprintf(b, "%senv - %d inherited variables (env_policy: %s)\n", detail, removed, step.EnvPolicy)
+	}
+	if len(step.Artifacts) > 0 {
+		fmt.Fprintf(b, "%sartifacts %s\n", detail, strings.Join(step.Artifacts, ", "))
+	}
+	for _, run := range step.Runs {
+		writeStep(b, run, detail)
+	}
+}
@@ -0,0 +1,329 @@
+package run
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/tasks"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+const testConfig = `version: 1
+tasks:
+  hello:
+    description: Say hello
+    command: sh
+    args: ["-c", "echo hello $0 from $(basename $PWD) $GREETING_SUFFIX"]
+    env:
+      GREETING_SUFFIX: "!"
+    cwd: work
+  fail:
+    command: sh
+    args: ["-c", "exit 7"]
+`
+
+// newTestRoot writes content as a config file and returns a root command
+// with run attached and --config pointing at it.
+func newTestRoot(t *testing.T, content string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "work"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().String("config", path, "")
+	root.PersistentFlags().Bool("dry-run", false, "")
+	root.AddCommand(NewCommand())
+
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	return root, &buf
+}
+
+func TestRun_List(t *testing.T) {
+	tests := []struct {
+		output string
+		want   []string
+	}{
+		{"text", []string{"Tasks:", "fail: sh -c exit 7", "hello: Say hello (sh -c"}},
+		{"json", []string{`"tasks"`, `"name": "fail"`, `"description": "Say hello"`}},
+		{"yaml", []string{"- name: fail", "description: Say hello"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			root, buf := newTestRoot(t, testConfig)
+			root.SetArgs([]string{"run", "--output", tt.output})
+			if err := root.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestRun_ListEmpty(t *testing.T) {
+	root, buf := newTestRoot(t, "version: 1\n")
+	root.SetArgs([]string{"run"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "No tasks defined") {
+		t.Errorf("output = %q", buf.String())
+	}
+}
+
+func TestRun_Task(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	root, buf := newTestRoot(t, testConfig)
+	root.SetArgs([]string{"run", "hello", "--", "world"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, want := buf.String(), "hello world from work !\n"; got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	root, buf := newTestRoot(t, testConfig)
+	root.SetArgs([]string{"run", "hello", "--dry-run", "--", "big world"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	out := buf.String()
+	for _, want := range []string{`Would run task "hello": sh -c "echo hello`, `"big world" (in `, "Dry run: nothing was changed."} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "hello big world from") {
+		t.Errorf("task ran under --dry-run:\n%s", out)
+	}
+}
+
+func TestRun_Plan(t *testing.T) {
+	content := testConfig + `  ci:
+    command: ado
+    args: [run, hello, --, there]
+  loop:
+    command: ado
+    args: [run, loop]
+  isolated:
+    command: ./deploy
+    env_policy: clean
+`
+	tests := []struct {
+		name    string
+		args    []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			"text",
+			[]string{"run", "ci", "--plan"},
+			[]string{`Plan for task "ci"`, "1. ci: ado run hello -- there\n", "   2. hello: sh -c \"echo hello", "      in " + string(filepath.Separator), "work\n", "      env + GREETING_SUFFIX=!\n"},
+			false,
+		},
+		{
+			"json",
+			[]string{"run", "hello", "--plan", "-o", "json", "--", "x"},
+			[]string{`"order": 1`, `"task": "hello"`, `"name": "GREETING_SUFFIX"`, `"change": "add"`},
+			false,
+		},
+		{"env policy", []string{"run", "isolated", "--plan"}, []string{" inherited variables (env_policy: clean)\n"}, false},
+		{"cycle", []string{"run", "loop", "--plan"}, []string{"2. loop: runs itself: loop -> loop"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GREETING_SUFFIX", "")
+			os.Unsetenv("GREETING_SUFFIX")
+			root, buf := newTestRoot(t, content)
+			root.SetArgs(tt.args)
+			err := root.Execute()
+			if (err != nil) != tt.wantErr || (err != nil && !ui.IsReported(err)) {
+				t.Fatalf("Execute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+
+	root, _ := newTestRoot(t, content)
+	root.SetArgs([]string{"run", "--plan"})
+	if err := root.Execute(); err == nil {
+		t.Error("--plan without a task succeeded")
+	}
+}
+
+func TestRun_Artifacts(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	base := t.TempDir()
+	root, buf := newTestRoot(t, testConfig)
+	root.SetArgs([]string{"run", "hello", "--artifacts-dir", base, "--", "world"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	runs, err := os.ReadDir(base)
+	if err != nil || len(runs) != 1 {
+		t.Fatalf("artifacts directory holds %v, %v; want one run", runs, err)
+	}
+	dir := filepath.Join(base, runs[0].Name())
+	if !strings.HasSuffix(buf.String(), "Artifacts saved to "+dir+"\n") {
+		t.Errorf("output = %q, want the artifacts directory", buf.String())
+	}
+	m, err := tasks.ReadManifest(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Tasks) != 1 || m.Tasks[0].Task != "hello" {
+		t.Fatalf("manifest = %+v", m)
+	}
+	if data, err := os.ReadFile(filepath.Join(dir, m.Tasks[0].Stdout)); err != nil || string(data) != "hello world from work !\n" {
+		t.Errorf("stdout.log = %q, %v", data, err)
+	}
+
+	// A task run from a task whose artifacts are saved adds to its run.
+	t.Setenv(tasks.ArtifactsEnv, dir)
+	root, buf = newTestRoot(t, testConfig)
+	root.SetArgs([]string{"run", "hello"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if strings.Contains(buf.String(), "Artifacts saved") {
+		t.Errorf("nested run reported the directory: %q", buf.String())
+	}
+	if m, err := tasks.ReadManifest(dir); err != nil || len(m.Tasks) != 2 || m.Tasks[1].Dir != "hello-2" {
+		t.Errorf("manifest = %+v, %v; want a second run of hello", m, err)
+	}
+}
+
+func TestRun_TaskExitCode(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	root, _ := newTestRoot(t, testConfig)
+	root.SetArgs([]string{"run", "fail"})
+	err := root.Execute()
+
+	var exitErr *tasks.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 7 {
+		t.Fatalf("Execute() error = %v, want exit status 7", err)
+	}
+}
+
+func TestRun_UnknownTask(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"lists available", testConfig, "unknown task \"nope\" (available: fail, hello)"},
+		{"none defined", "version: 1\n", "no tasks defined"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root, _ := newTestRoot(t, tt.content)
+			root.SetArgs([]string{"run", "nope"})
+			err := root.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_Completion(t *testing.T) {
+	root, _ := newTestRoot(t, testConfig)
+	cmd, _, err := root.Find([]string{"run"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names, directive := cmd.ValidArgsFunction(cmd, nil, "")
+	if directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("directive = %v", directive)
+	}
+	if len(names) != 2 || names[0] != "fail\t" || names[1] != "hello\tSay hello" {
+		t.Errorf("completions = %q", names)
+	}
+}
+
+func TestRunLint(t *testing.T) {
+	root, buf := newTestRoot(t, testConfig)
+	root.SetArgs([]string{"run", "lint"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "✓ Tasks valid: ") {
+		t.Errorf("output = %q", buf.String())
+	}
+
+	t.Setenv("LINT_ARGS", "--fast")
+	root, buf = newTestRoot(t, `version: 1
+aliases:
+  ship: run deploy
+tasks:
+  lint:
+    command: sh
+    args: [-c, "golangci-lint run ${LINT_ARGS}"]
+`)
+	root.SetArgs([]string{"run", "lint", "-o", "json"})
+	err := root.Execute()
+	if !ui.IsReported(err) {
+		t.Fatalf("Execute() error = %v, want a reported error", err)
+	}
+	var result internalconfig.ValidationResult
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, buf.String())
+	}
+	var rules []string
+	for _, issue := range append(result.Errors, result.Warnings...) {
+		rules = append(rules, fmt.Sprintf("%s@%d", issue.Rule, issue.Line))
+	}
+	if got, want := strings.Join(rules, " "), "undefined-task@3 shadowed-task@5 shell-interpolation@7"; got != want {
+		t.Errorf("issues = %s, want %s", got, want)
+	}
+}
+
+func TestRun_TaskNamedLikeSubcommand(t *testing.T) {
+	root, buf := newTestRoot(t, "version: 1\ntasks:\n  lint:\n    command: golangci-lint\n")
+	root.SetArgs([]string{"run", "--dry-run", "--", "lint"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `Would run task "lint": golangci-lint`) {
+		t.Errorf("output = %q", buf.String())
+	}
+}
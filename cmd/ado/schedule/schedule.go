@@ -0,0 +1,233 @@
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/logging"
+	"github.com/anowarislam/ado/internal/profiling"
+	"github.com/anowarislam/ado/internal/schedule"
+	"github.com/anowarislam/ado/internal/secrets"
+	"github.com/anowarislam/ado/internal/tasks"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// NewCommand returns the schedule command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schedule",
+		Short: "Run tasks on cron schedules",
+		Long: `Run tasks from the config file on cron expressions.
+
+Example config:
+  schedules:
+    - name: nightly-backup
+      task: backup
+      cron: "0 3 * * *"
+      jitter: 5m
+      overlap: skip`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return cmd.Help()
+		},
+	}
+
+	cmd.AddCommand(
+		newRunCommand(),
+		newListCommand(),
+	)
+
+	return cmd
+}
+
+func newRunCommand() *cobra.Command {
+	var (
+		runLog      string
+		gracePeriod time.Duration
+		pprofAddr   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "run",
+		Short: "Run the scheduler in the foreground",
+		Long: `Run configured schedules until interrupted.
+
+Each activation runs its task with the task's output streamed to stdout and
+stderr. Outcomes are appended to the run log as newline-delimited JSON.
+
+When a task is still running at its next activation, the schedule's
+overlap policy decides what happens: skip (default) records a skipped
+run, queue runs once more after the current run finishes, and allow
+starts another run alongside it.
+
+On SIGINT or SIGTERM no new runs start; running tasks get the grace
+period to finish before they are stopped.
+
+Examples:
+  # Run schedules, logging to the default run log
+  ado schedule run
+
+  # Log runs to stdout and stop tasks 5s after shutdown begins
+  ado schedule run --run-log - --grace-period 5s`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, path, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			jobs, err := schedule.Jobs(cfg)
+			if err != nil {
+				return err
+			}
+			if len(jobs) == 0 {
+				return errors.New("no schedules defined in config")
+			}
+
+			log, err := openRunLog(cmd, runLog)
+			if err != nil {
+				return err
+			}
+			defer log.Close()
+
+			ctx := cmd.Context()
+			if pprofAddr != "" {
+				if _, err := profiling.Listen(ctx, pprofAddr); err != nil {
+					return err
+				}
+			}
+
+			logger := logging.FromContext(ctx)
+			runner := tasks.Runner{
+				Stdout:  cmd.OutOrStdout(),
+				Stderr:  cmd.ErrOrStderr(),
+				Secrets: secrets.Default(),
+			}
+			if path != "" {
+				runner.BaseDir = filepath.Dir(path)
+			}
+
+			s := &schedule.Scheduler{
+				Jobs:        jobs,
+				GracePeriod: gracePeriod,
+				Run: func(ctx context.Context, job schedule.Job) error {
+					return runner.Run(ctx, job.TaskName, job.Task, nil)
+				},
+				Record: func(rec schedule.Record) {
+					logRecord(logger, rec)
+					if err := log.Write(rec); err != nil {
+						logger.Error("schedule: write run log", "error", err)
+					}
+				},
+			}
+
+			for _, summary := range schedule.Summarize(jobs, time.Now()) {
+				logger.Info("schedule: registered", "schedule", summary.Name, "task", summary.Task, "cron", summary.Cron, "next_run", summary.NextRun)
+			}
+			s.Start(ctx)
+			logger.Info("schedule: stopped")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&runLog, "run-log", "", `Run log path, or "-" for stdout (default: <log dir>/schedule.jsonl)`)
+	cmd.Flags().DurationVar(&gracePeriod, "grace-period", schedule.DefaultGracePeriod, "How long shutdown waits for running tasks before stopping them")
+	profiling.AddListenFlag(cmd, &pprofAddr)
+	return cmd
+}
+
+func newListCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List configured schedules and their next run",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			cfg, _, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			jobs, err := schedule.Jobs(cfg)
+			if err != nil {
+				return err
+			}
+
+			summaries := schedule.Summarize(jobs, time.Now())
+			payload := map[string][]schedule.Summary{"schedules": summaries}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatSchedules(summaries), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func loadConfig(cmd *cobra.Command) (*internalconfig.Config, string, error) {
+	configPath, err := cmd.Root().PersistentFlags().GetString("config")
+	if err != nil {
+		return nil, "", err
+	}
+	homeDir, _ := os.UserHomeDir()
+	return internalconfig.PathResolverFor(cmd.Context(), configPath, homeDir).Load()
+}
+
+// openRunLog opens the run log named by the --run-log flag.
+func openRunLog(cmd *cobra.Command, path string) (*schedule.RunLog, error) {
+	if path == "-" {
+		return schedule.NewRunLog(cmd.OutOrStdout()), nil
+	}
+	if path == "" {
+		path = filepath.Join(internalconfig.ResolveDirs().Log, "schedule.jsonl")
+	}
+	return schedule.OpenRunLog(path)
+}
+
+// logRecord reports a run outcome on the ado log.
+func logRecord(logger logging.Logger, rec schedule.Record) {
+	args := []any{"schedule", rec.Schedule, "task", rec.Task, "status", rec.Status}
+	if rec.ExitCode != nil {
+		args = append(args, "exit_code", *rec.ExitCode)
+	}
+	if rec.DurationMS > 0 {
+		args = append(args, "duration_ms", rec.DurationMS)
+	}
+	if rec.Error != "" {
+		args = append(args, "error", rec.Error)
+	}
+	if rec.Status == schedule.StatusSuccess {
+		logger.Info("schedule: run finished", args...)
+		return
+	}
+	logger.Warn("schedule: run finished", args...)
+}
+
+func formatSchedules(summaries []schedule.Summary) string {
+	if len(summaries) == 0 {
+		return "No schedules defined. Add a schedules: section to the config file."
+	}
+
+	var b strings.Builder
+	fmt.Fprintln(&b, "Schedules:")
+	for _, s := range summaries {
+		fmt.Fprintf(&b, "  %s: task %s, cron %q, overlap %s", s.Name, s.Task, s.Cron, s.Overlap)
+		if s.Jitter != "" {
+			fmt.Fprintf(&b, ", jitter %s", s.Jitter)
+		}
+		fmt.Fprintf(&b, "\n    next run: %s\n", s.NextRun.Format(time.RFC3339))
+	}
+	return b.String()
+}
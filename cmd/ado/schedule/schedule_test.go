@@ -0,0 +1,131 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/schedule"
+)
+
+const testConfig = `version: 1
+tasks:
+  hello:
+    command: sh
+    args: ["-c", "echo hello"]
+schedules:
+  - name: often
+    task: hello
+    cron: "@every 1s"
+  - task: hello
+    cron: "0 3 * * *"
+    jitter: 5m
+    overlap: queue
+`
+
+// newTestRoot writes content as a config file and returns a root command
+// with schedule attached and --config pointing at it.
+func newTestRoot(t *testing.T, content string) (*cobra.Command, *bytes.Buffer) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().String("config", path, "")
+	root.AddCommand(NewCommand())
+
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	return root, &buf
+}
+
+func TestScheduleList(t *testing.T) {
+	tests := []struct {
+		output string
+		want   []string
+	}{
+		{"text", []string{"Schedules:", `often: task hello, cron "@every 1s", overlap skip`, `hello: task hello, cron "0 3 * * *", overlap queue, jitter 5m0s`, "next run:"}},
+		{"json", []string{`"schedules"`, `"name": "often"`, `"jitter": "5m0s"`, `"next_run"`}},
+		{"yaml", []string{"schedules:", "name: often", "overlap: queue"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			root, buf := newTestRoot(t, testConfig)
+			root.SetArgs([]string{"schedule", "list", "-o", tt.output})
+			if err := root.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleList_Empty(t *testing.T) {
+	root, buf := newTestRoot(t, "version: 1\n")
+	root.SetArgs([]string{"schedule", "list"})
+	if err := root.Execute(); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "No schedules defined") {
+		t.Errorf("output = %q", buf.String())
+	}
+}
+
+func TestScheduleRun_NoSchedules(t *testing.T) {
+	root, _ := newTestRoot(t, "version: 1\n")
+	root.SetArgs([]string{"schedule", "run"})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), "no schedules defined") {
+		t.Errorf("Execute() error = %v", err)
+	}
+}
+
+func TestScheduleRun(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	root, buf := newTestRoot(t, testConfig)
+	runLog := filepath.Join(t.TempDir(), "logs", "schedule.jsonl")
+	root.SetArgs([]string{"schedule", "run", "--run-log", runLog, "--grace-period", "1s"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2500*time.Millisecond)
+	defer cancel()
+	if err := root.ExecuteContext(ctx); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "hello\n") {
+		t.Errorf("task output missing: %q", buf.String())
+	}
+	data, err := os.ReadFile(runLog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("run log has %d lines, want at least 2:\n%s", len(lines), data)
+	}
+	var rec schedule.Record
+	if err := json.Unmarshal([]byte(lines[0]), &rec); err != nil {
+		t.Fatal(err)
+	}
+	if rec.Schedule != "often" || rec.Status != schedule.StatusSuccess || rec.ExitCode == nil || *rec.ExitCode != 0 {
+		t.Errorf("record = %+v", rec)
+	}
+}
@@ -0,0 +1,180 @@
+package secret
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	"github.com/anowarislam/ado/internal/secrets"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// NewCommand returns the secret parent command with subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "secret",
+		Short: "Manage secrets stored in the OS keyring",
+		Long: `Store, read, list, and delete named secrets in the OS keyring: the macOS
+Keychain, Windows Credential Manager, or the Secret Service (GNOME Keyring,
+KWallet) on Linux.
+
+Secrets can be referenced as secret://NAME in task args and env values in
+the config file, and in 'ado http' request items and headers. References
+are resolved just before use and never written to disk.`,
+	}
+
+	cmd.AddCommand(
+		newSetCommand(),
+		newGetCommand(),
+		newListCommand(),
+		newDeleteCommand(),
+	)
+
+	return cmd
+}
+
+func newSetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "set NAME",
+		Short:       "Store a secret read from stdin",
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		Long: `Store a secret under NAME, replacing any existing value. The value is
+read from stdin so that it does not end up in shell history; one trailing
+newline is removed. On a terminal, ado prompts for a single line.
+
+Examples:
+  # Prompt for the value
+  ado secret set github-token
+
+  # Store the contents of a file
+  ado secret set deploy-key < id_ed25519`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			if err := secrets.ValidateName(name); err != nil {
+				return err
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				plan := ui.NewPlan()
+				plan.Add("store", fmt.Sprintf("secret %q", name), "value read from stdin")
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+
+			value, err := readValue(cmd, name)
+			if err != nil {
+				return err
+			}
+			if err := secrets.Default().Set(name, value); err != nil {
+				return err
+			}
+			_, err = fmt.Fprintf(cmd.ErrOrStderr(), "Stored secret %q\n", name)
+			return err
+		},
+	}
+	return cmd
+}
+
+// readValue reads the secret from stdin: one line when it is a terminal,
+// otherwise everything minus a single trailing newline.
+func readValue(cmd *cobra.Command, name string) (string, error) {
+	in := cmd.InOrStdin()
+	if ui.IsTerminal(in) {
+		fmt.Fprintf(cmd.ErrOrStderr(), "Value for %s: ", name)
+		line, err := bufio.NewReader(in).ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", fmt.Errorf("read value: %w", err)
+		}
+		return strings.TrimRight(line, "\r\n"), nil
+	}
+
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return "", fmt.Errorf("read value: %w", err)
+	}
+	value := strings.TrimSuffix(string(data), "\n")
+	return strings.TrimSuffix(value, "\r"), nil
+}
+
+func newGetCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "get NAME",
+		Short: "Print a secret",
+		Long: `Print the value of a secret to stdout, followed by a newline.
+
+Examples:
+  curl -H "Authorization: Bearer $(ado secret get github-token)" ...`,
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.FirstArg(completion.SecretNames(secrets.Default())),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			value, err := secrets.Default().Get(args[0])
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), value)
+			return err
+		},
+	}
+	return cmd
+}
+
+func newListCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List secret names",
+		Long:  "List the names of stored secrets. Values are never printed.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			names, err := secrets.Default().List()
+			if err != nil {
+				return err
+			}
+			payload := map[string][]string{"secrets": names}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				if len(names) == 0 {
+					return "No secrets stored. Add one with 'ado secret set NAME'.", nil
+				}
+				return strings.Join(names, "\n"), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func newDeleteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "delete NAME",
+		Short:             "Delete a secret",
+		Annotations:       map[string]string{ui.DryRunAnnotation: "true"},
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.FirstArg(completion.SecretNames(secrets.Default())),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				if _, err := secrets.Default().Get(args[0]); err != nil {
+					return err
+				}
+				plan := ui.NewPlan()
+				plan.Add("delete", fmt.Sprintf("secret %q", args[0]), "")
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+			if err := secrets.Default().Delete(args[0]); err != nil {
+				return err
+			}
+			_, err := fmt.Fprintf(cmd.ErrOrStderr(), "Deleted secret %q\n", args[0])
+			return err
+		},
+	}
+	return cmd
+}
@@ -0,0 +1,100 @@
+package secret
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/anowarislam/ado/internal/secrets"
+)
+
+func execute(t *testing.T, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+	cmd := NewCommand()
+	var stdout, stderr bytes.Buffer
+	cmd.SetOut(&stdout)
+	cmd.SetErr(&stderr)
+	cmd.SetIn(strings.NewReader(stdin))
+	cmd.SetArgs(args)
+	err := cmd.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestSecret_Lifecycle(t *testing.T) {
+	keyring.MockInit()
+
+	if out, _, err := execute(t, "", "list"); err != nil || !strings.Contains(out, "No secrets stored") {
+		t.Fatalf("list on empty keyring = %q, %v", out, err)
+	}
+
+	if _, stderr, err := execute(t, "s3cr3t\n", "set", "token"); err != nil || stderr != "Stored secret \"token\"\n" {
+		t.Fatalf("set = %q, %v", stderr, err)
+	}
+	if _, _, err := execute(t, "line1\nline2\n", "set", "multi"); err != nil {
+		t.Fatalf("set multi-line: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"token", "s3cr3t\n"},
+		{"multi", "line1\nline2\n"},
+	}
+	for _, tt := range tests {
+		if out, _, err := execute(t, "", "get", tt.name); err != nil || out != tt.want {
+			t.Errorf("get %s = %q, %v; want %q", tt.name, out, err, tt.want)
+		}
+	}
+
+	out, _, err := execute(t, "", "list", "-o", "json")
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	var payload map[string][]string
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if got := strings.Join(payload["secrets"], ","); got != "multi,token" {
+		t.Errorf("list = %q, want multi,token", got)
+	}
+
+	if _, _, err := execute(t, "", "delete", "token"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, _, err := execute(t, "", "get", "token"); !errors.Is(err, secrets.ErrNotFound) {
+		t.Errorf("get after delete error = %v, want ErrNotFound", err)
+	}
+	if out, _, _ := execute(t, "", "list"); out != "multi\n" {
+		t.Errorf("list after delete = %q", out)
+	}
+}
+
+func TestSecret_Errors(t *testing.T) {
+	keyring.MockInit()
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"invalid name", []string{"set", "a/b"}, "invalid secret name"},
+		{"missing secret", []string{"get", "nope"}, "secret not found: nope"},
+		{"delete missing", []string{"delete", "nope"}, "secret not found: nope"},
+		{"bad output", []string{"list", "-o", "xml"}, "unsupported output format"},
+		{"get without name", []string{"get"}, "accepts 1 arg(s)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := execute(t, "x", tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
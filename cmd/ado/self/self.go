@@ -0,0 +1,165 @@
+package self
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/ui"
+	"github.com/anowarislam/ado/internal/update"
+)
+
+// NewCommand returns the self parent command with subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "self",
+		Short: "Manage the ado installation",
+	}
+
+	cmd.AddCommand(
+		newUpdateCommand(),
+	)
+
+	return cmd
+}
+
+// UpdateResult is the outcome of `ado self update`.
+type UpdateResult struct {
+	CurrentVersion  string `json:"current_version" yaml:"current_version"`
+	LatestVersion   string `json:"latest_version" yaml:"latest_version"`
+	Channel         string `json:"channel" yaml:"channel"`
+	UpdateAvailable bool   `json:"update_available" yaml:"update_available"`
+	Updated         bool   `json:"updated" yaml:"updated"`
+	Signature       string `json:"signature,omitempty" yaml:"signature,omitempty"`
+	Path            string `json:"path,omitempty" yaml:"path,omitempty"`
+	ReleaseURL      string `json:"release_url,omitempty" yaml:"release_url,omitempty"`
+}
+
+func newUpdateCommand() *cobra.Command {
+	var (
+		channel string
+		check   bool
+		force   bool
+		output  string
+	)
+
+	cmd := &cobra.Command{
+		Use:         "update",
+		Short:       "Update ado to the latest release",
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		Long: `Download the latest GitHub release for this platform, verify it against the
+release checksums (and their Sigstore signature when cosign is installed),
+and atomically replace the running binary.
+
+Installations managed by Homebrew are refused; use brew upgrade instead.
+With --dry-run, ado checks for a release and reports what it would
+install without downloading it.
+
+Examples:
+  # Update to the latest stable release
+  ado self update
+
+  # Only report whether an update is available
+  ado self update --check
+
+  # Track prereleases
+  ado self update --channel prerelease`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			if err := update.ValidateChannel(channel); err != nil {
+				return err
+			}
+
+			executable, err := update.ResolveExecutable()
+			if err != nil {
+				return err
+			}
+			if !check && update.IsHomebrewManaged(executable) {
+				return update.ErrHomebrewManaged
+			}
+
+			ctx := cmd.Context()
+			client := update.NewClient()
+			release, err := client.Latest(ctx, channel)
+			if err != nil {
+				return fmt.Errorf("check for updates: %w", err)
+			}
+
+			result := UpdateResult{
+				CurrentVersion: internalmeta.CurrentBuildInfo().Version,
+				LatestVersion:  release.Version(),
+				Channel:        channel,
+				ReleaseURL:     release.HTMLURL,
+			}
+			result.UpdateAvailable = update.CompareVersions(result.CurrentVersion, result.LatestVersion) < 0
+
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun && !check {
+				plan := ui.NewPlan()
+				if result.UpdateAvailable || force {
+					plan.Add("install", executable, fmt.Sprintf("ado %s -> %s (%s channel)", result.CurrentVersion, result.LatestVersion, channel))
+				}
+				return ui.PrintPlan(cmd.OutOrStdout(), format, plan)
+			}
+
+			if !check && (result.UpdateAvailable || force) {
+				installer := update.Installer{
+					Client:   client,
+					Verifier: update.NewCosignVerifier(),
+					GOOS:     runtime.GOOS,
+					GOARCH:   runtime.GOARCH,
+				}
+				result.Signature, err = installer.Install(ctx, release, executable)
+				if err != nil {
+					return fmt.Errorf("update failed: %w", err)
+				}
+				result.Updated = true
+				result.Path = executable
+			}
+
+			return ui.PrintOutput(cmd.OutOrStdout(), format, result, func() (string, error) {
+				return formatUpdateResult(result), nil
+			})
+		},
+	}
+
+	cmd.Flags().StringVar(&channel, "channel", update.ChannelStable, "Release channel: stable, prerelease")
+	_ = cmd.RegisterFlagCompletionFunc("channel", completion.Fixed(update.ChannelStable, update.ChannelPrerelease))
+	cmd.Flags().BoolVar(&check, "check", false, "Only check for an update; do not install")
+	cmd.Flags().BoolVar(&force, "force", false, "Reinstall even when already up to date")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func formatUpdateResult(r UpdateResult) string {
+	var b strings.Builder
+
+	switch {
+	case r.Updated:
+		fmt.Fprintf(&b, "Updated ado %s -> %s (%s)\n", r.CurrentVersion, r.LatestVersion, r.Path)
+		switch r.Signature {
+		case update.SignatureVerified:
+			fmt.Fprintln(&b, "Checksum and signature verified")
+		case update.SignatureUnavailable:
+			fmt.Fprintln(&b, "Checksum verified; signature not checked (install cosign to verify)")
+		default:
+			fmt.Fprintln(&b, "Checksum verified; release is not signed")
+		}
+	case r.UpdateAvailable:
+		fmt.Fprintf(&b, "Update available: %s -> %s (%s channel)\n", r.CurrentVersion, r.LatestVersion, r.Channel)
+		if r.ReleaseURL != "" {
+			fmt.Fprintf(&b, "Release notes: %s\n", r.ReleaseURL)
+		}
+		fmt.Fprintln(&b, "Run `ado self update` to install")
+	default:
+		fmt.Fprintf(&b, "ado %s is up to date (%s channel, latest %s)\n", r.CurrentVersion, r.Channel, r.LatestVersion)
+	}
+
+	return b.String()
+}
@@ -0,0 +1,86 @@
+package self
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anowarislam/ado/internal/update"
+)
+
+func TestNewCommand(t *testing.T) {
+	cmd := NewCommand()
+
+	if cmd.Use != "self" {
+		t.Errorf("Use = %q, want %q", cmd.Use, "self")
+	}
+
+	subcommands := make(map[string]bool)
+	for _, sub := range cmd.Commands() {
+		subcommands[sub.Name()] = true
+	}
+	if !subcommands["update"] {
+		t.Error("expected subcommand \"update\" not found")
+	}
+}
+
+func TestUpdateCommand_InvalidFlags(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"bad channel", []string{"update", "--channel", "nightly"}, "invalid channel"},
+		{"bad output", []string{"update", "--output", "xml"}, "unsupported output format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCommand()
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatUpdateResult(t *testing.T) {
+	tests := []struct {
+		name   string
+		result UpdateResult
+		want   []string
+	}{
+		{
+			name:   "up to date",
+			result: UpdateResult{CurrentVersion: "1.2.0", LatestVersion: "1.2.0", Channel: "stable"},
+			want:   []string{"1.2.0 is up to date", "stable channel"},
+		},
+		{
+			name:   "available",
+			result: UpdateResult{CurrentVersion: "1.1.0", LatestVersion: "1.2.0", Channel: "stable", UpdateAvailable: true, ReleaseURL: "https://example.com/r"},
+			want:   []string{"Update available: 1.1.0 -> 1.2.0", "https://example.com/r", "ado self update"},
+		},
+		{
+			name:   "updated and verified",
+			result: UpdateResult{CurrentVersion: "1.1.0", LatestVersion: "1.2.0", Updated: true, Path: "/usr/local/bin/ado", Signature: update.SignatureVerified},
+			want:   []string{"Updated ado 1.1.0 -> 1.2.0 (/usr/local/bin/ado)", "signature verified"},
+		},
+		{
+			name:   "updated without cosign",
+			result: UpdateResult{Updated: true, Signature: update.SignatureUnavailable},
+			want:   []string{"install cosign"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := formatUpdateResult(tt.result)
+			for _, want := range tt.want {
+				if !strings.Contains(output, want) {
+					t.Errorf("output missing %q:\n%s", want, output)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+package serve
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/logging"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/profiling"
+	"github.com/anowarislam/ado/internal/secrets"
+	"github.com/anowarislam/ado/internal/server"
+)
+
+// Defaults for the serve flags.
+const (
+	DefaultAddr        = "127.0.0.1:8080"
+	DefaultGracePeriod = 10 * time.Second
+)
+
+// tokenEnv supplies the token when --token is not set.
+const tokenEnv = "ADO_SERVE_TOKEN"
+
+// NewCommand returns the serve command.
+func NewCommand(buildInfo internalmeta.BuildInfo) *cobra.Command {
+	var (
+		addr        string
+		token       string
+		insecure    bool
+		useGRPC     bool
+		gracePeriod time.Duration
+		pprofAddr   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve diagnostics over a local REST or gRPC API",
+		Long: `Serve ado diagnostics over HTTP so that fleet tooling can poll hosts
+instead of shelling out.
+
+Endpoints:
+  GET  /healthz              liveness probe, never requires a token
+  GET  /v1/meta/info         build metadata (ado meta info)
+  GET  /v1/meta/system       system diagnostics (ado meta system);
+                             ?no_network=true and ?security=true
+  POST /v1/config/validate   validate the YAML config in the request body
+
+Responses are JSON; errors are {"error": "..."}.
+
+With --grpc, the same address serves the ado.v1.AdoService gRPC API
+(proto/ado/v1/ado.proto) instead: GetBuildInfo, GetSystemInfo, ListTasks,
+and RunTask, which streams a task's stdout and stderr followed by its exit
+code. Tasks come from the config file, re-read on every call.
+
+With a token (--token or $ADO_SERVE_TOKEN, which may be a secret://NAME
+reference), /v1 requests and all gRPC calls must send "Authorization:
+Bearer TOKEN" (as call metadata for gRPC). Listening on a non-loopback
+address requires a token unless --insecure is given.
+
+On SIGINT or SIGTERM the server stops accepting connections and waits up to
+--grace-period for in-flight requests and task streams.
+
+Examples:
+  # Serve on localhost:8080
+  ado serve
+
+  # Serve on all interfaces with a token from the keyring
+  ado serve --addr :9100 --token secret://serve-token
+
+  # Query it
+  curl -H "Authorization: Bearer $TOKEN" http://host:9100/v1/meta/system?no_network=true
+
+  # Serve the gRPC API
+  ado serve --grpc --addr 127.0.0.1:9090`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			authToken, err := resolveToken(token)
+			if err != nil {
+				return err
+			}
+			if authToken == "" && !insecure && !server.IsLoopback(addr) {
+				return fmt.Errorf("refusing to serve on %s without a token; set --token or pass --insecure", addr)
+			}
+
+			ctx := cmd.Context()
+			if pprofAddr != "" {
+				if _, err := profiling.Listen(ctx, pprofAddr); err != nil {
+					return err
+				}
+			}
+
+			ln, err := net.Listen("tcp", addr)
+			if err != nil {
+				return fmt.Errorf("listen: %w", err)
+			}
+
+			log := logging.FromContext(ctx)
+			s := &server.Server{
+				Token:     authToken,
+				BuildInfo: buildInfo,
+				Logger:    log,
+			}
+
+			serve, scheme := s.Serve, "http://"
+			if useGRPC {
+				configPath, _ := cmd.Root().PersistentFlags().GetString("config")
+				homeDir, _ := os.UserHomeDir()
+				// Reread the config on each call, expanded like this command's.
+				expansion := internalconfig.PathResolverFor(cmd.Context(), configPath, homeDir).Expansion()
+				s.LoadConfig = func() (*internalconfig.Config, string, error) {
+					return internalconfig.NewPathResolver(configPath, homeDir).SetExpansion(expansion).Load()
+				}
+				s.Secrets = secrets.Default()
+				serve, scheme = s.ServeGRPC, "grpc://"
+			}
+			log.Info("Serving diagnostics", "addr", scheme+ln.Addr().String(), "auth", authToken != "")
+			if err := serve(ctx, ln, gracePeriod); err != nil && !errors.Is(err, net.ErrClosed) {
+				return err
+			}
+			log.Info("Server stopped")
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", DefaultAddr, "Address to listen on (host:port)")
+	cmd.Flags().StringVar(&token, "token", "", "Bearer token required on /v1 endpoints and gRPC calls (default $"+tokenEnv+")")
+	cmd.Flags().BoolVar(&useGRPC, "grpc", false, "Serve the gRPC API instead of REST")
+	cmd.Flags().BoolVar(&insecure, "insecure", false, "Allow serving on a non-loopback address without a token")
+	cmd.Flags().DurationVar(&gracePeriod, "grace-period", DefaultGracePeriod, "How long shutdown waits for in-flight requests")
+	profiling.AddListenFlag(cmd, &pprofAddr)
+	return cmd
+}
+
+// resolveToken falls back to $ADO_SERVE_TOKEN and expands secret://
+// references.
+func resolveToken(flag string) (string, error) {
+	if flag == "" {
+		flag = os.Getenv(tokenEnv)
+	}
+	token, err := secrets.Expand(secrets.Default(), flag)
+	if err != nil {
+		return "", fmt.Errorf("resolve token: %w", err)
+	}
+	return token, nil
+}
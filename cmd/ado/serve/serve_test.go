@@ -0,0 +1,95 @@
+package serve
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/secrets"
+)
+
+func execute(ctx context.Context, args ...string) error {
+	cmd := NewCommand(internalmeta.BuildInfo{Version: "test"})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+	cmd.SetArgs(args)
+	return cmd.ExecuteContext(ctx)
+}
+
+func TestServe_StopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := execute(ctx, "--addr", "127.0.0.1:0"); err != nil {
+		t.Errorf("Execute() error = %v", err)
+	}
+}
+
+func TestServe_GRPCStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := execute(ctx, "--grpc", "--addr", "127.0.0.1:0"); err != nil {
+		t.Errorf("Execute() error = %v", err)
+	}
+}
+
+func TestServe_Errors(t *testing.T) {
+	keyring.MockInit()
+	t.Setenv(tokenEnv, "")
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"public without token", []string{"--addr", ":0"}, "refusing to serve on :0 without a token"},
+		{"missing secret", []string{"--token", "secret://nope"}, "resolve token: resolve secret://nope"},
+		{"bad address", []string{"--addr", "not-an-addr", "--insecure"}, "listen:"},
+		{"extra args", []string{"now"}, "unknown command"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := execute(context.Background(), tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveToken(t *testing.T) {
+	keyring.MockInit()
+	if err := secrets.Default().Set("serve-token", "from-keyring"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		flag string
+		env  string
+		want string
+	}{
+		{"flag", "abc", "env", "abc"},
+		{"env fallback", "", "from-env", "from-env"},
+		{"secret reference", "secret://serve-token", "", "from-keyring"},
+		{"none", "", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(tokenEnv, tt.env)
+			got, err := resolveToken(tt.flag)
+			if err != nil {
+				t.Fatalf("resolveToken() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("resolveToken() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
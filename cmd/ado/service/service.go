@@ -0,0 +1,266 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	internalservice "github.com/anowarislam/ado/internal/service"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// options are the flags shared by the subcommands.
+type options struct {
+	system   bool
+	platform string
+}
+
+func (o *options) register(cmd *cobra.Command) {
+	cmd.Flags().BoolVar(&o.system, "system", false, "System-wide service instead of one for the current user (needs root)")
+	cmd.Flags().StringVar(&o.platform, "platform", internalservice.DefaultPlatform(), "Service manager: "+strings.Join(internalservice.Platforms, ", "))
+	_ = cmd.RegisterFlagCompletionFunc("platform", completion.Fixed(internalservice.Platforms...))
+}
+
+// manager runs systemctl and launchctl; tests replace it.
+var manager = &internalservice.Manager{}
+
+// NewCommand returns the service parent command with subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "service",
+		Short: "Install ado serve, schedule run, or agent run as an OS service",
+		Long: `Generate and install the OS service definition that keeps a
+long-running ado mode running: a systemd unit on Linux, a launchd agent
+or daemon on macOS, and a Windows service on Windows.
+
+Modes:
+  serve      ado serve, the diagnostics API
+  schedule   ado schedule run, the task scheduler
+  agent      ado agent run, the snapshot recorder
+
+Services run as the current user by default, from its systemd user
+manager or as a launchd agent; --system installs a system-wide unit or
+launchd daemon instead. Windows services are always system-wide.`,
+	}
+
+	cmd.AddCommand(
+		newInstallCommand(),
+		newUninstallCommand(),
+		newStatusCommand(),
+	)
+	return cmd
+}
+
+func newInstallCommand() *cobra.Command {
+	var (
+		opts       options
+		print      bool
+		executable string
+	)
+
+	cmd := &cobra.Command{
+		Use:         "install MODE [-- ARGS...]",
+		Short:       "Install and start a service running a long-running ado mode",
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		Long: `Write the service definition for MODE, enable it to start at boot or
+login, and (re)start it. Installing again replaces the definition, so
+rerun it after changing the arguments.
+
+The service runs this ado binary (or --executable) with the config file
+in use now, passed as --config so the service reads the same file
+whatever its user and working directory. ARGS after -- are added to the
+mode's command. The serve service sets ADO_FEATURES=serve.
+
+With --print the definition is printed instead of installed: the unit
+file, the property list, or the sc.exe commands for Windows.
+
+Examples:
+  # Run the scheduler for the current user
+  ado service install schedule
+
+  # A system-wide API on port 9000 with a token from the keyring
+  sudo ado service install serve --system -- --addr 0.0.0.0:9000 --token secret://serve-token
+
+  # Review the unit before installing it
+  ado service install serve --print`,
+		ValidArgsFunction: completion.FirstArg(completion.Fixed(internalservice.Modes...)),
+		Args: func(cmd *cobra.Command, args []string) error {
+			dash := cmd.ArgsLenAtDash()
+			if len(args) == 0 || dash == 0 {
+				return fmt.Errorf("requires a MODE: %s", strings.Join(internalservice.Modes, ", "))
+			}
+			if len(args) > 1 && dash != 1 {
+				return fmt.Errorf("unexpected argument %q: pass the mode's arguments after --", args[1])
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if executable == "" {
+				var err error
+				if executable, err = os.Executable(); err != nil {
+					return fmt.Errorf("locate ado binary: %w", err)
+				}
+			}
+			configPath, err := serviceConfigPath(cmd)
+			if err != nil {
+				return err
+			}
+			spec, err := internalservice.NewSpec(args[0], opts.platform, opts.system, executable, configPath, args[1:])
+			if err != nil {
+				return err
+			}
+
+			w := cmd.OutOrStdout()
+			if print {
+				_, err := w.Write(spec.Render())
+				return err
+			}
+			steps := spec.InstallSteps()
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				return ui.PrintPlan(w, ui.OutputText, stepPlan(steps))
+			}
+			if err := manager.Apply(cmd.Context(), spec, steps); err != nil {
+				return err
+			}
+
+			where := spec.Name()
+			if spec.Path() != "" {
+				where += " (" + spec.Path() + ")"
+			}
+			fmt.Fprintf(w, "Installed and started %s\n", where)
+			if spec.Platform == internalservice.PlatformSystemd && !spec.System {
+				fmt.Fprintln(w, "User services stop at logout unless lingering is on: loginctl enable-linger")
+			}
+			return nil
+		},
+	}
+
+	opts.register(cmd)
+	cmd.Flags().BoolVar(&print, "print", false, "Print the service definition instead of installing it")
+	cmd.Flags().StringVar(&executable, "executable", "", "ado binary the service runs (default: this one)")
+	return cmd
+}
+
+// serviceConfigPath returns the absolute path of the config file in use,
+// or "" when there is none.
+func serviceConfigPath(cmd *cobra.Command) (string, error) {
+	configPath, _ := cmd.Root().PersistentFlags().GetString("config")
+	homeDir, _ := os.UserHomeDir()
+	path, _ := internalconfig.PathResolverFor(cmd.Context(), configPath, homeDir).Resolve()
+	if path == "" {
+		return "", nil
+	}
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("config file: %w", err)
+	}
+	return filepath.Abs(path)
+}
+
+func newUninstallCommand() *cobra.Command {
+	var opts options
+
+	cmd := &cobra.Command{
+		Use:               "uninstall MODE",
+		Short:             "Stop and remove a service installed by ado service install",
+		Annotations:       map[string]string{ui.DryRunAnnotation: "true"},
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: completion.FirstArg(completion.Fixed(internalservice.Modes...)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			spec, err := internalservice.NewSpec(args[0], opts.platform, opts.system, "", "", nil)
+			if err != nil {
+				return err
+			}
+			steps := spec.UninstallSteps()
+			w := cmd.OutOrStdout()
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				return ui.PrintPlan(w, ui.OutputText, stepPlan(steps))
+			}
+			if err := manager.Apply(cmd.Context(), spec, steps); err != nil {
+				return err
+			}
+			fmt.Fprintf(w, "Removed %s\n", spec.Name())
+			return nil
+		},
+	}
+
+	opts.register(cmd)
+	return cmd
+}
+
+type statusOutput struct {
+	Services []internalservice.Status `json:"services" yaml:"services"`
+}
+
+func newStatusCommand() *cobra.Command {
+	var (
+		opts   options
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "status [MODE]",
+		Short: "Show whether the services are installed and running",
+		Long: `Show, for MODE or every mode, whether its service is installed, the
+service manager's state, and the process ID.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completion.FirstArg(completion.Fixed(internalservice.Modes...)),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			modes := internalservice.Modes
+			if len(args) == 1 {
+				modes = args
+			}
+			payload := statusOutput{Services: []internalservice.Status{}}
+			for _, mode := range modes {
+				spec, err := internalservice.NewSpec(mode, opts.platform, opts.system, "", "", nil)
+				if err != nil {
+					return err
+				}
+				payload.Services = append(payload.Services, manager.Status(cmd.Context(), spec))
+			}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				return formatStatus(payload.Services), nil
+			})
+		},
+	}
+
+	opts.register(cmd)
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func formatStatus(services []internalservice.Status) string {
+	lines := make([]string, len(services))
+	for i, st := range services {
+		var state string
+		switch {
+		case st.Error != "":
+			state = "error: " + st.Error
+		case !st.Installed:
+			state = "not installed"
+		default:
+			state = st.State
+			if st.PID > 0 {
+				state += fmt.Sprintf(", pid %d", st.PID)
+			}
+		}
+		lines[i] = fmt.Sprintf("%-9s %s: %s", st.Mode, st.Name, state)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func stepPlan(steps []internalservice.Step) *ui.Plan {
+	plan := ui.NewPlan()
+	for _, step := range steps {
+		plan.Add(step.Verb, step.Target, step.Detail)
+	}
+	return plan
+}
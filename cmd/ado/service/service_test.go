@@ -0,0 +1,121 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	internalservice "github.com/anowarislam/ado/internal/service"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// newTestRoot returns a root command with service attached, a config
+// file, user units under a temporary directory, and a log of the
+// systemctl commands run.
+func newTestRoot(t *testing.T) (*cobra.Command, *bytes.Buffer, *[]string, string) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", dir)
+	configPath := filepath.Join(dir, "config.yaml")
+	if err := os.WriteFile(configPath, []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var calls []string
+	saved := manager
+	manager = &internalservice.Manager{Run: func(_ context.Context, name string, args ...string) ([]byte, error) {
+		calls = append(calls, strings.Join(append([]string{name}, args...), " "))
+		return []byte("ActiveState=active\nSubState=running\nMainPID=77\n"), nil
+	}}
+	t.Cleanup(func() { manager = saved })
+
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().String("config", configPath, "")
+	root.PersistentFlags().Bool(ui.DryRunFlag, false, "")
+	root.AddCommand(NewCommand())
+	var buf bytes.Buffer
+	root.SetOut(&buf)
+	root.SetErr(&buf)
+	return root, &buf, &calls, configPath
+}
+
+func TestInstall_Print(t *testing.T) {
+	root, buf, calls, configPath := newTestRoot(t)
+	root.SetArgs([]string{"service", "install", "serve", "--platform", "systemd", "--executable", "/usr/bin/ado", "--print", "--", "--addr", "127.0.0.1:9000"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	want := "ExecStart=/usr/bin/ado --config " + configPath + " serve --addr 127.0.0.1:9000\n"
+	if !strings.Contains(buf.String(), want) || len(*calls) != 0 {
+		t.Errorf("output missing %q:\n%s", want, buf.String())
+	}
+}
+
+func TestInstall(t *testing.T) {
+	root, buf, calls, _ := newTestRoot(t)
+	root.SetArgs([]string{"service", "install", "schedule", "--platform", "systemd", "--executable", "/usr/bin/ado"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	unit := filepath.Join(os.Getenv("XDG_CONFIG_HOME"), "systemd", "user", "ado-schedule.service")
+	if data, err := os.ReadFile(unit); err != nil || !strings.Contains(string(data), " schedule run\n") {
+		t.Errorf("unit = %q, %v", data, err)
+	}
+	if !strings.Contains(buf.String(), "Installed and started ado-schedule.service ("+unit+")") {
+		t.Errorf("output = %q", buf.String())
+	}
+	if strings.Join(*calls, "; ") != "systemctl --user daemon-reload; systemctl --user enable ado-schedule.service; systemctl --user restart ado-schedule.service" {
+		t.Errorf("commands = %v", *calls)
+	}
+
+	buf.Reset()
+	root.SetArgs([]string{"service", "status", "--platform", "systemd"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	want := "serve     ado-serve.service: not installed\nschedule  ado-schedule.service: active (running), pid 77\nagent     ado-agent.service: not installed\n"
+	if buf.String() != want {
+		t.Errorf("status = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestInstall_DryRun(t *testing.T) {
+	root, buf, calls, _ := newTestRoot(t)
+	root.SetArgs([]string{"service", "uninstall", "serve", "--platform", "systemd", "--dry-run"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"Would run systemctl: --user disable --now ado-serve.service", "Would remove ", "Dry run: nothing was changed."} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("plan missing %q:\n%s", want, buf.String())
+		}
+	}
+	if len(*calls) != 0 {
+		t.Errorf("dry run ran %v", *calls)
+	}
+}
+
+func TestInstall_Args(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{nil, "requires a MODE"},
+		{[]string{"watch"}, `unknown mode "watch"`},
+		{[]string{"serve", "--addr", "x"}, "unknown flag: --addr"},
+		{[]string{"serve", "extra"}, `unexpected argument "extra"`},
+		{[]string{"serve", "--platform", "upstart"}, `unknown platform "upstart"`},
+	}
+	for _, tt := range tests {
+		root, _, _, _ := newTestRoot(t)
+		root.SetArgs(append([]string{"service", "install"}, tt.args...))
+		if err := root.Execute(); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: error = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+}
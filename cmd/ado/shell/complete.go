@@ -0,0 +1,179 @@
+package shell
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/alias"
+)
+
+// activeHelpPrefix marks cobra's active help lines in __complete output.
+const activeHelpPrefix = "_activeHelp_ "
+
+// complete completes the word before pos in line. It returns the new line
+// and cursor position, and the candidates to list when the word cannot be
+// completed further.
+func (s *session) complete(line string, pos int) (string, int, []string) {
+	before, after := line[:pos], line[pos:]
+	words, err := alias.Split(before)
+	if err != nil {
+		return line, pos, nil
+	}
+	// The word being completed starts after the last blank; quoted words
+	// with blanks are not completed.
+	start := strings.LastIndexAny(before, " \t") + 1
+	toComplete := before[start:]
+	if toComplete != "" {
+		words = words[:len(words)-1]
+	}
+	if len(words) > 0 && words[0] == "ado" {
+		words = words[1:]
+	}
+
+	candidates, noSpace := s.candidates(words, toComplete)
+	switch len(candidates) {
+	case 0:
+		return line, pos, nil
+	case 1:
+		word := quoteWord(candidates[0])
+		if !noSpace && !strings.HasSuffix(word, "/") && !strings.HasSuffix(word, "=") {
+			word += " "
+		}
+		return before[:start] + word + after, start + len(word), nil
+	}
+	if prefix := commonPrefix(candidates); len(prefix) > len(toComplete) {
+		return before[:start] + prefix + after, start + len(prefix), nil
+	}
+	return line, pos, candidates
+}
+
+// candidates returns the completions of toComplete after words: builtins
+// for the first word, then whatever `ado __complete` offers, falling back
+// to file names when the command allows them.
+func (s *session) candidates(words []string, toComplete string) ([]string, bool) {
+	var out []string
+	if len(words) == 0 {
+		for _, name := range builtinNames() {
+			if strings.HasPrefix(name, toComplete) {
+				out = append(out, name)
+			}
+		}
+	}
+
+	args := []string{cobra.ShellCompRequestCmd}
+	if s.config != "" && !hasFlag(words, "config", "") {
+		args = append(args, "--config", s.config)
+	}
+	args = append(append(args, words...), toComplete)
+	var buf bytes.Buffer
+	if err := s.run(s.ctx, args, &buf, io.Discard); err != nil {
+		return out, false
+	}
+
+	values, directive := parseCompletions(buf.String())
+	if directive&cobra.ShellCompDirectiveError != 0 {
+		return out, false
+	}
+	switch {
+	case directive&cobra.ShellCompDirectiveFilterFileExt != 0:
+		// The values are the file extensions to offer.
+		out = append(out, completeFiles(toComplete, values, false)...)
+	case directive&cobra.ShellCompDirectiveFilterDirs != 0:
+		out = append(out, completeFiles(toComplete, nil, true)...)
+	case len(values) == 0 && directive&cobra.ShellCompDirectiveNoFileComp == 0:
+		out = append(out, completeFiles(toComplete, nil, false)...)
+	default:
+		for _, v := range values {
+			if strings.HasPrefix(v, toComplete) {
+				out = append(out, v)
+			}
+		}
+	}
+	if directive&cobra.ShellCompDirectiveKeepOrder == 0 {
+		slices.Sort(out)
+	}
+	return slices.Compact(out), directive&cobra.ShellCompDirectiveNoSpace != 0
+}
+
+// parseCompletions reads the output of `ado __complete`: one candidate per
+// line, optionally followed by a tab and a description, then the directive
+// as ":N".
+func parseCompletions(out string) ([]string, cobra.ShellCompDirective) {
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if len(lines) == 0 || !strings.HasPrefix(lines[len(lines)-1], ":") {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	directive, err := strconv.Atoi(strings.TrimPrefix(lines[len(lines)-1], ":"))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	var values []string
+	for _, line := range lines[:len(lines)-1] {
+		if line == "" || strings.HasPrefix(line, activeHelpPrefix) {
+			continue
+		}
+		value, _, _ := strings.Cut(line, "\t")
+		values = append(values, value)
+	}
+	return values, cobra.ShellCompDirective(directive)
+}
+
+// completeFiles returns the paths starting with prefix. Directories end in
+// a separator; files are left out when dirsOnly is set or, given exts,
+// when they have none of those extensions.
+func completeFiles(prefix string, exts []string, dirsOnly bool) []string {
+	dir, base := filepath.Split(prefix)
+	entries, err := os.ReadDir(dirOrDot(dir))
+	if err != nil {
+		return nil
+	}
+	var out []string
+	for _, e := range entries {
+		name := e.Name()
+		if !strings.HasPrefix(name, base) || (strings.HasPrefix(name, ".") && !strings.HasPrefix(base, ".")) {
+			continue
+		}
+		switch {
+		case e.IsDir():
+			out = append(out, dir+name+string(filepath.Separator))
+		case dirsOnly:
+		case len(exts) == 0 || slices.Contains(exts, strings.TrimPrefix(filepath.Ext(name), ".")):
+			out = append(out, dir+name)
+		}
+	}
+	return out
+}
+
+func dirOrDot(dir string) string {
+	if dir == "" {
+		return "."
+	}
+	return dir
+}
+
+// quoteWord quotes s for alias.Split when it holds blanks or quotes.
+func quoteWord(s string) string {
+	if !strings.ContainsAny(s, " \t\"'\\") {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// commonPrefix returns the longest prefix shared by all of values.
+func commonPrefix(values []string) string {
+	prefix := values[0]
+	for _, v := range values[1:] {
+		for !strings.HasPrefix(v, prefix) {
+			prefix = prefix[:len(prefix)-1]
+		}
+	}
+	return prefix
+}
@@ -0,0 +1,90 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestComplete(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		wantLine string
+		wantList []string
+	}{
+		{"command", "ec", "echo ", nil},
+		{"subcommand", "meta sy", "meta system ", nil},
+		{"leading ado", "ado meta sy", "ado meta system ", nil},
+		{"ambiguous lists", "meta ", "meta ", []string{"env", "system"}},
+		{"common prefix", "e", "e", []string{"echo", "exit"}},
+		{"flag value", "meta system -o j", "meta system -o json ", nil},
+		{"no match", "zzz", "zzz", nil},
+		{"open quote", `echo "a`, `echo "a`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _ := newSession(&recorder{})
+			line, pos, list := s.complete(tt.line, len(tt.line))
+			if line != tt.wantLine || pos != len(tt.wantLine) {
+				t.Errorf("complete(%q) = %q at %d, want %q", tt.line, line, pos, tt.wantLine)
+			}
+			if !reflect.DeepEqual(list, tt.wantList) {
+				t.Errorf("complete(%q) listed %q, want %q", tt.line, list, tt.wantList)
+			}
+		})
+	}
+}
+
+func TestComplete_KeepsRestOfLine(t *testing.T) {
+	s, _, _ := newSession(&recorder{})
+	line, pos, _ := s.complete("meta sy -o json", len("meta sy"))
+	if line != "meta system  -o json" || pos != len("meta system ") {
+		t.Errorf("complete = %q at %d", line, pos)
+	}
+}
+
+func TestParseCompletions(t *testing.T) {
+	values, directive := parseCompletions("json\tJSON output\nyaml\n_activeHelp_ pick one\n:4\n")
+	if !reflect.DeepEqual(values, []string{"json", "yaml"}) || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("parseCompletions = %q, %d", values, directive)
+	}
+	if _, directive := parseCompletions("garbage"); directive != cobra.ShellCompDirectiveError {
+		t.Errorf("directive for garbage = %d", directive)
+	}
+}
+
+func TestCompleteFiles(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"a.yaml", "a.txt", ".hidden.yaml"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Mkdir(filepath.Join(dir, "adir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	prefix := dir + string(filepath.Separator) + "a"
+	sep := string(filepath.Separator)
+
+	tests := []struct {
+		name     string
+		exts     []string
+		dirsOnly bool
+		want     []string
+	}{
+		{"all", nil, false, []string{prefix + ".txt", prefix + ".yaml", prefix + "dir" + sep}},
+		{"extensions", []string{"yaml"}, false, []string{prefix + ".yaml", prefix + "dir" + sep}},
+		{"dirs only", nil, true, []string{prefix + "dir" + sep}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := completeFiles(prefix, tt.exts, tt.dirsOnly); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("completeFiles = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package shell
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// maxHistory bounds the lines kept in memory and in the history file.
+const maxHistory = 1000
+
+// history implements term.History, appending each line to a file so it
+// survives the session. Lines starting with a space are not recorded,
+// like HISTCONTROL=ignorespace in bash, and neither are repeats of the
+// previous line.
+type history struct {
+	// path is the history file; empty keeps history in memory only.
+	path string
+	max  int
+	// entries are oldest first.
+	entries []string
+}
+
+// load reads the history file, keeping the newest max lines. A file that
+// grew past twice that is rewritten so it stays bounded.
+func (h *history) load() {
+	f, err := os.Open(h.path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	if len(lines) > h.max {
+		h.entries = lines[len(lines)-h.max:]
+	} else {
+		h.entries = lines
+	}
+	if len(lines) > 2*h.max {
+		_ = os.WriteFile(h.path, []byte(strings.Join(h.entries, "\n")+"\n"), 0o600)
+	}
+}
+
+// Add implements term.History.
+func (h *history) Add(entry string) {
+	if strings.TrimSpace(entry) == "" || strings.HasPrefix(entry, " ") {
+		return
+	}
+	if n := len(h.entries); n > 0 && h.entries[n-1] == entry {
+		return
+	}
+	h.entries = append(h.entries, entry)
+	if len(h.entries) > h.max {
+		h.entries = h.entries[len(h.entries)-h.max:]
+	}
+	if h.path != "" {
+		h.save(entry)
+	}
+}
+
+// save appends entry to the history file. Failing to record history is
+// not worth interrupting the session, so errors are ignored.
+func (h *history) save(entry string) {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o700); err != nil {
+		return
+	}
+	f, err := os.OpenFile(h.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	_, _ = f.WriteString(entry + "\n")
+}
+
+// Len implements term.History.
+func (h *history) Len() int { return len(h.entries) }
+
+// At implements term.History; 0 is the newest entry.
+func (h *history) At(idx int) string { return h.entries[len(h.entries)-1-idx] }
@@ -0,0 +1,46 @@
+package shell
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHistory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", historyFile)
+	h := &history{path: path, max: 3}
+	for _, line := range []string{"meta system", "meta system", "", " secret get token", "echo a", "echo b", "echo c"} {
+		h.Add(line)
+	}
+
+	if h.Len() != 3 || h.At(0) != "echo c" || h.At(2) != "echo a" {
+		t.Errorf("entries = %q", h.entries)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "meta system\necho a\necho b\necho c\n"; string(data) != want {
+		t.Errorf("history file = %q, want %q", data, want)
+	}
+
+	loaded := &history{path: path, max: 3}
+	loaded.load()
+	if strings.Join(loaded.entries, ",") != "echo a,echo b,echo c" {
+		t.Errorf("loaded = %q", loaded.entries)
+	}
+}
+
+func TestHistory_LoadTruncates(t *testing.T) {
+	path := filepath.Join(t.TempDir(), historyFile)
+	if err := os.WriteFile(path, []byte("1\n2\n3\n4\n5\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	h := &history{path: path, max: 2}
+	h.load()
+	data, _ := os.ReadFile(path)
+	if strings.Join(h.entries, ",") != "4,5" || string(data) != "4\n5\n" {
+		t.Errorf("entries = %q, file = %q", h.entries, data)
+	}
+}
@@ -0,0 +1,339 @@
+package shell
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/anowarislam/ado/internal/alias"
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// RunFunc runs one ado command line, given without the leading "ado", as
+// a separate invocation writing to stdout and stderr.
+type RunFunc func(ctx context.Context, args []string, stdout, stderr io.Writer) error
+
+// prompt is shown before each line in interactive mode.
+const prompt = "ado> "
+
+// historyFile is the name of the history file in the state directory.
+const historyFile = "shell_history"
+
+type activeKey struct{}
+
+// Active reports whether ctx belongs to a command run from `ado shell`.
+func Active(ctx context.Context) bool {
+	return ctx != nil && ctx.Value(activeKey{}) != nil
+}
+
+// NewCommand returns the shell command. run executes each line.
+func NewCommand(run RunFunc) *cobra.Command {
+	var (
+		output    string
+		noHistory bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Run ado commands interactively",
+		Long: `Start an interactive session that runs ado commands without typing "ado"
+each time. Lines are split like a shell command (quotes and backslashes,
+no expansion), and a leading "ado" is ignored.
+
+In a terminal the session has line editing, history (Up/Down, saved in
+the state directory across sessions), and Tab completion of commands,
+flags, and values. Without a terminal, lines are read from stdin.
+
+Settings persist for the session and apply to every command:
+  set output FORMAT   Add --output FORMAT to commands that accept it
+  set config PATH     Use this config file (the session profile)
+  set                 Show the settings
+  unset NAME          Clear a setting
+
+Other builtins: history, exit (or Ctrl-D).
+
+Examples:
+  ado shell
+  ado> set output json
+  ado> meta system
+  ado> meta env --all
+
+  # Start with JSON output and a team profile
+  ado --config team.yaml shell -o json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if Active(cmd.Context()) {
+				return errors.New("already in ado shell")
+			}
+			if output != "" {
+				if _, err := ui.ParseOutputFormat(output); err != nil {
+					return err
+				}
+			}
+			configPath, _ := cmd.Root().PersistentFlags().GetString("config")
+			s := &session{
+				root:    cmd.Root(),
+				run:     run,
+				ctx:     context.WithValue(cmd.Context(), activeKey{}, true),
+				stdout:  cmd.OutOrStdout(),
+				stderr:  cmd.ErrOrStderr(),
+				output:  output,
+				config:  configPath,
+				history: &history{max: maxHistory},
+			}
+
+			in, ok := cmd.InOrStdin().(*os.File)
+			if !ok || !term.IsTerminal(int(in.Fd())) {
+				return s.runScript(cmd.InOrStdin())
+			}
+			if !noHistory {
+				if dir, err := config.StateDir(); err == nil {
+					s.history.path = filepath.Join(dir, historyFile)
+					s.history.load()
+				}
+			}
+			return s.runInteractive(in)
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Initial session output format: text, json, yaml")
+	cmd.Flags().BoolVar(&noHistory, "no-history", false, "Neither load nor save command history")
+	return cmd
+}
+
+// session is the state of one `ado shell`.
+type session struct {
+	root    *cobra.Command
+	run     RunFunc
+	ctx     context.Context
+	stdout  io.Writer
+	stderr  io.Writer
+	output  string
+	config  string
+	history *history
+}
+
+// errExit ends the session.
+var errExit = errors.New("exit")
+
+// runScript runs the lines of r without a prompt.
+func (s *session) runScript(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if err := s.ctx.Err(); err != nil {
+			return context.Cause(s.ctx)
+		}
+		if err := s.execLine(scanner.Text()); errors.Is(err, errExit) {
+			return nil
+		}
+	}
+	return scanner.Err()
+}
+
+// runInteractive reads lines from the terminal in until exit or EOF. The
+// terminal is in raw mode only while a line is edited, so commands see a
+// normal terminal.
+func (s *session) runInteractive(in *os.File) error {
+	fd := int(in.Fd())
+	t := term.NewTerminal(struct {
+		io.Reader
+		io.Writer
+	}{in, s.stdout}, prompt)
+	t.History = s.history
+	t.AutoCompleteCallback = func(line string, pos int, key rune) (string, int, bool) {
+		if key != '\t' {
+			return "", 0, false
+		}
+		newLine, newPos, list := s.complete(line, pos)
+		if len(list) > 0 {
+			fmt.Fprintf(t, "%s\n", strings.Join(list, "  "))
+		}
+		return newLine, newPos, true
+	}
+	if width, height, err := term.GetSize(fd); err == nil && width > 0 {
+		_ = t.SetSize(width, height)
+	}
+
+	for {
+		if err := s.ctx.Err(); err != nil {
+			return context.Cause(s.ctx)
+		}
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return fmt.Errorf("set terminal raw mode: %w", err)
+		}
+		line, err := t.ReadLine()
+		_ = term.Restore(fd, state)
+		if errors.Is(err, io.EOF) {
+			fmt.Fprintln(s.stdout)
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read line: %w", err)
+		}
+		if err := s.execLine(line); errors.Is(err, errExit) {
+			return nil
+		}
+	}
+}
+
+// execLine runs a builtin or an ado command. Command errors are printed,
+// not returned, so the session continues; only errExit is returned.
+func (s *session) execLine(line string) error {
+	args, err := alias.Split(line)
+	if err != nil {
+		fmt.Fprintf(s.stderr, "parse: %v\n", err)
+		return nil
+	}
+	if len(args) > 0 && args[0] == "ado" {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return nil
+	}
+
+	if builtin, ok := builtins[args[0]]; ok {
+		if err := builtin(s, args[1:]); err != nil {
+			if errors.Is(err, errExit) {
+				return err
+			}
+			fmt.Fprintln(s.stderr, err)
+		}
+		return nil
+	}
+
+	if err := s.run(s.ctx, s.commandArgs(args), s.stdout, s.stderr); err != nil {
+		fmt.Fprintln(s.stderr, err)
+	}
+	return nil
+}
+
+// commandArgs adds the session settings to args unless the line sets them
+// itself. --output is only added for commands that accept it.
+func (s *session) commandArgs(args []string) []string {
+	var out []string
+	if s.config != "" && !hasFlag(args, "config", "") {
+		out = append(out, "--config", s.config)
+	}
+	if s.output != "" && !hasFlag(args, "output", "o") {
+		if target, _, err := s.root.Find(args); err == nil && target.Flags().Lookup("output") != nil {
+			// Keep everything after "--" for the command's own arguments.
+			if i := slices.Index(args, "--"); i >= 0 {
+				return append(append(append(out, args[:i]...), "--output", s.output), args[i:]...)
+			}
+			return append(append(out, args...), "--output", s.output)
+		}
+	}
+	return append(out, args...)
+}
+
+// hasFlag reports whether args set --long or -short before any "--".
+func hasFlag(args []string, long, short string) bool {
+	for _, arg := range args {
+		switch {
+		case arg == "--":
+			return false
+		case arg == "--"+long || strings.HasPrefix(arg, "--"+long+"="):
+			return true
+		case short != "" && strings.HasPrefix(arg, "-"+short) && !strings.HasPrefix(arg, "--"):
+			return true
+		}
+	}
+	return false
+}
+
+// builtins are the session commands handled by the shell itself. They
+// take precedence over ado commands of the same name.
+var builtins = map[string]func(s *session, args []string) error{
+	"exit":    exitBuiltin,
+	"quit":    exitBuiltin,
+	"set":     setBuiltin,
+	"unset":   unsetBuiltin,
+	"history": historyBuiltin,
+}
+
+func exitBuiltin(*session, []string) error { return errExit }
+
+// settings lists the session settings set and unset accept.
+var settings = []string{"config", "output"}
+
+func setBuiltin(s *session, args []string) error {
+	switch len(args) {
+	case 0:
+		fmt.Fprintf(s.stdout, "config  %s\n", orNone(s.config))
+		fmt.Fprintf(s.stdout, "output  %s\n", orNone(s.output))
+		return nil
+	case 2:
+	default:
+		return errors.New("usage: set NAME VALUE (NAME is config or output)")
+	}
+
+	switch name, value := args[0], args[1]; name {
+	case "output":
+		if _, err := ui.ParseOutputFormat(value); err != nil {
+			return err
+		}
+		s.output = value
+	case "config":
+		if _, err := os.Stat(value); err != nil {
+			return fmt.Errorf("config %s: %w", value, err)
+		}
+		s.config = value
+	default:
+		return fmt.Errorf("unknown setting %q (use %s)", name, strings.Join(settings, ", "))
+	}
+	return nil
+}
+
+func unsetBuiltin(s *session, args []string) error {
+	if len(args) != 1 {
+		return errors.New("usage: unset NAME (NAME is config or output)")
+	}
+	switch args[0] {
+	case "output":
+		s.output = ""
+	case "config":
+		s.config = ""
+	default:
+		return fmt.Errorf("unknown setting %q (use %s)", args[0], strings.Join(settings, ", "))
+	}
+	return nil
+}
+
+func historyBuiltin(s *session, args []string) error {
+	if len(args) != 0 {
+		return errors.New("usage: history")
+	}
+	for i := s.history.Len() - 1; i >= 0; i-- {
+		fmt.Fprintf(s.stdout, "%5d  %s\n", s.history.Len()-i, s.history.At(i))
+	}
+	return nil
+}
+
+func orNone(s string) string {
+	if s == "" {
+		return "(none)"
+	}
+	return s
+}
+
+// builtinNames returns the builtin names in order, for completion.
+func builtinNames() []string {
+	names := make([]string, 0, len(builtins))
+	for name := range builtins {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,199 @@
+package shell
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func noop(*cobra.Command, []string) {}
+
+// testRoot is a small command tree standing in for ado's.
+func testRoot() *cobra.Command {
+	root := &cobra.Command{Use: "ado"}
+	root.PersistentFlags().String("config", "", "")
+
+	meta := &cobra.Command{Use: "meta"}
+	system := &cobra.Command{Use: "system", Run: noop}
+	system.Flags().StringP("output", "o", "text", "")
+	_ = system.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"text", "json", "yaml"}, cobra.ShellCompDirectiveNoFileComp))
+	meta.AddCommand(system, &cobra.Command{Use: "env", Run: noop})
+
+	echo := &cobra.Command{
+		Use: "echo",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cmd.Println(strings.Join(args, " "))
+			return nil
+		},
+	}
+	run := &cobra.Command{Use: "run", Run: noop}
+	run.Flags().StringP("output", "o", "text", "")
+
+	root.AddCommand(meta, echo, run)
+	return root
+}
+
+// recorder runs lines against testRoot and records their arguments.
+type recorder struct {
+	calls [][]string
+}
+
+func (r *recorder) run(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 || args[0] != cobra.ShellCompRequestCmd {
+		r.calls = append(r.calls, args)
+	}
+	root := testRoot()
+	root.SetOut(stdout)
+	root.SetErr(stderr)
+	root.SetArgs(args)
+	return root.ExecuteContext(ctx)
+}
+
+func newSession(r *recorder) (*session, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, stderr bytes.Buffer
+	return &session{
+		root:    testRoot(),
+		run:     r.run,
+		ctx:     context.WithValue(context.Background(), activeKey{}, true),
+		stdout:  &stdout,
+		stderr:  &stderr,
+		history: &history{max: maxHistory},
+	}, &stdout, &stderr
+}
+
+func TestCommandArgs(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		config string
+		args   []string
+		want   []string
+	}{
+		{"no settings", "", "", []string{"meta", "system"}, []string{"meta", "system"}},
+		{"output added", "json", "", []string{"meta", "system"}, []string{"meta", "system", "--output", "json"}},
+		{"output on line wins", "json", "", []string{"meta", "system", "-o", "yaml"}, []string{"meta", "system", "-o", "yaml"}},
+		{"no output flag", "json", "", []string{"meta", "env"}, []string{"meta", "env"}},
+		{"before dashdash", "json", "", []string{"run", "build", "--", "-o", "x"}, []string{"run", "build", "--output", "json", "--", "-o", "x"}},
+		{"config added", "", "team.yaml", []string{"echo", "hi"}, []string{"--config", "team.yaml", "echo", "hi"}},
+		{"config on line wins", "", "team.yaml", []string{"--config=x.yaml", "echo"}, []string{"--config=x.yaml", "echo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _ := newSession(&recorder{})
+			s.output, s.config = tt.output, tt.config
+			if got := s.commandArgs(tt.args); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commandArgs(%q) = %q, want %q", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecLine(t *testing.T) {
+	r := &recorder{}
+	s, stdout, stderr := newSession(r)
+
+	for _, line := range []string{"", "  ", `ado echo "hello world"`, "set output json", "meta system", "nosuch", `echo "open`} {
+		if err := s.execLine(line); err != nil {
+			t.Fatalf("execLine(%q) = %v", line, err)
+		}
+	}
+	want := [][]string{{"echo", "hello world"}, {"meta", "system", "--output", "json"}, {"nosuch"}}
+	if !reflect.DeepEqual(r.calls, want) {
+		t.Errorf("calls = %q, want %q", r.calls, want)
+	}
+	if stdout.String() != "hello world\n" {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+	for _, msg := range []string{"unknown command \"nosuch\"", "parse: unterminated \" quote"} {
+		if !strings.Contains(stderr.String(), msg) {
+			t.Errorf("stderr missing %q:\n%s", msg, stderr.String())
+		}
+	}
+
+	if err := s.execLine("exit"); err != errExit {
+		t.Errorf("exit = %v, want errExit", err)
+	}
+}
+
+func TestBuiltins(t *testing.T) {
+	config := filepath.Join(t.TempDir(), "team.yaml")
+	if err := os.WriteFile(config, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"set output", []string{"set", "output", "yaml"}, ""},
+		{"set config", []string{"set", "config", config}, ""},
+		{"bad output", []string{"set", "output", "xml"}, "xml"},
+		{"missing config", []string{"set", "config", "/nonexistent/ado.yaml"}, "no such file"},
+		{"unknown setting", []string{"set", "color", "on"}, "unknown setting"},
+		{"set usage", []string{"set", "output"}, "usage: set"},
+		{"unset usage", []string{"unset"}, "usage: unset"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _, _ := newSession(&recorder{})
+			err := builtins[tt.args[0]](s, tt.args[1:])
+			if tt.wantErr == "" && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	s, stdout, _ := newSession(&recorder{})
+	_ = setBuiltin(s, []string{"output", "json"})
+	_ = setBuiltin(s, nil)
+	if want := "config  (none)\noutput  json\n"; stdout.String() != want {
+		t.Errorf("set = %q, want %q", stdout.String(), want)
+	}
+	_ = unsetBuiltin(s, []string{"output"})
+	if s.output != "" {
+		t.Errorf("output after unset = %q", s.output)
+	}
+}
+
+func TestCommand_Script(t *testing.T) {
+	r := &recorder{}
+	root := &cobra.Command{Use: "ado"}
+	root.PersistentFlags().String("config", "", "")
+	root.AddCommand(NewCommand(r.run))
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetIn(strings.NewReader("echo one\nset output json\nexit\necho two\n"))
+	root.SetArgs([]string{"shell"})
+	if err := root.Execute(); err != nil {
+		t.Fatal(err)
+	}
+	if want := [][]string{{"echo", "one"}}; !reflect.DeepEqual(r.calls, want) {
+		t.Errorf("calls = %q, want %q", r.calls, want)
+	}
+	if out.String() != "one\n" {
+		t.Errorf("output = %q", out.String())
+	}
+}
+
+func TestCommand_Nested(t *testing.T) {
+	cmd := NewCommand((&recorder{}).run)
+	cmd.SetArgs(nil)
+	cmd.SetOut(io.Discard)
+	cmd.SetErr(io.Discard)
+	err := cmd.ExecuteContext(context.WithValue(context.Background(), activeKey{}, true))
+	if err == nil || !strings.Contains(err.Error(), "already in ado shell") {
+		t.Errorf("nested shell error = %v", err)
+	}
+}
@@ -0,0 +1,274 @@
+package shellinit
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/update"
+)
+
+// Shells lists the shells ado init supports.
+var Shells = []string{"bash", "zsh", "fish", "powershell"}
+
+// Options selects the parts of the integration script.
+type Options struct {
+	// Completion includes the completion script.
+	Completion bool
+	// Aliases are short names defined as shell functions (aliases in
+	// PowerShell) that run ado, with completion when enabled.
+	Aliases []string
+	// Prompt adds a hook that prints a notice before the prompt once a
+	// newer release is recorded by the update check.
+	Prompt bool
+	// Version is the running ado version the prompt hook compares against.
+	Version string
+	// UpdateStatePath is the update check cache the prompt hook reads.
+	UpdateStatePath string
+}
+
+var aliasPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// ValidateAlias reports whether name can be defined as a shell alias.
+func ValidateAlias(name string) error {
+	if !aliasPattern.MatchString(name) {
+		return fmt.Errorf("invalid alias %q: use letters, digits, '_' and '-', starting with a letter or '_'", name)
+	}
+	if name == "ado" {
+		return fmt.Errorf("invalid alias %q: it would replace ado itself", name)
+	}
+	return nil
+}
+
+// NewCommand returns the init command.
+func NewCommand(buildInfo internalmeta.BuildInfo) *cobra.Command {
+	var (
+		aliases      []string
+		prompt       bool
+		noCompletion bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "init SHELL",
+		Short: "Print shell integration: completion, aliases, and prompt hooks",
+		Long: `Print a script that sets up ado in the current shell: the completion
+script, shell aliases for ado (--alias), and a prompt hook (--prompt).
+Evaluate it from the shell's startup file so setup is a single line.
+
+The prompt hook prints a one-line notice before the prompt when the update
+check (updates.check in the config) has recorded a release other than the
+running one. It only reads the update check cache, so it adds no network
+or process start-up cost to the prompt.
+
+Setup:
+  # bash (~/.bashrc)
+  eval "$(ado init bash)"
+
+  # zsh (~/.zshrc, after compinit)
+  eval "$(ado init zsh)"
+
+  # fish (~/.config/fish/config.fish)
+  ado init fish | source
+
+  # PowerShell ($PROFILE)
+  ado init powershell | Out-String | Invoke-Expression
+
+Examples:
+  # Completion plus "a" as a short name for ado, and update notices
+  eval "$(ado init bash --alias a --prompt)"`,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: Shells,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			for _, name := range aliases {
+				if err := ValidateAlias(name); err != nil {
+					return err
+				}
+			}
+			opts := Options{
+				Completion: !noCompletion,
+				Aliases:    aliases,
+				Prompt:     prompt,
+				Version:    strings.TrimPrefix(buildInfo.Version, "v"),
+			}
+			if prompt {
+				path, err := update.DefaultStatePath()
+				if err != nil {
+					return err
+				}
+				opts.UpdateStatePath = path
+			}
+			return Write(cmd.OutOrStdout(), cmd.Root(), args[0], opts)
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&aliases, "alias", nil, "Define a short name for ado in the shell (repeatable)")
+	cmd.Flags().BoolVar(&prompt, "prompt", false, "Add a prompt hook that announces new ado releases")
+	cmd.Flags().BoolVar(&noCompletion, "no-completion", false, "Leave out the completion script")
+	_ = cmd.RegisterFlagCompletionFunc("alias", cobra.NoFileCompletions)
+	return cmd
+}
+
+// Write writes the integration script for shell to w. root generates the
+// completion script.
+func Write(w io.Writer, root *cobra.Command, shell string, opts Options) error {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# ado shell integration for %s, generated by `ado init %s`.\n", shell, shell)
+
+	var err error
+	switch shell {
+	case "bash":
+		err = writeBash(&buf, root, opts)
+	case "zsh":
+		err = writeZsh(&buf, root, opts)
+	case "fish":
+		err = writeFish(&buf, root, opts)
+	case "powershell":
+		err = writePowerShell(&buf, root, opts)
+	default:
+		return fmt.Errorf("unsupported shell %q (use %s)", shell, strings.Join(Shells, ", "))
+	}
+	if err != nil {
+		return fmt.Errorf("generate %s script: %w", shell, err)
+	}
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+func writeBash(b *bytes.Buffer, root *cobra.Command, opts Options) error {
+	if opts.Completion {
+		if err := root.GenBashCompletionV2(b, true); err != nil {
+			return err
+		}
+	}
+	for _, name := range opts.Aliases {
+		fmt.Fprintf(b, "%s() { ado \"$@\"; }\n", name)
+		if opts.Completion {
+			fmt.Fprintf(b, "complete -o default -F __start_ado %s\n", name)
+		}
+	}
+	if opts.Prompt {
+		writePOSIXNotice(b, opts)
+		b.WriteString(`case ";${PROMPT_COMMAND:-};" in
+  *";__ado_update_notice;"*) ;;
+  *) PROMPT_COMMAND="__ado_update_notice${PROMPT_COMMAND:+;$PROMPT_COMMAND}" ;;
+esac
+`)
+	}
+	return nil
+}
+
+func writeZsh(b *bytes.Buffer, root *cobra.Command, opts Options) error {
+	if opts.Completion {
+		if err := root.GenZshCompletion(b); err != nil {
+			return err
+		}
+	}
+	for _, name := range opts.Aliases {
+		fmt.Fprintf(b, "%s() { ado \"$@\" }\n", name)
+		if opts.Completion {
+			fmt.Fprintf(b, "compdef _ado %s\n", name)
+		}
+	}
+	if opts.Prompt {
+		writePOSIXNotice(b, opts)
+		b.WriteString("autoload -Uz add-zsh-hook\nadd-zsh-hook precmd __ado_update_notice\n")
+	}
+	return nil
+}
+
+// writePOSIXNotice defines __ado_update_notice for bash and zsh. It keeps
+// the exit status of the last command for prompts that show it.
+func writePOSIXNotice(b *bytes.Buffer, opts Options) {
+	path, version := shQuote(opts.UpdateStatePath), shQuote(opts.Version)
+	fmt.Fprintf(b, `__ado_update_notice() {
+  local ret=$? latest
+  if [ -z "${__ado_notified:-}" ] && [ -r %[1]s ]; then
+    latest=$(sed -n 's/.*"latest_version":"\([^"]*\)".*/\1/p' %[1]s 2>/dev/null)
+    if [ -n "$latest" ] && [ "$latest" != %[2]s ]; then
+      __ado_notified=1
+      printf 'ado %%s is available (current %%s); run: ado self update\n' "$latest" %[2]s >&2
+    fi
+  fi
+  return $ret
+}
+`, path, version)
+}
+
+func writeFish(b *bytes.Buffer, root *cobra.Command, opts Options) error {
+	if opts.Completion {
+		if err := root.GenFishCompletion(b, true); err != nil {
+			return err
+		}
+	}
+	for _, name := range opts.Aliases {
+		fmt.Fprintf(b, "function %s --wraps ado\n    ado $argv\nend\n", name)
+	}
+	if opts.Prompt {
+		path, version := fishQuote(opts.UpdateStatePath), fishQuote(opts.Version)
+		fmt.Fprintf(b, `function __ado_update_notice --on-event fish_prompt
+    set -q __ado_notified; and return
+    test -r %[1]s; or return
+    set -l latest (string match -r -g '"latest_version":"([^"]*)"' < %[1]s)
+    if test -n "$latest"; and test "$latest" != %[2]s
+        set -g __ado_notified 1
+        printf 'ado %%s is available (current %%s); run: ado self update\n' $latest %[2]s >&2
+    end
+end
+`, path, version)
+	}
+	return nil
+}
+
+func writePowerShell(b *bytes.Buffer, root *cobra.Command, opts Options) error {
+	if opts.Completion {
+		if err := root.GenPowerShellCompletionWithDesc(b); err != nil {
+			return err
+		}
+	}
+	for _, name := range opts.Aliases {
+		fmt.Fprintf(b, "Set-Alias -Name %s -Value ado -Scope Global\n", name)
+		if opts.Completion {
+			fmt.Fprintf(b, "Register-ArgumentCompleter -CommandName %s -ScriptBlock ${__adoCompleterBlock}\n", psQuote(name))
+		}
+	}
+	if opts.Prompt {
+		path, version := psQuote(opts.UpdateStatePath), psQuote(opts.Version)
+		fmt.Fprintf(b, `if (-not $global:__adoPrompt) {
+    $global:__adoPrompt = $function:prompt
+    $global:__adoNotified = $false
+    function global:prompt {
+        if (-not $global:__adoNotified -and (Test-Path -LiteralPath %[1]s)) {
+            try { $latest = (Get-Content -Raw -LiteralPath %[1]s | ConvertFrom-Json).latest_version } catch { $latest = $null }
+            if ($latest -and $latest -ne %[2]s) {
+                $global:__adoNotified = $true
+                [Console]::Error.WriteLine("ado $latest is available (current " + %[2]s + "); run: ado self update")
+            }
+        }
+        & $global:__adoPrompt
+    }
+}
+`, path, version)
+	}
+	return nil
+}
+
+// shQuote quotes s for bash and zsh.
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// fishQuote quotes s for fish, where backslash and quote are escaped
+// inside single quotes.
+func fishQuote(s string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, "'", `\'`).Replace(s) + "'"
+}
+
+// psQuote quotes s as a PowerShell literal string.
+func psQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "''") + "'"
+}
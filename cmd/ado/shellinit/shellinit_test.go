@@ -0,0 +1,163 @@
+package shellinit
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+)
+
+func testRoot() *cobra.Command {
+	root := &cobra.Command{Use: "ado"}
+	root.AddCommand(NewCommand(internalmeta.BuildInfo{Version: "v1.2.0"}), &cobra.Command{Use: "echo", Run: func(*cobra.Command, []string) {}})
+	return root
+}
+
+func execute(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	root := testRoot()
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"init"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestInit_Shells(t *testing.T) {
+	tests := []struct {
+		shell string
+		want  []string
+	}{
+		{"bash", []string{"__start_ado", "a() { ado \"$@\"; }", "complete -o default -F __start_ado a"}},
+		{"zsh", []string{"#compdef ado", "a() { ado \"$@\" }", "compdef _ado a"}},
+		{"fish", []string{"complete -c ado", "function a --wraps ado"}},
+		{"powershell", []string{"Register-ArgumentCompleter", "Set-Alias -Name a -Value ado", "-CommandName 'a'"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			out, err := execute(t, tt.shell, "--alias", "a")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("script missing %q", want)
+				}
+			}
+			if strings.Contains(out, "__ado_update_notice") || strings.Contains(out, "__adoPrompt") {
+				t.Error("prompt hook included without --prompt")
+			}
+		})
+	}
+}
+
+func TestInit_NoCompletion(t *testing.T) {
+	out, err := execute(t, "bash", "--no-completion", "--alias", "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(out, "__start_ado") {
+		t.Errorf("completion included with --no-completion:\n%s", out)
+	}
+	if !strings.Contains(out, "a() { ado \"$@\"; }") {
+		t.Errorf("alias missing:\n%s", out)
+	}
+}
+
+func TestInit_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown shell", []string{"tcsh"}, "unsupported shell"},
+		{"missing shell", nil, "accepts 1 arg"},
+		{"bad alias", []string{"bash", "--alias", "a;rm"}, "invalid alias"},
+		{"ado alias", []string{"bash", "--alias", "ado"}, "replace ado"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := execute(t, tt.args...)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrite_PromptQuoting(t *testing.T) {
+	opts := Options{Prompt: true, Version: "1.2.0", UpdateStatePath: "/tmp/it's/update-check.json"}
+	tests := []struct {
+		shell string
+		want  string
+	}{
+		{"bash", `'/tmp/it'\''s/update-check.json'`},
+		{"zsh", "add-zsh-hook precmd __ado_update_notice"},
+		{"fish", `'/tmp/it\'s/update-check.json'`},
+		{"powershell", `'/tmp/it''s/update-check.json'`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.shell, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, testRoot(), tt.shell, opts); err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("script missing %q:\n%s", tt.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestWrite_BashPromptHook(t *testing.T) {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash not available")
+	}
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "update-check.json")
+
+	var script bytes.Buffer
+	opts := Options{Completion: true, Aliases: []string{"a"}, Prompt: true, Version: "1.2.0", UpdateStatePath: statePath}
+	if err := Write(&script, testRoot(), "bash", opts); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		state string
+		want  string
+	}{
+		{"newer release", `{"checked_at":"2026-01-01T00:00:00Z","channel":"stable","latest_version":"1.3.0"}`, "ado 1.3.0 is available (current 1.2.0); run: ado self update\n"},
+		{"same release", `{"channel":"stable","latest_version":"1.2.0"}`, ""},
+		{"no cache", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			os.Remove(statePath)
+			if tt.state != "" {
+				if err := os.WriteFile(statePath, []byte(tt.state), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			// Run the hook twice: the notice is shown once per session, and
+			// the exit status of the previous command is kept.
+			cmd := exec.Command(bash, "--norc", "--noprofile", "-c", script.String()+"\neval \"$PROMPT_COMMAND\"; (exit 3); eval \"$PROMPT_COMMAND\"; [ $? -eq 3 ]")
+			var stderr bytes.Buffer
+			cmd.Stderr = &stderr
+			if err := cmd.Run(); err != nil {
+				t.Fatalf("bash: %v\n%s", err, stderr.String())
+			}
+			if stderr.String() != tt.want {
+				t.Errorf("stderr = %q, want %q", stderr.String(), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,418 @@
+package state
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	internalstate "github.com/anowarislam/ado/internal/state"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// NewCommand returns the state parent command with subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "state",
+		Short: "Keep values between runs in a local key-value store",
+		Long: `Store, read, count, and list small values that should survive between
+runs: last-run markers, counters, and cached results for workflows and
+scripts.
+
+Keys live in namespaces (--namespace, default "default") and may expire
+(--ttl); expired keys behave as if they were never set. The store is a
+single file, state.db, in ado's state directory (see 'ado meta paths').
+Values are not encrypted; use 'ado secret' for credentials.`,
+	}
+
+	cmd.AddCommand(
+		newGetCommand(),
+		newSetCommand(),
+		newIncrCommand(),
+		newDeleteCommand(),
+		newListCommand(),
+		newPruneCommand(),
+	)
+	return cmd
+}
+
+func newGetCommand() *cobra.Command {
+	var (
+		namespace string
+		fallback  string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "get KEY",
+		Short: "Print a value",
+		Long: `Print the value stored under KEY, followed by a newline. A missing or
+expired key is an error unless --default is given.
+
+Examples:
+  ado state get last-deploy -n release
+
+  # Fall back when the key is not set
+  since=$(ado state get last-sync --default 1970-01-01T00:00:00Z)`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := open()
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			entry, err := store.Get(namespace, args[0])
+			switch {
+			case errors.Is(err, internalstate.ErrNotFound) && cmd.Flags().Changed("default"):
+				entry.Value = fallback
+			case err != nil:
+				return keyError(namespace, args[0], err)
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), entry.Value)
+			return err
+		},
+	}
+
+	addNamespaceFlag(cmd, &namespace)
+	cmd.Flags().StringVar(&fallback, "default", "", "Value to print when the key is missing or expired")
+	return cmd
+}
+
+func newSetCommand() *cobra.Command {
+	var (
+		namespace string
+		ttl       time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:         "set KEY [VALUE]",
+		Short:       "Store a value",
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		Long: `Store VALUE under KEY, replacing any existing value. Without VALUE, the
+value is read from stdin with one trailing newline removed.
+
+Examples:
+  ado state set last-deploy "$(date -u +%FT%TZ)" -n release
+
+  # Cache a response for an hour
+  curl -s https://api.example.com/version | ado state set api-version --ttl 1h`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if ttl < 0 {
+				return errors.New("--ttl must not be negative")
+			}
+			var value string
+			if len(args) == 2 {
+				value = args[1]
+			} else {
+				data, err := io.ReadAll(cmd.InOrStdin())
+				if err != nil {
+					return fmt.Errorf("read value: %w", err)
+				}
+				value = strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+			}
+
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				if err := internalstate.ValidateNamespace(namespace); err != nil {
+					return err
+				}
+				if err := internalstate.ValidateKey(args[0]); err != nil {
+					return err
+				}
+				detail := fmt.Sprintf("%q", value)
+				if ttl > 0 {
+					detail += fmt.Sprintf(", expiring in %s", ttl)
+				}
+				plan := ui.NewPlan()
+				plan.Add("set", namespace+"/"+args[0], detail)
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+
+			store, err := open()
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+			_, err = store.Set(namespace, args[0], value, ttl)
+			return err
+		},
+	}
+
+	addNamespaceFlag(cmd, &namespace)
+	cmd.Flags().DurationVar(&ttl, "ttl", 0, "Expire the key after this long (e.g. 30m, 24h); 0 never expires")
+	return cmd
+}
+
+func newIncrCommand() *cobra.Command {
+	var (
+		namespace string
+		by        int64
+		ttl       time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:         "incr KEY",
+		Short:       "Add to a counter and print the result",
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		Long: `Add --by (default 1) to the integer stored under KEY and print the new
+value. A missing or expired key counts from 0. --ttl sets a new expiry;
+without it an existing expiry is kept. Concurrent increments from
+several processes are never lost.
+
+Examples:
+  # Count failures, resetting a day after the first one
+  ado state incr failures -n nightly --ttl 24h
+
+  ado state incr retries --by -1`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if ttl < 0 {
+				return errors.New("--ttl must not be negative")
+			}
+			store, err := open()
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				entry, err := store.IncrPreview(namespace, args[0], by, ttl)
+				if err != nil {
+					return err
+				}
+				detail := fmt.Sprintf("%q", entry.Value)
+				if ttl > 0 {
+					detail += fmt.Sprintf(", expiring in %s", ttl)
+				}
+				plan := ui.NewPlan()
+				plan.Add("set", namespace+"/"+args[0], detail)
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+
+			entry, err := store.Incr(namespace, args[0], by, ttl)
+			if err != nil {
+				return err
+			}
+			_, err = fmt.Fprintln(cmd.OutOrStdout(), entry.Value)
+			return err
+		},
+	}
+
+	addNamespaceFlag(cmd, &namespace)
+	cmd.Flags().Int64Var(&by, "by", 1, "Amount to add; may be negative")
+	cmd.Flags().DurationVar(&ttl, "ttl", 0, "Expire the key after this long; 0 keeps the current expiry")
+	return cmd
+}
+
+func newDeleteCommand() *cobra.Command {
+	var (
+		namespace string
+		missingOK bool
+	)
+
+	cmd := &cobra.Command{
+		Use:         "delete KEY",
+		Short:       "Delete a key",
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		Long: `Delete KEY. A missing key is an error unless --missing-ok is given.
+
+Examples:
+  ado state delete lock -n release --missing-ok`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := open()
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag)
+			if dryRun {
+				_, err = store.Get(namespace, args[0])
+			} else {
+				err = store.Delete(namespace, args[0])
+			}
+			if err != nil && !(missingOK && errors.Is(err, internalstate.ErrNotFound)) {
+				return keyError(namespace, args[0], err)
+			}
+			if dryRun {
+				plan := ui.NewPlan()
+				if err == nil {
+					plan.Add("delete", namespace+"/"+args[0], "")
+				}
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+			return nil
+		},
+	}
+
+	addNamespaceFlag(cmd, &namespace)
+	cmd.Flags().BoolVar(&missingOK, "missing-ok", false, "Succeed when the key does not exist")
+	return cmd
+}
+
+// listOutput is the payload for structured output.
+type listOutput struct {
+	Entries []internalstate.Entry `json:"entries" yaml:"entries"`
+}
+
+func newListCommand() *cobra.Command {
+	var (
+		namespace string
+		all       bool
+		output    string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list [PREFIX]",
+		Short: "List keys and values",
+		Long: `List the keys in a namespace, or in every namespace with --all, with
+their values and expiry. PREFIX limits the listing to keys starting
+with it.
+
+Examples:
+  ado state list -n release
+  ado state list cache/ --all -o json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			if all && cmd.Flags().Changed("namespace") {
+				return errors.New("--all and --namespace cannot be used together")
+			}
+			prefix := ""
+			if len(args) == 1 {
+				prefix = args[0]
+			}
+
+			store, err := open()
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			ns := namespace
+			if all {
+				ns = ""
+			}
+			entries, err := store.List(ns, prefix)
+			if err != nil {
+				return err
+			}
+			payload := listOutput{Entries: entries}
+			if payload.Entries == nil {
+				payload.Entries = []internalstate.Entry{}
+			}
+			return ui.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+				if len(entries) == 0 {
+					return "No keys stored.", nil
+				}
+				return formatEntries(entries, all, store.Now()), nil
+			})
+		},
+	}
+
+	addNamespaceFlag(cmd, &namespace)
+	cmd.Flags().BoolVarP(&all, "all", "A", false, "List every namespace")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func newPruneCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:         "prune",
+		Short:       "Delete expired keys",
+		Annotations: map[string]string{ui.DryRunAnnotation: "true"},
+		Long: `Delete expired keys from every namespace and drop namespaces left
+empty. Expired keys are already invisible, and writes prune the
+namespace they touch, so this only reclaims space.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			store, err := open()
+			if err != nil {
+				return err
+			}
+			defer store.Close()
+
+			if dryRun, _ := cmd.Flags().GetBool(ui.DryRunFlag); dryRun {
+				expired, err := store.Expired()
+				if err != nil {
+					return err
+				}
+				plan := ui.NewPlan()
+				for _, e := range expired {
+					plan.Add("delete", e.Namespace+"/"+e.Key, "expired")
+				}
+				return ui.PrintPlan(cmd.OutOrStdout(), ui.OutputText, plan)
+			}
+
+			removed, err := store.Prune()
+			if err != nil {
+				return err
+			}
+			noun := "keys"
+			if removed == 1 {
+				noun = "key"
+			}
+			_, err = fmt.Fprintf(cmd.ErrOrStderr(), "Removed %d expired %s\n", removed, noun)
+			return err
+		},
+	}
+	return cmd
+}
+
+func addNamespaceFlag(cmd *cobra.Command, namespace *string) {
+	cmd.Flags().StringVarP(namespace, "namespace", "n", internalstate.DefaultNamespace, "Namespace holding the key")
+}
+
+func open() (*internalstate.Store, error) {
+	path, err := internalstate.DefaultPath()
+	if err != nil {
+		return nil, err
+	}
+	return internalstate.Open(path)
+}
+
+func keyError(namespace, key string, err error) error {
+	if errors.Is(err, internalstate.ErrNotFound) {
+		return fmt.Errorf("key %q not found in namespace %q", key, namespace)
+	}
+	return err
+}
+
+// formatEntries renders one aligned line per entry: KEY VALUE, preceded
+// by the namespace when listing all of them. Values with line breaks or
+// control characters are quoted.
+func formatEntries(entries []internalstate.Entry, withNamespace bool, now time.Time) string {
+	nsWidth, keyWidth := 0, 0
+	for _, e := range entries {
+		nsWidth = max(nsWidth, len(e.Namespace))
+		keyWidth = max(keyWidth, len(e.Key))
+	}
+
+	var b strings.Builder
+	for i, e := range entries {
+		value := e.Value
+		if strings.ContainsFunc(value, func(r rune) bool { return r < 0x20 || r == 0x7f }) {
+			value = strconv.Quote(value)
+		}
+		line := fmt.Sprintf("%-*s  %s", keyWidth, e.Key, value)
+		if withNamespace {
+			line = fmt.Sprintf("%-*s  %s", nsWidth, e.Namespace, line)
+		}
+		if e.ExpiresAt != nil {
+			line += fmt.Sprintf("  (expires in %s)", e.ExpiresAt.Sub(now).Round(time.Second))
+		}
+		b.WriteString(strings.TrimRight(line, " "))
+		if i < len(entries)-1 {
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
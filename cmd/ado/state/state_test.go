@@ -0,0 +1,196 @@
+package state
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func execute(t *testing.T, stdin string, args ...string) (string, string, error) {
+	t.Helper()
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().Bool("dry-run", false, "")
+	root.AddCommand(NewCommand())
+	var stdout, stderr bytes.Buffer
+	root.SetIn(strings.NewReader(stdin))
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.SetArgs(append([]string{"state"}, args...))
+	err := root.Execute()
+	return stdout.String(), stderr.String(), err
+}
+
+func TestState_Lifecycle(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", dir)
+
+	if out, _, err := execute(t, "", "list"); err != nil || out != "No keys stored.\n" {
+		t.Fatalf("list on empty store = %q, %v", out, err)
+	}
+	if _, _, err := execute(t, "", "set", "last-run", "2026-01-02"); err != nil {
+		t.Fatalf("set: %v", err)
+	}
+	if _, _, err := execute(t, "from stdin\n", "set", "note", "-n", "release"); err != nil {
+		t.Fatalf("set from stdin: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "ado", "state.db")); err != nil {
+		t.Errorf("store not created in state dir: %v", err)
+	}
+
+	if out, _, err := execute(t, "", "get", "last-run"); err != nil || out != "2026-01-02\n" {
+		t.Errorf("get = %q, %v", out, err)
+	}
+	if out, _, err := execute(t, "", "get", "note", "--namespace", "release"); err != nil || out != "from stdin\n" {
+		t.Errorf("get from namespace = %q, %v", out, err)
+	}
+	if _, _, err := execute(t, "", "get", "note"); err == nil || err.Error() != `key "note" not found in namespace "default"` {
+		t.Errorf("get missing error = %v", err)
+	}
+	if out, _, err := execute(t, "", "get", "note", "--default", ""); err != nil || out != "\n" {
+		t.Errorf("get --default = %q, %v", out, err)
+	}
+
+	for _, want := range []string{"1\n", "2\n", "12\n"} {
+		args := []string{"incr", "count"}
+		if want == "12\n" {
+			args = append(args, "--by", "10")
+		}
+		if out, _, err := execute(t, "", args...); err != nil || out != want {
+			t.Errorf("incr = %q, %v, want %q", out, err, want)
+		}
+	}
+
+	out, _, err := execute(t, "", "list")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "count     12\nlast-run  2026-01-02\n"; out != want {
+		t.Errorf("list = %q, want %q", out, want)
+	}
+	out, _, err = execute(t, "", "list", "--all")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "release  note      from stdin\n") {
+		t.Errorf("list --all = %q", out)
+	}
+
+	if _, _, err := execute(t, "", "delete", "count"); err != nil {
+		t.Errorf("delete: %v", err)
+	}
+	if _, _, err := execute(t, "", "delete", "count"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("delete missing error = %v", err)
+	}
+	if _, _, err := execute(t, "", "delete", "count", "--missing-ok"); err != nil {
+		t.Errorf("delete --missing-ok: %v", err)
+	}
+}
+
+func TestState_DryRun(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	out, _, err := execute(t, "", "set", "k", "v", "--ttl", "1h", "--dry-run")
+	if err != nil || out != "Would set default/k: \"v\", expiring in 1h0m0s\nDry run: nothing was changed.\n" {
+		t.Errorf("set --dry-run = %q, %v", out, err)
+	}
+	if _, _, err := execute(t, "", "get", "k"); err == nil {
+		t.Error("get after set --dry-run succeeded")
+	}
+
+	if _, _, err := execute(t, "", "delete", "k", "--dry-run"); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("delete missing --dry-run error = %v", err)
+	}
+	if _, _, err := execute(t, "", "set", "k", "v"); err != nil {
+		t.Fatal(err)
+	}
+	if out, _, err := execute(t, "", "delete", "k", "--dry-run"); err != nil || !strings.HasPrefix(out, "Would delete default/k\n") {
+		t.Errorf("delete --dry-run = %q, %v", out, err)
+	}
+	if out, _, err := execute(t, "", "get", "k"); err != nil || out != "v\n" {
+		t.Errorf("get after delete --dry-run = %q, %v", out, err)
+	}
+
+	if out, _, err := execute(t, "", "incr", "n", "--by", "3", "--dry-run"); err != nil || out != "Would set default/n: \"3\"\nDry run: nothing was changed.\n" {
+		t.Errorf("incr --dry-run = %q, %v", out, err)
+	}
+	if _, _, err := execute(t, "", "get", "n"); err == nil {
+		t.Error("get after incr --dry-run succeeded")
+	}
+
+	if _, _, err := execute(t, "", "set", "old", "v", "--ttl", "1ns"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if out, _, err := execute(t, "", "prune", "--dry-run"); err != nil || out != "Would delete default/old: expired\nDry run: nothing was changed.\n" {
+		t.Errorf("prune --dry-run = %q, %v", out, err)
+	}
+	if _, stderr, err := execute(t, "", "prune"); err != nil || stderr != "Removed 1 expired key\n" {
+		t.Errorf("prune after --dry-run = %q, %v", stderr, err)
+	}
+}
+
+func TestState_ListJSON(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	if _, _, err := execute(t, "", "set", "token", "a\tb", "--ttl", "1h", "-n", "cache"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, _, err := execute(t, "", "list", "-n", "cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(out, `token  "a\tb"  (expires in 1h0m0s)`) && !strings.HasPrefix(out, `token  "a\tb"  (expires in 59m59s)`) {
+		t.Errorf("list = %q", out)
+	}
+
+	out, _, err = execute(t, "", "list", "-A", "-o", "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var payload listOutput
+	if err := json.Unmarshal([]byte(out), &payload); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if len(payload.Entries) != 1 || payload.Entries[0].Namespace != "cache" || payload.Entries[0].ExpiresAt == nil {
+		t.Errorf("payload = %+v", payload)
+	}
+}
+
+func TestState_Prune(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	if _, stderr, err := execute(t, "", "prune"); err != nil || stderr != "Removed 0 expired keys\n" {
+		t.Errorf("prune = %q, %v", stderr, err)
+	}
+}
+
+func TestState_Errors(t *testing.T) {
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"set negative ttl", []string{"set", "k", "v", "--ttl", "-1s"}, "--ttl must not be negative"},
+		{"incr negative ttl", []string{"incr", "k", "--ttl", "-1s"}, "--ttl must not be negative"},
+		{"bad namespace", []string{"set", "k", "v", "-n", "bad ns"}, "invalid namespace"},
+		{"incr text", []string{"incr", "text"}, "not an integer"},
+		{"all with namespace", []string{"list", "--all", "-n", "x"}, "cannot be used together"},
+		{"get no key", []string{"get"}, "accepts 1 arg(s)"},
+	}
+	if _, _, err := execute(t, "", "set", "text", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := execute(t, "", tt.args...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,193 @@
+package tls
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/tlsinspect"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// NewCommand returns the tls parent command with subcommands.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tls",
+		Short: "Inspect TLS servers",
+		Long:  `Diagnose TLS endpoints: certificate chains, names, and expiry.`,
+	}
+
+	cmd.AddCommand(newInspectCommand())
+
+	return cmd
+}
+
+func newInspectCommand() *cobra.Command {
+	var (
+		opts     tlsinspect.Options
+		warnDays int
+		caFile   string
+		output   string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "inspect HOST[:PORT]...",
+		Short: "Show a server's certificate chain and expiry",
+		Long: `Connect to each target, complete a TLS handshake, and print the
+negotiated version and cipher suite, whether the chain verifies, and
+every certificate the server presented: subject, issuer, SANs, validity,
+and days remaining. The port defaults to 443; https:// URLs also work.
+
+Chains that do not verify (self-signed, expired, wrong name) are still
+shown, with the reason. Verification uses the system roots, or --ca-file.
+
+ado exits 1 when a certificate in a chain has expired or expires within
+--warn-days days, so the command works as a CI or cron check.
+
+Examples:
+  # Inspect a public endpoint
+  ado tls inspect example.com
+
+  # Fail when anything expires within 30 days
+  ado tls inspect api.internal:8443 db.internal:5433 --warn-days 30
+
+  # Connect by IP but verify the expected name
+  ado tls inspect 10.0.0.5:443 --servername app.example.com
+
+  # Days remaining as JSON
+  ado tls inspect example.com -o json | jq '.[].days_remaining'`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+			if warnDays < 0 {
+				return errors.New("--warn-days must not be negative")
+			}
+			addresses := make([]string, len(args))
+			for i, arg := range args {
+				if addresses[i], err = tlsinspect.ParseAddress(arg); err != nil {
+					return err
+				}
+			}
+			if caFile != "" {
+				if opts.Roots, err = loadRoots(caFile); err != nil {
+					return err
+				}
+			}
+
+			ctx := cmd.Context()
+
+			results := make([]tlsinspect.Result, 0, len(addresses))
+			for _, address := range addresses {
+				result, err := tlsinspect.Inspect(ctx, address, opts)
+				if err != nil {
+					return err
+				}
+				results = append(results, result)
+			}
+
+			if err := ui.PrintOutput(cmd.OutOrStdout(), format, results, func() (string, error) {
+				return formatResults(results), nil
+			}); err != nil {
+				return err
+			}
+			return ui.Reported(checkExpiry(results, warnDays))
+		},
+	}
+
+	cmd.Flags().IntVar(&warnDays, "warn-days", 0, "Exit 1 when a certificate expires within this many days (0: only when expired)")
+	cmd.Flags().StringVar(&opts.ServerName, "servername", "", "Name sent as SNI and verified against the certificate (default: the target's host)")
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", tlsinspect.DefaultTimeout, "Timeout for connecting and the handshake")
+	cmd.Flags().StringVar(&caFile, "ca-file", "", "PEM file of root certificates to verify against instead of the system roots")
+	_ = cmd.MarkFlagFilename("ca-file", "pem", "crt")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+	return cmd
+}
+
+func loadRoots(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read CA file: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("%s contains no PEM certificates", path)
+	}
+	return pool, nil
+}
+
+// checkExpiry returns an error naming every target whose chain expires
+// within warnDays days or has already expired.
+func checkExpiry(results []tlsinspect.Result, warnDays int) error {
+	var problems []string
+	for _, r := range results {
+		switch {
+		case r.DaysRemaining < 0:
+			problems = append(problems, fmt.Sprintf("%s: certificate expired on %s", r.Address, r.NotAfter.Format(time.DateOnly)))
+		case r.DaysRemaining < warnDays:
+			problems = append(problems, fmt.Sprintf("%s: certificate expires in %d days, within --warn-days %d", r.Address, r.DaysRemaining, warnDays))
+		}
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(problems, "\n"))
+}
+
+func formatResults(results []tlsinspect.Result) string {
+	var b strings.Builder
+	for i, r := range results {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s (%s, %s)\n", r.Address, r.TLSVersion, r.CipherSuite)
+		if r.ServerName != hostOf(r.Address) {
+			fmt.Fprintf(&b, "  server name: %s\n", r.ServerName)
+		}
+		if r.Verified {
+			b.WriteString("  verified:    yes\n")
+		} else {
+			fmt.Fprintf(&b, "  verified:    no (%s)\n", r.VerifyError)
+		}
+		fmt.Fprintf(&b, "  expires:     %s (%s)\n", r.NotAfter.Format(time.DateOnly), formatDays(r.DaysRemaining))
+		b.WriteString("  chain:\n")
+		for j, c := range r.Certificates {
+			fmt.Fprintf(&b, "    %d %s\n", j, c.Subject)
+			fmt.Fprintf(&b, "      issuer:  %s\n", c.Issuer)
+			if names := append(append([]string{}, c.DNSNames...), c.IPAddresses...); len(names) > 0 {
+				fmt.Fprintf(&b, "      SANs:    %s\n", strings.Join(names, ", "))
+			}
+			fmt.Fprintf(&b, "      valid:   %s to %s (%s)\n", c.NotBefore.Format(time.DateOnly), c.NotAfter.Format(time.DateOnly), formatDays(c.DaysRemaining))
+			fmt.Fprintf(&b, "      sha256:  %s\n", c.SHA256Fingerprint)
+		}
+	}
+	return b.String()
+}
+
+func formatDays(days int) string {
+	unit := "days"
+	if days == 1 || days == -1 {
+		unit = "day"
+	}
+	if days < 0 {
+		return fmt.Sprintf("expired %d %s ago", -days, unit)
+	}
+	return fmt.Sprintf("%d %s left", days, unit)
+}
+
+// hostOf returns the host part of a host:port address.
+func hostOf(address string) string {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+	return host
+}
@@ -0,0 +1,129 @@
+package tls
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/tlsinspect"
+)
+
+func execute(args ...string) (string, error) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewCommand())
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"tls"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+// startServer returns a TLS server's address and a CA file trusting it.
+func startServer(t *testing.T) (string, string) {
+	t.Helper()
+	srv := httptest.NewUnstartedServer(http.NotFoundHandler())
+	// The client hangs up right after the handshake.
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	t.Cleanup(srv.Close)
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(caFile, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimPrefix(srv.URL, "https://"), caFile
+}
+
+func TestInspectCommand_JSON(t *testing.T) {
+	address, caFile := startServer(t)
+
+	out, err := execute("inspect", address, "--ca-file", caFile, "--servername", "example.com", "-o", "json")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var results []tlsinspect.Result
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if len(results) != 1 || !results[0].Verified || results[0].Address != address || len(results[0].Certificates) != 1 {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestInspectCommand_WarnDays(t *testing.T) {
+	address, _ := startServer(t)
+
+	out, err := execute("inspect", address, "--warn-days", "1000000")
+	if err == nil || !strings.Contains(err.Error(), address+": certificate expires in ") {
+		t.Errorf("Execute() error = %v", err)
+	}
+	for _, want := range []string{address + " (TLS 1.3, ", "  verified:    no (", "  chain:\n    0 O=Acme Co\n", "      SANs:    example.com, *.example.com, 127.0.0.1, ::1\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestInspectCommand_Errors(t *testing.T) {
+	empty := filepath.Join(t.TempDir(), "empty.pem")
+	if err := os.WriteFile(empty, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no target", []string{"inspect"}, "requires at least 1 arg"},
+		{"bad target", []string{"inspect", "host:port"}, `bad port "port"`},
+		{"negative warn days", []string{"inspect", "example.com", "--warn-days", "-1"}, "--warn-days must not be negative"},
+		{"empty CA file", []string{"inspect", "example.com", "--ca-file", empty}, "contains no PEM certificates"},
+		{"bad output", []string{"inspect", "example.com", "-o", "xml"}, "xml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := execute(tt.args...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckExpiry(t *testing.T) {
+	notAfter := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	results := []tlsinspect.Result{
+		{Address: "ok:443", DaysRemaining: 90, NotAfter: notAfter},
+		{Address: "soon:443", DaysRemaining: 5, NotAfter: notAfter},
+		{Address: "gone:443", DaysRemaining: -2, NotAfter: notAfter},
+	}
+
+	err := checkExpiry(results, 30)
+	want := "soon:443: certificate expires in 5 days, within --warn-days 30\ngone:443: certificate expired on 2026-03-01"
+	if err == nil || err.Error() != want {
+		t.Errorf("checkExpiry() = %v, want %q", err, want)
+	}
+	if err := checkExpiry(results[:2], 0); err != nil {
+		t.Errorf("checkExpiry(warnDays 0) = %v, want only expired certificates to fail", err)
+	}
+}
+
+func TestFormatDays(t *testing.T) {
+	tests := map[int]string{30: "30 days left", 1: "1 day left", 0: "0 days left", -1: "expired 1 day ago", -3: "expired 3 days ago"}
+	for days, want := range tests {
+		if got := formatDays(days); got != want {
+			t.Errorf("formatDays(%d) = %q, want %q", days, got, want)
+		}
+	}
+}
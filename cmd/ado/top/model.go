@@ -0,0 +1,278 @@
+package top
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// Sections are the views the dashboard can switch between.
+var Sections = []string{"overview", "cpu", "memory", "disk", "gpu"}
+
+const (
+	sectionOverview = iota
+	sectionCPU
+	sectionMemory
+	sectionDisk
+	sectionGPU
+)
+
+// barWidth is the width of utilization bars, excluding the label.
+const barWidth = 30
+
+var (
+	titleStyle     = lipgloss.NewStyle().Bold(true)
+	activeTabStyle = lipgloss.NewStyle().Bold(true).Reverse(true).Padding(0, 1)
+	tabStyle       = lipgloss.NewStyle().Padding(0, 1)
+	dimStyle       = lipgloss.NewStyle().Faint(true)
+	okStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	warnStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	critStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+)
+
+type (
+	sampleMsg internalmeta.Usage
+	tickMsg   time.Time
+)
+
+// model is the bubbletea model behind `ado top`.
+type model struct {
+	ctx         context.Context
+	sample      func(context.Context) internalmeta.Usage
+	interval    time.Duration
+	snapshotDir string
+	host        string
+
+	section int
+	usage   *internalmeta.Usage
+	paused  bool
+	status  string
+}
+
+func (m model) Init() tea.Cmd {
+	return m.collect
+}
+
+func (m model) collect() tea.Msg {
+	return sampleMsg(m.sample(m.ctx))
+}
+
+func (m model) tick() tea.Cmd {
+	return tea.Tick(m.interval, func(t time.Time) tea.Msg { return tickMsg(t) })
+}
+
+func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case sampleMsg:
+		usage := internalmeta.Usage(msg)
+		m.usage = &usage
+		return m, m.tick()
+
+	case tickMsg:
+		if m.paused {
+			return m, m.tick()
+		}
+		return m, m.collect
+
+	case tea.KeyMsg:
+		switch key := msg.String(); key {
+		case "q", "esc", "ctrl+c":
+			return m, tea.Quit
+		case "tab", "right", "l":
+			m.section = (m.section + 1) % len(Sections)
+		case "shift+tab", "left", "h":
+			m.section = (m.section + len(Sections) - 1) % len(Sections)
+		case "1", "2", "3", "4", "5":
+			m.section = int(key[0] - '1')
+		case "p", " ":
+			m.paused = !m.paused
+		case "s":
+			m.status = m.export()
+		}
+	}
+	return m, nil
+}
+
+// export writes the current sample to snapshotDir as JSON and returns a
+// status line describing the result.
+func (m model) export() string {
+	if m.usage == nil {
+		return "No sample to export yet"
+	}
+	data, err := ui.Marshal(ui.OutputJSON, m.usage)
+	if err != nil {
+		return "Export failed: " + err.Error()
+	}
+	name := "ado-top-" + m.usage.Time.Format("20060102-150405") + ".json"
+	path := filepath.Join(m.snapshotDir, name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "Export failed: " + err.Error()
+	}
+	return "Saved snapshot to " + path
+}
+
+func (m model) View() string {
+	var b strings.Builder
+
+	state := fmt.Sprintf("every %s", m.interval)
+	if m.paused {
+		state = "paused"
+	}
+	b.WriteString(titleStyle.Render("ado top") + "  " + m.host + "  " + dimStyle.Render(state) + "\n")
+
+	tabs := make([]string, len(Sections))
+	for i, name := range Sections {
+		label := fmt.Sprintf("%d %s", i+1, name)
+		if i == m.section {
+			tabs[i] = activeTabStyle.Render(label)
+		} else {
+			tabs[i] = tabStyle.Render(label)
+		}
+	}
+	b.WriteString(strings.Join(tabs, "") + "\n\n")
+
+	if m.usage == nil {
+		b.WriteString("Collecting...\n")
+	} else {
+		switch m.section {
+		case sectionOverview:
+			b.WriteString(m.viewOverview())
+		case sectionCPU:
+			b.WriteString(m.viewCPU())
+		case sectionMemory:
+			b.WriteString(m.viewMemory())
+		case sectionDisk:
+			b.WriteString(m.viewDisk())
+		case sectionGPU:
+			b.WriteString(m.viewGPU())
+		}
+	}
+
+	b.WriteString("\n")
+	if m.status != "" {
+		b.WriteString(m.status + "\n")
+	}
+	b.WriteString(dimStyle.Render("tab/1-5 switch  p pause  s save snapshot  q quit") + "\n")
+	return b.String()
+}
+
+func (m model) viewOverview() string {
+	u := m.usage
+	var b strings.Builder
+	b.WriteString(bar("CPU", u.CPU.TotalPercent, ""))
+	b.WriteString(bar("Memory", u.Memory.UsedPercent, fmt.Sprintf("%d / %d MB", u.Memory.UsedMB, u.Memory.TotalMB)))
+	for _, volume := range u.Storage {
+		if volume.Unresponsive {
+			continue
+		}
+		b.WriteString(bar(volume.Mountpoint, volume.UsedPercent, fmt.Sprintf("%d / %d MB", volume.UsedMB, volume.TotalMB)))
+	}
+	for _, gpu := range u.GPU {
+		b.WriteString(bar(fmt.Sprintf("GPU %d", gpu.Index), gpu.UtilizationPercent, gpu.Model))
+	}
+	return b.String()
+}
+
+func (m model) viewCPU() string {
+	u := m.usage
+	var b strings.Builder
+	cores := fmt.Sprintf("%d cores", len(u.CPU.PerCore))
+	if len(u.CPU.PerCore) == 1 {
+		cores = "1 core"
+	}
+	b.WriteString(bar("Total", u.CPU.TotalPercent, cores))
+	for i, percent := range u.CPU.PerCore {
+		b.WriteString(bar(fmt.Sprintf("cpu%d", i), percent, ""))
+	}
+	return b.String()
+}
+
+func (m model) viewMemory() string {
+	mem := m.usage.Memory
+	var b strings.Builder
+	b.WriteString(bar("Memory", mem.UsedPercent, fmt.Sprintf("%d / %d MB", mem.UsedMB, mem.TotalMB)))
+	fmt.Fprintf(&b, "%-12s %d MB\n", "Available", mem.AvailableMB)
+	if mem.SwapTotalMB > 0 {
+		swap := float64(mem.SwapUsedMB) / float64(mem.SwapTotalMB) * 100
+		b.WriteString(bar("Swap", swap, fmt.Sprintf("%d / %d MB", mem.SwapUsedMB, mem.SwapTotalMB)))
+	} else {
+		fmt.Fprintf(&b, "%-12s none\n", "Swap")
+	}
+	return b.String()
+}
+
+func (m model) viewDisk() string {
+	if len(m.usage.Storage) == 0 {
+		return "No volumes detected\n"
+	}
+	var b strings.Builder
+	for _, volume := range m.usage.Storage {
+		if volume.Unresponsive {
+			fmt.Fprintf(&b, "%-12s unresponsive  %s %s\n", truncate(volume.Mountpoint, 12), volume.Filesystem, volume.Device)
+			continue
+		}
+		detail := fmt.Sprintf("%d / %d MB  %s %s", volume.UsedMB, volume.TotalMB, volume.Filesystem, volume.Device)
+		b.WriteString(bar(volume.Mountpoint, volume.UsedPercent, detail))
+	}
+	return b.String()
+}
+
+func (m model) viewGPU() string {
+	if len(m.usage.GPU) == 0 {
+		if m.usage.GPUSource == "none" {
+			return "No GPU utilization available (nvidia-smi not found)\n"
+		}
+		return "No GPUs reported by " + m.usage.GPUSource + "\n"
+	}
+	var b strings.Builder
+	for _, gpu := range m.usage.GPU {
+		fmt.Fprintf(&b, "GPU %d  %s\n", gpu.Index, gpu.Model)
+		b.WriteString(bar("  Util", gpu.UtilizationPercent, ""))
+		if gpu.MemoryTotalMB > 0 {
+			memPercent := float64(gpu.MemoryUsedMB) / float64(gpu.MemoryTotalMB) * 100
+			b.WriteString(bar("  Memory", memPercent, fmt.Sprintf("%d / %d MB", gpu.MemoryUsedMB, gpu.MemoryTotalMB)))
+		}
+		if gpu.TemperatureC > 0 {
+			fmt.Fprintf(&b, "%-12s %.0f°C\n", "  Temp", gpu.TemperatureC)
+		}
+	}
+	return b.String()
+}
+
+// bar renders one labeled utilization line, colored by how full it is.
+func bar(label string, percent float64, detail string) string {
+	clamped := min(max(percent, 0), 100)
+	filled := int(clamped / 100 * barWidth)
+	style := okStyle
+	switch {
+	case percent >= 90:
+		style = critStyle
+	case percent >= 70:
+		style = warnStyle
+	}
+	line := fmt.Sprintf("%-12s %s%s %5.1f%%",
+		truncate(label, 12),
+		style.Render(strings.Repeat("█", filled)),
+		dimStyle.Render(strings.Repeat("░", barWidth-filled)),
+		percent)
+	if detail != "" {
+		line += "  " + detail
+	}
+	return line + "\n"
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n-1] + "…"
+}
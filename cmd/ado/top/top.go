@@ -0,0 +1,102 @@
+package top
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// minInterval keeps the refresh rate from turning ado top into the
+// busiest process on the host.
+const minInterval = 250 * time.Millisecond
+
+// NewCommand returns the top command.
+func NewCommand() *cobra.Command {
+	var (
+		interval    time.Duration
+		section     string
+		snapshotDir string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "top",
+		Short: "Live dashboard of CPU, memory, disk, and GPU utilization",
+		Long: `Show a full-screen dashboard of live resource utilization, refreshed every
+--interval. It samples the same collectors as ado meta system: CPU
+(total and per core), memory and swap, every real filesystem, and NVIDIA
+GPUs through nvidia-smi when it is installed.
+
+Keys:
+  tab, right, l      next section
+  shift+tab, left, h previous section
+  1-5                overview, cpu, memory, disk, gpu
+  p, space           pause or resume sampling
+  s                  save the current sample as JSON to --snapshot-dir
+  q, esc, ctrl+c     quit
+
+ado top needs an interactive terminal. For scripts, use
+ado meta system --output json.
+
+Examples:
+  # Open the dashboard
+  ado top
+
+  # Refresh every second, starting on the GPU view
+  ado top --interval 1s --section gpu
+
+  # Save snapshots under /tmp
+  ado top --snapshot-dir /tmp`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			start := slices.Index(Sections, section)
+			if start < 0 {
+				return fmt.Errorf("invalid section %q (valid: %v)", section, Sections)
+			}
+			if interval < minInterval {
+				return fmt.Errorf("--interval must be at least %s", minInterval)
+			}
+			if !ui.IsTerminal(cmd.InOrStdin()) || !ui.IsTerminal(cmd.OutOrStdout()) {
+				return errors.New("ado top needs an interactive terminal; use 'ado meta system --output json' for scripts")
+			}
+
+			ctx := cmd.Context()
+			host, _ := os.Hostname()
+			sampler := internalmeta.NewUsageSampler()
+			m := model{
+				ctx:         ctx,
+				sample:      sampler.Sample,
+				interval:    interval,
+				snapshotDir: snapshotDir,
+				host:        host,
+				section:     start,
+			}
+
+			p := tea.NewProgram(m,
+				tea.WithAltScreen(),
+				tea.WithContext(ctx),
+				tea.WithInput(cmd.InOrStdin()),
+				tea.WithOutput(cmd.OutOrStdout()),
+			)
+			if _, err := p.Run(); err != nil && !(errors.Is(err, tea.ErrProgramKilled) && ctx.Err() != nil) {
+				return fmt.Errorf("run dashboard: %w", err)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().DurationVarP(&interval, "interval", "n", 2*time.Second, "Time between samples")
+	cmd.Flags().StringVar(&section, "section", "overview", "Section to start on: overview, cpu, memory, disk, gpu")
+	cmd.Flags().StringVar(&snapshotDir, "snapshot-dir", ".", "Directory snapshots are saved to")
+	_ = cmd.RegisterFlagCompletionFunc("section", completion.Fixed(Sections...))
+	_ = cmd.MarkFlagDirname("snapshot-dir")
+	return cmd
+}
@@ -0,0 +1,221 @@
+package top
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+)
+
+func testUsage() internalmeta.Usage {
+	return internalmeta.Usage{
+		Time:   time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC),
+		CPU:    internalmeta.CPUUsage{TotalPercent: 42.5, PerCore: []float64{40, 45}},
+		Memory: internalmeta.MemoryInfo{TotalMB: 16000, UsedMB: 8000, AvailableMB: 8000, UsedPercent: 50},
+		Storage: []internalmeta.StorageInfo{
+			{Device: "/dev/sda1", Mountpoint: "/", Filesystem: "ext4", TotalMB: 100000, UsedMB: 95000, UsedPercent: 95},
+		},
+		GPU:       []internalmeta.GPUUsage{{Index: 0, Model: "Tesla T4", UtilizationPercent: 12, MemoryUsedMB: 100, MemoryTotalMB: 15360, TemperatureC: 40}},
+		GPUSource: "nvidia-smi",
+	}
+}
+
+func newTestModel(t *testing.T) model {
+	t.Helper()
+	return model{
+		ctx:         context.Background(),
+		sample:      func(context.Context) internalmeta.Usage { return testUsage() },
+		interval:    time.Second,
+		snapshotDir: t.TempDir(),
+		host:        "testhost",
+	}
+}
+
+func update(t *testing.T, m model, msg tea.Msg) (model, tea.Cmd) {
+	t.Helper()
+	next, cmd := m.Update(msg)
+	return next.(model), cmd
+}
+
+func key(s string) tea.KeyMsg {
+	switch s {
+	case "tab":
+		return tea.KeyMsg{Type: tea.KeyTab}
+	case "shift+tab":
+		return tea.KeyMsg{Type: tea.KeyShiftTab}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestModel_Sections(t *testing.T) {
+	m := newTestModel(t)
+	m, _ = update(t, m, m.collect())
+
+	tests := []struct {
+		key  string
+		want int
+		view string
+	}{
+		{"tab", sectionCPU, "cpu1"},
+		{"tab", sectionMemory, "Available"},
+		{"tab", sectionDisk, "ext4 /dev/sda1"},
+		{"tab", sectionGPU, "Tesla T4"},
+		{"tab", sectionOverview, "GPU 0"},
+		{"shift+tab", sectionGPU, "15360 MB"},
+		{"3", sectionMemory, "Swap         none"},
+		{"1", sectionOverview, "Memory"},
+	}
+	for _, tt := range tests {
+		m, _ = update(t, m, key(tt.key))
+		if m.section != tt.want {
+			t.Fatalf("after %q: section = %d, want %d", tt.key, m.section, tt.want)
+		}
+		if view := m.View(); !strings.Contains(view, tt.view) {
+			t.Errorf("after %q: view missing %q:\n%s", tt.key, tt.view, view)
+		}
+	}
+}
+
+func TestModel_Sampling(t *testing.T) {
+	m := newTestModel(t)
+	if !strings.Contains(m.View(), "Collecting...") {
+		t.Error("view before first sample should say Collecting...")
+	}
+	if m.Init() == nil {
+		t.Fatal("Init() should start sampling")
+	}
+
+	m, cmd := update(t, m, m.collect())
+	if m.usage == nil || cmd == nil {
+		t.Fatal("sample should be stored and the next tick scheduled")
+	}
+
+	m, cmd = update(t, m, tickMsg(time.Now()))
+	if _, ok := cmd().(sampleMsg); !ok {
+		t.Error("tick should trigger a sample")
+	}
+
+	m, _ = update(t, m, key("p"))
+	if !m.paused || !strings.Contains(m.View(), "paused") {
+		t.Error("p should pause")
+	}
+	m.sample = func(context.Context) internalmeta.Usage {
+		t.Error("sampled while paused")
+		return internalmeta.Usage{}
+	}
+	if _, cmd = update(t, m, tickMsg(time.Now())); cmd == nil {
+		t.Error("paused model should keep ticking")
+	}
+}
+
+func TestModel_Quit(t *testing.T) {
+	for _, k := range []tea.KeyMsg{key("q"), {Type: tea.KeyEsc}, {Type: tea.KeyCtrlC}} {
+		_, cmd := update(t, newTestModel(t), k)
+		if cmd == nil {
+			t.Fatalf("%q: no command", k)
+		}
+		if _, ok := cmd().(tea.QuitMsg); !ok {
+			t.Errorf("%q did not quit", k)
+		}
+	}
+}
+
+func TestModel_Export(t *testing.T) {
+	m := newTestModel(t)
+	m, _ = update(t, m, key("s"))
+	if m.status != "No sample to export yet" {
+		t.Errorf("status = %q", m.status)
+	}
+
+	m, _ = update(t, m, m.collect())
+	m, _ = update(t, m, key("s"))
+	path := filepath.Join(m.snapshotDir, "ado-top-20250304-050607.json")
+	if m.status != "Saved snapshot to "+path {
+		t.Fatalf("status = %q", m.status)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got internalmeta.Usage
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.CPU.TotalPercent != 42.5 {
+		t.Errorf("snapshot cpu = %v", got.CPU.TotalPercent)
+	}
+
+	m.snapshotDir = filepath.Join(path, "nope")
+	m, _ = update(t, m, key("s"))
+	if !strings.HasPrefix(m.status, "Export failed: ") {
+		t.Errorf("status = %q, want export failure", m.status)
+	}
+}
+
+func TestModel_NoGPU(t *testing.T) {
+	m := newTestModel(t)
+	usage := testUsage()
+	usage.GPU, usage.GPUSource = nil, "none"
+	m, _ = update(t, m, sampleMsg(usage))
+	m, _ = update(t, m, key("5"))
+	if !strings.Contains(m.View(), "nvidia-smi not found") {
+		t.Errorf("view:\n%s", m.View())
+	}
+}
+
+func TestModel_UnresponsiveMount(t *testing.T) {
+	m := newTestModel(t)
+	usage := testUsage()
+	usage.Storage = append(usage.Storage, internalmeta.StorageInfo{Device: "nas:/export", Mountpoint: "/mnt/nas", Filesystem: "nfs", Unresponsive: true})
+	m, _ = update(t, m, sampleMsg(usage))
+	if view := m.View(); strings.Contains(view, "/mnt/nas") {
+		t.Errorf("overview should leave out unresponsive mounts:\n%s", view)
+	}
+	m, _ = update(t, m, key("4"))
+	if view := m.View(); !strings.Contains(view, "/mnt/nas     unresponsive  nfs nas:/export") {
+		t.Errorf("view:\n%s", view)
+	}
+}
+
+func TestBar(t *testing.T) {
+	got := bar("a-very-long-mountpoint", 150, "detail")
+	if !strings.Contains(got, "a-very-long…") || !strings.Contains(got, "150.0%  detail") {
+		t.Errorf("bar() = %q", got)
+	}
+	if n := strings.Count(got, "█"); n != barWidth {
+		t.Errorf("bar() filled %d cells, want %d", n, barWidth)
+	}
+}
+
+func TestCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"bad section", []string{"--section", "net"}, `invalid section "net"`},
+		{"fast interval", []string{"--interval", "10ms"}, "--interval must be at least 250ms"},
+		{"no terminal", nil, "needs an interactive terminal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := NewCommand()
+			cmd.SetIn(&bytes.Buffer{})
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(tt.args)
+			err := cmd.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package waitfor
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	"github.com/anowarislam/ado/internal/tap"
+	"github.com/anowarislam/ado/internal/ui"
+	internalwaitfor "github.com/anowarislam/ado/internal/waitfor"
+)
+
+// NewCommand returns the wait-for command.
+func NewCommand() *cobra.Command {
+	var (
+		opts   internalwaitfor.Options
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wait-for TARGET... [-- COMMAND [ARG...]]",
+		Short: "Wait for ports, URLs, files, or commands to become ready",
+		Long: `Poll one or more targets every --interval until all of them are ready,
+then exit 0. If --timeout passes first, print which targets were still
+pending and exit 1.
+
+Targets:
+  tcp://HOST:PORT   a TCP connection succeeds (HOST:PORT and :PORT also work)
+  http(s)://URL     a GET returns --status (default 200)
+  file://PATH       the file or directory exists
+  -- COMMAND ARG... the command exits 0 (its output is discarded)
+
+Targets are checked concurrently. Each attempt is bounded by
+--attempt-timeout.
+
+Examples:
+  # Wait for Postgres and the API before running tests
+  ado wait-for db:5432 http://api:8080/healthz && make test
+
+  # Wait up to 2 minutes for a file written by another container
+  ado wait-for file:///shared/ready --timeout 2m
+
+  # Wait until a command succeeds
+  ado wait-for --interval 2s -- pg_isready -h db
+
+  # Report as JSON
+  ado wait-for :6379 -o json
+
+  # Report as TAP, one test point per target
+  ado wait-for db:5432 :6379 -o tap`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output, ui.OutputTAP)
+			if err != nil {
+				return err
+			}
+			targets, err := parseTargets(args, cmd.ArgsLenAtDash())
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			results := internalwaitfor.Wait(ctx, targets, opts)
+			if format == ui.OutputTAP {
+				err = tap.Write(cmd.OutOrStdout(), resultTests(results))
+			} else {
+				err = ui.PrintOutput(cmd.OutOrStdout(), format, results, func() (string, error) {
+					return formatResults(results), nil
+				})
+			}
+			if err != nil {
+				return err
+			}
+
+			var pending []string
+			for _, r := range results {
+				if !r.Ready {
+					pending = append(pending, r.Target)
+				}
+			}
+			if len(pending) == 0 {
+				return nil
+			}
+			if opts.Timeout > 0 {
+				return ui.Reported(fmt.Errorf("timed out after %s waiting for %s", opts.Timeout, strings.Join(pending, ", ")))
+			}
+			return ui.Reported(fmt.Errorf("stopped waiting for %s", strings.Join(pending, ", ")))
+		},
+	}
+
+	cmd.Flags().DurationVar(&opts.Timeout, "timeout", internalwaitfor.DefaultTimeout, "Give up after this long (0 waits forever)")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", internalwaitfor.DefaultInterval, "Delay between attempts")
+	cmd.Flags().DurationVar(&opts.AttemptTimeout, "attempt-timeout", internalwaitfor.DefaultAttemptTimeout, "Timeout for a single attempt")
+	cmd.Flags().IntVar(&opts.Status, "status", internalwaitfor.DefaultStatus, "HTTP status code that counts as ready")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml, tap")
+	_ = cmd.RegisterFlagCompletionFunc("output", completion.Fixed("text", "json", "yaml", "tap"))
+	return cmd
+}
+
+// parseTargets parses the positional targets, treating everything after
+// "--" as a single command target.
+func parseTargets(args []string, dash int) ([]internalwaitfor.Target, error) {
+	positional, command := args, []string(nil)
+	if dash >= 0 {
+		positional, command = args[:dash], args[dash:]
+	}
+
+	var targets []internalwaitfor.Target
+	for _, arg := range positional {
+		target, err := internalwaitfor.ParseTarget(arg)
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, target)
+	}
+	if len(command) > 0 {
+		targets = append(targets, internalwaitfor.Target{Kind: internalwaitfor.KindCommand, Command: command})
+	}
+	if len(targets) == 0 {
+		return nil, errors.New("nothing to wait for: pass at least one target or -- COMMAND")
+	}
+	return targets, nil
+}
+
+// resultTests reports each target as a TAP test point that passes when
+// the target became ready.
+func resultTests(results []internalwaitfor.Result) []tap.Test {
+	tests := make([]tap.Test, len(results))
+	for i, r := range results {
+		diagnostics := map[string]any{"attempts": r.Attempts, "duration_ms": r.DurationMS}
+		if r.Error != "" {
+			diagnostics["error"] = r.Error
+		}
+		tests[i] = tap.Test{OK: r.Ready, Description: r.Target, Diagnostics: diagnostics}
+	}
+	return tests
+}
+
+func formatResults(results []internalwaitfor.Result) string {
+	var b strings.Builder
+	for _, r := range results {
+		elapsed := (time.Duration(r.DurationMS) * time.Millisecond).Round(100 * time.Millisecond)
+		if r.Ready {
+			fmt.Fprintf(&b, "ready    %s (%d attempts, %s)\n", r.Target, r.Attempts, elapsed)
+		} else {
+			fmt.Fprintf(&b, "pending  %s (%d attempts, %s): %s\n", r.Target, r.Attempts, elapsed, r.Error)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,103 @@
+package waitfor
+
+import (
+	"bytes"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	internalwaitfor "github.com/anowarislam/ado/internal/waitfor"
+)
+
+func execute(args ...string) (string, error) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewCommand())
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&bytes.Buffer{})
+	root.SetArgs(append([]string{"wait-for"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestWaitForCommand(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	out, err := execute(ln.Addr().String(), "-o", "json", "--", "sh", "-c", "exit 0")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	var results []internalwaitfor.Result
+	if err := json.Unmarshal([]byte(out), &results); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, out)
+	}
+	if len(results) != 2 || !results[0].Ready || !results[1].Ready {
+		t.Fatalf("results = %+v", results)
+	}
+	if results[0].Target != "tcp://"+ln.Addr().String() || results[1].Target != "sh -c exit 0" {
+		t.Errorf("targets = %q, %q", results[0].Target, results[1].Target)
+	}
+}
+
+func TestWaitForCommand_Timeout(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	out, err := execute("file://"+missing, "--timeout", "50ms", "--interval", "10ms")
+	if err == nil || err.Error() != "timed out after 50ms waiting for file://"+missing {
+		t.Errorf("Execute() error = %v", err)
+	}
+	if !strings.HasPrefix(out, "pending  file://"+missing+" (") {
+		t.Errorf("output = %q", out)
+	}
+}
+
+func TestWaitForCommand_TAP(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing")
+	out, err := execute("file://"+missing, "--timeout", "50ms", "--interval", "10ms", "-o", "tap")
+	if err == nil {
+		t.Fatal("Execute() error = nil, want timeout")
+	}
+	for _, want := range []string{"TAP version 13\n1..1\nnot ok 1 - file://" + missing + "\n", "  error: ", "  ...\n"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestWaitForCommand_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no targets", nil, "nothing to wait for"},
+		{"bad target", []string{"nope"}, `invalid target "nope"`},
+		{"bad output", []string{":80", "-o", "xml"}, "xml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := execute(tt.args...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatResults(t *testing.T) {
+	got := formatResults([]internalwaitfor.Result{
+		{Target: "tcp://db:5432", Ready: true, Attempts: 3, DurationMS: 2049},
+		{Target: "http://api", Attempts: 5, DurationMS: 5000, Error: "status 503, want 200"},
+	})
+	want := "ready    tcp://db:5432 (3 attempts, 2s)\n" +
+		"pending  http://api (5 attempts, 5s): status 503, want 200\n"
+	if got != want {
+		t.Errorf("formatResults() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,304 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/profiling"
+	"github.com/anowarislam/ado/internal/tasks"
+	"github.com/anowarislam/ado/internal/watch"
+)
+
+// clearScreen moves the cursor home and clears the terminal.
+const clearScreen = "\033[H\033[2J"
+
+// NewCommand returns the watch command.
+func NewCommand() *cobra.Command {
+	var (
+		paths     []string
+		include   []string
+		exclude   []string
+		debounce  time.Duration
+		taskName  string
+		clear     bool
+		restart   bool
+		ndjson    bool
+		pprofAddr string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "watch [flags] [-- command [args...]]",
+		Short: "Re-run a command or task when files change",
+		Long: `Watch directories for file changes and re-run a command, or a task from the
+config file, after each batch of changes settles.
+
+The command runs once at start. Changes that arrive while it is running
+queue a single re-run after it exits; with --restart the running command
+is stopped and started again instead.
+
+Patterns use glob syntax with ** for any number of directories. Patterns
+without a slash match a single path element (e.g. "*.go", "node_modules").
+VCS directories, node_modules, and editor swap files are always ignored.
+
+With --ndjson, lifecycle events (watching, change, start, exit, error) are
+written to stdout as newline-delimited JSON for editor and tool
+integration, and the command's stdout is redirected to stderr.
+
+Examples:
+  # Re-run tests when Go files change
+  ado watch -i '*.go' -- go test ./...
+
+  # Re-run a configured task, clearing the screen each time
+  ado watch --task build --clear
+
+  # Restart a server on change, ignoring generated files
+  ado watch --restart -e 'gen/**' -- go run ./cmd/server`,
+		Args: func(cmd *cobra.Command, args []string) error {
+			switch {
+			case taskName != "" && len(args) > 0:
+				return errors.New("use either --task or a command after --, not both")
+			case taskName == "" && len(args) == 0:
+				return errors.New("a command after -- or --task is required")
+			}
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name, task, baseDir, err := resolveTask(cmd, taskName, args)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			if pprofAddr != "" {
+				if _, err := profiling.Listen(ctx, pprofAddr); err != nil {
+					return err
+				}
+			}
+
+			s := &session{
+				name:    name,
+				task:    task,
+				clear:   clear && !ndjson,
+				restart: restart,
+				out:     cmd.OutOrStdout(),
+				log:     cmd.ErrOrStderr(),
+				runner: tasks.Runner{
+					Stdin:   cmd.InOrStdin(),
+					Stdout:  cmd.OutOrStdout(),
+					Stderr:  cmd.ErrOrStderr(),
+					BaseDir: baseDir,
+				},
+			}
+			if ndjson {
+				s.events = watch.NewEventWriter(cmd.OutOrStdout())
+				s.runner.Stdout = cmd.ErrOrStderr()
+			}
+
+			w := &watch.Watcher{
+				Roots:    paths,
+				Matcher:  watch.Matcher{Include: include, Exclude: exclude},
+				Debounce: debounce,
+			}
+			return s.watch(ctx, w)
+		},
+	}
+
+	cmd.Flags().StringSliceVarP(&paths, "path", "p", []string{"."}, "Directories or files to watch (repeatable)")
+	cmd.Flags().StringSliceVarP(&include, "include", "i", nil, "Only react to paths matching these globs (repeatable)")
+	cmd.Flags().StringSliceVarP(&exclude, "exclude", "e", nil, "Ignore paths matching these globs (repeatable)")
+	cmd.Flags().DurationVar(&debounce, "debounce", watch.DefaultDebounce, "Wait for changes to settle this long before running")
+	cmd.Flags().StringVar(&taskName, "task", "", "Run a task from the config file instead of a command")
+	_ = cmd.RegisterFlagCompletionFunc("task", completion.TaskNames)
+	cmd.Flags().BoolVar(&clear, "clear", false, "Clear the screen before each run")
+	cmd.Flags().BoolVar(&restart, "restart", false, "Stop the running command on change instead of waiting for it")
+	cmd.Flags().BoolVar(&ndjson, "ndjson", false, "Write lifecycle events to stdout as newline-delimited JSON")
+	profiling.AddListenFlag(cmd, &pprofAddr)
+	return cmd
+}
+
+// resolveTask returns the task to run: a named task from the config file
+// or an ad-hoc command built from args.
+func resolveTask(cmd *cobra.Command, taskName string, args []string) (string, internalconfig.Task, string, error) {
+	if taskName == "" {
+		return args[0], internalconfig.Task{Command: args[0], Args: args[1:]}, "", nil
+	}
+
+	configPath, err := cmd.Root().PersistentFlags().GetString("config")
+	if err != nil {
+		return "", internalconfig.Task{}, "", err
+	}
+	homeDir, _ := os.UserHomeDir()
+	cfg, path, err := internalconfig.PathResolverFor(cmd.Context(), configPath, homeDir).Load()
+	if err != nil {
+		return "", internalconfig.Task{}, "", err
+	}
+
+	task, ok := cfg.Tasks[taskName]
+	if !ok {
+		return "", internalconfig.Task{}, "", fmt.Errorf("unknown task %q", taskName)
+	}
+	baseDir := ""
+	if path != "" {
+		baseDir = filepath.Dir(path)
+	}
+	return taskName, task, baseDir, nil
+}
+
+// session runs the task in response to change batches.
+type session struct {
+	name    string
+	task    internalconfig.Task
+	runner  tasks.Runner
+	clear   bool
+	restart bool
+	out     io.Writer // terminal output (screen clearing)
+	log     io.Writer // human-readable status lines
+	events  *watch.EventWriter
+
+	cancel context.CancelFunc
+	done   chan runResult
+}
+
+type runResult struct {
+	err      error
+	duration time.Duration
+}
+
+// watch runs the task once, then again after each batch of changes,
+// until ctx is done.
+func (s *session) watch(ctx context.Context, w *watch.Watcher) error {
+	changes := make(chan []watch.Change, 16)
+	watchErr := make(chan error, 1)
+	go func() {
+		watchErr <- w.Run(ctx, func(batch []watch.Change) {
+			select {
+			case changes <- batch:
+			case <-ctx.Done():
+			}
+		})
+	}()
+
+	s.emit(watch.Event{Event: watch.EventWatching, Roots: w.Roots}, "watching %s", strings.Join(w.Roots, ", "))
+	s.start(ctx, nil)
+
+	var queued []watch.Change
+	for {
+		select {
+		case <-ctx.Done():
+			s.stop()
+			return <-watchErr
+
+		case err := <-watchErr:
+			s.stop()
+			if err != nil && s.events != nil {
+				_ = s.events.Write(watch.Event{Event: watch.EventError, Error: err.Error()})
+			}
+			return err
+
+		case batch := <-changes:
+			s.emit(watch.Event{Event: watch.EventChange, Changes: batch}, "changed: %s", describeChanges(batch))
+			switch {
+			case s.done == nil:
+				s.start(ctx, batch)
+			case s.restart:
+				s.stop()
+				s.start(ctx, batch)
+			default:
+				queued = append(queued, batch...)
+			}
+
+		case res := <-s.done:
+			s.finish(res, false)
+			if queued != nil {
+				s.start(ctx, queued)
+				queued = nil
+			}
+		}
+	}
+}
+
+// start runs the task in the background.
+func (s *session) start(ctx context.Context, trigger []watch.Change) {
+	if s.clear {
+		fmt.Fprint(s.out, clearScreen)
+	}
+	command := append([]string{s.task.Command}, s.task.Args...)
+	s.emit(watch.Event{Event: watch.EventStart, Command: command, Changes: trigger}, "running: %s", strings.Join(command, " "))
+
+	runCtx, cancel := context.WithCancel(ctx)
+	done := make(chan runResult, 1)
+	s.cancel, s.done = cancel, done
+	go func() {
+		started := time.Now()
+		err := s.runner.Run(runCtx, s.name, s.task, nil)
+		done <- runResult{err: err, duration: time.Since(started)}
+	}()
+}
+
+// stop cancels a running task and reports how it ended.
+func (s *session) stop() {
+	if s.done == nil {
+		return
+	}
+	s.cancel()
+	s.finish(<-s.done, true)
+}
+
+// finish reports how a run ended; stopped is set when ado cancelled it.
+func (s *session) finish(res runResult, stopped bool) {
+	s.cancel()
+	s.cancel, s.done = nil, nil
+
+	ev := watch.Event{Event: watch.EventExit, DurationMS: res.duration.Milliseconds()}
+	elapsed := res.duration.Round(time.Millisecond)
+
+	var exitErr *tasks.ExitError
+	switch {
+	case res.err == nil:
+		code := 0
+		ev.ExitCode = &code
+		s.emit(ev, "exited with status 0 (%s)", elapsed)
+	case errors.As(res.err, &exitErr):
+		ev.ExitCode = &exitErr.Code
+		s.emit(ev, "exited with status %d (%s)", exitErr.Code, elapsed)
+	case stopped:
+		ev.Error = "stopped"
+		s.emit(ev, "stopped (%s)", elapsed)
+	default:
+		ev.Error = res.err.Error()
+		s.emit(ev, "%v (%s)", res.err, elapsed)
+	}
+}
+
+// emit writes ev in NDJSON mode, otherwise a human-readable status line.
+func (s *session) emit(ev watch.Event, format string, args ...any) {
+	if s.events != nil {
+		_ = s.events.Write(ev)
+		return
+	}
+	fmt.Fprintf(s.log, "[watch] "+format+"\n", args...)
+}
+
+// describeChanges summarizes a batch for status lines.
+func describeChanges(batch []watch.Change) string {
+	const shown = 3
+	var names []string
+	for i, c := range batch {
+		if i == shown {
+			names = append(names, fmt.Sprintf("and %d more", len(batch)-shown))
+			break
+		}
+		names = append(names, c.Path)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,184 @@
+package watch
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/watch"
+)
+
+// syncBuffer is a bytes.Buffer safe for concurrent writes and reads.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func newTestRoot() *cobra.Command {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.PersistentFlags().String("config", "", "")
+	root.AddCommand(NewCommand())
+	return root
+}
+
+func TestWatch_Args(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"nothing to run", []string{"watch"}, "a command after -- or --task is required"},
+		{"both", []string{"watch", "--task", "build", "--", "make"}, "not both"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := newTestRoot()
+			root.SetArgs(tt.args)
+			err := root.Execute()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want containing %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatch_UnknownTask(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := newTestRoot()
+	root.SetArgs([]string{"--config", path, "watch", "--task", "nope"})
+	if err := root.Execute(); err == nil || !strings.Contains(err.Error(), `unknown task "nope"`) {
+		t.Errorf("Execute() error = %v", err)
+	}
+}
+
+// readEvents parses the NDJSON written so far.
+func readEvents(t *testing.T, out string) []watch.Event {
+	t.Helper()
+	var events []watch.Event
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	for scanner.Scan() {
+		var ev watch.Event
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("invalid NDJSON line %q: %v", scanner.Text(), err)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+// waitFor polls until cond holds for the parsed events.
+func waitFor(t *testing.T, out *syncBuffer, cond func([]watch.Event) bool) []watch.Event {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if events := readEvents(t, out.String()); cond(events) {
+			return events
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+	t.Fatalf("timed out; events so far:\n%s", out.String())
+	return nil
+}
+
+func countEvents(events []watch.Event, kind string) int {
+	n := 0
+	for _, ev := range events {
+		if ev.Event == kind {
+			n++
+		}
+	}
+	return n
+}
+
+func TestWatch_NDJSON(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	dir := t.TempDir()
+	var stdout, stderr syncBuffer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	root := newTestRoot()
+	root.SetOut(&stdout)
+	root.SetErr(&stderr)
+	root.SetArgs([]string{"watch", "--ndjson", "-p", dir, "-i", "*.go", "--debounce", "50ms", "--", "sh", "-c", "echo ran; exit 2"})
+
+	done := make(chan error, 1)
+	go func() { done <- root.ExecuteContext(ctx) }()
+
+	waitFor(t, &stdout, func(events []watch.Event) bool { return countEvents(events, watch.EventExit) == 1 })
+
+	if err := os.WriteFile(filepath.Join(dir, "ignored.txt"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	events := waitFor(t, &stdout, func(events []watch.Event) bool { return countEvents(events, watch.EventExit) == 2 })
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	var kinds []string
+	for _, ev := range events {
+		kinds = append(kinds, ev.Event)
+	}
+	if got, want := strings.Join(kinds, ","), "watching,start,exit,change,start,exit"; got != want {
+		t.Errorf("events = %s, want %s", got, want)
+	}
+
+	change := events[3]
+	if len(change.Changes) != 1 || change.Changes[0].Path != "main.go" {
+		t.Errorf("change event = %+v, want only main.go", change)
+	}
+	if exit := events[5]; exit.ExitCode == nil || *exit.ExitCode != 2 {
+		t.Errorf("exit event = %+v, want exit code 2", exit)
+	}
+	// readEvents fails on any non-JSON line, so command output must have
+	// gone to stderr.
+	readEvents(t, stdout.String())
+	if !strings.Contains(stderr.String(), "ran\n") {
+		t.Errorf("command output missing from stderr: %q", stderr.String())
+	}
+}
+
+func TestDescribeChanges(t *testing.T) {
+	batch := []watch.Change{{Path: "a"}, {Path: "b"}, {Path: "c"}, {Path: "d"}, {Path: "e"}}
+	if got, want := describeChanges(batch[:2]), "a, b"; got != want {
+		t.Errorf("describeChanges() = %q, want %q", got, want)
+	}
+	if got, want := describeChanges(batch), "a, b, c, and 2 more"; got != want {
+		t.Errorf("describeChanges() = %q, want %q", got, want)
+	}
+}
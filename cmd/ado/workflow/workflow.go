@@ -0,0 +1,265 @@
+package workflow
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/completion"
+	"github.com/anowarislam/ado/internal/ghactions"
+	"github.com/anowarislam/ado/internal/junit"
+	"github.com/anowarislam/ado/internal/ui"
+	"github.com/anowarislam/ado/internal/validation"
+	internalworkflow "github.com/anowarislam/ado/internal/workflow"
+)
+
+// NewCommand returns the workflow command.
+func NewCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workflow",
+		Short: "Run multi-step pipelines defined in YAML",
+		Long: `Run multi-step pipelines defined in a YAML file: ordered steps with
+their own environment, working directory, and timeout, if: conditions,
+continue-on-error, and outputs passed from one step to the next.`,
+	}
+	cmd.AddCommand(newRunCommand(), newLintCommand())
+	return cmd
+}
+
+func newRunCommand() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "run FILE",
+		Short: "Run a workflow file",
+		Long: `Run the steps of a workflow file in order.
+
+Each step has either run, a script for the workflow's shell (sh unless
+shell: is set), or command and args, run directly without a shell. env,
+cwd (relative to the workflow file), and timeout apply per step;
+workflow-level env applies to every step.
+
+A failing step fails the workflow and skips the steps after it, unless
+it has continue-on-error: true. if: decides whether a step runs:
+
+  if: always()                                  run even after a failure
+  if: failure()                                 run only after a failure
+  if: steps.build.outputs.changed == 'true'     run when no step failed
+                                                and the output matches
+
+A step publishes outputs by appending NAME=VALUE lines (or NAME<<EOF
+... EOF for multi-line values) to the file named by $ADO_OUTPUT. Later
+steps read them as ${{ steps.ID.outputs.NAME }} in run, command, args,
+env, and cwd, alongside steps.ID.outcome, steps.ID.exit_code, and
+env.NAME.
+
+Step output streams to the terminal, with a progress line as each step
+starts and ends on stderr. With --output json, yaml, or junit, step output
+goes to stderr and a report of every step is printed when the run ends;
+junit reports each step as a test case for CI test report views.
+ado exits 1 when the workflow fails.
+
+Examples:
+  # Run a pipeline
+  ado workflow run release.yaml
+
+  # Structured report for CI
+  ado workflow run ci.yaml -o json > report.json
+
+  # JUnit report for Jenkins, GitLab, or Azure Pipelines
+  ado workflow run ci.yaml -o junit > report.xml
+
+A workflow file:
+  name: release
+  env:
+    REGISTRY: ghcr.io/acme
+  steps:
+    - id: version
+      run: echo "tag=$(git describe --tags)" >> "$ADO_OUTPUT"
+    - name: Build image
+      command: docker
+      args: [build, -t, "${{ env.REGISTRY }}/app:${{ steps.version.outputs.tag }}", .]
+      timeout: 10m
+    - name: Notify
+      if: failure()
+      run: ./scripts/notify.sh`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output, ui.OutputJUnit)
+			if err != nil {
+				return err
+			}
+			wf, err := internalworkflow.Load(args[0])
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+
+			runner := internalworkflow.Runner{Stdout: cmd.OutOrStdout(), Stderr: cmd.ErrOrStderr()}
+			if format == ui.OutputText {
+				runner.Progress = cmd.ErrOrStderr()
+			} else {
+				runner.Stdout = cmd.ErrOrStderr()
+			}
+			report, err := runner.Run(ctx, wf)
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case ui.OutputText:
+				fmt.Fprintln(cmd.ErrOrStderr(), formatSummary(report))
+			case ui.OutputJUnit:
+				name := report.Name
+				if name == "" {
+					name = args[0]
+				}
+				if err := junit.Write(cmd.OutOrStdout(), "ado workflow run", workflowSuite(name, report)); err != nil {
+					return err
+				}
+			default:
+				if err := ui.PrintOutput(cmd.OutOrStdout(), format, report, nil); err != nil {
+					return err
+				}
+			}
+			if report.Status != internalworkflow.StatusSuccess {
+				return ui.Reported(fmt.Errorf("workflow failed: %s", strings.Join(failedSteps(report), ", ")))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml, junit")
+	_ = cmd.RegisterFlagCompletionFunc("output", completion.Fixed("text", "json", "yaml", "junit"))
+	return cmd
+}
+
+// lintReporter reports the results of ado workflow lint.
+var lintReporter = validation.Reporter{Title: "ado workflow lint", Subject: "Workflow", Rules: internalworkflow.LintRules}
+
+func newLintCommand() *cobra.Command {
+	var (
+		strict bool
+		output string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "lint FILE",
+		Short: "Check a workflow file without running it",
+		Long: `Check a workflow file without running it, reporting every problem
+with its line, where ado workflow run stops at the first:
+
+  Errors     unknown keys, values of the wrong type, incomplete steps,
+             invalid ids and expressions, duplicate ids, and steps.ID
+             references to steps that are undefined or run later
+  Warnings   steps whose if: condition is never true, and step outputs
+             or env values interpolated into a shell script with ${{ }},
+             where a value can inject commands
+
+ado exits 1 when there are errors, or with --strict, warnings. Like ado
+config validate, issues are GitHub Actions annotations when run there.
+
+Examples:
+  # Check a workflow
+  ado workflow lint release.yaml
+
+  # SARIF for GitHub code scanning
+  ado workflow lint ci.yaml -o sarif > workflow.sarif`,
+		Args: cobra.ExactArgs(1),
+		ValidArgsFunction: func(_ *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := ui.ParseOutputFormat(output, validation.Formats...)
+			if err != nil {
+				return err
+			}
+			result, err := internalworkflow.Lint(args[0])
+			if err != nil {
+				return err
+			}
+			if strict {
+				validation.Strict(result)
+			}
+			if err := lintReporter.Write(cmd.OutOrStdout(), format, result); err != nil {
+				return err
+			}
+			if ghactions.Enabled() {
+				if err := lintReporter.ReportToActions(cmd.OutOrStdout(), format, result); err != nil {
+					return err
+				}
+			}
+			if !result.Valid {
+				return ui.Reported(fmt.Errorf("%s: %d errors", args[0], len(result.Errors)))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVarP(&strict, "strict", "s", false, "Treat warnings as errors")
+	cmd.Flags().StringVarP(&output, "output", "o", "text", validation.OutputHelp)
+	_ = cmd.RegisterFlagCompletionFunc("output", completion.Fixed("text", "json", "yaml", "junit", "sarif", "tap"))
+	return cmd
+}
+
+// failedSteps names the steps that failed the workflow.
+func failedSteps(r internalworkflow.Report) []string {
+	var names []string
+	for _, step := range r.Steps {
+		if step.Status == internalworkflow.StatusFailure && !step.ContinueOnError {
+			names = append(names, step.Name)
+		}
+	}
+	if len(names) == 0 {
+		names = append(names, "canceled")
+	}
+	return names
+}
+
+// workflowSuite reports r as a JUnit suite called name, with one case per
+// step.
+func workflowSuite(name string, r internalworkflow.Report) junit.TestSuite {
+	cases := make([]junit.TestCase, len(r.Steps))
+	for i, step := range r.Steps {
+		c := junit.TestCase{
+			Name:      step.Name,
+			Classname: name,
+			Time:      junit.Seconds(time.Duration(step.DurationMS) * time.Millisecond),
+		}
+		switch step.Status {
+		case internalworkflow.StatusFailure:
+			message := step.Error
+			if message == "" {
+				message = fmt.Sprintf("exit status %d", step.ExitCode)
+			}
+			if step.ContinueOnError {
+				message += " (continue-on-error)"
+			}
+			c.Failure = &junit.Failure{Message: message, Type: "step"}
+		case internalworkflow.StatusSkipped:
+			c.Skipped = &junit.Skipped{Message: "not run"}
+		}
+		cases[i] = c
+	}
+	suite := junit.NewSuite(name, cases...)
+	suite.Time = junit.Seconds(time.Duration(r.DurationMS) * time.Millisecond)
+	return suite
+}
+
+func formatSummary(r internalworkflow.Report) string {
+	return fmt.Sprintf("%d succeeded, %d failed, %d skipped (%d steps)",
+		r.Count(internalworkflow.StatusSuccess), r.Count(internalworkflow.StatusFailure),
+		r.Count(internalworkflow.StatusSkipped), len(r.Steps))
+}
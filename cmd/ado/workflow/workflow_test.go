@@ -0,0 +1,206 @@
+package workflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/junit"
+	"github.com/anowarislam/ado/internal/ui"
+	internalworkflow "github.com/anowarislam/ado/internal/workflow"
+)
+
+func execute(args ...string) (stdout, stderr string, err error) {
+	root := &cobra.Command{Use: "ado", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewCommand())
+	var out, errOut bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&errOut)
+	root.SetArgs(append([]string{"workflow"}, args...))
+	err = root.Execute()
+	return out.String(), errOut.String(), err
+}
+
+func writeWorkflow(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wf.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestWorkflowRun_Text(t *testing.T) {
+	path := writeWorkflow(t, `
+steps:
+  - id: greet
+    run: echo "who=world" >> "$ADO_OUTPUT"
+  - name: Say hello
+    run: echo "hello ${{ steps.greet.outputs.who }}"
+`)
+	stdout, stderr, err := execute("run", path)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if stdout != "hello world\n" {
+		t.Errorf("stdout = %q", stdout)
+	}
+	for _, want := range []string{"==> [2/2] Say hello\n", "--> [1/2] greet ok", "2 succeeded, 0 failed, 0 skipped (2 steps)\n"} {
+		if !strings.Contains(stderr, want) {
+			t.Errorf("stderr missing %q:\n%s", want, stderr)
+		}
+	}
+}
+
+func TestWorkflowRun_Failure(t *testing.T) {
+	path := writeWorkflow(t, `
+steps:
+  - name: Test
+    run: exit 2
+  - run: echo never
+`)
+	stdout, stderr, err := execute("run", path)
+	if err == nil || err.Error() != "workflow failed: Test" {
+		t.Errorf("Execute() error = %v", err)
+	}
+	if stdout != "" {
+		t.Errorf("stdout = %q", stdout)
+	}
+	if !strings.Contains(stderr, "0 succeeded, 1 failed, 1 skipped (2 steps)") {
+		t.Errorf("stderr = %q", stderr)
+	}
+}
+
+func TestWorkflowRun_JSON(t *testing.T) {
+	path := writeWorkflow(t, "name: ci\nsteps:\n  - id: a\n    run: echo out; echo v=1 >> \"$ADO_OUTPUT\"\n")
+	stdout, stderr, err := execute("run", path, "-o", "json")
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if stderr != "out\n" {
+		t.Errorf("stderr = %q, want step output", stderr)
+	}
+	var report internalworkflow.Report
+	if err := json.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("output is not JSON: %v\n%s", err, stdout)
+	}
+	if report.Name != "ci" || report.Status != "success" || report.Steps[0].Outputs["v"] != "1" {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestWorkflowRun_JUnit(t *testing.T) {
+	path := writeWorkflow(t, `
+name: ci
+steps:
+  - name: Build
+    run: echo built
+  - name: Lint
+    run: exit 3
+    continue-on-error: true
+  - name: Test
+    run: exit 2
+  - name: Deploy
+    run: echo never
+`)
+	stdout, stderr, err := execute("run", path, "-o", "junit")
+	if err == nil {
+		t.Fatal("Execute() error = nil, want failure")
+	}
+	if stderr != "built\n" {
+		t.Errorf("stderr = %q, want step output", stderr)
+	}
+	var report junit.TestSuites
+	if err := xml.Unmarshal([]byte(stdout), &report); err != nil {
+		t.Fatalf("output is not XML: %v\n%s", err, stdout)
+	}
+	suite := report.Suites[0]
+	if suite.Name != "ci" || suite.Tests != 4 || suite.Failures != 2 || suite.Skipped != 1 {
+		t.Errorf("suite = %s: %d tests, %d failures, %d skipped", suite.Name, suite.Tests, suite.Failures, suite.Skipped)
+	}
+	if got := suite.Cases[1].Failure.Message; got != "exit status 3 (continue-on-error)" {
+		t.Errorf("Lint failure = %q", got)
+	}
+	if suite.Cases[3].Skipped == nil {
+		t.Errorf("Deploy = %+v, want skipped", suite.Cases[3])
+	}
+}
+
+func TestWorkflowRun_Errors(t *testing.T) {
+	invalid := writeWorkflow(t, "steps:\n  - name: x\n")
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"no file", []string{"run"}, "accepts 1 arg(s)"},
+		{"missing", []string{"run", "does-not-exist.yaml"}, "open workflow"},
+		{"invalid", []string{"run", invalid}, "step 1: needs run or command"},
+		{"bad output", []string{"run", invalid, "-o", "xml"}, "unsupported output format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := execute(tt.args...); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Execute() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowLint(t *testing.T) {
+	path := writeWorkflow(t, "steps:\n  - run: make\n")
+	stdout, _, err := execute("lint", path)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if stdout != "✓ Workflow valid: "+path+"\n" {
+		t.Errorf("stdout = %q", stdout)
+	}
+
+	path = writeWorkflow(t, `steps:
+  - id: build
+    run: make
+    timeout: 5m
+    retries: 2
+  - command: sh
+    args: [-c, "echo ${{ steps.build.outputs.version }}"]
+    if: failure() && false
+`)
+	stdout, _, err = execute("lint", path)
+	if !ui.IsReported(err) {
+		t.Fatalf("Execute() error = %v, want a reported error", err)
+	}
+	for _, want := range []string{
+		"✗ Workflow invalid: " + path,
+		`Error: step "build": unknown key "retries" at line 5`,
+		"Warning: step 2 never runs: if: failure() && false is always false at line 8",
+		"Warning: step 2: ${{ steps.build.outputs.version }} is interpolated into the script sh runs",
+	} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("stdout lacks %q:\n%s", want, stdout)
+		}
+	}
+
+	stdout, _, _ = execute("lint", path, "-o", "sarif")
+	for _, want := range []string{`"ruleId": "unknown-key"`, `"ruleId": "unreachable-step"`, `"id": "shell-interpolation"`} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("SARIF lacks %q:\n%s", want, stdout)
+		}
+	}
+}
+
+func TestWorkflowLint_Strict(t *testing.T) {
+	path := writeWorkflow(t, "steps:\n  - run: ./cleanup\n    if: failure()\n")
+	if _, _, err := execute("lint", path); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if _, _, err := execute("lint", path, "--strict"); !ui.IsReported(err) {
+		t.Errorf("--strict: error = %v, want a reported error", err)
+	}
+}
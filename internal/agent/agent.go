@@ -0,0 +1,80 @@
+package agent
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/anowarislam/ado/internal/logging"
+	"github.com/anowarislam/ado/internal/meta"
+)
+
+// DefaultInterval is how often `ado agent run` takes a snapshot.
+const DefaultInterval = time.Minute
+
+// Agent takes a snapshot every Interval, appends it to Store, and prunes
+// the store to Retention.
+type Agent struct {
+	Store     *Store
+	Interval  time.Duration
+	Retention Retention
+	// Sample takes a snapshot; nil uses Sampler.
+	Sample func(ctx context.Context) Snapshot
+	Logger logging.Logger
+}
+
+// Sampler returns a Sample function backed by the host's collectors. CPU
+// usage is measured between consecutive calls, so it averages over the
+// interval.
+func Sampler() func(ctx context.Context) Snapshot {
+	sampler := meta.NewUsageSampler()
+	host, _ := os.Hostname()
+	return func(ctx context.Context) Snapshot {
+		return NewSnapshot(host, sampler.Sample(ctx))
+	}
+}
+
+// Run takes snapshots until ctx is done. Failing to write one is logged
+// and retried at the next interval, so a full disk does not stop the
+// agent for good.
+func (a *Agent) Run(ctx context.Context) {
+	sample := a.Sample
+	if sample == nil {
+		sample = Sampler()
+	}
+	logger := a.Logger
+	if logger == nil {
+		logger = logging.NopLogger()
+	}
+
+	a.prune(logger)
+	// The first CPU figure covers the time since boot; discard it so
+	// every stored figure covers one interval.
+	sample(ctx)
+	ticker := time.NewTicker(a.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		snap := sample(ctx)
+		if err := a.Store.Append(snap); err != nil {
+			logger.Error("agent: write snapshot", "error", err)
+			continue
+		}
+		logger.Debug("agent: snapshot", "time", snap.Time, "cpu_percent", snap.CPU, "memory_used_percent", snap.Memory.UsedPercent)
+		a.prune(logger)
+	}
+}
+
+func (a *Agent) prune(logger logging.Logger) {
+	removed, err := a.Store.Prune(a.Retention)
+	if err != nil {
+		logger.Error("agent: prune store", "error", err)
+	}
+	if removed > 0 {
+		logger.Info("agent: pruned", "snapshots", removed)
+	}
+}
@@ -0,0 +1,119 @@
+// Package agent records periodic snapshots of the host's resource usage
+// to a local store, so history and alerting can work without an external
+// monitoring system.
+package agent
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/anowarislam/ado/internal/meta"
+)
+
+// Snapshot is a compact record of resource usage at one point in time:
+// the figures of `ado top` without per-core, device, and model details.
+type Snapshot struct {
+	Time    time.Time `json:"time" yaml:"time"`
+	Host    string    `json:"host" yaml:"host"`
+	CPU     float64   `json:"cpu_percent" yaml:"cpu_percent"`
+	Memory  Memory    `json:"memory" yaml:"memory"`
+	Storage []Volume  `json:"storage,omitempty" yaml:"storage,omitempty"`
+	GPU     []GPU     `json:"gpu,omitempty" yaml:"gpu,omitempty"`
+}
+
+// Memory is memory and swap usage.
+type Memory struct {
+	UsedPercent float64 `json:"used_percent" yaml:"used_percent"`
+	UsedMB      uint64  `json:"used_mb" yaml:"used_mb"`
+	AvailableMB uint64  `json:"available_mb" yaml:"available_mb"`
+	TotalMB     uint64  `json:"total_mb" yaml:"total_mb"`
+	SwapUsedMB  uint64  `json:"swap_used_mb" yaml:"swap_used_mb"`
+}
+
+// Volume is the usage of one mounted filesystem.
+type Volume struct {
+	Mountpoint  string  `json:"mountpoint" yaml:"mountpoint"`
+	UsedPercent float64 `json:"used_percent" yaml:"used_percent"`
+	UsedMB      uint64  `json:"used_mb" yaml:"used_mb"`
+	FreeMB      uint64  `json:"free_mb" yaml:"free_mb"`
+}
+
+// GPU is the utilization of one GPU.
+type GPU struct {
+	Index              int     `json:"index" yaml:"index"`
+	UtilizationPercent float64 `json:"utilization_percent" yaml:"utilization_percent"`
+	MemoryUsedMB       uint64  `json:"memory_used_mb" yaml:"memory_used_mb"`
+}
+
+// NewSnapshot condenses a usage sample taken on host. Volumes that did
+// not answer in time are left out rather than recorded as empty.
+func NewSnapshot(host string, usage meta.Usage) Snapshot {
+	s := Snapshot{
+		Time: usage.Time.UTC(),
+		Host: host,
+		CPU:  usage.CPU.TotalPercent,
+		Memory: Memory{
+			UsedPercent: usage.Memory.UsedPercent,
+			UsedMB:      usage.Memory.UsedMB,
+			AvailableMB: usage.Memory.AvailableMB,
+			TotalMB:     usage.Memory.TotalMB,
+			SwapUsedMB:  usage.Memory.SwapUsedMB,
+		},
+	}
+	for _, v := range usage.Storage {
+		if v.Unresponsive {
+			continue
+		}
+		s.Storage = append(s.Storage, Volume{
+			Mountpoint:  v.Mountpoint,
+			UsedPercent: v.UsedPercent,
+			UsedMB:      v.UsedMB,
+			FreeMB:      v.FreeMB,
+		})
+	}
+	for _, g := range usage.GPU {
+		s.GPU = append(s.GPU, GPU{
+			Index:              g.Index,
+			UtilizationPercent: g.UtilizationPercent,
+			MemoryUsedMB:       g.MemoryUsedMB,
+		})
+	}
+	return s
+}
+
+// Sample is one numeric field of a snapshot.
+type Sample struct {
+	Field string
+	Value float64
+}
+
+// Samples lists the numeric fields of s, named like the JSON paths of a
+// snapshot with list items keyed by mountpoint or GPU index:
+// cpu_percent, memory.used_percent, storage[/].free_mb,
+// gpu[0].utilization_percent.
+func (s Snapshot) Samples() []Sample {
+	samples := []Sample{
+		{"cpu_percent", s.CPU},
+		{"memory.used_percent", s.Memory.UsedPercent},
+		{"memory.used_mb", float64(s.Memory.UsedMB)},
+		{"memory.available_mb", float64(s.Memory.AvailableMB)},
+		{"memory.total_mb", float64(s.Memory.TotalMB)},
+		{"memory.swap_used_mb", float64(s.Memory.SwapUsedMB)},
+	}
+	for _, v := range s.Storage {
+		prefix := "storage[" + v.Mountpoint + "]."
+		samples = append(samples,
+			Sample{prefix + "used_percent", v.UsedPercent},
+			Sample{prefix + "used_mb", float64(v.UsedMB)},
+			Sample{prefix + "free_mb", float64(v.FreeMB)},
+		)
+	}
+	for _, g := range s.GPU {
+		prefix := "gpu[" + strconv.Itoa(g.Index) + "]."
+		samples = append(samples,
+			Sample{prefix + "utilization_percent", g.UtilizationPercent},
+			Sample{prefix + "memory_used_mb", float64(g.MemoryUsedMB)},
+		)
+	}
+	return samples
+}
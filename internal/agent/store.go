@@ -0,0 +1,509 @@
+package agent
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/anowarislam/ado/internal/config"
+)
+
+// DirName is the store's directory inside the state directory.
+const DirName = "agent"
+
+// FileName is the store's SQLite database inside its directory.
+const FileName = "agent.db"
+
+// busyTimeout bounds how long a write waits for another process using
+// the database, such as `ado meta history` reading while the agent runs.
+const busyTimeout = 5 * time.Second
+
+// Default retention of `ado agent run`.
+const (
+	DefaultMaxAge   = 7 * 24 * time.Hour
+	DefaultMaxBytes = 100 << 20
+)
+
+// schema creates the tables of a new store. Snapshots are kept whole for
+// reading back; samples hold their numeric fields, one row each, for
+// queries over time.
+const schema = `
+PRAGMA auto_vacuum = INCREMENTAL;
+CREATE TABLE IF NOT EXISTS snapshots (
+	time INTEGER NOT NULL,
+	host TEXT NOT NULL,
+	data TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS snapshots_time ON snapshots (time);
+CREATE TABLE IF NOT EXISTS samples (
+	field TEXT NOT NULL,
+	time INTEGER NOT NULL,
+	value REAL NOT NULL
+);
+CREATE INDEX IF NOT EXISTS samples_field_time ON samples (field, time);
+CREATE INDEX IF NOT EXISTS samples_time ON samples (time);
+`
+
+// DefaultDir returns the store's directory in ado's state directory.
+func DefaultDir() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, DirName), nil
+}
+
+// Retention bounds how much history a store keeps. Zero fields are not
+// enforced.
+type Retention struct {
+	// MaxAge removes snapshots older than this.
+	MaxAge time.Duration
+	// MaxBytes removes the oldest snapshots until the database fits. The
+	// latest snapshot is kept whatever its size.
+	MaxBytes int64
+}
+
+// Store keeps snapshots in a SQLite database. Several processes may use
+// it at once: the agent writing and history queries reading.
+type Store struct {
+	// Path is the database file.
+	Path string
+	// Now is the clock retention is measured against.
+	Now func() time.Time
+
+	db *sql.DB
+}
+
+// Open opens or creates the store in dir. Snapshots that earlier versions
+// of the agent kept as daily JSON files there are moved into the
+// database.
+func Open(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create agent store: %w", err)
+	}
+	path := filepath.Join(dir, FileName)
+	dsn := fmt.Sprintf("file:%s?_pragma=busy_timeout(%d)&_pragma=journal_mode(WAL)", filepath.ToSlash(path), busyTimeout.Milliseconds())
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open agent store %s: %w", path, err)
+	}
+	// One connection serializes this process's statements; SQLite's own
+	// locking handles other processes.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open agent store %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0o600); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open agent store %s: %w", path, err)
+	}
+
+	s := &Store{Path: path, Now: time.Now, db: db}
+	if err := s.importDayFiles(dir); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close releases the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Append adds snap with its samples.
+func (s *Store) Append(snap Snapshot) error {
+	return s.insert([]Snapshot{snap})
+}
+
+func (s *Store) insert(snaps []Snapshot) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	defer tx.Rollback()
+	for _, snap := range snaps {
+		data, err := json.Marshal(snap)
+		if err != nil {
+			return fmt.Errorf("encode snapshot: %w", err)
+		}
+		at := snap.Time.UnixMilli()
+		if _, err := tx.Exec(`INSERT INTO snapshots (time, host, data) VALUES (?, ?, ?)`, at, snap.Host, string(data)); err != nil {
+			return fmt.Errorf("write snapshot: %w", err)
+		}
+		for _, sample := range snap.Samples() {
+			if _, err := tx.Exec(`INSERT INTO samples (field, time, value) VALUES (?, ?, ?)`, sample.Field, at, sample.Value); err != nil {
+				return fmt.Errorf("write snapshot: %w", err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("write snapshot: %w", err)
+	}
+	return nil
+}
+
+// Prune removes the snapshots outside r and returns how many it removed.
+func (s *Store) Prune(r Retention) (int, error) {
+	removed := 0
+	if r.MaxAge > 0 {
+		n, err := s.deleteBefore(s.Now().Add(-r.MaxAge).UnixMilli())
+		if err != nil {
+			return 0, err
+		}
+		removed += n
+	}
+
+	if r.MaxBytes > 0 {
+		used, err := s.usedBytes()
+		if err != nil {
+			return removed, err
+		}
+		if used > r.MaxBytes {
+			// Rows are about the same size, so remove the share of the
+			// oldest snapshots the database is over by.
+			var count int
+			if err := s.db.QueryRow(`SELECT count(*) FROM snapshots`).Scan(&count); err != nil {
+				return removed, fmt.Errorf("prune agent store: %w", err)
+			}
+			drop := min(int(math.Ceil(float64(count)*float64(used-r.MaxBytes)/float64(used))), count-1)
+			if drop > 0 {
+				var cutoff int64
+				if err := s.db.QueryRow(`SELECT time FROM snapshots ORDER BY time LIMIT 1 OFFSET ?`, drop).Scan(&cutoff); err != nil {
+					return removed, fmt.Errorf("prune agent store: %w", err)
+				}
+				n, err := s.deleteBefore(cutoff)
+				if err != nil {
+					return removed, err
+				}
+				removed += n
+			}
+		}
+	}
+
+	if removed > 0 {
+		if _, err := s.db.Exec(`PRAGMA incremental_vacuum`); err != nil {
+			return removed, fmt.Errorf("prune agent store: %w", err)
+		}
+	}
+	return removed, nil
+}
+
+// deleteBefore removes the snapshots taken before at, in Unix
+// milliseconds.
+func (s *Store) deleteBefore(at int64) (int, error) {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("prune agent store: %w", err)
+	}
+	defer tx.Rollback()
+	res, err := tx.Exec(`DELETE FROM snapshots WHERE time < ?`, at)
+	if err != nil {
+		return 0, fmt.Errorf("prune agent store: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM samples WHERE time < ?`, at); err != nil {
+		return 0, fmt.Errorf("prune agent store: %w", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("prune agent store: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+// usedBytes is the size of the database's live pages.
+func (s *Store) usedBytes() (int64, error) {
+	var pages, free, size int64
+	err := s.db.QueryRow(`SELECT page_count, freelist_count, page_size FROM pragma_page_count, pragma_freelist_count, pragma_page_size`).Scan(&pages, &free, &size)
+	if err != nil {
+		return 0, fmt.Errorf("measure agent store: %w", err)
+	}
+	return (pages - free) * size, nil
+}
+
+// Read returns the snapshots taken from from to to, inclusive, oldest
+// first. A zero to means up to now.
+func (s *Store) Read(from, to time.Time) ([]Snapshot, error) {
+	query := `SELECT data FROM snapshots WHERE time >= ? ORDER BY time`
+	args := []any{from.UnixMilli()}
+	if !to.IsZero() {
+		query = `SELECT data FROM snapshots WHERE time >= ? AND time <= ? ORDER BY time`
+		args = append(args, to.UnixMilli())
+	}
+	rows, err := s.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("read agent store: %w", err)
+	}
+	defer rows.Close()
+
+	var snaps []Snapshot
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("read agent store: %w", err)
+		}
+		var snap Snapshot
+		if err := json.Unmarshal([]byte(data), &snap); err != nil {
+			return nil, fmt.Errorf("read agent store: %w", err)
+		}
+		snaps = append(snaps, snap)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read agent store: %w", err)
+	}
+	return snaps, nil
+}
+
+// Stats summarizes a store. Oldest and Latest are nil when it is empty.
+type Stats struct {
+	Path      string     `json:"path" yaml:"path"`
+	SizeBytes int64      `json:"size_bytes" yaml:"size_bytes"`
+	Snapshots int        `json:"snapshots" yaml:"snapshots"`
+	Oldest    *time.Time `json:"oldest,omitempty" yaml:"oldest,omitempty"`
+	Latest    *Snapshot  `json:"latest,omitempty" yaml:"latest,omitempty"`
+}
+
+// Stats reports the size of the database, its snapshot count, and the
+// time range the snapshots cover.
+func (s *Store) Stats() (Stats, error) {
+	stats := Stats{Path: s.Path}
+	for _, suffix := range []string{"", "-wal"} {
+		if info, err := os.Stat(s.Path + suffix); err == nil {
+			stats.SizeBytes += info.Size()
+		}
+	}
+	if err := s.db.QueryRow(`SELECT count(*) FROM snapshots`).Scan(&stats.Snapshots); err != nil {
+		return stats, fmt.Errorf("read agent store: %w", err)
+	}
+	if stats.Snapshots == 0 {
+		return stats, nil
+	}
+	var (
+		oldest int64
+		data   string
+	)
+	if err := s.db.QueryRow(`SELECT min(time) FROM snapshots`).Scan(&oldest); err != nil {
+		return stats, fmt.Errorf("read agent store: %w", err)
+	}
+	if err := s.db.QueryRow(`SELECT data FROM snapshots ORDER BY time DESC LIMIT 1`).Scan(&data); err != nil {
+		return stats, fmt.Errorf("read agent store: %w", err)
+	}
+	var latest Snapshot
+	if err := json.Unmarshal([]byte(data), &latest); err != nil {
+		return stats, fmt.Errorf("read agent store: %w", err)
+	}
+	t := time.UnixMilli(oldest).UTC()
+	stats.Oldest, stats.Latest = &t, &latest
+	return stats, nil
+}
+
+// Fields lists the sample fields in the store, sorted.
+func (s *Store) Fields() ([]string, error) {
+	rows, err := s.db.Query(`SELECT DISTINCT field FROM samples ORDER BY field`)
+	if err != nil {
+		return nil, fmt.Errorf("read agent store: %w", err)
+	}
+	defer rows.Close()
+	var fields []string
+	for rows.Next() {
+		var field string
+		if err := rows.Scan(&field); err != nil {
+			return nil, fmt.Errorf("read agent store: %w", err)
+		}
+		fields = append(fields, field)
+	}
+	return fields, rows.Err()
+}
+
+// Aggregates combine the samples in one step of a query.
+const (
+	AggregateAvg = "avg"
+	AggregateMin = "min"
+	AggregateMax = "max"
+)
+
+// Aggregates lists the valid aggregates.
+var Aggregates = []string{AggregateAvg, AggregateMin, AggregateMax}
+
+// Query selects samples over time.
+type Query struct {
+	// From and To bound the range, inclusive; a zero To means now.
+	From, To time.Time
+	// Fields are field names or patterns in which * matches any run of
+	// characters, such as storage[*].used_percent.
+	Fields []string
+	// Step downsamples to one point per step, combining the samples in it
+	// with Aggregate. Zero returns every sample.
+	Step      time.Duration
+	Aggregate string
+}
+
+// Point is one value of a series. Time is the start of its step.
+type Point struct {
+	Time  time.Time `json:"time" yaml:"time"`
+	Value float64   `json:"value" yaml:"value"`
+}
+
+// Series is the history of one field.
+type Series struct {
+	Field  string  `json:"field" yaml:"field"`
+	Points []Point `json:"points" yaml:"points"`
+}
+
+// Query returns a series for each field matching q.Fields, in the order
+// of the patterns and then by name. Fields without samples in the range
+// are left out; a pattern matching no field at all is an error.
+func (s *Store) Query(q Query) ([]Series, error) {
+	aggregate := q.Aggregate
+	if aggregate == "" {
+		aggregate = AggregateAvg
+	}
+	if !slices.Contains(Aggregates, aggregate) {
+		return nil, fmt.Errorf("unknown aggregate %q (expected %s)", aggregate, strings.Join(Aggregates, ", "))
+	}
+
+	known, err := s.Fields()
+	if err != nil {
+		return nil, err
+	}
+	var fields []string
+	for _, pattern := range q.Fields {
+		matched := false
+		for _, field := range known {
+			if MatchField(pattern, field) {
+				matched = true
+				if !slices.Contains(fields, field) {
+					fields = append(fields, field)
+				}
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no field matches %q", pattern)
+		}
+	}
+
+	to := q.To
+	if to.IsZero() {
+		to = s.Now()
+	}
+	from := q.From.UnixMilli()
+	step := max(q.Step.Milliseconds(), 1)
+	var series []Series
+	for _, field := range fields {
+		// Grouping by the step's index keeps one row per step; with a
+		// step of a millisecond that is every sample.
+		rows, err := s.db.Query(`SELECT (time - ?1) / ?2 AS bucket, `+aggregate+`(value) FROM samples
+			WHERE field = ?3 AND time >= ?1 AND time <= ?4 GROUP BY bucket ORDER BY bucket`, from, step, field, to.UnixMilli())
+		if err != nil {
+			return nil, fmt.Errorf("query agent store: %w", err)
+		}
+		out := Series{Field: field, Points: []Point{}}
+		for rows.Next() {
+			var (
+				bucket int64
+				value  float64
+			)
+			if err := rows.Scan(&bucket, &value); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("query agent store: %w", err)
+			}
+			out.Points = append(out.Points, Point{Time: time.UnixMilli(from + bucket*step).UTC(), Value: value})
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("query agent store: %w", err)
+		}
+		if len(out.Points) > 0 {
+			series = append(series, out)
+		}
+	}
+	return series, nil
+}
+
+// MatchField reports whether field matches pattern, in which * matches
+// any run of characters and everything else matches itself.
+func MatchField(pattern, field string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == field
+	}
+	if !strings.HasPrefix(field, parts[0]) {
+		return false
+	}
+	field = field[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(field, part)
+		if i < 0 {
+			return false
+		}
+		field = field[i+len(part):]
+	}
+	return strings.HasSuffix(field, parts[len(parts)-1])
+}
+
+// Day files of earlier versions: newline-delimited JSON snapshots, one
+// file per UTC day.
+const (
+	dayFilePrefix = "snapshots-"
+	dayFileSuffix = ".jsonl"
+)
+
+// importDayFiles moves the snapshots of day files in dir into the
+// database. Each file is removed once its snapshots are committed, so an
+// interrupted import resumes with the next file.
+func (s *Store) importDayFiles(dir string) error {
+	paths, err := filepath.Glob(filepath.Join(dir, dayFilePrefix+"*"+dayFileSuffix))
+	if err != nil || len(paths) == 0 {
+		return err
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		snaps, err := readDayFile(path)
+		if err != nil {
+			return err
+		}
+		if err := s.insert(snaps); err != nil {
+			return fmt.Errorf("import %s: %w", path, err)
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("import %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// readDayFile reads a day file. Lines that cannot be decoded, such as
+// one cut short by a crash, are skipped.
+func readDayFile(path string) ([]Snapshot, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("import %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var snaps []Snapshot
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for scanner.Scan() {
+		var snap Snapshot
+		if json.Unmarshal(scanner.Bytes(), &snap) == nil && !snap.Time.IsZero() {
+			snaps = append(snaps, snap)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("import %s: %w", path, err)
+	}
+	return snaps, nil
+}
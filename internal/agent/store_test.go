@@ -0,0 +1,257 @@
+package agent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anowarislam/ado/internal/meta"
+)
+
+func TestNewSnapshot(t *testing.T) {
+	at := time.Date(2026, 10, 16, 12, 0, 0, 0, time.FixedZone("EST", -5*3600))
+	snap := NewSnapshot("web-1", meta.Usage{
+		Time:   at,
+		CPU:    meta.CPUUsage{TotalPercent: 12.5, PerCore: []float64{10, 15}},
+		Memory: meta.MemoryInfo{TotalMB: 16000, UsedMB: 8000, AvailableMB: 8000, UsedPercent: 50, SwapUsedMB: 10},
+		Storage: []meta.StorageInfo{
+			{Mountpoint: "/", Device: "/dev/sda1", UsedPercent: 60, UsedMB: 600, FreeMB: 400},
+			{Mountpoint: "/mnt/nfs", Unresponsive: true},
+		},
+		GPU: []meta.GPUUsage{{Index: 0, Model: "A100", UtilizationPercent: 90, MemoryUsedMB: 1000}},
+	})
+
+	if snap.Time.Location() != time.UTC || !snap.Time.Equal(at) || snap.Host != "web-1" || snap.CPU != 12.5 {
+		t.Errorf("snapshot = %+v", snap)
+	}
+	if snap.Memory.UsedPercent != 50 || snap.Memory.SwapUsedMB != 10 {
+		t.Errorf("memory = %+v", snap.Memory)
+	}
+	if len(snap.Storage) != 1 || snap.Storage[0].FreeMB != 400 {
+		t.Errorf("storage = %+v", snap.Storage)
+	}
+	if len(snap.GPU) != 1 || snap.GPU[0].UtilizationPercent != 90 {
+		t.Errorf("gpu = %+v", snap.GPU)
+	}
+}
+
+// openStore opens a store in a temporary directory with its clock at now.
+func openStore(t *testing.T, now time.Time) *Store {
+	t.Helper()
+	store, err := Open(filepath.Join(t.TempDir(), "agent"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { store.Close() })
+	store.Now = func() time.Time { return now }
+	return store
+}
+
+func TestStore_AppendRead(t *testing.T) {
+	start := time.Date(2026, 10, 15, 23, 59, 0, 0, time.UTC)
+	store := openStore(t, start.Add(time.Hour))
+	for i := range 3 {
+		snap := Snapshot{Time: start.Add(time.Duration(i) * time.Minute), Host: "web-1", CPU: float64(i), Storage: []Volume{{Mountpoint: "/", FreeMB: 100}}}
+		if err := store.Append(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	snaps, err := store.Read(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(snaps) != 3 || snaps[2].CPU != 2 || snaps[2].Storage[0].FreeMB != 100 {
+		t.Errorf("Read() = %+v", snaps)
+	}
+	snaps, _ = store.Read(start.Add(time.Minute), start.Add(time.Minute))
+	if len(snaps) != 1 || snaps[0].CPU != 1 {
+		t.Errorf("Read(range) = %+v", snaps)
+	}
+
+	stats, err := store.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Snapshots != 3 || stats.SizeBytes == 0 || !stats.Oldest.Equal(start) || stats.Latest.CPU != 2 {
+		t.Errorf("Stats() = %+v", stats)
+	}
+	fields, _ := store.Fields()
+	if !slices.Contains(fields, "storage[/].free_mb") || !slices.Contains(fields, "cpu_percent") {
+		t.Errorf("Fields() = %v", fields)
+	}
+}
+
+func TestOpen_ImportsDayFiles(t *testing.T) {
+	dir := t.TempDir()
+	day := filepath.Join(dir, "snapshots-2026-10-15.jsonl")
+	// A line cut short by a crash is skipped.
+	appendFile(t, day, `{"time":"2026-10-15T10:00:00Z","cpu_percent":5}`+"\n"+`{"time":"2026-10-15T10:01:00Z","cpu_per`)
+
+	store, err := Open(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer store.Close()
+	snaps, _ := store.Read(time.Time{}, time.Time{})
+	if len(snaps) != 1 || snaps[0].CPU != 5 {
+		t.Errorf("imported %+v", snaps)
+	}
+	if _, err := os.Stat(day); !os.IsNotExist(err) {
+		t.Errorf("day file left behind: %v", err)
+	}
+}
+
+func TestStore_Prune(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		retention Retention
+		want      int // snapshots kept
+	}{
+		{"unbounded", Retention{}, 48},
+		{"max age", Retention{MaxAge: 12 * time.Hour}, 13},
+		{"max bytes", Retention{MaxBytes: 1}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := openStore(t, now)
+			for i := range 48 {
+				if err := store.Append(Snapshot{Time: now.Add(-time.Duration(i) * time.Hour), CPU: float64(i)}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			removed, err := store.Prune(tt.retention)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stats, _ := store.Stats()
+			if stats.Snapshots != tt.want || removed != 48-tt.want || stats.Latest.CPU != 0 {
+				t.Errorf("kept %d, removed %d, want %d kept", stats.Snapshots, removed, tt.want)
+			}
+			series, _ := store.Query(Query{From: now.Add(-48 * time.Hour), Fields: []string{"cpu_percent"}})
+			if len(series) != 1 || len(series[0].Points) != tt.want {
+				t.Errorf("samples left: %+v", series)
+			}
+		})
+	}
+}
+
+func TestStore_Query(t *testing.T) {
+	start := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	store := openStore(t, start.Add(time.Hour))
+	for i := range 6 {
+		snap := Snapshot{
+			Time:    start.Add(time.Duration(i) * 10 * time.Minute),
+			CPU:     float64(i * 10),
+			Storage: []Volume{{Mountpoint: "/", UsedPercent: 50}, {Mountpoint: "/data", UsedPercent: float64(i)}},
+		}
+		if err := store.Append(snap); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query Query
+		want  string
+	}{
+		{"every sample", Query{Fields: []string{"cpu_percent"}}, "cpu_percent: 00:00=0 00:10=10 00:20=20 00:30=30 00:40=40 00:50=50"},
+		{"avg per step", Query{Fields: []string{"cpu_percent"}, Step: 30 * time.Minute}, "cpu_percent: 00:00=10 00:30=40"},
+		{"max per step", Query{Fields: []string{"cpu_percent"}, Step: 30 * time.Minute, Aggregate: AggregateMax}, "cpu_percent: 00:00=20 00:30=50"},
+		{"range", Query{Fields: []string{"cpu_percent"}, From: start.Add(15 * time.Minute), To: start.Add(30 * time.Minute)}, "cpu_percent: 00:20=20 00:30=30"},
+		{"pattern", Query{Fields: []string{"storage[*].used_percent"}, Step: time.Hour, Aggregate: AggregateMin}, "storage[/].used_percent: 00:00=50; storage[/data].used_percent: 00:00=0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.query.From.IsZero() {
+				tt.query.From = start
+			}
+			series, err := store.Query(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, s := range series {
+				var points []string
+				for _, p := range s.Points {
+					points = append(points, fmt.Sprintf("%s=%g", p.Time.Format("15:04"), p.Value))
+				}
+				got = append(got, s.Field+": "+strings.Join(points, " "))
+			}
+			if strings.Join(got, "; ") != tt.want {
+				t.Errorf("Query() = %s, want %s", strings.Join(got, "; "), tt.want)
+			}
+		})
+	}
+
+	for _, q := range []Query{{Fields: []string{"load"}}, {Fields: []string{"cpu_percent"}, Aggregate: "median"}} {
+		if _, err := store.Query(q); err == nil {
+			t.Errorf("Query(%+v) succeeded", q)
+		}
+	}
+}
+
+func TestMatchField(t *testing.T) {
+	tests := []struct {
+		pattern, field string
+		want           bool
+	}{
+		{"cpu_percent", "cpu_percent", true},
+		{"cpu", "cpu_percent", false},
+		{"memory.*", "memory.used_mb", true},
+		{"storage[*].free_mb", "storage[/mnt/data].free_mb", true},
+		{"storage[*].free_mb", "storage[/].used_mb", false},
+		{"*percent", "gpu[0].utilization_percent", true},
+		{"*", "anything", true},
+	}
+	for _, tt := range tests {
+		if got := MatchField(tt.pattern, tt.field); got != tt.want {
+			t.Errorf("MatchField(%q, %q) = %v, want %v", tt.pattern, tt.field, got, tt.want)
+		}
+	}
+}
+
+func TestAgent_Run(t *testing.T) {
+	store := openStore(t, time.Now())
+	var calls int
+	a := &Agent{
+		Store:    store,
+		Interval: 10 * time.Millisecond,
+		Sample: func(ctx context.Context) Snapshot {
+			calls++
+			return Snapshot{Time: time.Now().UTC(), CPU: float64(calls)}
+		},
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	a.Run(ctx)
+
+	snaps, err := store.Read(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first sample only primes the CPU measurement.
+	if len(snaps) == 0 || len(snaps) != calls-1 || snaps[0].CPU != 2 {
+		t.Errorf("%d calls, snapshots = %+v", calls, snaps)
+	}
+}
+
+func appendFile(t *testing.T, path, content string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,110 @@
+// Package alias expands the user-defined command aliases from the
+// `aliases:` section of the config file, such as
+// `sys: "meta system --output json"`.
+package alias
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// reserved are command names cobra adds at execution time.
+var reserved = []string{"help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]*$`)
+
+// ValidateName reports whether name can be used as an alias: letters,
+// digits, '_' and '-', starting with a letter or digit.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid alias name %q: use letters, digits, '_' and '-', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// Shadows reports whether name is taken by a command of root, including
+// command aliases and the commands cobra adds itself. Built-in commands
+// always win over a user alias of the same name.
+func Shadows(root *cobra.Command, name string) bool {
+	if slices.Contains(reserved, name) {
+		return true
+	}
+	for _, cmd := range root.Commands() {
+		if cmd.Name() == name || cmd.HasAlias(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Parse splits an expansion into arguments and checks that it starts with
+// a command of root. Aliases do not expand other aliases.
+func Parse(root *cobra.Command, expansion string) ([]string, error) {
+	args, err := Split(expansion)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) == 0 {
+		return nil, errors.New("expansion is empty")
+	}
+	if !Shadows(root, args[0]) {
+		return nil, fmt.Errorf("expansion must start with an ado command, not %q", args[0])
+	}
+	return args, nil
+}
+
+// Split breaks s into words like a POSIX shell, without expanding
+// anything: words are separated by unquoted whitespace, single quotes keep
+// their contents literally, and a backslash escapes the next character
+// outside quotes and '"', '\' and '$' inside double quotes.
+func Split(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, r := range s {
+		switch {
+		case escaped:
+			if quote == '"' && !strings.ContainsRune(`"\$`+"`", r) {
+				word.WriteRune('\\')
+			}
+			word.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped, inWord = true, true
+		case quote != 0 && r == quote:
+			quote = 0
+		case quote != 0:
+			word.WriteRune(r)
+		case r == '\'' || r == '"':
+			quote, inWord = r, true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	switch {
+	case escaped:
+		return nil, errors.New("trailing backslash")
+	case quote != 0:
+		return nil, fmt.Errorf("unterminated %c quote", quote)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,89 @@
+package alias
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", nil},
+		{"  meta   system\t-o json ", []string{"meta", "system", "-o", "json"}},
+		{`echo 'a  b' "c d"`, []string{"echo", "a  b", "c d"}},
+		{`echo it\'s`, []string{"echo", "it's"}},
+		{`echo "say \"hi\" \$HOME \n"`, []string{"echo", `say "hi" $HOME \n`}},
+		{`echo '\$x' ""`, []string{"echo", `\$x`, ""}},
+		{`echo a"b c"d`, []string{"echo", "ab cd"}},
+	}
+	for _, tt := range tests {
+		got, err := Split(tt.in)
+		if err != nil {
+			t.Errorf("Split(%q) error = %v", tt.in, err)
+			continue
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("Split(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+
+	for _, in := range []string{`echo "open`, `echo 'open`, `echo \`} {
+		if _, err := Split(in); err == nil {
+			t.Errorf("Split(%q) = nil error, want one", in)
+		}
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"sys", "deploy-prod", "k8s_ctx", "2fa"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "-x", "a b", "a/b", "é"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) = nil, want error", name)
+		}
+	}
+}
+
+func testRoot() *cobra.Command {
+	root := &cobra.Command{Use: "ado"}
+	root.AddCommand(&cobra.Command{Use: "meta"}, &cobra.Command{Use: "wait-for", Aliases: []string{"wait"}})
+	return root
+}
+
+func TestShadows(t *testing.T) {
+	root := testRoot()
+	for name, want := range map[string]bool{
+		"meta": true, "wait": true, "help": true, "completion": true, "__complete": true,
+		"sys": false, "met": false,
+	} {
+		if got := Shadows(root, name); got != want {
+			t.Errorf("Shadows(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	root := testRoot()
+	got, err := Parse(root, "meta system --output json")
+	if err != nil || !slices.Equal(got, []string{"meta", "system", "--output", "json"}) {
+		t.Errorf("Parse() = %q, %v", got, err)
+	}
+	for in, want := range map[string]string{
+		"":              "expansion is empty",
+		"sys --x":       `must start with an ado command, not "sys"`,
+		`meta "system`:  "unterminated",
+		"--output json": "must start with an ado command",
+	} {
+		if _, err := Parse(root, in); err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("Parse(%q) error = %v, want %q", in, err, want)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+// Package archive creates and extracts tar.gz and zip archives with glob
+// filtering and optionally reproducible output.
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anowarislam/ado/internal/watch"
+)
+
+// Supported formats.
+const (
+	FormatTarGz = "tar.gz"
+	FormatZip   = "zip"
+)
+
+// Formats lists the supported formats.
+var Formats = []string{FormatTarGz, FormatZip}
+
+// Entry types.
+const (
+	TypeFile    = "file"
+	TypeDir     = "dir"
+	TypeSymlink = "symlink"
+)
+
+// ReproducibleTime is the modification time of every entry in a
+// reproducible archive. It is the earliest time zip can represent, so both
+// formats use it.
+var ReproducibleTime = time.Date(1980, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Entry describes one archive member.
+type Entry struct {
+	Name      string `json:"name" yaml:"name"`
+	Type      string `json:"type" yaml:"type"`
+	SizeBytes int64  `json:"size_bytes" yaml:"size_bytes"`
+}
+
+// DetectFormat returns the format implied by name's extension.
+func DetectFormat(name string) (string, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return FormatTarGz, nil
+	case strings.HasSuffix(lower, ".zip"):
+		return FormatZip, nil
+	}
+	return "", fmt.Errorf("cannot tell the format of %q from its extension; use --format (%s)", name, strings.Join(Formats, ", "))
+}
+
+// CheckFormat validates an explicitly requested format.
+func CheckFormat(format string) error {
+	switch format {
+	case FormatTarGz, FormatZip:
+		return nil
+	}
+	return fmt.Errorf("unsupported format %q (supported: %s)", format, strings.Join(Formats, ", "))
+}
+
+// CreateOptions controls Create.
+type CreateOptions struct {
+	Format string
+	// Include limits files to these globs; empty includes everything.
+	Include []string
+	// Exclude drops matching files and directories.
+	Exclude []string
+	// Skip lists files never archived even when selected, such as the
+	// archive being written.
+	Skip []string
+	// Reproducible fixes timestamps to ReproducibleTime, normalizes
+	// permissions to 0644/0755, and drops owner information, so the same
+	// tree always yields the same bytes.
+	Reproducible bool
+}
+
+// member is a file system object queued for archiving.
+type member struct {
+	name string // slash-separated archive name, without a trailing slash
+	path string
+	info fs.FileInfo
+	link string
+}
+
+// Create writes an archive of sources to w and returns its entries in
+// archive order. Relative sources are resolved against dir and keep their
+// relative path as archive name; absolute sources lose the leading slash.
+// Entries are always sorted by name.
+func Create(w io.Writer, dir string, sources []string, opts CreateOptions) ([]Entry, error) {
+	if err := CheckFormat(opts.Format); err != nil {
+		return nil, err
+	}
+	m := watch.Matcher{Include: opts.Include, Exclude: opts.Exclude, NoDefaultExcludes: true}
+	members, err := collect(dir, sources, m, opts.Skip)
+	if err != nil {
+		return nil, err
+	}
+
+	if opts.Format == FormatZip {
+		return writeZip(w, members, opts.Reproducible)
+	}
+	return writeTarGz(w, members, opts.Reproducible)
+}
+
+func collect(dir string, sources []string, m watch.Matcher, skip []string) ([]member, error) {
+	seen := map[string]bool{}
+	var members []member
+
+	var skipped []fs.FileInfo
+	for _, p := range skip {
+		if info, err := os.Stat(p); err == nil {
+			skipped = append(skipped, info)
+		}
+	}
+
+	for _, source := range sources {
+		root := source
+		if !filepath.IsAbs(source) {
+			root = filepath.Join(dir, source)
+		}
+		base := strings.TrimLeft(filepath.ToSlash(filepath.Clean(source)), "/")
+		if base == ".." || strings.HasPrefix(base, "../") {
+			return nil, fmt.Errorf("source %q is outside %s; use -C to change the base directory", source, dir)
+		}
+
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(root, p)
+			if err != nil {
+				return err
+			}
+			name := path.Join(base, filepath.ToSlash(rel))
+			if name == "." || name == "" {
+				return nil
+			}
+			if m.Excluded(name) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			// Directories are recorded only when nothing is included
+			// explicitly; otherwise their files imply them.
+			if d.IsDir() && len(m.Include) > 0 {
+				return nil
+			}
+			if !d.IsDir() && !m.Match(name) {
+				return nil
+			}
+			if seen[name] {
+				return nil
+			}
+			seen[name] = true
+
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			for _, s := range skipped {
+				if os.SameFile(info, s) {
+					return nil
+				}
+			}
+			mem := member{name: name, path: p, info: info}
+			if info.Mode()&fs.ModeSymlink != 0 {
+				if mem.link, err = os.Readlink(p); err != nil {
+					return err
+				}
+			} else if !info.Mode().IsRegular() && !info.IsDir() {
+				return nil
+			}
+			members = append(members, mem)
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", source, err)
+		}
+	}
+
+	sort.Slice(members, func(i, j int) bool { return members[i].name < members[j].name })
+	return members, nil
+}
+
+// entryType classifies a member.
+func entryType(info fs.FileInfo) string {
+	switch {
+	case info.IsDir():
+		return TypeDir
+	case info.Mode()&fs.ModeSymlink != 0:
+		return TypeSymlink
+	}
+	return TypeFile
+}
+
+// normalizedMode is the permission recorded for info in a reproducible archive.
+func normalizedMode(info fs.FileInfo) fs.FileMode {
+	switch {
+	case info.IsDir():
+		return 0o755
+	case info.Mode()&fs.ModeSymlink != 0:
+		return 0o777
+	case info.Mode()&0o111 != 0:
+		return 0o755
+	}
+	return 0o644
+}
+
+func writeTarGz(w io.Writer, members []member, reproducible bool) ([]Entry, error) {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+	entries := make([]Entry, 0, len(members))
+
+	for _, mem := range members {
+		hdr, err := tar.FileInfoHeader(mem.info, mem.link)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", mem.name, err)
+		}
+		hdr.Name = mem.name
+		if mem.info.IsDir() {
+			hdr.Name += "/"
+		}
+		hdr.AccessTime, hdr.ChangeTime = time.Time{}, time.Time{}
+		if reproducible {
+			hdr.ModTime = ReproducibleTime
+			hdr.Mode = int64(normalizedMode(mem.info))
+			hdr.Uid, hdr.Gid, hdr.Uname, hdr.Gname = 0, 0, "", ""
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, fmt.Errorf("%s: %w", mem.name, err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if err := copyFile(tw, mem.path); err != nil {
+				return nil, fmt.Errorf("%s: %w", mem.name, err)
+			}
+		}
+		entries = append(entries, Entry{Name: mem.name, Type: entryType(mem.info), SizeBytes: hdr.Size})
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return entries, gz.Close()
+}
+
+func writeZip(w io.Writer, members []member, reproducible bool) ([]Entry, error) {
+	zw := zip.NewWriter(w)
+	entries := make([]Entry, 0, len(members))
+
+	for _, mem := range members {
+		fh, err := zip.FileInfoHeader(mem.info)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", mem.name, err)
+		}
+		fh.Name = mem.name
+		fh.Method = zip.Deflate
+		if mem.info.IsDir() {
+			fh.Name += "/"
+			fh.Method = zip.Store
+		}
+		if reproducible {
+			fh.Modified = ReproducibleTime
+			fh.SetMode(mem.info.Mode()&^fs.ModePerm | normalizedMode(mem.info))
+		}
+		fw, err := zw.CreateHeader(fh)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", mem.name, err)
+		}
+
+		var size int64
+		switch entryType(mem.info) {
+		case TypeSymlink:
+			if _, err := io.WriteString(fw, mem.link); err != nil {
+				return nil, fmt.Errorf("%s: %w", mem.name, err)
+			}
+		case TypeFile:
+			if err := copyFile(fw, mem.path); err != nil {
+				return nil, fmt.Errorf("%s: %w", mem.name, err)
+			}
+			size = mem.info.Size()
+		}
+		entries = append(entries, Entry{Name: mem.name, Type: entryType(mem.info), SizeBytes: size})
+	}
+
+	return entries, zw.Close()
+}
+
+func copyFile(w io.Writer, p string) error {
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
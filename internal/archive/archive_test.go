@@ -0,0 +1,355 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeTree creates files (name -> content) below dir.
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func entryNames(entries []Entry) []string {
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name
+	}
+	return names
+}
+
+func create(t *testing.T, dir string, sources []string, opts CreateOptions) ([]byte, []Entry) {
+	t.Helper()
+	var buf bytes.Buffer
+	entries, err := Create(&buf, dir, sources, opts)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return buf.Bytes(), entries
+}
+
+func TestDetectFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"out.tar.gz", FormatTarGz, false},
+		{"OUT.TGZ", FormatTarGz, false},
+		{"dist/out.zip", FormatZip, false},
+		{"out.tar", "", true},
+	}
+	for _, tt := range tests {
+		got, err := DetectFormat(tt.name)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("DetectFormat(%q) = %q, %v, want %q", tt.name, got, err, tt.want)
+		}
+	}
+}
+
+func TestCreateExtract_RoundTrip(t *testing.T) {
+	for _, format := range Formats {
+		t.Run(format, func(t *testing.T) {
+			src := t.TempDir()
+			writeTree(t, src, map[string]string{
+				"app/main.go":       "package main\n",
+				"app/sub/data.txt":  "data",
+				"app/run.sh":        "#!/bin/sh\n",
+				"app/.hidden/x.txt": "x",
+			})
+			if err := os.Chmod(filepath.Join(src, "app/run.sh"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.Symlink("main.go", filepath.Join(src, "app/link")); err != nil {
+				t.Fatal(err)
+			}
+
+			data, entries := create(t, src, []string{"app"}, CreateOptions{Format: format})
+			wantNames := []string{"app", "app/.hidden", "app/.hidden/x.txt", "app/link", "app/main.go", "app/run.sh", "app/sub", "app/sub/data.txt"}
+			if got := entryNames(entries); !slices.Equal(got, wantNames) {
+				t.Fatalf("entries = %q, want %q", got, wantNames)
+			}
+
+			archivePath := filepath.Join(t.TempDir(), "out."+format)
+			if err := os.WriteFile(archivePath, data, 0o644); err != nil {
+				t.Fatal(err)
+			}
+			dest := t.TempDir()
+			extracted, err := Extract(archivePath, dest, ExtractOptions{Format: format})
+			if err != nil {
+				t.Fatalf("Extract() error = %v", err)
+			}
+			if got := entryNames(extracted); !slices.Equal(got, wantNames) {
+				t.Errorf("extracted = %q, want %q", got, wantNames)
+			}
+
+			got, err := os.ReadFile(filepath.Join(dest, "app/sub/data.txt"))
+			if err != nil || string(got) != "data" {
+				t.Errorf("data.txt = %q, %v", got, err)
+			}
+			if link, err := os.Readlink(filepath.Join(dest, "app/link")); err != nil || link != "main.go" {
+				t.Errorf("link = %q, %v", link, err)
+			}
+			if info, err := os.Stat(filepath.Join(dest, "app/run.sh")); err != nil || info.Mode().Perm()&0o100 == 0 {
+				t.Errorf("run.sh lost its executable bit: %v, %v", info, err)
+			}
+		})
+	}
+}
+
+func TestCreate_Reproducible(t *testing.T) {
+	for _, format := range Formats {
+		t.Run(format, func(t *testing.T) {
+			src := t.TempDir()
+			writeTree(t, src, map[string]string{"b.txt": "b", "a/c.txt": "c"})
+			opts := CreateOptions{Format: format, Reproducible: true}
+
+			first, _ := create(t, src, []string{"."}, opts)
+			later := time.Now().Add(time.Hour)
+			for _, name := range []string{"b.txt", "a/c.txt", "a"} {
+				if err := os.Chtimes(filepath.Join(src, name), later, later); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := os.Chmod(filepath.Join(src, "b.txt"), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			second, _ := create(t, src, []string{"."}, opts)
+
+			if !bytes.Equal(first, second) {
+				t.Error("reproducible archives differ after changing mtimes and permissions")
+			}
+		})
+	}
+}
+
+func TestCreate_ReproducibleHeaders(t *testing.T) {
+	src := t.TempDir()
+	writeTree(t, src, map[string]string{"f.txt": "f"})
+
+	data, _ := create(t, src, []string{"f.txt"}, CreateOptions{Format: FormatTarGz, Reproducible: true})
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hdr, err := tar.NewReader(gz).Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !hdr.ModTime.Equal(ReproducibleTime) || hdr.Mode != 0o644 || hdr.Uid != 0 || hdr.Uname != "" {
+		t.Errorf("header = %+v", hdr)
+	}
+
+	data, _ = create(t, src, []string{"f.txt"}, CreateOptions{Format: FormatZip, Reproducible: true})
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f := zr.File[0]; !f.Modified.Equal(ReproducibleTime) || f.Mode().Perm() != 0o644 {
+		t.Errorf("zip header modified=%v mode=%v", f.Modified, f.Mode())
+	}
+}
+
+func TestCreate_Filters(t *testing.T) {
+	src := t.TempDir()
+	writeTree(t, src, map[string]string{
+		"main.go":           "",
+		"main_test.go":      "",
+		"README.md":         "",
+		"internal/x/x.go":   "",
+		"vendor/dep/dep.go": "",
+		".git/HEAD":         "",
+	})
+
+	tests := []struct {
+		name string
+		opts CreateOptions
+		want []string
+	}{
+		{
+			name: "everything",
+			opts: CreateOptions{},
+			want: []string{".git", ".git/HEAD", "README.md", "internal", "internal/x", "internal/x/x.go", "main.go", "main_test.go", "vendor", "vendor/dep", "vendor/dep/dep.go"},
+		},
+		{
+			name: "include",
+			opts: CreateOptions{Include: []string{"**/*.go"}, Exclude: []string{"**/*_test.go", "vendor"}},
+			want: []string{"internal/x/x.go", "main.go"},
+		},
+		{
+			name: "exclude directory",
+			opts: CreateOptions{Exclude: []string{".git", "vendor", "internal"}},
+			want: []string{"README.md", "main.go", "main_test.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Format = FormatTarGz
+			_, entries := create(t, src, []string{"."}, tt.opts)
+			if got := entryNames(entries); !slices.Equal(got, tt.want) {
+				t.Errorf("entries = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCreate_SkipAndSources(t *testing.T) {
+	src := t.TempDir()
+	writeTree(t, src, map[string]string{"a.txt": "a", "out.tar.gz": "old"})
+
+	_, entries := create(t, src, []string{"."}, CreateOptions{
+		Format: FormatTarGz,
+		Skip:   []string{filepath.Join(src, "out.tar.gz")},
+	})
+	if got := entryNames(entries); !slices.Equal(got, []string{"a.txt"}) {
+		t.Errorf("entries = %q, want only a.txt", got)
+	}
+
+	if _, err := Create(&bytes.Buffer{}, src, []string{"../x"}, CreateOptions{Format: FormatTarGz}); err == nil || !strings.Contains(err.Error(), "outside") {
+		t.Errorf("Create(../x) error = %v", err)
+	}
+	if _, err := Create(&bytes.Buffer{}, src, []string{"missing"}, CreateOptions{Format: FormatTarGz}); err == nil {
+		t.Error("Create(missing) succeeded")
+	}
+	if _, err := Create(&bytes.Buffer{}, src, []string{"."}, CreateOptions{Format: "rar"}); err == nil || !strings.Contains(err.Error(), `unsupported format "rar"`) {
+		t.Errorf("Create(rar) error = %v", err)
+	}
+}
+
+func TestExtract_Filters(t *testing.T) {
+	src := t.TempDir()
+	writeTree(t, src, map[string]string{"keep/a.txt": "a", "keep/b.log": "b", "drop/c.txt": "c"})
+	data, _ := create(t, src, []string{"."}, CreateOptions{Format: FormatZip})
+	archivePath := filepath.Join(t.TempDir(), "in.zip")
+	if err := os.WriteFile(archivePath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := t.TempDir()
+	entries, err := Extract(archivePath, dest, ExtractOptions{Format: FormatZip, Include: []string{"**/*.txt"}, Exclude: []string{"drop"}})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if got := entryNames(entries); !slices.Equal(got, []string{"keep/a.txt"}) {
+		t.Errorf("extracted = %q, want only keep/a.txt", got)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "drop")); !os.IsNotExist(err) {
+		t.Errorf("excluded directory was created: %v", err)
+	}
+}
+
+func TestExtract_DryRun(t *testing.T) {
+	src := t.TempDir()
+	writeTree(t, src, map[string]string{"dir/a.txt": "abc"})
+	data, _ := create(t, src, []string{"."}, CreateOptions{Format: FormatTarGz})
+	archivePath := filepath.Join(t.TempDir(), "in.tar.gz")
+	if err := os.WriteFile(archivePath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "out")
+	entries, err := Extract(archivePath, dest, ExtractOptions{Format: FormatTarGz, DryRun: true})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if got := entryNames(entries); !slices.Equal(got, []string{"dir", "dir/a.txt"}) || entries[1].SizeBytes != 3 {
+		t.Errorf("entries = %+v", entries)
+	}
+	if _, err := os.Stat(dest); !os.IsNotExist(err) {
+		t.Errorf("dry run created %s: %v", dest, err)
+	}
+}
+
+func TestExtract_ChainedSymlinksStayInside(t *testing.T) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, hdr := range []tar.Header{
+		{Name: "deep/", Typeflag: tar.TypeDir, Mode: 0o755},
+		{Name: "deep/a", Typeflag: tar.TypeSymlink, Linkname: ".."},
+		{Name: "deep/a/b", Typeflag: tar.TypeSymlink, Linkname: ".."},
+		{Name: "deep/a/b/escaped.txt", Typeflag: tar.TypeReg, Mode: 0o644, Size: 4},
+	} {
+		if err := tw.WriteHeader(&hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Size > 0 {
+			if _, err := tw.Write([]byte("evil")); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	tw.Close()
+	gz.Close()
+
+	archivePath := filepath.Join(t.TempDir(), "evil.tar.gz")
+	if err := os.WriteFile(archivePath, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	parent := t.TempDir()
+	dest := filepath.Join(parent, "dest")
+	if _, err := Extract(archivePath, dest, ExtractOptions{Format: FormatTarGz}); err == nil || !strings.Contains(err.Error(), "deep/a/b/escaped.txt") {
+		t.Errorf("Extract() error = %v, want an error for deep/a/b/escaped.txt", err)
+	}
+	_ = filepath.WalkDir(parent, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && d.Name() == "escaped.txt" {
+			t.Errorf("escaped.txt written to %s", path)
+		}
+		return nil
+	})
+}
+
+func TestExtract_RejectsUnsafeEntries(t *testing.T) {
+	tests := []struct {
+		name string
+		hdr  tar.Header
+		want string
+	}{
+		{"parent path", tar.Header{Name: "../evil.txt", Typeflag: tar.TypeReg, Mode: 0o644}, `unsafe path "../evil.txt"`},
+		{"absolute path", tar.Header{Name: "/etc/evil", Typeflag: tar.TypeReg, Mode: 0o644}, `unsafe path "/etc/evil"`},
+		{"absolute symlink", tar.Header{Name: "link", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}, "points outside"},
+		{"escaping symlink", tar.Header{Name: "a/link", Typeflag: tar.TypeSymlink, Linkname: "../../x"}, "points outside"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			gz := gzip.NewWriter(&buf)
+			tw := tar.NewWriter(gz)
+			if err := tw.WriteHeader(&tt.hdr); err != nil {
+				t.Fatal(err)
+			}
+			tw.Close()
+			gz.Close()
+
+			archivePath := filepath.Join(t.TempDir(), "evil.tar.gz")
+			if err := os.WriteFile(archivePath, buf.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			dest := filepath.Join(t.TempDir(), "dest")
+			if _, err := Extract(archivePath, dest, ExtractOptions{Format: FormatTarGz}); err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Extract() error = %v, want %q", err, tt.want)
+			}
+			if entries, _ := os.ReadDir(dest); len(entries) != 0 {
+				t.Errorf("dest is not empty: %v", entries)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package archive
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anowarislam/ado/internal/watch"
+)
+
+// ExtractOptions controls Extract.
+type ExtractOptions struct {
+	Format string
+	// Include limits extraction to matching files; empty extracts everything.
+	Include []string
+	// Exclude skips matching files and everything below matching directories.
+	Exclude []string
+	// DryRun checks and lists the entries without writing anything.
+	DryRun bool
+}
+
+// Extract unpacks the archive at src into dest and returns the extracted
+// entries. Entries that would land outside dest, including symlinks
+// pointing outside it, are rejected before anything is written for them.
+// Every write goes through an os.Root on dest, so an entry below a
+// symlink made by an earlier entry cannot escape it either. Hard links,
+// devices, and other special entries are skipped.
+func Extract(src, dest string, opts ExtractOptions) ([]Entry, error) {
+	if err := CheckFormat(opts.Format); err != nil {
+		return nil, err
+	}
+	x := &extractor{
+		matcher: watch.Matcher{Include: opts.Include, Exclude: opts.Exclude, NoDefaultExcludes: true},
+		dryRun:  opts.DryRun,
+	}
+	if _, err := os.Stat(src); err != nil {
+		return nil, err
+	}
+	if !opts.DryRun {
+		if err := os.MkdirAll(dest, 0o755); err != nil {
+			return nil, fmt.Errorf("create %s: %w", dest, err)
+		}
+		root, err := os.OpenRoot(dest)
+		if err != nil {
+			return nil, err
+		}
+		defer root.Close()
+		x.root = root
+	}
+
+	var err error
+	if opts.Format == FormatZip {
+		err = x.zip(src)
+	} else {
+		err = x.tarGz(src)
+	}
+	return x.entries, err
+}
+
+type extractor struct {
+	// root is the destination; nil in a dry run.
+	root    *os.Root
+	matcher watch.Matcher
+	dryRun  bool
+	entries []Entry
+}
+
+func (x *extractor) tarGz(src string) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", src, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", src, err)
+		}
+
+		var typ string
+		switch hdr.Typeflag {
+		case tar.TypeReg:
+			typ = TypeFile
+		case tar.TypeDir:
+			typ = TypeDir
+		case tar.TypeSymlink:
+			typ = TypeSymlink
+		default:
+			slog.Debug("Skipping unsupported archive entry", "name", hdr.Name, "type", string(hdr.Typeflag))
+			continue
+		}
+		if err := x.write(hdr.Name, typ, fs.FileMode(hdr.Mode).Perm(), hdr.ModTime, hdr.Linkname, tr); err != nil {
+			return err
+		}
+	}
+}
+
+func (x *extractor) zip(src string) error {
+	zr, err := zip.OpenReader(src)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", src, err)
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		mode := f.Mode()
+		typ := TypeFile
+		switch {
+		case mode.IsDir() || strings.HasSuffix(f.Name, "/"):
+			typ = TypeDir
+		case mode&fs.ModeSymlink != 0:
+			typ = TypeSymlink
+		case !mode.IsRegular():
+			slog.Debug("Skipping unsupported archive entry", "name", f.Name, "mode", mode)
+			continue
+		}
+
+		if err := x.zipEntry(f, typ); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (x *extractor) zipEntry(f *zip.File, typ string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	defer rc.Close()
+
+	var link string
+	if typ == TypeSymlink {
+		target, err := io.ReadAll(io.LimitReader(rc, 4096))
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+		link = string(target)
+	}
+	perm := f.Mode().Perm()
+	if perm == 0 {
+		perm = 0o644 // archives from tools that record no permissions
+	}
+	return x.write(f.Name, typ, perm, f.Modified, link, rc)
+}
+
+// write creates one entry below dest after checking that it stays there.
+func (x *extractor) write(name, typ string, perm fs.FileMode, modTime time.Time, link string, r io.Reader) error {
+	name = strings.TrimSuffix(name, "/")
+	if !filepath.IsLocal(filepath.FromSlash(name)) {
+		return fmt.Errorf("unsafe path %q in archive", name)
+	}
+	if typ == TypeSymlink {
+		resolved := path.Join(path.Dir(name), filepath.ToSlash(link))
+		if path.IsAbs(link) || !filepath.IsLocal(filepath.FromSlash(resolved)) {
+			return fmt.Errorf("symlink %q points outside the destination (%s)", name, link)
+		}
+	}
+
+	if x.matcher.Excluded(name) || (typ != TypeDir && !x.matcher.Match(name)) {
+		return nil
+	}
+	if typ == TypeDir && len(x.matcher.Include) > 0 {
+		return nil
+	}
+	if x.dryRun {
+		n, err := io.Copy(io.Discard, r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+		x.entries = append(x.entries, Entry{Name: name, Type: typ, SizeBytes: n})
+		return nil
+	}
+
+	if err := x.create(name, typ, perm, modTime, link, r); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// create writes the entry name through x.root, which refuses paths that
+// resolve outside the destination.
+func (x *extractor) create(name, typ string, perm fs.FileMode, modTime time.Time, link string, r io.Reader) error {
+	target := filepath.FromSlash(name)
+	if typ == TypeDir {
+		if err := x.root.MkdirAll(target, 0o755); err != nil {
+			return err
+		}
+		x.entries = append(x.entries, Entry{Name: name, Type: typ})
+		return nil
+	}
+
+	if err := x.root.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return err
+	}
+	// Replace whatever is there rather than writing through an existing
+	// symlink.
+	if info, err := x.root.Lstat(target); err == nil && !info.IsDir() {
+		if err := x.root.Remove(target); err != nil {
+			return err
+		}
+	}
+
+	if typ == TypeSymlink {
+		if err := x.root.Symlink(link, target); err != nil {
+			return err
+		}
+		x.entries = append(x.entries, Entry{Name: name, Type: typ})
+		return nil
+	}
+
+	f, err := x.root.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, perm)
+	if err != nil {
+		return err
+	}
+	n, err := io.Copy(f, r)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if !modTime.IsZero() {
+		_ = x.root.Chtimes(target, modTime, modTime)
+	}
+	x.entries = append(x.entries, Entry{Name: name, Type: typ, SizeBytes: n})
+	return nil
+}
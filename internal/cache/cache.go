@@ -0,0 +1,209 @@
+// Package cache reports and cleans what ado keeps in its cache directory:
+// files it can fetch or compute again, grouped in categories so they can
+// be removed selectively.
+package cache
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anowarislam/ado/internal/config"
+)
+
+// Category names.
+const (
+	CategoryUpdateCheck = "update-check"
+	// CategoryOther holds whatever no other category claims, such as
+	// files of older versions of ado.
+	CategoryOther = "other"
+)
+
+// Category is a kind of cached data.
+type Category struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	// match reports whether an entry directly in the cache directory
+	// belongs to the category.
+	match func(name string) bool
+}
+
+// Categories lists every category, CategoryOther last.
+var Categories = []Category{
+	{
+		Name:        CategoryUpdateCheck,
+		Description: "latest release found by the update check; checked again when missing",
+		match:       func(name string) bool { return name == "update-check.json" },
+	},
+	{
+		Name:        CategoryOther,
+		Description: "files no current command uses",
+		match:       func(string) bool { return true },
+	},
+}
+
+// CategoryNames returns the names of Categories.
+func CategoryNames() []string {
+	names := make([]string, len(Categories))
+	for i, c := range Categories {
+		names[i] = c.Name
+	}
+	return names
+}
+
+// ValidateCategories reports an error for names that are not categories.
+func ValidateCategories(names []string) error {
+	valid := CategoryNames()
+	for _, name := range names {
+		if !slices.Contains(valid, name) {
+			return fmt.Errorf("unknown cache category %q (valid: %s)", name, strings.Join(valid, ", "))
+		}
+	}
+	return nil
+}
+
+// Entry is a file or directory directly in the cache directory.
+type Entry struct {
+	Category string `json:"category" yaml:"category"`
+	Path     string `json:"path" yaml:"path"`
+	// SizeBytes is the size of a file, or of the files below a directory.
+	SizeBytes int64 `json:"size_bytes" yaml:"size_bytes"`
+	// Modified is when the file, or the newest file below a directory,
+	// was last written.
+	Modified time.Time `json:"modified" yaml:"modified"`
+}
+
+// Cache is ado's cache directory.
+type Cache struct {
+	Dir string
+	// Exclude lists directories below Dir that are not cache, such as the
+	// state directory on Windows, which lives inside it.
+	Exclude []string
+}
+
+// Default returns ado's cache directory, excluding the other directories
+// of ado that live inside it.
+func Default() (Cache, error) {
+	dirs := config.ResolveDirs()
+	if dirs.Cache == "" {
+		return Cache{}, errors.New("cache directory: neither $XDG_CACHE_HOME nor $HOME is defined")
+	}
+	return Cache{Dir: dirs.Cache, Exclude: []string{dirs.State, dirs.Data, dirs.Log, dirs.Plugin}}, nil
+}
+
+// Entries returns the entries of the cache by path. A missing cache
+// directory has none.
+func (c Cache) Entries() ([]Entry, error) {
+	items, err := os.ReadDir(c.Dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for _, item := range items {
+		path := filepath.Join(c.Dir, item.Name())
+		if c.excluded(path) {
+			continue
+		}
+		entry := Entry{Category: categorize(item.Name()), Path: path}
+		entry.SizeBytes, entry.Modified = usage(path)
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Select returns the entries of entries in one of categories (any when
+// empty) last written before cutoff (any when zero).
+func Select(entries []Entry, categories []string, cutoff time.Time) []Entry {
+	var selected []Entry
+	for _, e := range entries {
+		if len(categories) > 0 && !slices.Contains(categories, e.Category) {
+			continue
+		}
+		if !cutoff.IsZero() && !e.Modified.Before(cutoff) {
+			continue
+		}
+		selected = append(selected, e)
+	}
+	return selected
+}
+
+// Remove deletes entries and returns the ones it removed.
+func (c Cache) Remove(entries []Entry) ([]Entry, error) {
+	var (
+		removed []Entry
+		errs    []error
+	)
+	for _, e := range entries {
+		if err := os.RemoveAll(e.Path); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		removed = append(removed, e)
+	}
+	return removed, errors.Join(errs...)
+}
+
+func categorize(name string) string {
+	for _, c := range Categories {
+		if c.match(name) {
+			return c.Name
+		}
+	}
+	return CategoryOther
+}
+
+// excluded reports whether path is, or holds, an excluded directory.
+// Removing a directory that holds one would remove it too, so such
+// directories are left out whole.
+func (c Cache) excluded(path string) bool {
+	for _, dir := range c.Exclude {
+		if dir == "" {
+			continue
+		}
+		if rel, err := filepath.Rel(path, dir); err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	return false
+}
+
+// usage returns the total size of the regular files at or below path and
+// when the newest of them was written. Unreadable files are skipped.
+func usage(path string) (int64, time.Time) {
+	var (
+		size     int64
+		modified time.Time
+	)
+	_ = filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		size += info.Size()
+		if info.ModTime().After(modified) {
+			modified = info.ModTime()
+		}
+		return nil
+	})
+	if modified.IsZero() {
+		// No files: an empty directory is as old as the directory.
+		if info, err := os.Stat(path); err == nil {
+			modified = info.ModTime()
+		}
+	}
+	return size, modified
+}
@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeFile writes size bytes to path below dir, last written age ago.
+func writeFile(t *testing.T, dir, path string, size int, age time.Duration) string {
+	t.Helper()
+	full := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(full, make([]byte, size), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(full, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	return full
+}
+
+func TestEntries(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "update-check.json", 100, time.Hour)
+	writeFile(t, dir, "old/a", 10, 48*time.Hour)
+	writeFile(t, dir, "old/sub/b", 20, 72*time.Hour)
+	writeFile(t, dir, "state/schedule.json", 5, 0)
+	c := Cache{Dir: dir, Exclude: []string{filepath.Join(dir, "state")}}
+
+	entries, err := c.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Entries() = %+v, want 2 entries", entries)
+	}
+	old, update := entries[0], entries[1]
+	if old.Category != CategoryOther || old.SizeBytes != 30 || old.Path != filepath.Join(dir, "old") {
+		t.Errorf("old = %+v", old)
+	}
+	if age := time.Since(old.Modified); age < 47*time.Hour || age > 49*time.Hour {
+		t.Errorf("old modified %v ago, want the newest file below it", age)
+	}
+	if update.Category != CategoryUpdateCheck || update.SizeBytes != 100 {
+		t.Errorf("update-check = %+v", update)
+	}
+}
+
+func TestEntries_MissingDir(t *testing.T) {
+	entries, err := Cache{Dir: filepath.Join(t.TempDir(), "missing")}.Entries()
+	if err != nil || entries != nil {
+		t.Errorf("Entries() = %v, %v; want none", entries, err)
+	}
+}
+
+func TestEntries_ExcludesParents(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "nested/state/x", 1, 0)
+	c := Cache{Dir: dir, Exclude: []string{filepath.Join(dir, "nested", "state")}}
+	entries, err := c.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Entries() = %+v, want the directory holding state left out", entries)
+	}
+}
+
+func TestSelect(t *testing.T) {
+	now := time.Now()
+	entries := []Entry{
+		{Category: CategoryUpdateCheck, Path: "u", Modified: now.Add(-time.Hour)},
+		{Category: CategoryOther, Path: "new", Modified: now.Add(-time.Minute)},
+		{Category: CategoryOther, Path: "old", Modified: now.Add(-48 * time.Hour)},
+	}
+	tests := []struct {
+		name       string
+		categories []string
+		cutoff     time.Time
+		want       []string
+	}{
+		{name: "all", want: []string{"u", "new", "old"}},
+		{name: "category", categories: []string{CategoryOther}, want: []string{"new", "old"}},
+		{name: "age", cutoff: now.Add(-30 * time.Minute), want: []string{"u", "old"}},
+		{name: "both", categories: []string{CategoryOther}, cutoff: now.Add(-24 * time.Hour), want: []string{"old"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range Select(entries, tt.categories, tt.cutoff) {
+				got = append(got, e.Path)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Select() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRemove(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, dir, "update-check.json", 1, 0)
+	writeFile(t, dir, "old/a", 1, 0)
+	c := Cache{Dir: dir}
+	entries, err := c.Entries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed, err := c.Remove(Select(entries, []string{CategoryOther}, time.Time{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 1 {
+		t.Errorf("Remove() removed %+v", removed)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "old")); !os.IsNotExist(err) {
+		t.Errorf("old still exists: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "update-check.json")); err != nil {
+		t.Errorf("update-check.json removed: %v", err)
+	}
+}
+
+func TestValidateCategories(t *testing.T) {
+	if err := ValidateCategories([]string{CategoryUpdateCheck, CategoryOther}); err != nil {
+		t.Errorf("ValidateCategories() = %v", err)
+	}
+	err := ValidateCategories([]string{"downloads"})
+	if err == nil || !strings.Contains(err.Error(), `"downloads"`) || !strings.Contains(err.Error(), "update-check, other") {
+		t.Errorf("ValidateCategories(downloads) = %v", err)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	cacheHome := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cacheHome)
+	c, err := Default()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Dir != filepath.Join(cacheHome, "ado") {
+		t.Errorf("Dir = %q", c.Dir)
+	}
+	if len(c.Exclude) == 0 {
+		t.Error("Exclude is empty")
+	}
+}
@@ -0,0 +1,339 @@
+// Package checks loads and runs health checks declared in YAML: http,
+// tcp, dns, exec, disk space, and certificate expiry probes with
+// thresholds and severities, run concurrently into one report.
+package checks
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anowarislam/ado/internal/tlsinspect"
+)
+
+// DefaultTimeout bounds a check whose file sets no timeout.
+const DefaultTimeout = 10 * time.Second
+
+// Severities, from least to most severe. A failed check reports its
+// severity; the report's status is the most severe failure.
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
+// Severities lists the severities from least to most severe.
+var Severities = []string{SeverityInfo, SeverityWarning, SeverityCritical}
+
+// Check types.
+const (
+	TypeHTTP = "http"
+	TypeTCP  = "tcp"
+	TypeDNS  = "dns"
+	TypeExec = "exec"
+	TypeDisk = "disk"
+	TypeCert = "cert"
+)
+
+// DNS record types a dns check may look up.
+var recordTypes = []string{"A", "AAAA", "CNAME", "MX", "TXT", "NS"}
+
+// File is a parsed checks file.
+type File struct {
+	Defaults Defaults `yaml:"defaults"`
+	Checks   []Check  `yaml:"checks"`
+
+	// Dir resolves relative exec commands and disk paths; Load sets it to
+	// the directory containing the file.
+	Dir string `yaml:"-"`
+}
+
+// Defaults apply to every check that does not set its own.
+type Defaults struct {
+	Timeout  time.Duration `yaml:"timeout"`
+	Severity string        `yaml:"severity"`
+}
+
+// Check is one probe. Exactly one of the probe fields is set.
+type Check struct {
+	Name string `yaml:"name"`
+	// Severity is reported when the check fails; defaults to critical.
+	Severity string        `yaml:"severity"`
+	Timeout  time.Duration `yaml:"timeout"`
+
+	HTTP *HTTPProbe `yaml:"http"`
+	TCP  *TCPProbe  `yaml:"tcp"`
+	DNS  *DNSProbe  `yaml:"dns"`
+	Exec *ExecProbe `yaml:"exec"`
+	Disk *DiskProbe `yaml:"disk"`
+	Cert *CertProbe `yaml:"cert"`
+}
+
+// HTTPProbe requests a URL.
+type HTTPProbe struct {
+	URL    string `yaml:"url"`
+	Method string `yaml:"method"`
+	// Status is the expected status code; 0 accepts any 2xx.
+	Status int `yaml:"status"`
+	// Contains is text the response body must contain.
+	Contains   string        `yaml:"contains"`
+	MaxLatency time.Duration `yaml:"max_latency"`
+}
+
+// TCPProbe connects to host:port.
+type TCPProbe struct {
+	Address    string        `yaml:"address"`
+	MaxLatency time.Duration `yaml:"max_latency"`
+}
+
+// DNSProbe resolves a name.
+type DNSProbe struct {
+	Name string `yaml:"name"`
+	// Type is the record type; defaults to A, which accepts AAAA records
+	// too.
+	Type string `yaml:"type"`
+	// Server is the resolver, host[:port]; defaults to the system's.
+	Server string `yaml:"server"`
+	// Expect lists answers that must all be present.
+	Expect []string `yaml:"expect"`
+}
+
+// ExecProbe runs a command.
+type ExecProbe struct {
+	Command string   `yaml:"command"`
+	Args    []string `yaml:"args"`
+	// ExitCode is the exit status that passes; defaults to 0.
+	ExitCode int `yaml:"exit_code"`
+}
+
+// DiskProbe checks the space of the filesystem holding Path. At least one
+// threshold is set.
+type DiskProbe struct {
+	Path           string  `yaml:"path"`
+	MinFreeMB      uint64  `yaml:"min_free_mb"`
+	MaxUsedPercent float64 `yaml:"max_used_percent"`
+}
+
+// CertProbe checks the certificate chain a TLS server presents.
+type CertProbe struct {
+	// Address is HOST, HOST:PORT, or an https:// URL.
+	Address    string `yaml:"address"`
+	ServerName string `yaml:"server_name"`
+	// MinDays is how many days the chain must stay valid.
+	MinDays int `yaml:"min_days"`
+	// SkipVerify checks expiry only, accepting untrusted chains.
+	SkipVerify bool `yaml:"skip_verify"`
+}
+
+// Type is the kind of probe c runs.
+func (c Check) Type() string {
+	switch {
+	case c.HTTP != nil:
+		return TypeHTTP
+	case c.TCP != nil:
+		return TypeTCP
+	case c.DNS != nil:
+		return TypeDNS
+	case c.Exec != nil:
+		return TypeExec
+	case c.Disk != nil:
+		return TypeDisk
+	case c.Cert != nil:
+		return TypeCert
+	}
+	return ""
+}
+
+// Target is what c probes, as shown in reports.
+func (c Check) Target() string {
+	switch {
+	case c.HTTP != nil:
+		return c.HTTP.URL
+	case c.TCP != nil:
+		return c.TCP.Address
+	case c.DNS != nil:
+		return c.DNS.Name
+	case c.Exec != nil:
+		return strings.TrimSpace(c.Exec.Command + " " + strings.Join(c.Exec.Args, " "))
+	case c.Disk != nil:
+		return c.Disk.Path
+	case c.Cert != nil:
+		return c.Cert.Address
+	}
+	return ""
+}
+
+// Load reads and validates the checks file at path.
+func Load(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open checks: %w", err)
+	}
+	defer f.Close()
+
+	file, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	file.Dir = filepath.Dir(abs)
+	return file, nil
+}
+
+// Parse reads and validates a checks file and applies its defaults to
+// each check. Unknown fields are errors.
+func Parse(r io.Reader) (*File, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read checks: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var file File
+	if err := dec.Decode(&file); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("checks file is empty")
+		}
+		return nil, fmt.Errorf("parse checks: %w", err)
+	}
+	if err := file.Validate(); err != nil {
+		return nil, err
+	}
+	for i := range file.Checks {
+		c := &file.Checks[i]
+		if c.Severity == "" {
+			c.Severity = file.Defaults.Severity
+		}
+		if c.Severity == "" {
+			c.Severity = SeverityCritical
+		}
+		if c.Timeout == 0 {
+			c.Timeout = file.Defaults.Timeout
+		}
+		if c.Timeout == 0 {
+			c.Timeout = DefaultTimeout
+		}
+	}
+	return &file, nil
+}
+
+// Validate checks every check, so mistakes are reported before anything
+// runs.
+func (f *File) Validate() error {
+	if err := validateSeverity(f.Defaults.Severity); err != nil {
+		return fmt.Errorf("defaults: %w", err)
+	}
+	if f.Defaults.Timeout < 0 {
+		return errors.New("defaults: timeout must not be negative")
+	}
+	if len(f.Checks) == 0 {
+		return errors.New("no checks defined")
+	}
+	seen := map[string]bool{}
+	for i, c := range f.Checks {
+		where := fmt.Sprintf("check %d", i+1)
+		if c.Name != "" {
+			where = fmt.Sprintf("check %q", c.Name)
+		}
+		if seen[c.Name] {
+			return fmt.Errorf("%s: duplicate name", where)
+		}
+		seen[c.Name] = true
+		if err := c.validate(); err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+	}
+	return nil
+}
+
+func (c Check) validate() error {
+	probes := 0
+	for _, set := range []bool{c.HTTP != nil, c.TCP != nil, c.DNS != nil, c.Exec != nil, c.Disk != nil, c.Cert != nil} {
+		if set {
+			probes++
+		}
+	}
+	switch {
+	case c.Name == "":
+		return errors.New("needs a name")
+	case probes == 0:
+		return errors.New("needs one of http, tcp, dns, exec, disk, cert")
+	case probes > 1:
+		return errors.New("has more than one of http, tcp, dns, exec, disk, cert; use one per check")
+	case c.Timeout < 0:
+		return errors.New("timeout must not be negative")
+	}
+	if err := validateSeverity(c.Severity); err != nil {
+		return err
+	}
+
+	switch {
+	case c.HTTP != nil:
+		u, err := url.Parse(c.HTTP.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("http: invalid url %q: expected http(s)://HOST/PATH", c.HTTP.URL)
+		}
+		if c.HTTP.Status != 0 && (c.HTTP.Status < 100 || c.HTTP.Status > 599) {
+			return fmt.Errorf("http: invalid status %d", c.HTTP.Status)
+		}
+	case c.TCP != nil:
+		if _, _, err := net.SplitHostPort(c.TCP.Address); err != nil {
+			return fmt.Errorf("tcp: invalid address %q: expected HOST:PORT", c.TCP.Address)
+		}
+	case c.DNS != nil:
+		if c.DNS.Name == "" {
+			return errors.New("dns: needs a name")
+		}
+		if c.DNS.Type != "" && !slices.Contains(recordTypes, strings.ToUpper(c.DNS.Type)) {
+			return fmt.Errorf("dns: invalid type %q (valid: %s)", c.DNS.Type, strings.Join(recordTypes, ", "))
+		}
+	case c.Exec != nil:
+		if c.Exec.Command == "" {
+			return errors.New("exec: needs a command")
+		}
+	case c.Disk != nil:
+		if c.Disk.Path == "" {
+			return errors.New("disk: needs a path")
+		}
+		if c.Disk.MinFreeMB == 0 && c.Disk.MaxUsedPercent == 0 {
+			return errors.New("disk: needs min_free_mb or max_used_percent")
+		}
+		if c.Disk.MaxUsedPercent < 0 || c.Disk.MaxUsedPercent > 100 {
+			return fmt.Errorf("disk: max_used_percent %g is not between 0 and 100", c.Disk.MaxUsedPercent)
+		}
+	case c.Cert != nil:
+		if _, err := tlsinspect.ParseAddress(c.Cert.Address); err != nil {
+			return fmt.Errorf("cert: %w", err)
+		}
+		if c.Cert.MinDays < 0 {
+			return errors.New("cert: min_days must not be negative")
+		}
+	}
+	return nil
+}
+
+func validateSeverity(severity string) error {
+	if severity != "" && !slices.Contains(Severities, severity) {
+		return fmt.Errorf("invalid severity %q (valid: %s)", severity, strings.Join(Severities, ", "))
+	}
+	return nil
+}
+
+// SeverityRank orders severities: info is 1 and critical 3. Anything
+// else is 0.
+func SeverityRank(severity string) int {
+	return slices.Index(Severities, severity) + 1
+}
@@ -0,0 +1,114 @@
+package checks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	file, err := Parse(strings.NewReader(`
+defaults:
+  timeout: 3s
+  severity: warning
+checks:
+  - name: api
+    severity: critical
+    http:
+      url: https://api.example.com/healthz
+      contains: ok
+  - name: db
+    timeout: 1s
+    tcp:
+      address: db.internal:5432
+  - name: root disk
+    disk:
+      path: /
+      min_free_mb: 1024
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		typ, target, severity string
+		timeout               time.Duration
+	}{
+		{TypeHTTP, "https://api.example.com/healthz", SeverityCritical, 3 * time.Second},
+		{TypeTCP, "db.internal:5432", SeverityWarning, time.Second},
+		{TypeDisk, "/", SeverityWarning, 3 * time.Second},
+	}
+	for i, w := range want {
+		c := file.Checks[i]
+		if c.Type() != w.typ || c.Target() != w.target || c.Severity != w.severity || c.Timeout != w.timeout {
+			t.Errorf("check %d = %s %s %s %s, want %+v", i, c.Type(), c.Target(), c.Severity, c.Timeout, w)
+		}
+	}
+
+	file, err = Parse(strings.NewReader("checks:\n  - name: x\n    exec:\n      command: true\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := file.Checks[0]; c.Severity != SeverityCritical || c.Timeout != DefaultTimeout {
+		t.Errorf("defaults not applied: %+v", c)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name, yaml, wantErr string
+	}{
+		{"empty", "", "checks file is empty"},
+		{"no checks", "checks: []", "no checks defined"},
+		{"unknown field", "checks:\n  - name: x\n    ping: {}", "field ping not found"},
+		{"no name", "checks:\n  - tcp: {address: 'a:1'}", "check 1: needs a name"},
+		{"duplicate", "checks:\n  - {name: x, tcp: {address: 'a:1'}}\n  - {name: x, tcp: {address: 'a:1'}}", `check "x": duplicate name`},
+		{"no probe", "checks:\n  - name: x", "needs one of http"},
+		{"two probes", "checks:\n  - {name: x, tcp: {address: 'a:1'}, disk: {path: /, min_free_mb: 1}}", "more than one"},
+		{"severity", "checks:\n  - {name: x, severity: high, tcp: {address: 'a:1'}}", `invalid severity "high"`},
+		{"default severity", "defaults: {severity: high}\nchecks:\n  - {name: x, tcp: {address: 'a:1'}}", "defaults: invalid severity"},
+		{"timeout", "checks:\n  - {name: x, timeout: -1s, tcp: {address: 'a:1'}}", "timeout must not be negative"},
+		{"http url", "checks:\n  - {name: x, http: {url: 'example.com'}}", "http: invalid url"},
+		{"http status", "checks:\n  - {name: x, http: {url: 'http://a', status: 999}}", "http: invalid status"},
+		{"tcp address", "checks:\n  - {name: x, tcp: {address: 'db'}}", "tcp: invalid address"},
+		{"dns name", "checks:\n  - {name: x, dns: {type: A}}", "dns: needs a name"},
+		{"dns type", "checks:\n  - {name: x, dns: {name: a, type: SRV}}", `dns: invalid type "SRV"`},
+		{"exec command", "checks:\n  - {name: x, exec: {args: [a]}}", "exec: needs a command"},
+		{"disk threshold", "checks:\n  - {name: x, disk: {path: /}}", "needs min_free_mb or max_used_percent"},
+		{"disk percent", "checks:\n  - {name: x, disk: {path: /, max_used_percent: 120}}", "not between 0 and 100"},
+		{"cert address", "checks:\n  - {name: x, cert: {address: 'a:0'}}", "cert: invalid target"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.yaml))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "checks.yaml")
+	if err := os.WriteFile(path, []byte("checks:\n  - {name: x, disk: {path: ., min_free_mb: 1}}\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	file, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if file.Dir != dir {
+		t.Errorf("Dir = %q, want %q", file.Dir, dir)
+	}
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil || !strings.Contains(err.Error(), "open checks") {
+		t.Errorf("Load(missing) error = %v", err)
+	}
+}
+
+func TestSeverityRank(t *testing.T) {
+	if !(SeverityRank(SeverityCritical) > SeverityRank(SeverityWarning) && SeverityRank(SeverityWarning) > SeverityRank(SeverityInfo) && SeverityRank(SeverityInfo) > SeverityRank(StatusOK)) {
+		t.Errorf("ranks out of order")
+	}
+}
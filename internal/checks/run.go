@@ -0,0 +1,402 @@
+package checks
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
+
+	"github.com/anowarislam/ado/internal/process"
+	"github.com/anowarislam/ado/internal/tlsinspect"
+)
+
+// DefaultJobs is how many checks run at once by default. Checks mostly
+// wait on the network, so this does not follow the CPU count.
+const DefaultJobs = 8
+
+// Result statuses.
+const (
+	StatusPass = "pass"
+	StatusFail = "fail"
+)
+
+// StatusOK is the report status when no check failed.
+const StatusOK = "ok"
+
+// maxBody bounds how much of an HTTP response is searched for Contains.
+const maxBody = 1 << 20
+
+// Result is the outcome of one check.
+type Result struct {
+	Name     string `json:"name" yaml:"name"`
+	Type     string `json:"type" yaml:"type"`
+	Target   string `json:"target" yaml:"target"`
+	Severity string `json:"severity" yaml:"severity"`
+	Status   string `json:"status" yaml:"status"`
+	// Message describes what was observed, or why the check failed.
+	Message    string `json:"message" yaml:"message"`
+	DurationMS int64  `json:"duration_ms" yaml:"duration_ms"`
+}
+
+// Report aggregates the results of a run.
+type Report struct {
+	// Status is the severity of the most severe failure, or ok.
+	Status     string   `json:"status" yaml:"status"`
+	Total      int      `json:"total" yaml:"total"`
+	Passed     int      `json:"passed" yaml:"passed"`
+	Failed     int      `json:"failed" yaml:"failed"`
+	DurationMS int64    `json:"duration_ms" yaml:"duration_ms"`
+	Results    []Result `json:"results" yaml:"results"`
+}
+
+// FailedAtLeast counts the failed checks of severity or worse.
+func (r Report) FailedAtLeast(severity string) int {
+	n := 0
+	for _, res := range r.Results {
+		if res.Status == StatusFail && SeverityRank(res.Severity) >= SeverityRank(severity) {
+			n++
+		}
+	}
+	return n
+}
+
+// Runner runs the checks of a file.
+type Runner struct {
+	// Jobs is the maximum number of checks running at once; defaults to
+	// DefaultJobs.
+	Jobs int
+	// HTTPClient sends http checks; nil uses a client that does not follow
+	// redirects, so a check sees the status the URL itself returns.
+	HTTPClient *http.Client
+	// Roots verifies cert checks; nil uses the system pool.
+	Roots *x509.CertPool
+	// OnDone, when set, is called with each result as its check finishes.
+	// Calls are serialized.
+	OnDone func(Result)
+}
+
+// Run runs every check of f and returns the results in file order.
+func (r *Runner) Run(ctx context.Context, f *File) Report {
+	jobs := r.Jobs
+	if jobs < 1 {
+		jobs = DefaultJobs
+	}
+	started := time.Now()
+	report := Report{Status: StatusOK, Total: len(f.Checks), Results: make([]Result, len(f.Checks))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	slots := make(chan struct{}, jobs)
+	for i, c := range f.Checks {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			result := r.runOne(ctx, f, c)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Results[i] = result
+			if r.OnDone != nil {
+				r.OnDone(result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, res := range report.Results {
+		if res.Status == StatusPass {
+			report.Passed++
+			continue
+		}
+		report.Failed++
+		if SeverityRank(res.Severity) > SeverityRank(report.Status) {
+			report.Status = res.Severity
+		}
+	}
+	report.DurationMS = time.Since(started).Milliseconds()
+	return report
+}
+
+func (r *Runner) runOne(ctx context.Context, f *File, c Check) Result {
+	result := Result{Name: c.Name, Type: c.Type(), Target: c.Target(), Severity: c.Severity}
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+
+	started := time.Now()
+	message, err := r.probe(ctx, f, c)
+	result.DurationMS = time.Since(started).Milliseconds()
+	if err != nil {
+		result.Status, result.Message = StatusFail, err.Error()
+		if ctx.Err() != nil {
+			result.Message = fmt.Sprintf("timed out after %s: %s", c.Timeout, result.Message)
+		}
+		return result
+	}
+	result.Status, result.Message = StatusPass, message
+	return result
+}
+
+// probe runs c's probe, returning what it observed or why it failed.
+func (r *Runner) probe(ctx context.Context, f *File, c Check) (string, error) {
+	switch {
+	case c.HTTP != nil:
+		return r.probeHTTP(ctx, c.HTTP)
+	case c.TCP != nil:
+		return probeTCP(ctx, c.TCP)
+	case c.DNS != nil:
+		return probeDNS(ctx, c.DNS)
+	case c.Exec != nil:
+		return probeExec(ctx, f.Dir, c.Exec)
+	case c.Disk != nil:
+		return probeDisk(ctx, f.Dir, c.Disk)
+	case c.Cert != nil:
+		return r.probeCert(ctx, c.Cert)
+	}
+	return "", errors.New("no probe")
+}
+
+func (r *Runner) probeHTTP(ctx context.Context, p *HTTPProbe) (string, error) {
+	client := r.HTTPClient
+	if client == nil {
+		client = &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+	}
+	method := p.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(method), p.URL, nil)
+	if err != nil {
+		return "", err
+	}
+
+	started := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBody))
+	latency := time.Since(started)
+	if err != nil {
+		return "", fmt.Errorf("read body: %w", err)
+	}
+
+	switch {
+	case p.Status != 0 && resp.StatusCode != p.Status:
+		return "", fmt.Errorf("status %d, want %d", resp.StatusCode, p.Status)
+	case p.Status == 0 && (resp.StatusCode < 200 || resp.StatusCode > 299):
+		return "", fmt.Errorf("status %d, want 2xx", resp.StatusCode)
+	case p.Contains != "" && !strings.Contains(string(body), p.Contains):
+		return "", fmt.Errorf("status %d, body does not contain %q", resp.StatusCode, p.Contains)
+	case p.MaxLatency > 0 && latency > p.MaxLatency:
+		return "", fmt.Errorf("status %d in %s, slower than %s", resp.StatusCode, roundLatency(latency), p.MaxLatency)
+	}
+	return fmt.Sprintf("status %d in %s", resp.StatusCode, roundLatency(latency)), nil
+}
+
+func probeTCP(ctx context.Context, p *TCPProbe) (string, error) {
+	var d net.Dialer
+	started := time.Now()
+	conn, err := d.DialContext(ctx, "tcp", p.Address)
+	if err != nil {
+		return "", err
+	}
+	latency := time.Since(started)
+	_ = conn.Close()
+	if p.MaxLatency > 0 && latency > p.MaxLatency {
+		return "", fmt.Errorf("connected in %s, slower than %s", roundLatency(latency), p.MaxLatency)
+	}
+	return "connected in " + roundLatency(latency).String(), nil
+}
+
+func probeDNS(ctx context.Context, p *DNSProbe) (string, error) {
+	resolver := net.DefaultResolver
+	if p.Server != "" {
+		server := p.Server
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, "53")
+		}
+		resolver = &net.Resolver{
+			PreferGo: true,
+			Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, server)
+			},
+		}
+	}
+
+	recordType := strings.ToUpper(p.Type)
+	if recordType == "" {
+		recordType = "A"
+	}
+	var (
+		answers []string
+		err     error
+	)
+	switch recordType {
+	case "A":
+		answers, err = resolver.LookupHost(ctx, p.Name)
+	case "AAAA":
+		var ips []net.IP
+		ips, err = resolver.LookupIP(ctx, "ip6", p.Name)
+		for _, ip := range ips {
+			answers = append(answers, ip.String())
+		}
+	case "CNAME":
+		var cname string
+		cname, err = resolver.LookupCNAME(ctx, p.Name)
+		answers = []string{strings.TrimSuffix(cname, ".")}
+	case "MX":
+		var mxs []*net.MX
+		mxs, err = resolver.LookupMX(ctx, p.Name)
+		for _, mx := range mxs {
+			answers = append(answers, strings.TrimSuffix(mx.Host, "."))
+		}
+	case "TXT":
+		answers, err = resolver.LookupTXT(ctx, p.Name)
+	case "NS":
+		var nss []*net.NS
+		nss, err = resolver.LookupNS(ctx, p.Name)
+		for _, ns := range nss {
+			answers = append(answers, strings.TrimSuffix(ns.Host, "."))
+		}
+	}
+	if err != nil {
+		return "", err
+	}
+	if len(answers) == 0 {
+		return "", fmt.Errorf("no %s records", recordType)
+	}
+	for _, want := range p.Expect {
+		if !slices.Contains(answers, strings.TrimSuffix(want, ".")) {
+			return "", fmt.Errorf("resolved to %s, missing %s", strings.Join(answers, ", "), want)
+		}
+	}
+	return "resolved to " + strings.Join(answers, ", "), nil
+}
+
+func probeExec(ctx context.Context, dir string, p *ExecProbe) (string, error) {
+	command := p.Command
+	// A relative path such as ./check.sh is relative to the checks file;
+	// a bare name is looked up in PATH.
+	if strings.ContainsAny(command, "/"+string(filepath.Separator)) && !filepath.IsAbs(command) {
+		command = filepath.Join(dir, command)
+	}
+	cmd := exec.CommandContext(ctx, command, p.Args...)
+	cmd.Dir = dir
+	process.NewGroup(cmd)
+	process.Graceful(cmd, process.GracePeriod)
+	out, err := cmd.CombinedOutput()
+
+	code := 0
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr) && exitErr.ExitCode() >= 0:
+		code = exitErr.ExitCode()
+	case err != nil:
+		return "", err
+	}
+	line := lastLine(string(out))
+	if code != p.ExitCode {
+		message := fmt.Sprintf("exit status %d, want %d", code, p.ExitCode)
+		if line != "" {
+			message += ": " + line
+		}
+		return "", errors.New(message)
+	}
+	if line != "" {
+		return line, nil
+	}
+	return fmt.Sprintf("exit status %d", code), nil
+}
+
+// lastLine returns the last non-empty line of command output, which is
+// usually its verdict.
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+func probeDisk(ctx context.Context, dir string, p *DiskProbe) (string, error) {
+	path := p.Path
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	// Statfs on a hung network mount does not return, so wait for it
+	// only as long as the check's timeout.
+	type usage struct {
+		stat *disk.UsageStat
+		err  error
+	}
+	done := make(chan usage, 1)
+	go func() {
+		stat, err := disk.UsageWithContext(ctx, path)
+		done <- usage{stat, err}
+	}()
+	var u usage
+	select {
+	case u = <-done:
+	case <-ctx.Done():
+		return "", fmt.Errorf("%s did not respond", path)
+	}
+	if u.err != nil {
+		return "", fmt.Errorf("%s: %w", p.Path, u.err)
+	}
+
+	freeMB := u.stat.Free / (1024 * 1024)
+	observed := fmt.Sprintf("%d MB free, %.1f%% used", freeMB, u.stat.UsedPercent)
+	switch {
+	case p.MinFreeMB > 0 && freeMB < p.MinFreeMB:
+		return "", fmt.Errorf("%s, want at least %d MB free", observed, p.MinFreeMB)
+	case p.MaxUsedPercent > 0 && u.stat.UsedPercent > p.MaxUsedPercent:
+		return "", fmt.Errorf("%s, want at most %g%% used", observed, p.MaxUsedPercent)
+	}
+	return observed, nil
+}
+
+func (r *Runner) probeCert(ctx context.Context, p *CertProbe) (string, error) {
+	address, err := tlsinspect.ParseAddress(p.Address)
+	if err != nil {
+		return "", err
+	}
+	opts := tlsinspect.Options{ServerName: p.ServerName, Roots: r.Roots}
+	if deadline, ok := ctx.Deadline(); ok {
+		opts.Timeout = time.Until(deadline)
+	}
+	result, err := tlsinspect.Inspect(ctx, address, opts)
+	if err != nil {
+		return "", err
+	}
+
+	observed := fmt.Sprintf("expires in %d days (%s)", result.DaysRemaining, result.NotAfter.UTC().Format(time.DateOnly))
+	switch {
+	case !p.SkipVerify && !result.Verified:
+		return "", fmt.Errorf("untrusted: %s", result.VerifyError)
+	case result.DaysRemaining < p.MinDays:
+		return "", fmt.Errorf("%s, want at least %d", observed, p.MinDays)
+	}
+	return observed, nil
+}
+
+// roundLatency keeps latencies readable: whole milliseconds, or
+// microseconds below one.
+func roundLatency(d time.Duration) time.Duration {
+	if d < time.Millisecond {
+		return d.Round(time.Microsecond)
+	}
+	return d.Round(time.Millisecond)
+}
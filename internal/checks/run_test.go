@@ -0,0 +1,217 @@
+package checks
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runCheck runs c alone and returns its result.
+func runCheck(t *testing.T, runner *Runner, dir string, c Check) Result {
+	t.Helper()
+	if c.Severity == "" {
+		c.Severity = SeverityCritical
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 5 * time.Second
+	}
+	report := runner.Run(context.Background(), &File{Dir: dir, Checks: []Check{c}})
+	return report.Results[0]
+}
+
+func TestRun_HTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/healthz":
+			fmt.Fprint(w, `{"status":"ok"}`)
+		case "/slow":
+			time.Sleep(50 * time.Millisecond)
+		case "/moved":
+			http.Redirect(w, r, "/healthz", http.StatusFound)
+		default:
+			http.Error(w, "down", http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name       string
+		probe      HTTPProbe
+		wantStatus string
+		wantMsg    string
+	}{
+		{"ok", HTTPProbe{URL: srv.URL + "/healthz", Contains: `"ok"`}, StatusPass, "status 200 in "},
+		{"not 2xx", HTTPProbe{URL: srv.URL + "/down"}, StatusFail, "status 503, want 2xx"},
+		{"expected status", HTTPProbe{URL: srv.URL + "/down", Status: 503}, StatusPass, "status 503"},
+		{"redirect not followed", HTTPProbe{URL: srv.URL + "/moved", Status: 302}, StatusPass, "status 302"},
+		{"body", HTTPProbe{URL: srv.URL + "/healthz", Contains: "healthy"}, StatusFail, `body does not contain "healthy"`},
+		{"latency", HTTPProbe{URL: srv.URL + "/slow", MaxLatency: time.Millisecond}, StatusFail, "slower than 1ms"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := runCheck(t, &Runner{}, "", Check{Name: tt.name, HTTP: &tt.probe})
+			if got.Status != tt.wantStatus || !strings.Contains(got.Message, tt.wantMsg) {
+				t.Errorf("result = %s %q, want %s %q", got.Status, got.Message, tt.wantStatus, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestRun_TCP(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	open := ln.Addr().String()
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closedAddr := closed.Addr().String()
+	closed.Close()
+	defer ln.Close()
+
+	if got := runCheck(t, &Runner{}, "", Check{Name: "open", TCP: &TCPProbe{Address: open}}); got.Status != StatusPass || !strings.HasPrefix(got.Message, "connected in") {
+		t.Errorf("open port: %+v", got)
+	}
+	if got := runCheck(t, &Runner{}, "", Check{Name: "closed", TCP: &TCPProbe{Address: closedAddr}}); got.Status != StatusFail {
+		t.Errorf("closed port: %+v", got)
+	}
+}
+
+func TestRun_DNS(t *testing.T) {
+	got := runCheck(t, &Runner{}, "", Check{Name: "localhost", DNS: &DNSProbe{Name: "localhost"}})
+	if got.Status != StatusPass || !strings.Contains(got.Message, "127.0.0.1") {
+		t.Errorf("localhost: %+v", got)
+	}
+	got = runCheck(t, &Runner{}, "", Check{Name: "expect", DNS: &DNSProbe{Name: "localhost", Expect: []string{"192.0.2.1"}}})
+	if got.Status != StatusFail || !strings.Contains(got.Message, "missing 192.0.2.1") {
+		t.Errorf("expect: %+v", got)
+	}
+}
+
+func TestRun_Exec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses sh")
+	}
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "check.sh"), []byte("#!/bin/sh\necho checking\necho \"backups: $1\"\nexit $2\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		probe      ExecProbe
+		wantStatus string
+		wantMsg    string
+	}{
+		{"pass", ExecProbe{Command: "./check.sh", Args: []string{"fresh", "0"}}, StatusPass, "backups: fresh"},
+		{"fail", ExecProbe{Command: "./check.sh", Args: []string{"stale", "2"}}, StatusFail, "exit status 2, want 0: backups: stale"},
+		{"expected exit code", ExecProbe{Command: "./check.sh", Args: []string{"stale", "2"}, ExitCode: 2}, StatusPass, "backups: stale"},
+		{"path lookup", ExecProbe{Command: "true"}, StatusPass, "exit status 0"},
+		{"missing", ExecProbe{Command: "./missing.sh"}, StatusFail, "missing.sh"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := runCheck(t, &Runner{}, dir, Check{Name: tt.name, Exec: &tt.probe})
+			if got.Status != tt.wantStatus || !strings.Contains(got.Message, tt.wantMsg) {
+				t.Errorf("result = %s %q, want %s %q", got.Status, got.Message, tt.wantStatus, tt.wantMsg)
+			}
+		})
+	}
+
+	got := runCheck(t, &Runner{}, dir, Check{Name: "slow", Timeout: 50 * time.Millisecond, Exec: &ExecProbe{Command: "sleep", Args: []string{"5"}}})
+	if got.Status != StatusFail || !strings.HasPrefix(got.Message, "timed out after 50ms") {
+		t.Errorf("timeout: %+v", got)
+	}
+}
+
+func TestRun_Disk(t *testing.T) {
+	dir := t.TempDir()
+	tests := []struct {
+		name       string
+		probe      DiskProbe
+		wantStatus string
+		wantMsg    string
+	}{
+		{"pass", DiskProbe{Path: ".", MinFreeMB: 1}, StatusPass, "MB free"},
+		{"free", DiskProbe{Path: dir, MinFreeMB: 1 << 40}, StatusFail, "want at least 1099511627776 MB free"},
+		{"used", DiskProbe{Path: dir, MaxUsedPercent: 0.0001}, StatusFail, "want at most 0.0001% used"},
+		{"missing", DiskProbe{Path: filepath.Join(dir, "missing"), MinFreeMB: 1}, StatusFail, "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := runCheck(t, &Runner{}, dir, Check{Name: tt.name, Disk: &tt.probe})
+			if got.Status != tt.wantStatus || !strings.Contains(got.Message, tt.wantMsg) {
+				t.Errorf("result = %s %q, want %s %q", got.Status, got.Message, tt.wantStatus, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestRun_Cert(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.NotFoundHandler())
+	// The probe hangs up right after the handshake.
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	tests := []struct {
+		name       string
+		runner     *Runner
+		probe      CertProbe
+		wantStatus string
+		wantMsg    string
+	}{
+		{"trusted", &Runner{Roots: roots}, CertProbe{Address: srv.URL, ServerName: "example.com", MinDays: 14}, StatusPass, "expires in "},
+		{"untrusted", &Runner{}, CertProbe{Address: srv.URL, ServerName: "example.com"}, StatusFail, "untrusted: "},
+		{"skip verify", &Runner{}, CertProbe{Address: srv.URL, ServerName: "example.com", SkipVerify: true}, StatusPass, "expires in "},
+		{"expiring", &Runner{Roots: roots}, CertProbe{Address: srv.URL, ServerName: "example.com", MinDays: 1 << 20}, StatusFail, "want at least 1048576"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := runCheck(t, tt.runner, "", Check{Name: tt.name, Cert: &tt.probe})
+			if got.Status != tt.wantStatus || !strings.Contains(got.Message, tt.wantMsg) {
+				t.Errorf("result = %s %q, want %s %q", got.Status, got.Message, tt.wantStatus, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestRun_Report(t *testing.T) {
+	dir := t.TempDir()
+	file := &File{Dir: dir, Checks: []Check{
+		{Name: "ok", Severity: SeverityCritical, Timeout: time.Second, Disk: &DiskProbe{Path: ".", MinFreeMB: 1}},
+		{Name: "warn", Severity: SeverityWarning, Timeout: time.Second, Disk: &DiskProbe{Path: ".", MinFreeMB: 1 << 40}},
+		{Name: "info", Severity: SeverityInfo, Timeout: time.Second, Disk: &DiskProbe{Path: "missing", MinFreeMB: 1}},
+	}}
+
+	var done []string
+	runner := &Runner{Jobs: 2, OnDone: func(r Result) { done = append(done, r.Name) }}
+	report := runner.Run(context.Background(), file)
+
+	if report.Status != SeverityWarning || report.Total != 3 || report.Passed != 1 || report.Failed != 2 {
+		t.Errorf("report = %+v", report)
+	}
+	for i, name := range []string{"ok", "warn", "info"} {
+		if report.Results[i].Name != name {
+			t.Errorf("result %d = %s, want %s", i, report.Results[i].Name, name)
+		}
+	}
+	if len(done) != 3 {
+		t.Errorf("OnDone called for %v", done)
+	}
+	if report.FailedAtLeast(SeverityCritical) != 0 || report.FailedAtLeast(SeverityWarning) != 1 || report.FailedAtLeast(SeverityInfo) != 2 {
+		t.Errorf("FailedAtLeast = %d, %d, %d", report.FailedAtLeast(SeverityCritical), report.FailedAtLeast(SeverityWarning), report.FailedAtLeast(SeverityInfo))
+	}
+}
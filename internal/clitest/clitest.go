@@ -0,0 +1,104 @@
+// Package clitest runs end-to-end tests of the ado binary written as
+// testscript files: txtar archives holding a script of ado invocations
+// with their expected output, plus any files they need.
+//
+// The test binary doubles as ado. Main makes it run the real entry point,
+// root.ExecuteContext, when a script invokes `ado`, so scripts see what
+// users see: the full command tree, config resolution, output on stdout
+// and stderr, and the process exit status.
+//
+// Each script runs in a fresh work directory that is also $HOME, with
+// the update check disabled and no config from the environment, so
+// nothing leaks in from the machine running the tests.
+//
+// Besides the standard testscript commands, scripts can check an exact
+// exit status:
+//
+//	exitcode 124 ado --timeout 100ms run slow
+package clitest
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/rogpeppe/go-internal/testscript"
+
+	"github.com/anowarislam/ado/cmd/ado/root"
+)
+
+// Main runs the tests in m, or ado when the test binary is invoked as ado
+// by a script. Call it from TestMain.
+func Main(m *testing.M) {
+	os.Exit(testscript.RunMain(m, map[string]func() int{
+		"ado": func() int { return root.ExecuteContext(context.Background()) },
+	}))
+}
+
+// Run runs the scripts in dir, one subtest per file.
+func Run(t *testing.T, dir string) {
+	testscript.Run(t, Params(dir))
+}
+
+// Params returns the testscript parameters for the scripts in dir, for
+// callers that need to add commands or conditions.
+func Params(dir string) testscript.Params {
+	return testscript.Params{
+		Dir:                 dir,
+		Setup:               setup,
+		RequireExplicitExec: true,
+		RequireUniqueNames:  true,
+		Cmds: map[string]func(ts *testscript.TestScript, neg bool, args []string){
+			"exitcode": cmdExitCode,
+		},
+	}
+}
+
+// cmdExitCode runs a program like exec and fails unless it exits with the
+// given status.
+func cmdExitCode(ts *testscript.TestScript, neg bool, args []string) {
+	if neg {
+		ts.Fatalf("unsupported: ! exitcode")
+	}
+	if len(args) < 2 {
+		ts.Fatalf("usage: exitcode STATUS PROGRAM [ARGS...]")
+	}
+	want, err := strconv.Atoi(args[0])
+	if err != nil {
+		ts.Fatalf("invalid status %q", args[0])
+	}
+
+	got := 0
+	var exitErr *exec.ExitError
+	if err := ts.Exec(args[1], args[2:]...); errors.As(err, &exitErr) {
+		got = exitErr.ExitCode()
+	} else if err != nil {
+		ts.Fatalf("%s: %v", args[1], err)
+	}
+	if got != want {
+		ts.Fatalf("%s exited with status %d, want %d", args[1], got, want)
+	}
+}
+
+// setup isolates a script from the user's environment.
+func setup(env *testscript.Env) error {
+	home := env.WorkDir
+	env.Setenv("HOME", home)
+	env.Setenv("USERPROFILE", home)
+	env.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	env.Setenv("XDG_CACHE_HOME", filepath.Join(home, ".cache"))
+	env.Setenv("XDG_STATE_HOME", filepath.Join(home, ".local", "state"))
+	env.Setenv("XDG_DATA_HOME", filepath.Join(home, ".local", "share"))
+	env.Setenv("ADO_CONFIG", "")
+	env.Setenv("ADO_FEATURES", "")
+	env.Setenv("ADO_NO_UPDATE_CHECK", "1")
+	env.Setenv("NO_COLOR", "1")
+	env.Setenv("CI", "")
+	env.Setenv("GITHUB_ACTIONS", "")
+	env.Setenv("GITHUB_STEP_SUMMARY", "")
+	return nil
+}
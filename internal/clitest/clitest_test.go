@@ -0,0 +1,14 @@
+package clitest
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	Main(m)
+}
+
+func TestScripts(t *testing.T) {
+	Run(t, filepath.Join("testdata", "script"))
+}
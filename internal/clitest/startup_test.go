@@ -0,0 +1,44 @@
+package clitest
+
+import (
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+// startupBudget is the CPU time, kernel time excluded, that the commands
+// in TestStartupBudget may take. ado is invoked thousands of times from
+// scripts, so startup must stay clear of hardware probes, network calls,
+// and other work only some commands need.
+const startupBudget = 50 * time.Millisecond
+
+func TestStartupBudget(t *testing.T) {
+	if raceEnabled {
+		t.Skip("the race detector slows startup several times over")
+	}
+	ado, err := exec.LookPath("ado")
+	if err != nil {
+		t.Fatal(err)
+	}
+	home := t.TempDir()
+	env := append(os.Environ(), "HOME="+home, "XDG_CONFIG_HOME="+home, "ADO_CONFIG=", "ADO_FEATURES=", "ADO_NO_UPDATE_CHECK=1")
+
+	for _, args := range [][]string{{"--help"}, {"echo", "hi"}} {
+		// The fastest of a few runs discounts a busy machine.
+		best := time.Duration(-1)
+		for range 5 {
+			cmd := exec.Command(ado, args...)
+			cmd.Env = env
+			if out, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("ado %v: %v\n%s", args, err, out)
+			}
+			if user := cmd.ProcessState.UserTime(); best < 0 || user < best {
+				best = user
+			}
+		}
+		if best > startupBudget {
+			t.Errorf("ado %v took %v of CPU time, over the %v budget", args, best, startupBudget)
+		}
+	}
+}
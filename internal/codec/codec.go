@@ -0,0 +1,120 @@
+// Package codec implements the text encodings behind ado encode and
+// ado decode.
+package codec
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Supported schemes.
+const (
+	Base64    = "base64"
+	Base64URL = "base64url"
+	Hex       = "hex"
+	URL       = "url"
+	URLPath   = "urlpath"
+)
+
+// Schemes lists the supported schemes.
+var Schemes = []string{Base64, Base64URL, Hex, URL, URLPath}
+
+// ParseScheme normalizes and validates a scheme name.
+func ParseScheme(raw string) (string, error) {
+	scheme := strings.ToLower(raw)
+	for _, s := range Schemes {
+		if s == scheme {
+			return scheme, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported scheme %q (supported: %s)", raw, strings.Join(Schemes, ", "))
+}
+
+// Encode encodes data with scheme. base64 output is padded; base64url
+// output is not, as in JWTs and most URL-embedded tokens.
+func Encode(scheme string, data []byte) (string, error) {
+	switch scheme {
+	case Base64:
+		return base64.StdEncoding.EncodeToString(data), nil
+	case Base64URL:
+		return base64.RawURLEncoding.EncodeToString(data), nil
+	case Hex:
+		return hex.EncodeToString(data), nil
+	case URL:
+		return url.QueryEscape(string(data)), nil
+	case URLPath:
+		return url.PathEscape(string(data)), nil
+	}
+	return "", fmt.Errorf("unsupported scheme %q", scheme)
+}
+
+// Decode decodes text encoded with scheme. For base64 and hex, whitespace
+// anywhere is ignored so wrapped input decodes, and base64 padding is
+// optional. For the URL schemes, surrounding whitespace is ignored.
+func Decode(scheme string, text []byte) ([]byte, error) {
+	var (
+		out []byte
+		err error
+	)
+	switch scheme {
+	case Base64:
+		out, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(stripSpace(text), "="))
+	case Base64URL:
+		out, err = base64.RawURLEncoding.DecodeString(strings.TrimRight(stripSpace(text), "="))
+	case Hex:
+		out, err = hex.DecodeString(stripSpace(text))
+	case URL:
+		var s string
+		s, err = url.QueryUnescape(strings.TrimSpace(string(text)))
+		out = []byte(s)
+	case URLPath:
+		var s string
+		s, err = url.PathUnescape(strings.TrimSpace(string(text)))
+		out = []byte(s)
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q", scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("decode %s: %w", scheme, err)
+	}
+	return out, nil
+}
+
+func stripSpace(text []byte) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return -1
+		}
+		return r
+	}, string(text))
+}
+
+// ReadInput returns the input for a transform: args joined by spaces, or
+// the contents of file, or stdin when neither is given. file "-" also
+// means stdin.
+func ReadInput(stdin io.Reader, args []string, file string) ([]byte, error) {
+	switch {
+	case len(args) > 0 && file != "":
+		return nil, errors.New("pass text arguments or --file, not both")
+	case len(args) > 0:
+		return []byte(strings.Join(args, " ")), nil
+	case file != "" && file != "-":
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("read input: %w", err)
+		}
+		return data, nil
+	}
+	data, err := io.ReadAll(stdin)
+	if err != nil {
+		return nil, fmt.Errorf("read stdin: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,113 @@
+package codec
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		scheme string
+		in     string
+		want   string
+	}{
+		{Base64, "hello?>", "aGVsbG8/Pg=="},
+		{Base64URL, "hello?>", "aGVsbG8_Pg"},
+		{Hex, "hi\n", "68690a"},
+		{URL, "a b&c=d/é", "a+b%26c%3Dd%2F%C3%A9"},
+		{URLPath, "a b&c=d/é", "a%20b&c=d%2F%C3%A9"},
+		{Base64, "", ""},
+	}
+	for _, tt := range tests {
+		got, err := Encode(tt.scheme, []byte(tt.in))
+		if err != nil || got != tt.want {
+			t.Errorf("Encode(%s, %q) = %q, %v, want %q", tt.scheme, tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		scheme string
+		in     string
+		want   string
+	}{
+		{Base64, "aGVsbG8/Pg==", "hello?>"},
+		{Base64, "aGVsbG8/Pg", "hello?>"},
+		{Base64, "aGVs\nbG8/\r\nPg==\n", "hello?>"},
+		{Base64URL, "aGVsbG8_Pg", "hello?>"},
+		{Base64URL, "aGVsbG8_Pg==", "hello?>"},
+		{Hex, "68 69 0A\n", "hi\n"},
+		{URL, "a+b%26c%3Dd%2F%C3%A9\n", "a b&c=d/é"},
+		{URLPath, "a%20b+c", "a b+c"},
+	}
+	for _, tt := range tests {
+		got, err := Decode(tt.scheme, []byte(tt.in))
+		if err != nil || string(got) != tt.want {
+			t.Errorf("Decode(%s, %q) = %q, %v, want %q", tt.scheme, tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		scheme string
+		in     string
+		want   string
+	}{
+		{Base64, "aGVsbG8_Pg", "decode base64: illegal base64 data"},
+		{Base64URL, "aGVsbG8/Pg", "decode base64url: illegal base64 data"},
+		{Hex, "6g", "decode hex: encoding/hex: invalid byte"},
+		{Hex, "686", "decode hex: encoding/hex: odd length"},
+		{URL, "%zz", "decode url: invalid URL escape"},
+	}
+	for _, tt := range tests {
+		if _, err := Decode(tt.scheme, []byte(tt.in)); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Decode(%s, %q) error = %v, want %q", tt.scheme, tt.in, err, tt.want)
+		}
+	}
+}
+
+func TestParseScheme(t *testing.T) {
+	if got, err := ParseScheme("Base64URL"); err != nil || got != Base64URL {
+		t.Errorf("ParseScheme(Base64URL) = %q, %v", got, err)
+	}
+	if _, err := ParseScheme("rot13"); err == nil || !strings.Contains(err.Error(), `unsupported scheme "rot13"`) {
+		t.Errorf("ParseScheme(rot13) error = %v", err)
+	}
+}
+
+func TestReadInput(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "in.bin")
+	if err := os.WriteFile(file, []byte("from file"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		args []string
+		file string
+		want string
+	}{
+		{"args", []string{"a", "b"}, "", "a b"},
+		{"file", nil, file, "from file"},
+		{"stdin", nil, "", "from stdin\n"},
+		{"dash", nil, "-", "from stdin\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ReadInput(strings.NewReader("from stdin\n"), tt.args, tt.file)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("ReadInput() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+
+	if _, err := ReadInput(strings.NewReader(""), []string{"a"}, file); err == nil {
+		t.Error("ReadInput() with args and file succeeded")
+	}
+	if _, err := ReadInput(strings.NewReader(""), nil, file+".missing"); err == nil || !strings.Contains(err.Error(), "read input") {
+		t.Errorf("ReadInput(missing) error = %v", err)
+	}
+}
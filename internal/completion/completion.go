@@ -0,0 +1,104 @@
+// Package completion provides the shell completion functions shared by
+// ado commands. Cobra serves them for bash, zsh, fish, and PowerShell via
+// `ado completion SHELL`.
+package completion
+
+import (
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	internalconfig "github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/inventory"
+	"github.com/anowarislam/ado/internal/secrets"
+	"github.com/anowarislam/ado/internal/tasks"
+)
+
+// OutputFormats are the values accepted by -o/--output on most commands.
+var OutputFormats = []string{"text", "json", "yaml"}
+
+// LogLevels are the values accepted by --log-level.
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// Fixed completes one of choices and never falls back to file names.
+func Fixed(choices ...string) cobra.CompletionFunc {
+	return cobra.FixedCompletions(choices, cobra.ShellCompDirectiveNoFileComp)
+}
+
+// RegisterOutputFlags walks the command tree under root and completes
+// OutputFormats for every "output" flag that has no completion yet.
+// Commands with other formats register their own before this runs.
+//
+// It runs on every invocation, so it looks at each command's own flag sets
+// instead of cobra's merged views, which copy all flags per command.
+func RegisterOutputFlags(root *cobra.Command) {
+	if flag := root.Flags().Lookup("output"); flag != nil && root.PersistentFlags().Lookup("output") == nil {
+		if _, ok := root.GetFlagCompletionFunc("output"); !ok {
+			_ = root.RegisterFlagCompletionFunc("output", Fixed(OutputFormats...))
+		}
+	}
+	for _, sub := range root.Commands() {
+		RegisterOutputFlags(sub)
+	}
+}
+
+// TaskNames completes task names from the config file selected by the
+// root --config flag, with their descriptions.
+func TaskNames(cmd *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	configPath, _ := cmd.Root().PersistentFlags().GetString("config")
+	homeDir, _ := os.UserHomeDir()
+	cfg, _, err := internalconfig.PathResolverFor(cmd.Context(), configPath, homeDir).Load()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var names []cobra.Completion
+	for _, s := range tasks.List(cfg.Tasks) {
+		names = append(names, cobra.CompletionWithDesc(s.Name, s.Description))
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
+
+// InventoryHosts completes the group and host names of the inventory
+// named by the command's flag, or the default inventory when it is unset.
+func InventoryHosts(flag string) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		file, _ := cmd.Flags().GetString(flag)
+		homeDir, _ := os.UserHomeDir()
+		inv, err := inventory.Load(inventory.Path(file, homeDir))
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		var names []cobra.Completion
+		for _, name := range slices.Sorted(maps.Keys(inv.Groups)) {
+			names = append(names, cobra.CompletionWithDesc(name, "group"))
+		}
+		for _, h := range inv.All() {
+			names = append(names, cobra.CompletionWithDesc(h.Name, h.Destination()))
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// SecretNames completes the names of secrets in store. Values are never
+// read.
+func SecretNames(store secrets.Store) cobra.CompletionFunc {
+	return func(*cobra.Command, []string, string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		names, err := store.List()
+		if err != nil {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// FirstArg applies fn to the first positional argument only.
+func FirstArg(fn cobra.CompletionFunc) cobra.CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]cobra.Completion, cobra.ShellCompDirective) {
+		if len(args) > 0 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		return fn(cmd, args, toComplete)
+	}
+}
@@ -0,0 +1,79 @@
+package completion
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/secrets"
+)
+
+func TestRegisterOutputFlags(t *testing.T) {
+	root := &cobra.Command{Use: "ado"}
+	plain := &cobra.Command{Use: "plain"}
+	plain.Flags().StringP("output", "o", "text", "")
+	custom := &cobra.Command{Use: "custom"}
+	custom.Flags().StringP("output", "o", "text", "")
+	_ = custom.RegisterFlagCompletionFunc("output", Fixed("text", "table"))
+	parent := &cobra.Command{Use: "parent"}
+	nested := &cobra.Command{Use: "nested"}
+	nested.Flags().String("output", "", "")
+	none := &cobra.Command{Use: "none"}
+	parent.AddCommand(nested)
+	root.AddCommand(plain, custom, parent, none)
+
+	RegisterOutputFlags(root)
+
+	tests := []struct {
+		cmd  *cobra.Command
+		want []string
+	}{
+		{plain, OutputFormats},
+		{custom, []string{"text", "table"}},
+		{nested, OutputFormats},
+	}
+	for _, tt := range tests {
+		t.Run(tt.cmd.Name(), func(t *testing.T) {
+			fn, ok := tt.cmd.GetFlagCompletionFunc("output")
+			if !ok {
+				t.Fatal("no completion registered for --output")
+			}
+			got, directive := fn(tt.cmd, nil, "")
+			if !slices.Equal(got, tt.want) || directive != cobra.ShellCompDirectiveNoFileComp {
+				t.Errorf("completions = %v (%v), want %v", got, directive, tt.want)
+			}
+		})
+	}
+
+	if _, ok := none.GetFlagCompletionFunc("output"); ok {
+		t.Error("command without --output got a completion")
+	}
+}
+
+type failingStore struct{ secrets.Map }
+
+func (failingStore) List() ([]string, error) { return nil, errors.New("locked") }
+
+func TestSecretNames(t *testing.T) {
+	got, _ := SecretNames(secrets.Map{"b": "2", "a": "1"})(nil, nil, "")
+	if !slices.Equal(got, []string{"a", "b"}) {
+		t.Errorf("SecretNames() = %v, want [a b]", got)
+	}
+
+	got, directive := SecretNames(failingStore{})(nil, nil, "")
+	if got != nil || directive != cobra.ShellCompDirectiveNoFileComp {
+		t.Errorf("SecretNames() on error = %v (%v), want nothing", got, directive)
+	}
+}
+
+func TestFirstArg(t *testing.T) {
+	fn := FirstArg(Fixed("x"))
+	if got, _ := fn(nil, nil, ""); !slices.Equal(got, []string{"x"}) {
+		t.Errorf("first arg = %v, want [x]", got)
+	}
+	if got, _ := fn(nil, []string{"x"}, ""); got != nil {
+		t.Errorf("second arg = %v, want nothing", got)
+	}
+}
@@ -0,0 +1,266 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the parsed ado configuration file.
+type Config struct {
+	Version   int             `yaml:"version"`
+	Updates   UpdatesConfig   `yaml:"updates"`
+	Features  map[string]bool `yaml:"features"`
+	Tasks     map[string]Task `yaml:"tasks"`
+	Schedules []Schedule      `yaml:"schedules"`
+	// Aliases maps a name to the ado arguments it stands for, such as
+	// sys: "meta system --output json".
+	Aliases map[string]string `yaml:"aliases"`
+	// Defaults holds flag values per command, keyed by command names, such
+	// as meta: {system: {sections: [cpu, memory]}}. Flags given on the
+	// command line win.
+	Defaults map[string]any `yaml:"defaults"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+	DevOps   DevOpsConfig   `yaml:"devops"`
+	// Hooks are commands run before or after ado commands, by name.
+	Hooks map[string]Hook `yaml:"hooks"`
+}
+
+// DevOpsConfig holds the defaults for the ado devops commands.
+type DevOpsConfig struct {
+	// URL is the Azure DevOps host; defaults to https://dev.azure.com.
+	// Azure DevOps Server users set their collection URL.
+	URL          string `yaml:"url"`
+	Organization string `yaml:"organization"`
+	Project      string `yaml:"project"`
+	// Token is the personal access token, normally a secret://NAME
+	// reference; defaults to secret://azure-devops-pat.
+	Token string `yaml:"token"`
+}
+
+// MetricsConfig controls metric emission for command and task runs.
+type MetricsConfig struct {
+	StatsD StatsDConfig `yaml:"statsd"`
+}
+
+// StatsDConfig points metric emission at a StatsD or DogStatsD agent.
+type StatsDConfig struct {
+	// Address is the agent's host:port, such as 127.0.0.1:8125. Metrics
+	// are only sent when it is set.
+	Address string `yaml:"address"`
+	// Prefix is prepended to metric names; defaults to "ado.".
+	Prefix string `yaml:"prefix"`
+	// Tags are added to every metric as DogStatsD tags.
+	Tags map[string]string `yaml:"tags"`
+}
+
+// UpdatesConfig controls the background update availability check.
+type UpdatesConfig struct {
+	// Check enables a non-blocking check for newer releases, at most once per day.
+	Check bool `yaml:"check"`
+	// Channel is the release channel to track: stable (default) or prerelease.
+	Channel string `yaml:"channel"`
+}
+
+// Task is a named command run by `ado run`.
+type Task struct {
+	// Command is the executable to run, looked up on PATH. It is not
+	// passed through a shell.
+	Command string            `json:"command" yaml:"command"`
+	Args    []string          `json:"args,omitempty" yaml:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	// Cwd is the working directory; relative paths are resolved against
+	// the directory containing the config file.
+	Cwd         string `json:"cwd,omitempty" yaml:"cwd,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	// Artifacts are globs of the files, relative to the working directory,
+	// saved with the task's output when ado run saves artifacts.
+	Artifacts []string `json:"artifacts,omitempty" yaml:"artifacts,omitempty"`
+	// EnvPolicy decides which of ado's environment variables the task
+	// inherits: inherit (default) all of them, clean only those a process
+	// needs to run, or allowlist those and the ones matching EnvAllow.
+	// Env is set on top in every case.
+	EnvPolicy string `json:"env_policy,omitempty" yaml:"env_policy,omitempty"`
+	// EnvAllow lists the names, or path.Match globs of names, of the
+	// variables an allowlist task inherits.
+	EnvAllow []string `json:"env_allow,omitempty" yaml:"env_allow,omitempty"`
+}
+
+// Environment policies of a task.
+const (
+	EnvInherit   = "inherit"
+	EnvClean     = "clean"
+	EnvAllowlist = "allowlist"
+)
+
+// envPolicies lists valid values for tasks.*.env_policy.
+var envPolicies = map[string]bool{EnvInherit: true, EnvClean: true, EnvAllowlist: true}
+
+// Hook phases: whether a hook runs before or after a command.
+const (
+	HookPre  = "pre"
+	HookPost = "post"
+)
+
+// Failure policies of a hook.
+const (
+	// HookFail stops a command before it runs, or makes it fail after it
+	// succeeded.
+	HookFail = "fail"
+	// HookWarn prints a warning and carries on.
+	HookWarn = "warn"
+	// HookIgnore carries on silently.
+	HookIgnore = "ignore"
+)
+
+// DefaultHookTimeout stops a hook without a timeout.
+const DefaultHookTimeout = 30 * time.Second
+
+// hookPolicies lists valid values for hooks.*.on_failure.
+var hookPolicies = map[string]bool{HookFail: true, HookWarn: true, HookIgnore: true}
+
+// Hook is a command ado runs before or after the commands it applies to,
+// such as a VPN check before remote commands or a notification after
+// long runs. It is not passed through a shell.
+type Hook struct {
+	// When is pre, to run before the command, or post, after it.
+	When string `json:"when" yaml:"when"`
+	// Commands are the command paths the hook applies to, such as remote
+	// or "remote exec", each including the commands below it. Empty
+	// applies to every command.
+	Commands []string          `json:"commands,omitempty" yaml:"commands,omitempty"`
+	Command  string            `json:"command" yaml:"command"`
+	Args     []string          `json:"args,omitempty" yaml:"args,omitempty"`
+	Env      map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	// Timeout stops the hook after this long; defaults to
+	// DefaultHookTimeout.
+	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// OnFailure decides what a failing hook does: fail, warn, or ignore.
+	// Defaults to fail for pre hooks and warn for post hooks.
+	OnFailure string `json:"on_failure,omitempty" yaml:"on_failure,omitempty"`
+}
+
+// FailurePolicy returns the hook's on_failure, or its default.
+func (h Hook) FailurePolicy() string {
+	switch {
+	case h.OnFailure != "":
+		return h.OnFailure
+	case h.When == HookPost:
+		return HookWarn
+	default:
+		return HookFail
+	}
+}
+
+// Overlap policies for a schedule whose previous run is still going.
+const (
+	OverlapSkip  = "skip"
+	OverlapQueue = "queue"
+	OverlapAllow = "allow"
+)
+
+// overlapPolicies lists valid values for schedules[].overlap.
+var overlapPolicies = map[string]bool{OverlapSkip: true, OverlapQueue: true, OverlapAllow: true}
+
+// Schedule runs a task on a cron expression under `ado schedule run`.
+type Schedule struct {
+	// Name identifies the schedule in logs; defaults to the task name.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	Task string `json:"task" yaml:"task"`
+	// Cron is a standard 5-field expression (optionally prefixed with
+	// CRON_TZ=Zone) or a descriptor such as @daily or @every 10m.
+	Cron string `json:"cron" yaml:"cron"`
+	// Jitter delays each run by a random duration up to this value so
+	// many hosts on the same schedule don't fire at once.
+	Jitter time.Duration `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+	// Overlap decides what happens when a run is due while the previous
+	// one is still going: skip (default), queue, or allow.
+	Overlap string `json:"overlap,omitempty" yaml:"overlap,omitempty"`
+}
+
+// updateChannels lists valid values for updates.channel.
+var updateChannels = map[string]bool{"stable": true, "prerelease": true}
+
+// Default returns the configuration used when no config file exists.
+func Default() *Config {
+	return &Config{
+		Version:  1,
+		Updates:  UpdatesConfig{Channel: "stable"},
+		Features: map[string]bool{},
+		Tasks:    map[string]Task{},
+		Aliases:  map[string]string{},
+	}
+}
+
+// Load reads the config file at path, merged with the files it
+// includes, applying defaults for unset values. References in values are
+// expanded in the mode of $ADO_CONFIG_EXPAND; see LoadMode. An empty path
+// yields Default().
+func Load(path string) (*Config, error) {
+	return LoadMode(path, DefaultExpansion())
+}
+
+// LoadMode is Load with the expansion mode of ${...} references in
+// values: ExpandOn, ExpandOff, or ExpandStrict.
+func LoadMode(path, expansion string) (*Config, error) {
+	exp, err := newExpander(expansion)
+	if err != nil {
+		return nil, err
+	}
+	cfg := Default()
+	if path == "" {
+		return cfg, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	doc, err := newDocument(path, &node, exp)
+	var expandErr *expandError
+	if errors.As(err, &expandErr) {
+		return nil, fmt.Errorf("expand config: %w", err)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	if err := doc.root.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+
+	if cfg.Updates.Channel == "" {
+		cfg.Updates.Channel = "stable"
+	}
+	if cfg.Features == nil {
+		cfg.Features = map[string]bool{}
+	}
+	if cfg.Tasks == nil {
+		cfg.Tasks = map[string]Task{}
+	}
+	if cfg.Aliases == nil {
+		cfg.Aliases = map[string]string{}
+	}
+	for i := range cfg.Schedules {
+		if cfg.Schedules[i].Name == "" {
+			cfg.Schedules[i].Name = cfg.Schedules[i].Task
+		}
+		if cfg.Schedules[i].Overlap == "" {
+			cfg.Schedules[i].Overlap = OverlapSkip
+		}
+	}
+	return cfg, nil
+}
+
+// LoadResolved loads the config from explicitPath, $ADO_CONFIG, or the
+// default search paths, in that order. It returns the path used (empty
+// when none was found).
+func LoadResolved(explicitPath, homeDir string) (*Config, string, error) {
+	return NewPathResolver(explicitPath, homeDir).Load()
+}
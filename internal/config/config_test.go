@@ -0,0 +1,148 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name        string
+		content     string
+		wantCheck   bool
+		wantChannel string
+		wantErr     bool
+	}{
+		{name: "minimal", content: "version: 1\n", wantChannel: "stable"},
+		{name: "updates enabled", content: "version: 1\nupdates:\n  check: true\n", wantCheck: true, wantChannel: "stable"},
+		{name: "prerelease channel", content: "version: 1\nupdates:\n  check: true\n  channel: prerelease\n", wantCheck: true, wantChannel: "prerelease"},
+		{name: "invalid yaml", content: "version: [\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "config.yaml")
+			if err := os.WriteFile(path, []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			cfg, err := Load(path)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Load() expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Updates.Check != tt.wantCheck {
+				t.Errorf("Updates.Check = %v, want %v", cfg.Updates.Check, tt.wantCheck)
+			}
+			if cfg.Updates.Channel != tt.wantChannel {
+				t.Errorf("Updates.Channel = %q, want %q", cfg.Updates.Channel, tt.wantChannel)
+			}
+		})
+	}
+}
+
+func TestLoad_Tasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "version: 1\ntasks:\n  test:\n    command: go\n    args: [test, ./...]\n    env:\n      GOFLAGS: -count=1\n    cwd: ..\n    description: Run tests\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := Task{
+		Command:     "go",
+		Args:        []string{"test", "./..."},
+		Env:         map[string]string{"GOFLAGS": "-count=1"},
+		Cwd:         "..",
+		Description: "Run tests",
+	}
+	if got := cfg.Tasks["test"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("Tasks[test] = %+v, want %+v", got, want)
+	}
+
+	empty, err := Load("")
+	if err != nil || empty.Tasks == nil {
+		t.Errorf("Load(\"\").Tasks = %v, %v; want empty map", empty.Tasks, err)
+	}
+}
+
+func TestLoad_Schedules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "version: 1\nschedules:\n  - task: backup\n    cron: \"0 3 * * *\"\n    jitter: 90s\n  - name: hourly-sync\n    task: sync\n    cron: \"@hourly\"\n    overlap: queue\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []Schedule{
+		{Name: "backup", Task: "backup", Cron: "0 3 * * *", Jitter: 90 * time.Second, Overlap: OverlapSkip},
+		{Name: "hourly-sync", Task: "sync", Cron: "@hourly", Overlap: OverlapQueue},
+	}
+	if !reflect.DeepEqual(cfg.Schedules, want) {
+		t.Errorf("Schedules = %+v, want %+v", cfg.Schedules, want)
+	}
+}
+
+func TestLoad_EmptyPathReturnsDefault(t *testing.T) {
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if cfg.Version != 1 || cfg.Updates.Check || cfg.Updates.Channel != "stable" {
+		t.Errorf("Load(\"\") = %+v, want defaults", cfg)
+	}
+}
+
+func TestLoad_MissingFile(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load() expected error for missing explicit file")
+	}
+}
+
+func TestLoadResolved(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	envPath := filepath.Join(t.TempDir(), "env.yaml")
+	if err := os.WriteFile(envPath, []byte("version: 1\nupdates:\n  check: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("env var", func(t *testing.T) {
+		t.Setenv("ADO_CONFIG", envPath)
+		cfg, path, err := LoadResolved("", home)
+		if err != nil {
+			t.Fatalf("LoadResolved() error = %v", err)
+		}
+		if path != envPath || !cfg.Updates.Check {
+			t.Errorf("LoadResolved() = %+v, %q", cfg, path)
+		}
+	})
+
+	t.Run("nothing found", func(t *testing.T) {
+		t.Setenv("ADO_CONFIG", "")
+		cfg, path, err := LoadResolved("", home)
+		if err != nil {
+			t.Fatalf("LoadResolved() error = %v", err)
+		}
+		if path != "" || cfg.Updates.Check {
+			t.Errorf("LoadResolved() = %+v, %q, want defaults", cfg, path)
+		}
+	})
+}
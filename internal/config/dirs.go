@@ -0,0 +1,119 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+)
+
+// Dirs holds the per-user directories ado writes to. A field is empty when
+// its location cannot be determined (e.g. neither the XDG variable nor
+// $HOME is set).
+type Dirs struct {
+	// Cache holds data that is safe to delete (update checks).
+	Cache string `json:"cache" yaml:"cache"`
+	// State holds data that should survive restarts but is not worth
+	// syncing between machines: run history, audit logs, snapshots.
+	State string `json:"state" yaml:"state"`
+	// Data holds persistent user data.
+	Data string `json:"data" yaml:"data"`
+	// Log holds log files; it lives under State.
+	Log string `json:"log" yaml:"log"`
+	// Plugin holds installed plugins; it lives under Data.
+	Plugin string `json:"plugin" yaml:"plugin"`
+}
+
+// ResolveDirs resolves ado's directories following the XDG Base Directory
+// spec ($XDG_CACHE_HOME, $XDG_STATE_HOME, $XDG_DATA_HOME, honored on every
+// platform but Windows), with ~/Library on macOS and
+// %LocalAppData%/%AppData% on Windows as defaults.
+func ResolveDirs() Dirs {
+	return defaultDirResolver().resolve()
+}
+
+// StateDir returns ado's state directory ($XDG_STATE_HOME/ado by default).
+func StateDir() (string, error) {
+	if dir := ResolveDirs().State; dir != "" {
+		return dir, nil
+	}
+	return "", errors.New("state directory: neither $XDG_STATE_HOME nor $HOME is defined")
+}
+
+// DataDir returns ado's data directory ($XDG_DATA_HOME/ado by default).
+func DataDir() (string, error) {
+	if dir := ResolveDirs().Data; dir != "" {
+		return dir, nil
+	}
+	return "", errors.New("data directory: neither $XDG_DATA_HOME nor $HOME is defined")
+}
+
+// dirResolver resolves base directories for a given platform so tests can
+// exercise macOS and Windows rules anywhere.
+type dirResolver struct {
+	goos    string
+	homeDir string
+	getenv  func(string) string
+}
+
+func defaultDirResolver() dirResolver {
+	homeDir, _ := os.UserHomeDir()
+	return dirResolver{goos: runtime.GOOS, homeDir: homeDir, getenv: os.Getenv}
+}
+
+func (r dirResolver) resolve() Dirs {
+	state, data := r.stateDir(), r.dataDir()
+	return Dirs{
+		Cache:  r.cacheDir(),
+		State:  state,
+		Data:   data,
+		Log:    subDir(state, "logs"),
+		Plugin: subDir(data, "plugins"),
+	}
+}
+
+// base returns $xdgVar when set (outside Windows), otherwise the platform
+// default for that kind of directory.
+func (r dirResolver) base(xdgVar, windowsVar, darwinRel, unixRel string) string {
+	if r.goos == "windows" {
+		return r.getenv(windowsVar)
+	}
+	if dir := r.getenv(xdgVar); dir != "" {
+		return dir
+	}
+	if r.homeDir == "" {
+		return ""
+	}
+	if r.goos == "darwin" {
+		return filepath.Join(r.homeDir, darwinRel)
+	}
+	return filepath.Join(r.homeDir, unixRel)
+}
+
+func (r dirResolver) cacheDir() string {
+	return subDir(r.base("XDG_CACHE_HOME", "LocalAppData", "Library/Caches", ".cache"), "ado")
+}
+
+func (r dirResolver) stateDir() string {
+	switch {
+	case r.goos == "windows":
+		// Kept apart from the cache, which is %LocalAppData%\ado itself.
+		return subDir(r.cacheDir(), "state")
+	case r.goos == "darwin" && r.getenv("XDG_STATE_HOME") == "":
+		// macOS has no state directory; keep it apart from data.
+		return subDir(r.dataDir(), "state")
+	}
+	return subDir(r.base("XDG_STATE_HOME", "", "", ".local/state"), "ado")
+}
+
+func (r dirResolver) dataDir() string {
+	return subDir(r.base("XDG_DATA_HOME", "AppData", "Library/Application Support", ".local/share"), "ado")
+}
+
+// subDir joins name onto dir, or returns "" when dir is unknown.
+func subDir(dir, name string) string {
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, name)
+}
@@ -0,0 +1,96 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDirResolver(t *testing.T) {
+	tests := []struct {
+		name    string
+		goos    string
+		homeDir string
+		env     map[string]string
+		want    Dirs
+	}{
+		{
+			name:    "linux defaults",
+			goos:    "linux",
+			homeDir: "/home/u",
+			want: Dirs{
+				Cache:  "/home/u/.cache/ado",
+				State:  "/home/u/.local/state/ado",
+				Data:   "/home/u/.local/share/ado",
+				Log:    "/home/u/.local/state/ado/logs",
+				Plugin: "/home/u/.local/share/ado/plugins",
+			},
+		},
+		{
+			name:    "linux XDG overrides",
+			goos:    "linux",
+			homeDir: "/home/u",
+			env:     map[string]string{"XDG_CACHE_HOME": "/c", "XDG_STATE_HOME": "/s", "XDG_DATA_HOME": "/d"},
+			want:    Dirs{Cache: "/c/ado", State: "/s/ado", Data: "/d/ado", Log: "/s/ado/logs", Plugin: "/d/ado/plugins"},
+		},
+		{
+			name:    "darwin defaults",
+			goos:    "darwin",
+			homeDir: "/Users/u",
+			want: Dirs{
+				Cache:  "/Users/u/Library/Caches/ado",
+				State:  "/Users/u/Library/Application Support/ado/state",
+				Data:   "/Users/u/Library/Application Support/ado",
+				Log:    "/Users/u/Library/Application Support/ado/state/logs",
+				Plugin: "/Users/u/Library/Application Support/ado/plugins",
+			},
+		},
+		{
+			name: "windows ignores XDG",
+			goos: "windows",
+			env:  map[string]string{"LocalAppData": "/w/Local", "AppData": "/w/Roaming", "XDG_DATA_HOME": "/ignored"},
+			want: Dirs{
+				Cache:  "/w/Local/ado",
+				State:  "/w/Local/ado/state",
+				Data:   "/w/Roaming/ado",
+				Log:    "/w/Local/ado/state/logs",
+				Plugin: "/w/Roaming/ado/plugins",
+			},
+		},
+		{
+			name: "no home",
+			goos: "linux",
+			env:  map[string]string{"XDG_STATE_HOME": "/s"},
+			want: Dirs{State: "/s/ado", Log: "/s/ado/logs"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := dirResolver{goos: tt.goos, homeDir: tt.homeDir, getenv: func(k string) string { return tt.env[k] }}
+			got := r.resolve()
+			want := Dirs{
+				Cache:  filepath.FromSlash(tt.want.Cache),
+				State:  filepath.FromSlash(tt.want.State),
+				Data:   filepath.FromSlash(tt.want.Data),
+				Log:    filepath.FromSlash(tt.want.Log),
+				Plugin: filepath.FromSlash(tt.want.Plugin),
+			}
+			if got != want {
+				t.Errorf("resolve() = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestStateAndDataDir(t *testing.T) {
+	state, data := t.TempDir(), t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	t.Setenv("XDG_DATA_HOME", data)
+
+	if got, err := StateDir(); err != nil || got != filepath.Join(state, "ado") {
+		t.Errorf("StateDir() = %q, %v", got, err)
+	}
+	if got, err := DataDir(); err != nil || got != filepath.Join(data, "ado") {
+		t.Errorf("DataDir() = %q, %v", got, err)
+	}
+}
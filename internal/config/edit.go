@@ -0,0 +1,159 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SetFeature persists a feature toggle under `features:` in the config file
+// at path, creating the file (and its directory) if needed. Other content,
+// including comments, is preserved.
+func SetFeature(path, name string, enabled bool) error {
+	value := "false"
+	if enabled {
+		value = "true"
+	}
+	return setMappingValue(path, []string{"features", name}, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: value})
+}
+
+// SetAlias persists an alias under `aliases:` in the config file at path,
+// creating the file if needed and replacing an alias of the same name.
+func SetAlias(path, name, expansion string) error {
+	return setMappingValue(path, []string{"aliases", name}, &yaml.Node{Kind: yaml.ScalarNode, Value: expansion})
+}
+
+// RemoveAlias deletes an alias from the config file at path, dropping the
+// `aliases:` section when it becomes empty. It reports whether the alias
+// existed.
+func RemoveAlias(path, name string) (bool, error) {
+	doc, err := readDocument(path)
+	if err != nil {
+		return false, err
+	}
+	root := doc.Content[0]
+	aliases := mappingValue(root, "aliases")
+	if aliases == nil || aliases.Kind != yaml.MappingNode || !deleteMappingKey(aliases, name) {
+		return false, nil
+	}
+	if len(aliases.Content) == 0 {
+		deleteMappingKey(root, "aliases")
+	}
+	return true, writeDocument(path, doc)
+}
+
+// setMappingValue sets the value at keys (a path of nested mapping keys),
+// creating intermediate mappings as required.
+func setMappingValue(path string, keys []string, value *yaml.Node) error {
+	doc, err := readDocument(path)
+	if err != nil {
+		return err
+	}
+
+	node := doc.Content[0]
+	for i, key := range keys {
+		last := i == len(keys)-1
+		child := mappingValue(node, key)
+		switch {
+		case last && child != nil:
+			*child = *value
+		case last:
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, value)
+		case child == nil || child.Kind != yaml.MappingNode:
+			mapping := &yaml.Node{Kind: yaml.MappingNode}
+			if child != nil {
+				*child = *mapping
+				mapping = child
+			} else {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: key}, mapping)
+			}
+			node = mapping
+		default:
+			node = child
+		}
+	}
+	return writeDocument(path, doc)
+}
+
+// readDocument parses the config file at path, treating a missing or empty
+// file as `version: 1`. The document's top level is a mapping.
+func readDocument(path string) (*yaml.Node, error) {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	if len(bytes.TrimSpace(data)) == 0 {
+		data = []byte("version: 1\n")
+	}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse config: %w", err)
+	}
+	if doc.Kind != yaml.DocumentNode || len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("parse config: top level is not a mapping")
+	}
+	return &doc, nil
+}
+
+// writeDocument encodes doc to path, creating its directory if needed.
+func writeDocument(path string, doc *yaml.Node) error {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("encode config: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create config dir: %w", err)
+	}
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+// deleteMappingKey removes key and its value from a mapping node,
+// reporting whether it was present.
+func deleteMappingKey(mapping *yaml.Node, key string) bool {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			mapping.Content = append(mapping.Content[:i], mapping.Content[i+2:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// mappingValue returns the value node for key in a mapping node.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// UserConfigPath returns the config file that user-level settings are
+// written to: the first existing default search path, or the preferred
+// (XDG) location when none exists yet.
+func UserConfigPath(homeDir string) string {
+	paths := DefaultSearchPaths(homeDir)
+	for _, candidate := range paths {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	if len(paths) == 0 {
+		return ""
+	}
+	return paths[0]
+}
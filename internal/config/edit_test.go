@@ -0,0 +1,165 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSetFeature(t *testing.T) {
+	tests := []struct {
+		name    string
+		initial string // "" = file does not exist
+		feature string
+		enabled bool
+		want    map[string]bool
+		keep    string
+	}{
+		{
+			name:    "creates file",
+			feature: "serve",
+			enabled: true,
+			want:    map[string]bool{"serve": true},
+		},
+		{
+			name:    "adds section preserving comments",
+			initial: "# my settings\nversion: 1 # pinned\n",
+			feature: "serve",
+			enabled: true,
+			want:    map[string]bool{"serve": true},
+			keep:    "# pinned",
+		},
+		{
+			name:    "updates existing toggle",
+			initial: "version: 1\nfeatures:\n  serve: true\n  mcp: true\n",
+			feature: "serve",
+			enabled: false,
+			want:    map[string]bool{"serve": false, "mcp": true},
+		},
+		{
+			name:    "replaces non-mapping features value",
+			initial: "version: 1\nfeatures: null\n",
+			feature: "mcp",
+			enabled: true,
+			want:    map[string]bool{"mcp": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "ado", "config.yaml")
+			if tt.initial != "" {
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(tt.initial), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := SetFeature(path, tt.feature, tt.enabled); err != nil {
+				t.Fatalf("SetFeature() error = %v", err)
+			}
+
+			cfg, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if cfg.Version != 1 {
+				t.Errorf("Version = %d, want 1", cfg.Version)
+			}
+			for name, want := range tt.want {
+				if got, ok := cfg.Features[name]; !ok || got != want {
+					t.Errorf("Features[%q] = %v (set %v), want %v", name, got, ok, want)
+				}
+			}
+
+			if tt.keep != "" {
+				data, _ := os.ReadFile(path)
+				if !strings.Contains(string(data), tt.keep) {
+					t.Errorf("config lost %q:\n%s", tt.keep, data)
+				}
+			}
+		})
+	}
+}
+
+func TestSetFeature_InvalidConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("- a\n- b\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetFeature(path, "serve", true); err == nil {
+		t.Error("SetFeature() expected error for non-mapping config")
+	}
+}
+
+func TestSetAlias(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ado", "config.yaml")
+	if err := SetAlias(path, "sys", "meta system --output json"); err != nil {
+		t.Fatalf("SetAlias() error = %v", err)
+	}
+	if err := SetAlias(path, "hi", "echo 'hello world'"); err != nil {
+		t.Fatalf("SetAlias() error = %v", err)
+	}
+	if err := SetAlias(path, "sys", "meta system"); err != nil {
+		t.Fatalf("SetAlias() replace error = %v", err)
+	}
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	want := map[string]string{"sys": "meta system", "hi": "echo 'hello world'"}
+	if len(cfg.Aliases) != len(want) || cfg.Aliases["sys"] != want["sys"] || cfg.Aliases["hi"] != want["hi"] {
+		t.Errorf("Aliases = %v, want %v", cfg.Aliases, want)
+	}
+}
+
+func TestRemoveAlias(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	initial := "# settings\nversion: 1\naliases:\n  sys: meta system\n  hi: echo hi\n"
+	if err := os.WriteFile(path, []byte(initial), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if found, err := RemoveAlias(path, "nope"); found || err != nil {
+		t.Errorf("RemoveAlias(nope) = %v, %v; want false, nil", found, err)
+	}
+	if found, err := RemoveAlias(path, "sys"); !found || err != nil {
+		t.Fatalf("RemoveAlias(sys) = %v, %v", found, err)
+	}
+	data, _ := os.ReadFile(path)
+	if got := string(data); !strings.Contains(got, "hi: echo hi") || strings.Contains(got, "sys") || !strings.Contains(got, "# settings") {
+		t.Errorf("config after removing sys:\n%s", got)
+	}
+
+	if found, err := RemoveAlias(path, "hi"); !found || err != nil {
+		t.Fatalf("RemoveAlias(hi) = %v, %v", found, err)
+	}
+	data, _ = os.ReadFile(path)
+	if strings.Contains(string(data), "aliases") {
+		t.Errorf("empty aliases section kept:\n%s", data)
+	}
+}
+
+func TestUserConfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	if got, want := UserConfigPath(home), filepath.Join(home, ".config", "ado", "config.yaml"); got != want {
+		t.Errorf("UserConfigPath() = %q, want %q (preferred location)", got, want)
+	}
+
+	legacy := filepath.Join(home, ".ado", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(legacy), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacy, []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := UserConfigPath(home); got != legacy {
+		t.Errorf("UserConfigPath() = %q, want existing %q", got, legacy)
+	}
+}
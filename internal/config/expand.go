@@ -0,0 +1,240 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Expansion modes for ${...} references in config values.
+const (
+	// ExpandOn replaces references, with undefined variables empty.
+	ExpandOn = "on"
+	// ExpandOff leaves values as written.
+	ExpandOff = "off"
+	// ExpandStrict makes undefined variables without a default an error.
+	ExpandStrict = "strict"
+)
+
+// ExpandEnvVar sets the expansion mode when no flag does.
+const ExpandEnvVar = "ADO_CONFIG_EXPAND"
+
+// ExpansionModes lists the valid expansion modes.
+var ExpansionModes = []string{ExpandOn, ExpandOff, ExpandStrict}
+
+// DefaultExpansion returns the mode in $ADO_CONFIG_EXPAND, or ExpandOn
+// when it is unset or not a mode.
+func DefaultExpansion() string {
+	if mode := os.Getenv(ExpandEnvVar); slices.Contains(ExpansionModes, mode) {
+		return mode
+	}
+	return ExpandOn
+}
+
+// builtins are the ${ado.NAME} references; config_dir is handled by
+// expander.reference since it depends on the file.
+var builtins = map[string]func() (string, error){
+	"os":       func() (string, error) { return runtime.GOOS, nil },
+	"arch":     func() (string, error) { return runtime.GOARCH, nil },
+	"hostname": os.Hostname,
+	"home":     os.UserHomeDir,
+	"user": func() (string, error) {
+		u, err := user.Current()
+		if err != nil {
+			return "", err
+		}
+		return u.Username, nil
+	},
+}
+
+// expander replaces ${...} references in config values:
+//
+//	${NAME}            environment variable NAME; empty when undefined
+//	${NAME:-default}   default when NAME is unset or empty
+//	${NAME:?message}   an error with message when NAME is unset or empty
+//	${ado.os}          built-in values: os, arch, hostname, home, user,
+//	                   and config_dir, the directory of the file the
+//	                   value is in
+//
+// \${ is a literal ${, and ${{ ... }} expressions, as used by CI
+// systems, are left alone.
+type expander struct {
+	strict bool
+	lookup func(string) (string, bool)
+	// undefined collects the undefined variables referenced, with where
+	// they were, when not strict.
+	undefined []undefinedVar
+}
+
+// undefinedVar is a reference to an undefined variable.
+type undefinedVar struct {
+	name string
+	file string
+	line int
+}
+
+// newExpander returns an expander for mode, or nil for ExpandOff.
+func newExpander(mode string) (*expander, error) {
+	switch mode {
+	case ExpandOff:
+		return nil, nil
+	case ExpandOn, ExpandStrict, "":
+		return &expander{strict: mode == ExpandStrict, lookup: os.LookupEnv}, nil
+	}
+	return nil, fmt.Errorf("unknown expansion mode %q (expected %s)", mode, strings.Join(ExpansionModes, ", "))
+}
+
+// expandError reports a failed reference at a line of a file.
+type expandError struct {
+	file string
+	line int
+	err  error
+}
+
+func (e *expandError) Error() string { return fmt.Sprintf("%s:%d: %v", e.file, e.line, e.err) }
+func (e *expandError) Unwrap() error { return e.err }
+
+// expand replaces the references in the string values under node, which
+// was read from file unless origin says otherwise. Keys are left alone.
+// Plain scalars lose their string tag, so ${PORT} can fill a number.
+func (e *expander) expand(node *yaml.Node, file string, origin map[*yaml.Node]string) error {
+	if f, ok := origin[node]; ok {
+		file = f
+	}
+	switch node.Kind {
+	case yaml.DocumentNode, yaml.SequenceNode:
+		for _, child := range node.Content {
+			if err := e.expand(child, file, origin); err != nil {
+				return err
+			}
+		}
+	case yaml.MappingNode:
+		for i := 1; i < len(node.Content); i += 2 {
+			if err := e.expand(node.Content[i], file, origin); err != nil {
+				return err
+			}
+		}
+	case yaml.ScalarNode:
+		if node.ShortTag() != "!!str" || !strings.Contains(node.Value, "${") {
+			return nil
+		}
+		value, err := e.string(node.Value, file, node.Line)
+		if err != nil {
+			return err
+		}
+		if node.Style == 0 {
+			node.Tag = ""
+		}
+		node.Value = value
+	}
+	return nil
+}
+
+// string expands the references in s, a value at line of file.
+func (e *expander) string(s, file string, line int) (string, error) {
+	var b strings.Builder
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			b.WriteString(s)
+			return b.String(), nil
+		}
+		if i > 0 && s[i-1] == '\\' {
+			b.WriteString(s[:i-1])
+			b.WriteString("${")
+			s = s[i+2:]
+			continue
+		}
+		if strings.HasPrefix(s[i:], "${{") {
+			end := strings.Index(s[i:], "}}")
+			if end < 0 {
+				end = len(s) - i - 2
+			}
+			b.WriteString(s[:i+end+2])
+			s = s[i+end+2:]
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return "", &expandError{file, line, fmt.Errorf("unterminated ${ in %q", s[i:])}
+		}
+		b.WriteString(s[:i])
+		value, err := e.reference(s[i+2:i+end], file, line)
+		if err != nil {
+			return "", &expandError{file, line, err}
+		}
+		b.WriteString(value)
+		s = s[i+end+1:]
+	}
+}
+
+// reference resolves the inside of ${...}.
+func (e *expander) reference(ref, file string, line int) (string, error) {
+	name, fallback, hasDefault := strings.Cut(ref, ":-")
+	var message string
+	required := false
+	if !hasDefault {
+		name, message, required = strings.Cut(ref, ":?")
+	}
+
+	var (
+		value   string
+		defined bool
+	)
+	if builtin, ok := strings.CutPrefix(name, "ado."); ok {
+		switch get := builtins[builtin]; {
+		case builtin == "config_dir":
+			value, defined = filepath.Dir(absPath(file)), true
+		case get != nil:
+			v, err := get()
+			if err != nil {
+				return "", fmt.Errorf("${%s}: %w", ref, err)
+			}
+			value, defined = v, true
+		default:
+			return "", fmt.Errorf("unknown built-in ${%s} (expected ado.os, ado.arch, ado.hostname, ado.home, ado.user, or ado.config_dir)", name)
+		}
+	} else {
+		if !validVarName(name) {
+			return "", fmt.Errorf("invalid variable name in ${%s}", ref)
+		}
+		value, defined = e.lookup(name)
+	}
+
+	switch {
+	case value != "":
+		return value, nil
+	case hasDefault:
+		return fallback, nil
+	case required:
+		if message == "" {
+			message = "required but not set"
+		}
+		return "", fmt.Errorf("%s: %s", name, message)
+	case !defined && e.strict:
+		return "", fmt.Errorf("undefined environment variable %s", name)
+	case !defined:
+		e.undefined = append(e.undefined, undefinedVar{name, file, line})
+	}
+	return value, nil
+}
+
+// validVarName reports whether name can be an environment variable
+// reference: letters, digits, and underscores, not starting with a digit.
+func validVarName(name string) bool {
+	if name == "" || (name[0] >= '0' && name[0] <= '9') {
+		return false
+	}
+	for _, r := range name {
+		if !(r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9')) {
+			return false
+		}
+	}
+	return true
+}
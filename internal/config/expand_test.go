@@ -0,0 +1,132 @@
+package config
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestExpander_String(t *testing.T) {
+	env := map[string]string{"HOST": "db.internal", "EMPTY": ""}
+	e := &expander{lookup: func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}}
+
+	tests := map[string]string{
+		"postgres://${HOST}:5432":   "postgres://db.internal:5432",
+		"${PORT:-5432}":             "5432",
+		"${EMPTY:-fallback}":        "fallback",
+		"${UNSET}x":                 "x",
+		`\${HOST}`:                  "${HOST}",
+		"${{ github.sha }} ${HOST}": "${{ github.sha }} db.internal",
+		"$HOME and $5":              "$HOME and $5",
+		"${ado.os}/${ado.arch}":     runtime.GOOS + "/" + runtime.GOARCH,
+		"${ado.config_dir}/tools":   filepath.Join(filepath.Dir(absPath("conf.d/a.yaml")), "tools"),
+	}
+	for in, want := range tests {
+		if got, err := e.string(in, "conf.d/a.yaml", 1); err != nil || got != want {
+			t.Errorf("string(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+	if len(e.undefined) != 1 || e.undefined[0].name != "UNSET" {
+		t.Errorf("undefined = %+v", e.undefined)
+	}
+
+	for in, want := range map[string]string{
+		"${HOST":             "unterminated ${",
+		"${}":                "invalid variable name",
+		"${my-var}":          "invalid variable name",
+		"${ado.nope}":        "unknown built-in ${ado.nope}",
+		"${TOKEN:?set it}":   "TOKEN: set it",
+		"${EMPTY:?}":         "EMPTY: required but not set",
+		"a ${HOST} ${2BAD}b": "invalid variable name in ${2BAD}",
+	} {
+		if _, err := e.string(in, "config.yaml", 7); err == nil || !strings.Contains(err.Error(), want) || !strings.HasPrefix(err.Error(), "config.yaml:7: ") {
+			t.Errorf("string(%q) error = %v, want %q", in, err, want)
+		}
+	}
+
+	e.strict = true
+	if _, err := e.string("${UNSET}", "config.yaml", 3); err == nil || err.Error() != "config.yaml:3: undefined environment variable UNSET" {
+		t.Errorf("strict error = %v", err)
+	}
+}
+
+func TestLoadMode(t *testing.T) {
+	t.Setenv("ADO_TEST_STATSD", "statsd.internal:8125")
+	t.Setenv("ADO_TEST_CHECK", "true")
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": `version: 1
+include: ["${ADO_TEST_PROFILE:-dev}.yaml"]
+updates:
+  check: ${ADO_TEST_CHECK}
+metrics:
+  statsd:
+    address: ${ADO_TEST_STATSD}
+    tags: {host: "${ado.hostname}"}
+tasks:
+  deploy:
+    command: deploy
+    args: ['--env=${ADO_TEST_UNSET}', "--sha=${{ github.sha }}"]
+`,
+		"dev.yaml": "aliases: {cfg: 'echo ${ado.config_dir}'}\n",
+	})
+	path := filepath.Join(dir, "config.yaml")
+
+	cfg, err := LoadMode(path, ExpandOn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Metrics.StatsD.Address != "statsd.internal:8125" || !cfg.Updates.Check || cfg.Metrics.StatsD.Tags["host"] == "" {
+		t.Errorf("metrics = %+v, updates = %+v", cfg.Metrics, cfg.Updates)
+	}
+	if args := strings.Join(cfg.Tasks["deploy"].Args, " "); args != "--env= --sha=${{ github.sha }}" {
+		t.Errorf("args = %q", args)
+	}
+	if cfg.Aliases["cfg"] != "echo "+dir {
+		t.Errorf("alias = %q, want the directory of dev.yaml", cfg.Aliases["cfg"])
+	}
+
+	if _, err := LoadMode(path, ExpandStrict); err == nil || !strings.Contains(err.Error(), "expand config: "+path+":12: undefined environment variable ADO_TEST_UNSET") {
+		t.Errorf("strict error = %v", err)
+	}
+	if _, err := LoadMode(path, ExpandOff); err == nil || !strings.Contains(err.Error(), `${ADO_TEST_PROFILE:-dev}.yaml`) {
+		t.Errorf("unexpanded include error = %v", err)
+	}
+	if _, err := LoadMode(path, "sometimes"); err == nil {
+		t.Error("LoadMode() accepted an unknown mode")
+	}
+
+	t.Setenv(ExpandEnvVar, ExpandStrict)
+	if _, err := Load(path); err == nil {
+		t.Errorf("Load() ignored $%s", ExpandEnvVar)
+	}
+}
+
+func TestValidateMode_Expansion(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": "version: 1\ninclude: [extra.yaml]\ndevops: {url: '${ADO_TEST_URL:-https://dev.azure.com}'}\n",
+		"extra.yaml":  "tasks:\n  build: {command: make, env: {CC: '${ADO_TEST_UNSET}'}}\n",
+	})
+	path := filepath.Join(dir, "config.yaml")
+
+	result, err := ValidateMode(path, ExpandOn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid || len(result.Warnings) != 1 || result.Warnings[0].Rule != RuleUndefinedVar || result.Warnings[0].File != filepath.Join(dir, "extra.yaml") || result.Warnings[0].Line != 2 {
+		t.Errorf("result = %+v", result)
+	}
+
+	result, _ = ValidateMode(path, ExpandStrict)
+	if result.Valid || len(result.Errors) != 1 || result.Errors[0].Rule != RuleExpand || result.Errors[0].Message != "undefined environment variable ADO_TEST_UNSET" {
+		t.Errorf("strict result = %+v", result)
+	}
+
+	result, _ = ValidateMode(path, ExpandOff)
+	if result.Valid || len(result.Errors) != 1 || !strings.Contains(result.Errors[0].Message, "invalid devops.url") {
+		t.Errorf("unexpanded result = %+v", result)
+	}
+}
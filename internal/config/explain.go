@@ -0,0 +1,122 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Resolution step statuses.
+const (
+	StepSelected         = "selected"
+	StepNotSet           = "not_set"
+	StepNotFound         = "not_found"
+	StepPermissionDenied = "permission_denied"
+	StepSkipped          = "skipped"
+	StepInvalid          = "invalid"
+)
+
+// ResolutionStep records how one candidate config source was evaluated.
+type ResolutionStep struct {
+	Order  int    `json:"order" yaml:"order"`
+	Source string `json:"source" yaml:"source"`
+	Path   string `json:"path,omitempty" yaml:"path,omitempty"`
+	Status string `json:"status" yaml:"status"`
+	Reason string `json:"reason" yaml:"reason"`
+}
+
+// ExplainConfigPath evaluates every config source in precedence order
+// (--config flag, $ADO_CONFIG, default search paths) and records why each
+// was or wasn't selected. It follows the same rules as ResolveConfigPath.
+func ExplainConfigPath(flagPath string, envPath string, envSet bool, homeDir string) []ResolutionStep {
+	var steps []ResolutionStep
+	selected := false
+
+	add := func(source, path, status, reason string) {
+		steps = append(steps, ResolutionStep{Order: len(steps) + 1, Source: source, Path: path, Status: status, Reason: reason})
+	}
+
+	explicit := func(source, path, unsetReason string, set bool) {
+		switch {
+		case !set || path == "":
+			add(source, path, StepNotSet, unsetReason)
+		case selected:
+			add(source, path, StepSkipped, "overridden by --config flag")
+		default:
+			selected = true
+			add(source, path, StepSelected, "explicit path takes precedence; "+describeFile(path))
+		}
+	}
+
+	explicit("--config flag", flagPath, "flag not provided", flagPath != "")
+	explicit("ADO_CONFIG", envPath, "environment variable not set", envSet)
+
+	for _, candidate := range searchPathCandidates(homeDir) {
+		if selected {
+			add(candidate.source, candidate.path, StepSkipped, "a higher-precedence source was selected")
+			continue
+		}
+
+		info, err := os.Stat(candidate.path)
+		switch {
+		case err == nil && info.IsDir():
+			add(candidate.source, candidate.path, StepInvalid, "path is a directory")
+		case err == nil:
+			selected = true
+			add(candidate.source, candidate.path, StepSelected, "first existing default path; "+describeFile(candidate.path))
+		case os.IsNotExist(err):
+			add(candidate.source, candidate.path, StepNotFound, "file does not exist")
+		case os.IsPermission(err):
+			add(candidate.source, candidate.path, StepPermissionDenied, "cannot access path (check permissions of parent directories)")
+		default:
+			add(candidate.source, candidate.path, StepInvalid, err.Error())
+		}
+	}
+
+	return steps
+}
+
+// describeFile summarizes whether a selected file can actually be loaded.
+func describeFile(path string) string {
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		return "file does not exist, so loading will fail"
+	case os.IsPermission(err):
+		return "cannot access path (permission denied)"
+	case err != nil:
+		return err.Error()
+	case info.IsDir():
+		return "path is a directory, so loading will fail"
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Sprintf("file exists but is not readable: %v", err)
+	}
+	f.Close()
+	return "file exists and is readable"
+}
+
+type searchPathCandidate struct {
+	source string
+	path   string
+}
+
+// searchPathCandidates returns the default search paths with the name of
+// the rule that produced each.
+func searchPathCandidates(homeDir string) []searchPathCandidate {
+	var candidates []searchPathCandidate
+
+	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
+		candidates = append(candidates, searchPathCandidate{"$XDG_CONFIG_HOME", filepath.Join(xdg, "ado", "config.yaml")})
+	} else if homeDir != "" {
+		candidates = append(candidates, searchPathCandidate{"~/.config (XDG default)", filepath.Join(homeDir, ".config", "ado", "config.yaml")})
+	}
+
+	if homeDir != "" {
+		candidates = append(candidates, searchPathCandidate{"~/.ado (legacy)", filepath.Join(homeDir, ".ado", "config.yaml")})
+	}
+
+	return candidates
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func stepStatuses(steps []ResolutionStep) []string {
+	statuses := make([]string, len(steps))
+	for i, s := range steps {
+		statuses[i] = s.Source + "=" + s.Status
+	}
+	return statuses
+}
+
+func TestExplainConfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	legacy := filepath.Join(home, ".ado", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(legacy), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacy, []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	explicit := filepath.Join(t.TempDir(), "explicit.yaml")
+
+	tests := []struct {
+		name     string
+		flag     string
+		env      string
+		envSet   bool
+		want     []string
+		wantPath string
+	}{
+		{
+			name: "default path found after missing xdg",
+			want: []string{
+				"--config flag=not_set",
+				"ADO_CONFIG=not_set",
+				"~/.config (XDG default)=not_found",
+				"~/.ado (legacy)=selected",
+			},
+			wantPath: legacy,
+		},
+		{
+			name:   "flag overrides env and defaults",
+			flag:   explicit,
+			env:    "/env/config.yaml",
+			envSet: true,
+			want: []string{
+				"--config flag=selected",
+				"ADO_CONFIG=skipped",
+				"~/.config (XDG default)=skipped",
+				"~/.ado (legacy)=skipped",
+			},
+			wantPath: explicit,
+		},
+		{
+			name:   "empty env var is treated as unset",
+			env:    "",
+			envSet: true,
+			want: []string{
+				"--config flag=not_set",
+				"ADO_CONFIG=not_set",
+				"~/.config (XDG default)=not_found",
+				"~/.ado (legacy)=selected",
+			},
+			wantPath: legacy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps := ExplainConfigPath(tt.flag, tt.env, tt.envSet, home)
+
+			got := stepStatuses(steps)
+			if len(got) != len(tt.want) {
+				t.Fatalf("steps = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("step %d = %q, want %q", i+1, got[i], tt.want[i])
+				}
+				if steps[i].Order != i+1 {
+					t.Errorf("step %d Order = %d", i+1, steps[i].Order)
+				}
+			}
+
+			// The trace must agree with the resolver.
+			resolveFrom := tt.flag
+			if resolveFrom == "" {
+				resolveFrom = tt.env
+			}
+			if resolved, _ := ResolveConfigPath(resolveFrom, home); resolved != tt.wantPath {
+				t.Errorf("ResolveConfigPath() = %q, want %q", resolved, tt.wantPath)
+			}
+		})
+	}
+}
+
+func TestExplainConfigPath_SelectedMissingFile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.yaml")
+	steps := ExplainConfigPath(missing, "", false, "")
+
+	if steps[0].Status != StepSelected {
+		t.Fatalf("flag step status = %q, want selected", steps[0].Status)
+	}
+	if want := "file does not exist, so loading will fail"; !contains(steps[0].Reason, want) {
+		t.Errorf("Reason = %q, want containing %q", steps[0].Reason, want)
+	}
+}
+
+func TestExplainConfigPath_DirectoryCandidate(t *testing.T) {
+	home := t.TempDir()
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	if err := os.MkdirAll(filepath.Join(xdg, "ado", "config.yaml"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	steps := ExplainConfigPath("", "", false, home)
+	if got := steps[2]; got.Source != "$XDG_CONFIG_HOME" || got.Status != StepInvalid {
+		t.Errorf("xdg step = %+v, want invalid", got)
+	}
+}
+
+func TestResolveConfigPath_SkipsDirectories(t *testing.T) {
+	home := t.TempDir()
+	xdg := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", xdg)
+	if err := os.MkdirAll(filepath.Join(xdg, "ado", "config.yaml"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, _ := ResolveConfigPath("", home); got != "" {
+		t.Errorf("ResolveConfigPath() = %q, want directory skipped", got)
+	}
+}
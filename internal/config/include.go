@@ -0,0 +1,271 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// includeKey lists the files a config file includes.
+const includeKey = "include"
+
+// document is a config file merged with the files it includes.
+type document struct {
+	// root is the merged top-level mapping; nil for an empty file.
+	root *yaml.Node
+	// files lists the included files, in merge order.
+	files []string
+	// origin maps every node to the file it was read from.
+	origin map[*yaml.Node]string
+	// exp expands references in include paths and values; nil leaves
+	// them as written.
+	exp *expander
+}
+
+// includeError reports a failed include: line is the line of the
+// include key in file, the file that names the broken one.
+type includeError struct {
+	file string
+	line int
+	err  error
+}
+
+func (e *includeError) Error() string { return e.err.Error() }
+func (e *includeError) Unwrap() error { return e.err }
+
+// newDocument merges the config file at path, parsed as node, with the
+// files it includes. Included files are merged in the order listed,
+// with glob matches sorted, and the including file is merged last, so
+// its own values win. Mappings merge key by key; the top-level schedules
+// lists are concatenated; any other value replaces the one merged before
+// it. Problems with includes are *includeError values.
+//
+// With exp, the references in include paths and then in the merged
+// values are expanded; failures are *expandError values.
+func newDocument(path string, node *yaml.Node, exp *expander) (*document, error) {
+	doc := &document{origin: map[*yaml.Node]string{}, exp: exp}
+	root, err := doc.merge(path, node, nil)
+	if err != nil {
+		return nil, err
+	}
+	if root == nil {
+		root = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	}
+	if exp != nil {
+		if err := exp.expand(root, path, doc.origin); err != nil {
+			return nil, err
+		}
+	}
+	doc.root = root
+	return doc, nil
+}
+
+// merge returns the mapping of file, parsed as node, merged with its
+// includes. stack holds the files including it, to detect cycles.
+func (d *document) merge(file string, node *yaml.Node, stack []string) (*yaml.Node, error) {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind == 0 {
+		return nil, nil
+	}
+	if node.Kind != yaml.MappingNode {
+		if len(stack) == 0 {
+			// Decoding reports it.
+			return node, nil
+		}
+		return nil, fmt.Errorf("%s: expected a mapping at the top level, got %s", file, node.ShortTag())
+	}
+	d.record(file, node)
+	if len(stack) > 0 {
+		absTaskDirs(filepath.Dir(absPath(file)), node)
+	}
+
+	index := mappingIndex(node, includeKey)
+	if index < 0 {
+		return node, nil
+	}
+	includeLine := node.Content[index].Line
+	patterns, err := includePatterns(node.Content[index+1])
+	if err != nil {
+		return nil, &includeError{file, includeLine, fmt.Errorf("%s: %w", file, err)}
+	}
+
+	stack = append(stack, absPath(file))
+	merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+	for _, pattern := range patterns {
+		if d.exp != nil {
+			if pattern, err = d.exp.string(pattern, file, includeLine); err != nil {
+				return nil, &includeError{file, includeLine, err}
+			}
+		}
+		paths, err := expandInclude(filepath.Dir(file), pattern)
+		if err != nil {
+			return nil, &includeError{file, includeLine, fmt.Errorf("%s: include %q: %w", file, pattern, err)}
+		}
+		for _, path := range paths {
+			if i := slices.Index(stack, absPath(path)); i >= 0 {
+				cycle := append(slices.Clone(stack[i:]), absPath(path))
+				return nil, &includeError{file, includeLine, fmt.Errorf("include cycle: %s", strings.Join(cycle, " -> "))}
+			}
+			data, err := os.ReadFile(path)
+			if err == nil {
+				var child yaml.Node
+				if err = yaml.Unmarshal(data, &child); err == nil {
+					var included *yaml.Node
+					included, err = d.merge(path, &child, stack)
+					if included != nil {
+						mergeMapping(merged, included, true)
+					}
+				}
+			}
+			if err != nil {
+				var inc *includeError
+				if errors.As(err, &inc) {
+					return nil, err
+				}
+				return nil, &includeError{file, includeLine, fmt.Errorf("%s: include %q: %w", file, pattern, err)}
+			}
+			if !slices.Contains(d.files, path) {
+				d.files = append(d.files, path)
+			}
+		}
+	}
+
+	own := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: node.Line, Column: node.Column}
+	own.Content = append(slices.Clone(node.Content[:index]), node.Content[index+2:]...)
+	mergeMapping(merged, own, true)
+	merged.Line, merged.Column = node.Line, node.Column
+	return merged, nil
+}
+
+// record notes file as the origin of node and everything under it.
+func (d *document) record(file string, node *yaml.Node) {
+	d.origin[node] = file
+	for _, child := range node.Content {
+		d.record(file, child)
+	}
+}
+
+// fileOf returns the file node was read from when it is not path.
+func (d *document) fileOf(node *yaml.Node, path string) string {
+	if file := d.origin[node]; file != path {
+		return file
+	}
+	return ""
+}
+
+// includePatterns returns the value of include:, a path or a list of
+// paths.
+func includePatterns(node *yaml.Node) ([]string, error) {
+	var patterns []string
+	switch node.Kind {
+	case yaml.ScalarNode:
+		patterns = []string{node.Value}
+	case yaml.SequenceNode:
+		for _, item := range node.Content {
+			if item.Kind != yaml.ScalarNode {
+				return nil, fmt.Errorf("line %d: include entries must be paths", item.Line)
+			}
+			patterns = append(patterns, item.Value)
+		}
+	default:
+		return nil, fmt.Errorf("line %d: include must be a path or a list of paths", node.Line)
+	}
+	for _, pattern := range patterns {
+		if pattern == "" {
+			return nil, errors.New("include entries must not be empty")
+		}
+	}
+	return patterns, nil
+}
+
+// expandInclude resolves pattern against dir. A glob may match nothing;
+// a plain path must exist.
+func expandInclude(dir, pattern string) ([]string, error) {
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(dir, pattern)
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+	matches, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(matches)
+	return matches, nil
+}
+
+// mergeMapping merges the mapping src into dst. Nested mappings merge
+// key by key; when top is set, sequences under the schedules key are
+// concatenated; other values in src replace those in dst, together with
+// their key so lines point at the definition that won.
+func mergeMapping(dst, src *yaml.Node, top bool) {
+	for i := 0; i+1 < len(src.Content); i += 2 {
+		key, value := src.Content[i], src.Content[i+1]
+		j := mappingIndex(dst, key.Value)
+		if j < 0 {
+			dst.Content = append(dst.Content, key, value)
+			continue
+		}
+		old := dst.Content[j+1]
+		switch {
+		case old.Kind == yaml.MappingNode && value.Kind == yaml.MappingNode:
+			merged := &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", Line: value.Line, Column: value.Column}
+			merged.Content = slices.Clone(old.Content)
+			mergeMapping(merged, value, false)
+			dst.Content[j], dst.Content[j+1] = key, merged
+		case top && key.Value == "schedules" && old.Kind == yaml.SequenceNode && value.Kind == yaml.SequenceNode:
+			merged := &yaml.Node{Kind: yaml.SequenceNode, Tag: "!!seq", Line: value.Line, Column: value.Column}
+			merged.Content = append(slices.Clone(old.Content), value.Content...)
+			dst.Content[j], dst.Content[j+1] = key, merged
+		default:
+			dst.Content[j], dst.Content[j+1] = key, value
+		}
+	}
+}
+
+// mappingIndex returns the index of key in a mapping node's content, or
+// -1.
+func mappingIndex(mapping *yaml.Node, key string) int {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// absPath returns path made absolute, or cleaned when that fails, for
+// comparing file names.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// absTaskDirs makes relative task cwd values of an included file
+// absolute against its directory, since relative ones are otherwise
+// resolved against the directory of the main config file.
+func absTaskDirs(dir string, root *yaml.Node) {
+	tasks := mappingValue(root, "tasks")
+	if tasks == nil || tasks.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 1; i < len(tasks.Content); i += 2 {
+		task := tasks.Content[i]
+		if task.Kind != yaml.MappingNode {
+			continue
+		}
+		if cwd := mappingValue(task, "cwd"); cwd != nil && cwd.Kind == yaml.ScalarNode && cwd.Value != "" && !filepath.IsAbs(cwd.Value) {
+			cwd.Value = filepath.Join(dir, cwd.Value)
+		}
+	}
+}
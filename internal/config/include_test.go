@@ -0,0 +1,163 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes files, keyed by slash-separated paths, under a
+// temporary directory and returns it.
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestLoad_Include(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": `version: 1
+include: [base.yaml, conf.d/*.yaml]
+features: {serve: true}
+tasks:
+  build: {command: make}
+schedules:
+  - {task: build, cron: "@daily"}
+`,
+		"base.yaml": "features: {serve: false, watch: true}\nupdates: {channel: prerelease}\n",
+		"conf.d/10-tasks.yaml": `tasks:
+  build: {command: go, args: [build]}
+  lint: {command: golangci-lint, cwd: tools}
+schedules:
+  - {task: lint, cron: "@hourly"}
+`,
+		"conf.d/20-empty.yaml": "",
+	})
+
+	cfg, err := Load(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Features["serve"] || !cfg.Features["watch"] || cfg.Updates.Channel != "prerelease" {
+		t.Errorf("features = %v, channel = %q", cfg.Features, cfg.Updates.Channel)
+	}
+	// The including file's own task wins, but nested mappings merge.
+	if build := cfg.Tasks["build"]; build.Command != "make" || len(build.Args) != 1 {
+		t.Errorf("build = %+v", build)
+	}
+	if lint := cfg.Tasks["lint"]; lint.Cwd != filepath.Join(dir, "conf.d", "tools") {
+		t.Errorf("lint cwd = %q", lint.Cwd)
+	}
+	if len(cfg.Schedules) != 2 || cfg.Schedules[0].Task != "lint" || cfg.Schedules[1].Task != "build" {
+		t.Errorf("schedules = %+v", cfg.Schedules)
+	}
+}
+
+func TestLoad_IncludeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{
+			name:  "missing file",
+			files: map[string]string{"config.yaml": "include: extra.yaml\n"},
+			want:  `include "extra.yaml": open `,
+		},
+		{
+			name:  "glob matching nothing",
+			files: map[string]string{"config.yaml": "include: conf.d/*.yaml\n"},
+		},
+		{
+			name: "cycle",
+			files: map[string]string{
+				"config.yaml": "include: [a.yaml]\n",
+				"a.yaml":      "include: [sub/b.yaml]\n",
+				"sub/b.yaml":  "include: [../a.yaml]\n",
+			},
+			want: "include cycle: ",
+		},
+		{
+			name: "included twice",
+			files: map[string]string{
+				"config.yaml": "include: [a.yaml, b.yaml]\n",
+				"a.yaml":      "include: [b.yaml]\n",
+				"b.yaml":      "version: 1\n",
+			},
+		},
+		{
+			name:  "not a path",
+			files: map[string]string{"config.yaml": "include: {a: b}\n"},
+			want:  "include must be a path or a list of paths",
+		},
+		{
+			name: "included list",
+			files: map[string]string{
+				"config.yaml": "include: a.yaml\n",
+				"a.yaml":      "- version: 1\n",
+			},
+			want: "expected a mapping at the top level",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeFiles(t, tt.files)
+			_, err := Load(filepath.Join(dir, "config.yaml"))
+			switch {
+			case tt.want == "" && err != nil:
+				t.Errorf("Load() error = %v", err)
+			case tt.want != "" && (err == nil || !strings.Contains(err.Error(), tt.want)):
+				t.Errorf("Load() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidate_Include(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": "version: 1\ninclude: [tasks.yaml]\nschedules:\n  - {task: build, cron: \"@daily\"}\n",
+		"tasks.yaml":  "tasks:\n  build: {command: make}\n  test: {args: [-v]}\nextra: true\n",
+	})
+	tasks := filepath.Join(dir, "tasks.yaml")
+
+	result, err := Validate(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Includes) != 1 || result.Includes[0] != tasks {
+		t.Errorf("Includes = %v", result.Includes)
+	}
+	// The schedule's task comes from the included file; the issues in it
+	// point there.
+	if len(result.Errors) != 1 || result.Errors[0].File != tasks || result.Errors[0].Line != 3 || result.Errors[0].Rule != RuleTask {
+		t.Errorf("Errors = %+v", result.Errors)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].File != tasks || result.Warnings[0].Line != 4 {
+		t.Errorf("Warnings = %+v", result.Warnings)
+	}
+
+	dir = writeFiles(t, map[string]string{"config.yaml": "version: 1\ninclude: [config.yaml]\n"})
+	result, err = Validate(filepath.Join(dir, "config.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid || len(result.Errors) != 1 || result.Errors[0].Rule != RuleInclude || result.Errors[0].Line != 2 || result.Errors[0].File != "" {
+		t.Errorf("Errors = %+v", result.Errors)
+	}
+
+	// Content without a file is validated on its own.
+	result = ValidateData("request body", []byte("version: 1\ninclude: [/etc/passwd]\n"))
+	if !result.Valid || len(result.Warnings) != 1 || result.Warnings[0].Rule != RuleInclude {
+		t.Errorf("ValidateData = %+v", result)
+	}
+}
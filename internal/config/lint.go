@@ -0,0 +1,218 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anowarislam/ado/internal/alias"
+	"github.com/anowarislam/ado/internal/process"
+)
+
+// Kinds of issue LintTasks reports besides those Validate reports.
+const (
+	RuleUndefinedTask      = "undefined-task"
+	RuleShellInterpolation = "shell-interpolation"
+	RuleShadowedTask       = "shadowed-task"
+)
+
+// LintRules lists every kind of issue LintTasks reports.
+var LintRules = append(slices.Clone(ValidationRules),
+	ValidationRule{RuleUndefinedTask, "An alias or task runs a task that is not defined", "error"},
+	ValidationRule{RuleShellInterpolation, "A ${...} reference is expanded into a shell script, where its value can inject commands", "warning"},
+	ValidationRule{RuleShadowedTask, "A task is named like a subcommand of ado run, which runs instead", "warning"},
+)
+
+// knownTaskKeys lists the valid keys of a task.
+var knownTaskKeys = map[string]bool{
+	"command":     true,
+	"args":        true,
+	"env":         true,
+	"cwd":         true,
+	"description": true,
+	"artifacts":   true,
+	"env_policy":  true,
+	"env_allow":   true,
+}
+
+// LintTasks validates the config file at path like ValidateMode, and
+// then checks its tasks the way ado run would use them: unknown task
+// keys, aliases and tasks running undefined tasks, tasks named like one
+// of subcommands, the subcommands of ado run, which run instead of them,
+// and ${...} references expanded into shell scripts.
+func LintTasks(path, expansion string, subcommands []string) (*ValidationResult, error) {
+	result, err := ValidateMode(path, expansion)
+	if err != nil {
+		return nil, err
+	}
+
+	// Problems reading or parsing the file are reported by ValidateMode.
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return result, nil
+	}
+	var node yaml.Node
+	if yaml.Unmarshal(data, &node) != nil || len(node.Content) == 0 {
+		return result, nil
+	}
+	// References are left as written, so the linter sees them.
+	doc, err := newDocument(path, &node, nil)
+	if err != nil {
+		return result, nil
+	}
+	l := &taskLinter{path: path, doc: doc, result: result, subcommands: subcommands, expand: expansion != ExpandOff}
+	l.lint()
+	return result, nil
+}
+
+// taskLinter adds the issues of LintTasks to a validation result.
+type taskLinter struct {
+	path        string
+	doc         *document
+	result      *ValidationResult
+	subcommands []string
+	// expand is set when ${...} references are expanded.
+	expand bool
+	// tasks holds the names of the defined tasks.
+	tasks map[string]bool
+}
+
+func (l *taskLinter) add(severity, rule, message string, node *yaml.Node) {
+	issue := ValidationIssue{Message: message, Severity: severity, Rule: rule}
+	if node != nil {
+		issue.Line = node.Line
+		issue.File = l.doc.fileOf(node, l.path)
+	}
+	if severity == "error" {
+		l.result.Valid = false
+		l.result.Errors = append(l.result.Errors, issue)
+	} else {
+		l.result.Warnings = append(l.result.Warnings, issue)
+	}
+}
+
+func (l *taskLinter) lint() {
+	l.tasks = map[string]bool{}
+	tasks := mappingValue(l.doc.root, "tasks")
+	if tasks != nil && tasks.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(tasks.Content); i += 2 {
+			l.tasks[tasks.Content[i].Value] = true
+		}
+		for _, i := range sortedKeyIndexes(tasks) {
+			l.lintTask(tasks.Content[i], tasks.Content[i+1])
+		}
+	}
+
+	aliases := mappingValue(l.doc.root, "aliases")
+	if aliases != nil && aliases.Kind == yaml.MappingNode {
+		for _, i := range sortedKeyIndexes(aliases) {
+			key, value := aliases.Content[i], aliases.Content[i+1]
+			args, err := alias.Split(value.Value)
+			if err != nil || value.Kind != yaml.ScalarNode {
+				continue // reported by Validate
+			}
+			if task, ok := l.runs(args); ok && !l.tasks[task] {
+				l.add("error", RuleUndefinedTask, fmt.Sprintf("aliases.%s: runs undefined task %q", key.Value, task), key)
+			}
+		}
+	}
+}
+
+func (l *taskLinter) lintTask(key, task *yaml.Node) {
+	name := key.Value
+	if slices.Contains(l.subcommands, name) {
+		l.add("warning", RuleShadowedTask, fmt.Sprintf("task %q is shadowed by `ado run %s`; run it with `ado run -- %s`", name, name, name), key)
+	}
+	if task.Kind != yaml.MappingNode {
+		return
+	}
+	for _, i := range sortedKeyIndexes(task) {
+		if k := task.Content[i]; !knownTaskKeys[k.Value] {
+			l.add("warning", RuleUnknownKey, fmt.Sprintf("task %q: unknown key %q", name, k.Value), k)
+		}
+	}
+
+	command := mappingValue(task, "command")
+	argsNode := mappingValue(task, "args")
+	if command == nil || command.Kind != yaml.ScalarNode || argsNode == nil || argsNode.Kind != yaml.SequenceNode {
+		return
+	}
+	args := make([]string, len(argsNode.Content))
+	for i, arg := range argsNode.Content {
+		args[i] = arg.Value
+	}
+
+	if filepath.Base(command.Value) == "ado" {
+		if target, ok := l.runs(args); ok && !l.tasks[target] {
+			l.add("error", RuleUndefinedTask, fmt.Sprintf("task %q: runs undefined task %q", name, target), argsNode)
+		}
+	}
+
+	if i := process.ShellScript(command.Value, args); i >= 0 && l.expand {
+		for _, ref := range envReferences(args[i]) {
+			l.add("warning", RuleShellInterpolation, fmt.Sprintf("task %q: ${%s} is expanded into the script %s runs, where its value can inject commands; use \"$%s\" to let the shell expand it", name, ref, filepath.Base(command.Value), ref), argsNode.Content[i])
+		}
+	}
+}
+
+// runs returns the task ado args runs: NAME in run NAME or run -- NAME,
+// but not a subcommand of run.
+func (l *taskLinter) runs(args []string) (string, bool) {
+	if len(args) < 2 || args[0] != "run" {
+		return "", false
+	}
+	name := args[1]
+	if name == "--" && len(args) > 2 {
+		return args[2], true
+	}
+	if strings.HasPrefix(name, "-") || slices.Contains(l.subcommands, name) {
+		return "", false
+	}
+	return name, true
+}
+
+// sortedKeyIndexes returns the indexes of the keys of a mapping node in
+// key order.
+func sortedKeyIndexes(mapping *yaml.Node) []int {
+	var indexes []int
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		indexes = append(indexes, i)
+	}
+	slices.SortStableFunc(indexes, func(a, b int) int {
+		return strings.Compare(mapping.Content[a].Value, mapping.Content[b].Value)
+	})
+	return indexes
+}
+
+// envReferences returns the environment variables referenced in s, as
+// by ${NAME} or ${NAME:-default}, in order. Built-ins and escaped
+// references are left out.
+func envReferences(s string) []string {
+	var names []string
+	for {
+		i := strings.Index(s, "${")
+		if i < 0 {
+			return names
+		}
+		if (i > 0 && s[i-1] == '\\') || strings.HasPrefix(s[i:], "${{") {
+			s = s[i+2:]
+			continue
+		}
+		end := strings.IndexByte(s[i:], '}')
+		if end < 0 {
+			return names
+		}
+		ref := s[i+2 : i+end]
+		if j := strings.Index(ref, ":"); j >= 0 {
+			ref = ref[:j]
+		}
+		if validVarName(ref) && !slices.Contains(names, ref) {
+			names = append(names, ref)
+		}
+		s = s[i+end+1:]
+	}
+}
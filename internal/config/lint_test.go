@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintTasks(t *testing.T) {
+	t.Setenv("BRANCH", "main")
+	dir := writeFiles(t, map[string]string{
+		"config.yaml": `version: 1
+include: [tasks.yaml]
+aliases:
+  ci: run test -- -v
+  ship: run deploy
+  check: run lint
+tasks:
+  test:
+    command: go
+    args: [test, ./...]
+    timeout: 5m
+  release:
+    command: ado
+    args: [run, publish]
+  lint:
+    command: golangci-lint
+`,
+		"tasks.yaml": `tasks:
+  push:
+    command: bash
+    args: [-o, pipefail, -ec, 'git push origin ${BRANCH} && echo \${HOME} ${ado.os}']
+  safe:
+    command: sh
+    args: [./push.sh, "${BRANCH}"]
+`,
+	})
+	path := filepath.Join(dir, "config.yaml")
+
+	result, err := LintTasks(path, ExpandOn, []string{"lint"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid {
+		t.Error("Valid = true, want false")
+	}
+	var got []string
+	for _, issue := range append(result.Errors, result.Warnings...) {
+		file := ""
+		if issue.File != "" {
+			file = filepath.Base(issue.File) + ":"
+		}
+		got = append(got, fmt.Sprintf("%s %s%d %s: %s", issue.Severity, file, issue.Line, issue.Rule, issue.Message))
+	}
+	want := []string{
+		`error 14 undefined-task: task "release": runs undefined task "publish"`,
+		`error 5 undefined-task: aliases.ship: runs undefined task "deploy"`,
+		`warning 15 shadowed-task: task "lint" is shadowed by ` + "`ado run lint`; run it with `ado run -- lint`",
+		`warning tasks.yaml:4 shell-interpolation: task "push": ${BRANCH} is expanded into the script bash runs, where its value can inject commands; use "$BRANCH" to let the shell expand it`,
+		`warning 11 unknown-key: task "test": unknown key "timeout"`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	// Without expansion, references reach the shell as written.
+	result, err = LintTasks(path, ExpandOff, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, w := range result.Warnings {
+		if w.Rule == RuleShellInterpolation {
+			t.Errorf("ExpandOff: %+v", w)
+		}
+	}
+}
+
+func TestLintTasks_Invalid(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"config.yaml": "version: [\n"})
+	result, err := LintTasks(filepath.Join(dir, "config.yaml"), ExpandOn, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Rule != RuleYAMLSyntax {
+		t.Errorf("Errors = %+v, want one yaml-syntax error", result.Errors)
+	}
+}
+
+func TestEnvReferences(t *testing.T) {
+	got := envReferences(`${A} ${B:-x} \${C} ${{ D }} ${ado.os} ${A} ${E:?set E}`)
+	if strings.Join(got, ",") != "A,B,E" {
+		t.Errorf("envReferences = %v, want [A B E]", got)
+	}
+}
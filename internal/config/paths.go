@@ -2,23 +2,14 @@ package config
 
 import (
 	"os"
-	"path/filepath"
 )
 
 // DefaultSearchPaths returns the default config lookup order, excluding any explicit flag value.
 func DefaultSearchPaths(homeDir string) []string {
 	var paths []string
-
-	if xdg := os.Getenv("XDG_CONFIG_HOME"); xdg != "" {
-		paths = append(paths, filepath.Join(xdg, "ado", "config.yaml"))
-	} else if homeDir != "" {
-		paths = append(paths, filepath.Join(homeDir, ".config", "ado", "config.yaml"))
-	}
-
-	if homeDir != "" {
-		paths = append(paths, filepath.Join(homeDir, ".ado", "config.yaml"))
+	for _, candidate := range searchPathCandidates(homeDir) {
+		paths = append(paths, candidate.path)
 	}
-
 	return paths
 }
 
@@ -30,7 +21,7 @@ func ResolveConfigPath(explicitPath, homeDir string) (string, []string) {
 
 	sources := DefaultSearchPaths(homeDir)
 	for _, candidate := range sources {
-		if _, err := os.Stat(candidate); err == nil {
+		if info, err := os.Stat(candidate); err == nil && !info.IsDir() {
 			return candidate, sources
 		}
 	}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"context"
+	"os"
+	"slices"
+	"sync"
+)
+
+// PathResolver memoizes ResolveConfigPath, so a command that consults the
+// config repeatedly searches the filesystem once. The root command creates
+// one before each command runs and passes it down in the context; see
+// PathResolverFor.
+//
+// The result is fixed once resolved: long-running servers that should
+// notice a config file created later keep calling LoadResolved.
+type PathResolver struct {
+	flag    string // the --config value the resolver was made for
+	homeDir string
+	path    string // flag or $ADO_CONFIG
+	// expansion is the mode of ${...} references; see LoadMode.
+	expansion string
+
+	once     sync.Once
+	resolved string
+	sources  []string
+
+	loadOnce sync.Once
+	cfg      *Config
+	err      error
+}
+
+// NewPathResolver returns a resolver for the --config value explicitPath,
+// falling back to $ADO_CONFIG and then the default search paths like
+// LoadResolved.
+func NewPathResolver(explicitPath, homeDir string) *PathResolver {
+	path := explicitPath
+	if path == "" {
+		path = os.Getenv("ADO_CONFIG")
+	}
+	return &PathResolver{flag: explicitPath, homeDir: homeDir, path: path, expansion: DefaultExpansion()}
+}
+
+// SetExpansion sets the mode Load expands ${...} references in, which
+// defaults to that of $ADO_CONFIG_EXPAND, and returns r. Configs loaded
+// before keep theirs.
+func (r *PathResolver) SetExpansion(mode string) *PathResolver {
+	r.expansion = mode
+	return r
+}
+
+// Expansion returns the mode Load expands ${...} references in.
+func (r *PathResolver) Expansion() string {
+	return r.expansion
+}
+
+// Resolve returns the config path (empty when none was found) and the
+// sources checked, like ResolveConfigPath. Only the first call touches the
+// filesystem.
+func (r *PathResolver) Resolve() (string, []string) {
+	r.once.Do(func() {
+		r.resolved, r.sources = ResolveConfigPath(r.path, r.homeDir)
+	})
+	return r.resolved, slices.Clone(r.sources)
+}
+
+// Load loads the config from the resolved path. It returns the path used,
+// like LoadResolved. The file itself is read on every call.
+func (r *PathResolver) Load() (*Config, string, error) {
+	resolved, _ := r.Resolve()
+	cfg, err := LoadMode(resolved, r.expansion)
+	if err != nil {
+		return nil, resolved, err
+	}
+	return cfg, resolved, nil
+}
+
+// Config loads the config like Load, but reads the file only once. The
+// result is shared by all callers, which must not modify it; startup code
+// that consults the config several times per invocation uses it.
+func (r *PathResolver) Config() (*Config, string, error) {
+	r.loadOnce.Do(func() {
+		r.cfg, _, r.err = r.Load()
+	})
+	resolved, _ := r.Resolve()
+	return r.cfg, resolved, r.err
+}
+
+// WithPathResolver returns a new context carrying r.
+func WithPathResolver(ctx context.Context, r *PathResolver) context.Context {
+	return context.WithValue(ctx, pathResolverKey{}, r)
+}
+
+// PathResolverFor returns the resolver in ctx when it was made for the
+// same --config value and home directory, and a new one otherwise.
+func PathResolverFor(ctx context.Context, explicitPath, homeDir string) *PathResolver {
+	if ctx != nil {
+		if r, ok := ctx.Value(pathResolverKey{}).(*PathResolver); ok && r.flag == explicitPath && r.homeDir == homeDir {
+			return r
+		}
+	}
+	return NewPathResolver(explicitPath, homeDir)
+}
+
+// pathResolverKey is the context key for storing the PathResolver.
+type pathResolverKey struct{}
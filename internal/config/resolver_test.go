@@ -0,0 +1,102 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPathResolver_Memoizes(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(t.TempDir(), "xdg"))
+	t.Setenv("ADO_CONFIG", "")
+
+	r := NewPathResolver("", home)
+	if path, sources := r.Resolve(); path != "" || len(sources) != 2 {
+		t.Fatalf("Resolve() = %q, %v; want no path and two sources", path, sources)
+	}
+
+	// A file created afterwards is not seen by the same resolver.
+	path := filepath.Join(home, ".ado", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := r.Resolve(); got != "" {
+		t.Errorf("memoized Resolve() = %q, want empty", got)
+	}
+	if got, _ := NewPathResolver("", home).Resolve(); got != path {
+		t.Errorf("new resolver Resolve() = %q, want %q", got, path)
+	}
+}
+
+func TestPathResolver_Load(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("version: 1\naliases:\n  st: meta system\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("ADO_CONFIG", path)
+
+	cfg, got, err := NewPathResolver("", t.TempDir()).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != path || cfg.Aliases["st"] != "meta system" {
+		t.Errorf("Load() = %v from %q, want the $ADO_CONFIG file", cfg.Aliases, got)
+	}
+}
+
+func TestPathResolverFor(t *testing.T) {
+	r := NewPathResolver("a.yaml", "/home/u")
+	ctx := WithPathResolver(context.Background(), r)
+
+	tests := []struct {
+		name         string
+		ctx          context.Context
+		explicitPath string
+		homeDir      string
+		same         bool
+	}{
+		{"matching", ctx, "a.yaml", "/home/u", true},
+		{"other --config", ctx, "b.yaml", "/home/u", false},
+		{"other home", ctx, "a.yaml", "/home/v", false},
+		{"no resolver", context.Background(), "a.yaml", "/home/u", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := PathResolverFor(tt.ctx, tt.explicitPath, tt.homeDir)
+			if (got == r) != tt.same {
+				t.Errorf("PathResolverFor() reused the context resolver = %v, want %v", got == r, tt.same)
+			}
+			if path, _ := got.Resolve(); path != tt.explicitPath {
+				t.Errorf("Resolve() = %q, want %q", path, tt.explicitPath)
+			}
+		})
+	}
+}
+
+func TestPathResolver_Config(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("version: 1\nfeatures:\n  serve: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	r := NewPathResolver(path, t.TempDir())
+	first, _, err := r.Config()
+	if err != nil || !first.Features["serve"] {
+		t.Fatalf("Config() = %v, %v", first, err)
+	}
+
+	// Later edits are not read again; Load still reads the file.
+	if err := os.WriteFile(path, []byte("version: 1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if again, got, _ := r.Config(); again != first || got != path {
+		t.Errorf("Config() = %p from %q, want the first result %p", again, got, first)
+	}
+	if loaded, _, _ := r.Load(); loaded.Features["serve"] {
+		t.Error("Load() returned the cached config")
+	}
+}
@@ -1,40 +1,142 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"maps"
+	"net"
+	"net/url"
 	"os"
+	"path"
+	"slices"
+	"sort"
+	"strings"
 
+	"github.com/robfig/cron/v3"
 	"gopkg.in/yaml.v3"
+
+	"github.com/anowarislam/ado/internal/alias"
 )
 
 // ValidationResult holds the result of config validation.
 type ValidationResult struct {
-	Valid    bool              `json:"valid" yaml:"valid"`
-	Path     string            `json:"path" yaml:"path"`
+	Valid bool   `json:"valid" yaml:"valid"`
+	Path  string `json:"path" yaml:"path"`
+	// Includes lists the files Path includes, in merge order.
+	Includes []string          `json:"includes,omitempty" yaml:"includes,omitempty"`
 	Errors   []ValidationIssue `json:"errors" yaml:"errors"`
 	Warnings []ValidationIssue `json:"warnings" yaml:"warnings"`
 }
 
 // ValidationIssue represents a single validation error or warning.
 type ValidationIssue struct {
-	Message  string `json:"message" yaml:"message"`
-	Line     int    `json:"line,omitempty" yaml:"line,omitempty"`
+	Message string `json:"message" yaml:"message"`
+	Line    int    `json:"line,omitempty" yaml:"line,omitempty"`
+	// File is the included file the issue is in; empty for the
+	// validated file itself.
+	File     string `json:"file,omitempty" yaml:"file,omitempty"`
 	Severity string `json:"severity" yaml:"severity"`
+	// Rule identifies the kind of issue, one of ValidationRules.
+	Rule string `json:"rule" yaml:"rule"`
+}
+
+// Kinds of validation issue, for tools that group or suppress issues by
+// kind, such as SARIF consumers.
+const (
+	RuleFileNotFound     = "file-not-found"
+	RulePermissionDenied = "permission-denied"
+	RuleEmptyFile        = "empty-file"
+	RuleYAMLSyntax       = "yaml-syntax"
+	RuleUnknownKey       = "unknown-key"
+	RuleInvalidStructure = "invalid-structure"
+	RuleVersion          = "version"
+	RuleUpdatesChannel   = "updates-channel"
+	RuleTask             = "task"
+	RuleSchedule         = "schedule"
+	RuleHook             = "hook"
+	RuleAlias            = "alias"
+	RuleMetrics          = "metrics"
+	RuleDefaults         = "defaults"
+	RuleDevOps           = "devops"
+	RuleInclude          = "include"
+	RuleExpand           = "expand"
+	RuleUndefinedVar     = "undefined-variable"
+)
+
+// ValidationRule describes a kind of validation issue.
+type ValidationRule struct {
+	ID          string
+	Description string
+	// Severity is the severity of issues of this kind outside strict mode.
+	Severity string
+}
+
+// ValidationRules lists every kind of issue Validate reports.
+var ValidationRules = []ValidationRule{
+	{RuleFileNotFound, "The config file does not exist", "error"},
+	{RulePermissionDenied, "The config file cannot be read", "error"},
+	{RuleEmptyFile, "The config file is empty", "error"},
+	{RuleYAMLSyntax, "The config file is not valid YAML", "error"},
+	{RuleUnknownKey, "A key is not part of the config schema", "warning"},
+	{RuleInvalidStructure, "A value has the wrong type for its key", "error"},
+	{RuleVersion, "The version key is missing or unsupported", "error"},
+	{RuleUpdatesChannel, "updates.channel is not stable or prerelease", "error"},
+	{RuleTask, "A task definition is incomplete or invalid", "error"},
+	{RuleSchedule, "A schedule has a missing or invalid task, cron, jitter, or overlap", "error"},
+	{RuleHook, "A hook has a missing or invalid when, command, timeout, or on_failure", "error"},
+	{RuleAlias, "An alias has an invalid name or expansion", "error"},
+	{RuleMetrics, "The metrics section is invalid", "error"},
+	{RuleDefaults, "A defaults value cannot be a flag value", "error"},
+	{RuleDevOps, "devops.url is not an http or https URL", "error"},
+	{RuleInclude, "An included file is missing, invalid, or includes itself", "error"},
+	{RuleExpand, "A ${...} reference in a value is malformed or names a required variable that is not set", "error"},
+	{RuleUndefinedVar, "A ${...} reference names an undefined environment variable", "warning"},
 }
 
 // ConfigSchema represents the expected config file structure.
 type ConfigSchema struct {
-	Version int `yaml:"version"`
+	Version   int               `yaml:"version"`
+	Updates   UpdatesConfig     `yaml:"updates"`
+	Features  map[string]bool   `yaml:"features"`
+	Tasks     map[string]Task   `yaml:"tasks"`
+	Schedules []Schedule        `yaml:"schedules"`
+	Aliases   map[string]string `yaml:"aliases"`
+	Defaults  map[string]any    `yaml:"defaults"`
+	Metrics   MetricsConfig     `yaml:"metrics"`
+	DevOps    DevOpsConfig      `yaml:"devops"`
+	Hooks     map[string]Hook   `yaml:"hooks"`
 }
 
 // knownKeys lists valid top-level config keys.
 var knownKeys = map[string]bool{
-	"version": true,
+	"version":   true,
+	"updates":   true,
+	"features":  true,
+	"tasks":     true,
+	"schedules": true,
+	"aliases":   true,
+	"defaults":  true,
+	"metrics":   true,
+	"devops":    true,
+	"hooks":     true,
+	"include":   true,
 }
 
 // Validate validates a config file at the given path.
 // Returns a ValidationResult with any errors or warnings found.
+// References in values are expanded in the mode of $ADO_CONFIG_EXPAND.
 func Validate(path string) (*ValidationResult, error) {
+	return ValidateMode(path, DefaultExpansion())
+}
+
+// ValidateMode is Validate with the expansion mode of ${...} references,
+// as for LoadMode. Undefined variables are warnings, or errors in
+// ExpandStrict.
+func ValidateMode(path, expansion string) (*ValidationResult, error) {
+	exp, err := newExpander(expansion)
+	if err != nil {
+		return nil, err
+	}
 	result := &ValidationResult{
 		Path:     path,
 		Valid:    true,
@@ -50,6 +152,7 @@ func Validate(path string) (*ValidationResult, error) {
 			result.Errors = append(result.Errors, ValidationIssue{
 				Message:  fmt.Sprintf("config file not found: %q", path),
 				Severity: "error",
+				Rule:     RuleFileNotFound,
 			})
 			return result, nil
 		}
@@ -58,20 +161,42 @@ func Validate(path string) (*ValidationResult, error) {
 			result.Errors = append(result.Errors, ValidationIssue{
 				Message:  fmt.Sprintf("permission denied: %q", path),
 				Severity: "error",
+				Rule:     RulePermissionDenied,
 			})
 			return result, nil
 		}
 		return nil, fmt.Errorf("read config: %w", err)
 	}
 
+	return validateData(path, data, true, exp), nil
+}
+
+// ValidateData validates config file contents. path is only reported in
+// the result, so callers validating uploaded or piped data may pass a
+// label instead; includes are not followed.
+func ValidateData(path string, data []byte) *ValidationResult {
+	return validateData(path, data, false, nil)
+}
+
+// validateData validates config file contents, merged with the files
+// they include and expanded with exp when includes is set.
+func validateData(path string, data []byte, includes bool, exp *expander) *ValidationResult {
+	result := &ValidationResult{
+		Path:     path,
+		Valid:    true,
+		Errors:   []ValidationIssue{},
+		Warnings: []ValidationIssue{},
+	}
+
 	// Handle empty file
 	if len(data) == 0 {
 		result.Valid = false
 		result.Errors = append(result.Errors, ValidationIssue{
 			Message:  "config file is empty",
 			Severity: "error",
+			Rule:     RuleEmptyFile,
 		})
-		return result, nil
+		return result
 	}
 
 	// Parse YAML to check syntax and get line numbers
@@ -81,8 +206,19 @@ func Validate(path string) (*ValidationResult, error) {
 		result.Errors = append(result.Errors, ValidationIssue{
 			Message:  fmt.Sprintf("invalid YAML: %s", err.Error()),
 			Severity: "error",
+			Rule:     RuleYAMLSyntax,
 		})
-		return result, nil
+		return result
+	}
+
+	// at locates issue at key, which may be in an included file.
+	doc := &document{root: &rawNode}
+	at := func(issue ValidationIssue, key *yaml.Node) ValidationIssue {
+		if key != nil {
+			issue.Line = key.Line
+			issue.File = doc.fileOf(key, path)
+		}
+		return issue
 	}
 
 	// Parse into map to check for unknown keys
@@ -92,31 +228,93 @@ func Validate(path string) (*ValidationResult, error) {
 		result.Errors = append(result.Errors, ValidationIssue{
 			Message:  fmt.Sprintf("invalid YAML structure: %s", err.Error()),
 			Severity: "error",
+			Rule:     RuleInvalidStructure,
 		})
-		return result, nil
+		return result
+	}
+
+	// Merge the included files
+	if includes {
+		merged, err := newDocument(path, &rawNode, exp)
+		if err != nil {
+			issue := ValidationIssue{Message: err.Error(), Severity: "error", Rule: RuleInclude}
+			var (
+				inc       *includeError
+				expandErr *expandError
+			)
+			switch {
+			case errors.As(err, &inc):
+				issue.Line = inc.line
+				if inc.file != path {
+					issue.File = inc.file
+				}
+			case errors.As(err, &expandErr):
+				issue.Message = expandErr.err.Error()
+				issue.Rule = RuleExpand
+				issue.Line = expandErr.line
+				if expandErr.file != path {
+					issue.File = expandErr.file
+				}
+			}
+			result.Valid = false
+			result.Errors = append(result.Errors, issue)
+			return result
+		}
+		doc = merged
+		result.Includes = doc.files
+		if exp != nil {
+			for _, v := range exp.undefined {
+				issue := ValidationIssue{
+					Message:  fmt.Sprintf("undefined environment variable %s (expanded to an empty string)", v.name),
+					Line:     v.line,
+					Severity: "warning",
+					Rule:     RuleUndefinedVar,
+				}
+				if v.file != path {
+					issue.File = v.file
+				}
+				result.Warnings = append(result.Warnings, issue)
+			}
+		}
+		rawMap = nil
+		if err := doc.root.Decode(&rawMap); err != nil {
+			result.Valid = false
+			result.Errors = append(result.Errors, ValidationIssue{
+				Message:  fmt.Sprintf("invalid YAML structure: %s", err.Error()),
+				Severity: "error",
+				Rule:     RuleInvalidStructure,
+			})
+			return result
+		}
+	} else if _, ok := rawMap[includeKey]; ok {
+		result.Warnings = append(result.Warnings, at(ValidationIssue{
+			Message:  "include is not followed when validating content; validate the file instead",
+			Severity: "warning",
+			Rule:     RuleInclude,
+		}, findKey(doc.root, includeKey)))
 	}
 
 	// Check for unknown keys
-	for key := range rawMap {
+	for _, key := range slices.Sorted(maps.Keys(rawMap)) {
 		if !knownKeys[key] {
-			line := findKeyLine(&rawNode, key)
-			result.Warnings = append(result.Warnings, ValidationIssue{
+			result.Warnings = append(result.Warnings, at(ValidationIssue{
 				Message:  fmt.Sprintf("unknown key %q", key),
-				Line:     line,
 				Severity: "warning",
-			})
+				Rule:     RuleUnknownKey,
+			}, findKey(doc.root, key)))
 		}
 	}
 
 	// Parse into schema struct for validation
 	var schema ConfigSchema
-	if err := yaml.Unmarshal(data, &schema); err != nil {
+	if err := doc.root.Decode(&schema); err != nil {
 		result.Valid = false
 		result.Errors = append(result.Errors, ValidationIssue{
 			Message:  fmt.Sprintf("invalid config structure: %s", err.Error()),
 			Severity: "error",
+			Rule:     RuleInvalidStructure,
 		})
-		return result, nil
+		return result
 	}
 
 	// Validate required fields
@@ -125,40 +323,278 @@ func Validate(path string) (*ValidationResult, error) {
 		result.Errors = append(result.Errors, ValidationIssue{
 			Message:  "missing required key \"version\"",
 			Severity: "error",
+			Rule:     RuleVersion,
 		})
 	} else if schema.Version != 1 {
 		result.Valid = false
 		result.Errors = append(result.Errors, ValidationIssue{
 			Message:  fmt.Sprintf("unsupported config version: %d (expected: 1)", schema.Version),
 			Severity: "error",
+			Rule:     RuleVersion,
 		})
 	}
 
-	return result, nil
+	if channel := schema.Updates.Channel; channel != "" && !updateChannels[channel] {
+		result.Valid = false
+		result.Errors = append(result.Errors, at(ValidationIssue{
+			Message:  fmt.Sprintf("invalid updates.channel %q (expected: stable or prerelease)", channel),
+			Severity: "error",
+			Rule:     RuleUpdatesChannel,
+		}, findKey(doc.root, "updates")))
+	}
+
+	for _, name := range sortedTaskNames(schema.Tasks) {
+		for _, msg := range taskProblems(schema.Tasks[name]) {
+			result.Valid = false
+			result.Errors = append(result.Errors, at(ValidationIssue{
+				Message:  fmt.Sprintf("task %q: %s", name, msg),
+				Severity: "error",
+				Rule:     RuleTask,
+			}, findTaskKey(doc.root, name)))
+		}
+	}
+
+	for i, sched := range schema.Schedules {
+		for _, msg := range scheduleProblems(sched, schema.Tasks) {
+			result.Valid = false
+			result.Errors = append(result.Errors, at(ValidationIssue{
+				Message:  fmt.Sprintf("schedules[%d]: %s", i, msg),
+				Severity: "error",
+				Rule:     RuleSchedule,
+			}, findKey(doc.root, "schedules")))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(schema.Hooks)) {
+		for _, msg := range hookProblems(schema.Hooks[name]) {
+			result.Valid = false
+			result.Errors = append(result.Errors, at(ValidationIssue{
+				Message:  fmt.Sprintf("hooks.%s: %s", name, msg),
+				Severity: "error",
+				Rule:     RuleHook,
+			}, findEntryKey(doc.root, "hooks", name)))
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(schema.Aliases)) {
+		for _, msg := range aliasProblems(name, schema.Aliases[name]) {
+			result.Valid = false
+			result.Errors = append(result.Errors, at(ValidationIssue{
+				Message:  fmt.Sprintf("aliases.%s: %s", name, msg),
+				Severity: "error",
+				Rule:     RuleAlias,
+			}, findKey(doc.root, "aliases")))
+		}
+	}
+
+	if address := schema.Metrics.StatsD.Address; address != "" {
+		if _, port, err := net.SplitHostPort(address); err != nil || port == "" {
+			result.Valid = false
+			result.Errors = append(result.Errors, at(ValidationIssue{
+				Message:  fmt.Sprintf("invalid metrics.statsd.address %q (expected host:port)", address),
+				Severity: "error",
+				Rule:     RuleMetrics,
+			}, findKey(doc.root, "metrics")))
+		}
+	}
+
+	if raw := schema.DevOps.URL; raw != "" {
+		if u, err := url.Parse(raw); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			result.Valid = false
+			result.Errors = append(result.Errors, at(ValidationIssue{
+				Message:  fmt.Sprintf("invalid devops.url %q (expected an http or https URL)", raw),
+				Severity: "error",
+				Rule:     RuleDevOps,
+			}, findKey(doc.root, "devops")))
+		}
+	}
+
+	for _, msg := range defaultsProblems("defaults", schema.Defaults) {
+		result.Valid = false
+		result.Errors = append(result.Errors, at(ValidationIssue{
+			Message:  msg,
+			Severity: "error",
+			Rule:     RuleDefaults,
+		}, findKey(doc.root, "defaults")))
+	}
+
+	return result
+}
+
+// defaultsProblems lists values in the defaults: section that cannot be
+// flag values: anything but a section, a scalar, or a list of scalars.
+// Whether the commands and flags exist is checked against the command tree
+// by `ado config validate`.
+func defaultsProblems(prefix string, node map[string]any) []string {
+	var problems []string
+	for _, key := range slices.Sorted(maps.Keys(node)) {
+		path := prefix + "." + key
+		switch value := node[key].(type) {
+		case map[string]any:
+			problems = append(problems, defaultsProblems(path, value)...)
+		case []any:
+			for _, item := range value {
+				if !isScalar(item) {
+					problems = append(problems, fmt.Sprintf("%s: list items must be strings, numbers, or booleans", path))
+					break
+				}
+			}
+		default:
+			if !isScalar(value) {
+				problems = append(problems, fmt.Sprintf("%s: expected a flag value or a command section", path))
+			}
+		}
+	}
+	return problems
+}
+
+func isScalar(v any) bool {
+	switch v.(type) {
+	case string, bool, int, float64:
+		return true
+	}
+	return false
+}
+
+// aliasProblems lists what is wrong with an alias. Whether the expansion
+// starts with a known command is checked when the alias is used.
+func aliasProblems(name, expansion string) []string {
+	var problems []string
+	if err := alias.ValidateName(name); err != nil {
+		problems = append(problems, err.Error())
+	}
+	if args, err := alias.Split(expansion); err != nil {
+		problems = append(problems, "invalid expansion: "+err.Error())
+	} else if len(args) == 0 {
+		problems = append(problems, "expansion is empty")
+	}
+	return problems
+}
+
+// taskProblems lists what is wrong with a task.
+func taskProblems(task Task) []string {
+	var problems []string
+	if task.Command == "" {
+		problems = append(problems, `missing required key "command"`)
+	}
+	if task.EnvPolicy != "" && !envPolicies[task.EnvPolicy] {
+		problems = append(problems, fmt.Sprintf("invalid env_policy %q (expected: inherit, clean, or allowlist)", task.EnvPolicy))
+	}
+	if len(task.EnvAllow) > 0 && task.EnvPolicy != EnvAllowlist {
+		problems = append(problems, "env_allow is only used with env_policy: allowlist")
+	}
+	for _, pattern := range task.EnvAllow {
+		if _, err := path.Match(pattern, ""); err != nil || pattern == "" {
+			problems = append(problems, fmt.Sprintf("invalid env_allow pattern %q", pattern))
+		}
+	}
+	return problems
+}
+
+// hookProblems lists what is wrong with a hook.
+func hookProblems(hook Hook) []string {
+	var problems []string
+	switch hook.When {
+	case HookPre, HookPost:
+	case "":
+		problems = append(problems, `missing required key "when"`)
+	default:
+		problems = append(problems, fmt.Sprintf("invalid when %q (expected: pre or post)", hook.When))
+	}
+	if hook.Command == "" {
+		problems = append(problems, `missing required key "command"`)
+	}
+	for _, command := range hook.Commands {
+		if strings.TrimSpace(command) == "" {
+			problems = append(problems, "commands must not contain an empty path")
+			break
+		}
+	}
+	if hook.Timeout < 0 {
+		problems = append(problems, "timeout must not be negative")
+	}
+	if hook.OnFailure != "" && !hookPolicies[hook.OnFailure] {
+		problems = append(problems, fmt.Sprintf("invalid on_failure %q (expected: fail, warn, or ignore)", hook.OnFailure))
+	}
+	return problems
+}
+
+// scheduleProblems lists what is wrong with a schedule entry.
+func scheduleProblems(sched Schedule, tasks map[string]Task) []string {
+	var problems []string
+	switch _, ok := tasks[sched.Task]; {
+	case sched.Task == "":
+		problems = append(problems, `missing required key "task"`)
+	case !ok:
+		problems = append(problems, fmt.Sprintf("unknown task %q", sched.Task))
+	}
+	if sched.Cron == "" {
+		problems = append(problems, `missing required key "cron"`)
+	} else if _, err := cron.ParseStandard(sched.Cron); err != nil {
+		problems = append(problems, fmt.Sprintf("invalid cron %q: %v", sched.Cron, err))
+	}
+	if sched.Jitter < 0 {
+		problems = append(problems, "jitter must not be negative")
+	}
+	if sched.Overlap != "" && !overlapPolicies[sched.Overlap] {
+		problems = append(problems, fmt.Sprintf("invalid overlap %q (expected: skip, queue, or allow)", sched.Overlap))
+	}
+	return problems
+}
+
+func sortedTaskNames(tasks map[string]Task) []string {
+	names := make([]string, 0, len(tasks))
+	for name := range tasks {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// findTaskKey returns the key node of a task's name under the tasks key.
+func findTaskKey(node *yaml.Node, name string) *yaml.Node {
+	return findEntryKey(node, "tasks", name)
+}
+
+// findEntryKey returns the key node of name under the top-level mapping
+// section, such as a task under tasks.
+func findEntryKey(node *yaml.Node, section, name string) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(node.Content)-1; i += 2 {
+		if node.Content[i].Value == section {
+			return findKey(node.Content[i+1], name)
+		}
+	}
+	return nil
 }
 
-// findKeyLine searches the YAML node tree for a key and returns its line number.
-func findKeyLine(node *yaml.Node, key string) int {
+// findKey searches the YAML node tree for a key and returns its node.
+func findKey(node *yaml.Node, key string) *yaml.Node {
 	if node == nil {
-		return 0
+		return nil
 	}
 
 	switch node.Kind {
 	case yaml.DocumentNode:
 		for _, child := range node.Content {
-			if line := findKeyLine(child, key); line > 0 {
-				return line
+			if found := findKey(child, key); found != nil {
+				return found
 			}
 		}
 	case yaml.MappingNode:
 		for i := 0; i < len(node.Content)-1; i += 2 {
 			keyNode := node.Content[i]
 			if keyNode.Value == key {
-				return keyNode.Line
+				return keyNode
 			}
 		}
 	}
-	return 0
+	return nil
 }
 
 // HasErrors returns true if there are any validation errors.
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"testing"
 )
 
@@ -49,6 +50,30 @@ func TestValidate(t *testing.T) {
 			wantErrors:  1,
 			errContains: "empty",
 		},
+		{
+			name:      "statsd metrics",
+			content:   "version: 1\nmetrics:\n  statsd:\n    address: 127.0.0.1:8125\n    tags: {team: infra}\n",
+			wantValid: true,
+		},
+		{
+			name:        "statsd address without port",
+			content:     "version: 1\nmetrics:\n  statsd:\n    address: localhost\n",
+			wantValid:   false,
+			wantErrors:  1,
+			errContains: "invalid metrics.statsd.address",
+		},
+		{
+			name:      "devops",
+			content:   "version: 1\ndevops:\n  organization: contoso\n  project: web\n  token: secret://ado-pat\n",
+			wantValid: true,
+		},
+		{
+			name:        "devops url without scheme",
+			content:     "version: 1\ndevops:\n  url: tfs.example.com/DefaultCollection\n",
+			wantValid:   false,
+			wantErrors:  1,
+			errContains: "invalid devops.url",
+		},
 		{
 			name:        "unsupported version",
 			content:     "version: 99\n",
@@ -61,6 +86,125 @@ func TestValidate(t *testing.T) {
 			content:   "version: 1\n",
 			wantValid: true,
 		},
+		{
+			name:      "updates section",
+			content:   "version: 1\nupdates:\n  check: true\n  channel: prerelease\n",
+			wantValid: true,
+		},
+		{
+			name:        "invalid update channel",
+			content:     "version: 1\nupdates:\n  channel: nightly\n",
+			wantValid:   false,
+			wantErrors:  1,
+			errContains: "invalid updates.channel",
+		},
+		{
+			name:      "tasks section",
+			content:   "version: 1\ntasks:\n  build:\n    command: go\n    args: [build, ./...]\n    env:\n      CGO_ENABLED: \"0\"\n    cwd: src\n    description: Build\n",
+			wantValid: true,
+		},
+		{
+			name:        "task without command",
+			content:     "version: 1\ntasks:\n  build:\n    args: [build]\n",
+			wantValid:   false,
+			wantErrors:  1,
+			errContains: `task "build": missing required key "command"`,
+		},
+		{
+			name:      "task env policy",
+			content:   "version: 1\ntasks:\n  deploy:\n    command: ./deploy\n    env_policy: allowlist\n    env_allow: [AWS_*, KUBECONFIG]\n    env:\n      TOKEN: secret://deploy\n",
+			wantValid: true,
+		},
+		{
+			name:        "task with invalid env policy",
+			content:     "version: 1\ntasks:\n  deploy:\n    command: ./deploy\n    env_policy: none\n    env_allow: [\"[\"]\n",
+			wantValid:   false,
+			wantErrors:  3,
+			errContains: `task "deploy": invalid env_policy "none" (expected: inherit, clean, or allowlist)`,
+		},
+		{
+			name:      "hooks section",
+			content:   "version: 1\nhooks:\n  vpn:\n    when: pre\n    commands: [remote, devops]\n    command: ./check-vpn\n    timeout: 5s\n  notify:\n    when: post\n    command: notify-send\n    args: [done]\n    on_failure: ignore\n",
+			wantValid: true,
+		},
+		{
+			name:        "hook with every problem",
+			content:     "version: 1\nhooks:\n  vpn:\n    when: before\n    commands: [\"\"]\n    timeout: -1s\n    on_failure: retry\n",
+			wantValid:   false,
+			wantErrors:  5,
+			errContains: `hooks.vpn: invalid when "before" (expected: pre or post)`,
+		},
+		{
+			name:        "hook with misspelled when",
+			content:     "version: 1\nhooks:\n  vpn:\n    when: pre-run\n    command: ./check-vpn\n",
+			wantValid:   false,
+			wantErrors:  1,
+			errContains: `hooks.vpn: invalid when "pre-run" (expected: pre or post)`,
+		},
+		{
+			name:        "hook without when",
+			content:     "version: 1\nhooks:\n  vpn:\n    command: ./check-vpn\n",
+			wantValid:   false,
+			wantErrors:  1,
+			errContains: `hooks.vpn: missing required key "when"`,
+		},
+		{
+			name:      "schedules section",
+			content:   "version: 1\ntasks:\n  backup:\n    command: restic\nschedules:\n  - task: backup\n    cron: \"0 3 * * *\"\n    jitter: 5m\n    overlap: queue\n  - task: backup\n    cron: \"@every 1h\"\n",
+			wantValid: true,
+		},
+		{
+			name:        "schedule with unknown task",
+			content:     "version: 1\nschedules:\n  - task: backup\n    cron: \"@daily\"\n",
+			wantValid:   false,
+			wantErrors:  1,
+			errContains: `schedules[0]: unknown task "backup"`,
+		},
+		{
+			name:        "schedule with invalid cron",
+			content:     "version: 1\ntasks:\n  b:\n    command: x\nschedules:\n  - task: b\n    cron: \"61 * * * *\"\n",
+			wantValid:   false,
+			wantErrors:  1,
+			errContains: `invalid cron "61 * * * *"`,
+		},
+		{
+			name:        "schedule with every problem",
+			content:     "version: 1\nschedules:\n  - overlap: replace\n    jitter: -1s\n",
+			wantValid:   false,
+			wantErrors:  4,
+			errContains: `invalid overlap "replace"`,
+		},
+		{
+			name:      "aliases section",
+			content:   "version: 1\naliases:\n  sys: meta system --output json\n  hi: \"echo 'hello world'\"\n",
+			wantValid: true,
+		},
+		{
+			name:        "alias with unterminated quote",
+			content:     "version: 1\naliases:\n  hi: \"echo 'hello\"\n",
+			wantValid:   false,
+			wantErrors:  1,
+			errContains: "aliases.hi: invalid expansion: unterminated ' quote",
+		},
+		{
+			name:        "alias with invalid name and empty expansion",
+			content:     "version: 1\naliases:\n  a b: \"\"\n",
+			wantValid:   false,
+			wantErrors:  2,
+			errContains: `invalid alias name "a b"`,
+		},
+		{
+			name:      "defaults section",
+			content:   "version: 1\ndefaults:\n  timeout: 5m\n  echo:\n    repeat: 2\n  meta:\n    system:\n      sections: [cpu, memory]\n",
+			wantValid: true,
+		},
+		{
+			name:        "defaults with nested list",
+			content:     "version: 1\ndefaults:\n  meta:\n    system:\n      sections: [[cpu]]\n      output:\n",
+			wantValid:   false,
+			wantErrors:  2,
+			errContains: "defaults.meta.system.sections: list items must be strings, numbers, or booleans",
+		},
 	}
 
 	for _, tt := range tests {
@@ -101,6 +245,12 @@ func TestValidate(t *testing.T) {
 					t.Errorf("Expected error containing %q, got: %+v", tt.errContains, result.Errors)
 				}
 			}
+
+			for _, issue := range append(result.Errors, result.Warnings...) {
+				if !slices.ContainsFunc(ValidationRules, func(r ValidationRule) bool { return r.ID == issue.Rule }) {
+					t.Errorf("issue %q has unknown rule %q", issue.Message, issue.Rule)
+				}
+			}
 		})
 	}
 }
@@ -249,3 +399,44 @@ func containsSubstring(s, substr string) bool {
 	}
 	return false
 }
+
+func TestValidate_TaskErrorLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "version: 1\ntasks:\n  ok:\n    command: true\n  broken:\n    args: [x]\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Validate(path)
+	if err != nil {
+		t.Fatalf("Validate() error: %v", err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Line != 5 {
+		t.Errorf("Errors = %+v, want one error on line 5", result.Errors)
+	}
+}
+
+func TestValidateData(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		wantValid  bool
+		wantErrors int
+	}{
+		{"valid", "version: 1\n", true, 0},
+		{"empty", "", false, 1},
+		{"invalid yaml", "version: [", false, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := ValidateData("upload", []byte(tt.data))
+			if result.Path != "upload" {
+				t.Errorf("Path = %q, want upload", result.Path)
+			}
+			if result.Valid != tt.wantValid || len(result.Errors) != tt.wantErrors {
+				t.Errorf("ValidateData() = valid %v, %d errors; want %v, %d", result.Valid, len(result.Errors), tt.wantValid, tt.wantErrors)
+			}
+		})
+	}
+}
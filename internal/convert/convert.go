@@ -0,0 +1,275 @@
+// Package convert translates documents between JSON, YAML, TOML, and CSV.
+package convert
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// Supported formats.
+const (
+	JSON = "json"
+	YAML = "yaml"
+	TOML = "toml"
+	CSV  = "csv"
+)
+
+// Formats lists the supported formats.
+var Formats = []string{JSON, YAML, TOML, CSV}
+
+// ParseFormat validates a format name, accepting "yml" for YAML.
+func ParseFormat(name string) (string, error) {
+	switch f := strings.ToLower(name); f {
+	case JSON, YAML, TOML, CSV:
+		return f, nil
+	case "yml":
+		return YAML, nil
+	default:
+		return "", fmt.Errorf("unsupported format %q (supported: %s)", name, strings.Join(Formats, ", "))
+	}
+}
+
+// FormatFromPath infers a format from a file extension, or returns "".
+func FormatFromPath(path string) string {
+	f, err := ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+	if err != nil {
+		return ""
+	}
+	return f
+}
+
+// Sniff guesses the format of data that has no file name: JSON when it
+// starts with { or [, otherwise YAML.
+func Sniff(data []byte) string {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return JSON
+	}
+	return YAML
+}
+
+// Decode parses data in format into maps, lists, and scalars.
+func Decode(data []byte, format string) (any, error) {
+	switch format {
+	case JSON:
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return nil, fmt.Errorf("parse json: %w", err)
+		}
+		if _, err := dec.Token(); err != io.EOF {
+			return nil, errors.New("parse json: unexpected data after top-level value")
+		}
+		return normalize(v), nil
+
+	case YAML:
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		var v any
+		if err := dec.Decode(&v); err != nil && err != io.EOF {
+			return nil, fmt.Errorf("parse yaml: %w", err)
+		}
+		var extra any
+		if err := dec.Decode(&extra); err != io.EOF {
+			return nil, errors.New("parse yaml: multiple documents are not supported")
+		}
+		return normalize(v), nil
+
+	case TOML:
+		var v map[string]any
+		if err := toml.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("parse toml: %w", err)
+		}
+		return normalize(v), nil
+
+	case CSV:
+		return decodeCSV(data)
+
+	default:
+		_, err := ParseFormat(format)
+		return nil, err
+	}
+}
+
+// Options control encoding.
+type Options struct {
+	// Compact writes JSON on a single line.
+	Compact bool
+}
+
+// Encode serializes v in format. The output ends with a newline.
+func Encode(v any, format string, opts Options) ([]byte, error) {
+	switch format {
+	case JSON:
+		if opts.Compact {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("serialize json: %w", err)
+			}
+			return append(data, '\n'), nil
+		}
+		return ui.Marshal(ui.OutputJSON, v)
+
+	case YAML:
+		return ui.Marshal(ui.OutputYAML, v)
+
+	case TOML:
+		if _, ok := v.(map[string]any); !ok {
+			return nil, errors.New("toml output requires an object at the top level")
+		}
+		data, err := toml.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("serialize toml: %w", err)
+		}
+		return data, nil
+
+	case CSV:
+		return encodeCSV(v)
+
+	default:
+		_, err := ParseFormat(format)
+		return nil, err
+	}
+}
+
+// normalize converts YAML's map[any]any into map[string]any and JSON
+// numbers into int64 or float64, so every format can encode the result.
+func normalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, val := range v {
+			v[k] = normalize(val)
+		}
+		return v
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			out[fmt.Sprint(k)] = normalize(val)
+		}
+		return out
+	case []any:
+		for i, val := range v {
+			v[i] = normalize(val)
+		}
+		return v
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		f, _ := v.Float64()
+		return f
+	default:
+		return v
+	}
+}
+
+// decodeCSV reads a header row followed by records into a list of objects
+// with string values.
+func decodeCSV(data []byte) (any, error) {
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("parse csv: %w", err)
+	}
+	rows := make([]any, 0, max(len(records)-1, 0))
+	if len(records) == 0 {
+		return rows, nil
+	}
+	header := records[0]
+	for _, record := range records[1:] {
+		row := make(map[string]any, len(header))
+		for i, name := range header {
+			row[name] = record[i]
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// encodeCSV writes a list of objects as CSV. Columns are the union of all
+// keys, sorted; nested values are written as JSON.
+func encodeCSV(v any) ([]byte, error) {
+	list, ok := v.([]any)
+	if !ok {
+		return nil, errors.New("csv output requires a list of objects")
+	}
+
+	seen := map[string]bool{}
+	var columns []string
+	rows := make([]map[string]any, 0, len(list))
+	for i, item := range list {
+		row, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("csv output requires a list of objects (item %d is not an object)", i)
+		}
+		for k := range row {
+			if !seen[k] {
+				seen[k] = true
+				columns = append(columns, k)
+			}
+		}
+		rows = append(rows, row)
+	}
+	sort.Strings(columns)
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(columns); err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		record := make([]string, len(columns))
+		for i, col := range columns {
+			cell, err := csvCell(row[col])
+			if err != nil {
+				return nil, err
+			}
+			record[i] = cell
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, fmt.Errorf("serialize csv: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func csvCell(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case map[string]any, []any:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("serialize csv cell: %w", err)
+		}
+		return string(data), nil
+	default:
+		return fmt.Sprint(v), nil
+	}
+}
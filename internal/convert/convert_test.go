@@ -0,0 +1,189 @@
+package convert
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseFormat(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{"json", JSON, false},
+		{"YAML", YAML, false},
+		{"yml", YAML, false},
+		{"toml", TOML, false},
+		{"csv", CSV, false},
+		{"xml", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseFormat(tt.in)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFormat() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	tests := map[string]string{
+		"a.json":       JSON,
+		"dir/b.yml":    YAML,
+		"Cargo.toml":   TOML,
+		"data.CSV":     CSV,
+		"README":       "",
+		"notes.txt":    "",
+		"archive.yaml": YAML,
+	}
+	for path, want := range tests {
+		if got := FormatFromPath(path); got != want {
+			t.Errorf("FormatFromPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestSniff(t *testing.T) {
+	if got := Sniff([]byte("  \n{\"a\": 1}")); got != JSON {
+		t.Errorf("Sniff(object) = %q, want json", got)
+	}
+	if got := Sniff([]byte("[1]")); got != JSON {
+		t.Errorf("Sniff(array) = %q, want json", got)
+	}
+	if got := Sniff([]byte("a: 1")); got != YAML {
+		t.Errorf("Sniff(yaml) = %q, want yaml", got)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		in       string
+		opts     Options
+		want     string
+	}{
+		{
+			name: "yaml to json",
+			from: YAML, to: JSON,
+			in:   "name: ado\nport: 80\nratio: 0.5\n",
+			want: "{\n  \"name\": \"ado\",\n  \"port\": 80,\n  \"ratio\": 0.5\n}\n",
+		},
+		{
+			name: "yaml to compact json",
+			from: YAML, to: JSON,
+			in:   "b: [1, 2]\na: true\n",
+			opts: Options{Compact: true},
+			want: "{\"a\":true,\"b\":[1,2]}\n",
+		},
+		{
+			name: "json to yaml keeps integers",
+			from: JSON, to: YAML,
+			in:   `{"port": 8080, "big": 12345678901234, "f": 1.5}`,
+			want: "big: 12345678901234\nf: 1.5\nport: 8080\n",
+		},
+		{
+			name: "json to toml",
+			from: JSON, to: TOML,
+			in:   `{"title": "x", "server": {"port": 80}}`,
+			want: "title = 'x'\n\n[server]\nport = 80\n",
+		},
+		{
+			name: "toml to yaml",
+			from: TOML, to: YAML,
+			in:   "name = \"ado\"\n[db]\nport = 5432\n",
+			want: "db:\n    port: 5432\nname: ado\n",
+		},
+		{
+			name: "json to csv",
+			from: JSON, to: CSV,
+			in:   `[{"name": "a", "n": 1}, {"name": "b, c", "tags": ["x"]}]`,
+			want: "n,name,tags\n1,a,\n,\"b, c\",\"[\"\"x\"\"]\"\n",
+		},
+		{
+			name: "csv to json",
+			from: CSV, to: JSON,
+			in:   "name,port\nweb,80\n",
+			opts: Options{Compact: true},
+			want: "[{\"name\":\"web\",\"port\":\"80\"}]\n",
+		},
+		{
+			name: "empty csv",
+			from: CSV, to: JSON,
+			in:   "",
+			opts: Options{Compact: true},
+			want: "[]\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := Decode([]byte(tt.in), tt.from)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			got, err := Encode(v, tt.to, tt.opts)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Encode() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		in     string
+		want   string
+	}{
+		{"bad json", JSON, "{", "parse json"},
+		{"trailing json", JSON, "{} {}", "unexpected data"},
+		{"bad yaml", YAML, "a: [1", "parse yaml"},
+		{"multi-document yaml", YAML, "a: 1\n---\nb: 2\n", "multiple documents"},
+		{"bad toml", TOML, "a = ", "parse toml"},
+		{"ragged csv", CSV, "a,b\n1\n", "parse csv"},
+		{"unknown format", "xml", "", "unsupported format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode([]byte(tt.in), tt.format)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Decode() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncode_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		v      any
+		want   string
+	}{
+		{"toml list", TOML, []any{1}, "requires an object"},
+		{"csv object", CSV, map[string]any{"a": 1}, "requires a list of objects"},
+		{"csv scalars", CSV, []any{1}, "item 0 is not an object"},
+		{"unknown format", "xml", nil, "unsupported format"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Encode(tt.v, tt.format, Options{})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Encode() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
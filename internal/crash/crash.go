@@ -0,0 +1,98 @@
+// Package crash writes reports for panics that reach the top of ado, so
+// that crashes in the field can be diagnosed from a file users attach to
+// an issue.
+package crash
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// ExitCode is the status ado exits with after a panic, EX_SOFTWARE from
+// sysexits.h.
+const ExitCode = 70
+
+// DirName is the directory holding crash reports inside the state
+// directory.
+const DirName = "crashes"
+
+// Report describes a panic.
+type Report struct {
+	Time  time.Time
+	Value any
+	Stack []byte
+	// Args is the command line, including the program name. Format
+	// redacts secrets in it.
+	Args  []string
+	Build meta.BuildInfo
+}
+
+// Dir returns where crash reports are written.
+func Dir() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, DirName), nil
+}
+
+// Format renders the report as plain text.
+func (r Report) Format() string {
+	var b strings.Builder
+	b.WriteString("ado crash report\n\n")
+	fmt.Fprintf(&b, "Time:     %s\n", r.Time.UTC().Format(time.RFC3339))
+	fmt.Fprintf(&b, "Version:  %s\n", describeBuild(r.Build))
+	fmt.Fprintf(&b, "Go:       %s\n", runtime.Version())
+	fmt.Fprintf(&b, "Platform: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	fmt.Fprintf(&b, "CPUs:     %d (GOMAXPROCS %d)\n", runtime.NumCPU(), runtime.GOMAXPROCS(0))
+	fmt.Fprintf(&b, "Command:  %s\n", ui.QuoteArgs(meta.RedactArgs(r.Args)))
+	fmt.Fprintf(&b, "\npanic: %v\n\n", r.Value)
+	b.Write(r.Stack)
+	if len(r.Stack) > 0 && r.Stack[len(r.Stack)-1] != '\n' {
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Write saves the report in dir as crash-TIMESTAMP-PID.txt, readable only
+// by the user, and returns its path.
+func Write(dir string, r Report) (string, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return "", fmt.Errorf("create crash directory: %w", err)
+	}
+	name := fmt.Sprintf("crash-%s-%d.txt", r.Time.UTC().Format("20060102T150405Z"), os.Getpid())
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(r.Format()), 0o600); err != nil {
+		return "", fmt.Errorf("write crash report: %w", err)
+	}
+	return path, nil
+}
+
+func describeBuild(b meta.BuildInfo) string {
+	var details []string
+	if b.Commit != "" {
+		details = append(details, "commit "+b.Commit)
+	}
+	if b.BuildTime != "" {
+		details = append(details, "built "+b.BuildTime)
+	}
+	if b.Dirty {
+		details = append(details, "dirty")
+	}
+	version := b.Version
+	if version == "" {
+		version = "unknown"
+	}
+	if len(details) == 0 {
+		return version
+	}
+	return fmt.Sprintf("%s (%s)", version, strings.Join(details, ", "))
+}
@@ -0,0 +1,73 @@
+package crash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anowarislam/ado/internal/meta"
+)
+
+func testReport() Report {
+	return Report{
+		Time:  time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC),
+		Value: "boom",
+		Stack: []byte("goroutine 1 [running]:\nmain.main()"),
+		Args:  []string{"ado", "serve", "--token", "s3cret", "--addr", ":9100 x"},
+		Build: meta.BuildInfo{Version: "1.2.3", Commit: "abc1234", Dirty: true},
+	}
+}
+
+func TestReport_Format(t *testing.T) {
+	got := testReport().Format()
+	for _, want := range []string{
+		"Time:     2026-10-16T09:30:00Z\n",
+		"Version:  1.2.3 (commit abc1234, dirty)\n",
+		"Platform: ",
+		`Command:  ado serve --token [REDACTED] --addr ":9100 x"` + "\n",
+		"\npanic: boom\n\ngoroutine 1 [running]:\nmain.main()\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("Format() missing %q in:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "s3cret") {
+		t.Error("Format() leaked a secret argument")
+	}
+}
+
+func TestWrite(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), DirName)
+	path, err := Write(dir, testReport())
+	if err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if !strings.HasPrefix(filepath.Base(path), "crash-20261016T093000Z-") || filepath.Dir(path) != dir {
+		t.Errorf("path = %q", path)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("mode = %o, want 600", perm)
+	}
+	data, _ := os.ReadFile(path)
+	if !strings.HasPrefix(string(data), "ado crash report\n") {
+		t.Errorf("contents = %q", data)
+	}
+}
+
+func TestDir(t *testing.T) {
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	dir, err := Dir()
+	if err != nil {
+		t.Fatalf("Dir() error = %v", err)
+	}
+	if want := filepath.Join(state, "ado", DirName); dir != want {
+		t.Errorf("Dir() = %q, want %q", dir, want)
+	}
+}
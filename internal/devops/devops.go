@@ -0,0 +1,322 @@
+// Package devops is a small client for the Azure DevOps REST API: listing
+// and running pipelines and querying work items, for the ado devops
+// commands.
+//
+// Requests authenticate with a personal access token (PAT), sent as the
+// password of HTTP basic auth as Azure DevOps expects.
+package devops
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anowarislam/ado/internal/httpclient"
+)
+
+// APIVersion is the REST API version requested.
+const APIVersion = "7.1"
+
+// DefaultBaseURL is the Azure DevOps Services host. Azure DevOps Server
+// installations use their own collection URL.
+const DefaultBaseURL = "https://dev.azure.com"
+
+// DefaultTokenRef is where the PAT is read from when the config names
+// none: the secret stored with `ado secret set azure-devops-pat`.
+const DefaultTokenRef = "secret://azure-devops-pat"
+
+// maxWorkItemBatch is the most work items the API returns per request.
+const maxWorkItemBatch = 200
+
+// ErrUnauthorized is returned when Azure DevOps rejects the token.
+var ErrUnauthorized = errors.New("azure devops rejected the personal access token; check it has not expired and has the needed scopes")
+
+// Client calls the API of one organization and project.
+type Client struct {
+	HTTP *httpclient.Client
+	// BaseURL defaults to DefaultBaseURL.
+	BaseURL      string
+	Organization string
+	Project      string
+	Token        string
+}
+
+// Pipeline is a pipeline definition.
+type Pipeline struct {
+	ID     int    `json:"id" yaml:"id"`
+	Name   string `json:"name" yaml:"name"`
+	Folder string `json:"folder" yaml:"folder"`
+	URL    string `json:"url" yaml:"url"`
+}
+
+// Run is a pipeline run.
+type Run struct {
+	ID         int       `json:"id" yaml:"id"`
+	Name       string    `json:"name" yaml:"name"`
+	PipelineID int       `json:"pipeline_id" yaml:"pipeline_id"`
+	Pipeline   string    `json:"pipeline" yaml:"pipeline"`
+	State      string    `json:"state" yaml:"state"`
+	Result     string    `json:"result,omitempty" yaml:"result,omitempty"`
+	Created    time.Time `json:"created" yaml:"created"`
+	URL        string    `json:"url" yaml:"url"`
+}
+
+// WorkItem is a work item with its common fields.
+type WorkItem struct {
+	ID         int    `json:"id" yaml:"id"`
+	Type       string `json:"type" yaml:"type"`
+	Title      string `json:"title" yaml:"title"`
+	State      string `json:"state" yaml:"state"`
+	AssignedTo string `json:"assigned_to,omitempty" yaml:"assigned_to,omitempty"`
+	URL        string `json:"url" yaml:"url"`
+}
+
+// RunOptions customize a pipeline run.
+type RunOptions struct {
+	// Branch is the branch of the pipeline's repository to run, such as
+	// main or refs/heads/main; the pipeline's default when empty.
+	Branch string
+	// Variables set pipeline variables that allow overriding at queue time.
+	Variables map[string]string
+}
+
+// Pipelines lists the project's pipelines.
+func (c *Client) Pipelines(ctx context.Context) ([]Pipeline, error) {
+	var resp struct {
+		Value []apiPipeline `json:"value"`
+	}
+	if err := c.call(ctx, http.MethodGet, c.projectURL("_apis/pipelines"), nil, nil, &resp); err != nil {
+		return nil, err
+	}
+	pipelines := make([]Pipeline, len(resp.Value))
+	for i, p := range resp.Value {
+		pipelines[i] = p.pipeline()
+	}
+	return pipelines, nil
+}
+
+// FindPipeline returns the pipeline called ref, or with ref as its ID.
+func (c *Client) FindPipeline(ctx context.Context, ref string) (Pipeline, error) {
+	pipelines, err := c.Pipelines(ctx)
+	if err != nil {
+		return Pipeline{}, err
+	}
+	id, idErr := strconv.Atoi(ref)
+	for _, p := range pipelines {
+		if p.Name == ref || (idErr == nil && p.ID == id) {
+			return p, nil
+		}
+	}
+	return Pipeline{}, fmt.Errorf("no pipeline %q in %s/%s", ref, c.Organization, c.Project)
+}
+
+// RunPipeline queues a run of the pipeline with id.
+func (c *Client) RunPipeline(ctx context.Context, id int, opts RunOptions) (Run, error) {
+	body := map[string]any{}
+	if opts.Branch != "" {
+		ref := opts.Branch
+		if !strings.HasPrefix(ref, "refs/") {
+			ref = "refs/heads/" + ref
+		}
+		body["resources"] = map[string]any{"repositories": map[string]any{"self": map[string]string{"refName": ref}}}
+	}
+	if len(opts.Variables) > 0 {
+		vars := map[string]any{}
+		for name, value := range opts.Variables {
+			vars[name] = map[string]string{"value": value}
+		}
+		body["variables"] = vars
+	}
+
+	var resp apiRun
+	if err := c.call(ctx, http.MethodPost, c.projectURL(fmt.Sprintf("_apis/pipelines/%d/runs", id)), nil, body, &resp); err != nil {
+		return Run{}, err
+	}
+	return resp.run(), nil
+}
+
+// QueryWorkItems runs a WIQL query and returns up to top matching work
+// items in the query's order.
+func (c *Client) QueryWorkItems(ctx context.Context, wiql string, top int) ([]WorkItem, error) {
+	query := url.Values{}
+	if top > 0 {
+		query.Set("$top", strconv.Itoa(top))
+	}
+	var result struct {
+		WorkItems []struct {
+			ID int `json:"id"`
+		} `json:"workItems"`
+	}
+	if err := c.call(ctx, http.MethodPost, c.projectURL("_apis/wit/wiql"), query, map[string]string{"query": wiql}, &result); err != nil {
+		return nil, err
+	}
+
+	items := make([]WorkItem, 0, len(result.WorkItems))
+	for start := 0; start < len(result.WorkItems); start += maxWorkItemBatch {
+		batch := result.WorkItems[start:min(start+maxWorkItemBatch, len(result.WorkItems))]
+		ids := make([]string, len(batch))
+		for i, ref := range batch {
+			ids[i] = strconv.Itoa(ref.ID)
+		}
+		query := url.Values{
+			"ids":    {strings.Join(ids, ",")},
+			"fields": {"System.Id,System.WorkItemType,System.Title,System.State,System.AssignedTo"},
+		}
+		var resp struct {
+			Value []apiWorkItem `json:"value"`
+		}
+		if err := c.call(ctx, http.MethodGet, c.orgURL("_apis/wit/workitems"), query, nil, &resp); err != nil {
+			return nil, err
+		}
+		for _, item := range resp.Value {
+			items = append(items, item.workItem(c.orgURL(fmt.Sprintf("%s/_workitems/edit/%d", url.PathEscape(c.Project), item.ID))))
+		}
+	}
+	return items, nil
+}
+
+// call sends a request and decodes the JSON response into out. POST
+// requests are not retried, so a run is never queued twice.
+func (c *Client) call(ctx context.Context, method, rawURL string, query url.Values, body, out any) error {
+	if c.Organization == "" || c.Project == "" {
+		return errors.New("azure devops organization and project are required: set devops.organization and devops.project in the config file or pass --organization and --project")
+	}
+	if c.Token == "" {
+		return errors.New("azure devops personal access token is empty")
+	}
+
+	req := &httpclient.Request{Method: method, URL: rawURL, Header: http.Header{}, Query: url.Values{"api-version": {APIVersion}}}
+	for name, values := range query {
+		req.Query[name] = values
+	}
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(":"+c.Token)))
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		req.Body = data
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := c.HTTP
+	if client == nil {
+		client = &httpclient.Client{Timeout: httpclient.DefaultTimeout}
+	}
+	if method != http.MethodGet && client.Retries > 0 {
+		once := *client
+		once.Retries = 0
+		client = &once
+	}
+	resp, err := client.Do(ctx, req)
+	if err != nil {
+		return fmt.Errorf("azure devops: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized,
+		// A bad token gets a sign-in page instead of an error status.
+		resp.StatusCode == http.StatusNonAuthoritativeInfo && !httpclient.IsJSON(resp.Headers.Get("Content-Type")):
+		return ErrUnauthorized
+	case resp.StatusCode >= 300:
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		statusErr := httpclient.CheckStatus(resp)
+		if json.Unmarshal(resp.Raw, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("azure devops: %w: %s", statusErr, apiErr.Message)
+		}
+		return fmt.Errorf("azure devops: %w", statusErr)
+	}
+	if err := json.Unmarshal(resp.Raw, out); err != nil {
+		return fmt.Errorf("azure devops: decode response: %w", err)
+	}
+	return nil
+}
+
+func (c *Client) orgURL(path string) string {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	return strings.TrimSuffix(base, "/") + "/" + url.PathEscape(c.Organization) + "/" + path
+}
+
+func (c *Client) projectURL(path string) string {
+	return c.orgURL(url.PathEscape(c.Project) + "/" + path)
+}
+
+// apiLinks holds the web link the API returns with resources.
+type apiLinks struct {
+	Web struct {
+		Href string `json:"href"`
+	} `json:"web"`
+}
+
+type apiPipeline struct {
+	ID     int      `json:"id"`
+	Name   string   `json:"name"`
+	Folder string   `json:"folder"`
+	Links  apiLinks `json:"_links"`
+}
+
+func (p apiPipeline) pipeline() Pipeline {
+	return Pipeline{ID: p.ID, Name: p.Name, Folder: p.Folder, URL: p.Links.Web.Href}
+}
+
+type apiRun struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	State       string    `json:"state"`
+	Result      string    `json:"result"`
+	CreatedDate time.Time `json:"createdDate"`
+	Pipeline    struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"pipeline"`
+	Links apiLinks `json:"_links"`
+}
+
+func (r apiRun) run() Run {
+	return Run{
+		ID:         r.ID,
+		Name:       r.Name,
+		PipelineID: r.Pipeline.ID,
+		Pipeline:   r.Pipeline.Name,
+		State:      r.State,
+		Result:     r.Result,
+		Created:    r.CreatedDate,
+		URL:        r.Links.Web.Href,
+	}
+}
+
+type apiWorkItem struct {
+	ID     int `json:"id"`
+	Fields struct {
+		Type       string `json:"System.WorkItemType"`
+		Title      string `json:"System.Title"`
+		State      string `json:"System.State"`
+		AssignedTo struct {
+			DisplayName string `json:"displayName"`
+		} `json:"System.AssignedTo"`
+	} `json:"fields"`
+}
+
+func (w apiWorkItem) workItem(webURL string) WorkItem {
+	return WorkItem{
+		ID:         w.ID,
+		Type:       w.Fields.Type,
+		Title:      w.Fields.Title,
+		State:      w.Fields.State,
+		AssignedTo: w.Fields.AssignedTo.DisplayName,
+		URL:        webURL,
+	}
+}
@@ -0,0 +1,212 @@
+package devops
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anowarislam/ado/internal/httpclient"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &Client{
+		HTTP:         &httpclient.Client{},
+		BaseURL:      srv.URL,
+		Organization: "contoso",
+		Project:      "web app",
+		Token:        "pat",
+	}
+}
+
+func TestClient_Pipelines(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/contoso/web app/_apis/pipelines" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if r.URL.Query().Get("api-version") != APIVersion {
+			t.Errorf("api-version = %q", r.URL.Query().Get("api-version"))
+		}
+		want := "Basic " + base64.StdEncoding.EncodeToString([]byte(":pat"))
+		if got := r.Header.Get("Authorization"); got != want {
+			t.Errorf("Authorization = %q, want %q", got, want)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"count":2,"value":[
+			{"id":1,"name":"ci","folder":"\\","_links":{"web":{"href":"https://dev.azure.com/contoso/web/_build/definition?definitionId=1"}}},
+			{"id":7,"name":"release","folder":"\\deploy"}]}`)
+	})
+
+	pipelines, err := c.Pipelines(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pipelines) != 2 || pipelines[0].Name != "ci" || pipelines[0].URL == "" || pipelines[1].ID != 7 || pipelines[1].Folder != `\deploy` {
+		t.Errorf("Pipelines() = %+v", pipelines)
+	}
+
+	p, err := c.FindPipeline(context.Background(), "7")
+	if err != nil || p.Name != "release" {
+		t.Errorf("FindPipeline(7) = %+v, %v", p, err)
+	}
+	if p, err := c.FindPipeline(context.Background(), "ci"); err != nil || p.ID != 1 {
+		t.Errorf("FindPipeline(ci) = %+v, %v", p, err)
+	}
+	if _, err := c.FindPipeline(context.Background(), "nightly"); err == nil {
+		t.Error("FindPipeline(nightly) succeeded")
+	}
+}
+
+func TestClient_RunPipeline(t *testing.T) {
+	var body map[string]any
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/contoso/web app/_apis/pipelines/7/runs" {
+			t.Errorf("request = %s %s", r.Method, r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"id":42,"name":"20261016.1","state":"inProgress","createdDate":"2026-10-16T09:00:00Z",
+			"pipeline":{"id":7,"name":"release"},"_links":{"web":{"href":"https://example/run/42"}}}`)
+	})
+
+	run, err := c.RunPipeline(context.Background(), 7, RunOptions{Branch: "main", Variables: map[string]string{"env": "staging"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if run.ID != 42 || run.Pipeline != "release" || run.State != "inProgress" || run.URL != "https://example/run/42" || run.Created.IsZero() {
+		t.Errorf("RunPipeline() = %+v", run)
+	}
+	data, _ := json.Marshal(body)
+	want := `{"resources":{"repositories":{"self":{"refName":"refs/heads/main"}}},"variables":{"env":{"value":"staging"}}}`
+	if string(data) != want {
+		t.Errorf("body = %s, want %s", data, want)
+	}
+}
+
+func TestClient_RunPipeline_NotRetried(t *testing.T) {
+	calls := 0
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	})
+	c.HTTP.Retries = 3
+
+	if _, err := c.RunPipeline(context.Background(), 7, RunOptions{}); err == nil {
+		t.Fatal("RunPipeline() succeeded")
+	}
+	if calls != 1 {
+		t.Errorf("calls = %d, want 1", calls)
+	}
+}
+
+func TestClient_QueryWorkItems(t *testing.T) {
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/contoso/web app/_apis/wit/wiql":
+			if r.URL.Query().Get("$top") != "5" {
+				t.Errorf("$top = %q", r.URL.Query().Get("$top"))
+			}
+			var body struct{ Query string }
+			json.NewDecoder(r.Body).Decode(&body)
+			if !strings.HasPrefix(body.Query, "SELECT") {
+				t.Errorf("query = %q", body.Query)
+			}
+			io.WriteString(w, `{"workItems":[{"id":12},{"id":3}]}`)
+		case "/contoso/_apis/wit/workitems":
+			if r.URL.Query().Get("ids") != "12,3" {
+				t.Errorf("ids = %q", r.URL.Query().Get("ids"))
+			}
+			io.WriteString(w, `{"value":[
+				{"id":12,"fields":{"System.WorkItemType":"Bug","System.Title":"Crash on start","System.State":"Active","System.AssignedTo":{"displayName":"Sam Lee"}}},
+				{"id":3,"fields":{"System.WorkItemType":"Task","System.Title":"Docs","System.State":"New"}}]}`)
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	})
+
+	items, err := c.QueryWorkItems(context.Background(), "SELECT [System.Id] FROM WorkItems", 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].ID != 12 || items[0].Type != "Bug" || items[0].AssignedTo != "Sam Lee" || items[1].Title != "Docs" {
+		t.Errorf("QueryWorkItems() = %+v", items)
+	}
+	if !strings.HasSuffix(items[0].URL, "/contoso/web%20app/_workitems/edit/12") {
+		t.Errorf("URL = %q", items[0].URL)
+	}
+}
+
+func TestClient_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		client  func(*Client)
+		want    string
+		is      error
+	}{
+		{
+			name:    "unauthorized",
+			handler: func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusUnauthorized) },
+			is:      ErrUnauthorized,
+		},
+		{
+			name: "sign-in page",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html")
+				w.WriteHeader(http.StatusNonAuthoritativeInfo)
+				io.WriteString(w, "<html>sign in</html>")
+			},
+			is: ErrUnauthorized,
+		},
+		{
+			name: "api message",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				io.WriteString(w, `{"message":"TF200016: The following project does not exist: web app."}`)
+			},
+			want: "404 Not Found: TF200016",
+		},
+		{
+			name:    "missing project",
+			handler: func(w http.ResponseWriter, r *http.Request) { t.Error("request sent") },
+			client:  func(c *Client) { c.Project = "" },
+			want:    "organization and project are required",
+		},
+		{
+			name:    "missing token",
+			handler: func(w http.ResponseWriter, r *http.Request) { t.Error("request sent") },
+			client:  func(c *Client) { c.Token = "" },
+			want:    "token is empty",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, tt.handler)
+			if tt.client != nil {
+				tt.client(c)
+			}
+			_, err := c.Pipelines(context.Background())
+			if err == nil {
+				t.Fatal("Pipelines() succeeded")
+			}
+			if tt.is != nil && !errors.Is(err, tt.is) {
+				t.Errorf("error = %v, want %v", err, tt.is)
+			}
+			if tt.want != "" && !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
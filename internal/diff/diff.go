@@ -0,0 +1,287 @@
+// Package diff compares structured documents (JSON, YAML, or decoded Go
+// values) by key and type rather than by line.
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Change operations, named as in JSON Patch (RFC 6902).
+const (
+	OpAdd     = "add"
+	OpRemove  = "remove"
+	OpReplace = "replace"
+)
+
+// Change is one difference between two documents. Path is a JSON Pointer
+// (RFC 6901) into the documents; From is absent for adds and To for
+// removes.
+type Change struct {
+	Op   string
+	Path string
+	From any
+	To   any
+}
+
+// changeFields is the serialized form of a Change. Pointers keep null
+// values distinct from absent ones.
+type changeFields struct {
+	Op   string `json:"op" yaml:"op"`
+	Path string `json:"path" yaml:"path"`
+	From *any   `json:"from,omitempty" yaml:"from,omitempty"`
+	To   *any   `json:"to,omitempty" yaml:"to,omitempty"`
+}
+
+func (c Change) fields() changeFields {
+	f := changeFields{Op: c.Op, Path: c.Path}
+	if c.Op != OpAdd {
+		f.From = &c.From
+	}
+	if c.Op != OpRemove {
+		f.To = &c.To
+	}
+	return f
+}
+
+// MarshalJSON includes from and to whenever the operation has them, even
+// when they are null.
+func (c Change) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.fields())
+}
+
+// MarshalYAML mirrors MarshalJSON.
+func (c Change) MarshalYAML() (any, error) {
+	return c.fields(), nil
+}
+
+// Compare returns the changes that turn a into b. Map keys are compared
+// regardless of order; list elements are compared by index. Values of
+// different types are never equal, so 1 and "1" differ, but integers and
+// floats compare by value.
+func Compare(a, b any) []Change {
+	var changes []Change
+	compare("", Normalize(a), Normalize(b), &changes)
+	return changes
+}
+
+// Equal reports whether a and b are structurally equal.
+func Equal(a, b any) bool {
+	return len(Compare(a, b)) == 0
+}
+
+func compare(path string, a, b any, changes *[]Change) {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, key := range sortedKeys(av, bv) {
+			child := path + "/" + escapePointer(key)
+			aval, inA := av[key]
+			bval, inB := bv[key]
+			switch {
+			case !inB:
+				*changes = append(*changes, Change{Op: OpRemove, Path: child, From: aval})
+			case !inA:
+				*changes = append(*changes, Change{Op: OpAdd, Path: child, To: bval})
+			default:
+				compare(child, aval, bval, changes)
+			}
+		}
+		return
+
+	case []any:
+		bv, ok := b.([]any)
+		if !ok {
+			break
+		}
+		shared := min(len(av), len(bv))
+		for i := range shared {
+			compare(path+"/"+strconv.Itoa(i), av[i], bv[i], changes)
+		}
+		for i := shared; i < len(bv); i++ {
+			*changes = append(*changes, Change{Op: OpAdd, Path: path + "/" + strconv.Itoa(i), To: bv[i]})
+		}
+		// Remove from the end so each index is valid when applied in order.
+		for i := len(av) - 1; i >= shared; i-- {
+			*changes = append(*changes, Change{Op: OpRemove, Path: path + "/" + strconv.Itoa(i), From: av[i]})
+		}
+		return
+
+	default:
+		if reflect.DeepEqual(a, b) {
+			return
+		}
+	}
+	*changes = append(*changes, Change{Op: OpReplace, Path: path, From: a, To: b})
+}
+
+func sortedKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// escapePointer escapes a key for use in a JSON Pointer.
+func escapePointer(key string) string {
+	return strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+}
+
+// Normalize converts decoded JSON or YAML into a canonical form: maps
+// with string keys, []any lists, float64 numbers, and RFC 3339 strings
+// for timestamps.
+func Normalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			out[k] = Normalize(val)
+		}
+		return out
+	case map[any]any:
+		out := make(map[string]any, len(v))
+		for k, val := range v {
+			out[fmt.Sprint(k)] = Normalize(val)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, val := range v {
+			out[i] = Normalize(val)
+		}
+		return out
+	case int:
+		return float64(v)
+	case int64:
+		return float64(v)
+	case uint64:
+		return float64(v)
+	case float32:
+		return float64(v)
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	default:
+		return v
+	}
+}
+
+// FormatValue renders a value compactly for text output.
+func FormatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+			return strconv.FormatInt(int64(v), 10)
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case map[string]any:
+		parts := make([]string, 0, len(v))
+		for _, k := range sortedKeys(v, nil) {
+			parts = append(parts, strconv.Quote(k)+": "+FormatValue(v[k]))
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	case []any:
+		parts := make([]string, len(v))
+		for i, val := range v {
+			parts[i] = FormatValue(val)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// typeName names a normalized value's type for text output.
+func typeName(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// FormatText renders changes one per line: "+ path: value" for adds,
+// "- path: value" for removes, and "~ path: old -> new" for replaces,
+// noting type changes.
+func FormatText(changes []Change) string {
+	var b strings.Builder
+	for _, c := range changes {
+		path := c.Path
+		if path == "" {
+			path = "/"
+		}
+		switch c.Op {
+		case OpAdd:
+			fmt.Fprintf(&b, "+ %s: %s\n", path, FormatValue(c.To))
+		case OpRemove:
+			fmt.Fprintf(&b, "- %s: %s\n", path, FormatValue(c.From))
+		default:
+			fmt.Fprintf(&b, "~ %s: %s -> %s", path, FormatValue(c.From), FormatValue(c.To))
+			if from, to := typeName(c.From), typeName(c.To); from != to {
+				fmt.Fprintf(&b, " (%s -> %s)", from, to)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// PatchOp is a JSON Patch (RFC 6902) operation.
+type PatchOp struct {
+	Op    string `json:"op" yaml:"op"`
+	Path  string `json:"path" yaml:"path"`
+	Value any    `json:"value" yaml:"value"`
+}
+
+// MarshalJSON omits value for remove operations only, so adding or
+// replacing with null stays valid.
+func (p PatchOp) MarshalJSON() ([]byte, error) {
+	if p.Op == OpRemove {
+		return json.Marshal(map[string]any{"op": p.Op, "path": p.Path})
+	}
+	type plain PatchOp
+	return json.Marshal(plain(p))
+}
+
+// Patch converts changes into a JSON Patch document.
+func Patch(changes []Change) []PatchOp {
+	ops := make([]PatchOp, 0, len(changes))
+	for _, c := range changes {
+		op := PatchOp{Op: c.Op, Path: c.Path}
+		if c.Op != OpRemove {
+			op.Value = c.To
+		}
+		ops = append(ops, op)
+	}
+	return ops
+}
@@ -0,0 +1,155 @@
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func decode(t *testing.T, name, src string) any {
+	t.Helper()
+	v, err := Decode(strings.NewReader(src), name)
+	if err != nil {
+		t.Fatalf("Decode(%s) error = %v", name, err)
+	}
+	return v
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want string
+	}{
+		{
+			name: "key order and format ignored",
+			a:    `{"b": 1, "a": [1, 2]}`,
+			b:    "a: [1, 2.0]\nb: 1\n",
+			want: "",
+		},
+		{
+			name: "nested replace add remove",
+			a:    "server:\n  port: 80\n  host: a\n",
+			b:    "server:\n  port: 8080\n  tls: true\n",
+			want: "- /server/host: \"a\"\n~ /server/port: 80 -> 8080\n+ /server/tls: true\n",
+		},
+		{
+			name: "type change",
+			a:    "port: 80",
+			b:    `port: "80"`,
+			want: "~ /port: 80 -> \"80\" (number -> string)\n",
+		},
+		{
+			name: "list grows and shrinks",
+			a:    "a: [1, 2, 3]\nb: [x]",
+			b:    "a: [1]\nb: [x, y, z]",
+			want: "- /a/2: 3\n- /a/1: 2\n+ /b/1: \"y\"\n+ /b/2: \"z\"\n",
+		},
+		{
+			name: "object replaced by scalar",
+			a:    "a: {x: 1}",
+			b:    "a: null",
+			want: "~ /a: {\"x\": 1} -> null (object -> null)\n",
+		},
+		{
+			name: "pointer escaping",
+			a:    `{"a/b": 1, "c~d": 1}`,
+			b:    `{"a/b": 2, "c~d": 2}`,
+			want: "~ /a~1b: 1 -> 2\n~ /c~0d: 1 -> 2\n",
+		},
+		{
+			name: "root scalar",
+			a:    "1",
+			b:    "2",
+			want: "~ /: 1 -> 2\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nameA := "a.yaml"
+			if strings.HasPrefix(tt.a, "{") {
+				nameA = "a.json"
+			}
+			a := decode(t, nameA, tt.a)
+			b := decode(t, "b.yaml", tt.b)
+			got := FormatText(Compare(a, b))
+			if got != tt.want {
+				t.Errorf("FormatText(Compare()) =\n%s\nwant\n%s", got, tt.want)
+			}
+			if Equal(a, b) != (tt.want == "") {
+				t.Errorf("Equal() = %v", Equal(a, b))
+			}
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	a := decode(t, "a.yaml", "keep: 1\nold: x\nlist: [1, 2]\nval: 1")
+	b := decode(t, "b.yaml", "keep: 1\nnew: null\nlist: [1]\nval: null")
+
+	data, err := json.Marshal(Patch(Compare(a, b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"op":"remove","path":"/list/1"},{"op":"add","path":"/new","value":null},{"op":"remove","path":"/old"},{"op":"replace","path":"/val","value":null}]`
+	if string(data) != want {
+		t.Errorf("Patch() =\n%s\nwant\n%s", data, want)
+	}
+}
+
+func TestChange_Marshal(t *testing.T) {
+	changes := []Change{
+		{Op: OpAdd, Path: "/a", To: nil},
+		{Op: OpRemove, Path: "/b", From: 1.0},
+		{Op: OpReplace, Path: "/c", From: "x", To: nil},
+	}
+
+	data, err := json.Marshal(changes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[{"op":"add","path":"/a","to":null},{"op":"remove","path":"/b","from":1},{"op":"replace","path":"/c","from":"x","to":null}]`
+	if string(data) != want {
+		t.Errorf("json = %s, want %s", data, want)
+	}
+
+	out, err := yaml.Marshal(changes[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(out); got != "op: replace\npath: /c\nfrom: x\nto: null\n" {
+		t.Errorf("yaml = %q", got)
+	}
+}
+
+func TestFromValue(t *testing.T) {
+	type inner struct {
+		Port int `yaml:"port"`
+	}
+	type doc struct {
+		Name  string `yaml:"name"`
+		Inner inner  `yaml:"inner"`
+	}
+
+	got, err := FromValue(doc{Name: "x", Inner: inner{Port: 80}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"name": "x", "inner": map[string]any{"port": float64(80)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FromValue() = %#v, want %#v", got, want)
+	}
+}
+
+func TestDecode_Errors(t *testing.T) {
+	if _, err := Decode(strings.NewReader("{"), "bad.json"); err == nil || !strings.Contains(err.Error(), "parse bad.json") {
+		t.Errorf("Decode(bad json) error = %v", err)
+	}
+	if _, err := Decode(strings.NewReader("a: [1"), "bad.yaml"); err == nil || !strings.Contains(err.Error(), "parse bad.yaml") {
+		t.Errorf("Decode(bad yaml) error = %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Decode parses a JSON or YAML document. Files named *.json are parsed as
+// JSON; anything else as YAML, which also accepts JSON. name is used for
+// format detection and error messages.
+func Decode(r io.Reader, name string) (any, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+
+	var v any
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		if err := json.Unmarshal(data, &v); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", name, err)
+		}
+		return Normalize(v), nil
+	}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", name, err)
+	}
+	return Normalize(v), nil
+}
+
+// Load decodes the document at path.
+func Load(path string) (any, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Decode(f, path)
+}
+
+// FromValue converts a Go value into a comparable document by encoding
+// it as YAML and decoding the result, so struct field names follow their
+// yaml tags.
+func FromValue(v any) (any, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("encode document: %w", err)
+	}
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("decode document: %w", err)
+	}
+	return Normalize(doc), nil
+}
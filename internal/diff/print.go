@@ -0,0 +1,82 @@
+package diff
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// Output modes accepted by Print in addition to ui's json and yaml.
+const (
+	OutputUnified = "unified"
+	OutputPatch   = "patch"
+)
+
+// Outputs lists the modes accepted by Print, for flag completion.
+var Outputs = []string{"text", OutputUnified, OutputPatch, "json", "yaml"}
+
+// OutputHelp describes the modes accepted by Print, for flag help.
+const OutputHelp = "Output format: text, unified, patch (JSON Patch), json, yaml"
+
+// ErrDiffer is returned by commands run with --exit-code when the
+// documents differ.
+var ErrDiffer = errors.New("documents differ")
+
+// ValidateOutput reports whether output is a mode accepted by Print.
+func ValidateOutput(output string) error {
+	if output == OutputUnified || output == OutputPatch {
+		return nil
+	}
+	_, err := ui.ParseOutputFormat(output)
+	return err
+}
+
+// Result is the structured output of a comparison.
+type Result struct {
+	From    string   `json:"from" yaml:"from"`
+	To      string   `json:"to" yaml:"to"`
+	Equal   bool     `json:"equal" yaml:"equal"`
+	Changes []Change `json:"changes" yaml:"changes"`
+}
+
+// Print compares a and b and writes the result in the given mode: text
+// (one line per change), unified (line diff of canonical YAML), patch (a
+// JSON Patch document), json, or yaml. It reports whether the documents
+// differ.
+func Print(w io.Writer, output string, a, b any, nameA, nameB string) (bool, error) {
+	changes := Compare(a, b)
+	if changes == nil {
+		changes = []Change{}
+	}
+	differ := len(changes) > 0
+
+	switch output {
+	case OutputUnified:
+		text, err := Unified(a, b, nameA, nameB, DefaultContext)
+		if err != nil {
+			return differ, err
+		}
+		_, err = io.WriteString(w, text)
+		return differ, err
+
+	case OutputPatch:
+		data, err := json.MarshalIndent(Patch(changes), "", "  ")
+		if err != nil {
+			return differ, fmt.Errorf("serialize patch: %w", err)
+		}
+		_, err = w.Write(append(data, '\n'))
+		return differ, err
+	}
+
+	format, err := ui.ParseOutputFormat(output)
+	if err != nil {
+		return differ, err
+	}
+	result := Result{From: nameA, To: nameB, Equal: !differ, Changes: changes}
+	return differ, ui.PrintOutput(w, format, result, func() (string, error) {
+		return FormatText(changes), nil
+	})
+}
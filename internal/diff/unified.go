@@ -0,0 +1,205 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultContext is the number of unchanged lines shown around each hunk.
+const DefaultContext = 3
+
+// Unified renders a unified line diff of a and b after converting both to
+// canonical YAML (keys sorted, consistent formatting), so key order and
+// formatting differences do not show up. It returns "" when the documents
+// are equal.
+func Unified(a, b any, nameA, nameB string, context int) (string, error) {
+	linesA, err := canonicalLines(a)
+	if err != nil {
+		return "", err
+	}
+	linesB, err := canonicalLines(b)
+	if err != nil {
+		return "", err
+	}
+
+	edits := lineEdits(linesA, linesB)
+	hunks := groupHunks(edits, context)
+	if len(hunks) == 0 {
+		return "", nil
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s\n+++ %s\n", nameA, nameB)
+	for _, h := range hunks {
+		fmt.Fprintf(&out, "@@ -%s +%s @@\n", hunkRange(h.startA, h.countA), hunkRange(h.startB, h.countB))
+		for _, e := range h.edits {
+			out.WriteByte(e.kind)
+			out.WriteString(e.line)
+			out.WriteByte('\n')
+		}
+	}
+	return out.String(), nil
+}
+
+func canonicalLines(v any) ([]string, error) {
+	data, err := yaml.Marshal(Normalize(v))
+	if err != nil {
+		return nil, fmt.Errorf("render document: %w", err)
+	}
+	text := strings.TrimSuffix(string(data), "\n")
+	if text == "" {
+		return nil, nil
+	}
+	return strings.Split(text, "\n"), nil
+}
+
+// edit is one line of a line diff: ' ' (unchanged), '-', or '+'.
+type edit struct {
+	kind byte
+	line string
+	// lineA and lineB are 1-based line numbers in each input (0 if absent).
+	lineA, lineB int
+}
+
+// lineEdits returns a shortest edit script from a to b (Myers' algorithm).
+func lineEdits(a, b []string) []edit {
+	n, m := len(a), len(b)
+	maxD := n + m
+	offset := maxD + 1
+	v := make([]int, 2*maxD+3)
+	var trace [][]int
+
+	for d := 0; d <= maxD; d++ {
+		trace = append(trace, append([]int(nil), v...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, offset, d)
+			}
+		}
+	}
+	return nil
+}
+
+// backtrack walks the Myers trace back from (len(a), len(b)).
+func backtrack(a, b []string, trace [][]int, offset, d int) []edit {
+	var edits []edit
+	x, y := len(a), len(b)
+	for ; d > 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			edits = append(edits, edit{kind: ' ', line: a[x-1], lineA: x, lineB: y})
+			x--
+			y--
+		}
+		if x == prevX {
+			edits = append(edits, edit{kind: '+', line: b[y-1], lineB: y})
+		} else {
+			edits = append(edits, edit{kind: '-', line: a[x-1], lineA: x})
+		}
+		x, y = prevX, prevY
+	}
+	for x > 0 && y > 0 {
+		edits = append(edits, edit{kind: ' ', line: a[x-1], lineA: x, lineB: y})
+		x--
+		y--
+	}
+
+	for i, j := 0, len(edits)-1; i < j; i, j = i+1, j-1 {
+		edits[i], edits[j] = edits[j], edits[i]
+	}
+	return edits
+}
+
+type hunk struct {
+	startA, countA int
+	startB, countB int
+	edits          []edit
+}
+
+// groupHunks splits edits into hunks with up to context unchanged lines
+// around each change, merging hunks whose context would overlap.
+func groupHunks(edits []edit, context int) []hunk {
+	var ranges [][2]int
+	for i, e := range edits {
+		if e.kind == ' ' {
+			continue
+		}
+		start, end := max(i-context, 0), min(i+context+1, len(edits))
+		if n := len(ranges); n > 0 && start <= ranges[n-1][1] {
+			ranges[n-1][1] = end
+		} else {
+			ranges = append(ranges, [2]int{start, end})
+		}
+	}
+
+	hunks := make([]hunk, 0, len(ranges))
+	for _, r := range ranges {
+		hunks = append(hunks, makeHunk(edits, r[0], r[1]))
+	}
+	return hunks
+}
+
+func makeHunk(edits []edit, start, end int) hunk {
+	h := hunk{edits: edits[start:end]}
+	for _, e := range h.edits {
+		if e.kind != '+' {
+			if h.countA == 0 {
+				h.startA = e.lineA
+			}
+			h.countA++
+		}
+		if e.kind != '-' {
+			if h.countB == 0 {
+				h.startB = e.lineB
+			}
+			h.countB++
+		}
+	}
+	// An empty side is positioned after the preceding line.
+	if h.countA == 0 {
+		h.startA = precedingLine(edits, start, func(e edit) int { return e.lineA })
+	}
+	if h.countB == 0 {
+		h.startB = precedingLine(edits, start, func(e edit) int { return e.lineB })
+	}
+	return h
+}
+
+func precedingLine(edits []edit, start int, line func(edit) int) int {
+	for i := start - 1; i >= 0; i-- {
+		if n := line(edits[i]); n > 0 {
+			return n
+		}
+	}
+	return 0
+}
+
+func hunkRange(start, count int) string {
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
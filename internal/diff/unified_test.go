@@ -0,0 +1,94 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	a := map[string]any{"name": "ado", "port": 80, "tags": []any{"a", "b"}}
+	b := map[string]any{"tags": []any{"a", "b"}, "port": 8080, "name": "ado"}
+
+	got, err := Unified(a, b, "a.yaml", "b.yaml", DefaultContext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `--- a.yaml
++++ b.yaml
+@@ -1,5 +1,5 @@
+ name: ado
+-port: 80
++port: 8080
+ tags:
+     - a
+     - b
+`
+	if got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+
+	if got, _ := Unified(a, a, "a", "b", DefaultContext); got != "" {
+		t.Errorf("Unified(equal) = %q, want empty", got)
+	}
+}
+
+func TestUnified_Hunks(t *testing.T) {
+	// 20 keys; change the 2nd and 18th so they land in separate hunks.
+	a := map[string]any{}
+	b := map[string]any{}
+	for i := range 20 {
+		key := fmt.Sprintf("k%02d", i)
+		a[key], b[key] = i, i
+	}
+	b["k01"] = "changed"
+	delete(b, "k17")
+	b["k20"] = "added"
+
+	got, err := Unified(a, b, "a", "b", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `--- a
++++ b
+@@ -1,3 +1,3 @@
+ k00: 0
+-k01: 1
++k01: changed
+ k02: 2
+@@ -17,4 +17,4 @@
+ k16: 16
+-k17: 17
+ k18: 18
+ k19: 19
++k20: added
+`
+	if got != want {
+		t.Errorf("Unified() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestLineEdits(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want string
+	}{
+		{"", "", ""},
+		{"", "x", "+x"},
+		{"x", "", "-x"},
+		{"a b c", "a c", " a -b  c"},
+		{"a b c", "a x c", " a -b +x  c"},
+		{"a b c d", "b c d e", "-a  b  c  d +e"},
+	}
+
+	for _, tt := range tests {
+		edits := lineEdits(strings.Fields(tt.a), strings.Fields(tt.b))
+		var parts []string
+		for _, e := range edits {
+			parts = append(parts, string(e.kind)+e.line)
+		}
+		if got := strings.Join(parts, " "); got != tt.want {
+			t.Errorf("lineEdits(%q, %q) = %q, want %q", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
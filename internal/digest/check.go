@@ -0,0 +1,108 @@
+package digest
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"strings"
+)
+
+// Check statuses.
+const (
+	StatusOK      = "ok"
+	StatusFailed  = "failed"
+	StatusMissing = "missing"
+	StatusError   = "error"
+)
+
+var (
+	// gnuLine matches sha256sum-style lines: "<hex>  name" or "<hex> *name".
+	gnuLine = regexp.MustCompile(`^([0-9a-fA-F]+) [ *](.+)$`)
+	// bsdLine matches BSD/--tag style lines: "SHA256 (name) = <hex>".
+	bsdLine = regexp.MustCompile(`^([A-Za-z0-9-]+) \((.+)\) = ([0-9a-fA-F]+)$`)
+)
+
+// Checksum is one expected digest from a checksum file.
+type Checksum struct {
+	// Algorithm is set for BSD-style lines; otherwise the caller's default
+	// applies.
+	Algorithm string
+	Path      string
+	Digest    string
+	Line      int
+}
+
+// ParseChecksums reads a checksum file in GNU (sha256sum) or BSD (--tag)
+// format. Blank lines and lines starting with # are ignored. name is used
+// in error messages.
+func ParseChecksums(r io.Reader, name string) ([]Checksum, error) {
+	var sums []Checksum
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		if m := gnuLine.FindStringSubmatch(text); m != nil {
+			sums = append(sums, Checksum{Path: m[2], Digest: strings.ToLower(m[1]), Line: line})
+			continue
+		}
+		if m := bsdLine.FindStringSubmatch(text); m != nil {
+			sums = append(sums, Checksum{Algorithm: strings.ToLower(m[1]), Path: m[2], Digest: strings.ToLower(m[3]), Line: line})
+			continue
+		}
+		return nil, fmt.Errorf("%s:%d: invalid checksum line", name, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	if len(sums) == 0 {
+		return nil, fmt.Errorf("%s: no checksums found", name)
+	}
+	return sums, nil
+}
+
+// CheckResult is the outcome of verifying one checksum.
+type CheckResult struct {
+	Path      string `json:"path" yaml:"path"`
+	Algorithm string `json:"algorithm" yaml:"algorithm"`
+	Status    string `json:"status" yaml:"status"`
+	Expected  string `json:"expected" yaml:"expected"`
+	Actual    string `json:"actual,omitempty" yaml:"actual,omitempty"`
+	Error     string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Verify hashes each checksum's path and compares digests. Directories are
+// hashed with Dir. algorithm applies to checksums without their own.
+func Verify(sums []Checksum, algorithm string) []CheckResult {
+	results := make([]CheckResult, 0, len(sums))
+	for _, sum := range sums {
+		algo := sum.Algorithm
+		if algo == "" {
+			algo = algorithm
+		}
+		res := CheckResult{Path: sum.Path, Algorithm: algo, Expected: sum.Digest}
+
+		entry, err := Path(sum.Path, algo)
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			res.Status = StatusMissing
+		case err != nil:
+			res.Status = StatusError
+			res.Error = err.Error()
+		case entry.Digest == sum.Digest:
+			res.Status = StatusOK
+			res.Actual = entry.Digest
+		default:
+			res.Status = StatusFailed
+			res.Actual = entry.Digest
+		}
+		results = append(results, res)
+	}
+	return results
+}
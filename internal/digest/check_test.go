@@ -0,0 +1,73 @@
+package digest
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseChecksums(t *testing.T) {
+	input := `# release checksums
+2CF24DBA5FB0A30E26E83B2AC5B9E29E1B161E5C1FA7425E73043362938B9824  hello.txt
+5d41402abc4b2a76b9719d911017c592 *bin/hello.exe
+
+MD5 (with space.txt) = 5d41402abc4b2a76b9719d911017c592
+`
+	sums, err := ParseChecksums(strings.NewReader(input), "sums.txt")
+	if err != nil {
+		t.Fatalf("ParseChecksums() error = %v", err)
+	}
+	want := []Checksum{
+		{Path: "hello.txt", Digest: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824", Line: 2},
+		{Path: "bin/hello.exe", Digest: "5d41402abc4b2a76b9719d911017c592", Line: 3},
+		{Algorithm: "md5", Path: "with space.txt", Digest: "5d41402abc4b2a76b9719d911017c592", Line: 5},
+	}
+	if !reflect.DeepEqual(sums, want) {
+		t.Errorf("ParseChecksums() = %+v, want %+v", sums, want)
+	}
+}
+
+func TestParseChecksums_Errors(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"abc\n", "sums.txt:1: invalid checksum line"},
+		{"# only comments\n\n", "sums.txt: no checksums found"},
+	}
+	for _, tt := range tests {
+		if _, err := ParseChecksums(strings.NewReader(tt.input), "sums.txt"); err == nil || err.Error() != tt.want {
+			t.Errorf("ParseChecksums(%q) error = %v, want %q", tt.input, err, tt.want)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "hello.txt"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	hello := filepath.Join(dir, "hello.txt")
+
+	sums := []Checksum{
+		{Path: hello, Digest: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{Path: hello, Digest: "0000"},
+		{Algorithm: "md5", Path: hello, Digest: "5d41402abc4b2a76b9719d911017c592"},
+		{Path: filepath.Join(dir, "gone.txt"), Digest: "00"},
+		{Algorithm: "crc32", Path: hello, Digest: "00"},
+	}
+	results := Verify(sums, SHA256)
+
+	var statuses []string
+	for _, r := range results {
+		statuses = append(statuses, r.Algorithm+":"+r.Status)
+	}
+	if got, want := strings.Join(statuses, ","), "sha256:ok,sha256:failed,md5:ok,sha256:missing,crc32:error"; got != want {
+		t.Errorf("statuses = %s, want %s", got, want)
+	}
+	if results[1].Actual != sums[0].Digest {
+		t.Errorf("failed result actual = %q", results[1].Actual)
+	}
+}
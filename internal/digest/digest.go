@@ -0,0 +1,168 @@
+// Package digest computes file, directory, and stream digests and verifies
+// checksum files.
+package digest
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Supported algorithms.
+const (
+	SHA256  = "sha256"
+	SHA512  = "sha512"
+	BLAKE2b = "blake2b"
+	MD5     = "md5"
+)
+
+// DefaultAlgorithm is used when none is specified.
+const DefaultAlgorithm = SHA256
+
+// Algorithms lists the supported algorithms.
+var Algorithms = []string{SHA256, SHA512, BLAKE2b, MD5}
+
+// Entry kinds.
+const (
+	KindFile  = "file"
+	KindDir   = "dir"
+	KindStdin = "stdin"
+)
+
+// New returns a hash for algorithm.
+func New(algorithm string) (hash.Hash, error) {
+	switch strings.ToLower(algorithm) {
+	case SHA256:
+		return sha256.New(), nil
+	case SHA512:
+		return sha512.New(), nil
+	case BLAKE2b, "blake2", "blake2b-512":
+		return blake2b.New512(nil)
+	case MD5:
+		return md5.New(), nil
+	default:
+		return nil, fmt.Errorf("unsupported algorithm %q (supported: %s)", algorithm, strings.Join(Algorithms, ", "))
+	}
+}
+
+// Entry is the digest of one input.
+type Entry struct {
+	Path      string `json:"path" yaml:"path"`
+	Kind      string `json:"kind" yaml:"kind"`
+	Digest    string `json:"digest" yaml:"digest"`
+	SizeBytes int64  `json:"size_bytes" yaml:"size_bytes"`
+}
+
+// Reader hashes everything read from r.
+func Reader(r io.Reader, algorithm string) (string, int64, error) {
+	h, err := New(algorithm)
+	if err != nil {
+		return "", 0, err
+	}
+	n, err := io.Copy(h, r)
+	if err != nil {
+		return "", n, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
+
+// Path hashes a file, or a directory tree with Dir.
+func Path(path, algorithm string) (Entry, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	if info.IsDir() {
+		sum, size, err := Dir(path, algorithm)
+		if err != nil {
+			return Entry{}, err
+		}
+		return Entry{Path: path, Kind: KindDir, Digest: sum, SizeBytes: size}, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	defer f.Close()
+	sum, size, err := Reader(f, algorithm)
+	if err != nil {
+		return Entry{}, fmt.Errorf("read %s: %w", path, err)
+	}
+	return Entry{Path: path, Kind: KindFile, Digest: sum, SizeBytes: size}, nil
+}
+
+// Dir returns a deterministic Merkle-style digest of a directory tree and
+// the total size of its files.
+//
+// Each directory's digest is the hash of one line per child, sorted by
+// name: "<kind> <digest> <name>\n", where kind is f (file), d (directory),
+// or l (symlink, hashed by its target). Permissions, timestamps, and
+// ownership do not affect the digest, so identical trees hash equally on
+// every platform.
+func Dir(path, algorithm string) (string, int64, error) {
+	h, err := New(algorithm)
+	if err != nil {
+		return "", 0, err
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return "", 0, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+
+	var total int64
+	for _, entry := range entries {
+		child := filepath.Join(path, entry.Name())
+		var kind, sum string
+		var size int64
+
+		switch {
+		case entry.Type()&fs.ModeSymlink != 0:
+			target, err := os.Readlink(child)
+			if err != nil {
+				return "", 0, err
+			}
+			kind = "l"
+			sum, _, err = Reader(strings.NewReader(filepath.ToSlash(target)), algorithm)
+			if err != nil {
+				return "", 0, err
+			}
+		case entry.IsDir():
+			kind = "d"
+			if sum, size, err = Dir(child, algorithm); err != nil {
+				return "", 0, err
+			}
+		case entry.Type().IsRegular():
+			kind = "f"
+			f, err := os.Open(child)
+			if err != nil {
+				return "", 0, err
+			}
+			sum, size, err = Reader(f, algorithm)
+			f.Close()
+			if err != nil {
+				return "", 0, fmt.Errorf("read %s: %w", child, err)
+			}
+		default:
+			// Sockets, devices, and pipes have no stable content.
+			continue
+		}
+
+		total += size
+		fmt.Fprintf(h, "%s %s %s\n", kind, sum, entry.Name())
+	}
+	return hex.EncodeToString(h.Sum(nil)), total, nil
+}
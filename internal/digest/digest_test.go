@@ -0,0 +1,153 @@
+package digest
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestReader(t *testing.T) {
+	tests := []struct {
+		algorithm string
+		want      string
+	}{
+		{SHA256, "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+		{SHA512, "9b71d224bd62f3785d96d46ad3ea3d73319bfbc2890caadae2dff72519673ca72323c3d99ba5c11d7c7acc6e14b8c5da0c4663475c2e5c3adef46f73bcdec043"},
+		{BLAKE2b, "e4cfa39a3d37be31c59609e807970799caa68a19bfaa15135f165085e01d41a65ba1e1b146aeb6bd0092b49eac214c103ccfa3a365954bbbe52f74a2b3620c94"},
+		{MD5, "5d41402abc4b2a76b9719d911017c592"},
+		{"SHA256", "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.algorithm, func(t *testing.T) {
+			got, n, err := Reader(strings.NewReader("hello"), tt.algorithm)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || n != 5 {
+				t.Errorf("Reader() = %s, %d; want %s, 5", got, n, tt.want)
+			}
+		})
+	}
+
+	if _, _, err := Reader(strings.NewReader(""), "crc32"); err == nil || !strings.Contains(err.Error(), "unsupported algorithm") {
+		t.Errorf("Reader(crc32) error = %v", err)
+	}
+}
+
+// writeTree creates files (relative path -> content) under a new temp dir.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestDir(t *testing.T) {
+	files := map[string]string{"a.txt": "a", "sub/b.txt": "bb", "sub/deep/c.txt": "ccc"}
+	first := writeTree(t, files)
+	second := writeTree(t, files)
+
+	sum1, size, err := Dir(first, SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 6 {
+		t.Errorf("size = %d, want 6", size)
+	}
+
+	// Identical content in another location, with different permissions,
+	// hashes the same.
+	if err := os.Chmod(filepath.Join(second, "a.txt"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	sum2, _, err := Dir(second, SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum1 != sum2 {
+		t.Errorf("identical trees hash differently: %s vs %s", sum1, sum2)
+	}
+
+	changes := map[string]func(root string) error{
+		"content": func(root string) error {
+			return os.WriteFile(filepath.Join(root, "sub", "deep", "c.txt"), []byte("cc!"), 0o644)
+		},
+		"rename": func(root string) error {
+			return os.Rename(filepath.Join(root, "a.txt"), filepath.Join(root, "z.txt"))
+		},
+		"empty dir": func(root string) error {
+			return os.Mkdir(filepath.Join(root, "empty"), 0o755)
+		},
+	}
+	for name, change := range changes {
+		t.Run(name, func(t *testing.T) {
+			root := writeTree(t, files)
+			if err := change(root); err != nil {
+				t.Fatal(err)
+			}
+			got, _, err := Dir(root, SHA256)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got == sum1 {
+				t.Errorf("digest unchanged after %s change", name)
+			}
+		})
+	}
+}
+
+func TestDir_Symlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symlinks need privileges on Windows")
+	}
+	root := writeTree(t, map[string]string{"target.txt": "x"})
+	before, _, err := Dir(root, SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink("target.txt", filepath.Join(root, "link")); err != nil {
+		t.Fatal(err)
+	}
+	after, size, err := Dir(root, SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after == before || size != 1 {
+		t.Errorf("symlink not hashed by target: before=%s after=%s size=%d", before, after, size)
+	}
+}
+
+func TestPath(t *testing.T) {
+	root := writeTree(t, map[string]string{"f.txt": "hello"})
+
+	entry, err := Path(filepath.Join(root, "f.txt"), SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Kind != KindFile || entry.SizeBytes != 5 || !strings.HasPrefix(entry.Digest, "2cf24dba") {
+		t.Errorf("Path(file) = %+v", entry)
+	}
+
+	entry, err = Path(root, SHA256)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.Kind != KindDir || entry.SizeBytes != 5 {
+		t.Errorf("Path(dir) = %+v", entry)
+	}
+
+	if _, err := Path(filepath.Join(root, "missing"), SHA256); !os.IsNotExist(err) {
+		t.Errorf("Path(missing) error = %v", err)
+	}
+}
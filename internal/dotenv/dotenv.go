@@ -0,0 +1,254 @@
+// Package dotenv parses .env files and resolves them against the process
+// environment.
+package dotenv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/anowarislam/ado/internal/process"
+)
+
+// Var is a variable assignment read from a dotenv file.
+type Var struct {
+	Key   string
+	Value string
+	// interpolate is false for single-quoted values.
+	interpolate bool
+}
+
+// Parse reads assignments from r. Lines have the form KEY=VALUE, with an
+// optional "export " prefix. Blank lines and lines starting with # are
+// skipped. Values may be:
+//
+//   - unquoted: trimmed, and cut at " #" to allow trailing comments
+//   - 'single-quoted': taken literally, never interpolated
+//   - "double-quoted": may span lines and understands \n, \t, \", \\ and \$
+//
+// Unquoted and double-quoted values are interpolated by Resolve. name is
+// used in error messages.
+func Parse(r io.Reader, name string) ([]Var, error) {
+	var vars []Var
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		key, value, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || !validKey(key) {
+			return nil, fmt.Errorf("%s:%d: invalid line (want KEY=VALUE)", name, lineNo)
+		}
+		value = strings.TrimSpace(value)
+
+		v := Var{Key: key, interpolate: true}
+		switch {
+		case strings.HasPrefix(value, "'"):
+			end := strings.Index(value[1:], "'")
+			if end < 0 {
+				return nil, fmt.Errorf("%s:%d: unterminated single quote", name, lineNo)
+			}
+			v.Value = value[1 : end+1]
+			v.interpolate = false
+
+		case strings.HasPrefix(value, `"`):
+			start := lineNo
+			body := value[1:]
+			for {
+				if s, ok := cutDoubleQuoted(body); ok {
+					v.Value = s
+					break
+				}
+				if !scanner.Scan() {
+					return nil, fmt.Errorf("%s:%d: unterminated double quote", name, start)
+				}
+				lineNo++
+				body += "\n" + scanner.Text()
+			}
+
+		default:
+			if i := strings.Index(value, " #"); i >= 0 {
+				value = strings.TrimSpace(value[:i])
+			}
+			v.Value = value
+		}
+		vars = append(vars, v)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read %s: %w", name, err)
+	}
+	return vars, nil
+}
+
+// ParseFile parses the dotenv file at path.
+func ParseFile(path string) ([]Var, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return Parse(f, path)
+}
+
+// cutDoubleQuoted unescapes s up to its closing double quote. It reports
+// false when s has no closing quote yet. \$ is kept escaped so that Resolve
+// leaves the dollar sign alone.
+func cutDoubleQuoted(s string) (string, bool) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '"':
+			return b.String(), true
+		case c == '\\' && i+1 < len(s):
+			i++
+			switch s[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case '$':
+				b.WriteString(`\$`)
+			default:
+				b.WriteByte(s[i])
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", false
+}
+
+func validKey(key string) bool {
+	if key == "" {
+		return false
+	}
+	for i, c := range key {
+		switch {
+		case c == '_', c >= 'A' && c <= 'Z', c >= 'a' && c <= 'z':
+		case c >= '0' && c <= '9' && i > 0:
+		case c == '.' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Resolve applies the files' assignments, in order, on top of base (a list
+// of KEY=VALUE pairs such as os.Environ()). Later files override earlier
+// ones. Variables already set in base keep their value unless override is
+// true. References of the form $VAR, ${VAR}, ${VAR:-default} and
+// ${VAR-default} are expanded against the variables resolved so far.
+//
+// It returns the full environment and the variables the files set.
+func Resolve(base []string, files [][]Var, override bool) (env []string, loaded map[string]string) {
+	values := make(map[string]string, len(base))
+	inherited := make(map[string]string, len(base))
+	for _, kv := range base {
+		key, value, _ := strings.Cut(kv, "=")
+		values[key] = value
+		inherited[process.EnvKey(key)] = value
+	}
+
+	loaded = map[string]string{}
+	for _, vars := range files {
+		for _, v := range vars {
+			if value, ok := inherited[process.EnvKey(v.Key)]; ok && !override {
+				loaded[v.Key] = value
+				continue
+			}
+			value := v.Value
+			if v.interpolate {
+				value = expand(value, values)
+			}
+			values[v.Key] = value
+			loaded[v.Key] = value
+		}
+	}
+
+	return process.MergeEnv(base, loaded), loaded
+}
+
+// SortedKeys returns the keys of vars in order.
+func SortedKeys(vars map[string]string) []string {
+	keys := make([]string, 0, len(vars))
+	for key := range vars {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// expand replaces variable references in s. \$ yields a literal dollar.
+func expand(s string, values map[string]string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\\' && i+1 < len(s) && s[i+1] == '$' {
+			b.WriteByte('$')
+			i++
+			continue
+		}
+		if c != '$' || i+1 == len(s) {
+			b.WriteByte(c)
+			continue
+		}
+
+		if s[i+1] == '{' {
+			end := strings.IndexByte(s[i+2:], '}')
+			if end < 0 {
+				b.WriteString(s[i:])
+				return b.String()
+			}
+			b.WriteString(lookup(s[i+2:i+2+end], values))
+			i += end + 2
+			continue
+		}
+
+		j := i + 1
+		for j < len(s) && isNameByte(s[j], j == i+1) {
+			j++
+		}
+		if j == i+1 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteString(values[s[i+1:j]])
+		i = j - 1
+	}
+	return b.String()
+}
+
+// lookup resolves the inside of ${...}: NAME, NAME:-default (default when
+// unset or empty), or NAME-default (default when unset).
+func lookup(expr string, values map[string]string) string {
+	if name, def, ok := strings.Cut(expr, ":-"); ok {
+		if v := values[name]; v != "" {
+			return v
+		}
+		return expand(def, values)
+	}
+	if name, def, ok := strings.Cut(expr, "-"); ok {
+		if v, set := values[name]; set {
+			return v
+		}
+		return expand(def, values)
+	}
+	return values[expr]
+}
+
+func isNameByte(c byte, first bool) bool {
+	return c == '_' || c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || !first && c >= '0' && c <= '9'
+}
@@ -0,0 +1,123 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func parse(t *testing.T, src string) []Var {
+	t.Helper()
+	vars, err := Parse(strings.NewReader(src), ".env")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	return vars
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want map[string]string
+	}{
+		{
+			name: "plain, comments, export",
+			src:  "# comment\n\nA=1\nexport B = two words \nC=x # trailing\nD=a#b\nE=\n",
+			want: map[string]string{"A": "1", "B": "two words", "C": "x", "D": "a#b", "E": ""},
+		},
+		{
+			name: "single quotes are literal",
+			src:  `A='$HOME \n # x'`,
+			want: map[string]string{"A": `$HOME \n # x`},
+		},
+		{
+			name: "double quotes unescape",
+			src:  `A="line1\nline2\t\"q\" \\ # not a comment"`,
+			want: map[string]string{"A": "line1\nline2\t\"q\" \\ # not a comment"},
+		},
+		{
+			name: "multi-line double quotes",
+			src:  "KEY=\"-----BEGIN-----\nabc\n-----END-----\"\nNEXT=1\n",
+			want: map[string]string{"KEY": "-----BEGIN-----\nabc\n-----END-----", "NEXT": "1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := map[string]string{}
+			for _, v := range parse(t, tt.src) {
+				got[v.Key] = v.Value
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{"no equals", "A=1\nJUST_A_WORD\n", ".env:2: invalid line"},
+		{"bad key", "1A=x", ".env:1: invalid line"},
+		{"unterminated single", "A='x", ".env:1: unterminated single quote"},
+		{"unterminated double", "A=1\nB=\"x\ny\n", ".env:2: unterminated double quote"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.src), ".env")
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolve(t *testing.T) {
+	base := []string{"HOME=/home/u", "PORT=80", "EMPTY="}
+	files := [][]Var{
+		parse(t, "PORT=8080\nHOST=localhost\nURL=http://${HOST}:$PORT/\nDATA=$HOME/data\nLIT='$HOME'\nESC=\"\\$HOME\"\n"),
+		parse(t, "HOST=example.com\nA=${MISSING:-def}\nB=${EMPTY:-def}\nC=${EMPTY-def}\nD=${MISSING-$HOST}\n"),
+	}
+
+	tests := []struct {
+		name     string
+		override bool
+		want     map[string]string
+	}{
+		{
+			name: "environment wins",
+			want: map[string]string{
+				"PORT": "80", "HOST": "example.com", "URL": "http://localhost:80/",
+				"DATA": "/home/u/data", "LIT": "$HOME", "ESC": "$HOME",
+				"A": "def", "B": "def", "C": "", "D": "example.com",
+			},
+		},
+		{
+			name:     "override",
+			override: true,
+			want: map[string]string{
+				"PORT": "8080", "HOST": "example.com", "URL": "http://localhost:8080/",
+				"DATA": "/home/u/data", "LIT": "$HOME", "ESC": "$HOME",
+				"A": "def", "B": "def", "C": "", "D": "example.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env, loaded := Resolve(base, files, tt.override)
+			if !reflect.DeepEqual(loaded, tt.want) {
+				t.Errorf("loaded = %#v, want %#v", loaded, tt.want)
+			}
+			if env[0] != "HOME=/home/u" || env[1] != "EMPTY=" || len(env) != 2+len(tt.want) {
+				t.Errorf("env = %q", env)
+			}
+		})
+	}
+}
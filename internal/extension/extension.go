@@ -0,0 +1,92 @@
+// Package extension holds subcommands registered through pkg/adocli and
+// the dependencies handed to them. It lives apart from pkg/adocli so that
+// cmd/ado/root can read the registry without importing the public package.
+package extension
+
+import (
+	"context"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/logging"
+	"github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// Factory builds a subcommand from the shared dependencies.
+type Factory func(Deps) *cobra.Command
+
+var (
+	mu        sync.Mutex
+	factories []Factory
+)
+
+// Register adds a factory. Factories run, in registration order, each
+// time a root command is built.
+func Register(f Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	factories = append(factories, f)
+}
+
+// Commands builds the registered subcommands, skipping factories that
+// return nil.
+func Commands(deps Deps) []*cobra.Command {
+	mu.Lock()
+	registered := append([]Factory(nil), factories...)
+	mu.Unlock()
+
+	cmds := make([]*cobra.Command, 0, len(registered))
+	for _, f := range registered {
+		if cmd := f(deps); cmd != nil {
+			cmds = append(cmds, cmd)
+		}
+	}
+	return cmds
+}
+
+// Reset removes all registered factories. It is meant for tests.
+func Reset() {
+	mu.Lock()
+	defer mu.Unlock()
+	factories = nil
+}
+
+// Deps gives registered commands the services built-in commands use.
+type Deps struct {
+	// BuildInfo describes the running binary.
+	BuildInfo meta.BuildInfo
+}
+
+// Logger returns the logger configured from --log-level. It is available
+// once the command runs; before that it is the default logger.
+func (Deps) Logger(ctx context.Context) logging.Logger {
+	return logging.FromContext(ctx)
+}
+
+// LoadConfig loads the config file selected by --config, $ADO_CONFIG, or
+// the default search paths. It returns the defaults and an empty path when
+// no file exists.
+func (Deps) LoadConfig(cmd *cobra.Command) (*config.Config, string, error) {
+	configPath, err := cmd.Root().PersistentFlags().GetString("config")
+	if err != nil {
+		return nil, "", err
+	}
+	homeDir, _ := os.UserHomeDir()
+	return config.PathResolverFor(cmd.Context(), configPath, homeDir).Load()
+}
+
+// ParseOutputFormat validates an --output value.
+func (Deps) ParseOutputFormat(raw string) (ui.OutputFormat, error) {
+	return ui.ParseOutputFormat(raw)
+}
+
+// PrintOutput writes payload as JSON or YAML, or calls renderText for
+// text output, exactly as built-in commands do.
+func (Deps) PrintOutput(w io.Writer, format ui.OutputFormat, payload any, renderText func() (string, error)) error {
+	return ui.PrintOutput(w, format, payload, renderText)
+}
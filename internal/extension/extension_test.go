@@ -0,0 +1,94 @@
+package extension
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/logging"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+func TestCommands(t *testing.T) {
+	t.Cleanup(Reset)
+	if got := Commands(Deps{}); len(got) != 0 {
+		t.Fatalf("Commands() before Register = %d commands", len(got))
+	}
+
+	Register(func(Deps) *cobra.Command { return &cobra.Command{Use: "b"} })
+	Register(func(Deps) *cobra.Command { return nil })
+	Register(func(Deps) *cobra.Command { return &cobra.Command{Use: "a"} })
+
+	cmds := Commands(Deps{})
+	if len(cmds) != 2 || cmds[0].Name() != "b" || cmds[1].Name() != "a" {
+		t.Errorf("Commands() = %v, want [b a] in registration order", cmds)
+	}
+
+	// Each call builds fresh commands.
+	if again := Commands(Deps{}); again[0] == cmds[0] {
+		t.Error("Commands() reused a command from a previous call")
+	}
+
+	Reset()
+	if got := Commands(Deps{}); len(got) != 0 {
+		t.Errorf("Commands() after Reset = %d commands", len(got))
+	}
+}
+
+func TestDeps_Logger(t *testing.T) {
+	log := logging.NopLogger()
+	ctx := logging.WithContext(context.Background(), log)
+	if got := (Deps{}).Logger(ctx); got != log {
+		t.Errorf("Logger() = %v, want the context logger", got)
+	}
+	if (Deps{}).Logger(context.Background()) == nil {
+		t.Error("Logger() without a context logger = nil")
+	}
+}
+
+func TestDeps_LoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("version: 1\ntasks:\n  hi:\n    command: echo\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	root := &cobra.Command{Use: "ado"}
+	root.PersistentFlags().String("config", "", "")
+	sub := &cobra.Command{Use: "sub"}
+	root.AddCommand(sub)
+	if err := root.PersistentFlags().Set("config", path); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, got, err := (Deps{}).LoadConfig(sub)
+	if err != nil {
+		t.Fatalf("LoadConfig() error = %v", err)
+	}
+	if got != path || cfg.Tasks["hi"].Command != "echo" {
+		t.Errorf("LoadConfig() = %+v, %q", cfg, got)
+	}
+
+	if _, _, err := (Deps{}).LoadConfig(&cobra.Command{Use: "orphan"}); err == nil {
+		t.Error("LoadConfig() without a --config flag: want error")
+	}
+}
+
+func TestDeps_PrintOutput(t *testing.T) {
+	deps := Deps{}
+	format, err := deps.ParseOutputFormat("json")
+	if err != nil || format != ui.OutputJSON {
+		t.Fatalf("ParseOutputFormat() = %q, %v", format, err)
+	}
+
+	var buf bytes.Buffer
+	if err := deps.PrintOutput(&buf, format, map[string]int{"n": 1}, nil); err != nil {
+		t.Fatalf("PrintOutput() error = %v", err)
+	}
+	if buf.String() != "{\n  \"n\": 1\n}\n" {
+		t.Errorf("PrintOutput() = %q", buf.String())
+	}
+}
@@ -0,0 +1,178 @@
+// Package features is the registry of compiled-in feature flags.
+//
+// Features let subsystems ship in the binary before they are ready for
+// everyone. Each feature has a stage and a default; users override the
+// default per feature in the `features:` section of the config file or,
+// for one shell or CI job, with $ADO_FEATURES. Commands registered behind
+// a feature with Gate are hidden and refuse to run while it is off.
+package features
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/cobra"
+)
+
+// EnvVar overrides feature toggles from the environment: a comma-separated
+// list of names to enable, with a leading "-" to disable instead, such as
+// ADO_FEATURES=serve,-workflow. It wins over the config file.
+const EnvVar = "ADO_FEATURES"
+
+// CommandAnnotation holds the feature a command is gated behind.
+const CommandAnnotation = "ado/feature"
+
+// Stage describes the maturity of a feature.
+type Stage string
+
+const (
+	StageExperimental Stage = "experimental"
+	StageBeta         Stage = "beta"
+	StageStable       Stage = "stable"
+)
+
+// Feature is a compiled-in feature flag.
+type Feature struct {
+	Name        string `json:"name" yaml:"name"`
+	Description string `json:"description" yaml:"description"`
+	Stage       Stage  `json:"stage" yaml:"stage"`
+	Default     bool   `json:"default" yaml:"default"`
+}
+
+// State is a feature together with its effective value.
+type State struct {
+	Feature `yaml:",inline"`
+	Enabled bool   `json:"enabled" yaml:"enabled"`
+	Source  string `json:"source" yaml:"source"` // default, config, or env
+}
+
+// Registry holds the known features.
+type Registry struct {
+	mu       sync.RWMutex
+	features map[string]Feature
+}
+
+// NewRegistry returns an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{features: map[string]Feature{}}
+}
+
+// Default is the registry that subsystems register their features with.
+var Default = NewRegistry()
+
+// Register adds a feature. It panics on duplicate or empty names, since
+// registration happens at init time and such errors are programming bugs.
+func (r *Registry) Register(f Feature) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if f.Name == "" {
+		panic("features: empty feature name")
+	}
+	if _, ok := r.features[f.Name]; ok {
+		panic(fmt.Sprintf("features: duplicate feature %q", f.Name))
+	}
+	r.features[f.Name] = f
+}
+
+// Lookup returns the named feature.
+func (r *Registry) Lookup(name string) (Feature, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	f, ok := r.features[name]
+	return f, ok
+}
+
+// All returns every registered feature, sorted by name.
+func (r *Registry) All() []Feature {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := make([]Feature, 0, len(r.features))
+	for _, f := range r.features {
+		all = append(all, f)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// Resolve returns the effective state of every feature given the toggles
+// from the config file and from $ADO_FEATURES (see ParseEnv), which win.
+// Toggles for unknown features are ignored.
+func (r *Registry) Resolve(config, env map[string]bool) []State {
+	all := r.All()
+	states := make([]State, 0, len(all))
+	for _, f := range all {
+		state := State{Feature: f, Enabled: f.Default, Source: "default"}
+		if enabled, ok := config[f.Name]; ok {
+			state.Enabled = enabled
+			state.Source = "config"
+		}
+		if enabled, ok := env[f.Name]; ok {
+			state.Enabled = enabled
+			state.Source = "env"
+		}
+		states = append(states, state)
+	}
+	return states
+}
+
+// Enabled reports whether the named feature is on given the toggles from
+// the config file and the environment. Unknown features are always off.
+func (r *Registry) Enabled(name string, config, env map[string]bool) bool {
+	f, ok := r.Lookup(name)
+	if !ok {
+		return false
+	}
+	if enabled, ok := env[name]; ok {
+		return enabled
+	}
+	if enabled, ok := config[name]; ok {
+		return enabled
+	}
+	return f.Default
+}
+
+// ParseEnv parses a value of $ADO_FEATURES into toggles. Names are
+// separated by commas or blanks; "-name" disables a feature.
+func ParseEnv(value string) map[string]bool {
+	toggles := map[string]bool{}
+	for _, name := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == ' ' || r == '\t' }) {
+		if disabled, ok := strings.CutPrefix(name, "-"); ok {
+			toggles[disabled] = false
+		} else {
+			toggles[name] = true
+		}
+	}
+	return toggles
+}
+
+// EnvToggles returns the toggles set by $ADO_FEATURES.
+func EnvToggles() map[string]bool {
+	return ParseEnv(os.Getenv(EnvVar))
+}
+
+// Gate registers cmd behind the named feature and returns it.
+func Gate(cmd *cobra.Command, feature string) *cobra.Command {
+	if cmd.Annotations == nil {
+		cmd.Annotations = map[string]string{}
+	}
+	cmd.Annotations[CommandAnnotation] = feature
+	return cmd
+}
+
+// CommandFeature returns the feature cmd is gated behind, directly or
+// through a parent command, and the command that carries the gate. It
+// returns "" and nil for commands that are not gated.
+func CommandFeature(cmd *cobra.Command) (string, *cobra.Command) {
+	for c := cmd; c != nil; c = c.Parent() {
+		if name := c.Annotations[CommandAnnotation]; name != "" {
+			return name, c
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,142 @@
+package features
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func testRegistry() *Registry {
+	r := NewRegistry()
+	r.Register(Feature{Name: "serve", Description: "HTTP API server", Stage: StageExperimental})
+	r.Register(Feature{Name: "color", Description: "Colored output", Stage: StageStable, Default: true})
+	return r
+}
+
+func TestRegistry_All(t *testing.T) {
+	r := testRegistry()
+
+	var names []string
+	for _, f := range r.All() {
+		names = append(names, f.Name)
+	}
+	if want := []string{"color", "serve"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("All() names = %v, want %v", names, want)
+	}
+}
+
+func TestRegistry_Register_Panics(t *testing.T) {
+	tests := []struct {
+		name    string
+		feature Feature
+	}{
+		{"duplicate", Feature{Name: "serve"}},
+		{"empty name", Feature{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Register() did not panic")
+				}
+			}()
+			testRegistry().Register(tt.feature)
+		})
+	}
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	r := testRegistry()
+
+	states := r.Resolve(map[string]bool{"serve": true, "color": false, "unknown": true}, nil)
+	if len(states) != 2 {
+		t.Fatalf("Resolve() returned %d states, want 2", len(states))
+	}
+	for _, s := range states {
+		if s.Enabled != (s.Name == "serve") {
+			t.Errorf("%s: Enabled = %v", s.Name, s.Enabled)
+		}
+		if s.Source != "config" {
+			t.Errorf("%s: Source = %q, want config", s.Name, s.Source)
+		}
+	}
+
+	for _, s := range r.Resolve(nil, nil) {
+		if s.Enabled != s.Default || s.Source != "default" {
+			t.Errorf("%s: got %+v, want default", s.Name, s)
+		}
+	}
+}
+
+func TestRegistry_Enabled(t *testing.T) {
+	r := testRegistry()
+
+	tests := []struct {
+		name    string
+		toggles map[string]bool
+		want    bool
+	}{
+		{"serve", nil, false},
+		{"serve", map[string]bool{"serve": true}, true},
+		{"color", nil, true},
+		{"color", map[string]bool{"color": false}, false},
+		{"unknown", map[string]bool{"unknown": true}, false},
+	}
+
+	for _, tt := range tests {
+		if got := r.Enabled(tt.name, tt.toggles, nil); got != tt.want {
+			t.Errorf("Enabled(%q, %v) = %v, want %v", tt.name, tt.toggles, got, tt.want)
+		}
+	}
+}
+
+func TestRegistry_Env(t *testing.T) {
+	r := testRegistry()
+	config := map[string]bool{"serve": true}
+	env := ParseEnv("-serve, color")
+
+	states := r.Resolve(config, env)
+	for _, s := range states {
+		if s.Source != "env" || s.Enabled != (s.Name == "color") {
+			t.Errorf("%s: got %+v, want env override", s.Name, s)
+		}
+	}
+	if r.Enabled("serve", config, env) {
+		t.Error("env did not override config")
+	}
+	if !r.Enabled("serve", config, nil) {
+		t.Error("config toggle ignored")
+	}
+}
+
+func TestParseEnv(t *testing.T) {
+	tests := []struct {
+		value string
+		want  map[string]bool
+	}{
+		{"", map[string]bool{}},
+		{"serve", map[string]bool{"serve": true}},
+		{"serve,-mcp workflow", map[string]bool{"serve": true, "mcp": false, "workflow": true}},
+		{" , serve ,", map[string]bool{"serve": true}},
+	}
+	for _, tt := range tests {
+		if got := ParseEnv(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseEnv(%q) = %v, want %v", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestCommandFeature(t *testing.T) {
+	parent := Gate(&cobra.Command{Use: "serve"}, "serve")
+	child := &cobra.Command{Use: "start"}
+	parent.AddCommand(child)
+
+	if name, gate := CommandFeature(child); gate != parent || name != "serve" {
+		t.Errorf("CommandFeature(child) = %q, %v", name, gate)
+	}
+	if _, gate := CommandFeature(&cobra.Command{Use: "echo"}); gate != nil {
+		t.Error("ungated command reported a feature")
+	}
+}
@@ -0,0 +1,167 @@
+// Package ghactions speaks the GitHub Actions workflow command protocol,
+// so ado's checks surface in Actions as annotations on the offending file
+// and line, as Markdown in the job summary, and with secret values masked
+// in the log.
+//
+// Workflow commands are lines written to stdout or stderr; the runner strips them
+// from the log and acts on them. Outside Actions they would be noise, so
+// callers check Enabled first.
+package ghactions
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Environment variables set by the Actions runner.
+const (
+	// EnvVar is "true" in every step of an Actions job.
+	EnvVar = "GITHUB_ACTIONS"
+	// SummaryEnvVar names the file that collects the step's Markdown summary.
+	SummaryEnvVar = "GITHUB_STEP_SUMMARY"
+	// WorkspaceEnvVar is the checkout directory annotation paths are
+	// relative to.
+	WorkspaceEnvVar = "GITHUB_WORKSPACE"
+)
+
+// Enabled reports whether ado is running in a GitHub Actions step.
+func Enabled() bool {
+	return os.Getenv(EnvVar) == "true"
+}
+
+// Levels of annotation.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNotice  = "notice"
+)
+
+// Annotation is a message attached to a file and line in the workflow run
+// and, for pull requests, the diff.
+type Annotation struct {
+	Level   string
+	File    string
+	Line    int
+	Title   string
+	Message string
+}
+
+// String formats a as a workflow command, such as
+// ::error file=config.yaml,line=3::unknown key "foo".
+func (a Annotation) String() string {
+	var props []string
+	if a.File != "" {
+		props = append(props, "file="+escapeProperty(RelPath(a.File)))
+	}
+	if a.Line > 0 {
+		props = append(props, "line="+strconv.Itoa(a.Line))
+	}
+	if a.Title != "" {
+		props = append(props, "title="+escapeProperty(a.Title))
+	}
+	level := a.Level
+	if level == "" {
+		level = LevelError
+	}
+	cmd := "::" + level
+	if len(props) > 0 {
+		cmd += " " + strings.Join(props, ",")
+	}
+	return cmd + "::" + escapeData(a.Message)
+}
+
+// Annotate writes each annotation to w, one per line.
+func Annotate(w io.Writer, annotations ...Annotation) error {
+	for _, a := range annotations {
+		if _, err := fmt.Fprintln(w, a); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AddMask tells the runner to replace value with *** wherever it appears
+// in the log from now on. The runner masks single lines, so each line of a
+// multi-line value is masked on its own.
+func AddMask(w io.Writer, value string) error {
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSuffix(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "::add-mask::%s\n", escapeData(line)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// AppendSummary adds markdown to the job summary. It does nothing when
+// the runner provides no summary file.
+func AppendSummary(markdown string) error {
+	path := os.Getenv(SummaryEnvVar)
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("open step summary: %w", err)
+	}
+	if !strings.HasSuffix(markdown, "\n") {
+		markdown += "\n"
+	}
+	if _, err := io.WriteString(f, markdown); err != nil {
+		f.Close()
+		return fmt.Errorf("write step summary: %w", err)
+	}
+	return f.Close()
+}
+
+// Table formats a Markdown table for a summary, escaping the pipes and
+// line breaks in cells.
+func Table(header []string, rows [][]string) string {
+	var b strings.Builder
+	row := func(cells []string) {
+		b.WriteString("|")
+		for _, cell := range cells {
+			cell = strings.NewReplacer("|", `\|`, "\r\n", "<br>", "\n", "<br>").Replace(cell)
+			b.WriteString(" " + cell + " |")
+		}
+		b.WriteString("\n")
+	}
+	row(header)
+	b.WriteString(strings.Repeat("|---", len(header)) + "|\n")
+	for _, r := range rows {
+		row(r)
+	}
+	return b.String()
+}
+
+// RelPath returns path relative to the workspace, as annotations need it
+// to link to the file. Paths outside the workspace are returned unchanged.
+func RelPath(path string) string {
+	workspace := os.Getenv(WorkspaceEnvVar)
+	if workspace == "" || !filepath.IsAbs(path) {
+		return path
+	}
+	rel, err := filepath.Rel(workspace, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// escapeData escapes a command's message, which ends at the line.
+func escapeData(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A").Replace(s)
+}
+
+// escapeProperty escapes a command property value, which also ends at a
+// comma or colon.
+func escapeProperty(s string) string {
+	return strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C").Replace(s)
+}
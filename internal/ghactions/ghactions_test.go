@@ -0,0 +1,93 @@
+package ghactions
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnabled(t *testing.T) {
+	for value, want := range map[string]bool{"true": true, "": false, "false": false} {
+		t.Setenv(EnvVar, value)
+		if got := Enabled(); got != want {
+			t.Errorf("Enabled() with %s=%q = %v, want %v", EnvVar, value, got, want)
+		}
+	}
+}
+
+func TestAnnotation_String(t *testing.T) {
+	workspace := t.TempDir()
+	t.Setenv(WorkspaceEnvVar, workspace)
+
+	tests := []struct {
+		name string
+		a    Annotation
+		want string
+	}{
+		{"message only", Annotation{Message: "broken"}, "::error::broken"},
+		{
+			"file in workspace and line",
+			Annotation{Level: LevelWarning, File: filepath.Join(workspace, "ci", "ado.yaml"), Line: 3, Message: `unknown key "foo"`},
+			`::warning file=ci/ado.yaml,line=3::unknown key "foo"`,
+		},
+		{
+			"file outside workspace",
+			Annotation{File: "/etc/ado.yaml", Message: "x"},
+			"::error file=/etc/ado.yaml::x",
+		},
+		{
+			"escaping",
+			Annotation{Level: LevelNotice, Title: "a, b: c", Message: "100% done\nnext"},
+			"::notice title=a%2C b%3A c::100%25 done%0Anext",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAddMask(t *testing.T) {
+	var buf bytes.Buffer
+	if err := AddMask(&buf, "line one\r\n\nline two"); err != nil {
+		t.Fatal(err)
+	}
+	want := "::add-mask::line one\n::add-mask::line two\n"
+	if buf.String() != want {
+		t.Errorf("output = %q, want %q", buf.String(), want)
+	}
+}
+
+func TestTable(t *testing.T) {
+	got := Table([]string{"Line", "Message"}, [][]string{{"3", "a | b"}, {"", "one\ntwo"}})
+	want := "| Line | Message |\n|---|---|\n| 3 | a \\| b |\n|  | one<br>two |\n"
+	if got != want {
+		t.Errorf("Table() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestAppendSummary(t *testing.T) {
+	t.Setenv(SummaryEnvVar, "")
+	if err := AppendSummary("ignored"); err != nil {
+		t.Fatalf("without summary file: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "summary.md")
+	t.Setenv(SummaryEnvVar, path)
+	for _, md := range []string{"### one", "### two\n"} {
+		if err := AppendSummary(md); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "### one\n### two\n" {
+		t.Errorf("summary = %q", data)
+	}
+}
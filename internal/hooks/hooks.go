@@ -0,0 +1,201 @@
+// Package hooks runs the commands of the hooks: section of the ado config
+// file before and after the ado commands they apply to.
+package hooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/process"
+	"github.com/anowarislam/ado/internal/secrets"
+)
+
+// Variables set for a hook.
+const (
+	// EnvWhen is pre or post. ado started with it set, as by a hook that
+	// runs ado, runs no hooks.
+	EnvWhen = "ADO_HOOK"
+	// EnvCommand is the path of the command, such as "remote system".
+	EnvCommand = "ADO_COMMAND"
+	// EnvExitCode is the status the command exits with, for post hooks.
+	EnvExitCode = "ADO_EXIT_CODE"
+	// EnvDurationMS is how long the command ran, for post hooks.
+	EnvDurationMS = "ADO_DURATION_MS"
+)
+
+// Applies reports whether hook applies to the command at path, such as
+// "remote system": a path in hook.Commands applies to itself and the
+// commands below it, and no paths apply to every command.
+func Applies(hook config.Hook, path string) bool {
+	if len(hook.Commands) == 0 {
+		return true
+	}
+	words := strings.Fields(path)
+	for _, prefix := range hook.Commands {
+		want := strings.Fields(prefix)
+		if len(want) > 0 && len(want) <= len(words) && slices.Equal(want, words[:len(want)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// Select returns the names of the hooks run when, pre or post, that apply
+// to the command at path, in the order they run: sorted by name. A hook
+// with any other when never runs; `ado config validate` rejects it.
+func Select(hooks map[string]config.Hook, when, path string) []string {
+	var names []string
+	for _, name := range slices.Sorted(maps.Keys(hooks)) {
+		if hook := hooks[name]; hook.When == when && Applies(hook, path) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Error reports a hook that failed under the fail policy.
+type Error struct {
+	Hook string
+	When string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s hook %q failed: %v", e.When, e.Hook, e.Err)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// ExitCode is the status ado exits with: 1, whatever status the hook
+// exited with, which could be mistaken for the command's.
+func (e *Error) ExitCode() int { return 1 }
+
+// ErrorCode is the code of the failure in structured error output.
+func (e *Error) ErrorCode() string { return "hook_failed" }
+
+// ErrorDetails names the hook in structured error output.
+func (e *Error) ErrorDetails() map[string]any {
+	return map[string]any{"hook": e.Hook, "when": e.When}
+}
+
+// Outcome is how the command a post hook runs after ended.
+type Outcome struct {
+	ExitCode int
+	Duration time.Duration
+}
+
+// Runner runs hooks.
+type Runner struct {
+	// Output receives the hooks' stdout and stderr, and warnings, so that
+	// they stay apart from the command's own output.
+	Output io.Writer
+	// Environ returns the environment hooks inherit; defaults to
+	// os.Environ.
+	Environ func() []string
+	// Secrets resolves secret://NAME references in hook args and env
+	// values; references are left as-is when nil.
+	Secrets secrets.Store
+}
+
+// Run runs the hooks of hooks run when, pre or post, that apply to the
+// command at path, in name order. outcome describes the command for post
+// hooks and is nil for pre hooks.
+//
+// A failing hook with on_failure fail yields an *Error: the pre hooks
+// after it do not run, while post hooks all run. One with warn prints a
+// warning, and one with ignore is passed over.
+func (r Runner) Run(ctx context.Context, hooks map[string]config.Hook, when, path string, outcome *Outcome) error {
+	var errs []error
+	for _, name := range Select(hooks, when, path) {
+		hook := hooks[name]
+		err := r.run(ctx, hook, when, path, outcome)
+		if err == nil {
+			continue
+		}
+		switch hook.FailurePolicy() {
+		case config.HookIgnore:
+		case config.HookWarn:
+			fmt.Fprintf(r.output(), "warning: %s hook %q failed: %v\n", when, name, err)
+		default:
+			errs = append(errs, &Error{Hook: name, When: when, Err: err})
+			if when == config.HookPre {
+				return errs[0]
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// run runs one hook, stopping it after its timeout.
+func (r Runner) run(ctx context.Context, hook config.Hook, when, path string, outcome *Outcome) error {
+	args := slices.Clone(hook.Args)
+	env := maps.Clone(hook.Env)
+	if r.Secrets != nil {
+		if err := secrets.ExpandAll(r.Secrets, args); err != nil {
+			return err
+		}
+		for key, value := range env {
+			expanded, err := secrets.Expand(r.Secrets, value)
+			if err != nil {
+				return err
+			}
+			env[key] = expanded
+		}
+	}
+
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = config.DefaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, hook.Command, args...)
+	cmd.Stdout = r.output()
+	cmd.Stderr = r.output()
+	cmd.Env = r.environ(env, when, path, outcome)
+	process.Graceful(cmd, process.GracePeriod)
+
+	err := cmd.Run()
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+	return err
+}
+
+// environ returns the environment of a hook: the inherited one with the
+// hook's variables set, and then the variables describing the command.
+func (r Runner) environ(env map[string]string, when, path string, outcome *Outcome) []string {
+	environ := os.Environ
+	if r.Environ != nil {
+		environ = r.Environ
+	}
+	if env == nil {
+		env = map[string]string{}
+	}
+	env[EnvWhen] = when
+	env[EnvCommand] = path
+	if outcome != nil {
+		env[EnvExitCode] = strconv.Itoa(outcome.ExitCode)
+		env[EnvDurationMS] = strconv.FormatInt(outcome.Duration.Milliseconds(), 10)
+	}
+
+	return process.MergeEnv(environ(), env)
+}
+
+func (r Runner) output() io.Writer {
+	if r.Output == nil {
+		return io.Discard
+	}
+	return r.Output
+}
@@ -0,0 +1,144 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anowarislam/ado/internal/config"
+)
+
+func requireSh(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+}
+
+func TestApplies(t *testing.T) {
+	tests := []struct {
+		commands []string
+		path     string
+		want     bool
+	}{
+		{nil, "meta info", true},
+		{[]string{"remote"}, "remote exec", true},
+		{[]string{"remote exec"}, "remote exec", true},
+		{[]string{"remote  exec"}, "remote exec", true},
+		{[]string{"remote exec"}, "remote", false},
+		{[]string{"remote"}, "remotely", false},
+		{[]string{"meta", "http"}, "http get", true},
+		{[]string{"meta"}, "run", false},
+	}
+	for _, tt := range tests {
+		if got := Applies(config.Hook{Commands: tt.commands}, tt.path); got != tt.want {
+			t.Errorf("Applies(%q, %q) = %v, want %v", tt.commands, tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestSelect(t *testing.T) {
+	hooks := map[string]config.Hook{
+		"b-vpn":    {When: config.HookPre, Commands: []string{"remote"}},
+		"a-auth":   {When: config.HookPre},
+		"c-notify": {When: config.HookPost},
+		"d-other":  {When: config.HookPre, Commands: []string{"http"}},
+	}
+	if got := strings.Join(Select(hooks, config.HookPre, "remote exec"), ","); got != "a-auth,b-vpn" {
+		t.Errorf("Select(pre) = %s, want a-auth,b-vpn", got)
+	}
+	if got := strings.Join(Select(hooks, config.HookPost, "remote exec"), ","); got != "c-notify" {
+		t.Errorf("Select(post) = %s, want c-notify", got)
+	}
+}
+
+// shHook returns a hook running script with sh.
+func shHook(when, script, onFailure string) config.Hook {
+	return config.Hook{When: when, Command: "sh", Args: []string{"-c", script}, OnFailure: onFailure}
+}
+
+func TestRunner_Run(t *testing.T) {
+	requireSh(t)
+
+	tests := []struct {
+		name    string
+		hooks   map[string]config.Hook
+		when    string
+		outcome *Outcome
+		want    string
+		wantErr string
+	}{
+		{
+			name: "pre hooks see the command",
+			hooks: map[string]config.Hook{
+				"a": shHook(config.HookPre, `echo "$ADO_HOOK $ADO_COMMAND ${ADO_EXIT_CODE-none} $GREETING"`, ""),
+			},
+			when: config.HookPre,
+			want: "pre remote exec none hi\n",
+		},
+		{
+			name: "post hooks see how the command ended",
+			hooks: map[string]config.Hook{
+				"a": shHook(config.HookPost, `echo "$ADO_HOOK $ADO_EXIT_CODE $ADO_DURATION_MS"`, ""),
+			},
+			when:    config.HookPost,
+			outcome: &Outcome{ExitCode: 3, Duration: 1500 * time.Millisecond},
+			want:    "post 3 1500\n",
+		},
+		{
+			name: "failing pre hook stops the rest",
+			hooks: map[string]config.Hook{
+				"a": shHook(config.HookPre, "exit 1", config.HookIgnore),
+				"b": shHook(config.HookPre, "echo b; exit 2", ""),
+				"c": shHook(config.HookPre, "echo c", ""),
+			},
+			when:    config.HookPre,
+			want:    "b\n",
+			wantErr: `pre hook "b" failed: exit status 2`,
+		},
+		{
+			name: "post hooks all run",
+			hooks: map[string]config.Hook{
+				"a": shHook(config.HookPost, "exit 1", config.HookFail),
+				"b": shHook(config.HookPost, "exit 2", ""),
+				"c": shHook(config.HookPost, "echo c", ""),
+			},
+			when:    config.HookPost,
+			outcome: &Outcome{},
+			want:    "warning: post hook \"b\" failed: exit status 2\nc\n",
+			wantErr: `post hook "a" failed: exit status 1`,
+		},
+		{
+			name: "timeout",
+			hooks: map[string]config.Hook{
+				"slow": {When: config.HookPre, Command: "sh", Args: []string{"-c", "exec sleep 5"}, Timeout: 50 * time.Millisecond},
+			},
+			when:    config.HookPre,
+			wantErr: `pre hook "slow" failed: timed out after 50ms`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			r := Runner{Output: &out, Environ: func() []string { return []string{"GREETING=hi"} }}
+			err := r.Run(context.Background(), tt.hooks, tt.when, "remote exec", tt.outcome)
+			if out.String() != tt.want {
+				t.Errorf("output = %q, want %q", out.String(), tt.want)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Run() error = %v", err)
+				}
+				return
+			}
+			var hookErr *Error
+			if err == nil || err.Error() != tt.wantErr || !errors.As(err, &hookErr) || hookErr.ExitCode() != 1 {
+				t.Errorf("Run() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package httpclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Defaults for Client.
+const (
+	DefaultTimeout    = 30 * time.Second
+	DefaultRetryDelay = 500 * time.Millisecond
+)
+
+// maxRetryAfter caps how long a Retry-After header can delay a retry.
+const maxRetryAfter = time.Minute
+
+// Client sends requests, retrying transport errors, 429, and 5xx
+// responses.
+type Client struct {
+	HTTPClient *http.Client
+	// Timeout bounds each attempt; zero means no limit.
+	Timeout time.Duration
+	// Retries is the number of additional attempts after the first.
+	Retries int
+	// RetryDelay is the delay before the first retry; it doubles after each.
+	RetryDelay time.Duration
+	UserAgent  string
+
+	sleep func(ctx context.Context, d time.Duration) error
+}
+
+// Response is the structured result of a request.
+type Response struct {
+	Method     string      `json:"method" yaml:"method"`
+	URL        string      `json:"url" yaml:"url"`
+	Proto      string      `json:"proto" yaml:"proto"`
+	Status     string      `json:"status" yaml:"status"`
+	StatusCode int         `json:"status_code" yaml:"status_code"`
+	Headers    http.Header `json:"headers" yaml:"headers"`
+	// Body is the decoded JSON body for JSON responses, the body text for
+	// other UTF-8 responses, and nil for binary responses.
+	Body any `json:"body" yaml:"body"`
+	// BodyBase64 holds binary response bodies.
+	BodyBase64 string `json:"body_base64,omitempty" yaml:"body_base64,omitempty"`
+	DurationMS int64  `json:"duration_ms" yaml:"duration_ms"`
+	Attempts   int    `json:"attempts" yaml:"attempts"`
+
+	// Raw is the undecoded body.
+	Raw []byte `json:"-" yaml:"-"`
+}
+
+// StatusError reports an HTTP error status. Its exit code is the status
+// class (3, 4, or 5), as with httpie's --check-status.
+type StatusError struct {
+	Status     string
+	StatusCode int
+}
+
+func (e *StatusError) Error() string {
+	return "HTTP " + e.Status
+}
+
+// ExitCode returns the status class.
+func (e *StatusError) ExitCode() int {
+	return e.StatusCode / 100
+}
+
+// CheckStatus returns a *StatusError for 3xx, 4xx, and 5xx responses.
+func CheckStatus(resp *Response) error {
+	if resp.StatusCode >= 300 {
+		return &StatusError{Status: resp.Status, StatusCode: resp.StatusCode}
+	}
+	return nil
+}
+
+// Do sends req, retrying as configured, and returns the final response.
+func (c *Client) Do(ctx context.Context, req *Request) (*Response, error) {
+	if _, _, err := req.build(); err != nil {
+		return nil, err
+	}
+	delay := c.RetryDelay
+	if delay <= 0 {
+		delay = DefaultRetryDelay
+	}
+
+	for attempt := 1; ; attempt++ {
+		started := time.Now()
+		resp, retryAfter, err := c.attempt(ctx, req)
+		if resp != nil {
+			resp.DurationMS = time.Since(started).Milliseconds()
+			resp.Attempts = attempt
+		}
+		if attempt > c.Retries || !retryable(resp, err) || ctx.Err() != nil {
+			if err != nil && attempt > 1 {
+				return nil, fmt.Errorf("%w (after %d attempts)", err, attempt)
+			}
+			return resp, err
+		}
+
+		wait := delay
+		if retryAfter > 0 {
+			wait = min(retryAfter, maxRetryAfter)
+		}
+		if err := c.wait(ctx, wait); err != nil {
+			return nil, err
+		}
+		delay *= 2
+	}
+}
+
+// attempt sends one request and reads the whole response.
+func (c *Client) attempt(ctx context.Context, req *Request) (*Response, time.Duration, error) {
+	httpReq, body, err := req.build()
+	if err != nil {
+		return nil, 0, err
+	}
+	if c.UserAgent != "" && httpReq.Header.Get("User-Agent") == "" {
+		httpReq.Header.Set("User-Agent", c.UserAgent)
+	}
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+	httpReq = httpReq.WithContext(ctx)
+	if body != nil {
+		httpReq.Body = io.NopCloser(bytes.NewReader(body))
+		httpReq.ContentLength = int64(len(body))
+		httpReq.GetBody = func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%s %s: %w", req.Method, httpReq.URL.Redacted(), err)
+	}
+	defer httpResp.Body.Close()
+
+	raw, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("read response: %w", err)
+	}
+
+	resp := &Response{
+		Method:     req.Method,
+		URL:        httpResp.Request.URL.Redacted(),
+		Proto:      httpResp.Proto,
+		Status:     httpResp.Status,
+		StatusCode: httpResp.StatusCode,
+		Headers:    httpResp.Header,
+		Raw:        raw,
+	}
+	resp.Body, resp.BodyBase64 = decodeBody(httpResp.Header.Get("Content-Type"), raw)
+	return resp, parseRetryAfter(httpResp.Header.Get("Retry-After")), nil
+}
+
+// retryable reports whether an attempt's outcome is worth retrying.
+func retryable(resp *Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+}
+
+func (c *Client) wait(ctx context.Context, d time.Duration) error {
+	if c.sleep != nil {
+		return c.sleep(ctx, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// parseRetryAfter returns the delay from a Retry-After header in seconds
+// or HTTP-date form, or zero.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
+
+// decodeBody parses JSON bodies and returns other bodies as text, or as
+// base64 when they are not valid UTF-8.
+func decodeBody(contentType string, raw []byte) (any, string) {
+	if len(raw) == 0 {
+		return nil, ""
+	}
+	if IsJSON(contentType) {
+		var v any
+		if err := json.Unmarshal(raw, &v); err == nil {
+			return v, ""
+		}
+	}
+	if utf8.Valid(raw) {
+		return string(raw), ""
+	}
+	return nil, base64.StdEncoding.EncodeToString(raw)
+}
+
+// IsJSON reports whether a Content-Type names a JSON media type.
+func IsJSON(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
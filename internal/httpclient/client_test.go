@@ -0,0 +1,184 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestClient_Do(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		w.Header().Set("X-Seen-Agent", r.UserAgent())
+		_, _ = io.WriteString(w, `{"method":"`+r.Method+`","query":"`+r.URL.RawQuery+`","body":`+string(body)+`}`)
+	}))
+	defer srv.Close()
+
+	req, err := NewRequest("POST", srv.URL+"/echo", []string{"q==1", "n:=2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &Client{UserAgent: "ado/test"}
+	resp, err := c.Do(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+
+	if resp.StatusCode != 200 || resp.Status != "200 OK" || resp.Attempts != 1 || resp.Proto != "HTTP/1.1" {
+		t.Errorf("response = %+v", resp)
+	}
+	if got := resp.Headers.Get("X-Seen-Agent"); got != "ado/test" {
+		t.Errorf("User-Agent = %q", got)
+	}
+	want := map[string]any{"method": "POST", "query": "q=1", "body": map[string]any{"n": float64(2)}}
+	if !reflect.DeepEqual(resp.Body, want) {
+		t.Errorf("Body = %#v, want %#v", resp.Body, want)
+	}
+}
+
+func TestClient_Retries(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		retries      int
+		wantStatus   int
+		wantAttempts int
+	}{
+		{"succeeds after 503", []int{503, 503, 200}, 3, 200, 3},
+		{"gives up", []int{500, 500, 500}, 2, 500, 3},
+		{"no retries by default", []int{503, 200}, 0, 503, 1},
+		{"4xx not retried", []int{404, 200}, 3, 404, 1},
+		{"429 retried", []int{429, 200}, 1, 200, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if string(body) != "payload" {
+					t.Errorf("attempt %d body = %q", calls.Load()+1, body)
+				}
+				n := int(calls.Add(1)) - 1
+				w.WriteHeader(tt.statuses[min(n, len(tt.statuses)-1)])
+			}))
+			defer srv.Close()
+
+			var waits []time.Duration
+			c := &Client{
+				Retries:    tt.retries,
+				RetryDelay: 10 * time.Millisecond,
+				sleep: func(ctx context.Context, d time.Duration) error {
+					waits = append(waits, d)
+					return nil
+				},
+			}
+			req := &Request{Method: "PUT", URL: srv.URL, Body: []byte("payload")}
+			resp, err := c.Do(context.Background(), req)
+			if err != nil {
+				t.Fatalf("Do() error = %v", err)
+			}
+			if resp.StatusCode != tt.wantStatus || resp.Attempts != tt.wantAttempts {
+				t.Errorf("status = %d attempts = %d, want %d and %d", resp.StatusCode, resp.Attempts, tt.wantStatus, tt.wantAttempts)
+			}
+			for i, w := range waits {
+				if want := 10 * time.Millisecond << i; w != want {
+					t.Errorf("wait %d = %s, want %s (exponential backoff)", i, w, want)
+				}
+			}
+		})
+	}
+}
+
+func TestClient_RetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	var waited time.Duration
+	c := &Client{Retries: 1, sleep: func(ctx context.Context, d time.Duration) error { waited = d; return nil }}
+	if _, err := c.Do(context.Background(), &Request{Method: "GET", URL: srv.URL}); err != nil {
+		t.Fatal(err)
+	}
+	if waited != 7*time.Second {
+		t.Errorf("waited %s, want Retry-After 7s", waited)
+	}
+}
+
+func TestClient_TransportError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	url := srv.URL
+	srv.Close()
+
+	c := &Client{Retries: 2, sleep: func(context.Context, time.Duration) error { return nil }}
+	_, err := c.Do(context.Background(), &Request{Method: "GET", URL: url})
+	if err == nil || !strings.Contains(err.Error(), "after 3 attempts") {
+		t.Errorf("Do() error = %v, want after 3 attempts", err)
+	}
+}
+
+func TestClient_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+	}))
+	defer srv.Close()
+
+	c := &Client{Timeout: 20 * time.Millisecond}
+	_, err := c.Do(context.Background(), &Request{Method: "GET", URL: srv.URL})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestDecodeBody(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		raw         string
+		want        any
+		wantBase64  string
+	}{
+		{"json", "application/json", `{"a":1}`, map[string]any{"a": float64(1)}, ""},
+		{"problem json", "application/problem+json", `[true]`, []any{true}, ""},
+		{"invalid json", "application/json", `{`, "{", ""},
+		{"text", "text/plain", "hi", "hi", ""},
+		{"binary", "application/octet-stream", "\xff\x00", nil, "/wA="},
+		{"empty", "application/json", "", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, b64 := decodeBody(tt.contentType, []byte(tt.raw))
+			if !reflect.DeepEqual(got, tt.want) || b64 != tt.wantBase64 {
+				t.Errorf("decodeBody() = %#v, %q; want %#v, %q", got, b64, tt.want, tt.wantBase64)
+			}
+		})
+	}
+}
+
+func TestStatusError(t *testing.T) {
+	if err := CheckStatus(&Response{StatusCode: 204}); err != nil {
+		t.Errorf("CheckStatus(204) = %v", err)
+	}
+	err := CheckStatus(&Response{Status: "404 Not Found", StatusCode: 404})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.ExitCode() != 4 || err.Error() != "HTTP 404 Not Found" {
+		t.Errorf("CheckStatus(404) = %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+// Package httpclient builds and sends ad-hoc HTTP requests for the http
+// command, with retries and structured responses.
+package httpclient
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Item separators, matched at the earliest position in an item; at the
+// same position the longer separator wins.
+const (
+	sepRawJSON = ":="
+	sepQuery   = "=="
+	sepHeader  = ":"
+	sepField   = "="
+)
+
+var separators = []string{sepRawJSON, sepQuery, sepHeader, sepField}
+
+// Request is an HTTP request assembled from command-line arguments.
+type Request struct {
+	Method string
+	URL    string
+	Header http.Header
+	Query  url.Values
+	// Fields are JSON body fields. They are ignored when Body is set.
+	Fields map[string]any
+	Body   []byte
+}
+
+// NewRequest returns a request for method and rawURL with request items
+// applied. Items use httpie syntax:
+//
+//	Header:value   request header
+//	name==value    query parameter
+//	field=value    JSON string field
+//	field:=json    raw JSON field (number, bool, array, object, null)
+func NewRequest(method, rawURL string, items []string) (*Request, error) {
+	req := &Request{
+		Method: strings.ToUpper(method),
+		URL:    normalizeURL(rawURL),
+		Header: http.Header{},
+		Query:  url.Values{},
+		Fields: map[string]any{},
+	}
+	for _, item := range items {
+		if err := req.addItem(item); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
+
+func (r *Request) addItem(item string) error {
+	key, sep, value := splitItem(item)
+	if sep == "" || key == "" {
+		return fmt.Errorf("invalid request item %q: expected Header:value, name==value, field=value, or field:=json", item)
+	}
+	switch sep {
+	case sepHeader:
+		r.Header.Add(key, strings.TrimSpace(value))
+	case sepQuery:
+		r.Query.Add(key, value)
+	case sepField:
+		r.Fields[key] = value
+	case sepRawJSON:
+		var v any
+		if err := json.Unmarshal([]byte(value), &v); err != nil {
+			return fmt.Errorf("invalid JSON in %q: %w", item, err)
+		}
+		r.Fields[key] = v
+	}
+	return nil
+}
+
+// splitItem finds the first separator in item.
+func splitItem(item string) (key, sep, value string) {
+	for i := range len(item) {
+		for _, s := range separators {
+			if strings.HasPrefix(item[i:], s) {
+				return item[:i], s, item[i+len(s):]
+			}
+		}
+	}
+	return "", "", ""
+}
+
+// normalizeURL defaults the scheme to http and expands the ":port/path"
+// shorthand to localhost.
+func normalizeURL(raw string) string {
+	if strings.HasPrefix(raw, ":") {
+		raw = "localhost" + raw
+	}
+	if !strings.Contains(raw, "://") {
+		raw = "http://" + raw
+	}
+	return raw
+}
+
+// build returns the *http.Request for one attempt.
+func (r *Request) build() (*http.Request, []byte, error) {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid URL %q: %w", r.URL, err)
+	}
+	if len(r.Query) > 0 {
+		q := u.Query()
+		for k, vs := range r.Query {
+			for _, v := range vs {
+				q.Add(k, v)
+			}
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	header := r.Header.Clone()
+	if header == nil {
+		header = http.Header{}
+	}
+	body := r.Body
+	if body == nil && len(r.Fields) > 0 {
+		body, err = json.Marshal(r.Fields)
+		if err != nil {
+			return nil, nil, fmt.Errorf("encode JSON body: %w", err)
+		}
+		if header.Get("Content-Type") == "" {
+			header.Set("Content-Type", "application/json")
+		}
+		if header.Get("Accept") == "" {
+			header.Set("Accept", "application/json, */*;q=0.5")
+		}
+	}
+
+	req, err := http.NewRequest(r.Method, u.String(), nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header = header
+	if host := header.Get("Host"); host != "" {
+		req.Host = host
+	}
+	return req, body, nil
+}
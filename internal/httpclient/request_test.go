@@ -0,0 +1,135 @@
+package httpclient
+
+import (
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewRequest_Items(t *testing.T) {
+	req, err := NewRequest("post", "example.com/api", []string{
+		"X-Token:abc",
+		"Accept: text/plain",
+		"page==2",
+		"name=ado",
+		"count:=3",
+		"tags:=[\"a\",\"b\"]",
+		"url=http://x",
+		"X-Pair:a=b",
+	})
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+
+	if req.Method != "POST" || req.URL != "http://example.com/api" {
+		t.Errorf("method/url = %s %s", req.Method, req.URL)
+	}
+	if got := req.Header.Get("X-Token"); got != "abc" {
+		t.Errorf("X-Token = %q", got)
+	}
+	if got := req.Header.Get("Accept"); got != "text/plain" {
+		t.Errorf("Accept = %q", got)
+	}
+	if got := req.Header.Get("X-Pair"); got != "a=b" {
+		t.Errorf("X-Pair = %q", got)
+	}
+	if got := req.Query.Get("page"); got != "2" {
+		t.Errorf("page = %q", got)
+	}
+	want := map[string]any{"name": "ado", "count": float64(3), "tags": []any{"a", "b"}, "url": "http://x"}
+	if !reflect.DeepEqual(req.Fields, want) {
+		t.Errorf("Fields = %#v, want %#v", req.Fields, want)
+	}
+}
+
+func TestNewRequest_Errors(t *testing.T) {
+	tests := []struct {
+		item string
+		want string
+	}{
+		{"plain", "invalid request item"},
+		{"=value", "invalid request item"},
+		{"n:={bad", "invalid JSON"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.item, func(t *testing.T) {
+			_, err := NewRequest("GET", "example.com", []string{tt.item})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("NewRequest() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeURL(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"https://example.com", "https://example.com"},
+		{"example.com/x", "http://example.com/x"},
+		{":8080/health", "http://localhost:8080/health"},
+	}
+	for _, tt := range tests {
+		if got := normalizeURL(tt.in); got != tt.want {
+			t.Errorf("normalizeURL(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBuild(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         *Request
+		wantURL     string
+		wantBody    string
+		wantType    string
+		wantHostHdr string
+	}{
+		{
+			name:     "json fields",
+			req:      &Request{Method: "POST", URL: "http://h/p?a=1", Query: map[string][]string{"b": {"2"}}, Fields: map[string]any{"k": "v"}},
+			wantURL:  "http://h/p?a=1&b=2",
+			wantBody: `{"k":"v"}`,
+			wantType: "application/json",
+		},
+		{
+			name:     "raw body wins",
+			req:      &Request{Method: "PUT", URL: "http://h", Body: []byte("raw"), Fields: map[string]any{"k": "v"}, Header: http.Header{"Content-Type": {"text/plain"}}},
+			wantURL:  "http://h",
+			wantBody: "raw",
+			wantType: "text/plain",
+		},
+		{
+			name:        "host header",
+			req:         &Request{Method: "GET", URL: "http://127.0.0.1", Header: http.Header{"Host": {"example.com"}}},
+			wantURL:     "http://127.0.0.1",
+			wantHostHdr: "example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			httpReq, body, err := tt.req.build()
+			if err != nil {
+				t.Fatalf("build() error = %v", err)
+			}
+			if got := httpReq.URL.String(); got != tt.wantURL {
+				t.Errorf("URL = %q, want %q", got, tt.wantURL)
+			}
+			if string(body) != tt.wantBody {
+				t.Errorf("body = %q, want %q", body, tt.wantBody)
+			}
+			if got := httpReq.Header.Get("Content-Type"); got != tt.wantType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantType)
+			}
+			if tt.wantHostHdr != "" && httpReq.Host != tt.wantHostHdr {
+				t.Errorf("Host = %q, want %q", httpReq.Host, tt.wantHostHdr)
+			}
+			if httpReq.Body != nil && httpReq.Body != http.NoBody {
+				data, _ := io.ReadAll(httpReq.Body)
+				t.Errorf("build() set a body %q; Do attaches it per attempt", data)
+			}
+		})
+	}
+}
@@ -0,0 +1,207 @@
+// Package idgen generates UUIDs, ULIDs, nanoids, and random strings.
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/bits"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kinds of identifier.
+const (
+	KindUUID      = "uuid"
+	KindUUIDv7    = "uuidv7"
+	KindULID      = "ulid"
+	KindNanoID    = "nanoid"
+	KindHex       = "hex"
+	KindBase64    = "base64"
+	KindBase64URL = "base64url"
+)
+
+// Kinds lists the supported kinds.
+var Kinds = []string{KindUUID, KindUUIDv7, KindULID, KindNanoID, KindHex, KindBase64, KindBase64URL}
+
+// Defaults for Options.
+const (
+	DefaultNanoIDLength   = 21
+	DefaultBytes          = 16
+	DefaultNanoIDAlphabet = "_-0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+)
+
+// crockford is the ULID alphabet: Crockford's base32, without I, L, O, U.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// Options configures a Generator.
+type Options struct {
+	// Length is the nanoid length in characters.
+	Length int
+	// Alphabet is the nanoid alphabet.
+	Alphabet string
+	// Bytes is the amount of randomness behind hex and base64 strings.
+	Bytes int
+}
+
+// Generator produces identifiers of one kind. ULIDs from the same
+// Generator are strictly increasing, even within one millisecond.
+type Generator struct {
+	kind string
+	opts Options
+	rand io.Reader
+	now  func() time.Time
+
+	lastULID [16]byte
+}
+
+// New returns a Generator for kind that reads crypto/rand.
+func New(kind string, opts Options) (*Generator, error) {
+	return newGenerator(kind, opts, rand.Reader, time.Now)
+}
+
+func newGenerator(kind string, opts Options, r io.Reader, now func() time.Time) (*Generator, error) {
+	kind = strings.ToLower(kind)
+	switch kind {
+	case KindUUID, KindUUIDv7, KindULID:
+	case KindNanoID:
+		if opts.Length == 0 {
+			opts.Length = DefaultNanoIDLength
+		}
+		if opts.Alphabet == "" {
+			opts.Alphabet = DefaultNanoIDAlphabet
+		}
+		if opts.Length < 1 {
+			return nil, fmt.Errorf("nanoid length must be at least 1, got %d", opts.Length)
+		}
+		if n := len(opts.Alphabet); n < 2 || n > 256 {
+			return nil, fmt.Errorf("nanoid alphabet must have 2 to 256 characters, got %d", n)
+		}
+		if !isASCII(opts.Alphabet) {
+			return nil, errors.New("nanoid alphabet must be ASCII")
+		}
+	case KindHex, KindBase64, KindBase64URL:
+		if opts.Bytes == 0 {
+			opts.Bytes = DefaultBytes
+		}
+		if opts.Bytes < 1 {
+			return nil, fmt.Errorf("byte count must be at least 1, got %d", opts.Bytes)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported kind %q (supported: %s)", kind, strings.Join(Kinds, ", "))
+	}
+	return &Generator{kind: kind, opts: opts, rand: r, now: now}, nil
+}
+
+// Next returns a new identifier.
+func (g *Generator) Next() (string, error) {
+	switch g.kind {
+	case KindUUID:
+		id, err := uuid.NewRandomFromReader(g.rand)
+		return id.String(), err
+	case KindUUIDv7:
+		id, err := uuid.NewV7FromReader(g.rand)
+		return id.String(), err
+	case KindULID:
+		return g.ulid()
+	case KindNanoID:
+		return g.nanoid()
+	}
+
+	buf := make([]byte, g.opts.Bytes)
+	if _, err := io.ReadFull(g.rand, buf); err != nil {
+		return "", err
+	}
+	switch g.kind {
+	case KindHex:
+		return hex.EncodeToString(buf), nil
+	case KindBase64:
+		return base64.StdEncoding.EncodeToString(buf), nil
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// ulid returns a 26-character ULID: a 48-bit millisecond timestamp and 80
+// random bits. Within the same millisecond as the previous ULID the random
+// part is incremented instead of redrawn, keeping the sequence sortable.
+func (g *Generator) ulid() (string, error) {
+	var id [16]byte
+	ms := uint64(g.now().UnixMilli())
+	binary.BigEndian.PutUint16(id[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(id[2:6], uint32(ms))
+
+	if [6]byte(id[:6]) == [6]byte(g.lastULID[:6]) {
+		copy(id[6:], g.lastULID[6:])
+		if !increment(id[6:]) {
+			return "", errors.New("ULID random component overflowed within one millisecond")
+		}
+	} else if _, err := io.ReadFull(g.rand, id[6:]); err != nil {
+		return "", err
+	}
+	g.lastULID = id
+	return encodeULID(id), nil
+}
+
+// increment adds one to the big-endian number b and reports false on
+// overflow.
+func increment(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// encodeULID encodes 128 bits as 26 base32 characters, most significant
+// first; the leading character carries only 3 bits.
+func encodeULID(id [16]byte) string {
+	hi := binary.BigEndian.Uint64(id[:8])
+	lo := binary.BigEndian.Uint64(id[8:])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// nanoid draws characters uniformly from the alphabet by masking random
+// bytes to the next power of two and rejecting values outside it.
+func (g *Generator) nanoid() (string, error) {
+	alphabet := g.opts.Alphabet
+	mask := 1<<bits.Len(uint(len(alphabet)-1)) - 1
+	out := make([]byte, 0, g.opts.Length)
+	buf := make([]byte, g.opts.Length)
+	for len(out) < g.opts.Length {
+		if _, err := io.ReadFull(g.rand, buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if i := int(b) & mask; i < len(alphabet) {
+				out = append(out, alphabet[i])
+				if len(out) == g.opts.Length {
+					break
+				}
+			}
+		}
+	}
+	return string(out), nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,125 @@
+package idgen
+
+import (
+	"bytes"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// zeros is an endless reader of zero bytes.
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestGenerator_Formats(t *testing.T) {
+	tests := []struct {
+		kind string
+		opts Options
+		want *regexp.Regexp
+	}{
+		{KindUUID, Options{}, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)},
+		{KindUUIDv7, Options{}, regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)},
+		{KindULID, Options{}, regexp.MustCompile(`^[0-7][0-9A-HJKMNP-TV-Z]{25}$`)},
+		{KindNanoID, Options{}, regexp.MustCompile(`^[A-Za-z0-9_-]{21}$`)},
+		{KindNanoID, Options{Length: 8, Alphabet: "ab"}, regexp.MustCompile(`^[ab]{8}$`)},
+		{KindHex, Options{}, regexp.MustCompile(`^[0-9a-f]{32}$`)},
+		{KindHex, Options{Bytes: 4}, regexp.MustCompile(`^[0-9a-f]{8}$`)},
+		{KindBase64, Options{Bytes: 4}, regexp.MustCompile(`^[A-Za-z0-9+/]{6}==$`)},
+		{KindBase64URL, Options{Bytes: 32}, regexp.MustCompile(`^[A-Za-z0-9_-]{43}$`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			g, err := New(tt.kind, tt.opts)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			first, err := g.Next()
+			if err != nil {
+				t.Fatalf("Next() error = %v", err)
+			}
+			if !tt.want.MatchString(first) {
+				t.Errorf("Next() = %q, want match for %s", first, tt.want)
+			}
+			if second, _ := g.Next(); second == first {
+				t.Errorf("Next() returned %q twice", first)
+			}
+		})
+	}
+}
+
+func TestULID_Encoding(t *testing.T) {
+	now := func() time.Time { return time.UnixMilli(1469918176385) }
+	g, err := newGenerator(KindULID, Options{}, zeros{}, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := g.Next()
+	if want := "01ARYZ6S410000000000000000"; got != want {
+		t.Errorf("ulid = %q, want %q", got, want)
+	}
+	// Same millisecond: the random part is incremented.
+	if got, _ := g.Next(); got != "01ARYZ6S410000000000000001" {
+		t.Errorf("second ulid = %q", got)
+	}
+}
+
+func TestULID_Monotonic(t *testing.T) {
+	g, err := New(KindULID, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := make([]string, 1000)
+	for i := range ids {
+		if ids[i], err = g.Next(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if !sort.StringsAreSorted(ids) {
+		t.Error("ULIDs from one generator are not sorted")
+	}
+}
+
+func TestULID_Overflow(t *testing.T) {
+	now := func() time.Time { return time.UnixMilli(1) }
+	g, err := newGenerator(KindULID, Options{}, bytes.NewReader(bytes.Repeat([]byte{0xff}, 10)), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Next(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.Next(); err == nil || !strings.Contains(err.Error(), "overflowed") {
+		t.Errorf("Next() error = %v, want overflow", err)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		kind string
+		opts Options
+		want string
+	}{
+		{"guid", Options{}, `unsupported kind "guid"`},
+		{KindNanoID, Options{Length: -1}, "length must be at least 1"},
+		{KindNanoID, Options{Alphabet: "a"}, "2 to 256 characters"},
+		{KindNanoID, Options{Alphabet: "äö"}, "must be ASCII"},
+		{KindHex, Options{Bytes: -4}, "at least 1"},
+	}
+	for _, tt := range tests {
+		if _, err := New(tt.kind, tt.opts); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("New(%q, %+v) error = %v, want %q", tt.kind, tt.opts, err, tt.want)
+		}
+	}
+}
+
+func TestNew_CaseInsensitive(t *testing.T) {
+	if _, err := New("ULID", Options{}); err != nil {
+		t.Errorf("New(ULID) error = %v", err)
+	}
+}
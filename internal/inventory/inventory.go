@@ -0,0 +1,392 @@
+// Package inventory reads the host inventory: the hosts ado's remote
+// commands reach, their SSH settings and labels, and named groups of
+// them.
+package inventory
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anowarislam/ado/internal/config"
+)
+
+// EnvVar names the inventory file when --inventory is not given.
+const EnvVar = "ADO_INVENTORY"
+
+// FileName is the inventory file looked up next to the user config file.
+const FileName = "inventory.yaml"
+
+// Inventory is a parsed inventory file.
+type Inventory struct {
+	Version int `yaml:"version"`
+	// Defaults are the SSH settings of every host.
+	Defaults SSH              `yaml:"defaults"`
+	Hosts    map[string]Entry `yaml:"hosts"`
+	Groups   map[string]Group `yaml:"groups"`
+}
+
+// SSH holds how a host is reached.
+type SSH struct {
+	User string `yaml:"user"`
+	Port int    `yaml:"port"`
+	// Options are extra ssh arguments, such as -oConnectTimeout=5.
+	Options []string `yaml:"options"`
+}
+
+// Entry is a host as written in the file.
+type Entry struct {
+	// Address is what ssh connects to; defaults to the host's name, which
+	// may itself be a Host entry of ~/.ssh/config.
+	Address string `yaml:"address"`
+	SSH     `yaml:",inline"`
+	Labels  map[string]string `yaml:"labels"`
+}
+
+// Group names a set of hosts and the settings they share.
+type Group struct {
+	// Hosts are host names or glob patterns over them, such as web-*.
+	Hosts []string `yaml:"hosts"`
+	// Groups are included groups, whose hosts are members too.
+	Groups []string `yaml:"groups"`
+	SSH    `yaml:",inline"`
+	Labels map[string]string `yaml:"labels"`
+}
+
+// Host is a host with the settings of its groups and the defaults
+// applied.
+type Host struct {
+	Name    string            `json:"name" yaml:"name"`
+	Address string            `json:"address" yaml:"address"`
+	User    string            `json:"user,omitempty" yaml:"user,omitempty"`
+	Port    int               `json:"port,omitempty" yaml:"port,omitempty"`
+	Options []string          `json:"ssh_options,omitempty" yaml:"ssh_options,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+	Groups  []string          `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+// Destination is the ssh destination, [user@]address.
+func (h Host) Destination() string {
+	if h.User != "" {
+		return h.User + "@" + h.Address
+	}
+	return h.Address
+}
+
+// SSHArgs are the ssh arguments placed before the destination.
+func (h Host) SSHArgs() []string {
+	var args []string
+	if h.Port != 0 {
+		args = append(args, "-p", fmt.Sprint(h.Port))
+	}
+	return append(args, h.Options...)
+}
+
+// Path returns the inventory file to use: explicit if set, then
+// $ADO_INVENTORY, then inventory.yaml in the user config directory.
+func Path(explicit, homeDir string) string {
+	if explicit != "" {
+		return explicit
+	}
+	if env := os.Getenv(EnvVar); env != "" {
+		return env
+	}
+	userConfig := config.UserConfigPath(homeDir)
+	if userConfig == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(userConfig), FileName)
+}
+
+// Load reads the inventory at path and fails on the first problem Check
+// reports.
+func Load(path string) (*Inventory, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read inventory: %w", err)
+	}
+	inv, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if problems := inv.Check(); len(problems) > 0 {
+		msg := problems[0]
+		if len(problems) > 1 {
+			msg += fmt.Sprintf(" (and %d more; see 'ado inventory validate')", len(problems)-1)
+		}
+		return nil, fmt.Errorf("%s: %s", path, msg)
+	}
+	return inv, nil
+}
+
+// Parse decodes an inventory. Unknown fields are errors; the content is
+// not checked, see Check.
+func Parse(r io.Reader) (*Inventory, error) {
+	dec := yaml.NewDecoder(r)
+	dec.KnownFields(true)
+	var inv Inventory
+	if err := dec.Decode(&inv); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("inventory is empty")
+		}
+		return nil, fmt.Errorf("parse inventory: %w", err)
+	}
+	return &inv, nil
+}
+
+// Check returns every problem in the inventory, sorted by host and group
+// name.
+func (inv *Inventory) Check() []string {
+	var problems []string
+	if inv.Version != 1 {
+		problems = append(problems, fmt.Sprintf("unsupported version %d (expected 1)", inv.Version))
+	}
+	problems = append(problems, sshProblems("defaults", inv.Defaults)...)
+
+	for _, name := range slices.Sorted(maps.Keys(inv.Hosts)) {
+		entry := inv.Hosts[name]
+		where := fmt.Sprintf("host %q", name)
+		if !validName(name) {
+			problems = append(problems, fmt.Sprintf("%s: invalid name", where))
+		}
+		if entry.Address != "" && !validName(entry.Address) {
+			problems = append(problems, fmt.Sprintf("%s: invalid address %q", where, entry.Address))
+		}
+		problems = append(problems, sshProblems(where, entry.SSH)...)
+		problems = append(problems, labelProblems(where, entry.Labels)...)
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(inv.Groups)) {
+		group := inv.Groups[name]
+		where := fmt.Sprintf("group %q", name)
+		if !validName(name) {
+			problems = append(problems, fmt.Sprintf("%s: invalid name", where))
+		}
+		if _, ok := inv.Hosts[name]; ok {
+			problems = append(problems, fmt.Sprintf("%s: a host has the same name", where))
+		}
+		for _, pattern := range group.Hosts {
+			if _, err := path.Match(pattern, ""); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: invalid host pattern %q", where, pattern))
+			} else if len(inv.matchHosts(pattern)) == 0 {
+				problems = append(problems, fmt.Sprintf("%s: no host matches %q", where, pattern))
+			}
+		}
+		for _, included := range group.Groups {
+			if _, ok := inv.Groups[included]; !ok {
+				problems = append(problems, fmt.Sprintf("%s: unknown group %q", where, included))
+			}
+		}
+		if cycle := inv.cycle(name); cycle != nil {
+			problems = append(problems, fmt.Sprintf("%s: groups include each other: %s", where, strings.Join(cycle, " -> ")))
+		}
+		problems = append(problems, sshProblems(where, group.SSH)...)
+		problems = append(problems, labelProblems(where, group.Labels)...)
+	}
+	return problems
+}
+
+func sshProblems(where string, s SSH) []string {
+	var problems []string
+	if s.User != "" && !validName(s.User) {
+		problems = append(problems, fmt.Sprintf("%s: invalid user %q", where, s.User))
+	}
+	if s.Port < 0 || s.Port > 65535 {
+		problems = append(problems, fmt.Sprintf("%s: port %d out of range", where, s.Port))
+	}
+	for _, opt := range s.Options {
+		// Options go before the destination, so anything else would be
+		// taken as the host.
+		if !strings.HasPrefix(opt, "-") {
+			problems = append(problems, fmt.Sprintf("%s: ssh option %q does not start with -", where, opt))
+		}
+	}
+	return problems
+}
+
+func labelProblems(where string, labels map[string]string) []string {
+	var problems []string
+	for _, key := range slices.Sorted(maps.Keys(labels)) {
+		if key == "" || strings.ContainsAny(key, "=, ") {
+			problems = append(problems, fmt.Sprintf("%s: invalid label %q", where, key))
+		}
+	}
+	return problems
+}
+
+// validName reports whether s is usable as a host, address, or user: no
+// whitespace or shell metacharacters, and no leading - that ssh would
+// read as an option.
+func validName(s string) bool {
+	if s == "" || strings.HasPrefix(s, "-") {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || strings.ContainsRune("._-@:[]%", r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// cycle returns a chain of included groups leading from start back to
+// it, or nil.
+func (inv *Inventory) cycle(start string) []string {
+	seen := map[string]bool{}
+	var walk func(trail []string) []string
+	walk = func(trail []string) []string {
+		for _, included := range inv.Groups[trail[len(trail)-1]].Groups {
+			if included == start {
+				return append(slices.Clone(trail), start)
+			}
+			if seen[included] {
+				continue
+			}
+			seen[included] = true
+			if c := walk(append(slices.Clone(trail), included)); c != nil {
+				return c
+			}
+		}
+		return nil
+	}
+	return walk([]string{start})
+}
+
+// matchHosts returns the names of the hosts matching a glob pattern.
+func (inv *Inventory) matchHosts(pattern string) []string {
+	var names []string
+	for _, name := range slices.Sorted(maps.Keys(inv.Hosts)) {
+		if ok, _ := path.Match(pattern, name); ok {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// members returns the host names in group and the groups it includes.
+func (inv *Inventory) members(group string, seen map[string]bool) []string {
+	if seen[group] {
+		return nil
+	}
+	seen[group] = true
+	var names []string
+	for _, pattern := range inv.Groups[group].Hosts {
+		names = append(names, inv.matchHosts(pattern)...)
+	}
+	for _, included := range inv.Groups[group].Groups {
+		names = append(names, inv.members(included, seen)...)
+	}
+	return names
+}
+
+// groupsOf returns the sorted names of the groups host belongs to.
+func (inv *Inventory) groupsOf(host string) []string {
+	var groups []string
+	for _, name := range slices.Sorted(maps.Keys(inv.Groups)) {
+		if slices.Contains(inv.members(name, map[string]bool{}), host) {
+			groups = append(groups, name)
+		}
+	}
+	return groups
+}
+
+// Host returns the named host with its settings resolved. The defaults
+// apply first, then each of its groups in name order, then the host's
+// own settings; options accumulate and later values win otherwise.
+func (inv *Inventory) Host(name string) (Host, bool) {
+	entry, ok := inv.Hosts[name]
+	if !ok {
+		return Host{}, false
+	}
+	h := Host{Name: name, Address: name, Groups: inv.groupsOf(name)}
+	apply := func(s SSH, labels map[string]string) {
+		if s.User != "" {
+			h.User = s.User
+		}
+		if s.Port != 0 {
+			h.Port = s.Port
+		}
+		h.Options = append(h.Options, s.Options...)
+		for k, v := range labels {
+			if h.Labels == nil {
+				h.Labels = map[string]string{}
+			}
+			h.Labels[k] = v
+		}
+	}
+	apply(inv.Defaults, nil)
+	for _, group := range h.Groups {
+		apply(inv.Groups[group].SSH, inv.Groups[group].Labels)
+	}
+	apply(entry.SSH, entry.Labels)
+	if entry.Address != "" {
+		h.Address = entry.Address
+	}
+	return h, true
+}
+
+// All returns every host, sorted by name.
+func (inv *Inventory) All() []Host {
+	hosts := make([]Host, 0, len(inv.Hosts))
+	for _, name := range slices.Sorted(maps.Keys(inv.Hosts)) {
+		h, _ := inv.Host(name)
+		hosts = append(hosts, h)
+	}
+	return hosts
+}
+
+// Select returns the hosts matching any of patterns, sorted by name. A
+// pattern is a group or host name, a glob over group and host names such
+// as web-*, or a label selector KEY=VALUE whose value may be a glob. A
+// pattern matching nothing is an error, so a typo does not silently
+// shrink the target list.
+func (inv *Inventory) Select(patterns []string) ([]Host, error) {
+	selected := map[string]bool{}
+	all := inv.All()
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid host pattern %q", pattern)
+		}
+		matched := false
+		if key, value, ok := strings.Cut(pattern, "="); ok {
+			for _, h := range all {
+				if v, has := h.Labels[key]; has {
+					if ok, _ := path.Match(value, v); ok {
+						selected[h.Name], matched = true, true
+					}
+				}
+			}
+		} else {
+			for _, group := range slices.Sorted(maps.Keys(inv.Groups)) {
+				if ok, _ := path.Match(pattern, group); ok {
+					for _, name := range inv.members(group, map[string]bool{}) {
+						selected[name], matched = true, true
+					}
+				}
+			}
+			for _, name := range inv.matchHosts(pattern) {
+				selected[name], matched = true, true
+			}
+		}
+		if !matched {
+			return nil, fmt.Errorf("no hosts match %q", pattern)
+		}
+	}
+
+	var hosts []Host
+	for _, h := range all {
+		if selected[h.Name] {
+			hosts = append(hosts, h)
+		}
+	}
+	return hosts, nil
+}
@@ -0,0 +1,199 @@
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testInventory = `version: 1
+defaults:
+  user: ops
+  options: [-oConnectTimeout=5]
+hosts:
+  web-1: {address: 10.0.1.11, labels: {zone: a}}
+  web-2: {address: 10.0.1.12, port: 2222, labels: {zone: b}}
+  db-1: {user: postgres}
+  bastion: {}
+groups:
+  web:
+    hosts: [web-*]
+    labels: {role: web}
+  prod:
+    groups: [web]
+    hosts: [db-1]
+    user: deploy
+    labels: {env: prod}
+`
+
+func parse(t *testing.T, data string) *Inventory {
+	t.Helper()
+	inv, err := Parse(strings.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return inv
+}
+
+func TestInventory_Host(t *testing.T) {
+	inv := parse(t, testInventory)
+	if problems := inv.Check(); len(problems) > 0 {
+		t.Fatalf("problems: %v", problems)
+	}
+
+	tests := []struct {
+		name string
+		want Host
+	}{
+		{"web-2", Host{
+			Name: "web-2", Address: "10.0.1.12", User: "deploy", Port: 2222,
+			Options: []string{"-oConnectTimeout=5"},
+			Labels:  map[string]string{"env": "prod", "role": "web", "zone": "b"},
+			Groups:  []string{"prod", "web"},
+		}},
+		{"db-1", Host{
+			Name: "db-1", Address: "db-1", User: "postgres",
+			Options: []string{"-oConnectTimeout=5"},
+			Labels:  map[string]string{"env": "prod"},
+			Groups:  []string{"prod"},
+		}},
+		{"bastion", Host{Name: "bastion", Address: "bastion", User: "ops", Options: []string{"-oConnectTimeout=5"}}},
+	}
+	for _, tt := range tests {
+		got, ok := inv.Host(tt.name)
+		if !ok || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Host(%q) =\n%+v\nwant\n%+v", tt.name, got, tt.want)
+		}
+	}
+	if _, ok := inv.Host("nope"); ok {
+		t.Error("Host(nope) found")
+	}
+
+	web2, _ := inv.Host("web-2")
+	if web2.Destination() != "deploy@10.0.1.12" || strings.Join(web2.SSHArgs(), " ") != "-p 2222 -oConnectTimeout=5" {
+		t.Errorf("Destination = %q, SSHArgs = %v", web2.Destination(), web2.SSHArgs())
+	}
+}
+
+func TestInventory_Select(t *testing.T) {
+	inv := parse(t, testInventory)
+	tests := []struct {
+		patterns []string
+		want     string
+		err      string
+	}{
+		{[]string{"web"}, "web-1 web-2", ""},
+		{[]string{"prod"}, "db-1 web-1 web-2", ""},
+		{[]string{"web-*"}, "web-1 web-2", ""},
+		{[]string{"p*"}, "db-1 web-1 web-2", ""},
+		{[]string{"bastion", "db-1"}, "bastion db-1", ""},
+		{[]string{"zone=b"}, "web-2", ""},
+		{[]string{"role=w*", "bastion"}, "bastion web-1 web-2", ""},
+		{[]string{"web", "cache-*"}, "", `no hosts match "cache-*"`},
+		{[]string{"zone=c"}, "", `no hosts match "zone=c"`},
+		{[]string{"web-["}, "", "invalid host pattern"},
+	}
+	for _, tt := range tests {
+		hosts, err := inv.Select(tt.patterns)
+		if tt.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.err) {
+				t.Errorf("Select(%v) error = %v, want %q", tt.patterns, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("Select(%v): %v", tt.patterns, err)
+			continue
+		}
+		var names []string
+		for _, h := range hosts {
+			names = append(names, h.Name)
+		}
+		if got := strings.Join(names, " "); got != tt.want {
+			t.Errorf("Select(%v) = %s, want %s", tt.patterns, got, tt.want)
+		}
+	}
+}
+
+func TestInventory_Check(t *testing.T) {
+	inv := parse(t, `version: 2
+defaults: {options: [ConnectTimeout=5]}
+hosts:
+  -web: {}
+  web-1: {address: "10.0.1.11; rm -rf /", port: 70000}
+  web: {labels: {"a=b": x}}
+groups:
+  web: {hosts: [web-1]}
+  a: {groups: [b]}
+  b: {groups: [a, missing], hosts: [cache-*]}
+`)
+	want := []string{
+		"unsupported version 2 (expected 1)",
+		`defaults: ssh option "ConnectTimeout=5" does not start with -`,
+		`host "-web": invalid name`,
+		`host "web": invalid label "a=b"`,
+		`host "web-1": invalid address "10.0.1.11; rm -rf /"`,
+		`host "web-1": port 70000 out of range`,
+		`group "a": groups include each other: a -> b -> a`,
+		`group "b": no host matches "cache-*"`,
+		`group "b": unknown group "missing"`,
+		`group "b": groups include each other: b -> a -> b`,
+		`group "web": a host has the same name`,
+	}
+	if got := inv.Check(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Check() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	if _, err := Load(write("ok.yaml", testInventory)); err != nil {
+		t.Errorf("Load: %v", err)
+	}
+	tests := []struct {
+		data string
+		want string
+	}{
+		{"", "inventory is empty"},
+		{"version: 1\nhost: {}\n", "field host not found"},
+		{"version: 1\nhosts: {a: {port: -1, user: '-x'}}\n", `invalid user "-x" (and 1 more; see 'ado inventory validate')`},
+	}
+	for i, tt := range tests {
+		_, err := Load(write(fmt.Sprintf("bad%d.yaml", i), tt.data))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Load(%q) error = %v, want %q", tt.data, err, tt.want)
+		}
+	}
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("missing file error = %v", err)
+	}
+}
+
+func TestPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv(EnvVar, "")
+	if got := Path("/etc/ado/hosts.yaml", home); got != "/etc/ado/hosts.yaml" {
+		t.Errorf("explicit = %q", got)
+	}
+	if got := Path("", home); filepath.Base(got) != FileName || !strings.HasPrefix(got, home) {
+		t.Errorf("default = %q", got)
+	}
+	t.Setenv(EnvVar, "/srv/inventory.yaml")
+	if got := Path("", home); got != "/srv/inventory.yaml" {
+		t.Errorf("env = %q", got)
+	}
+}
@@ -0,0 +1,109 @@
+// Package junit writes JUnit XML reports, the test report format Jenkins,
+// GitLab, and Azure Pipelines display natively, so that the results of
+// ado's validation and check commands show up in their test report UIs.
+//
+// The schema followed is the common subset those tools read: testsuites
+// holding testsuite elements, each holding testcase elements with an
+// optional failure or skipped child.
+package junit
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// TestSuites is the root element of a report.
+type TestSuites struct {
+	XMLName  xml.Name    `xml:"testsuites"`
+	Name     string      `xml:"name,attr,omitempty"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Time     string      `xml:"time,attr,omitempty"`
+	Suites   []TestSuite `xml:"testsuite"`
+}
+
+// TestSuite groups the cases of one check, such as one config file or
+// one workflow run.
+type TestSuite struct {
+	Name      string     `xml:"name,attr"`
+	Tests     int        `xml:"tests,attr"`
+	Failures  int        `xml:"failures,attr"`
+	Errors    int        `xml:"errors,attr"`
+	Skipped   int        `xml:"skipped,attr"`
+	Time      string     `xml:"time,attr,omitempty"`
+	Timestamp string     `xml:"timestamp,attr,omitempty"`
+	Cases     []TestCase `xml:"testcase"`
+}
+
+// TestCase is one check. It passed unless Failure or Skipped is set.
+type TestCase struct {
+	Name      string   `xml:"name,attr"`
+	Classname string   `xml:"classname,attr"`
+	Time      string   `xml:"time,attr,omitempty"`
+	Failure   *Failure `xml:"failure,omitempty"`
+	Skipped   *Skipped `xml:"skipped,omitempty"`
+	SystemOut string   `xml:"system-out,omitempty"`
+}
+
+// Failure describes why a case failed. Message is the one-line summary CI
+// systems show in lists; Text holds any detail.
+type Failure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// Skipped marks a case that did not run, with the reason.
+type Skipped struct {
+	Message string `xml:"message,attr,omitempty"`
+}
+
+// NewSuite returns a suite of cases with its counts filled in.
+func NewSuite(name string, cases ...TestCase) TestSuite {
+	suite := TestSuite{Name: name, Tests: len(cases), Cases: cases}
+	for _, c := range cases {
+		switch {
+		case c.Failure != nil:
+			suite.Failures++
+		case c.Skipped != nil:
+			suite.Skipped++
+		}
+	}
+	return suite
+}
+
+// Seconds formats d as the time attribute expects it: seconds with
+// millisecond precision.
+func Seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', 3, 64)
+}
+
+// Timestamp formats t as the timestamp attribute expects it: ISO 8601
+// without a zone, in UTC.
+func Timestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05")
+}
+
+// Write writes a report of suites to w, named name, with totals summed
+// from the suites.
+func Write(w io.Writer, name string, suites ...TestSuite) error {
+	report := TestSuites{Name: name, Suites: suites}
+	for _, s := range suites {
+		report.Tests += s.Tests
+		report.Failures += s.Failures
+		report.Skipped += s.Skipped
+	}
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serialize junit: %w", err)
+	}
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
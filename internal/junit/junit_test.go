@@ -0,0 +1,52 @@
+package junit
+
+import (
+	"bytes"
+	"encoding/xml"
+	"testing"
+	"time"
+)
+
+func TestWrite(t *testing.T) {
+	suite := NewSuite("ado config validate",
+		TestCase{Name: "valid", Classname: "config.yaml", Time: Seconds(1500 * time.Millisecond)},
+		TestCase{Name: "line 3", Classname: "config.yaml", Failure: &Failure{Message: `unknown key "a<b"`, Type: "error", Text: "detail"}},
+		TestCase{Name: "signature", Classname: "ado", Skipped: &Skipped{Message: "unsigned"}},
+	)
+	var buf bytes.Buffer
+	if err := Write(&buf, "ado", suite); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `<?xml version="1.0" encoding="UTF-8"?>
+<testsuites name="ado" tests="3" failures="1" skipped="1">
+  <testsuite name="ado config validate" tests="3" failures="1" errors="0" skipped="1">
+    <testcase name="valid" classname="config.yaml" time="1.500"></testcase>
+    <testcase name="line 3" classname="config.yaml">
+      <failure message="unknown key &#34;a&lt;b&#34;" type="error">detail</failure>
+    </testcase>
+    <testcase name="signature" classname="ado">
+      <skipped message="unsigned"></skipped>
+    </testcase>
+  </testsuite>
+</testsuites>
+`
+	if buf.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	var parsed TestSuites
+	if err := xml.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatalf("output does not parse: %v", err)
+	}
+	if got := parsed.Suites[0].Cases[1].Failure.Message; got != `unknown key "a<b"` {
+		t.Errorf("round-tripped failure message = %q", got)
+	}
+}
+
+func TestTimestamp(t *testing.T) {
+	ts := time.Date(2026, 10, 16, 9, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	if got := Timestamp(ts); got != "2026-10-16T07:30:00" {
+		t.Errorf("Timestamp() = %q", got)
+	}
+}
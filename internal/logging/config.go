@@ -14,6 +14,9 @@ type Config struct {
 	// Output is the output destination: stderr, stdout.
 	// Default: "stderr"
 	Output string
+
+	// RunLog, when set, also receives every record, whatever Level is.
+	RunLog *RunLog
 }
 
 // DefaultConfig returns the default logging configuration.
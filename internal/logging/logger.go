@@ -39,6 +39,9 @@ func New(cfg Config) Logger {
 	level := parseLevel(cfg.Level)
 	output := resolveOutput(cfg.Output)
 	handler := createHandler(cfg.Format, output, level)
+	if cfg.RunLog != nil {
+		handler = Tee(handler, cfg.RunLog.Handler())
+	}
 
 	return &logger{
 		slog: slog.New(handler),
@@ -0,0 +1,239 @@
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Run statuses reported by ReadRunInfo.
+const (
+	RunOK     = "ok"
+	RunFailed = "failed"
+	// RunUnfinished is a run without a Run finished record: still running,
+	// or killed.
+	RunUnfinished = "unfinished"
+)
+
+// Messages of the records that open and close a run log.
+const (
+	MsgRunStarted  = "Run started"
+	MsgRunFinished = "Run finished"
+)
+
+// tailBytes is how much of the end of a run log ReadRunInfo reads to find
+// the Run finished record.
+const tailBytes = 64 << 10
+
+// RunInfo summarizes a run log.
+type RunInfo struct {
+	ID         string    `json:"run_id" yaml:"run_id"`
+	Path       string    `json:"path" yaml:"path"`
+	Command    string    `json:"command" yaml:"command"`
+	Started    time.Time `json:"started" yaml:"started"`
+	Status     string    `json:"status" yaml:"status"`
+	ExitCode   *int      `json:"exit_code,omitempty" yaml:"exit_code,omitempty"`
+	DurationMS int64     `json:"duration_ms,omitempty" yaml:"duration_ms,omitempty"`
+	Error      string    `json:"error,omitempty" yaml:"error,omitempty"`
+	SizeBytes  int64     `json:"size_bytes" yaml:"size_bytes"`
+}
+
+// Attr is a field of a record, in the order it was written.
+type Attr struct {
+	Key   string
+	Value json.RawMessage
+}
+
+// Record is one line of a run log.
+type Record struct {
+	Time  time.Time
+	Level slog.Level
+	Msg   string
+	RunID string
+	// Attrs holds the fields besides the four above.
+	Attrs []Attr
+	// Line is the record as written, without the newline.
+	Line []byte
+}
+
+// Attr returns the value of the field key, or nil.
+func (r Record) Attr(key string) json.RawMessage {
+	for _, a := range r.Attrs {
+		if a.Key == key {
+			return a.Value
+		}
+	}
+	return nil
+}
+
+// String returns the string field key, or "".
+func (r Record) String(key string) string {
+	var s string
+	_ = json.Unmarshal(r.Attr(key), &s)
+	return s
+}
+
+// ParseRecord parses a line of a run log.
+func ParseRecord(line []byte) (Record, error) {
+	rec := Record{Line: line}
+	dec := json.NewDecoder(bytes.NewReader(line))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return rec, errors.New("not a JSON object")
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return rec, err
+		}
+		key, _ := tok.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return rec, err
+		}
+		var s string
+		switch key {
+		case slog.TimeKey:
+			if err := json.Unmarshal(value, &rec.Time); err != nil {
+				return rec, fmt.Errorf("time: %w", err)
+			}
+		case slog.LevelKey:
+			if err := json.Unmarshal(value, &s); err != nil {
+				return rec, fmt.Errorf("level: %w", err)
+			}
+			if err := rec.Level.UnmarshalText([]byte(s)); err != nil {
+				return rec, err
+			}
+		case slog.MessageKey:
+			_ = json.Unmarshal(value, &rec.Msg)
+		case "run_id":
+			_ = json.Unmarshal(value, &rec.RunID)
+		default:
+			rec.Attrs = append(rec.Attrs, Attr{Key: key, Value: value})
+		}
+	}
+	return rec, nil
+}
+
+// ReadRecords calls fn with each complete record of the run log at path
+// from byte offset on, and returns the offset after the last one, where
+// reading can resume once more is written. Lines that are not records are
+// skipped.
+func ReadRecords(path string, offset int64, fn func(Record) error) (int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return offset, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return offset, err
+	}
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if err == io.EOF {
+			// A partial line is still being written.
+			return offset, nil
+		}
+		if err != nil {
+			return offset, err
+		}
+		offset += int64(len(line))
+		rec, perr := ParseRecord(bytes.TrimSuffix(line, []byte("\n")))
+		if perr != nil {
+			continue
+		}
+		if err := fn(rec); err != nil {
+			return offset, err
+		}
+	}
+}
+
+// ReadRunInfo summarizes the run log at path from its first record and
+// its Run finished record.
+func ReadRunInfo(path string) (RunInfo, error) {
+	_, id, ok := ParseRunLogName(filepath.Base(path))
+	if !ok {
+		return RunInfo{}, fmt.Errorf("%s is not a run log", path)
+	}
+	info := RunInfo{ID: id, Path: path, Status: RunUnfinished}
+	f, err := os.Open(path)
+	if err != nil {
+		return info, err
+	}
+	defer f.Close()
+	stat, err := f.Stat()
+	if err != nil {
+		return info, err
+	}
+	info.SizeBytes, info.Started = stat.Size(), stat.ModTime()
+
+	first, err := bufio.NewReader(f).ReadBytes('\n')
+	if err != nil && err != io.EOF {
+		return info, err
+	}
+	if rec, err := ParseRecord(bytes.TrimSpace(first)); err == nil && rec.Msg == MsgRunStarted {
+		info.Started, info.Command = rec.Time, rec.String("command")
+	}
+
+	start := max(0, stat.Size()-tailBytes)
+	tail := make([]byte, stat.Size()-start)
+	if _, err := f.ReadAt(tail, start); err != nil && err != io.EOF {
+		return info, err
+	}
+	lines := bytes.Split(bytes.TrimSpace(tail), []byte("\n"))
+	for _, line := range slices.Backward(lines) {
+		rec, err := ParseRecord(line)
+		if err != nil || rec.Msg != MsgRunFinished {
+			continue
+		}
+		var code int
+		if json.Unmarshal(rec.Attr("exit_code"), &code) == nil {
+			info.ExitCode = &code
+		}
+		_ = json.Unmarshal(rec.Attr("duration_ms"), &info.DurationMS)
+		info.Error = rec.String("error")
+		info.Status = RunOK
+		if code != 0 {
+			info.Status = RunFailed
+		}
+		break
+	}
+	return info, nil
+}
+
+// ListRunLogs summarizes the run logs in dir, oldest first. A missing dir
+// has none.
+func ListRunLogs(dir string) ([]RunInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var runs []RunInfo
+	for _, entry := range entries {
+		if _, _, ok := ParseRunLogName(entry.Name()); !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := ReadRunInfo(filepath.Join(dir, entry.Name()))
+		if errors.Is(err, os.ErrNotExist) {
+			// Pruned by another ado process.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, info)
+	}
+	slices.SortStableFunc(runs, func(a, b RunInfo) int { return a.Started.Compare(b.Started) })
+	return runs, nil
+}
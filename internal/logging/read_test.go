@@ -0,0 +1,126 @@
+package logging
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeRunLog writes a run log of id started on 2026-10-16 with lines.
+func writeRunLog(t *testing.T, dir, id string, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(dir, "2026-10-16-"+id+".log")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestParseRecord(t *testing.T) {
+	rec, err := ParseRecord([]byte(`{"time":"2026-10-16T14:02:11.5Z","level":"WARN","msg":"slow","run_id":"01JA","target":"db","attempt":2}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !rec.Time.Equal(time.Date(2026, 10, 16, 14, 2, 11, 500e6, time.UTC)) || rec.Level != slog.LevelWarn || rec.Msg != "slow" || rec.RunID != "01JA" {
+		t.Errorf("record = %+v", rec)
+	}
+	if len(rec.Attrs) != 2 || rec.Attrs[0].Key != "target" || rec.String("target") != "db" || string(rec.Attr("attempt")) != "2" {
+		t.Errorf("attrs = %+v", rec.Attrs)
+	}
+	for _, line := range []string{"", "[]", `{"level":"LOUD"}`, `{"time":"yesterday"}`, `{"msg":`} {
+		if _, err := ParseRecord([]byte(line)); err == nil {
+			t.Errorf("ParseRecord(%q) succeeded", line)
+		}
+	}
+}
+
+func TestReadRecords(t *testing.T) {
+	path := writeRunLog(t, t.TempDir(), "A",
+		`{"time":"2026-10-16T14:00:00Z","level":"INFO","msg":"one"}`,
+		`not a record`,
+		`{"time":"2026-10-16T14:00:01Z","level":"INFO","msg":"two"}`,
+		`{"time":"2026-10-16T14:00:02Z","level":"INFO","msg":"thr`,
+	)
+	var msgs []string
+	collect := func(rec Record) error {
+		msgs = append(msgs, rec.Msg)
+		return nil
+	}
+	offset, err := ReadRecords(path, 0, collect)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(msgs, ",") != "one,two" {
+		t.Errorf("records = %v", msgs)
+	}
+
+	// The partial line is read once it is complete.
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("ee\"}\n")
+	f.Close()
+	msgs = nil
+	if _, err := ReadRecords(path, offset, collect); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(msgs, ",") != "three" {
+		t.Errorf("records after %d = %v", offset, msgs)
+	}
+}
+
+func TestListRunLogs(t *testing.T) {
+	dir := t.TempDir()
+	writeRunLog(t, dir, "OK",
+		`{"time":"2026-10-16T14:00:00Z","level":"INFO","msg":"Run started","run_id":"OK","command":"meta system"}`,
+		`{"time":"2026-10-16T14:00:01Z","level":"INFO","msg":"Run finished","run_id":"OK","command":"meta system","duration_ms":1200,"exit_code":0}`,
+		``)
+	writeRunLog(t, dir, "FAILED",
+		`{"time":"2026-10-16T13:00:00Z","level":"INFO","msg":"Run started","run_id":"FAILED","command":"check run"}`,
+		`{"time":"2026-10-16T13:00:02Z","level":"ERROR","msg":"Run finished","run_id":"FAILED","command":"check run","duration_ms":2000,"exit_code":1,"error":"1 of 2 checks failed"}`,
+		``)
+	writeRunLog(t, dir, "RUNNING",
+		`{"time":"2026-10-16T15:00:00Z","level":"INFO","msg":"Run started","run_id":"RUNNING","command":"agent run"}`,
+		``)
+	if err := os.WriteFile(filepath.Join(dir, "schedule.jsonl"), []byte("{}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	runs, err := ListRunLogs(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runs) != 3 {
+		t.Fatalf("runs = %+v", runs)
+	}
+	want := []struct {
+		id, command, status string
+		exitCode            int
+		durationMS          int64
+	}{
+		{"FAILED", "check run", RunFailed, 1, 2000},
+		{"OK", "meta system", RunOK, 0, 1200},
+		{"RUNNING", "agent run", RunUnfinished, -1, 0},
+	}
+	for i, w := range want {
+		run := runs[i]
+		code := -1
+		if run.ExitCode != nil {
+			code = *run.ExitCode
+		}
+		if run.ID != w.id || run.Command != w.command || run.Status != w.status || code != w.exitCode || run.DurationMS != w.durationMS {
+			t.Errorf("run %d = %+v, want %+v", i, run, w)
+		}
+	}
+	if runs[0].Error != "1 of 2 checks failed" || runs[0].SizeBytes == 0 {
+		t.Errorf("failed run = %+v", runs[0])
+	}
+
+	if runs, err := ListRunLogs(filepath.Join(dir, "missing")); err != nil || len(runs) != 0 {
+		t.Errorf("ListRunLogs(missing) = %v, %v", runs, err)
+	}
+}
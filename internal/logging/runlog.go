@@ -0,0 +1,167 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// DefaultRunLogRetention is how long run logs are kept by default.
+const DefaultRunLogRetention = 14 * 24 * time.Hour
+
+// runLogPattern matches run log file names: DATE-RUNID.log.
+var runLogPattern = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2})-([0-9A-Za-z]+)\.log$`)
+
+// RunLog is the log file of one ado invocation. It receives every record,
+// debug level included, as JSON lines tagged with the run ID, whatever
+// level the console logs at.
+type RunLog struct {
+	ID   string
+	Path string
+
+	file    *os.File
+	handler slog.Handler
+}
+
+// RunLogName returns the file name of the log of run id, started at t.
+func RunLogName(t time.Time, id string) string {
+	return t.UTC().Format(time.DateOnly) + "-" + id + ".log"
+}
+
+// ParseRunLogName returns the date and run ID in the name of a run log.
+// ok is false for other files, which share the log directory.
+func ParseRunLogName(name string) (date time.Time, id string, ok bool) {
+	m := runLogPattern.FindStringSubmatch(name)
+	if m == nil {
+		return time.Time{}, "", false
+	}
+	date, err := time.Parse(time.DateOnly, m[1])
+	if err != nil {
+		return time.Time{}, "", false
+	}
+	return date, m[2], true
+}
+
+// CreateRunLog creates the log of run id, started at t, in dir, readable
+// by the user only.
+func CreateRunLog(dir, id string, t time.Time) (*RunLog, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+	path := filepath.Join(dir, RunLogName(t, id))
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("create run log: %w", err)
+	}
+	handler := slog.NewJSONHandler(file, &slog.HandlerOptions{Level: slog.LevelDebug}).
+		WithAttrs([]slog.Attr{slog.String("run_id", id)})
+	return &RunLog{ID: id, Path: path, file: file, handler: handler}, nil
+}
+
+// Handler returns the handler writing to the file.
+func (l *RunLog) Handler() slog.Handler {
+	return l.handler
+}
+
+// Log writes a record to the file only, such as how the run started and
+// ended.
+func (l *RunLog) Log(ctx context.Context, level slog.Level, msg string, args ...any) {
+	slog.New(l.handler).Log(ctx, level, msg, args...)
+}
+
+// Close closes the file.
+func (l *RunLog) Close() error {
+	return l.file.Close()
+}
+
+// ExpiredRunLogs returns the paths of the run logs in dir last written
+// before cutoff, oldest name first. Other files in dir are left out; a
+// missing dir has none.
+func ExpiredRunLogs(dir string, cutoff time.Time) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for _, entry := range entries {
+		if _, _, ok := ParseRunLogName(entry.Name()); !ok || !entry.Type().IsRegular() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	return paths, nil
+}
+
+// PruneRunLogs removes the run logs in dir last written before cutoff and
+// returns how many it removed. Other files in dir are left alone.
+func PruneRunLogs(dir string, cutoff time.Time) (int, error) {
+	paths, err := ExpiredRunLogs(dir, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	removed := 0
+	var errs []error
+	for _, path := range paths {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+			continue
+		}
+		removed++
+	}
+	return removed, errors.Join(errs...)
+}
+
+// Tee returns a handler that sends each record to every one of handlers
+// enabled for its level.
+func Tee(handlers ...slog.Handler) slog.Handler {
+	return teeHandler(handlers)
+}
+
+type teeHandler []slog.Handler
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range t {
+		if h.Enabled(ctx, r.Level) {
+			errs = append(errs, h.Handle(ctx, r.Clone()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	out := make(teeHandler, len(t))
+	for i, h := range t {
+		out[i] = h.WithAttrs(attrs)
+	}
+	return out
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	out := make(teeHandler, len(t))
+	for i, h := range t {
+		out[i] = h.WithGroup(name)
+	}
+	return out
+}
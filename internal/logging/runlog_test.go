@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunLogName(t *testing.T) {
+	started := time.Date(2026, 10, 16, 23, 30, 0, 0, time.FixedZone("", -5*3600))
+	name := RunLogName(started, "01JA2B3C")
+	if name != "2026-10-17-01JA2B3C.log" {
+		t.Errorf("RunLogName() = %q", name)
+	}
+	date, id, ok := ParseRunLogName(name)
+	if !ok || id != "01JA2B3C" || !date.Equal(time.Date(2026, 10, 17, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("ParseRunLogName(%q) = %v, %q, %v", name, date, id, ok)
+	}
+	for _, other := range []string{"schedule.jsonl", "2026-10-17.log", "2026-13-01-x.log", "2026-10-17-a.b.log"} {
+		if _, _, ok := ParseRunLogName(other); ok {
+			t.Errorf("ParseRunLogName(%q) ok", other)
+		}
+	}
+}
+
+func TestRunLog(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "logs")
+	runLog, err := CreateRunLog(dir, "01JA2B3C", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var console bytes.Buffer
+	log := &logger{slog: slog.New(Tee(createHandler("text", &console, slog.LevelWarn), runLog.Handler()))}
+
+	log.Debug("probing", "attempt", 1)
+	log.Warn("slow", "ms", 900)
+	runLog.Log(context.Background(), slog.LevelInfo, "Run started")
+	if err := runLog.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Contains(console.String(), "probing") || !strings.Contains(console.String(), "slow") {
+		t.Errorf("console = %q", console.String())
+	}
+	data, err := os.ReadFile(runLog.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("run log = %q", data)
+	}
+	for i, msg := range []string{"probing", "slow", "Run started"} {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(lines[i]), &record); err != nil {
+			t.Fatal(err)
+		}
+		if record["msg"] != msg || record["run_id"] != "01JA2B3C" {
+			t.Errorf("record %d = %v", i, record)
+		}
+	}
+	if info, err := os.Stat(runLog.Path); err == nil && runtime.GOOS != "windows" && info.Mode().Perm() != 0o600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+}
+
+func TestPruneRunLogs(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	files := map[string]time.Duration{
+		"2026-10-01-OLD.log":    20 * 24 * time.Hour,
+		"2026-10-15-RECENT.log": 24 * time.Hour,
+		"schedule.jsonl":        20 * 24 * time.Hour,
+	}
+	for name, age := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, now.Add(-age), now.Add(-age)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	removed, err := PruneRunLogs(dir, now.Add(-DefaultRunLogRetention))
+	if err != nil || removed != 1 {
+		t.Fatalf("PruneRunLogs() = %d, %v", removed, err)
+	}
+	for name := range files {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if exists := err == nil; exists == (name == "2026-10-01-OLD.log") {
+			t.Errorf("%s exists = %v", name, exists)
+		}
+	}
+	if removed, err := PruneRunLogs(filepath.Join(dir, "missing"), now); removed != 0 || err != nil {
+		t.Errorf("PruneRunLogs(missing) = %d, %v", removed, err)
+	}
+}
@@ -0,0 +1,261 @@
+// Package mcp implements a Model Context Protocol server over stdio:
+// newline-delimited JSON-RPC 2.0 messages that let an AI assistant list
+// and call tools.
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/anowarislam/ado/internal/logging"
+)
+
+// ProtocolVersion is the newest MCP revision the server speaks.
+const ProtocolVersion = "2025-06-18"
+
+// supportedVersions lists the revisions accepted from clients, newest first.
+var supportedVersions = []string{ProtocolVersion, "2025-03-26", "2024-11-05"}
+
+// MaxMessageBytes bounds a single incoming message.
+const MaxMessageBytes = 4 << 20
+
+// JSON-RPC error codes.
+const (
+	codeParseError     = -32700
+	codeInvalidRequest = -32600
+	codeMethodNotFound = -32601
+	codeInvalidParams  = -32602
+)
+
+// Tool is a capability exposed to clients.
+type Tool struct {
+	Name        string
+	Description string
+	// InputSchema is the JSON Schema of the tool's arguments object.
+	InputSchema map[string]any
+	// Call runs the tool. A returned error is reported to the client as a
+	// failed tool result, not a protocol error, so the model can see it.
+	Call func(ctx context.Context, args json.RawMessage) (*Result, error)
+}
+
+// Result is the outcome of a tool call.
+type Result struct {
+	// Text is shown to the model; tools return JSON documents.
+	Text string
+	// IsError marks a call that ran but failed, such as a task exiting
+	// non-zero.
+	IsError bool
+}
+
+// Server answers MCP requests for a fixed set of tools.
+type Server struct {
+	Name    string
+	Version string
+	Tools   []Tool
+	Logger  logging.Logger
+}
+
+type request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  any             `json:"result,omitempty"`
+	Error   *rpcError       `json:"error,omitempty"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string { return e.Message }
+
+// Serve reads requests from r and writes responses to w until r is
+// exhausted or ctx is cancelled. Requests are handled one at a time.
+func (s *Server) Serve(ctx context.Context, r io.Reader, w io.Writer) error {
+	lines := make(chan []byte)
+	readErr := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Buffer(make([]byte, 64*1024), MaxMessageBytes)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		readErr <- scanner.Err()
+	}()
+
+	enc := json.NewEncoder(w)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-readErr:
+			if err != nil {
+				return fmt.Errorf("read request: %w", err)
+			}
+			return nil
+		case line := <-lines:
+			if len(line) == 0 {
+				continue
+			}
+			resp := s.handle(ctx, line)
+			if resp == nil {
+				continue
+			}
+			if err := enc.Encode(resp); err != nil {
+				return fmt.Errorf("write response: %w", err)
+			}
+		}
+	}
+}
+
+// handle answers one message; it returns nil for notifications.
+func (s *Server) handle(ctx context.Context, line []byte) *response {
+	var req request
+	if err := json.Unmarshal(line, &req); err != nil {
+		return &response{JSONRPC: "2.0", ID: json.RawMessage("null"), Error: &rpcError{codeParseError, "parse error: " + err.Error()}}
+	}
+	if req.ID == nil {
+		s.logger().Debug("mcp notification", "method", req.Method)
+		return nil
+	}
+	resp := &response{JSONRPC: "2.0", ID: req.ID}
+	if req.JSONRPC != "2.0" || req.Method == "" {
+		resp.Error = &rpcError{codeInvalidRequest, "invalid request: want jsonrpc 2.0 with a method"}
+		return resp
+	}
+
+	result, err := s.dispatch(ctx, req)
+	var rerr *rpcError
+	switch {
+	case errors.As(err, &rerr):
+		resp.Error = rerr
+	case err != nil:
+		resp.Error = &rpcError{codeInvalidParams, err.Error()}
+	default:
+		resp.Result = result
+	}
+	s.logger().Debug("mcp request", "method", req.Method, "error", err)
+	return resp
+}
+
+func (s *Server) dispatch(ctx context.Context, req request) (any, error) {
+	switch req.Method {
+	case "initialize":
+		return s.initialize(req.Params)
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return s.listTools(), nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	default:
+		return nil, &rpcError{codeMethodNotFound, "method not found: " + req.Method}
+	}
+}
+
+func (s *Server) initialize(params json.RawMessage) (any, error) {
+	var p struct {
+		ProtocolVersion string `json:"protocolVersion"`
+	}
+	if len(params) > 0 {
+		if err := json.Unmarshal(params, &p); err != nil {
+			return nil, fmt.Errorf("invalid initialize params: %w", err)
+		}
+	}
+	// Echo a version we support, otherwise offer our newest and let the
+	// client decide whether to continue.
+	version := ProtocolVersion
+	if slices.Contains(supportedVersions, p.ProtocolVersion) {
+		version = p.ProtocolVersion
+	}
+	return map[string]any{
+		"protocolVersion": version,
+		"capabilities":    map[string]any{"tools": map[string]any{}},
+		"serverInfo":      map[string]string{"name": s.Name, "version": s.Version},
+	}, nil
+}
+
+type toolInfo struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description"`
+	InputSchema map[string]any `json:"inputSchema"`
+}
+
+func (s *Server) listTools() any {
+	tools := make([]toolInfo, 0, len(s.Tools))
+	for _, t := range s.Tools {
+		schema := t.InputSchema
+		if schema == nil {
+			schema = map[string]any{"type": "object"}
+		}
+		tools = append(tools, toolInfo{Name: t.Name, Description: t.Description, InputSchema: schema})
+	}
+	return map[string]any{"tools": tools}
+}
+
+type content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type callResult struct {
+	Content []content `json:"content"`
+	IsError bool      `json:"isError"`
+}
+
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (any, error) {
+	var p struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	}
+	if err := json.Unmarshal(params, &p); err != nil {
+		return nil, fmt.Errorf("invalid tools/call params: %w", err)
+	}
+	idx := slices.IndexFunc(s.Tools, func(t Tool) bool { return t.Name == p.Name })
+	if idx < 0 {
+		return nil, fmt.Errorf("unknown tool: %q", p.Name)
+	}
+	if len(p.Arguments) == 0 || string(p.Arguments) == "null" {
+		p.Arguments = json.RawMessage("{}")
+	}
+
+	s.logger().Info("Calling tool", "tool", p.Name)
+	res, err := s.Tools[idx].Call(ctx, p.Arguments)
+	if err != nil {
+		res = &Result{Text: err.Error(), IsError: true}
+	}
+	return callResult{Content: []content{{Type: "text", Text: res.Text}}, IsError: res.IsError}, nil
+}
+
+func (s *Server) logger() logging.Logger {
+	if s.Logger == nil {
+		return logging.NopLogger()
+	}
+	return s.Logger
+}
+
+// JSONResult returns v as an indented JSON result.
+func JSONResult(v any) (*Result, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("encode result: %w", err)
+	}
+	return &Result{Text: string(data)}, nil
+}
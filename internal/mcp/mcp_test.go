@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testServer() *Server {
+	return &Server{
+		Name:    "ado",
+		Version: "1.2.3",
+		Tools: []Tool{
+			{
+				Name:        "echo",
+				Description: "Echo the message",
+				InputSchema: map[string]any{"type": "object"},
+				Call: func(_ context.Context, args json.RawMessage) (*Result, error) {
+					var in struct{ Message string }
+					if err := json.Unmarshal(args, &in); err != nil {
+						return nil, err
+					}
+					return &Result{Text: in.Message}, nil
+				},
+			},
+			{
+				Name: "fail",
+				Call: func(context.Context, json.RawMessage) (*Result, error) {
+					return nil, errors.New("boom")
+				},
+			},
+		},
+	}
+}
+
+// roundTrip sends each line to a fresh server and decodes the responses.
+func roundTrip(t *testing.T, lines ...string) []map[string]any {
+	t.Helper()
+	var out strings.Builder
+	in := strings.NewReader(strings.Join(lines, "\n") + "\n")
+	if err := testServer().Serve(context.Background(), in, &out); err != nil {
+		t.Fatalf("Serve() error = %v", err)
+	}
+
+	var responses []map[string]any
+	scanner := bufio.NewScanner(strings.NewReader(out.String()))
+	for scanner.Scan() {
+		var resp map[string]any
+		if err := json.Unmarshal(scanner.Bytes(), &resp); err != nil {
+			t.Fatalf("decode %q: %v", scanner.Text(), err)
+		}
+		responses = append(responses, resp)
+	}
+	return responses
+}
+
+func TestServe_Initialize(t *testing.T) {
+	tests := []struct {
+		name        string
+		version     string
+		wantVersion string
+	}{
+		{"supported version echoed", "2025-03-26", "2025-03-26"},
+		{"unknown version", "1999-01-01", ProtocolVersion},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := roundTrip(t,
+				`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"`+tt.version+`","capabilities":{},"clientInfo":{"name":"test"}}}`,
+				`{"jsonrpc":"2.0","method":"notifications/initialized"}`,
+			)
+			if len(responses) != 1 {
+				t.Fatalf("got %d responses, want 1 (notifications get none)", len(responses))
+			}
+			result := responses[0]["result"].(map[string]any)
+			if got := result["protocolVersion"]; got != tt.wantVersion {
+				t.Errorf("protocolVersion = %v, want %s", got, tt.wantVersion)
+			}
+			info := result["serverInfo"].(map[string]any)
+			if info["name"] != "ado" || info["version"] != "1.2.3" {
+				t.Errorf("serverInfo = %v", info)
+			}
+			if _, ok := result["capabilities"].(map[string]any)["tools"]; !ok {
+				t.Errorf("capabilities = %v, want tools", result["capabilities"])
+			}
+		})
+	}
+}
+
+func TestServe_ToolsList(t *testing.T) {
+	responses := roundTrip(t, `{"jsonrpc":"2.0","id":"a","method":"tools/list"}`)
+	if responses[0]["id"] != "a" {
+		t.Errorf("id = %v, want a", responses[0]["id"])
+	}
+	tools := responses[0]["result"].(map[string]any)["tools"].([]any)
+	if len(tools) != 2 {
+		t.Fatalf("got %d tools, want 2", len(tools))
+	}
+	fail := tools[1].(map[string]any)
+	if fail["name"] != "fail" || fail["inputSchema"].(map[string]any)["type"] != "object" {
+		t.Errorf("tool without schema = %v, want default object schema", fail)
+	}
+}
+
+func TestServe_ToolsCall(t *testing.T) {
+	tests := []struct {
+		name        string
+		params      string
+		wantText    string
+		wantIsError bool
+	}{
+		{"success", `{"name":"echo","arguments":{"Message":"hi"}}`, "hi", false},
+		{"tool error", `{"name":"fail"}`, "boom", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := roundTrip(t, `{"jsonrpc":"2.0","id":7,"method":"tools/call","params":`+tt.params+`}`)
+			result := responses[0]["result"].(map[string]any)
+			content := result["content"].([]any)[0].(map[string]any)
+			if content["type"] != "text" || content["text"] != tt.wantText {
+				t.Errorf("content = %v, want text %q", content, tt.wantText)
+			}
+			if result["isError"] != tt.wantIsError {
+				t.Errorf("isError = %v, want %v", result["isError"], tt.wantIsError)
+			}
+		})
+	}
+}
+
+func TestServe_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		wantCode float64
+	}{
+		{"parse error", `{not json`, codeParseError},
+		{"missing jsonrpc", `{"id":1,"method":"ping"}`, codeInvalidRequest},
+		{"unknown method", `{"jsonrpc":"2.0","id":1,"method":"resources/list"}`, codeMethodNotFound},
+		{"unknown tool", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"nope"}}`, codeInvalidParams},
+		{"bad params", `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":[]}`, codeInvalidParams},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := roundTrip(t, tt.line)
+			rerr, ok := responses[0]["error"].(map[string]any)
+			if !ok {
+				t.Fatalf("response = %v, want error", responses[0])
+			}
+			if rerr["code"] != tt.wantCode {
+				t.Errorf("code = %v, want %v", rerr["code"], tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestServe_Ping(t *testing.T) {
+	responses := roundTrip(t, "", `{"jsonrpc":"2.0","id":1,"method":"ping"}`)
+	if len(responses) != 1 || responses[0]["error"] != nil {
+		t.Errorf("responses = %v, want one empty result", responses)
+	}
+}
@@ -0,0 +1,390 @@
+package meta
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Assertion is a threshold check on one field of the system report, such
+// as memory.used_percent < 90 or storage["/"].free_mb > 10240.
+//
+// Fields are named by their JSON paths. List items are selected with
+// [N] by position, [KEY] or ["KEY"] by mountpoint (storage) or name, or
+// [*] for every item, each of which must pass.
+type Assertion struct {
+	Source string
+	Field  string
+	Op     string
+	// Value is a float64, bool, or string.
+	Value any
+
+	path []pathSegment
+}
+
+// Assertion operators, longest first so <= is not read as <.
+var assertOps = []string{"<=", ">=", "==", "!=", "<", ">"}
+
+// listKeys are the fields a [KEY] selector matches list items by.
+var listKeys = []string{"mountpoint", "name"}
+
+// pathSegment is a field name, or a list selector when list is set.
+type pathSegment struct {
+	name string
+	list bool
+	key  string // "*" selects every item
+}
+
+// ParseAssertion parses FIELD OP VALUE, where OP is one of <, <=, >, >=,
+// ==, != and VALUE is a number, true, false, or a string, quoted or not.
+func ParseAssertion(src string) (Assertion, error) {
+	a := Assertion{Source: src}
+	at, op := findAssertOp(src)
+	if at < 0 {
+		return a, fmt.Errorf("assertion %q: expected FIELD OP VALUE with OP one of %s", src, strings.Join(assertOps, " "))
+	}
+	a.Field, a.Op = strings.TrimSpace(src[:at]), op
+	path, err := parsePath(a.Field)
+	if err != nil {
+		return a, fmt.Errorf("assertion %q: %w", src, err)
+	}
+	a.path = path
+
+	raw := strings.TrimSpace(src[at+len(op):])
+	switch {
+	case raw == "":
+		return a, fmt.Errorf("assertion %q: missing value after %s", src, op)
+	case len(raw) >= 2 && (raw[0] == '"' || raw[0] == '\'') && raw[len(raw)-1] == raw[0]:
+		a.Value = raw[1 : len(raw)-1]
+	case raw == "true" || raw == "false":
+		a.Value = raw == "true"
+	default:
+		if n, err := strconv.ParseFloat(raw, 64); err == nil {
+			a.Value = n
+		} else {
+			a.Value = raw
+		}
+	}
+	if _, ok := a.Value.(float64); !ok && op != "==" && op != "!=" {
+		return a, fmt.Errorf("assertion %q: %s needs a number", src, op)
+	}
+	return a, nil
+}
+
+// findAssertOp returns the offset and text of the first operator outside
+// brackets and quotes, or -1.
+func findAssertOp(src string) (int, string) {
+	depth, quote := 0, byte(0)
+	for i := 0; i < len(src); i++ {
+		c := src[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[':
+			depth++
+		case c == ']' && depth > 0:
+			depth--
+		case depth == 0:
+			for _, op := range assertOps {
+				if strings.HasPrefix(src[i:], op) {
+					return i, op
+				}
+			}
+		}
+	}
+	return -1, ""
+}
+
+// parsePath splits a field such as storage["/"].free_mb into segments.
+func parsePath(field string) ([]pathSegment, error) {
+	var path []pathSegment
+	rest := field
+	for rest != "" {
+		switch {
+		case rest[0] == '[':
+			end := closingBracket(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated [ in %s", field)
+			}
+			key := strings.TrimSpace(rest[1:end])
+			if len(key) >= 2 && (key[0] == '"' || key[0] == '\'') && key[len(key)-1] == key[0] {
+				key = key[1 : len(key)-1]
+			}
+			if key == "" {
+				return nil, fmt.Errorf("empty [] in %s", field)
+			}
+			if len(path) == 0 {
+				return nil, fmt.Errorf("%s starts with a list selector", field)
+			}
+			path = append(path, pathSegment{list: true, key: key})
+			rest = rest[end+1:]
+		case rest[0] == '.' && len(path) > 0:
+			rest = rest[1:]
+			fallthrough
+		default:
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			name := strings.TrimSpace(rest[:end])
+			if name == "" || strings.ContainsFunc(name, func(r rune) bool { return r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) }) {
+				return nil, fmt.Errorf("invalid field %s", field)
+			}
+			path = append(path, pathSegment{name: name})
+			rest = rest[end:]
+		}
+	}
+	if len(path) == 0 {
+		return nil, errors.New("missing field")
+	}
+	return path, nil
+}
+
+// closingBracket returns the offset of the ] closing the [ that s starts
+// with, skipping quoted keys, which may contain ], or -1.
+func closingBracket(s string) int {
+	quote := byte(0)
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+// Sections lists the report sections a refers to, so only those need
+// collecting. Host identity fields belong to no section.
+func (a Assertion) Sections() []string {
+	if len(a.path) > 0 && slices.Contains(SystemSections, a.path[0].name) {
+		return []string{a.path[0].name}
+	}
+	return nil
+}
+
+// AssertionResult is the outcome of an assertion on one field; an
+// assertion selecting every list item has one result per item.
+type AssertionResult struct {
+	Assertion string `json:"assertion" yaml:"assertion"`
+	Field     string `json:"field" yaml:"field"`
+	Actual    any    `json:"actual" yaml:"actual"`
+	Passed    bool   `json:"passed" yaml:"passed"`
+	// Error says why the field could not be compared, such as a section
+	// that was not collected.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// AssertionReport is the outcome of a set of assertions.
+type AssertionReport struct {
+	Passed  bool              `json:"passed" yaml:"passed"`
+	Results []AssertionResult `json:"results" yaml:"results"`
+}
+
+// Failed counts the failed results.
+func (r AssertionReport) Failed() int {
+	failed := 0
+	for _, res := range r.Results {
+		if !res.Passed {
+			failed++
+		}
+	}
+	return failed
+}
+
+// CheckAssertions evaluates assertions against info. A field that is
+// missing, in a section that was not collected, or of a volume that did
+// not respond fails its assertion.
+func CheckAssertions(info SystemInfo, assertions []Assertion) AssertionReport {
+	data, _ := json.Marshal(info)
+	var doc any
+	_ = json.Unmarshal(data, &doc)
+
+	report := AssertionReport{Passed: true, Results: []AssertionResult{}}
+	for _, a := range assertions {
+		results := a.check(info, doc)
+		for _, res := range results {
+			report.Passed = report.Passed && res.Passed
+		}
+		report.Results = append(report.Results, results...)
+	}
+	return report
+}
+
+func (a Assertion) check(info SystemInfo, doc any) []AssertionResult {
+	fail := func(field, format string, args ...any) []AssertionResult {
+		return []AssertionResult{{Assertion: a.Source, Field: field, Error: fmt.Sprintf(format, args...)}}
+	}
+	for _, section := range a.Sections() {
+		if !info.Collected(section) {
+			return fail(a.Field, "section %s was not collected", section)
+		}
+	}
+
+	type match struct {
+		field string
+		value any
+		// unresponsive names the list item the value belongs to when
+		// it did not answer in time, such as a hung network mount.
+		unresponsive string
+	}
+	matches := []match{{value: doc}}
+	for _, seg := range a.path {
+		var next []match
+		for _, m := range matches {
+			if !seg.list {
+				obj, ok := m.value.(map[string]any)
+				if m.value == nil {
+					return fail(m.field, "%s was not detected", m.field)
+				}
+				if !ok {
+					return fail(m.field, "%s is %s, not an object", m.field, describeJSON(m.value))
+				}
+				field := seg.name
+				if m.field != "" {
+					field = m.field + "." + seg.name
+				}
+				v, ok := obj[seg.name]
+				if !ok {
+					return fail(field, "no field %s", field)
+				}
+				next = append(next, match{field, v, m.unresponsive})
+				continue
+			}
+			items, ok := m.value.([]any)
+			if !ok {
+				return fail(m.field, "%s is %s, not a list", m.field, describeJSON(m.value))
+			}
+			for i, item := range items {
+				key := itemKey(item, i)
+				if seg.key == "*" || seg.key == key {
+					field, unresponsive := m.field+"["+key+"]", m.unresponsive
+					if obj, ok := item.(map[string]any); ok && obj["unresponsive"] == true {
+						unresponsive = field
+					}
+					next = append(next, match{field, item, unresponsive})
+				}
+			}
+			if len(next) == 0 {
+				return fail(m.field+"["+seg.key+"]", "no item of %s matches [%s]", m.field, seg.key)
+			}
+		}
+		matches = next
+	}
+
+	results := make([]AssertionResult, len(matches))
+	for i, m := range matches {
+		res := AssertionResult{Assertion: a.Source, Field: m.field, Actual: m.value}
+		switch {
+		case m.unresponsive != "":
+			res.Error = m.unresponsive + " did not respond"
+		case m.value == nil:
+			res.Error = m.field + " was not detected"
+		default:
+			res.Passed, res.Error = compare(m.value, a.Op, a.Value)
+		}
+		results[i] = res
+	}
+	return results
+}
+
+// itemKey names a list item by its first key field, or by its position.
+func itemKey(item any, i int) string {
+	if obj, ok := item.(map[string]any); ok {
+		for _, k := range listKeys {
+			if s, ok := obj[k].(string); ok && s != "" {
+				return s
+			}
+		}
+	}
+	return strconv.Itoa(i)
+}
+
+// compare applies op to actual and want, returning an error message when
+// their types cannot be compared with op.
+func compare(actual any, op string, want any) (bool, string) {
+	if n, ok := actual.(float64); ok {
+		w, ok := want.(float64)
+		if !ok {
+			return false, fmt.Sprintf("cannot compare number %s with %v", FormatAssertValue(n), want)
+		}
+		switch op {
+		case "<":
+			return n < w, ""
+		case "<=":
+			return n <= w, ""
+		case ">":
+			return n > w, ""
+		case ">=":
+			return n >= w, ""
+		case "==":
+			return n == w, ""
+		}
+		return n != w, ""
+	}
+	switch actual.(type) {
+	case map[string]any, []any:
+		return false, fmt.Sprintf("cannot compare %s; select a field inside it", describeJSON(actual))
+	}
+	if op != "==" && op != "!=" {
+		return false, fmt.Sprintf("cannot compare %s with %s", describeJSON(actual), op)
+	}
+	var equal bool
+	switch actual := actual.(type) {
+	case bool:
+		w, ok := want.(bool)
+		if !ok {
+			return false, fmt.Sprintf("cannot compare boolean with %s", FormatAssertValue(want))
+		}
+		equal = actual == w
+	case string:
+		// An unquoted number matches a string holding it.
+		equal = actual == FormatAssertValue(want)
+	}
+	return equal == (op == "=="), ""
+}
+
+// describeJSON names the kind of a decoded JSON value.
+func describeJSON(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "an object"
+	case []any:
+		return "a list"
+	case float64:
+		return "a number"
+	case bool:
+		return "a boolean"
+	}
+	return "a string"
+}
+
+// FormatAssertValue prints a field or assertion value as written in an
+// assertion: numbers without trailing zeros, null for nil.
+func FormatAssertValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case string, bool:
+		return fmt.Sprint(v)
+	}
+	data, _ := json.Marshal(v)
+	return string(data)
+}
@@ -0,0 +1,126 @@
+package meta
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestParseAssertion(t *testing.T) {
+	tests := []struct {
+		src     string
+		field   string
+		op      string
+		value   any
+		wantErr string
+	}{
+		{src: "memory.used_percent < 90", field: "memory.used_percent", op: "<", value: 90.0},
+		{src: `storage["/"].free_mb>=10240`, field: `storage["/"].free_mb`, op: ">=", value: 10240.0},
+		{src: "storage[/mnt/a<b].used_percent <= 80.5", field: "storage[/mnt/a<b].used_percent", op: "<=", value: 80.5},
+		{src: "os == linux", field: "os", op: "==", value: "linux"},
+		{src: `cpu.vendor != "Apple Inc"`, field: "cpu.vendor", op: "!=", value: "Apple Inc"},
+		{src: "npu.detected == false", field: "npu.detected", op: "==", value: false},
+		{src: "memory.used_percent", wantErr: "expected FIELD OP VALUE"},
+		{src: "memory.used_percent <", wantErr: "missing value"},
+		{src: "os > linux", wantErr: "> needs a number"},
+		{src: "storage[.free_mb > 1", wantErr: "expected FIELD OP VALUE"},
+		{src: "storage[/]].free_mb > 1", wantErr: "invalid field"},
+		{src: "storage[].free_mb > 1", wantErr: "empty []"},
+		{src: "< 5", wantErr: "missing field"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			a, err := ParseAssertion(tt.src)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if a.Field != tt.field || a.Op != tt.op || a.Value != tt.value {
+				t.Errorf("ParseAssertion() = %q %q %#v, want %q %q %#v", a.Field, a.Op, a.Value, tt.field, tt.op, tt.value)
+			}
+		})
+	}
+}
+
+func TestCheckAssertions(t *testing.T) {
+	info := SystemInfo{
+		OS:     "linux",
+		CPU:    CPUInfo{Cores: 8, Vendor: "GenuineIntel"},
+		Memory: MemoryInfo{UsedPercent: 42.5, TotalMB: 16384},
+		Storage: []StorageInfo{
+			{Mountpoint: "/", FreeMB: 20480, UsedPercent: 60},
+			{Mountpoint: "/data", FreeMB: 512, UsedPercent: 95},
+			{Mountpoint: "/mnt/nfs", Unresponsive: true},
+		},
+		GPU:      []GPUInfo{{Vendor: "NVIDIA", Model: "A100"}},
+		Sections: []string{SectionCPU, SectionMemory, SectionStorage, SectionGPU, SectionCloud},
+	}
+
+	tests := []struct {
+		src  string
+		want string // field=actual:passed or field:error per result
+	}{
+		{"memory.used_percent < 90", "memory.used_percent=42.5:true"},
+		{"memory.used_percent >= 42.5", "memory.used_percent=42.5:true"},
+		{`storage["/"].free_mb > 10240`, "storage[/].free_mb=20480:true"},
+		{"storage[/data].free_mb > 10240", "storage[/data].free_mb=512:false"},
+		{"storage[*].used_percent < 90", "storage[/].used_percent=60:true storage[/data].used_percent=95:false storage[/mnt/nfs].used_percent:storage[/mnt/nfs] did not respond"},
+		{"gpu[0].vendor == NVIDIA", "gpu[0].vendor=NVIDIA:true"},
+		{"cpu.cores == 8", "cpu.cores=8:true"},
+		{"os != linux", "os=linux:false"},
+		{"storage[/home].free_mb > 1", "storage[/home]:no item of storage matches [/home]"},
+		{"memory.free_mb > 1", "memory.free_mb:no field memory.free_mb"},
+		{"cloud.provider == aws", "cloud:cloud was not detected"},
+		{"cgroup.memory_limit_mb > 1", "cgroup.memory_limit_mb:section cgroup was not collected"},
+		{"memory > 1", "memory:cannot compare an object; select a field inside it"},
+		{"os == 5", "os=linux:false"},
+		{"cpu.cores == eight", "cpu.cores:cannot compare number 8 with eight"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			a, err := ParseAssertion(tt.src)
+			if err != nil {
+				t.Fatal(err)
+			}
+			report := CheckAssertions(info, []Assertion{a})
+			var got []string
+			passed := true
+			for _, res := range report.Results {
+				passed = passed && res.Passed
+				if res.Error != "" {
+					got = append(got, res.Field+":"+res.Error)
+				} else {
+					got = append(got, fmt.Sprintf("%s=%s:%v", res.Field, FormatAssertValue(res.Actual), res.Passed))
+				}
+			}
+			if strings.Join(got, " ") != tt.want {
+				t.Errorf("results = %s, want %s", strings.Join(got, " "), tt.want)
+			}
+			if report.Passed != passed || report.Failed() == 0 != passed {
+				t.Errorf("report passed = %v, failed = %d", report.Passed, report.Failed())
+			}
+		})
+	}
+}
+
+func TestAssertion_Sections(t *testing.T) {
+	for src, want := range map[string]string{
+		"memory.used_percent < 90":  "memory",
+		`storage["/"].free_mb > 1`:  "storage",
+		"os == linux":               "",
+		"security.selinux == false": "security",
+	} {
+		a, err := ParseAssertion(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Join(a.Sections(), ","); got != want {
+			t.Errorf("Sections(%q) = %q, want %q", src, got, want)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package meta
+
+import (
+	"bufio"
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// CgroupInfo represents the cgroup resource limits applied to the current process.
+// Zero limits mean "unlimited" (no constraint beyond the host).
+type CgroupInfo struct {
+	Version       int     `json:"version" yaml:"version"` // 1 or 2
+	Path          string  `json:"path" yaml:"path"`
+	MemoryLimitMB uint64  `json:"memory_limit_mb" yaml:"memory_limit_mb"`
+	MemoryUsageMB uint64  `json:"memory_usage_mb" yaml:"memory_usage_mb"`
+	CPUQuota      float64 `json:"cpu_quota" yaml:"cpu_quota"` // in CPUs, e.g. 1.5
+}
+
+// Limit sources reported in MemoryInfo.LimitSource and CPUInfo.LimitSource.
+const (
+	LimitSourceHost   = "host"
+	LimitSourceCgroup = "cgroup"
+)
+
+// cgroupV1Unlimited is the threshold above which a v1 memory limit is
+// treated as unlimited (the kernel reports PAGE_COUNTER_MAX rounded to pages).
+const cgroupV1Unlimited = 1 << 62
+
+// cgroupReader locates cgroup files; fields are overridable for tests.
+type cgroupReader struct {
+	procSelfCgroup string
+	mountRoot      string
+}
+
+func newCgroupReader() cgroupReader {
+	return cgroupReader{
+		procSelfCgroup: "/proc/self/cgroup",
+		mountRoot:      "/sys/fs/cgroup",
+	}
+}
+
+// detectCgroup reads cgroup v1 or v2 limits for the current process.
+// Returns nil when cgroups are unavailable (non-Linux hosts, restricted /proc).
+func detectCgroup(ctx context.Context) *CgroupInfo {
+	info, err := newCgroupReader().read()
+	if err != nil {
+		slog.DebugContext(ctx, "Cgroup detection failed", "error", err)
+		return nil
+	}
+	return info
+}
+
+func (r cgroupReader) read() (*CgroupInfo, error) {
+	f, err := os.Open(r.procSelfCgroup)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// Lines look like "0::/user.slice" (v2) or "4:memory:/docker/abc" (v1).
+	v1Paths := map[string]string{}
+	v2Path := ""
+	hasV2 := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if parts[0] == "0" && parts[1] == "" {
+			v2Path = parts[2]
+			hasV2 = true
+			continue
+		}
+		for _, controller := range strings.Split(parts[1], ",") {
+			v1Paths[controller] = parts[2]
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, ok := v1Paths["memory"]; ok {
+		return r.readV1(v1Paths), nil
+	}
+	if hasV2 {
+		return r.readV2(v2Path), nil
+	}
+	return nil, os.ErrNotExist
+}
+
+func (r cgroupReader) readV2(path string) *CgroupInfo {
+	dir := r.resolveDir(r.mountRoot, path)
+	info := &CgroupInfo{Version: 2, Path: path}
+
+	if limit, ok := readCgroupUint(filepath.Join(dir, "memory.max")); ok {
+		info.MemoryLimitMB = limit / 1024 / 1024
+	}
+	if usage, ok := readCgroupUint(filepath.Join(dir, "memory.current")); ok {
+		info.MemoryUsageMB = usage / 1024 / 1024
+	}
+
+	// cpu.max holds "<quota> <period>" where quota may be "max".
+	if data, err := os.ReadFile(filepath.Join(dir, "cpu.max")); err == nil {
+		fields := strings.Fields(string(data))
+		if len(fields) == 2 && fields[0] != "max" {
+			quota, qerr := strconv.ParseFloat(fields[0], 64)
+			period, perr := strconv.ParseFloat(fields[1], 64)
+			if qerr == nil && perr == nil && period > 0 {
+				info.CPUQuota = quota / period
+			}
+		}
+	}
+
+	return info
+}
+
+func (r cgroupReader) readV1(paths map[string]string) *CgroupInfo {
+	info := &CgroupInfo{Version: 1, Path: paths["memory"]}
+
+	memDir := r.resolveDir(filepath.Join(r.mountRoot, "memory"), paths["memory"])
+	if limit, ok := readCgroupUint(filepath.Join(memDir, "memory.limit_in_bytes")); ok && limit < cgroupV1Unlimited {
+		info.MemoryLimitMB = limit / 1024 / 1024
+	}
+	if usage, ok := readCgroupUint(filepath.Join(memDir, "memory.usage_in_bytes")); ok {
+		info.MemoryUsageMB = usage / 1024 / 1024
+	}
+
+	if cpuPath, ok := paths["cpu"]; ok {
+		cpuDir := r.resolveDir(filepath.Join(r.mountRoot, "cpu"), cpuPath)
+		quota, qerr := readCgroupInt(filepath.Join(cpuDir, "cpu.cfs_quota_us"))
+		period, perr := readCgroupInt(filepath.Join(cpuDir, "cpu.cfs_period_us"))
+		if qerr == nil && perr == nil && quota > 0 && period > 0 {
+			info.CPUQuota = float64(quota) / float64(period)
+		}
+	}
+
+	return info
+}
+
+// resolveDir returns the cgroup directory for path under root. Inside a
+// container with a private cgroup namespace the host path is not mounted,
+// so fall back to the mount root which then represents our own cgroup.
+func (r cgroupReader) resolveDir(root, path string) string {
+	dir := filepath.Join(root, path)
+	if _, err := os.Stat(dir); err == nil {
+		return dir
+	}
+	return root
+}
+
+// readCgroupUint reads a numeric cgroup file; "max" and unreadable files report ok=false.
+func readCgroupUint(path string) (uint64, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, false
+	}
+	value, err := strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return value, true
+}
+
+func readCgroupInt(path string) (int64, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+}
+
+// applyCgroupLimits marks which memory and CPU numbers apply to the process.
+// A cgroup limit only takes effect when it is tighter than the host total.
+func applyCgroupLimits(info *SystemInfo) {
+	info.Memory.EffectiveLimitMB = info.Memory.TotalMB
+	info.Memory.LimitSource = LimitSourceHost
+	info.CPU.EffectiveCPUs = float64(info.CPU.Cores)
+	info.CPU.LimitSource = LimitSourceHost
+
+	if info.Cgroup == nil {
+		return
+	}
+
+	if limit := info.Cgroup.MemoryLimitMB; limit > 0 && (info.Memory.TotalMB == 0 || limit < info.Memory.TotalMB) {
+		info.Memory.EffectiveLimitMB = limit
+		info.Memory.LimitSource = LimitSourceCgroup
+	}
+	if quota := info.Cgroup.CPUQuota; quota > 0 && (info.CPU.Cores == 0 || quota < float64(info.CPU.Cores)) {
+		info.CPU.EffectiveCPUs = quota
+		info.CPU.LimitSource = LimitSourceCgroup
+	}
+}
@@ -0,0 +1,137 @@
+package meta
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeCgroupFS creates a fake /proc/self/cgroup and cgroup mount under a temp dir.
+func writeCgroupFS(t *testing.T, procSelf string, files map[string]string) cgroupReader {
+	t.Helper()
+	dir := t.TempDir()
+	r := cgroupReader{
+		procSelfCgroup: filepath.Join(dir, "proc-self-cgroup"),
+		mountRoot:      filepath.Join(dir, "cgroup"),
+	}
+	if err := os.WriteFile(r.procSelfCgroup, []byte(procSelf), 0o644); err != nil {
+		t.Fatalf("write proc cgroup: %v", err)
+	}
+	for name, content := range files {
+		path := filepath.Join(r.mountRoot, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	return r
+}
+
+func TestCgroupReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		procSelf string
+		files    map[string]string
+		want     CgroupInfo
+	}{
+		{
+			name:     "v2 namespaced container",
+			procSelf: "0::/\n",
+			files: map[string]string{
+				"memory.max":     "536870912\n",
+				"memory.current": "104857600\n",
+				"cpu.max":        "150000 100000\n",
+			},
+			want: CgroupInfo{Version: 2, Path: "/", MemoryLimitMB: 512, MemoryUsageMB: 100, CPUQuota: 1.5},
+		},
+		{
+			name:     "v2 host session unlimited",
+			procSelf: "0::/user.slice/session-1.scope\n",
+			files: map[string]string{
+				"user.slice/session-1.scope/memory.max":     "max\n",
+				"user.slice/session-1.scope/memory.current": "2097152\n",
+				"user.slice/session-1.scope/cpu.max":        "max 100000\n",
+			},
+			want: CgroupInfo{Version: 2, Path: "/user.slice/session-1.scope", MemoryUsageMB: 2},
+		},
+		{
+			name:     "v1 docker",
+			procSelf: "12:cpu,cpuacct:/docker/abc\n4:memory:/docker/abc\n1:name=systemd:/docker/abc\n",
+			files: map[string]string{
+				"memory/memory.limit_in_bytes": "1073741824\n",
+				"memory/memory.usage_in_bytes": "52428800\n",
+				"cpu/cpu.cfs_quota_us":         "200000\n",
+				"cpu/cpu.cfs_period_us":        "100000\n",
+			},
+			want: CgroupInfo{Version: 1, Path: "/docker/abc", MemoryLimitMB: 1024, MemoryUsageMB: 50, CPUQuota: 2},
+		},
+		{
+			name:     "v1 unlimited",
+			procSelf: "4:memory:/\n3:cpu,cpuacct:/\n",
+			files: map[string]string{
+				"memory/memory.limit_in_bytes": "9223372036854771712\n",
+				"cpu/cpu.cfs_quota_us":         "-1\n",
+				"cpu/cpu.cfs_period_us":        "100000\n",
+			},
+			want: CgroupInfo{Version: 1, Path: "/"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := writeCgroupFS(t, tt.procSelf, tt.files)
+			got, err := r.read()
+			if err != nil {
+				t.Fatalf("read() error = %v", err)
+			}
+			if *got != tt.want {
+				t.Errorf("read() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCgroupReader_Unavailable(t *testing.T) {
+	r := cgroupReader{procSelfCgroup: filepath.Join(t.TempDir(), "missing")}
+	if _, err := r.read(); err == nil {
+		t.Error("read() expected error when /proc/self/cgroup is missing")
+	}
+}
+
+func TestApplyCgroupLimits(t *testing.T) {
+	tests := []struct {
+		name          string
+		cgroup        *CgroupInfo
+		wantMemLimit  uint64
+		wantMemSource string
+		wantCPUs      float64
+		wantCPUSource string
+	}{
+		{"no cgroup", nil, 16384, LimitSourceHost, 8, LimitSourceHost},
+		{"unlimited cgroup", &CgroupInfo{Version: 2}, 16384, LimitSourceHost, 8, LimitSourceHost},
+		{"tighter limits", &CgroupInfo{Version: 2, MemoryLimitMB: 512, CPUQuota: 0.5}, 512, LimitSourceCgroup, 0.5, LimitSourceCgroup},
+		{"limits above host", &CgroupInfo{Version: 1, MemoryLimitMB: 32768, CPUQuota: 16}, 16384, LimitSourceHost, 8, LimitSourceHost},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := SystemInfo{
+				CPU:    CPUInfo{Cores: 8},
+				Memory: MemoryInfo{TotalMB: 16384},
+				Cgroup: tt.cgroup,
+			}
+			applyCgroupLimits(&info)
+
+			if info.Memory.EffectiveLimitMB != tt.wantMemLimit || info.Memory.LimitSource != tt.wantMemSource {
+				t.Errorf("memory = %d (%s), want %d (%s)",
+					info.Memory.EffectiveLimitMB, info.Memory.LimitSource, tt.wantMemLimit, tt.wantMemSource)
+			}
+			if info.CPU.EffectiveCPUs != tt.wantCPUs || info.CPU.LimitSource != tt.wantCPUSource {
+				t.Errorf("cpu = %.2f (%s), want %.2f (%s)",
+					info.CPU.EffectiveCPUs, info.CPU.LimitSource, tt.wantCPUs, tt.wantCPUSource)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package meta
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TimeInfo represents locale, timezone, and clock state of the host.
+type TimeInfo struct {
+	Locale      string            `json:"locale" yaml:"locale"` // effective LC_ALL > LC_CTYPE > LANG
+	LocaleEnv   map[string]string `json:"locale_env" yaml:"locale_env"`
+	Timezone    string            `json:"timezone" yaml:"timezone"` // IANA name when resolvable
+	UTCOffset   string            `json:"utc_offset" yaml:"utc_offset"`
+	CurrentTime string            `json:"current_time" yaml:"current_time"` // RFC 3339, local zone
+	NTPServer   string            `json:"ntp_server,omitempty" yaml:"ntp_server,omitempty"`
+	NTPOffsetMS *float64          `json:"ntp_offset_ms,omitempty" yaml:"ntp_offset_ms,omitempty"` // local clock minus server clock
+	NTPError    string            `json:"ntp_error,omitempty" yaml:"ntp_error,omitempty"`
+}
+
+// DefaultNTPServer is queried when clock drift measurement is requested
+// without an explicit server.
+const DefaultNTPServer = "pool.ntp.org"
+
+// ntpTimeout bounds a single SNTP exchange.
+const ntpTimeout = 2 * time.Second
+
+// localeVars lists the environment variables that determine the locale,
+// in the order reported.
+var localeVars = []string{"LANG", "LC_ALL", "LC_CTYPE", "LC_MESSAGES", "LANGUAGE"}
+
+// ntpEpochOffset is the number of seconds between 1900-01-01 (NTP epoch) and 1970-01-01.
+const ntpEpochOffset = 2208988800
+
+// collectTimeInfo gathers locale and timezone information and, when
+// ntpServer is non-empty, measures the local clock offset against it.
+func collectTimeInfo(ctx context.Context, ntpServer string) *TimeInfo {
+	now := time.Now()
+	_, offset := now.Zone()
+
+	info := &TimeInfo{
+		LocaleEnv:   map[string]string{},
+		Timezone:    resolveTimezone(now),
+		UTCOffset:   formatUTCOffset(offset),
+		CurrentTime: now.Format(time.RFC3339),
+	}
+
+	for _, key := range localeVars {
+		if value, ok := os.LookupEnv(key); ok {
+			info.LocaleEnv[key] = value
+		}
+	}
+	info.Locale = effectiveLocale(info.LocaleEnv)
+
+	if ntpServer != "" {
+		info.NTPServer = ntpServer
+		offset, err := queryNTPOffset(ctx, ntpServer, time.Now)
+		if err != nil {
+			slog.DebugContext(ctx, "NTP offset measurement failed", "server", ntpServer, "error", err)
+			info.NTPError = err.Error()
+		} else {
+			ms := float64(offset) / float64(time.Millisecond)
+			info.NTPOffsetMS = &ms
+		}
+	}
+
+	return info
+}
+
+// effectiveLocale applies POSIX precedence: LC_ALL overrides LC_CTYPE, which overrides LANG.
+func effectiveLocale(env map[string]string) string {
+	for _, key := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := env[key]; value != "" {
+			return value
+		}
+	}
+	return "C"
+}
+
+// resolveTimezone returns the IANA timezone name from $TZ or the
+// /etc/localtime symlink, falling back to the zone abbreviation.
+func resolveTimezone(now time.Time) string {
+	if tz := strings.TrimPrefix(os.Getenv("TZ"), ":"); tz != "" {
+		return tz
+	}
+	if target, err := filepath.EvalSymlinks("/etc/localtime"); err == nil {
+		if i := strings.Index(target, "zoneinfo/"); i >= 0 {
+			return target[i+len("zoneinfo/"):]
+		}
+	}
+	if data, err := os.ReadFile("/etc/timezone"); err == nil {
+		if tz := strings.TrimSpace(string(data)); tz != "" {
+			return tz
+		}
+	}
+	name, _ := now.Zone()
+	return name
+}
+
+func formatUTCOffset(seconds int) string {
+	sign := '+'
+	if seconds < 0 {
+		sign = '-'
+		seconds = -seconds
+	}
+	return fmt.Sprintf("%c%02d:%02d", sign, seconds/3600, (seconds%3600)/60)
+}
+
+// queryNTPOffset performs a single SNTP (RFC 4330) exchange and returns the
+// local clock offset relative to the server (positive = local clock ahead).
+func queryNTPOffset(ctx context.Context, server string, now func() time.Time) (time.Duration, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "123")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, ntpTimeout)
+	defer cancel()
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", server)
+	if err != nil {
+		return 0, fmt.Errorf("dial ntp server: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	// LI=0, VN=4, Mode=3 (client).
+	req := make([]byte, 48)
+	req[0] = 0x23
+	t1 := now()
+	if _, err := conn.Write(req); err != nil {
+		return 0, fmt.Errorf("send ntp request: %w", err)
+	}
+
+	resp := make([]byte, 48)
+	n, err := conn.Read(resp)
+	t4 := now()
+	if err != nil {
+		return 0, fmt.Errorf("read ntp response: %w", err)
+	}
+	if n < 48 {
+		return 0, errors.New("short ntp response")
+	}
+	if mode := resp[0] & 0x07; mode != 4 {
+		return 0, fmt.Errorf("unexpected ntp mode %d", mode)
+	}
+
+	t2 := ntpTimestamp(resp[32:40]) // server receive
+	t3 := ntpTimestamp(resp[40:48]) // server transmit
+
+	// Standard SNTP offset: server is ahead by ((t2-t1)+(t3-t4))/2, so the
+	// local clock is ahead by the negation.
+	serverAhead := (t2.Sub(t1) + t3.Sub(t4)) / 2
+	return -serverAhead, nil
+}
+
+// ntpTimestamp decodes a 64-bit NTP timestamp (seconds since 1900 + fraction).
+func ntpTimestamp(b []byte) time.Time {
+	secs := binary.BigEndian.Uint32(b[0:4])
+	frac := binary.BigEndian.Uint32(b[4:8])
+	nanos := (int64(frac) * int64(time.Second)) >> 32
+	return time.Unix(int64(secs)-ntpEpochOffset, nanos)
+}
@@ -0,0 +1,121 @@
+package meta
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+)
+
+// putNTPTimestamp encodes t as a 64-bit NTP timestamp.
+func putNTPTimestamp(b []byte, t time.Time) {
+	binary.BigEndian.PutUint32(b[0:4], uint32(t.Unix()+ntpEpochOffset))
+	binary.BigEndian.PutUint32(b[4:8], uint32((int64(t.Nanosecond())<<32)/int64(time.Second)))
+}
+
+// startFakeNTP serves SNTP responses reporting serverTime for both receive
+// and transmit timestamps.
+func startFakeNTP(t *testing.T, serverTime time.Time) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 48)
+		for {
+			_, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			resp := make([]byte, 48)
+			resp[0] = 0x24 // VN=4, Mode=4 (server)
+			putNTPTimestamp(resp[32:40], serverTime)
+			putNTPTimestamp(resp[40:48], serverTime)
+			_, _ = conn.WriteTo(resp, addr)
+		}
+	}()
+
+	return conn.LocalAddr().String()
+}
+
+func TestQueryNTPOffset(t *testing.T) {
+	server := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	addr := startFakeNTP(t, server)
+
+	// The fake local clock is 1.5s ahead of the server and the exchange is
+	// instantaneous, so the measured offset must be exactly +1.5s.
+	local := server.Add(1500 * time.Millisecond)
+	offset, err := queryNTPOffset(context.Background(), addr, func() time.Time { return local })
+	if err != nil {
+		t.Fatalf("queryNTPOffset() error = %v", err)
+	}
+
+	if diff := offset - 1500*time.Millisecond; diff > time.Millisecond || diff < -time.Millisecond {
+		t.Errorf("queryNTPOffset() = %v, want 1.5s", offset)
+	}
+}
+
+func TestEffectiveLocale(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want string
+	}{
+		{"LC_ALL wins", map[string]string{"LANG": "en_US.UTF-8", "LC_ALL": "de_DE.UTF-8"}, "de_DE.UTF-8"},
+		{"LC_CTYPE over LANG", map[string]string{"LANG": "en_US.UTF-8", "LC_CTYPE": "C.UTF-8"}, "C.UTF-8"},
+		{"LANG only", map[string]string{"LANG": "fr_FR.UTF-8"}, "fr_FR.UTF-8"},
+		{"empty LC_ALL ignored", map[string]string{"LANG": "en_GB.UTF-8", "LC_ALL": ""}, "en_GB.UTF-8"},
+		{"nothing set", map[string]string{}, "C"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := effectiveLocale(tt.env); got != tt.want {
+				t.Errorf("effectiveLocale() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatUTCOffset(t *testing.T) {
+	tests := []struct {
+		seconds int
+		want    string
+	}{
+		{0, "+00:00"},
+		{3600, "+01:00"},
+		{19800, "+05:30"},
+		{-18000, "-05:00"},
+	}
+
+	for _, tt := range tests {
+		if got := formatUTCOffset(tt.seconds); got != tt.want {
+			t.Errorf("formatUTCOffset(%d) = %q, want %q", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestCollectTimeInfo(t *testing.T) {
+	t.Setenv("TZ", "Europe/Berlin")
+	t.Setenv("LANG", "en_US.UTF-8")
+	t.Setenv("LC_ALL", "")
+
+	info := collectTimeInfo(context.Background(), "")
+
+	if info.Timezone != "Europe/Berlin" {
+		t.Errorf("Timezone = %q, want Europe/Berlin", info.Timezone)
+	}
+	if info.Locale != "en_US.UTF-8" {
+		t.Errorf("Locale = %q, want en_US.UTF-8", info.Locale)
+	}
+	if _, err := time.Parse(time.RFC3339, info.CurrentTime); err != nil {
+		t.Errorf("CurrentTime %q is not RFC 3339: %v", info.CurrentTime, err)
+	}
+	if info.NTPOffsetMS != nil || info.NTPServer != "" {
+		t.Error("NTP should not be queried without a server")
+	}
+}
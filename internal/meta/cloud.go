@@ -0,0 +1,250 @@
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CloudInfo represents cloud provider and instance metadata.
+type CloudInfo struct {
+	Provider        string `json:"provider" yaml:"provider"` // aws, gcp, azure
+	InstanceType    string `json:"instance_type" yaml:"instance_type"`
+	Region          string `json:"region" yaml:"region"`
+	Zone            string `json:"zone" yaml:"zone"`
+	DetectionMethod string `json:"detection_method" yaml:"detection_method"` // dmi, metadata
+}
+
+// Cloud provider identifiers reported in CloudInfo.Provider.
+const (
+	CloudAWS   = "aws"
+	CloudGCP   = "gcp"
+	CloudAzure = "azure"
+)
+
+// azureChassisAssetTag is the DMI chassis asset tag Azure assigns to every VM.
+const azureChassisAssetTag = "7783-7084-3265-9085-8269-3286-77"
+
+// metadataTimeout bounds each metadata endpoint request so that a
+// misidentified host never stalls system collection.
+const metadataTimeout = 500 * time.Millisecond
+
+// cloudDetector holds the inputs used for cloud detection so tests can point
+// it at a fake DMI directory and metadata server.
+type cloudDetector struct {
+	dmiDir   string
+	awsURL   string
+	gcpURL   string
+	azureURL string
+	client   *http.Client
+}
+
+func newCloudDetector() cloudDetector {
+	return cloudDetector{
+		dmiDir:   "/sys/class/dmi/id",
+		awsURL:   "http://169.254.169.254",
+		gcpURL:   "http://metadata.google.internal",
+		azureURL: "http://169.254.169.254",
+		client:   &http.Client{Timeout: metadataTimeout},
+	}
+}
+
+// detectCloud identifies the cloud provider from DMI strings and, unless
+// skipNetwork is set, queries the provider's metadata endpoint for instance
+// details. Metadata endpoints are only contacted once DMI has identified the
+// provider, so non-cloud hosts never pay for network timeouts.
+// Returns nil if no cloud provider is detected.
+func detectCloud(ctx context.Context, skipNetwork bool) *CloudInfo {
+	return newCloudDetector().detect(ctx, skipNetwork)
+}
+
+func (d cloudDetector) detect(ctx context.Context, skipNetwork bool) *CloudInfo {
+	provider := d.providerFromDMI()
+	if provider == "" {
+		slog.DebugContext(ctx, "No cloud provider detected from DMI")
+		return nil
+	}
+
+	info := &CloudInfo{
+		Provider:        provider,
+		DetectionMethod: "dmi",
+	}
+
+	// DMI product name carries the instance type on AWS Nitro instances.
+	if provider == CloudAWS {
+		info.InstanceType = d.readDMI("product_name")
+	}
+
+	if skipNetwork {
+		return info
+	}
+
+	var err error
+	switch provider {
+	case CloudAWS:
+		err = d.fillAWS(ctx, info)
+	case CloudGCP:
+		err = d.fillGCP(ctx, info)
+	case CloudAzure:
+		err = d.fillAzure(ctx, info)
+	}
+	if err != nil {
+		slog.DebugContext(ctx, "Cloud metadata lookup failed", "provider", provider, "error", err)
+		return info
+	}
+
+	info.DetectionMethod = "metadata"
+	return info
+}
+
+// providerFromDMI maps well-known DMI vendor strings to a provider identifier.
+func (d cloudDetector) providerFromDMI() string {
+	sysVendor := strings.ToLower(d.readDMI("sys_vendor"))
+	biosVendor := strings.ToLower(d.readDMI("bios_vendor"))
+	productName := strings.ToLower(d.readDMI("product_name"))
+	productVersion := strings.ToLower(d.readDMI("product_version"))
+
+	switch {
+	case strings.Contains(sysVendor, "amazon") || strings.Contains(biosVendor, "amazon") ||
+		strings.Contains(productVersion, "amazon"):
+		return CloudAWS
+	case strings.Contains(sysVendor, "google") || strings.Contains(productName, "google compute engine"):
+		return CloudGCP
+	case d.readDMI("chassis_asset_tag") == azureChassisAssetTag:
+		return CloudAzure
+	case strings.Contains(sysVendor, "microsoft") && strings.Contains(productName, "virtual machine"):
+		return CloudAzure
+	}
+	return ""
+}
+
+func (d cloudDetector) readDMI(name string) string {
+	data, err := os.ReadFile(filepath.Join(d.dmiDir, name))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// fillAWS queries the EC2 instance metadata service using an IMDSv2 token.
+func (d cloudDetector) fillAWS(ctx context.Context, info *CloudInfo) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, d.awsURL+"/latest/api/token", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+	token, err := d.fetch(req)
+	if err != nil {
+		return fmt.Errorf("imds token: %w", err)
+	}
+
+	get := func(path string) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.awsURL+"/latest/meta-data/"+path, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("X-aws-ec2-metadata-token", token)
+		return d.fetch(req)
+	}
+
+	if info.InstanceType, err = get("instance-type"); err != nil {
+		return err
+	}
+	if info.Region, err = get("placement/region"); err != nil {
+		return err
+	}
+	if info.Zone, err = get("placement/availability-zone"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// fillGCP queries the GCE metadata server.
+func (d cloudDetector) fillGCP(ctx context.Context, info *CloudInfo) error {
+	get := func(path string) (string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.gcpURL+"/computeMetadata/v1/instance/"+path, nil)
+		if err != nil {
+			return "", err
+		}
+		req.Header.Set("Metadata-Flavor", "Google")
+		return d.fetch(req)
+	}
+
+	// Both values are returned as resource paths, e.g.
+	// "projects/123/machineTypes/e2-medium" and "projects/123/zones/us-central1-a".
+	machineType, err := get("machine-type")
+	if err != nil {
+		return err
+	}
+	zone, err := get("zone")
+	if err != nil {
+		return err
+	}
+
+	info.InstanceType = lastPathElement(machineType)
+	info.Zone = lastPathElement(zone)
+	if i := strings.LastIndex(info.Zone, "-"); i > 0 {
+		info.Region = info.Zone[:i]
+	}
+	return nil
+}
+
+// fillAzure queries the Azure Instance Metadata Service.
+func (d cloudDetector) fillAzure(ctx context.Context, info *CloudInfo) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
+		d.azureURL+"/metadata/instance/compute?api-version=2021-02-01", nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Metadata", "true")
+	body, err := d.fetch(req)
+	if err != nil {
+		return err
+	}
+
+	var compute struct {
+		VMSize   string `json:"vmSize"`
+		Location string `json:"location"`
+		Zone     string `json:"zone"`
+	}
+	if err := json.Unmarshal([]byte(body), &compute); err != nil {
+		return fmt.Errorf("decode azure metadata: %w", err)
+	}
+
+	info.InstanceType = compute.VMSize
+	info.Region = compute.Location
+	info.Zone = compute.Zone
+	return nil
+}
+
+func (d cloudDetector) fetch(req *http.Request) (string, error) {
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s %s: unexpected status %d", req.Method, req.URL.Path, resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(body)), nil
+}
+
+func lastPathElement(s string) string {
+	if i := strings.LastIndex(s, "/"); i >= 0 {
+		return s[i+1:]
+	}
+	return s
+}
@@ -0,0 +1,186 @@
+package meta
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeDMI(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, value := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(value+"\n"), 0o644); err != nil {
+			t.Fatalf("write dmi %s: %v", name, err)
+		}
+	}
+	return dir
+}
+
+func TestCloudDetector_ProviderFromDMI(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"aws sys vendor", map[string]string{"sys_vendor": "Amazon EC2"}, CloudAWS},
+		{"aws xen bios", map[string]string{"bios_vendor": "Xen", "product_version": "4.11.amazon"}, CloudAWS},
+		{"gcp", map[string]string{"sys_vendor": "Google", "product_name": "Google Compute Engine"}, CloudGCP},
+		{"azure asset tag", map[string]string{"chassis_asset_tag": azureChassisAssetTag}, CloudAzure},
+		{"azure vendor", map[string]string{"sys_vendor": "Microsoft Corporation", "product_name": "Virtual Machine"}, CloudAzure},
+		{"bare metal", map[string]string{"sys_vendor": "Dell Inc.", "product_name": "PowerEdge R640"}, ""},
+		{"no dmi", map[string]string{}, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := cloudDetector{dmiDir: writeDMI(t, tt.files)}
+			if got := d.providerFromDMI(); got != tt.want {
+				t.Errorf("providerFromDMI() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCloudDetector_AWSMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPut && r.URL.Path == "/latest/api/token":
+			_, _ = w.Write([]byte("tok"))
+		case r.Header.Get("X-aws-ec2-metadata-token") != "tok":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/latest/meta-data/instance-type":
+			_, _ = w.Write([]byte("m5.large"))
+		case r.URL.Path == "/latest/meta-data/placement/region":
+			_, _ = w.Write([]byte("us-east-1"))
+		case r.URL.Path == "/latest/meta-data/placement/availability-zone":
+			_, _ = w.Write([]byte("us-east-1a"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	d := cloudDetector{
+		dmiDir: writeDMI(t, map[string]string{"sys_vendor": "Amazon EC2", "product_name": "m5.large"}),
+		awsURL: srv.URL,
+		client: srv.Client(),
+	}
+
+	info := d.detect(context.Background(), false)
+	if info == nil {
+		t.Fatal("detect() returned nil, want AWS")
+	}
+	want := CloudInfo{Provider: CloudAWS, InstanceType: "m5.large", Region: "us-east-1", Zone: "us-east-1a", DetectionMethod: "metadata"}
+	if *info != want {
+		t.Errorf("detect() = %+v, want %+v", *info, want)
+	}
+}
+
+func TestCloudDetector_GCPMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/computeMetadata/v1/instance/machine-type":
+			_, _ = w.Write([]byte("projects/123/machineTypes/e2-medium"))
+		case "/computeMetadata/v1/instance/zone":
+			_, _ = w.Write([]byte("projects/123/zones/us-central1-a"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	d := cloudDetector{
+		dmiDir: writeDMI(t, map[string]string{"sys_vendor": "Google", "product_name": "Google Compute Engine"}),
+		gcpURL: srv.URL,
+		client: srv.Client(),
+	}
+
+	info := d.detect(context.Background(), false)
+	if info == nil {
+		t.Fatal("detect() returned nil, want GCP")
+	}
+	want := CloudInfo{Provider: CloudGCP, InstanceType: "e2-medium", Region: "us-central1", Zone: "us-central1-a", DetectionMethod: "metadata"}
+	if *info != want {
+		t.Errorf("detect() = %+v, want %+v", *info, want)
+	}
+}
+
+func TestCloudDetector_AzureMetadata(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata") != "true" || r.URL.Path != "/metadata/instance/compute" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"vmSize":"Standard_D2s_v3","location":"westeurope","zone":"1"}`))
+	}))
+	defer srv.Close()
+
+	d := cloudDetector{
+		dmiDir:   writeDMI(t, map[string]string{"chassis_asset_tag": azureChassisAssetTag}),
+		azureURL: srv.URL,
+		client:   srv.Client(),
+	}
+
+	info := d.detect(context.Background(), false)
+	if info == nil {
+		t.Fatal("detect() returned nil, want Azure")
+	}
+	want := CloudInfo{Provider: CloudAzure, InstanceType: "Standard_D2s_v3", Region: "westeurope", Zone: "1", DetectionMethod: "metadata"}
+	if *info != want {
+		t.Errorf("detect() = %+v, want %+v", *info, want)
+	}
+}
+
+func TestCloudDetector_SkipNetwork(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected metadata request: %s %s", r.Method, r.URL.Path)
+	}))
+	defer srv.Close()
+
+	d := cloudDetector{
+		dmiDir: writeDMI(t, map[string]string{"sys_vendor": "Amazon EC2", "product_name": "c6i.xlarge"}),
+		awsURL: srv.URL,
+		client: srv.Client(),
+	}
+
+	info := d.detect(context.Background(), true)
+	if info == nil {
+		t.Fatal("detect() returned nil, want AWS")
+	}
+	if info.DetectionMethod != "dmi" || info.InstanceType != "c6i.xlarge" {
+		t.Errorf("detect() = %+v, want dmi detection with instance type from product_name", *info)
+	}
+}
+
+func TestCloudDetector_MetadataFailureKeepsDMI(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	d := cloudDetector{
+		dmiDir: writeDMI(t, map[string]string{"sys_vendor": "Google"}),
+		gcpURL: srv.URL,
+		client: srv.Client(),
+	}
+
+	info := d.detect(context.Background(), false)
+	if info == nil || info.Provider != CloudGCP || info.DetectionMethod != "dmi" {
+		t.Errorf("detect() = %+v, want GCP detected via dmi", info)
+	}
+}
+
+func TestCloudDetector_NotCloud(t *testing.T) {
+	d := cloudDetector{dmiDir: writeDMI(t, map[string]string{"sys_vendor": "LENOVO"})}
+	if info := d.detect(context.Background(), false); info != nil {
+		t.Errorf("detect() = %+v, want nil", info)
+	}
+}
@@ -0,0 +1,45 @@
+package meta
+
+import "runtime/debug"
+
+// DependencyInfo represents a Go module compiled into the ado binary.
+type DependencyInfo struct {
+	Path    string `json:"path" yaml:"path"`
+	Version string `json:"version" yaml:"version"`
+	Sum     string `json:"sum" yaml:"sum"`
+	// Replace is the replacement module path@version when a replace
+	// directive was in effect at build time.
+	Replace string `json:"replace,omitempty" yaml:"replace,omitempty"`
+}
+
+// CollectDependencies returns the module dependencies recorded in the
+// binary's build info, in the order the toolchain recorded them (sorted by path).
+// Returns an empty slice when build info is unavailable.
+func CollectDependencies() []DependencyInfo {
+	bi, ok := readBuildInfo()
+	if !ok {
+		return []DependencyInfo{}
+	}
+	return dependenciesFromBuildInfo(bi)
+}
+
+func dependenciesFromBuildInfo(bi *debug.BuildInfo) []DependencyInfo {
+	deps := make([]DependencyInfo, 0, len(bi.Deps))
+	for _, mod := range bi.Deps {
+		dep := DependencyInfo{
+			Path:    mod.Path,
+			Version: mod.Version,
+			Sum:     mod.Sum,
+		}
+		if mod.Replace != nil {
+			dep.Replace = mod.Replace.Path
+			if mod.Replace.Version != "" {
+				dep.Replace += "@" + mod.Replace.Version
+			}
+			// The checksum of the module actually compiled in is the replacement's.
+			dep.Sum = mod.Replace.Sum
+		}
+		deps = append(deps, dep)
+	}
+	return deps
+}
@@ -0,0 +1,47 @@
+package meta
+
+import (
+	"reflect"
+	"runtime/debug"
+	"testing"
+)
+
+func TestDependenciesFromBuildInfo(t *testing.T) {
+	bi := &debug.BuildInfo{
+		Deps: []*debug.Module{
+			{Path: "github.com/spf13/cobra", Version: "v1.9.1", Sum: "h1:cobra="},
+			{
+				Path:    "golang.org/x/sys",
+				Version: "v0.37.0",
+				Sum:     "h1:original=",
+				Replace: &debug.Module{Path: "example.com/fork/sys", Version: "v0.37.1", Sum: "h1:fork="},
+			},
+			{
+				Path:    "example.com/local",
+				Version: "v0.0.0",
+				Replace: &debug.Module{Path: "../local"},
+			},
+		},
+	}
+
+	got := dependenciesFromBuildInfo(bi)
+	want := []DependencyInfo{
+		{Path: "github.com/spf13/cobra", Version: "v1.9.1", Sum: "h1:cobra="},
+		{Path: "golang.org/x/sys", Version: "v0.37.0", Sum: "h1:fork=", Replace: "example.com/fork/sys@v0.37.1"},
+		{Path: "example.com/local", Version: "v0.0.0", Replace: "../local"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dependenciesFromBuildInfo() =\n  %+v\nwant\n  %+v", got, want)
+	}
+}
+
+func TestCollectDependencies_NoBuildInfo(t *testing.T) {
+	orig := readBuildInfo
+	defer func() { readBuildInfo = orig }()
+	readBuildInfo = func() (*debug.BuildInfo, bool) { return nil, false }
+
+	if got := CollectDependencies(); got == nil || len(got) != 0 {
+		t.Errorf("CollectDependencies() = %#v, want empty slice", got)
+	}
+}
@@ -0,0 +1,250 @@
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// DockerInfo represents the container ado runs in and the Docker daemon
+// it can reach.
+type DockerInfo struct {
+	// Container is nil when ado does not appear to run in a container.
+	Container *ContainerInfo `json:"container" yaml:"container"`
+	Daemon    DockerDaemon   `json:"daemon" yaml:"daemon"`
+}
+
+// ContainerInfo describes the container the current process runs in.
+type ContainerInfo struct {
+	Runtime string `json:"runtime" yaml:"runtime"` // docker, podman, kubernetes, containerd, lxc
+	ID      string `json:"id,omitempty" yaml:"id,omitempty"`
+	// Limits are the container's cgroup limits; host-wide figures such as
+	// those of free or /proc/meminfo do not reflect them.
+	Limits *CgroupInfo `json:"limits,omitempty" yaml:"limits,omitempty"`
+}
+
+// DockerDaemon describes the Docker daemon at the configured socket.
+type DockerDaemon struct {
+	Host              string `json:"host" yaml:"host"`
+	Reachable         bool   `json:"reachable" yaml:"reachable"`
+	Version           string `json:"version,omitempty" yaml:"version,omitempty"`
+	APIVersion        string `json:"api_version,omitempty" yaml:"api_version,omitempty"`
+	OS                string `json:"os,omitempty" yaml:"os,omitempty"`
+	Arch              string `json:"arch,omitempty" yaml:"arch,omitempty"`
+	StorageDriver     string `json:"storage_driver,omitempty" yaml:"storage_driver,omitempty"`
+	CgroupDriver      string `json:"cgroup_driver,omitempty" yaml:"cgroup_driver,omitempty"`
+	CgroupVersion     string `json:"cgroup_version,omitempty" yaml:"cgroup_version,omitempty"`
+	ContainersRunning int    `json:"containers_running" yaml:"containers_running"`
+	Containers        int    `json:"containers" yaml:"containers"`
+	Images            int    `json:"images" yaml:"images"`
+	CPUs              int    `json:"cpus,omitempty" yaml:"cpus,omitempty"`
+	MemoryMB          uint64 `json:"memory_mb,omitempty" yaml:"memory_mb,omitempty"`
+	Error             string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// DefaultDockerHost is the daemon socket used when $DOCKER_HOST is unset.
+const DefaultDockerHost = "unix:///var/run/docker.sock"
+
+// DefaultDockerTimeout bounds the daemon queries.
+const DefaultDockerTimeout = 2 * time.Second
+
+// containerIDPattern matches a full container ID in cgroup paths and
+// mount points, such as /docker/<id> or cri-containerd-<id>.scope.
+var containerIDPattern = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// dockerProbe holds the inputs of Docker detection; fields are overridable
+// for tests.
+type dockerProbe struct {
+	root   string // filesystem root holding /.dockerenv and /proc
+	host   string
+	getenv func(string) string
+	cgroup func(ctx context.Context) *CgroupInfo
+}
+
+func newDockerProbe() dockerProbe {
+	host := os.Getenv("DOCKER_HOST")
+	if host == "" {
+		host = DefaultDockerHost
+	}
+	return dockerProbe{root: "/", host: host, getenv: os.Getenv, cgroup: detectCgroup}
+}
+
+// CollectDockerInfo detects whether ado runs in a container, with the
+// container's limits, and queries the Docker daemon at $DOCKER_HOST (or
+// the default socket) within timeout. An unreachable daemon is reported
+// in DockerDaemon.Error rather than failing the collection.
+func CollectDockerInfo(ctx context.Context, timeout time.Duration) DockerInfo {
+	return newDockerProbe().collect(ctx, timeout)
+}
+
+func (p dockerProbe) collect(ctx context.Context, timeout time.Duration) DockerInfo {
+	if timeout <= 0 {
+		timeout = DefaultDockerTimeout
+	}
+	info := DockerInfo{Container: p.container(ctx), Daemon: DockerDaemon{Host: p.host}}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	if err := p.queryDaemon(ctx, &info.Daemon); err != nil {
+		slog.DebugContext(ctx, "Docker daemon query failed", "host", p.host, "error", err)
+		info.Daemon.Error = err.Error()
+	}
+	return info
+}
+
+// container identifies the container runtime from the marker files
+// runtimes create and from the cgroup of PID 1.
+func (p dockerProbe) container(ctx context.Context) *ContainerInfo {
+	cgroups := p.read("proc/1/cgroup")
+	runtime := ""
+	switch {
+	case p.exists(".dockerenv"):
+		runtime = "docker"
+	case p.exists("run/.containerenv"):
+		runtime = "podman"
+	case p.getenv("KUBERNETES_SERVICE_HOST") != "" || strings.Contains(cgroups, "kubepods"):
+		runtime = "kubernetes"
+	case strings.Contains(cgroups, "/docker/") || strings.Contains(cgroups, "docker-"):
+		runtime = "docker"
+	case strings.Contains(cgroups, "containerd"):
+		runtime = "containerd"
+	case strings.Contains(cgroups, "/lxc/") || p.getenv("container") == "lxc":
+		runtime = "lxc"
+	}
+	if runtime == "" {
+		return nil
+	}
+
+	info := &ContainerInfo{Runtime: runtime, Limits: p.cgroup(ctx)}
+	// cgroup v1 paths carry the ID; under v2 the cgroup is "/", but the
+	// runtime's bind mounts of hostname and resolv.conf name it.
+	for _, name := range []string{"proc/self/cgroup", "proc/self/mountinfo"} {
+		if id := containerIDPattern.FindString(p.read(name)); id != "" {
+			info.ID = id
+			break
+		}
+	}
+	return info
+}
+
+func (p dockerProbe) exists(name string) bool {
+	_, err := os.Stat(filepath.Join(p.root, name))
+	return err == nil
+}
+
+func (p dockerProbe) read(name string) string {
+	data, err := os.ReadFile(filepath.Join(p.root, name))
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// queryDaemon fills d from the daemon's /version and /info endpoints.
+func (p dockerProbe) queryDaemon(ctx context.Context, d *DockerDaemon) error {
+	client, baseURL, err := dockerClient(p.host, p.getenv("DOCKER_TLS_VERIFY") != "")
+	if err != nil {
+		return err
+	}
+
+	var version struct {
+		Version    string
+		APIVersion string `json:"ApiVersion"`
+		Os         string
+		Arch       string
+	}
+	if err := dockerGet(ctx, client, baseURL+"/version", &version); err != nil {
+		return err
+	}
+	d.Reachable = true
+	d.Version = version.Version
+	d.APIVersion = version.APIVersion
+	d.OS = version.Os
+	d.Arch = version.Arch
+
+	var info struct {
+		Driver            string
+		CgroupDriver      string
+		CgroupVersion     string
+		ContainersRunning int
+		Containers        int
+		Images            int
+		NCPU              int
+		MemTotal          uint64
+	}
+	if err := dockerGet(ctx, client, baseURL+"/info", &info); err != nil {
+		return err
+	}
+	d.StorageDriver = info.Driver
+	d.CgroupDriver = info.CgroupDriver
+	d.CgroupVersion = info.CgroupVersion
+	d.ContainersRunning = info.ContainersRunning
+	d.Containers = info.Containers
+	d.Images = info.Images
+	d.CPUs = info.NCPU
+	d.MemoryMB = info.MemTotal / 1024 / 1024
+	return nil
+}
+
+// dockerClient returns an HTTP client and base URL for a DOCKER_HOST
+// value. unix:// sockets and plain tcp:// are supported; TLS-protected
+// daemons and Windows named pipes are not.
+func dockerClient(host string, tlsVerify bool) (*http.Client, string, error) {
+	scheme, addr, ok := strings.Cut(host, "://")
+	if !ok || addr == "" {
+		return nil, "", fmt.Errorf("invalid DOCKER_HOST %q", host)
+	}
+	switch scheme {
+	case "unix":
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", addr)
+			},
+		}
+		return &http.Client{Transport: transport}, "http://docker", nil
+	case "tcp", "http":
+		if tlsVerify {
+			return nil, "", errors.New("DOCKER_TLS_VERIFY is set; TLS daemons are not supported")
+		}
+		return &http.Client{}, "http://" + addr, nil
+	default:
+		return nil, "", fmt.Errorf("unsupported DOCKER_HOST scheme %q", scheme)
+	}
+}
+
+func dockerGet(ctx context.Context, client *http.Client, url string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return errors.New("no Docker socket found")
+		}
+		if errors.Is(err, os.ErrPermission) {
+			return errors.New("permission denied on the Docker socket; add the user to the docker group")
+		}
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", req.URL.Path, resp.Status)
+	}
+	return json.Unmarshal(body, out)
+}
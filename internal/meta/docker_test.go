@@ -0,0 +1,174 @@
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testContainerID = "4f1b7c2a9e3d5f60718293a4b5c6d7e8f9a0b1c2d3e4f5061728394a5b6c7d8e"
+
+func fakeDockerDaemon() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/version":
+			json.NewEncoder(w).Encode(map[string]string{"Version": "27.3.1", "ApiVersion": "1.47", "Os": "linux", "Arch": "amd64"})
+		case "/info":
+			json.NewEncoder(w).Encode(map[string]any{
+				"Driver": "overlay2", "CgroupDriver": "systemd", "CgroupVersion": "2",
+				"ContainersRunning": 3, "Containers": 5, "Images": 12, "NCPU": 8, "MemTotal": 16 << 30,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestDockerProbe_Container(t *testing.T) {
+	limits := &CgroupInfo{Version: 2, Path: "/", MemoryLimitMB: 512, CPUQuota: 1.5}
+	tests := []struct {
+		name        string
+		files       map[string]string
+		env         map[string]string
+		wantRuntime string
+		wantID      string
+	}{
+		{
+			name:  "host",
+			files: map[string]string{"proc/1/cgroup": "0::/init.scope\n"},
+		},
+		{
+			name: "docker cgroup v2",
+			files: map[string]string{
+				".dockerenv":          "",
+				"proc/1/cgroup":       "0::/\n",
+				"proc/self/cgroup":    "0::/\n",
+				"proc/self/mountinfo": "612 590 254:1 /var/lib/docker/containers/" + testContainerID + "/hostname /etc/hostname rw\n",
+			},
+			wantRuntime: "docker",
+			wantID:      testContainerID,
+		},
+		{
+			name:        "docker cgroup v1",
+			files:       map[string]string{"proc/1/cgroup": "4:memory:/docker/" + testContainerID + "\n", "proc/self/cgroup": "4:memory:/docker/" + testContainerID + "\n"},
+			wantRuntime: "docker",
+			wantID:      testContainerID,
+		},
+		{
+			name:        "podman",
+			files:       map[string]string{"run/.containerenv": ""},
+			wantRuntime: "podman",
+		},
+		{
+			name:        "kubernetes",
+			files:       map[string]string{"proc/1/cgroup": "0::/\n"},
+			env:         map[string]string{"KUBERNETES_SERVICE_HOST": "10.0.0.1"},
+			wantRuntime: "kubernetes",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := dockerProbe{
+				root:   writeFiles(t, tt.files),
+				getenv: func(key string) string { return tt.env[key] },
+				cgroup: func(context.Context) *CgroupInfo { return limits },
+			}
+			got := p.container(context.Background())
+			if tt.wantRuntime == "" {
+				if got != nil {
+					t.Errorf("container() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil {
+				t.Fatal("container() = nil")
+			}
+			if got.Runtime != tt.wantRuntime || got.ID != tt.wantID || got.Limits != limits {
+				t.Errorf("container() = %+v, want runtime %q, id %q", got, tt.wantRuntime, tt.wantID)
+			}
+		})
+	}
+}
+
+func TestDockerProbe_Daemon(t *testing.T) {
+	srv := httptest.NewServer(fakeDockerDaemon())
+	defer srv.Close()
+
+	p := dockerProbe{
+		root:   t.TempDir(),
+		host:   "tcp://" + strings.TrimPrefix(srv.URL, "http://"),
+		getenv: func(string) string { return "" },
+		cgroup: func(context.Context) *CgroupInfo { return nil },
+	}
+	info := p.collect(context.Background(), 0)
+	d := info.Daemon
+	if !d.Reachable || d.Error != "" {
+		t.Fatalf("daemon = %+v", d)
+	}
+	if d.Version != "27.3.1" || d.APIVersion != "1.47" || d.StorageDriver != "overlay2" || d.ContainersRunning != 3 || d.Images != 12 || d.MemoryMB != 16384 {
+		t.Errorf("daemon = %+v", d)
+	}
+	if info.Container != nil {
+		t.Errorf("container = %+v, want nil", info.Container)
+	}
+}
+
+func TestDockerProbe_UnixSocket(t *testing.T) {
+	sock := filepath.Join(t.TempDir(), "docker.sock")
+	ln, err := net.Listen("unix", sock)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	srv := &http.Server{Handler: fakeDockerDaemon()}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	p := dockerProbe{root: t.TempDir(), host: "unix://" + sock, getenv: func(string) string { return "" }, cgroup: func(context.Context) *CgroupInfo { return nil }}
+	if d := p.collect(context.Background(), 0).Daemon; !d.Reachable || d.Version != "27.3.1" {
+		t.Errorf("daemon = %+v", d)
+	}
+}
+
+func TestDockerProbe_DaemonErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		env  map[string]string
+		want string
+	}{
+		{"missing socket", "unix://" + filepath.Join(os.TempDir(), "ado-no-such-docker.sock"), nil, "no Docker socket found"},
+		{"bad host", "docker.example.com", nil, "invalid DOCKER_HOST"},
+		{"unsupported scheme", "ssh://user@host", nil, `unsupported DOCKER_HOST scheme "ssh"`},
+		{"tls", "tcp://127.0.0.1:2376", map[string]string{"DOCKER_TLS_VERIFY": "1"}, "TLS daemons are not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := dockerProbe{root: t.TempDir(), host: tt.host, getenv: func(key string) string { return tt.env[key] }, cgroup: func(context.Context) *CgroupInfo { return nil }}
+			d := p.collect(context.Background(), 0).Daemon
+			if d.Reachable || !strings.Contains(d.Error, tt.want) {
+				t.Errorf("daemon = %+v, want error containing %q", d, tt.want)
+			}
+		})
+	}
+}
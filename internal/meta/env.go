@@ -2,33 +2,74 @@ package meta
 
 import (
 	"os"
+	"strings"
 
 	"github.com/anowarislam/ado/internal/config"
 )
 
 type EnvInfo struct {
-	ConfigPath    string            `json:"config_path" yaml:"config_path"`
-	ConfigSources []string          `json:"config_sources" yaml:"config_sources"`
-	HomeDir       string            `json:"home_dir" yaml:"home_dir"`
-	CacheDir      string            `json:"cache_dir" yaml:"cache_dir"`
-	Env           map[string]string `json:"env" yaml:"env"`
+	ConfigPath    string   `json:"config_path" yaml:"config_path"`
+	ConfigSources []string `json:"config_sources" yaml:"config_sources"`
+	HomeDir       string   `json:"home_dir" yaml:"home_dir"`
+	CacheDir      string   `json:"cache_dir" yaml:"cache_dir"`
+	// StateDir and DataDir are ado's own directories (run history,
+	// snapshots, persistent data); empty when they cannot be determined.
+	StateDir string            `json:"state_dir" yaml:"state_dir"`
+	DataDir  string            `json:"data_dir" yaml:"data_dir"`
+	Env      map[string]string `json:"env" yaml:"env"`
+	// SystemEnv holds diagnostic-relevant variables outside the ADO_*
+	// allowlist (or every variable with EnvOptions.AllEnv), with secrets
+	// masked unless EnvOptions.ShowSecrets is set.
+	SystemEnv map[string]string `json:"system_env" yaml:"system_env"`
+	// Resolution is the ordered decision trace behind ConfigPath.
+	Resolution []config.ResolutionStep `json:"resolution" yaml:"resolution"`
 }
 
+// EnvOptions controls which environment variables CollectEnvInfoWithOptions reports.
+type EnvOptions struct {
+	// AllEnv reports every environment variable instead of the curated set.
+	AllEnv bool
+	// ShowSecrets disables redaction of secret-looking values in SystemEnv.
+	ShowSecrets bool
+	// ConfigResolver, when set, resolves the config path instead of a new
+	// search. It must have been made for the same explicit config path.
+	ConfigResolver *config.PathResolver
+}
+
+// adoEnvVars is the allowlist of ado's own variables, reported verbatim.
+var adoEnvVars = []string{"ADO_CONFIG", "ADO_LOG_LEVEL"}
+
+// diagnosticEnvVars are variables that commonly explain behavior
+// differences between machines.
+var diagnosticEnvVars = []string{
+	"PATH", "SHELL", "TERM", "COLORTERM", "LANG",
+	"HTTP_PROXY", "HTTPS_PROXY", "NO_PROXY", "ALL_PROXY",
+	"http_proxy", "https_proxy", "no_proxy", "all_proxy",
+	"CI", "GITHUB_ACTIONS", "TF_BUILD",
+	"XDG_CONFIG_HOME", "XDG_CACHE_HOME", "XDG_DATA_HOME", "XDG_STATE_HOME", "XDG_RUNTIME_DIR",
+}
+
+// CollectEnvInfo reports environment information with the curated set of
+// diagnostic variables.
 func CollectEnvInfo(explicitConfig string) EnvInfo {
+	return CollectEnvInfoWithOptions(explicitConfig, EnvOptions{})
+}
+
+// CollectEnvInfoWithOptions reports environment information.
+func CollectEnvInfoWithOptions(explicitConfig string, opts EnvOptions) EnvInfo {
 	homeDir, _ := os.UserHomeDir()
 	cacheDir, _ := os.UserCacheDir()
+	dirs := config.ResolveDirs()
 
-	configPath := explicitConfig
-	if configPath == "" {
-		if envPath, ok := os.LookupEnv("ADO_CONFIG"); ok {
-			configPath = envPath
-		}
+	envPath, envSet := os.LookupEnv("ADO_CONFIG")
+	resolver := opts.ConfigResolver
+	if resolver == nil {
+		resolver = config.NewPathResolver(explicitConfig, homeDir)
 	}
-
-	resolved, sources := config.ResolveConfigPath(configPath, homeDir)
+	resolved, sources := resolver.Resolve()
 
 	envVars := map[string]string{}
-	for _, key := range []string{"ADO_CONFIG", "ADO_LOG_LEVEL"} {
+	for _, key := range adoEnvVars {
 		if value, ok := os.LookupEnv(key); ok {
 			envVars[key] = value
 		}
@@ -39,6 +80,39 @@ func CollectEnvInfo(explicitConfig string) EnvInfo {
 		ConfigSources: sources,
 		HomeDir:       homeDir,
 		CacheDir:      cacheDir,
+		StateDir:      dirs.State,
+		DataDir:       dirs.Data,
 		Env:           envVars,
+		SystemEnv:     collectSystemEnv(opts.AllEnv, opts.ShowSecrets),
+		Resolution:    config.ExplainConfigPath(explicitConfig, envPath, envSet, homeDir),
+	}
+}
+
+func collectSystemEnv(all, showSecrets bool) map[string]string {
+	vars := map[string]string{}
+	if all {
+		for _, kv := range os.Environ() {
+			key, value, _ := strings.Cut(kv, "=")
+			if key != "" {
+				vars[key] = value
+			}
+		}
+	} else {
+		for _, key := range diagnosticEnvVars {
+			if value, ok := os.LookupEnv(key); ok {
+				vars[key] = value
+			}
+		}
+	}
+
+	for _, key := range adoEnvVars {
+		delete(vars, key) // reported in Env
+	}
+	if showSecrets {
+		return vars
+	}
+	for key, value := range vars {
+		vars[key] = RedactEnvValue(key, value)
 	}
+	return vars
 }
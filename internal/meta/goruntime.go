@@ -0,0 +1,82 @@
+package meta
+
+import (
+	"math"
+	"os"
+	"runtime"
+	"runtime/debug"
+)
+
+// GoRuntimeInfo represents the Go runtime settings of the running ado process.
+type GoRuntimeInfo struct {
+	Version    string `json:"version" yaml:"version"`
+	GOMAXPROCS int    `json:"gomaxprocs" yaml:"gomaxprocs"`
+	NumCPU     int    `json:"num_cpu" yaml:"num_cpu"`
+	// CgroupCPUs is the GOMAXPROCS value implied by the cgroup CPU quota
+	// (quota rounded down, minimum 1); 0 when no quota applies.
+	CgroupCPUs int `json:"cgroup_cpus" yaml:"cgroup_cpus"`
+	// MemoryLimitBytes is the soft memory limit (GOMEMLIMIT); -1 = unlimited.
+	MemoryLimitBytes int64 `json:"memory_limit_bytes" yaml:"memory_limit_bytes"`
+	// GCPercent is the GOGC value; -1 = garbage collection disabled.
+	GCPercent    int               `json:"gc_percent" yaml:"gc_percent"`
+	NumGoroutine int               `json:"num_goroutine" yaml:"num_goroutine"`
+	Env          map[string]string `json:"env" yaml:"env"`
+}
+
+// goRuntimeEnvVars lists the environment variables that tune the Go runtime.
+var goRuntimeEnvVars = []string{"GOMAXPROCS", "GOMEMLIMIT", "GOGC", "GODEBUG"}
+
+// collectGoRuntimeInfo reports current Go runtime settings. cgroup may be
+// nil when not running under a CPU-limited cgroup.
+func collectGoRuntimeInfo(cgroup *CgroupInfo) *GoRuntimeInfo {
+	info := &GoRuntimeInfo{
+		Version:          runtime.Version(),
+		GOMAXPROCS:       runtime.GOMAXPROCS(0),
+		NumCPU:           runtime.NumCPU(),
+		CgroupCPUs:       cgroupCPUs(cgroup),
+		MemoryLimitBytes: currentMemoryLimit(),
+		GCPercent:        currentGCPercent(),
+		NumGoroutine:     runtime.NumGoroutine(),
+		Env:              map[string]string{},
+	}
+
+	for _, key := range goRuntimeEnvVars {
+		if value, ok := os.LookupEnv(key); ok {
+			info.Env[key] = value
+		}
+	}
+
+	return info
+}
+
+// currentMemoryLimit reads the soft memory limit without changing it.
+func currentMemoryLimit() int64 {
+	limit := debug.SetMemoryLimit(-1)
+	if limit == math.MaxInt64 {
+		return -1
+	}
+	return limit
+}
+
+// currentGCPercent reads GOGC. The runtime only exposes it via the setter,
+// so the previous value is restored immediately.
+func currentGCPercent() int {
+	percent := debug.SetGCPercent(-1)
+	debug.SetGCPercent(percent)
+	return percent
+}
+
+// cgroupCPUs converts a cgroup CPU quota to a GOMAXPROCS value the way
+// container-aware schedulers do: round down, never below one. It is only
+// reported: since Go 1.25 the runtime sizes GOMAXPROCS to the quota itself
+// and keeps it updated, which setting it explicitly would turn off.
+func cgroupCPUs(cgroup *CgroupInfo) int {
+	if cgroup == nil || cgroup.CPUQuota <= 0 {
+		return 0
+	}
+	procs := int(math.Floor(cgroup.CPUQuota))
+	if procs < 1 {
+		procs = 1
+	}
+	return procs
+}
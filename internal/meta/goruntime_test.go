@@ -0,0 +1,61 @@
+package meta
+
+import (
+	"runtime"
+	"runtime/debug"
+	"testing"
+)
+
+func TestCgroupCPUs(t *testing.T) {
+	tests := []struct {
+		name   string
+		cgroup *CgroupInfo
+		want   int
+	}{
+		{"no cgroup", nil, 0},
+		{"no quota", &CgroupInfo{Version: 2}, 0},
+		{"fractional below one", &CgroupInfo{CPUQuota: 0.5}, 1},
+		{"fractional rounds down", &CgroupInfo{CPUQuota: 2.7}, 2},
+		{"whole", &CgroupInfo{CPUQuota: 4}, 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cgroupCPUs(tt.cgroup); got != tt.want {
+				t.Errorf("cgroupCPUs() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectGoRuntimeInfo(t *testing.T) {
+	t.Setenv("GOGC", "200")
+	prevGC := debug.SetGCPercent(150)
+	defer debug.SetGCPercent(prevGC)
+	prevLimit := debug.SetMemoryLimit(512 << 20)
+	defer debug.SetMemoryLimit(prevLimit)
+
+	info := collectGoRuntimeInfo(&CgroupInfo{CPUQuota: 1.5})
+
+	if info.Version != runtime.Version() {
+		t.Errorf("Version = %q, want %q", info.Version, runtime.Version())
+	}
+	if info.GOMAXPROCS != runtime.GOMAXPROCS(0) {
+		t.Errorf("GOMAXPROCS = %d, want %d", info.GOMAXPROCS, runtime.GOMAXPROCS(0))
+	}
+	if info.CgroupCPUs != 1 {
+		t.Errorf("CgroupCPUs = %d, want 1", info.CgroupCPUs)
+	}
+	if info.GCPercent != 150 {
+		t.Errorf("GCPercent = %d, want 150", info.GCPercent)
+	}
+	if got := debug.SetGCPercent(150); got != 150 {
+		t.Errorf("reading GCPercent changed it to %d", got)
+	}
+	if info.MemoryLimitBytes != 512<<20 {
+		t.Errorf("MemoryLimitBytes = %d, want %d", info.MemoryLimitBytes, 512<<20)
+	}
+	if info.Env["GOGC"] != "200" {
+		t.Errorf("Env[GOGC] = %q, want 200", info.Env["GOGC"])
+	}
+}
@@ -0,0 +1,59 @@
+//go:build !minimal
+
+package meta
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/jaypipes/ghw"
+)
+
+// HardwareDetection reports whether the GPU, NPU, and SMART disk health
+// probes are compiled in. Builds with the minimal tag leave them out, and
+// with them the PCI database ghw needs.
+const HardwareDetection = true
+
+// npuProbe is the NPU probe of DefaultCollectors.
+var npuProbe = detectNPU
+
+// detectGPU attempts to detect GPU information using hardware-level detection.
+// Returns empty slice if detection fails (graceful degradation).
+// Logs detection failures via slog at debug level.
+//
+// Phase 2 implementation: Cross-platform GPU detection using ghw.
+// Detects NVIDIA, AMD, Intel, Apple, and other GPUs on Linux, Windows, and macOS.
+func detectGPU(ctx context.Context) []GPUInfo {
+	gpus := []GPUInfo{}
+
+	if runtime.GOOS == "linux" && !hasGPUDevices("/") {
+		slog.DebugContext(ctx, "No GPU devices; skipping PCI enumeration")
+		return gpus
+	}
+
+	// Use ghw for hardware-level GPU detection
+	gpu, err := ghw.GPU()
+	if err != nil {
+		slog.DebugContext(ctx, "GPU detection failed", "error", err)
+		return gpus
+	}
+
+	if gpu == nil || len(gpu.GraphicsCards) == 0 {
+		slog.DebugContext(ctx, "No GPUs detected")
+		return gpus
+	}
+
+	for _, card := range gpu.GraphicsCards {
+		if card.DeviceInfo == nil {
+			continue
+		}
+
+		info := classifyGPU(card.DeviceInfo.Vendor.Name, card.DeviceInfo.Product.Name)
+		gpus = append(gpus, info)
+
+		slog.DebugContext(ctx, "Detected GPU", "vendor", info.Vendor, "model", info.Model, "type", info.Type)
+	}
+
+	return gpus
+}
@@ -0,0 +1,24 @@
+//go:build minimal
+
+package meta
+
+import (
+	"context"
+	"log/slog"
+)
+
+// HardwareDetection reports whether the GPU, NPU, and SMART disk health
+// probes are compiled in. This minimal build leaves them out.
+const HardwareDetection = false
+
+// npuProbe is the NPU probe of DefaultCollectors.
+func npuProbe(ctx context.Context, _, _ string) *NPUInfo {
+	slog.DebugContext(ctx, "NPU detection "+notCompiledIn)
+	return nil
+}
+
+// detectGPU reports no GPUs: ghw is not compiled in.
+func detectGPU(ctx context.Context) []GPUInfo {
+	slog.DebugContext(ctx, "GPU detection "+notCompiledIn)
+	return []GPUInfo{}
+}
@@ -0,0 +1,31 @@
+//go:build minimal
+
+package meta
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestMinimalBuild(t *testing.T) {
+	info := CollectSystemInfo(context.Background(), SystemOptions{SkipNetwork: true})
+	if info.Collected(SectionGPU) || info.Collected(SectionNPU) {
+		t.Errorf("sections = %v, want gpu and npu left out", info.Sections)
+	}
+	if len(info.GPU) != 0 || info.NPU != nil {
+		t.Errorf("GPU = %v, NPU = %v; want none", info.GPU, info.NPU)
+	}
+
+	// An injected probe still runs.
+	gpu := func(context.Context) []GPUInfo { return []GPUInfo{{Vendor: "NVIDIA"}} }
+	info = CollectSystemInfo(context.Background(), SystemOptions{SkipNetwork: true, Sections: []string{SectionGPU}, Collectors: Collectors{GPU: gpu}})
+	if !slices.Equal(info.Sections, []string{SectionGPU}) || len(info.GPU) != 1 {
+		t.Errorf("sections = %v, GPU = %v; want the injected probe", info.Sections, info.GPU)
+	}
+
+	health := newDiskHealthChecker(defaultToolEnv()).check(context.Background(), "/dev/sda1")
+	if health.Status != HealthUnavailable || health.Message != notCompiledIn {
+		t.Errorf("health = %+v, want unavailable and not compiled in", health)
+	}
+}
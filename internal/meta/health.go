@@ -0,0 +1,176 @@
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DiskHealth represents the SMART health status of the device backing a volume.
+type DiskHealth struct {
+	Status  string `json:"status" yaml:"status"`                       // passed, failing, unknown, unavailable
+	Source  string `json:"source" yaml:"source"`                       // smartctl, none
+	Device  string `json:"device,omitempty" yaml:"device,omitempty"`   // physical device queried
+	Message string `json:"message,omitempty" yaml:"message,omitempty"` // reason when status is not conclusive
+}
+
+// Disk health status values reported in DiskHealth.Status.
+const (
+	HealthPassed      = "passed"
+	HealthFailing     = "failing"
+	HealthUnknown     = "unknown"
+	HealthUnavailable = "unavailable"
+)
+
+// smartTimeout bounds the smartctl invocations of one checkAll call, which
+// run concurrently.
+const smartTimeout = 5 * time.Second
+
+// Partition name patterns capturing the whole-disk name: nvme0n1p2 / mmcblk0p1 (p<N>),
+// sda1 / vdb3 / xvda1 (<N>), and macOS disk3s1s1 (s<N>...).
+var (
+	partitionPSuffix      = regexp.MustCompile(`^((?:nvme\d+n\d+)|(?:mmcblk\d+))p\d+$`)
+	partitionDigitSuffix  = regexp.MustCompile(`^((?:sd|hd|vd|xvd)[a-z]+)\d+$`)
+	partitionDarwinSuffix = regexp.MustCompile(`^(disk\d+)(?:s\d+)+$`)
+)
+
+// diskHealthChecker queries SMART health once per physical device.
+type diskHealthChecker struct {
+	env   toolEnv
+	cache map[string]*DiskHealth
+}
+
+func newDiskHealthChecker(env toolEnv) *diskHealthChecker {
+	return &diskHealthChecker{env: env, cache: map[string]*DiskHealth{}}
+}
+
+// check returns the health of the physical device backing partition device.
+// Missing smartctl, insufficient privileges, and virtual devices degrade to
+// an "unavailable" or "unknown" status instead of an error.
+func (c *diskHealthChecker) check(ctx context.Context, device string) *DiskHealth {
+	return c.checkAll(ctx, []string{device})[0]
+}
+
+// checkAll returns the health of the physical devices backing each of the
+// partition devices, in order, like check. The physical devices are
+// queried concurrently under a single smartTimeout deadline, so a host
+// with many disks does not wait for each in turn.
+func (c *diskHealthChecker) checkAll(ctx context.Context, devices []string) []*DiskHealth {
+	results := make([]*DiskHealth, len(devices))
+	pending := map[string][]int{}
+	for i, device := range devices {
+		switch {
+		case !HardwareDetection:
+			results[i] = &DiskHealth{Status: HealthUnavailable, Source: "none", Message: notCompiledIn}
+		case !strings.HasPrefix(device, "/dev/"):
+			results[i] = &DiskHealth{Status: HealthUnavailable, Source: "none", Message: "not a block device"}
+		default:
+			disk := parentDisk(device)
+			if cached, ok := c.cache[disk]; ok {
+				results[i] = cached
+				continue
+			}
+			pending[disk] = append(pending[disk], i)
+		}
+	}
+	if len(pending) == 0 {
+		return results
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, smartTimeout)
+	defer cancel()
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for disk, indexes := range pending {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			health := c.query(ctx, disk)
+			mu.Lock()
+			defer mu.Unlock()
+			c.cache[disk] = health
+			for _, i := range indexes {
+				results[i] = health
+			}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func (c *diskHealthChecker) query(ctx context.Context, disk string) *DiskHealth {
+	smartctl, err := c.env.lookPath("smartctl")
+	if err != nil {
+		return &DiskHealth{Status: HealthUnavailable, Source: "none", Device: disk, Message: "smartctl not installed"}
+	}
+
+	// smartctl encodes findings in its exit status bitmask, so a non-zero
+	// exit is expected for failing disks; the JSON body is authoritative.
+	out, runErr := c.env.run(ctx, smartctl, "-H", "-j", disk)
+	health := parseSmartctl(out)
+	health.Device = disk
+	if health.Status == HealthUnknown && health.Message == "" && runErr != nil {
+		health.Message = runErr.Error()
+	}
+
+	slog.DebugContext(ctx, "Disk health checked", "device", disk, "status", health.Status, "message", health.Message)
+	return health
+}
+
+// parseSmartctl interprets `smartctl -H -j` output.
+func parseSmartctl(output string) *DiskHealth {
+	health := &DiskHealth{Status: HealthUnknown, Source: "smartctl"}
+
+	var report struct {
+		Smartctl struct {
+			Messages []struct {
+				String   string `json:"string"`
+				Severity string `json:"severity"`
+			} `json:"messages"`
+		} `json:"smartctl"`
+		SmartStatus *struct {
+			Passed bool `json:"passed"`
+		} `json:"smart_status"`
+	}
+	if err := json.Unmarshal([]byte(output), &report); err != nil {
+		health.Message = "unrecognized smartctl output"
+		return health
+	}
+
+	if report.SmartStatus != nil {
+		if report.SmartStatus.Passed {
+			health.Status = HealthPassed
+		} else {
+			health.Status = HealthFailing
+		}
+		return health
+	}
+
+	// No verdict: usually permission denied or a device without SMART support.
+	for _, msg := range report.Smartctl.Messages {
+		if msg.String != "" {
+			health.Message = msg.String
+			break
+		}
+	}
+	return health
+}
+
+// parentDisk maps a partition device path to its whole-disk device path.
+// Unrecognized names are returned unchanged.
+func parentDisk(device string) string {
+	dir, name := filepath.Split(device)
+	for _, re := range []*regexp.Regexp{partitionPSuffix, partitionDigitSuffix, partitionDarwinSuffix} {
+		if m := re.FindStringSubmatch(name); m != nil {
+			return dir + m[1]
+		}
+	}
+	return device
+}
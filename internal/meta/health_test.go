@@ -0,0 +1,174 @@
+package meta
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParentDisk(t *testing.T) {
+	tests := []struct {
+		device string
+		want   string
+	}{
+		{"/dev/sda1", "/dev/sda"},
+		{"/dev/xvda1", "/dev/xvda"},
+		{"/dev/vdb12", "/dev/vdb"},
+		{"/dev/nvme0n1p2", "/dev/nvme0n1"},
+		{"/dev/mmcblk0p1", "/dev/mmcblk0"},
+		{"/dev/disk3s1s1", "/dev/disk3"},
+		{"/dev/sda", "/dev/sda"},
+		{"/dev/mapper/vg-root", "/dev/mapper/vg-root"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.device, func(t *testing.T) {
+			if got := parentDisk(tt.device); got != tt.want {
+				t.Errorf("parentDisk(%q) = %q, want %q", tt.device, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSmartctl(t *testing.T) {
+	tests := []struct {
+		name        string
+		output      string
+		wantStatus  string
+		wantMessage string
+	}{
+		{
+			name:       "passed",
+			output:     `{"smart_status":{"passed":true}}`,
+			wantStatus: HealthPassed,
+		},
+		{
+			name:       "failing",
+			output:     `{"smart_status":{"passed":false}}`,
+			wantStatus: HealthFailing,
+		},
+		{
+			name:        "permission denied",
+			output:      `{"smartctl":{"messages":[{"string":"Smartctl open device: /dev/sda failed: Permission denied","severity":"error"}]}}`,
+			wantStatus:  HealthUnknown,
+			wantMessage: "Smartctl open device: /dev/sda failed: Permission denied",
+		},
+		{
+			name:        "garbage",
+			output:      "smartctl: unrecognized option",
+			wantStatus:  HealthUnknown,
+			wantMessage: "unrecognized smartctl output",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseSmartctl(tt.output)
+			if got.Status != tt.wantStatus || got.Message != tt.wantMessage {
+				t.Errorf("parseSmartctl() = %+v, want status %q message %q", got, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestDiskHealthChecker(t *testing.T) {
+	if !HardwareDetection {
+		t.Skip("SMART support is not compiled in")
+	}
+	calls := 0
+	env := toolEnv{
+		lookPath: func(file string) (string, error) { return "/usr/sbin/smartctl", nil },
+		run: func(ctx context.Context, path string, args ...string) (string, error) {
+			calls++
+			return `{"smart_status":{"passed":false}}`, errors.New("exit status 8")
+		},
+	}
+	checker := newDiskHealthChecker(env)
+
+	first := checker.check(context.Background(), "/dev/sda1")
+	second := checker.check(context.Background(), "/dev/sda2")
+
+	if first.Status != HealthFailing || first.Device != "/dev/sda" {
+		t.Errorf("check(/dev/sda1) = %+v, want failing on /dev/sda", first)
+	}
+	if second != first {
+		t.Error("check() should reuse the cached result for partitions of the same disk")
+	}
+	if calls != 1 {
+		t.Errorf("smartctl invoked %d times, want 1", calls)
+	}
+}
+
+func TestDiskHealthChecker_CheckAll(t *testing.T) {
+	if !HardwareDetection {
+		t.Skip("SMART support is not compiled in")
+	}
+	var (
+		mu        sync.Mutex
+		deadlines = map[time.Time]bool{}
+		arrived   atomic.Int32
+		all       = make(chan struct{})
+	)
+	env := toolEnv{
+		lookPath: func(file string) (string, error) { return "/usr/sbin/smartctl", nil },
+		run: func(ctx context.Context, path string, args ...string) (string, error) {
+			deadline, _ := ctx.Deadline()
+			mu.Lock()
+			deadlines[deadline] = true
+			mu.Unlock()
+			// Each query waits for the others: queried one at a time,
+			// the first would never see them.
+			if arrived.Add(1) == 3 {
+				close(all)
+			}
+			select {
+			case <-all:
+				return `{"smart_status":{"passed":true}}`, nil
+			case <-time.After(time.Second):
+				return "", errors.New("disks queried one at a time")
+			}
+		},
+	}
+
+	devices := []string{"/dev/sda1", "/dev/sdb1", "server:/export", "/dev/nvme0n1p2", "/dev/sda2"}
+	got := newDiskHealthChecker(env).checkAll(context.Background(), devices)
+	want := []string{HealthPassed, HealthPassed, HealthUnavailable, HealthPassed, HealthPassed}
+	for i, health := range got {
+		if health.Status != want[i] {
+			t.Errorf("%s = %+v, want %s", devices[i], health, want[i])
+		}
+	}
+	if got[0] != got[4] {
+		t.Error("partitions of the same disk were queried separately")
+	}
+	if len(deadlines) != 1 || deadlines[time.Time{}] {
+		t.Errorf("deadlines = %v, want one shared deadline", deadlines)
+	}
+}
+
+func TestDiskHealthChecker_Degradation(t *testing.T) {
+	missing := toolEnv{
+		lookPath: func(file string) (string, error) { return "", errors.New("not found") },
+	}
+
+	tests := []struct {
+		name   string
+		device string
+		want   string
+	}{
+		{"smartctl missing", "/dev/sda1", HealthUnavailable},
+		{"network filesystem", "server:/export", HealthUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := newDiskHealthChecker(missing).check(context.Background(), tt.device)
+			if got.Status != tt.want {
+				t.Errorf("check(%q) status = %q, want %q", tt.device, got.Status, tt.want)
+			}
+		})
+	}
+}
@@ -3,15 +3,23 @@ package meta
 import (
 	"fmt"
 	"runtime"
+	"runtime/debug"
 )
 
 // Build metadata is set at compile time via -ldflags when available.
 const Name = "ado"
 
+// Default values reported when build metadata is unavailable.
+const (
+	defaultVersion   = "0.0.0-dev"
+	defaultCommit    = "none"
+	defaultBuildTime = "unknown"
+)
+
 var (
-	Version   = "0.0.0-dev"
-	Commit    = "none"
-	BuildTime = "unknown"
+	Version   = defaultVersion
+	Commit    = defaultCommit
+	BuildTime = defaultBuildTime
 )
 
 type BuildInfo struct {
@@ -19,12 +27,20 @@ type BuildInfo struct {
 	Version   string `json:"version" yaml:"version"`
 	Commit    string `json:"commit" yaml:"commit"`
 	BuildTime string `json:"build_time" yaml:"build_time"`
+	Dirty     bool   `json:"dirty" yaml:"dirty"`
 	GoVersion string `json:"go_version" yaml:"go_version"`
 	Platform  string `json:"platform" yaml:"platform"`
+	// LatestVersion and UpdateAvailable come from the cached update check
+	// and are empty/false when no check has run.
+	LatestVersion   string `json:"latest_version,omitempty" yaml:"latest_version,omitempty"`
+	UpdateAvailable bool   `json:"update_available" yaml:"update_available"`
 }
 
+// readBuildInfo is replaceable in tests.
+var readBuildInfo = debug.ReadBuildInfo
+
 func CurrentBuildInfo() BuildInfo {
-	return BuildInfo{
+	info := BuildInfo{
 		Name:      Name,
 		Version:   Version,
 		Commit:    Commit,
@@ -32,4 +48,39 @@ func CurrentBuildInfo() BuildInfo {
 		GoVersion: runtime.Version(),
 		Platform:  fmt.Sprintf("%s/%s", runtime.GOOS, runtime.GOARCH),
 	}
+
+	if bi, ok := readBuildInfo(); ok {
+		applyModuleBuildInfo(&info, bi)
+	}
+
+	return info
+}
+
+// applyModuleBuildInfo fills fields that were not set via -ldflags from the
+// module and VCS metadata embedded by the Go toolchain, so `go install` and
+// plain `go build` binaries still report something truthful. Values set via
+// -ldflags always take precedence.
+func applyModuleBuildInfo(info *BuildInfo, bi *debug.BuildInfo) {
+	if info.Version == defaultVersion && bi.Main.Version != "" && bi.Main.Version != "(devel)" {
+		info.Version = bi.Main.Version
+	}
+
+	settings := map[string]string{}
+	for _, s := range bi.Settings {
+		settings[s.Key] = s.Value
+	}
+
+	if info.Commit == defaultCommit {
+		if rev := settings["vcs.revision"]; rev != "" {
+			info.Commit = rev
+		}
+	}
+	if info.BuildTime == defaultBuildTime {
+		// vcs.time is the commit time; it is the closest reproducible
+		// timestamp available without ldflags.
+		if t := settings["vcs.time"]; t != "" {
+			info.BuildTime = t
+		}
+	}
+	info.Dirty = settings["vcs.modified"] == "true"
 }
@@ -0,0 +1,69 @@
+package meta
+
+import (
+	"runtime/debug"
+	"testing"
+)
+
+func TestApplyModuleBuildInfo(t *testing.T) {
+	vcsSettings := []debug.BuildSetting{
+		{Key: "vcs", Value: "git"},
+		{Key: "vcs.revision", Value: "0123456789abcdef"},
+		{Key: "vcs.time", Value: "2024-05-01T10:00:00Z"},
+		{Key: "vcs.modified", Value: "true"},
+	}
+
+	tests := []struct {
+		name string
+		in   BuildInfo
+		bi   debug.BuildInfo
+		want BuildInfo
+	}{
+		{
+			name: "go install from module proxy",
+			in:   BuildInfo{Version: defaultVersion, Commit: defaultCommit, BuildTime: defaultBuildTime},
+			bi:   debug.BuildInfo{Main: debug.Module{Version: "v1.4.0"}},
+			want: BuildInfo{Version: "v1.4.0", Commit: defaultCommit, BuildTime: defaultBuildTime},
+		},
+		{
+			name: "local build with vcs stamping",
+			in:   BuildInfo{Version: defaultVersion, Commit: defaultCommit, BuildTime: defaultBuildTime},
+			bi:   debug.BuildInfo{Main: debug.Module{Version: "(devel)"}, Settings: vcsSettings},
+			want: BuildInfo{Version: defaultVersion, Commit: "0123456789abcdef", BuildTime: "2024-05-01T10:00:00Z", Dirty: true},
+		},
+		{
+			name: "ldflags take precedence",
+			in:   BuildInfo{Version: "1.2.3", Commit: "abc123", BuildTime: "2024-06-01"},
+			bi:   debug.BuildInfo{Main: debug.Module{Version: "v9.9.9"}, Settings: vcsSettings},
+			want: BuildInfo{Version: "1.2.3", Commit: "abc123", BuildTime: "2024-06-01", Dirty: true},
+		},
+		{
+			name: "no metadata at all",
+			in:   BuildInfo{Version: defaultVersion, Commit: defaultCommit, BuildTime: defaultBuildTime},
+			bi:   debug.BuildInfo{},
+			want: BuildInfo{Version: defaultVersion, Commit: defaultCommit, BuildTime: defaultBuildTime},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.in
+			applyModuleBuildInfo(&got, &tt.bi)
+			if got != tt.want {
+				t.Errorf("applyModuleBuildInfo() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCurrentBuildInfo_FallsBackToModuleInfo(t *testing.T) {
+	orig := readBuildInfo
+	defer func() { readBuildInfo = orig }()
+	readBuildInfo = func() (*debug.BuildInfo, bool) {
+		return &debug.BuildInfo{Main: debug.Module{Version: "v2.0.0"}}, true
+	}
+
+	if got := CurrentBuildInfo(); got.Version != "v2.0.0" || got.Name != Name {
+		t.Errorf("CurrentBuildInfo() = %+v, want version v2.0.0", got)
+	}
+}
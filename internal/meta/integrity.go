@@ -0,0 +1,136 @@
+package meta
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+)
+
+// Release signing identity, set at release build time via -ldflags. Empty
+// values mean the build carries no expected identity.
+var (
+	SigningIdentity   = ""
+	SigningIssuer     = ""
+	MinisignPublicKey = ""
+)
+
+// Digest embedding. A binary cannot contain its own hash, so releases embed
+// the SHA-256 of the binary computed with the digest field zeroed: the
+// post-build step internal/tools/embeddigest patches the placeholder below
+// in place, and VerifyEmbeddedDigest recomputes the hash the same way.
+const (
+	digestPrefix = "<<ado-sha256:"
+	digestSuffix = ">>"
+	digestLen    = sha256.Size * 2
+)
+
+// embeddedDigest must be a single string literal so that its bytes appear
+// verbatim in the binary's read-only data.
+var embeddedDigest = "<<ado-sha256:0000000000000000000000000000000000000000000000000000000000000000>>"
+
+// Digest verification outcomes.
+const (
+	DigestMatch       = "match"
+	DigestMismatch    = "mismatch"
+	DigestNotEmbedded = "not_embedded"
+)
+
+// zeroDigest is the placeholder value of an unpatched build.
+var zeroDigest = bytes.Repeat([]byte("0"), digestLen)
+
+// ErrDigestField is returned when a binary contains no digest field, or
+// more than one candidate.
+var ErrDigestField = errors.New("embedded digest field not found exactly once")
+
+// EmbeddedDigest returns the digest this binary was stamped with, or ""
+// for builds that were not stamped.
+func EmbeddedDigest() string {
+	value := embeddedDigest[len(digestPrefix) : len(digestPrefix)+digestLen]
+	if value == string(zeroDigest) {
+		return ""
+	}
+	return value
+}
+
+// findDigestField returns the offset of the digest value inside data.
+func findDigestField(data []byte) (int, error) {
+	prefix := []byte(digestPrefix)
+	offset := -1
+	for start := 0; ; {
+		i := bytes.Index(data[start:], prefix)
+		if i < 0 {
+			break
+		}
+		pos := start + i + len(prefix)
+		start = pos
+		if pos+digestLen+len(digestSuffix) > len(data) || !isHex(data[pos:pos+digestLen]) ||
+			string(data[pos+digestLen:pos+digestLen+len(digestSuffix)]) != digestSuffix {
+			continue
+		}
+		if offset >= 0 {
+			return 0, ErrDigestField
+		}
+		offset = pos
+	}
+	if offset < 0 {
+		return 0, ErrDigestField
+	}
+	return offset, nil
+}
+
+func isHex(b []byte) bool {
+	for _, c := range b {
+		if !('0' <= c && c <= '9' || 'a' <= c && c <= 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// ComputeDigest returns the digest stored in a binary image and the digest
+// computed over the image with that field zeroed.
+func ComputeDigest(data []byte) (stored, computed string, err error) {
+	offset, err := findDigestField(data)
+	if err != nil {
+		return "", "", err
+	}
+	stored = string(data[offset : offset+digestLen])
+
+	h := sha256.New()
+	h.Write(data[:offset])
+	h.Write(zeroDigest)
+	h.Write(data[offset+digestLen:])
+	return stored, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// StampDigest writes the binary's digest into its digest field in place.
+func StampDigest(data []byte) error {
+	offset, err := findDigestField(data)
+	if err != nil {
+		return err
+	}
+	copy(data[offset:], zeroDigest)
+
+	sum := sha256.Sum256(data)
+	copy(data[offset:], hex.EncodeToString(sum[:]))
+	return nil
+}
+
+// VerifyEmbeddedDigest checks a binary image against its stamped digest and
+// returns one of DigestMatch, DigestMismatch, or DigestNotEmbedded.
+func VerifyEmbeddedDigest(data []byte) (string, error) {
+	stored, computed, err := ComputeDigest(data)
+	if err != nil {
+		return "", fmt.Errorf("verify digest: %w", err)
+	}
+	switch {
+	case stored == string(zeroDigest):
+		return DigestNotEmbedded, nil
+	case stored == computed:
+		return DigestMatch, nil
+	default:
+		return DigestMismatch, nil
+	}
+}
@@ -0,0 +1,79 @@
+package meta
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func fakeBinary(field string) []byte {
+	var b bytes.Buffer
+	b.WriteString("\x7fELF header bytes ")
+	b.WriteString(digestPrefix) // the bare prefix constant also appears in real binaries
+	b.WriteString("other rodata ")
+	b.WriteString(field)
+	b.WriteString(" trailing code")
+	return b.Bytes()
+}
+
+func TestStampAndVerifyDigest(t *testing.T) {
+	data := fakeBinary(embeddedDigest)
+
+	status, err := VerifyEmbeddedDigest(data)
+	if err != nil || status != DigestNotEmbedded {
+		t.Fatalf("unstamped: status = %q, err = %v", status, err)
+	}
+
+	if err := StampDigest(data); err != nil {
+		t.Fatalf("StampDigest() error = %v", err)
+	}
+	status, err = VerifyEmbeddedDigest(data)
+	if err != nil || status != DigestMatch {
+		t.Fatalf("stamped: status = %q, err = %v", status, err)
+	}
+
+	data[len(data)-1] ^= 0xff
+	status, err = VerifyEmbeddedDigest(data)
+	if err != nil || status != DigestMismatch {
+		t.Fatalf("tampered: status = %q, err = %v", status, err)
+	}
+}
+
+func TestStampDigest_Idempotent(t *testing.T) {
+	data := fakeBinary(embeddedDigest)
+	if err := StampDigest(data); err != nil {
+		t.Fatal(err)
+	}
+	first := append([]byte(nil), data...)
+	if err := StampDigest(data); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(first, data) {
+		t.Error("restamping changed the binary")
+	}
+}
+
+func TestFindDigestField_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"missing", []byte("no field here")},
+		{"duplicate", append(fakeBinary(embeddedDigest), embeddedDigest...)},
+		{"truncated", []byte(embeddedDigest[:40])},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := findDigestField(tt.data); !errors.Is(err, ErrDigestField) {
+				t.Errorf("findDigestField() error = %v, want ErrDigestField", err)
+			}
+		})
+	}
+}
+
+func TestEmbeddedDigest_Unstamped(t *testing.T) {
+	if got := EmbeddedDigest(); got != "" {
+		t.Errorf("EmbeddedDigest() = %q, want empty for test binary", got)
+	}
+}
@@ -0,0 +1,462 @@
+package meta
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// K8sInfo represents the Kubernetes cluster ado is pointed at.
+type K8sInfo struct {
+	// Kubeconfig lists the kubeconfig files read, in $KUBECONFIG order.
+	Kubeconfig []string `json:"kubeconfig" yaml:"kubeconfig"`
+	// InCluster is set when ado runs in a pod with a service account.
+	InCluster bool   `json:"in_cluster" yaml:"in_cluster"`
+	Context   string `json:"context" yaml:"context"`
+	Cluster   string `json:"cluster,omitempty" yaml:"cluster,omitempty"`
+	User      string `json:"user,omitempty" yaml:"user,omitempty"`
+	Namespace string `json:"namespace" yaml:"namespace"`
+	Server    string `json:"server,omitempty" yaml:"server,omitempty"`
+	// ServerVersion is the API server's version; empty when unreachable.
+	ServerVersion string `json:"server_version,omitempty" yaml:"server_version,omitempty"`
+	Platform      string `json:"platform,omitempty" yaml:"platform,omitempty"`
+	Reachable     bool   `json:"reachable" yaml:"reachable"`
+	// ClientVersion is the version of kubectl on PATH, if any.
+	ClientVersion string   `json:"client_version,omitempty" yaml:"client_version,omitempty"`
+	Skew          *K8sSkew `json:"skew,omitempty" yaml:"skew,omitempty"`
+	Node          *K8sNode `json:"node,omitempty" yaml:"node,omitempty"`
+	Error         string   `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// K8sSkew compares the kubectl and API server minor versions. kubectl
+// supports servers one minor version older or newer than itself.
+type K8sSkew struct {
+	Minors    int  `json:"minors" yaml:"minors"` // server minus client
+	Supported bool `json:"supported" yaml:"supported"`
+}
+
+// K8sNode is the node an in-cluster pod is scheduled on.
+type K8sNode struct {
+	Name string `json:"name" yaml:"name"`
+	// Allocatable is the node's schedulable capacity, such as cpu, memory,
+	// and pods, in Kubernetes quantity notation.
+	Allocatable map[string]string `json:"allocatable,omitempty" yaml:"allocatable,omitempty"`
+	Error       string            `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// DefaultK8sTimeout bounds the API server queries.
+const DefaultK8sTimeout = 3 * time.Second
+
+// serviceAccountDir holds the credentials Kubernetes mounts into pods.
+const serviceAccountDir = "/var/run/secrets/kubernetes.io/serviceaccount"
+
+// inClusterContext is the context name reported for service account
+// credentials.
+const inClusterContext = "in-cluster"
+
+// minorPattern matches the leading digits of a minor version such as
+// "30+" (as reported by EKS and GKE).
+var minorPattern = regexp.MustCompile(`^\d+`)
+
+// k8sProbe holds the inputs of Kubernetes detection; fields are
+// overridable for tests.
+type k8sProbe struct {
+	getenv   func(string) string
+	homeDir  string
+	saDir    string
+	hostname func() (string, error)
+	kubectl  func(ctx context.Context) ToolInfo
+}
+
+func newK8sProbe() k8sProbe {
+	home, _ := os.UserHomeDir()
+	var kubectl toolProbe
+	for _, probe := range defaultToolProbes {
+		if probe.Name == "kubectl" {
+			kubectl = probe
+		}
+	}
+	return k8sProbe{
+		getenv:   os.Getenv,
+		homeDir:  home,
+		saDir:    serviceAccountDir,
+		hostname: os.Hostname,
+		kubectl: func(ctx context.Context) ToolInfo {
+			return probeTool(ctx, defaultToolEnv(), kubectl, DefaultToolTimeout)
+		},
+	}
+}
+
+// CollectK8sInfo reports the current kubeconfig context, or the pod's
+// service account when there is none, and queries the API server for its
+// version within timeout. In a pod it also reports the allocatable
+// resources of the pod's node, which needs RBAC permission to get pods
+// and nodes. Failures are reported in the Error fields.
+func CollectK8sInfo(ctx context.Context, timeout time.Duration) K8sInfo {
+	return newK8sProbe().collect(ctx, timeout)
+}
+
+// k8sCluster is how to reach and authenticate to an API server.
+type k8sCluster struct {
+	server   string
+	caData   []byte
+	insecure bool
+	token    string
+	certData []byte
+	keyData  []byte
+}
+
+func (p k8sProbe) collect(ctx context.Context, timeout time.Duration) K8sInfo {
+	if timeout <= 0 {
+		timeout = DefaultK8sTimeout
+	}
+	info := K8sInfo{Namespace: "default"}
+	_, err := os.Stat(filepath.Join(p.saDir, "token"))
+	info.InCluster = err == nil && p.getenv("KUBERNETES_SERVICE_HOST") != ""
+
+	cluster, err := p.fromKubeconfig(&info)
+	if err == nil && cluster == nil && info.InCluster {
+		cluster, err = p.fromServiceAccount(&info)
+	}
+	switch {
+	case err != nil:
+		info.Error = err.Error()
+	case cluster == nil:
+		info.Error = "no current context; set one with kubectl config use-context"
+	}
+
+	if kubectl := p.kubectl(ctx); kubectl.Found {
+		info.ClientVersion = kubectl.Version
+	}
+	if cluster == nil {
+		return info
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	client, err := cluster.client()
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	if err := p.queryVersion(ctx, client, cluster, &info); err != nil {
+		slog.DebugContext(ctx, "Kubernetes API server unreachable", "server", cluster.server, "error", err)
+		info.Error = err.Error()
+		return info
+	}
+	info.Skew = versionSkew(info.ClientVersion, info.ServerVersion)
+
+	if info.InCluster && info.Context == inClusterContext {
+		info.Node = p.node(ctx, client, cluster, info.Namespace)
+	}
+	return info
+}
+
+// kubeconfigFile is the subset of the kubeconfig format read.
+type kubeconfigFile struct {
+	CurrentContext string `yaml:"current-context"`
+	Clusters       []struct {
+		Name    string `yaml:"name"`
+		Cluster struct {
+			Server                   string `yaml:"server"`
+			CertificateAuthority     string `yaml:"certificate-authority"`
+			CertificateAuthorityData string `yaml:"certificate-authority-data"`
+			InsecureSkipTLSVerify    bool   `yaml:"insecure-skip-tls-verify"`
+		} `yaml:"cluster"`
+	} `yaml:"clusters"`
+	Contexts []struct {
+		Name    string `yaml:"name"`
+		Context struct {
+			Cluster   string `yaml:"cluster"`
+			User      string `yaml:"user"`
+			Namespace string `yaml:"namespace"`
+		} `yaml:"context"`
+	} `yaml:"contexts"`
+	Users []struct {
+		Name string `yaml:"name"`
+		User struct {
+			Token                 string `yaml:"token"`
+			ClientCertificate     string `yaml:"client-certificate"`
+			ClientCertificateData string `yaml:"client-certificate-data"`
+			ClientKey             string `yaml:"client-key"`
+			ClientKeyData         string `yaml:"client-key-data"`
+		} `yaml:"user"`
+	} `yaml:"users"`
+}
+
+// fromKubeconfig resolves the current context of the kubeconfig files
+// named by $KUBECONFIG, or ~/.kube/config, merged as kubectl does: the
+// first file to set a value wins. It returns nil without error when
+// there is no current context.
+func (p k8sProbe) fromKubeconfig(info *K8sInfo) (*k8sCluster, error) {
+	var paths []string
+	if env := p.getenv("KUBECONFIG"); env != "" {
+		paths = filepath.SplitList(env)
+	} else if p.homeDir != "" {
+		paths = []string{filepath.Join(p.homeDir, ".kube", "config")}
+	}
+
+	var files []kubeconfigFile
+	var dirs []string
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read kubeconfig: %w", err)
+		}
+		var f kubeconfigFile
+		if err := yaml.Unmarshal(data, &f); err != nil {
+			return nil, fmt.Errorf("parse kubeconfig %s: %w", path, err)
+		}
+		info.Kubeconfig = append(info.Kubeconfig, path)
+		files = append(files, f)
+		dirs = append(dirs, filepath.Dir(path))
+	}
+
+	for _, f := range files {
+		if f.CurrentContext != "" {
+			info.Context = f.CurrentContext
+			break
+		}
+	}
+	if info.Context == "" {
+		return nil, nil
+	}
+
+	found := false
+	for _, f := range files {
+		for _, c := range f.Contexts {
+			if c.Name == info.Context && !found {
+				found = true
+				info.Cluster, info.User = c.Context.Cluster, c.Context.User
+				if c.Context.Namespace != "" {
+					info.Namespace = c.Context.Namespace
+				}
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("current context %q is not defined in the kubeconfig", info.Context)
+	}
+
+	cluster := &k8sCluster{}
+	found = false
+	for i, f := range files {
+		for _, c := range f.Clusters {
+			if c.Name != info.Cluster || found {
+				continue
+			}
+			found = true
+			cluster.server = c.Cluster.Server
+			cluster.insecure = c.Cluster.InsecureSkipTLSVerify
+			var err error
+			if cluster.caData, err = fileOrData(dirs[i], c.Cluster.CertificateAuthority, c.Cluster.CertificateAuthorityData); err != nil {
+				return nil, fmt.Errorf("cluster %q: certificate authority: %w", c.Name, err)
+			}
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("cluster %q of context %q is not defined in the kubeconfig", info.Cluster, info.Context)
+	}
+	info.Server = cluster.server
+
+	// Exec and auth-provider credentials are not run; /version is readable
+	// without credentials on most clusters.
+	found = false
+	for i, f := range files {
+		for _, u := range f.Users {
+			if u.Name != info.User || found {
+				continue
+			}
+			found = true
+			cluster.token = u.User.Token
+			var err error
+			if cluster.certData, err = fileOrData(dirs[i], u.User.ClientCertificate, u.User.ClientCertificateData); err != nil {
+				return nil, fmt.Errorf("user %q: client certificate: %w", u.Name, err)
+			}
+			if cluster.keyData, err = fileOrData(dirs[i], u.User.ClientKey, u.User.ClientKeyData); err != nil {
+				return nil, fmt.Errorf("user %q: client key: %w", u.Name, err)
+			}
+		}
+	}
+	return cluster, nil
+}
+
+// fromServiceAccount returns the in-cluster API server and the pod's
+// service account credentials.
+func (p k8sProbe) fromServiceAccount(info *K8sInfo) (*k8sCluster, error) {
+	token, err := os.ReadFile(filepath.Join(p.saDir, "token"))
+	if err != nil {
+		return nil, fmt.Errorf("read service account token: %w", err)
+	}
+	ca, err := os.ReadFile(filepath.Join(p.saDir, "ca.crt"))
+	if err != nil {
+		return nil, fmt.Errorf("read service account CA: %w", err)
+	}
+	if ns, err := os.ReadFile(filepath.Join(p.saDir, "namespace")); err == nil {
+		info.Namespace = strings.TrimSpace(string(ns))
+	}
+	port := p.getenv("KUBERNETES_SERVICE_PORT")
+	if port == "" {
+		port = "443"
+	}
+	info.Context = inClusterContext
+	info.Server = "https://" + net.JoinHostPort(p.getenv("KUBERNETES_SERVICE_HOST"), port)
+	return &k8sCluster{server: info.Server, caData: ca, token: strings.TrimSpace(string(token))}, nil
+}
+
+// fileOrData returns inline base64 data, or the contents of path relative
+// to the kubeconfig's directory.
+func fileOrData(dir, path, data string) ([]byte, error) {
+	if data != "" {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	if path == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return os.ReadFile(path)
+}
+
+func (c *k8sCluster) client() (*http.Client, error) {
+	// insecure-skip-tls-verify in the kubeconfig is honored as kubectl does.
+	tlsConfig := &tls.Config{InsecureSkipVerify: c.insecure}
+	if len(c.caData) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(c.caData) {
+			return nil, errors.New("certificate authority has no PEM certificates")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(c.certData) > 0 && len(c.keyData) > 0 {
+		cert, err := tls.X509KeyPair(c.certData, c.keyData)
+		if err != nil {
+			return nil, fmt.Errorf("client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig, Proxy: http.ProxyFromEnvironment}}, nil
+}
+
+func (p k8sProbe) queryVersion(ctx context.Context, client *http.Client, cluster *k8sCluster, info *K8sInfo) error {
+	var version struct {
+		GitVersion string `json:"gitVersion"`
+		Platform   string `json:"platform"`
+	}
+	if err := k8sGet(ctx, client, cluster, "/version", &version); err != nil {
+		return err
+	}
+	info.Reachable = true
+	info.ServerVersion = strings.TrimPrefix(version.GitVersion, "v")
+	info.Platform = version.Platform
+	return nil
+}
+
+// node reports the allocatable resources of the node the pod runs on.
+// The pod is found by its hostname, which Kubernetes sets to the pod name.
+func (p k8sProbe) node(ctx context.Context, client *http.Client, cluster *k8sCluster, namespace string) *K8sNode {
+	node := &K8sNode{Name: p.getenv("NODE_NAME")}
+	if node.Name == "" {
+		pod, err := p.hostname()
+		if err != nil {
+			node.Error = err.Error()
+			return node
+		}
+		var spec struct {
+			Spec struct {
+				NodeName string `json:"nodeName"`
+			} `json:"spec"`
+		}
+		if err := k8sGet(ctx, client, cluster, "/api/v1/namespaces/"+url.PathEscape(namespace)+"/pods/"+url.PathEscape(pod), &spec); err != nil {
+			node.Error = err.Error()
+			return node
+		}
+		node.Name = spec.Spec.NodeName
+	}
+
+	var status struct {
+		Status struct {
+			Allocatable map[string]string `json:"allocatable"`
+		} `json:"status"`
+	}
+	if err := k8sGet(ctx, client, cluster, "/api/v1/nodes/"+url.PathEscape(node.Name), &status); err != nil {
+		node.Error = err.Error()
+		return node
+	}
+	node.Allocatable = status.Status.Allocatable
+	return node
+}
+
+func k8sGet(ctx context.Context, client *http.Client, cluster *k8sCluster, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(cluster.server, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if cluster.token != "" {
+		req.Header.Set("Authorization", "Bearer "+cluster.token)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		var status struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &status) == nil && status.Message != "" {
+			return fmt.Errorf("GET %s: %s: %s", path, resp.Status, status.Message)
+		}
+		return fmt.Errorf("GET %s: %s", path, resp.Status)
+	}
+	return json.Unmarshal(body, out)
+}
+
+// versionSkew compares the minor versions of client and server, such as
+// 1.31.0 and 1.30.2-eks-1234; nil when either is unknown or the major
+// versions differ.
+func versionSkew(client, server string) *K8sSkew {
+	cMajor, cMinor, ok1 := majorMinor(client)
+	sMajor, sMinor, ok2 := majorMinor(server)
+	if !ok1 || !ok2 || cMajor != sMajor {
+		return nil
+	}
+	minors := sMinor - cMinor
+	return &K8sSkew{Minors: minors, Supported: minors >= -1 && minors <= 1}
+}
+
+func majorMinor(version string) (major, minor int, ok bool) {
+	parts := strings.SplitN(strings.TrimPrefix(version, "v"), ".", 3)
+	if len(parts) < 2 {
+		return 0, 0, false
+	}
+	major, err1 := strconv.Atoi(parts[0])
+	minor, err2 := strconv.Atoi(minorPattern.FindString(parts[1]))
+	return major, minor, err1 == nil && err2 == nil
+}
@@ -0,0 +1,170 @@
+package meta
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// fakeAPIServer serves /version and, for requests with the token, the
+// pod and node lookups.
+func fakeAPIServer(t *testing.T, token string) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/version" && r.Header.Get("Authorization") != "Bearer "+token {
+			w.WriteHeader(http.StatusForbidden)
+			json.NewEncoder(w).Encode(map[string]string{"message": "forbidden"})
+			return
+		}
+		switch r.URL.Path {
+		case "/version":
+			json.NewEncoder(w).Encode(map[string]string{"gitVersion": "v1.29.4-eks-036c24b", "platform": "linux/amd64"})
+		case "/api/v1/namespaces/build/pods/runner-7f9c":
+			json.NewEncoder(w).Encode(map[string]any{"spec": map[string]string{"nodeName": "ip-10-0-1-5"}})
+		case "/api/v1/nodes/ip-10-0-1-5":
+			json.NewEncoder(w).Encode(map[string]any{"status": map[string]any{"allocatable": map[string]string{"cpu": "3920m", "memory": "15186Mi", "pods": "58"}}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func caPEM(srv *httptest.Server) []byte {
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+}
+
+func testK8sProbe(env map[string]string, kubectlVersion string) k8sProbe {
+	return k8sProbe{
+		getenv:   func(key string) string { return env[key] },
+		saDir:    filepath.Join(os.TempDir(), "ado-no-such-serviceaccount"),
+		hostname: func() (string, error) { return "runner-7f9c", nil },
+		kubectl: func(context.Context) ToolInfo {
+			return ToolInfo{Name: "kubectl", Found: kubectlVersion != "", Version: kubectlVersion}
+		},
+	}
+}
+
+func TestK8sProbe_Kubeconfig(t *testing.T) {
+	srv := fakeAPIServer(t, "")
+	dir := t.TempDir()
+	// The context lives in the second file; the first sets current-context.
+	first := filepath.Join(dir, "config")
+	second := filepath.Join(dir, "staging.yaml")
+	os.WriteFile(first, []byte("current-context: staging\n"), 0o600)
+	os.WriteFile(second, []byte(`clusters:
+- name: eks-staging
+  cluster:
+    server: `+srv.URL+`
+    certificate-authority-data: `+base64.StdEncoding.EncodeToString(caPEM(srv))+`
+contexts:
+- name: staging
+  context: {cluster: eks-staging, user: deployer, namespace: web}
+users:
+- name: deployer
+  user:
+    exec: {command: aws}
+`), 0o600)
+
+	p := testK8sProbe(map[string]string{"KUBECONFIG": first + string(os.PathListSeparator) + filepath.Join(dir, "missing") + string(os.PathListSeparator) + second}, "1.31.2")
+	info := p.collect(context.Background(), 0)
+
+	if info.Error != "" {
+		t.Fatalf("Error = %q", info.Error)
+	}
+	if info.Context != "staging" || info.Cluster != "eks-staging" || info.User != "deployer" || info.Namespace != "web" || info.Server != srv.URL {
+		t.Errorf("context = %+v", info)
+	}
+	if len(info.Kubeconfig) != 2 {
+		t.Errorf("Kubeconfig = %v", info.Kubeconfig)
+	}
+	if !info.Reachable || info.ServerVersion != "1.29.4-eks-036c24b" || info.Platform != "linux/amd64" {
+		t.Errorf("server = %+v", info)
+	}
+	if info.Skew == nil || info.Skew.Minors != -2 || info.Skew.Supported {
+		t.Errorf("Skew = %+v, want -2 unsupported", info.Skew)
+	}
+	if info.InCluster || info.Node != nil {
+		t.Errorf("InCluster = %v, Node = %+v", info.InCluster, info.Node)
+	}
+}
+
+func TestK8sProbe_InCluster(t *testing.T) {
+	srv := fakeAPIServer(t, "sa-token")
+	saDir := t.TempDir()
+	os.WriteFile(filepath.Join(saDir, "token"), []byte("sa-token\n"), 0o600)
+	os.WriteFile(filepath.Join(saDir, "ca.crt"), caPEM(srv), 0o600)
+	os.WriteFile(filepath.Join(saDir, "namespace"), []byte("build"), 0o600)
+
+	host, port, _ := strings.Cut(strings.TrimPrefix(srv.URL, "https://"), ":")
+	p := testK8sProbe(map[string]string{"KUBERNETES_SERVICE_HOST": host, "KUBERNETES_SERVICE_PORT": port}, "")
+	p.saDir = saDir
+	p.homeDir = t.TempDir()
+	info := p.collect(context.Background(), 0)
+
+	if !info.InCluster || info.Context != inClusterContext || info.Namespace != "build" || !info.Reachable {
+		t.Fatalf("info = %+v", info)
+	}
+	if info.Skew != nil {
+		t.Errorf("Skew = %+v without kubectl", info.Skew)
+	}
+	if info.Node == nil || info.Node.Name != "ip-10-0-1-5" || info.Node.Allocatable["memory"] != "15186Mi" || info.Node.Error != "" {
+		t.Errorf("Node = %+v", info.Node)
+	}
+}
+
+func TestK8sProbe_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		kubeconfig string
+		want       string
+	}{
+		{"no kubeconfig", "", "no current context"},
+		{"no current context", "clusters: []\n", "no current context"},
+		{"undefined context", "current-context: prod\n", `current context "prod" is not defined`},
+		{"undefined cluster", "current-context: prod\ncontexts:\n- name: prod\n  context: {cluster: gke-prod}\n", `cluster "gke-prod" of context "prod" is not defined`},
+		{"unreachable", "current-context: prod\ncontexts:\n- name: prod\n  context: {cluster: c}\nclusters:\n- name: c\n  cluster: {server: 'https://127.0.0.1:1'}\n", "connect"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			home := t.TempDir()
+			if tt.kubeconfig != "" {
+				os.MkdirAll(filepath.Join(home, ".kube"), 0o755)
+				os.WriteFile(filepath.Join(home, ".kube", "config"), []byte(tt.kubeconfig), 0o600)
+			}
+			p := testK8sProbe(nil, "")
+			p.homeDir = home
+			info := p.collect(context.Background(), 0)
+			if info.Reachable || !strings.Contains(info.Error, tt.want) {
+				t.Errorf("Error = %q, want it to contain %q", info.Error, tt.want)
+			}
+		})
+	}
+}
+
+func TestVersionSkew(t *testing.T) {
+	tests := []struct {
+		client, server string
+		want           *K8sSkew
+	}{
+		{"1.31.0", "1.30.2", &K8sSkew{Minors: -1, Supported: true}},
+		{"1.29.1", "1.31.0+k3s1", &K8sSkew{Minors: 2, Supported: false}},
+		{"v1.30.0", "1.30+", &K8sSkew{Minors: 0, Supported: true}},
+		{"", "1.30.0", nil},
+		{"2.0.0", "1.30.0", nil},
+	}
+	for _, tt := range tests {
+		got := versionSkew(tt.client, tt.server)
+		if (got == nil) != (tt.want == nil) || (got != nil && *got != *tt.want) {
+			t.Errorf("versionSkew(%q, %q) = %+v, want %+v", tt.client, tt.server, got, tt.want)
+		}
+	}
+}
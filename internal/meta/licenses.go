@@ -0,0 +1,64 @@
+package meta
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+//go:generate go run ../tools/genlicenses -o licenses.json
+
+// licensesJSON is the license inventory of the modules compiled into ado,
+// produced at build time by internal/tools/genlicenses.
+//
+//go:embed licenses.json
+var licensesJSON []byte
+
+// LicenseInfo represents the license of a module compiled into ado.
+type LicenseInfo struct {
+	Path    string `json:"path" yaml:"path"`
+	Version string `json:"version" yaml:"version"`
+	// License is an SPDX expression, or "Unknown" when the license text
+	// could not be classified.
+	License     string `json:"license" yaml:"license"`
+	LicenseFile string `json:"license_file,omitempty" yaml:"license_file,omitempty"`
+}
+
+// LicenseGroup lists the modules distributed under a single license.
+type LicenseGroup struct {
+	License string   `json:"license" yaml:"license"`
+	Modules []string `json:"modules" yaml:"modules"`
+}
+
+// CollectLicenses returns the embedded license inventory.
+func CollectLicenses() ([]LicenseInfo, error) {
+	return parseLicenses(licensesJSON)
+}
+
+func parseLicenses(data []byte) ([]LicenseInfo, error) {
+	licenses := []LicenseInfo{}
+	if err := json.Unmarshal(data, &licenses); err != nil {
+		return nil, fmt.Errorf("parse embedded license inventory: %w", err)
+	}
+	return licenses, nil
+}
+
+// GroupLicenses groups modules by SPDX expression, sorted by expression.
+// Module paths within a group keep their input order.
+func GroupLicenses(licenses []LicenseInfo) []LicenseGroup {
+	index := map[string]int{}
+	groups := []LicenseGroup{}
+	for _, l := range licenses {
+		i, ok := index[l.License]
+		if !ok {
+			i = len(groups)
+			index[l.License] = i
+			groups = append(groups, LicenseGroup{License: l.License})
+		}
+		groups[i].Modules = append(groups[i].Modules, l.Path)
+	}
+
+	sort.Slice(groups, func(i, j int) bool { return groups[i].License < groups[j].License })
+	return groups
+}
@@ -0,0 +1,52 @@
+package meta
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCollectLicenses_Embedded(t *testing.T) {
+	licenses, err := CollectLicenses()
+	if err != nil {
+		t.Fatalf("CollectLicenses() error = %v", err)
+	}
+	if len(licenses) == 0 {
+		t.Fatal("embedded license inventory is empty; run go generate ./internal/meta")
+	}
+
+	for _, l := range licenses {
+		if l.Path == "" || l.License == "" {
+			t.Errorf("incomplete entry: %+v", l)
+		}
+		if l.License == "Unknown" {
+			t.Errorf("module %s has an unclassified license; review it and extend genlicenses", l.Path)
+		}
+	}
+}
+
+func TestParseLicenses_Invalid(t *testing.T) {
+	if _, err := parseLicenses([]byte("not json")); err == nil {
+		t.Error("parseLicenses() expected error for invalid JSON")
+	}
+}
+
+func TestGroupLicenses(t *testing.T) {
+	licenses := []LicenseInfo{
+		{Path: "github.com/spf13/cobra", License: "Apache-2.0"},
+		{Path: "github.com/spf13/pflag", License: "BSD-3-Clause"},
+		{Path: "github.com/jaypipes/ghw", License: "Apache-2.0"},
+	}
+
+	got := GroupLicenses(licenses)
+	want := []LicenseGroup{
+		{License: "Apache-2.0", Modules: []string{"github.com/spf13/cobra", "github.com/jaypipes/ghw"}},
+		{License: "BSD-3-Clause", Modules: []string{"github.com/spf13/pflag"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GroupLicenses() = %+v, want %+v", got, want)
+	}
+
+	if got := GroupLicenses(nil); got == nil || len(got) != 0 {
+		t.Errorf("GroupLicenses(nil) = %#v, want empty slice", got)
+	}
+}
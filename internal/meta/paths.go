@@ -0,0 +1,103 @@
+package meta
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/anowarislam/ado/internal/config"
+)
+
+// Directory kinds reported by CollectPaths.
+const (
+	PathConfig = "config"
+	PathCache  = "cache"
+	PathState  = "state"
+	PathData   = "data"
+	PathLog    = "log"
+	PathPlugin = "plugin"
+)
+
+// PathInfo describes a directory, or an explicit config file, ado reads
+// from or writes to.
+type PathInfo struct {
+	Kind        string `json:"kind" yaml:"kind"`
+	Path        string `json:"path" yaml:"path"`
+	Description string `json:"description" yaml:"description"`
+	Exists      bool   `json:"exists" yaml:"exists"`
+	// SizeBytes is the total size of regular files below Path, excluding
+	// nested directories that are reported separately.
+	SizeBytes int64 `json:"size_bytes" yaml:"size_bytes"`
+}
+
+// CollectPaths reports the directories ado uses with existence and size.
+func CollectPaths(explicitConfig string) []PathInfo {
+	homeDir, _ := os.UserHomeDir()
+	return collectPaths(explicitConfig, homeDir, config.ResolveDirs())
+}
+
+// collectPaths lists every directory ado uses. Config directories are the
+// ones on the config search path; an explicit config file is listed
+// itself, since its directory may be anything, such as the home directory.
+func collectPaths(explicitConfig, homeDir string, dirs config.Dirs) []PathInfo {
+	var infos []PathInfo
+	seen := map[string]bool{}
+	add := func(kind, path, description string) {
+		if path == "" || seen[path] {
+			return
+		}
+		seen[path] = true
+		infos = append(infos, PathInfo{Kind: kind, Path: path, Description: description})
+	}
+
+	if explicitConfig != "" {
+		add(PathConfig, explicitConfig, "configuration file (explicit --config/ADO_CONFIG)")
+	}
+	for _, source := range config.DefaultSearchPaths(homeDir) {
+		add(PathConfig, filepath.Dir(source), "configuration files; back up")
+	}
+
+	add(PathCache, dirs.Cache, "cached data (update checks); safe to delete")
+	add(PathState, dirs.State, "state (run history, snapshots); back up to keep history")
+	add(PathData, dirs.Data, "persistent data; back up")
+	add(PathLog, dirs.Log, "log files; safe to delete")
+	add(PathPlugin, dirs.Plugin, "installed plugins; reinstallable")
+
+	for i := range infos {
+		infos[i].Exists, infos[i].SizeBytes = dirUsage(infos[i].Path, seen)
+	}
+	return infos
+}
+
+// dirUsage reports whether path exists and the total size of the regular
+// files below it, skipping nested directories listed in exclude.
+// Unreadable entries are ignored so a partial size is still reported.
+func dirUsage(path string, exclude map[string]bool) (bool, int64) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, 0
+	}
+	if !info.IsDir() {
+		return true, info.Size()
+	}
+
+	var size int64
+	_ = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if p != path && exclude[p] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.Type().IsRegular() {
+			if fi, err := d.Info(); err == nil {
+				size += fi.Size()
+			}
+		}
+		return nil
+	})
+	return true, size
+}
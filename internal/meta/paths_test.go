@@ -0,0 +1,64 @@
+package meta
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/anowarislam/ado/internal/config"
+)
+
+func TestCollectPaths(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", "")
+	state := filepath.Join(home, ".local", "state", "ado")
+	dirs := config.Dirs{
+		Cache:  filepath.Join(home, ".cache", "ado"),
+		State:  state,
+		Data:   filepath.Join(home, ".local", "share", "ado"),
+		Log:    filepath.Join(state, "logs"),
+		Plugin: filepath.Join(home, ".local", "share", "ado", "plugins"),
+	}
+	if err := os.MkdirAll(filepath.Join(state, "logs"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(state, "history.db"), make([]byte, 100), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(state, "logs", "run.log"), make([]byte, 40), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	byKind := map[string][]PathInfo{}
+	// An explicit config file in the home directory is sized alone, not
+	// with everything else in the home directory.
+	explicit := filepath.Join(home, "ado.yaml")
+	if err := os.WriteFile(explicit, make([]byte, 7), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range collectPaths(explicit, home, dirs) {
+		byKind[p.Kind] = append(byKind[p.Kind], p)
+	}
+
+	if got := len(byKind[PathConfig]); got != 3 {
+		t.Errorf("config dirs = %d, want explicit + 2 search dirs: %#v", got, byKind[PathConfig])
+	} else if c := byKind[PathConfig][0]; c.Path != explicit || !c.Exists || c.SizeBytes != 7 {
+		t.Errorf("first config path = %+v, want explicit %s with 7 bytes", c, explicit)
+	}
+
+	for _, kind := range []string{PathCache, PathState, PathData, PathLog, PathPlugin} {
+		if len(byKind[kind]) != 1 {
+			t.Fatalf("%s dirs = %#v, want one", kind, byKind[kind])
+		}
+	}
+
+	if s := byKind[PathState][0]; !s.Exists || s.SizeBytes != 100 {
+		t.Errorf("state = %+v, want exists with 100 bytes (logs excluded)", s)
+	}
+	if l := byKind[PathLog][0]; !l.Exists || l.SizeBytes != 40 {
+		t.Errorf("log = %+v, want exists with 40 bytes", l)
+	}
+	if c := byKind[PathCache][0]; c.Exists || c.SizeBytes != 0 {
+		t.Errorf("cache = %+v, want missing", c)
+	}
+}
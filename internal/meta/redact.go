@@ -0,0 +1,108 @@
+package meta
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redacted replaces values that look like secrets.
+const Redacted = "[REDACTED]"
+
+// secretNameParts mark variable names whose values are likely secrets.
+var secretNameParts = []string{"TOKEN", "SECRET", "PASSWORD", "PASSWD", "KEY", "CREDENTIAL", "AUTH"}
+
+// secretValuePatterns match well-known credential formats regardless of
+// the variable name they appear in.
+var secretValuePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\b(ghp|gho|ghu|ghs|ghr)_[A-Za-z0-9]{20,}`), // GitHub tokens
+	regexp.MustCompile(`\bgithub_pat_[A-Za-z0-9_]{20,}`),
+	regexp.MustCompile(`\bglpat-[A-Za-z0-9_-]{20,}`),             // GitLab
+	regexp.MustCompile(`\bxox[abprs]-[A-Za-z0-9-]{10,}`),         // Slack
+	regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`),            // AWS access key IDs
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_-]{20,}`),                // API secret keys
+	regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9._~+/=-]{10,}`), // Authorization headers
+}
+
+// IsSecretEnvName reports whether a variable name suggests a secret value.
+func IsSecretEnvName(name string) bool {
+	upper := strings.ToUpper(name)
+	for _, part := range secretNameParts {
+		if strings.Contains(upper, part) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactEnvValue masks secrets in an environment variable: the whole value
+// when the name suggests a secret, otherwise URL passwords (e.g. in proxy
+// settings) and well-known token formats embedded in the value.
+func RedactEnvValue(name, value string) string {
+	if value == "" {
+		return value
+	}
+	if IsSecretEnvName(name) {
+		return Redacted
+	}
+
+	value = redactURLPasswords(value)
+	for _, pattern := range secretValuePatterns {
+		value = pattern.ReplaceAllString(value, Redacted)
+	}
+	return value
+}
+
+// RedactArgs returns a copy of command-line args with secrets masked: the
+// value of a flag whose name suggests a secret (--token=X or --token X),
+// the value of a NAME=VALUE argument named like one, and URL passwords and
+// well-known token formats anywhere else.
+func RedactArgs(args []string) []string {
+	out := make([]string, len(args))
+	secretFlag := false
+	for i, arg := range args {
+		switch {
+		case secretFlag:
+			out[i] = Redacted
+			secretFlag = false
+		case strings.HasPrefix(arg, "-") && arg != "-" && arg != "--":
+			name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+			switch {
+			case !IsSecretEnvName(name):
+				out[i] = RedactEnvValue("", arg)
+			case hasValue:
+				out[i] = arg[:len(arg)-len(value)] + Redacted
+			default:
+				out[i] = arg
+				secretFlag = true
+			}
+		default:
+			name, value, ok := strings.Cut(arg, "=")
+			if ok && IsSecretEnvName(name) {
+				out[i] = name + "=" + RedactEnvValue(name, value)
+			} else {
+				out[i] = RedactEnvValue("", arg)
+			}
+		}
+	}
+	return out
+}
+
+// urlCandidate matches URL-like substrings with a userinfo component.
+var urlCandidate = regexp.MustCompile(`[A-Za-z][A-Za-z0-9+.-]*://[^\s,;@/]+@[^\s,;]*`)
+
+// redactURLPasswords masks the password of every URL with userinfo,
+// keeping the user name so misconfigured accounts remain diagnosable.
+func redactURLPasswords(value string) string {
+	return urlCandidate.ReplaceAllStringFunc(value, func(raw string) string {
+		u, err := url.Parse(raw)
+		if err != nil || u.User == nil {
+			return raw
+		}
+		if _, hasPassword := u.User.Password(); !hasPassword {
+			return raw
+		}
+		userinfo := u.User.Username() + ":" + Redacted
+		return strings.Replace(raw, u.User.String(), userinfo, 1)
+	})
+}
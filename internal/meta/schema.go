@@ -0,0 +1,21 @@
+package meta
+
+import (
+	"fmt"
+
+	"github.com/anowarislam/ado/internal/schema"
+)
+
+// SystemInfoSchemaVersion identifies the shape of the SystemInfo payload.
+// Bump it whenever fields are added, removed, renamed, or change type so
+// downstream consumers can detect which schema a given output follows.
+const SystemInfoSchemaVersion = 3
+
+// SystemInfoSchema returns the JSON Schema describing `ado meta system` output.
+func SystemInfoSchema() *schema.Schema {
+	return schema.Generate(SystemInfo{}, schema.Options{
+		ID:      fmt.Sprintf("https://github.com/anowarislam/ado/schemas/system-info/v%d.json", SystemInfoSchemaVersion),
+		Title:   "ado meta system",
+		Version: SystemInfoSchemaVersion,
+	})
+}
@@ -0,0 +1,40 @@
+package meta
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// TestSystemInfoSchema_MatchesOutput guards against the schema drifting from
+// the actual JSON payload: every emitted top-level key must be described and
+// every required key must be emitted.
+func TestSystemInfoSchema_MatchesOutput(t *testing.T) {
+	s := SystemInfoSchema()
+	if s.Version != SystemInfoSchemaVersion {
+		t.Errorf("schema version = %d, want %d", s.Version, SystemInfoSchemaVersion)
+	}
+
+	data, err := json.Marshal(CollectSystemInfo(context.Background(), SystemOptions{SkipNetwork: true}))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var payload map[string]any
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	for key := range payload {
+		if _, ok := s.Properties[key]; !ok {
+			t.Errorf("output key %q missing from schema", key)
+		}
+	}
+	for _, key := range s.Required {
+		if _, ok := payload[key]; !ok {
+			t.Errorf("required schema key %q missing from output", key)
+		}
+	}
+	if got := payload["schema_version"]; got != float64(SystemInfoSchemaVersion) {
+		t.Errorf("schema_version = %v, want %d", got, SystemInfoSchemaVersion)
+	}
+}
@@ -0,0 +1,152 @@
+package meta
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// SecurityInfo represents kernel security posture and per-process limits
+// that commonly explain permission and resource differences between users.
+type SecurityInfo struct {
+	User            string            `json:"user" yaml:"user"`
+	UID             int               `json:"uid" yaml:"uid"`
+	IsRoot          bool              `json:"is_root" yaml:"is_root"`
+	SELinux         string            `json:"selinux" yaml:"selinux"`   // enforcing, permissive, disabled, unknown
+	AppArmor        string            `json:"apparmor" yaml:"apparmor"` // enabled, disabled, unknown
+	AppArmorProfile string            `json:"apparmor_profile,omitempty" yaml:"apparmor_profile,omitempty"`
+	SecureBoot      string            `json:"secure_boot" yaml:"secure_boot"` // enabled, disabled, unsupported, unknown
+	Sysctls         map[string]string `json:"sysctls" yaml:"sysctls"`
+	Ulimits         []UlimitInfo      `json:"ulimits" yaml:"ulimits"`
+}
+
+// UlimitInfo represents a resource limit of the current process.
+// Unlimited values are reported as "unlimited".
+type UlimitInfo struct {
+	Name string `json:"name" yaml:"name"`
+	Soft string `json:"soft" yaml:"soft"`
+	Hard string `json:"hard" yaml:"hard"`
+}
+
+// reportedSysctls lists kernel parameters that frequently explain
+// environment-specific failures (OOM behavior, file descriptor exhaustion).
+var reportedSysctls = []string{
+	"vm.overcommit_memory",
+	"vm.overcommit_ratio",
+	"vm.swappiness",
+	"vm.max_map_count",
+	"fs.file-max",
+	"fs.nr_open",
+	"fs.inotify.max_user_watches",
+	"kernel.pid_max",
+}
+
+// secureBootVar is the EFI variable holding the Secure Boot state.
+const secureBootVar = "SecureBoot-8be4df61-93ca-11d2-aa0d-00e098032b8c"
+
+// securityReader reads security state from the filesystem; root is a path
+// prefix so tests can supply a fake /sys and /proc tree.
+type securityReader struct {
+	root string
+}
+
+// collectSecurityInfo gathers security posture for the current process.
+// Unavailable sources degrade to "unknown" rather than failing.
+func collectSecurityInfo(ctx context.Context) *SecurityInfo {
+	r := securityReader{root: "/"}
+	info := r.read()
+
+	info.UID = os.Getuid()
+	info.IsRoot = info.UID == 0
+	if u, err := user.Current(); err == nil {
+		info.User = u.Username
+	} else {
+		slog.DebugContext(ctx, "Current user lookup failed", "error", err)
+	}
+
+	info.Ulimits = collectUlimits()
+	return info
+}
+
+func (r securityReader) read() *SecurityInfo {
+	info := &SecurityInfo{
+		SELinux:    r.selinux(),
+		AppArmor:   "unknown",
+		SecureBoot: r.secureBoot(),
+		Sysctls:    map[string]string{},
+		Ulimits:    []UlimitInfo{},
+	}
+
+	if enabled, ok := r.readFile("sys/module/apparmor/parameters/enabled"); ok {
+		if enabled == "Y" {
+			info.AppArmor = "enabled"
+			if profile, ok := r.readFile("proc/self/attr/current"); ok {
+				info.AppArmorProfile = strings.TrimRight(profile, "\x00")
+			}
+		} else {
+			info.AppArmor = "disabled"
+		}
+	}
+
+	for _, name := range reportedSysctls {
+		path := filepath.Join("proc/sys", strings.ReplaceAll(name, ".", "/"))
+		if value, ok := r.readFile(path); ok {
+			info.Sysctls[name] = strings.Join(strings.Fields(value), " ")
+		}
+	}
+
+	return info
+}
+
+func (r securityReader) selinux() string {
+	enforce, ok := r.readFile("sys/fs/selinux/enforce")
+	if !ok {
+		if _, err := os.Stat(filepath.Join(r.root, "sys/fs/selinux")); err == nil {
+			return "unknown"
+		}
+		return "disabled"
+	}
+	switch enforce {
+	case "1":
+		return "enforcing"
+	case "0":
+		return "permissive"
+	}
+	return "unknown"
+}
+
+// secureBoot reads the EFI SecureBoot variable: 4 attribute bytes followed
+// by a single data byte (1 = enabled).
+func (r securityReader) secureBoot() string {
+	if _, err := os.Stat(filepath.Join(r.root, "sys/firmware/efi")); err != nil {
+		return "unsupported"
+	}
+	data, err := os.ReadFile(filepath.Join(r.root, "sys/firmware/efi/efivars", secureBootVar))
+	if err != nil || len(data) < 5 {
+		return "unknown"
+	}
+	if data[4] == 1 {
+		return "enabled"
+	}
+	return "disabled"
+}
+
+func (r securityReader) readFile(rel string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(r.root, rel))
+	if err != nil {
+		return "", false
+	}
+	return strings.TrimSpace(string(data)), true
+}
+
+// formatRlimit renders a limit value, mapping the platform's infinity to "unlimited".
+func formatRlimit(value, infinity uint64) string {
+	if value == infinity {
+		return "unlimited"
+	}
+	return strconv.FormatUint(value, 10)
+}
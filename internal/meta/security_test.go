@@ -0,0 +1,123 @@
+package meta
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for rel, content := range files {
+		path := filepath.Join(root, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", rel, err)
+		}
+	}
+	return root
+}
+
+func TestSecurityReader(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"sys/fs/selinux/enforce":                    "1",
+		"sys/module/apparmor/parameters/enabled":    "Y\n",
+		"proc/self/attr/current":                    "docker-default (enforce)\n",
+		"sys/firmware/efi/efivars/" + secureBootVar: "\x06\x00\x00\x00\x01",
+		"proc/sys/vm/overcommit_memory":             "2\n",
+		"proc/sys/fs/file-max":                      "9223372036854775807\n",
+		"proc/sys/fs/inotify/max_user_watches":      "8192\n",
+	})
+
+	info := securityReader{root: root}.read()
+
+	if info.SELinux != "enforcing" {
+		t.Errorf("SELinux = %q, want enforcing", info.SELinux)
+	}
+	if info.AppArmor != "enabled" || info.AppArmorProfile != "docker-default (enforce)" {
+		t.Errorf("AppArmor = %q (%q), want enabled (docker-default (enforce))", info.AppArmor, info.AppArmorProfile)
+	}
+	if info.SecureBoot != "enabled" {
+		t.Errorf("SecureBoot = %q, want enabled", info.SecureBoot)
+	}
+
+	wantSysctls := map[string]string{
+		"vm.overcommit_memory":        "2",
+		"fs.file-max":                 "9223372036854775807",
+		"fs.inotify.max_user_watches": "8192",
+	}
+	if len(info.Sysctls) != len(wantSysctls) {
+		t.Errorf("Sysctls = %v, want %v", info.Sysctls, wantSysctls)
+	}
+	for key, want := range wantSysctls {
+		if got := info.Sysctls[key]; got != want {
+			t.Errorf("Sysctls[%q] = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestSecurityReader_Defaults(t *testing.T) {
+	tests := []struct {
+		name           string
+		files          map[string]string
+		wantSELinux    string
+		wantAppArmor   string
+		wantSecureBoot string
+	}{
+		{
+			name:           "nothing available",
+			files:          map[string]string{},
+			wantSELinux:    "disabled",
+			wantAppArmor:   "unknown",
+			wantSecureBoot: "unsupported",
+		},
+		{
+			name: "permissive, apparmor off, secure boot off",
+			files: map[string]string{
+				"sys/fs/selinux/enforce":                    "0",
+				"sys/module/apparmor/parameters/enabled":    "N",
+				"sys/firmware/efi/efivars/" + secureBootVar: "\x06\x00\x00\x00\x00",
+			},
+			wantSELinux:    "permissive",
+			wantAppArmor:   "disabled",
+			wantSecureBoot: "disabled",
+		},
+		{
+			name: "efi without readable variable",
+			files: map[string]string{
+				"sys/firmware/efi/systab": "",
+			},
+			wantSELinux:    "disabled",
+			wantAppArmor:   "unknown",
+			wantSecureBoot: "unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := securityReader{root: writeTree(t, tt.files)}.read()
+			if info.SELinux != tt.wantSELinux {
+				t.Errorf("SELinux = %q, want %q", info.SELinux, tt.wantSELinux)
+			}
+			if info.AppArmor != tt.wantAppArmor {
+				t.Errorf("AppArmor = %q, want %q", info.AppArmor, tt.wantAppArmor)
+			}
+			if info.SecureBoot != tt.wantSecureBoot {
+				t.Errorf("SecureBoot = %q, want %q", info.SecureBoot, tt.wantSecureBoot)
+			}
+		})
+	}
+}
+
+func TestFormatRlimit(t *testing.T) {
+	const inf = ^uint64(0)
+	if got := formatRlimit(inf, inf); got != "unlimited" {
+		t.Errorf("formatRlimit(inf) = %q, want unlimited", got)
+	}
+	if got := formatRlimit(1024, inf); got != "1024" {
+		t.Errorf("formatRlimit(1024) = %q, want 1024", got)
+	}
+}
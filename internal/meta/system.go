@@ -2,10 +2,16 @@ package meta
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"time"
 
-	"github.com/jaypipes/ghw"
 	"github.com/shirou/gopsutil/v4/cpu"
 	"github.com/shirou/gopsutil/v4/disk"
 	"github.com/shirou/gopsutil/v4/host"
@@ -14,15 +20,157 @@ import (
 
 // SystemInfo represents comprehensive system diagnostic information.
 type SystemInfo struct {
-	OS           string        `json:"os" yaml:"os"`
-	Platform     string        `json:"platform" yaml:"platform"`
-	Kernel       string        `json:"kernel" yaml:"kernel"`
-	Architecture string        `json:"architecture" yaml:"architecture"`
-	CPU          CPUInfo       `json:"cpu" yaml:"cpu"`
-	Memory       MemoryInfo    `json:"memory" yaml:"memory"`
-	Storage      []StorageInfo `json:"storage" yaml:"storage"`
-	GPU          []GPUInfo     `json:"gpu" yaml:"gpu"`
-	NPU          *NPUInfo      `json:"npu" yaml:"npu"`
+	SchemaVersion int            `json:"schema_version" yaml:"schema_version"`
+	OS            string         `json:"os" yaml:"os"`
+	Platform      string         `json:"platform" yaml:"platform"`
+	Kernel        string         `json:"kernel" yaml:"kernel"`
+	Architecture  string         `json:"architecture" yaml:"architecture"`
+	CPU           CPUInfo        `json:"cpu" yaml:"cpu"`
+	Memory        MemoryInfo     `json:"memory" yaml:"memory"`
+	Storage       []StorageInfo  `json:"storage" yaml:"storage"`
+	GPU           []GPUInfo      `json:"gpu" yaml:"gpu"`
+	NPU           *NPUInfo       `json:"npu" yaml:"npu"`
+	Cloud         *CloudInfo     `json:"cloud" yaml:"cloud"`
+	Cgroup        *CgroupInfo    `json:"cgroup" yaml:"cgroup"`
+	Time          *TimeInfo      `json:"time" yaml:"time"`
+	GoRuntime     *GoRuntimeInfo `json:"go_runtime" yaml:"go_runtime"`
+	Security      *SecurityInfo  `json:"security,omitempty" yaml:"security,omitempty"`
+	// Sections lists the sections that were collected; the others are
+	// left empty. See SystemSections.
+	Sections []string `json:"sections" yaml:"sections"`
+}
+
+// Collected reports whether section was collected. A report without a
+// section list, such as one built by hand, counts as complete.
+func (s SystemInfo) Collected(section string) bool {
+	return s.Sections == nil || slices.Contains(s.Sections, section)
+}
+
+// Sections of the system report that SystemOptions.Sections can select.
+// The host identity (OS, platform, kernel, architecture) is always
+// collected.
+const (
+	SectionCPU       = "cpu"
+	SectionMemory    = "memory"
+	SectionStorage   = "storage"
+	SectionGPU       = "gpu"
+	SectionNPU       = "npu"
+	SectionCgroup    = "cgroup"
+	SectionGoRuntime = "go_runtime"
+	SectionCloud     = "cloud"
+	SectionTime      = "time"
+	SectionSecurity  = "security"
+)
+
+// SystemSections lists every section in report order.
+var SystemSections = []string{
+	SectionCPU, SectionMemory, SectionStorage, SectionGPU, SectionNPU,
+	SectionCgroup, SectionGoRuntime, SectionCloud, SectionTime, SectionSecurity,
+}
+
+// DefaultSystemSections are collected when SystemOptions.Sections is
+// empty: everything but the optional security section.
+var DefaultSystemSections = SystemSections[:len(SystemSections)-1]
+
+// notCompiledIn is the message reported for probes left out of minimal
+// builds; see HardwareDetection.
+const notCompiledIn = "not compiled in (minimal build)"
+
+// ParseSections validates section names, as given to --sections, and
+// returns them in report order without duplicates.
+func ParseSections(names []string) ([]string, error) {
+	for _, name := range names {
+		if !slices.Contains(SystemSections, name) {
+			return nil, fmt.Errorf("unknown section %q (valid: %s)", name, strings.Join(SystemSections, ", "))
+		}
+	}
+	var sections []string
+	for _, section := range SystemSections {
+		if slices.Contains(names, section) {
+			sections = append(sections, section)
+		}
+	}
+	return sections, nil
+}
+
+// SystemOptions controls optional behavior of CollectSystemInfo.
+type SystemOptions struct {
+	// SkipNetwork disables detectors that make network calls, such as
+	// cloud metadata endpoint lookups.
+	SkipNetwork bool
+
+	// IncludeSecurity adds the optional security posture section (SELinux,
+	// AppArmor, Secure Boot, sysctls, and process ulimits).
+	IncludeSecurity bool
+
+	// NTPServer, when set, measures local clock drift against this server.
+	// Ignored when SkipNetwork is set.
+	NTPServer string
+
+	// Sections limits collection to these sections, skipping the probes
+	// of the others; empty means DefaultSystemSections. IncludeSecurity
+	// adds the security section either way.
+	Sections []string
+
+	// Collectors replaces the hardware probes, for tests and embedders
+	// that supply their own. Nil fields use the production probes.
+	Collectors Collectors
+}
+
+// Collectors are the probes CollectSystemInfo reads the host's hardware
+// with. Each is best-effort: failures are logged and leave the section
+// at its "unknown" value.
+type Collectors struct {
+	// HostInfo reports the operating system, platform, and kernel.
+	HostInfo func(ctx context.Context) (HostInfo, error)
+
+	// CPUInfo reports the model, vendor, core count, and frequency.
+	CPUInfo func(ctx context.Context) (CPUInfo, error)
+
+	// GPU lists the graphics cards. It returns an empty slice when none
+	// are found or detection fails.
+	GPU func(ctx context.Context) []GPUInfo
+
+	// NPU infers a neural processing unit from the CPU model and OS, or
+	// returns nil.
+	NPU func(ctx context.Context, cpuModel, os string) *NPUInfo
+}
+
+// DefaultCollectors returns the production probes, for embedders that
+// wrap rather than replace them.
+func DefaultCollectors() Collectors {
+	return Collectors{
+		HostInfo: hostInfo,
+		CPUInfo:  cpuInfo,
+		GPU:      detectGPU,
+		NPU:      npuProbe,
+	}
+}
+
+// withDefaults fills the nil fields of c with the production probes.
+func (c Collectors) withDefaults() Collectors {
+	defaults := DefaultCollectors()
+	if c.HostInfo == nil {
+		c.HostInfo = defaults.HostInfo
+	}
+	if c.CPUInfo == nil {
+		c.CPUInfo = defaults.CPUInfo
+	}
+	if c.GPU == nil {
+		c.GPU = defaults.GPU
+	}
+	if c.NPU == nil {
+		c.NPU = defaults.NPU
+	}
+	return c
+}
+
+// HostInfo identifies the operating system of the host.
+type HostInfo struct {
+	OS           string
+	Platform     string
+	Kernel       string
+	Architecture string
 }
 
 // CPUInfo represents CPU information.
@@ -31,6 +179,10 @@ type CPUInfo struct {
 	Vendor       string  `json:"vendor" yaml:"vendor"`
 	Cores        int32   `json:"cores" yaml:"cores"`
 	FrequencyMHz float64 `json:"frequency_mhz" yaml:"frequency_mhz"`
+	// EffectiveCPUs is the CPU capacity available to this process: the host
+	// core count, or the cgroup CPU quota when it is tighter.
+	EffectiveCPUs float64 `json:"effective_cpus" yaml:"effective_cpus"`
+	LimitSource   string  `json:"limit_source" yaml:"limit_source"` // host, cgroup
 }
 
 // MemoryInfo represents memory and swap information.
@@ -41,17 +193,25 @@ type MemoryInfo struct {
 	UsedPercent float64 `json:"used_percent" yaml:"used_percent"`
 	SwapTotalMB uint64  `json:"swap_total_mb" yaml:"swap_total_mb"`
 	SwapUsedMB  uint64  `json:"swap_used_mb" yaml:"swap_used_mb"`
+	// EffectiveLimitMB is the memory available to this process: the host
+	// total, or the cgroup memory limit when it is tighter.
+	EffectiveLimitMB uint64 `json:"effective_limit_mb" yaml:"effective_limit_mb"`
+	LimitSource      string `json:"limit_source" yaml:"limit_source"` // host, cgroup
 }
 
 // StorageInfo represents storage volume information.
 type StorageInfo struct {
-	Device      string  `json:"device" yaml:"device"`
-	Mountpoint  string  `json:"mountpoint" yaml:"mountpoint"`
-	Filesystem  string  `json:"filesystem" yaml:"filesystem"`
-	TotalMB     uint64  `json:"total_mb" yaml:"total_mb"`
-	UsedMB      uint64  `json:"used_mb" yaml:"used_mb"`
-	FreeMB      uint64  `json:"free_mb" yaml:"free_mb"`
-	UsedPercent float64 `json:"used_percent" yaml:"used_percent"`
+	Device      string      `json:"device" yaml:"device"`
+	Mountpoint  string      `json:"mountpoint" yaml:"mountpoint"`
+	Filesystem  string      `json:"filesystem" yaml:"filesystem"`
+	TotalMB     uint64      `json:"total_mb" yaml:"total_mb"`
+	UsedMB      uint64      `json:"used_mb" yaml:"used_mb"`
+	FreeMB      uint64      `json:"free_mb" yaml:"free_mb"`
+	UsedPercent float64     `json:"used_percent" yaml:"used_percent"`
+	Health      *DiskHealth `json:"health" yaml:"health"`
+	// Unresponsive marks a mount, typically a network or FUSE filesystem,
+	// that did not answer in time; its usage figures are zero.
+	Unresponsive bool `json:"unresponsive,omitempty" yaml:"unresponsive,omitempty"`
 }
 
 // GPUInfo represents GPU information.
@@ -77,170 +237,344 @@ type NPUInfo struct {
 // - Cores: 0 = unknown
 // - FrequencyMHz: 0.0 = unknown (common on Apple Silicon)
 // - TotalMB/UsedMB: 0 = detection failed
-func CollectSystemInfo(ctx context.Context) SystemInfo {
+// - Cloud: nil = not running on a recognized cloud provider
+func CollectSystemInfo(ctx context.Context, opts SystemOptions) SystemInfo {
+	collectors := opts.Collectors.withDefaults()
+	sections := opts.Sections
+	if len(sections) == 0 {
+		sections = DefaultSystemSections
+	}
+	if opts.IncludeSecurity {
+		sections = append(slices.Clone(sections), SectionSecurity)
+	}
+	sections, _ = ParseSections(sections)
+	// Minimal builds have no GPU or NPU probes of their own.
+	if !HardwareDetection {
+		sections = slices.DeleteFunc(sections, func(s string) bool {
+			return (s == SectionGPU && opts.Collectors.GPU == nil) || (s == SectionNPU && opts.Collectors.NPU == nil)
+		})
+	}
+	want := func(section string) bool { return slices.Contains(sections, section) }
+
 	info := SystemInfo{
-		OS:           "unknown",
-		Platform:     "unknown",
-		Kernel:       "unknown",
-		Architecture: "unknown",
+		SchemaVersion: SystemInfoSchemaVersion,
+		OS:            "unknown",
+		Platform:      "unknown",
+		Kernel:        "unknown",
+		Architecture:  "unknown",
 		CPU: CPUInfo{
 			Model:  "unknown",
 			Vendor: "unknown",
 			Cores:  0,
 		},
-		Memory:  MemoryInfo{},
-		Storage: []StorageInfo{},
-		GPU:     []GPUInfo{},
+		Memory:   MemoryInfo{},
+		Storage:  []StorageInfo{},
+		GPU:      []GPUInfo{},
+		Sections: sections,
 	}
 
 	// OS and host info (graceful degradation)
-	if hostInfo, err := host.InfoWithContext(ctx); err == nil {
-		info.OS = hostInfo.OS
-		info.Platform = hostInfo.Platform + " " + hostInfo.PlatformVersion
-		info.Kernel = hostInfo.KernelVersion
-		info.Architecture = hostInfo.KernelArch
+	if h, err := collectors.HostInfo(ctx); err == nil {
+		info.OS = h.OS
+		info.Platform = h.Platform
+		info.Kernel = h.Kernel
+		info.Architecture = h.Architecture
 	} else {
 		slog.DebugContext(ctx, "Host info detection failed", "error", err)
 	}
 
-	// CPU info (graceful degradation)
-	if cpuInfos, err := cpu.InfoWithContext(ctx); err == nil && len(cpuInfos) > 0 {
-		first := cpuInfos[0]
-		info.CPU = CPUInfo{
-			Model:        first.ModelName,
-			Vendor:       first.VendorID,
-			Cores:        int32(first.Cores),
-			FrequencyMHz: first.Mhz,
+	// CPU info (graceful degradation); NPU inference needs the model
+	if want(SectionCPU) || want(SectionNPU) {
+		if c, err := collectors.CPUInfo(ctx); err == nil {
+			info.CPU = c
+		} else {
+			slog.DebugContext(ctx, "CPU detection failed", "error", err)
 		}
-	} else if err != nil {
-		slog.DebugContext(ctx, "CPU detection failed", "error", err)
 	}
 
-	// Memory info (graceful degradation)
+	// Memory and swap info (graceful degradation)
+	if want(SectionMemory) {
+		info.Memory = collectMemory(ctx)
+	}
+
+	// Storage info (graceful degradation)
+	if want(SectionStorage) {
+		info.Storage = collectStorage(ctx, newDiskHealthChecker(defaultToolEnv()))
+	}
+
+	// Phase 2: GPU detection (best-effort). Enumerating the PCI bus is the
+	// slowest probe, so it only runs when the section is wanted.
+	if want(SectionGPU) {
+		info.GPU = collectors.GPU(ctx)
+	}
+
+	// Phase 3: NPU detection (best-effort, CPU model-based inference)
+	if want(SectionNPU) {
+		info.NPU = collectors.NPU(ctx, info.CPU.Model, info.OS)
+	}
+
+	// Container resource limits (best-effort, Linux cgroup v1/v2); they
+	// also bound the CPU, memory, and Go runtime figures
+	if want(SectionCgroup) || want(SectionCPU) || want(SectionMemory) || want(SectionGoRuntime) {
+		info.Cgroup = detectCgroup(ctx)
+		applyCgroupLimits(&info)
+	}
+
+	// Go runtime settings of this process (cgroup-aware CPU suggestion)
+	if want(SectionGoRuntime) {
+		info.GoRuntime = collectGoRuntimeInfo(info.Cgroup)
+	}
+
+	// Cloud provider detection (best-effort, DMI plus optional metadata lookup)
+	if want(SectionCloud) {
+		info.Cloud = detectCloud(ctx, opts.SkipNetwork)
+	}
+
+	// Locale, timezone, and optional clock drift
+	if want(SectionTime) {
+		ntpServer := opts.NTPServer
+		if opts.SkipNetwork {
+			ntpServer = ""
+		}
+		info.Time = collectTimeInfo(ctx, ntpServer)
+	}
+
+	// Security posture (optional section)
+	if want(SectionSecurity) {
+		info.Security = collectSecurityInfo(ctx)
+	}
+
+	return info
+}
+
+// hostInfo reads the host identity with gopsutil.
+func hostInfo(ctx context.Context) (HostInfo, error) {
+	h, err := host.InfoWithContext(ctx)
+	if err != nil {
+		return HostInfo{}, err
+	}
+	return HostInfo{
+		OS:           h.OS,
+		Platform:     h.Platform + " " + h.PlatformVersion,
+		Kernel:       h.KernelVersion,
+		Architecture: h.KernelArch,
+	}, nil
+}
+
+// cpuInfo reads the first CPU's details with gopsutil.
+func cpuInfo(ctx context.Context) (CPUInfo, error) {
+	cpus, err := cpu.InfoWithContext(ctx)
+	if err != nil {
+		return CPUInfo{}, err
+	}
+	if len(cpus) == 0 {
+		return CPUInfo{}, errors.New("no CPUs reported")
+	}
+	first := cpus[0]
+	return CPUInfo{
+		Model:        first.ModelName,
+		Vendor:       first.VendorID,
+		Cores:        int32(first.Cores),
+		FrequencyMHz: first.Mhz,
+	}, nil
+}
+
+// collectMemory reports physical memory and swap usage.
+func collectMemory(ctx context.Context) MemoryInfo {
+	var m MemoryInfo
 	if memInfo, err := mem.VirtualMemoryWithContext(ctx); err == nil {
-		info.Memory.TotalMB = memInfo.Total / 1024 / 1024
-		info.Memory.AvailableMB = memInfo.Available / 1024 / 1024
-		info.Memory.UsedMB = memInfo.Used / 1024 / 1024
-		info.Memory.UsedPercent = memInfo.UsedPercent
+		m.TotalMB = memInfo.Total / 1024 / 1024
+		m.AvailableMB = memInfo.Available / 1024 / 1024
+		m.UsedMB = memInfo.Used / 1024 / 1024
+		m.UsedPercent = memInfo.UsedPercent
 	} else {
 		slog.DebugContext(ctx, "Memory detection failed", "error", err)
 	}
 
-	// Swap info (graceful degradation)
 	if swapInfo, err := mem.SwapMemoryWithContext(ctx); err == nil {
-		info.Memory.SwapTotalMB = swapInfo.Total / 1024 / 1024
-		info.Memory.SwapUsedMB = swapInfo.Used / 1024 / 1024
+		m.SwapTotalMB = swapInfo.Total / 1024 / 1024
+		m.SwapUsedMB = swapInfo.Used / 1024 / 1024
 	} else {
 		slog.DebugContext(ctx, "Swap detection failed", "error", err)
 	}
+	return m
+}
 
-	// Storage info (graceful degradation)
-	if partitions, err := disk.PartitionsWithContext(ctx, false); err == nil {
-		// Filter out pseudo-filesystems (Linux /proc, /sys, etc.)
-		skipFsTypes := map[string]bool{
-			"sysfs": true, "proc": true, "devtmpfs": true, "tmpfs": true,
-			"devpts": true, "cgroup": true, "cgroup2": true, "overlay": true,
-		}
+// skipFsTypes lists pseudo-filesystems (Linux /proc, /sys, etc.) left out
+// of storage reports.
+var skipFsTypes = map[string]bool{
+	"sysfs": true, "proc": true, "devtmpfs": true, "tmpfs": true,
+	"devpts": true, "cgroup": true, "cgroup2": true, "overlay": true,
+}
 
-		for _, partition := range partitions {
-			// Skip pseudo-filesystems
-			if skipFsTypes[partition.Fstype] {
-				continue
-			}
+// collectStorage reports usage of every real filesystem. SMART health is
+// only queried when health is non-nil.
+func collectStorage(ctx context.Context, health *diskHealthChecker) []StorageInfo {
+	storage := []StorageInfo{}
+	partitions, err := disk.PartitionsWithContext(ctx, false)
+	if err != nil {
+		slog.DebugContext(ctx, "Storage detection failed", "error", err)
+		return storage
+	}
+	partitions = slices.DeleteFunc(partitions, func(p disk.PartitionStat) bool { return skipFsTypes[p.Fstype] })
+
+	for i, result := range mountUsage(ctx, partitions, disk.UsageWithContext, mountTimeout) {
+		partition := partitions[i]
+		volume := StorageInfo{
+			Device:     partition.Device,
+			Mountpoint: partition.Mountpoint,
+			Filesystem: partition.Fstype,
+		}
+		switch {
+		case result.unresponsive:
+			slog.DebugContext(ctx, "Mount did not respond", "mountpoint", partition.Mountpoint, "timeout", mountTimeout)
+			volume.Unresponsive = true
+			storage = append(storage, volume)
+			continue
+		case result.err != nil:
+			continue
+		}
+		volume.TotalMB = result.usage.Total / 1024 / 1024
+		volume.UsedMB = result.usage.Used / 1024 / 1024
+		volume.FreeMB = result.usage.Free / 1024 / 1024
+		volume.UsedPercent = result.usage.UsedPercent
+		storage = append(storage, volume)
+	}
 
-			if usage, err := disk.UsageWithContext(ctx, partition.Mountpoint); err == nil {
-				info.Storage = append(info.Storage, StorageInfo{
-					Device:      partition.Device,
-					Mountpoint:  partition.Mountpoint,
-					Filesystem:  partition.Fstype,
-					TotalMB:     usage.Total / 1024 / 1024,
-					UsedMB:      usage.Used / 1024 / 1024,
-					FreeMB:      usage.Free / 1024 / 1024,
-					UsedPercent: usage.UsedPercent,
-				})
+	if health != nil {
+		var devices []string
+		var volumes []*StorageInfo
+		for i := range storage {
+			if !storage[i].Unresponsive {
+				devices = append(devices, storage[i].Device)
+				volumes = append(volumes, &storage[i])
 			}
 		}
-	} else {
-		slog.DebugContext(ctx, "Storage detection failed", "error", err)
+		for i, h := range health.checkAll(ctx, devices) {
+			volumes[i].Health = h
+		}
 	}
+	return storage
+}
 
-	// Phase 2: GPU detection (best-effort)
-	info.GPU = detectGPU(ctx)
-
-	// Phase 3: NPU detection (best-effort, CPU model-based inference)
-	info.NPU = detectNPU(ctx, info.CPU.Model, info.OS)
+// mountTimeout bounds the usage query of a single mount. A hung NFS, SMB,
+// or FUSE mount blocks statfs in the kernel regardless of ctx.
+const mountTimeout = 2 * time.Second
 
-	return info
+// usageResult is the outcome of querying one mount.
+type usageResult struct {
+	usage        *disk.UsageStat
+	err          error
+	unresponsive bool
 }
 
-// detectGPU attempts to detect GPU information using hardware-level detection.
-// Returns empty slice if detection fails (graceful degradation).
-// Logs detection failures via slog at debug level.
-//
-// Phase 2 implementation: Cross-platform GPU detection using ghw.
-// Detects NVIDIA, AMD, Intel, Apple, and other GPUs on Linux, Windows, and macOS.
-func detectGPU(ctx context.Context) []GPUInfo {
-	gpus := []GPUInfo{}
-
-	// Use ghw for hardware-level GPU detection
-	gpu, err := ghw.GPU()
-	if err != nil {
-		slog.DebugContext(ctx, "GPU detection failed", "error", err)
-		return gpus
+// pendingMounts holds the mountpoints whose earlier query has not returned
+// yet. They are reported unresponsive right away instead of stacking up
+// another blocked goroutine, which matters for repeated sampling in
+// `ado top`.
+var pendingMounts sync.Map
+
+// mountUsage queries the usage of all partitions concurrently and returns
+// the results in partition order. A mountpoint listed more than once, as
+// with bind mounts and over-mounts, is queried once and its result shared.
+// A mount that does not answer within timeout is reported unresponsive;
+// its query is abandoned, not cancelled, since a blocked statfs cannot be
+// interrupted.
+func mountUsage(ctx context.Context, partitions []disk.PartitionStat, usage func(context.Context, string) (*disk.UsageStat, error), timeout time.Duration) []usageResult {
+	var mountpoints []string
+	indexes := make(map[string][]int)
+	for i, partition := range partitions {
+		if _, seen := indexes[partition.Mountpoint]; !seen {
+			mountpoints = append(mountpoints, partition.Mountpoint)
+		}
+		indexes[partition.Mountpoint] = append(indexes[partition.Mountpoint], i)
 	}
 
-	if gpu == nil || len(gpu.GraphicsCards) == 0 {
-		slog.DebugContext(ctx, "No GPUs detected")
-		return gpus
+	unique := make([]usageResult, len(mountpoints))
+	var wg sync.WaitGroup
+	for i, mountpoint := range mountpoints {
+		// Only a query left in flight by an earlier call is pending here,
+		// since each mountpoint is queried once per call.
+		if _, pending := pendingMounts.LoadOrStore(mountpoint, true); pending {
+			unique[i].unresponsive = true
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			done := make(chan usageResult, 1)
+			go func() {
+				defer pendingMounts.Delete(mountpoint)
+				u, err := usage(ctx, mountpoint)
+				done <- usageResult{usage: u, err: err}
+			}()
+			timer := time.NewTimer(timeout)
+			defer timer.Stop()
+			select {
+			case unique[i] = <-done:
+			case <-timer.C:
+				unique[i].unresponsive = true
+			case <-ctx.Done():
+				unique[i].err = ctx.Err()
+			}
+		}()
 	}
+	wg.Wait()
 
-	for _, card := range gpu.GraphicsCards {
-		if card.DeviceInfo == nil {
-			continue
+	results := make([]usageResult, len(partitions))
+	for i, mountpoint := range mountpoints {
+		for _, j := range indexes[mountpoint] {
+			results[j] = unique[i]
 		}
+	}
+	return results
+}
 
-		// Determine GPU vendor from device info
-		vendor := "Unknown"
-		gpuType := "unknown"
+// hasGPUDevices reports whether a Linux host under root exposes a DRM card
+// or an NVIDIA driver GPU. Servers without either have no GPU for ghw to
+// find, and checking is much cheaper than enumerating the PCI bus.
+func hasGPUDevices(root string) bool {
+	if cards, _ := filepath.Glob(filepath.Join(root, "sys", "class", "drm", "card[0-9]*")); len(cards) > 0 {
+		return true
+	}
+	entries, _ := os.ReadDir(filepath.Join(root, "proc", "driver", "nvidia", "gpus"))
+	return len(entries) > 0
+}
 
-		// Normalize vendor name
-		vendorLower := strings.ToLower(card.DeviceInfo.Vendor.Name)
-		if strings.Contains(vendorLower, "nvidia") {
-			vendor = "NVIDIA"
+// classifyGPU normalizes the vendor name reported by the hardware and
+// infers whether the card is integrated or discrete.
+func classifyGPU(vendorName, productName string) GPUInfo {
+	vendor := "Unknown"
+	gpuType := "unknown"
+
+	// Normalize vendor name
+	vendorLower := strings.ToLower(vendorName)
+	if strings.Contains(vendorLower, "nvidia") {
+		vendor = "NVIDIA"
+		gpuType = "discrete"
+	} else if strings.Contains(vendorLower, "amd") || strings.Contains(vendorLower, "advanced micro devices") {
+		vendor = "AMD"
+		gpuType = "discrete"
+	} else if strings.Contains(vendorLower, "intel") {
+		vendor = "Intel"
+		// Intel GPUs can be integrated or discrete
+		if strings.Contains(strings.ToLower(productName), "arc") {
 			gpuType = "discrete"
-		} else if strings.Contains(vendorLower, "amd") || strings.Contains(vendorLower, "advanced micro devices") {
-			vendor = "AMD"
-			gpuType = "discrete"
-		} else if strings.Contains(vendorLower, "intel") {
-			vendor = "Intel"
-			// Intel GPUs can be integrated or discrete
-			if strings.Contains(strings.ToLower(card.DeviceInfo.Product.Name), "arc") {
-				gpuType = "discrete"
-			} else {
-				gpuType = "integrated"
-			}
-		} else if strings.Contains(vendorLower, "apple") {
-			vendor = "Apple"
-			gpuType = "integrated"
 		} else {
-			vendor = card.DeviceInfo.Vendor.Name
-		}
-
-		model := card.DeviceInfo.Product.Name
-		if model == "" {
-			model = "Unknown Model"
+			gpuType = "integrated"
 		}
-
-		gpus = append(gpus, GPUInfo{
-			Vendor: vendor,
-			Model:  model,
-			Type:   gpuType,
-		})
-
-		slog.DebugContext(ctx, "Detected GPU", "vendor", vendor, "model", model, "type", gpuType)
+	} else if strings.Contains(vendorLower, "apple") {
+		vendor = "Apple"
+		gpuType = "integrated"
+	} else {
+		vendor = vendorName
 	}
 
-	return gpus
+	model := productName
+	if model == "" {
+		model = "Unknown Model"
+	}
+	return GPUInfo{Vendor: vendor, Model: model, Type: gpuType}
 }
 
 // detectNPU attempts to infer NPU presence from CPU model.
@@ -2,12 +2,21 @@ package meta
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/disk"
 )
 
 func TestCollectSystemInfo(t *testing.T) {
 	ctx := context.Background()
-	info := CollectSystemInfo(ctx)
+	info := CollectSystemInfo(ctx, SystemOptions{SkipNetwork: true})
 
 	t.Run("OS fields populated", func(t *testing.T) {
 		if info.OS == "" {
@@ -192,3 +201,257 @@ func TestDetectNPU(t *testing.T) {
 		})
 	}
 }
+
+func TestCollectSystemInfo_Collectors(t *testing.T) {
+	ctx := context.Background()
+	collectors := Collectors{
+		HostInfo: func(context.Context) (HostInfo, error) {
+			return HostInfo{OS: "darwin", Platform: "macOS 14.2", Kernel: "23.2.0", Architecture: "arm64"}, nil
+		},
+		CPUInfo: func(context.Context) (CPUInfo, error) {
+			return CPUInfo{Model: "Apple M2 Pro", Vendor: "Apple", Cores: 10}, nil
+		},
+		GPU: func(context.Context) []GPUInfo {
+			return []GPUInfo{{Vendor: "Apple", Model: "Apple M2 Pro GPU", Type: "integrated"}}
+		},
+	}
+	info := CollectSystemInfo(ctx, SystemOptions{SkipNetwork: true, Collectors: collectors})
+
+	if info.OS != "darwin" || info.Platform != "macOS 14.2" || info.Architecture != "arm64" {
+		t.Errorf("host = %s/%s/%s", info.OS, info.Platform, info.Architecture)
+	}
+	if info.CPU.Model != "Apple M2 Pro" || info.CPU.Cores != 10 {
+		t.Errorf("CPU = %+v", info.CPU)
+	}
+	if len(info.GPU) != 1 || info.GPU[0].Model != "Apple M2 Pro GPU" {
+		t.Errorf("GPU = %+v", info.GPU)
+	}
+	// The default NPU probe sees the faked CPU model.
+	if HardwareDetection && (info.NPU == nil || info.NPU.Type != "Apple Neural Engine") {
+		t.Errorf("NPU = %+v", info.NPU)
+	}
+}
+
+func TestCollectSystemInfo_CollectorErrors(t *testing.T) {
+	collectors := Collectors{
+		HostInfo: func(context.Context) (HostInfo, error) { return HostInfo{}, errors.New("no host") },
+		CPUInfo:  func(context.Context) (CPUInfo, error) { return CPUInfo{}, errors.New("no cpu") },
+		GPU:      func(context.Context) []GPUInfo { return []GPUInfo{} },
+		NPU:      func(context.Context, string, string) *NPUInfo { return nil },
+	}
+	info := CollectSystemInfo(context.Background(), SystemOptions{SkipNetwork: true, Collectors: collectors})
+
+	if info.OS != "unknown" || info.Kernel != "unknown" || info.CPU.Model != "unknown" || info.CPU.Cores != 0 {
+		t.Errorf("failed probes should leave unknown values, got OS %q, CPU %+v", info.OS, info.CPU)
+	}
+	if info.GPU == nil || info.NPU != nil {
+		t.Errorf("GPU = %v, NPU = %v", info.GPU, info.NPU)
+	}
+}
+
+func TestCollectSystemInfo_Sections(t *testing.T) {
+	var probed []string
+	collectors := Collectors{
+		HostInfo: func(context.Context) (HostInfo, error) { return HostInfo{OS: "linux"}, nil },
+		CPUInfo: func(context.Context) (CPUInfo, error) {
+			probed = append(probed, "cpu")
+			return CPUInfo{Model: "AMD EPYC 7763", Cores: 64}, nil
+		},
+		GPU: func(context.Context) []GPUInfo {
+			probed = append(probed, "gpu")
+			return nil
+		},
+		NPU: func(context.Context, string, string) *NPUInfo {
+			probed = append(probed, "npu")
+			return nil
+		},
+	}
+
+	tests := []struct {
+		name     string
+		opts     SystemOptions
+		sections []string
+		probed   []string
+	}{
+		{"defaults", SystemOptions{}, DefaultSystemSections, []string{"cpu", "gpu", "npu"}},
+		{"memory only", SystemOptions{Sections: []string{SectionMemory}}, []string{"memory"}, nil},
+		{"npu needs the cpu model", SystemOptions{Sections: []string{SectionNPU}}, []string{"npu"}, []string{"cpu", "npu"}},
+		{"security added", SystemOptions{Sections: []string{SectionTime}, IncludeSecurity: true}, []string{"time", "security"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probed = nil
+			tt.opts.SkipNetwork = true
+			tt.opts.Collectors = collectors
+			info := CollectSystemInfo(context.Background(), tt.opts)
+			if !slices.Equal(info.Sections, tt.sections) {
+				t.Errorf("sections = %v, want %v", info.Sections, tt.sections)
+			}
+			if !slices.Equal(probed, tt.probed) {
+				t.Errorf("probed = %v, want %v", probed, tt.probed)
+			}
+			if info.OS != "linux" {
+				t.Errorf("host info should always be collected, OS = %q", info.OS)
+			}
+		})
+	}
+}
+
+func TestParseSections(t *testing.T) {
+	got, err := ParseSections([]string{"memory", "cpu", "memory"})
+	if err != nil || !slices.Equal(got, []string{"cpu", "memory"}) {
+		t.Errorf("ParseSections() = %v, %v; want [cpu memory]", got, err)
+	}
+	if _, err := ParseSections([]string{"gpus"}); err == nil || !strings.Contains(err.Error(), `unknown section "gpus" (valid: cpu, memory,`) {
+		t.Errorf("ParseSections(gpus) error = %v", err)
+	}
+}
+
+func TestSystemInfo_Collected(t *testing.T) {
+	if !(SystemInfo{}).Collected(SectionGPU) {
+		t.Error("a report without sections should count as complete")
+	}
+	info := SystemInfo{Sections: []string{SectionCPU}}
+	if !info.Collected(SectionCPU) || info.Collected(SectionGPU) {
+		t.Errorf("Collected() disagrees with sections %v", info.Sections)
+	}
+}
+
+func TestMountUsage(t *testing.T) {
+	release := make(chan struct{})
+	usage := func(_ context.Context, path string) (*disk.UsageStat, error) {
+		switch path {
+		case "/mnt/hung":
+			<-release
+		case "/mnt/gone":
+			return nil, errors.New("no such file or directory")
+		}
+		return &disk.UsageStat{Path: path, Total: 1 << 30}, nil
+	}
+	partitions := []disk.PartitionStat{{Mountpoint: "/"}, {Mountpoint: "/mnt/hung"}, {Mountpoint: "/mnt/gone"}}
+
+	start := time.Now()
+	results := mountUsage(context.Background(), partitions, usage, 50*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("mountUsage took %v, want about the timeout", elapsed)
+	}
+	if results[0].usage == nil || results[0].usage.Path != "/" || results[0].unresponsive {
+		t.Errorf("/ = %+v", results[0])
+	}
+	if !results[1].unresponsive {
+		t.Errorf("/mnt/hung = %+v, want unresponsive", results[1])
+	}
+	if results[2].err == nil {
+		t.Errorf("/mnt/gone = %+v, want an error", results[2])
+	}
+
+	// The hung query is still outstanding, so the mount is reported
+	// unresponsive without another attempt.
+	if again := mountUsage(context.Background(), partitions[1:2], usage, time.Hour); !again[0].unresponsive {
+		t.Errorf("second query = %+v, want unresponsive", again[0])
+	}
+
+	close(release)
+	for deadline := time.Now().Add(time.Second); time.Now().Before(deadline); time.Sleep(time.Millisecond) {
+		if _, pending := pendingMounts.Load("/mnt/hung"); !pending {
+			break
+		}
+	}
+	if again := mountUsage(context.Background(), partitions[1:2], usage, time.Second); again[0].usage == nil {
+		t.Errorf("after the mount recovered = %+v, want usage", again[0])
+	}
+}
+
+func TestMountUsage_Duplicates(t *testing.T) {
+	var calls atomic.Int32
+	usage := func(_ context.Context, path string) (*disk.UsageStat, error) {
+		calls.Add(1)
+		time.Sleep(10 * time.Millisecond)
+		return &disk.UsageStat{Path: path, Total: 1 << 30}, nil
+	}
+	// A bind mount lists /srv twice; both entries get its usage.
+	partitions := []disk.PartitionStat{{Mountpoint: "/srv", Device: "/dev/sda1"}, {Mountpoint: "/"}, {Mountpoint: "/srv", Device: "/dev/sdb1"}}
+	results := mountUsage(context.Background(), partitions, usage, time.Second)
+	for i, result := range results {
+		if result.usage == nil || result.usage.Path != partitions[i].Mountpoint || result.unresponsive {
+			t.Errorf("%s = %+v", partitions[i].Mountpoint, result)
+		}
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("usage queried %d times, want 2", got)
+	}
+}
+
+func TestHasGPUDevices(t *testing.T) {
+	tests := []struct {
+		name  string
+		files []string
+		want  bool
+	}{
+		{"none", []string{"sys/class/drm/version"}, false},
+		{"drm card", []string{"sys/class/drm/card0/device"}, true},
+		{"render node only", []string{"sys/class/drm/renderD128/device"}, false},
+		{"nvidia driver", []string{"proc/driver/nvidia/gpus/0000:3b:00.0/information"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for _, f := range tt.files {
+				path := filepath.Join(root, f)
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, nil, 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := hasGPUDevices(root); got != tt.want {
+				t.Errorf("hasGPUDevices() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyGPU(t *testing.T) {
+	tests := []struct {
+		vendor, product string
+		want            GPUInfo
+	}{
+		{"NVIDIA Corporation", "GA102 [GeForce RTX 3090]", GPUInfo{"NVIDIA", "GA102 [GeForce RTX 3090]", "discrete"}},
+		{"Advanced Micro Devices, Inc. [AMD/ATI]", "Navi 21", GPUInfo{"AMD", "Navi 21", "discrete"}},
+		{"Intel Corporation", "Alder Lake-P GT2 [Iris Xe Graphics]", GPUInfo{"Intel", "Alder Lake-P GT2 [Iris Xe Graphics]", "integrated"}},
+		{"Intel Corporation", "DG2 [Arc A770]", GPUInfo{"Intel", "DG2 [Arc A770]", "discrete"}},
+		{"Apple", "Apple M2 Pro", GPUInfo{"Apple", "Apple M2 Pro", "integrated"}},
+		{"Matrox Electronics Systems Ltd.", "", GPUInfo{"Matrox Electronics Systems Ltd.", "Unknown Model", "unknown"}},
+	}
+	for _, tt := range tests {
+		if got := classifyGPU(tt.vendor, tt.product); got != tt.want {
+			t.Errorf("classifyGPU(%q, %q) = %+v, want %+v", tt.vendor, tt.product, got, tt.want)
+		}
+	}
+}
+
+// BenchmarkCollectSystemInfo measures collection without the hardware
+// probes, whose cost depends on the machine: memory, storage, cgroup,
+// runtime, and time still run for real.
+func BenchmarkCollectSystemInfo(b *testing.B) {
+	opts := SystemOptions{
+		SkipNetwork: true,
+		Collectors: Collectors{
+			HostInfo: func(context.Context) (HostInfo, error) {
+				return HostInfo{OS: "linux", Platform: "ubuntu 24.04", Kernel: "6.8.0", Architecture: "x86_64"}, nil
+			},
+			CPUInfo: func(context.Context) (CPUInfo, error) {
+				return CPUInfo{Model: "AMD Ryzen AI 9 HX 370", Vendor: "AuthenticAMD", Cores: 12}, nil
+			},
+			GPU: func(context.Context) []GPUInfo {
+				return []GPUInfo{{Vendor: "AMD", Model: "Radeon 890M", Type: "integrated"}}
+			},
+		},
+	}
+	ctx := context.Background()
+	b.ReportAllocs()
+	for b.Loop() {
+		CollectSystemInfo(ctx, opts)
+	}
+}
@@ -0,0 +1,137 @@
+package meta
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ToolInfo represents the detected version of a developer tool.
+type ToolInfo struct {
+	Name    string `json:"name" yaml:"name"`
+	Found   bool   `json:"found" yaml:"found"`
+	Path    string `json:"path,omitempty" yaml:"path,omitempty"`
+	Version string `json:"version,omitempty" yaml:"version,omitempty"`
+	Error   string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// toolProbe describes how to query a tool for its version.
+type toolProbe struct {
+	Name       string
+	Binaries   []string // candidates tried in order, e.g. python3 then python
+	VersionArg []string
+}
+
+// defaultToolProbes lists the tools reported by `ado meta tools`.
+var defaultToolProbes = []toolProbe{
+	{Name: "git", Binaries: []string{"git"}, VersionArg: []string{"--version"}},
+	{Name: "docker", Binaries: []string{"docker"}, VersionArg: []string{"--version"}},
+	{Name: "kubectl", Binaries: []string{"kubectl"}, VersionArg: []string{"version", "--client"}},
+	{Name: "go", Binaries: []string{"go"}, VersionArg: []string{"version"}},
+	{Name: "node", Binaries: []string{"node"}, VersionArg: []string{"--version"}},
+	{Name: "python", Binaries: []string{"python3", "python"}, VersionArg: []string{"--version"}},
+	{Name: "make", Binaries: []string{"make"}, VersionArg: []string{"--version"}},
+}
+
+// DefaultToolTimeout bounds how long a single tool may take to report its version.
+const DefaultToolTimeout = 2 * time.Second
+
+// versionPattern matches the first dotted version number in tool output.
+var versionPattern = regexp.MustCompile(`\d+\.\d+(?:\.\d+)?(?:[-+][0-9A-Za-z.]+)?`)
+
+// toolEnv abstracts binary lookup and execution so tests can fake tools.
+type toolEnv struct {
+	lookPath func(file string) (string, error)
+	run      func(ctx context.Context, path string, args ...string) (string, error)
+}
+
+func defaultToolEnv() toolEnv {
+	return toolEnv{
+		lookPath: exec.LookPath,
+		run: func(ctx context.Context, path string, args ...string) (string, error) {
+			out, err := exec.CommandContext(ctx, path, args...).CombinedOutput()
+			return string(out), err
+		},
+	}
+}
+
+// CollectToolInfo detects versions of commonly used developer tools.
+// Each tool is probed concurrently and bounded by timeout; tools that are
+// missing or fail to respond are reported with Found=false or an Error
+// rather than failing the whole collection.
+func CollectToolInfo(ctx context.Context, timeout time.Duration) []ToolInfo {
+	return collectTools(ctx, defaultToolEnv(), defaultToolProbes, timeout)
+}
+
+func collectTools(ctx context.Context, env toolEnv, probes []toolProbe, timeout time.Duration) []ToolInfo {
+	if timeout <= 0 {
+		timeout = DefaultToolTimeout
+	}
+
+	results := make([]ToolInfo, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func(i int, probe toolProbe) {
+			defer wg.Done()
+			results[i] = probeTool(ctx, env, probe, timeout)
+		}(i, probe)
+	}
+	wg.Wait()
+
+	return results
+}
+
+func probeTool(ctx context.Context, env toolEnv, probe toolProbe, timeout time.Duration) ToolInfo {
+	info := ToolInfo{Name: probe.Name}
+
+	for _, binary := range probe.Binaries {
+		if path, err := env.lookPath(binary); err == nil {
+			info.Path = path
+			break
+		}
+	}
+	if info.Path == "" {
+		slog.DebugContext(ctx, "Tool not found", "tool", probe.Name)
+		return info
+	}
+	info.Found = true
+
+	runCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	out, err := env.run(runCtx, info.Path, probe.VersionArg...)
+	if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+		info.Error = "timed out after " + timeout.String()
+		return info
+	}
+	if err != nil && out == "" {
+		info.Error = err.Error()
+		return info
+	}
+
+	info.Version = parseToolVersion(out)
+	if info.Version == "" {
+		info.Error = "unrecognized version output"
+	}
+	slog.DebugContext(ctx, "Detected tool", "tool", probe.Name, "path", info.Path, "version", info.Version)
+	return info
+}
+
+// parseToolVersion extracts the first version number from the first
+// non-empty line of a tool's version output.
+func parseToolVersion(output string) string {
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		return versionPattern.FindString(line)
+	}
+	return ""
+}
@@ -0,0 +1,85 @@
+package meta
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseToolVersion(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{"git", "git version 2.43.0\n", "2.43.0"},
+		{"docker", "Docker version 24.0.7, build afdd53b\n", "24.0.7"},
+		{"kubectl", "Client Version: v1.29.2\nKustomize Version: v5.0.4\n", "1.29.2"},
+		{"go", "go version go1.22.0 linux/amd64\n", "1.22.0"},
+		{"node", "v20.11.1\n", "20.11.1"},
+		{"python", "Python 3.12.1\n", "3.12.1"},
+		{"make", "\nGNU Make 4.3\nBuilt for x86_64-pc-linux-gnu\n", "4.3"},
+		{"prerelease", "tool 1.2.3-rc.1\n", "1.2.3-rc.1"},
+		{"no version", "usage: tool [flags]\n", ""},
+		{"empty", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseToolVersion(tt.output); got != tt.want {
+				t.Errorf("parseToolVersion(%q) = %q, want %q", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectTools(t *testing.T) {
+	env := toolEnv{
+		lookPath: func(file string) (string, error) {
+			switch file {
+			case "git", "python", "slow", "broken":
+				return "/usr/bin/" + file, nil
+			}
+			return "", errors.New("not found")
+		},
+		run: func(ctx context.Context, path string, args ...string) (string, error) {
+			switch path {
+			case "/usr/bin/git":
+				return "git version 2.43.0\n", nil
+			case "/usr/bin/python":
+				return "Python 3.11.4\n", nil
+			case "/usr/bin/slow":
+				<-ctx.Done()
+				return "", ctx.Err()
+			}
+			return "", errors.New("exit status 1")
+		},
+	}
+
+	probes := []toolProbe{
+		{Name: "git", Binaries: []string{"git"}},
+		{Name: "python", Binaries: []string{"python3", "python"}},
+		{Name: "docker", Binaries: []string{"docker"}},
+		{Name: "slow", Binaries: []string{"slow"}},
+		{Name: "broken", Binaries: []string{"broken"}},
+	}
+
+	got := collectTools(context.Background(), env, probes, 20*time.Millisecond)
+	want := []ToolInfo{
+		{Name: "git", Found: true, Path: "/usr/bin/git", Version: "2.43.0"},
+		{Name: "python", Found: true, Path: "/usr/bin/python", Version: "3.11.4"},
+		{Name: "docker", Found: false},
+		{Name: "slow", Found: true, Path: "/usr/bin/slow", Error: "timed out after 20ms"},
+		{Name: "broken", Found: true, Path: "/usr/bin/broken", Error: "exit status 1"},
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf("collectTools() returned %d results, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("collectTools()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,8 @@
+//go:build !linux && !darwin
+
+package meta
+
+// collectUlimits returns no limits on platforms without POSIX rlimits.
+func collectUlimits() []UlimitInfo {
+	return []UlimitInfo{}
+}
@@ -0,0 +1,35 @@
+//go:build linux || darwin
+
+package meta
+
+import "golang.org/x/sys/unix"
+
+// reportedRlimits lists the process resource limits included in SecurityInfo.
+var reportedRlimits = []struct {
+	name     string
+	resource int
+}{
+	{"nofile", unix.RLIMIT_NOFILE},
+	{"nproc", unix.RLIMIT_NPROC},
+	{"stack", unix.RLIMIT_STACK},
+	{"core", unix.RLIMIT_CORE},
+	{"memlock", unix.RLIMIT_MEMLOCK},
+	{"as", unix.RLIMIT_AS},
+}
+
+// collectUlimits returns the soft and hard resource limits of the current process.
+func collectUlimits() []UlimitInfo {
+	limits := []UlimitInfo{}
+	for _, rl := range reportedRlimits {
+		var lim unix.Rlimit
+		if err := unix.Getrlimit(rl.resource, &lim); err != nil {
+			continue
+		}
+		limits = append(limits, UlimitInfo{
+			Name: rl.name,
+			Soft: formatRlimit(lim.Cur, unix.RLIM_INFINITY),
+			Hard: formatRlimit(lim.Max, unix.RLIM_INFINITY),
+		})
+	}
+	return limits
+}
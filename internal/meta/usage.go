@@ -0,0 +1,131 @@
+package meta
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shirou/gopsutil/v4/cpu"
+)
+
+// nvidiaSMITimeout bounds a single nvidia-smi invocation.
+const nvidiaSMITimeout = 2 * time.Second
+
+// nvidiaSMIQuery is the --query-gpu field list parsed by parseNvidiaSMI.
+const nvidiaSMIQuery = "index,name,utilization.gpu,memory.used,memory.total,temperature.gpu"
+
+// Usage is a point-in-time sample of resource utilization. Unlike
+// SystemInfo it is cheap enough to collect every second or two.
+type Usage struct {
+	Time    time.Time     `json:"time" yaml:"time"`
+	CPU     CPUUsage      `json:"cpu" yaml:"cpu"`
+	Memory  MemoryInfo    `json:"memory" yaml:"memory"`
+	Storage []StorageInfo `json:"storage" yaml:"storage"`
+	GPU     []GPUUsage    `json:"gpu" yaml:"gpu"`
+	// GPUSource names where GPU utilization came from: nvidia-smi, or none
+	// when no supported tool is installed.
+	GPUSource string `json:"gpu_source" yaml:"gpu_source"`
+}
+
+// CPUUsage is CPU utilization since the previous sample.
+type CPUUsage struct {
+	TotalPercent float64   `json:"total_percent" yaml:"total_percent"`
+	PerCore      []float64 `json:"per_core" yaml:"per_core"`
+}
+
+// GPUUsage is the utilization of one GPU.
+type GPUUsage struct {
+	Index              int     `json:"index" yaml:"index"`
+	Model              string  `json:"model" yaml:"model"`
+	UtilizationPercent float64 `json:"utilization_percent" yaml:"utilization_percent"`
+	MemoryUsedMB       uint64  `json:"memory_used_mb" yaml:"memory_used_mb"`
+	MemoryTotalMB      uint64  `json:"memory_total_mb" yaml:"memory_total_mb"`
+	TemperatureC       float64 `json:"temperature_c" yaml:"temperature_c"` // 0 = unknown
+}
+
+// UsageSampler collects Usage samples. CPU percentages are measured
+// between consecutive calls to Sample, so the first sample reports usage
+// since boot.
+type UsageSampler struct {
+	env toolEnv
+}
+
+// NewUsageSampler returns a sampler backed by the host's collectors.
+func NewUsageSampler() *UsageSampler {
+	return &UsageSampler{env: defaultToolEnv()}
+}
+
+// Sample collects current utilization. Like CollectSystemInfo it never
+// fails: sections that cannot be read are left empty.
+func (s *UsageSampler) Sample(ctx context.Context) Usage {
+	usage := Usage{
+		Time:    time.Now(),
+		Memory:  collectMemory(ctx),
+		Storage: collectStorage(ctx, nil),
+		GPU:     []GPUUsage{},
+	}
+
+	if total, err := cpu.PercentWithContext(ctx, 0, false); err == nil && len(total) > 0 {
+		usage.CPU.TotalPercent = total[0]
+	} else if err != nil {
+		slog.DebugContext(ctx, "CPU usage detection failed", "error", err)
+	}
+	if perCore, err := cpu.PercentWithContext(ctx, 0, true); err == nil {
+		usage.CPU.PerCore = perCore
+	} else {
+		slog.DebugContext(ctx, "Per-core CPU usage detection failed", "error", err)
+	}
+
+	usage.GPU, usage.GPUSource = s.gpuUsage(ctx)
+	return usage
+}
+
+func (s *UsageSampler) gpuUsage(ctx context.Context) ([]GPUUsage, string) {
+	smi, err := s.env.lookPath("nvidia-smi")
+	if err != nil {
+		return []GPUUsage{}, "none"
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, nvidiaSMITimeout)
+	defer cancel()
+	out, err := s.env.run(runCtx, smi, "--query-gpu="+nvidiaSMIQuery, "--format=csv,noheader,nounits")
+	if err != nil {
+		slog.DebugContext(ctx, "nvidia-smi failed", "error", err)
+		return []GPUUsage{}, "nvidia-smi"
+	}
+	return parseNvidiaSMI(out), "nvidia-smi"
+}
+
+// parseNvidiaSMI parses nvidia-smi CSV output for nvidiaSMIQuery. Fields
+// the driver reports as "[N/A]" or "[Not Supported]" are left zero.
+func parseNvidiaSMI(out string) []GPUUsage {
+	gpus := []GPUUsage{}
+	for _, line := range strings.Split(out, "\n") {
+		fields := strings.Split(line, ",")
+		if len(fields) != 6 {
+			continue
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		index, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+		util, _ := strconv.ParseFloat(fields[2], 64)
+		memUsed, _ := strconv.ParseUint(fields[3], 10, 64)
+		memTotal, _ := strconv.ParseUint(fields[4], 10, 64)
+		temp, _ := strconv.ParseFloat(fields[5], 64)
+		gpus = append(gpus, GPUUsage{
+			Index:              index,
+			Model:              fields[1],
+			UtilizationPercent: util,
+			MemoryUsedMB:       memUsed,
+			MemoryTotalMB:      memTotal,
+			TemperatureC:       temp,
+		})
+	}
+	return gpus
+}
@@ -0,0 +1,91 @@
+package meta
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestParseNvidiaSMI(t *testing.T) {
+	out := "0, NVIDIA A100-SXM4-40GB, 87, 30123, 40960, 61\n" +
+		"1, NVIDIA A100-SXM4-40GB, [N/A], 0, 40960, [N/A]\n" +
+		"garbage line\n"
+	want := []GPUUsage{
+		{Index: 0, Model: "NVIDIA A100-SXM4-40GB", UtilizationPercent: 87, MemoryUsedMB: 30123, MemoryTotalMB: 40960, TemperatureC: 61},
+		{Index: 1, Model: "NVIDIA A100-SXM4-40GB", MemoryTotalMB: 40960},
+	}
+	if got := parseNvidiaSMI(out); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseNvidiaSMI() = %+v, want %+v", got, want)
+	}
+}
+
+func TestUsageSampler_GPU(t *testing.T) {
+	tests := []struct {
+		name       string
+		env        toolEnv
+		wantGPUs   int
+		wantSource string
+	}{
+		{
+			name: "nvidia-smi",
+			env: toolEnv{
+				lookPath: func(string) (string, error) { return "/usr/bin/nvidia-smi", nil },
+				run: func(context.Context, string, ...string) (string, error) {
+					return "0, Tesla T4, 12, 100, 15360, 40\n", nil
+				},
+			},
+			wantGPUs:   1,
+			wantSource: "nvidia-smi",
+		},
+		{
+			name: "nvidia-smi fails",
+			env: toolEnv{
+				lookPath: func(string) (string, error) { return "/usr/bin/nvidia-smi", nil },
+				run: func(context.Context, string, ...string) (string, error) {
+					return "", errors.New("driver not loaded")
+				},
+			},
+			wantSource: "nvidia-smi",
+		},
+		{
+			name: "not installed",
+			env: toolEnv{
+				lookPath: func(string) (string, error) { return "", exec.ErrNotFound },
+			},
+			wantSource: "none",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &UsageSampler{env: tt.env}
+			gpus, source := s.gpuUsage(context.Background())
+			if len(gpus) != tt.wantGPUs || source != tt.wantSource {
+				t.Errorf("gpuUsage() = %d GPUs from %q, want %d from %q", len(gpus), source, tt.wantGPUs, tt.wantSource)
+			}
+			if gpus == nil {
+				t.Error("gpuUsage() returned nil, want empty slice")
+			}
+		})
+	}
+}
+
+func TestUsageSampler_Sample(t *testing.T) {
+	s := &UsageSampler{env: toolEnv{lookPath: func(string) (string, error) { return "", exec.ErrNotFound }}}
+	usage := s.Sample(context.Background())
+	if usage.Time.IsZero() {
+		t.Error("Time not set")
+	}
+	if usage.Storage == nil || usage.GPU == nil {
+		t.Error("Storage and GPU must be non-nil")
+	}
+	if usage.CPU.TotalPercent < 0 || usage.CPU.TotalPercent > 100 {
+		t.Errorf("TotalPercent = %v, want 0-100", usage.CPU.TotalPercent)
+	}
+	for _, volume := range usage.Storage {
+		if volume.Health != nil {
+			t.Errorf("%s: health queried in a usage sample", volume.Mountpoint)
+		}
+	}
+}
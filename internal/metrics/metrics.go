@@ -0,0 +1,150 @@
+// Package metrics sends command and task metrics to a StatsD agent, so
+// automation driven by ado can be monitored without parsing logs. Metrics
+// carry DogStatsD tags, which the Datadog agent, Telegraf, and the
+// Prometheus statsd_exporter understand.
+//
+// Emission is opt-in through the metrics.statsd section of the config
+// file, and best-effort: metrics go out over UDP and a failed send never
+// fails the command.
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anowarislam/ado/internal/config"
+)
+
+// Kinds of run recorded by Client.Run. Each yields the counter KIND.runs
+// and the timer KIND.duration, before the configured prefix.
+const (
+	// Command runs are tagged with the command path, such as meta_system.
+	Command = "command"
+	// Task runs are tagged with the config task name.
+	Task = "task"
+)
+
+// DefaultPrefix is prepended to metric names when the config sets none.
+const DefaultPrefix = "ado."
+
+// Values of the status tag.
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+)
+
+// Client sends metrics to a StatsD agent. A nil *Client discards them, so
+// callers need not check whether emission is enabled. It is safe for
+// concurrent use.
+type Client struct {
+	mu     sync.Mutex
+	w      io.Writer
+	prefix string
+	tags   []string
+}
+
+// New returns a client for cfg, or nil when cfg sets no address.
+func New(cfg config.StatsDConfig) (*Client, error) {
+	if cfg.Address == "" {
+		return nil, nil
+	}
+	conn, err := net.Dial("udp", cfg.Address)
+	if err != nil {
+		return nil, fmt.Errorf("statsd %s: %w", cfg.Address, err)
+	}
+	return NewClient(conn, cfg), nil
+}
+
+// NewClient returns a client that writes each metric to w with a single
+// Write, as a datagram would carry it. cfg.Address is ignored.
+func NewClient(w io.Writer, cfg config.StatsDConfig) *Client {
+	prefix := cfg.Prefix
+	if prefix == "" {
+		prefix = DefaultPrefix
+	}
+	var tags []string
+	for _, key := range slices.Sorted(maps.Keys(cfg.Tags)) {
+		tags = append(tags, Tag(key, cfg.Tags[key]))
+	}
+	return &Client{w: w, prefix: prefix, tags: tags}
+}
+
+// Count adds value to the counter name.
+func (c *Client) Count(name string, value int64, tags ...string) {
+	c.send(name, strconv.FormatInt(value, 10), "c", tags)
+}
+
+// Timing records d, in milliseconds, for the timer name.
+func (c *Client) Timing(name string, d time.Duration, tags ...string) {
+	c.send(name, strconv.FormatFloat(float64(d)/float64(time.Millisecond), 'f', -1, 64), "ms", tags)
+}
+
+// Run records one run of kind that took d and exited with exitCode: a
+// count of KIND.runs and a timing of KIND.duration, both tagged with
+// status and exit_code.
+func (c *Client) Run(kind string, d time.Duration, exitCode int, tags ...string) {
+	status := StatusSuccess
+	if exitCode != 0 {
+		status = StatusFailure
+	}
+	tags = append(tags, Tag("status", status), Tag("exit_code", strconv.Itoa(exitCode)))
+	c.Count(kind+".runs", 1, tags...)
+	c.Timing(kind+".duration", d, tags...)
+}
+
+// Close releases the connection to the agent.
+func (c *Client) Close() error {
+	if c == nil {
+		return nil
+	}
+	if closer, ok := c.w.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// send writes one metric in the DogStatsD line format,
+// prefix.name:value|kind|#tag,tag. Errors are dropped: the agent may be
+// down, and metrics must not fail the work they measure.
+func (c *Client) send(name, value, kind string, tags []string) {
+	if c == nil {
+		return
+	}
+	var b strings.Builder
+	b.WriteString(c.prefix + name + ":" + value + "|" + kind)
+	if all := append(slices.Clone(c.tags), tags...); len(all) > 0 {
+		b.WriteString("|#" + strings.Join(all, ","))
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, _ = io.WriteString(c.w, b.String())
+}
+
+// Tag formats a DogStatsD tag, replacing the characters the protocol
+// reserves and blanks with underscores.
+func Tag(key, value string) string {
+	clean := strings.NewReplacer("|", "_", ",", "_", "#", "_", " ", "_", "\t", "_", "\n", "_")
+	return clean.Replace(key) + ":" + clean.Replace(value)
+}
+
+// WithClient returns a new context carrying c.
+func WithClient(ctx context.Context, c *Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, c)
+}
+
+// FromContext returns the client in ctx, or nil.
+func FromContext(ctx context.Context) *Client {
+	c, _ := ctx.Value(clientKey{}).(*Client)
+	return c
+}
+
+// clientKey is the context key for storing the Client.
+type clientKey struct{}
@@ -0,0 +1,110 @@
+package metrics
+
+import (
+	"context"
+	"net"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anowarislam/ado/internal/config"
+)
+
+// packets records each Write as one metric line.
+type packets []string
+
+func (p *packets) Write(b []byte) (int, error) {
+	*p = append(*p, string(b))
+	return len(b), nil
+}
+
+func TestClient(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.StatsDConfig
+		send func(*Client)
+		want []string
+	}{
+		{
+			"count with default prefix",
+			config.StatsDConfig{},
+			func(c *Client) { c.Count("deploys", 2) },
+			[]string{"ado.deploys:2|c"},
+		},
+		{
+			"timing with global and metric tags",
+			config.StatsDConfig{Prefix: "ci.", Tags: map[string]string{"team": "infra", "env": "prod"}},
+			func(c *Client) { c.Timing("step", 1500*time.Microsecond, Tag("step", "build")) },
+			[]string{"ci.step:1.5|ms|#env:prod,team:infra,step:build"},
+		},
+		{
+			"run",
+			config.StatsDConfig{},
+			func(c *Client) { c.Run(Task, 2*time.Second, 3, Tag("task", "test")) },
+			[]string{
+				"ado.task.runs:1|c|#task:test,status:failure,exit_code:3",
+				"ado.task.duration:2000|ms|#task:test,status:failure,exit_code:3",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got packets
+			tt.send(NewClient(&got, tt.cfg))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("sent %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTag(t *testing.T) {
+	if got := Tag("command", "meta system|x,y#z"); got != "command:meta_system_x_y_z" {
+		t.Errorf("Tag() = %q", got)
+	}
+}
+
+func TestNilClient(t *testing.T) {
+	var c *Client
+	c.Count("x", 1)
+	c.Run(Command, time.Second, 0)
+	if err := c.Close(); err != nil {
+		t.Error(err)
+	}
+	if FromContext(context.Background()) != nil {
+		t.Error("FromContext() without a client should be nil")
+	}
+}
+
+func TestNew(t *testing.T) {
+	if c, err := New(config.StatsDConfig{}); c != nil || err != nil {
+		t.Errorf("New() without an address = %v, %v; want nil, nil", c, err)
+	}
+	if _, err := New(config.StatsDConfig{Address: "no-port"}); err == nil || !strings.Contains(err.Error(), "statsd no-port") {
+		t.Errorf("New(no-port) error = %v", err)
+	}
+
+	agent, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skip("no UDP:", err)
+	}
+	defer agent.Close()
+	c, err := New(config.StatsDConfig{Address: agent.LocalAddr().String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+	ctx := WithClient(context.Background(), c)
+	FromContext(ctx).Count("hits", 1)
+
+	buf := make([]byte, 512)
+	_ = agent.SetReadDeadline(time.Now().Add(5 * time.Second))
+	n, _, err := agent.ReadFrom(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := string(buf[:n]); got != "ado.hits:1|c" {
+		t.Errorf("agent received %q", got)
+	}
+}
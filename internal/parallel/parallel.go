@@ -0,0 +1,178 @@
+// Package parallel runs a command template once per input item with
+// bounded concurrency.
+package parallel
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anowarislam/ado/internal/process"
+)
+
+// Placeholder is replaced by the item in every template argument. When no
+// argument contains it, the item is appended as the last argument.
+const Placeholder = "{}"
+
+// Result statuses.
+const (
+	StatusOK       = "ok"
+	StatusFailed   = "failed"
+	StatusSkipped  = "skipped"
+	StatusCanceled = "canceled"
+)
+
+// Options controls Run.
+type Options struct {
+	// Jobs is the maximum number of commands running at once.
+	Jobs int
+	// FailFast stops starting new items after the first failure and
+	// cancels the ones still running, which are reported as canceled.
+	FailFast bool
+	// OnDone, when set, is called with each result as its command finishes.
+	// Calls are serialized.
+	OnDone func(Result)
+}
+
+// Result is the outcome of one item.
+type Result struct {
+	Index   int      `json:"index" yaml:"index"`
+	Item    string   `json:"item" yaml:"item"`
+	Command []string `json:"command" yaml:"command"`
+	Status  string   `json:"status" yaml:"status"`
+	// ExitCode is -1 when the command did not run to completion.
+	ExitCode   int    `json:"exit_code" yaml:"exit_code"`
+	DurationMS int64  `json:"duration_ms" yaml:"duration_ms"`
+	Stdout     string `json:"stdout" yaml:"stdout"`
+	Stderr     string `json:"stderr" yaml:"stderr"`
+	Error      string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Report aggregates the results of a run.
+type Report struct {
+	Total      int      `json:"total" yaml:"total"`
+	Succeeded  int      `json:"succeeded" yaml:"succeeded"`
+	Failed     int      `json:"failed" yaml:"failed"`
+	Skipped    int      `json:"skipped" yaml:"skipped"`
+	Canceled   int      `json:"canceled" yaml:"canceled"`
+	DurationMS int64    `json:"duration_ms" yaml:"duration_ms"`
+	Results    []Result `json:"results" yaml:"results"`
+}
+
+// Expand returns template with item substituted for Placeholder.
+func Expand(template []string, item string) []string {
+	args := make([]string, 0, len(template)+1)
+	substituted := false
+	for _, arg := range template {
+		if strings.Contains(arg, Placeholder) {
+			substituted = true
+		}
+		args = append(args, strings.ReplaceAll(arg, Placeholder, item))
+	}
+	if !substituted {
+		args = append(args, item)
+	}
+	return args
+}
+
+// Run executes template once per item and returns the results in item
+// order. Commands get no stdin; their output is captured.
+func Run(ctx context.Context, items, template []string, opts Options) Report {
+	if opts.Jobs < 1 {
+		opts.Jobs = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	started := time.Now()
+	report := Report{Total: len(items), Results: make([]Result, len(items))}
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	slots := make(chan struct{}, opts.Jobs)
+
+	for i, item := range items {
+		result := Result{Index: i, Item: item, Command: Expand(template, item), Status: StatusSkipped, ExitCode: -1}
+
+		// Once the run is canceled, the remaining items are skipped.
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			report.Results[i] = result
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			result = run(ctx, result)
+			mu.Lock()
+			defer mu.Unlock()
+			report.Results[i] = result
+			if result.Status == StatusFailed && opts.FailFast {
+				cancel()
+			}
+			if opts.OnDone != nil {
+				opts.OnDone(result)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, r := range report.Results {
+		switch r.Status {
+		case StatusOK:
+			report.Succeeded++
+		case StatusFailed:
+			report.Failed++
+		case StatusCanceled:
+			report.Canceled++
+		default:
+			report.Skipped++
+		}
+	}
+	report.DurationMS = time.Since(started).Milliseconds()
+	return report
+}
+
+func run(ctx context.Context, result Result) Result {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, result.Command[0], result.Command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	process.NewGroup(cmd)
+	process.Graceful(cmd, process.GracePeriod)
+
+	started := time.Now()
+	err := cmd.Run()
+	result.DurationMS = time.Since(started).Milliseconds()
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		result.Status = StatusOK
+		result.ExitCode = 0
+	case ctx.Err() != nil:
+		result.Status = StatusCanceled
+		result.Error = ctx.Err().Error()
+	case errors.As(err, &exitErr) && exitErr.ExitCode() > 0:
+		result.Status = StatusFailed
+		result.ExitCode = exitErr.ExitCode()
+		result.Error = err.Error()
+	default:
+		result.Status = StatusFailed
+		result.Error = err.Error()
+	}
+	return result
+}
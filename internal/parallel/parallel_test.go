@@ -0,0 +1,95 @@
+package parallel
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name     string
+		template []string
+		want     []string
+	}{
+		{"append", []string{"gzip", "-9"}, []string{"gzip", "-9", "a.log"}},
+		{"placeholder", []string{"cp", "{}", "{}.bak"}, []string{"cp", "a.log", "a.log.bak"}},
+		{"embedded", []string{"echo", "file={}"}, []string{"echo", "file=a.log"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Expand(tt.template, "a.log"); !slices.Equal(got, tt.want) {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun_CollectAll(t *testing.T) {
+	var done atomic.Int32
+	opts := Options{Jobs: 2, OnDone: func(Result) { done.Add(1) }}
+	report := Run(context.Background(), []string{"a", "b", "c"}, []string{"sh", "-c", `echo out-$0; echo err-$0 >&2; [ $0 != b ] || exit 7`}, opts)
+
+	if report.Total != 3 || report.Succeeded != 2 || report.Failed != 1 || report.Skipped != 0 {
+		t.Errorf("report = %+v", report)
+	}
+	if done.Load() != 3 {
+		t.Errorf("OnDone called %d times, want 3", done.Load())
+	}
+	for i, item := range []string{"a", "b", "c"} {
+		r := report.Results[i]
+		if r.Index != i || r.Item != item || r.Stdout != "out-"+item+"\n" || r.Stderr != "err-"+item+"\n" {
+			t.Errorf("result %d = %+v", i, r)
+		}
+	}
+	if b := report.Results[1]; b.Status != StatusFailed || b.ExitCode != 7 || b.Error != "exit status 7" {
+		t.Errorf("failed result = %+v", b)
+	}
+	if a := report.Results[0]; a.Status != StatusOK || a.ExitCode != 0 || a.Error != "" {
+		t.Errorf("ok result = %+v", a)
+	}
+}
+
+func TestRun_Jobs(t *testing.T) {
+	items := []string{"1", "2", "3", "4", "5", "6"}
+	report := Run(context.Background(), items, []string{"sh", "-c", "sleep 0.05"}, Options{Jobs: 2})
+	if report.Succeeded != len(items) {
+		t.Fatalf("report = %+v", report)
+	}
+	// Six 50ms commands, two at a time, take at least 150ms.
+	if report.DurationMS < 140 {
+		t.Errorf("duration = %dms, want at least 150ms with 2 jobs", report.DurationMS)
+	}
+}
+
+func TestRun_FailFast(t *testing.T) {
+	items := []string{"fail", "slow", "later1", "later2"}
+	template := []string{"sh", "-c", `if [ $0 = fail ]; then exit 1; fi; sleep 5`}
+	report := Run(context.Background(), items, template, Options{Jobs: 2, FailFast: true})
+
+	want := []string{StatusFailed, StatusCanceled, StatusSkipped, StatusSkipped}
+	for i, r := range report.Results {
+		if r.Status != want[i] {
+			t.Errorf("%s: status = %q, want %q", r.Item, r.Status, want[i])
+		}
+	}
+	if report.Failed != 1 || report.Canceled != 1 || report.Skipped != 2 {
+		t.Errorf("report = %+v", report)
+	}
+	if report.DurationMS > 4000 {
+		t.Errorf("fail-fast waited %dms for the slow command", report.DurationMS)
+	}
+	if r := report.Results[3]; r.ExitCode != -1 || !slices.Equal(r.Command, []string{"sh", "-c", template[2], "later2"}) {
+		t.Errorf("skipped result = %+v", r)
+	}
+}
+
+func TestRun_StartError(t *testing.T) {
+	report := Run(context.Background(), []string{"x"}, []string{"/nonexistent/ado-test-cmd"}, Options{Jobs: 1})
+	r := report.Results[0]
+	if r.Status != StatusFailed || r.ExitCode != -1 || !strings.Contains(r.Error, "no such file") {
+		t.Errorf("result = %+v", r)
+	}
+}
@@ -0,0 +1,52 @@
+package process
+
+import (
+	"maps"
+	"runtime"
+	"slices"
+	"strings"
+)
+
+// EnvKey returns the form of the variable name under which the platform
+// looks it up: upper case on Windows, whose environment is
+// case-insensitive, and name itself elsewhere.
+func EnvKey(name string) string {
+	return envKey(name, runtime.GOOS == "windows")
+}
+
+func envKey(name string, fold bool) string {
+	if fold {
+		return strings.ToUpper(name)
+	}
+	return name
+}
+
+// MergeEnv returns env, a list of KEY=VALUE pairs such as os.Environ(),
+// with the variables of overrides set. Inherited entries for those
+// variables are dropped, compared as EnvKey does, and overrides are
+// appended in name order. env is returned as is when overrides is empty.
+func MergeEnv(env []string, overrides map[string]string) []string {
+	return mergeEnv(env, overrides, runtime.GOOS == "windows")
+}
+
+func mergeEnv(env []string, overrides map[string]string, fold bool) []string {
+	if len(overrides) == 0 {
+		return env
+	}
+	set := make(map[string]bool, len(overrides))
+	for key := range overrides {
+		set[envKey(key, fold)] = true
+	}
+
+	merged := make([]string, 0, len(env)+len(overrides))
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		if !set[envKey(key, fold)] {
+			merged = append(merged, kv)
+		}
+	}
+	for _, key := range slices.Sorted(maps.Keys(overrides)) {
+		merged = append(merged, key+"="+overrides[key])
+	}
+	return merged
+}
@@ -0,0 +1,54 @@
+package process
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMergeEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       []string
+		overrides map[string]string
+		fold      bool
+		want      []string
+	}{
+		{
+			name: "no overrides",
+			env:  []string{"B=1", "A=2"},
+			want: []string{"B=1", "A=2"},
+		},
+		{
+			name:      "overrides replace and append in order",
+			env:       []string{"PATH=/bin", "HOME=/root", "A=old"},
+			overrides: map[string]string{"Z": "z", "A": "new"},
+			want:      []string{"PATH=/bin", "HOME=/root", "A=new", "Z=z"},
+		},
+		{
+			name:      "value containing equals",
+			env:       []string{"OPTS=a=b"},
+			overrides: map[string]string{"OPTS": "c=d"},
+			want:      []string{"OPTS=c=d"},
+		},
+		{
+			name:      "case-sensitive names",
+			env:       []string{"Path=/bin"},
+			overrides: map[string]string{"PATH": "/usr/bin"},
+			want:      []string{"Path=/bin", "PATH=/usr/bin"},
+		},
+		{
+			name:      "case-insensitive names",
+			env:       []string{"Path=C:\\Windows", "TEMP=C:\\Temp"},
+			overrides: map[string]string{"PATH": "C:\\bin"},
+			fold:      true,
+			want:      []string{"TEMP=C:\\Temp", "PATH=C:\\bin"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mergeEnv(tt.env, tt.overrides, tt.fold); !slices.Equal(got, tt.want) {
+				t.Errorf("mergeEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
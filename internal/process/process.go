@@ -0,0 +1,23 @@
+// Package process configures child processes so that canceling their
+// context stops them gracefully: they are asked to terminate and only
+// killed if they outlive a grace period. It also builds their environment
+// from the inherited one.
+package process
+
+import (
+	"os/exec"
+	"time"
+)
+
+// GracePeriod is how long a canceled child may take to exit after it is
+// asked to terminate before it is killed. It also bounds how long the
+// child's own children may keep its output open afterwards.
+const GracePeriod = 5 * time.Second
+
+// Graceful makes cmd, which must come from exec.CommandContext, receive
+// SIGTERM instead of SIGKILL when its context is done. A child still
+// running after grace is killed. Call it before starting cmd.
+func Graceful(cmd *exec.Cmd, grace time.Duration) {
+	cmd.Cancel = func() error { return terminate(cmd, grace) }
+	cmd.WaitDelay = grace
+}
@@ -0,0 +1,109 @@
+//go:build !windows
+
+package process
+
+import (
+	"context"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestGraceful_Terminates(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var out syncBuffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", `trap 'echo stopping; exit 3' TERM; echo ready; while :; do sleep 0.05; done`)
+	cmd.Stdout = &out
+	Graceful(cmd, GracePeriod)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return strings.Contains(out.String(), "ready") })
+
+	started := time.Now()
+	cancel()
+	_ = cmd.Wait()
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("Wait() took %s after cancel", elapsed)
+	}
+	if got := cmd.ProcessState.ExitCode(); got != 3 {
+		t.Errorf("exit code = %d, want 3 from the TERM trap", got)
+	}
+	if !strings.Contains(out.String(), "stopping") {
+		t.Errorf("output = %q, want the trap's message", out.String())
+	}
+}
+
+func TestGraceful_KillsAfterGrace(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var out syncBuffer
+	cmd := exec.CommandContext(ctx, "sh", "-c", `trap '' TERM; echo ready; while :; do sleep 0.05; done`)
+	cmd.Stdout = &out
+	Graceful(cmd, 200*time.Millisecond)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return strings.Contains(out.String(), "ready") })
+
+	started := time.Now()
+	cancel()
+	if err := cmd.Wait(); err == nil {
+		t.Fatal("Wait() = nil, want an error for the killed process")
+	}
+	if elapsed := time.Since(started); elapsed < 200*time.Millisecond || elapsed > 3*time.Second {
+		t.Errorf("Wait() took %s, want about the 200ms grace period", elapsed)
+	}
+}
+
+func TestNewGroup_StopsGrandchildren(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var out syncBuffer
+	// The shell waits on sleep, which holds stdout open until it exits.
+	cmd := exec.CommandContext(ctx, "sh", "-c", "echo ready; sleep 30; true")
+	cmd.Stdout = &out
+	NewGroup(cmd)
+	Graceful(cmd, GracePeriod)
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, func() bool { return strings.Contains(out.String(), "ready") })
+
+	started := time.Now()
+	cancel()
+	_ = cmd.Wait()
+	if elapsed := time.Since(started); elapsed > 2*time.Second {
+		t.Errorf("Wait() took %s, want the whole group terminated", elapsed)
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for the child to start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// syncBuffer is a strings.Builder that is safe to read while the command
+// writes to it.
+type syncBuffer struct {
+	mu sync.Mutex
+	b  strings.Builder
+}
+
+func (s *syncBuffer) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *syncBuffer) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.b.String()
+}
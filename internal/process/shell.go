@@ -0,0 +1,30 @@
+package process
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// ShellScript returns the index in args of the script a POSIX shell
+// command runs, such as that of "echo hi" in sh -c "echo hi", or -1 when
+// command is not a shell running a script.
+func ShellScript(command string, args []string) int {
+	switch filepath.Base(command) {
+	case "sh", "bash", "zsh", "dash", "ksh", "ash":
+	default:
+		return -1
+	}
+	for i, arg := range args[:max(len(args)-1, 0)] {
+		switch {
+		case i > 0 && (args[i-1] == "-o" || args[i-1] == "+o"):
+			continue // the option -o sets, as in -o pipefail
+		case arg == "--" || !strings.HasPrefix(arg, "-") && !strings.HasPrefix(arg, "+"):
+			return -1 // a script file and its arguments
+		}
+		// -c, alone or with other single-letter options, as in -ec.
+		if !strings.HasPrefix(arg, "--") && strings.Contains(arg, "c") {
+			return i + 1
+		}
+	}
+	return -1
+}
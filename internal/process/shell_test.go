@@ -0,0 +1,23 @@
+package process
+
+import "testing"
+
+func TestShellScript(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+		want    int
+	}{
+		{"sh", []string{"-c", "echo"}, 1},
+		{"/bin/bash", []string{"-eu", "-o", "pipefail", "-c", "echo"}, 4},
+		{"bash", []string{"-ec", "echo"}, 1},
+		{"bash", []string{"script.sh", "-c", "x"}, -1},
+		{"sh", []string{"-c"}, -1},
+		{"python", []string{"-c", "print()"}, -1},
+	}
+	for _, tt := range tests {
+		if got := ShellScript(tt.command, tt.args); got != tt.want {
+			t.Errorf("ShellScript(%q, %q) = %d, want %d", tt.command, tt.args, got, tt.want)
+		}
+	}
+}
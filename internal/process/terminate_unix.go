@@ -0,0 +1,31 @@
+//go:build !windows
+
+package process
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// NewGroup starts cmd in its own process group, so that cancellation
+// reaches the processes it starts too, such as the commands run by a
+// shell. Only use it for children that do not read the terminal: a
+// background process group is stopped when it does.
+func NewGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+}
+
+// terminate sends SIGTERM to cmd's process, or to its whole group after
+// NewGroup, in which case the group is killed once grace has passed.
+func terminate(cmd *exec.Cmd, grace time.Duration) error {
+	if cmd.SysProcAttr == nil || !cmd.SysProcAttr.Setpgid {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	pgid := -cmd.Process.Pid
+	time.AfterFunc(grace, func() { _ = syscall.Kill(pgid, syscall.SIGKILL) })
+	return syscall.Kill(pgid, syscall.SIGTERM)
+}
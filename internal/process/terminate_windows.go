@@ -0,0 +1,18 @@
+//go:build windows
+
+package process
+
+import (
+	"os/exec"
+	"time"
+)
+
+// NewGroup does nothing on Windows, where a canceled child is killed
+// outright.
+func NewGroup(cmd *exec.Cmd) {}
+
+// terminate kills cmd's process: Windows cannot deliver SIGTERM to another
+// process.
+func terminate(cmd *exec.Cmd, grace time.Duration) error {
+	return cmd.Process.Kill()
+}
@@ -0,0 +1,134 @@
+// Package profiling exposes ado's own runtime profiles: one-shot dumps for
+// the hidden `ado debug` commands, and net/http/pprof endpoints for
+// long-running commands started with --pprof-listen.
+package profiling
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"runtime"
+	"runtime/debug"
+	rpprof "runtime/pprof"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/logging"
+	"github.com/anowarislam/ado/internal/server"
+)
+
+// ListenFlag is the flag long-running commands accept to serve profiles.
+const ListenFlag = "pprof-listen"
+
+// AddListenFlag registers --pprof-listen on cmd, storing the address in
+// addr.
+func AddListenFlag(cmd *cobra.Command, addr *string) {
+	cmd.Flags().StringVar(addr, ListenFlag, "", "Serve net/http/pprof on this loopback address (host:port) while running")
+}
+
+// Handler returns the pprof endpoints under /debug/pprof/. The cmdline
+// endpoint is left out because arguments may hold tokens.
+func Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	return mux
+}
+
+// Listen serves Handler on addr until ctx is done and returns the address
+// it listens on. Only loopback addresses are accepted: profiles reveal
+// memory contents and stacks.
+func Listen(ctx context.Context, addr string) (net.Addr, error) {
+	if !server.IsLoopback(addr) {
+		return nil, fmt.Errorf("--%s must be a loopback address such as 127.0.0.1:6060, not %q", ListenFlag, addr)
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("pprof listen: %w", err)
+	}
+
+	srv := &http.Server{Handler: Handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Close()
+	}()
+	go func() {
+		if err := srv.Serve(ln); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logging.FromContext(ctx).Warn("pprof server stopped", "error", err)
+		}
+	}()
+	logging.FromContext(ctx).Info("Serving pprof", "addr", "http://"+ln.Addr().String()+"/debug/pprof/")
+	return ln.Addr(), nil
+}
+
+// WriteGoroutines writes the stacks of all goroutines in the format of an
+// unrecovered panic.
+func WriteGoroutines(w io.Writer) error {
+	return rpprof.Lookup("goroutine").WriteTo(w, 2)
+}
+
+// WriteHeap runs a garbage collection and writes a gzipped heap profile
+// for `go tool pprof`.
+func WriteHeap(w io.Writer) error {
+	runtime.GC()
+	return rpprof.WriteHeapProfile(w)
+}
+
+// GCStats summarizes the garbage collector and memory of the process.
+type GCStats struct {
+	NumGC        int64         `json:"num_gc" yaml:"num_gc"`
+	LastGC       time.Time     `json:"last_gc" yaml:"last_gc"`
+	PauseTotal   time.Duration `json:"pause_total_ns" yaml:"pause_total_ns"`
+	HeapAlloc    uint64        `json:"heap_alloc_bytes" yaml:"heap_alloc_bytes"`
+	HeapSys      uint64        `json:"heap_sys_bytes" yaml:"heap_sys_bytes"`
+	HeapObjects  uint64        `json:"heap_objects" yaml:"heap_objects"`
+	NextGC       uint64        `json:"next_gc_bytes" yaml:"next_gc_bytes"`
+	TotalAlloc   uint64        `json:"total_alloc_bytes" yaml:"total_alloc_bytes"`
+	Sys          uint64        `json:"sys_bytes" yaml:"sys_bytes"`
+	Goroutines   int           `json:"goroutines" yaml:"goroutines"`
+	GOMAXPROCS   int           `json:"gomaxprocs" yaml:"gomaxprocs"`
+	MemoryLimit  int64         `json:"memory_limit_bytes" yaml:"memory_limit_bytes"`
+	GoVersion    string        `json:"go_version" yaml:"go_version"`
+	CollectedAt  time.Time     `json:"collected_at" yaml:"collected_at"`
+	RecentPauses []string      `json:"recent_pauses,omitempty" yaml:"recent_pauses,omitempty"`
+}
+
+// maxRecentPauses bounds the pause durations ReadGCStats reports.
+const maxRecentPauses = 5
+
+// ReadGCStats returns the current GC and memory statistics.
+func ReadGCStats() GCStats {
+	var gc debug.GCStats
+	debug.ReadGCStats(&gc)
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+
+	stats := GCStats{
+		NumGC:       gc.NumGC,
+		LastGC:      gc.LastGC,
+		PauseTotal:  gc.PauseTotal,
+		HeapAlloc:   mem.HeapAlloc,
+		HeapSys:     mem.HeapSys,
+		HeapObjects: mem.HeapObjects,
+		NextGC:      mem.NextGC,
+		TotalAlloc:  mem.TotalAlloc,
+		Sys:         mem.Sys,
+		Goroutines:  runtime.NumGoroutine(),
+		GOMAXPROCS:  runtime.GOMAXPROCS(0),
+		// A negative limit reads the current one without changing it.
+		MemoryLimit: debug.SetMemoryLimit(-1),
+		GoVersion:   runtime.Version(),
+		CollectedAt: time.Now(),
+	}
+	for i := 0; i < len(gc.Pause) && i < maxRecentPauses; i++ {
+		stats.RecentPauses = append(stats.RecentPauses, gc.Pause[i].String())
+	}
+	return stats
+}
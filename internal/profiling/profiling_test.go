@@ -0,0 +1,76 @@
+package profiling
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestListen(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	addr, err := Listen(ctx, "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	base := "http://" + addr.String() + "/debug/pprof/"
+
+	resp, err := http.Get(base + "goroutine?debug=1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !strings.Contains(string(body), "goroutine profile") {
+		t.Errorf("GET goroutine = %d %q", resp.StatusCode, body)
+	}
+
+	resp, err = http.Get(base + "cmdline")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ = io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if strings.Contains(string(body), "profiling.test") {
+		t.Errorf("cmdline endpoint exposed the command line: %q", body)
+	}
+}
+
+func TestListen_NonLoopback(t *testing.T) {
+	for _, addr := range []string{":6060", "0.0.0.0:6060", "example.com:6060"} {
+		if _, err := Listen(context.Background(), addr); err == nil || !strings.Contains(err.Error(), "must be a loopback address") {
+			t.Errorf("Listen(%q) error = %v", addr, err)
+		}
+	}
+}
+
+func TestWriteGoroutines(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteGoroutines(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "TestWriteGoroutines") {
+		t.Errorf("stacks missing the running test:\n%s", buf.String())
+	}
+}
+
+func TestWriteHeap(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHeap(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if b := buf.Bytes(); len(b) < 2 || b[0] != 0x1f || b[1] != 0x8b {
+		t.Errorf("heap profile is not gzipped (%d bytes)", buf.Len())
+	}
+}
+
+func TestReadGCStats(t *testing.T) {
+	stats := ReadGCStats()
+	if stats.Goroutines < 1 || stats.GOMAXPROCS < 1 || stats.HeapAlloc == 0 || stats.GoVersion == "" {
+		t.Errorf("ReadGCStats() = %+v", stats)
+	}
+}
@@ -0,0 +1,253 @@
+// Package remote runs ado diagnostics on other hosts over SSH.
+//
+// Commands go through the ssh client on PATH, so ~/.ssh/config, the SSH
+// agent, known_hosts, and ProxyJump work as they do for the user. The
+// remote host runs its own ado when one is on its PATH; otherwise the
+// local binary can be streamed over the connection into a temporary file,
+// run once, and removed.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	internalmeta "github.com/anowarislam/ado/internal/meta"
+)
+
+// DefaultJobs is how many hosts are contacted at once.
+const DefaultJobs = 8
+
+// Exit statuses of the remote scripts.
+const (
+	exitNotFound     = 127 // no ado on the remote PATH
+	exitIncompatible = 126 // the local binary cannot run on the remote host
+	exitSSH          = 255 // ssh itself failed: connection, authentication, host key
+)
+
+// ErrNotInstalled is returned when the remote host has no ado binary and
+// uploading is off.
+var ErrNotInstalled = errors.New("ado is not installed on the remote host; install it or pass --upload to run a temporary copy")
+
+// Client runs ado on remote hosts.
+type Client struct {
+	// SSH is the ssh client to run; defaults to "ssh".
+	SSH string
+	// Options are extra ssh arguments placed before the host, such as
+	// "-p", "2222" or "-o", "ConnectTimeout=5".
+	Options []string
+	// Binary is the remote ado command; defaults to "ado".
+	Binary string
+	// Upload streams the local binary to hosts without ado.
+	Upload bool
+	// Self is the binary uploaded; defaults to the running executable.
+	Self string
+
+	// run executes ssh; overridable for tests.
+	run func(ctx context.Context, name string, args []string, stdin io.Reader) (stdout, stderr []byte, exitCode int, err error)
+}
+
+// Run runs ado with args on host and returns its standard output.
+func (c *Client) Run(ctx context.Context, host string, args []string) ([]byte, error) {
+	binary := c.Binary
+	if binary == "" {
+		binary = "ado"
+	}
+	script := fmt.Sprintf("command -v %s >/dev/null 2>&1 || exit %d; exec %s %s",
+		shellQuote(binary), exitNotFound, shellQuote(binary), shellJoin(args))
+	stdout, err := c.ssh(ctx, host, script, nil)
+	var exitErr *remoteExitError
+	if !errors.As(err, &exitErr) || exitErr.code != exitNotFound {
+		return stdout, err
+	}
+	if !c.Upload {
+		return nil, fmt.Errorf("%s: %w", host, ErrNotInstalled)
+	}
+	return c.runUploaded(ctx, host, args)
+}
+
+// runUploaded streams the local binary to a temporary file on host, runs
+// it with args, and removes it.
+func (c *Client) runUploaded(ctx context.Context, host string, args []string) ([]byte, error) {
+	pattern, ok := unamePatterns[runtime.GOOS+"/"+runtime.GOARCH]
+	if !ok {
+		return nil, fmt.Errorf("%s: uploading a %s/%s binary is not supported", host, runtime.GOOS, runtime.GOARCH)
+	}
+	self := c.Self
+	if self == "" {
+		var err error
+		if self, err = os.Executable(); err != nil {
+			return nil, fmt.Errorf("locate ado binary: %w", err)
+		}
+	}
+	f, err := os.Open(self)
+	if err != nil {
+		return nil, fmt.Errorf("open ado binary: %w", err)
+	}
+	defer f.Close()
+
+	script := fmt.Sprintf(`case "$(uname -sm)" in %s) ;; *) echo "remote is $(uname -sm), ado is %s/%s" >&2; exit %d;; esac
+f=$(mktemp "${TMPDIR:-/tmp}/ado.XXXXXX") || exit 1
+trap 'rm -f "$f"' EXIT
+cat > "$f" && chmod 700 "$f" && "$f" %s`, pattern, runtime.GOOS, runtime.GOARCH, exitIncompatible, shellJoin(args))
+	return c.ssh(ctx, host, script, f)
+}
+
+// unamePatterns matches `uname -sm` output of hosts that can run this
+// binary, by GOOS/GOARCH.
+var unamePatterns = map[string]string{
+	"linux/amd64":   `"Linux x86_64"`,
+	"linux/arm64":   `"Linux aarch64"|"Linux arm64"`,
+	"linux/386":     `"Linux i"[3-6]"86"|"Linux x86_64"`,
+	"darwin/amd64":  `"Darwin x86_64"|"Darwin arm64"`,
+	"darwin/arm64":  `"Darwin arm64"`,
+	"freebsd/amd64": `"FreeBSD amd64"`,
+}
+
+// SystemInfo runs `ado meta system` on host with extra flags.
+func (c *Client) SystemInfo(ctx context.Context, host string, flags []string) (*internalmeta.SystemInfo, error) {
+	var info internalmeta.SystemInfo
+	if err := c.runJSON(ctx, host, append([]string{"meta", "system"}, flags...), &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+// EnvInfo runs `ado meta env` on host with extra flags.
+func (c *Client) EnvInfo(ctx context.Context, host string, flags []string) (*internalmeta.EnvInfo, error) {
+	var info internalmeta.EnvInfo
+	if err := c.runJSON(ctx, host, append([]string{"meta", "env"}, flags...), &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func (c *Client) runJSON(ctx context.Context, host string, args []string, out any) error {
+	stdout, err := c.Run(ctx, host, append(args, "--output", "json"))
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(stdout, out); err != nil {
+		return fmt.Errorf("%s: decode remote output: %w", host, err)
+	}
+	return nil
+}
+
+// remoteExitError reports a non-zero exit of the remote script.
+type remoteExitError struct {
+	host   string
+	code   int
+	stderr string
+}
+
+func (e *remoteExitError) Error() string {
+	msg := e.stderr
+	if msg == "" {
+		msg = fmt.Sprintf("exit status %d", e.code)
+	}
+	switch e.code {
+	case exitSSH:
+		return fmt.Sprintf("%s: ssh: %s", e.host, msg)
+	case exitIncompatible:
+		return fmt.Sprintf("%s: cannot run the uploaded binary: %s", e.host, msg)
+	}
+	return fmt.Sprintf("%s: remote ado failed: %s", e.host, msg)
+}
+
+func (c *Client) ssh(ctx context.Context, host, script string, stdin io.Reader) ([]byte, error) {
+	name := c.SSH
+	if name == "" {
+		name = "ssh"
+	}
+	// BatchMode keeps ssh from prompting, which would hang fan-out.
+	args := append(append([]string{"-o", "BatchMode=yes"}, c.Options...), "--", host, script)
+	run := c.run
+	if run == nil {
+		run = runSSH
+	}
+	stdout, stderr, code, err := run(ctx, name, args, stdin)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", host, err)
+	}
+	if code != 0 {
+		return nil, &remoteExitError{host: host, code: code, stderr: strings.TrimSpace(string(stderr))}
+	}
+	return stdout, nil
+}
+
+func runSSH(ctx context.Context, name string, args []string, stdin io.Reader) ([]byte, []byte, int, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, name, args...)
+	cmd.Stdin = stdin
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && ctx.Err() == nil {
+		return stdout.Bytes(), stderr.Bytes(), exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return nil, nil, -1, err
+	}
+	return stdout.Bytes(), stderr.Bytes(), 0, nil
+}
+
+// HostResult is the outcome of one host in FanOut.
+type HostResult struct {
+	Host   string                   `json:"host" yaml:"host"`
+	System *internalmeta.SystemInfo `json:"system,omitempty" yaml:"system,omitempty"`
+	Env    *internalmeta.EnvInfo    `json:"env,omitempty" yaml:"env,omitempty"`
+	// Text is the remote command's text output, for text mode.
+	Text  string `json:"-" yaml:"-"`
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// FanOut calls fn for each host, at most jobs at a time, and returns the
+// results in host order. An error from fn is recorded in the host's
+// result.
+func FanOut(ctx context.Context, hosts []string, jobs int, fn func(ctx context.Context, result *HostResult) error) []HostResult {
+	if jobs < 1 {
+		jobs = DefaultJobs
+	}
+	results := make([]HostResult, len(hosts))
+	sem := make(chan struct{}, jobs)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		results[i].Host = host
+		wg.Add(1)
+		go func(r *HostResult) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := fn(ctx, r); err != nil {
+				r.Error = err.Error()
+			}
+		}(&results[i])
+	}
+	wg.Wait()
+	return results
+}
+
+// shellJoin quotes args for a POSIX shell.
+func shellJoin(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = shellQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+func shellQuote(s string) string {
+	if s != "" && strings.Trim(s, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_./=,:@") == "" {
+		return s
+	}
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
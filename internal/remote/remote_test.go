@@ -0,0 +1,171 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeSSH records the ssh invocations and answers them from handle.
+type fakeSSH struct {
+	calls  [][]string
+	stdins []string
+	handle func(script string) (stdout, stderr string, code int)
+}
+
+func (f *fakeSSH) run(_ context.Context, name string, args []string, stdin io.Reader) ([]byte, []byte, int, error) {
+	f.calls = append(f.calls, append([]string{name}, args...))
+	in := ""
+	if stdin != nil {
+		data, _ := io.ReadAll(stdin)
+		in = string(data)
+	}
+	f.stdins = append(f.stdins, in)
+	stdout, stderr, code := f.handle(args[len(args)-1])
+	return []byte(stdout), []byte(stderr), code, nil
+}
+
+func TestClient_Run(t *testing.T) {
+	fake := &fakeSSH{handle: func(string) (string, string, int) { return `{"config_path":"/etc/ado/config.yaml"}`, "", 0 }}
+	c := &Client{Options: []string{"-p", "2222"}, run: fake.run}
+
+	info, err := c.EnvInfo(context.Background(), "ops@web-1", []string{"--explain"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ConfigPath != "/etc/ado/config.yaml" {
+		t.Errorf("ConfigPath = %q", info.ConfigPath)
+	}
+	got := strings.Join(fake.calls[0], " ")
+	want := "ssh -o BatchMode=yes -p 2222 -- ops@web-1 command -v ado >/dev/null 2>&1 || exit 127; exec ado meta env --explain --output json"
+	if got != want {
+		t.Errorf("ssh args =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestClient_Run_NotInstalled(t *testing.T) {
+	fake := &fakeSSH{handle: func(string) (string, string, int) { return "", "", exitNotFound }}
+	c := &Client{run: fake.run}
+	_, err := c.Run(context.Background(), "web-1", []string{"meta", "system"})
+	if !errors.Is(err, ErrNotInstalled) {
+		t.Errorf("error = %v, want ErrNotInstalled", err)
+	}
+	if len(fake.calls) != 1 {
+		t.Errorf("ssh ran %d times, want 1", len(fake.calls))
+	}
+}
+
+func TestClient_Run_Upload(t *testing.T) {
+	self := filepath.Join(t.TempDir(), "ado")
+	if err := os.WriteFile(self, []byte("binary"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeSSH{handle: func(script string) (string, string, int) {
+		if strings.HasPrefix(script, "command -v") {
+			return "", "", exitNotFound
+		}
+		return "uploaded output", "", 0
+	}}
+	c := &Client{Upload: true, Self: self, run: fake.run}
+
+	out, err := c.Run(context.Background(), "web-1", []string{"meta", "system", "--sections", "cpu,memory"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != "uploaded output" || len(fake.calls) != 2 {
+		t.Fatalf("output = %q after %d calls", out, len(fake.calls))
+	}
+	script := fake.calls[1][len(fake.calls[1])-1]
+	for _, want := range []string{"uname -sm", "mktemp", "trap 'rm -f \"$f\"' EXIT", `"$f" meta system --sections cpu,memory`} {
+		if !strings.Contains(script, want) {
+			t.Errorf("upload script missing %q:\n%s", want, script)
+		}
+	}
+	if fake.stdins[1] != "binary" {
+		t.Errorf("stdin = %q, want the binary", fake.stdins[1])
+	}
+}
+
+func TestClient_Run_Errors(t *testing.T) {
+	tests := []struct {
+		code   int
+		stderr string
+		want   string
+	}{
+		{exitSSH, "ssh: connect to host web-1 port 22: Connection refused", "web-1: ssh: ssh: connect to host"},
+		{exitIncompatible, "remote is Linux armv7l, ado is linux/amd64", "cannot run the uploaded binary: remote is Linux armv7l"},
+		{1, "", "web-1: remote ado failed: exit status 1"},
+	}
+	for _, tt := range tests {
+		fake := &fakeSSH{handle: func(string) (string, string, int) { return "", tt.stderr, tt.code }}
+		c := &Client{run: fake.run}
+		_, err := c.Run(context.Background(), "web-1", nil)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("code %d: error = %v, want %q", tt.code, err, tt.want)
+		}
+	}
+}
+
+func TestClient_SystemInfo_BadOutput(t *testing.T) {
+	fake := &fakeSSH{handle: func(string) (string, string, int) { return "Welcome to web-1!\n{", "", 0 }}
+	c := &Client{run: fake.run}
+	if _, err := c.SystemInfo(context.Background(), "web-1", nil); err == nil || !strings.Contains(err.Error(), "decode remote output") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestFanOut(t *testing.T) {
+	hosts := []string{"a", "b", "c", "d", "e"}
+	var running, peak atomic.Int32
+	results := FanOut(context.Background(), hosts, 2, func(_ context.Context, r *HostResult) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if r.Host == "c" {
+			return errors.New("unreachable")
+		}
+		r.Text = "ok " + r.Host
+		return nil
+	})
+
+	if peak.Load() > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", peak.Load())
+	}
+	for i, r := range results {
+		if r.Host != hosts[i] {
+			t.Errorf("results[%d].Host = %q, want %q", i, r.Host, hosts[i])
+		}
+	}
+	if results[2].Error != "unreachable" || results[0].Text != "ok a" {
+		t.Errorf("results = %+v", results)
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	tests := map[string]string{
+		"meta":          "meta",
+		"cpu,memory":    "cpu,memory",
+		"":              "''",
+		"it's":          `'it'\''s'`,
+		"a b":           "'a b'",
+		"$(rm -rf /)":   "'$(rm -rf /)'",
+		"--config=/x y": "'--config=/x y'",
+	}
+	for in, want := range tests {
+		if got := shellQuote(in); got != want {
+			t.Errorf("shellQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
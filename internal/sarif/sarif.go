@@ -0,0 +1,161 @@
+// Package sarif writes SARIF 2.1.0 logs, the static analysis format GitHub
+// code scanning ingests, so that problems ado finds in files show up as
+// annotations on pull requests.
+//
+// Only the parts of the schema code scanning reads are modeled: one run
+// per tool, the tool's rules, and results located by file and line.
+package sarif
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Version and Schema identify the SARIF revision written.
+const (
+	Version = "2.1.0"
+	Schema  = "https://json.schemastore.org/sarif-2.1.0.json"
+)
+
+// Levels of result.
+const (
+	LevelError   = "error"
+	LevelWarning = "warning"
+	LevelNote    = "note"
+)
+
+// Log is a SARIF document.
+type Log struct {
+	Schema  string `json:"$schema"`
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run holds the results of one tool invocation.
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the analyzer.
+type Tool struct {
+	Driver Driver `json:"driver"`
+}
+
+// Driver is the analyzer's main component and its rules.
+type Driver struct {
+	Name           string `json:"name"`
+	Version        string `json:"version,omitempty"`
+	InformationURI string `json:"informationUri,omitempty"`
+	Rules          []Rule `json:"rules,omitempty"`
+}
+
+// Rule is a kind of result.
+type Rule struct {
+	ID                   string               `json:"id"`
+	ShortDescription     Message              `json:"shortDescription"`
+	DefaultConfiguration DefaultConfiguration `json:"defaultConfiguration"`
+}
+
+// DefaultConfiguration gives the level of a rule's results unless a
+// result overrides it.
+type DefaultConfiguration struct {
+	Level string `json:"level"`
+}
+
+// Message is plain text.
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Result is one problem found.
+type Result struct {
+	RuleID    string     `json:"ruleId"`
+	RuleIndex int        `json:"ruleIndex"`
+	Level     string     `json:"level"`
+	Message   Message    `json:"message"`
+	Locations []Location `json:"locations"`
+}
+
+// Location places a result in a file.
+type Location struct {
+	PhysicalLocation PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is a file and, when known, a region of it.
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region,omitempty"`
+}
+
+// ArtifactLocation names a file by URI.
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is a range of lines, numbered from 1.
+type Region struct {
+	StartLine int `json:"startLine"`
+}
+
+// NewResult returns a result for the rule at ruleIndex in rules, located
+// at line of path. A line of 0 places the result on the file as a whole.
+func NewResult(rules []Rule, ruleIndex int, level, message, path string, line int) Result {
+	loc := PhysicalLocation{ArtifactLocation: ArtifactLocation{URI: ArtifactURI(path)}}
+	if line > 0 {
+		loc.Region = &Region{StartLine: line}
+	}
+	return Result{
+		RuleID:    rules[ruleIndex].ID,
+		RuleIndex: ruleIndex,
+		Level:     level,
+		Message:   Message{Text: message},
+		Locations: []Location{{PhysicalLocation: loc}},
+	}
+}
+
+// ArtifactURI returns the URI for path. Code scanning matches results to
+// repository files by relative URI, so paths under the working directory
+// are made relative to it; others become file URIs.
+func ArtifactURI(path string) string {
+	if !filepath.IsAbs(path) {
+		return filepath.ToSlash(filepath.Clean(path))
+	}
+	if wd, err := os.Getwd(); err == nil {
+		if rel, err := filepath.Rel(wd, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return filepath.ToSlash(rel)
+		}
+	}
+	uri := filepath.ToSlash(path)
+	if !strings.HasPrefix(uri, "/") {
+		// Windows drive paths: file:///C:/...
+		uri = "/" + uri
+	}
+	return "file://" + uri
+}
+
+// Write writes log to w as indented JSON.
+func Write(w io.Writer, log Log) error {
+	if log.Schema == "" {
+		log.Schema = Schema
+	}
+	if log.Version == "" {
+		log.Version = Version
+	}
+	for i := range log.Runs {
+		if log.Runs[i].Results == nil {
+			// Code scanning treats a missing results array as a failed run.
+			log.Runs[i].Results = []Result{}
+		}
+	}
+	data, err := json.MarshalIndent(log, "", "  ")
+	if err != nil {
+		return fmt.Errorf("serialize sarif: %w", err)
+	}
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,79 @@
+package sarif
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	rules := []Rule{
+		{ID: "unknown-key", ShortDescription: Message{Text: "Unknown key"}, DefaultConfiguration: DefaultConfiguration{Level: LevelWarning}},
+		{ID: "version", ShortDescription: Message{Text: "Bad version"}, DefaultConfiguration: DefaultConfiguration{Level: LevelError}},
+	}
+	log := Log{Runs: []Run{{
+		Tool: Tool{Driver: Driver{Name: "ado", Version: "1.2.3", Rules: rules}},
+		Results: []Result{
+			NewResult(rules, 1, LevelError, "missing version", "config.yaml", 0),
+			NewResult(rules, 0, LevelWarning, `unknown key "x"`, "config.yaml", 4),
+		},
+	}}}
+	var buf bytes.Buffer
+	if err := Write(&buf, log); err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("output is not JSON: %v", err)
+	}
+	if got["version"] != Version || got["$schema"] != Schema {
+		t.Errorf("version = %v, $schema = %v", got["version"], got["$schema"])
+	}
+
+	var parsed Log
+	if err := json.Unmarshal(buf.Bytes(), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	results := parsed.Runs[0].Results
+	if results[0].RuleID != "version" || results[0].RuleIndex != 1 || results[0].Locations[0].PhysicalLocation.Region != nil {
+		t.Errorf("file-level result = %+v", results[0])
+	}
+	if region := results[1].Locations[0].PhysicalLocation.Region; region == nil || region.StartLine != 4 {
+		t.Errorf("line result region = %+v", region)
+	}
+}
+
+func TestWrite_EmptyResults(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, Log{Runs: []Run{{Tool: Tool{Driver: Driver{Name: "ado"}}}}}); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"results": []`)) {
+		t.Errorf("output has no empty results array:\n%s", buf.String())
+	}
+}
+
+func TestArtifactURI(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("unix paths")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := map[string]string{
+		"config.yaml":                     "config.yaml",
+		"./ci/../ado.yaml":                "ado.yaml",
+		filepath.Join(wd, "ci", "a.yaml"): "ci/a.yaml",
+		"/etc/ado/config.yaml":            "file:///etc/ado/config.yaml",
+	}
+	for path, want := range tests {
+		if got := ArtifactURI(path); got != want {
+			t.Errorf("ArtifactURI(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
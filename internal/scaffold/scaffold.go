@@ -0,0 +1,350 @@
+// Package scaffold renders starter files for ado's automation commands:
+// a task file, a workflow, a check suite, a GitHub Actions workflow that
+// runs ado, and systemd units, filled in from answers to a few questions.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anowarislam/ado/internal/alias"
+	"github.com/anowarislam/ado/internal/checks"
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/service"
+	"github.com/anowarislam/ado/internal/workflow"
+)
+
+// Kinds of starter files.
+const (
+	KindTasks         = "tasks"
+	KindWorkflow      = "workflow"
+	KindChecks        = "checks"
+	KindGitHubActions = "github-actions"
+	KindSystemd       = "systemd"
+)
+
+//go:embed templates/*.tmpl
+var templateFS embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"yaml":      yamlScalar,
+	"words":     words,
+	"steps":     splitSteps,
+	"execStart": execStart,
+}).ParseFS(templateFS, "templates/*.tmpl"))
+
+// Question is a value a generator needs. `ado generate` asks it, or takes
+// the answer from the flag named Key.
+type Question struct {
+	Key     string
+	Prompt  string
+	Default string
+	// Validate rejects an answer; nil accepts any.
+	Validate func(string) error
+}
+
+// Answers maps question keys to answers.
+type Answers map[string]string
+
+// File is a generated file.
+type File struct {
+	// Path is relative to the directory the files are written to.
+	Path string
+	Data []byte
+}
+
+// Generator renders the starter files of one kind.
+type Generator struct {
+	Kind        string
+	Description string
+	Questions   []Question
+
+	// files names the files to render and the template of each.
+	files func(Answers) []fileTemplate
+	// check validates a rendered file, so a template or an answer that
+	// produces an invalid file is caught before it is written.
+	check func(data []byte) error
+}
+
+// Generators returns every generator.
+func Generators() []Generator {
+	return []Generator{
+		{
+			Kind:        KindTasks,
+			Description: "Tasks for ado run, to include from the config file",
+			Questions: []Question{
+				{Key: "name", Prompt: "Task name", Default: "build", Validate: validateName},
+				{Key: "command", Prompt: "Command line the task runs", Default: "make build", Validate: validateCommand},
+				{Key: "description", Prompt: "Description", Default: "Build the project"},
+			},
+			files: single("tasks.yaml", "tasks.yaml.tmpl"),
+			check: checkTasks,
+		},
+		{
+			Kind:        KindWorkflow,
+			Description: "Workflow for ado workflow run",
+			Questions: []Question{
+				{Key: "name", Prompt: "Workflow name", Default: "ci"},
+				{Key: "steps", Prompt: "Commands, one step each, separated by ';'", Default: "go vet ./...; go test ./...", Validate: validateSteps},
+			},
+			files: single("workflow.yaml", "workflow.yaml.tmpl"),
+			check: func(data []byte) error {
+				_, err := workflow.Parse(bytes.NewReader(data))
+				return err
+			},
+		},
+		{
+			Kind:        KindChecks,
+			Description: "Health checks for ado check run",
+			Questions: []Question{
+				{Key: "url", Prompt: "URL that must answer with 2xx (empty for none)", Default: "https://example.com/"},
+				{Key: "disk", Prompt: "Path whose filesystem must keep free space (empty for none)", Default: "/"},
+				{Key: "cert", Prompt: "TLS address whose certificate must not expire soon (empty for none)"},
+			},
+			files: single("checks.yaml", "checks.yaml.tmpl"),
+			check: func(data []byte) error {
+				_, err := checks.Parse(bytes.NewReader(data))
+				return err
+			},
+		},
+		{
+			Kind:        KindGitHubActions,
+			Description: "GitHub Actions workflow that installs and runs ado",
+			Questions: []Question{
+				{Key: "name", Prompt: "Workflow name", Default: "ado", Validate: validateName},
+				{Key: "command", Prompt: "ado command to run", Default: "check run checks.yaml", Validate: validateCommand},
+				{Key: "cron", Prompt: "Cron schedule, UTC (empty to run on push and pull requests only)", Validate: validateCron},
+				{Key: "version", Prompt: "ado release to install", Default: "latest", Validate: validateVersion},
+			},
+			files: func(a Answers) []fileTemplate {
+				return []fileTemplate{{".github/workflows/" + a["name"] + ".yml", "github-actions.yml.tmpl"}}
+			},
+			check: checkYAML,
+		},
+		{
+			Kind:        KindSystemd,
+			Description: "systemd service, and timer for a schedule, running an ado command",
+			Questions: []Question{
+				{Key: "name", Prompt: "Unit name", Default: "ado-job", Validate: validateUnitName},
+				{Key: "command", Prompt: "ado command to run", Default: "run build", Validate: validateCommand},
+				{Key: "description", Prompt: "Description", Default: "ado job"},
+				{Key: "schedule", Prompt: "OnCalendar= schedule (empty for no timer)", Default: "daily"},
+				{Key: "executable", Prompt: "Path of the ado binary", Default: executable(), Validate: validateExecutable},
+			},
+			files: func(a Answers) []fileTemplate {
+				files := []fileTemplate{{a["name"] + ".service", "systemd.service.tmpl"}}
+				if a["schedule"] != "" {
+					files = append(files, fileTemplate{a["name"] + ".timer", "systemd.timer.tmpl"})
+				}
+				return files
+			},
+		},
+	}
+}
+
+// Kinds returns the kinds of the generators.
+func Kinds() []string {
+	var kinds []string
+	for _, g := range Generators() {
+		kinds = append(kinds, g.Kind)
+	}
+	return kinds
+}
+
+// Lookup returns the generator of kind.
+func Lookup(kind string) (Generator, error) {
+	for _, g := range Generators() {
+		if g.Kind == kind {
+			return g, nil
+		}
+	}
+	return Generator{}, fmt.Errorf("unknown kind %q (valid: %s)", kind, strings.Join(Kinds(), ", "))
+}
+
+// Generate renders the files of g. Unanswered questions take their
+// default.
+func (g Generator) Generate(answers Answers) ([]File, error) {
+	a := Answers{}
+	for _, q := range g.Questions {
+		answer, ok := answers[q.Key]
+		if !ok {
+			answer = q.Default
+		}
+		answer = strings.TrimSpace(answer)
+		if err := q.Check(answer); err != nil {
+			return nil, err
+		}
+		a[q.Key] = answer
+	}
+
+	var files []File
+	for _, f := range g.files(a) {
+		var b bytes.Buffer
+		if err := templates.ExecuteTemplate(&b, f.template, a); err != nil {
+			return nil, fmt.Errorf("render %s: %w", f.path, err)
+		}
+		if g.check != nil {
+			if err := g.check(b.Bytes()); err != nil {
+				return nil, fmt.Errorf("generated %s is invalid: %w", f.path, err)
+			}
+		}
+		files = append(files, File{Path: f.path, Data: b.Bytes()})
+	}
+	return files, nil
+}
+
+// Check validates answer, naming the question in the error.
+func (q Question) Check(answer string) error {
+	if q.Validate == nil {
+		return nil
+	}
+	if err := q.Validate(answer); err != nil {
+		return fmt.Errorf("%s: %w", q.Key, err)
+	}
+	return nil
+}
+
+// fileTemplate is a file to render from a template.
+type fileTemplate struct {
+	path     string
+	template string
+}
+
+// single is a generator's files func for one file.
+func single(path, tmpl string) func(Answers) []fileTemplate {
+	return func(Answers) []fileTemplate {
+		return []fileTemplate{{path, tmpl}}
+	}
+}
+
+var (
+	namePattern     = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_.-]*$`)
+	unitNamePattern = regexp.MustCompile(`^[A-Za-z0-9:_.@-]+$`)
+	versionPattern  = regexp.MustCompile(`^v\d+\.\d+\.\d+\S*$`)
+)
+
+func validateName(s string) error {
+	if !namePattern.MatchString(s) {
+		return fmt.Errorf("invalid name %q: use letters, digits, '.', '_' and '-'", s)
+	}
+	return nil
+}
+
+func validateUnitName(s string) error {
+	switch {
+	case strings.HasSuffix(s, ".service"):
+		return fmt.Errorf("give unit name %q without .service", s)
+	case !unitNamePattern.MatchString(s):
+		return fmt.Errorf("invalid unit name %q: use letters, digits, ':', '_', '.', '@' and '-'", s)
+	}
+	return nil
+}
+
+func validateCommand(s string) error {
+	args, err := alias.Split(s)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New("command is empty")
+	}
+	return nil
+}
+
+func validateSteps(s string) error {
+	if len(splitSteps(s)) == 0 {
+		return errors.New("no steps")
+	}
+	return nil
+}
+
+func validateCron(s string) error {
+	if s != "" && len(strings.Fields(s)) != 5 {
+		return fmt.Errorf("invalid cron schedule %q: need 5 fields", s)
+	}
+	return nil
+}
+
+func validateVersion(s string) error {
+	if s != "latest" && !versionPattern.MatchString(s) {
+		return fmt.Errorf("invalid version %q: use latest or a release tag such as v1.2.3", s)
+	}
+	return nil
+}
+
+func validateExecutable(s string) error {
+	if !strings.HasPrefix(s, "/") {
+		return fmt.Errorf("%q is not an absolute path", s)
+	}
+	return nil
+}
+
+// executable is the path of the running ado, the default binary for
+// systemd units.
+func executable() string {
+	path, err := os.Executable()
+	if err != nil || !strings.HasPrefix(path, "/") {
+		return "/usr/local/bin/ado"
+	}
+	return path
+}
+
+// splitSteps splits the steps answer into commands.
+func splitSteps(s string) []string {
+	var steps []string
+	for _, step := range strings.Split(s, ";") {
+		if step = strings.TrimSpace(step); step != "" {
+			steps = append(steps, step)
+		}
+	}
+	return steps
+}
+
+// yamlScalar renders v as a YAML scalar, quoted when it needs to be.
+func yamlScalar(v string) (string, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(string(data), "\n"), nil
+}
+
+// words splits a command line into its words; validateCommand has
+// rejected lines that do not split.
+func words(s string) []string {
+	args, _ := alias.Split(s)
+	return args
+}
+
+// execStart renders ExecStart= running ado at executable with the words
+// of command.
+func execStart(executable, command string) string {
+	argv := append([]string{executable}, words(command)...)
+	for i, arg := range argv {
+		argv[i] = service.SystemdQuote(arg)
+	}
+	return strings.Join(argv, " ")
+}
+
+// checkTasks validates a task file the way ado config validate would.
+func checkTasks(data []byte) error {
+	result := config.ValidateData("tasks.yaml", data)
+	if len(result.Errors) > 0 {
+		return errors.New(result.Errors[0].Message)
+	}
+	return nil
+}
+
+// checkYAML checks that data parses as YAML.
+func checkYAML(data []byte) error {
+	var v any
+	return yaml.Unmarshal(data, &v)
+}
@@ -0,0 +1,139 @@
+package scaffold
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/anowarislam/ado/internal/ui/uitest"
+)
+
+func TestGenerate_Defaults(t *testing.T) {
+	for _, g := range Generators() {
+		t.Run(g.Kind, func(t *testing.T) {
+			// The default binary is wherever the tests run from.
+			files, err := g.Generate(Answers{"executable": "/usr/local/bin/ado"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var b strings.Builder
+			for _, f := range files {
+				b.WriteString("==> " + f.Path + " <==\n")
+				b.Write(f.Data)
+			}
+			uitest.Golden(t, g.Kind, b.String())
+		})
+	}
+}
+
+func TestGenerate_Answers(t *testing.T) {
+	tests := []struct {
+		kind    string
+		answers Answers
+		paths   []string
+		want    []string
+		notWant []string
+	}{
+		{
+			kind:    KindTasks,
+			answers: Answers{"name": "test", "command": `go test -run 'Foo Bar' ./...`, "description": "Run: the tests"},
+			paths:   []string{"tasks.yaml"},
+			want:    []string{"  test:\n", "    command: go\n", "      - Foo Bar\n", `description: 'Run: the tests'`},
+		},
+		{
+			kind:    KindTasks,
+			answers: Answers{"command": "make"},
+			want:    []string{"    command: make\n"},
+			notWant: []string{"args:"},
+		},
+		{
+			kind:    KindWorkflow,
+			answers: Answers{"name": "release", "steps": "make dist;; ./upload.sh # comment"},
+			want:    []string{"name: release\n", "    run: make dist\n", `    run: './upload.sh # comment'`},
+		},
+		{
+			kind:    KindChecks,
+			answers: Answers{"url": "", "disk": "", "cert": "example.com:443"},
+			want:    []string{"address: example.com:443"},
+			notWant: []string{"http:", "disk:"},
+		},
+		{
+			kind:    KindGitHubActions,
+			answers: Answers{"name": "health", "cron": "*/30 * * * *", "version": "v1.4.0"},
+			paths:   []string{".github/workflows/health.yml"},
+			want:    []string{`- cron: '*/30 * * * *'`, "gh release download v1.4.0 --repo"},
+		},
+		{
+			kind:    KindSystemd,
+			answers: Answers{"name": "backup", "command": `run backup --target "/mnt/my disk"`, "schedule": "", "executable": "/opt/ado bin/ado"},
+			paths:   []string{"backup.service"},
+			want:    []string{`ExecStart="/opt/ado bin/ado" run backup --target "/mnt/my disk"`, "WantedBy=default.target", "enable --now backup.service"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.kind, func(t *testing.T) {
+			g, err := Lookup(tt.kind)
+			if err != nil {
+				t.Fatal(err)
+			}
+			files, err := g.Generate(tt.answers)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var paths []string
+			var out strings.Builder
+			for _, f := range files {
+				paths = append(paths, f.Path)
+				out.Write(f.Data)
+			}
+			if tt.paths != nil && strings.Join(paths, ",") != strings.Join(tt.paths, ",") {
+				t.Errorf("paths = %v, want %v", paths, tt.paths)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Errorf("output lacks %q:\n%s", want, out.String())
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(out.String(), notWant) {
+					t.Errorf("output has %q:\n%s", notWant, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestGenerate_Errors(t *testing.T) {
+	tests := []struct {
+		kind    string
+		answers Answers
+		want    string
+	}{
+		{KindTasks, Answers{"name": "my task"}, `name: invalid name "my task"`},
+		{KindTasks, Answers{"command": ""}, "command: command is empty"},
+		{KindTasks, Answers{"command": `echo "unterminated`}, "command: "},
+		{KindWorkflow, Answers{"steps": " ; "}, "steps: no steps"},
+		{KindChecks, Answers{"url": "", "disk": ""}, "generated checks.yaml is invalid"},
+		{KindChecks, Answers{"url": "example.com"}, "generated checks.yaml is invalid"},
+		{KindGitHubActions, Answers{"cron": "daily"}, "cron: invalid cron schedule"},
+		{KindGitHubActions, Answers{"version": "1.4"}, "version: invalid version"},
+		{KindSystemd, Answers{"name": "job.service"}, "without .service"},
+		{KindSystemd, Answers{"executable": "ado"}, "executable: "},
+	}
+	for _, tt := range tests {
+		g, err := Lookup(tt.kind)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = g.Generate(tt.answers)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s %v: err = %v, want %q", tt.kind, tt.answers, err, tt.want)
+		}
+	}
+}
+
+func TestLookup_Unknown(t *testing.T) {
+	_, err := Lookup("dockerfile")
+	if err == nil || !strings.Contains(err.Error(), "tasks, workflow, checks, github-actions, systemd") {
+		t.Errorf("Lookup(dockerfile) = %v", err)
+	}
+}
@@ -0,0 +1,58 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// RunLog appends records as newline-delimited JSON. It is safe for
+// concurrent use.
+type RunLog struct {
+	mu     sync.Mutex
+	w      io.Writer
+	closer io.Closer
+}
+
+// NewRunLog writes records to w.
+func NewRunLog(w io.Writer) *RunLog {
+	return &RunLog{w: w}
+}
+
+// OpenRunLog appends records to the file at path, creating it and its
+// directory if needed.
+func OpenRunLog(path string) (*RunLog, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("create run log dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("open run log: %w", err)
+	}
+	return &RunLog{w: f, closer: f}, nil
+}
+
+// Write appends rec as one line.
+func (l *RunLog) Write(rec Record) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encode run record: %w", err)
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write run log: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying file, if RunLog opened one.
+func (l *RunLog) Close() error {
+	if l.closer == nil {
+		return nil
+	}
+	return l.closer.Close()
+}
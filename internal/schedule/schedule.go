@@ -0,0 +1,299 @@
+// Package schedule runs configured tasks on cron expressions.
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/robfig/cron/v3"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/tasks"
+)
+
+// DefaultGracePeriod is how long shutdown waits for running tasks before
+// stopping them.
+const DefaultGracePeriod = 30 * time.Second
+
+// Run statuses recorded in the run log.
+const (
+	StatusSuccess = "success"
+	StatusFailed  = "failed"
+	StatusSkipped = "skipped"
+	StatusStopped = "stopped"
+)
+
+// Job is a schedule entry resolved against its task.
+type Job struct {
+	Name     string
+	Task     config.Task
+	TaskName string
+	Spec     string
+	Schedule cron.Schedule
+	Jitter   time.Duration
+	Overlap  string
+}
+
+// Jobs resolves the config's schedules. It fails on the first entry with
+// an unknown task or an invalid cron expression.
+func Jobs(cfg *config.Config) ([]Job, error) {
+	jobs := make([]Job, 0, len(cfg.Schedules))
+	for _, entry := range cfg.Schedules {
+		task, ok := cfg.Tasks[entry.Task]
+		if !ok {
+			return nil, fmt.Errorf("schedule %q: unknown task %q", entry.Name, entry.Task)
+		}
+		sched, err := cron.ParseStandard(entry.Cron)
+		if err != nil {
+			return nil, fmt.Errorf("schedule %q: invalid cron %q: %w", entry.Name, entry.Cron, err)
+		}
+		overlap := entry.Overlap
+		if overlap == "" {
+			overlap = config.OverlapSkip
+		}
+		jobs = append(jobs, Job{
+			Name:     entry.Name,
+			Task:     task,
+			TaskName: entry.Task,
+			Spec:     entry.Cron,
+			Schedule: sched,
+			Jitter:   entry.Jitter,
+			Overlap:  overlap,
+		})
+	}
+	return jobs, nil
+}
+
+// Record is the outcome of one scheduled activation.
+type Record struct {
+	Schedule    string     `json:"schedule" yaml:"schedule"`
+	Task        string     `json:"task" yaml:"task"`
+	Status      string     `json:"status" yaml:"status"`
+	ScheduledAt time.Time  `json:"scheduled_at" yaml:"scheduled_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty" yaml:"started_at,omitempty"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty" yaml:"finished_at,omitempty"`
+	DurationMS  int64      `json:"duration_ms,omitempty" yaml:"duration_ms,omitempty"`
+	ExitCode    *int       `json:"exit_code,omitempty" yaml:"exit_code,omitempty"`
+	Error       string     `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Scheduler fires jobs at their scheduled times until its context ends.
+type Scheduler struct {
+	Jobs []Job
+	// Run executes a job's task.
+	Run func(ctx context.Context, job Job) error
+	// Record receives every outcome, including skipped activations. It may
+	// be called concurrently.
+	Record func(Record)
+	// GracePeriod bounds how long shutdown waits for running tasks.
+	GracePeriod time.Duration
+
+	now    func() time.Time
+	jitter func(max time.Duration) time.Duration
+}
+
+// jobState tracks in-flight runs of one job for the overlap policy.
+type jobState struct {
+	job     Job
+	mu      sync.Mutex
+	running int
+	queued  *time.Time
+}
+
+// Start runs the scheduler until ctx is done, then waits up to the grace
+// period for running tasks before cancelling them.
+func (s *Scheduler) Start(ctx context.Context) {
+	// Runs outlive ctx during the grace period.
+	runCtx, cancelRuns := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelRuns()
+
+	var loops, runs sync.WaitGroup
+	for _, job := range s.Jobs {
+		state := &jobState{job: job}
+		loops.Add(1)
+		go func() {
+			defer loops.Done()
+			s.loop(ctx, runCtx, state, &runs)
+		}()
+	}
+	loops.Wait()
+
+	done := make(chan struct{})
+	go func() {
+		runs.Wait()
+		close(done)
+	}()
+
+	grace := s.GracePeriod
+	if grace <= 0 {
+		grace = DefaultGracePeriod
+	}
+	timer := time.NewTimer(grace)
+	defer timer.Stop()
+	select {
+	case <-done:
+	case <-timer.C:
+		cancelRuns()
+		<-done
+	}
+}
+
+// loop waits for each activation of one job and dispatches it.
+func (s *Scheduler) loop(ctx, runCtx context.Context, state *jobState, runs *sync.WaitGroup) {
+	next := state.job.Schedule.Next(s.clock())
+	for !next.IsZero() {
+		delay := next.Sub(s.clock()) + s.randomJitter(state.job.Jitter)
+		timer := time.NewTimer(max(delay, 0))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		s.dispatch(ctx, runCtx, state, next, runs)
+		// Activations missed while the host was asleep are skipped rather
+		// than replayed.
+		next = state.job.Schedule.Next(later(s.clock(), next))
+	}
+}
+
+// dispatch applies the overlap policy and starts a run if allowed.
+func (s *Scheduler) dispatch(ctx, runCtx context.Context, state *jobState, scheduledAt time.Time, runs *sync.WaitGroup) {
+	state.mu.Lock()
+	if state.running > 0 {
+		switch state.job.Overlap {
+		case config.OverlapQueue:
+			state.queued = &scheduledAt
+			state.mu.Unlock()
+			return
+		case config.OverlapAllow:
+		default:
+			state.mu.Unlock()
+			s.record(Record{
+				Schedule:    state.job.Name,
+				Task:        state.job.TaskName,
+				Status:      StatusSkipped,
+				ScheduledAt: scheduledAt,
+				Error:       "previous run still in progress",
+			})
+			return
+		}
+	}
+	state.running++
+	runs.Add(1)
+	state.mu.Unlock()
+
+	go s.execute(ctx, runCtx, state, scheduledAt, runs)
+}
+
+// execute runs the job, records the outcome, and starts a queued run.
+func (s *Scheduler) execute(ctx, runCtx context.Context, state *jobState, scheduledAt time.Time, runs *sync.WaitGroup) {
+	defer runs.Done()
+
+	for {
+		started := s.clock()
+		err := s.Run(runCtx, state.job)
+		finished := s.clock()
+
+		rec := Record{
+			Schedule:    state.job.Name,
+			Task:        state.job.TaskName,
+			Status:      StatusSuccess,
+			ScheduledAt: scheduledAt,
+			StartedAt:   &started,
+			FinishedAt:  &finished,
+			DurationMS:  finished.Sub(started).Milliseconds(),
+		}
+		var exitErr *tasks.ExitError
+		switch {
+		case err == nil:
+			code := 0
+			rec.ExitCode = &code
+		case errors.As(err, &exitErr):
+			rec.Status = StatusFailed
+			rec.ExitCode = &exitErr.Code
+			rec.Error = err.Error()
+		case runCtx.Err() != nil:
+			rec.Status = StatusStopped
+			rec.Error = "stopped at shutdown"
+		default:
+			rec.Status = StatusFailed
+			rec.Error = err.Error()
+		}
+		s.record(rec)
+
+		state.mu.Lock()
+		if state.queued == nil || ctx.Err() != nil {
+			state.running--
+			state.mu.Unlock()
+			return
+		}
+		scheduledAt = *state.queued
+		state.queued = nil
+		state.mu.Unlock()
+	}
+}
+
+func (s *Scheduler) record(rec Record) {
+	if s.Record != nil {
+		s.Record(rec)
+	}
+}
+
+func (s *Scheduler) clock() time.Time {
+	if s.now != nil {
+		return s.now()
+	}
+	return time.Now()
+}
+
+func (s *Scheduler) randomJitter(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	if s.jitter != nil {
+		return s.jitter(limit)
+	}
+	return rand.N(limit)
+}
+
+func later(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
+
+// Summary describes a job and its next activation.
+type Summary struct {
+	Name    string    `json:"name" yaml:"name"`
+	Task    string    `json:"task" yaml:"task"`
+	Cron    string    `json:"cron" yaml:"cron"`
+	Jitter  string    `json:"jitter,omitempty" yaml:"jitter,omitempty"`
+	Overlap string    `json:"overlap" yaml:"overlap"`
+	NextRun time.Time `json:"next_run" yaml:"next_run"`
+}
+
+// Summarize lists jobs with their next activation after now (before jitter).
+func Summarize(jobs []Job, now time.Time) []Summary {
+	summaries := make([]Summary, 0, len(jobs))
+	for _, job := range jobs {
+		summary := Summary{
+			Name:    job.Name,
+			Task:    job.TaskName,
+			Cron:    job.Spec,
+			Overlap: job.Overlap,
+			NextRun: job.Schedule.Next(now),
+		}
+		if job.Jitter > 0 {
+			summary.Jitter = job.Jitter.String()
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries
+}
@@ -0,0 +1,286 @@
+package schedule
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/tasks"
+)
+
+// every fires at a fixed interval; cron's @every has one-second resolution.
+type every time.Duration
+
+func (e every) Next(t time.Time) time.Time { return t.Add(time.Duration(e)) }
+
+// recorder collects records concurrently.
+type recorder struct {
+	mu      sync.Mutex
+	records []Record
+}
+
+func (r *recorder) add(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, rec)
+}
+
+func (r *recorder) count(status string) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, rec := range r.records {
+		if rec.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// concurrency tracks the maximum number of simultaneous runs.
+type concurrency struct {
+	current, peak atomic.Int32
+}
+
+func (c *concurrency) enter() {
+	n := c.current.Add(1)
+	for {
+		p := c.peak.Load()
+		if n <= p || c.peak.CompareAndSwap(p, n) {
+			return
+		}
+	}
+}
+
+func (c *concurrency) exit() { c.current.Add(-1) }
+
+// runFor starts s and stops it after d.
+func runFor(s *Scheduler, d time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), d)
+	defer cancel()
+	s.Start(ctx)
+}
+
+func TestScheduler_Runs(t *testing.T) {
+	rec := &recorder{}
+	var runs atomic.Int32
+	s := &Scheduler{
+		Jobs: []Job{{Name: "tick", TaskName: "tick", Schedule: every(20 * time.Millisecond), Overlap: config.OverlapSkip}},
+		Run: func(ctx context.Context, job Job) error {
+			runs.Add(1)
+			return nil
+		},
+		Record: rec.add,
+	}
+
+	runFor(s, 150*time.Millisecond)
+
+	if n := runs.Load(); n < 3 {
+		t.Errorf("runs = %d, want at least 3", n)
+	}
+	if got := rec.count(StatusSuccess); got != int(runs.Load()) {
+		t.Errorf("success records = %d, want %d", got, runs.Load())
+	}
+	first := rec.records[0]
+	if first.Schedule != "tick" || first.StartedAt == nil || first.ExitCode == nil || *first.ExitCode != 0 {
+		t.Errorf("record = %+v", first)
+	}
+}
+
+func TestScheduler_OverlapPolicies(t *testing.T) {
+	tests := []struct {
+		overlap     string
+		wantSkipped bool
+		wantPeak    int32 // 0 = more than one
+	}{
+		{config.OverlapSkip, true, 1},
+		{config.OverlapQueue, false, 1},
+		{config.OverlapAllow, false, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.overlap, func(t *testing.T) {
+			rec := &recorder{}
+			var c concurrency
+			s := &Scheduler{
+				Jobs: []Job{{Name: "slow", Schedule: every(15 * time.Millisecond), Overlap: tt.overlap}},
+				Run: func(ctx context.Context, job Job) error {
+					c.enter()
+					defer c.exit()
+					time.Sleep(50 * time.Millisecond)
+					return nil
+				},
+				Record: rec.add,
+			}
+
+			runFor(s, 200*time.Millisecond)
+
+			if skipped := rec.count(StatusSkipped) > 0; skipped != tt.wantSkipped {
+				t.Errorf("skipped records = %d, want skipped=%v", rec.count(StatusSkipped), tt.wantSkipped)
+			}
+			peak := c.peak.Load()
+			if tt.wantPeak == 0 && peak < 2 {
+				t.Errorf("peak concurrency = %d, want > 1", peak)
+			}
+			if tt.wantPeak != 0 && peak != tt.wantPeak {
+				t.Errorf("peak concurrency = %d, want %d", peak, tt.wantPeak)
+			}
+			if rec.count(StatusSuccess) < 2 {
+				t.Errorf("success records = %d, want at least 2", rec.count(StatusSuccess))
+			}
+		})
+	}
+}
+
+func TestScheduler_GracefulShutdown(t *testing.T) {
+	tests := []struct {
+		name       string
+		runTime    time.Duration
+		wantStatus string
+	}{
+		{"finishes within grace period", 60 * time.Millisecond, StatusSuccess},
+		{"stopped after grace period", time.Hour, StatusStopped},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := &recorder{}
+			var started atomic.Bool
+			s := &Scheduler{
+				Jobs: []Job{{Name: "long", Schedule: every(10 * time.Millisecond)}},
+				Run: func(ctx context.Context, job Job) error {
+					if !started.CompareAndSwap(false, true) {
+						return nil
+					}
+					select {
+					case <-time.After(tt.runTime):
+						return nil
+					case <-ctx.Done():
+						return ctx.Err()
+					}
+				},
+				Record:      rec.add,
+				GracePeriod: 150 * time.Millisecond,
+			}
+
+			begin := time.Now()
+			runFor(s, 30*time.Millisecond)
+			if elapsed := time.Since(begin); elapsed > time.Second {
+				t.Errorf("shutdown took %s", elapsed)
+			}
+
+			if rec.count(tt.wantStatus) == 0 {
+				t.Errorf("records = %+v, want one %s", rec.records, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestScheduler_FailureAndJitter(t *testing.T) {
+	rec := &recorder{}
+	var jitterLimit atomic.Int64
+	s := &Scheduler{
+		Jobs: []Job{{Name: "fail", TaskName: "broken", Schedule: every(20 * time.Millisecond), Jitter: time.Minute}},
+		Run: func(ctx context.Context, job Job) error {
+			return &tasks.ExitError{Task: job.TaskName, Code: 4}
+		},
+		Record: rec.add,
+		jitter: func(limit time.Duration) time.Duration {
+			jitterLimit.Store(int64(limit))
+			return 0
+		},
+	}
+
+	runFor(s, 70*time.Millisecond)
+
+	if time.Duration(jitterLimit.Load()) != time.Minute {
+		t.Errorf("jitter limit = %s, want 1m", time.Duration(jitterLimit.Load()))
+	}
+	if rec.count(StatusFailed) == 0 {
+		t.Fatalf("records = %+v, want failures", rec.records)
+	}
+	if r := rec.records[0]; r.ExitCode == nil || *r.ExitCode != 4 || r.Task != "broken" {
+		t.Errorf("record = %+v, want exit code 4", r)
+	}
+}
+
+func TestJobs(t *testing.T) {
+	cfg := config.Default()
+	cfg.Tasks["backup"] = config.Task{Command: "restic"}
+	cfg.Schedules = []config.Schedule{{Name: "nightly", Task: "backup", Cron: "0 3 * * *", Jitter: time.Minute}}
+
+	jobs, err := Jobs(cfg)
+	if err != nil {
+		t.Fatalf("Jobs() error = %v", err)
+	}
+	if len(jobs) != 1 || jobs[0].Task.Command != "restic" || jobs[0].Overlap != config.OverlapSkip {
+		t.Fatalf("Jobs() = %+v", jobs)
+	}
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.Local)
+	summaries := Summarize(jobs, now)
+	want := Summary{Name: "nightly", Task: "backup", Cron: "0 3 * * *", Jitter: "1m0s", Overlap: "skip", NextRun: time.Date(2026, 5, 2, 3, 0, 0, 0, time.Local)}
+	if len(summaries) != 1 || summaries[0] != want {
+		t.Errorf("Summarize() = %+v, want %+v", summaries, want)
+	}
+
+	for _, tt := range []struct {
+		sched config.Schedule
+		want  string
+	}{
+		{config.Schedule{Name: "x", Task: "missing", Cron: "@daily"}, `unknown task "missing"`},
+		{config.Schedule{Name: "x", Task: "backup", Cron: "not cron"}, `invalid cron "not cron"`},
+	} {
+		cfg.Schedules = []config.Schedule{tt.sched}
+		if _, err := Jobs(cfg); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Jobs(%+v) error = %v, want %q", tt.sched, err, tt.want)
+		}
+	}
+}
+
+func TestRunLog(t *testing.T) {
+	var buf bytes.Buffer
+	code := 0
+	at := time.Date(2026, 1, 2, 3, 4, 0, 0, time.UTC)
+	if err := NewRunLog(&buf).Write(Record{Schedule: "s", Task: "t", Status: StatusSuccess, ScheduledAt: at, ExitCode: &code}); err != nil {
+		t.Fatal(err)
+	}
+	want := `{"schedule":"s","task":"t","status":"success","scheduled_at":"2026-01-02T03:04:00Z","exit_code":0}` + "\n"
+	if buf.String() != want {
+		t.Errorf("run log = %q, want %q", buf.String(), want)
+	}
+
+	path := filepath.Join(t.TempDir(), "logs", "schedule.jsonl")
+	for range 2 {
+		l, err := OpenRunLog(path)
+		if err != nil {
+			t.Fatalf("OpenRunLog() error = %v", err)
+		}
+		if err := l.Write(Record{Schedule: "s", Status: StatusSkipped}); err != nil {
+			t.Fatal(err)
+		}
+		if err := l.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("run log has %d lines, want 2 (appended)", len(lines))
+	}
+	var rec Record
+	if err := json.Unmarshal([]byte(lines[1]), &rec); err != nil || rec.Status != StatusSkipped {
+		t.Errorf("line = %q, err = %v", lines[1], err)
+	}
+}
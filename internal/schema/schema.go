@@ -0,0 +1,141 @@
+// Package schema generates JSON Schema documents from Go types.
+//
+// Schemas are derived from struct fields and their `json` tags, so they
+// describe exactly what ado emits with --output json (and, by the same
+// data model, --output yaml).
+package schema
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Draft is the JSON Schema dialect emitted by Generate.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is a JSON Schema document or subschema.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty" yaml:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty" yaml:"$id,omitempty"`
+	Title                string             `json:"title,omitempty" yaml:"title,omitempty"`
+	Version              int                `json:"x-schema-version,omitempty" yaml:"x-schema-version,omitempty"`
+	Ref                  string             `json:"$ref,omitempty" yaml:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty" yaml:"type,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty" yaml:"anyOf,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty" yaml:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty" yaml:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty" yaml:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty" yaml:"items,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Defs                 map[string]*Schema `json:"$defs,omitempty" yaml:"$defs,omitempty"`
+}
+
+// Options describes the root document produced by Generate.
+type Options struct {
+	ID      string
+	Title   string
+	Version int
+}
+
+// Generate returns the JSON Schema for the type of v. Named struct types
+// other than the root are emitted once under $defs and referenced by $ref.
+func Generate(v any, opts Options) *Schema {
+	g := &generator{defs: map[string]*Schema{}}
+
+	t := reflect.TypeOf(v)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	root := g.object(t)
+	root.Schema = Draft
+	root.ID = opts.ID
+	root.Title = opts.Title
+	root.Version = opts.Version
+	if len(g.defs) > 0 {
+		root.Defs = g.defs
+	}
+	return root
+}
+
+type generator struct {
+	defs map[string]*Schema
+}
+
+func (g *generator) schemaFor(t reflect.Type) *Schema {
+	switch t.Kind() {
+	case reflect.Pointer:
+		return &Schema{AnyOf: []*Schema{g.schemaFor(t.Elem()), {Type: "null"}}}
+	case reflect.Struct:
+		name := t.Name()
+		if name == "" {
+			return g.object(t)
+		}
+		if _, ok := g.defs[name]; !ok {
+			g.defs[name] = nil // reserve to stop recursion
+			g.defs[name] = g.object(t)
+		}
+		return &Schema{Ref: "#/$defs/" + name}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schemaFor(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schemaFor(t.Elem())}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &Schema{Type: "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		zero := 0.0
+		return &Schema{Type: "integer", Minimum: &zero}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	default:
+		// Interfaces and other dynamic values accept anything.
+		return &Schema{}
+	}
+}
+
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, omitEmpty, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		s.Properties[name] = g.schemaFor(field.Type)
+		if !omitEmpty {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	return s
+}
+
+// jsonFieldName mirrors encoding/json's tag handling.
+func jsonFieldName(field reflect.StructField) (name string, omitEmpty, skip bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = field.Name
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" || opt == "omitzero" {
+			omitEmpty = true
+		}
+	}
+	return name, omitEmpty, false
+}
@@ -0,0 +1,89 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+type inner struct {
+	Name string `json:"name"`
+}
+
+type sample struct {
+	Str      string            `json:"str"`
+	Count    int32             `json:"count"`
+	Size     uint64            `json:"size"`
+	Ratio    float64           `json:"ratio"`
+	Enabled  bool              `json:"enabled"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Inner    inner             `json:"inner"`
+	Optional *inner            `json:"optional"`
+	Note     string            `json:"note,omitempty"`
+	Skipped  string            `json:"-"`
+	NoTag    string
+	hidden   string
+}
+
+func TestGenerate(t *testing.T) {
+	s := Generate(&sample{}, Options{ID: "urn:test", Title: "sample", Version: 3})
+
+	if s.Schema != Draft || s.ID != "urn:test" || s.Title != "sample" || s.Version != 3 {
+		t.Errorf("root metadata = %q %q %q %d", s.Schema, s.ID, s.Title, s.Version)
+	}
+	if s.Type != "object" {
+		t.Fatalf("root type = %q, want object", s.Type)
+	}
+
+	tests := []struct {
+		prop string
+		want *Schema
+	}{
+		{"str", &Schema{Type: "string"}},
+		{"count", &Schema{Type: "integer"}},
+		{"ratio", &Schema{Type: "number"}},
+		{"enabled", &Schema{Type: "boolean"}},
+		{"tags", &Schema{Type: "array", Items: &Schema{Type: "string"}}},
+		{"labels", &Schema{Type: "object", AdditionalProperties: &Schema{Type: "string"}}},
+		{"inner", &Schema{Ref: "#/$defs/inner"}},
+		{"optional", &Schema{AnyOf: []*Schema{{Ref: "#/$defs/inner"}, {Type: "null"}}}},
+		{"NoTag", &Schema{Type: "string"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.prop, func(t *testing.T) {
+			if got := s.Properties[tt.prop]; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("property %q = %+v, want %+v", tt.prop, got, tt.want)
+			}
+		})
+	}
+
+	if size := s.Properties["size"]; size.Type != "integer" || size.Minimum == nil || *size.Minimum != 0 {
+		t.Errorf("unsigned property = %+v, want integer with minimum 0", size)
+	}
+	for _, absent := range []string{"Skipped", "-", "hidden"} {
+		if _, ok := s.Properties[absent]; ok {
+			t.Errorf("property %q should be excluded", absent)
+		}
+	}
+
+	wantRequired := []string{"str", "count", "size", "ratio", "enabled", "tags", "labels", "inner", "optional", "NoTag"}
+	if !reflect.DeepEqual(s.Required, wantRequired) {
+		t.Errorf("Required = %v, want %v", s.Required, wantRequired)
+	}
+
+	def, ok := s.Defs["inner"]
+	if !ok || def.Properties["name"].Type != "string" {
+		t.Errorf("$defs[inner] = %+v, want object with string name", def)
+	}
+}
+
+type node struct {
+	Children []node `json:"children"`
+}
+
+func TestGenerate_Recursive(t *testing.T) {
+	s := Generate(node{}, Options{})
+	if got := s.Properties["children"].Items.Ref; got != "#/$defs/node" {
+		t.Errorf("recursive reference = %q, want #/$defs/node", got)
+	}
+}
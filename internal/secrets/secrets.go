@@ -0,0 +1,239 @@
+// Package secrets stores named secrets in the OS keyring (macOS Keychain,
+// Windows Credential Manager, or the Secret Service on Linux) and expands
+// secret://NAME references in strings.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/zalando/go-keyring"
+
+	"github.com/anowarislam/ado/internal/ghactions"
+)
+
+// Service is the keyring service name ado stores secrets under.
+const Service = "ado"
+
+// RefPrefix starts a secret reference.
+const RefPrefix = "secret://"
+
+// ErrNotFound is returned when a secret does not exist.
+var ErrNotFound = errors.New("secret not found")
+
+// Store holds named secrets.
+type Store interface {
+	Get(name string) (string, error)
+	Set(name, value string) error
+	Delete(name string) error
+	List() ([]string, error)
+}
+
+// Default returns the OS keyring store. In a GitHub Actions step the
+// values it reads are masked in the job log.
+func Default() Store {
+	store := Keyring{Service: Service}
+	if ghactions.Enabled() {
+		// Workflow commands on stderr are honored too, and keep the mask
+		// out of output captured with $(ado secret get NAME).
+		return Masked{Store: store, Mask: func(value string) { _ = ghactions.AddMask(os.Stderr, value) }}
+	}
+	return store
+}
+
+// ValidateName reports whether name can be used for a secret: letters,
+// digits, '.', '_' and '-', starting with a letter or digit.
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: use letters, digits, '.', '_' and '-', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+var (
+	namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+	refPattern  = regexp.MustCompile(regexp.QuoteMeta(RefPrefix) + `([A-Za-z0-9][A-Za-z0-9._-]*)`)
+)
+
+// HasRef reports whether s contains a secret reference.
+func HasRef(s string) bool {
+	return strings.Contains(s, RefPrefix)
+}
+
+// Expand replaces every secret://NAME reference in s with the secret's
+// value. The store is only consulted when s contains a reference.
+func Expand(store Store, s string) (string, error) {
+	if !HasRef(s) {
+		return s, nil
+	}
+	var firstErr error
+	out := refPattern.ReplaceAllStringFunc(s, func(ref string) string {
+		name := strings.TrimPrefix(ref, RefPrefix)
+		value, err := store.Get(name)
+		if err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("resolve %s: %w", ref, err)
+		}
+		return value
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return out, nil
+}
+
+// ExpandAll expands references in each string of values, in place.
+func ExpandAll(store Store, values []string) error {
+	for i, v := range values {
+		expanded, err := Expand(store, v)
+		if err != nil {
+			return err
+		}
+		values[i] = expanded
+	}
+	return nil
+}
+
+// Keyring is a Store backed by the OS keyring. The keyring has no portable
+// way to enumerate entries, so the names are also kept in an index entry.
+type Keyring struct {
+	Service string
+}
+
+// indexKey holds the newline-separated list of secret names. It cannot
+// collide with a secret because names must start with a letter or digit.
+const indexKey = ".index"
+
+// Get returns the named secret.
+func (k Keyring) Get(name string) (string, error) {
+	value, err := keyring.Get(k.Service, name)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return "", fmt.Errorf("read keyring: %w", err)
+	}
+	return value, nil
+}
+
+// Set stores value under name, replacing any existing secret.
+func (k Keyring) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if err := keyring.Set(k.Service, name, value); err != nil {
+		return fmt.Errorf("write keyring: %w", err)
+	}
+	names, err := k.List()
+	if err != nil {
+		return err
+	}
+	if i := sort.SearchStrings(names, name); i < len(names) && names[i] == name {
+		return nil
+	}
+	return k.writeIndex(append(names, name))
+}
+
+// Delete removes the named secret.
+func (k Keyring) Delete(name string) error {
+	err := keyring.Delete(k.Service, name)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return fmt.Errorf("write keyring: %w", err)
+	}
+
+	names, err := k.List()
+	if err != nil {
+		return err
+	}
+	kept := names[:0]
+	for _, n := range names {
+		if n != name {
+			kept = append(kept, n)
+		}
+	}
+	return k.writeIndex(kept)
+}
+
+// List returns the names of stored secrets, sorted.
+func (k Keyring) List() ([]string, error) {
+	index, err := keyring.Get(k.Service, indexKey)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read keyring: %w", err)
+	}
+	names := strings.Fields(index)
+	sort.Strings(names)
+	return names, nil
+}
+
+func (k Keyring) writeIndex(names []string) error {
+	sort.Strings(names)
+	if err := keyring.Set(k.Service, indexKey, strings.Join(names, "\n")); err != nil {
+		return fmt.Errorf("write keyring: %w", err)
+	}
+	return nil
+}
+
+// Masked is a Store that passes every value it reads to Mask before
+// returning it, so the value can be hidden wherever it ends up printed.
+type Masked struct {
+	Store
+	Mask func(value string)
+}
+
+// Get returns the named secret after masking it.
+func (m Masked) Get(name string) (string, error) {
+	value, err := m.Store.Get(name)
+	if err == nil {
+		m.Mask(value)
+	}
+	return value, err
+}
+
+// Map is an in-memory Store.
+type Map map[string]string
+
+// Get returns the named secret.
+func (m Map) Get(name string) (string, error) {
+	value, ok := m[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return value, nil
+}
+
+// Set stores value under name.
+func (m Map) Set(name, value string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	m[name] = value
+	return nil
+}
+
+// Delete removes the named secret.
+func (m Map) Delete(name string) error {
+	if _, ok := m[name]; !ok {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	delete(m, name)
+	return nil
+}
+
+// List returns the names of stored secrets, sorted.
+func (m Map) List() ([]string, error) {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
@@ -0,0 +1,143 @@
+package secrets
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"token", "GITHUB_TOKEN", "prod.db-password", "1password"} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) error = %v", name, err)
+		}
+	}
+	for _, name := range []string{"", ".index", "-x", "a b", "a/b", "a:b"} {
+		if err := ValidateName(name); err == nil {
+			t.Errorf("ValidateName(%q) = nil, want error", name)
+		}
+	}
+}
+
+func TestExpand(t *testing.T) {
+	store := Map{"token": "s3cr3t", "user": "alice"}
+
+	tests := []struct {
+		in      string
+		want    string
+		wantErr string
+	}{
+		{"plain value", "plain value", ""},
+		{"secret://token", "s3cr3t", ""},
+		{"Authorization:Bearer secret://token", "Authorization:Bearer s3cr3t", ""},
+		{"secret://user:secret://token@host", "alice:s3cr3t@host", ""},
+		{"secret://missing", "", "resolve secret://missing: secret not found: missing"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Expand(store, tt.in)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("Expand() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expand() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Expand() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandAll(t *testing.T) {
+	values := []string{"a", "secret://token"}
+	if err := ExpandAll(Map{"token": "x"}, values); err != nil {
+		t.Fatalf("ExpandAll() error = %v", err)
+	}
+	if !reflect.DeepEqual(values, []string{"a", "x"}) {
+		t.Errorf("values = %q", values)
+	}
+}
+
+func TestMasked(t *testing.T) {
+	var masked []string
+	store := Masked{Store: Map{"token": "s3cr3t"}, Mask: func(v string) { masked = append(masked, v) }}
+
+	got, err := Expand(store, "Bearer secret://token")
+	if err != nil || got != "Bearer s3cr3t" {
+		t.Fatalf("Expand() = %q, %v", got, err)
+	}
+	if _, err := store.Get("missing"); err == nil {
+		t.Error("Get(missing) error = nil")
+	}
+	if !reflect.DeepEqual(masked, []string{"s3cr3t"}) {
+		t.Errorf("masked = %q, want the token only", masked)
+	}
+}
+
+func TestDefault_GitHubActions(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "")
+	if _, ok := Default().(Keyring); !ok {
+		t.Errorf("Default() = %T outside Actions, want Keyring", Default())
+	}
+	t.Setenv("GITHUB_ACTIONS", "true")
+	if _, ok := Default().(Masked); !ok {
+		t.Errorf("Default() = %T in Actions, want Masked", Default())
+	}
+}
+
+func TestKeyring(t *testing.T) {
+	keyring.MockInit()
+	k := Keyring{Service: Service}
+
+	if names, err := k.List(); err != nil || len(names) != 0 {
+		t.Fatalf("List() on empty keyring = %v, %v", names, err)
+	}
+	if _, err := k.Get("token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+
+	for _, kv := range [][2]string{{"token", "one"}, {"api", "two"}, {"token", "three"}} {
+		if err := k.Set(kv[0], kv[1]); err != nil {
+			t.Fatalf("Set(%s) error = %v", kv[0], err)
+		}
+	}
+	if value, err := k.Get("token"); err != nil || value != "three" {
+		t.Errorf("Get(token) = %q, %v", value, err)
+	}
+	if names, _ := k.List(); !reflect.DeepEqual(names, []string{"api", "token"}) {
+		t.Errorf("List() = %q", names)
+	}
+
+	if err := k.Delete("api"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if names, _ := k.List(); !reflect.DeepEqual(names, []string{"token"}) {
+		t.Errorf("List() after delete = %q", names)
+	}
+	if err := k.Delete("api"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete(missing) error = %v, want ErrNotFound", err)
+	}
+	if err := k.Set(".index", "x"); err == nil || !strings.Contains(err.Error(), "invalid secret name") {
+		t.Errorf("Set(.index) error = %v", err)
+	}
+}
+
+func TestKeyring_Unavailable(t *testing.T) {
+	keyring.MockInitWithError(errors.New("no keyring daemon"))
+	k := Keyring{Service: Service}
+
+	if _, err := k.Get("token"); err == nil || !strings.Contains(err.Error(), "read keyring: no keyring daemon") {
+		t.Errorf("Get() error = %v", err)
+	}
+	if err := k.Set("token", "x"); err == nil || !strings.Contains(err.Error(), "write keyring") {
+		t.Errorf("Set() error = %v", err)
+	}
+}
@@ -0,0 +1,286 @@
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/tasks"
+	"github.com/anowarislam/ado/pkg/adov1"
+)
+
+// GRPCServer returns a gRPC server with the AdoService registered and,
+// when Token is set, every call authenticated with it.
+func (s *Server) GRPCServer() *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(s.unaryAuth),
+		grpc.ChainStreamInterceptor(s.streamAuth),
+	)
+	adov1.RegisterAdoServiceServer(srv, &grpcService{s: s})
+	return srv
+}
+
+// ServeGRPC serves the gRPC API on ln until ctx is cancelled, then waits
+// up to gracePeriod for in-flight calls before cutting them off.
+func (s *Server) ServeGRPC(ctx context.Context, ln net.Listener, gracePeriod time.Duration) error {
+	srv := s.GRPCServer()
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errc:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		srv.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(gracePeriod):
+		srv.Stop()
+		return errors.New("shutdown: grace period exceeded")
+	}
+	if err := <-errc; err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+		return fmt.Errorf("serve: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) unaryAuth(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if err := s.checkToken(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *Server) streamAuth(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := s.checkToken(ss.Context()); err != nil {
+		return err
+	}
+	return handler(srv, ss)
+}
+
+// checkToken expects "authorization: Bearer TOKEN" call metadata.
+func (s *Server) checkToken(ctx context.Context) error {
+	if s.Token == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	for _, got := range md.Get("authorization") {
+		if subtle.ConstantTimeCompare([]byte(got), []byte("Bearer "+s.Token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+}
+
+// grpcService implements adov1.AdoServiceServer on top of Server.
+type grpcService struct {
+	adov1.UnimplementedAdoServiceServer
+	s *Server
+}
+
+func (g *grpcService) GetBuildInfo(context.Context, *adov1.GetBuildInfoRequest) (*adov1.BuildInfo, error) {
+	info := g.s.BuildInfo
+	return &adov1.BuildInfo{
+		Name:      info.Name,
+		Version:   info.Version,
+		Commit:    info.Commit,
+		BuildTime: info.BuildTime,
+		Dirty:     info.Dirty,
+		GoVersion: info.GoVersion,
+		Platform:  info.Platform,
+	}, nil
+}
+
+func (g *grpcService) GetSystemInfo(ctx context.Context, req *adov1.GetSystemInfoRequest) (*adov1.SystemInfo, error) {
+	collect := g.s.CollectSystem
+	if collect == nil {
+		collect = meta.CollectSystemInfo
+	}
+	info := collect(ctx, meta.SystemOptions{
+		SkipNetwork:     req.GetNoNetwork(),
+		IncludeSecurity: req.GetSecurity(),
+	})
+
+	details, err := toStruct(info)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "encode system info: %v", err)
+	}
+	out := &adov1.SystemInfo{
+		SchemaVersion: int32(info.SchemaVersion),
+		Os:            info.OS,
+		Platform:      info.Platform,
+		Kernel:        info.Kernel,
+		Architecture:  info.Architecture,
+		Cpu: &adov1.CPUInfo{
+			Model:         info.CPU.Model,
+			Vendor:        info.CPU.Vendor,
+			Cores:         info.CPU.Cores,
+			FrequencyMhz:  info.CPU.FrequencyMHz,
+			EffectiveCpus: info.CPU.EffectiveCPUs,
+			LimitSource:   info.CPU.LimitSource,
+		},
+		Memory: &adov1.MemoryInfo{
+			TotalMb:          info.Memory.TotalMB,
+			AvailableMb:      info.Memory.AvailableMB,
+			UsedMb:           info.Memory.UsedMB,
+			UsedPercent:      info.Memory.UsedPercent,
+			SwapTotalMb:      info.Memory.SwapTotalMB,
+			SwapUsedMb:       info.Memory.SwapUsedMB,
+			EffectiveLimitMb: info.Memory.EffectiveLimitMB,
+			LimitSource:      info.Memory.LimitSource,
+		},
+		Details: details,
+	}
+	for _, st := range info.Storage {
+		out.Storage = append(out.Storage, &adov1.StorageInfo{
+			Device:      st.Device,
+			Mountpoint:  st.Mountpoint,
+			Filesystem:  st.Filesystem,
+			TotalMb:     st.TotalMB,
+			UsedMb:      st.UsedMB,
+			FreeMb:      st.FreeMB,
+			UsedPercent: st.UsedPercent,
+		})
+	}
+	return out, nil
+}
+
+// toStruct converts v to a protobuf Struct through its JSON encoding, so
+// the document matches `-o json` output.
+func toStruct(v any) (*structpb.Struct, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]any
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+	return structpb.NewStruct(m)
+}
+
+func (g *grpcService) ListTasks(context.Context, *adov1.ListTasksRequest) (*adov1.ListTasksResponse, error) {
+	cfg, _, err := g.s.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	resp := &adov1.ListTasksResponse{}
+	for _, t := range tasks.List(cfg.Tasks) {
+		resp.Tasks = append(resp.Tasks, &adov1.Task{
+			Name:        t.Name,
+			Description: t.Description,
+			Command:     t.Command,
+			Args:        t.Args,
+		})
+	}
+	return resp, nil
+}
+
+func (g *grpcService) RunTask(req *adov1.RunTaskRequest, stream adov1.AdoService_RunTaskServer) error {
+	cfg, path, err := g.s.loadConfig()
+	if err != nil {
+		return err
+	}
+	task, ok := cfg.Tasks[req.GetName()]
+	if !ok {
+		names := make([]string, 0, len(cfg.Tasks))
+		for name := range cfg.Tasks {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return status.Errorf(codes.NotFound, "unknown task %q (available: %v)", req.GetName(), names)
+	}
+
+	sender := &streamSender{stream: stream}
+	runner := tasks.Runner{
+		Stdout:  sender.writer(adov1.Output_STREAM_STDOUT),
+		Stderr:  sender.writer(adov1.Output_STREAM_STDERR),
+		Secrets: g.s.Secrets,
+	}
+	if path != "" {
+		runner.BaseDir = filepath.Dir(path)
+	}
+
+	g.s.logger().Info("Running task", "task", req.GetName(), "transport", "grpc")
+	err = runner.Run(stream.Context(), req.GetName(), task, req.GetArgs())
+	code := 0
+	var exitErr *tasks.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		code = exitErr.Code
+	case stream.Context().Err() != nil:
+		return status.FromContextError(stream.Context().Err()).Err()
+	case err != nil:
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return sender.send(&adov1.RunTaskResponse{
+		Event: &adov1.RunTaskResponse_Exit{Exit: &adov1.Exit{Code: int32(code)}},
+	})
+}
+
+// loadConfig wraps Server.LoadConfig with gRPC status codes.
+func (s *Server) loadConfig() (*config.Config, string, error) {
+	if s.LoadConfig == nil {
+		return nil, "", status.Error(codes.FailedPrecondition, "no config available")
+	}
+	cfg, path, err := s.LoadConfig()
+	if err != nil {
+		return nil, "", status.Errorf(codes.FailedPrecondition, "load config: %v", err)
+	}
+	return cfg, path, nil
+}
+
+// streamSender serializes sends from the stdout and stderr writers, which
+// the task's process may write to concurrently.
+type streamSender struct {
+	mu     sync.Mutex
+	stream adov1.AdoService_RunTaskServer
+}
+
+func (s *streamSender) send(resp *adov1.RunTaskResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stream.Send(resp)
+}
+
+func (s *streamSender) writer(which adov1.Output_Stream) *outputWriter {
+	return &outputWriter{sender: s, stream: which}
+}
+
+type outputWriter struct {
+	sender *streamSender
+	stream adov1.Output_Stream
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	data := append([]byte(nil), p...)
+	err := w.sender.send(&adov1.RunTaskResponse{
+		Event: &adov1.RunTaskResponse_Output{Output: &adov1.Output{Stream: w.stream, Data: data}},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,267 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/secrets"
+	"github.com/anowarislam/ado/pkg/adov1"
+)
+
+func newGRPCClient(t *testing.T, s *Server) adov1.AdoServiceClient {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	srv := s.GRPCServer()
+	go func() { _ = srv.Serve(ln) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return ln.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return adov1.NewAdoServiceClient(conn)
+}
+
+func taskServer(t *testing.T, tasks map[string]config.Task) *Server {
+	t.Helper()
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	return &Server{
+		LoadConfig: func() (*config.Config, string, error) {
+			return &config.Config{Version: 1, Tasks: tasks}, path, nil
+		},
+		Secrets: secrets.Map{"greeting": "hello"},
+	}
+}
+
+func TestGRPC_GetBuildInfo(t *testing.T) {
+	client := newGRPCClient(t, &Server{BuildInfo: meta.BuildInfo{Name: "ado", Version: "1.2.3", Dirty: true}})
+
+	info, err := client.GetBuildInfo(context.Background(), &adov1.GetBuildInfoRequest{})
+	if err != nil {
+		t.Fatalf("GetBuildInfo() error = %v", err)
+	}
+	if info.GetVersion() != "1.2.3" || info.GetName() != "ado" || !info.GetDirty() {
+		t.Errorf("GetBuildInfo() = %v", info)
+	}
+}
+
+func TestGRPC_GetSystemInfo(t *testing.T) {
+	var gotOpts meta.SystemOptions
+	client := newGRPCClient(t, &Server{
+		CollectSystem: func(_ context.Context, opts meta.SystemOptions) meta.SystemInfo {
+			gotOpts = opts
+			return meta.SystemInfo{
+				SchemaVersion: 1,
+				OS:            "testos",
+				CPU:           meta.CPUInfo{Cores: 8},
+				Storage:       []meta.StorageInfo{{Mountpoint: "/"}},
+			}
+		},
+	})
+
+	info, err := client.GetSystemInfo(context.Background(), &adov1.GetSystemInfoRequest{NoNetwork: true})
+	if err != nil {
+		t.Fatalf("GetSystemInfo() error = %v", err)
+	}
+	if !gotOpts.SkipNetwork || gotOpts.IncludeSecurity {
+		t.Errorf("options = %+v", gotOpts)
+	}
+	if info.GetOs() != "testos" || info.GetCpu().GetCores() != 8 || len(info.GetStorage()) != 1 {
+		t.Errorf("GetSystemInfo() = %v", info)
+	}
+	if got := info.GetDetails().GetFields()["os"].GetStringValue(); got != "testos" {
+		t.Errorf("details.os = %q, want testos", got)
+	}
+}
+
+func TestGRPC_ListTasks(t *testing.T) {
+	client := newGRPCClient(t, taskServer(t, map[string]config.Task{
+		"test":  {Command: "go", Args: []string{"test"}, Description: "Run tests"},
+		"build": {Command: "go"},
+	}))
+
+	resp, err := client.ListTasks(context.Background(), &adov1.ListTasksRequest{})
+	if err != nil {
+		t.Fatalf("ListTasks() error = %v", err)
+	}
+	var names []string
+	for _, task := range resp.GetTasks() {
+		names = append(names, task.GetName())
+	}
+	if len(names) != 2 || names[0] != "build" || names[1] != "test" {
+		t.Errorf("ListTasks() names = %v", names)
+	}
+}
+
+// collect drains a RunTask stream into its stdout, stderr and exit code.
+func collect(t *testing.T, stream adov1.AdoService_RunTaskClient) (stdout, stderr string, code int32, err error) {
+	t.Helper()
+	code = -1
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return stdout, stderr, code, nil
+		}
+		if err != nil {
+			return stdout, stderr, code, err
+		}
+		if out := resp.GetOutput(); out != nil {
+			switch out.GetStream() {
+			case adov1.Output_STREAM_STDOUT:
+				stdout += string(out.GetData())
+			case adov1.Output_STREAM_STDERR:
+				stderr += string(out.GetData())
+			}
+		}
+		if exit := resp.GetExit(); exit != nil {
+			code = exit.GetCode()
+		}
+	}
+}
+
+func TestGRPC_RunTask(t *testing.T) {
+	if _, err := os.Stat("/bin/sh"); err != nil {
+		t.Skip("requires /bin/sh")
+	}
+	client := newGRPCClient(t, taskServer(t, map[string]config.Task{
+		"greet": {Command: "sh", Args: []string{"-c", `echo "$1 $0"; echo oops >&2`, "secret://greeting"}},
+		"fail":  {Command: "sh", Args: []string{"-c", "exit 3"}},
+	}))
+
+	tests := []struct {
+		name       string
+		req        *adov1.RunTaskRequest
+		wantStdout string
+		wantStderr string
+		wantCode   int32
+	}{
+		{"output and args", &adov1.RunTaskRequest{Name: "greet", Args: []string{"world"}}, "world hello\n", "oops\n", 0},
+		{"exit code", &adov1.RunTaskRequest{Name: "fail"}, "", "", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := client.RunTask(context.Background(), tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			stdout, stderr, code, err := collect(t, stream)
+			if err != nil {
+				t.Fatalf("RunTask() error = %v", err)
+			}
+			if stdout != tt.wantStdout || stderr != tt.wantStderr || code != tt.wantCode {
+				t.Errorf("RunTask() = (%q, %q, %d), want (%q, %q, %d)",
+					stdout, stderr, code, tt.wantStdout, tt.wantStderr, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestGRPC_RunTaskErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		server *Server
+		task   string
+		want   codes.Code
+	}{
+		{"unknown task", taskServer(t, nil), "nope", codes.NotFound},
+		{"no config", &Server{}, "build", codes.FailedPrecondition},
+		{"config error", &Server{LoadConfig: func() (*config.Config, string, error) {
+			return nil, "", errors.New("broken")
+		}}, "build", codes.FailedPrecondition},
+		{"missing command", taskServer(t, map[string]config.Task{"empty": {}}), "empty", codes.FailedPrecondition},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := newGRPCClient(t, tt.server).RunTask(context.Background(), &adov1.RunTaskRequest{Name: tt.task})
+			if err != nil {
+				t.Fatal(err)
+			}
+			_, _, _, err = collect(t, stream)
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("RunTask() code = %v, want %v (err %v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestGRPC_Auth(t *testing.T) {
+	client := newGRPCClient(t, &Server{Token: "s3cret"})
+
+	tests := []struct {
+		name  string
+		token string
+		want  codes.Code
+	}{
+		{"missing", "", codes.Unauthenticated},
+		{"wrong", "Bearer nope", codes.Unauthenticated},
+		{"valid", "Bearer s3cret", codes.OK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, "authorization", tt.token)
+			}
+			_, err := client.GetBuildInfo(ctx, &adov1.GetBuildInfoRequest{})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("GetBuildInfo() code = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGRPC_StreamAuth(t *testing.T) {
+	client := newGRPCClient(t, &Server{Token: "s3cret"})
+	stream, err := client.RunTask(context.Background(), &adov1.RunTaskRequest{Name: "x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, err := collect(t, stream); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("RunTask() error = %v, want Unauthenticated", err)
+	}
+}
+
+func TestServeGRPC_StopsOnCancel(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- (&Server{}).ServeGRPC(ctx, ln, time.Second) }()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("ServeGRPC() error = %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ServeGRPC() did not return after cancel")
+	}
+}
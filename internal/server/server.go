@@ -0,0 +1,233 @@
+// Package server exposes ado diagnostics over a local REST API and a gRPC
+// service (see pkg/adov1).
+package server
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/logging"
+	"github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/secrets"
+)
+
+// MaxConfigBytes bounds the body accepted by /v1/config/validate.
+const MaxConfigBytes = 1 << 20
+
+// Server serves the diagnostics API.
+type Server struct {
+	// Token, when set, must be sent as "Authorization: Bearer TOKEN" on
+	// every /v1 request and gRPC call. /healthz is always open.
+	Token     string
+	BuildInfo meta.BuildInfo
+	Logger    logging.Logger
+	// CollectSystem gathers system diagnostics; defaults to
+	// meta.CollectSystemInfo.
+	CollectSystem func(context.Context, meta.SystemOptions) meta.SystemInfo
+	// LoadConfig returns the config and its path for the task RPCs; they
+	// fail with FailedPrecondition when nil.
+	LoadConfig func() (*config.Config, string, error)
+	// Secrets resolves secret:// references in task args and env.
+	Secrets secrets.Store
+}
+
+// Handler returns the API routes.
+func (s *Server) Handler() http.Handler {
+	routes := []struct {
+		method, path string
+		handler      http.Handler
+	}{
+		{http.MethodGet, "/healthz", http.HandlerFunc(s.handleHealth)},
+		{http.MethodGet, "/v1/meta/info", s.authorize(http.HandlerFunc(s.handleInfo))},
+		{http.MethodGet, "/v1/meta/system", s.authorize(http.HandlerFunc(s.handleSystem))},
+		{http.MethodPost, "/v1/config/validate", s.authorize(http.HandlerFunc(s.handleValidate))},
+	}
+
+	mux := http.NewServeMux()
+	for _, route := range routes {
+		mux.Handle(route.method+" "+route.path, route.handler)
+		// The method-less pattern only matches other methods, so errors
+		// stay JSON instead of the mux's plain-text 405.
+		method := route.method
+		mux.HandleFunc(route.path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Allow", method)
+			writeError(w, http.StatusMethodNotAllowed, r.Method+" not allowed; use "+method)
+		})
+	}
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusNotFound, "no such endpoint: "+r.URL.Path)
+	})
+	return s.logRequests(mux)
+}
+
+// Serve handles connections on ln until ctx is cancelled, then stops
+// accepting connections and waits up to gracePeriod for in-flight requests.
+func (s *Server) Serve(ctx context.Context, ln net.Listener, gracePeriod time.Duration) error {
+	srv := &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errc := make(chan error, 1)
+	go func() { errc <- srv.Serve(ln) }()
+
+	select {
+	case err := <-errc:
+		return fmt.Errorf("serve: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), gracePeriod)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("shutdown: %w", err)
+	}
+	if err := <-errc; !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serve: %w", err)
+	}
+	return nil
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+func (s *Server) handleInfo(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.BuildInfo)
+}
+
+// handleSystem accepts the boolean query parameters no_network and
+// security, mirroring `ado meta system --no-network --security`.
+func (s *Server) handleSystem(w http.ResponseWriter, r *http.Request) {
+	var opts meta.SystemOptions
+	var err error
+	if opts.SkipNetwork, err = boolParam(r, "no_network"); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if opts.IncludeSecurity, err = boolParam(r, "security"); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	collect := s.CollectSystem
+	if collect == nil {
+		collect = meta.CollectSystemInfo
+	}
+	writeJSON(w, http.StatusOK, collect(r.Context(), opts))
+}
+
+// handleValidate validates the YAML config in the request body. The
+// response is the validation result; an invalid config is still 200.
+func (s *Server) handleValidate(w http.ResponseWriter, r *http.Request) {
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxConfigBytes))
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		writeError(w, http.StatusRequestEntityTooLarge, fmt.Sprintf("config exceeds %d bytes", MaxConfigBytes))
+		return
+	case err != nil:
+		writeError(w, http.StatusBadRequest, "read body: "+err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, config.ValidateData("request body", data))
+}
+
+// authorize rejects requests without the bearer token, when one is set.
+func (s *Server) authorize(next http.Handler) http.Handler {
+	if s.Token == "" {
+		return next
+	}
+	want := []byte("Bearer " + s.Token)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := []byte(r.Header.Get("Authorization"))
+		if subtle.ConstantTimeCompare(got, want) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="ado"`)
+			writeError(w, http.StatusUnauthorized, "missing or invalid bearer token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// statusRecorder captures the status code for request logging.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (s *Server) logger() logging.Logger {
+	if s.Logger == nil {
+		return logging.NopLogger()
+	}
+	return s.Logger
+}
+
+func (s *Server) logRequests(next http.Handler) http.Handler {
+	log := s.logger()
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		log.Debug("request",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"duration_ms", time.Since(start).Milliseconds(),
+			"remote", r.RemoteAddr,
+		)
+	})
+}
+
+func boolParam(r *http.Request, name string) (bool, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return false, nil
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, fmt.Errorf("invalid %s=%q: want true or false", name, raw)
+	}
+	return v, nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
+
+// IsLoopback reports whether addr (host:port) only listens on a loopback
+// interface.
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
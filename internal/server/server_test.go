@@ -0,0 +1,218 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anowarislam/ado/internal/meta"
+)
+
+func newTestServer(t *testing.T, token string) (*httptest.Server, *meta.SystemOptions) {
+	t.Helper()
+	var gotOpts meta.SystemOptions
+	s := &Server{
+		Token:     token,
+		BuildInfo: meta.BuildInfo{Version: "1.2.3"},
+		CollectSystem: func(_ context.Context, opts meta.SystemOptions) meta.SystemInfo {
+			gotOpts = opts
+			return meta.SystemInfo{OS: "testos"}
+		},
+	}
+	srv := httptest.NewServer(s.Handler())
+	t.Cleanup(srv.Close)
+	return srv, &gotOpts
+}
+
+func request(t *testing.T, method, url, token, body string) (int, map[string]any) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	var payload map[string]any
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		t.Fatalf("decode response: %v", err)
+	}
+	return resp.StatusCode, payload
+}
+
+func TestHandler(t *testing.T) {
+	srv, _ := newTestServer(t, "")
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantKey    string
+		wantValue  any
+	}{
+		{"health", "GET", "/healthz", "", 200, "status", "ok"},
+		{"info", "GET", "/v1/meta/info", "", 200, "version", "1.2.3"},
+		{"system", "GET", "/v1/meta/system", "", 200, "os", "testos"},
+		{"validate ok", "POST", "/v1/config/validate", "version: 1\n", 200, "valid", true},
+		{"validate bad", "POST", "/v1/config/validate", "version: [", 200, "valid", false},
+		{"validate empty", "POST", "/v1/config/validate", "", 200, "valid", false},
+		{"wrong method", "GET", "/v1/config/validate", "", 405, "error", "GET not allowed; use POST"},
+		{"unknown path", "GET", "/v1/nope", "", 404, "error", "no such endpoint: /v1/nope"},
+		{"bad query", "GET", "/v1/meta/system?security=maybe", "", 400, "error", `invalid security="maybe": want true or false`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if tt.wantKey == "" {
+				return
+			}
+			var payload map[string]any
+			if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if payload[tt.wantKey] != tt.wantValue {
+				t.Errorf("%s = %v, want %v", tt.wantKey, payload[tt.wantKey], tt.wantValue)
+			}
+		})
+	}
+}
+
+func TestHandler_SystemOptions(t *testing.T) {
+	srv, opts := newTestServer(t, "")
+	if status, _ := request(t, "GET", srv.URL+"/v1/meta/system?no_network=true&security=1", "", ""); status != 200 {
+		t.Fatalf("status = %d", status)
+	}
+	if !opts.SkipNetwork || !opts.IncludeSecurity {
+		t.Errorf("options = %+v, want both set", *opts)
+	}
+}
+
+func TestHandler_Token(t *testing.T) {
+	srv, _ := newTestServer(t, "s3cr3t")
+
+	tests := []struct {
+		name       string
+		path       string
+		token      string
+		wantStatus int
+	}{
+		{"health is open", "/healthz", "", 200},
+		{"missing token", "/v1/meta/info", "", 401},
+		{"wrong token", "/v1/meta/info", "nope", 401},
+		{"valid token", "/v1/meta/info", "s3cr3t", 200},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, payload := request(t, "GET", srv.URL+tt.path, tt.token, "")
+			if status != tt.wantStatus {
+				t.Errorf("status = %d, want %d (%v)", status, tt.wantStatus, payload)
+			}
+		})
+	}
+}
+
+func TestHandler_ValidateTooLarge(t *testing.T) {
+	srv, _ := newTestServer(t, "")
+	body := "a: " + strings.Repeat("x", MaxConfigBytes)
+	status, payload := request(t, "POST", srv.URL+"/v1/config/validate", "", body)
+	if status != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413 (%v)", status, payload)
+	}
+}
+
+func TestServe_GracefulShutdown(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	release := make(chan struct{})
+	s := &Server{CollectSystem: func(context.Context, meta.SystemOptions) meta.SystemInfo {
+		<-release
+		return meta.SystemInfo{OS: "done"}
+	}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- s.Serve(ctx, ln, 5*time.Second) }()
+
+	url := "http://" + ln.Addr().String()
+	got := make(chan string, 1)
+	go func() {
+		resp, err := http.Get(url + "/v1/meta/system")
+		if err != nil {
+			got <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		got <- string(body)
+	}()
+
+	// Wait until the slow request is in flight, then shut down.
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if resp, err := http.Get(url + "/healthz"); err == nil {
+			resp.Body.Close()
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("server did not start")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+
+	if body := <-got; !strings.Contains(body, `"os": "done"`) {
+		t.Errorf("in-flight request = %q, want it to complete", body)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Serve() error = %v", err)
+	}
+}
+
+func TestIsLoopback(t *testing.T) {
+	tests := map[string]bool{
+		"127.0.0.1:8080": true,
+		"localhost:80":   true,
+		"[::1]:8080":     true,
+		":8080":          false,
+		"0.0.0.0:8080":   false,
+		"10.0.0.5:8080":  false,
+		"not-an-addr":    false,
+	}
+	for addr, want := range tests {
+		if got := IsLoopback(addr); got != want {
+			t.Errorf("IsLoopback(%q) = %v, want %v", addr, got, want)
+		}
+	}
+}
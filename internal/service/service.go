@@ -0,0 +1,523 @@
+// Package service generates and installs OS service definitions that run
+// ado's long-running modes, `ado serve`, `ado schedule run`, and
+// `ado agent run`: systemd units, launchd property lists, and Windows
+// services.
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/anowarislam/ado/internal/config"
+)
+
+// Long-running modes that can be installed as a service.
+const (
+	ModeServe    = "serve"
+	ModeSchedule = "schedule"
+	ModeAgent    = "agent"
+)
+
+// Modes lists the installable modes.
+var Modes = []string{ModeServe, ModeSchedule, ModeAgent}
+
+// Service managers.
+const (
+	PlatformSystemd = "systemd"
+	PlatformLaunchd = "launchd"
+	PlatformWindows = "windows"
+)
+
+// Platforms lists the supported service managers.
+var Platforms = []string{PlatformSystemd, PlatformLaunchd, PlatformWindows}
+
+// DefaultPlatform returns the service manager of the running OS.
+func DefaultPlatform() string {
+	switch runtime.GOOS {
+	case "darwin":
+		return PlatformLaunchd
+	case "windows":
+		return PlatformWindows
+	}
+	return PlatformSystemd
+}
+
+// launchdPrefix namespaces launchd labels, reverse-DNS style.
+const launchdPrefix = "io.github.anowarislam.ado."
+
+// Spec describes one service.
+type Spec struct {
+	Mode     string
+	Platform string
+	// System installs a system-wide service instead of one for the
+	// current user. Windows services are always system-wide.
+	System bool
+	// Executable is the ado binary the service runs.
+	Executable string
+	// Args are the arguments after the executable.
+	Args []string
+	// Env is set in the service's environment.
+	Env map[string]string
+
+	// dir holds the unit file; logDir holds launchd's output log.
+	dir    string
+	logDir string
+	uid    int
+}
+
+// NewSpec returns the service running mode with ado at executable. A
+// non-empty configPath is passed as --config, and extra arguments follow
+// the mode's command.
+func NewSpec(mode, platform string, system bool, executable, configPath string, extra []string) (*Spec, error) {
+	if !slices.Contains(Modes, mode) {
+		return nil, fmt.Errorf("unknown mode %q (expected %s)", mode, strings.Join(Modes, ", "))
+	}
+	if !slices.Contains(Platforms, platform) {
+		return nil, fmt.Errorf("unknown platform %q (expected %s)", platform, strings.Join(Platforms, ", "))
+	}
+
+	s := &Spec{Mode: mode, Platform: platform, System: system || platform == PlatformWindows, Executable: executable, Env: map[string]string{}, uid: os.Getuid()}
+	if configPath != "" {
+		s.Args = append(s.Args, "--config", configPath)
+	}
+	switch mode {
+	case ModeServe:
+		s.Args = append(s.Args, "serve")
+		// serve is experimental; enable it for the service whatever the
+		// config says.
+		s.Env["ADO_FEATURES"] = "serve"
+	case ModeSchedule:
+		s.Args = append(s.Args, "schedule", "run")
+	case ModeAgent:
+		s.Args = append(s.Args, "agent", "run")
+	}
+	s.Args = append(s.Args, extra...)
+
+	homeDir, _ := os.UserHomeDir()
+	switch {
+	case platform == PlatformSystemd && s.System:
+		s.dir = "/etc/systemd/system"
+	case platform == PlatformSystemd:
+		configHome := os.Getenv("XDG_CONFIG_HOME")
+		if configHome == "" {
+			if homeDir == "" {
+				return nil, errors.New("cannot locate the systemd user unit directory: neither $XDG_CONFIG_HOME nor $HOME is defined")
+			}
+			configHome = filepath.Join(homeDir, ".config")
+		}
+		s.dir = filepath.Join(configHome, "systemd", "user")
+	case platform == PlatformLaunchd && s.System:
+		s.dir, s.logDir = "/Library/LaunchDaemons", "/Library/Logs/ado"
+	case platform == PlatformLaunchd:
+		if homeDir == "" {
+			return nil, errors.New("cannot locate ~/Library/LaunchAgents: $HOME is not defined")
+		}
+		s.dir, s.logDir = filepath.Join(homeDir, "Library", "LaunchAgents"), config.ResolveDirs().Log
+	}
+	return s, nil
+}
+
+// Name is the unit, label, or Windows service name.
+func (s *Spec) Name() string {
+	switch s.Platform {
+	case PlatformSystemd:
+		return "ado-" + s.Mode + ".service"
+	case PlatformLaunchd:
+		return launchdPrefix + s.Mode
+	}
+	return "ado-" + s.Mode
+}
+
+// Path is the unit or property list file, or "" for Windows services,
+// which live in the registry.
+func (s *Spec) Path() string {
+	switch s.Platform {
+	case PlatformSystemd:
+		return filepath.Join(s.dir, s.Name())
+	case PlatformLaunchd:
+		return filepath.Join(s.dir, s.Name()+".plist")
+	}
+	return ""
+}
+
+func (s *Spec) description() string {
+	switch s.Mode {
+	case ModeServe:
+		return "ado diagnostics API (ado serve)"
+	case ModeAgent:
+		return "ado snapshot agent (ado agent run)"
+	}
+	return "ado task scheduler (ado schedule run)"
+}
+
+// Render returns the unit file or property list. For Windows services it
+// returns the equivalent sc.exe commands.
+func (s *Spec) Render() []byte {
+	switch s.Platform {
+	case PlatformSystemd:
+		return s.renderSystemd()
+	case PlatformLaunchd:
+		return s.renderLaunchd()
+	}
+	return s.renderWindows()
+}
+
+func (s *Spec) renderSystemd() []byte {
+	var b bytes.Buffer
+	b.WriteString("# Generated by 'ado service install'; reinstalling overwrites changes.\n")
+	b.WriteString("[Unit]\n")
+	fmt.Fprintf(&b, "Description=%s\n", s.description())
+	b.WriteString("Documentation=https://github.com/anowarislam/ado\n")
+	b.WriteString("After=network-online.target\nWants=network-online.target\n\n")
+	b.WriteString("[Service]\nType=simple\n")
+	argv := append([]string{s.Executable}, s.Args...)
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = SystemdQuote(arg)
+	}
+	fmt.Fprintf(&b, "ExecStart=%s\n", strings.Join(quoted, " "))
+	for _, key := range slices.Sorted(maps.Keys(s.Env)) {
+		fmt.Fprintf(&b, "Environment=%s\n", SystemdQuote(key+"="+s.Env[key]))
+	}
+	b.WriteString("Restart=on-failure\nRestartSec=5\n\n")
+	b.WriteString("[Install]\n")
+	if s.System {
+		b.WriteString("WantedBy=multi-user.target\n")
+	} else {
+		b.WriteString("WantedBy=default.target\n")
+	}
+	return b.Bytes()
+}
+
+// SystemdQuote quotes arg for ExecStart= and Environment=, escaping the
+// % specifiers and $ variables systemd would expand.
+func SystemdQuote(arg string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "%", "%%", "$", "$$").Replace(arg)
+	if arg == "" || strings.ContainsAny(arg, " \t\n'\"\\;") {
+		return `"` + escaped + `"`
+	}
+	return escaped
+}
+
+func (s *Spec) renderLaunchd() []byte {
+	var b bytes.Buffer
+	str := func(v string) string {
+		var e bytes.Buffer
+		_ = xml.EscapeText(&e, []byte(v))
+		return "<string>" + e.String() + "</string>"
+	}
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<!-- Generated by 'ado service install'; reinstalling overwrites changes. -->
+<plist version="1.0">
+<dict>
+`)
+	fmt.Fprintf(&b, "\t<key>Label</key>\n\t%s\n", str(s.Name()))
+	b.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range append([]string{s.Executable}, s.Args...) {
+		fmt.Fprintf(&b, "\t\t%s\n", str(arg))
+	}
+	b.WriteString("\t</array>\n")
+	if len(s.Env) > 0 {
+		b.WriteString("\t<key>EnvironmentVariables</key>\n\t<dict>\n")
+		for _, key := range slices.Sorted(maps.Keys(s.Env)) {
+			fmt.Fprintf(&b, "\t\t<key>%s</key>\n\t\t%s\n", key, str(s.Env[key]))
+		}
+		b.WriteString("\t</dict>\n")
+	}
+	b.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n")
+	b.WriteString("\t<key>KeepAlive</key>\n\t<dict>\n\t\t<key>SuccessfulExit</key>\n\t\t<false/>\n\t</dict>\n")
+	log := s.logPath()
+	fmt.Fprintf(&b, "\t<key>StandardOutPath</key>\n\t%s\n", str(log))
+	fmt.Fprintf(&b, "\t<key>StandardErrorPath</key>\n\t%s\n", str(log))
+	b.WriteString("</dict>\n</plist>\n")
+	return b.Bytes()
+}
+
+func (s *Spec) logPath() string {
+	return filepath.Join(s.logDir, "service-"+s.Mode+".log")
+}
+
+func (s *Spec) renderWindows() []byte {
+	var b bytes.Buffer
+	name := s.Name()
+	b.WriteString(":: Generated by 'ado service install --print'; 'ado service install' does the same.\n")
+	fmt.Fprintf(&b, "sc.exe create %s binPath= %s start= delayed-auto DisplayName= %s\n", name, windowsQuote(s.commandLine()), windowsQuote("ado "+s.Mode))
+	fmt.Fprintf(&b, "sc.exe description %s %s\n", name, windowsQuote(s.description()))
+	fmt.Fprintf(&b, "sc.exe failure %s reset= 86400 actions= restart/5000\n", name)
+	if len(s.Env) > 0 {
+		var env []string
+		for _, key := range slices.Sorted(maps.Keys(s.Env)) {
+			env = append(env, key+"="+s.Env[key])
+		}
+		fmt.Fprintf(&b, "reg.exe add HKLM\\SYSTEM\\CurrentControlSet\\Services\\%s /v Environment /t REG_MULTI_SZ /d %s /f\n", name, windowsQuote(strings.Join(env, `\0`)))
+	}
+	fmt.Fprintf(&b, "sc.exe start %s\n", name)
+	return b.Bytes()
+}
+
+// commandLine is the Windows command line of the service.
+func (s *Spec) commandLine() string {
+	argv := append([]string{s.Executable}, s.Args...)
+	quoted := make([]string, len(argv))
+	for i, arg := range argv {
+		quoted[i] = windowsQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// windowsQuote quotes arg for a Windows command line.
+func windowsQuote(arg string) string {
+	if arg != "" && !strings.ContainsAny(arg, " \t\"") {
+		return arg
+	}
+	return `"` + strings.ReplaceAll(arg, `"`, `\"`) + `"`
+}
+
+// Step is one change Install or Uninstall makes.
+type Step struct {
+	// Verb is write, remove, mkdir, run, create, or delete.
+	Verb string
+	// Target is a file, directory, command, or Windows service.
+	Target string
+	Detail string
+
+	data []byte
+	argv []string
+	// optional steps may fail, such as stopping a service that is not
+	// running.
+	optional bool
+}
+
+// InstallSteps returns the changes that install and start s, replacing
+// an earlier installation.
+func (s *Spec) InstallSteps() []Step {
+	switch s.Platform {
+	case PlatformSystemd:
+		return []Step{
+			writeStep(s.Path(), s.Render(), s.description()),
+			s.systemctl(false, "daemon-reload"),
+			s.systemctl(false, "enable", s.Name()),
+			s.systemctl(false, "restart", s.Name()),
+		}
+	case PlatformLaunchd:
+		return []Step{
+			{Verb: "mkdir", Target: s.logDir, Detail: "for " + filepath.Base(s.logPath())},
+			s.launchctl(true, "bootout", s.launchdTarget()),
+			writeStep(s.Path(), s.Render(), s.description()),
+			s.launchctl(false, "bootstrap", s.launchdDomain(), s.Path()),
+		}
+	}
+	return []Step{{Verb: "create", Target: "service " + s.Name(), Detail: s.commandLine()}}
+}
+
+// UninstallSteps returns the changes that stop and remove s.
+func (s *Spec) UninstallSteps() []Step {
+	switch s.Platform {
+	case PlatformSystemd:
+		return []Step{
+			s.systemctl(true, "disable", "--now", s.Name()),
+			{Verb: "remove", Target: s.Path()},
+			s.systemctl(false, "daemon-reload"),
+		}
+	case PlatformLaunchd:
+		return []Step{
+			s.launchctl(true, "bootout", s.launchdTarget()),
+			{Verb: "remove", Target: s.Path()},
+		}
+	}
+	return []Step{{Verb: "delete", Target: "service " + s.Name()}}
+}
+
+func writeStep(path string, data []byte, detail string) Step {
+	return Step{Verb: "write", Target: path, Detail: detail, data: data}
+}
+
+func runStep(optional bool, argv ...string) Step {
+	return Step{Verb: "run", Target: argv[0], Detail: strings.Join(argv[1:], " "), argv: argv, optional: optional}
+}
+
+func (s *Spec) systemctl(optional bool, args ...string) Step {
+	if !s.System {
+		args = append([]string{"--user"}, args...)
+	}
+	return runStep(optional, append([]string{"systemctl"}, args...)...)
+}
+
+func (s *Spec) launchctl(optional bool, args ...string) Step {
+	return runStep(optional, append([]string{"launchctl"}, args...)...)
+}
+
+func (s *Spec) launchdDomain() string {
+	if s.System {
+		return "system"
+	}
+	return "gui/" + strconv.Itoa(s.uid)
+}
+
+func (s *Spec) launchdTarget() string {
+	return s.launchdDomain() + "/" + s.Name()
+}
+
+// Runner runs a service manager command and returns its combined output.
+type Runner func(ctx context.Context, name string, args ...string) ([]byte, error)
+
+func execRunner(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+// Manager applies install and uninstall steps and queries service state.
+type Manager struct {
+	// Run runs systemctl and launchctl; defaults to executing them.
+	Run Runner
+}
+
+func (m *Manager) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	if m.Run != nil {
+		return m.Run(ctx, name, args...)
+	}
+	return execRunner(ctx, name, args...)
+}
+
+// Apply makes the changes of steps in order, stopping at the first
+// failure.
+func (m *Manager) Apply(ctx context.Context, s *Spec, steps []Step) error {
+	for _, step := range steps {
+		var err error
+		switch step.Verb {
+		case "write":
+			if err = os.MkdirAll(filepath.Dir(step.Target), 0o755); err == nil {
+				err = os.WriteFile(step.Target, step.data, 0o644)
+			}
+		case "remove":
+			if err = os.Remove(step.Target); errors.Is(err, os.ErrNotExist) {
+				err = nil
+			}
+		case "mkdir":
+			err = os.MkdirAll(step.Target, 0o755)
+		case "run":
+			var out []byte
+			out, err = m.run(ctx, step.argv[0], step.argv[1:]...)
+			if err != nil && step.optional {
+				err = nil
+			} else if err != nil {
+				err = fmt.Errorf("%s: %w: %s", strings.Join(step.argv, " "), err, strings.TrimSpace(string(out)))
+			}
+		case "create":
+			err = installWindowsService(s)
+		case "delete":
+			err = removeWindowsService(s.Name())
+		}
+		if errors.Is(err, os.ErrPermission) && s.System {
+			return fmt.Errorf("%w (system services need root or Administrator)", err)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status is the state of an installed service.
+type Status struct {
+	Mode     string `json:"mode" yaml:"mode"`
+	Platform string `json:"platform" yaml:"platform"`
+	Name     string `json:"name" yaml:"name"`
+	Path     string `json:"path,omitempty" yaml:"path,omitempty"`
+	// Installed reports whether the unit file or Windows service exists.
+	Installed bool `json:"installed" yaml:"installed"`
+	// State is the service manager's state, such as "active (running)",
+	// "running", or "stopped".
+	State string `json:"state,omitempty" yaml:"state,omitempty"`
+	PID   int    `json:"pid,omitempty" yaml:"pid,omitempty"`
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Status reports whether s is installed and, if so, its state.
+func (m *Manager) Status(ctx context.Context, s *Spec) Status {
+	st := Status{Mode: s.Mode, Platform: s.Platform, Name: s.Name(), Path: s.Path()}
+	if s.Platform == PlatformWindows {
+		windowsStatus(s.Name(), &st)
+		return st
+	}
+	if _, err := os.Stat(st.Path); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			st.Error = err.Error()
+		}
+		return st
+	}
+	st.Installed = true
+
+	var (
+		out []byte
+		err error
+	)
+	if s.Platform == PlatformSystemd {
+		step := s.systemctl(false, "show", s.Name(), "--property=ActiveState,SubState,MainPID")
+		out, err = m.run(ctx, step.argv[0], step.argv[1:]...)
+	} else {
+		out, err = m.run(ctx, "launchctl", "print", s.launchdTarget())
+	}
+	if err != nil {
+		if s.Platform == PlatformLaunchd {
+			// launchctl print fails for services that are not loaded.
+			st.State = "not loaded"
+			return st
+		}
+		st.Error = fmt.Sprintf("%v: %s", err, strings.TrimSpace(string(out)))
+		return st
+	}
+	if s.Platform == PlatformSystemd {
+		parseSystemdShow(string(out), &st)
+	} else {
+		parseLaunchdPrint(string(out), &st)
+	}
+	return st
+}
+
+func parseSystemdShow(out string, st *Status) {
+	props := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		if key, value, ok := strings.Cut(strings.TrimSpace(line), "="); ok {
+			props[key] = value
+		}
+	}
+	st.State = props["ActiveState"]
+	if sub := props["SubState"]; sub != "" {
+		st.State += " (" + sub + ")"
+	}
+	st.PID, _ = strconv.Atoi(props["MainPID"])
+}
+
+// parseLaunchdPrint reads the top-level state and pid of `launchctl
+// print`, which is meant for people and may change between macOS
+// releases.
+func parseLaunchdPrint(out string, st *Status) {
+	for _, line := range strings.Split(out, "\n") {
+		// Top-level properties are indented by one tab.
+		if !strings.HasPrefix(line, "\t") || strings.HasPrefix(line, "\t\t") {
+			continue
+		}
+		key, value, ok := strings.Cut(strings.TrimSpace(line), " = ")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "state":
+			st.State = value
+		case "pid":
+			st.PID, _ = strconv.Atoi(value)
+		}
+	}
+}
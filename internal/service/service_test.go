@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNewSpec(t *testing.T) {
+	configHome := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configHome)
+
+	s, err := NewSpec(ModeServe, PlatformSystemd, false, "/usr/local/bin/ado", "/etc/ado/config.yaml", []string{"--addr", "127.0.0.1:9000"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(s.Args, " "); got != "--config /etc/ado/config.yaml serve --addr 127.0.0.1:9000" {
+		t.Errorf("Args = %s", got)
+	}
+	if s.Env["ADO_FEATURES"] != "serve" {
+		t.Errorf("Env = %v", s.Env)
+	}
+	if want := filepath.Join(configHome, "systemd", "user", "ado-serve.service"); s.Path() != want {
+		t.Errorf("Path = %s, want %s", s.Path(), want)
+	}
+
+	s, _ = NewSpec(ModeSchedule, PlatformLaunchd, true, "/opt/ado", "", nil)
+	if s.Name() != "io.github.anowarislam.ado.schedule" || s.Path() != "/Library/LaunchDaemons/io.github.anowarislam.ado.schedule.plist" {
+		t.Errorf("Name = %s, Path = %s", s.Name(), s.Path())
+	}
+	s, _ = NewSpec(ModeSchedule, PlatformWindows, false, `C:\ado\ado.exe`, "", nil)
+	if !s.System || s.Path() != "" || s.Name() != "ado-schedule" {
+		t.Errorf("windows spec = %+v", s)
+	}
+	s, _ = NewSpec(ModeAgent, PlatformSystemd, false, "ado", "", []string{"--interval", "10s"})
+	if strings.Join(s.Args, " ") != "agent run --interval 10s" || !strings.Contains(string(s.Render()), "Description=ado snapshot agent (ado agent run)") {
+		t.Errorf("agent spec = %+v", s)
+	}
+
+	for _, args := range [][2]string{{"watch", PlatformSystemd}, {ModeServe, "upstart"}} {
+		if _, err := NewSpec(args[0], args[1], false, "ado", "", nil); err == nil {
+			t.Errorf("NewSpec(%q, %q) succeeded", args[0], args[1])
+		}
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	tests := []struct {
+		platform string
+		system   bool
+		want     []string
+	}{
+		{PlatformSystemd, true, []string{
+			`ExecStart=/usr/local/bin/ado serve --token "secret://serve token" --addr 100%%`,
+			"Environment=ADO_FEATURES=serve\n",
+			"WantedBy=multi-user.target\n",
+		}},
+		{PlatformSystemd, false, []string{"WantedBy=default.target\n"}},
+		{PlatformLaunchd, false, []string{
+			"<key>Label</key>\n\t<string>io.github.anowarislam.ado.serve</string>",
+			"\t\t<string>secret://serve token</string>\n",
+			"<key>ADO_FEATURES</key>\n\t\t<string>serve</string>",
+			"service-serve.log</string>",
+		}},
+		{PlatformWindows, true, []string{
+			`sc.exe create ado-serve binPath= "/usr/local/bin/ado serve --token \"secret://serve token\" --addr 100%" start= delayed-auto`,
+			`/d ADO_FEATURES=serve /f`,
+		}},
+	}
+	for _, tt := range tests {
+		s, err := NewSpec(ModeServe, tt.platform, tt.system, "/usr/local/bin/ado", "", []string{"--token", "secret://serve token", "--addr", "100%"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := string(s.Render())
+		for _, want := range tt.want {
+			if !strings.Contains(got, want) {
+				t.Errorf("%s: render missing %q:\n%s", tt.platform, want, got)
+			}
+		}
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := map[string]string{
+		"serve":           "serve",
+		"":                `""`,
+		"a b":             `"a b"`,
+		`say "hi"`:        `"say \"hi\""`,
+		"$HOME":           "$$HOME",
+		"%h/config.yaml":  "%%h/config.yaml",
+		`C:\path`:         `"C:\\path"`,
+		"--label=a;b":     `"--label=a;b"`,
+		"KEY=with spaces": `"KEY=with spaces"`,
+	}
+	for in, want := range tests {
+		if got := SystemdQuote(in); got != want {
+			t.Errorf("SystemdQuote(%q) = %s, want %s", in, got, want)
+		}
+	}
+}
+
+// fakeRunner records commands and fails those starting with fail.
+type fakeRunner struct {
+	calls []string
+	fail  string
+	out   string
+}
+
+func (f *fakeRunner) run(_ context.Context, name string, args ...string) ([]byte, error) {
+	call := strings.Join(append([]string{name}, args...), " ")
+	f.calls = append(f.calls, call)
+	if f.fail != "" && strings.HasPrefix(call, f.fail) {
+		return []byte("Unit ado-serve.service not loaded."), errors.New("exit status 5")
+	}
+	return []byte(f.out), nil
+}
+
+func TestManager_InstallUninstall(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	s, _ := NewSpec(ModeServe, PlatformSystemd, false, "/usr/local/bin/ado", "", nil)
+	fake := &fakeRunner{fail: "systemctl --user disable"}
+	m := &Manager{Run: fake.run}
+
+	if err := m.Apply(context.Background(), s, s.InstallSteps()); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(s.Path())
+	if err != nil || !strings.Contains(string(data), "ExecStart=/usr/local/bin/ado serve") {
+		t.Fatalf("unit = %q, %v", data, err)
+	}
+
+	// Stopping a unit that is not running is not an error.
+	if err := m.Apply(context.Background(), s, s.UninstallSteps()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(s.Path()); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("unit still exists: %v", err)
+	}
+	want := []string{
+		"systemctl --user daemon-reload",
+		"systemctl --user enable ado-serve.service",
+		"systemctl --user restart ado-serve.service",
+		"systemctl --user disable --now ado-serve.service",
+		"systemctl --user daemon-reload",
+	}
+	if strings.Join(fake.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("commands =\n%s\nwant\n%s", strings.Join(fake.calls, "\n"), strings.Join(want, "\n"))
+	}
+
+	fake = &fakeRunner{fail: "systemctl --user restart"}
+	m.Run = fake.run
+	err = m.Apply(context.Background(), s, s.InstallSteps())
+	if err == nil || !strings.Contains(err.Error(), "systemctl --user restart ado-serve.service: exit status 5: Unit ado-serve.service not loaded.") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestManager_Status(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	s, _ := NewSpec(ModeSchedule, PlatformSystemd, false, "ado", "", nil)
+	fake := &fakeRunner{out: "ActiveState=active\nSubState=running\nMainPID=4242\n"}
+	m := &Manager{Run: fake.run}
+
+	if st := m.Status(context.Background(), s); st.Installed || st.State != "" || len(fake.calls) != 0 {
+		t.Errorf("status before install = %+v", st)
+	}
+	os.MkdirAll(filepath.Dir(s.Path()), 0o755)
+	os.WriteFile(s.Path(), s.Render(), 0o644)
+	st := m.Status(context.Background(), s)
+	if !st.Installed || st.State != "active (running)" || st.PID != 4242 || st.Name != "ado-schedule.service" {
+		t.Errorf("status = %+v", st)
+	}
+}
+
+func TestParseLaunchdPrint(t *testing.T) {
+	out := "gui/501/io.github.anowarislam.ado.serve = {\n\tactive count = 1\n\tpath = /Users/me/Library/LaunchAgents/io.github.anowarislam.ado.serve.plist\n\tstate = running\n\n\tprogram = /usr/local/bin/ado\n\tpid = 812\n\tendpoint = {\n\t\tstate = active\n\t}\n}\n"
+	var st Status
+	parseLaunchdPrint(out, &st)
+	if st.State != "running" || st.PID != 812 {
+		t.Errorf("status = %+v", st)
+	}
+}
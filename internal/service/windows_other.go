@@ -0,0 +1,22 @@
+//go:build !windows
+
+package service
+
+import (
+	"context"
+	"errors"
+)
+
+var errNotWindows = errors.New("windows services can only be managed on Windows; use --print for the sc.exe commands")
+
+func installWindowsService(*Spec) error { return errNotWindows }
+
+func removeWindowsService(string) error { return errNotWindows }
+
+func windowsStatus(_ string, st *Status) { st.Error = errNotWindows.Error() }
+
+// Main runs run and returns its exit status. On Windows it also runs ado
+// as a service when the service control manager started it.
+func Main(ctx context.Context, run func(context.Context) int) int {
+	return run(ctx)
+}
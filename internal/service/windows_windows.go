@@ -0,0 +1,193 @@
+//go:build windows
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"time"
+
+	"golang.org/x/sys/windows"
+	"golang.org/x/sys/windows/registry"
+	"golang.org/x/sys/windows/svc"
+	"golang.org/x/sys/windows/svc/mgr"
+)
+
+// installWindowsService creates s with the service control manager, or
+// updates it when it exists, and starts it.
+func installWindowsService(s *Spec) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connect to the service manager: %w", err)
+	}
+	defer m.Disconnect()
+
+	cfg := mgr.Config{
+		DisplayName:      "ado " + s.Mode,
+		Description:      s.description(),
+		StartType:        mgr.StartAutomatic,
+		DelayedAutoStart: true,
+	}
+	service, err := m.OpenService(s.Name())
+	if err == nil {
+		current, err := service.Config()
+		if err != nil {
+			service.Close()
+			return fmt.Errorf("read service %s: %w", s.Name(), err)
+		}
+		current.BinaryPathName = s.commandLine()
+		current.DisplayName, current.Description = cfg.DisplayName, cfg.Description
+		current.StartType, current.DelayedAutoStart = cfg.StartType, cfg.DelayedAutoStart
+		if err := service.UpdateConfig(current); err != nil {
+			service.Close()
+			return fmt.Errorf("update service %s: %w", s.Name(), err)
+		}
+	} else if service, err = m.CreateService(s.Name(), s.Executable, cfg, s.Args...); err != nil {
+		return fmt.Errorf("create service %s: %w", s.Name(), err)
+	}
+	defer service.Close()
+
+	restart := []mgr.RecoveryAction{{Type: mgr.ServiceRestart, Delay: 5 * time.Second}}
+	if err := service.SetRecoveryActions(restart, uint32((24 * time.Hour).Seconds())); err != nil {
+		return fmt.Errorf("set recovery actions of %s: %w", s.Name(), err)
+	}
+	if err := setServiceEnv(s); err != nil {
+		return err
+	}
+	if err := service.Start(); err != nil && !errors.Is(err, windows.ERROR_SERVICE_ALREADY_RUNNING) {
+		return fmt.Errorf("start service %s: %w", s.Name(), err)
+	}
+	return nil
+}
+
+// setServiceEnv sets the Environment value the service control manager
+// adds to the service's environment.
+func setServiceEnv(s *Spec) error {
+	key, err := registry.OpenKey(registry.LOCAL_MACHINE, `SYSTEM\CurrentControlSet\Services\`+s.Name(), registry.SET_VALUE)
+	if err != nil {
+		return fmt.Errorf("open service %s registry key: %w", s.Name(), err)
+	}
+	defer key.Close()
+	if len(s.Env) == 0 {
+		if err := key.DeleteValue("Environment"); err != nil && !errors.Is(err, registry.ErrNotExist) {
+			return fmt.Errorf("clear service %s environment: %w", s.Name(), err)
+		}
+		return nil
+	}
+	var env []string
+	for _, k := range slices.Sorted(maps.Keys(s.Env)) {
+		env = append(env, k+"="+s.Env[k])
+	}
+	if err := key.SetStringsValue("Environment", env); err != nil {
+		return fmt.Errorf("set service %s environment: %w", s.Name(), err)
+	}
+	return nil
+}
+
+// removeWindowsService stops and deletes the named service.
+func removeWindowsService(name string) error {
+	m, err := mgr.Connect()
+	if err != nil {
+		return fmt.Errorf("connect to the service manager: %w", err)
+	}
+	defer m.Disconnect()
+	service, err := m.OpenService(name)
+	if err != nil {
+		if errors.Is(err, windows.ERROR_SERVICE_DOES_NOT_EXIST) {
+			return nil
+		}
+		return fmt.Errorf("open service %s: %w", name, err)
+	}
+	defer service.Close()
+	_, _ = service.Control(svc.Stop)
+	if err := service.Delete(); err != nil {
+		return fmt.Errorf("delete service %s: %w", name, err)
+	}
+	return nil
+}
+
+// windowsStates names the states of svc.State.
+var windowsStates = map[svc.State]string{
+	svc.Stopped:         "stopped",
+	svc.StartPending:    "starting",
+	svc.StopPending:     "stopping",
+	svc.Running:         "running",
+	svc.ContinuePending: "continuing",
+	svc.PausePending:    "pausing",
+	svc.Paused:          "paused",
+}
+
+func windowsStatus(name string, st *Status) {
+	m, err := mgr.Connect()
+	if err != nil {
+		st.Error = fmt.Sprintf("connect to the service manager: %v", err)
+		return
+	}
+	defer m.Disconnect()
+	service, err := m.OpenService(name)
+	if err != nil {
+		if !errors.Is(err, windows.ERROR_SERVICE_DOES_NOT_EXIST) {
+			st.Error = err.Error()
+		}
+		return
+	}
+	defer service.Close()
+	st.Installed = true
+	status, err := service.Query()
+	if err != nil {
+		st.Error = err.Error()
+		return
+	}
+	st.State = windowsStates[status.State]
+	st.PID = int(status.ProcessId)
+}
+
+// Main runs run and returns its exit status. When ado was started by the
+// service control manager, run is reported to it as a service, and
+// stopping the service cancels run's context as SIGTERM does elsewhere.
+func Main(ctx context.Context, run func(context.Context) int) int {
+	if isService, err := svc.IsWindowsService(); err != nil || !isService {
+		return run(ctx)
+	}
+	h := &handler{ctx: ctx, run: run}
+	if err := svc.Run("ado", h); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	return h.code
+}
+
+type handler struct {
+	ctx  context.Context
+	run  func(context.Context) int
+	code int
+}
+
+func (h *handler) Execute(_ []string, requests <-chan svc.ChangeRequest, status chan<- svc.Status) (bool, uint32) {
+	status <- svc.Status{State: svc.StartPending}
+	ctx, cancel := context.WithCancel(h.ctx)
+	defer cancel()
+	done := make(chan int, 1)
+	go func() { done <- h.run(ctx) }()
+	status <- svc.Status{State: svc.Running, Accepts: svc.AcceptStop | svc.AcceptShutdown}
+
+	for {
+		select {
+		case h.code = <-done:
+			status <- svc.Status{State: svc.StopPending}
+			return false, uint32(h.code)
+		case req := <-requests:
+			switch req.Cmd {
+			case svc.Interrogate:
+				status <- req.CurrentStatus
+			case svc.Stop, svc.Shutdown:
+				status <- svc.Status{State: svc.StopPending}
+				cancel()
+			}
+		}
+	}
+}
@@ -0,0 +1,392 @@
+// Package state is a small persistent key-value store under ado's state
+// directory. Keys live in namespaces and may expire, so workflows and
+// scripts can keep last-run markers, counters, and cached values between
+// invocations.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/anowarislam/ado/internal/config"
+)
+
+// DefaultNamespace holds keys stored without a namespace.
+const DefaultNamespace = "default"
+
+// FileName is the store's file inside the state directory.
+const FileName = "state.db"
+
+// lockTimeout bounds how long Open waits for another process holding the
+// store.
+const lockTimeout = 5 * time.Second
+
+// ErrNotFound is returned for keys that do not exist or have expired.
+var ErrNotFound = errors.New("key not found")
+
+// Entry is a stored value.
+type Entry struct {
+	Namespace string     `json:"namespace" yaml:"namespace"`
+	Key       string     `json:"key" yaml:"key"`
+	Value     string     `json:"value" yaml:"value"`
+	UpdatedAt time.Time  `json:"updated_at" yaml:"updated_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+}
+
+func (e Entry) expired(now time.Time) bool {
+	return e.ExpiresAt != nil && !now.Before(*e.ExpiresAt)
+}
+
+// record is how an entry is encoded in the database.
+type record struct {
+	Value     string     `json:"v"`
+	UpdatedAt time.Time  `json:"u"`
+	ExpiresAt *time.Time `json:"e,omitempty"`
+}
+
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._:/-]*$`)
+
+// ValidateNamespace reports whether name can be used as a namespace:
+// letters, digits, '.', '_', ':', '/' and '-', starting with a letter or
+// digit.
+func ValidateNamespace(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid namespace %q: use letters, digits, '.', '_', ':', '/' and '-', starting with a letter or digit", name)
+	}
+	return nil
+}
+
+// ValidateKey reports whether key can be stored: non-empty, without
+// newlines or control characters.
+func ValidateKey(key string) error {
+	if key == "" {
+		return errors.New("key must not be empty")
+	}
+	if strings.IndexFunc(key, func(r rune) bool { return r < 0x20 || r == 0x7f }) >= 0 {
+		return fmt.Errorf("invalid key %q: control characters are not allowed", key)
+	}
+	return nil
+}
+
+// DefaultPath returns the store's path in ado's state directory.
+func DefaultPath() (string, error) {
+	dir, err := config.StateDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, FileName), nil
+}
+
+// Store is an open state database. It holds a file lock, so other ado
+// processes wait in Open until it is closed.
+type Store struct {
+	db *bolt.DB
+	// Now is the clock used for timestamps and expiry.
+	Now func() time.Time
+}
+
+// Open opens or creates the store at path.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("create state directory: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: lockTimeout})
+	if errors.Is(err, bolt.ErrTimeout) {
+		return nil, fmt.Errorf("open state store %s: still locked by another ado process after %s", path, lockTimeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("open state store %s: %w", path, err)
+	}
+	return &Store{db: db, Now: time.Now}, nil
+}
+
+// Close releases the store.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Get returns the entry for key. Expired keys are reported as ErrNotFound.
+func (s *Store) Get(namespace, key string) (Entry, error) {
+	var entry Entry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(namespace))
+		if b == nil {
+			return ErrNotFound
+		}
+		data := b.Get([]byte(key))
+		if data == nil {
+			return ErrNotFound
+		}
+		var err error
+		if entry, err = decode(namespace, key, data); err != nil {
+			return err
+		}
+		if entry.expired(s.Now()) {
+			return ErrNotFound
+		}
+		return nil
+	})
+	return entry, err
+}
+
+// Set stores value under key. A positive ttl makes the key expire after
+// that long; otherwise it never expires.
+func (s *Store) Set(namespace, key, value string, ttl time.Duration) (Entry, error) {
+	if err := validate(namespace, key); err != nil {
+		return Entry{}, err
+	}
+	entry := s.newEntry(namespace, key, value, ttl)
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b, err := s.bucket(tx, namespace)
+		if err != nil {
+			return err
+		}
+		return put(b, entry)
+	})
+	return entry, err
+}
+
+// Incr adds delta to the integer stored under key and returns the new
+// entry. Missing and expired keys count from zero. A positive ttl sets a
+// new expiry; otherwise an existing expiry is kept.
+func (s *Store) Incr(namespace, key string, delta int64, ttl time.Duration) (Entry, error) {
+	if err := validate(namespace, key); err != nil {
+		return Entry{}, err
+	}
+	var entry Entry
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b, err := s.bucket(tx, namespace)
+		if err != nil {
+			return err
+		}
+		if entry, err = s.incremented(namespace, key, b.Get([]byte(key)), delta, ttl); err != nil {
+			return err
+		}
+		return put(b, entry)
+	})
+	return entry, err
+}
+
+// IncrPreview returns the entry Incr would store, without storing it.
+func (s *Store) IncrPreview(namespace, key string, delta int64, ttl time.Duration) (Entry, error) {
+	if err := validate(namespace, key); err != nil {
+		return Entry{}, err
+	}
+	var entry Entry
+	err := s.db.View(func(tx *bolt.Tx) error {
+		var data []byte
+		if b := tx.Bucket([]byte(namespace)); b != nil {
+			data = b.Get([]byte(key))
+		}
+		var err error
+		entry, err = s.incremented(namespace, key, data, delta, ttl)
+		return err
+	})
+	return entry, err
+}
+
+// incremented returns the entry for key after adding delta to data, its
+// stored form, which is nil for a missing key.
+func (s *Store) incremented(namespace, key string, data []byte, delta int64, ttl time.Duration) (Entry, error) {
+	var current int64
+	var expires *time.Time
+	if data != nil {
+		old, err := decode(namespace, key, data)
+		if err != nil {
+			return Entry{}, err
+		}
+		if !old.expired(s.Now()) {
+			if current, err = strconv.ParseInt(strings.TrimSpace(old.Value), 10, 64); err != nil {
+				return Entry{}, fmt.Errorf("%s/%s holds %q, not an integer", namespace, key, old.Value)
+			}
+			expires = old.ExpiresAt
+		}
+	}
+	entry := s.newEntry(namespace, key, strconv.FormatInt(current+delta, 10), ttl)
+	if ttl <= 0 {
+		entry.ExpiresAt = expires
+	}
+	return entry, nil
+}
+
+// Delete removes key. It returns ErrNotFound when the key does not exist or
+// has already expired.
+func (s *Store) Delete(namespace, key string) error {
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket([]byte(namespace))
+		if b == nil {
+			return ErrNotFound
+		}
+		data := b.Get([]byte(key))
+		if data == nil {
+			return ErrNotFound
+		}
+		entry, err := decode(namespace, key, data)
+		if err != nil {
+			return err
+		}
+		if err := b.Delete([]byte(key)); err != nil {
+			return err
+		}
+		if entry.expired(s.Now()) {
+			return ErrNotFound
+		}
+		return nil
+	})
+}
+
+// List returns the live entries in namespace whose keys start with prefix,
+// sorted by key. An empty namespace lists every namespace.
+func (s *Store) List(namespace, prefix string) ([]Entry, error) {
+	var entries []Entry
+	now := s.Now()
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			if namespace != "" && string(name) != namespace {
+				return nil
+			}
+			c := b.Cursor()
+			for k, v := c.Seek([]byte(prefix)); k != nil && strings.HasPrefix(string(k), prefix); k, v = c.Next() {
+				entry, err := decode(string(name), string(k), v)
+				if err != nil {
+					return err
+				}
+				if !entry.expired(now) {
+					entries = append(entries, entry)
+				}
+			}
+			return nil
+		})
+	})
+	return entries, err
+}
+
+// Prune deletes expired keys and empty namespaces, returning how many keys
+// were removed. Writes also prune the namespace they touch.
+func (s *Store) Prune() (int, error) {
+	removed := 0
+	now := s.Now()
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		var empty [][]byte
+		err := tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			n, left, err := purge(b, string(name), now)
+			removed += n
+			if left == 0 {
+				empty = append(empty, name)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		for _, name := range empty {
+			if err := tx.DeleteBucket(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return removed, err
+}
+
+// Expired returns the expired entries Prune would delete, by namespace
+// and key.
+func (s *Store) Expired() ([]Entry, error) {
+	var entries []Entry
+	now := s.Now()
+	err := s.db.View(func(tx *bolt.Tx) error {
+		return tx.ForEach(func(name []byte, b *bolt.Bucket) error {
+			return b.ForEach(func(k, v []byte) error {
+				entry, err := decode(string(name), string(k), v)
+				if err == nil && entry.expired(now) {
+					entries = append(entries, entry)
+				}
+				return err
+			})
+		})
+	})
+	return entries, err
+}
+
+// purge deletes the expired keys in b and reports how many were removed
+// and how many are left.
+func purge(b *bolt.Bucket, namespace string, now time.Time) (removed, left int, err error) {
+	var expired [][]byte
+	err = b.ForEach(func(k, v []byte) error {
+		entry, err := decode(namespace, string(k), v)
+		if err != nil {
+			return err
+		}
+		if entry.expired(now) {
+			expired = append(expired, k)
+		} else {
+			left++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, k := range expired {
+		if err := b.Delete(k); err != nil {
+			return removed, left, err
+		}
+		removed++
+	}
+	return removed, left, nil
+}
+
+func (s *Store) newEntry(namespace, key, value string, ttl time.Duration) Entry {
+	now := s.Now().UTC()
+	entry := Entry{Namespace: namespace, Key: key, Value: value, UpdatedAt: now}
+	if ttl > 0 {
+		expires := now.Add(ttl)
+		entry.ExpiresAt = &expires
+	}
+	return entry
+}
+
+// bucket returns the namespace's bucket for writing, creating it and
+// pruning its expired keys.
+func (s *Store) bucket(tx *bolt.Tx, namespace string) (*bolt.Bucket, error) {
+	b, err := tx.CreateBucketIfNotExists([]byte(namespace))
+	if err != nil {
+		return nil, fmt.Errorf("create namespace %q: %w", namespace, err)
+	}
+	if _, _, err := purge(b, namespace, s.Now()); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+func validate(namespace, key string) error {
+	if err := ValidateNamespace(namespace); err != nil {
+		return err
+	}
+	return ValidateKey(key)
+}
+
+func put(b *bolt.Bucket, e Entry) error {
+	data, err := json.Marshal(record{Value: e.Value, UpdatedAt: e.UpdatedAt, ExpiresAt: e.ExpiresAt})
+	if err != nil {
+		return err
+	}
+	return b.Put([]byte(e.Key), data)
+}
+
+func decode(namespace, key string, data []byte) (Entry, error) {
+	var r record
+	if err := json.Unmarshal(data, &r); err != nil {
+		return Entry{}, fmt.Errorf("decode %s/%s: %w", namespace, key, err)
+	}
+	return Entry{Namespace: namespace, Key: key, Value: r.Value, UpdatedAt: r.UpdatedAt, ExpiresAt: r.ExpiresAt}, nil
+}
@@ -0,0 +1,250 @@
+package state
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// clock is a settable time source.
+type clock struct{ now time.Time }
+
+func (c *clock) Now() time.Time { return c.now }
+
+func openStore(t *testing.T) (*Store, *clock) {
+	t.Helper()
+	s, err := Open(filepath.Join(t.TempDir(), "nested", FileName))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+	c := &clock{now: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	s.Now = c.Now
+	return s, c
+}
+
+func TestStore_SetGetDelete(t *testing.T) {
+	s, _ := openStore(t)
+
+	if _, err := s.Get(DefaultNamespace, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get(missing) error = %v, want ErrNotFound", err)
+	}
+	entry, err := s.Set(DefaultNamespace, "greeting", "hello\nworld", 0)
+	if err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if entry.ExpiresAt != nil || entry.UpdatedAt.IsZero() {
+		t.Errorf("entry = %+v", entry)
+	}
+	got, err := s.Get(DefaultNamespace, "greeting")
+	if err != nil || got.Value != "hello\nworld" {
+		t.Errorf("Get() = %+v, %v", got, err)
+	}
+	if _, err := s.Get("other", "greeting"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() in other namespace error = %v", err)
+	}
+
+	if err := s.Delete(DefaultNamespace, "greeting"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := s.Delete(DefaultNamespace, "greeting"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second Delete() error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStore_TTL(t *testing.T) {
+	s, c := openStore(t)
+
+	entry, err := s.Set("cache", "token", "abc", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := c.now.Add(time.Minute); entry.ExpiresAt == nil || !entry.ExpiresAt.Equal(want) {
+		t.Errorf("ExpiresAt = %v, want %v", entry.ExpiresAt, want)
+	}
+	c.now = c.now.Add(59 * time.Second)
+	if _, err := s.Get("cache", "token"); err != nil {
+		t.Errorf("Get() before expiry error = %v", err)
+	}
+	c.now = c.now.Add(time.Second)
+	if _, err := s.Get("cache", "token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() after expiry error = %v, want ErrNotFound", err)
+	}
+	if entries, _ := s.List("cache", ""); len(entries) != 0 {
+		t.Errorf("List() includes expired entries: %+v", entries)
+	}
+	if err := s.Delete("cache", "token"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Delete() of expired key error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestStore_Incr(t *testing.T) {
+	s, c := openStore(t)
+
+	for i, want := range []string{"1", "2", "7"} {
+		delta := int64(1)
+		if i == 2 {
+			delta = 5
+		}
+		entry, err := s.Incr(DefaultNamespace, "runs", delta, 0)
+		if err != nil || entry.Value != want {
+			t.Fatalf("Incr() = %q, %v, want %q", entry.Value, err, want)
+		}
+	}
+
+	// A TTL set once is kept by later increments without one.
+	if _, err := s.Incr("ns", "hits", 1, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	entry, err := s.Incr("ns", "hits", -3, 0)
+	if err != nil || entry.Value != "-2" || entry.ExpiresAt == nil {
+		t.Errorf("Incr() = %+v, %v", entry, err)
+	}
+	// After expiry the counter restarts.
+	c.now = c.now.Add(2 * time.Hour)
+	if entry, _ := s.Incr("ns", "hits", 1, 0); entry.Value != "1" || entry.ExpiresAt != nil {
+		t.Errorf("Incr() after expiry = %+v", entry)
+	}
+
+	if _, err := s.Set(DefaultNamespace, "name", "ado", 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Incr(DefaultNamespace, "name", 1, 0); err == nil || !strings.Contains(err.Error(), "not an integer") {
+		t.Errorf("Incr() on text error = %v", err)
+	}
+}
+
+func TestStore_IncrPreview(t *testing.T) {
+	s, _ := openStore(t)
+	if entry, err := s.IncrPreview("ns", "runs", 2, time.Hour); err != nil || entry.Value != "2" || entry.ExpiresAt == nil {
+		t.Errorf("IncrPreview() on a missing key = %+v, %v", entry, err)
+	}
+	if _, err := s.Incr("ns", "runs", 5, 0); err != nil {
+		t.Fatal(err)
+	}
+	if entry, err := s.IncrPreview("ns", "runs", -1, 0); err != nil || entry.Value != "4" {
+		t.Errorf("IncrPreview() = %+v, %v, want 4", entry, err)
+	}
+	if entry, err := s.Get("ns", "runs"); err != nil || entry.Value != "5" {
+		t.Errorf("Get() after IncrPreview() = %+v, %v, want 5", entry, err)
+	}
+}
+
+func TestStore_IncrConcurrent(t *testing.T) {
+	s, _ := openStore(t)
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.Incr(DefaultNamespace, "n", 1, 0); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if got, _ := s.Get(DefaultNamespace, "n"); got.Value != "20" {
+		t.Errorf("counter = %q, want 20", got.Value)
+	}
+}
+
+func TestStore_List(t *testing.T) {
+	s, _ := openStore(t)
+	for _, kv := range [][3]string{
+		{"b", "cache/two", "2"},
+		{"b", "cache/one", "1"},
+		{"b", "other", "x"},
+		{"a", "cache/a", "a"},
+	} {
+		if _, err := s.Set(kv[0], kv[1], kv[2], 0); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		namespace, prefix string
+		want              string
+	}{
+		{"b", "", "b/cache/one b/cache/two b/other"},
+		{"b", "cache/", "b/cache/one b/cache/two"},
+		{"", "cache/", "a/cache/a b/cache/one b/cache/two"},
+		{"c", "", ""},
+	}
+	for _, tt := range tests {
+		entries, err := s.List(tt.namespace, tt.prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got []string
+		for _, e := range entries {
+			got = append(got, e.Namespace+"/"+e.Key)
+		}
+		if strings.Join(got, " ") != tt.want {
+			t.Errorf("List(%q, %q) = %v, want %s", tt.namespace, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestStore_Prune(t *testing.T) {
+	s, c := openStore(t)
+	if _, err := s.Set("short", "a", "1", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Set("mixed", "a", "1", time.Second); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Set("mixed", "b", "2", 0); err != nil {
+		t.Fatal(err)
+	}
+	c.now = c.now.Add(time.Minute)
+
+	expired, err := s.Expired()
+	if err != nil || len(expired) != 2 || expired[0].Namespace != "mixed" || expired[1].Namespace != "short" {
+		t.Errorf("Expired() = %+v, %v", expired, err)
+	}
+	removed, err := s.Prune()
+	if err != nil || removed != 2 {
+		t.Errorf("Prune() = %d, %v, want 2", removed, err)
+	}
+	if entries, _ := s.List("", ""); len(entries) != 1 || entries[0].Key != "b" {
+		t.Errorf("entries after prune = %+v", entries)
+	}
+	if removed, _ := s.Prune(); removed != 0 {
+		t.Errorf("second Prune() = %d, want 0", removed)
+	}
+}
+
+func TestStore_Validation(t *testing.T) {
+	s, _ := openStore(t)
+	tests := []struct {
+		namespace, key, want string
+	}{
+		{"", "k", "invalid namespace"},
+		{"-x", "k", "invalid namespace"},
+		{"has space", "k", "invalid namespace"},
+		{"ok", "", "key must not be empty"},
+		{"ok", "a\nb", "control characters"},
+	}
+	for _, tt := range tests {
+		if _, err := s.Set(tt.namespace, tt.key, "v", 0); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Set(%q, %q) error = %v, want %q", tt.namespace, tt.key, err, tt.want)
+		}
+	}
+	for _, ns := range []string{"default", "release/prod", "team:ci", "a.b_c-1"} {
+		if err := ValidateNamespace(ns); err != nil {
+			t.Errorf("ValidateNamespace(%q) = %v", ns, err)
+		}
+	}
+}
+
+func TestDefaultPath(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", dir)
+	got, err := DefaultPath()
+	if err != nil || got != filepath.Join(dir, "ado", FileName) {
+		t.Errorf("DefaultPath() = %q, %v", got, err)
+	}
+}
@@ -0,0 +1,64 @@
+// Package tap writes Test Anything Protocol (TAP) version 13 streams, so
+// the results of ado's check commands can be consumed by prove and other
+// TAP harnesses.
+package tap
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Test is one test point.
+type Test struct {
+	OK          bool
+	Description string
+	// Skip, when set, marks the test as skipped for this reason. Skipped
+	// tests count as passing.
+	Skip string
+	// Diagnostics are written as a YAML block after the test point.
+	Diagnostics map[string]any
+}
+
+// Write writes a TAP 13 stream with a plan for tests followed by a test
+// point for each.
+func Write(w io.Writer, tests []Test) error {
+	var b strings.Builder
+	b.WriteString("TAP version 13\n")
+	fmt.Fprintf(&b, "1..%d\n", len(tests))
+	for i, t := range tests {
+		status := "ok"
+		if !t.OK {
+			status = "not ok"
+		}
+		fmt.Fprintf(&b, "%s %d", status, i+1)
+		if t.Description != "" {
+			b.WriteString(" - " + escape(t.Description))
+		}
+		if t.Skip != "" {
+			b.WriteString(" # SKIP " + escape(t.Skip))
+		}
+		b.WriteString("\n")
+		if len(t.Diagnostics) > 0 {
+			data, err := yaml.Marshal(t.Diagnostics)
+			if err != nil {
+				return fmt.Errorf("serialize tap diagnostics: %w", err)
+			}
+			b.WriteString("  ---\n")
+			for _, line := range strings.SplitAfter(strings.TrimSuffix(string(data), "\n"), "\n") {
+				b.WriteString("  " + line)
+			}
+			b.WriteString("\n  ...\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// escape keeps s on one line and keeps a '#' in it from starting a
+// directive.
+func escape(s string) string {
+	return strings.NewReplacer("\\", `\\`, "#", `\#`, "\r", " ", "\n", " ").Replace(s)
+}
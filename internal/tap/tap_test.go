@@ -0,0 +1,43 @@
+package tap
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []Test{
+		{OK: true, Description: "tcp://db:5432"},
+		{Description: "line 3: issue #1\nnext", Diagnostics: map[string]any{"severity": "error", "line": 3}},
+		{OK: true, Description: "signature", Skip: "no signature found"},
+		{OK: true},
+	}
+	var buf bytes.Buffer
+	if err := Write(&buf, tests); err != nil {
+		t.Fatal(err)
+	}
+	want := `TAP version 13
+1..4
+ok 1 - tcp://db:5432
+not ok 2 - line 3: issue \#1 next
+  ---
+  line: 3
+  severity: error
+  ...
+ok 3 - signature # SKIP no signature found
+ok 4
+`
+	if buf.String() != want {
+		t.Errorf("Write() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestWrite_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := Write(&buf, nil); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "TAP version 13\n1..0\n" {
+		t.Errorf("Write() = %q", buf.String())
+	}
+}
@@ -0,0 +1,265 @@
+package tasks
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/anowarislam/ado/internal/watch"
+)
+
+// ArtifactsEnv names the variable holding the artifacts directory of the
+// run, set for the tasks whose artifacts are saved. Tasks can write files
+// of their own there, and ado run saves the artifacts of the tasks a task
+// runs in the same directory.
+const ArtifactsEnv = "ADO_ARTIFACTS_DIR"
+
+// ManifestName is the file in an artifacts directory listing the tasks
+// that ran and what each one produced.
+const ManifestName = "manifest.json"
+
+// Manifest lists the tasks of a run in the order they finished.
+type Manifest struct {
+	Tasks []TaskArtifacts `json:"tasks" yaml:"tasks"`
+}
+
+// TaskArtifacts is what one task run produced. Paths are relative to the
+// artifacts directory, using forward slashes.
+type TaskArtifacts struct {
+	Task string `json:"task" yaml:"task"`
+	// Dir holds the task's output and files.
+	Dir string `json:"dir" yaml:"dir"`
+	// Command is the command line, with secret references unresolved.
+	Command    []string  `json:"command" yaml:"command"`
+	Started    time.Time `json:"started" yaml:"started"`
+	DurationMS int64     `json:"duration_ms" yaml:"duration_ms"`
+	ExitCode   int       `json:"exit_code" yaml:"exit_code"`
+	Stdout     string    `json:"stdout" yaml:"stdout"`
+	Stderr     string    `json:"stderr" yaml:"stderr"`
+	Files      []File    `json:"files,omitempty" yaml:"files,omitempty"`
+	// Missing lists the artifacts patterns that matched no file.
+	Missing []string `json:"missing,omitempty" yaml:"missing,omitempty"`
+	// Error says why files could not be saved, or the task not started.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// File is a file a task declared as an artifact, copied into the
+// artifacts directory.
+type File struct {
+	Path string `json:"path" yaml:"path"`
+	// Source is the path of the file relative to the task's working
+	// directory.
+	Source    string `json:"source" yaml:"source"`
+	SizeBytes int64  `json:"size_bytes" yaml:"size_bytes"`
+	SHA256    string `json:"sha256" yaml:"sha256"`
+}
+
+// ReadManifest reads the manifest of the artifacts directory dir. A
+// directory without one has no tasks.
+func ReadManifest(dir string) (*Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return &Manifest{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", ManifestName, err)
+	}
+	return &m, nil
+}
+
+// recording captures the output of a task run into the artifacts
+// directory run.
+type recording struct {
+	run            string
+	record         TaskArtifacts
+	stdout, stderr *os.File
+}
+
+// unsafeName matches the characters of a task name left out of its
+// directory name.
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// startRecording creates the directory of task name in the artifacts
+// directory run, named after the task, and its output files.
+func startRecording(run, name string, argv []string) (*recording, error) {
+	if err := os.MkdirAll(run, 0o700); err != nil {
+		return nil, fmt.Errorf("create artifacts directory: %w", err)
+	}
+	base := unsafeName.ReplaceAllString(name, "_")
+	if base == "" || base == "." || base == ".." {
+		base = "task"
+	}
+	// A task that runs more than once in a run gets a directory per run.
+	dir := base
+	for i := 2; ; i++ {
+		err := os.Mkdir(filepath.Join(run, dir), 0o700)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("create artifacts directory: %w", err)
+		}
+		dir = base + "-" + strconv.Itoa(i)
+	}
+
+	rec := &recording{run: run, record: TaskArtifacts{
+		Task:    name,
+		Dir:     dir,
+		Command: argv,
+		Started: time.Now(),
+		Stdout:  dir + "/stdout.log",
+		Stderr:  dir + "/stderr.log",
+	}}
+	var err error
+	if rec.stdout, err = os.OpenFile(filepath.Join(run, dir, "stdout.log"), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600); err != nil {
+		return nil, fmt.Errorf("create artifacts: %w", err)
+	}
+	if rec.stderr, err = os.OpenFile(filepath.Join(run, dir, "stderr.log"), os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600); err != nil {
+		rec.stdout.Close()
+		return nil, fmt.Errorf("create artifacts: %w", err)
+	}
+	return rec, nil
+}
+
+// finish closes the output files, copies the files in workDir matching
+// patterns, and adds the task to the manifest, given the error the task
+// ended with.
+func (rec *recording) finish(patterns []string, workDir string, runErr error) error {
+	rec.record.DurationMS = time.Since(rec.record.Started).Milliseconds()
+	rec.record.ExitCode = exitStatus(runErr)
+	errs := []error{rec.stdout.Close(), rec.stderr.Close()}
+
+	var exitErr *ExitError
+	if runErr != nil && !errors.As(runErr, &exitErr) {
+		rec.record.Error = runErr.Error()
+	} else if len(patterns) > 0 {
+		if err := rec.copyFiles(patterns, workDir); err != nil {
+			rec.record.Error = err.Error()
+			errs = append(errs, err)
+		}
+	}
+	errs = append(errs, rec.addToManifest())
+	return errors.Join(errs...)
+}
+
+// copyFiles copies the files below workDir matching patterns, which use
+// the syntax of watch.Matcher, into the task's directory, keeping their
+// relative paths. The artifacts directory itself is skipped when it lies
+// below workDir.
+func (rec *recording) copyFiles(patterns []string, workDir string) error {
+	if workDir == "" {
+		var err error
+		if workDir, err = os.Getwd(); err != nil {
+			return err
+		}
+	}
+	run, _ := filepath.Abs(rec.run)
+	matched := make([]bool, len(patterns))
+	err := filepath.WalkDir(workDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(workDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		if d.IsDir() {
+			if abs, _ := filepath.Abs(path); abs == run || (watch.Matcher{}).Excluded(rel) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		selected := false
+		for i, pattern := range patterns {
+			if (watch.Matcher{Include: []string{pattern}, NoDefaultExcludes: true}).Match(rel) {
+				matched[i], selected = true, true
+			}
+		}
+		if !selected {
+			return nil
+		}
+		file, err := rec.copyFile(path, rel)
+		if err != nil {
+			return err
+		}
+		rec.record.Files = append(rec.record.Files, file)
+		return nil
+	})
+	for i, pattern := range patterns {
+		if !matched[i] {
+			rec.record.Missing = append(rec.record.Missing, pattern)
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("save artifacts: %w", err)
+	}
+	return nil
+}
+
+// copyFile copies the file at path, rel below the working directory, into
+// the files directory of the task.
+func (rec *recording) copyFile(path, rel string) (File, error) {
+	target := filepath.Join(rec.run, rec.record.Dir, "files", rel)
+	if err := os.MkdirAll(filepath.Dir(target), 0o700); err != nil {
+		return File{}, err
+	}
+	src, err := os.Open(path)
+	if err != nil {
+		return File{}, err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return File{}, err
+	}
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(dst, h), src)
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return File{}, err
+	}
+	return File{
+		Path:      rec.record.Dir + "/files/" + filepath.ToSlash(rel),
+		Source:    filepath.ToSlash(rel),
+		SizeBytes: n,
+		SHA256:    hex.EncodeToString(h.Sum(nil)),
+	}, nil
+}
+
+// addToManifest appends the task to the manifest of the run. Tasks of a
+// run finish one at a time, so the manifest is never written concurrently.
+func (rec *recording) addToManifest() error {
+	m, err := ReadManifest(rec.run)
+	if err != nil {
+		return err
+	}
+	m.Tasks = append(m.Tasks, rec.record)
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(rec.run, ManifestName)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("write %s: %w", ManifestName, err)
+	}
+	return os.Rename(tmp, path)
+}
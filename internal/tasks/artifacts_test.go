@@ -0,0 +1,100 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anowarislam/ado/internal/config"
+)
+
+func TestRunner_RunArtifacts(t *testing.T) {
+	requireSh(t)
+
+	work := t.TempDir()
+	run := filepath.Join(work, "artifacts", "run")
+	var stdout, stderr bytes.Buffer
+	r := Runner{
+		Stdout:       &stdout,
+		Stderr:       &stderr,
+		BaseDir:      work,
+		Environ:      func() []string { return []string{"PATH=" + os.Getenv("PATH")} },
+		ArtifactsDir: run,
+	}
+	task := config.Task{
+		Command:   "sh",
+		Args:      []string{"-c", `mkdir -p out/sub && echo "$1" > out/sub/report.xml && echo done && echo oops >&2 && echo extra > "$ADO_ARTIFACTS_DIR/extra.txt"; exit $2`, "sh"},
+		Cwd:       ".",
+		Artifacts: []string{"out/**/*.xml", "*.missing"},
+	}
+
+	if err := r.Run(context.Background(), "test:unit", task, []string{"first", "0"}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	err := r.Run(context.Background(), "test:unit", task, []string{"second", "3"})
+	var exitErr *ExitError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("Run() error = %v, want exit code 3", err)
+	}
+
+	// Output is still streamed.
+	if stdout.String() != "done\ndone\n" {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+	if !strings.Contains(stderr.String(), `warning: task "test:unit": no artifacts match "*.missing"`) {
+		t.Errorf("stderr = %q, want a warning about *.missing", stderr.String())
+	}
+
+	m, err := ReadManifest(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Tasks) != 2 {
+		t.Fatalf("manifest has %d tasks, want 2", len(m.Tasks))
+	}
+	for i, want := range []struct {
+		dir, report string
+		code        int
+	}{{"test_unit", "first\n", 0}, {"test_unit-2", "second\n", 3}} {
+		got := m.Tasks[i]
+		if got.Task != "test:unit" || got.Dir != want.dir || got.ExitCode != want.code || got.Error != "" {
+			t.Errorf("task %d = %+v, want dir %s and exit code %d", i, got, want.dir, want.code)
+		}
+		if !reflect.DeepEqual(got.Missing, []string{"*.missing"}) {
+			t.Errorf("task %d: Missing = %q", i, got.Missing)
+		}
+		if len(got.Files) != 1 || got.Files[0].Source != "out/sub/report.xml" || got.Files[0].Path != want.dir+"/files/out/sub/report.xml" || got.Files[0].SizeBytes != int64(len(want.report)) {
+			t.Fatalf("task %d: Files = %+v", i, got.Files)
+		}
+		for path, content := range map[string]string{got.Stdout: "done\n", got.Stderr: "oops\n", got.Files[0].Path: want.report} {
+			data, err := os.ReadFile(filepath.Join(run, filepath.FromSlash(path)))
+			if err != nil || string(data) != content {
+				t.Errorf("%s = %q, %v; want %q", path, data, err, content)
+			}
+		}
+	}
+	if data, err := os.ReadFile(filepath.Join(run, "extra.txt")); err != nil || string(data) != "extra\n" {
+		t.Errorf("extra.txt = %q, %v", data, err)
+	}
+}
+
+func TestRunner_RunArtifactsNotStarted(t *testing.T) {
+	run := t.TempDir()
+	r := Runner{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}, ArtifactsDir: run}
+	err := r.Run(context.Background(), "t", config.Task{Command: "ado-test-missing-command", Artifacts: []string{"*"}}, nil)
+	if err == nil {
+		t.Fatal("Run() succeeded")
+	}
+	m, err := ReadManifest(run)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Tasks) != 1 || m.Tasks[0].ExitCode != 1 || !strings.Contains(m.Tasks[0].Error, "ado-test-missing-command") || len(m.Tasks[0].Files) != 0 {
+		t.Errorf("manifest = %+v", m.Tasks)
+	}
+}
@@ -0,0 +1,200 @@
+package tasks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/process"
+)
+
+// Kinds of environment change in a plan.
+const (
+	// EnvAdd sets a variable the inherited environment does not have.
+	EnvAdd = "add"
+	// EnvOverride replaces the inherited value of a variable.
+	EnvOverride = "override"
+	// EnvRemove leaves out an inherited variable, under the task's
+	// env_policy.
+	EnvRemove = "remove"
+)
+
+// Step is a task in a plan: what Run would execute, where, and with
+// which environment changes, and the tasks it runs in turn with ado run.
+type Step struct {
+	// Order is when the task starts, counting from 1. A task started by
+	// another runs while that one waits for it, so tasks never overlap.
+	Order   int      `json:"order" yaml:"order"`
+	Task    string   `json:"task" yaml:"task"`
+	Command string   `json:"command,omitempty" yaml:"command,omitempty"`
+	Args    []string `json:"args,omitempty" yaml:"args,omitempty"`
+	// Dir is the working directory, resolved like Run does.
+	Dir string `json:"dir,omitempty" yaml:"dir,omitempty"`
+	// EnvPolicy is the task's env_policy, when it is not inherit.
+	EnvPolicy string      `json:"env_policy,omitempty" yaml:"env_policy,omitempty"`
+	Env       []EnvChange `json:"env,omitempty" yaml:"env,omitempty"`
+	// Artifacts are the task's artifacts globs.
+	Artifacts []string `json:"artifacts,omitempty" yaml:"artifacts,omitempty"`
+	// Runs lists the tasks this one runs with ado run, in order.
+	Runs []Step `json:"runs,omitempty" yaml:"runs,omitempty"`
+	// Error says why a task this one runs cannot be planned: it is not
+	// defined, or runs itself.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Valid reports whether every task in the plan is defined and none runs
+// itself.
+func (s Step) Valid() bool {
+	if s.Error != "" {
+		return false
+	}
+	for _, run := range s.Runs {
+		if !run.Valid() {
+			return false
+		}
+	}
+	return true
+}
+
+// EnvChange is a variable a task sets in the environment it inherits, or
+// leaves out of it.
+type EnvChange struct {
+	Name string `json:"name" yaml:"name"`
+	// Value is as configured, with secret:// references unresolved. It is
+	// empty for EnvRemove.
+	Value string `json:"value,omitempty" yaml:"value,omitempty"`
+	// Change is EnvAdd, EnvOverride, or EnvRemove.
+	Change string `json:"change" yaml:"change"`
+}
+
+// Plan returns what running the task name of defined with extraArgs
+// would do, without running anything: its command line, working
+// directory, and environment changes, and the same for every task it
+// runs with ado run, recursively. Secret references are left unresolved.
+func (r Runner) Plan(defined map[string]config.Task, name string, extraArgs []string) (Step, error) {
+	task, ok := defined[name]
+	if !ok {
+		return Step{}, fmt.Errorf("unknown task %q", name)
+	}
+	environ := os.Environ
+	if r.Environ != nil {
+		environ = r.Environ
+	}
+	dir, _ := os.Getwd()
+	p := &planner{runner: r, defined: defined}
+	return p.step(name, task, extraArgs, environ(), dir, nil), nil
+}
+
+// planner builds the steps of a plan.
+type planner struct {
+	runner  Runner
+	defined map[string]config.Task
+	// started counts the steps so far, for Step.Order.
+	started int
+}
+
+// step plans task, run with extraArgs in env and dir, the environment
+// and working directory of the process starting it. stack lists the
+// tasks running it, to detect cycles.
+func (p *planner) step(name string, task config.Task, extraArgs, env []string, dir string, stack []string) Step {
+	p.started++
+	argv, taskDir := p.runner.Describe(task, extraArgs)
+	if taskDir != "" {
+		dir = taskDir
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+	}
+	kept := inherited(env, task)
+	s := Step{Order: p.started, Task: name, Command: argv[0], Args: argv[1:], Dir: dir, Env: envChanges(kept, task.Env), Artifacts: task.Artifacts}
+	if task.EnvPolicy != "" && task.EnvPolicy != config.EnvInherit {
+		s.EnvPolicy = task.EnvPolicy
+		s.Env = append(s.Env, envRemoved(env, kept)...)
+	}
+
+	child, childArgs, ok := runsTask(task.Command, argv[1:])
+	if !ok {
+		return s
+	}
+	stack = append(stack, name)
+	childTask, defined := p.defined[child]
+	switch {
+	case !defined:
+		s.Runs = append(s.Runs, Step{Order: p.started + 1, Task: child, Error: "not defined"})
+		p.started++
+	case slices.Contains(stack, child):
+		s.Runs = append(s.Runs, Step{Order: p.started + 1, Task: child, Error: "runs itself: " + strings.Join(append(stack, child), " -> ")})
+		p.started++
+	default:
+		s.Runs = append(s.Runs, p.step(child, childTask, childArgs, process.MergeEnv(kept, task.Env), dir, stack))
+	}
+	return s
+}
+
+// runsTask returns the task an ado command runs with args, as in ado run
+// NAME or ado run -- NAME, and the arguments passed to it.
+func runsTask(command string, args []string) (string, []string, bool) {
+	if name := filepath.Base(command); name != "ado" && name != "ado.exe" {
+		return "", nil, false
+	}
+	if len(args) < 2 || args[0] != "run" {
+		return "", nil, false
+	}
+	rest := args[1:]
+	if rest[0] == "--" {
+		rest = rest[1:]
+	}
+	if len(rest) == 0 || strings.HasPrefix(rest[0], "-") {
+		return "", nil, false
+	}
+	extra := rest[1:]
+	if len(extra) > 0 && extra[0] == "--" {
+		extra = extra[1:]
+	}
+	return rest[0], extra, true
+}
+
+// envChanges lists the variables of overrides that add to or change env,
+// sorted by name.
+func envChanges(env []string, overrides map[string]string) []EnvChange {
+	inherited := make(map[string]string, len(env))
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		inherited[process.EnvKey(key)] = value
+	}
+	var changes []EnvChange
+	for _, key := range sortedKeys(overrides) {
+		value, ok := inherited[process.EnvKey(key)]
+		switch {
+		case !ok:
+			changes = append(changes, EnvChange{Name: key, Value: overrides[key], Change: EnvAdd})
+		case value != overrides[key]:
+			changes = append(changes, EnvChange{Name: key, Value: overrides[key], Change: EnvOverride})
+		}
+	}
+	return changes
+}
+
+// envRemoved lists the variables of env left out of kept, sorted by name.
+func envRemoved(env, kept []string) []EnvChange {
+	names := make(map[string]bool, len(kept))
+	for _, kv := range kept {
+		key, _, _ := strings.Cut(kv, "=")
+		names[key] = true
+	}
+	var removed []string
+	for _, kv := range env {
+		if key, _, _ := strings.Cut(kv, "="); !names[key] {
+			removed = append(removed, key)
+		}
+	}
+	slices.Sort(removed)
+	changes := make([]EnvChange, 0, len(removed))
+	for _, key := range slices.Compact(removed) {
+		changes = append(changes, EnvChange{Name: key, Change: EnvRemove})
+	}
+	return changes
+}
@@ -0,0 +1,125 @@
+package tasks
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/anowarislam/ado/internal/config"
+)
+
+func TestRunner_Plan(t *testing.T) {
+	base := t.TempDir()
+	defined := map[string]config.Task{
+		"release": {Command: "ado", Args: []string{"run", "build", "--", "-v"}, Env: map[string]string{"VERSION": "1.2", "HOME": "/root"}},
+		"build":   {Command: "/usr/bin/ado", Args: []string{"run", "--", "compile"}, Cwd: "web", Env: map[string]string{"VERSION": "1.2", "TOKEN": "secret://token"}},
+		"compile": {Command: "go", Args: []string{"build", "./..."}, Artifacts: []string{"bin/*"}},
+	}
+	r := Runner{BaseDir: base, Environ: func() []string { return []string{"HOME=/home/me", "PATH=/bin"} }}
+
+	got, err := r.Plan(defined, "release", []string{"--fast"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	web := filepath.Join(base, "web")
+	compile := Step{Order: 3, Task: "compile", Command: "go", Args: []string{"build", "./...", "-v", "--fast"}, Dir: web, Artifacts: []string{"bin/*"}}
+	build := Step{
+		Order: 2, Task: "build", Command: "/usr/bin/ado", Args: []string{"run", "--", "compile", "-v", "--fast"}, Dir: web,
+		Env:  []EnvChange{{Name: "TOKEN", Value: "secret://token", Change: EnvAdd}},
+		Runs: []Step{compile},
+	}
+	if !reflect.DeepEqual(got.Runs, []Step{build}) {
+		t.Errorf("Runs = %+v, want %+v", got.Runs, []Step{build})
+	}
+	wantEnv := []EnvChange{{Name: "HOME", Value: "/root", Change: EnvOverride}, {Name: "VERSION", Value: "1.2", Change: EnvAdd}}
+	if got.Order != 1 || got.Dir == "" || !reflect.DeepEqual(got.Env, wantEnv) {
+		t.Errorf("Plan() = %+v, want order 1, a working directory, and env %+v", got, wantEnv)
+	}
+	if !got.Valid() {
+		t.Error("Valid() = false")
+	}
+
+	if _, err := r.Plan(defined, "missing", nil); err == nil {
+		t.Error("Plan() of an undefined task succeeded")
+	}
+}
+
+func TestRunner_PlanEnvPolicy(t *testing.T) {
+	defined := map[string]config.Task{
+		"deploy": {Command: "ado", Args: []string{"run", "push"}, EnvPolicy: config.EnvAllowlist, EnvAllow: []string{"AWS_*"}, Env: map[string]string{"TOKEN": "secret://deploy"}},
+		"push":   {Command: "./push"},
+	}
+	r := Runner{Environ: func() []string {
+		return []string{"PATH=/bin", "AWS_REGION=eu-west-1", "GITHUB_TOKEN=x", "NPM_TOKEN=y"}
+	}}
+	got, err := r.Plan(defined, "deploy", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []EnvChange{
+		{Name: "TOKEN", Value: "secret://deploy", Change: EnvAdd},
+		{Name: "GITHUB_TOKEN", Change: EnvRemove},
+		{Name: "NPM_TOKEN", Change: EnvRemove},
+	}
+	if got.EnvPolicy != config.EnvAllowlist || !reflect.DeepEqual(got.Env, want) {
+		t.Errorf("Plan() = policy %q, env %+v; want %q, %+v", got.EnvPolicy, got.Env, config.EnvAllowlist, want)
+	}
+	// A task run by another inherits what that one passes on.
+	if push := got.Runs[0]; push.EnvPolicy != "" || len(push.Env) != 0 {
+		t.Errorf("push = %+v, want no env changes", push)
+	}
+}
+
+func TestRunner_PlanInvalid(t *testing.T) {
+	defined := map[string]config.Task{
+		"a":   {Command: "ado", Args: []string{"run", "b"}},
+		"b":   {Command: "ado", Args: []string{"run", "a"}},
+		"bad": {Command: "ado", Args: []string{"run", "nope"}},
+	}
+	tests := []struct {
+		task, want string
+	}{
+		{"a", "runs itself: a -> b -> a"},
+		{"bad", "not defined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.task, func(t *testing.T) {
+			step, err := Runner{}.Plan(defined, tt.task, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if step.Valid() {
+				t.Error("Valid() = true")
+			}
+			for len(step.Runs) > 0 {
+				step = step.Runs[0]
+			}
+			if step.Error != tt.want {
+				t.Errorf("Error = %q, want %q", step.Error, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunsTask(t *testing.T) {
+	tests := []struct {
+		command string
+		args    []string
+		want    string
+		extra   []string
+	}{
+		{"ado", []string{"run", "build"}, "build", []string{}},
+		{"ado", []string{"run", "--", "lint", "--", "-v"}, "lint", []string{"-v"}},
+		{"/usr/local/bin/ado", []string{"run", "test", "-run", "X"}, "test", []string{"-run", "X"}},
+		{"ado", []string{"run"}, "", nil},
+		{"ado", []string{"run", "--output", "json"}, "", nil},
+		{"ado", []string{"config", "show"}, "", nil},
+		{"make", []string{"run", "build"}, "", nil},
+	}
+	for _, tt := range tests {
+		name, extra, ok := runsTask(tt.command, tt.args)
+		if name != tt.want || ok != (tt.want != "") || (ok && !reflect.DeepEqual(extra, tt.extra)) {
+			t.Errorf("runsTask(%q, %q) = %q, %q, %v", tt.command, tt.args, name, extra, ok)
+		}
+	}
+}
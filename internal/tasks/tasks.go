@@ -0,0 +1,275 @@
+// Package tasks runs the named commands defined under `tasks:` in the
+// ado config file.
+package tasks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/metrics"
+	"github.com/anowarislam/ado/internal/process"
+	"github.com/anowarislam/ado/internal/secrets"
+)
+
+// Summary describes a task for listing.
+type Summary struct {
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Command     string   `json:"command" yaml:"command"`
+	Args        []string `json:"args,omitempty" yaml:"args,omitempty"`
+}
+
+// List returns the tasks sorted by name.
+func List(tasks map[string]config.Task) []Summary {
+	summaries := make([]Summary, 0, len(tasks))
+	for name, task := range tasks {
+		summaries = append(summaries, Summary{
+			Name:        name,
+			Description: task.Description,
+			Command:     task.Command,
+			Args:        task.Args,
+		})
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].Name < summaries[j].Name })
+	return summaries
+}
+
+// ExitError reports that a task ran but exited with a non-zero status.
+type ExitError struct {
+	Task string
+	Code int
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("task %q exited with status %d", e.Task, e.Code)
+}
+
+// ExitCode is the status ado should exit with.
+func (e *ExitError) ExitCode() int {
+	return e.Code
+}
+
+// Runner executes tasks, streaming output as it is produced.
+type Runner struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+	// BaseDir resolves relative task working directories; normally the
+	// directory containing the config file.
+	BaseDir string
+	// Environ returns the environment tasks inherit; defaults to os.Environ.
+	Environ func() []string
+	// Secrets resolves secret://NAME references in task args and env
+	// values; references are left as-is when nil.
+	Secrets secrets.Store
+	// ArtifactsDir, when set, is the artifacts directory of the run: the
+	// output of each task, and the files matching its artifacts globs, are
+	// saved in a directory of its own there and listed in the manifest.
+	ArtifactsDir string
+}
+
+// Run executes task with extraArgs appended to its configured args. A
+// task that exits non-zero yields an *ExitError carrying its status. The
+// run is reported to the metrics client in ctx, if any.
+func (r Runner) Run(ctx context.Context, name string, task config.Task, extraArgs []string) error {
+	start := time.Now()
+	err := r.run(ctx, name, task, extraArgs)
+	metrics.FromContext(ctx).Run(metrics.Task, time.Since(start), exitStatus(err), metrics.Tag("task", name))
+	return err
+}
+
+// exitStatus returns the exit status a task run ending in err counts as.
+func exitStatus(err error) int {
+	var exitErr *ExitError
+	switch {
+	case err == nil:
+		return 0
+	case errors.As(err, &exitErr):
+		return exitErr.Code
+	default:
+		return 1
+	}
+}
+
+func (r Runner) run(ctx context.Context, name string, task config.Task, extraArgs []string) error {
+	if task.Command == "" {
+		return fmt.Errorf("task %q has no command", name)
+	}
+
+	args := append(append([]string{}, task.Args...), extraArgs...)
+	env := task.Env
+	if r.Secrets != nil {
+		var err error
+		if args, env, err = r.resolveSecrets(args, env); err != nil {
+			return fmt.Errorf("task %q: %w", name, err)
+		}
+	}
+
+	cmd := exec.CommandContext(ctx, task.Command, args...)
+	cmd.Stdin = r.Stdin
+	cmd.Stdout = r.Stdout
+	cmd.Stderr = r.Stderr
+	cmd.Dir = r.workDir(task.Cwd)
+	process.Graceful(cmd, process.GracePeriod)
+	if r.ArtifactsDir == "" {
+		cmd.Env = r.environ(task, env)
+		return execute(cmd, name)
+	}
+
+	argv, _ := r.Describe(task, extraArgs)
+	rec, err := startRecording(r.ArtifactsDir, name, argv)
+	if err != nil {
+		return fmt.Errorf("task %q: %w", name, err)
+	}
+	cmd.Stdout = teeTo(r.Stdout, rec.stdout)
+	cmd.Stderr = teeTo(r.Stderr, rec.stderr)
+	withDir := make(map[string]string, len(env)+1)
+	maps.Copy(withDir, env)
+	withDir[ArtifactsEnv] = r.ArtifactsDir
+	cmd.Env = r.environ(task, withDir)
+
+	err = execute(cmd, name)
+	if saveErr := rec.finish(task.Artifacts, cmd.Dir, err); saveErr != nil {
+		err = errors.Join(err, fmt.Errorf("task %q: %w", name, saveErr))
+	}
+	for _, pattern := range rec.record.Missing {
+		fmt.Fprintf(r.errOut(), "warning: task %q: no artifacts match %q\n", name, pattern)
+	}
+	return err
+}
+
+// execute runs cmd for task name.
+func execute(cmd *exec.Cmd, name string) error {
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &exitErr) && exitErr.ExitCode() > 0:
+		return &ExitError{Task: name, Code: exitErr.ExitCode()}
+	default:
+		return fmt.Errorf("run task %q: %w", name, err)
+	}
+}
+
+// Describe returns the command line Run would execute for task and the
+// directory it would run in, without starting it. Secret references are
+// left unresolved so that previews do not reveal them.
+func (r Runner) Describe(task config.Task, extraArgs []string) (argv []string, dir string) {
+	argv = append([]string{task.Command}, task.Args...)
+	return append(argv, extraArgs...), r.workDir(task.Cwd)
+}
+
+// teeTo returns a writer to out, which may be nil, and file.
+func teeTo(out io.Writer, file *os.File) io.Writer {
+	if out == nil {
+		return file
+	}
+	return io.MultiWriter(out, file)
+}
+
+// errOut returns where warnings go.
+func (r Runner) errOut() io.Writer {
+	if r.Stderr == nil {
+		return io.Discard
+	}
+	return r.Stderr
+}
+
+func (r Runner) workDir(cwd string) string {
+	if cwd == "" || filepath.IsAbs(cwd) || r.BaseDir == "" {
+		return cwd
+	}
+	return filepath.Join(r.BaseDir, cwd)
+}
+
+// resolveSecrets expands secret references in args and env values,
+// returning copies so the task definition is left untouched.
+func (r Runner) resolveSecrets(args []string, env map[string]string) ([]string, map[string]string, error) {
+	if err := secrets.ExpandAll(r.Secrets, args); err != nil {
+		return nil, nil, err
+	}
+	resolved := make(map[string]string, len(env))
+	for key, value := range env {
+		expanded, err := secrets.Expand(r.Secrets, value)
+		if err != nil {
+			return nil, nil, err
+		}
+		resolved[key] = expanded
+	}
+	return args, resolved, nil
+}
+
+// environ overlays the task's variables on the environment it inherits
+// under its env_policy.
+func (r Runner) environ(task config.Task, overrides map[string]string) []string {
+	environ := os.Environ
+	if r.Environ != nil {
+		environ = r.Environ
+	}
+	return process.MergeEnv(inherited(environ(), task), overrides)
+}
+
+// BaseEnv lists the variables, or globs of their names, that tasks with
+// env_policy clean or allowlist inherit: the ones programs commonly need
+// to run, on Unix and on Windows.
+var BaseEnv = []string{
+	"PATH", "HOME", "USER", "LOGNAME", "SHELL", "TMPDIR", "TERM", "TZ", "LANG", "LC_*",
+	"SYSTEMROOT", "SYSTEMDRIVE", "WINDIR", "COMSPEC", "PATHEXT", "TEMP", "TMP",
+	"USERPROFILE", "APPDATA", "LOCALAPPDATA", "PROGRAMDATA",
+}
+
+// inherited returns the variables of env that task inherits under its
+// env_policy.
+func inherited(env []string, task config.Task) []string {
+	var allowed []string
+	switch task.EnvPolicy {
+	case config.EnvClean:
+		allowed = BaseEnv
+	case config.EnvAllowlist:
+		allowed = append(slices.Clip(BaseEnv), task.EnvAllow...)
+	default:
+		return env
+	}
+	kept := make([]string, 0, len(allowed))
+	for _, kv := range env {
+		key, _, _ := strings.Cut(kv, "=")
+		if envAllowed(key, allowed) {
+			kept = append(kept, kv)
+		}
+	}
+	return kept
+}
+
+// envAllowed reports whether the variable key matches one of patterns.
+// Names are case-insensitive on Windows, as its environment is.
+func envAllowed(key string, patterns []string) bool {
+	key = process.EnvKey(key)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(process.EnvKey(pattern), key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
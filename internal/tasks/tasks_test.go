@@ -0,0 +1,193 @@
+package tasks
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/metrics"
+	"github.com/anowarislam/ado/internal/secrets"
+)
+
+func requireSh(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+}
+
+func TestList(t *testing.T) {
+	got := List(map[string]config.Task{
+		"test":  {Command: "go", Args: []string{"test"}, Description: "Run tests"},
+		"build": {Command: "go", Args: []string{"build"}},
+	})
+
+	want := []Summary{
+		{Name: "build", Command: "go", Args: []string{"build"}},
+		{Name: "test", Command: "go", Args: []string{"test"}, Description: "Run tests"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRunner_Run(t *testing.T) {
+	requireSh(t)
+
+	base := t.TempDir()
+	if err := os.Mkdir(filepath.Join(base, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		task       config.Task
+		extra      []string
+		wantStdout string
+		wantCode   int
+	}{
+		{
+			name:       "args and extra args",
+			task:       config.Task{Command: "sh", Args: []string{"-c", `echo "$0 $1"`, "first"}},
+			extra:      []string{"second"},
+			wantStdout: "first second\n",
+		},
+		{
+			name:       "env override",
+			task:       config.Task{Command: "sh", Args: []string{"-c", `echo "$GREETING $KEEP"`}, Env: map[string]string{"GREETING": "hello"}},
+			wantStdout: "hello kept\n",
+		},
+		{
+			name:       "relative cwd",
+			task:       config.Task{Command: "sh", Args: []string{"-c", "basename \"$PWD\""}, Cwd: "sub"},
+			wantStdout: "sub\n",
+		},
+		{
+			name:       "clean env policy",
+			task:       config.Task{Command: "sh", Args: []string{"-c", `echo "${KEEP-unset} $GREETING"`}, Env: map[string]string{"GREETING": "hi"}, EnvPolicy: config.EnvClean},
+			wantStdout: "unset hi\n",
+		},
+		{
+			name:       "allowlist env policy",
+			task:       config.Task{Command: "sh", Args: []string{"-c", `echo "${KEEP-unset} ${GREETING-unset}"`}, EnvPolicy: config.EnvAllowlist, EnvAllow: []string{"KE*"}},
+			wantStdout: "kept unset\n",
+		},
+		{
+			name:     "exit code",
+			task:     config.Task{Command: "sh", Args: []string{"-c", "exit 3"}},
+			wantCode: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout bytes.Buffer
+			r := Runner{
+				Stdout:  &stdout,
+				Stderr:  &bytes.Buffer{},
+				BaseDir: base,
+				Environ: func() []string { return []string{"PATH=" + os.Getenv("PATH"), "GREETING=old", "KEEP=kept"} },
+			}
+
+			err := r.Run(context.Background(), "t", tt.task, tt.extra)
+			if tt.wantCode != 0 {
+				var exitErr *ExitError
+				if !errors.As(err, &exitErr) || exitErr.ExitCode() != tt.wantCode {
+					t.Fatalf("Run() error = %v, want exit code %d", err, tt.wantCode)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if stdout.String() != tt.wantStdout {
+				t.Errorf("stdout = %q, want %q", stdout.String(), tt.wantStdout)
+			}
+		})
+	}
+}
+
+func TestRunner_RunMetrics(t *testing.T) {
+	requireSh(t)
+	var sent metricLines
+	ctx := metrics.WithClient(context.Background(), metrics.NewClient(&sent, config.StatsDConfig{}))
+
+	r := Runner{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+	_ = r.Run(ctx, "ok", config.Task{Command: "sh", Args: []string{"-c", "true"}}, nil)
+	_ = r.Run(ctx, "fail", config.Task{Command: "sh", Args: []string{"-c", "exit 4"}}, nil)
+
+	want := []string{
+		"ado.task.runs:1|c|#task:ok,status:success,exit_code:0",
+		"ado.task.runs:1|c|#task:fail,status:failure,exit_code:4",
+	}
+	var runs []string
+	for _, line := range sent {
+		if strings.HasPrefix(line, "ado.task.runs:") {
+			runs = append(runs, line)
+		}
+	}
+	if !reflect.DeepEqual(runs, want) || len(sent) != 4 {
+		t.Errorf("sent %q, want counters %q and a timing each", sent, want)
+	}
+}
+
+// metricLines records each metric written by a metrics.Client.
+type metricLines []string
+
+func (m *metricLines) Write(b []byte) (int, error) {
+	*m = append(*m, string(b))
+	return len(b), nil
+}
+
+func TestRunner_RunSecrets(t *testing.T) {
+	requireSh(t)
+
+	task := config.Task{
+		Command: "sh",
+		Args:    []string{"-c", `echo "$0 $TOKEN"`, "user=secret://user"},
+		Env:     map[string]string{"TOKEN": "Bearer secret://token"},
+	}
+	var stdout bytes.Buffer
+	r := Runner{
+		Stdout:  &stdout,
+		Stderr:  &bytes.Buffer{},
+		Secrets: secrets.Map{"user": "alice", "token": "s3cr3t"},
+	}
+
+	if err := r.Run(context.Background(), "t", task, nil); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if stdout.String() != "user=alice Bearer s3cr3t\n" {
+		t.Errorf("stdout = %q", stdout.String())
+	}
+	if task.Env["TOKEN"] != "Bearer secret://token" || task.Args[2] != "user=secret://user" {
+		t.Errorf("task definition was modified: %+v", task)
+	}
+
+	r.Secrets = secrets.Map{}
+	err := r.Run(context.Background(), "t", task, nil)
+	if !errors.Is(err, secrets.ErrNotFound) {
+		t.Errorf("Run(missing secret) error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestRunner_RunErrors(t *testing.T) {
+	r := Runner{Stdout: &bytes.Buffer{}, Stderr: &bytes.Buffer{}}
+
+	if err := r.Run(context.Background(), "empty", config.Task{}, nil); err == nil || !strings.Contains(err.Error(), "no command") {
+		t.Errorf("Run(no command) error = %v", err)
+	}
+
+	err := r.Run(context.Background(), "missing", config.Task{Command: "ado-test-no-such-binary"}, nil)
+	var exitErr *ExitError
+	if err == nil || errors.As(err, &exitErr) {
+		t.Errorf("Run(missing binary) error = %v, want start failure", err)
+	}
+}
@@ -0,0 +1,212 @@
+// Package tlsinspect connects to TLS servers and describes the certificate
+// chains they present.
+package tlsinspect
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"math"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPort is used when a target has no port.
+const DefaultPort = "443"
+
+// DefaultTimeout bounds the connection and handshake.
+const DefaultTimeout = 10 * time.Second
+
+// Options controls Inspect.
+type Options struct {
+	// ServerName overrides the SNI name and the name verified against the
+	// leaf certificate; by default it is the target's host.
+	ServerName string
+	// Timeout bounds dialing and the handshake.
+	Timeout time.Duration
+	// Roots verifies the chain; nil uses the system pool.
+	Roots *x509.CertPool
+	// Now is the reference time for days remaining; zero means time.Now.
+	Now time.Time
+}
+
+// Certificate describes one certificate of a chain.
+type Certificate struct {
+	Subject            string    `json:"subject" yaml:"subject"`
+	Issuer             string    `json:"issuer" yaml:"issuer"`
+	SerialNumber       string    `json:"serial_number" yaml:"serial_number"`
+	DNSNames           []string  `json:"dns_names,omitempty" yaml:"dns_names,omitempty"`
+	IPAddresses        []string  `json:"ip_addresses,omitempty" yaml:"ip_addresses,omitempty"`
+	NotBefore          time.Time `json:"not_before" yaml:"not_before"`
+	NotAfter           time.Time `json:"not_after" yaml:"not_after"`
+	DaysRemaining      int       `json:"days_remaining" yaml:"days_remaining"`
+	IsCA               bool      `json:"is_ca" yaml:"is_ca"`
+	SignatureAlgorithm string    `json:"signature_algorithm" yaml:"signature_algorithm"`
+	PublicKeyAlgorithm string    `json:"public_key_algorithm" yaml:"public_key_algorithm"`
+	SHA256Fingerprint  string    `json:"sha256_fingerprint" yaml:"sha256_fingerprint"`
+}
+
+// Result describes the connection to one target.
+type Result struct {
+	Address     string `json:"address" yaml:"address"`
+	ServerName  string `json:"server_name" yaml:"server_name"`
+	TLSVersion  string `json:"tls_version" yaml:"tls_version"`
+	CipherSuite string `json:"cipher_suite" yaml:"cipher_suite"`
+	// Verified reports whether the chain is trusted and valid for
+	// ServerName. The chain is inspected either way.
+	Verified    bool   `json:"verified" yaml:"verified"`
+	VerifyError string `json:"verify_error,omitempty" yaml:"verify_error,omitempty"`
+	// NotAfter and DaysRemaining are those of the certificate in the
+	// chain that expires first.
+	NotAfter      time.Time     `json:"not_after" yaml:"not_after"`
+	DaysRemaining int           `json:"days_remaining" yaml:"days_remaining"`
+	Certificates  []Certificate `json:"certificates" yaml:"certificates"`
+}
+
+// ParseAddress turns HOST, HOST:PORT, or an https:// URL into host:port,
+// defaulting the port to 443.
+func ParseAddress(raw string) (string, error) {
+	target := raw
+	if strings.Contains(target, "://") {
+		u, err := url.Parse(target)
+		if err != nil || u.Host == "" {
+			return "", fmt.Errorf("invalid target %q: expected HOST[:PORT] or https://HOST", raw)
+		}
+		target = u.Host
+	}
+
+	host, port, err := net.SplitHostPort(target)
+	if err != nil {
+		// No port: take the whole target as host, unwrapping "[::1]".
+		host, port = strings.TrimSuffix(strings.TrimPrefix(target, "["), "]"), DefaultPort
+	}
+	if host == "" || strings.ContainsAny(host, "/ ") {
+		return "", fmt.Errorf("invalid target %q: expected HOST[:PORT] or https://HOST", raw)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("invalid target %q: bad port %q", raw, port)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// Inspect performs a TLS handshake with address (host:port) and describes
+// the negotiated connection and the presented chain. Untrusted, expired,
+// and mismatched certificates are still described; the verification
+// outcome is reported in the result rather than as an error.
+func Inspect(ctx context.Context, address string, opts Options) (Result, error) {
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return Result{}, err
+	}
+	serverName := opts.ServerName
+	if serverName == "" {
+		serverName = host
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, opts.Timeout)
+	defer cancel()
+	dialer := &tls.Dialer{Config: &tls.Config{
+		ServerName: serverName,
+		// Verification happens below so that broken chains can still be
+		// inspected.
+		InsecureSkipVerify: true,
+	}}
+	slog.DebugContext(ctx, "Connecting", "address", address, "server_name", serverName)
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return Result{}, fmt.Errorf("connect to %s: %w", address, err)
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	result := Result{
+		Address:     address,
+		ServerName:  serverName,
+		TLSVersion:  tls.VersionName(state.Version),
+		CipherSuite: tls.CipherSuiteName(state.CipherSuite),
+	}
+	if len(state.PeerCertificates) == 0 {
+		return result, fmt.Errorf("%s presented no certificates", address)
+	}
+
+	if err := verify(state.PeerCertificates, serverName, opts.Roots, opts.Now); err != nil {
+		result.VerifyError = err.Error()
+	} else {
+		result.Verified = true
+	}
+	for i, cert := range state.PeerCertificates {
+		c := describe(cert, opts.Now)
+		result.Certificates = append(result.Certificates, c)
+		if i == 0 || c.NotAfter.Before(result.NotAfter) {
+			result.NotAfter, result.DaysRemaining = c.NotAfter, c.DaysRemaining
+		}
+	}
+	return result, nil
+}
+
+// verify checks chain[0] for serverName, using the rest of the chain as
+// intermediates.
+func verify(chain []*x509.Certificate, serverName string, roots *x509.CertPool, now time.Time) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		DNSName:       serverName,
+		Roots:         roots,
+		Intermediates: intermediates,
+		CurrentTime:   now,
+	})
+	return err
+}
+
+func describe(cert *x509.Certificate, now time.Time) Certificate {
+	sum := sha256.Sum256(cert.Raw)
+	c := Certificate{
+		Subject:            cert.Subject.String(),
+		Issuer:             cert.Issuer.String(),
+		SerialNumber:       formatSerial(cert),
+		DNSNames:           cert.DNSNames,
+		NotBefore:          cert.NotBefore.UTC(),
+		NotAfter:           cert.NotAfter.UTC(),
+		DaysRemaining:      DaysRemaining(cert.NotAfter, now),
+		IsCA:               cert.IsCA,
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		PublicKeyAlgorithm: cert.PublicKeyAlgorithm.String(),
+		SHA256Fingerprint:  hex.EncodeToString(sum[:]),
+	}
+	for _, ip := range cert.IPAddresses {
+		c.IPAddresses = append(c.IPAddresses, ip.String())
+	}
+	return c
+}
+
+// DaysRemaining returns the whole days from now until notAfter, rounded
+// down; it is negative once the certificate has expired.
+func DaysRemaining(notAfter, now time.Time) int {
+	return int(math.Floor(notAfter.Sub(now).Hours() / 24))
+}
+
+// formatSerial renders the serial number as colon-separated hex, as
+// openssl does.
+func formatSerial(cert *x509.Certificate) string {
+	raw := cert.SerialNumber.Bytes()
+	parts := make([]string, len(raw))
+	for i, b := range raw {
+		parts[i] = fmt.Sprintf("%02x", b)
+	}
+	return strings.Join(parts, ":")
+}
@@ -0,0 +1,123 @@
+package tlsinspect
+
+import (
+	"context"
+	"crypto/x509"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAddress(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr string
+	}{
+		{"example.com", "example.com:443", ""},
+		{"example.com:8443", "example.com:8443", ""},
+		{"https://example.com/path", "example.com:443", ""},
+		{"https://example.com:9443", "example.com:9443", ""},
+		{"[::1]", "[::1]:443", ""},
+		{"[::1]:8443", "[::1]:8443", ""},
+		{"10.0.0.1", "10.0.0.1:443", ""},
+		{"", "", "invalid target"},
+		{"example.com:0", "", "bad port"},
+		{"example.com:https", "", "bad port"},
+		{"https://", "", "invalid target"},
+	}
+	for _, tt := range tests {
+		got, err := ParseAddress(tt.raw)
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseAddress(%q) error = %v, want %q", tt.raw, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("ParseAddress(%q) = %q, %v, want %q", tt.raw, got, err, tt.want)
+		}
+	}
+}
+
+func TestDaysRemaining(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		notAfter time.Time
+		want     int
+	}{
+		{now.Add(30 * 24 * time.Hour), 30},
+		{now.Add(30*24*time.Hour - time.Minute), 29},
+		{now.Add(time.Hour), 0},
+		{now.Add(-time.Hour), -1},
+	}
+	for _, tt := range tests {
+		if got := DaysRemaining(tt.notAfter, now); got != tt.want {
+			t.Errorf("DaysRemaining(%v) = %d, want %d", tt.notAfter, got, tt.want)
+		}
+	}
+}
+
+func TestInspect(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.NotFoundHandler())
+	// The client hangs up right after the handshake.
+	srv.Config.ErrorLog = log.New(io.Discard, "", 0)
+	srv.StartTLS()
+	defer srv.Close()
+	address := strings.TrimPrefix(srv.URL, "https://")
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+	now := srv.Certificate().NotAfter.Add(-10 * 24 * time.Hour)
+
+	tests := []struct {
+		name         string
+		opts         Options
+		wantVerified bool
+		wantError    string
+	}{
+		{"trusted", Options{ServerName: "example.com", Roots: roots, Now: now}, true, ""},
+		{"unknown authority", Options{ServerName: "example.com", Now: now}, false, "x509"},
+		{"wrong name", Options{ServerName: "other.test", Roots: roots, Now: now}, false, "other.test"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Inspect(context.Background(), address, tt.opts)
+			if err != nil {
+				t.Fatalf("Inspect() error = %v", err)
+			}
+			if got.Verified != tt.wantVerified || !strings.Contains(got.VerifyError, tt.wantError) {
+				t.Errorf("Verified = %v, VerifyError = %q", got.Verified, got.VerifyError)
+			}
+			if got.ServerName != tt.opts.ServerName || got.TLSVersion == "" || got.CipherSuite == "" {
+				t.Errorf("result = %+v", got)
+			}
+			if len(got.Certificates) != 1 {
+				t.Fatalf("certificates = %d, want 1", len(got.Certificates))
+			}
+			cert := got.Certificates[0]
+			if got.DaysRemaining != 10 || cert.DaysRemaining != 10 || !got.NotAfter.Equal(cert.NotAfter) {
+				t.Errorf("days remaining = %d (chain), %d (leaf)", got.DaysRemaining, cert.DaysRemaining)
+			}
+			if !strings.Contains(strings.Join(cert.DNSNames, ","), "example.com") || len(cert.IPAddresses) == 0 {
+				t.Errorf("SANs = %v %v", cert.DNSNames, cert.IPAddresses)
+			}
+			if len(cert.SHA256Fingerprint) != 64 {
+				t.Errorf("fingerprint = %q", cert.SHA256Fingerprint)
+			}
+		})
+	}
+}
+
+func TestInspect_ConnectError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	address := strings.TrimPrefix(srv.URL, "http://")
+	srv.Close()
+
+	if _, err := Inspect(context.Background(), address, Options{Timeout: time.Second}); err == nil || !strings.Contains(err.Error(), "connect to "+address) {
+		t.Errorf("Inspect() error = %v", err)
+	}
+}
@@ -0,0 +1,40 @@
+// Command embeddigest stamps an ado binary with its own SHA-256 digest so
+// that `ado meta verify` can detect accidental damage; tampering is caught
+// by the signed checksums.txt, which lists the stamped binaries. It runs as
+// a GoReleaser post-build hook:
+//
+//	go run ./internal/tools/embeddigest dist/ado_linux_amd64_v1/ado
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/anowarislam/ado/internal/meta"
+)
+
+func main() {
+	if len(os.Args) != 2 {
+		fmt.Fprintln(os.Stderr, "usage: embeddigest BINARY")
+		os.Exit(2)
+	}
+	if err := run(os.Args[1]); err != nil {
+		fmt.Fprintf(os.Stderr, "embeddigest: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if err := meta.StampDigest(data); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return os.WriteFile(path, data, info.Mode().Perm())
+}
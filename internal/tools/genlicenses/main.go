@@ -0,0 +1,168 @@
+// Command genlicenses writes the license inventory embedded into ado.
+//
+// It lists the modules compiled into ./cmd/ado, locates each module's
+// license file in the module cache, and classifies it to an SPDX
+// identifier. Run it via `go generate ./internal/meta` (or
+// `make go.licenses`) whenever dependencies change.
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// licenseEntry mirrors meta.LicenseInfo; it is duplicated so the generator
+// does not import the package whose data it produces.
+type licenseEntry struct {
+	Path        string `json:"path"`
+	Version     string `json:"version"`
+	License     string `json:"license"`
+	LicenseFile string `json:"license_file,omitempty"`
+}
+
+// platforms are the release targets; dependencies differ per GOOS (e.g.
+// WMI bindings on Windows), so the inventory covers their union.
+var platforms = []string{"linux", "darwin", "windows"}
+
+// licenseFileNames are checked in order in each module root.
+var licenseFileNames = []string{"LICENSE", "LICENSE.txt", "LICENSE.md", "LICENCE", "COPYING", "License"}
+
+// declaredLicenses records, after review by hand, the license of modules
+// that ship no license file and only state it in their README.
+var declaredLicenses = map[string]string{
+	"github.com/mattn/go-localereader": "MIT",
+}
+
+func main() {
+	out := flag.String("o", "licenses.json", "output file")
+	pkg := flag.String("pkg", "github.com/anowarislam/ado/cmd/ado", "main package whose dependencies are inventoried")
+	flag.Parse()
+
+	if err := run(*pkg, *out); err != nil {
+		fmt.Fprintf(os.Stderr, "genlicenses: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(pkg, out string) error {
+	modules, err := listModules(pkg)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]licenseEntry, 0, len(modules))
+	for _, mod := range modules {
+		entry := licenseEntry{Path: mod.path, Version: mod.version, License: "Unknown"}
+		if file, text, ok := findLicense(mod.dir); ok {
+			entry.LicenseFile = file
+			entry.License = classify(text)
+		} else if license, ok := declaredLicenses[mod.path]; ok {
+			entry.LicenseFile = "README.md"
+			entry.License = license
+		}
+		entries = append(entries, entry)
+	}
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode inventory: %w", err)
+	}
+	data = append(data, '\n')
+	if err := os.WriteFile(out, data, 0o644); err != nil {
+		return fmt.Errorf("write %s: %w", out, err)
+	}
+	return nil
+}
+
+type module struct {
+	path, version, dir string
+}
+
+// listModules returns the non-main modules providing packages to pkg on
+// any release platform.
+func listModules(pkg string) ([]module, error) {
+	var output []byte
+	for _, goos := range platforms {
+		cmd := exec.Command("go", "list", "-deps", "-f", "{{with .Module}}{{if not .Main}}{{.Path}} {{.Version}} {{.Dir}}{{end}}{{end}}", pkg)
+		cmd.Env = append(os.Environ(), "GOOS="+goos)
+		cmd.Stderr = os.Stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("go list (GOOS=%s): %w", goos, err)
+		}
+		output = append(output, out...)
+	}
+	return parseModules(output), nil
+}
+
+func parseModules(output []byte) []module {
+	seen := map[string]bool{}
+	var modules []module
+
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		fields := strings.SplitN(strings.TrimSpace(scanner.Text()), " ", 3)
+		if len(fields) != 3 || seen[fields[0]] {
+			continue
+		}
+		seen[fields[0]] = true
+		modules = append(modules, module{path: fields[0], version: fields[1], dir: fields[2]})
+	}
+
+	sort.Slice(modules, func(i, j int) bool { return modules[i].path < modules[j].path })
+	return modules
+}
+
+func findLicense(dir string) (string, string, bool) {
+	for _, name := range licenseFileNames {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return name, string(data), true
+		}
+	}
+	return "", "", false
+}
+
+// classify maps license text to an SPDX expression using distinctive
+// phrases from each license. Files that carry several licenses (e.g.
+// gopkg.in/yaml.v3) yield an "AND" expression. It intentionally recognizes
+// only the licenses that appear in ado's dependency tree; anything else is
+// "Unknown" and should be reviewed by hand.
+func classify(text string) string {
+	t := strings.Join(strings.Fields(text), " ")
+
+	var ids []string
+	if strings.Contains(t, "Apache License") && strings.Contains(t, "Version 2.0") {
+		ids = append(ids, "Apache-2.0")
+	}
+	if strings.Contains(t, "Redistribution and use in source and binary forms") {
+		if strings.Contains(t, "Neither the name") || strings.Contains(t, "names of its contributors") {
+			ids = append(ids, "BSD-3-Clause")
+		} else {
+			ids = append(ids, "BSD-2-Clause")
+		}
+	}
+	if strings.Contains(t, "Permission to use, copy, modify, and/or distribute this software") ||
+		strings.Contains(t, "Permission to use, copy, modify, and distribute this software for any purpose with or without fee") {
+		ids = append(ids, "ISC")
+	}
+	if strings.Contains(t, "Permission is hereby granted, free of charge") {
+		ids = append(ids, "MIT")
+	}
+	if strings.Contains(t, "Mozilla Public License Version 2.0") || strings.Contains(t, "Mozilla Public License, version 2.0") {
+		ids = append(ids, "MPL-2.0")
+	}
+
+	if len(ids) == 0 {
+		return "Unknown"
+	}
+	return strings.Join(ids, " AND ")
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"apache", "Apache License\n Version 2.0, January 2004", "Apache-2.0"},
+		{"mit", "MIT License\n\nPermission is hereby granted, free of charge, to any person", "MIT"},
+		{"bsd3", "Redistribution and use in source and binary forms ...\n* Neither the name of Google Inc.", "BSD-3-Clause"},
+		{"bsd2", "Redistribution and use in source and binary forms, with or without modification", "BSD-2-Clause"},
+		{"isc", "Permission to use, copy, modify, and/or distribute this software for any", "ISC"},
+		{"mpl", "Mozilla Public License Version 2.0\n==================", "MPL-2.0"},
+		{"dual", "Permission is hereby granted, free of charge ...\n Apache License\n Version 2.0", "Apache-2.0 AND MIT"},
+		{"unknown", "All rights reserved.", "Unknown"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classify(tt.text); got != tt.want {
+				t.Errorf("classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseModules(t *testing.T) {
+	output := []byte("github.com/spf13/pflag v1.0.6 /mod/pflag\n\ngithub.com/spf13/cobra v1.9.1 /mod/cobra\ngithub.com/spf13/pflag v1.0.6 /mod/pflag\n")
+
+	got := parseModules(output)
+	want := []module{
+		{path: "github.com/spf13/cobra", version: "v1.9.1", dir: "/mod/cobra"},
+		{path: "github.com/spf13/pflag", version: "v1.0.6", dir: "/mod/pflag"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parseModules() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// colorCodes maps color names to ANSI foreground codes.
+var colorCodes = map[string]string{
+	"black":   "30",
+	"red":     "31",
+	"green":   "32",
+	"yellow":  "33",
+	"blue":    "34",
+	"magenta": "35",
+	"cyan":    "36",
+	"white":   "37",
+	"gray":    "90",
+}
+
+// ColorNames lists the accepted color names.
+var ColorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white", "gray"}
+
+// presets are named styles for status lines.
+var presets = map[string]Style{
+	"success": {Color: "green"},
+	"warning": {Color: "yellow"},
+	"error":   {Color: "red", Bold: true},
+	"info":    {Color: "cyan"},
+}
+
+// StyleNames lists the words ParseStyle accepts besides color names.
+var StyleNames = []string{"bold", "dim", "italic", "underline", "success", "warning", "error", "info"}
+
+// Style is a set of ANSI text attributes. The zero Style renders text
+// unchanged.
+type Style struct {
+	Color     string
+	Bold      bool
+	Dim       bool
+	Italic    bool
+	Underline bool
+}
+
+// ParseStyle parses a comma-separated list of attributes (bold, dim,
+// italic, underline), color names, and presets (success, warning, error,
+// info). Later words override the color of earlier ones.
+func ParseStyle(spec string) (Style, error) {
+	var s Style
+	for _, word := range strings.Split(spec, ",") {
+		word = strings.ToLower(strings.TrimSpace(word))
+		switch word {
+		case "":
+		case "bold":
+			s.Bold = true
+		case "dim":
+			s.Dim = true
+		case "italic":
+			s.Italic = true
+		case "underline":
+			s.Underline = true
+		default:
+			if preset, ok := presets[word]; ok {
+				s.Color = preset.Color
+				s.Bold = s.Bold || preset.Bold
+				continue
+			}
+			if _, ok := colorCodes[word]; !ok {
+				return Style{}, fmt.Errorf("unknown style %q (use %s, or a color: %s)", word, strings.Join(StyleNames, ", "), strings.Join(ColorNames, ", "))
+			}
+			s.Color = word
+		}
+	}
+	return s, nil
+}
+
+// ValidateColor reports whether name is a known color.
+func ValidateColor(name string) error {
+	if _, ok := colorCodes[name]; !ok {
+		return fmt.Errorf("unknown color %q (use %s)", name, strings.Join(ColorNames, ", "))
+	}
+	return nil
+}
+
+// codes returns the SGR parameters for s, or "" for the zero Style.
+func (s Style) codes() string {
+	var codes []string
+	if s.Bold {
+		codes = append(codes, "1")
+	}
+	if s.Dim {
+		codes = append(codes, "2")
+	}
+	if s.Italic {
+		codes = append(codes, "3")
+	}
+	if s.Underline {
+		codes = append(codes, "4")
+	}
+	if code, ok := colorCodes[s.Color]; ok {
+		codes = append(codes, code)
+	}
+	return strings.Join(codes, ";")
+}
+
+// Render wraps each non-empty line of text in the style's escape codes, so
+// every line ends with a reset.
+func (s Style) Render(text string) string {
+	codes := s.codes()
+	if codes == "" {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = "\x1b[" + codes + "m" + line + "\x1b[0m"
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ColorEnabled reports whether escape codes should be written to w. A
+// non-empty NO_COLOR disables color and a FORCE_COLOR other than "" or "0"
+// enables it; otherwise w must be a terminal and TERM must not be "dumb".
+func ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if force := os.Getenv("FORCE_COLOR"); force != "" && force != "0" {
+		return true
+	}
+	return IsTerminal(w) && os.Getenv("TERM") != "dumb"
+}
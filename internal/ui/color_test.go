@@ -0,0 +1,92 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestParseStyle(t *testing.T) {
+	tests := []struct {
+		spec string
+		want Style
+	}{
+		{"", Style{}},
+		{"bold", Style{Bold: true}},
+		{"Bold, underline ,red", Style{Color: "red", Bold: true, Underline: true}},
+		{"dim,italic", Style{Dim: true, Italic: true}},
+		{"error", Style{Color: "red", Bold: true}},
+		{"success,blue", Style{Color: "blue"}},
+		{"gray,warning", Style{Color: "yellow"}},
+	}
+	for _, tt := range tests {
+		got, err := ParseStyle(tt.spec)
+		if err != nil {
+			t.Errorf("ParseStyle(%q) error = %v", tt.spec, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseStyle(%q) = %+v, want %+v", tt.spec, got, tt.want)
+		}
+	}
+
+	if _, err := ParseStyle("bold,blink"); err == nil || !strings.Contains(err.Error(), `unknown style "blink"`) {
+		t.Errorf("ParseStyle(blink) error = %v", err)
+	}
+}
+
+func TestValidateColor(t *testing.T) {
+	for _, name := range ColorNames {
+		if err := ValidateColor(name); err != nil {
+			t.Errorf("ValidateColor(%q) = %v", name, err)
+		}
+	}
+	if err := ValidateColor("purple"); err == nil {
+		t.Error("ValidateColor(purple) = nil, want error")
+	}
+}
+
+func TestStyle_Render(t *testing.T) {
+	tests := []struct {
+		style Style
+		text  string
+		want  string
+	}{
+		{Style{}, "plain", "plain"},
+		{Style{Bold: true}, "hi", "\x1b[1mhi\x1b[0m"},
+		{Style{Color: "green", Bold: true, Underline: true}, "ok", "\x1b[1;4;32mok\x1b[0m"},
+		{Style{Color: "gray", Dim: true, Italic: true}, "x", "\x1b[2;3;90mx\x1b[0m"},
+		{Style{Color: "red"}, "a\n\nb", "\x1b[31ma\x1b[0m\n\n\x1b[31mb\x1b[0m"},
+	}
+	for _, tt := range tests {
+		if got := tt.style.Render(tt.text); got != tt.want {
+			t.Errorf("%+v.Render(%q) = %q, want %q", tt.style, tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	var buf bytes.Buffer
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("FORCE_COLOR", "")
+	if ColorEnabled(&buf) {
+		t.Error("ColorEnabled(buffer) = true, want false")
+	}
+
+	t.Setenv("FORCE_COLOR", "1")
+	if !ColorEnabled(&buf) {
+		t.Error("ColorEnabled() with FORCE_COLOR = false, want true")
+	}
+
+	t.Setenv("FORCE_COLOR", "0")
+	if ColorEnabled(&buf) {
+		t.Error("ColorEnabled() with FORCE_COLOR=0 = true, want false")
+	}
+
+	t.Setenv("FORCE_COLOR", "1")
+	t.Setenv("NO_COLOR", "1")
+	if ColorEnabled(&buf) {
+		t.Error("ColorEnabled() with NO_COLOR = true, want false")
+	}
+}
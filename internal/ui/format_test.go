@@ -0,0 +1,26 @@
+package ui
+
+import "testing"
+
+func TestFormatSize(t *testing.T) {
+	tests := map[int64]string{
+		0:             "0 B",
+		1023:          "1023 B",
+		1536:          "1.5 KiB",
+		5 << 30:       "5.0 GiB",
+		(1 << 40) + 1: "1.0 TiB",
+	}
+	for n, want := range tests {
+		if got := FormatSize(n); got != want {
+			t.Errorf("FormatSize(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestPlural(t *testing.T) {
+	for n, want := range map[int]string{0: "entries", 1: "entry", 2: "entries"} {
+		if got := Plural(n, "entry", "entries"); got != want {
+			t.Errorf("Plural(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
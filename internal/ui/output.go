@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"slices"
 
 	"gopkg.in/yaml.v3"
 )
@@ -15,9 +16,19 @@ const (
 	OutputText OutputFormat = "text"
 	OutputJSON OutputFormat = "json"
 	OutputYAML OutputFormat = "yaml"
+	// OutputJUnit is a JUnit XML report. Only commands that report checks
+	// accept it, and they write it themselves rather than via PrintOutput.
+	OutputJUnit OutputFormat = "junit"
+	// OutputSARIF is a SARIF 2.1.0 log, written like OutputJUnit.
+	OutputSARIF OutputFormat = "sarif"
+	// OutputTAP is a Test Anything Protocol stream, written like
+	// OutputJUnit.
+	OutputTAP OutputFormat = "tap"
 )
 
-func ParseOutputFormat(raw string) (OutputFormat, error) {
+// ParseOutputFormat parses an --output value. Text, JSON, and YAML are
+// always accepted; extra lists the report formats the command also writes.
+func ParseOutputFormat(raw string, extra ...OutputFormat) (OutputFormat, error) {
 	if raw == "" {
 		return OutputText, nil
 	}
@@ -25,9 +36,11 @@ func ParseOutputFormat(raw string) (OutputFormat, error) {
 	switch OutputFormat(raw) {
 	case OutputText, OutputJSON, OutputYAML:
 		return OutputFormat(raw), nil
-	default:
-		return "", fmt.Errorf("unsupported output format: %s", raw)
 	}
+	if slices.Contains(extra, OutputFormat(raw)) {
+		return OutputFormat(raw), nil
+	}
+	return "", fmt.Errorf("unsupported output format: %s", raw)
 }
 
 func PrintOutput(w io.Writer, format OutputFormat, payload any, renderText func() (string, error)) error {
@@ -45,27 +58,63 @@ func PrintOutput(w io.Writer, format OutputFormat, payload any, renderText func(
 		}
 		_, err = io.WriteString(w, text)
 		return err
+	case OutputJSON, OutputYAML:
+		data, err := Marshal(format, payload)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(data)
+		return err
+	default:
+		return errors.New("unknown output format")
+	}
+}
+
+// Marshal serializes payload as indented JSON or YAML, ending with a
+// newline. It is the encoding PrintOutput uses for structured output.
+func Marshal(format OutputFormat, payload any) ([]byte, error) {
+	switch format {
 	case OutputJSON:
 		data, err := json.MarshalIndent(payload, "", "  ")
 		if err != nil {
-			return fmt.Errorf("serialize json: %w", err)
+			return nil, fmt.Errorf("serialize json: %w", err)
 		}
-		_, err = w.Write(append(data, '\n'))
-		return err
+		return append(data, '\n'), nil
 	case OutputYAML:
 		data, err := yaml.Marshal(payload)
 		if err != nil {
-			return fmt.Errorf("serialize yaml: %w", err)
-		}
-		_, err = w.Write(data)
-		if err != nil {
-			return err
+			return nil, fmt.Errorf("serialize yaml: %w", err)
 		}
 		if len(data) == 0 || data[len(data)-1] != '\n' {
-			_, err = w.Write([]byte("\n"))
+			data = append(data, '\n')
 		}
-		return err
+		return data, nil
 	default:
-		return errors.New("unknown output format")
+		return nil, fmt.Errorf("cannot marshal %s output", format)
 	}
 }
+
+// reportedError marks a failure that a command's output already describes.
+type reportedError struct {
+	err error
+}
+
+func (e *reportedError) Error() string { return e.err.Error() }
+
+func (e *reportedError) Unwrap() error { return e.err }
+
+// Reported marks err as already described by the result the command wrote,
+// such as a report listing failed items, so that no separate error
+// document is written for it in JSON or YAML mode. It returns nil for nil.
+func Reported(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &reportedError{err: err}
+}
+
+// IsReported reports whether err was marked by Reported.
+func IsReported(err error) bool {
+	var reported *reportedError
+	return errors.As(err, &reported)
+}
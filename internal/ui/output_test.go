@@ -3,6 +3,7 @@ package ui
 import (
 	"bytes"
 	"errors"
+	"fmt"
 	"testing"
 )
 
@@ -19,6 +20,7 @@ func TestParseOutputFormat(t *testing.T) {
 		{"yaml format", "yaml", OutputYAML, false},
 		{"invalid format", "xml", "", true},
 		{"invalid format csv", "csv", "", true},
+		{"report format not offered", "junit", "", true},
 	}
 
 	for _, tt := range tests {
@@ -35,6 +37,16 @@ func TestParseOutputFormat(t *testing.T) {
 	}
 }
 
+func TestParseOutputFormat_Extra(t *testing.T) {
+	got, err := ParseOutputFormat("junit", OutputJUnit)
+	if err != nil || got != OutputJUnit {
+		t.Errorf("ParseOutputFormat(junit, junit) = %v, %v", got, err)
+	}
+	if got, err := ParseOutputFormat("json", OutputJUnit); err != nil || got != OutputJSON {
+		t.Errorf("ParseOutputFormat(json, junit) = %v, %v", got, err)
+	}
+}
+
 func TestPrintOutput_Text(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -206,3 +218,89 @@ func TestPrintOutput_YAML_EnsuresTrailingNewline(t *testing.T) {
 		t.Error("YAML output should end with newline")
 	}
 }
+
+func TestMarshal(t *testing.T) {
+	payload := map[string]any{"a": 1}
+	tests := []struct {
+		format  OutputFormat
+		want    string
+		wantErr bool
+	}{
+		{OutputJSON, "{\n  \"a\": 1\n}\n", false},
+		{OutputYAML, "a: 1\n", false},
+		{OutputText, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			got, err := Marshal(tt.format, payload)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Marshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReported(t *testing.T) {
+	if Reported(nil) != nil {
+		t.Error("Reported(nil) != nil")
+	}
+	base := errors.New("2 of 3 items failed")
+	err := Reported(base)
+	if err.Error() != base.Error() || !errors.Is(err, base) {
+		t.Errorf("Reported() = %v, want it to wrap %v", err, base)
+	}
+	if !IsReported(fmt.Errorf("outer: %w", err)) {
+		t.Error("IsReported(wrapped) = false")
+	}
+	if IsReported(base) {
+		t.Error("IsReported(unmarked) = true")
+	}
+}
+
+// benchPayload resembles a mid-sized command result: nested objects and a
+// list of records.
+type benchPayload struct {
+	Name    string            `json:"name" yaml:"name"`
+	Labels  map[string]string `json:"labels" yaml:"labels"`
+	Records []benchRecord     `json:"records" yaml:"records"`
+}
+
+type benchRecord struct {
+	ID      int     `json:"id" yaml:"id"`
+	Path    string  `json:"path" yaml:"path"`
+	Size    uint64  `json:"size" yaml:"size"`
+	Percent float64 `json:"percent" yaml:"percent"`
+	OK      bool    `json:"ok" yaml:"ok"`
+}
+
+func BenchmarkPrintOutput(b *testing.B) {
+	payload := benchPayload{Name: "bench", Labels: map[string]string{"os": "linux", "arch": "amd64"}}
+	for i := range 100 {
+		payload.Records = append(payload.Records, benchRecord{ID: i, Path: fmt.Sprintf("/mnt/disk%d", i), Size: uint64(i) << 30, Percent: float64(i) / 3, OK: i%2 == 0})
+	}
+	renderText := func() (string, error) {
+		var buf bytes.Buffer
+		for _, r := range payload.Records {
+			fmt.Fprintf(&buf, "%d %s %d %.1f%%\n", r.ID, r.Path, r.Size, r.Percent)
+		}
+		return buf.String(), nil
+	}
+
+	for _, format := range []OutputFormat{OutputText, OutputJSON, OutputYAML} {
+		b.Run(string(format), func(b *testing.B) {
+			var buf bytes.Buffer
+			b.ReportAllocs()
+			for b.Loop() {
+				buf.Reset()
+				if err := PrintOutput(&buf, format, payload, renderText); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.SetBytes(int64(buf.Len()))
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// DryRunFlag is the global flag that makes mutating commands print the
+// changes they would make instead of making them.
+const DryRunFlag = "dry-run"
+
+// DryRunAnnotation marks a command that honors --dry-run. The root command
+// refuses --dry-run for commands without it, so a preview never changes
+// anything by accident.
+const DryRunAnnotation = "ado/dry-run"
+
+// Action is one change a command would make.
+type Action struct {
+	// Verb is what would happen, e.g. "write", "delete", "run".
+	Verb string `json:"verb" yaml:"verb"`
+	// Target is what it would happen to: a file, key, or task.
+	Target string `json:"target" yaml:"target"`
+	Detail string `json:"detail,omitempty" yaml:"detail,omitempty"`
+}
+
+// Plan lists the actions a command skipped because of --dry-run.
+type Plan struct {
+	DryRun  bool     `json:"dry_run" yaml:"dry_run"`
+	Actions []Action `json:"actions" yaml:"actions"`
+}
+
+// NewPlan returns an empty dry-run plan.
+func NewPlan() *Plan {
+	return &Plan{DryRun: true, Actions: []Action{}}
+}
+
+// Add records an action. detail may be empty.
+func (p *Plan) Add(verb, target, detail string) {
+	p.Actions = append(p.Actions, Action{Verb: verb, Target: target, Detail: detail})
+}
+
+// Text renders one "Would VERB TARGET: DETAIL" line per action followed by
+// a reminder that nothing was changed.
+func (p *Plan) Text() string {
+	var b strings.Builder
+	if len(p.Actions) == 0 {
+		b.WriteString("Nothing to do.\n")
+	}
+	for _, a := range p.Actions {
+		fmt.Fprintf(&b, "Would %s %s", a.Verb, a.Target)
+		if a.Detail != "" {
+			fmt.Fprintf(&b, ": %s", a.Detail)
+		}
+		b.WriteByte('\n')
+	}
+	b.WriteString("Dry run: nothing was changed.")
+	return b.String()
+}
+
+// PrintPlan writes p to w in format.
+func PrintPlan(w io.Writer, format OutputFormat, p *Plan) error {
+	return PrintOutput(w, format, p, func() (string, error) {
+		return p.Text(), nil
+	})
+}
+
+// QuoteArgs joins args for display, quoting those a shell would split or
+// expand.
+func QuoteArgs(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg == "" || strings.ContainsAny(arg, " \t\n\"'\\$`*?;&|<>()") {
+			arg = strconv.Quote(arg)
+		}
+		quoted[i] = arg
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,47 @@
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPlan_Text(t *testing.T) {
+	plan := NewPlan()
+	if got, want := plan.Text(), "Nothing to do.\nDry run: nothing was changed."; got != want {
+		t.Errorf("empty Text() = %q, want %q", got, want)
+	}
+
+	plan.Add("write", "/tmp/config.yaml", `set alias "x"`)
+	plan.Add("delete", "default/k", "")
+	want := "Would write /tmp/config.yaml: set alias \"x\"\nWould delete default/k\nDry run: nothing was changed."
+	if got := plan.Text(); got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+func TestPrintPlan_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := PrintPlan(&buf, OutputJSON, NewPlan()); err != nil {
+		t.Fatal(err)
+	}
+	if got := buf.String(); !strings.Contains(got, `"dry_run": true`) || !strings.Contains(got, `"actions": []`) {
+		t.Errorf("PrintPlan(json) = %s", got)
+	}
+}
+
+func TestQuoteArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"go", "test", "./..."}, "go test ./..."},
+		{[]string{"sh", "-c", "echo $HOME"}, `sh -c "echo $HOME"`},
+		{[]string{"echo", ""}, `echo ""`},
+	}
+	for _, tt := range tests {
+		if got := QuoteArgs(tt.args); got != tt.want {
+			t.Errorf("QuoteArgs(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
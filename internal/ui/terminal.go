@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"io"
+	"os"
+)
+
+// IsTerminal reports whether v is an *os.File connected to a terminal.
+func IsTerminal(v any) bool {
+	f, ok := v.(*os.File)
+	if !ok {
+		return false
+	}
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
+// IsInteractive reports whether a user can be expected to respond on in:
+// in must be a terminal and the process must not be running under CI.
+func IsInteractive(in io.Reader) bool {
+	if _, ok := os.LookupEnv("CI"); ok {
+		return false
+	}
+	return IsTerminal(in)
+}
@@ -0,0 +1,29 @@
+package ui
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+func TestIsTerminal(t *testing.T) {
+	if IsTerminal(&bytes.Buffer{}) {
+		t.Error("IsTerminal(buffer) = true, want false")
+	}
+
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if IsTerminal(f) {
+		t.Error("IsTerminal(regular file) = true, want false")
+	}
+}
+
+func TestIsInteractive_CI(t *testing.T) {
+	t.Setenv("CI", "true")
+	if IsInteractive(os.Stdin) {
+		t.Error("IsInteractive() = true under CI, want false")
+	}
+}
@@ -0,0 +1,67 @@
+// Package uitest checks command output against golden files, so changes
+// to formatting show up in review as diffs of testdata/*.golden instead of
+// slipping past substring checks.
+//
+// After an intended change, rewrite the golden files of a package with
+//
+//	go test ./cmd/ado/meta -update
+//
+// and review the diff before committing it.
+package uitest
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rogpeppe/go-internal/diff"
+
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files in testdata with the current output")
+
+// Golden compares got with testdata/NAME.golden in the package under
+// test. With -update, it writes got to the file instead.
+func Golden(t testing.TB, name string, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	want, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		t.Fatalf("golden file %s does not exist; run go test with -update to create it", path)
+	case err != nil:
+		t.Fatal(err)
+	case !bytes.Equal(want, []byte(got)):
+		t.Errorf("output differs from %s (run go test with -update to accept it):\n%s",
+			path, diff.Diff(path, want, "got", []byte(got)))
+	}
+}
+
+// Formats renders payload in every output format as ui.PrintOutput does,
+// using renderText for text, and compares each rendering with
+// testdata/NAME.FORMAT.golden.
+func Formats(t testing.TB, name string, payload any, renderText func() (string, error)) {
+	t.Helper()
+	for _, format := range []ui.OutputFormat{ui.OutputText, ui.OutputJSON, ui.OutputYAML} {
+		var buf bytes.Buffer
+		if err := ui.PrintOutput(&buf, format, payload, renderText); err != nil {
+			t.Fatalf("render %s: %v", format, err)
+		}
+		Golden(t, name+"."+string(format), buf.String())
+	}
+}
@@ -0,0 +1,80 @@
+package uitest
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recorder captures failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func TestGolden(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if err := os.Mkdir("testdata", 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join("testdata", "out.golden"), []byte("a\nb\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		file string
+		got  string
+		want string
+	}{
+		{"match", "out", "a\nb\n", ""},
+		{"mismatch shows diff", "out", "a\nc\n", "-b\n+c"},
+		{"missing file", "missing", "a\n", "run go test with -update to create it"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			Golden(r, tt.file, tt.got)
+			failures := strings.Join(r.failures, "\n")
+			if tt.want == "" && failures != "" {
+				t.Errorf("unexpected failure: %s", failures)
+			}
+			if tt.want != "" && !strings.Contains(failures, tt.want) {
+				t.Errorf("failures = %q, want %q", failures, tt.want)
+			}
+		})
+	}
+}
+
+func TestGolden_Update(t *testing.T) {
+	t.Chdir(t.TempDir())
+	*update = true
+	t.Cleanup(func() { *update = false })
+
+	Formats(t, "nested/greeting", map[string]string{"greeting": "hi"}, func() (string, error) {
+		return "hi", nil
+	})
+
+	for name, want := range map[string]string{
+		"nested/greeting.text.golden": "hi\n",
+		"nested/greeting.json.golden": "{\n  \"greeting\": \"hi\"\n}\n",
+		"nested/greeting.yaml.golden": "greeting: hi\n",
+	} {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		if err != nil || string(data) != want {
+			t.Errorf("%s = %q, %v; want %q", name, data, err, want)
+		}
+	}
+}
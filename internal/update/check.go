@@ -0,0 +1,168 @@
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// DefaultCheckInterval is how long a cached update check stays fresh.
+const DefaultCheckInterval = 24 * time.Hour
+
+// RetryDelay is how long after a failed check the next one waits; it
+// doubles with each further failure, up to the check interval.
+const RetryDelay = time.Hour
+
+// CheckState is the cached result of the last update availability check.
+//
+// AttemptedAt and Failures are recorded before the network is queried, so
+// that a check cut short by the process exiting, like one that failed, is
+// not retried by every following command.
+type CheckState struct {
+	CheckedAt     time.Time `json:"checked_at"`
+	Channel       string    `json:"channel"`
+	LatestVersion string    `json:"latest_version"`
+	ReleaseURL    string    `json:"release_url,omitempty"`
+	AttemptedAt   time.Time `json:"attempted_at,omitzero"`
+	Failures      int       `json:"failures,omitempty"`
+}
+
+// DefaultStatePath returns the cache file for update check results.
+func DefaultStatePath() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("locate cache dir: %w", err)
+	}
+	return filepath.Join(cacheDir, "ado", "update-check.json"), nil
+}
+
+// LoadState reads a cached check result.
+func LoadState(path string) (*CheckState, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read update state: %w", err)
+	}
+	var state CheckState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("parse update state: %w", err)
+	}
+	return &state, nil
+}
+
+// SaveState writes a check result, creating the cache directory if needed.
+// The file is replaced atomically, so concurrent commands and a process
+// killed mid-write never leave a partial file behind.
+func SaveState(path string, state *CheckState) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("create cache dir: %w", err)
+	}
+	data, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("encode update state: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".update-check-*.tmp")
+	if err != nil {
+		return fmt.Errorf("write update state: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write update state: %w", err)
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write update state: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write update state: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("write update state: %w", err)
+	}
+	return nil
+}
+
+// due reports whether a check on channel should query the network at now:
+// the last result is stale, and the retry delay after failed or
+// unfinished attempts has passed.
+func (s *CheckState) due(channel string, now time.Time, interval time.Duration) bool {
+	if s.Channel != channel {
+		return true
+	}
+	if s.Failures > 0 {
+		return now.Sub(s.AttemptedAt) >= retryDelay(s.Failures, interval)
+	}
+	return now.Sub(s.CheckedAt) >= interval
+}
+
+// retryDelay is the wait after failures failed attempts in a row.
+func retryDelay(failures int, interval time.Duration) time.Duration {
+	delay := RetryDelay
+	for i := 1; i < failures && delay < interval; i++ {
+		delay *= 2
+	}
+	return min(delay, interval)
+}
+
+// Checker determines the latest release, querying GitHub at most once per
+// Interval per channel and caching the answer at StatePath.
+type Checker struct {
+	Client    *Client
+	StatePath string
+	Interval  time.Duration
+	Now       func() time.Time
+}
+
+// NewChecker returns a checker using the default cache location.
+func NewChecker() (*Checker, error) {
+	path, err := DefaultStatePath()
+	if err != nil {
+		return nil, err
+	}
+	return &Checker{
+		Client:    NewClient(),
+		StatePath: path,
+		Interval:  DefaultCheckInterval,
+		Now:       time.Now,
+	}, nil
+}
+
+// Check returns the latest known release on channel, from cache when fresh
+// or while failed checks back off. The attempt is recorded as a failure
+// before the network is queried, and cleared once it succeeds.
+func (c *Checker) Check(ctx context.Context, channel string) (*CheckState, error) {
+	now := c.Now().UTC()
+	state, err := LoadState(c.StatePath)
+	if err == nil && !state.due(channel, now, c.Interval) {
+		return state, nil
+	}
+	if err != nil || state.Channel != channel {
+		state = &CheckState{Channel: channel}
+	}
+
+	state.AttemptedAt = now
+	state.Failures++
+	if err := SaveState(c.StatePath, state); err != nil {
+		return nil, err
+	}
+
+	release, err := c.Client.Latest(ctx, channel)
+	if err != nil {
+		return nil, err
+	}
+
+	state = &CheckState{
+		CheckedAt:     now,
+		Channel:       channel,
+		LatestVersion: release.Version(),
+		ReleaseURL:    release.HTMLURL,
+	}
+	if err := SaveState(c.StatePath, state); err != nil {
+		return state, err
+	}
+	return state, nil
+}
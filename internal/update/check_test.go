@@ -0,0 +1,147 @@
+package update
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChecker_Check(t *testing.T) {
+	var requests atomic.Int32
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		_, _ = w.Write([]byte(`{"tag_name":"v1.4.0","html_url":"https://example.com/v1.4.0"}`))
+	})
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	checker := &Checker{
+		Client:    client,
+		StatePath: filepath.Join(t.TempDir(), "ado", "update-check.json"),
+		Interval:  DefaultCheckInterval,
+		Now:       func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	state, err := checker.Check(ctx, ChannelStable)
+	if err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if state.LatestVersion != "1.4.0" || state.ReleaseURL != "https://example.com/v1.4.0" {
+		t.Errorf("state = %+v", state)
+	}
+
+	// Fresh cache: no new request.
+	now = now.Add(time.Hour)
+	if _, err := checker.Check(ctx, ChannelStable); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests after cached check = %d, want 1", got)
+	}
+
+	// Different channel bypasses the cache.
+	if _, err := checker.Check(ctx, ChannelPrerelease); err == nil {
+		// The fake server returns an object, not a list, for the prerelease endpoint.
+		t.Error("Check(prerelease) expected decode error")
+	}
+
+	// Stale cache: refreshes.
+	now = now.Add(DefaultCheckInterval)
+	if _, err := checker.Check(ctx, ChannelStable); err != nil {
+		t.Fatalf("Check() error = %v", err)
+	}
+	if got := requests.Load(); got != 3 {
+		t.Errorf("requests after stale check = %d, want 3", got)
+	}
+}
+
+func TestChecker_CheckBackoff(t *testing.T) {
+	var requests atomic.Int32
+	failing := true
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"tag_name":"v1.4.0"}`))
+	})
+
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	checker := &Checker{
+		Client:    client,
+		StatePath: filepath.Join(t.TempDir(), "update-check.json"),
+		Interval:  DefaultCheckInterval,
+		Now:       func() time.Time { return now },
+	}
+	ctx := context.Background()
+
+	// Failures back off 1h, then 2h.
+	for i, advance := range []time.Duration{0, 30 * time.Minute, 30 * time.Minute, time.Hour, time.Hour} {
+		now = now.Add(advance)
+		_, _ = checker.Check(ctx, ChannelStable)
+		if want := []int32{1, 1, 2, 2, 3}[i]; requests.Load() != want {
+			t.Fatalf("check %d: requests = %d, want %d", i, requests.Load(), want)
+		}
+	}
+	if state, err := LoadState(checker.StatePath); err != nil || state.Failures != 3 {
+		t.Fatalf("state = %+v, %v; want 3 failures", state, err)
+	}
+
+	// A success clears the failures.
+	failing = false
+	now = now.Add(4 * time.Hour)
+	state, err := checker.Check(ctx, ChannelStable)
+	if err != nil || state.LatestVersion != "1.4.0" || state.Failures != 0 {
+		t.Fatalf("Check() = %+v, %v", state, err)
+	}
+}
+
+func TestChecker_CheckInterrupted(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("request made with a canceled context")
+	})
+	checker := &Checker{
+		Client:    client,
+		StatePath: filepath.Join(t.TempDir(), "update-check.json"),
+		Interval:  DefaultCheckInterval,
+		Now:       time.Now,
+	}
+	if err := SaveState(checker.StatePath, &CheckState{Channel: ChannelStable, LatestVersion: "1.3.0", CheckedAt: time.Now().Add(-48 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A check cut short, as by the process exiting, counts as attempted.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := checker.Check(ctx, ChannelStable); err == nil {
+		t.Fatal("Check() with a canceled context succeeded")
+	}
+	state, err := checker.Check(context.Background(), ChannelStable)
+	if err != nil || state.Failures != 1 || state.LatestVersion != "1.3.0" {
+		t.Errorf("Check() = %+v, %v; want the cached 1.3.0 while backing off", state, err)
+	}
+}
+
+func TestLoadState_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadState(filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("LoadState() expected error for missing file")
+	}
+
+	path := filepath.Join(dir, "state.json")
+	if err := SaveState(path, &CheckState{LatestVersion: "1.0.0"}); err != nil {
+		t.Fatalf("SaveState() error = %v", err)
+	}
+	state, err := LoadState(path)
+	if err != nil || state.LatestVersion != "1.0.0" {
+		t.Errorf("LoadState() = %+v, %v", state, err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+}
@@ -0,0 +1,213 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrHomebrewManaged is returned when the binary is owned by Homebrew,
+// which must perform the upgrade itself to keep its metadata consistent.
+var ErrHomebrewManaged = errors.New("ado is managed by Homebrew; run `brew upgrade ado` instead")
+
+// blobVerifier verifies a detached signature; CosignVerifier implements it.
+type blobVerifier interface {
+	VerifyBlob(ctx context.Context, blob, signature, certificate []byte) error
+}
+
+// Installer downloads, verifies, and installs a release over an executable.
+type Installer struct {
+	Client   *Client
+	Verifier blobVerifier
+	GOOS     string
+	GOARCH   string
+}
+
+// Install downloads the archive for the installer's platform, verifies it
+// against the release's checksums.txt (and its signature when published),
+// and atomically replaces executable. It returns the signature status.
+func (in Installer) Install(ctx context.Context, release *Release, executable string) (string, error) {
+	archiveName := ArchiveName(release.Version(), in.GOOS, in.GOARCH)
+	archiveAsset, ok := release.Asset(archiveName)
+	if !ok {
+		return "", fmt.Errorf("release %s has no asset %s", release.TagName, archiveName)
+	}
+	checksumsAsset, ok := release.Asset(ChecksumsAsset)
+	if !ok {
+		return "", fmt.Errorf("release %s has no %s; refusing to install unverified binary", release.TagName, ChecksumsAsset)
+	}
+
+	checksums, err := in.Client.Download(ctx, checksumsAsset)
+	if err != nil {
+		return "", err
+	}
+	status, err := in.verifyChecksumsSignature(ctx, release, checksums)
+	if err != nil {
+		return "", err
+	}
+
+	archive, err := in.Client.Download(ctx, archiveAsset)
+	if err != nil {
+		return "", err
+	}
+	if err := VerifyChecksum(archive, archiveName, checksums); err != nil {
+		return "", err
+	}
+
+	binary, err := extractBinary(archive, archiveName, binaryName(in.GOOS))
+	if err != nil {
+		return "", err
+	}
+	if err := ReplaceExecutable(executable, binary); err != nil {
+		return "", err
+	}
+	return status, nil
+}
+
+func (in Installer) verifyChecksumsSignature(ctx context.Context, release *Release, checksums []byte) (string, error) {
+	sigAsset, hasSig := release.Asset(ChecksumsAsset + SignatureSuffix)
+	certAsset, hasCert := release.Asset(ChecksumsAsset + CertificateSuffix)
+	if !hasSig || !hasCert {
+		return SignatureUnsigned, nil
+	}
+
+	signature, err := in.Client.Download(ctx, sigAsset)
+	if err != nil {
+		return "", err
+	}
+	certificate, err := in.Client.Download(ctx, certAsset)
+	if err != nil {
+		return "", err
+	}
+
+	if err := in.Verifier.VerifyBlob(ctx, checksums, signature, certificate); err != nil {
+		if errors.Is(err, ErrCosignNotFound) {
+			return SignatureUnavailable, nil
+		}
+		return "", err
+	}
+	return SignatureVerified, nil
+}
+
+func binaryName(goos string) string {
+	if goos == "windows" {
+		return "ado.exe"
+	}
+	return "ado"
+}
+
+// extractBinary returns the contents of the file named binary from a
+// .tar.gz or .zip archive.
+func extractBinary(archive []byte, archiveName, binary string) ([]byte, error) {
+	if strings.HasSuffix(archiveName, ".zip") {
+		zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+		if err != nil {
+			return nil, fmt.Errorf("open %s: %w", archiveName, err)
+		}
+		for _, f := range zr.File {
+			if path.Base(f.Name) != binary || f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return nil, fmt.Errorf("open %s in %s: %w", binary, archiveName, err)
+			}
+			defer rc.Close()
+			return io.ReadAll(rc)
+		}
+		return nil, fmt.Errorf("%s not found in %s", binary, archiveName)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(archive))
+	if err != nil {
+		return nil, fmt.Errorf("open %s: %w", archiveName, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("%s not found in %s", binary, archiveName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", archiveName, err)
+		}
+		if hdr.Typeflag == tar.TypeReg && path.Base(hdr.Name) == binary {
+			return io.ReadAll(tr)
+		}
+	}
+}
+
+// ResolveExecutable returns the real path of the running binary.
+func ResolveExecutable() (string, error) {
+	exe, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("locate executable: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(exe)
+	if err != nil {
+		return "", fmt.Errorf("resolve executable: %w", err)
+	}
+	return resolved, nil
+}
+
+// IsHomebrewManaged reports whether path lies inside a Homebrew Cellar.
+func IsHomebrewManaged(path string) bool {
+	return strings.Contains(filepath.ToSlash(path), "/Cellar/")
+}
+
+// ReplaceExecutable atomically replaces the file at path with data. The new
+// binary is written next to the target and renamed over it, so a failure
+// never leaves a partially written executable. On Windows, where a running
+// executable cannot be overwritten, the old binary is first moved aside.
+func ReplaceExecutable(path string, data []byte) error {
+	mode := os.FileMode(0o755)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".ado-update-*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op after a successful rename
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write new binary: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync new binary: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close new binary: %w", err)
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return fmt.Errorf("chmod new binary: %w", err)
+	}
+
+	if filepath.Ext(path) == ".exe" {
+		old := path + ".old"
+		_ = os.Remove(old)
+		if err := os.Rename(path, old); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("move old binary aside: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replace binary: %w", err)
+	}
+	return nil
+}
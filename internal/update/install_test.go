@@ -0,0 +1,213 @@
+package update
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func makeTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func makeZip(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractBinary(t *testing.T) {
+	files := map[string]string{"LICENSE": "MIT", "ado": "unix binary", "ado.exe": "windows binary"}
+
+	got, err := extractBinary(makeTarGz(t, files), "ado_1.0.0_linux_amd64.tar.gz", "ado")
+	if err != nil || string(got) != "unix binary" {
+		t.Errorf("tar.gz: got %q, %v", got, err)
+	}
+
+	got, err = extractBinary(makeZip(t, files), "ado_1.0.0_windows_amd64.zip", "ado.exe")
+	if err != nil || string(got) != "windows binary" {
+		t.Errorf("zip: got %q, %v", got, err)
+	}
+
+	if _, err := extractBinary(makeTarGz(t, map[string]string{"README.md": ""}), "x.tar.gz", "ado"); err == nil {
+		t.Error("expected error when binary is missing")
+	}
+	if _, err := extractBinary([]byte("garbage"), "x.tar.gz", "ado"); err == nil {
+		t.Error("expected error for corrupt archive")
+	}
+}
+
+func TestReplaceExecutable(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ado")
+	if err := os.WriteFile(path, []byte("old"), 0o750); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ReplaceExecutable(path, []byte("new")); err != nil {
+		t.Fatalf("ReplaceExecutable() error = %v", err)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != "new" {
+		t.Errorf("contents = %q, want %q", data, "new")
+	}
+	info, _ := os.Stat(path)
+	if info.Mode().Perm() != 0o750 {
+		t.Errorf("mode = %v, want 0750", info.Mode().Perm())
+	}
+
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("temp files left behind: %v", entries)
+	}
+}
+
+func TestIsHomebrewManaged(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{"/opt/homebrew/Cellar/ado/1.2.0/bin/ado", true},
+		{"/usr/local/Cellar/ado/1.2.0/bin/ado", true},
+		{"/home/linuxbrew/.linuxbrew/Cellar/ado/1.2.0/bin/ado", true},
+		{"/usr/local/bin/ado", false},
+		{"/home/me/go/bin/ado", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsHomebrewManaged(tt.path); got != tt.want {
+			t.Errorf("IsHomebrewManaged(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+type fakeVerifier struct{ err error }
+
+func (f fakeVerifier) VerifyBlob(ctx context.Context, blob, signature, certificate []byte) error {
+	return f.err
+}
+
+func TestInstaller_Install(t *testing.T) {
+	archiveName := "ado_1.2.0_linux_amd64.tar.gz"
+	archive := makeTarGz(t, map[string]string{"ado": "new binary"})
+	checksums := checksumLine(archive, archiveName)
+
+	tests := []struct {
+		name       string
+		checksums  string
+		signed     bool
+		verifier   fakeVerifier
+		wantStatus string
+		wantErr    bool
+	}{
+		{name: "unsigned", checksums: checksums, wantStatus: SignatureUnsigned},
+		{name: "signed and verified", checksums: checksums, signed: true, wantStatus: SignatureVerified},
+		{name: "cosign missing", checksums: checksums, signed: true, verifier: fakeVerifier{ErrCosignNotFound}, wantStatus: SignatureUnavailable},
+		{name: "bad signature", checksums: checksums, signed: true, verifier: fakeVerifier{errors.New("bad")}, wantErr: true},
+		{name: "checksum mismatch", checksums: "0000  " + archiveName + "\n", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assets := map[string]string{archiveName: string(archive), ChecksumsAsset: tt.checksums}
+			if tt.signed {
+				assets[ChecksumsAsset+SignatureSuffix] = "sig"
+				assets[ChecksumsAsset+CertificateSuffix] = "cert"
+			}
+
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				content, ok := assets[strings.TrimPrefix(r.URL.Path, "/")]
+				if !ok {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+				_, _ = w.Write([]byte(content))
+			})
+
+			release := &Release{TagName: "v1.2.0"}
+			for name := range assets {
+				release.Assets = append(release.Assets, Asset{Name: name, URL: client.APIURL + "/" + name})
+			}
+
+			exe := filepath.Join(t.TempDir(), "ado")
+			if err := os.WriteFile(exe, []byte("old binary"), 0o755); err != nil {
+				t.Fatal(err)
+			}
+
+			in := Installer{Client: client, Verifier: tt.verifier, GOOS: "linux", GOARCH: "amd64"}
+			status, err := in.Install(context.Background(), release, exe)
+
+			data, _ := os.ReadFile(exe)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("Install() expected error")
+				}
+				if string(data) != "old binary" {
+					t.Errorf("binary replaced despite error: %q", data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+			if status != tt.wantStatus {
+				t.Errorf("status = %q, want %q", status, tt.wantStatus)
+			}
+			if string(data) != "new binary" {
+				t.Errorf("binary = %q, want %q", data, "new binary")
+			}
+		})
+	}
+}
+
+func TestInstaller_Install_MissingAssets(t *testing.T) {
+	in := Installer{Client: NewClient(), GOOS: "linux", GOARCH: "amd64"}
+
+	if _, err := in.Install(context.Background(), &Release{TagName: "v1.0.0"}, "unused"); err == nil {
+		t.Error("expected error when platform archive is missing")
+	}
+
+	release := &Release{TagName: "v1.0.0", Assets: []Asset{{Name: "ado_1.0.0_linux_amd64.tar.gz"}}}
+	if _, err := in.Install(context.Background(), release, "unused"); err == nil || !strings.Contains(err.Error(), ChecksumsAsset) {
+		t.Errorf("expected checksums error, got %v", err)
+	}
+}
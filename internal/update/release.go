@@ -0,0 +1,189 @@
+// Package update discovers, verifies, and installs ado releases published
+// on GitHub.
+package update
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Release channels.
+const (
+	ChannelStable     = "stable"
+	ChannelPrerelease = "prerelease"
+)
+
+// DefaultRepository is the GitHub repository releases are published to.
+const DefaultRepository = "anowarislam/ado"
+
+// defaultAPIURL is the GitHub REST API base URL.
+const defaultAPIURL = "https://api.github.com"
+
+// requestTimeout bounds release metadata requests; downloads use the
+// caller's context only.
+const requestTimeout = 10 * time.Second
+
+// ErrNoRelease is returned when no release matches the requested channel.
+var ErrNoRelease = errors.New("no release found")
+
+// Release is a published GitHub release.
+type Release struct {
+	TagName    string  `json:"tag_name" yaml:"tag_name"`
+	Name       string  `json:"name" yaml:"name"`
+	Prerelease bool    `json:"prerelease" yaml:"prerelease"`
+	Draft      bool    `json:"draft" yaml:"draft"`
+	HTMLURL    string  `json:"html_url" yaml:"html_url"`
+	Assets     []Asset `json:"assets" yaml:"assets"`
+}
+
+// Asset is a file attached to a release.
+type Asset struct {
+	Name string `json:"name" yaml:"name"`
+	URL  string `json:"browser_download_url" yaml:"url"`
+	Size int64  `json:"size" yaml:"size"`
+}
+
+// Version returns the release version without the leading "v".
+func (r Release) Version() string {
+	return strings.TrimPrefix(r.TagName, "v")
+}
+
+// Asset returns the named asset, if attached.
+func (r Release) Asset(name string) (Asset, bool) {
+	for _, a := range r.Assets {
+		if a.Name == name {
+			return a, true
+		}
+	}
+	return Asset{}, false
+}
+
+// Client queries GitHub releases. The zero value is not usable; use NewClient.
+type Client struct {
+	APIURL     string
+	Repository string
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client for DefaultRepository.
+func NewClient() *Client {
+	return &Client{
+		APIURL:     defaultAPIURL,
+		Repository: DefaultRepository,
+		HTTPClient: http.DefaultClient,
+	}
+}
+
+// ValidateChannel reports whether channel is a supported release channel.
+func ValidateChannel(channel string) error {
+	switch channel {
+	case ChannelStable, ChannelPrerelease:
+		return nil
+	default:
+		return fmt.Errorf("invalid channel %q: must be %s or %s", channel, ChannelStable, ChannelPrerelease)
+	}
+}
+
+// Latest returns the newest release on channel. The stable channel uses
+// GitHub's "latest release" (never a prerelease); the prerelease channel
+// returns the newest non-draft release of either kind.
+func (c *Client) Latest(ctx context.Context, channel string) (*Release, error) {
+	if err := ValidateChannel(channel); err != nil {
+		return nil, err
+	}
+
+	if channel == ChannelStable {
+		var release Release
+		if err := c.getJSON(ctx, "/releases/latest", &release); err != nil {
+			return nil, err
+		}
+		return &release, nil
+	}
+
+	var releases []Release
+	if err := c.getJSON(ctx, "/releases?per_page=20", &releases); err != nil {
+		return nil, err
+	}
+	for i := range releases {
+		if !releases[i].Draft {
+			return &releases[i], nil
+		}
+	}
+	return nil, ErrNoRelease
+}
+
+func (c *Client) getJSON(ctx context.Context, path string, v any) error {
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+
+	url := strings.TrimRight(c.APIURL, "/") + "/repos/" + c.Repository + path
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("query releases: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNoRelease
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("query releases: unexpected status %s", resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("decode releases: %w", err)
+	}
+	return nil
+}
+
+// Download fetches an asset's contents.
+func (c *Client) Download(ctx context.Context, asset Asset) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, asset.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request: %w", err)
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("download %s: unexpected status %s", asset.Name, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("download %s: %w", asset.Name, err)
+	}
+	return data, nil
+}
+
+// ArchiveName returns the release archive name for a platform, matching
+// the name_template in .goreleaser.yaml.
+func ArchiveName(version, goos, goarch string) string {
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("ado_%s_%s_%s.%s", strings.TrimPrefix(version, "v"), goos, goarch, ext)
+}
+
+// BinaryAssetName returns the name the bare binary for a platform is listed
+// under in checksums.txt, matching the checksum extra_files in
+// .goreleaser.yaml.
+func BinaryAssetName(goos, goarch string) string {
+	return fmt.Sprintf("ado_%s_%s", goos, goarch) + strings.TrimPrefix(binaryName(goos), "ado")
+}
@@ -0,0 +1,127 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+	return &Client{APIURL: srv.URL, Repository: "owner/repo", HTTPClient: srv.Client()}
+}
+
+func TestClient_Latest(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel string
+		path    string
+		body    string
+		status  int
+		wantTag string
+		wantErr error
+	}{
+		{
+			name:    "stable",
+			channel: ChannelStable,
+			path:    "/repos/owner/repo/releases/latest",
+			body:    `{"tag_name":"v1.2.0","assets":[{"name":"checksums.txt","browser_download_url":"u"}]}`,
+			status:  http.StatusOK,
+			wantTag: "v1.2.0",
+		},
+		{
+			name:    "prerelease skips drafts",
+			channel: ChannelPrerelease,
+			path:    "/repos/owner/repo/releases",
+			body:    `[{"tag_name":"v1.3.0","draft":true},{"tag_name":"v1.3.0-rc.1","prerelease":true},{"tag_name":"v1.2.0"}]`,
+			status:  http.StatusOK,
+			wantTag: "v1.3.0-rc.1",
+		},
+		{
+			name:    "no releases",
+			channel: ChannelStable,
+			path:    "/repos/owner/repo/releases/latest",
+			status:  http.StatusNotFound,
+			wantErr: ErrNoRelease,
+		},
+		{
+			name:    "only drafts",
+			channel: ChannelPrerelease,
+			path:    "/repos/owner/repo/releases",
+			body:    `[{"tag_name":"v2.0.0","draft":true}]`,
+			status:  http.StatusOK,
+			wantErr: ErrNoRelease,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != tt.path {
+					t.Errorf("path = %q, want %q", r.URL.Path, tt.path)
+				}
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			release, err := client.Latest(context.Background(), tt.channel)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Latest() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Latest() error = %v", err)
+			}
+			if release.TagName != tt.wantTag {
+				t.Errorf("TagName = %q, want %q", release.TagName, tt.wantTag)
+			}
+		})
+	}
+}
+
+func TestClient_Latest_InvalidChannel(t *testing.T) {
+	if _, err := NewClient().Latest(context.Background(), "nightly"); err == nil {
+		t.Error("Latest() expected error for invalid channel")
+	}
+}
+
+func TestClient_Latest_ServerError(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+	if _, err := client.Latest(context.Background(), ChannelStable); err == nil {
+		t.Error("Latest() expected error for 403")
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	tests := []struct {
+		version, goos, goarch string
+		want                  string
+	}{
+		{"v1.2.0", "linux", "amd64", "ado_1.2.0_linux_amd64.tar.gz"},
+		{"1.2.0", "darwin", "arm64", "ado_1.2.0_darwin_arm64.tar.gz"},
+		{"1.2.0-rc.1", "windows", "amd64", "ado_1.2.0-rc.1_windows_amd64.zip"},
+	}
+
+	for _, tt := range tests {
+		if got := ArchiveName(tt.version, tt.goos, tt.goarch); got != tt.want {
+			t.Errorf("ArchiveName(%q, %q, %q) = %q, want %q", tt.version, tt.goos, tt.goarch, got, tt.want)
+		}
+	}
+}
+
+func TestBinaryAssetName(t *testing.T) {
+	if got := BinaryAssetName("linux", "arm64"); got != "ado_linux_arm64" {
+		t.Errorf("BinaryAssetName(linux, arm64) = %q", got)
+	}
+	if got := BinaryAssetName("windows", "amd64"); got != "ado_windows_amd64.exe" {
+		t.Errorf("BinaryAssetName(windows, amd64) = %q", got)
+	}
+}
@@ -0,0 +1,185 @@
+package update
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Release signing parameters. checksums.txt is signed keylessly by the
+// GoReleaser workflow, so the certificate identity is the workflow itself.
+const (
+	ChecksumsAsset      = "checksums.txt"
+	SignatureSuffix     = ".sig"
+	CertificateSuffix   = ".pem"
+	DefaultCertIdentity = `^https://github\.com/anowarislam/ado/\.github/workflows/goreleaser\.yml@`
+	DefaultCertIssuer   = "https://token.actions.githubusercontent.com"
+)
+
+// Signature verification outcomes.
+const (
+	SignatureVerified    = "verified"
+	SignatureUnsigned    = "unsigned"    // no signature was published or found
+	SignatureUnavailable = "unavailable" // the verification tool is not installed
+	SignatureFailed      = "failed"
+)
+
+// Checksum verification outcomes of a binary against checksums.txt.
+const (
+	ChecksumMatch    = "match"
+	ChecksumMismatch = "mismatch"
+	ChecksumMissing  = "missing" // no checksums.txt, or no entry for the binary
+)
+
+// ErrNotListed is returned when checksums.txt has no entry for a file.
+var ErrNotListed = errors.New("no checksum listed")
+
+// ErrCosignNotFound is returned when signature verification needs cosign
+// and it is not in PATH.
+var ErrCosignNotFound = errors.New("cosign not found in PATH")
+
+// ErrMinisignNotFound is returned when signature verification needs
+// minisign and it is not in PATH.
+var ErrMinisignNotFound = errors.New("minisign not found in PATH")
+
+// runCombined runs a command and returns its combined output.
+func runCombined(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return exec.CommandContext(ctx, name, args...).CombinedOutput()
+}
+
+// writeTempFiles writes files into a new temp dir and returns its path;
+// the caller removes it.
+func writeTempFiles(files map[string][]byte) (string, error) {
+	dir, err := os.MkdirTemp("", "ado-verify-")
+	if err != nil {
+		return "", fmt.Errorf("create temp dir: %w", err)
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("write %s: %w", name, err)
+		}
+	}
+	return dir, nil
+}
+
+// VerifyChecksum checks data against the entry for name in a checksums.txt
+// file ("<sha256>  <name>" per line).
+func VerifyChecksum(data []byte, name string, checksums []byte) error {
+	want, err := lookupChecksum(checksums, name)
+	if err != nil {
+		return err
+	}
+
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, want) {
+		return fmt.Errorf("checksum mismatch for %s: got %s, want %s", name, got, want)
+	}
+	return nil
+}
+
+func lookupChecksum(checksums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == name {
+			return fields[0], nil
+		}
+	}
+	return "", fmt.Errorf("%w for %s", ErrNotListed, name)
+}
+
+// CosignVerifier verifies keyless Sigstore blob signatures by running the
+// cosign CLI. LookPath and Run are replaceable in tests.
+type CosignVerifier struct {
+	Identity string // certificate identity regexp
+	Issuer   string // OIDC issuer
+	LookPath func(file string) (string, error)
+	Run      func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// NewCosignVerifier returns a verifier for ado's release workflow identity.
+func NewCosignVerifier() CosignVerifier {
+	return CosignVerifier{
+		Identity: DefaultCertIdentity,
+		Issuer:   DefaultCertIssuer,
+		LookPath: exec.LookPath,
+		Run:      runCombined,
+	}
+}
+
+// VerifyBlob verifies that signature and certificate cover blob. It returns
+// ErrCosignNotFound when cosign is unavailable.
+func (v CosignVerifier) VerifyBlob(ctx context.Context, blob, signature, certificate []byte) error {
+	cosign, err := v.LookPath("cosign")
+	if err != nil {
+		return ErrCosignNotFound
+	}
+
+	dir, err := writeTempFiles(map[string][]byte{"blob": blob, "blob.sig": signature, "blob.pem": certificate})
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	output, err := v.Run(ctx, cosign, "verify-blob",
+		"--signature", filepath.Join(dir, "blob.sig"),
+		"--certificate", filepath.Join(dir, "blob.pem"),
+		"--certificate-identity-regexp", v.Identity,
+		"--certificate-oidc-issuer", v.Issuer,
+		filepath.Join(dir, "blob"),
+	)
+	if err != nil {
+		return fmt.Errorf("signature verification failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// MinisignVerifier verifies minisign signatures by running the minisign
+// CLI. LookPath and Run are replaceable in tests.
+type MinisignVerifier struct {
+	PublicKey string // base64 public key
+	LookPath  func(file string) (string, error)
+	Run       func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// NewMinisignVerifier returns a verifier for publicKey.
+func NewMinisignVerifier(publicKey string) MinisignVerifier {
+	return MinisignVerifier{PublicKey: publicKey, LookPath: exec.LookPath, Run: runCombined}
+}
+
+// Verify checks that signature (a .minisig file) covers message. It returns
+// ErrMinisignNotFound when minisign is unavailable.
+func (v MinisignVerifier) Verify(ctx context.Context, message, signature []byte) error {
+	if v.PublicKey == "" {
+		return errors.New("no minisign public key configured")
+	}
+	minisign, err := v.LookPath("minisign")
+	if err != nil {
+		return ErrMinisignNotFound
+	}
+
+	dir, err := writeTempFiles(map[string][]byte{"blob": message, "blob.minisig": signature})
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	output, err := v.Run(ctx, minisign, "-V", "-q",
+		"-P", v.PublicKey,
+		"-m", filepath.Join(dir, "blob"),
+		"-x", filepath.Join(dir, "blob.minisig"),
+	)
+	if err != nil {
+		return fmt.Errorf("signature verification failed: %s", strings.TrimSpace(string(output)))
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package update
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"os"
+	"slices"
+	"testing"
+)
+
+func checksumLine(data []byte, name string) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + "  " + name + "\n"
+}
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("binary contents")
+	checksums := []byte("deadbeef  other.tar.gz\n" + checksumLine(data, "ado_1.0.0_linux_amd64.tar.gz"))
+
+	if err := VerifyChecksum(data, "ado_1.0.0_linux_amd64.tar.gz", checksums); err != nil {
+		t.Errorf("VerifyChecksum() error = %v", err)
+	}
+	if err := VerifyChecksum([]byte("tampered"), "ado_1.0.0_linux_amd64.tar.gz", checksums); err == nil {
+		t.Error("VerifyChecksum() expected mismatch error")
+	}
+	if err := VerifyChecksum(data, "missing.zip", checksums); err == nil {
+		t.Error("VerifyChecksum() expected error for unlisted file")
+	}
+}
+
+func TestCosignVerifier_VerifyBlob(t *testing.T) {
+	var gotArgs []string
+	v := CosignVerifier{
+		Identity: "id",
+		Issuer:   "issuer",
+		LookPath: func(string) (string, error) { return "/usr/bin/cosign", nil },
+		Run: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			gotArgs = args
+			// All three inputs must exist while cosign runs.
+			for _, i := range []int{2, 4, len(args) - 1} {
+				if _, err := os.Stat(args[i]); err != nil {
+					t.Errorf("input %s missing: %v", args[i], err)
+				}
+			}
+			return nil, nil
+		},
+	}
+
+	if err := v.VerifyBlob(context.Background(), []byte("blob"), []byte("sig"), []byte("cert")); err != nil {
+		t.Fatalf("VerifyBlob() error = %v", err)
+	}
+	if gotArgs[0] != "verify-blob" || !slices.Contains(gotArgs, "id") || !slices.Contains(gotArgs, "issuer") {
+		t.Errorf("unexpected cosign args: %v", gotArgs)
+	}
+}
+
+func TestCosignVerifier_Failures(t *testing.T) {
+	missing := CosignVerifier{LookPath: func(string) (string, error) { return "", errors.New("not found") }}
+	if err := missing.VerifyBlob(context.Background(), nil, nil, nil); !errors.Is(err, ErrCosignNotFound) {
+		t.Errorf("VerifyBlob() error = %v, want ErrCosignNotFound", err)
+	}
+
+	rejected := CosignVerifier{
+		LookPath: func(string) (string, error) { return "cosign", nil },
+		Run: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			return []byte("error: none of the expected identities matched"), errors.New("exit status 1")
+		},
+	}
+	if err := rejected.VerifyBlob(context.Background(), nil, nil, nil); err == nil || errors.Is(err, ErrCosignNotFound) {
+		t.Errorf("VerifyBlob() error = %v, want verification failure", err)
+	}
+}
+
+func TestMinisignVerifier_Verify(t *testing.T) {
+	var gotArgs []string
+	v := MinisignVerifier{
+		PublicKey: "RWQkey",
+		LookPath:  func(string) (string, error) { return "/usr/bin/minisign", nil },
+		Run: func(ctx context.Context, name string, args ...string) ([]byte, error) {
+			gotArgs = args
+			return nil, nil
+		},
+	}
+	if err := v.Verify(context.Background(), []byte("bin"), []byte("sig")); err != nil {
+		t.Fatalf("Verify() error = %v", err)
+	}
+	if !slices.Contains(gotArgs, "-V") || !slices.Contains(gotArgs, "RWQkey") {
+		t.Errorf("unexpected minisign args: %v", gotArgs)
+	}
+
+	v.PublicKey = ""
+	if err := v.Verify(context.Background(), nil, nil); err == nil {
+		t.Error("Verify() expected error without public key")
+	}
+
+	missing := MinisignVerifier{PublicKey: "k", LookPath: func(string) (string, error) { return "", errors.New("nope") }}
+	if err := missing.Verify(context.Background(), nil, nil); !errors.Is(err, ErrMinisignNotFound) {
+		t.Errorf("Verify() error = %v, want ErrMinisignNotFound", err)
+	}
+}
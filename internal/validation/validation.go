@@ -0,0 +1,242 @@
+// Package validation reports the issues found in a file by ado config
+// validate, ado run lint, and ado workflow lint: as text, JSON, YAML,
+// JUnit, SARIF, or TAP, and as GitHub Actions annotations and a job
+// summary.
+package validation
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/ghactions"
+	"github.com/anowarislam/ado/internal/junit"
+	"github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/sarif"
+	"github.com/anowarislam/ado/internal/tap"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// Formats lists the output formats Write accepts besides text, JSON, and
+// YAML.
+var Formats = []ui.OutputFormat{ui.OutputJUnit, ui.OutputSARIF, ui.OutputTAP}
+
+// OutputHelp describes the --output values of a validating command.
+const OutputHelp = "Output format: text, json, yaml, junit, sarif, tap"
+
+// Reporter reports the results of one validating command.
+type Reporter struct {
+	// Title names the command, such as "ado config validate", in
+	// JUnit reports and annotations.
+	Title string
+	// Subject is what is validated, such as "Config", in "✓ Config
+	// valid: PATH".
+	Subject string
+	// Rules lists every kind of issue the command reports, for SARIF.
+	Rules []config.ValidationRule
+}
+
+// Write writes result to w in format.
+func (r Reporter) Write(w io.Writer, format ui.OutputFormat, result *config.ValidationResult) error {
+	switch format {
+	case ui.OutputJUnit:
+		return junit.Write(w, r.Title, r.Suite(result))
+	case ui.OutputSARIF:
+		return sarif.Write(w, r.Log(result))
+	case ui.OutputTAP:
+		return tap.Write(w, r.Tests(result))
+	}
+	return ui.PrintOutput(w, format, result, func() (string, error) {
+		return r.Text(result), nil
+	})
+}
+
+// Strict turns the warnings of result into errors, for --strict.
+func Strict(result *config.ValidationResult) {
+	if !result.HasWarnings() {
+		return
+	}
+	for _, w := range result.Warnings {
+		w.Severity = "error"
+		result.Errors = append(result.Errors, w)
+	}
+	result.Warnings = []config.ValidationIssue{}
+	result.Valid = false
+}
+
+// Text describes result, one line per included file and issue.
+func (r Reporter) Text(result *config.ValidationResult) string {
+	var b strings.Builder
+
+	if result.Valid {
+		fmt.Fprintf(&b, "\u2713 %s valid: %s", r.Subject, result.Path)
+	} else {
+		fmt.Fprintf(&b, "\u2717 %s invalid: %s", r.Subject, result.Path)
+	}
+
+	for _, file := range result.Includes {
+		fmt.Fprintf(&b, "\n  Included: %s", file)
+	}
+
+	for _, e := range result.Errors {
+		fmt.Fprintf(&b, "\n  Error: %s%s", e.Message, Location(e))
+	}
+
+	for _, w := range result.Warnings {
+		fmt.Fprintf(&b, "\n  Warning: %s%s", w.Message, Location(w))
+	}
+
+	return b.String()
+}
+
+// Suite reports result as a JUnit suite: one failed case per error, one
+// passing case per warning with the warning as its output, and a single
+// passing case for a file without issues.
+func (r Reporter) Suite(result *config.ValidationResult) junit.TestSuite {
+	var cases []junit.TestCase
+	issueCase := func(issue config.ValidationIssue) junit.TestCase {
+		name := issue.Message
+		if issue.Line > 0 {
+			name = fmt.Sprintf("line %d: %s", issue.Line, issue.Message)
+		}
+		return junit.TestCase{Name: name, Classname: File(result, issue)}
+	}
+	for _, e := range result.Errors {
+		c := issueCase(e)
+		c.Failure = &junit.Failure{Message: e.Message, Type: e.Severity}
+		cases = append(cases, c)
+	}
+	for _, w := range result.Warnings {
+		c := issueCase(w)
+		c.SystemOut = "warning: " + w.Message
+		cases = append(cases, c)
+	}
+	if len(cases) == 0 {
+		cases = append(cases, junit.TestCase{Name: "valid", Classname: result.Path})
+	}
+	return junit.NewSuite(result.Path, cases...)
+}
+
+// Tests reports result as TAP test points, failing one per error and
+// passing one per warning, like Suite.
+func (r Reporter) Tests(result *config.ValidationResult) []tap.Test {
+	var tests []tap.Test
+	for _, issue := range issues(result) {
+		diagnostics := map[string]any{"severity": issue.Severity, "rule": issue.Rule, "file": File(result, issue)}
+		if issue.Line > 0 {
+			diagnostics["line"] = issue.Line
+		}
+		tests = append(tests, tap.Test{OK: issue.Severity == "warning", Description: issue.Message, Diagnostics: diagnostics})
+	}
+	if len(tests) == 0 {
+		tests = append(tests, tap.Test{OK: true, Description: result.Path + " is valid"})
+	}
+	return tests
+}
+
+// Log reports result as a SARIF log with a rule per kind of issue, for
+// GitHub code scanning.
+func (r Reporter) Log(result *config.ValidationResult) sarif.Log {
+	rules := make([]sarif.Rule, len(r.Rules))
+	index := make(map[string]int, len(rules))
+	for i, rule := range r.Rules {
+		rules[i] = sarif.Rule{
+			ID:                   rule.ID,
+			ShortDescription:     sarif.Message{Text: rule.Description},
+			DefaultConfiguration: sarif.DefaultConfiguration{Level: sarifLevel(rule.Severity)},
+		}
+		index[rule.ID] = i
+	}
+
+	var results []sarif.Result
+	for _, issue := range issues(result) {
+		results = append(results, sarif.NewResult(rules, index[issue.Rule], sarifLevel(issue.Severity), issue.Message, File(result, issue), issue.Line))
+	}
+	return sarif.Log{Runs: []sarif.Run{{
+		Tool: sarif.Tool{Driver: sarif.Driver{
+			Name:           "ado",
+			Version:        meta.CurrentBuildInfo().Version,
+			InformationURI: "https://github.com/anowarislam/ado",
+			Rules:          rules,
+		}},
+		Results: results,
+	}}}
+}
+
+// sarifLevel maps a validation severity to a SARIF level.
+func sarifLevel(severity string) string {
+	if severity == "warning" {
+		return sarif.LevelWarning
+	}
+	return sarif.LevelError
+}
+
+// ReportToActions annotates the file with each issue, unless w carries
+// structured output, and adds the result to the job summary.
+func (r Reporter) ReportToActions(w io.Writer, format ui.OutputFormat, result *config.ValidationResult) error {
+	issues := issues(result)
+	if format == ui.OutputText {
+		for _, issue := range issues {
+			level := ghactions.LevelError
+			if issue.Severity == "warning" {
+				level = ghactions.LevelWarning
+			}
+			a := ghactions.Annotation{Level: level, File: File(result, issue), Line: issue.Line, Title: r.Title, Message: issue.Message}
+			if err := ghactions.Annotate(w, a); err != nil {
+				return err
+			}
+		}
+	}
+
+	var b strings.Builder
+	if result.Valid {
+		fmt.Fprintf(&b, "### \u2713 %s valid: `%s`\n", r.Subject, ghactions.RelPath(result.Path))
+	} else {
+		fmt.Fprintf(&b, "### \u2717 %s invalid: `%s`\n", r.Subject, ghactions.RelPath(result.Path))
+	}
+	if len(issues) > 0 {
+		rows := make([][]string, len(issues))
+		for i, issue := range issues {
+			line := ""
+			if issue.Line > 0 {
+				line = strconv.Itoa(issue.Line)
+			}
+			if issue.File != "" {
+				line = ghactions.RelPath(issue.File) + ":" + line
+			}
+			rows[i] = []string{issue.Severity, line, issue.Message}
+		}
+		b.WriteString("\n" + ghactions.Table([]string{"Severity", "Line", "Message"}, rows))
+	}
+	return ghactions.AppendSummary(b.String())
+}
+
+// Location describes where an issue is, such as " at line 3" or
+// " at line 3 of conf.d/tasks.yaml" for one in an included file.
+func Location(issue config.ValidationIssue) string {
+	switch {
+	case issue.Line > 0 && issue.File != "":
+		return fmt.Sprintf(" at line %d of %s", issue.Line, issue.File)
+	case issue.Line > 0:
+		return fmt.Sprintf(" at line %d", issue.Line)
+	case issue.File != "":
+		return " in " + issue.File
+	}
+	return ""
+}
+
+// File returns the file issue is in: an included file, or the validated
+// one.
+func File(result *config.ValidationResult, issue config.ValidationIssue) string {
+	if issue.File != "" {
+		return issue.File
+	}
+	return result.Path
+}
+
+// issues returns the errors of result followed by its warnings.
+func issues(result *config.ValidationResult) []config.ValidationIssue {
+	return append(append([]config.ValidationIssue{}, result.Errors...), result.Warnings...)
+}
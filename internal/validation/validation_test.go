@@ -0,0 +1,78 @@
+package validation
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+var testReporter = Reporter{
+	Title:   "ado test lint",
+	Subject: "Test",
+	Rules: []config.ValidationRule{
+		{ID: "broken", Description: "Something is broken", Severity: "error"},
+		{ID: "odd", Description: "Something is odd", Severity: "warning"},
+	},
+}
+
+func testResult() *config.ValidationResult {
+	return &config.ValidationResult{
+		Path:     "test.yaml",
+		Valid:    false,
+		Errors:   []config.ValidationIssue{{Message: "broken thing", Line: 3, Severity: "error", Rule: "broken"}},
+		Warnings: []config.ValidationIssue{{Message: "odd thing", Line: 7, File: "inc.yaml", Severity: "warning", Rule: "odd"}},
+	}
+}
+
+func TestReporter_Text(t *testing.T) {
+	want := "✗ Test invalid: test.yaml\n  Error: broken thing at line 3\n  Warning: odd thing at line 7 of inc.yaml"
+	if got := testReporter.Text(testResult()); got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+}
+
+func TestReporter_Write(t *testing.T) {
+	tests := []struct {
+		format ui.OutputFormat
+		want   []string
+	}{
+		{ui.OutputJSON, []string{`"rule": "odd"`, `"file": "inc.yaml"`}},
+		{ui.OutputJUnit, []string{`name="ado test lint"`, `name="line 3: broken thing"`}},
+		{ui.OutputSARIF, []string{`"id": "broken"`, `"text": "Something is odd"`, `"ruleIndex": 1`, `"uri": "inc.yaml"`}},
+		{ui.OutputTAP, []string{"not ok 1 - broken thing", "ok 2 - odd thing"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var b bytes.Buffer
+			if err := testReporter.Write(&b, tt.format, testResult()); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(b.String(), want) {
+					t.Errorf("output lacks %q:\n%s", want, b.String())
+				}
+			}
+		})
+	}
+}
+
+func TestStrict(t *testing.T) {
+	result := testResult()
+	result.Errors = nil
+	result.Valid = true
+	Strict(result)
+	if result.Valid || len(result.Warnings) != 0 || len(result.Errors) != 1 {
+		t.Fatalf("Strict() = %+v", result)
+	}
+	if issue := result.Errors[0]; issue.Severity != "error" || issue.Rule != "odd" || issue.File != "inc.yaml" || issue.Line != 7 {
+		t.Errorf("error = %+v", issue)
+	}
+
+	result = &config.ValidationResult{Path: "test.yaml", Valid: true}
+	if Strict(result); !result.Valid {
+		t.Error("Strict() invalidated a result without warnings")
+	}
+}
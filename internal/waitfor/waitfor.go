@@ -0,0 +1,220 @@
+// Package waitfor polls TCP ports, HTTP endpoints, files, and commands
+// until they are ready.
+package waitfor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults for Options.
+const (
+	DefaultTimeout        = time.Minute
+	DefaultInterval       = time.Second
+	DefaultAttemptTimeout = 5 * time.Second
+	DefaultStatus         = http.StatusOK
+)
+
+// Target kinds.
+const (
+	KindTCP     = "tcp"
+	KindHTTP    = "http"
+	KindFile    = "file"
+	KindCommand = "command"
+)
+
+// Target is something to wait for.
+type Target struct {
+	Kind string
+	// Address is host:port for tcp, the URL for http, and the path for file.
+	Address string
+	// Command is the argv run for command targets.
+	Command []string
+}
+
+// String returns the target as it is reported in results.
+func (t Target) String() string {
+	switch t.Kind {
+	case KindTCP:
+		return "tcp://" + t.Address
+	case KindFile:
+		return "file://" + t.Address
+	case KindCommand:
+		return strings.Join(t.Command, " ")
+	}
+	return t.Address
+}
+
+// ParseTarget parses tcp://HOST:PORT, http(s)://URL, file://PATH, or the
+// HOST:PORT shorthand for tcp. ":PORT" means localhost.
+func ParseTarget(raw string) (Target, error) {
+	switch {
+	case strings.HasPrefix(raw, "http://"), strings.HasPrefix(raw, "https://"):
+		return Target{Kind: KindHTTP, Address: raw}, nil
+	case strings.HasPrefix(raw, "file://"):
+		path := strings.TrimPrefix(raw, "file://")
+		if path == "" {
+			return Target{}, fmt.Errorf("invalid target %q: missing path", raw)
+		}
+		return Target{Kind: KindFile, Address: path}, nil
+	}
+
+	address := strings.TrimPrefix(raw, "tcp://")
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return Target{}, fmt.Errorf("invalid target %q: expected tcp://HOST:PORT, http(s)://URL, or file://PATH", raw)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return Target{}, fmt.Errorf("invalid target %q: bad port %q", raw, port)
+	}
+	if host == "" {
+		host = "localhost"
+	}
+	return Target{Kind: KindTCP, Address: net.JoinHostPort(host, port)}, nil
+}
+
+// Options controls Wait.
+type Options struct {
+	// Timeout bounds the whole wait; 0 waits until ctx is done.
+	Timeout time.Duration
+	// Interval is the delay between attempts on the same target.
+	Interval time.Duration
+	// AttemptTimeout bounds a single attempt.
+	AttemptTimeout time.Duration
+	// Status is the HTTP status code that counts as ready.
+	Status int
+}
+
+// Result reports how waiting for one target went.
+type Result struct {
+	Target     string `json:"target" yaml:"target"`
+	Kind       string `json:"kind" yaml:"kind"`
+	Ready      bool   `json:"ready" yaml:"ready"`
+	Attempts   int    `json:"attempts" yaml:"attempts"`
+	DurationMS int64  `json:"duration_ms" yaml:"duration_ms"`
+	// Error is why the last attempt failed, when the target never became ready.
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Wait polls every target concurrently until all are ready or the
+// timeout expires, and returns a result per target in input order.
+func Wait(ctx context.Context, targets []Target, opts Options) []Result {
+	if opts.Interval <= 0 {
+		opts.Interval = DefaultInterval
+	}
+	if opts.AttemptTimeout <= 0 {
+		opts.AttemptTimeout = DefaultAttemptTimeout
+	}
+	if opts.Status == 0 {
+		opts.Status = DefaultStatus
+	}
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+
+	results := make([]Result, len(targets))
+	var wg sync.WaitGroup
+	for i, target := range targets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = waitOne(ctx, target, opts)
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func waitOne(ctx context.Context, target Target, opts Options) (result Result) {
+	result = Result{Target: target.String(), Kind: target.Kind}
+	started := time.Now()
+	defer func() { result.DurationMS = time.Since(started).Milliseconds() }()
+
+	for {
+		result.Attempts++
+		err := attempt(ctx, target, opts)
+		if err == nil {
+			result.Ready = true
+			result.Error = ""
+			return result
+		}
+		// An attempt cut short by the overall deadline says less than the
+		// failure before it.
+		if ctx.Err() == nil || result.Error == "" {
+			result.Error = err.Error()
+		}
+		slog.DebugContext(ctx, "Target not ready", "target", result.Target, "attempt", result.Attempts, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return result
+		case <-time.After(opts.Interval):
+		}
+	}
+}
+
+func attempt(ctx context.Context, target Target, opts Options) error {
+	ctx, cancel := context.WithTimeout(ctx, opts.AttemptTimeout)
+	defer cancel()
+
+	switch target.Kind {
+	case KindTCP:
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", target.Address)
+		if err != nil {
+			return err
+		}
+		return conn.Close()
+
+	case KindHTTP:
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.Address, nil)
+		if err != nil {
+			return err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return err
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		if resp.StatusCode != opts.Status {
+			return fmt.Errorf("status %d, want %d", resp.StatusCode, opts.Status)
+		}
+		return nil
+
+	case KindFile:
+		_, err := os.Stat(target.Address)
+		return err
+
+	case KindCommand:
+		out, err := exec.CommandContext(ctx, target.Command[0], target.Command[1:]...).CombinedOutput()
+		if err != nil {
+			if line := lastLine(string(out)); line != "" {
+				return fmt.Errorf("%w: %s", err, line)
+			}
+			return err
+		}
+		return nil
+	}
+	return errors.New("unknown target kind " + target.Kind)
+}
+
+// lastLine returns the last non-empty line of command output, which is
+// usually the most useful part of an error.
+func lastLine(out string) string {
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,148 @@
+package waitfor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseTarget(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    Target
+		wantErr string
+	}{
+		{raw: "tcp://db:5432", want: Target{Kind: KindTCP, Address: "db:5432"}},
+		{raw: "db:5432", want: Target{Kind: KindTCP, Address: "db:5432"}},
+		{raw: ":8080", want: Target{Kind: KindTCP, Address: "localhost:8080"}},
+		{raw: "[::1]:80", want: Target{Kind: KindTCP, Address: "[::1]:80"}},
+		{raw: "http://api/healthz", want: Target{Kind: KindHTTP, Address: "http://api/healthz"}},
+		{raw: "https://example.com", want: Target{Kind: KindHTTP, Address: "https://example.com"}},
+		{raw: "file:///tmp/ready", want: Target{Kind: KindFile, Address: "/tmp/ready"}},
+		{raw: "file://", wantErr: "missing path"},
+		{raw: "db:http", wantErr: `bad port "http"`},
+		{raw: "db:0", wantErr: `bad port "0"`},
+		{raw: "just-a-name", wantErr: "expected tcp://HOST:PORT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := ParseTarget(tt.raw)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ParseTarget() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseTarget() error = %v", err)
+			}
+			if got.Kind != tt.want.Kind || got.Address != tt.want.Address {
+				t.Errorf("ParseTarget() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTargetString(t *testing.T) {
+	tests := []struct {
+		target Target
+		want   string
+	}{
+		{Target{Kind: KindTCP, Address: "db:5432"}, "tcp://db:5432"},
+		{Target{Kind: KindHTTP, Address: "http://api"}, "http://api"},
+		{Target{Kind: KindFile, Address: "/tmp/x"}, "file:///tmp/x"},
+		{Target{Kind: KindCommand, Command: []string{"pg_isready", "-h", "db"}}, "pg_isready -h db"},
+	}
+	for _, tt := range tests {
+		if got := tt.target.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+var fast = Options{Timeout: 2 * time.Second, Interval: 10 * time.Millisecond}
+
+func TestWait_Ready(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	file := filepath.Join(t.TempDir(), "ready")
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = os.WriteFile(file, nil, 0o644)
+	}()
+
+	targets := []Target{
+		{Kind: KindTCP, Address: ln.Addr().String()},
+		{Kind: KindHTTP, Address: srv.URL},
+		{Kind: KindFile, Address: file},
+		{Kind: KindCommand, Command: []string{"sh", "-c", "exit 0"}},
+	}
+	results := Wait(context.Background(), targets, fast)
+	for i, r := range results {
+		if !r.Ready || r.Error != "" {
+			t.Errorf("%s: ready = %v, error = %q", r.Target, r.Ready, r.Error)
+		}
+		if r.Kind != targets[i].Kind {
+			t.Errorf("%s: kind = %q, want %q", r.Target, r.Kind, targets[i].Kind)
+		}
+	}
+	if results[1].Attempts != 3 {
+		t.Errorf("http attempts = %d, want 3", results[1].Attempts)
+	}
+	if results[2].Attempts < 2 {
+		t.Errorf("file attempts = %d, want at least 2", results[2].Attempts)
+	}
+}
+
+func TestWait_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	opts := fast
+	opts.Timeout = 100 * time.Millisecond
+	targets := []Target{
+		{Kind: KindHTTP, Address: srv.URL},
+		{Kind: KindFile, Address: filepath.Join(t.TempDir(), "missing")},
+		{Kind: KindCommand, Command: []string{"sh", "-c", "echo not yet >&2; exit 3"}},
+	}
+	results := Wait(context.Background(), targets, opts)
+
+	wantErrors := []string{"status 204, want 200", "no such file", "exit status 3: not yet"}
+	for i, r := range results {
+		if r.Ready {
+			t.Errorf("%s: ready, want pending", r.Target)
+		}
+		if !strings.Contains(r.Error, wantErrors[i]) {
+			t.Errorf("%s: error = %q, want %q", r.Target, r.Error, wantErrors[i])
+		}
+		if r.Attempts < 2 || r.DurationMS < 50 {
+			t.Errorf("%s: attempts = %d after %dms", r.Target, r.Attempts, r.DurationMS)
+		}
+	}
+
+	opts.Status = http.StatusNoContent
+	if r := Wait(context.Background(), targets[:1], opts); !r[0].Ready {
+		t.Errorf("--status 204: %+v", r[0])
+	}
+}
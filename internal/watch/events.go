@@ -0,0 +1,58 @@
+package watch
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Event types emitted in NDJSON mode.
+const (
+	EventWatching = "watching"
+	EventChange   = "change"
+	EventStart    = "start"
+	EventExit     = "exit"
+	EventError    = "error"
+)
+
+// Event is one line of `ado watch --ndjson` output.
+type Event struct {
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`
+	// Roots is set on "watching".
+	Roots []string `json:"roots,omitempty"`
+	// Changes is set on "change" and on the "start" it triggered.
+	Changes []Change `json:"changes,omitempty"`
+	// Command is set on "start".
+	Command []string `json:"command,omitempty"`
+	// ExitCode and DurationMS are set on "exit".
+	ExitCode   *int  `json:"exit_code,omitempty"`
+	DurationMS int64 `json:"duration_ms,omitempty"`
+	// Error is set on "error" and on "exit" when the command failed to start
+	// or was stopped.
+	Error string `json:"error,omitempty"`
+}
+
+// EventWriter writes events as newline-delimited JSON. It is safe for
+// concurrent use.
+type EventWriter struct {
+	mu  sync.Mutex
+	enc *json.Encoder
+	now func() time.Time
+}
+
+// NewEventWriter returns an EventWriter writing to w.
+func NewEventWriter(w io.Writer) *EventWriter {
+	return &EventWriter{enc: json.NewEncoder(w), now: time.Now}
+}
+
+// Write stamps ev with the current time (unless set) and writes it.
+func (w *EventWriter) Write(ev Event) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if ev.Time.IsZero() {
+		ev.Time = w.now().UTC()
+	}
+	return w.enc.Encode(ev)
+}
@@ -0,0 +1,116 @@
+package watch
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultExcludes are always ignored: VCS metadata, dependency trees, and
+// editor swap/backup files that would otherwise trigger spurious re-runs.
+var DefaultExcludes = []string{
+	".git", ".hg", ".svn", "node_modules",
+	"*.swp", "*.swx", "*~", ".#*", "4913",
+}
+
+// Matcher selects paths by glob. Patterns use path.Match syntax plus "**"
+// for any number of directories. A pattern without a slash matches any
+// single path element (e.g. "*.go" or "node_modules"); a pattern with a
+// slash matches the whole path relative to the watch root.
+type Matcher struct {
+	// Include limits matches to these patterns; empty includes everything.
+	Include []string
+	// Exclude removes matches, and excluded directories are not watched.
+	Exclude []string
+	// NoDefaultExcludes stops DefaultExcludes from applying, for callers
+	// that select files rather than watch them.
+	NoDefaultExcludes bool
+}
+
+// Match reports whether rel, a path relative to the watch root, is selected.
+func (m Matcher) Match(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	if m.Excluded(rel) {
+		return false
+	}
+	if len(m.Include) == 0 {
+		return true
+	}
+	for _, pattern := range m.Include {
+		if matchPattern(pattern, rel, false) {
+			return true
+		}
+	}
+	return false
+}
+
+// Excluded reports whether rel or any of its parent directories is excluded.
+func (m Matcher) Excluded(rel string) bool {
+	rel = filepath.ToSlash(rel)
+	patterns := m.Exclude
+	if !m.NoDefaultExcludes {
+		patterns = append(DefaultExcludes[:len(DefaultExcludes):len(DefaultExcludes)], m.Exclude...)
+	}
+	for _, pattern := range patterns {
+		if matchPattern(pattern, rel, true) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPattern matches a slash-separated relative path. With prefix set, a
+// match of any leading directory also counts, so excluding "build" or
+// "build/**" excludes everything below it.
+func matchPattern(pattern, rel string, prefix bool) bool {
+	pattern = filepath.ToSlash(pattern)
+	segments := strings.Split(rel, "/")
+
+	if !strings.Contains(pattern, "/") {
+		last := len(segments) - 1
+		for i, segment := range segments {
+			if i < last && !prefix {
+				continue
+			}
+			if ok, _ := path.Match(pattern, segment); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	patterns := strings.Split(strings.TrimPrefix(pattern, "./"), "/")
+	if prefix {
+		for i := 1; i <= len(segments); i++ {
+			if matchSegments(patterns, segments[:i]) {
+				return true
+			}
+		}
+		return false
+	}
+	return matchSegments(patterns, segments)
+}
+
+// matchSegments matches path elements against pattern elements, where a
+// "**" element matches zero or more path elements.
+func matchSegments(patterns, segments []string) bool {
+	for len(patterns) > 0 {
+		if patterns[0] == "**" {
+			rest := patterns[1:]
+			for i := 0; i <= len(segments); i++ {
+				if matchSegments(rest, segments[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(segments) == 0 {
+			return false
+		}
+		if ok, _ := path.Match(patterns[0], segments[0]); !ok {
+			return false
+		}
+		patterns, segments = patterns[1:], segments[1:]
+	}
+	return len(segments) == 0
+}
@@ -0,0 +1,64 @@
+package watch
+
+import "testing"
+
+func TestMatcher_Match(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher Matcher
+		path    string
+		want    bool
+	}{
+		{"everything by default", Matcher{}, "main.go", true},
+		{"basename include", Matcher{Include: []string{"*.go"}}, "internal/watch/watch.go", true},
+		{"basename include miss", Matcher{Include: []string{"*.go"}}, "README.md", false},
+		{"basename include ignores dirs", Matcher{Include: []string{"*.go"}}, "x.go/README.md", false},
+		{"path include", Matcher{Include: []string{"cmd/*.go"}}, "cmd/main.go", true},
+		{"path include depth", Matcher{Include: []string{"cmd/*.go"}}, "cmd/ado/main.go", false},
+		{"double star", Matcher{Include: []string{"cmd/**/*.go"}}, "cmd/ado/run/run.go", true},
+		{"double star zero dirs", Matcher{Include: []string{"cmd/**/*.go"}}, "cmd/main.go", true},
+		{"leading double star", Matcher{Include: []string{"**/testdata/*"}}, "a/b/testdata/x.txt", true},
+		{"exclude wins", Matcher{Include: []string{"*.go"}, Exclude: []string{"*_test.go"}}, "a/x_test.go", false},
+		{"exclude dir by name", Matcher{Exclude: []string{"dist"}}, "dist/ado", false},
+		{"exclude dir by path", Matcher{Exclude: []string{"build/**"}}, "build/out/bin", false},
+		{"exclude dir prefix", Matcher{Exclude: []string{"docs/site"}}, "docs/site/index.html", false},
+		{"default git", Matcher{}, ".git/HEAD", false},
+		{"default node_modules", Matcher{}, "web/node_modules/x/index.js", false},
+		{"default swap", Matcher{}, "main.go.swp", false},
+		{"default backup", Matcher{}, "main.go~", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.matcher.Match(tt.path); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatcher_Excluded(t *testing.T) {
+	m := Matcher{Exclude: []string{"vendor"}}
+	for path, want := range map[string]bool{
+		"vendor":     true,
+		"a/vendor":   true,
+		"vendor2":    false,
+		".git":       true,
+		"src":        false,
+		"src/vendor": true,
+	} {
+		if got := m.Excluded(path); got != want {
+			t.Errorf("Excluded(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func TestMatcher_NoDefaultExcludes(t *testing.T) {
+	m := Matcher{Exclude: []string{"vendor"}, NoDefaultExcludes: true}
+	if m.Excluded(".git/config") {
+		t.Error("Excluded(.git/config) = true with NoDefaultExcludes")
+	}
+	if !m.Excluded("vendor/x") {
+		t.Error("Excluded(vendor/x) = false, want explicit excludes to apply")
+	}
+}
@@ -0,0 +1,215 @@
+// Package watch reports batches of file changes below a set of
+// directories, filtered by glob and debounced.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDebounce is how long the watcher waits for changes to settle
+// before reporting them.
+const DefaultDebounce = 200 * time.Millisecond
+
+// Change describes one changed path within a batch.
+type Change struct {
+	Path string `json:"path" yaml:"path"`
+	// Op is the operation that changed the path: create, write, remove,
+	// rename, or chmod.
+	Op string `json:"op" yaml:"op"`
+}
+
+// Watcher watches directory trees recursively.
+type Watcher struct {
+	// Roots are the directories to watch; new subdirectories are picked up
+	// as they are created. A root may also be a single file.
+	Roots    []string
+	Matcher  Matcher
+	Debounce time.Duration
+}
+
+// root is a watched root: a directory tree, or a single file, watched
+// through the directory holding it.
+type root struct {
+	dir string
+	// file is the name of a single-file root in dir.
+	file string
+}
+
+// Run watches until ctx is done, calling onChange with each debounced
+// batch of matching changes (one entry per path, in first-seen order).
+// onChange runs on the watch goroutine, so it should return promptly.
+func (w *Watcher) Run(ctx context.Context, onChange func([]Change)) error {
+	fsw, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("create watcher: %w", err)
+	}
+	defer fsw.Close()
+
+	roots := make([]root, 0, len(w.Roots))
+	for _, path := range w.Roots {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return fmt.Errorf("resolve %s: %w", path, err)
+		}
+		info, err := os.Stat(abs)
+		if err != nil {
+			return fmt.Errorf("watch %s: %w", abs, err)
+		}
+		if !info.IsDir() {
+			r := root{dir: filepath.Dir(abs), file: filepath.Base(abs)}
+			if err := fsw.Add(r.dir); err != nil {
+				return fmt.Errorf("watch %s: %w", abs, err)
+			}
+			roots = append(roots, r)
+			continue
+		}
+		if err := w.addTree(ctx, fsw, abs, abs, nil); err != nil {
+			return err
+		}
+		roots = append(roots, root{dir: abs})
+	}
+
+	debounce := w.Debounce
+	if debounce <= 0 {
+		debounce = DefaultDebounce
+	}
+	timer := time.NewTimer(debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	var pending []Change
+	seen := map[string]int{}
+	record := func(r root, rel, op string) {
+		if !w.Matcher.Match(rel) {
+			return
+		}
+		change := Change{Path: filepath.ToSlash(rel), Op: op}
+		if len(roots) > 1 && r.file == "" {
+			change.Path = filepath.ToSlash(filepath.Join(filepath.Base(r.dir), rel))
+		}
+		if i, ok := seen[change.Path]; ok {
+			// A file created in this batch stays "create" while it is
+			// written; removal or rename replaces the earlier op.
+			if pending[i].Op != "create" || (change.Op != "write" && change.Op != "chmod") {
+				pending[i].Op = change.Op
+			}
+		} else {
+			seen[change.Path] = len(pending)
+			pending = append(pending, change)
+		}
+		timer.Reset(debounce)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case err, ok := <-fsw.Errors:
+			if !ok {
+				return nil
+			}
+			slog.WarnContext(ctx, "File watcher error", "error", err)
+
+		case event, ok := <-fsw.Events:
+			if !ok {
+				return nil
+			}
+			r, rel := relativeTo(roots, event.Name)
+			if rel == "" || w.Matcher.Excluded(rel) {
+				continue
+			}
+			if event.Has(fsnotify.Create) && r.file == "" {
+				if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+					// Files can land in the directory before it is
+					// watched; report the ones already there as created.
+					err := w.addTree(ctx, fsw, r.dir, event.Name, func(path string) {
+						if rel, err := filepath.Rel(r.dir, path); err == nil && !w.Matcher.Excluded(rel) {
+							record(r, rel, "create")
+						}
+					})
+					if err != nil {
+						slog.WarnContext(ctx, "Cannot watch new directory", "path", event.Name, "error", err)
+					}
+					continue
+				}
+			}
+			record(r, rel, opName(event.Op))
+
+		case <-timer.C:
+			if len(pending) > 0 {
+				onChange(pending)
+				pending, seen = nil, map[string]int{}
+			}
+		}
+	}
+}
+
+// addTree watches dir and every non-excluded directory below it, calling
+// found, when set, with each file in them.
+func (w *Watcher) addTree(ctx context.Context, fsw *fsnotify.Watcher, root, dir string, found func(path string)) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == dir {
+				return fmt.Errorf("watch %s: %w", path, err)
+			}
+			slog.DebugContext(ctx, "Skipping unreadable path", "path", path, "error", err)
+			return nil
+		}
+		if !d.IsDir() {
+			if found != nil {
+				found(path)
+			}
+			return nil
+		}
+		if rel, _ := filepath.Rel(root, path); rel != "." && w.Matcher.Excluded(rel) {
+			return filepath.SkipDir
+		}
+		if err := fsw.Add(path); err != nil {
+			return fmt.Errorf("watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// relativeTo returns the root containing name and name relative to its
+// directory. Of a single-file root's directory, only the file counts.
+func relativeTo(roots []root, name string) (root, string) {
+	for _, r := range roots {
+		if r.file != "" {
+			if filepath.Dir(name) == r.dir && filepath.Base(name) == r.file {
+				return r, r.file
+			}
+			continue
+		}
+		rel, err := filepath.Rel(r.dir, name)
+		if err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+			return r, rel
+		}
+	}
+	return root{}, ""
+}
+
+func opName(op fsnotify.Op) string {
+	switch {
+	case op.Has(fsnotify.Create):
+		return "create"
+	case op.Has(fsnotify.Remove):
+		return "remove"
+	case op.Has(fsnotify.Rename):
+		return "rename"
+	case op.Has(fsnotify.Write):
+		return "write"
+	default:
+		return "chmod"
+	}
+}
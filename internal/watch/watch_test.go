@@ -0,0 +1,176 @@
+package watch
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// startWatcher runs w in the background and returns a channel of batches.
+func startWatcher(t *testing.T, w *Watcher) <-chan []Change {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	batches := make(chan []Change, 10)
+	done := make(chan error, 1)
+	go func() {
+		done <- w.Run(ctx, func(c []Change) { batches <- c })
+	}()
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	})
+	// Give the watcher time to register its directories.
+	time.Sleep(100 * time.Millisecond)
+	return batches
+}
+
+func waitBatch(t *testing.T, batches <-chan []Change) []Change {
+	t.Helper()
+	select {
+	case b := <-batches:
+		return b
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for changes")
+		return nil
+	}
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatcher_DebouncesAndFilters(t *testing.T) {
+	root := t.TempDir()
+	w := &Watcher{Roots: []string{root}, Matcher: Matcher{Include: []string{"*.go"}}, Debounce: 100 * time.Millisecond}
+	batches := startWatcher(t, w)
+
+	writeFile(t, filepath.Join(root, "notes.txt"), "ignored")
+	writeFile(t, filepath.Join(root, "a.go"), "package a")
+	writeFile(t, filepath.Join(root, "a.go"), "package a // again")
+	writeFile(t, filepath.Join(root, "b.go"), "package b")
+
+	got := waitBatch(t, batches)
+	var paths []string
+	for _, c := range got {
+		paths = append(paths, c.Path)
+	}
+	if want := []string{"a.go", "b.go"}; !reflect.DeepEqual(paths, want) {
+		t.Errorf("batch paths = %v, want %v (%+v)", paths, want, got)
+	}
+}
+
+func TestWatcher_NewDirectories(t *testing.T) {
+	root := t.TempDir()
+	w := &Watcher{Roots: []string{root}, Debounce: 50 * time.Millisecond}
+	batches := startWatcher(t, w)
+
+	sub := filepath.Join(root, "pkg")
+	if err := os.Mkdir(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(100 * time.Millisecond)
+	writeFile(t, filepath.Join(sub, "x.go"), "package x")
+
+	for {
+		batch := waitBatch(t, batches)
+		for _, c := range batch {
+			if c.Path == "pkg/x.go" {
+				return
+			}
+		}
+	}
+}
+
+func TestWatcher_NewDirectoryWithFiles(t *testing.T) {
+	root := t.TempDir()
+	w := &Watcher{Roots: []string{root}, Matcher: Matcher{Include: []string{"*.go"}}, Debounce: 50 * time.Millisecond}
+	batches := startWatcher(t, w)
+
+	// A tree moved in arrives with its files before it can be watched.
+	staged := filepath.Join(t.TempDir(), "pkg")
+	if err := os.MkdirAll(filepath.Join(staged, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(staged, "a.go"), "package a")
+	writeFile(t, filepath.Join(staged, "notes.txt"), "ignored")
+	writeFile(t, filepath.Join(staged, "sub", "b.go"), "package b")
+	if err := os.Rename(staged, filepath.Join(root, "pkg")); err != nil {
+		t.Fatal(err)
+	}
+
+	got := waitBatch(t, batches)
+	want := []Change{{Path: "pkg/a.go", Op: "create"}, {Path: "pkg/sub/b.go", Op: "create"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("batch = %+v, want %+v", got, want)
+	}
+}
+
+func TestWatcher_FileRoot(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "go.mod")
+	writeFile(t, path, "module a")
+	w := &Watcher{Roots: []string{path}, Debounce: 50 * time.Millisecond}
+	batches := startWatcher(t, w)
+
+	writeFile(t, filepath.Join(dir, "go.sum"), "ignored")
+	writeFile(t, path, "module b")
+
+	got := waitBatch(t, batches)
+	if want := []Change{{Path: "go.mod", Op: "write"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("batch = %+v, want %+v", got, want)
+	}
+}
+
+func TestWatcher_SkipsExcludedDirectories(t *testing.T) {
+	root := t.TempDir()
+	if err := os.Mkdir(filepath.Join(root, "dist"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	w := &Watcher{Roots: []string{root}, Matcher: Matcher{Exclude: []string{"dist"}}, Debounce: 50 * time.Millisecond}
+	batches := startWatcher(t, w)
+
+	writeFile(t, filepath.Join(root, "dist", "ado"), "binary")
+	writeFile(t, filepath.Join(root, "main.go"), "package main")
+
+	got := waitBatch(t, batches)
+	if len(got) != 1 || got[0].Path != "main.go" || got[0].Op != "create" {
+		t.Errorf("batch = %+v, want only main.go create", got)
+	}
+}
+
+func TestWatcher_MissingRoot(t *testing.T) {
+	w := &Watcher{Roots: []string{filepath.Join(t.TempDir(), "missing")}}
+	if err := w.Run(context.Background(), func([]Change) {}); err == nil {
+		t.Error("Run() expected error for missing root")
+	}
+}
+
+func TestEventWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewEventWriter(&buf)
+	w.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	code := 0
+	if err := w.Write(Event{Event: EventChange, Changes: []Change{{Path: "a.go", Op: "write"}}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Write(Event{Event: EventExit, ExitCode: &code, DurationMS: 12}); err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"time":"2026-01-02T03:04:05Z","event":"change","changes":[{"path":"a.go","op":"write"}]}
+{"time":"2026-01-02T03:04:05Z","event":"exit","exit_code":0,"duration_ms":12}
+`
+	if buf.String() != want {
+		t.Errorf("output =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
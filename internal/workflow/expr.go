@@ -0,0 +1,462 @@
+package workflow
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expressions are a small language for if: conditions and ${{ }}
+// interpolation:
+//
+//	steps.ID.outputs.NAME   steps.ID.outcome   steps.ID.exit_code   env.NAME
+//	'string'  "string"  123  true  false
+//	success()  failure()  always()  contains(a, b)  startsWith(a, b)
+//	a == b   a != b   !a   a && b   a || b   ( ... )
+//
+// Values are strings or booleans. Comparisons are on the string forms.
+// Empty strings, "false", and false are falsy; everything else is truthy.
+
+// expr is a parsed expression.
+type expr interface {
+	eval(s *scope) any
+}
+
+// scope is what expressions are evaluated against.
+type scope struct {
+	steps  map[string]*StepResult
+	env    func(string) string
+	failed bool
+}
+
+type literal struct{ v any }
+
+func (l literal) eval(*scope) any { return l.v }
+
+// ref is a dotted reference such as steps.build.outputs.version.
+type ref struct{ path []string }
+
+func (r ref) eval(s *scope) any {
+	if r.path[0] == "env" {
+		return s.env(r.path[1])
+	}
+	step := s.steps[r.path[1]]
+	if step == nil {
+		return ""
+	}
+	switch r.path[2] {
+	case "outcome":
+		return step.Status
+	case "exit_code":
+		return fmt.Sprint(step.ExitCode)
+	}
+	return step.Outputs[r.path[3]]
+}
+
+type call struct {
+	name string
+	args []expr
+}
+
+func (c call) eval(s *scope) any {
+	switch c.name {
+	case "success":
+		return !s.failed
+	case "failure":
+		return s.failed
+	case "always":
+		return true
+	case "contains":
+		return strings.Contains(toString(c.args[0].eval(s)), toString(c.args[1].eval(s)))
+	}
+	return strings.HasPrefix(toString(c.args[0].eval(s)), toString(c.args[1].eval(s)))
+}
+
+// functions maps each function to its number of arguments.
+var functions = map[string]int{
+	"success":    0,
+	"failure":    0,
+	"always":     0,
+	"contains":   2,
+	"startsWith": 2,
+}
+
+// statusFunctions decide on their own whether a step runs after a failure.
+var statusFunctions = []string{"success", "failure", "always"}
+
+type not struct{ x expr }
+
+func (n not) eval(s *scope) any { return !truthy(n.x.eval(s)) }
+
+type binary struct {
+	op   string
+	l, r expr
+}
+
+func (b binary) eval(s *scope) any {
+	switch b.op {
+	case "&&":
+		return truthy(b.l.eval(s)) && truthy(b.r.eval(s))
+	case "||":
+		return truthy(b.l.eval(s)) || truthy(b.r.eval(s))
+	case "==":
+		return toString(b.l.eval(s)) == toString(b.r.eval(s))
+	}
+	return toString(b.l.eval(s)) != toString(b.r.eval(s))
+}
+
+func truthy(v any) bool {
+	switch v := v.(type) {
+	case bool:
+		return v
+	case string:
+		return v != "" && v != "false"
+	}
+	return false
+}
+
+func toString(v any) string {
+	if b, ok := v.(bool); ok {
+		if b {
+			return "true"
+		}
+		return "false"
+	}
+	s, _ := v.(string)
+	return s
+}
+
+// usesStatus reports whether e calls one of the status functions.
+func usesStatus(e expr) bool {
+	switch e := e.(type) {
+	case call:
+		for _, name := range statusFunctions {
+			if e.name == name {
+				return true
+			}
+		}
+		for _, arg := range e.args {
+			if usesStatus(arg) {
+				return true
+			}
+		}
+	case not:
+		return usesStatus(e.x)
+	case binary:
+		return usesStatus(e.l) || usesStatus(e.r)
+	}
+	return false
+}
+
+// parser parses one expression. known lists the step IDs a reference may
+// name; nil allows any.
+type parser struct {
+	tokens []string
+	pos    int
+	known  map[string]bool
+}
+
+// parseExpr parses src, checking that step references name known steps.
+func parseExpr(src string, known map[string]bool) (expr, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, errors.New("empty expression")
+	}
+	p := &parser{tokens: tokens, known: known}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+	return e, nil
+}
+
+func (p *parser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *parser) next() string {
+	tok := p.peek()
+	p.pos++
+	return tok
+}
+
+func (p *parser) expect(tok string) error {
+	if got := p.next(); got != tok {
+		if got == "" {
+			return fmt.Errorf("expected %q at end of expression", tok)
+		}
+		return fmt.Errorf("expected %q, got %q", tok, got)
+	}
+	return nil
+}
+
+func (p *parser) or() (expr, error) {
+	return p.binaryLevel("||", p.and)
+}
+
+func (p *parser) and() (expr, error) {
+	return p.binaryLevel("&&", p.unary)
+}
+
+func (p *parser) binaryLevel(op string, operand func() (expr, error)) (expr, error) {
+	l, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == op {
+		p.next()
+		r, err := operand()
+		if err != nil {
+			return nil, err
+		}
+		l = binary{op: op, l: l, r: r}
+	}
+	return l, nil
+}
+
+func (p *parser) unary() (expr, error) {
+	if p.peek() == "!" {
+		p.next()
+		x, err := p.unary()
+		if err != nil {
+			return nil, err
+		}
+		return not{x}, nil
+	}
+	l, err := p.primary()
+	if err != nil {
+		return nil, err
+	}
+	if op := p.peek(); op == "==" || op == "!=" {
+		p.next()
+		r, err := p.primary()
+		if err != nil {
+			return nil, err
+		}
+		return binary{op: op, l: l, r: r}, nil
+	}
+	return l, nil
+}
+
+func (p *parser) primary() (expr, error) {
+	tok := p.next()
+	switch {
+	case tok == "":
+		return nil, errors.New("unexpected end of expression")
+	case tok == "(":
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return e, p.expect(")")
+	case tok[0] == '\'' || tok[0] == '"':
+		return literal{tok[1:]}, nil
+	case tok == "true" || tok == "false":
+		return literal{tok == "true"}, nil
+	case unicode.IsDigit(rune(tok[0])):
+		return literal{tok}, nil
+	case !isIdentStart(rune(tok[0])):
+		return nil, fmt.Errorf("unexpected %q", tok)
+	}
+
+	if p.peek() == "(" {
+		return p.call(tok)
+	}
+	path := []string{tok}
+	for p.peek() == "." {
+		p.next()
+		seg := p.next()
+		if seg == "" || !isIdentStart(rune(seg[0])) {
+			return nil, fmt.Errorf("invalid reference %s.%s", strings.Join(path, "."), seg)
+		}
+		path = append(path, seg)
+	}
+	return p.ref(path)
+}
+
+func (p *parser) call(name string) (expr, error) {
+	arity, ok := functions[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown function %s()", name)
+	}
+	p.next() // (
+	var args []expr
+	for p.peek() != ")" {
+		if len(args) > 0 {
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	p.next() // )
+	if len(args) != arity {
+		return nil, fmt.Errorf("%s() takes %d arguments, got %d", name, arity, len(args))
+	}
+	return call{name: name, args: args}, nil
+}
+
+func (p *parser) ref(path []string) (expr, error) {
+	name := strings.Join(path, ".")
+	switch path[0] {
+	case "env":
+		if len(path) != 2 {
+			return nil, fmt.Errorf("invalid reference %s: use env.NAME", name)
+		}
+		return ref{path}, nil
+	case "steps":
+		if len(path) < 3 {
+			return nil, fmt.Errorf("invalid reference %s: use steps.ID.outputs.NAME, steps.ID.outcome, or steps.ID.exit_code", name)
+		}
+		if p.known != nil && !p.known[path[1]] {
+			return nil, fmt.Errorf("%s refers to unknown or later step %q", name, path[1])
+		}
+		switch {
+		case len(path) == 3 && (path[2] == "outcome" || path[2] == "exit_code"):
+			return ref{path}, nil
+		case len(path) == 4 && path[2] == "outputs":
+			return ref{path}, nil
+		}
+		return nil, fmt.Errorf("invalid reference %s: use steps.ID.outputs.NAME, steps.ID.outcome, or steps.ID.exit_code", name)
+	}
+	return nil, fmt.Errorf("unknown name %q (expected steps or env)", path[0])
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || unicode.IsLetter(r)
+}
+
+func isIdent(r rune) bool {
+	return r == '_' || r == '-' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// tokenize splits src into tokens. String tokens keep their opening quote
+// as a marker; inside a string, a doubled quote is a literal quote.
+func tokenize(src string) ([]string, error) {
+	var tokens []string
+	runes := []rune(src)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '\'' || r == '"':
+			var b strings.Builder
+			b.WriteRune(r)
+			j := i + 1
+			for ; j < len(runes); j++ {
+				if runes[j] == r {
+					// A doubled quote is an escaped quote.
+					if j+1 < len(runes) && runes[j+1] == r {
+						b.WriteRune(r)
+						j++
+						continue
+					}
+					break
+				}
+				b.WriteRune(runes[j])
+			}
+			if j >= len(runes) {
+				return nil, fmt.Errorf("unterminated string starting at offset %d", i)
+			}
+			tokens = append(tokens, b.String())
+			i = j + 1
+		case isIdent(r):
+			j := i
+			for j < len(runes) && isIdent(runes[j]) {
+				j++
+			}
+			tokens = append(tokens, string(runes[i:j]))
+			i = j
+		default:
+			two := ""
+			if i+1 < len(runes) {
+				two = string(runes[i : i+2])
+			}
+			switch {
+			case two == "==" || two == "!=" || two == "&&" || two == "||":
+				tokens = append(tokens, two)
+				i += 2
+			case strings.ContainsRune("!().,", r):
+				tokens = append(tokens, string(r))
+				i++
+			default:
+				return nil, fmt.Errorf("unexpected character %q", r)
+			}
+		}
+	}
+	return tokens, nil
+}
+
+// template is a string with ${{ expression }} placeholders.
+type template struct {
+	// literals surround exprs: literals[i] precedes exprs[i], and there is
+	// one more literal than expressions.
+	literals []string
+	exprs    []expr
+}
+
+// parseTemplate parses the ${{ }} placeholders in src.
+func parseTemplate(src string, known map[string]bool) (template, error) {
+	var t template
+	rest := src
+	for {
+		start := strings.Index(rest, "${{")
+		if start < 0 {
+			t.literals = append(t.literals, rest)
+			return t, nil
+		}
+		end := strings.Index(rest[start:], "}}")
+		if end < 0 {
+			return template{}, fmt.Errorf("unterminated ${{ in %q", src)
+		}
+		e, err := parseExpr(rest[start+3:start+end], known)
+		if err != nil {
+			return template{}, fmt.Errorf("${{%s}}: %w", rest[start+3:start+end], err)
+		}
+		t.literals = append(t.literals, rest[:start])
+		t.exprs = append(t.exprs, e)
+		rest = rest[start+end+2:]
+	}
+}
+
+func (t template) render(s *scope) string {
+	var b strings.Builder
+	for i, lit := range t.literals {
+		b.WriteString(lit)
+		if i < len(t.exprs) {
+			b.WriteString(toString(t.exprs[i].eval(s)))
+		}
+	}
+	return b.String()
+}
+
+// renderShell renders t as a shell script in which each placeholder is a
+// reference to a variable, added to vars, holding its value.
+func (t template) renderShell(s *scope, vars map[string]string) string {
+	var b strings.Builder
+	for i, lit := range t.literals {
+		b.WriteString(lit)
+		if i < len(t.exprs) {
+			name := ExprEnvPrefix + strconv.Itoa(i+1)
+			vars[name] = toString(t.exprs[i].eval(s))
+			b.WriteString("${" + name + "}")
+		}
+	}
+	return b.String()
+}
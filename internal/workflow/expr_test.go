@@ -0,0 +1,146 @@
+package workflow
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseExpr_Eval(t *testing.T) {
+	sc := &scope{
+		steps: map[string]*StepResult{
+			"build": {Status: StatusSuccess, ExitCode: 0, Outputs: map[string]string{"version": "1.2.3"}},
+			"lint":  {Status: StatusFailure, ExitCode: 2},
+		},
+		env: func(name string) string {
+			if name == "CI" {
+				return "true"
+			}
+			return ""
+		},
+	}
+	known := map[string]bool{"build": true, "lint": true}
+
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"success()", true},
+		{"failure()", false},
+		{"always()", true},
+		{"true", true},
+		{"false", false},
+		{"''", false},
+		{"'false'", false},
+		{"steps.build.outputs.version == '1.2.3'", true},
+		{"steps.build.outputs.version != \"1.2.3\"", false},
+		{"steps.build.outputs.missing", false},
+		{"steps.lint.outcome == 'failure'", true},
+		{"steps.lint.exit_code == 2", true},
+		{"env.CI", true},
+		{"env.HOME", false},
+		{"!env.CI", false},
+		{"env.CI && steps.lint.outcome == 'success'", false},
+		{"env.CI == 'false' || steps.build.outcome == 'success'", true},
+		{"!(env.CI && false)", true},
+		{"contains(steps.build.outputs.version, '.2.')", true},
+		{"startsWith(steps.build.outputs.version, '2')", false},
+		{"'it''s' == \"it's\"", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			e, err := parseExpr(tt.src, known)
+			if err != nil {
+				t.Fatalf("parseExpr() error = %v", err)
+			}
+			if got := truthy(e.eval(sc)); got != tt.want {
+				t.Errorf("eval = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExpr_Errors(t *testing.T) {
+	known := map[string]bool{"build": true}
+	tests := []struct {
+		src  string
+		want string
+	}{
+		{"", "empty expression"},
+		{"steps.later.outcome", `unknown or later step "later"`},
+		{"steps.build", "invalid reference steps.build"},
+		{"steps.build.outputs", "invalid reference"},
+		{"steps.build.status", "invalid reference"},
+		{"env", "use env.NAME"},
+		{"vars.x", `unknown name "vars"`},
+		{"nope()", "unknown function nope()"},
+		{"contains('a')", "takes 2 arguments, got 1"},
+		{"'open", "unterminated string"},
+		{"a = b", "unexpected character '='"},
+		{"success() success()", `unexpected "success"`},
+		{"(true", `expected ")" at end of expression`},
+		{"true &&", "unexpected end of expression"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			_, err := parseExpr(tt.src, known)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("parseExpr(%q) error = %v, want %q", tt.src, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsesStatus(t *testing.T) {
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{"env.CI", false},
+		{"contains(env.A, 'x')", false},
+		{"always()", true},
+		{"failure() && env.CI", true},
+		{"!success()", true},
+	}
+	for _, tt := range tests {
+		e, err := parseExpr(tt.src, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := usesStatus(e); got != tt.want {
+			t.Errorf("usesStatus(%q) = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestTemplate(t *testing.T) {
+	sc := &scope{
+		steps: map[string]*StepResult{"v": {Outputs: map[string]string{"tag": "v1"}}},
+		env:   func(string) string { return "prod" },
+	}
+	known := map[string]bool{"v": true}
+
+	tests := []struct {
+		src, want string
+	}{
+		{"plain", "plain"},
+		{"", ""},
+		{"deploy ${{ steps.v.outputs.tag }} to ${{env.TARGET}}", "deploy v1 to prod"},
+		{"${{ steps.v.outcome == '' }}", "true"},
+	}
+	for _, tt := range tests {
+		tmpl, err := parseTemplate(tt.src, known)
+		if err != nil {
+			t.Fatalf("parseTemplate(%q) error = %v", tt.src, err)
+		}
+		if got := tmpl.render(sc); got != tt.want {
+			t.Errorf("render(%q) = %q, want %q", tt.src, got, tt.want)
+		}
+	}
+
+	if _, err := parseTemplate("echo ${{ env.X", known); err == nil || !strings.Contains(err.Error(), "unterminated ${{") {
+		t.Errorf("unterminated template error = %v", err)
+	}
+	if _, err := parseTemplate("${{ steps.w.outcome }}", known); err == nil {
+		t.Error("expected error for unknown step in template")
+	}
+}
@@ -0,0 +1,445 @@
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/process"
+)
+
+// Kinds of issue Lint reports besides the config file ones it shares
+// with ado config validate.
+const (
+	RuleStep            = "step"
+	RuleDuplicateID     = "duplicate-id"
+	RuleUndefinedStep   = "undefined-step"
+	RuleUnreachableStep = "unreachable-step"
+)
+
+// LintRules lists every kind of issue Lint reports.
+var LintRules = []config.ValidationRule{
+	{ID: config.RuleFileNotFound, Description: "The workflow file does not exist", Severity: "error"},
+	{ID: config.RulePermissionDenied, Description: "The workflow file cannot be read", Severity: "error"},
+	{ID: config.RuleEmptyFile, Description: "The workflow file is empty", Severity: "error"},
+	{ID: config.RuleYAMLSyntax, Description: "The workflow file is not valid YAML", Severity: "error"},
+	{ID: config.RuleUnknownKey, Description: "A key is not part of the workflow schema", Severity: "error"},
+	{ID: config.RuleInvalidStructure, Description: "A value has the wrong type for its key", Severity: "error"},
+	{ID: RuleStep, Description: "The workflow has no steps, or a step is incomplete or has an invalid id, timeout, or expression", Severity: "error"},
+	{ID: RuleDuplicateID, Description: "Two steps have the same id", Severity: "error"},
+	{ID: RuleUndefinedStep, Description: "An expression refers to a step that is not defined or does not run before it", Severity: "error"},
+	{ID: RuleUnreachableStep, Description: "A step's if: condition is never true", Severity: "warning"},
+	{ID: config.RuleShellInterpolation, Description: "A ${{ }} value is interpolated into the script of a shell command step, where it can inject commands", Severity: "warning"},
+}
+
+// knownKeys lists the keys of a workflow, and knownStepKeys those of a
+// step.
+var (
+	knownKeys     = map[string]bool{"name": true, "shell": true, "env": true, "steps": true}
+	knownStepKeys = map[string]bool{
+		"id": true, "name": true, "run": true, "command": true, "args": true, "env": true,
+		"cwd": true, "timeout": true, "if": true, "continue-on-error": true,
+	}
+)
+
+// Lint checks the workflow file at path without running it. Unlike Load,
+// which stops at the first problem, it reports every one with its line:
+// unknown keys, invalid steps, references to undefined or later steps,
+// steps whose if: condition is never true, and step outputs and
+// environment variables interpolated into shell scripts.
+func Lint(path string) (*config.ValidationResult, error) {
+	l := &linter{result: &config.ValidationResult{
+		Path:     path,
+		Valid:    true,
+		Errors:   []config.ValidationIssue{},
+		Warnings: []config.ValidationIssue{},
+	}}
+	data, err := os.ReadFile(path)
+	switch {
+	case os.IsNotExist(err):
+		l.add("error", config.RuleFileNotFound, fmt.Sprintf("workflow file not found: %q", path), 0)
+	case os.IsPermission(err):
+		l.add("error", config.RulePermissionDenied, fmt.Sprintf("permission denied: %q", path), 0)
+	case err != nil:
+		return nil, fmt.Errorf("read workflow: %w", err)
+	default:
+		l.lint(data)
+	}
+	return l.result, nil
+}
+
+// linter collects the issues of one workflow file.
+type linter struct {
+	result *config.ValidationResult
+}
+
+func (l *linter) add(severity, rule, message string, line int) {
+	issue := config.ValidationIssue{Message: message, Line: line, Severity: severity, Rule: rule}
+	if severity == "error" {
+		l.result.Valid = false
+		l.result.Errors = append(l.result.Errors, issue)
+	} else {
+		l.result.Warnings = append(l.result.Warnings, issue)
+	}
+}
+
+// lintedStep is a step that decoded, with its node for lines.
+type lintedStep struct {
+	Step
+	node  *yaml.Node
+	where string
+	// valid is set when the step passed Step.validate.
+	valid bool
+}
+
+func (l *linter) lint(data []byte) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		l.add("error", config.RuleEmptyFile, "workflow is empty", 0)
+		return
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		line := 0
+		if m := syntaxErrorLine.FindStringSubmatch(err.Error()); m != nil {
+			line, _ = strconv.Atoi(m[1])
+		}
+		l.add("error", config.RuleYAMLSyntax, fmt.Sprintf("invalid YAML: %s", err), line)
+		return
+	}
+	if len(doc.Content) == 0 {
+		l.add("error", config.RuleEmptyFile, "workflow is empty", 0)
+		return
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		l.add("error", config.RuleInvalidStructure, "a workflow must be a mapping with a steps key", root.Line)
+		return
+	}
+
+	var top struct {
+		Name  string            `yaml:"name"`
+		Shell string            `yaml:"shell"`
+		Env   map[string]string `yaml:"env"`
+	}
+	l.decode(root, &top)
+	var stepsNode *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		switch key := root.Content[i]; {
+		case !knownKeys[key.Value]:
+			l.add("error", config.RuleUnknownKey, fmt.Sprintf("unknown key %q", key.Value), key.Line)
+		case key.Value == "steps":
+			stepsNode = root.Content[i+1]
+		}
+	}
+
+	switch {
+	case stepsNode == nil:
+		l.add("error", RuleStep, "workflow has no steps", 0)
+		return
+	case stepsNode.Kind != yaml.SequenceNode:
+		l.add("error", config.RuleInvalidStructure, "steps must be a list", stepsNode.Line)
+		return
+	case len(stepsNode.Content) == 0:
+		l.add("error", RuleStep, "workflow has no steps", stepsNode.Line)
+		return
+	}
+
+	var steps []lintedStep
+	ids := map[string]int{}
+	for i, node := range stepsNode.Content {
+		s := lintedStep{node: node, where: fmt.Sprintf("step %d", i+1)}
+		if node.Kind == yaml.MappingNode {
+			if id := mappingValue(node, "id"); id != nil && id.Kind == yaml.ScalarNode && id.Value != "" {
+				s.where = fmt.Sprintf("step %q", id.Value)
+			}
+			for j := 0; j+1 < len(node.Content); j += 2 {
+				if key := node.Content[j]; !knownStepKeys[key.Value] {
+					l.add("error", config.RuleUnknownKey, fmt.Sprintf("%s: unknown key %q", s.where, key.Value), key.Line)
+				}
+			}
+		}
+		if !l.decode(node, &s.Step) {
+			continue
+		}
+		if err := s.validate(nil); err != nil {
+			l.add("error", RuleStep, fmt.Sprintf("%s: %v", s.where, err), node.Line)
+		} else {
+			s.valid = true
+		}
+		if s.ID != "" && idPattern.MatchString(s.ID) {
+			if first, ok := ids[s.ID]; ok {
+				l.add("error", RuleDuplicateID, fmt.Sprintf("%s: duplicate id %q (first used at line %d)", s.where, s.ID, first), fieldNode(node, "id").Line)
+			} else {
+				ids[s.ID] = fieldNode(node, "id").Line
+			}
+		}
+		steps = append(steps, s)
+	}
+
+	earlier := map[string]bool{}
+	canFail := false
+	for i, s := range steps {
+		if s.valid {
+			l.lintReferences(s, earlier, ids)
+			l.lintReachable(s, i, canFail)
+			l.lintInterpolation(s)
+		}
+		if s.ID != "" {
+			earlier[s.ID] = true
+		}
+		if !s.ContinueOnError {
+			canFail = true
+		}
+	}
+}
+
+// typeErrorLine and syntaxErrorLine match the lines in yaml.v3 decoding
+// and syntax errors.
+var (
+	typeErrorLine   = regexp.MustCompile(`^line (\d+): `)
+	syntaxErrorLine = regexp.MustCompile(`^yaml: line (\d+): `)
+)
+
+// decode decodes node into v, reporting the values with the wrong type,
+// and reports whether it succeeded.
+func (l *linter) decode(node *yaml.Node, v any) bool {
+	err := node.Decode(v)
+	if err == nil {
+		return true
+	}
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		l.add("error", config.RuleInvalidStructure, err.Error(), node.Line)
+		return false
+	}
+	for _, msg := range typeErr.Errors {
+		line := node.Line
+		if m := typeErrorLine.FindStringSubmatch(msg); m != nil {
+			line, _ = strconv.Atoi(m[1])
+			msg = msg[len(m[0]):]
+		}
+		l.add("error", config.RuleInvalidStructure, msg, line)
+	}
+	return false
+}
+
+// lintReferences reports the steps.ID references of s that do not name a
+// step in earlier.
+func (l *linter) lintReferences(s lintedStep, earlier map[string]bool, ids map[string]int) {
+	check := func(name, src string, line int) {
+		e, err := parseExpr(src, nil)
+		if err != nil {
+			return
+		}
+		for _, id := range stepRefs(e) {
+			switch {
+			case earlier[id]:
+			case id == s.ID:
+				l.add("error", RuleUndefinedStep, fmt.Sprintf("%s: %s: steps.%s refers to the step itself; steps can only use the steps before them", s.where, name, id), line)
+			case ids[id] > 0:
+				l.add("error", RuleUndefinedStep, fmt.Sprintf("%s: %s: steps.%s refers to a step that runs later; steps can only use the steps before them", s.where, name, id), line)
+			default:
+				l.add("error", RuleUndefinedStep, fmt.Sprintf("%s: %s: steps.%s refers to undefined step %q", s.where, name, id, id), line)
+			}
+		}
+	}
+	if s.If != "" {
+		check("if", stripDelims(s.If), fieldNode(s.node, "if").Line)
+	}
+	for _, f := range s.templated() {
+		node := fieldNode(s.node, f.name)
+		for _, p := range placeholders(f.value) {
+			check(f.name, p.src, valueLine(node, p.offset))
+		}
+	}
+}
+
+// lintReachable reports s when its if: condition can never be true.
+// canFail is set when a step before it can fail the workflow.
+func (l *linter) lintReachable(s lintedStep, i int, canFail bool) {
+	if s.If == "" {
+		return
+	}
+	e, err := parseExpr(stripDelims(s.If), nil)
+	if err != nil {
+		return
+	}
+	if !usesStatus(e) {
+		e = binary{op: "&&", l: call{name: "success"}, r: e}
+	}
+	line := fieldNode(s.node, "if").Line
+	afterSuccess, ok := constant(e, false)
+	if !ok || truthy(afterSuccess) {
+		return
+	}
+	afterFailure, ok := constant(e, true)
+	switch {
+	case ok && !truthy(afterFailure):
+		l.add("warning", RuleUnreachableStep, fmt.Sprintf("%s never runs: if: %s is always false", s.where, strings.TrimSpace(s.If)), line)
+	case !canFail && i == 0:
+		l.add("warning", RuleUnreachableStep, fmt.Sprintf("%s never runs: if: %s is only true after a failure, and no step runs before it", s.where, strings.TrimSpace(s.If)), line)
+	case !canFail:
+		l.add("warning", RuleUnreachableStep, fmt.Sprintf("%s never runs: if: %s is only true after a failure, and every step before it has continue-on-error", s.where, strings.TrimSpace(s.If)), line)
+	}
+}
+
+// lintInterpolation reports step outputs and environment variables
+// interpolated into the script of a shell command step, whose values the
+// shell would run as code. Placeholders in run: are passed to the shell
+// in variables instead.
+func (l *linter) lintInterpolation(s lintedStep) {
+	i := process.ShellScript(s.Command, s.Args)
+	if i < 0 {
+		return
+	}
+	name, script, shell := fmt.Sprintf("args[%d]", i), s.Args[i], filepath.Base(s.Command)
+	node := fieldNode(s.node, name)
+	for _, p := range placeholders(script) {
+		e, err := parseExpr(p.src, nil)
+		if err != nil {
+			continue
+		}
+		if r, ok := e.(ref); ok && (r.path[0] == "env" || r.path[2] == "outputs") {
+			l.add("warning", config.RuleShellInterpolation, fmt.Sprintf("%s: ${{%s}} is interpolated into the script %s runs, where its value can inject commands; pass it in env: and use \"$NAME\" instead", s.where, p.src, shell), valueLine(node, p.offset))
+		}
+	}
+}
+
+// placeholder is a ${{ }} placeholder in a string.
+type placeholder struct {
+	// src is the expression between the delimiters.
+	src string
+	// offset is where the placeholder starts.
+	offset int
+}
+
+// placeholders returns the ${{ }} placeholders in s.
+func placeholders(s string) []placeholder {
+	var found []placeholder
+	for offset := 0; ; {
+		start := strings.Index(s[offset:], "${{")
+		if start < 0 {
+			return found
+		}
+		start += offset
+		end := strings.Index(s[start:], "}}")
+		if end < 0 {
+			return found
+		}
+		found = append(found, placeholder{src: s[start+3 : start+end], offset: start})
+		offset = start + end + 2
+	}
+}
+
+// stepRefs returns the step IDs e refers to.
+func stepRefs(e expr) []string {
+	switch e := e.(type) {
+	case ref:
+		if e.path[0] == "steps" {
+			return []string{e.path[1]}
+		}
+	case call:
+		var ids []string
+		for _, arg := range e.args {
+			ids = append(ids, stepRefs(arg)...)
+		}
+		return ids
+	case not:
+		return stepRefs(e.x)
+	case binary:
+		return append(stepRefs(e.l), stepRefs(e.r)...)
+	}
+	return nil
+}
+
+// constant evaluates e when its value only depends on whether an earlier
+// step failed, and not on step results or the environment.
+func constant(e expr, failed bool) (any, bool) {
+	sc := &scope{failed: failed}
+	switch e := e.(type) {
+	case literal:
+		return e.v, true
+	case call:
+		args := make([]expr, len(e.args))
+		for i, arg := range e.args {
+			v, ok := constant(arg, failed)
+			if !ok {
+				return nil, false
+			}
+			args[i] = literal{v}
+		}
+		return call{name: e.name, args: args}.eval(sc), true
+	case not:
+		v, ok := constant(e.x, failed)
+		if !ok {
+			return nil, false
+		}
+		return !truthy(v), true
+	case binary:
+		lv, lok := constant(e.l, failed)
+		rv, rok := constant(e.r, failed)
+		switch {
+		case e.op == "&&" && (lok && !truthy(lv) || rok && !truthy(rv)):
+			return false, true
+		case e.op == "||" && (lok && truthy(lv) || rok && truthy(rv)):
+			return true, true
+		case !lok || !rok:
+			return nil, false
+		}
+		return binary{op: e.op, l: literal{lv}, r: literal{rv}}.eval(sc), true
+	}
+	return nil, false
+}
+
+// fieldNode returns the value node of a step field named like the fields
+// of Step.templated, such as run, args[1], or env.NAME, or the step node
+// itself when the field cannot be found.
+func fieldNode(step *yaml.Node, name string) *yaml.Node {
+	if step.Kind != yaml.MappingNode {
+		return step
+	}
+	key, rest, _ := strings.Cut(name, ".")
+	key, index, indexed := strings.Cut(key, "[")
+	value := mappingValue(step, key)
+	switch {
+	case value == nil:
+		return step
+	case indexed && value.Kind == yaml.SequenceNode:
+		if i, err := strconv.Atoi(strings.TrimSuffix(index, "]")); err == nil && i < len(value.Content) {
+			return value.Content[i]
+		}
+	case rest != "" && value.Kind == yaml.MappingNode:
+		if v := mappingValue(value, rest); v != nil {
+			return v
+		}
+	}
+	return value
+}
+
+// mappingValue returns the value of key in a mapping node, or nil.
+func mappingValue(mapping *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(mapping.Content); i += 2 {
+		if mapping.Content[i].Value == key {
+			return mapping.Content[i+1]
+		}
+	}
+	return nil
+}
+
+// valueLine returns the line of the character at offset in the value of
+// node. Lines are exact in literal block scalars (|), whose lines are
+// the value's; elsewhere the value's first line is used.
+func valueLine(node *yaml.Node, offset int) int {
+	if node.Style&yaml.LiteralStyle != 0 {
+		return node.Line + 1 + strings.Count(node.Value[:offset], "\n")
+	}
+	return node.Line
+}
@@ -0,0 +1,131 @@
+package workflow
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func lintSource(t *testing.T, src string) []string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wf.yaml")
+	if err := os.WriteFile(path, []byte(src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Lint(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid != (len(result.Errors) == 0) {
+		t.Errorf("Valid = %v with %d errors", result.Valid, len(result.Errors))
+	}
+	var issues []string
+	for _, issue := range append(result.Errors, result.Warnings...) {
+		issues = append(issues, fmt.Sprintf("%s %d %s: %s", issue.Severity, issue.Line, issue.Rule, issue.Message))
+	}
+	return issues
+}
+
+func TestLint(t *testing.T) {
+	issues := lintSource(t, `name: release
+timeout: 5m
+steps:
+  - id: version
+    run: |
+      echo "tag=v1" >> "$ADO_OUTPUT"
+  - id: build
+    command: go
+    args: [build, "-ldflags=-X main.v=${{ steps.version.outputs.tag }}"]
+    env:
+      NEXT: ${{ steps.publish.outcome }}
+    shel: bash
+  - id: notify
+    run: |
+      echo building
+      curl -d "${{ steps.version.outputs.tag }}" ${{ env.HOOK }}
+    if: ${{ steps.nope.outcome == 'failure' }}
+  - id: version
+    command: bash
+    args: [-ec, "echo ${{ steps.build.outcome }} ${{ steps.build.outputs.sha }}"]
+  - id: publish
+    run: ./publish
+    if: false && steps.build.outcome == 'success'
+  - id: tag
+    command: git
+    timeout: soon
+`)
+	want := []string{
+		`error 2 unknown-key: unknown key "timeout"`,
+		`error 12 unknown-key: step "build": unknown key "shel"`,
+		`error 18 duplicate-id: step "version": duplicate id "version" (first used at line 4)`,
+		`error 26 invalid-structure: cannot unmarshal !!str ` + "`soon`" + ` into time.Duration`,
+		`error 11 undefined-step: step "build": env.NEXT: steps.publish refers to a step that runs later; steps can only use the steps before them`,
+		`error 17 undefined-step: step "notify": if: steps.nope refers to undefined step "nope"`,
+		`warning 20 shell-interpolation: step "version": ${{ steps.build.outputs.sha }} is interpolated into the script bash runs, where its value can inject commands; pass it in env: and use "$NAME" instead`,
+		`warning 23 unreachable-step: step "publish" never runs: if: false && steps.build.outcome == 'success' is always false`,
+	}
+	if strings.Join(issues, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(issues, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestLint_Reachability(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{
+			"failure first",
+			"steps:\n  - run: ./cleanup\n    if: failure()\n",
+			"and no step runs before it",
+		},
+		{
+			"failure after steps that cannot fail",
+			"steps:\n  - run: ./a\n    continue-on-error: true\n  - run: ./b\n    if: ${{ failure() }}\n",
+			"every step before it has continue-on-error",
+		},
+		{"failure after a step that can fail", "steps:\n  - run: ./a\n  - run: ./b\n    if: failure()\n", ""},
+		{"always", "steps:\n  - run: ./a\n    if: always() || false\n", ""},
+		{"never", "steps:\n  - run: ./a\n    if: \"!always()\"\n", "is always false"},
+		{"comparison", "steps:\n  - run: ./a\n    if: \"'a' == 'b'\"\n", "is always false"},
+		{"depends on env", "steps:\n  - run: ./a\n    if: env.CI == 'true'\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := strings.Join(lintSource(t, tt.src), "\n")
+			if tt.want == "" && issues != "" || !strings.Contains(issues, tt.want) {
+				t.Errorf("issues = %q, want %q", issues, tt.want)
+			}
+		})
+	}
+}
+
+func TestLint_Files(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"empty", "\n", "error 0 empty-file: workflow is empty"},
+		{"syntax", "steps: [\n", "error 1 yaml-syntax: invalid YAML: "},
+		{"not a mapping", "- run: a\n", "error 1 invalid-structure: a workflow must be a mapping with a steps key"},
+		{"no steps", "name: x\n", "error 0 step: workflow has no steps"},
+		{"incomplete step", "steps:\n  - name: x\n", `error 2 step: step 1: needs run or command`},
+		{"valid", "steps:\n  - run: make\n", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := strings.Join(lintSource(t, tt.src), "\n")
+			if tt.want == "" && issues != "" || !strings.HasPrefix(issues, tt.want) {
+				t.Errorf("issues = %q, want %q", issues, tt.want)
+			}
+		})
+	}
+
+	result, err := Lint(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Rule != "file-not-found" {
+		t.Errorf("missing file: errors = %+v", result.Errors)
+	}
+}
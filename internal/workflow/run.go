@@ -0,0 +1,359 @@
+package workflow
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anowarislam/ado/internal/process"
+)
+
+// Step and workflow statuses.
+const (
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusSkipped = "skipped"
+)
+
+// OutputEnv names the variable holding the file a step writes its
+// outputs to, one NAME=VALUE per line.
+const OutputEnv = "ADO_OUTPUT"
+
+// ExprEnvPrefix names the variables holding the values of the ${{ }}
+// placeholders of a run: script: ADO_EXPR_1 for the first, and so on.
+const ExprEnvPrefix = "ADO_EXPR_"
+
+// StepResult is the outcome of one step.
+type StepResult struct {
+	ID     string `json:"id,omitempty" yaml:"id,omitempty"`
+	Name   string `json:"name" yaml:"name"`
+	Status string `json:"status" yaml:"status"`
+	// ExitCode is -1 when the step did not run to completion.
+	ExitCode        int               `json:"exit_code" yaml:"exit_code"`
+	DurationMS      int64             `json:"duration_ms" yaml:"duration_ms"`
+	ContinueOnError bool              `json:"continue_on_error,omitempty" yaml:"continue_on_error,omitempty"`
+	Outputs         map[string]string `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+	Error           string            `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// Report is the outcome of a workflow run.
+type Report struct {
+	Name       string       `json:"name,omitempty" yaml:"name,omitempty"`
+	Status     string       `json:"status" yaml:"status"`
+	DurationMS int64        `json:"duration_ms" yaml:"duration_ms"`
+	Steps      []StepResult `json:"steps" yaml:"steps"`
+}
+
+// Count returns how many steps ended with status.
+func (r Report) Count(status string) int {
+	n := 0
+	for _, step := range r.Steps {
+		if step.Status == status {
+			n++
+		}
+	}
+	return n
+}
+
+// Runner executes workflows, streaming step output as it is produced.
+type Runner struct {
+	Stdout io.Writer
+	Stderr io.Writer
+	// Progress receives a line as each step starts and ends; nil discards.
+	Progress io.Writer
+	// Environ returns the environment steps inherit; defaults to os.Environ.
+	Environ func() []string
+}
+
+// Run executes the steps in order and reports every step, including
+// skipped ones. A failed step fails the workflow unless it has
+// continue-on-error; later steps are then skipped unless their if:
+// condition calls failure() or always(). When ctx is canceled the running
+// step is stopped and the remaining steps are skipped.
+func (r Runner) Run(ctx context.Context, wf *Workflow) (Report, error) {
+	started := time.Now()
+	outDir, err := os.MkdirTemp("", "ado-workflow-")
+	if err != nil {
+		return Report{}, fmt.Errorf("create output dir: %w", err)
+	}
+	defer os.RemoveAll(outDir)
+
+	environ := os.Environ
+	if r.Environ != nil {
+		environ = r.Environ
+	}
+	base := process.MergeEnv(environ(), wf.Env)
+	sc := &scope{steps: map[string]*StepResult{}, env: lookup(base)}
+
+	report := Report{Name: wf.Name, Steps: make([]StepResult, len(wf.Steps))}
+	for i, step := range wf.Steps {
+		res := &report.Steps[i]
+		*res = StepResult{ID: step.ID, Name: step.Label(), ExitCode: -1, ContinueOnError: step.ContinueOnError}
+		prefix := fmt.Sprintf("[%d/%d] %s", i+1, len(wf.Steps), res.Name)
+
+		run, err := shouldRun(step, sc)
+		switch {
+		case ctx.Err() != nil:
+			res.Status, res.Error = StatusSkipped, "canceled"
+		case err != nil:
+			res.Status, res.Error = StatusFailure, err.Error()
+		case !run:
+			res.Status = StatusSkipped
+		}
+		if res.Status == "" {
+			r.progress("==> %s", prefix)
+			r.runStep(ctx, wf, step, sc, base, filepath.Join(outDir, fmt.Sprintf("step-%d", i+1)), res)
+		}
+		r.progress("--> %s %s", prefix, describe(*res))
+
+		if res.Status == StatusFailure && !step.ContinueOnError {
+			sc.failed = true
+		}
+		if step.ID != "" {
+			sc.steps[step.ID] = res
+		}
+	}
+
+	report.Status = StatusSuccess
+	if sc.failed || ctx.Err() != nil {
+		report.Status = StatusFailure
+	}
+	report.DurationMS = time.Since(started).Milliseconds()
+	return report, nil
+}
+
+// shouldRun evaluates the step's if: condition. Conditions without a
+// status function are combined with success().
+func shouldRun(step Step, sc *scope) (bool, error) {
+	cond := stripDelims(step.If)
+	if cond == "" {
+		cond = "success()"
+	}
+	e, err := parseExpr(cond, knownSteps(sc))
+	if err != nil {
+		return false, fmt.Errorf("if: %w", err)
+	}
+	if !usesStatus(e) {
+		e = binary{op: "&&", l: call{name: "success"}, r: e}
+	}
+	return truthy(e.eval(sc)), nil
+}
+
+// knownSteps returns the IDs of the steps that have already been reached.
+func knownSteps(sc *scope) map[string]bool {
+	known := make(map[string]bool, len(sc.steps))
+	for id := range sc.steps {
+		known[id] = true
+	}
+	return known
+}
+
+func (r Runner) runStep(ctx context.Context, wf *Workflow, step Step, sc *scope, base []string, outFile string, res *StepResult) {
+	started := time.Now()
+	defer func() { res.DurationMS = time.Since(started).Milliseconds() }()
+	res.Status = StatusFailure
+
+	rendered, err := render(step, sc)
+	if err != nil {
+		res.Error = err.Error()
+		return
+	}
+	if err := os.WriteFile(outFile, nil, 0o600); err != nil {
+		res.Error = fmt.Sprintf("create output file: %v", err)
+		return
+	}
+
+	stepCtx := ctx
+	if step.Timeout > 0 {
+		var cancel context.CancelFunc
+		stepCtx, cancel = context.WithTimeout(ctx, step.Timeout)
+		defer cancel()
+	}
+
+	var cmd *exec.Cmd
+	if rendered.Run != "" {
+		shell := wf.Shell
+		if shell == "" {
+			shell = DefaultShell
+		}
+		cmd = exec.CommandContext(stepCtx, shell, "-c", rendered.Run)
+	} else {
+		cmd = exec.CommandContext(stepCtx, rendered.Command, rendered.Args...)
+	}
+	cmd.Stdout = r.Stdout
+	cmd.Stderr = r.Stderr
+	cmd.Dir = workDir(wf.Dir, rendered.Cwd)
+	cmd.Env = process.MergeEnv(base, rendered.Env)
+	cmd.Env = append(cmd.Env, OutputEnv+"="+outFile)
+	process.NewGroup(cmd)
+	process.Graceful(cmd, process.GracePeriod)
+
+	slog.DebugContext(ctx, "Running workflow step", "step", res.Name, "dir", cmd.Dir)
+	err = cmd.Run()
+	if cmd.ProcessState != nil {
+		res.ExitCode = cmd.ProcessState.ExitCode()
+	}
+
+	outputs, outErr := readOutputs(outFile)
+	res.Outputs = outputs
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(stepCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil:
+		res.Error = fmt.Sprintf("timed out after %s", step.Timeout)
+	case ctx.Err() != nil:
+		res.Error = "canceled"
+	case errors.As(err, &exitErr):
+		res.Error = exitErr.Error()
+	case err != nil:
+		res.Error = err.Error()
+	case outErr != nil:
+		res.Error = outErr.Error()
+	default:
+		res.Status = StatusSuccess
+	}
+}
+
+// render resolves the ${{ }} placeholders in a copy of step. Those in run
+// become references to variables added to its env, so that the shell does
+// not parse their values.
+func render(step Step, sc *scope) (Step, error) {
+	known := knownSteps(sc)
+	var err error
+	expand := func(name, value string) string {
+		if err != nil {
+			return ""
+		}
+		t, perr := parseTemplate(value, known)
+		if perr != nil {
+			err = fmt.Errorf("%s: %w", name, perr)
+			return ""
+		}
+		return t.render(sc)
+	}
+
+	out := step
+	out.Command = expand("command", step.Command)
+	out.Cwd = expand("cwd", step.Cwd)
+	out.Args = make([]string, len(step.Args))
+	for i, arg := range step.Args {
+		out.Args[i] = expand(fmt.Sprintf("args[%d]", i), arg)
+	}
+	out.Env = make(map[string]string, len(step.Env))
+	for _, key := range sortedKeys(step.Env) {
+		out.Env[key] = expand("env."+key, step.Env[key])
+	}
+	if err != nil {
+		return out, err
+	}
+	t, err := parseTemplate(step.Run, known)
+	if err != nil {
+		return out, fmt.Errorf("run: %w", err)
+	}
+	out.Run = t.renderShell(sc, out.Env)
+	return out, nil
+}
+
+// readOutputs parses NAME=VALUE lines from path. A NAME<<DELIMITER line
+// starts a multi-line value that ends at a line holding only DELIMITER.
+func readOutputs(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	outputs := map[string]string{}
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+		if name, delim, ok := strings.Cut(text, "<<"); ok && !strings.Contains(name, "=") {
+			var lines []string
+			closed := false
+			for scanner.Scan() {
+				line++
+				if scanner.Text() == delim {
+					closed = true
+					break
+				}
+				lines = append(lines, scanner.Text())
+			}
+			if !closed {
+				return outputs, fmt.Errorf("%s: output %q: missing closing %q", OutputEnv, name, delim)
+			}
+			outputs[name] = strings.Join(lines, "\n")
+			continue
+		}
+		name, value, ok := strings.Cut(text, "=")
+		if !ok || name == "" {
+			return outputs, fmt.Errorf("%s line %d: expected NAME=VALUE or NAME<<DELIMITER", OutputEnv, line)
+		}
+		outputs[name] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return outputs, fmt.Errorf("read %s: %w", OutputEnv, err)
+	}
+	if len(outputs) == 0 {
+		return nil, nil
+	}
+	return outputs, nil
+}
+
+func (r Runner) progress(format string, args ...any) {
+	if r.Progress != nil {
+		fmt.Fprintf(r.Progress, format+"\n", args...)
+	}
+}
+
+// describe is the end-of-step progress text.
+func describe(res StepResult) string {
+	elapsed := (time.Duration(res.DurationMS) * time.Millisecond).Round(100 * time.Millisecond)
+	switch res.Status {
+	case StatusSuccess:
+		return fmt.Sprintf("ok (%s)", elapsed)
+	case StatusSkipped:
+		if res.Error != "" {
+			return "skipped: " + res.Error
+		}
+		return "skipped"
+	}
+	text := fmt.Sprintf("failed: %s (%s)", res.Error, elapsed)
+	if res.ContinueOnError {
+		text += ", continuing"
+	}
+	return text
+}
+
+func workDir(base, cwd string) string {
+	if cwd == "" {
+		return base
+	}
+	if filepath.IsAbs(cwd) || base == "" {
+		return cwd
+	}
+	return filepath.Join(base, cwd)
+}
+
+// lookup returns a function reading variables from env.
+func lookup(env []string) func(string) string {
+	vars := make(map[string]string, len(env))
+	for _, kv := range env {
+		key, value, _ := strings.Cut(kv, "=")
+		vars[key] = value
+	}
+	return func(name string) string { return vars[name] }
+}
@@ -0,0 +1,250 @@
+package workflow
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func runWorkflow(t *testing.T, src string) (Report, string, string) {
+	t.Helper()
+	wf, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	wf.Dir = t.TempDir()
+	var stdout, progress bytes.Buffer
+	r := Runner{
+		Stdout:   &stdout,
+		Stderr:   &stdout,
+		Progress: &progress,
+		Environ:  func() []string { return []string{"PATH=" + os.Getenv("PATH"), "BASE=base"} },
+	}
+	report, err := r.Run(context.Background(), wf)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	return report, stdout.String(), progress.String()
+}
+
+func statuses(r Report) string {
+	var s []string
+	for _, step := range r.Steps {
+		s = append(s, step.Status)
+	}
+	return strings.Join(s, ",")
+}
+
+func TestRun_OutputsAndEnv(t *testing.T) {
+	report, stdout, _ := runWorkflow(t, `
+env:
+  GREETING: hello
+steps:
+  - id: version
+    run: |
+      echo "tag=v1.2" >> "$ADO_OUTPUT"
+      printf 'notes<<EOF\nline one\nline two\nEOF\n' >> "$ADO_OUTPUT"
+  - command: sh
+    args: ["-c", "echo $GREETING $BASE $STEP ${{ steps.version.outputs.tag }}"]
+    env:
+      STEP: ${{ env.GREETING }}-step
+  - run: echo "${{ steps.version.outputs.notes }}"
+`)
+	if report.Status != StatusSuccess {
+		t.Fatalf("status = %s, steps = %+v", report.Status, report.Steps)
+	}
+	if got := report.Steps[0].Outputs; got["tag"] != "v1.2" || got["notes"] != "line one\nline two" {
+		t.Errorf("outputs = %q", got)
+	}
+	if want := "hello base hello-step v1.2\nline one\nline two\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+	if report.Steps[1].ExitCode != 0 {
+		t.Errorf("exit code = %d", report.Steps[1].ExitCode)
+	}
+}
+
+func TestRun_OutputsNotRunAsCode(t *testing.T) {
+	report, stdout, _ := runWorkflow(t, `
+steps:
+  - id: evil
+    run: echo 'tag=$(echo injected); echo injected' >> "$ADO_OUTPUT"
+  - run: echo "tag ${{ steps.evil.outputs.tag }}" ${{ 'a b' }}
+`)
+	if report.Status != StatusSuccess {
+		t.Fatalf("status = %s, steps = %+v", report.Status, report.Steps)
+	}
+	if want := "tag $(echo injected); echo injected a b\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+}
+
+func TestRun_Conditions(t *testing.T) {
+	report, stdout, progress := runWorkflow(t, `
+steps:
+  - id: ok
+    run: echo ok
+  - id: broken
+    run: exit 3
+  - name: after failure
+    run: echo skipped
+  - name: cleanup
+    if: always()
+    run: echo cleanup
+  - name: on failure
+    if: failure() && steps.broken.exit_code == 3
+    run: echo failure
+  - name: env only
+    if: steps.ok.outcome == 'success'
+    run: echo env-only
+`)
+	if report.Status != StatusFailure {
+		t.Errorf("status = %s, want failure", report.Status)
+	}
+	if got, want := statuses(report), "success,failure,skipped,success,success,skipped"; got != want {
+		t.Errorf("statuses = %s, want %s", got, want)
+	}
+	broken := report.Steps[1]
+	if broken.ExitCode != 3 || broken.Error != "exit status 3" {
+		t.Errorf("broken = %+v", broken)
+	}
+	if skipped := report.Steps[2]; skipped.ExitCode != -1 {
+		t.Errorf("skipped exit code = %d, want -1", skipped.ExitCode)
+	}
+	if want := "ok\ncleanup\nfailure\n"; stdout != want {
+		t.Errorf("stdout = %q, want %q", stdout, want)
+	}
+	for _, want := range []string{"==> [1/6] ok\n", "--> [2/6] broken failed: exit status 3", "--> [3/6] after failure skipped\n"} {
+		if !strings.Contains(progress, want) {
+			t.Errorf("progress missing %q:\n%s", want, progress)
+		}
+	}
+	if report.Count(StatusSuccess) != 3 || report.Count(StatusSkipped) != 2 {
+		t.Errorf("counts = %d success, %d skipped", report.Count(StatusSuccess), report.Count(StatusSkipped))
+	}
+}
+
+func TestRun_ContinueOnError(t *testing.T) {
+	report, _, progress := runWorkflow(t, `
+steps:
+  - id: flaky
+    run: exit 1
+    continue-on-error: true
+  - if: steps.flaky.outcome == 'failure'
+    run: echo recovered
+`)
+	if report.Status != StatusSuccess {
+		t.Errorf("status = %s, want success", report.Status)
+	}
+	if got, want := statuses(report), "failure,success"; got != want {
+		t.Errorf("statuses = %s, want %s", got, want)
+	}
+	if !strings.Contains(progress, ", continuing") {
+		t.Errorf("progress = %q", progress)
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	started := time.Now()
+	report, _, _ := runWorkflow(t, `
+steps:
+  - run: sleep 10
+    timeout: 100ms
+`)
+	if elapsed := time.Since(started); elapsed > 5*time.Second {
+		t.Errorf("took %s, timeout not enforced", elapsed)
+	}
+	if step := report.Steps[0]; step.Status != StatusFailure || step.Error != "timed out after 100ms" {
+		t.Errorf("step = %+v", step)
+	}
+}
+
+func TestRun_Cwd(t *testing.T) {
+	wf, err := Parse(strings.NewReader("steps:\n  - run: pwd\n    cwd: sub\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wf.Dir = t.TempDir()
+	if err := os.Mkdir(filepath.Join(wf.Dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	var stdout bytes.Buffer
+	if _, err := (Runner{Stdout: &stdout}).Run(context.Background(), wf); err != nil {
+		t.Fatal(err)
+	}
+	got, _ := filepath.EvalSymlinks(strings.TrimSpace(stdout.String()))
+	want, _ := filepath.EvalSymlinks(filepath.Join(wf.Dir, "sub"))
+	if got != want {
+		t.Errorf("cwd = %q, want %q", got, want)
+	}
+}
+
+func TestRun_InvalidOutputs(t *testing.T) {
+	report, _, _ := runWorkflow(t, `
+steps:
+  - run: echo "not an output" >> "$ADO_OUTPUT"
+`)
+	if step := report.Steps[0]; step.Status != StatusFailure || !strings.Contains(step.Error, "expected NAME=VALUE") {
+		t.Errorf("step = %+v", step)
+	}
+}
+
+func TestRun_Canceled(t *testing.T) {
+	wf, err := Parse(strings.NewReader("steps:\n  - run: echo a\n  - run: echo b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report, err := Runner{}.Run(ctx, wf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Status != StatusFailure || report.Steps[0].Error != "canceled" {
+		t.Errorf("report = %+v", report)
+	}
+}
+
+func TestReadOutputs(t *testing.T) {
+	tests := []struct {
+		name, content string
+		want          map[string]string
+		wantErr       string
+	}{
+		{"empty", "", nil, ""},
+		{"pairs", "a=1\n\nb=x=y\n", map[string]string{"a": "1", "b": "x=y"}, ""},
+		{"heredoc", "msg<<END\nhi\n\nthere\nEND\nz=1\n", map[string]string{"msg": "hi\n\nthere", "z": "1"}, ""},
+		{"unterminated", "msg<<END\nhi\n", nil, `missing closing "END"`},
+		{"no equals", "a=1\noops\n", nil, "line 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "out")
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readOutputs(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("outputs = %q, want %q", got, tt.want)
+			}
+			for k, v := range tt.want {
+				if got[k] != v {
+					t.Errorf("outputs[%q] = %q, want %q", k, got[k], v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+// Package workflow loads and runs multi-step pipelines defined in YAML:
+// ordered steps with their own env, working directory, and timeout,
+// if: conditions, continue-on-error, and outputs passed between steps.
+package workflow
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultShell runs run: scripts.
+const DefaultShell = "sh"
+
+// Workflow is a parsed workflow file.
+type Workflow struct {
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Shell runs run: scripts as SHELL -c SCRIPT; defaults to sh.
+	Shell string            `json:"shell,omitempty" yaml:"shell,omitempty"`
+	Env   map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	Steps []Step            `json:"steps" yaml:"steps"`
+
+	// Dir resolves relative step working directories; Load sets it to the
+	// directory containing the file.
+	Dir string `json:"-" yaml:"-"`
+}
+
+// Step is one unit of work. Exactly one of Run and Command is set.
+type Step struct {
+	// ID names the step in expressions such as steps.ID.outputs.NAME.
+	ID   string `json:"id,omitempty" yaml:"id,omitempty"`
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
+	// Run is a script passed to the workflow's shell.
+	Run string `json:"run,omitempty" yaml:"run,omitempty"`
+	// Command is an executable run directly with Args, without a shell.
+	Command string            `json:"command,omitempty" yaml:"command,omitempty"`
+	Args    []string          `json:"args,omitempty" yaml:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty" yaml:"env,omitempty"`
+	Cwd     string            `json:"cwd,omitempty" yaml:"cwd,omitempty"`
+	Timeout time.Duration     `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	// If is an expression deciding whether the step runs. Without a status
+	// function (success, failure, always) it only runs when no earlier
+	// step has failed.
+	If string `json:"if,omitempty" yaml:"if,omitempty"`
+	// ContinueOnError keeps a failure of this step from failing the
+	// workflow and from skipping later steps.
+	ContinueOnError bool `json:"continue_on_error,omitempty" yaml:"continue-on-error,omitempty"`
+}
+
+// Label is how the step is shown in progress output.
+func (s Step) Label() string {
+	switch {
+	case s.Name != "":
+		return s.Name
+	case s.ID != "":
+		return s.ID
+	case s.Run != "":
+		line, _, _ := strings.Cut(strings.TrimSpace(s.Run), "\n")
+		return line
+	}
+	return strings.TrimSpace(s.Command + " " + strings.Join(s.Args, " "))
+}
+
+var idPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// Load reads and validates the workflow file at path.
+func Load(path string) (*Workflow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open workflow: %w", err)
+	}
+	defer f.Close()
+
+	wf, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	wf.Dir = filepath.Dir(abs)
+	return wf, nil
+}
+
+// Parse reads and validates a workflow. Unknown fields are errors.
+func Parse(r io.Reader) (*Workflow, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("read workflow: %w", err)
+	}
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var wf Workflow
+	if err := dec.Decode(&wf); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("workflow is empty")
+		}
+		return nil, fmt.Errorf("parse workflow: %w", err)
+	}
+	if err := wf.Validate(); err != nil {
+		return nil, err
+	}
+	return &wf, nil
+}
+
+// Validate checks the steps and parses every expression, so mistakes are
+// reported before anything runs.
+func (wf *Workflow) Validate() error {
+	if len(wf.Steps) == 0 {
+		return errors.New("workflow has no steps")
+	}
+	known := map[string]bool{}
+	for i, step := range wf.Steps {
+		where := fmt.Sprintf("step %d", i+1)
+		if step.ID != "" {
+			where = fmt.Sprintf("step %q", step.ID)
+		}
+		if err := step.validate(known); err != nil {
+			return fmt.Errorf("%s: %w", where, err)
+		}
+		if step.ID != "" {
+			known[step.ID] = true
+		}
+	}
+	return nil
+}
+
+// validate checks s. known lists the IDs of the steps before it; nil
+// skips checking step references and duplicate IDs.
+func (s Step) validate(known map[string]bool) error {
+	switch {
+	case s.ID != "" && !idPattern.MatchString(s.ID):
+		return fmt.Errorf("invalid id %q: use letters, digits, _ and -, starting with a letter or _", s.ID)
+	case s.ID != "" && known[s.ID]:
+		return fmt.Errorf("duplicate id %q", s.ID)
+	case s.Run == "" && s.Command == "":
+		return errors.New("needs run or command")
+	case s.Run != "" && s.Command != "":
+		return errors.New("has both run and command; use one")
+	case s.Run != "" && len(s.Args) > 0:
+		return errors.New("args only apply to command")
+	case s.Timeout < 0:
+		return errors.New("timeout must not be negative")
+	}
+
+	if s.If != "" {
+		if _, err := parseExpr(stripDelims(s.If), known); err != nil {
+			return fmt.Errorf("if: %w", err)
+		}
+	}
+	for _, f := range s.templated() {
+		if _, err := parseTemplate(f.value, known); err != nil {
+			return fmt.Errorf("%s: %w", f.name, err)
+		}
+	}
+	return nil
+}
+
+// field is a step field that may contain ${{ }} placeholders.
+type field struct {
+	name, value string
+}
+
+// templated returns the fields that may contain placeholders, in a stable
+// order.
+func (s Step) templated() []field {
+	fields := []field{{"run", s.Run}, {"command", s.Command}, {"cwd", s.Cwd}}
+	for i, arg := range s.Args {
+		fields = append(fields, field{fmt.Sprintf("args[%d]", i), arg})
+	}
+	for _, key := range sortedKeys(s.Env) {
+		fields = append(fields, field{"env." + key, s.Env[key]})
+	}
+	return fields
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// stripDelims allows if: values written as ${{ expression }}.
+func stripDelims(cond string) string {
+	cond = strings.TrimSpace(cond)
+	if strings.HasPrefix(cond, "${{") && strings.HasSuffix(cond, "}}") {
+		return cond[3 : len(cond)-2]
+	}
+	return cond
+}
@@ -0,0 +1,117 @@
+package workflow
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	wf, err := Parse(strings.NewReader(`
+name: release
+shell: bash
+env:
+  TARGET: prod
+steps:
+  - id: version
+    run: echo "tag=v1" >> "$ADO_OUTPUT"
+  - name: Build
+    command: go
+    args: [build, "-ldflags=-X main.v=${{ steps.version.outputs.tag }}"]
+    cwd: app
+    timeout: 5m
+    env:
+      CGO_ENABLED: "0"
+  - run: ./notify
+    if: ${{ failure() }}
+    continue-on-error: true
+`))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if wf.Name != "release" || wf.Shell != "bash" || wf.Env["TARGET"] != "prod" {
+		t.Errorf("workflow = %+v", wf)
+	}
+	if len(wf.Steps) != 3 {
+		t.Fatalf("steps = %d, want 3", len(wf.Steps))
+	}
+	build := wf.Steps[1]
+	if build.Timeout != 5*time.Minute || build.Cwd != "app" || build.Env["CGO_ENABLED"] != "0" || len(build.Args) != 2 {
+		t.Errorf("build step = %+v", build)
+	}
+	if !wf.Steps[2].ContinueOnError {
+		t.Error("continue-on-error not parsed")
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name, src, want string
+	}{
+		{"empty", "", "workflow is empty"},
+		{"no steps", "name: x\n", "no steps"},
+		{"unknown field", "steps:\n  - run: a\n    shel: bash\n", "field shel not found"},
+		{"neither", "steps:\n  - name: x\n", "step 1: needs run or command"},
+		{"both", "steps:\n  - run: a\n    command: b\n", "has both run and command"},
+		{"args with run", "steps:\n  - run: a\n    args: [b]\n", "args only apply to command"},
+		{"bad id", "steps:\n  - id: 1st\n    run: a\n", `invalid id "1st"`},
+		{"duplicate id", "steps:\n  - id: a\n    run: a\n  - id: a\n    run: b\n", `step "a": duplicate id "a"`},
+		{"negative timeout", "steps:\n  - run: a\n    timeout: -1s\n", "timeout must not be negative"},
+		{"bad if", "steps:\n  - run: a\n    if: success(\n", "if:"},
+		{"forward ref", "steps:\n  - run: echo ${{ steps.b.outcome }}\n  - id: b\n    run: b\n", `step 1: run: ${{ steps.b.outcome }}: steps.b.outcome refers to unknown or later step "b"`},
+		{"self ref", "steps:\n  - id: a\n    run: a\n    if: steps.a.outcome == 'success'\n", "unknown or later step"},
+		{"env template", "steps:\n  - command: a\n    env:\n      X: ${{ nope }}\n", "env.X:"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(strings.NewReader(tt.src))
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "wf.yaml")
+	if err := os.WriteFile(path, []byte("steps:\n  - run: true\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	wf, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if wf.Dir != dir {
+		t.Errorf("Dir = %q, want %q", wf.Dir, dir)
+	}
+
+	if err := os.WriteFile(path, []byte("steps: []\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.HasPrefix(err.Error(), path+": ") {
+		t.Errorf("Load() error = %v, want path prefix", err)
+	}
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Load() of missing file succeeded")
+	}
+}
+
+func TestStep_Label(t *testing.T) {
+	tests := []struct {
+		step Step
+		want string
+	}{
+		{Step{Name: "Build", ID: "b", Run: "make"}, "Build"},
+		{Step{ID: "b", Run: "make"}, "b"},
+		{Step{Run: "  make all\nmake install\n"}, "make all"},
+		{Step{Command: "go", Args: []string{"test", "./..."}}, "go test ./..."},
+	}
+	for _, tt := range tests {
+		if got := tt.step.Label(); got != tt.want {
+			t.Errorf("Label() = %q, want %q", got, tt.want)
+		}
+	}
+}
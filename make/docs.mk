@@ -10,11 +10,13 @@ MKDOCS ?= mkdocs
 PIP ?= pip
 DOCS_PORT ?= 8000
 DOCS_DEPS := mkdocs-material mkdocs-minify-plugin pillow cairosvg
+MAN_DIR ?= man
+REFERENCE_DIR ?= $(DOCS_DIR)/reference
 
 # ------------------------------------------------------------------------------
 # Targets
 # ------------------------------------------------------------------------------
-.PHONY: docs.install docs.build docs.serve docs.deploy docs.clean docs.check
+.PHONY: docs.install docs.build docs.serve docs.deploy docs.clean docs.check docs.man docs.reference
 
 docs.install: ## Install MkDocs and dependencies
 	$(call log_info,"Installing MkDocs dependencies...")
@@ -35,9 +37,21 @@ docs.deploy: docs.build ## Deploy documentation to GitHub Pages
 	@$(MKDOCS) gh-deploy --force
 	$(call log_success,"Documentation deployed")
 
+docs.man: ## Generate man pages into $(MAN_DIR)/
+	$(call log_info,"Generating man pages...")
+	@$(GO) run ./cmd/ado docs man --dir $(MAN_DIR)
+	$(call log_success,"Man pages generated in $(MAN_DIR)/")
+
+docs.reference: ## Generate the markdown CLI reference into $(REFERENCE_DIR)/
+	$(call log_info,"Generating CLI reference...")
+	@$(GO) run ./cmd/ado docs markdown --dir $(REFERENCE_DIR)
+	$(call log_success,"CLI reference generated in $(REFERENCE_DIR)/")
+
 docs.clean: ## Clean built documentation
 	$(call log_info,"Cleaning documentation artifacts...")
 	@rm -rf $(SITE_DIR)/
+	@rm -rf $(MAN_DIR)/
+	@rm -rf $(REFERENCE_DIR)/
 	@rm -f $(DOCS_DIR)/changelog.md
 	$(call log_success,"Documentation artifacts cleaned")
 
@@ -52,3 +66,4 @@ docs.check: _docs-prep ## Check documentation for errors
 .PHONY: _docs-prep
 _docs-prep:
 	@cp CHANGELOG.md $(DOCS_DIR)/changelog.md 2>/dev/null || true
+	@$(GO) run ./cmd/ado docs markdown --dir $(REFERENCE_DIR) >/dev/null
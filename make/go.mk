@@ -10,6 +10,7 @@ GO ?= go
 GO_ENV ?= env GOCACHE=$(PROJECT_ROOT)/.gocache
 GO_BUILD_CMD ?= ./cmd/ado
 GO_BINARY ?= $(PROJECT_ROOT)/ado
+GO_MINIMAL_BINARY ?= $(PROJECT_ROOT)/ado-minimal
 
 # Build flags
 GO_LDFLAGS := -s -w \
@@ -24,16 +25,27 @@ GO_TEST_PKGS ?= ./cmd/ado/... ./internal/...
 GO_TEST_FLAGS ?= -race -timeout 30s
 GO_COVER_FLAGS ?= -coverprofile=coverage.out -covermode=atomic
 
+# Benchmark flags: repeated runs give benchstat enough samples
+GO_BENCH_COUNT ?= 10
+GO_BENCH_OUT ?= bench_output.txt
+GO_BENCH_BASE ?= bench_base.txt
+BENCHSTAT ?= $(GO) run golang.org/x/perf/cmd/benchstat@latest
+
 # ------------------------------------------------------------------------------
 # Build Targets
 # ------------------------------------------------------------------------------
-.PHONY: go.build go.build.all go.install
+.PHONY: go.build go.build.minimal go.build.all go.install
 
 go.build: _check-go ## Build the ado binary
 	$(call log_info,"Building $(PROJECT_NAME)...")
 	@$(GO_ENV) $(GO) build -ldflags "$(GO_LDFLAGS)" -o $(GO_BINARY) $(GO_BUILD_CMD)
 	$(call log_success,"Built: $(GO_BINARY)")
 
+go.build.minimal: _check-go ## Build a static binary without GPU/NPU/SMART probes
+	$(call log_info,"Building minimal $(PROJECT_NAME)...")
+	@$(GO_ENV) CGO_ENABLED=0 $(GO) build -tags minimal -ldflags "$(GO_LDFLAGS)" -o $(GO_MINIMAL_BINARY) $(GO_BUILD_CMD)
+	$(call log_success,"Built: $(GO_MINIMAL_BINARY)")
+
 go.build.all: _check-go ## Build for all platforms (via goreleaser)
 	$(call log_info,"Building for all platforms...")
 	@command -v goreleaser >/dev/null 2>&1 || { \
@@ -51,7 +63,7 @@ go.install: _check-go ## Install binary to GOPATH/bin
 # ------------------------------------------------------------------------------
 # Test Targets
 # ------------------------------------------------------------------------------
-.PHONY: go.test go.test.cover go.test.verbose go.test.race go.bench
+.PHONY: go.test go.test.cover go.test.verbose go.test.race go.test.e2e go.bench go.bench.base go.bench.compare
 
 go.test: _check-go ## Run Go tests
 	$(call log_info,"Running Go tests...")
@@ -88,9 +100,22 @@ go.test.race: _check-go ## Run Go tests with race detector
 	@$(GO_ENV) $(GO) test -race $(GO_TEST_PKGS)
 	$(call log_success,"No race conditions detected")
 
-go.bench: _check-go ## Run Go benchmarks
+go.test.e2e: _check-go ## Run end-to-end CLI scripts (internal/clitest/testdata/script)
+	$(call log_info,"Running end-to-end CLI tests...")
+	@$(GO_ENV) $(GO) test -run TestScripts ./internal/clitest/...
+	$(call log_success,"All end-to-end tests passed")
+
+go.bench: _check-go ## Run Go benchmarks (results in bench_output.txt)
 	$(call log_info,"Running Go benchmarks...")
-	@$(GO_ENV) $(GO) test -bench=. -benchmem $(GO_TEST_PKGS)
+	@$(GO_ENV) $(GO) test -run='^$$' -bench=. -benchmem -count=$(GO_BENCH_COUNT) $(GO_TEST_PKGS) | tee $(GO_BENCH_OUT)
+
+go.bench.base: go.bench ## Save benchmark results as the baseline for go.bench.compare
+	@cp $(GO_BENCH_OUT) $(GO_BENCH_BASE)
+	$(call log_success,"Baseline saved to $(GO_BENCH_BASE)")
+
+go.bench.compare: go.bench ## Compare benchmarks against the saved baseline with benchstat
+	@test -f $(GO_BENCH_BASE) || { echo "No baseline: run make go.bench.base first"; exit 1; }
+	@$(BENCHSTAT) $(GO_BENCH_BASE) $(GO_BENCH_OUT)
 
 # ------------------------------------------------------------------------------
 # Lint Targets
@@ -134,7 +159,7 @@ go.tidy: _check-go ## Sync Go module dependencies
 # ------------------------------------------------------------------------------
 # Dependency Targets
 # ------------------------------------------------------------------------------
-.PHONY: go.deps go.deps.update go.deps.graph
+.PHONY: go.deps go.deps.update go.deps.graph go.licenses go.proto
 
 go.deps: _check-go ## Download Go dependencies
 	$(call log_info,"Downloading Go dependencies...")
@@ -151,6 +176,19 @@ go.deps.update: _check-go ## Update Go dependencies
 go.deps.graph: _check-go ## Show Go dependency graph
 	@$(GO) mod graph
 
+go.licenses: _check-go ## Regenerate the embedded dependency license inventory
+	$(call log_info,"Generating license inventory...")
+	@$(GO) generate ./internal/meta
+	$(call log_success,"License inventory updated: internal/meta/licenses.json")
+
+go.proto: ## Regenerate gRPC stubs in pkg/adov1 (needs protoc, protoc-gen-go, protoc-gen-go-grpc)
+	$(call log_info,"Generating protobuf code...")
+	@protoc -I proto \
+		--go_out=. --go_opt=module=github.com/anowarislam/ado \
+		--go-grpc_out=. --go-grpc_opt=module=github.com/anowarislam/ado \
+		proto/ado/v1/ado.proto
+	$(call log_success,"Generated pkg/adov1")
+
 # ------------------------------------------------------------------------------
 # Cleanup Targets
 # ------------------------------------------------------------------------------
@@ -0,0 +1,128 @@
+// Package adocli is the stable API for building custom ado distributions:
+// binaries that ship every built-in command plus their own.
+//
+// A distribution registers its commands before executing the root command:
+//
+//	func main() {
+//		adocli.Register(func(deps adocli.Deps) *cobra.Command {
+//			var output string
+//			cmd := &cobra.Command{
+//				Use:   "deploy",
+//				Short: "Deploy the current service",
+//				RunE: func(cmd *cobra.Command, args []string) error {
+//					deps.Logger(cmd.Context()).Info("deploying")
+//					format, err := deps.ParseOutputFormat(output)
+//					if err != nil {
+//						return err
+//					}
+//					result := map[string]string{"status": "ok"}
+//					return deps.PrintOutput(cmd.OutOrStdout(), format, result, func() (string, error) {
+//						return "Deployed", nil
+//					})
+//				},
+//			}
+//			cmd.Flags().StringVarP(&output, "output", "o", "text", "Output format: text, json, yaml")
+//			return cmd
+//		})
+//		adocli.Execute()
+//	}
+//
+// Registered commands get the global --config and --log-level flags, the
+// update notice, and exit-status handling of built-in commands.
+//
+// Experimental commands can ship behind a feature flag that users turn on
+// with `ado meta features enable NAME` or $ADO_FEATURES:
+//
+//	adocli.RegisterFeature(adocli.Feature{Name: "deploy", Stage: adocli.StageExperimental})
+//	adocli.Register(func(deps adocli.Deps) *cobra.Command {
+//		return adocli.Gate(newDeployCommand(deps), "deploy")
+//	})
+package adocli
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/cmd/ado/root"
+	"github.com/anowarislam/ado/internal/config"
+	"github.com/anowarislam/ado/internal/extension"
+	"github.com/anowarislam/ado/internal/features"
+	"github.com/anowarislam/ado/internal/logging"
+	"github.com/anowarislam/ado/internal/meta"
+	"github.com/anowarislam/ado/internal/ui"
+)
+
+// Deps gives registered commands the logger, config loader, and output
+// renderers that built-in commands use.
+type Deps = extension.Deps
+
+// Logger provides structured, leveled logging.
+type Logger = logging.Logger
+
+// Config is the parsed ado config file.
+type Config = config.Config
+
+// BuildInfo describes the running binary.
+type BuildInfo = meta.BuildInfo
+
+// OutputFormat is a value of the --output flag.
+type OutputFormat = ui.OutputFormat
+
+// Output formats accepted by Deps.PrintOutput.
+const (
+	OutputText = ui.OutputText
+	OutputJSON = ui.OutputJSON
+	OutputYAML = ui.OutputYAML
+)
+
+// Feature is a compiled-in feature flag.
+type Feature = features.Feature
+
+// Stage describes the maturity of a feature.
+type Stage = features.Stage
+
+// Feature stages.
+const (
+	StageExperimental = features.StageExperimental
+	StageBeta         = features.StageBeta
+	StageStable       = features.StageStable
+)
+
+// RegisterFeature adds a feature flag, listed by `ado meta features`. It
+// panics if the name is empty or taken.
+func RegisterFeature(f Feature) {
+	features.Default.Register(f)
+}
+
+// Gate puts cmd behind the named feature: while the feature is off, cmd
+// is hidden from help and completion and refuses to run. It returns cmd.
+func Gate(cmd *cobra.Command, feature string) *cobra.Command {
+	return features.Gate(cmd, feature)
+}
+
+// Register adds a subcommand to every root command built afterwards. The
+// factory runs once per root command. Names must not collide with
+// built-in or other registered commands.
+func Register(factory func(deps Deps) *cobra.Command) {
+	extension.Register(factory)
+}
+
+// NewRootCommand builds the ado root command with built-in and registered
+// subcommands, for distributions that execute it themselves.
+func NewRootCommand() *cobra.Command {
+	return root.NewRootCommand()
+}
+
+// Execute runs ado with os.Args and exits with a non-zero status on error,
+// as the stock binary does.
+func Execute() {
+	root.Execute()
+}
+
+// ExecuteContext runs ado with os.Args like Execute, but returns the exit
+// status instead of exiting, for programs that run ado's commands in
+// process.
+func ExecuteContext(ctx context.Context) int {
+	return root.ExecuteContext(ctx)
+}
@@ -0,0 +1,90 @@
+package adocli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/anowarislam/ado/internal/extension"
+	"github.com/anowarislam/ado/pkg/adocli"
+)
+
+func TestRegister(t *testing.T) {
+	t.Setenv("ADO_NO_UPDATE_CHECK", "1")
+	t.Cleanup(extension.Reset)
+
+	adocli.Register(func(deps adocli.Deps) *cobra.Command {
+		var output string
+		cmd := &cobra.Command{
+			Use: "greet",
+			RunE: func(cmd *cobra.Command, args []string) error {
+				deps.Logger(cmd.Context()).Debug("greeting")
+				format, err := deps.ParseOutputFormat(output)
+				if err != nil {
+					return err
+				}
+				payload := map[string]string{"greeting": "hello"}
+				return deps.PrintOutput(cmd.OutOrStdout(), format, payload, func() (string, error) {
+					return "hello", nil
+				})
+			},
+		}
+		cmd.Flags().StringVarP(&output, "output", "o", "text", "")
+		return cmd
+	})
+
+	tests := []struct {
+		format adocli.OutputFormat
+		check  func(t *testing.T, out string)
+	}{
+		{adocli.OutputText, func(t *testing.T, out string) {
+			if out != "hello\n" {
+				t.Errorf("output = %q", out)
+			}
+		}},
+		{adocli.OutputJSON, func(t *testing.T, out string) {
+			var payload map[string]string
+			if err := json.Unmarshal([]byte(out), &payload); err != nil || payload["greeting"] != "hello" {
+				t.Errorf("output = %q, err = %v", out, err)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			root := adocli.NewRootCommand()
+			var buf bytes.Buffer
+			root.SetOut(&buf)
+			root.SetArgs([]string{"--log-level", "debug", "greet", "-o", string(tt.format)})
+			if err := root.Execute(); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			tt.check(t, buf.String())
+		})
+	}
+}
+
+func TestGate(t *testing.T) {
+	t.Setenv("ADO_NO_UPDATE_CHECK", "1")
+	t.Setenv("ADO_CONFIG", "")
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Cleanup(extension.Reset)
+
+	adocli.RegisterFeature(adocli.Feature{Name: "adocli-test-deploy", Stage: adocli.StageBeta})
+	adocli.Register(func(deps adocli.Deps) *cobra.Command {
+		return adocli.Gate(&cobra.Command{Use: "deploy", RunE: func(*cobra.Command, []string) error { return nil }}, "adocli-test-deploy")
+	})
+
+	for _, env := range []string{"", "adocli-test-deploy"} {
+		t.Setenv("ADO_FEATURES", env)
+		root := adocli.NewRootCommand()
+		root.SetOut(&bytes.Buffer{})
+		root.SetArgs([]string{"deploy"})
+		err := root.Execute()
+		if enabled := env != ""; enabled != (err == nil) {
+			t.Errorf("ADO_FEATURES=%q: err = %v", env, err)
+		}
+	}
+}
@@ -0,0 +1,1204 @@
+// Protocol for `ado serve --grpc`. Field names follow the JSON output of
+// the matching ado commands.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: ado/v1/ado.proto
+
+package adov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Output_Stream int32
+
+const (
+	Output_STREAM_UNSPECIFIED Output_Stream = 0
+	Output_STREAM_STDOUT      Output_Stream = 1
+	Output_STREAM_STDERR      Output_Stream = 2
+)
+
+// Enum value maps for Output_Stream.
+var (
+	Output_Stream_name = map[int32]string{
+		0: "STREAM_UNSPECIFIED",
+		1: "STREAM_STDOUT",
+		2: "STREAM_STDERR",
+	}
+	Output_Stream_value = map[string]int32{
+		"STREAM_UNSPECIFIED": 0,
+		"STREAM_STDOUT":      1,
+		"STREAM_STDERR":      2,
+	}
+)
+
+func (x Output_Stream) Enum() *Output_Stream {
+	p := new(Output_Stream)
+	*p = x
+	return p
+}
+
+func (x Output_Stream) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Output_Stream) Descriptor() protoreflect.EnumDescriptor {
+	return file_ado_v1_ado_proto_enumTypes[0].Descriptor()
+}
+
+func (Output_Stream) Type() protoreflect.EnumType {
+	return &file_ado_v1_ado_proto_enumTypes[0]
+}
+
+func (x Output_Stream) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Output_Stream.Descriptor instead.
+func (Output_Stream) EnumDescriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{12, 0}
+}
+
+type GetBuildInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBuildInfoRequest) Reset() {
+	*x = GetBuildInfoRequest{}
+	mi := &file_ado_v1_ado_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBuildInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBuildInfoRequest) ProtoMessage() {}
+
+func (x *GetBuildInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBuildInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetBuildInfoRequest) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{0}
+}
+
+// BuildInfo describes an ado binary.
+type BuildInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Version       string                 `protobuf:"bytes,2,opt,name=version,proto3" json:"version,omitempty"`
+	Commit        string                 `protobuf:"bytes,3,opt,name=commit,proto3" json:"commit,omitempty"`
+	BuildTime     string                 `protobuf:"bytes,4,opt,name=build_time,json=buildTime,proto3" json:"build_time,omitempty"`
+	Dirty         bool                   `protobuf:"varint,5,opt,name=dirty,proto3" json:"dirty,omitempty"`
+	GoVersion     string                 `protobuf:"bytes,6,opt,name=go_version,json=goVersion,proto3" json:"go_version,omitempty"`
+	Platform      string                 `protobuf:"bytes,7,opt,name=platform,proto3" json:"platform,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BuildInfo) Reset() {
+	*x = BuildInfo{}
+	mi := &file_ado_v1_ado_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BuildInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BuildInfo) ProtoMessage() {}
+
+func (x *BuildInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BuildInfo.ProtoReflect.Descriptor instead.
+func (*BuildInfo) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BuildInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *BuildInfo) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *BuildInfo) GetCommit() string {
+	if x != nil {
+		return x.Commit
+	}
+	return ""
+}
+
+func (x *BuildInfo) GetBuildTime() string {
+	if x != nil {
+		return x.BuildTime
+	}
+	return ""
+}
+
+func (x *BuildInfo) GetDirty() bool {
+	if x != nil {
+		return x.Dirty
+	}
+	return false
+}
+
+func (x *BuildInfo) GetGoVersion() string {
+	if x != nil {
+		return x.GoVersion
+	}
+	return ""
+}
+
+func (x *BuildInfo) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+type GetSystemInfoRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Skip detectors that make network calls, such as cloud metadata.
+	NoNetwork bool `protobuf:"varint,1,opt,name=no_network,json=noNetwork,proto3" json:"no_network,omitempty"`
+	// Include the security posture section.
+	Security      bool `protobuf:"varint,2,opt,name=security,proto3" json:"security,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetSystemInfoRequest) Reset() {
+	*x = GetSystemInfoRequest{}
+	mi := &file_ado_v1_ado_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetSystemInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetSystemInfoRequest) ProtoMessage() {}
+
+func (x *GetSystemInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetSystemInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetSystemInfoRequest) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *GetSystemInfoRequest) GetNoNetwork() bool {
+	if x != nil {
+		return x.NoNetwork
+	}
+	return false
+}
+
+func (x *GetSystemInfoRequest) GetSecurity() bool {
+	if x != nil {
+		return x.Security
+	}
+	return false
+}
+
+// SystemInfo holds the most used host diagnostics as typed fields. The
+// complete `ado meta system -o json` document is in details.
+type SystemInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	SchemaVersion int32                  `protobuf:"varint,1,opt,name=schema_version,json=schemaVersion,proto3" json:"schema_version,omitempty"`
+	Os            string                 `protobuf:"bytes,2,opt,name=os,proto3" json:"os,omitempty"`
+	Platform      string                 `protobuf:"bytes,3,opt,name=platform,proto3" json:"platform,omitempty"`
+	Kernel        string                 `protobuf:"bytes,4,opt,name=kernel,proto3" json:"kernel,omitempty"`
+	Architecture  string                 `protobuf:"bytes,5,opt,name=architecture,proto3" json:"architecture,omitempty"`
+	Cpu           *CPUInfo               `protobuf:"bytes,6,opt,name=cpu,proto3" json:"cpu,omitempty"`
+	Memory        *MemoryInfo            `protobuf:"bytes,7,opt,name=memory,proto3" json:"memory,omitempty"`
+	Storage       []*StorageInfo         `protobuf:"bytes,8,rep,name=storage,proto3" json:"storage,omitempty"`
+	Details       *structpb.Struct       `protobuf:"bytes,9,opt,name=details,proto3" json:"details,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SystemInfo) Reset() {
+	*x = SystemInfo{}
+	mi := &file_ado_v1_ado_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SystemInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SystemInfo) ProtoMessage() {}
+
+func (x *SystemInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SystemInfo.ProtoReflect.Descriptor instead.
+func (*SystemInfo) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *SystemInfo) GetSchemaVersion() int32 {
+	if x != nil {
+		return x.SchemaVersion
+	}
+	return 0
+}
+
+func (x *SystemInfo) GetOs() string {
+	if x != nil {
+		return x.Os
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetPlatform() string {
+	if x != nil {
+		return x.Platform
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetKernel() string {
+	if x != nil {
+		return x.Kernel
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetArchitecture() string {
+	if x != nil {
+		return x.Architecture
+	}
+	return ""
+}
+
+func (x *SystemInfo) GetCpu() *CPUInfo {
+	if x != nil {
+		return x.Cpu
+	}
+	return nil
+}
+
+func (x *SystemInfo) GetMemory() *MemoryInfo {
+	if x != nil {
+		return x.Memory
+	}
+	return nil
+}
+
+func (x *SystemInfo) GetStorage() []*StorageInfo {
+	if x != nil {
+		return x.Storage
+	}
+	return nil
+}
+
+func (x *SystemInfo) GetDetails() *structpb.Struct {
+	if x != nil {
+		return x.Details
+	}
+	return nil
+}
+
+type CPUInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Model         string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"`
+	Vendor        string                 `protobuf:"bytes,2,opt,name=vendor,proto3" json:"vendor,omitempty"`
+	Cores         int32                  `protobuf:"varint,3,opt,name=cores,proto3" json:"cores,omitempty"`
+	FrequencyMhz  float64                `protobuf:"fixed64,4,opt,name=frequency_mhz,json=frequencyMhz,proto3" json:"frequency_mhz,omitempty"`
+	EffectiveCpus float64                `protobuf:"fixed64,5,opt,name=effective_cpus,json=effectiveCpus,proto3" json:"effective_cpus,omitempty"`
+	LimitSource   string                 `protobuf:"bytes,6,opt,name=limit_source,json=limitSource,proto3" json:"limit_source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CPUInfo) Reset() {
+	*x = CPUInfo{}
+	mi := &file_ado_v1_ado_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CPUInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CPUInfo) ProtoMessage() {}
+
+func (x *CPUInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CPUInfo.ProtoReflect.Descriptor instead.
+func (*CPUInfo) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *CPUInfo) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *CPUInfo) GetVendor() string {
+	if x != nil {
+		return x.Vendor
+	}
+	return ""
+}
+
+func (x *CPUInfo) GetCores() int32 {
+	if x != nil {
+		return x.Cores
+	}
+	return 0
+}
+
+func (x *CPUInfo) GetFrequencyMhz() float64 {
+	if x != nil {
+		return x.FrequencyMhz
+	}
+	return 0
+}
+
+func (x *CPUInfo) GetEffectiveCpus() float64 {
+	if x != nil {
+		return x.EffectiveCpus
+	}
+	return 0
+}
+
+func (x *CPUInfo) GetLimitSource() string {
+	if x != nil {
+		return x.LimitSource
+	}
+	return ""
+}
+
+type MemoryInfo struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TotalMb          uint64                 `protobuf:"varint,1,opt,name=total_mb,json=totalMb,proto3" json:"total_mb,omitempty"`
+	AvailableMb      uint64                 `protobuf:"varint,2,opt,name=available_mb,json=availableMb,proto3" json:"available_mb,omitempty"`
+	UsedMb           uint64                 `protobuf:"varint,3,opt,name=used_mb,json=usedMb,proto3" json:"used_mb,omitempty"`
+	UsedPercent      float64                `protobuf:"fixed64,4,opt,name=used_percent,json=usedPercent,proto3" json:"used_percent,omitempty"`
+	SwapTotalMb      uint64                 `protobuf:"varint,5,opt,name=swap_total_mb,json=swapTotalMb,proto3" json:"swap_total_mb,omitempty"`
+	SwapUsedMb       uint64                 `protobuf:"varint,6,opt,name=swap_used_mb,json=swapUsedMb,proto3" json:"swap_used_mb,omitempty"`
+	EffectiveLimitMb uint64                 `protobuf:"varint,7,opt,name=effective_limit_mb,json=effectiveLimitMb,proto3" json:"effective_limit_mb,omitempty"`
+	LimitSource      string                 `protobuf:"bytes,8,opt,name=limit_source,json=limitSource,proto3" json:"limit_source,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *MemoryInfo) Reset() {
+	*x = MemoryInfo{}
+	mi := &file_ado_v1_ado_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MemoryInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MemoryInfo) ProtoMessage() {}
+
+func (x *MemoryInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MemoryInfo.ProtoReflect.Descriptor instead.
+func (*MemoryInfo) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *MemoryInfo) GetTotalMb() uint64 {
+	if x != nil {
+		return x.TotalMb
+	}
+	return 0
+}
+
+func (x *MemoryInfo) GetAvailableMb() uint64 {
+	if x != nil {
+		return x.AvailableMb
+	}
+	return 0
+}
+
+func (x *MemoryInfo) GetUsedMb() uint64 {
+	if x != nil {
+		return x.UsedMb
+	}
+	return 0
+}
+
+func (x *MemoryInfo) GetUsedPercent() float64 {
+	if x != nil {
+		return x.UsedPercent
+	}
+	return 0
+}
+
+func (x *MemoryInfo) GetSwapTotalMb() uint64 {
+	if x != nil {
+		return x.SwapTotalMb
+	}
+	return 0
+}
+
+func (x *MemoryInfo) GetSwapUsedMb() uint64 {
+	if x != nil {
+		return x.SwapUsedMb
+	}
+	return 0
+}
+
+func (x *MemoryInfo) GetEffectiveLimitMb() uint64 {
+	if x != nil {
+		return x.EffectiveLimitMb
+	}
+	return 0
+}
+
+func (x *MemoryInfo) GetLimitSource() string {
+	if x != nil {
+		return x.LimitSource
+	}
+	return ""
+}
+
+type StorageInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Device        string                 `protobuf:"bytes,1,opt,name=device,proto3" json:"device,omitempty"`
+	Mountpoint    string                 `protobuf:"bytes,2,opt,name=mountpoint,proto3" json:"mountpoint,omitempty"`
+	Filesystem    string                 `protobuf:"bytes,3,opt,name=filesystem,proto3" json:"filesystem,omitempty"`
+	TotalMb       uint64                 `protobuf:"varint,4,opt,name=total_mb,json=totalMb,proto3" json:"total_mb,omitempty"`
+	UsedMb        uint64                 `protobuf:"varint,5,opt,name=used_mb,json=usedMb,proto3" json:"used_mb,omitempty"`
+	FreeMb        uint64                 `protobuf:"varint,6,opt,name=free_mb,json=freeMb,proto3" json:"free_mb,omitempty"`
+	UsedPercent   float64                `protobuf:"fixed64,7,opt,name=used_percent,json=usedPercent,proto3" json:"used_percent,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StorageInfo) Reset() {
+	*x = StorageInfo{}
+	mi := &file_ado_v1_ado_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StorageInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StorageInfo) ProtoMessage() {}
+
+func (x *StorageInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StorageInfo.ProtoReflect.Descriptor instead.
+func (*StorageInfo) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *StorageInfo) GetDevice() string {
+	if x != nil {
+		return x.Device
+	}
+	return ""
+}
+
+func (x *StorageInfo) GetMountpoint() string {
+	if x != nil {
+		return x.Mountpoint
+	}
+	return ""
+}
+
+func (x *StorageInfo) GetFilesystem() string {
+	if x != nil {
+		return x.Filesystem
+	}
+	return ""
+}
+
+func (x *StorageInfo) GetTotalMb() uint64 {
+	if x != nil {
+		return x.TotalMb
+	}
+	return 0
+}
+
+func (x *StorageInfo) GetUsedMb() uint64 {
+	if x != nil {
+		return x.UsedMb
+	}
+	return 0
+}
+
+func (x *StorageInfo) GetFreeMb() uint64 {
+	if x != nil {
+		return x.FreeMb
+	}
+	return 0
+}
+
+func (x *StorageInfo) GetUsedPercent() float64 {
+	if x != nil {
+		return x.UsedPercent
+	}
+	return 0
+}
+
+type ListTasksRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksRequest) Reset() {
+	*x = ListTasksRequest{}
+	mi := &file_ado_v1_ado_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksRequest) ProtoMessage() {}
+
+func (x *ListTasksRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksRequest.ProtoReflect.Descriptor instead.
+func (*ListTasksRequest) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{7}
+}
+
+type ListTasksResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tasks         []*Task                `protobuf:"bytes,1,rep,name=tasks,proto3" json:"tasks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTasksResponse) Reset() {
+	*x = ListTasksResponse{}
+	mi := &file_ado_v1_ado_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTasksResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTasksResponse) ProtoMessage() {}
+
+func (x *ListTasksResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTasksResponse.ProtoReflect.Descriptor instead.
+func (*ListTasksResponse) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListTasksResponse) GetTasks() []*Task {
+	if x != nil {
+		return x.Tasks
+	}
+	return nil
+}
+
+// Task summarizes a task from the config file.
+type Task struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Command       string                 `protobuf:"bytes,3,opt,name=command,proto3" json:"command,omitempty"`
+	Args          []string               `protobuf:"bytes,4,rep,name=args,proto3" json:"args,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Task) Reset() {
+	*x = Task{}
+	mi := &file_ado_v1_ado_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Task) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Task) ProtoMessage() {}
+
+func (x *Task) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Task.ProtoReflect.Descriptor instead.
+func (*Task) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Task) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Task) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *Task) GetCommand() string {
+	if x != nil {
+		return x.Command
+	}
+	return ""
+}
+
+func (x *Task) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+type RunTaskRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Task name under `tasks:` in the config file.
+	Name string `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	// Arguments appended to the task's configured args.
+	Args          []string `protobuf:"bytes,2,rep,name=args,proto3" json:"args,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunTaskRequest) Reset() {
+	*x = RunTaskRequest{}
+	mi := &file_ado_v1_ado_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunTaskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunTaskRequest) ProtoMessage() {}
+
+func (x *RunTaskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunTaskRequest.ProtoReflect.Descriptor instead.
+func (*RunTaskRequest) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *RunTaskRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *RunTaskRequest) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+type RunTaskResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*RunTaskResponse_Output
+	//	*RunTaskResponse_Exit
+	Event         isRunTaskResponse_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RunTaskResponse) Reset() {
+	*x = RunTaskResponse{}
+	mi := &file_ado_v1_ado_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RunTaskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RunTaskResponse) ProtoMessage() {}
+
+func (x *RunTaskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RunTaskResponse.ProtoReflect.Descriptor instead.
+func (*RunTaskResponse) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RunTaskResponse) GetEvent() isRunTaskResponse_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *RunTaskResponse) GetOutput() *Output {
+	if x != nil {
+		if x, ok := x.Event.(*RunTaskResponse_Output); ok {
+			return x.Output
+		}
+	}
+	return nil
+}
+
+func (x *RunTaskResponse) GetExit() *Exit {
+	if x != nil {
+		if x, ok := x.Event.(*RunTaskResponse_Exit); ok {
+			return x.Exit
+		}
+	}
+	return nil
+}
+
+type isRunTaskResponse_Event interface {
+	isRunTaskResponse_Event()
+}
+
+type RunTaskResponse_Output struct {
+	Output *Output `protobuf:"bytes,1,opt,name=output,proto3,oneof"`
+}
+
+type RunTaskResponse_Exit struct {
+	Exit *Exit `protobuf:"bytes,2,opt,name=exit,proto3,oneof"`
+}
+
+func (*RunTaskResponse_Output) isRunTaskResponse_Event() {}
+
+func (*RunTaskResponse_Exit) isRunTaskResponse_Event() {}
+
+// Output is a chunk of task output.
+type Output struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Stream        Output_Stream          `protobuf:"varint,1,opt,name=stream,proto3,enum=ado.v1.Output_Stream" json:"stream,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Output) Reset() {
+	*x = Output{}
+	mi := &file_ado_v1_ado_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Output) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Output) ProtoMessage() {}
+
+func (x *Output) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Output.ProtoReflect.Descriptor instead.
+func (*Output) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Output) GetStream() Output_Stream {
+	if x != nil {
+		return x.Stream
+	}
+	return Output_STREAM_UNSPECIFIED
+}
+
+func (x *Output) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// Exit reports how the task finished.
+type Exit struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          int32                  `protobuf:"varint,1,opt,name=code,proto3" json:"code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Exit) Reset() {
+	*x = Exit{}
+	mi := &file_ado_v1_ado_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Exit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Exit) ProtoMessage() {}
+
+func (x *Exit) ProtoReflect() protoreflect.Message {
+	mi := &file_ado_v1_ado_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Exit.ProtoReflect.Descriptor instead.
+func (*Exit) Descriptor() ([]byte, []int) {
+	return file_ado_v1_ado_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Exit) GetCode() int32 {
+	if x != nil {
+		return x.Code
+	}
+	return 0
+}
+
+var File_ado_v1_ado_proto protoreflect.FileDescriptor
+
+const file_ado_v1_ado_proto_rawDesc = "" +
+	"\n" +
+	"\x10ado/v1/ado.proto\x12\x06ado.v1\x1a\x1cgoogle/protobuf/struct.proto\"\x15\n" +
+	"\x13GetBuildInfoRequest\"\xc1\x01\n" +
+	"\tBuildInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\tR\aversion\x12\x16\n" +
+	"\x06commit\x18\x03 \x01(\tR\x06commit\x12\x1d\n" +
+	"\n" +
+	"build_time\x18\x04 \x01(\tR\tbuildTime\x12\x14\n" +
+	"\x05dirty\x18\x05 \x01(\bR\x05dirty\x12\x1d\n" +
+	"\n" +
+	"go_version\x18\x06 \x01(\tR\tgoVersion\x12\x1a\n" +
+	"\bplatform\x18\a \x01(\tR\bplatform\"Q\n" +
+	"\x14GetSystemInfoRequest\x12\x1d\n" +
+	"\n" +
+	"no_network\x18\x01 \x01(\bR\tnoNetwork\x12\x1a\n" +
+	"\bsecurity\x18\x02 \x01(\bR\bsecurity\"\xcc\x02\n" +
+	"\n" +
+	"SystemInfo\x12%\n" +
+	"\x0eschema_version\x18\x01 \x01(\x05R\rschemaVersion\x12\x0e\n" +
+	"\x02os\x18\x02 \x01(\tR\x02os\x12\x1a\n" +
+	"\bplatform\x18\x03 \x01(\tR\bplatform\x12\x16\n" +
+	"\x06kernel\x18\x04 \x01(\tR\x06kernel\x12\"\n" +
+	"\farchitecture\x18\x05 \x01(\tR\farchitecture\x12!\n" +
+	"\x03cpu\x18\x06 \x01(\v2\x0f.ado.v1.CPUInfoR\x03cpu\x12*\n" +
+	"\x06memory\x18\a \x01(\v2\x12.ado.v1.MemoryInfoR\x06memory\x12-\n" +
+	"\astorage\x18\b \x03(\v2\x13.ado.v1.StorageInfoR\astorage\x121\n" +
+	"\adetails\x18\t \x01(\v2\x17.google.protobuf.StructR\adetails\"\xbc\x01\n" +
+	"\aCPUInfo\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12\x16\n" +
+	"\x06vendor\x18\x02 \x01(\tR\x06vendor\x12\x14\n" +
+	"\x05cores\x18\x03 \x01(\x05R\x05cores\x12#\n" +
+	"\rfrequency_mhz\x18\x04 \x01(\x01R\ffrequencyMhz\x12%\n" +
+	"\x0eeffective_cpus\x18\x05 \x01(\x01R\reffectiveCpus\x12!\n" +
+	"\flimit_source\x18\x06 \x01(\tR\vlimitSource\"\x9d\x02\n" +
+	"\n" +
+	"MemoryInfo\x12\x19\n" +
+	"\btotal_mb\x18\x01 \x01(\x04R\atotalMb\x12!\n" +
+	"\favailable_mb\x18\x02 \x01(\x04R\vavailableMb\x12\x17\n" +
+	"\aused_mb\x18\x03 \x01(\x04R\x06usedMb\x12!\n" +
+	"\fused_percent\x18\x04 \x01(\x01R\vusedPercent\x12\"\n" +
+	"\rswap_total_mb\x18\x05 \x01(\x04R\vswapTotalMb\x12 \n" +
+	"\fswap_used_mb\x18\x06 \x01(\x04R\n" +
+	"swapUsedMb\x12,\n" +
+	"\x12effective_limit_mb\x18\a \x01(\x04R\x10effectiveLimitMb\x12!\n" +
+	"\flimit_source\x18\b \x01(\tR\vlimitSource\"\xd5\x01\n" +
+	"\vStorageInfo\x12\x16\n" +
+	"\x06device\x18\x01 \x01(\tR\x06device\x12\x1e\n" +
+	"\n" +
+	"mountpoint\x18\x02 \x01(\tR\n" +
+	"mountpoint\x12\x1e\n" +
+	"\n" +
+	"filesystem\x18\x03 \x01(\tR\n" +
+	"filesystem\x12\x19\n" +
+	"\btotal_mb\x18\x04 \x01(\x04R\atotalMb\x12\x17\n" +
+	"\aused_mb\x18\x05 \x01(\x04R\x06usedMb\x12\x17\n" +
+	"\afree_mb\x18\x06 \x01(\x04R\x06freeMb\x12!\n" +
+	"\fused_percent\x18\a \x01(\x01R\vusedPercent\"\x12\n" +
+	"\x10ListTasksRequest\"7\n" +
+	"\x11ListTasksResponse\x12\"\n" +
+	"\x05tasks\x18\x01 \x03(\v2\f.ado.v1.TaskR\x05tasks\"j\n" +
+	"\x04Task\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x18\n" +
+	"\acommand\x18\x03 \x01(\tR\acommand\x12\x12\n" +
+	"\x04args\x18\x04 \x03(\tR\x04args\"8\n" +
+	"\x0eRunTaskRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04args\x18\x02 \x03(\tR\x04args\"h\n" +
+	"\x0fRunTaskResponse\x12(\n" +
+	"\x06output\x18\x01 \x01(\v2\x0e.ado.v1.OutputH\x00R\x06output\x12\"\n" +
+	"\x04exit\x18\x02 \x01(\v2\f.ado.v1.ExitH\x00R\x04exitB\a\n" +
+	"\x05event\"\x93\x01\n" +
+	"\x06Output\x12-\n" +
+	"\x06stream\x18\x01 \x01(\x0e2\x15.ado.v1.Output.StreamR\x06stream\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\"F\n" +
+	"\x06Stream\x12\x16\n" +
+	"\x12STREAM_UNSPECIFIED\x10\x00\x12\x11\n" +
+	"\rSTREAM_STDOUT\x10\x01\x12\x11\n" +
+	"\rSTREAM_STDERR\x10\x02\"\x1a\n" +
+	"\x04Exit\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\x05R\x04code2\x8f\x02\n" +
+	"\n" +
+	"AdoService\x12>\n" +
+	"\fGetBuildInfo\x12\x1b.ado.v1.GetBuildInfoRequest\x1a\x11.ado.v1.BuildInfo\x12A\n" +
+	"\rGetSystemInfo\x12\x1c.ado.v1.GetSystemInfoRequest\x1a\x12.ado.v1.SystemInfo\x12@\n" +
+	"\tListTasks\x12\x18.ado.v1.ListTasksRequest\x1a\x19.ado.v1.ListTasksResponse\x12<\n" +
+	"\aRunTask\x12\x16.ado.v1.RunTaskRequest\x1a\x17.ado.v1.RunTaskResponse0\x01B,Z*github.com/anowarislam/ado/pkg/adov1;adov1b\x06proto3"
+
+var (
+	file_ado_v1_ado_proto_rawDescOnce sync.Once
+	file_ado_v1_ado_proto_rawDescData []byte
+)
+
+func file_ado_v1_ado_proto_rawDescGZIP() []byte {
+	file_ado_v1_ado_proto_rawDescOnce.Do(func() {
+		file_ado_v1_ado_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ado_v1_ado_proto_rawDesc), len(file_ado_v1_ado_proto_rawDesc)))
+	})
+	return file_ado_v1_ado_proto_rawDescData
+}
+
+var file_ado_v1_ado_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_ado_v1_ado_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_ado_v1_ado_proto_goTypes = []any{
+	(Output_Stream)(0),           // 0: ado.v1.Output.Stream
+	(*GetBuildInfoRequest)(nil),  // 1: ado.v1.GetBuildInfoRequest
+	(*BuildInfo)(nil),            // 2: ado.v1.BuildInfo
+	(*GetSystemInfoRequest)(nil), // 3: ado.v1.GetSystemInfoRequest
+	(*SystemInfo)(nil),           // 4: ado.v1.SystemInfo
+	(*CPUInfo)(nil),              // 5: ado.v1.CPUInfo
+	(*MemoryInfo)(nil),           // 6: ado.v1.MemoryInfo
+	(*StorageInfo)(nil),          // 7: ado.v1.StorageInfo
+	(*ListTasksRequest)(nil),     // 8: ado.v1.ListTasksRequest
+	(*ListTasksResponse)(nil),    // 9: ado.v1.ListTasksResponse
+	(*Task)(nil),                 // 10: ado.v1.Task
+	(*RunTaskRequest)(nil),       // 11: ado.v1.RunTaskRequest
+	(*RunTaskResponse)(nil),      // 12: ado.v1.RunTaskResponse
+	(*Output)(nil),               // 13: ado.v1.Output
+	(*Exit)(nil),                 // 14: ado.v1.Exit
+	(*structpb.Struct)(nil),      // 15: google.protobuf.Struct
+}
+var file_ado_v1_ado_proto_depIdxs = []int32{
+	5,  // 0: ado.v1.SystemInfo.cpu:type_name -> ado.v1.CPUInfo
+	6,  // 1: ado.v1.SystemInfo.memory:type_name -> ado.v1.MemoryInfo
+	7,  // 2: ado.v1.SystemInfo.storage:type_name -> ado.v1.StorageInfo
+	15, // 3: ado.v1.SystemInfo.details:type_name -> google.protobuf.Struct
+	10, // 4: ado.v1.ListTasksResponse.tasks:type_name -> ado.v1.Task
+	13, // 5: ado.v1.RunTaskResponse.output:type_name -> ado.v1.Output
+	14, // 6: ado.v1.RunTaskResponse.exit:type_name -> ado.v1.Exit
+	0,  // 7: ado.v1.Output.stream:type_name -> ado.v1.Output.Stream
+	1,  // 8: ado.v1.AdoService.GetBuildInfo:input_type -> ado.v1.GetBuildInfoRequest
+	3,  // 9: ado.v1.AdoService.GetSystemInfo:input_type -> ado.v1.GetSystemInfoRequest
+	8,  // 10: ado.v1.AdoService.ListTasks:input_type -> ado.v1.ListTasksRequest
+	11, // 11: ado.v1.AdoService.RunTask:input_type -> ado.v1.RunTaskRequest
+	2,  // 12: ado.v1.AdoService.GetBuildInfo:output_type -> ado.v1.BuildInfo
+	4,  // 13: ado.v1.AdoService.GetSystemInfo:output_type -> ado.v1.SystemInfo
+	9,  // 14: ado.v1.AdoService.ListTasks:output_type -> ado.v1.ListTasksResponse
+	12, // 15: ado.v1.AdoService.RunTask:output_type -> ado.v1.RunTaskResponse
+	12, // [12:16] is the sub-list for method output_type
+	8,  // [8:12] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
+}
+
+func init() { file_ado_v1_ado_proto_init() }
+func file_ado_v1_ado_proto_init() {
+	if File_ado_v1_ado_proto != nil {
+		return
+	}
+	file_ado_v1_ado_proto_msgTypes[11].OneofWrappers = []any{
+		(*RunTaskResponse_Output)(nil),
+		(*RunTaskResponse_Exit)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ado_v1_ado_proto_rawDesc), len(file_ado_v1_ado_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_ado_v1_ado_proto_goTypes,
+		DependencyIndexes: file_ado_v1_ado_proto_depIdxs,
+		EnumInfos:         file_ado_v1_ado_proto_enumTypes,
+		MessageInfos:      file_ado_v1_ado_proto_msgTypes,
+	}.Build()
+	File_ado_v1_ado_proto = out.File
+	file_ado_v1_ado_proto_goTypes = nil
+	file_ado_v1_ado_proto_depIdxs = nil
+}
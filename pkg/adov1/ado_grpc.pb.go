@@ -0,0 +1,260 @@
+// Protocol for `ado serve --grpc`. Field names follow the JSON output of
+// the matching ado commands.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: ado/v1/ado.proto
+
+package adov1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AdoService_GetBuildInfo_FullMethodName  = "/ado.v1.AdoService/GetBuildInfo"
+	AdoService_GetSystemInfo_FullMethodName = "/ado.v1.AdoService/GetSystemInfo"
+	AdoService_ListTasks_FullMethodName     = "/ado.v1.AdoService/ListTasks"
+	AdoService_RunTask_FullMethodName       = "/ado.v1.AdoService/RunTask"
+)
+
+// AdoServiceClient is the client API for AdoService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AdoService exposes diagnostics and config-defined tasks.
+type AdoServiceClient interface {
+	// GetBuildInfo returns the build metadata of the serving binary
+	// (`ado meta info`).
+	GetBuildInfo(ctx context.Context, in *GetBuildInfoRequest, opts ...grpc.CallOption) (*BuildInfo, error)
+	// GetSystemInfo collects host diagnostics (`ado meta system`).
+	GetSystemInfo(ctx context.Context, in *GetSystemInfoRequest, opts ...grpc.CallOption) (*SystemInfo, error)
+	// ListTasks returns the tasks defined in the server's config file.
+	ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksResponse, error)
+	// RunTask runs a config-defined task, streaming its output as it is
+	// produced. The last message carries the exit status. Cancelling the
+	// call stops the task.
+	RunTask(ctx context.Context, in *RunTaskRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunTaskResponse], error)
+}
+
+type adoServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdoServiceClient(cc grpc.ClientConnInterface) AdoServiceClient {
+	return &adoServiceClient{cc}
+}
+
+func (c *adoServiceClient) GetBuildInfo(ctx context.Context, in *GetBuildInfoRequest, opts ...grpc.CallOption) (*BuildInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(BuildInfo)
+	err := c.cc.Invoke(ctx, AdoService_GetBuildInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adoServiceClient) GetSystemInfo(ctx context.Context, in *GetSystemInfoRequest, opts ...grpc.CallOption) (*SystemInfo, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SystemInfo)
+	err := c.cc.Invoke(ctx, AdoService_GetSystemInfo_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adoServiceClient) ListTasks(ctx context.Context, in *ListTasksRequest, opts ...grpc.CallOption) (*ListTasksResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTasksResponse)
+	err := c.cc.Invoke(ctx, AdoService_ListTasks_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adoServiceClient) RunTask(ctx context.Context, in *RunTaskRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[RunTaskResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AdoService_ServiceDesc.Streams[0], AdoService_RunTask_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[RunTaskRequest, RunTaskResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdoService_RunTaskClient = grpc.ServerStreamingClient[RunTaskResponse]
+
+// AdoServiceServer is the server API for AdoService service.
+// All implementations must embed UnimplementedAdoServiceServer
+// for forward compatibility.
+//
+// AdoService exposes diagnostics and config-defined tasks.
+type AdoServiceServer interface {
+	// GetBuildInfo returns the build metadata of the serving binary
+	// (`ado meta info`).
+	GetBuildInfo(context.Context, *GetBuildInfoRequest) (*BuildInfo, error)
+	// GetSystemInfo collects host diagnostics (`ado meta system`).
+	GetSystemInfo(context.Context, *GetSystemInfoRequest) (*SystemInfo, error)
+	// ListTasks returns the tasks defined in the server's config file.
+	ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error)
+	// RunTask runs a config-defined task, streaming its output as it is
+	// produced. The last message carries the exit status. Cancelling the
+	// call stops the task.
+	RunTask(*RunTaskRequest, grpc.ServerStreamingServer[RunTaskResponse]) error
+	mustEmbedUnimplementedAdoServiceServer()
+}
+
+// UnimplementedAdoServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdoServiceServer struct{}
+
+func (UnimplementedAdoServiceServer) GetBuildInfo(context.Context, *GetBuildInfoRequest) (*BuildInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBuildInfo not implemented")
+}
+func (UnimplementedAdoServiceServer) GetSystemInfo(context.Context, *GetSystemInfoRequest) (*SystemInfo, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSystemInfo not implemented")
+}
+func (UnimplementedAdoServiceServer) ListTasks(context.Context, *ListTasksRequest) (*ListTasksResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTasks not implemented")
+}
+func (UnimplementedAdoServiceServer) RunTask(*RunTaskRequest, grpc.ServerStreamingServer[RunTaskResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method RunTask not implemented")
+}
+func (UnimplementedAdoServiceServer) mustEmbedUnimplementedAdoServiceServer() {}
+func (UnimplementedAdoServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeAdoServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdoServiceServer will
+// result in compilation errors.
+type UnsafeAdoServiceServer interface {
+	mustEmbedUnimplementedAdoServiceServer()
+}
+
+func RegisterAdoServiceServer(s grpc.ServiceRegistrar, srv AdoServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAdoServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AdoService_ServiceDesc, srv)
+}
+
+func _AdoService_GetBuildInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBuildInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdoServiceServer).GetBuildInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdoService_GetBuildInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdoServiceServer).GetBuildInfo(ctx, req.(*GetBuildInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdoService_GetSystemInfo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSystemInfoRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdoServiceServer).GetSystemInfo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdoService_GetSystemInfo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdoServiceServer).GetSystemInfo(ctx, req.(*GetSystemInfoRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdoService_ListTasks_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTasksRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdoServiceServer).ListTasks(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AdoService_ListTasks_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdoServiceServer).ListTasks(ctx, req.(*ListTasksRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AdoService_RunTask_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(RunTaskRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AdoServiceServer).RunTask(m, &grpc.GenericServerStream[RunTaskRequest, RunTaskResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AdoService_RunTaskServer = grpc.ServerStreamingServer[RunTaskResponse]
+
+// AdoService_ServiceDesc is the grpc.ServiceDesc for AdoService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AdoService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ado.v1.AdoService",
+	HandlerType: (*AdoServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetBuildInfo",
+			Handler:    _AdoService_GetBuildInfo_Handler,
+		},
+		{
+			MethodName: "GetSystemInfo",
+			Handler:    _AdoService_GetSystemInfo_Handler,
+		},
+		{
+			MethodName: "ListTasks",
+			Handler:    _AdoService_ListTasks_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "RunTask",
+			Handler:       _AdoService_RunTask_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "ado/v1/ado.proto",
+}